@@ -3,6 +3,7 @@ package addon
 import (
 	"context"
 	"embed"
+	"strings"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/openshift/library-go/pkg/assets"
@@ -22,6 +23,7 @@ import (
 	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
 	addonv1alpha1client "open-cluster-management.io/api/client/addon/clientset/versioned"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	appsubv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
 	appsubutils "open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -89,9 +91,28 @@ func getValue(cluster *clusterv1.ManagedCluster,
 	return addonfactory.JsonStructToValues(addonValues)
 }
 
+// getAgentUpgradeValues deploys the agent image rolled out to the cluster by an AgentUpgrade, the hub sets the image on
+// the addon once the wave of the cluster started
+func getAgentUpgradeValues(cluster *clusterv1.ManagedCluster,
+	addon *addonapiv1alpha1.ManagedClusterAddOn) (addonfactory.Values, error) {
+	image := addon.GetAnnotations()[appsubv1alpha1.AnnotationAgentUpgradeImage]
+	if image == "" {
+		return addonfactory.Values{}, nil
+	}
+
+	return addonfactory.Values{
+		"global": map[string]interface{}{
+			"imageOverrides": map[string]interface{}{
+				"multicluster_operators_subscription": image,
+			},
+		},
+	}, nil
+}
+
 func toAddonResources(config addonapiv1alpha1.AddOnDeploymentConfig) (addonfactory.Values, error) {
 	type resource struct {
 		Memory string `json:"memory"`
+		CPU    string `json:"cpu,omitempty"`
 	}
 
 	type resources struct {
@@ -120,6 +141,14 @@ func toAddonResources(config addonapiv1alpha1.AddOnDeploymentConfig) (addonfacto
 		if variable.Name == "LimitsMemory" {
 			jsonStruct.Resources.Limits.Memory = variable.Value
 		}
+
+		if variable.Name == "RequestCPU" {
+			jsonStruct.Resources.Requests.CPU = variable.Value
+		}
+
+		if variable.Name == "LimitsCPU" {
+			jsonStruct.Resources.Limits.CPU = variable.Value
+		}
 	}
 
 	values, err := addonfactory.JsonStructToValues(jsonStruct)
@@ -130,6 +159,34 @@ func toAddonResources(config addonapiv1alpha1.AddOnDeploymentConfig) (addonfacto
 	return values, nil
 }
 
+// toAddonAgentFlags sets the agent flags from the customized variables: ProfilingAddress is the address of the agent
+// profiling endpoints, they are disabled if the variable is not set. TLSMinVersion, TLSCipherSuites and FIPSMode are
+// the TLS settings of the agent connections.
+func toAddonAgentFlags(config addonapiv1alpha1.AddOnDeploymentConfig) (addonfactory.Values, error) {
+	values := addonfactory.Values{}
+
+	for _, variable := range config.Spec.CustomizedVariables {
+		switch variable.Name {
+		case "ProfilingAddress":
+			values["profilingAddr"] = variable.Value
+		case "TLSMinVersion":
+			values["tlsMinVersion"] = variable.Value
+		case "TLSCipherSuites":
+			values["tlsCipherSuites"] = variable.Value
+		case "FIPSMode":
+			values["fipsMode"] = strings.EqualFold(variable.Value, "true")
+		case "ClusterSecretServiceAccount":
+			values["clusterSecretServiceAccount"] = variable.Value
+		case "ClusterSecretClusterRole":
+			values["clusterSecretClusterRole"] = variable.Value
+		case "ClusterSecretRoleNamespaces":
+			values["clusterSecretRoleNamespaces"] = variable.Value
+		}
+	}
+
+	return values, nil
+}
+
 func newRegistrationOption(kubeClient *kubernetes.Clientset, addonName string) *agent.RegistrationOption {
 	return &agent.RegistrationOption{
 		CSRConfigurations: agent.KubeClientSignerConfigurations(addonName, addonName),
@@ -181,7 +238,8 @@ func applyManifestFromFile(file, clusterName, addonName string, kubeClient *kube
 	return nil
 }
 
-func NewAddonManager(kubeConfig *rest.Config, agentImage string, agentInstallAllStrategy bool) (addonmanager.AddonManager, error) {
+func NewAddonManager(kubeConfig *rest.Config, agentImage string, agentInstallAllStrategy bool,
+	clusterProxy ClusterProxyOptions) (addonmanager.AddonManager, error) {
 	AppMgrImage = agentImage
 
 	addonMgr, err := addonmanager.New(kubeConfig)
@@ -211,16 +269,25 @@ func NewAddonManager(kubeConfig *rest.Config, agentImage string, agentInstallAll
 		WithGetValuesFuncs(
 			getValue,
 			addonfactory.GetValuesFromAddonAnnotation,
+			// deploy the agent image rolled out by the AgentUpgrade
+			getAgentUpgradeValues,
 			// get the AddOnDeloymentConfig object and transform nodeSelector and toleration defined in spec.NodePlacement to Values object
 			addonfactory.GetAddOnDeloymentConfigValues(
 				addonGetter,
 				addonfactory.ToAddOnNodePlacementValues,
 			),
-			// get the AddOnDeloymentConfig object and transform request/limit memory defined in Spec.CustomizedVariables to Values object
+			// get the AddOnDeloymentConfig object and transform request/limit memory and cpu defined in Spec.CustomizedVariables to Values object
 			addonfactory.GetAddOnDeloymentConfigValues(
 				addonGetter,
 				toAddonResources,
 			),
+			// get the AddOnDeloymentConfig object and transform the agent flags defined in Spec.CustomizedVariables to Values object
+			addonfactory.GetAddOnDeloymentConfigValues(
+				addonGetter,
+				toAddonAgentFlags,
+			),
+			// point the cluster secret to the cluster-proxy user server of the cluster
+			getClusterProxyValues(clusterProxy, addonClient, kubeClient),
 		).
 		WithAgentRegistrationOption(newRegistrationOption(kubeClient, AppMgrAddonName))
 
@@ -4,13 +4,17 @@ import (
 	"testing"
 
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"open-cluster-management.io/addon-framework/pkg/addonfactory"
 	"open-cluster-management.io/addon-framework/pkg/agent"
 	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	addonfake "open-cluster-management.io/api/client/addon/clientset/versioned/fake"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	appsubv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
 )
 
 var (
@@ -54,13 +58,20 @@ func newAddon(name, cluster, installNamespace string, annotationValues string) *
 	return addon
 }
 
+func newUpgradedAddon(name, cluster, image string) *addonapiv1alpha1.ManagedClusterAddOn {
+	addon := newAddon(name, cluster, "", "")
+	addon.SetAnnotations(map[string]string{appsubv1alpha1.AnnotationAgentUpgradeImage: image})
+
+	return addon
+}
+
 func newAgentAddon(t *testing.T) agent.AgentAddon {
 	registrationOption := newRegistrationOption(nil, AppMgrAddonName)
 	getValuesFunc := getValue
 
 	agentAddon, err := addonfactory.NewAgentAddonFactory(AppMgrAddonName, ChartFS, ChartDir).
 		WithScheme(scheme).
-		WithGetValuesFuncs(getValuesFunc, addonfactory.GetValuesFromAddonAnnotation).
+		WithGetValuesFuncs(getValuesFunc, addonfactory.GetValuesFromAddonAnnotation, getAgentUpgradeValues).
 		WithAgentRegistrationOption(registrationOption).
 		BuildHelmAgentAddon()
 	if err != nil {
@@ -96,6 +107,22 @@ func TestManifest(t *testing.T) {
 			expectedImage:     "quay.io/open-cluster-management/multicluster_operators_subscription:latest",
 			expectedCount:     6,
 		},
+		{
+			name:              "case_3",
+			cluster:           newCluster("local-cluster"),
+			addon:             newAddon(AppMgrAddonName, "local-cluster", "test", `{"clusterProxy":{"serverURL":"https://proxy.example.com/local-cluster","serverName":"proxy.example.com","caData":"ca"}}`),
+			expectedNamespace: "test",
+			expectedImage:     "quay.io/open-cluster-management/multicluster_operators_subscription:latest",
+			expectedCount:     7,
+		},
+		{
+			name:              "case_4",
+			cluster:           newCluster("local-cluster"),
+			addon:             newUpgradedAddon(AppMgrAddonName, "local-cluster", "quay.io/test/multicluster_operators_subscription:upgrade"),
+			expectedNamespace: "open-cluster-management-agent-addon",
+			expectedImage:     "quay.io/test/multicluster_operators_subscription:upgrade",
+			expectedCount:     6,
+		},
 	}
 	AppMgrImage = "quay.io/open-cluster-management/multicluster_operators_subscription:latest"
 	agentAddon := newAgentAddon(t)
@@ -128,3 +155,113 @@ func TestManifest(t *testing.T) {
 		})
 	}
 }
+
+func TestAddonDeploymentConfigValues(t *testing.T) {
+	config := addonapiv1alpha1.AddOnDeploymentConfig{
+		Spec: addonapiv1alpha1.AddOnDeploymentConfigSpec{
+			CustomizedVariables: []addonapiv1alpha1.CustomizedVariable{
+				{Name: "RequestMemory", Value: "256Mi"},
+				{Name: "RequestCPU", Value: "100m"},
+				{Name: "LimitsCPU", Value: "1"},
+				{Name: "ProfilingAddress", Value: ":6060"},
+				{Name: "TLSMinVersion", Value: "1.3"},
+				{Name: "FIPSMode", Value: "true"},
+				{Name: "ClusterSecretServiceAccount", Value: "argocd-manager"},
+				{Name: "ClusterSecretClusterRole", Value: "argocd-manager"},
+			},
+		},
+	}
+
+	values, err := toAddonResources(config)
+	if err != nil {
+		t.Fatalf("failed to get the resources values with error %v", err)
+	}
+
+	resources, _ := values["resources"].(map[string]interface{})
+	requests, _ := resources["requests"].(map[string]interface{})
+	limits, _ := resources["limits"].(map[string]interface{})
+
+	if requests["memory"] != "256Mi" || requests["cpu"] != "100m" {
+		t.Errorf("unexpected requests %v", requests)
+	}
+
+	if limits["memory"] != "2Gi" || limits["cpu"] != "1" {
+		t.Errorf("unexpected limits %v", limits)
+	}
+
+	values, err = toAddonAgentFlags(config)
+	if err != nil {
+		t.Fatalf("failed to get the agent flag values with error %v", err)
+	}
+
+	if values["profilingAddr"] != ":6060" {
+		t.Errorf("expected profiling address is :6060, but got %v", values["profilingAddr"])
+	}
+
+	if values["tlsMinVersion"] != "1.3" || values["fipsMode"] != true {
+		t.Errorf("unexpected TLS values %v", values)
+	}
+
+	if values["clusterSecretServiceAccount"] != "argocd-manager" || values["clusterSecretClusterRole"] != "argocd-manager" {
+		t.Errorf("unexpected cluster secret service account values %v", values)
+	}
+
+	values, _ = toAddonResources(addonapiv1alpha1.AddOnDeploymentConfig{})
+	resources, _ = values["resources"].(map[string]interface{})
+	requests, _ = resources["requests"].(map[string]interface{})
+
+	if _, ok := requests["cpu"]; ok {
+		t.Errorf("expected no cpu request by default, but got %v", requests)
+	}
+}
+
+func TestClusterProxyValues(t *testing.T) {
+	cluster := newCluster("cluster1")
+	addon := newAddon(AppMgrAddonName, "cluster1", "", "")
+
+	proxyAddon := newAddon(ClusterProxyAddonName, "cluster1", "", "")
+	proxyAddon.Status.Conditions = []metav1.Condition{
+		{Type: addonapiv1alpha1.ManagedClusterAddOnConditionAvailable, Status: metav1.ConditionTrue},
+	}
+
+	caConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "proxy-ca", Namespace: "open-cluster-management-addon"},
+		Data:       map[string]string{"service-ca.crt": "ca"},
+	}
+
+	opts := ClusterProxyOptions{
+		ServerURL:   "https://proxy.example.com:9092/",
+		CAConfigMap: "open-cluster-management-addon/proxy-ca",
+	}
+
+	// the cluster-proxy addon isn't installed
+	values, err := getClusterProxyValues(opts, addonfake.NewSimpleClientset(), kubefake.NewSimpleClientset(caConfigMap))(cluster, addon)
+	if err != nil || len(values) != 0 {
+		t.Errorf("expected no cluster-proxy values, but got %v, err: %v", values, err)
+	}
+
+	// the cluster-proxy server isn't set
+	values, err = getClusterProxyValues(ClusterProxyOptions{}, addonfake.NewSimpleClientset(proxyAddon),
+		kubefake.NewSimpleClientset(caConfigMap))(cluster, addon)
+	if err != nil || len(values) != 0 {
+		t.Errorf("expected no cluster-proxy values, but got %v, err: %v", values, err)
+	}
+
+	values, err = getClusterProxyValues(opts, addonfake.NewSimpleClientset(proxyAddon),
+		kubefake.NewSimpleClientset(caConfigMap))(cluster, addon)
+	if err != nil {
+		t.Fatalf("failed to get the cluster-proxy values with error %v", err)
+	}
+
+	clusterProxy, _ := values["clusterProxy"].(map[string]interface{})
+	if clusterProxy["serverURL"] != "https://proxy.example.com:9092/cluster1" || clusterProxy["serverName"] != "proxy.example.com" ||
+		clusterProxy["caData"] != "ca" {
+		t.Errorf("unexpected cluster-proxy values %v", clusterProxy)
+	}
+
+	// the CA ConfigMap is missing
+	_, err = getClusterProxyValues(opts, addonfake.NewSimpleClientset(proxyAddon), kubefake.NewSimpleClientset())(cluster, addon)
+	if err == nil {
+		t.Errorf("expected error for the missing CA ConfigMap")
+	}
+}
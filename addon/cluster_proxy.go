@@ -0,0 +1,111 @@
+package addon
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"open-cluster-management.io/addon-framework/pkg/addonfactory"
+	addonapiv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	addonv1alpha1client "open-cluster-management.io/api/client/addon/clientset/versioned"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+// ClusterProxyAddonName is the name of the cluster-proxy addon, the hub reaches the API servers of the managed clusters
+// with the addon through its user server
+const ClusterProxyAddonName = "cluster-proxy"
+
+// ClusterProxyOptions is the cluster-proxy user server the cluster secrets point to. The cluster secrets point to the
+// API servers of the managed clusters if ServerURL is empty
+type ClusterProxyOptions struct {
+	ServerURL string
+	// ServerName is the TLS server name of the user server, the host of ServerURL by default
+	ServerName string
+	// CAConfigMap is the <namespace>/<name> of the ConfigMap holding the CA of the user server on the hub
+	CAConfigMap string
+}
+
+// caConfigMapKeys are the keys of the CA in the ConfigMap, service-ca.crt is the key of the service CA ConfigMaps
+var caConfigMapKeys = []string{"ca.crt", "service-ca.crt"}
+
+// getClusterProxyValues sets the cluster-proxy server of the agent on the clusters with the cluster-proxy addon
+// available, the agent points the cluster secret to the user server of the cluster instead of its API server
+func getClusterProxyValues(opts ClusterProxyOptions, addonClient addonv1alpha1client.Interface,
+	kubeClient kubernetes.Interface) addonfactory.GetValuesFunc {
+	return func(cluster *clusterv1.ManagedCluster, addon *addonapiv1alpha1.ManagedClusterAddOn) (addonfactory.Values, error) {
+		if opts.ServerURL == "" {
+			return addonfactory.Values{}, nil
+		}
+
+		proxyAddon, err := addonClient.AddonV1alpha1().ManagedClusterAddOns(cluster.Name).Get(context.TODO(),
+			ClusterProxyAddonName, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return addonfactory.Values{}, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the cluster-proxy addon of cluster %v, err: %w", cluster.Name, err)
+		}
+
+		if !meta.IsStatusConditionTrue(proxyAddon.Status.Conditions, addonapiv1alpha1.ManagedClusterAddOnConditionAvailable) {
+			klog.Infof("the cluster-proxy addon of cluster %v is not available, the cluster secret points to its API server",
+				cluster.Name)
+
+			return addonfactory.Values{}, nil
+		}
+
+		serverName := opts.ServerName
+		if serverName == "" {
+			u, err := url.Parse(opts.ServerURL)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cluster-proxy server URL %v, err: %w", opts.ServerURL, err)
+			}
+
+			serverName = u.Hostname()
+		}
+
+		caData, err := getClusterProxyCA(opts.CAConfigMap, kubeClient)
+		if err != nil {
+			return nil, err
+		}
+
+		return addonfactory.Values{
+			"clusterProxy": map[string]interface{}{
+				"serverURL":  strings.TrimSuffix(opts.ServerURL, "/") + "/" + cluster.Name,
+				"serverName": serverName,
+				"caData":     caData,
+			},
+		}, nil
+	}
+}
+
+// getClusterProxyCA returns the CA of the cluster-proxy user server from its ConfigMap on the hub
+func getClusterProxyCA(caConfigMap string, kubeClient kubernetes.Interface) (string, error) {
+	if caConfigMap == "" {
+		return "", nil
+	}
+
+	ns, name, found := strings.Cut(caConfigMap, "/")
+	if !found || ns == "" || name == "" {
+		return "", fmt.Errorf("invalid cluster-proxy CA ConfigMap %v, expecting <namespace>/<name>", caConfigMap)
+	}
+
+	cm, err := kubeClient.CoreV1().ConfigMaps(ns).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get the cluster-proxy CA ConfigMap %v, err: %w", caConfigMap, err)
+	}
+
+	for _, key := range caConfigMapKeys {
+		if ca := cm.Data[key]; ca != "" {
+			return ca, nil
+		}
+	}
+
+	return "", fmt.Errorf("the cluster-proxy CA ConfigMap %v has none of the keys %v", caConfigMap, caConfigMapKeys)
+}
@@ -0,0 +1,178 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	subapis "open-cluster-management.io/multicloud-operators-subscription/pkg/apis"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/backup"
+)
+
+const usage = `Usage:
+  appsub-backup export [--namespace <ns>] [--file <bundle.yaml>]
+  appsub-backup import --file <bundle.yaml> [--namespace-map <old>=<new>] [--cluster-map <old>=<new>] [--dry-run]
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(1)
+	}
+
+	var err error
+
+	switch os.Args[1] {
+	case "export":
+		err = runExport(os.Args[2:])
+	case "import":
+		err = runImport(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(1)
+	}
+
+	if err != nil {
+		klog.Error(err)
+		os.Exit(1)
+	}
+}
+
+func newClient() (client.Client, error) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := runtime.NewScheme()
+
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	if err := subapis.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	return client.New(cfg, client.Options{Scheme: scheme})
+}
+
+func runExport(args []string) error {
+	fs := pflag.NewFlagSet("export", pflag.ExitOnError)
+	namespace := fs.String("namespace", "", "Export the subscriptions of this namespace only. All namespaces by default.")
+	file := fs.String("file", "", "The bundle file to write. Standard output by default.")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	bundle, err := backup.Export(context.TODO(), c, *namespace)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(bundle)
+	if err != nil {
+		return err
+	}
+
+	if *file == "" {
+		_, err = os.Stdout.Write(data)
+
+		return err
+	}
+
+	klog.Infof("exported %v subscriptions, %v channels and %v revision pins to %v",
+		len(bundle.Subscriptions), len(bundle.Channels), len(bundle.RevisionPins), *file)
+
+	return os.WriteFile(*file, data, 0600)
+}
+
+func runImport(args []string) error {
+	fs := pflag.NewFlagSet("import", pflag.ExitOnError)
+	file := fs.String("file", "", "The bundle file to restore.")
+	namespaceMap := fs.StringSlice("namespace-map", nil, "Rename a namespace of the bundle, in the format of <old>=<new>. Can be repeated.")
+	clusterMap := fs.StringSlice("cluster-map", nil, "Rename a managed cluster of the bundle, in the format of <old>=<new>. Can be repeated.")
+	dryRun := fs.Bool("dry-run", false, "Validate the import against the hub without persisting anything. "+
+		"The objects of the namespaces missing on the hub are reported as would be created.")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *file == "" {
+		return fmt.Errorf("--file is required")
+	}
+
+	opts := backup.ImportOptions{DryRun: *dryRun}
+
+	var err error
+
+	if opts.NamespaceMap, err = parseMapping(*namespaceMap); err != nil {
+		return err
+	}
+
+	if opts.ClusterMap, err = parseMapping(*clusterMap); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+
+	bundle := &backup.Bundle{}
+	if err := yaml.Unmarshal(data, bundle); err != nil {
+		return fmt.Errorf("failed to parse bundle %v, err: %w", *file, err)
+	}
+
+	c, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	return backup.Import(context.TODO(), c, bundle, opts)
+}
+
+func parseMapping(entries []string) (map[string]string, error) {
+	res := map[string]string{}
+
+	for _, entry := range entries {
+		kv := strings.SplitN(entry, "=", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return nil, fmt.Errorf("invalid mapping %q, expecting <old>=<new>", entry)
+		}
+
+		res[kv[0]] = kv[1]
+	}
+
+	return res, nil
+}
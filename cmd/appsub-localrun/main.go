@@ -0,0 +1,441 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/pflag"
+	"k8s.io/klog/v2"
+)
+
+const usage = `Usage:
+  appsub-localrun up [--dir <dir>] [--kind-image <image>] [--skip-ocm]
+  appsub-localrun run [--dir <dir>] [--components hub,appsubsummary,placementrule,agent] [--sync-interval <seconds>] [--v <level>]
+  appsub-localrun down [--dir <dir>]
+`
+
+const (
+	// the kind clusters of the hub and of the managed cluster, deploy/ocm/install.sh uses their kind-hub and
+	// kind-cluster1 contexts
+	hubCluster     = "hub"
+	managedCluster = "cluster1"
+
+	// agentAddonNamespace is the namespace of the agent lease on the managed cluster
+	agentAddonNamespace = "open-cluster-management-agent-addon"
+)
+
+// the binaries of the components, built from the cmd folders of the repository
+var binaries = map[string]string{
+	"multicluster-operators-subscription":  "./cmd/manager",
+	"appsubsummary":                        "./cmd/appsubsummary",
+	"multicluster-operators-placementrule": "./cmd/placementrule",
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(1)
+	}
+
+	var err error
+
+	switch os.Args[1] {
+	case "up":
+		err = runUp(os.Args[2:])
+	case "run":
+		err = runComponents(os.Args[2:])
+	case "down":
+		err = runDown(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(1)
+	}
+
+	if err != nil {
+		klog.Error(err)
+		os.Exit(1)
+	}
+}
+
+func kubeconfigPath(dir, cluster string) string {
+	return filepath.Join(dir, cluster+".kubeconfig")
+}
+
+// runUp creates the kind clusters of the hub and of the managed cluster, installs the OCM registration on them, and
+// writes their kubeconfigs in the directory
+func runUp(args []string) error {
+	fs := pflag.NewFlagSet("up", pflag.ExitOnError)
+	dir := fs.String("dir", ".localrun", "The directory of the kubeconfigs and of the binaries.")
+	kindImage := fs.String("kind-image", "", "The node image of the kind clusters. The kind default if empty.")
+	skipOCM := fs.Bool("skip-ocm", false, "Don't install the OCM registration and work agents, the subscriptions are not "+
+		"propagated to the managed cluster without them.")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	for _, tool := range []string{"kind", "kubectl", "git", "make"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			return fmt.Errorf("%v is required in the PATH: %w", tool, err)
+		}
+	}
+
+	if err := os.MkdirAll(*dir, 0700); err != nil {
+		return err
+	}
+
+	existing, err := output("kind", "get", "clusters")
+	if err != nil {
+		return err
+	}
+
+	for _, cluster := range []string{hubCluster, managedCluster} {
+		if containsLine(existing, cluster) {
+			klog.Infof("kind cluster %v already exists", cluster)
+		} else {
+			createArgs := []string{"create", "cluster", "--name", cluster}
+			if *kindImage != "" {
+				createArgs = append(createArgs, "--image", *kindImage)
+			}
+
+			if err := run("kind", createArgs...); err != nil {
+				return fmt.Errorf("failed to create kind cluster %v: %w", cluster, err)
+			}
+		}
+
+		kubeconfig, err := output("kind", "get", "kubeconfig", "--name", cluster)
+		if err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(kubeconfigPath(*dir, cluster), []byte(kubeconfig), 0600); err != nil {
+			return err
+		}
+	}
+
+	hubKubeconfig := kubeconfigPath(*dir, hubCluster)
+	clusterKubeconfig := kubeconfigPath(*dir, managedCluster)
+
+	if !*skipOCM {
+		if err := installOCM(hubKubeconfig); err != nil {
+			return err
+		}
+
+		if err := acceptManagedCluster(hubKubeconfig); err != nil {
+			return err
+		}
+	}
+
+	// the subscription CRDs of the hub and of the agent, the controllers run outside of the clusters
+	if err := applyCRDs(hubKubeconfig, "deploy/hub-common"); err != nil {
+		return err
+	}
+
+	if err := applyCRDs(clusterKubeconfig, "deploy/common"); err != nil {
+		return err
+	}
+
+	if err := kubectl(clusterKubeconfig, "create", "namespace", agentAddonNamespace); err != nil {
+		klog.V(1).Infof("namespace %v not created: %v", agentAddonNamespace, err)
+	}
+
+	klog.Infof("the hub and the managed cluster are ready, run the controllers with: appsub-localrun run --dir %v", *dir)
+
+	return nil
+}
+
+// installOCM installs the registration and work agents with deploy/ocm/install.sh, the registration-operator
+// creates the bootstrap hub kubeconfig secret of the klusterlet from the internal kubeconfig of the hub
+func installOCM(hubKubeconfig string) error {
+	if err := kubectl(hubKubeconfig, "get", "namespace", "open-cluster-management-hub"); err == nil {
+		klog.Info("OCM is already installed on the hub")
+
+		return nil
+	}
+
+	return run("bash", "deploy/ocm/install.sh")
+}
+
+// acceptManagedCluster approves the certificate signing requests of the klusterlet and accepts the managed cluster
+// on the hub, like clusteradm accept
+func acceptManagedCluster(hubKubeconfig string) error {
+	klog.Infof("waiting for the managed cluster %v to register", managedCluster)
+
+	err := poll(5*time.Minute, func() bool {
+		return kubectl(hubKubeconfig, "get", "managedcluster", managedCluster) == nil
+	})
+	if err != nil {
+		return fmt.Errorf("managed cluster %v didn't register: %w", managedCluster, err)
+	}
+
+	csrs, err := output("kubectl", "--kubeconfig", hubKubeconfig, "get", "csr", "-o", "name",
+		"-l", "open-cluster-management.io/cluster-name="+managedCluster)
+	if err != nil {
+		return err
+	}
+
+	for _, csr := range strings.Fields(csrs) {
+		if err := kubectl(hubKubeconfig, "certificate", "approve", csr); err != nil {
+			return err
+		}
+	}
+
+	if err := kubectl(hubKubeconfig, "patch", "managedcluster", managedCluster, "--type=merge",
+		"-p", `{"spec":{"hubAcceptsClient":true}}`); err != nil {
+		return err
+	}
+
+	return poll(5*time.Minute, func() bool {
+		joined, err := output("kubectl", "--kubeconfig", hubKubeconfig, "get", "managedcluster", managedCluster,
+			"-o", `jsonpath={.status.conditions[?(@.type=="ManagedClusterJoined")].status}`)
+
+		return err == nil && joined == "True"
+	})
+}
+
+// applyCRDs applies the CRD files of the deploy folder
+func applyCRDs(kubeconfig, deployDir string) error {
+	files, err := filepath.Glob(filepath.Join(deployDir, "*crd*.yaml"))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if err := kubectl(kubeconfig, "apply", "-f", file); err != nil {
+			return fmt.Errorf("failed to apply %v: %w", file, err)
+		}
+	}
+
+	return nil
+}
+
+// component is a controller run as a local process
+type component struct {
+	name   string
+	binary string
+	args   []string
+}
+
+func newComponents(dir string, syncInterval, verbosity int) []component {
+	bin := filepath.Join(dir, "bin")
+	hubKubeconfig := kubeconfigPath(dir, hubCluster)
+	logArgs := []string{"--alsologtostderr", fmt.Sprintf("--v=%v", verbosity)}
+
+	return []component{
+		{
+			name:   "hub",
+			binary: filepath.Join(bin, "multicluster-operators-subscription"),
+			args: append([]string{"--kubeconfig", hubKubeconfig, "--debug",
+				fmt.Sprintf("--sync-interval=%v", syncInterval)}, logArgs...),
+		},
+		{
+			name:   "appsubsummary",
+			binary: filepath.Join(bin, "appsubsummary"),
+			args:   append([]string{"--kubeconfig", hubKubeconfig, fmt.Sprintf("--sync-interval=%v", syncInterval)}, logArgs...),
+		},
+		{
+			name:   "placementrule",
+			binary: filepath.Join(bin, "multicluster-operators-placementrule"),
+			args:   append([]string{"--kubeconfig", hubKubeconfig}, logArgs...),
+		},
+		{
+			name:   "agent",
+			binary: filepath.Join(bin, "multicluster-operators-subscription"),
+			args: append([]string{"--kubeconfig", kubeconfigPath(dir, managedCluster),
+				"--hub-cluster-configfile", hubKubeconfig, "--cluster-name", managedCluster, "--debug",
+				fmt.Sprintf("--sync-interval=%v", syncInterval)}, logArgs...),
+		},
+	}
+}
+
+// runComponents builds the controllers and runs the hub controllers and the agent as local processes until one of
+// them exits or the harness is interrupted
+func runComponents(args []string) error {
+	fs := pflag.NewFlagSet("run", pflag.ExitOnError)
+	dir := fs.String("dir", ".localrun", "The directory of the kubeconfigs and of the binaries.")
+	names := fs.StringSlice("components", []string{"hub", "appsubsummary", "placementrule", "agent"},
+		"The components to run.")
+	syncInterval := fs.Int("sync-interval", 15, "The sync interval of the controllers in seconds.")
+	verbosity := fs.Int("v", 1, "The log verbosity of the controllers.")
+	skipBuild := fs.Bool("skip-build", false, "Run the binaries of the previous run without building them.")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	for _, cluster := range []string{hubCluster, managedCluster} {
+		if _, err := os.Stat(kubeconfigPath(*dir, cluster)); err != nil {
+			return fmt.Errorf("the kubeconfig of %v is missing, run appsub-localrun up first: %w", cluster, err)
+		}
+	}
+
+	if !*skipBuild {
+		for binary, pkg := range binaries {
+			klog.Infof("building %v", pkg)
+
+			if err := run("go", "build", "-o", filepath.Join(*dir, "bin", binary), pkg); err != nil {
+				return err
+			}
+		}
+	}
+
+	selected := map[string]bool{}
+	for _, name := range *names {
+		selected[name] = true
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	for _, c := range newComponents(*dir, *syncInterval, *verbosity) {
+		if !selected[c.name] {
+			continue
+		}
+
+		cmd := exec.Command(c.binary, c.args...) // #nosec G204 the binaries are built by the harness
+		cmd.Env = append(os.Environ(), "WATCH_NAMESPACE=")
+
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return err
+		}
+
+		cmd.Stderr = cmd.Stdout
+
+		if err := cmd.Start(); err != nil {
+			cancel()
+
+			return fmt.Errorf("failed to start %v: %w", c.name, err)
+		}
+
+		klog.Infof("started %v, pid %v", c.name, cmd.Process.Pid)
+
+		go prefixLines(c.name, stdout, os.Stdout)
+
+		wg.Add(1)
+
+		go func(name string, cmd *exec.Cmd) {
+			defer wg.Done()
+
+			done := make(chan error, 1)
+			go func() { done <- cmd.Wait() }()
+
+			select {
+			case err := <-done:
+				// a component exiting stops the others
+				errOnce.Do(func() { firstErr = fmt.Errorf("%v exited: %v", name, err) })
+				cancel()
+			case <-ctx.Done():
+				_ = cmd.Process.Signal(os.Interrupt)
+				<-done
+			}
+		}(c.name, cmd)
+	}
+
+	wg.Wait()
+
+	return firstErr
+}
+
+// runDown deletes the kind clusters and the directory of the harness
+func runDown(args []string) error {
+	fs := pflag.NewFlagSet("down", pflag.ExitOnError)
+	dir := fs.String("dir", ".localrun", "The directory of the kubeconfigs and of the binaries.")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	for _, cluster := range []string{hubCluster, managedCluster} {
+		if err := run("kind", "delete", "cluster", "--name", cluster); err != nil {
+			return err
+		}
+	}
+
+	return os.RemoveAll(*dir)
+}
+
+// prefixLines copies the lines of the component output with its name as prefix
+func prefixLines(name string, r io.Reader, w io.Writer) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		fmt.Fprintf(w, "[%v] %v\n", name, scanner.Text())
+	}
+}
+
+func kubectl(kubeconfig string, args ...string) error {
+	return run("kubectl", append([]string{"--kubeconfig", kubeconfig}, args...)...)
+}
+
+func run(name string, args ...string) error {
+	cmd := exec.Command(name, args...) // #nosec G204 the harness runs the kind, kubectl and go tools
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
+
+func output(name string, args ...string) (string, error) {
+	out, err := exec.Command(name, args...).Output() // #nosec G204 the harness runs the kind and kubectl tools
+	if err != nil {
+		return "", fmt.Errorf("%v %v failed: %w", name, strings.Join(args, " "), err)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+func containsLine(lines, line string) bool {
+	for _, l := range strings.Split(lines, "\n") {
+		if strings.TrimSpace(l) == line {
+			return true
+		}
+	}
+
+	return false
+}
+
+func poll(timeout time.Duration, condition func() bool) error {
+	deadline := time.Now().Add(timeout)
+
+	for !condition() {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out after %v", timeout)
+		}
+
+		time.Sleep(5 * time.Second)
+	}
+
+	return nil
+}
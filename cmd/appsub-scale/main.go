@@ -0,0 +1,239 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	subapis "open-cluster-management.io/multicloud-operators-subscription/pkg/apis"
+	ansiblejob "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/ansible/v1alpha1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/controller/mcmhub"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/scale"
+)
+
+const usage = `Usage:
+  appsub-scale run [--subscriptions <n>] [--channels <n>] [--clusters <n> | --cluster-selector <selector>] [--envtest] [--metrics-url <url>] [--report <report.yaml>]
+  appsub-scale cleanup [--namespace <ns>] [--run-id <id>]
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(1)
+	}
+
+	var err error
+
+	switch os.Args[1] {
+	case "run":
+		err = runScale(os.Args[2:])
+	case "cleanup":
+		err = runCleanup(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(1)
+	}
+
+	if err != nil {
+		klog.Error(err)
+		os.Exit(1)
+	}
+}
+
+func newScheme() (*runtime.Scheme, error) {
+	scheme := runtime.NewScheme()
+
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	if err := ansiblejob.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	if err := subapis.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	return scheme, nil
+}
+
+func runScale(args []string) error {
+	fs := pflag.NewFlagSet("run", pflag.ExitOnError)
+	opts := scale.Options{}
+
+	fs.StringVar(&opts.RunID, "run-id", fmt.Sprintf("scale-%d", time.Now().Unix()),
+		"The run ID naming and labeling the synthetic objects.")
+	fs.StringVar(&opts.Namespace, "namespace", "appsub-scale", "The namespace of the synthetic channels and subscriptions.")
+	fs.IntVar(&opts.Subscriptions, "subscriptions", 100, "The number of synthetic subscriptions.")
+	fs.IntVar(&opts.Channels, "channels", 10, "The number of synthetic channels, the subscriptions are spread over them.")
+	fs.IntVar(&opts.Clusters, "clusters", 10, "The number of synthetic managed clusters, without agent.")
+	fs.StringVar(&opts.ClusterSelector, "cluster-selector", "",
+		"Place the subscriptions on the existing managed clusters of this label selector instead of synthetic clusters.")
+	fs.StringVar(&opts.ChannelType, "channel-type", chnv1.ChannelTypeNamespace, "The type of the synthetic channels.")
+	fs.StringVar(&opts.ChannelPathname, "channel-pathname", "",
+		"The pathname of the synthetic channels. The namespace of the subscriptions by default.")
+	fs.DurationVar(&opts.CreateInterval, "create-interval", 0, "The pause between the creations of the subscriptions.")
+	fs.BoolVar(&opts.WaitApplied, "wait-applied", false,
+		"Wait for the work agents of the clusters to apply the manifestWorks, the synthetic clusters have no agent.")
+	fs.DurationVar(&opts.Timeout, "timeout", 10*time.Minute, "How long to wait for the propagation of the subscriptions.")
+
+	useEnvtest := fs.Bool("envtest", false,
+		"Run the hub subscription controller in the harness against an envtest API server, KUBEBUILDER_ASSETS must be set.")
+	crdDirs := fs.StringSlice("crd-dir", []string{filepath.Join("deploy", "crds"), filepath.Join("hack", "test")},
+		"The CRD folders installed in the envtest API server. Can be repeated.")
+	metricsURL := fs.String("metrics-url", "", "The metrics endpoint of the hub controllers to sample their memory.")
+	reportFile := fs.String("report", "", "The report file to write. Standard output by default.")
+	cleanup := fs.Bool("cleanup", false, "Delete the synthetic objects after the run.")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if opts.ChannelPathname == "" {
+		opts.ChannelPathname = opts.Namespace
+	}
+
+	scheme, err := newScheme()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var cfg *rest.Config
+
+	if *useEnvtest {
+		env := &envtest.Environment{CRDDirectoryPaths: *crdDirs, ErrorIfCRDPathMissing: true}
+
+		if cfg, err = env.Start(); err != nil {
+			return fmt.Errorf("failed to start envtest, err: %w", err)
+		}
+
+		defer func() {
+			if err := env.Stop(); err != nil {
+				klog.Errorf("failed to stop envtest, err: %v", err)
+			}
+		}()
+
+		if err := startHubController(ctx, cfg, scheme); err != nil {
+			return err
+		}
+
+		opts.MemorySampler = scale.ProcessMemorySampler()
+	} else if cfg, err = config.GetConfig(); err != nil {
+		return err
+	}
+
+	if *metricsURL != "" {
+		opts.MemorySampler = scale.MetricsMemorySampler(*metricsURL)
+	}
+
+	c, err := client.NewWithWatch(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+
+	report, err := scale.Run(ctx, c, opts)
+	if err != nil {
+		return err
+	}
+
+	if *cleanup {
+		if err := scale.Cleanup(ctx, c, opts.Namespace, opts.RunID); err != nil {
+			klog.Errorf("failed to clean up run %v, err: %v", opts.RunID, err)
+		}
+	}
+
+	data, err := yaml.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	if *reportFile == "" {
+		_, err = os.Stdout.Write(data)
+
+		return err
+	}
+
+	klog.Infof("propagated %v of %v subscriptions to %v clusters, p99 latency %v, report written to %v",
+		report.Propagated, report.Subscriptions, report.Clusters, report.PropagationLatency.P99, *reportFile)
+
+	return os.WriteFile(*reportFile, data, 0600)
+}
+
+// startHubController runs the hub subscription controller propagating the subscriptions to the manifestWorks
+func startHubController(ctx context.Context, cfg *rest.Config, scheme *runtime.Scheme) error {
+	mgr, err := manager.New(cfg, manager.Options{Scheme: scheme, MetricsBindAddress: "0"})
+	if err != nil {
+		return err
+	}
+
+	if err := mcmhub.Add(mgr); err != nil {
+		return err
+	}
+
+	go func() {
+		if err := mgr.Start(ctx); err != nil {
+			klog.Errorf("failed to start the hub controller, err: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+func runCleanup(args []string) error {
+	fs := pflag.NewFlagSet("cleanup", pflag.ExitOnError)
+	namespace := fs.String("namespace", "appsub-scale", "The namespace of the synthetic channels and subscriptions.")
+	runID := fs.String("run-id", "", "Delete the synthetic objects of this run only. All the runs by default.")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	scheme, err := newScheme()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+
+	return scale.Cleanup(context.TODO(), c, *namespace, *runID)
+}
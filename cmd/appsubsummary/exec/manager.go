@@ -15,6 +15,7 @@
 package exec
 
 import (
+	"crypto/tls"
 	"fmt"
 	"os"
 
@@ -24,7 +25,9 @@ import (
 	appsubapi "open-cluster-management.io/multicloud-operators-subscription/pkg/apis"
 	managedClusterView "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/view/v1beta1"
 	"open-cluster-management.io/multicloud-operators-subscription/pkg/controller"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/controller/appsubsummary"
 	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils/tlsconfig"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
@@ -40,6 +43,11 @@ var (
 
 // RunManager starts the actual manager.
 func RunManager() {
+	if err := tlsconfig.Configure(options.TLS); err != nil {
+		klog.Error(err, "")
+		os.Exit(1)
+	}
+
 	enableLeaderElection := false
 
 	if _, err := rest.InClusterConfig(); err == nil {
@@ -65,6 +73,8 @@ func RunManager() {
 		}
 	}
 
+	tlsconfig.ApplyRestConfig(cfg)
+
 	klog.Info("Leader election settings",
 		"leaseDuration", options.LeaderElectionLeaseDuration,
 		"renewDeadline", options.LeaderElectionRenewDeadline,
@@ -80,8 +90,11 @@ func RunManager() {
 		LeaseDuration:           &options.LeaderElectionLeaseDuration,
 		RenewDeadline:           &options.LeaderElectionRenewDeadline,
 		RetryPeriod:             &options.LeaderElectionRetryPeriod,
-		WebhookServer:           &k8swebhook.Server{TLSMinVersion: "1.3"},
-		ClientDisableCacheFor:   []client.Object{&corev1.Secret{}, &corev1.ServiceAccount{}},
+		WebhookServer: &k8swebhook.Server{
+			TLSMinVersion: tlsconfig.MinVersionName(tls.VersionTLS13),
+			TLSOpts:       []func(*tls.Config){tlsconfig.Apply},
+		},
+		ClientDisableCacheFor: []client.Object{&corev1.Secret{}, &corev1.ServiceAccount{}},
 	})
 	if err != nil {
 		klog.Error(err, "")
@@ -102,6 +115,10 @@ func RunManager() {
 		os.Exit(1)
 	}
 
+	appsubsummary.SetTelemetrySummary(options.TelemetrySummary, options.TelemetryInterval)
+	appsubsummary.SetSubscriptionHistoryLimit(options.HistoryLimit)
+	appsubsummary.SetSubscriptionWebhookAllowInternal(options.WebhookAllowInternal)
+
 	// Setup all Controllers.
 	if err := controller.AddAppSubSummaryToManager(mgr, options.SyncInterval); err != nil {
 		klog.Error(err, "")
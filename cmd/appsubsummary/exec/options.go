@@ -18,6 +18,8 @@ import (
 	"time"
 
 	pflag "github.com/spf13/pflag"
+
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils/tlsconfig"
 )
 
 // AppSubStatusCMDOptions for command line flag parsing.
@@ -28,6 +30,11 @@ type AppSubStatusCMDOptions struct {
 	LeaderElectionLeaseDuration time.Duration
 	LeaderElectionRenewDeadline time.Duration
 	LeaderElectionRetryPeriod   time.Duration
+	TelemetrySummary            bool
+	TelemetryInterval           time.Duration
+	HistoryLimit                int
+	WebhookAllowInternal        bool
+	TLS                         tlsconfig.Options
 }
 
 var options = AppSubStatusCMDOptions{
@@ -37,6 +44,9 @@ var options = AppSubStatusCMDOptions{
 	LeaderElectionLeaseDuration: 137 * time.Second,
 	LeaderElectionRenewDeadline: 107 * time.Second,
 	LeaderElectionRetryPeriod:   26 * time.Second,
+	TelemetrySummary:            false,
+	TelemetryInterval:           10 * time.Minute,
+	HistoryLimit:                50,
 }
 
 // ProcessFlags parses command line parameters into options.
@@ -91,4 +101,35 @@ func ProcessFlags() {
 		"The duration the clients should wait between attempting acquisition and renewal "+
 			"of a leadership. This is only applicable if leader election is enabled.",
 	)
+
+	flag.BoolVar(
+		&options.TelemetrySummary,
+		"telemetry-summary",
+		options.TelemetrySummary,
+		"Write the redacted summary of the subscriptions, the channels and the agents of the hub to the "+
+			"subscription-telemetry SubscriptionTelemetry for the support bundles.",
+	)
+
+	flag.DurationVar(
+		&options.TelemetryInterval,
+		"telemetry-interval",
+		options.TelemetryInterval,
+		"The interval of the telemetry summary.",
+	)
+
+	flag.IntVar(
+		&options.HistoryLimit,
+		"subscription-history-limit",
+		options.HistoryLimit,
+		"The number of the events kept in the SubscriptionHistory of each subscription, 0 disables the histories.",
+	)
+
+	flag.BoolVar(
+		&options.WebhookAllowInternal,
+		"subscription-webhook-allow-internal",
+		options.WebhookAllowInternal,
+		"Allow the SubscriptionWebhooks to post to the loopback, link-local, private and cluster service addresses.",
+	)
+
+	options.TLS.AddFlags(flag)
 }
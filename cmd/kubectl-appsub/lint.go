@@ -0,0 +1,58 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/lint"
+)
+
+func runLint(args []string) error {
+	fs := pflag.NewFlagSet("lint", pflag.ExitOnError)
+	files := fs.StringSliceP("filename", "f", nil, "The files or folders of the manifests. Can be repeated, - is the standard input.")
+	strict := fs.Bool("strict", false, "Fail on the warnings too.")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	docs, err := readDocuments(*files)
+	if err != nil {
+		return err
+	}
+
+	linter := lint.NewLinter()
+
+	for _, doc := range docs {
+		if err := linter.Add(doc.file, doc.data); err != nil {
+			return fmt.Errorf("failed to parse %v, err: %w", doc.file, err)
+		}
+	}
+
+	issues := linter.Lint()
+
+	for _, issue := range issues {
+		fmt.Println(issue.String())
+	}
+
+	if lint.HasErrors(issues) || (*strict && len(issues) > 0) {
+		return fmt.Errorf("found %v issues", len(issues))
+	}
+
+	return nil
+}
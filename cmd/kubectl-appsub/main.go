@@ -0,0 +1,54 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"k8s.io/klog/v2"
+)
+
+const usage = `Usage:
+  kubectl-appsub lint -f <manifests.yaml|folder>... [--strict]
+  kubectl-appsub plan [-f <manifests.yaml>...] [--subscription <ns>/<name>] [--kubeconfig <file>] [--skip-revision] [--output text|json]
+  kubectl-appsub render -f <manifests.yaml>... [--subscription <ns>/<name>] [--cluster-admin] [--work-dir <dir>] [--output <file>]
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(1)
+	}
+
+	var err error
+
+	switch os.Args[1] {
+	case "lint":
+		err = runLint(os.Args[2:])
+	case "plan":
+		err = runPlan(os.Args[2:])
+	case "render":
+		err = runRender(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(1)
+	}
+
+	if err != nil {
+		klog.Error(err)
+		os.Exit(1)
+	}
+}
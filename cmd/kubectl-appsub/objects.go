@@ -0,0 +1,211 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	subapis "open-cluster-management.io/multicloud-operators-subscription/pkg/apis"
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+// objects are the subscriptions, channels and their references read from the manifest files
+type objects struct {
+	subscriptions []*appv1.Subscription
+	channels      []*chnv1.Channel
+	others        []client.Object
+}
+
+// document is a YAML document of a manifest file
+type document struct {
+	file string
+	data []byte
+}
+
+// readDocuments reads the multi-document YAML files, the folders are walked for the .yaml and .yml files and "-" is
+// the standard input
+func readDocuments(files []string) ([]document, error) {
+	docs := []document{}
+
+	for _, file := range files {
+		paths := []string{file}
+
+		if fi, err := os.Stat(file); err == nil && fi.IsDir() {
+			paths = nil
+
+			err := filepath.WalkDir(file, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+
+				ext := strings.ToLower(filepath.Ext(path))
+				if !d.IsDir() && (ext == ".yaml" || ext == ".yml") {
+					paths = append(paths, path)
+				}
+
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for _, path := range paths {
+			var (
+				data []byte
+				err  error
+			)
+
+			if path == "-" {
+				data, err = io.ReadAll(os.Stdin)
+			} else {
+				data, err = os.ReadFile(path) // #nosec G304 the file is given by the user
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			for _, item := range utils.ParseKubeResoures(data) {
+				docs = append(docs, document{file: path, data: item})
+			}
+		}
+	}
+
+	return docs, nil
+}
+
+// readObjects reads the objects of the manifest files
+func readObjects(files []string) (*objects, error) {
+	docs, err := readDocuments(files)
+	if err != nil {
+		return nil, err
+	}
+
+	objs := &objects{}
+
+	for _, doc := range docs {
+		if err := objs.add(doc.data); err != nil {
+			return nil, fmt.Errorf("failed to parse %v, err: %w", doc.file, err)
+		}
+	}
+
+	return objs, nil
+}
+
+func (o *objects) add(item []byte) error {
+	u := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal(item, u); err != nil {
+		return err
+	}
+
+	var obj client.Object
+
+	switch u.GetKind() {
+	case "Subscription":
+		sub := &appv1.Subscription{}
+		o.subscriptions = append(o.subscriptions, sub)
+		obj = sub
+	case "Channel":
+		chn := &chnv1.Channel{}
+		o.channels = append(o.channels, chn)
+		obj = chn
+	case "Secret":
+		obj = &corev1.Secret{}
+		o.others = append(o.others, obj)
+	case "ConfigMap":
+		obj = &corev1.ConfigMap{}
+		o.others = append(o.others, obj)
+	default:
+		return nil
+	}
+
+	return yaml.Unmarshal(item, obj)
+}
+
+// subscription returns the subscription of the namespaced name, the only subscription if the name is empty
+func (o *objects) subscription(nsName string) (*appv1.Subscription, error) {
+	if nsName == "" {
+		if len(o.subscriptions) != 1 {
+			return nil, fmt.Errorf("found %v subscriptions, select one with --subscription", len(o.subscriptions))
+		}
+
+		return o.subscriptions[0], nil
+	}
+
+	for _, sub := range o.subscriptions {
+		if sub.Namespace+"/"+sub.Name == nsName {
+			return sub, nil
+		}
+	}
+
+	return nil, fmt.Errorf("subscription %v not found", nsName)
+}
+
+// channel returns the channel of the subscription, the channel namespace defaults to the subscription namespace
+func (o *objects) channel(sub *appv1.Subscription) (*chnv1.Channel, error) {
+	chnNsName := sub.Spec.Channel
+	if !strings.Contains(chnNsName, "/") {
+		chnNsName = sub.Namespace + "/" + chnNsName
+	}
+
+	for _, chn := range o.channels {
+		if chn.Namespace+"/"+chn.Name == chnNsName {
+			return chn, nil
+		}
+	}
+
+	return nil, fmt.Errorf("channel %v of subscription %v/%v not found", chnNsName, sub.Namespace, sub.Name)
+}
+
+// client returns a client serving the objects read from the files
+func (o *objects) client() (client.Client, error) {
+	scheme := runtime.NewScheme()
+
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	if err := subapis.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+
+	for _, chn := range o.channels {
+		builder = builder.WithObjects(chn)
+	}
+
+	for _, sub := range o.subscriptions {
+		builder = builder.WithObjects(sub)
+	}
+
+	return builder.WithObjects(o.others...).Build(), nil
+}
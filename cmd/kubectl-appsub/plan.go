@@ -0,0 +1,229 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	subapis "open-cluster-management.io/multicloud-operators-subscription/pkg/apis"
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/controller/mcmhub"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/render"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+// plan is the output of the plan subcommand
+type plan struct {
+	Subscription string `json:"subscription"`
+	// Revision is the git commit or the chart versions, empty if the revision is not resolved
+	Revision  string `json:"revision,omitempty"`
+	Resources int    `json:"resources"`
+	*mcmhub.PropagationPlan
+}
+
+func runPlan(args []string) error {
+	fs := pflag.NewFlagSet("plan", pflag.ExitOnError)
+	files := fs.StringSliceP("filename", "f", nil, "The files of the subscription to plan instead of the subscription on the hub, "+
+		"and of its channel if it is not on the hub. Can be repeated, - is the standard input.")
+	subName := fs.String("subscription", "",
+		"The <namespace>/<name> of the subscription. Required if the files don't have one subscription.")
+	kubeconfig := fs.String("kubeconfig", "", "The kubeconfig of the hub. The default loading rules of kubectl are used by default.")
+	skipRevision := fs.Bool("skip-revision", false, "Don't resolve the revision and the resources of the channel.")
+	workDir := fs.String("work-dir", "", "The folder of the git clone and the chart downloads. A temporary folder by default.")
+	output := fs.StringP("output", "o", "text", "The output format, text or json.")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *output != "text" && *output != "json" {
+		return fmt.Errorf("unsupported output %v, it must be text or json", *output)
+	}
+
+	clt, err := hubClient(*kubeconfig)
+	if err != nil {
+		return err
+	}
+
+	sub, chn, err := planSubscription(clt, *files, *subName)
+	if err != nil {
+		return err
+	}
+
+	p := &plan{Subscription: sub.Namespace + "/" + sub.Name}
+
+	if !*skipRevision {
+		res, err := render.Render(render.Options{
+			Subscription: sub,
+			Channel:      chn,
+			Client:       clt,
+			WorkDir:      *workDir,
+			ClusterAdmin: utils.IsClusterAdmin(clt, sub, nil),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to resolve the revision of subscription %v: %w", p.Subscription, err)
+		}
+
+		p.Revision = res.Revision
+		p.Resources = len(res.Manifests)
+	}
+
+	if p.PropagationPlan, err = mcmhub.PlanPropagation(clt, sub, p.Resources, time.Now()); err != nil {
+		return err
+	}
+
+	if *output == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(p)
+	}
+
+	printPlan(os.Stdout, p)
+
+	return nil
+}
+
+// hubClient returns a client of the hub of the kubeconfig
+func hubClient(kubeconfig string) (client.Client, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = kubeconfig
+
+	cfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the kubeconfig of the hub: %w", err)
+	}
+
+	scheme := runtime.NewScheme()
+
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	if err := subapis.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	return client.New(cfg, client.Options{Scheme: scheme})
+}
+
+// planSubscription returns the subscription of the files, or of the hub without files, and its channel from the files
+// or from the hub
+func planSubscription(clt client.Client, files []string, subName string) (*appv1.Subscription, *chnv1.Channel, error) {
+	objs := &objects{}
+
+	if len(files) > 0 {
+		var err error
+
+		if objs, err = readObjects(files); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		nn := utils.NamespacedNameFormat(subName)
+		if nn.Name == "" {
+			return nil, nil, fmt.Errorf("--subscription <namespace>/<name> is required without files")
+		}
+
+		sub := &appv1.Subscription{}
+		if err := clt.Get(context.TODO(), nn, sub); err != nil {
+			return nil, nil, fmt.Errorf("failed to get subscription %v: %w", subName, err)
+		}
+
+		objs.subscriptions = append(objs.subscriptions, sub)
+	}
+
+	sub, err := objs.subscription(subName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if chn, err := objs.channel(sub); err == nil {
+		return sub, chn, nil
+	}
+
+	chn, _, err := mcmhub.GetSubscriptionRefChannel(clt, sub)
+	if err == nil && chn == nil {
+		err = fmt.Errorf("channel %v not found", sub.Spec.Channel)
+	}
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get the channel of subscription %v/%v: %w", sub.Namespace, sub.Name, err)
+	}
+
+	return sub, chn, nil
+}
+
+func printPlan(w io.Writer, p *plan) {
+	fmt.Fprintf(w, "Subscription %v\n", p.Subscription)
+
+	if p.Reason != "" {
+		fmt.Fprintf(w, "Would not propagate: %v\n", p.Reason)
+
+		return
+	}
+
+	revision := ""
+	if p.Revision != "" {
+		revision = " revision " + p.Revision
+	}
+
+	fmt.Fprintf(w, "Would deploy%v (%v resources) to %v clusters in %v waves\n",
+		revision, p.Resources, p.ClusterCount(), len(p.Waves))
+
+	if p.ClusterAdmin {
+		fmt.Fprintln(w, "The subscription is propagated with the cluster-admin annotation")
+	}
+
+	for i, wave := range p.Waves {
+		names := []string{}
+		overridden := []string{}
+
+		for _, cluster := range wave.Clusters {
+			names = append(names, cluster.Name)
+
+			if cluster.PackageOverrides != nil || cluster.GitBranch != "" || cluster.GitPath != "" || cluster.GitCommit != "" {
+				overridden = append(overridden, cluster.Name)
+			}
+		}
+
+		group := ""
+		if wave.DecisionGroup != "" {
+			group = " decision group " + wave.DecisionGroup
+		}
+
+		fmt.Fprintf(w, "Wave %v%v: %v\n", i+1, group, strings.Join(names, ", "))
+
+		if len(overridden) > 0 {
+			fmt.Fprintf(w, "  override rules or promotion pins apply to: %v\n", strings.Join(overridden, ", "))
+		}
+	}
+
+	if p.NextWindow != nil {
+		fmt.Fprintf(w, "The timewindow blocks the deployment until %v\n", p.NextWindow.Format(time.RFC3339))
+	}
+}
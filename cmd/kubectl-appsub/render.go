@@ -0,0 +1,84 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+
+	"github.com/spf13/pflag"
+
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/render"
+)
+
+func runRender(args []string) error {
+	fs := pflag.NewFlagSet("render", pflag.ExitOnError)
+	files := fs.StringSliceP("filename", "f", nil, "The files of the subscription, its channel and the channel secret and configmap. Can be repeated, - is the standard input.")
+	subName := fs.String("subscription", "", "The <namespace>/<name> of the subscription to render. Required if the files have several subscriptions.")
+	clusterAdmin := fs.Bool("cluster-admin", false, "Keep the namespaces of the manifests like a subscription of a subscription admin.")
+	workDir := fs.String("work-dir", "", "The folder of the git clone and the chart downloads. A temporary folder by default.")
+	output := fs.StringP("output", "o", "", "The file of the rendered manifests. Standard output by default.")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	objs, err := readObjects(*files)
+	if err != nil {
+		return err
+	}
+
+	sub, err := objs.subscription(*subName)
+	if err != nil {
+		return err
+	}
+
+	chn, err := objs.channel(sub)
+	if err != nil {
+		return err
+	}
+
+	c, err := objs.client()
+	if err != nil {
+		return err
+	}
+
+	res, err := render.Render(render.Options{
+		Subscription: sub,
+		Channel:      chn,
+		Client:       c,
+		WorkDir:      *workDir,
+		ClusterAdmin: *clusterAdmin,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := render.ToYAML(res.Manifests)
+	if err != nil {
+		return err
+	}
+
+	if res.Revision != "" {
+		data = append([]byte("# revision: "+res.Revision+"\n"), data...)
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(data)
+
+		return err
+	}
+
+	return os.WriteFile(*output, data, 0600)
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -44,11 +45,15 @@ import (
 	agentaddon "open-cluster-management.io/multicloud-operators-subscription/addon"
 	"open-cluster-management.io/multicloud-operators-subscription/pkg/apis"
 	ansiblejob "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/ansible/v1alpha1"
+	appsubv1beta1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1beta1"
 	"open-cluster-management.io/multicloud-operators-subscription/pkg/controller"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/controller/mcmhub"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/controller/spoketoken"
 	leasectrl "open-cluster-management.io/multicloud-operators-subscription/pkg/controller/subscription"
 	"open-cluster-management.io/multicloud-operators-subscription/pkg/subscriber"
 	"open-cluster-management.io/multicloud-operators-subscription/pkg/synchronizer"
 	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils/tlsconfig"
 	"open-cluster-management.io/multicloud-operators-subscription/pkg/webhook"
 	k8swebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
 )
@@ -63,9 +68,32 @@ var (
 const (
 	AddonName               = "application-manager"
 	leaseUpdateJitterFactor = 0.25
+	webhookCertDir          = "/tmp/k8s-webhook-server/serving-certs"
 )
 
 func RunManager() {
+	if err := tlsconfig.Configure(Options.TLS); err != nil {
+		klog.Error(err, "")
+		os.Exit(1)
+	}
+
+	if err := utils.SetFeatureGates(Options.FeatureGates); err != nil {
+		klog.Error("Failed to set the feature gates, error:", err)
+		os.Exit(1)
+	}
+
+	klog.Info("Feature gates: ", utils.FeatureGateStates())
+
+	utils.SetGitPathPolicy(utils.GitPathPolicy{
+		AllowExternalSymlinks: Options.GitAllowExternalSymlinks,
+		AllowHiddenFiles:      Options.GitAllowHiddenFiles,
+	})
+
+	if err := utils.SetFaultInjection(Options.FaultInjection); err != nil {
+		klog.Error("Failed to set the fault injection, error:", err)
+		os.Exit(1)
+	}
+
 	enableLeaderElection := false
 
 	if _, err := rest.InClusterConfig(); err == nil {
@@ -91,9 +119,6 @@ func RunManager() {
 
 	klog.Info("kubeconfig:" + Options.KubeConfig)
 
-	// increase the dafault QPS(5) to 100, only sends 5 requests to API server
-	// seems to be unrealistic. Reading some other projects, it seems QPS 100 is
-	// a pretty common practice
 	var err error
 
 	cfg := ctrl.GetConfigOrDie()
@@ -107,8 +132,24 @@ func RunManager() {
 		}
 	}
 
-	cfg.QPS = 100.0
-	cfg.Burst = 200
+	tlsconfig.ApplyRestConfig(cfg)
+
+	// the user-agents identify the requests of the operator and its controllers in the audit logs and the API
+	// Priority and Fairness metrics, the status writes have their own rate limiter
+	component := "hub-subscription"
+	if Options.Standalone {
+		component = "standalone-subscription"
+	} else if Options.ClusterName != "" {
+		component = AddonName
+	}
+
+	utils.ConfigureAPIClient(cfg, utils.APIClientOptions{
+		Component:   component,
+		QPS:         Options.KubeAPIQPS,
+		Burst:       Options.KubeAPIBurst,
+		StatusQPS:   Options.KubeAPIStatusQPS,
+		StatusBurst: Options.KubeAPIStatusBurst,
+	})
 
 	klog.Info("Leader election settings",
 		"leaseDuration", Options.LeaderElectionLeaseDuration,
@@ -125,8 +166,13 @@ func RunManager() {
 		LeaseDuration:           &Options.LeaderElectionLeaseDuration,
 		RenewDeadline:           &Options.LeaderElectionRenewDeadline,
 		RetryPeriod:             &Options.LeaderElectionRetryPeriod,
-		WebhookServer:           &k8swebhook.Server{TLSMinVersion: "1.3"},
-		ClientDisableCacheFor:   []client.Object{&corev1.Secret{}, &corev1.ServiceAccount{}},
+		WebhookServer: &k8swebhook.Server{
+			CertDir:       webhookCertDir,
+			TLSMinVersion: tlsconfig.MinVersionName(tls.VersionTLS13),
+			TLSOpts:       []func(*tls.Config){tlsconfig.Apply},
+		},
+		ClientDisableCacheFor: []client.Object{&corev1.Secret{}, &corev1.ServiceAccount{}},
+		NewClient:             utils.NewManagerClient,
 	})
 
 	if err != nil {
@@ -134,6 +180,26 @@ func RunManager() {
 		os.Exit(1)
 	}
 
+	if Options.WebhookService != "" {
+		if err := addWebhookCertRotator(mgr); err != nil {
+			klog.Error("Failed to set up the webhook certificate rotator, error:", err)
+			os.Exit(1)
+		}
+	}
+
+	if err := addControllerConfigWatcher(mgr); err != nil {
+		klog.Error("Failed to set up the controller config watcher, error:", err)
+		os.Exit(1)
+	}
+
+	if Options.ProfilingAddr != "" {
+		go func() {
+			if err := utils.ServeProfiling(Options.ProfilingAddr); err != nil {
+				klog.Error("Failed to serve the profiling endpoints, error:", err)
+			}
+		}()
+	}
+
 	// id is the namespacedname of this cluster in hub
 	var id = &types.NamespacedName{
 		Name:      Options.ClusterName,
@@ -150,6 +216,9 @@ func RunManager() {
 			klog.Error("Failed to build config to hub cluster with the pathname provided ", Options.HubConfigFilePathName, " err:", err)
 			os.Exit(1)
 		}
+
+		hubconfig.UserAgent = utils.APIUserAgent(AddonName, "hub", false)
+		tlsconfig.ApplyRestConfig(hubconfig)
 	}
 
 	klog.Info("Starting ... Registering Components for cluster: ", id)
@@ -185,12 +254,36 @@ func RunManager() {
 			os.Exit(1)
 		}
 
+		mcmhub.SetPropagationAccessReview(Options.PropagationAccessReview)
+		mcmhub.SetHubName(Options.HubName)
+		mcmhub.SetAgentHeartbeatTimeout(Options.AgentHeartbeatTimeout)
+		mcmhub.SetAgentVersionGating(Options.AgentVersionGating)
+
 		// Setup all Hub Controllers
 		if err := controller.AddHubToManager(mgr); err != nil {
 			klog.Error(err, "")
 			os.Exit(1)
 		}
 
+		// Setup the conversion webhooks of the v1beta1 subscriptions and channels and the validating webhook of the
+		// subscriptions, the webhook server needs a serving certificate
+		if Options.WebhookService != "" || hasWebhookCerts() {
+			if err := (&appsubv1beta1.Subscription{}).SetupWebhookWithManager(mgr); err != nil {
+				klog.Error("Failed to set up the subscription conversion webhook, error:", err)
+				os.Exit(1)
+			}
+
+			if err := (&appsubv1beta1.Channel{}).SetupWebhookWithManager(mgr); err != nil {
+				klog.Error("Failed to set up the channel conversion webhook, error:", err)
+				os.Exit(1)
+			}
+
+			if err := mcmhub.SetupSubscriptionValidatorWithManager(mgr); err != nil {
+				klog.Error("Failed to set up the subscription validating webhook, error:", err)
+				os.Exit(1)
+			}
+		}
+
 		if !Options.Debug {
 			// Setup Webhook listner
 			if err := webhook.AddToManager(mgr, hubconfig, Options.TLSKeyFilePathName, Options.TLSCrtFilePathName, Options.DisableTLS, true); err != nil {
@@ -205,6 +298,31 @@ func RunManager() {
 			os.Exit(1)
 		}
 
+		additionalHubs, err := utils.ParseAdditionalHubConfigs(Options.AdditionalHubConfigs, Options.ClusterName)
+		if err != nil {
+			klog.Error("Failed to parse additional hub configs, error:", err)
+			os.Exit(1)
+		}
+
+		if err := utils.LoadHubConfigs(additionalHubs); err != nil {
+			klog.Error(err)
+			os.Exit(1)
+		}
+
+		utils.SetAdditionalHubConfigs(additionalHubs)
+
+		spoketoken.SetClusterSecretServer(spoketoken.ServerConfig{
+			URL:        Options.ClusterSecretServerURL,
+			ServerName: Options.ClusterSecretServerName,
+			CAFile:     Options.ClusterSecretCAFile,
+		})
+
+		spoketoken.SetClusterSecretServiceAccount(spoketoken.ScopedServiceAccount{
+			Name:        Options.ClusterSecretSA,
+			ClusterRole: Options.ClusterSecretClusterRole,
+			Namespaces:  Options.ClusterSecretNamespaces,
+		})
+
 		if err := setupStandalone(mgr, hubconfig, id, false); err != nil {
 			klog.Error("Failed to setup managed subscription, error:", err)
 			os.Exit(1)
@@ -243,6 +361,56 @@ func RunManager() {
 		go wait.JitterUntilWithContext(context.TODO(), leaseReconciler.Reconcile,
 			time.Duration(Options.LeaseDurationSeconds)*time.Second, leaseUpdateJitterFactor, true)
 
+		// the heartbeat lease in the cluster namespace on the hub reports the version and the health of the agent, and the
+		// capabilities of the cluster the hub renders the helm charts with
+		heartbeatReconciler := leasectrl.HeartbeatReconciler{
+			HubKubeClient:           hubKubeClient,
+			ManagedClusterDiscovery: managedClusterKubeClient.Discovery(),
+			ClusterName:             Options.ClusterName,
+			LeaseDurationSeconds:    int32(Options.LeaseDurationSeconds),
+		}
+
+		go wait.JitterUntilWithContext(context.TODO(), heartbeatReconciler.Reconcile,
+			time.Duration(Options.LeaseDurationSeconds)*time.Second, leaseUpdateJitterFactor, true)
+
+		// each additional hub gets its own lease so that the addon status is reported to every hub independently
+		for _, hub := range additionalHubs {
+			additionalHubKubeClient, err := kubernetes.NewForConfig(hub.RestConfig)
+			if err != nil {
+				klog.Errorf("Failed to create kube client of additional hub %v, error: %v", hub.Name, err)
+				os.Exit(1)
+			}
+
+			additionalHubKubeConfigCheckSum, err := utils.GetCheckSum(hub.ConfigFilePathName)
+			if err != nil {
+				klog.Errorf("Failed to get the checksum of the kubeconfig file of additional hub %v, error: %v", hub.Name, err)
+				os.Exit(1)
+			}
+
+			additionalLeaseReconciler := leasectrl.LeaseReconciler{
+				HubKubeClient:         additionalHubKubeClient,
+				HubConfigFilePathName: hub.ConfigFilePathName,
+				HubConfigCheckSum:     additionalHubKubeConfigCheckSum,
+				KubeClient:            managedClusterKubeClient,
+				ClusterName:           hub.ClusterName,
+				LeaseName:             AddonName,
+				LeaseDurationSeconds:  int32(Options.LeaseDurationSeconds),
+			}
+
+			go wait.JitterUntilWithContext(context.TODO(), additionalLeaseReconciler.Reconcile,
+				time.Duration(Options.LeaseDurationSeconds)*time.Second, leaseUpdateJitterFactor, true)
+
+			additionalHeartbeatReconciler := leasectrl.HeartbeatReconciler{
+				HubKubeClient:           additionalHubKubeClient,
+				ManagedClusterDiscovery: managedClusterKubeClient.Discovery(),
+				ClusterName:             hub.ClusterName,
+				LeaseDurationSeconds:    int32(Options.LeaseDurationSeconds),
+			}
+
+			go wait.JitterUntilWithContext(context.TODO(), additionalHeartbeatReconciler.Reconcile,
+				time.Duration(Options.LeaseDurationSeconds)*time.Second, leaseUpdateJitterFactor, true)
+		}
+
 		// add liveness probe server
 		cc, err := addonutils.NewConfigChecker("managed-serviceaccount-agent", "/var/run/klusterlet/kubeconfig")
 		if err != nil {
@@ -288,7 +456,11 @@ func RunManager() {
 
 		klog.Infof("Agent image: %v", agentImage)
 
-		adddonmgr, err := agentaddon.NewAddonManager(cfg, agentImage, Options.AgentInstallAll)
+		adddonmgr, err := agentaddon.NewAddonManager(cfg, agentImage, Options.AgentInstallAll, agentaddon.ClusterProxyOptions{
+			ServerURL:   Options.ClusterProxyServerURL,
+			ServerName:  Options.ClusterProxyServerName,
+			CAConfigMap: Options.ClusterProxyCAConfigMap,
+		})
 		if err != nil {
 			klog.Error("Failed to setup addon manager, error:", err)
 			os.Exit(1)
@@ -334,6 +506,15 @@ func setupStandalone(mgr manager.Manager, hubconfig *rest.Config, id *types.Name
 		return err
 	}
 
+	// the agent throttles itself close to the memory and CPU limits of its cgroup
+	if Options.ResourceThrottleInterval > 0 {
+		if err := mgr.Add(utils.NewResourceThrottler(Options.ResourceThrottleInterval)); err != nil {
+			klog.Error("Failed to initialize the resource throttler with error:", err)
+
+			return err
+		}
+	}
+
 	if standalone && !Options.Debug {
 		// Setup Webhook listner
 		if err := webhook.AddToManager(mgr, hubconfig, Options.TLSKeyFilePathName, Options.TLSCrtFilePathName, Options.DisableTLS, false); err != nil {
@@ -358,12 +539,60 @@ func serveHealthProbes(healthProbeBindAddress string, configCheck healthz.Checke
 		Handler:           mux,
 		ReadHeaderTimeout: 5 * time.Second,
 		Addr:              healthProbeBindAddress,
-		TLSConfig: &tls.Config{
-			MinVersion: tls.VersionTLS12,
-		},
+		TLSConfig:         tlsconfig.NewConfig(tls.VersionTLS12),
 	}
 
 	klog.Infof("heath probes server is running...")
 
 	return server.ListenAndServe()
 }
+
+// addWebhookCertRotator rotates the serving certificate of the webhook service in the webhook server certificate directory
+func addWebhookCertRotator(mgr manager.Manager) error {
+	namespace, err := utils.GetComponentNamespace()
+	if err != nil {
+		return err
+	}
+
+	rotator := utils.NewCertRotator(mgr, Options.WebhookService, namespace, webhookCertDir)
+
+	if err := rotator.Rotate(context.TODO()); err != nil {
+		return err
+	}
+
+	return mgr.Add(rotator)
+}
+
+// addControllerConfigWatcher loads the subscription-controller-config ConfigMap of the operator namespace before the
+// controllers start and reloads it when it changes
+func addControllerConfigWatcher(mgr manager.Manager) error {
+	namespace, err := utils.GetComponentNamespace()
+	if err != nil {
+		klog.Infof("Not running in a pod, the controller config is read from namespace %v", namespace)
+	}
+
+	kubeClient, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+
+	watcher := utils.NewControllerConfigWatcher(kubeClient, namespace)
+
+	// the controllers start with their defaults if the ConfigMap can't be read, the watcher loads it later
+	if err := watcher.Load(context.TODO()); err != nil {
+		klog.Warning(err)
+	}
+
+	return mgr.Add(watcher)
+}
+
+// hasWebhookCerts checks if a serving certificate is mounted in the webhook server certificate directory, by cert-manager for example
+func hasWebhookCerts() bool {
+	for _, file := range []string{"tls.crt", "tls.key"} {
+		if _, err := os.Stat(filepath.Join(webhookCertDir, file)); err != nil {
+			return false
+		}
+	}
+
+	return true
+}
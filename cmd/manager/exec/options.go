@@ -18,6 +18,9 @@ import (
 	"time"
 
 	pflag "github.com/spf13/pflag"
+
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils/tlsconfig"
 )
 
 // SubscriptionCMDOptions for command line flag parsing
@@ -26,6 +29,7 @@ type SubscriptionCMDOptions struct {
 	KubeConfig                  string
 	ClusterName                 string
 	HubConfigFilePathName       string
+	AdditionalHubConfigs        []string
 	TLSKeyFilePathName          string
 	TLSCrtFilePathName          string
 	SyncInterval                int
@@ -38,6 +42,31 @@ type SubscriptionCMDOptions struct {
 	LeaderElectionRetryPeriod   time.Duration
 	Debug                       bool
 	AgentInstallAll             bool
+	ProfilingAddr               string
+	PropagationAccessReview     bool
+	HubName                     string
+	AgentHeartbeatTimeout       time.Duration
+	AgentVersionGating          bool
+	GitAllowExternalSymlinks    bool
+	GitAllowHiddenFiles         bool
+	ResourceThrottleInterval    time.Duration
+	FaultInjection              utils.FaultInjection
+	WebhookService              string
+	ClusterSecretServerURL      string
+	ClusterSecretServerName     string
+	ClusterSecretCAFile         string
+	ClusterSecretSA             string
+	ClusterSecretClusterRole    string
+	ClusterSecretNamespaces     []string
+	ClusterProxyServerURL       string
+	ClusterProxyServerName      string
+	ClusterProxyCAConfigMap     string
+	KubeAPIQPS                  float32
+	KubeAPIBurst                int
+	KubeAPIStatusQPS            float32
+	KubeAPIStatusBurst          int
+	FeatureGates                string
+	TLS                         tlsconfig.Options
 }
 
 var Options = SubscriptionCMDOptions{
@@ -51,6 +80,12 @@ var Options = SubscriptionCMDOptions{
 	Standalone:                  false,
 	AgentImage:                  "quay.io/open-cluster-management/multicloud-operators-subscription:latest",
 	Debug:                       false,
+	KubeAPIQPS:                  100.0,
+	KubeAPIBurst:                200,
+	KubeAPIStatusQPS:            50.0,
+	KubeAPIStatusBurst:          100,
+	AgentHeartbeatTimeout:       15 * time.Minute,
+	ResourceThrottleInterval:    utils.DefaultResourceThrottleInterval,
 }
 
 // ProcessFlags parses command line parameters into Options
@@ -78,6 +113,14 @@ func ProcessFlags() {
 		"Configuration file pathname to hub kubernetes cluster",
 	)
 
+	flag.StringSliceVar(
+		&Options.AdditionalHubConfigs,
+		"additional-hub-cluster-configfile",
+		Options.AdditionalHubConfigs,
+		"Additional hub the managed cluster syncs to, in the format of <hub-name>[/<cluster-name>]=<kubeconfig-path>. "+
+			"Can be repeated. The hub from --hub-cluster-configfile always takes precedence",
+	)
+
 	flag.StringVar(
 		&Options.ClusterName,
 		"cluster-name",
@@ -175,4 +218,216 @@ func ProcessFlags() {
 		false,
 		"Configure the install strategy of agent on managed clusters. "+
 			"Enabling this will automatically install agent on all managed cluster.")
+
+	flag.StringVar(
+		&Options.ProfilingAddr,
+		"profiling-addr",
+		Options.ProfilingAddr,
+		"The address the pprof, expvar and runtime debug endpoints bind to, e.g. localhost:6060. The endpoints are "+
+			"plain HTTP without authentication, bind them to localhost. The endpoints are disabled if it is empty.",
+	)
+
+	flag.BoolVar(
+		&Options.PropagationAccessReview,
+		"propagation-access-review",
+		false,
+		"Review with SubjectAccessReviews that the user who created a subscription can create its resource kinds in the "+
+			"managed cluster namespaces on the hub before the subscription is propagated.",
+	)
+
+	flag.StringVar(
+		&Options.HubName,
+		"hub-name",
+		"",
+		"The name of the hub set in the hub-name annotation of the subscriptions propagated to the managed clusters. "+
+			"It must match the hub name of the --additional-hub-cluster-configfile flag of the agents syncing to this hub.",
+	)
+
+	flag.DurationVar(
+		&Options.AgentHeartbeatTimeout,
+		"agent-heartbeat-timeout",
+		Options.AgentHeartbeatTimeout,
+		"The duration after which the hub flags the subscriptions of a managed cluster whose agent hasn't renewed its "+
+			"heartbeat lease with the AgentNotReporting condition.",
+	)
+
+	flag.BoolVar(
+		&Options.AgentVersionGating,
+		"agent-version-gating",
+		false,
+		"Hold the propagation of the subscriptions to the managed clusters whose agent version would ignore some of their "+
+			"spec features.",
+	)
+
+	flag.BoolVar(
+		&Options.GitAllowExternalSymlinks,
+		"git-allow-external-symlinks",
+		false,
+		"Follow the symlinks of the Git repos that resolve outside of the repo. By default, such a symlink fails the "+
+			"subscription, so an untrusted repo can't deploy or render the files of the operator.",
+	)
+
+	flag.BoolVar(
+		&Options.GitAllowHiddenFiles,
+		"git-allow-hidden-files",
+		false,
+		"Deploy the files and folders starting with a dot below the path of the Git subscriptions, they are ignored by "+
+			"default.",
+	)
+
+	flag.DurationVar(
+		&Options.ResourceThrottleInterval,
+		"resource-throttle-interval",
+		Options.ResourceThrottleInterval,
+		"The interval the agent samples the memory and CPU usage of its cgroup at. Close to the cgroup limits, the agent "+
+			"reduces its apply concurrency and defers the large Git clones. 0 disables the throttling.",
+	)
+
+	flag.Float64Var(
+		&Options.FaultInjection.ApplyFailureRate,
+		"fault-apply-failure-rate",
+		0,
+		"For the resilience tests only. The probability, from 0 to 1, of the agent failing the apply of a resource with "+
+			"a retriable error.",
+	)
+
+	flag.DurationVar(
+		&Options.FaultInjection.HubWriteDelay,
+		"fault-hub-write-delay",
+		0,
+		"For the resilience tests only. The delay the agent waits for before each write of a subscription result to the "+
+			"hub.",
+	)
+
+	flag.Float64Var(
+		&Options.FaultInjection.StatusDropRate,
+		"fault-status-drop-rate",
+		0,
+		"For the resilience tests only. The probability, from 0 to 1, of the agent dropping a status update of a "+
+			"subscription.",
+	)
+
+	flag.StringSliceVar(
+		&Options.FaultInjection.Namespaces,
+		"fault-namespaces",
+		nil,
+		"The namespaces of the subscriptions the faults are injected in, all the namespaces if empty. Can be repeated.",
+	)
+
+	flag.StringVar(
+		&Options.WebhookService,
+		"webhook-service",
+		Options.WebhookService,
+		"The service of the admission and conversion webhooks of the operator. When it is set, the operator rotates a "+
+			"self-signed serving certificate of the service and injects its CA bundle in the webhook configurations and "+
+			"CRDs labeled with apps.open-cluster-management.io/inject-cabundle: <service>. When it is empty, the webhook "+
+			"certificate is provided in the webhook certificate directory, by cert-manager for example.",
+	)
+
+	flag.StringVar(
+		&Options.ClusterSecretServerURL,
+		"cluster-secret-server-url",
+		Options.ClusterSecretServerURL,
+		"The server URL of the cluster secret the agent syncs to the hub, e.g. the cluster-proxy user server of the "+
+			"cluster. The API server URL of the managed cluster is used if it is empty.",
+	)
+
+	flag.StringVar(
+		&Options.ClusterSecretServerName,
+		"cluster-secret-server-name",
+		Options.ClusterSecretServerName,
+		"The TLS server name of the cluster secret server.",
+	)
+
+	flag.StringVar(
+		&Options.ClusterSecretCAFile,
+		"cluster-secret-ca-file",
+		Options.ClusterSecretCAFile,
+		"The CA file of the cluster secret server. The server is not verified if it is empty.",
+	)
+
+	flag.StringVar(
+		&Options.ClusterSecretSA,
+		"cluster-secret-service-account",
+		Options.ClusterSecretSA,
+		"The dedicated service account the agent creates in its namespace and exports the token of in the cluster secret. "+
+			"The application-manager token is exported if it is empty.",
+	)
+
+	flag.StringVar(
+		&Options.ClusterSecretClusterRole,
+		"cluster-secret-cluster-role",
+		Options.ClusterSecretClusterRole,
+		"The ClusterRole the cluster secret service account is bound to, required with --cluster-secret-service-account.",
+	)
+
+	flag.StringSliceVar(
+		&Options.ClusterSecretNamespaces,
+		"cluster-secret-role-namespaces",
+		Options.ClusterSecretNamespaces,
+		"The namespaces the cluster secret service account is bound to its ClusterRole in. The service account is "+
+			"bound to its ClusterRole in the whole cluster if it is empty.",
+	)
+
+	flag.StringVar(
+		&Options.ClusterProxyServerURL,
+		"cluster-proxy-server-url",
+		Options.ClusterProxyServerURL,
+		"The URL of the cluster-proxy user server the hub reaches the managed clusters through, e.g. "+
+			"https://cluster-proxy-addon-user.multicluster-engine.svc:9092. The cluster secrets of the clusters with the "+
+			"cluster-proxy addon available point to <url>/<cluster-name> when it is set.",
+	)
+
+	flag.StringVar(
+		&Options.ClusterProxyServerName,
+		"cluster-proxy-server-name",
+		Options.ClusterProxyServerName,
+		"The TLS server name of the cluster-proxy user server, the host of the cluster-proxy server URL is used if it is empty.",
+	)
+
+	flag.StringVar(
+		&Options.ClusterProxyCAConfigMap,
+		"cluster-proxy-ca-configmap",
+		Options.ClusterProxyCAConfigMap,
+		"The <namespace>/<name> of the ConfigMap holding the CA of the cluster-proxy user server in its ca.crt or "+
+			"service-ca.crt key. The server is not verified if it is empty.",
+	)
+
+	flag.Float32Var(
+		&Options.KubeAPIQPS,
+		"kube-api-qps",
+		Options.KubeAPIQPS,
+		"The maximum queries per second of the spec reads and writes of the operator to the API server.",
+	)
+
+	flag.IntVar(
+		&Options.KubeAPIBurst,
+		"kube-api-burst",
+		Options.KubeAPIBurst,
+		"The maximum burst of the spec reads and writes of the operator to the API server.",
+	)
+
+	flag.Float32Var(
+		&Options.KubeAPIStatusQPS,
+		"kube-api-status-qps",
+		Options.KubeAPIStatusQPS,
+		"The maximum queries per second of the status writes of the operator to the API server, the status "+
+			"subresources and the SubscriptionStatus and SubscriptionReport objects. They don't consume the spec queries.",
+	)
+
+	flag.IntVar(
+		&Options.KubeAPIStatusBurst,
+		"kube-api-status-burst",
+		Options.KubeAPIStatusBurst,
+		"The maximum burst of the status writes of the operator to the API server.",
+	)
+
+	flag.StringVar(
+		&Options.FeatureGates,
+		"feature-gates",
+		Options.FeatureGates,
+		utils.FeatureGatesUsage(),
+	)
+
+	Options.TLS.AddFlags(flag)
 }
@@ -15,6 +15,7 @@
 package exec
 
 import (
+	"crypto/tls"
 	"fmt"
 	"os"
 
@@ -22,6 +23,7 @@ import (
 	"open-cluster-management.io/multicloud-operators-subscription/pkg/placementrule/controller"
 	"open-cluster-management.io/multicloud-operators-subscription/pkg/placementrule/utils"
 	appsubutils "open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils/tlsconfig"
 
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
@@ -39,6 +41,11 @@ var (
 
 // RunManager starts the actual manager
 func RunManager() {
+	if err := tlsconfig.Configure(options.TLS); err != nil {
+		klog.Error(err, "")
+		os.Exit(1)
+	}
+
 	enableLeaderElection := false
 
 	if _, err := rest.InClusterConfig(); err == nil {
@@ -68,6 +75,8 @@ func RunManager() {
 	cfg.QPS = 30.0
 	cfg.Burst = 60
 
+	tlsconfig.ApplyRestConfig(cfg)
+
 	klog.Info("Leader election settings",
 		"leaseDuration", options.LeaderElectionLeaseDuration,
 		"renewDeadline", options.LeaderElectionRenewDeadline,
@@ -82,7 +91,10 @@ func RunManager() {
 		LeaseDuration:           &options.LeaderElectionLeaseDuration,
 		RenewDeadline:           &options.LeaderElectionRenewDeadline,
 		RetryPeriod:             &options.LeaderElectionRetryPeriod,
-		WebhookServer:           &k8swebhook.Server{TLSMinVersion: "1.3"},
+		WebhookServer: &k8swebhook.Server{
+			TLSMinVersion: tlsconfig.MinVersionName(tls.VersionTLS13),
+			TLSOpts:       []func(*tls.Config){tlsconfig.Apply},
+		},
 	})
 
 	if err != nil {
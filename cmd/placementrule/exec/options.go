@@ -18,6 +18,8 @@ import (
 	"time"
 
 	pflag "github.com/spf13/pflag"
+
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils/tlsconfig"
 )
 
 // PlacementRuleCMDOptions for command line flag parsing
@@ -27,6 +29,7 @@ type PlacementRuleCMDOptions struct {
 	LeaderElectionLeaseDuration time.Duration
 	LeaderElectionRenewDeadline time.Duration
 	LeaderElectionRetryPeriod   time.Duration
+	TLS                         tlsconfig.Options
 }
 
 var options = PlacementRuleCMDOptions{
@@ -82,4 +85,6 @@ func ProcessFlags() {
 		"The duration the clients should wait between attempting acquisition and renewal "+
 			"of a leadership. This is only applicable if leader election is enabled.",
 	)
+
+	options.TLS.AddFlags(flag)
 }
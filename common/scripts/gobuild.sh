@@ -32,6 +32,7 @@ BUILD_GOOS=${GOOS:-linux}
 BUILD_GOARCH=${GOARCH:-$(go env GOARCH)}
 GOBINARY=${GOBINARY:-go}
 BUILDINFO=${BUILDINFO:-""}
+VERSION=${VERSION:-$(cat COMPONENT_VERSION 2>/dev/null || true)}
 STATIC=${STATIC:-1}
 LDFLAGS="-extldflags -static"
 GOBUILDFLAGS=${GOBUILDFLAGS:-""}
@@ -39,12 +40,16 @@ GOBUILDFLAGS=${GOBUILDFLAGS:-""}
 IFS=' ' read -r -a GOBUILDFLAGS_ARRAY <<< "$GOBUILDFLAGS"
 
 GCFLAGS=${GCFLAGS:-}
-export CGO_ENABLED=0
+export CGO_ENABLED=${CGO_ENABLED:-0}
 
 if [[ "${STATIC}" !=  "1" ]];then
     LDFLAGS=""
 fi
 
+if [[ -n "${VERSION}" ]];then
+    LDFLAGS="${LDFLAGS} -X open-cluster-management.io/multicloud-operators-subscription/pkg/utils.buildVersion=${VERSION}"
+fi
+
 time GOOS=${BUILD_GOOS} GOARCH=${BUILD_GOARCH} ${GOBINARY} build \
         ${V} "${GOBUILDFLAGS_ARRAY[@]}" ${GCFLAGS:+-gcflags "${GCFLAGS}"} \
         -o "${OUT}" \
@@ -88,7 +88,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _deployManagedCommonAppsOpenClusterManagementIo_helmreleases_crdYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x5c\xdd\x6f\xdb\x46\x12\x7f\xf7\x5f\x31\x70\x1f\x7c\x05\x42\x09\xc9\xbd\x14\x7a\x33\xec\xb4\xd5\x5d\xea\x04\x96\x2f\xf7\x10\x04\xc5\x8a\x3b\x24\xf7\x44\xee\xb2\xfb\x21\x45\x57\xf4\x7f\x3f\xcc\x2e\x29\x4b\x22\x29\xd2\x46\x94\x26\x57\xee\x8b\x2d\x72\x3f\xe6\x7b\x7e\x9c\x11\xc5\x4a\xf1\x1e\xb5\x11\x4a\xce\x80\x95\x02\x3f\x59\x94\xf4\xc9\x4c\x56\x3f\x98\x89\x50\xd3\xf5\xcb\x8b\x95\x90\x7c\x06\x37\xce\x58\x55\xdc\xa3\x51\x4e\xc7\x78\x8b\x89\x90\xc2\x0a\x25\x2f\x0a\xb4\x8c\x33\xcb\x66\x17\x00\x92\x15\x38\x83\x0c\xf3\x42\x63\x8e\xcc\xa0\x99\xb0\xb2\x34\x13\x55\xa2\x8c\xe2\xdc\x19\x8b\x3a\x2a\x98\x64\x29\x16\x28\xed\x44\xa8\x0b\x53\x62\x4c\x4b\x53\xad\x5c\x49\x44\x9c\x9e\x1e\xce\x30\xb4\x02\x20\x50\xf6\x33\xe6\xc5\x7d\x38\xce\x5f\xcd\x85\xb1\xff\x3c\xbe\xf3\x46\x18\xeb\xef\x96\xb9\xd3\x2c\x3f\x24\xd2\xdf\x30\x42\xa6\x2e\x67\xfa\xe0\xd6\x05\x80\x89\x55\x89\x33\xb8\xa3\x63\x4b\x16\x23\xbf\x00\x58\x07\x99\x79\x32\xa2\x8a\xeb\xf5\xcb\xb0\x4d\x9c\x61\xc1\x02\x7d\x00\xc4\xc9\xf5\xbb\xf9\xfb\xbf\x2f\x0e\x2e\x03\x70\x34\xb1\x16\xa5\xf5\x92\xdf\xa3\x13\x84\x01\x9b\x21\x84\xf9\x90\x28\xed\x3f\x1a\xb7\xdc\xcd\xaf\xa9\x86\xeb\x77\xf3\xdd\x7e\xa5\x56\x25\x6a\x2b\x6a\xd1\x84\xb1\xa7\xde\xbd\xab\x47\xa7\x5f\x11\x81\x61\x16\x70\xd2\x2b\x06\x12\x2a\x26\x91\x57\x3c\x81\x4a\xc0\x66\xc2\x80\xc6\x52\xa3\x41\x69\x99\x37\x00\x38\x18\x2a\x01\x26\x41\x2d\xff\x83\xb1\x9d\xc0\x02\x35\x6d\x03\x26\x53\x2e\xe7\x10\x2b\xb9\x46\x6d\x41\x63\xac\x52\x29\xfe\xbb\xdb\xdb\x80\x55\xfe\xd0\x9c\x59\xac\x34\xf5\x38\x84\xb4\xa8\x25\xcb\x61\xcd\x72\x87\x2f\x80\x49\x0e\x05\xdb\x82\x46\x3a\x05\x9c\xdc\xdb\xcf\x4f\x31\x13\xf8\x45\x69\x04\x21\x13\x35\x83\xcc\xda\xd2\xcc\xa6\xd3\x54\xd8\xda\xac\x63\x55\x14\x4e\x0a\xbb\x9d\xc6\x4a\x5a\x2d\x96\xce\x2a\x6d\xa6\x1c\xd7\x98\x4f\x8d\x48\x23\xa6\xe3\x4c\x58\x8c\xad\xd3\x38\x65\xa5\x88\x3c\xe9\xd2\x7a\xdf\x28\xf8\x77\xba\x72\x04\x73\x75\x40\xab\xdd\x92\xad\x18\xab\x85\x4c\xf7\x6e\x78\x43\x3d\xa1\x01\x32\x57\xd2\x3c\xab\x96\x06\x2e\x1e\x05\x4d\x97\x48\x3a\xf7\xaf\x17\x0f\x50\x1f\xed\x95\x71\x2c\x7d\x2f\xf7\xc7\x85\xe6\x51\x05\x24\x30\x21\x13\xd4\x41\x89\x89\x56\x85\xdf\x13\x25\x2f\x95\x90\xd6\x7f\x88\x73\x81\xf2\x58\xfc\xc6\x2d\x0b\x61\x49\xef\xbf\x39\x34\x96\x74\x35\x81\x1b\x26\xa5\xb2\xb0\x44\x70\x25\x67\x16\xf9\x04\xe6\x12\x6e\x58\x81\xf9\x0d\x33\x78\x76\x05\x90\xa4\x4d\x44\x82\x1d\xa6\x82\xfd\x30\x75\x3c\x39\x48\x6d\xef\x86\xc6\x52\x9d\xd0\xd7\x9e\xbf\xde\x63\xa9\x0e\xbc\x86\x96\x1a\x61\x95\xde\x92\x2b\x1c\xc7\xa6\x7a\xb4\xbb\x2b\x8d\x38\x63\xda\x52\xb0\x39\xbe\x71\x44\xc3\x4d\x3d\xaf\x8e\x18\x14\x85\x82\x8b\x62\xd8\x04\x36\xc2\x66\x42\xee\xa8\x6a\xec\xd7\x21\x29\x4f\x85\x92\x89\x48\x7f\x61\xe5\x3d\x26\x7d\x84\xf8\xa9\x4e\xfb\x60\x00\x25\xd3\xac\x40\x4b\x06\x67\x15\xb0\x38\x46\x13\xc8\xa3\xa0\x1a\xe9\x47\x69\xf1\xc6\xae\xe4\xe7\x7e\xea\x0d\xb3\x2c\x57\xe9\xc2\x5b\x79\x63\x5a\xb7\xe8\xe0\x44\xc4\x6b\x25\xfd\xfa\xdd\xbc\x8e\x72\xb5\xe4\x34\x26\xa8\x29\xd7\xb4\xae\x3e\x21\x31\x1a\x89\xc0\x9c\xbf\x63\x36\x1b\x70\xf6\xd5\x3c\x09\x87\x79\x7f\x27\x59\x41\x29\x30\xc6\x83\x00\x0a\x42\x1a\x8b\x8c\x83\x4a\x5a\x77\x04\x9a\x4a\x4e\xa1\xb1\x5a\xf1\x22\x78\x77\x15\x46\x1e\xc3\xae\x65\x42\x02\xa3\xb8\x22\x38\xfc\x63\xf1\xf6\x6e\xfa\x53\xd3\x20\xf6\xb8\xa8\x55\x67\x2c\xb3\x3e\xf9\xbe\x00\xe3\xe2\x0c\x98\x21\x36\x84\x46\xbe\xa0\x3b\x93\x82\x49\x91\xa0\xb1\x93\xea\x0c\xd4\xe6\xc3\xab\x8f\xed\xd2\x03\xf8\x51\x69\xc0\x4f\xac\x28\x73\x7c\x01\x22\x48\x7c\x17\xb2\xbc\xe0\xe3\x60\xcf\x24\x8e\xdd\x8e\x95\x21\x77\x49\x00\x4a\xc5\x2b\xb6\x37\x9e\x5d\xcb\x56\x08\xaa\x62\xd7\x21\xe4\x62\x85\x33\xb8\x24\xa4\xb1\x47\xe6\xef\xe4\x30\x7f\x5c\x76\xec\xfa\xb7\x4d\x86\x1a\xe1\x92\x26\x5d\x06\xe2\x76\x39\xea\xc0\xd3\x76\x44\xda\x8c\x59\xb0\x5a\xa4\x29\xea\x56\xeb\xa6\xe1\x03\x2e\x85\xb1\xef\x41\x69\x92\x80\x54\x7b\x5b\xc8\xca\x9d\x89\x52\x91\x08\xe4\x0d\xa2\x3f\xbc\xfa\xd8\x49\xf1\xa1\xbc\x40\x48\x8e\x9f\xe0\x55\x70\x2a\x61\x48\x4a\xdf\x4f\xe0\xc1\x5b\xc7\x56\x5a\xf6\x89\x4e\x8a\x33\x65\xb0\x4b\xb2\x4a\xe6\x5b\xe2\x39\x63\x6b\x04\xa3\x0a\x84\x0d\xe6\x79\x54\xf9\x2f\x6c\x98\x0f\x71\xb5\xe2\xc8\xde\x18\xf9\xbf\x3d\x69\xad\x35\x32\x78\x78\x7b\xfb\x76\x16\x28\x23\x83\x4a\x25\x91\x43\x19\x25\x11\x94\xe9\x29\xc5\x87\x3c\xe5\xad\xb1\x91\xe8\xea\x61\x5c\x30\x1f\xab\x28\xe8\xc9\x14\xeb\x20\x92\x38\xca\x1c\x93\xab\xe7\xf8\x71\x33\x5d\xd7\xa3\x25\x6d\x1f\x07\x8e\x3f\x2d\xf1\x0d\x64\x4e\xb6\xe6\x96\x26\x73\x77\x7b\x56\x7e\x92\xb9\x95\x5b\xa2\x96\x68\xd1\xf3\xc7\x55\x6c\x88\xb5\x18\x4b\x6b\xa6\x6a\x8d\x7a\x2d\x70\x33\xdd\x28\xbd\x12\x32\x8d\xc8\x34\xa3\x60\x03\x66\xea\xa1\xfc\xf4\x3b\xff\xe7\xd9\xbc\x78\x50\x3e\x94\x21\x3f\xf9\x4b\x70\x45\xe7\x98\xe9\xb3\x98\xaa\xf1\xdd\xf0\x3c\x76\xb5\x08\x01\x23\x3e\x5e\x4b\x6e\xb1\xc9\x44\x9c\xd5\xc0\xbd\x8a\xb1\x1d\xce\x24\x08\x25\xf2\x10\x9a\x99\xdc\x9e\xdd\x94\x49\xa0\x4e\x13\x45\xdb\xc8\x6f\xa1\xf2\x88\x49\x4e\xff\x1b\x61\x2c\x5d\x7f\x96\x04\x9d\x18\xe4\xbe\xff\x9a\xdf\x7e\x19\x03\x77\xe2\x59\xbe\xda\x01\x4e\x3d\x23\x22\x45\x63\x7b\x90\xd9\xad\x9f\x54\xe3\x43\x02\x60\x1e\x07\x56\xe8\x30\x6c\xf1\x14\x50\x28\xa4\xc1\xd8\x69\x5c\xac\x44\xf9\x1e\xb5\x48\xb6\x3d\x04\xcc\x1b\x0b\x88\x18\x67\x90\x93\x61\x9a\x95\x28\x03\x41\xc6\x3f\xa2\x5c\x19\x78\x78\xb3\x68\x11\x53\x4c\x70\x2f\x11\x31\xb3\xfe\xa1\x34\xfc\xdb\x7c\xf2\xac\x69\x5f\x2a\x95\x23\x3b\xbe\xbb\x61\x36\xce\x76\x21\x60\x41\x0f\xf5\xbc\xae\x66\xb4\x20\xc9\x03\x3e\xfe\x7d\x6a\xed\x3e\x4b\x28\xd9\x32\xc7\x70\x16\xe5\xc3\x5d\x28\x08\x55\x04\xff\x38\x50\x89\x7f\xf7\x00\xf9\x24\x2e\x0c\xc6\x1a\x6d\x3f\x28\x5f\xf8\x79\x44\x92\x33\xd8\x87\xc4\xab\x94\xd9\x22\xf9\x03\x24\xde\x04\x75\x23\x14\x3f\xe0\x77\x84\xe2\x23\x14\x0f\x14\x8f\x50\x7c\x84\xe2\x03\x98\x1b\xa1\xf8\x08\xc5\x47\x28\xfe\x8d\x43\xf1\xa0\xe5\x1e\x3c\x76\x35\xbf\x5b\xbc\xbe\x7f\x80\xeb\xdb\xdb\xf9\xc3\xfc\xed\xdd\xf5\x1b\x58\xbc\x7b\x7d\x03\x3f\xce\x5f\xbf\xb9\x5d\x40\x54\x67\xf2\x90\xe4\x49\x14\x55\xfb\xab\x85\xd4\x79\x51\x2a\x6d\x99\xb4\x33\xb8\x77\x12\x2e\x09\x83\x31\xab\x74\x64\xf8\x0a\x52\x94\xf4\x09\x61\xf5\x83\xb9\x24\x9b\xd3\xb8\xbb\x14\x2b\x8e\xc0\x92\xf6\x5d\x0b\xc5\x45\xb2\x0d\x8d\x06\x1f\xeb\x73\x84\x6b\xce\x21\xf6\x7d\xbf\x80\x56\x42\x89\xd7\x19\x9a\x45\x9a\x58\x3a\x91\x73\xca\xb7\x2c\x6d\x45\x80\xb5\xd6\x96\x4a\xad\xa2\xf5\xcb\x09\xfd\x9d\xec\x2d\x24\x1d\x2e\x71\xab\x24\xff\x75\xc9\x8c\x88\xcd\xb4\xa2\x55\xc8\xf4\xd7\x58\xf3\x49\x66\x8b\xbc\x65\xdf\x80\x47\x21\x53\x39\x0f\x90\xd6\xe9\x1c\xac\xda\x30\xcd\x1f\x11\xae\x87\xd9\x4d\x55\x9f\xc6\xac\xa9\xb0\x99\x5b\x0e\xb0\xd8\x9f\x84\xfd\xd9\x2d\x69\xb7\xb5\xe0\x55\xe9\xff\x74\xed\xdb\xd3\xd3\xe1\xed\xb9\xa2\x87\x1b\x0f\xc4\x59\x45\x43\x7b\xc9\xbe\x9f\x03\x1a\x4b\xcd\x64\xdc\x81\x7c\xa1\xdf\x67\xa1\x6e\x43\x74\xe3\xe7\x81\xbb\x38\x9d\x77\x12\x09\x20\x2c\x16\x27\x6e\x0f\x3a\xa1\x9e\xc4\xb4\x66\xdb\x13\x7e\xdd\xea\xba\x10\x34\x3e\x4c\xdd\xe7\xd2\xf5\xa8\xe8\x2f\xa3\xe8\xcc\xb7\xe2\x8e\xfb\x7a\xf5\x68\xe9\xef\x95\xea\x50\xe5\xc4\x63\x88\xa8\x56\x0b\x5c\xe3\x10\x75\xf7\xab\xf0\x1b\x90\x9c\xbf\xdd\x2f\xb5\x10\x95\x1f\xb6\x25\xbe\x96\xae\xf0\xab\x0c\x65\xb2\xae\x3a\x47\x2f\xf9\x27\xc8\x62\xb9\x5d\x0c\xc9\xbb\xd7\xf5\xbc\x21\xc9\xe2\x89\xb9\xe2\x74\x8f\xb4\x41\xca\x67\xec\x93\xc2\xd0\x5e\x69\x3f\x13\x30\xa0\x50\xd3\x94\xea\x53\x8b\x35\x30\xcc\x52\x7b\x8a\x36\x0d\x3a\x3e\x53\xe1\x06\xce\x53\xbc\x81\xf3\x16\x70\xe0\x4c\x45\x1c\x38\x5b\x21\x07\xce\x57\xcc\x81\xf3\x16\x74\xe0\x2c\x45\x1d\x38\x4b\x61\x07\xce\x52\xdc\x81\x67\x17\x78\x60\x98\xef\x77\x17\x7a\xe0\x1b\x29\xf6\x0c\x64\xb4\xbb\xe8\xd3\x64\xf4\xab\x28\xfc\x3c\x81\xaf\x13\x05\xa0\x76\xe6\xbe\x8a\x22\xd0\x40\x06\x07\x15\x83\x9a\x6c\x7e\xa6\x82\x10\x7c\x4b\x45\xa1\x81\x12\xed\x2c\x0e\x35\xa5\xf8\x15\x14\x88\x06\x31\xd5\x03\xa5\x87\xb4\x50\x1b\xcc\x7f\xa6\x36\x2a\x3c\xa5\x95\x0a\x3d\x8d\x48\x38\xdd\x8c\x6c\xf0\xf0\x99\x1a\x92\xd0\xdf\x94\x84\x11\xf3\x8e\x98\x77\xc4\xbc\x23\xe6\x1d\x31\xef\x88\x79\x47\xcc\x3b\x62\xde\x11\xf3\xfe\xb9\x98\x77\xec\xa9\x55\xe3\x2f\xd3\x6a\x19\x7b\x6a\x7f\x11\x45\x8f\x3d\xb5\xff\xa3\x9e\xda\xba\x2b\xc7\x1f\x50\x54\x67\xf2\xea\x5b\xe5\xe1\xcb\xcc\xd5\xd2\x8e\xd3\x5a\x48\xe9\x20\xa3\x7e\x19\xfc\x71\x7c\x8a\x1e\xb3\x5b\xe4\x5f\x6a\xd5\x6b\x8c\x9c\x5c\x49\xb5\x91\x91\x07\xf0\x66\x06\x56\xbb\x7d\x0c\x41\x0f\x9d\xee\x48\xc5\x27\xde\xb8\x54\x92\xfb\x57\xd9\x5b\x8c\xa2\xd3\x56\xfa\x8c\x30\x67\xc6\x3e\x68\x26\x8d\xdf\xf9\x41\x74\xe3\xdf\x44\xe9\x82\xd9\x19\x70\x66\x31\xb2\xa2\xe8\x02\x43\xbd\x56\x59\xa0\x31\x2c\xed\x3c\xa7\x77\xbd\x46\x66\xba\x21\x5e\xef\xf2\x36\xa1\x3f\x61\x79\xb7\x43\x0c\xf8\x96\xde\x6f\x4e\x68\x6c\xc5\x55\x51\x45\x57\xeb\x2d\xda\xb7\xcb\x37\xbb\x5c\xb7\xdb\xef\x39\x96\xb9\xda\x22\xaf\xde\xf7\x6d\x52\x73\xda\x66\xea\x4a\x48\xbb\x10\x9e\xfd\x4d\xd2\xe7\x45\x83\x76\x91\x46\x7b\xbe\xd2\xef\xce\x8d\x8b\xde\x77\xf9\x9e\xb7\x1a\xab\x34\x59\xec\xde\x15\xb7\xd4\xc7\x2f\x65\x54\x86\x05\xbf\xff\x71\xf1\x68\x63\x84\x0e\x4a\x8b\xfc\xee\xf8\xd7\x20\x2e\x43\x3d\xa3\xfe\x99\x07\xff\x71\xcf\xc3\xe1\xc3\xc7\x8b\x70\x30\xf2\xf7\xf5\xaf\x38\xd0\xc5\xff\x05\x00\x00\xff\xff\x12\x3b\x90\x28\x0a\x43\x00\x00")
+var _deployManagedCommonAppsOpenClusterManagementIo_helmreleases_crdYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xed\x5d\xcd\x6f\xeb\x36\x12\xbf\xfb\xaf\x20\xd2\x43\xb6\x40\x64\xa3\xdd\xcb\xc2\xb7\x20\x49\xdb\xec\xe6\x25\x0f\x76\xde\xeb\xa1\x28\x0a\x5a\xa2\x25\x36\x12\xa9\x25\xa9\x38\xde\xa2\xff\xfb\xce\x90\x92\x6d\xd9\xfa\x8a\x13\xe5\x25\x80\x7c\x89\x2d\x91\xc3\xf9\xe6\xfc\x46\x84\x42\x53\xfe\x95\x29\xcd\xa5\x98\x12\x9a\x72\xf6\x64\x98\xc0\x5f\x7a\xfc\xf0\x2f\x3d\xe6\x72\xf2\xf8\xc3\xe8\x81\x8b\x60\x4a\x2e\x32\x6d\x64\x32\x63\x5a\x66\xca\x67\x97\x6c\xc9\x05\x37\x30\x72\x94\x30\x43\x03\x6a\xe8\x74\x44\x88\xa0\x09\x9b\x92\x88\xc5\x89\x62\x31\xa3\x9a\xe9\x31\x4d\x53\x3d\x96\x29\x13\x9e\x1f\x03\x09\xa6\xbc\x84\x0a\x1a\xb2\x84\x09\x03\x0b\x8c\x74\xca\x7c\x9c\x1a\x2a\x99\xa5\xc8\x44\xf3\x70\xb7\x86\xc6\x19\x84\x38\xce\x7e\x81\xe5\x66\x6e\x39\x7b\x35\xe6\xda\xfc\x67\xff\xce\x0d\x5c\xb4\x77\xd3\x38\x53\x34\x2e\x33\x69\x6f\x68\x2e\xc2\x2c\xa6\xaa\x74\x0b\xee\x68\x1f\xd8\x99\x92\x5b\x5c\x36\xa5\x3e\x0b\xe0\xda\xa3\xd3\x99\x65\xc3\xcb\xa5\x06\x4d\x59\x32\x7e\xc4\x12\xea\xf8\x23\x04\x25\x39\xff\x7c\xfd\xf5\x9f\xf3\xd2\x65\x42\x02\xa6\x7d\xc5\x53\x63\x35\xbf\xc3\x27\xe1\x9a\x98\x88\x11\x37\x9e\x2c\xa5\xb2\x3f\x75\xb6\xd8\x8c\x2f\xb8\x26\x40\x78\x43\x2f\x55\xb0\x94\x32\xbc\x50\x8d\xfb\xd0\xad\x79\x77\xae\xee\xad\x7e\x8a\x0c\xba\x51\x70\x03\xec\xca\x1c\x0b\xb9\x90\x2c\xc8\x65\x22\x72\x09\xd7\x81\x3f\xc5\x52\xc5\x34\x18\x84\x5a\x07\x20\xa5\x0f\x0c\xa2\x82\xc8\xc5\x9f\xcc\x37\x63\x32\x67\x0a\xc9\x10\x1d\xc9\x2c\x0e\x88\x2f\x05\xfc\x34\x40\xc1\x97\xa1\xe0\xff\xdb\xd0\x86\x15\xa5\x5d\x34\xa6\x86\xe5\x96\xda\x7e\xb8\x00\x47\x10\x34\x26\x8f\x34\xce\xd8\x19\x2c\x10\x90\x84\xae\x81\x0c\xae\x42\x32\xb1\x43\xcf\x0e\xd1\x63\xf2\x49\x2a\x50\xa6\x58\x4a\xb0\xa7\x31\xa9\x9e\x4e\x26\x21\x37\x85\x5b\xfb\x32\x49\x32\x70\xe0\x35\x7c\x13\x46\xf1\x45\x66\xa4\xd2\x93\x80\x3d\xb2\x78\xa2\x79\xe8\x51\xe5\x47\xdc\x00\xf5\x4c\xb1\x09\xa8\xd1\xb3\xac\x0b\x63\x63\x23\x09\xbe\x53\x79\x20\xe8\xd3\x12\xaf\x66\x8d\xbe\xa2\x81\xa2\x08\x77\x6e\x58\x47\x6d\xb0\x00\xba\x2b\x5a\x9e\xe6\x53\x9d\x14\x5b\x45\xe3\x25\xd4\xce\xec\x6a\x7e\x4f\x8a\xa5\xad\x31\xf6\xb5\x6f\xf5\xbe\x9d\xa8\xb7\x26\x40\x85\x81\x3e\x98\x72\x46\x5c\x2a\x99\x58\x9a\x4c\x04\xa9\x04\x0d\xdb\x1f\x7e\xcc\x61\xd6\x1e\x51\x70\xbe\x84\x1b\xb4\xfb\x7f\x41\xb5\x06\x6d\x35\x26\x17\x54\x08\x69\xc8\x82\x91\x2c\x85\xf0\x67\xc1\x98\x5c\x0b\xb8\x9a\xb0\xf8\x02\xbc\xb3\x77\x03\xa0\xa6\xb5\x87\x8a\xed\x66\x82\xdd\x34\xb5\x3f\xd8\x69\x6d\xe7\x06\xe8\x4f\x36\xd8\x6b\x27\x5e\x67\x30\xb2\x14\x35\x38\x55\x73\x10\x66\x8d\xa1\xb0\x9f\x9b\x9a\xc3\x15\x3f\x7e\x44\x95\xc1\x64\xb3\x7f\x63\x8f\x87\x8b\x62\x5c\x91\x31\x30\x0b\xb9\x10\x65\x8e\x08\x59\x71\xb0\xb4\xd8\x70\x75\x40\xaf\x46\x53\x96\x0b\x29\x96\x3c\xfc\x44\xd3\x19\x5b\xb6\x31\x62\x87\x42\x52\xc5\x9f\x24\xa5\x0a\xf8\x30\xe8\x70\x10\xd1\xd4\x87\x08\x71\xec\x61\x52\xf5\xd4\x56\x5b\xc1\x01\x55\x8c\x73\x3b\xf4\x02\xcc\x14\xcb\x70\x6e\xbd\xfc\x60\x58\xbd\xea\x9a\x32\x5e\x25\xeb\x90\xf8\x8a\x2c\x57\x68\x4e\x31\x88\x10\xdc\x6b\x2a\x67\x37\x68\x0c\x3f\x4b\xce\xe2\xe0\x33\x35\x51\x87\xb5\x4f\xaf\x97\x6e\x31\x1b\xef\xa8\x2b\x02\xfb\xaf\xcf\x4a\x09\x14\x34\x02\x7b\x20\x0d\xe0\x62\x25\x45\x82\x43\x31\x28\x20\xd4\xdc\x8c\x33\x17\xdd\x79\x1a\xd9\xa6\x5d\x43\x41\xb9\x14\xf3\x0a\x0f\xc8\xbf\xe7\x77\xb7\x93\x9f\x65\x0d\x49\x2b\x45\x61\x3a\x0d\x49\xde\x6e\xbe\x67\x90\x06\xfc\x88\x40\xa6\x06\x31\x60\xbd\x60\x8e\x77\xc6\xb0\x3b\xf3\x25\x24\x85\x71\xbe\x06\x68\xf3\xb7\x1f\x7f\x1f\xd7\x90\xfe\x09\xb6\x33\xf6\x44\x93\x34\x86\x2c\xce\x9d\xc6\x37\x29\xcb\x2a\xde\x77\xfe\x8c\xea\xd8\x50\xcc\x1d\xb9\x4e\x03\x24\x95\x41\x2e\xf6\xca\x8a\x6b\xe8\x03\x90\xcd\xc5\x85\x34\x1a\xf3\x07\xb0\xda\x09\x56\x1a\x3b\x6c\xfe\x85\x01\xf3\xf7\x49\x0d\xd5\x7f\xac\x22\x60\x87\x9c\xe0\xa0\x13\xc7\xdc\x66\x8f\x2a\x45\xda\x86\x49\x13\x51\xc8\xa1\x8a\x87\x21\x4c\x0c\x6a\xc8\xda\x84\x8b\x69\xec\x7b\x02\xaa\x00\x0d\x08\xb9\x43\x42\xe4\xe1\x8c\x9c\x72\x30\x43\x70\xc0\x34\xe8\xb6\x96\xe3\xb2\xbe\xc0\x75\x02\xf6\x44\x7e\x74\x41\x05\x44\x41\x4b\xdf\x8f\xc9\xbd\xf5\x8e\x35\x8c\x7c\xc2\x95\xfc\x48\xc2\x36\x51\x43\x51\x8a\x78\x8d\x32\x47\xf4\x11\x2a\x10\x09\xbc\xad\x58\x1c\x7b\x79\xfc\x92\x15\xb5\x29\xae\x30\x1c\xfa\x1b\xc5\xf8\x37\x8d\xde\x5a\x54\x06\xf7\x77\x97\x77\x53\xc7\x19\x3a\x54\x28\x90\x1d\xdc\x51\x80\x38\xec\xf4\xb8\xc5\xbb\x7d\xca\x7a\xe3\xc1\x46\xb7\xb3\x37\x59\xf7\x01\x36\x21\xe9\x89\x90\x15\x49\x64\x99\xe1\xce\x31\x3e\x3d\x26\x8e\x0f\xb7\xeb\x86\x6d\x7b\x3f\x71\x7c\xb3\x8d\xaf\xa3\x70\xa2\x72\x6f\x39\x14\xee\x76\xc7\xcb\x1b\x85\x7b\xc8\x16\x50\x9d\x41\xce\xb7\xf2\x05\xd2\xd7\x28\x9a\xcf\x52\xa3\x27\x12\xd2\xeb\x23\x67\xab\xc9\x4a\x2a\x60\x39\xf4\xd0\x35\x3d\xe7\x03\x7a\x62\x4b\xf9\xc9\x77\xf6\xcf\xd1\xb2\xd8\xa2\xbc\xab\x40\x76\xf0\x5b\x48\x85\xeb\xe8\xc9\x51\x42\x15\xf5\x5d\xf7\x7d\xec\x74\xee\x12\x86\xbf\x3f\x17\xc3\x62\x15\x71\xc8\xdb\x79\xe1\x9e\xe7\xd8\x9a\x60\xe2\x58\x25\x06\x2e\x35\x53\xb1\xee\xdd\x95\x51\xa1\x99\x42\x8e\xd6\x9e\x25\x21\x63\x0f\x02\x1f\xbf\x6b\xc0\x6b\x78\xfd\x28\x0d\x66\xbc\x53\xf8\x7e\xb9\xbe\x7c\x1b\x07\x07\x7e\x8e\xf1\xef\x9a\xe2\xd4\x0a\xc2\x43\xd8\x74\x5b\x2a\xb3\x4b\x3b\xa8\xa8\x0f\xb1\x00\xb3\x75\x60\x5e\x1d\x3a\x12\xcf\x29\x0a\xa1\x18\x61\x60\x2f\x36\x7f\xe0\x29\x38\x18\x5f\xae\x5b\x18\xb8\x3e\x98\x80\xcc\x64\x1a\x36\x0f\x70\x4c\x0d\x57\x1d\x43\xda\x42\x94\x53\x4d\xee\x6f\xe6\x15\x6a\xf2\xb1\xdc\x03\xef\x86\x7a\x03\xcb\x35\xf7\xf5\x10\x79\x16\xbc\x2f\xa4\x84\x82\x7b\xff\xee\x8a\x1a\x3f\xda\xa4\x80\x39\x82\xfa\xa0\xe8\x66\xe8\x16\x39\x7e\x6d\x9a\xbb\x2b\x12\x13\x74\x11\x33\xb7\x16\xee\x87\x9b\x54\xe0\xba\x08\x16\x0e\xe4\xea\xdf\x00\xc8\x67\x49\x91\x63\xff\x0e\xf8\x60\xb6\x1d\x59\x85\x10\x72\x6f\xb0\x63\xce\xec\x15\xd8\xd6\x69\x16\x9b\x51\x65\x5a\xd8\x9f\x5e\x87\x6b\x5a\x3c\xc8\x50\x15\x32\x73\x5b\x9f\xb5\x4b\x22\xdc\x97\x47\xef\xf2\xe1\x2e\x6c\xa1\x0e\xdc\xc2\x52\x99\xc6\x71\x0d\xb0\x28\xc9\x78\x48\xe9\x55\xc5\xba\xa1\x0b\x16\x57\xf8\x14\x0d\x02\xdb\x32\xa3\xf1\xe7\x46\x0c\xd3\x98\x14\x9a\x34\xe4\x16\x26\x54\xb1\xbc\x97\x62\x7f\xe6\xb2\x39\x26\xb7\x32\x57\x2c\x0c\x35\x2f\x94\x61\x56\x3d\xbe\x62\x08\xea\x9f\x93\x93\xb2\x34\x54\xb0\x7b\x5c\xcc\x2e\xdb\xe2\xe9\xcb\x76\x24\x36\xfc\x62\x9e\x63\x67\x7b\xa1\x04\x61\x7d\x15\x40\x09\x28\xe3\x00\xaa\xda\x05\x5b\x4a\x27\x59\xe5\xa6\xe9\xda\x67\x39\x13\xfa\xcc\x79\xb8\x2d\x61\x9d\x2c\x76\x09\xbc\x52\x78\xca\xb3\x02\x0f\x12\x99\x62\xa6\x1d\x0d\xcf\xed\x38\xcc\x05\x90\x13\xda\x20\x70\x5e\xab\x56\x88\x53\x82\xc0\xe3\x01\x03\x0f\x18\x78\xc0\xc0\x03\x06\x1e\x30\xf0\x80\x81\x07\x0c\x3c\x60\xe0\x01\x03\xdb\xb4\x67\xad\xdc\x52\x8f\x9d\x5e\xdf\xce\xaf\x66\xf7\xe4\xfc\xf2\xf2\xfa\xfe\xfa\xee\xf6\xfc\x86\xcc\x3f\x5f\x5d\x90\x9f\xae\xaf\x6e\x2e\xe7\xc4\x2b\x76\x72\xb7\xc9\xa3\x2a\xf2\xe7\xce\x15\xac\x5e\x27\xa9\x54\x86\x0a\x03\xe8\x2a\x13\xe4\x04\x6b\x30\x0a\xe6\xf6\x74\xf0\x40\x42\x26\xf0\x17\x23\xe0\x18\x27\xe8\x73\x8a\x6d\x2e\xf9\x32\x60\x84\x2e\xab\xa9\x26\x32\x00\x60\xec\x9e\xf0\xd9\x5c\x0f\x00\xf2\x3c\x80\x82\xc5\x3e\x70\x77\xd5\x8a\x7b\xb6\x92\xe1\x23\x6a\x82\x96\x58\x64\xdc\x96\xc4\x86\x86\x95\x15\x60\x61\x35\xa8\x65\x1f\xbc\xc7\x1f\xc6\xf8\x77\xbc\x33\x11\x6d\xb8\x60\x6b\x29\x82\x3f\x16\x54\x73\x30\x66\xce\x2b\x2c\xf0\x07\xd4\xdc\xe3\xc8\x24\x71\x05\x5d\x57\x8f\x92\x08\x0a\x72\x57\xd2\x66\x2a\x06\x59\x57\x54\x05\xdb\x0a\xd7\x56\xee\xa7\xcf\xac\x59\x21\xa4\xa2\x6c\xd1\xc1\x63\x7f\xe6\xe6\x97\x6c\x81\xd4\x1e\x79\x90\xe3\x86\xe6\x87\x4e\x96\x9f\x9a\x68\x8f\x25\x76\x15\x6c\x21\x4e\x73\x1e\xaa\x9f\x95\xb5\x4b\x80\x9f\x85\xa2\xc2\x8f\xea\xee\xb6\xc6\xec\xe6\xf9\x5f\x7d\xfd\xdc\x91\x0a\xd8\x45\xd7\x13\x80\x7c\x94\x34\xdc\xee\xb4\x42\x31\x88\x2a\x45\xd7\x0d\x71\x5d\x19\xba\xb9\xc5\xbb\x99\xbb\x2f\x5b\x0f\x86\x7e\x1b\x43\x47\xb6\xa9\xb2\xff\x40\xbd\xe1\xc1\x3a\x00\xe4\x92\xc9\x51\x46\x97\x51\x81\x4f\xd8\x58\xbb\x98\xbb\xdd\x84\x09\x57\x0a\xb6\xea\x7a\xed\x94\x38\xfb\xe4\x46\xef\xb5\xcf\x52\x79\xb6\xe5\x10\x76\x10\x65\x4f\x6a\xe0\xf6\xd7\xa4\x73\x98\xf0\x65\x76\x63\x41\x6e\xce\x85\x6d\xd9\x14\xe2\x05\x0e\xbb\xd8\x63\x3f\xae\x9b\x90\x2f\xea\xc6\x1e\x6f\xec\x4a\x55\x3b\xc1\xca\x0a\x2f\x2f\x6b\x85\xab\x85\x33\x05\x00\xdd\xb6\x97\x5b\xd8\xec\x62\x9b\xd6\x9e\x4b\x83\x60\x77\xd6\x0f\x67\x1b\x28\x9d\x43\x42\x4d\x98\x90\x59\x18\xd9\xba\x46\x25\xd5\x0d\xe4\x03\x53\x49\x12\x83\x22\xd6\x32\xc3\x2e\x44\x8a\x20\x0b\x74\xe5\x76\xec\x6d\xcd\x84\xe5\x43\x0e\xe9\x5a\x28\x76\x95\xbc\x4b\x17\xe7\x35\x7b\x3a\x47\x24\x85\x8e\xfd\x9e\xfe\xbb\x3f\xbd\xf7\x82\xde\xac\x33\xd4\x7f\x9f\xe8\x2d\xba\x46\x6f\xd2\x43\x7a\xb3\x8e\x52\xdf\xfd\xa5\xbe\xbb\x4d\x7d\xf7\x9e\x5e\xa3\x13\xf5\x82\xec\x53\xdf\xa5\xfa\x78\x3d\xab\x17\xa8\xa1\xbe\x9f\xf5\xee\xbb\x5b\x2f\x94\xba\xa1\xf3\xf5\x21\xfa\x60\x2f\x10\xbf\x53\x8f\xec\x0d\x3a\x66\x1f\xb4\x7f\xf6\x02\xcd\xd7\xf6\xd6\xde\x79\xa7\xed\x68\x91\x5b\x10\x5e\x09\x8e\x4e\x47\xaf\xb2\x2c\x1e\x36\xc7\x42\xaa\x89\x9c\x87\xeb\x8d\x5e\xc8\x76\x1b\xba\xfd\x10\x18\xdb\xde\x6e\xc7\xd7\xae\x7f\x77\x0f\x83\xaf\x44\x96\xd8\x59\x16\xd2\xd6\x1d\x45\x79\x49\x7b\x96\xc6\x66\xde\xa5\x43\x7b\x5e\x8c\xeb\xd2\x56\x7c\x66\x57\xb1\xf9\x18\x7b\x9f\x47\xd9\x3b\x1f\x67\xef\x06\x0b\xbb\x80\xc1\x97\x43\xc0\x0e\x9e\xda\x01\xee\xf5\x05\xf2\x7a\x82\x76\x3d\x03\xba\xbe\x60\x5c\x7f\xe0\xad\x47\xc8\xd6\x33\x50\xeb\x07\x9e\xf5\x03\xca\xfa\x81\x62\xc7\x03\xb0\x0e\xb1\xdf\x0c\xb6\x3e\x0a\xc4\xea\x20\x68\x33\x9c\x7a\xa7\x20\xaa\xa3\x5c\x2d\x80\xe9\x1d\xc3\xa4\x0e\x02\x76\x86\x44\xbd\x01\xa1\x0f\x05\x7f\xba\x3c\x5f\xe2\x9d\x43\xfe\x9d\x00\x9c\x56\xa1\x5a\x4a\xe9\x2e\xa7\xdc\x0f\x84\x7f\xa5\x93\xee\xb6\x66\xed\x7a\xda\xbd\xed\xc8\x6a\x87\x47\x28\x7d\x1c\x5d\xed\x70\x7c\x75\xa8\x79\x87\x9a\x77\xa8\x79\x87\x9a\x77\xa8\x79\x87\x9a\x77\xa8\x79\x87\x9a\x77\xa8\x79\xbf\x71\xcd\x3b\x9c\xbe\x1c\x4e\x5f\x0e\xa7\x2f\x87\xd3\x97\xc3\xe9\xcb\xe1\xf4\xe5\x70\xfa\x72\x38\x7d\x39\x9c\xbe\x1c\x4e\x5f\x0e\xa7\x2f\x87\xd3\x97\xc3\xe9\xcb\xe1\xf4\xe5\x70\xfa\x72\x38\x7d\x39\x9c\xbe\x1c\x4e\x5f\x0e\xa7\x2f\x3f\xd2\xe9\xcb\xc7\xba\xb4\x54\xe2\xa8\x48\x39\xf9\xfb\xb0\xdc\x3b\x97\xf2\xa9\xdd\x5f\x80\x55\xc3\x46\xf1\x66\xf7\xed\xe7\xc9\xdb\x86\x97\x67\xdf\x50\xad\x1e\x99\x97\x89\x07\x21\x57\xc2\xb3\xb5\x8e\x86\xaa\x48\x65\xbb\xc9\x0e\xab\xfc\x6c\xcf\xc4\x0d\xaf\x4f\x96\xc2\xbd\x64\xab\xc2\x29\x6a\x7d\xa5\x0d\x18\xc6\x54\x9b\x7b\x45\x21\xa5\x21\xe5\x7b\x5e\xbf\xdd\x3b\x70\x3b\x25\xf8\x46\x6c\xcf\xc0\xc0\xd1\x91\x5e\x09\xb9\x44\xd3\xb0\x76\x9d\xd6\xf9\x8a\x51\x5d\xbf\x2b\xb5\x4e\xaf\x52\xfa\x73\xdb\xcd\xc7\x4d\x6e\xca\x3c\x5e\xce\x57\xe5\x2d\xa4\x3b\x7a\x66\xe8\xd6\xc7\x3d\xbe\x78\xac\xed\xc5\xd7\xf6\xf5\x65\xf9\xbb\xd6\x0e\x5f\x5d\xe6\x5e\x6c\x16\x20\x26\xc2\xf3\x1f\x29\x7c\x5d\xac\xf3\xf7\xb2\x69\xb3\xff\xc2\xb2\xd7\x73\xd6\xa6\x6a\xb4\xd5\x70\x69\x04\x3c\x7d\x1b\xa7\x6b\x36\x3c\x4a\x55\x79\xc3\x72\xfc\x7a\x76\x0f\x58\x1a\xcb\x35\xbe\xde\xd1\x9a\x67\xfa\xcc\x03\xe7\x45\xcb\x61\xfa\xba\x6f\x25\x3a\x6e\x17\xa8\xd6\xa8\xb7\x93\x23\xdb\xd3\xf8\xc1\x45\x9b\xb3\x83\x9d\x2c\xad\xa1\x64\xc4\x4c\xb5\x73\x25\x5b\xa8\xfd\x37\x6b\xe6\x09\x85\xfc\xf5\xf7\x68\x9b\x5b\xb0\x8f\x93\x1a\x16\xdc\xee\xff\x4b\x8f\x93\x93\xd2\xff\xea\xb0\x3f\x77\x32\x3b\xf9\xed\xf7\x91\x5b\x98\x05\x5f\x8b\x7f\xc5\x81\x17\xff\x0f\x8d\x77\xc7\xbe\xcf\x64\x00\x00")
 
 func deployManagedCommonAppsOpenClusterManagementIo_helmreleases_crdYamlBytes() ([]byte, error) {
 	return bindataRead(
@@ -103,7 +103,7 @@ func deployManagedCommonAppsOpenClusterManagementIo_helmreleases_crdYaml() (*ass
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "deploy/managed-common/apps.open-cluster-management.io_helmreleases_crd.yaml", size: 17162, mode: os.FileMode(436), modTime: time.Unix(1661072854, 0)}
+	info := bindataFileInfo{name: "deploy/managed-common/apps.open-cluster-management.io_helmreleases_crd.yaml", size: 25807, mode: os.FileMode(436), modTime: time.Unix(1792029589, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -128,7 +128,7 @@ func deployManagedCommonAppsOpenClusterManagementIo_placementrules_crdYaml() (*a
 	return a, nil
 }
 
-var _deployManagedCommonAppsOpenClusterManagementIo_subscriptionreports_crd_v1alpha1Yaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xc4\x5a\x59\x6f\xdc\x46\xf2\x7f\xd7\xa7\x28\x28\x0f\x4e\x00\x91\xa3\x23\xfe\xe7\xaf\x79\x73\xe4\xf5\x42\xbb\x89\x6c\x48\x72\x16\x48\x90\x87\x1e\x76\x71\xd8\x71\xb3\x9b\xdb\xc7\x8c\xb9\x41\xbe\xfb\xa2\xaa\x9b\x1c\xce\xa1\xcb\x40\xb2\x84\x0d\x9b\x7d\x54\x55\xd7\xf1\xab\xaa\xe6\x1c\x15\x45\x71\x24\x3a\xf5\x13\x3a\xaf\xac\x99\x83\xe8\x14\x7e\x0e\x68\xe8\xcd\x97\x9f\xfe\xdf\x97\xca\xce\x56\x67\x47\x9f\x94\x91\x73\xb8\x8a\x3e\xd8\xf6\x16\xbd\x8d\xae\xc2\xb7\x58\x2b\xa3\x82\xb2\xe6\xa8\xc5\x20\xa4\x08\x62\x7e\x04\x20\x8c\xb1\x41\xd0\xb0\xa7\x57\x80\xca\x9a\xe0\xac\xd6\xe8\x8a\x25\x9a\xf2\x53\x5c\xe0\x22\x2a\x2d\xd1\x31\xf1\x81\xf5\xea\xb4\x7c\x5d\x9e\x1e\x01\x54\x0e\x79\xfb\xbd\x6a\xd1\x07\xd1\x76\x73\x30\x51\xeb\x23\x00\x23\x5a\x9c\x83\x8f\x0b\x5f\x39\xd5\xd1\x1a\x87\x9d\x75\xc1\x97\xa2\xeb\x7c\x69\x3b\x34\x45\xa5\xa3\x0f\xe8\x8a\x56\x18\xb1\xc4\x16\x4d\x28\x95\x3d\xf2\x1d\x56\x24\xcd\xd2\xd9\xd8\xd1\x31\x1f\x5f\x9e\x58\x65\xf9\xd3\xd9\xef\x26\x5c\x6f\x99\x2b\x4f\x6a\xe5\xc3\x3f\x1f\x58\xf0\x83\xf2\x69\x51\xa7\xa3\x13\xfa\xa0\xe4\x3c\xef\x1b\xeb\xc2\xcd\x86\x63\xc1\x02\xc6\x85\xdb\xf0\xf1\xca\x2c\xa3\x16\xee\x10\x91\x23\x00\x5f\xd9\x0e\xe7\xc0\x34\x3a\x51\xa1\x3c\x02\xc8\x9a\x65\x9a\x05\x08\x29\xd9\x56\x42\x7f\x70\xca\x04\x74\x57\x56\xc7\xd6\x8c\x1c\x7f\xf3\xd6\x7c\x10\xa1\x99\x43\x99\xa8\xde\xf7\x1d\xf2\xdc\xa0\xf7\xdb\xdd\xe1\xd0\x13\x4f\x1f\x9c\x32\xcb\x7d\x2a\x3e\xb6\xad\x70\x7d\x29\xb1\xd3\xb6\x67\x89\x36\xb4\xde\x6e\x0f\x3e\x8f\x92\x32\x1f\x9c\x5d\x3a\xf4\x7e\x8b\xd6\xf5\xee\xf0\xf3\xa8\xd5\x42\xe9\x1d\xa9\xde\x4d\x87\x9e\x47\xa5\x73\xb6\x13\x4b\xf6\xd7\x77\xfb\x04\x3f\x3c\x30\xfb\x3c\xda\xd9\x37\xb7\x4f\x7b\xb5\x3d\xf8\x38\xa5\x21\x2e\xcb\xbd\x98\xda\xa2\xf9\x66\xb9\x6d\x52\x29\x42\x1a\x48\xd3\xab\x33\xa1\xbb\x46\x9c\x25\x47\xac\x1a\x6c\xc5\x3c\xaf\xa7\x18\x7a\xf3\xe1\xfa\xa7\x8b\xbb\xad\x61\x00\x89\xa3\x93\x1e\x0a\x0d\x50\x1e\x42\x83\x90\xb6\x41\x6d\x1d\xbf\x1e\x08\x10\x78\xf3\xe1\x7a\xa4\x4a\xda\x46\x17\xd4\x10\x28\xe9\x99\x00\xd8\x64\x74\x47\x86\x57\x24\x66\x5a\x05\x92\x90\x0b\x93\x04\x39\x4a\x50\xe6\x93\x81\xad\x21\x34\xca\x83\xc3\xce\xa1\x47\x93\xb0\x8c\x86\x85\x01\xbb\xf8\x0d\xab\x50\xc2\x1d\x3a\xda\x48\x91\x1b\xb5\x24\x88\x5b\xa1\x0b\xe0\xb0\xb2\x4b\xa3\xfe\x33\x52\xf3\x10\x2c\xb3\xd1\x22\xa0\x0f\xc0\x91\x67\x84\x86\x95\xd0\x11\x4f\x40\x18\x09\xad\xe8\xc1\x21\xd1\x85\x68\x26\x14\x78\x89\x2f\xe1\x47\xeb\x10\x94\xa9\xed\x1c\x9a\x10\x3a\x3f\x9f\xcd\x96\x2a\x0c\xe0\x5c\xd9\xb6\x8d\x46\x85\x7e\xc6\x38\xab\x16\x31\x58\xe7\x67\x12\x57\xa8\x67\x5e\x2d\x0b\xe1\xaa\x46\x05\xac\x42\x74\x38\x13\x9d\x2a\x58\x58\xc3\x00\x5d\xb6\xf2\x2b\x97\xe1\xdc\xbf\xda\x52\xde\x9e\x63\xa5\x87\xc1\xf0\x11\x2d\x13\x16\x92\x71\x45\xde\x9a\x4e\xb1\x51\x26\x0d\x91\x3e\x6e\xff\x76\x77\x0f\x03\xeb\xa4\xf0\xa4\xdb\xcd\x52\xbf\x51\x33\xa9\x48\x99\x1a\x5d\x5a\x59\x3b\xdb\x32\x15\x34\xb2\xb3\xca\x04\x7e\xa9\xb4\x42\x13\xc8\x87\x5a\x15\xc8\x7e\xff\x8e\xe8\x03\x59\xa0\x84\x2b\xce\x4a\xb0\x40\x88\x1d\x79\xb7\x2c\xe1\xda\xc0\x95\x68\x51\x5f\x09\x8f\x7f\xba\x92\x49\x9b\xbe\x20\xe5\x3d\x4f\xcd\xd3\x84\xba\xbb\x38\xe9\x69\x32\xb1\xc1\xeb\x47\x2c\xb3\x41\xef\x21\xf6\x28\xb8\xc1\x3a\x50\x92\x04\xad\x15\xba\xe4\xfb\xc8\x7c\xe8\xff\x93\xfc\x33\x3c\x68\x62\xbb\xcd\xa5\x80\x37\x5d\xa7\x55\xc5\x61\xb2\x33\x93\xc1\xea\x39\x27\x1e\xdd\xf0\xd1\x33\xe4\x35\xec\x61\x06\x6c\x97\x32\x1a\x38\xac\xd1\xa1\x21\x4f\xb2\x7b\x40\xb2\x21\xbd\x45\x59\x05\x6c\x77\x98\xed\x3a\xf3\x7b\xd6\xf4\xed\x48\x9c\x8c\x2f\x94\xf1\x80\xc6\xc6\x65\xc3\xfe\xe2\xda\x84\x0f\xc1\x82\xc6\x00\xbd\x8d\xa0\x0c\x95\x1b\x81\x74\xdb\x5a\xa9\xea\x9e\x45\x62\x19\x1d\xca\x11\x43\x8a\xa2\x80\x1b\x5c\x43\xf4\xe8\x47\xd4\x61\xd5\x0b\x87\x20\x95\xaf\x6c\x74\x62\x89\x12\x16\x58\x89\xe8\xd9\x24\x52\xd5\xb5\xaa\xa2\x0e\x7d\x96\x75\x41\x11\x45\xfe\x1e\xbd\x58\x22\xac\x1b\x34\x80\xed\x02\xa5\x44\x09\xca\x10\x7c\xfa\x12\xe0\xac\x84\xeb\xa5\xb1\xc4\xbf\x56\xa8\x25\x8d\x5d\x13\x1e\x55\x3a\x4a\xa4\x08\x33\x7d\x9e\x81\x75\xa3\xaa\x86\x85\xa0\x98\x59\xa2\x41\x27\xb4\xee\xa1\xb1\x4c\xa0\x04\x78\x47\x6e\x63\x7c\x10\xa6\xc2\x93\xd1\x2c\x03\xbc\x12\xa8\xbd\x23\x52\x94\x85\x98\xce\xc2\x86\x86\x90\xb6\x07\x27\x1c\xea\x9e\x50\x41\xb1\x78\xa2\x0a\x51\xe8\x24\x7c\x09\x70\x4e\x71\x99\x26\xd3\x79\x1a\xd4\x5d\x16\xd5\x83\x6a\x3b\xeb\xbd\x5a\x68\xb6\xb3\x90\x12\x48\xd1\xaa\x56\x15\xaf\xe3\x34\xa2\x8c\x54\x2b\x25\xa7\x44\xaf\x0d\xb4\xd6\x87\x8d\x5a\x78\xc2\x9f\x90\x59\x5c\xd2\x76\x27\x5c\x20\xb5\x0a\x37\x38\x63\x70\xaa\xe2\xf0\x05\xad\x3e\xe1\x09\x1c\xb7\xd1\x87\x64\x44\xb0\x46\xf7\xec\x69\x14\xd5\xf0\x86\x0f\xfc\xfd\x31\xd9\xfb\xf8\xe3\xf5\x5b\xd6\x5a\xd6\x55\x1a\xe4\x48\xe3\xfd\x0b\x1c\x69\xa3\x3c\x2e\x99\xd9\x7d\x63\x3d\x42\x35\x22\xd4\x1a\xb5\x1e\x8c\x8b\x72\xdb\xa2\x25\xc0\x05\xa9\xa8\xb2\xc6\x2b\x1f\x08\xef\x58\x5b\xec\x83\x25\xc0\xf7\xd9\x53\xc8\xe1\xd2\x29\xb3\x33\xd5\xec\xc3\xe1\x24\xe5\xbc\x71\x0b\xb8\xa8\x77\xd7\xc0\xa2\x4f\x7b\x4f\xb2\x27\xb4\xe2\x13\x85\x5c\x80\x46\x38\xc9\x4a\x8e\x9e\x50\x39\x58\xe8\x1c\x4a\x55\x05\x58\x37\x22\xc0\x5a\x69\x0d\x8d\xe8\x3a\x24\x51\xbe\x2d\xe1\xbe\xc1\xc1\xa7\x46\x2f\x50\x6d\xe7\xb0\x52\x1e\x59\x6b\x76\x85\x4e\xf7\x90\x87\x4a\x80\x21\x7f\x90\x2e\xc4\x30\x0e\xad\xe8\x3a\xce\x1c\x16\x04\x7c\xbc\xfd\x81\x48\x2b\x4f\x3a\xa3\x92\x40\xc6\x0a\x41\xb4\x0b\xb5\x8c\x2a\xf4\x29\x8e\x23\x27\x1f\x4e\xb7\x9d\xc3\x9c\xc3\x89\x23\xa5\x05\x45\x56\x4f\x29\x28\x53\x9e\x78\x49\x25\x7c\xf6\x0d\x90\xd8\xa1\x91\x68\xaa\x9e\x44\xa2\x20\x6f\x30\xb5\x10\x27\x9b\xd4\x15\x3b\x8d\xcc\x93\xa8\x4f\x2a\x8a\x01\x4c\xb3\x87\xfb\xe0\x62\x95\xbc\xd8\x39\xd4\xb8\x12\x26\x94\x00\xaf\x4b\xf8\xd7\x68\x7c\x14\x5e\xe9\x1e\xaa\x46\x98\x25\x82\x0a\x5b\x06\x1d\xc0\x41\xf9\xad\xf8\xe6\xc0\xd5\x36\xc1\xaf\x3f\xc9\xf9\x2d\xd7\x1d\xc3\x1e\x7a\xd8\x3a\xa2\xae\x09\x99\x4c\x6c\xd1\xd9\xe8\x87\x2a\xa5\x04\x78\x6b\xcd\xab\x57\x81\x6d\x0d\x06\xd7\x8c\x1b\x89\x11\x21\x6d\x34\x12\x5d\x0e\x36\x94\x34\x99\x08\x87\x06\x7b\x90\x96\xcd\x95\xfb\x3c\x72\x4f\x1f\x50\x48\x52\x40\xf4\x29\xe1\x67\x41\x4e\x52\x73\x47\xda\x27\x91\x35\x9b\xde\xae\x94\x64\x2e\x14\x98\x28\x07\xc2\x82\x95\x45\xc1\x50\xd4\xb6\xe2\x19\x6b\x08\x5f\xdd\x06\xee\x4b\x46\x22\xfc\x2c\xda\x4e\xe3\x09\x97\x0b\xaa\xc2\x11\xb0\x3d\x3b\xab\x90\xad\xf2\x3e\x25\x82\xa5\xf2\xc1\x89\x04\xef\x93\x3c\xdf\xc4\x45\x59\xd9\x76\x46\xbd\xa9\x33\x18\xd0\x53\x12\x9f\x2d\xb4\x5d\xcc\xc8\x58\xc2\x63\x71\x56\x9e\x7d\x37\x1b\x69\x4d\x49\xcd\x56\x67\x33\x86\x82\x72\x69\xbf\xfa\xe1\xf5\xc5\x05\x94\xaf\x76\xf2\xca\xe1\xc2\x35\x3d\x0f\x95\xaf\x07\x32\x12\xe9\x7d\xc7\xbd\xb2\x2e\x42\x79\x60\xef\x03\xa9\x36\x3d\xf5\x80\xd0\x4f\x72\x7d\x75\x5d\xe7\xec\x35\xc6\x60\xa7\xb0\xc2\xad\x9a\x98\xf3\x41\xb6\xba\x30\x40\x25\x85\xc3\x3c\x77\x92\x3c\x20\x57\x84\x9b\x9a\x99\x92\x29\x88\x9c\x0c\xfe\x71\xf7\xfe\x66\xf6\x77\x9b\xe4\x02\x51\x55\xe8\x69\x8b\x08\xdc\x95\x9f\x80\x8f\x94\x94\x3c\x89\xa6\x1c\xca\x3b\x9a\x29\x5b\x61\x54\x8d\x3e\x94\x99\x1a\x3a\xff\xcb\xf9\xaf\x3b\x6e\xa1\x92\xa6\xc6\xfa\x72\x48\xe7\xca\xa7\xc3\x8c\x7b\x61\xad\x42\xc3\x22\x75\x56\x66\xa1\xd7\x2c\x6c\xa0\xb0\xb0\x59\xd8\x88\x9c\x13\xe6\x70\x4c\x11\x31\x61\xfd\x3b\x01\xfd\x1f\xc7\xf0\xf5\x9a\x13\x0b\xe3\xfe\x71\x62\x38\x36\x02\xa9\xea\x4a\x12\x6d\x18\xb3\xbb\x07\xa7\x96\x4b\xa4\x14\xcd\xb5\x2d\xd5\x8f\xdf\x70\x81\x56\x83\xb1\x93\xc5\x4c\x82\xf4\x39\xc6\xe3\xae\x20\xbf\x9c\xff\x7a\x0c\x5f\x6f\x9f\x8b\x32\x23\x7e\x86\x73\x02\x0d\x3e\x59\x67\xe5\x37\x19\x48\x7d\x6f\x82\xf8\x4c\x34\x2b\x4a\x46\x66\xcc\x70\x8d\x58\x21\x78\xdb\xa6\xac\x54\xa4\xc6\x49\xc2\x5a\xf4\xa9\x3e\x4c\xaa\x24\xab\x0a\xce\xa1\x3b\x6d\xd2\xfd\xfb\xb7\xef\xe7\x89\x1b\x99\x6d\x69\x06\x68\xaf\x15\x95\x6d\x09\x31\xa9\xa0\x67\x9b\x93\x20\x31\x19\x29\xd8\x11\x05\x13\xea\xd6\x91\x4a\xeb\xbd\xb8\x7a\xd2\xcb\xf7\xfb\x95\x43\x0e\xce\x59\x67\x37\xa0\xfe\x67\x3d\xc1\x33\x8e\xc5\x8d\xf9\x93\xc7\xba\x99\xf8\xda\xa3\xc7\xda\xe0\x1e\x9d\x4c\xda\xca\xd3\xa1\x2a\xec\x82\x9f\x51\x8a\x5e\x29\x5c\xcf\xd6\xd6\x7d\x52\x66\x59\x90\x33\x15\xc9\xc2\x7e\xc6\xf7\x64\xb3\xaf\xf8\x9f\x2f\x3a\x05\x5f\x57\x3d\xef\x28\xbc\xf4\xaf\x38\x0f\xf1\xf1\xb3\x17\x1f\xc7\x6d\xd7\xc1\x4f\x1f\xea\x6e\xa8\x5e\x77\x76\x92\xfb\xa7\xd2\x2b\xdf\x44\x4c\x10\xab\x15\x32\x41\x9a\x30\xfd\x9f\xee\xa2\xa4\xb4\xe8\x88\x77\x5f\xe4\xf4\x5e\x08\x23\x8b\xb1\xfc\xac\xfa\x17\x6b\x29\xaa\x67\x04\x24\x95\xd1\x7f\x89\xe3\x46\xf5\xe2\xe8\x7b\xa0\x0b\x1f\x26\x84\x73\xa2\xdf\x6e\x6c\xa3\x0e\x8f\xb5\xb5\xfb\xd7\x63\xb7\xbc\x67\xa8\x8d\x7c\xa6\x01\x12\x83\x50\xfa\xa5\x6d\xec\xd3\xe4\x93\x9a\x79\x8c\x0b\x26\x33\xed\xa2\xa6\xad\xf4\x0b\xaa\x9a\x44\xef\x49\x4b\x67\x51\x88\x5f\x95\xea\x56\xca\xd4\x31\x54\x76\x03\x5d\x5b\xcd\x7c\xba\x4e\xa6\x92\xe0\x00\xe9\xfd\xdb\x89\xf4\x14\xb0\x73\x0b\xbd\x3d\xb9\x75\x15\xbc\x3d\xf5\xd0\xfd\xee\xf4\x79\xd4\xdd\x53\x60\x3f\xa9\x87\xbb\xd4\x4d\xa4\x7b\x8d\xc9\x8d\xcc\xa1\x9b\xd1\x97\x8a\x10\xc6\x0f\x29\x4f\x49\x31\x5e\x0f\xef\x18\x84\x28\x4c\xbd\x64\x2d\xa8\xb4\x8e\xe6\x90\x36\x1e\x73\x0a\x60\xd0\x37\xf6\x81\xa9\x1d\x69\x6e\xac\x29\x0c\x92\xf2\x57\x08\xb5\x13\xb9\x1f\xa7\x12\x03\x3c\x56\xd6\x48\x10\x21\xd1\x4b\x6f\x04\xa3\x3a\xa6\x56\xf8\x66\xd8\x98\xe7\xd2\x6d\x6a\xaa\x84\x36\xb4\xb8\x29\xf7\x21\x35\xae\x2b\x04\x33\x65\xc9\x94\x87\x8d\x5c\xa1\x55\x36\x1a\x8e\x91\x35\x75\xc2\x54\x9a\xa8\x16\x4b\xf8\x31\xb7\xf6\x7c\x2f\x79\x4a\xe8\x7d\x79\x79\x79\x92\xff\xa6\x3b\x16\xaf\x56\x98\x8b\xad\x54\xec\xb4\xa2\xa7\x2d\x5a\xb5\x2a\xa4\x4e\x2e\x35\xbb\xd9\xc7\xd1\x48\xaa\xaa\xac\xe1\xba\x0f\x3f\x1f\xac\xef\xe9\x49\x37\x50\x73\x6a\x75\x2f\xce\x1f\x58\x93\xbc\x83\x9a\xe1\xe5\xce\x65\xdc\xf0\x24\x25\x3d\xcf\x2c\xb7\xe3\x05\xed\xb0\x8d\x4c\xf2\xf1\xfe\x2a\xb9\x89\x57\x94\xa9\x3e\x1a\xf5\x19\xb0\xb3\x55\x03\x67\x97\xdf\x9d\x16\xa7\x67\xc5\xe9\xd9\xfd\xe9\xe9\x9c\xff\xfc\xbc\xab\xb3\x53\x9e\xdf\x5a\x92\xd5\x78\x59\x9c\x9d\x17\x17\x67\xf7\xe7\x17\xf3\xd7\x97\xf3\xd7\x97\x3f\x4f\xf4\xf9\xb4\x4a\xfe\xef\xdb\x2f\x54\x49\xbe\x26\x38\x98\xaa\x8a\xe4\x19\x07\x67\xb2\x46\x1e\x0c\xd1\x03\x69\xe3\xc5\x19\x25\x7f\x1f\x7a\x51\x46\xb9\x4b\x7b\x36\x98\x2f\x06\x32\xa9\x90\xe7\x1c\xb5\x45\xf0\xe1\x38\x1e\xbe\x4b\xed\xeb\x66\x4b\x88\xe1\x4b\xd5\x76\x9e\x49\x21\x44\x31\xac\x35\xa4\xaf\xae\x72\xa4\xb8\x0f\xf7\xdc\x39\x24\xe8\x86\x60\xf7\xa1\xed\x61\xdc\x1b\xb6\x3d\x21\xe5\xf0\x25\xf2\x01\x29\xa7\xc2\x64\x10\x18\xe5\xf1\x91\x9b\xd4\x3a\x6a\xdd\xbf\x44\xb2\x94\x6e\x9e\x90\x2b\x65\x9b\xe7\x4b\x95\x88\x52\xd0\x24\xf9\x5e\x22\xd0\xe6\xc3\xea\x13\x42\x6d\x3e\xb5\x3e\x5f\x30\xe1\xc6\x26\xae\x73\x96\xbb\x7a\x5b\xc3\x02\x09\xde\x1e\xcc\xca\x8f\x08\xbb\x97\x91\x9f\x90\x79\xef\x1b\xec\x97\xe8\x74\x60\x9a\x2e\x9e\x77\xfd\xf6\xf9\xe2\x1f\x8c\xf4\x7d\xac\x29\x60\xef\x1b\xfc\x81\xbd\x1e\xdd\x0a\xe5\x1c\x82\x8b\x69\x95\x0f\xd6\x89\x25\x4e\x47\xe2\x62\xf3\x61\x05\x7e\xff\xe3\xc8\x07\x11\x22\x9b\x59\x54\x54\x26\xa3\xbc\xd9\xfd\xa5\xc3\xf1\x31\xbf\x0c\x3f\x5a\xe0\x57\x02\x35\x95\x7e\xd3\x01\xbf\xfc\x7a\x94\x58\xa1\xfc\x69\xf8\x89\x01\x0d\xfe\x37\x00\x00\xff\xff\x61\x8c\x1e\xdb\x4d\x22\x00\x00")
+var _deployManagedCommonAppsOpenClusterManagementIo_subscriptionreports_crd_v1alpha1Yaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xed\x5b\x5b\x73\xdb\xc6\x15\x7e\xe7\xaf\xd8\x91\x1f\xd4\xcc\x88\xa0\x2e\x71\x53\xf1\xcd\x91\xeb\x8c\x5b\x57\xf6\x48\xb2\x3b\x93\x4c\x1e\x96\xc0\x92\xdc\x08\xc0\xa2\xbb\x80\x24\x36\x93\xff\xde\xef\x9c\x5d\x80\x00\xaf\xa0\xdd\xa4\xc9\x54\x1c\x5d\xc8\xbd\x9c\x3d\xf7\x1b\x17\x83\xe1\x70\x38\x90\x85\xfe\xa4\xac\xd3\x26\x1f\x0b\xbc\x57\x4f\xa5\xca\xe9\x93\x8b\xee\xff\xe2\x22\x6d\x46\x0f\x67\x83\x7b\x9d\x27\x63\x71\x55\xb9\xd2\x64\x37\xca\x99\xca\xc6\xea\xb5\x9a\xea\x5c\x97\x58\x39\xc8\x54\x29\x13\x59\xca\xf1\x40\x08\x99\xe7\xa6\x94\x34\xec\xe8\xa3\x10\xb1\xc9\x4b\x6b\xd2\x54\xd9\xe1\x4c\xe5\xd1\x7d\x35\x51\x93\x4a\xa7\x89\xb2\x0c\xbc\x3e\xfa\xe1\x34\x7a\x19\x9d\x62\x47\x6c\x15\x6f\xbf\xd3\x99\x72\xa5\xcc\x8a\xb1\xc8\xab\x34\xc5\x4c\x2e\x33\x35\x16\xae\x9a\xb8\xd8\xea\x82\xd6\x58\x55\x18\x5b\xba\x48\x16\x85\x8b\x4c\xa1\xf2\x61\x9c\x02\x49\x1c\x95\xc9\x5c\xce\x54\xa6\xf2\x12\xa7\x0c\x5c\xa1\x62\xc2\x66\x66\x4d\x55\x10\x99\xbb\x97\xfb\xa3\x02\xfe\x9e\xf6\xdb\xd6\xa9\x37\x7c\x2a\x4f\xa6\xda\x95\x7f\xdf\xb2\xe0\x1d\xe6\x78\x51\x91\x56\x56\xa6\x1b\x31\xe7\x79\x37\xc7\xdb\xeb\xe5\x89\x43\x46\xb0\x9a\xd8\xe5\x39\x4e\xe7\xb3\x2a\x95\x76\x13\x10\x2c\x70\x31\xa8\x19\x0b\x86\x51\xc8\x58\x25\x18\x0b\x9c\x65\x98\x80\x98\x24\x2c\x2b\x99\x7e\xb0\x3a\x07\xc9\x57\x26\xad\xb2\xbc\x39\xf1\x27\x67\xf2\x0f\xb2\x9c\x8f\x45\xe4\xa1\xde\x2d\x0a\xc5\x73\x35\xdf\x6f\x56\x87\xcb\x05\x9d\xe9\x4a\xc0\x9b\xad\x43\x71\x55\x96\x49\xbb\x88\x12\x55\xa4\x66\xc1\x18\x2d\x61\xbd\xee\x0e\xf6\x83\xa4\xf3\x0f\xd6\xcc\xac\x72\xae\x03\xeb\xed\xea\x70\x3f\x68\x53\xa9\xd3\x15\xac\xde\xb4\x87\xfa\x41\x29\xac\x29\xe4\x8c\xf5\xf5\xcd\x3a\xc0\x0f\x5b\x66\xfb\xc1\x0e\xba\xd9\xa5\xf6\xaa\x3b\xb8\x1b\x52\x6d\x97\xd1\x9a\x4d\x75\x60\xbe\x9a\x75\x45\x8a\x2d\x7e\xc0\x4f\x3f\x9c\xc9\xb4\x98\xcb\x33\xaf\x88\xf1\x5c\x65\x72\x1c\xd6\x93\x0d\xbd\xfa\xf0\xf6\xd3\xc5\x6d\x67\x58\x88\x44\x35\x4a\xba\xc9\x34\x84\x76\xa2\x9c\x2b\xe1\xb7\x89\xa9\xb1\xfc\x71\x83\x81\x08\x80\x6f\xa0\x12\xb7\x95\x2d\x75\x6d\x28\xfe\xd5\x72\x60\xad\xd1\x15\x1c\x8e\x09\x4d\xbf\x0a\x13\xf0\x5c\xca\x63\x10\xac\x44\x25\x81\x32\x61\xa6\x18\x07\x7a\x38\x1f\x3a\x05\x87\xc0\x8c\xa3\x61\x89\xbf\x93\x9f\x54\x5c\x46\xe2\x56\x59\xda\x48\x96\x5b\xa5\x09\xb9\x38\x7c\x2c\xb1\x27\x36\xb3\x5c\xff\xbb\x81\x86\x33\x0c\x1f\x93\x82\xa5\x0e\x64\x93\xe5\xc1\x06\xc5\x83\x4c\x2b\x75\x02\x90\x89\xc8\xe4\x02\x1b\x09\xae\xa8\xf2\x16\x04\x5e\xe2\x22\xf1\x0f\x63\x15\x36\x4e\xcd\x58\xcc\xcb\xb2\x70\xe3\xd1\x68\xa6\xcb\xda\x39\xc7\x26\xcb\x2a\xb8\xe1\xc5\x88\xfd\xac\x9e\x54\xa5\xb1\x6e\x94\xa8\x07\x95\x8e\x9c\x9e\x0d\xa5\x8d\xe7\xba\x04\xf4\xca\xaa\x11\x58\x35\x64\x64\x73\x76\xd0\x51\x96\xbc\xb0\xc1\x9d\xbb\xe3\x0e\xf3\xd6\x14\xcb\xbf\xd8\x19\xee\xe0\x32\xf9\x42\x12\xae\x0c\x5b\x3d\x15\x4b\x66\xd2\x10\xf1\xe3\xe6\xaf\xb7\x77\xa2\x3e\xda\x33\xdc\xf3\x76\xb9\xd4\x2d\xd9\x4c\x2c\x02\x07\x94\xf5\x2b\xa7\xd6\x64\x0c\x45\xe5\x49\x61\xc0\x53\xfe\x10\xa7\x1a\xbb\x48\x87\x32\x5d\x92\xfc\xfe\x05\xf6\x95\x24\x81\x48\x5c\x71\x54\x12\x13\x25\xaa\x82\xb4\x3b\x89\xe0\x36\x30\x9a\xa9\xf4\x4a\x3a\xf5\xab\x33\x99\xb8\xe9\x86\xc4\xbc\x7e\x6c\x6e\x07\xd4\xd5\xc5\x9e\x4f\xad\x89\xa5\xbf\xde\x21\x99\xa5\xf7\xae\x6d\x8f\x8c\x5b\xc0\xf0\x74\x42\x88\x4e\x35\xb8\xcb\xba\xaf\xf8\x1c\x7a\xdf\x8a\x3f\xf5\x4b\xe5\x55\xd6\x3d\x65\x28\x5e\x15\x45\xaa\x63\x36\x93\x95\x99\xe0\xac\xfa\x50\xdc\xa8\xe1\x4e\x1a\xc2\x1a\xd6\x30\x58\x63\xe1\x23\x1a\x36\x43\x37\x54\x4e\x9a\x64\xd6\x1c\xc9\x12\x74\x07\x32\xc4\x95\xad\x1c\xb6\xaa\xcc\xef\x99\xd3\x37\x0d\x70\x12\xbe\xd4\xb9\x03\x17\x4c\x35\x9b\xb3\xbe\xd8\xcc\xfb\x07\x1c\x9c\xaa\x52\x2c\x4c\x85\x61\x4a\x37\x4a\xe2\x6d\x66\x12\x3d\x5d\x30\x4a\x8c\xa3\x85\x5d\xd7\x3e\x04\xb9\x97\xb8\x56\x8f\xa2\x72\x20\xa8\xf6\x3a\xcc\x7a\x09\x5d\x4c\x34\x62\x3a\xd2\x86\x19\x76\x4c\x54\x2c\xb1\x8a\x16\x01\xdc\x54\xc7\x55\x5a\x2e\x02\xae\x13\xb2\x28\xd2\xf7\xca\x61\xad\x78\x9c\xab\x5c\xa8\x6c\xa2\x92\x04\x1b\x75\x4e\xee\x13\x86\x24\xce\xa0\xf0\xb3\xdc\xd0\xf9\x90\x74\x9a\xd0\xd8\x5b\xf2\x47\x08\x32\x00\x04\x0b\xcb\x17\x61\x06\x30\x74\x3c\x67\x24\xc8\x66\x90\xb3\x29\x64\x2f\xe9\x42\xcc\x0d\x03\xc0\xce\x37\xa4\x36\x39\x02\x09\xb8\x72\xd2\x88\xa5\x76\xaf\xe4\xd4\xde\x10\x28\x8a\x42\x0c\x67\x62\xf0\x06\x96\x0c\x47\x87\x8f\x00\x05\xaf\xa0\x19\x3d\x09\x93\x81\x00\x19\x79\x00\x3e\x27\xbb\xf4\x93\x9e\x9e\xb9\x4a\x8b\x80\x2a\xa4\x9e\x15\xc6\x39\x3d\x49\x59\xce\xc8\x68\x04\x31\x1a\xaa\x1b\xf3\x3a\x0e\x23\x30\x31\xfd\xa0\x93\x36\x50\x58\x7a\x66\xe0\x7c\x1b\xb6\xf0\x84\x3b\x21\xb1\x58\xcf\xed\x42\x22\xaa\xc4\x94\x60\xd5\xca\x08\xfd\x8c\xd9\x7c\x91\xe2\xdd\x83\xc8\xa3\x0c\xaa\xec\x85\x28\x4c\x0e\x12\x48\xd3\xc8\xaa\xc5\x2b\x26\xf8\xdb\x23\x92\xf7\xd1\xc7\xb7\xaf\x99\x6b\x81\x57\x7e\x90\x2d\x8d\xf7\x4f\x54\x03\x1b\x93\x11\x1f\x76\x37\x37\x90\x6d\xdc\x78\xa8\x47\x95\xa6\xb5\x70\x81\x6c\x47\xa2\xd8\x71\x41\x2c\x82\x26\x3a\x64\x97\xe4\xef\x98\x5b\xac\x83\x98\xfc\x36\x68\x0a\x29\x9c\xa7\x32\x28\xd3\x94\x75\xb8\x3c\xf1\x31\xaf\xd9\x22\x6c\x95\xae\xae\x11\x93\x85\xdf\x7b\x12\x34\x21\x93\xf7\x64\x72\x20\x4a\xda\x84\x99\x8c\x23\x2c\x87\x36\xb8\xea\x04\xb4\x60\xa1\xc4\x1f\x0d\xc4\xe7\x48\x5d\x15\xa1\xf2\x75\x04\xca\x54\xad\x53\x8d\x16\x40\x86\x88\x71\x1a\x38\x12\xd7\x0c\x94\x02\xbc\x0c\x43\xd8\x55\xc7\x0f\xe2\x85\xac\xc7\x81\x41\x51\x70\xe4\x80\xd4\xc5\xc7\x9b\x77\x04\x1a\x8b\xc0\x33\x4a\x09\x92\x0a\xb6\x29\xb3\x89\x9e\x55\x70\xd1\xde\x8e\x2b\x0e\x3e\x1c\x6e\x01\x24\xc4\x70\x3a\x91\xc2\x82\x26\xa9\xfb\x10\x14\x20\xb7\xb4\x24\x46\x3c\xf0\xba\x01\x21\x80\x14\x78\xc7\x78\x41\x28\x91\x91\x63\x90\x4b\x88\x93\x65\xe8\xaa\x0a\xa8\x23\xa7\x21\x80\xde\xca\x28\x6a\x67\x1a\x34\x1c\x42\xaf\x62\xaf\xc5\xf0\x02\xa9\x7a\x90\x28\x35\x84\x78\x19\x89\x7f\x36\xc2\x57\xd2\x69\x70\x23\x9e\xcb\x1c\xaa\xaf\xcb\x8e\x40\x6b\xe7\x80\xff\x6d\xfb\x66\xc3\x4d\x8d\x77\xbf\xc0\xdb\xc7\xb7\x90\x77\xd4\x7b\xe8\xc5\xd2\x91\x90\x31\xb0\x80\x13\x57\x20\xc3\xd5\x59\x0a\x0e\x7a\x6d\xf2\xe3\xe3\x92\x65\x2d\x72\x78\x25\xf2\x1b\xfe\x20\xf2\xb4\x15\xd8\x60\x83\xb1\x61\x04\x93\x1e\x30\x08\x84\x23\x32\x2c\xae\x50\xe7\x91\x7a\x42\x33\x65\x42\x0c\xa8\x9c\x0f\xf8\x01\x91\x13\x5f\xdc\x11\xf7\x09\xe5\x94\x45\x6f\x60\xae\x7c\x0a\x19\x26\xde\x04\xc0\x92\x99\x45\xc6\x30\x9c\x9a\x98\x67\xc0\x54\xf8\x57\xbb\x74\xf7\x11\x7b\x22\xf5\x84\x84\x36\x05\x70\x4a\x17\x74\xac\x1a\x87\xed\x58\x59\x65\x92\x69\xe7\x7c\x20\x98\xc1\x68\xac\xf4\xee\xbd\x15\xe7\xe7\xd5\x24\x42\x8c\x1f\x51\x6d\x6a\x73\x05\xfe\x51\x10\x1f\x4d\x52\x33\x19\x91\xb0\xa0\x12\xc3\xb3\xe8\xec\x9b\x51\x03\xab\x0d\x0a\x05\xf2\x88\x5d\x41\x34\x33\x2f\xde\xbd\xbc\xb8\x10\xd1\xf1\x4a\x5c\xd9\x9c\xb8\xee\x4e\x5f\x37\x44\x24\xe2\xfb\x8a\x7a\x05\x5e\x94\xd1\x86\xbd\x5b\x42\xad\x7f\x4d\x6b\x0f\xbd\xf7\xd4\xe3\xb7\xd3\x10\xbd\x1a\x1b\x2c\xb4\x8a\x55\x27\x27\xe6\x78\x10\xa4\x8e\x41\x4a\x29\x60\x65\x7e\xee\xc4\x6b\x40\xc8\x08\x97\x39\x33\x05\x53\x00\xf3\xfe\xfe\x6f\xb7\xef\xaf\x47\xdf\x19\x8f\x17\xac\x06\xe2\xa3\x2d\xd0\x96\x8c\x1d\x97\xab\x28\x28\x39\x42\x0d\x90\x93\x5b\x9a\x89\xa0\xfd\x7a\x0a\x87\x1a\x05\x68\xe0\xcd\x0f\xe7\x3f\xae\xa8\x85\xf6\x9c\x6a\xf2\xcb\x3a\x9c\x6b\xe7\x89\x69\xf6\xc2\x46\x80\x28\xa1\x54\x98\x24\x20\xfd\xc8\xc8\x96\x64\x16\x26\x20\x8b\x7c\x96\x62\xc2\x58\x1c\x91\x45\xb4\x8e\xfe\x99\x1c\xfd\x2f\x47\xe2\x4f\x8f\x1c\x58\xd8\xef\x1f\xf9\x03\x9b\x42\xc0\x67\x5d\x1e\xa3\xe5\xc1\xac\xee\x60\xcf\x6c\xa6\x28\x44\x73\x6e\x4b\xf9\xe3\x57\x9c\xa0\x4d\x61\x5f\xad\xc5\x0c\x82\xf8\xd9\xd8\xe3\x2a\x22\xe0\x01\xb0\xe8\xd2\x45\x91\x51\x3d\x89\x73\x72\x1a\x4c\x19\x68\xfc\x2a\x38\x52\xb7\xc0\xca\x27\x82\x19\x53\x30\xca\x9b\x08\x37\x97\x0f\x48\xa6\x4c\xe6\xa3\xd2\xd0\x17\x4e\x88\x49\xc8\xc7\xcd\xb4\x61\x25\x49\x55\x72\x0c\x5d\x29\x93\xee\xde\xbf\x7e\x3f\xf6\xa7\x91\xd8\x66\x79\xed\xda\x01\x06\x3e\xd1\x7b\x4c\x4a\xe8\x59\xe6\x84\x48\xe5\x85\x84\xa3\x6b\x2f\xe8\xbd\xee\xb4\xa2\xd4\x7a\xcd\xae\xf6\x6a\xf9\x7a\xbd\xb2\xb5\x6a\x59\x35\xa8\xff\x59\x4d\xd0\x83\x2c\x2e\xcc\xf7\x92\x75\xdd\xd2\xb5\x9d\x64\x2d\xfd\x1e\x51\x96\x98\xd8\x11\x51\xb1\x2a\x4a\x37\xa2\x10\xfd\xa0\xd5\xe3\xe8\xd1\x58\x20\x3b\x1b\x92\x32\x0d\xbd\x84\xdd\x88\xfb\x64\xa3\x17\xfc\xef\xb3\xa8\xe0\x76\x55\x3f\x52\x78\xe9\x6f\x41\x0f\x9d\xe3\x46\x07\x93\x63\xbb\x79\xf0\x7e\xa2\x6e\xeb\xec\x75\x65\x27\xa9\xbf\x4f\xbd\x42\x27\xa2\xe5\xb1\x32\x99\x78\x97\x86\xb8\xff\xab\xab\x28\x31\xad\xb2\x74\xf6\x62\x18\xc2\xfb\x10\x46\x3b\x6c\xd2\xcf\x78\x71\x30\x97\x2a\xdd\xc3\x20\x29\x8d\xfe\x4d\x14\x17\xd8\x1c\xaa\xb7\x5b\xaa\xf0\x7a\x42\x5a\x2b\x17\xdd\xc2\x16\xe5\xda\xae\xb2\x76\xbd\x3d\x76\xc3\x7b\xea\xdc\xc8\x05\x18\xd8\x05\x7f\x9e\x1e\x5a\xc6\xee\x07\xef\xd9\xcc\x63\x9c\x30\xe5\xed\x2a\xaa\x5d\x4a\x1f\x90\xd5\xf8\xf6\xea\xcd\xe6\xaa\x7e\x03\x96\x6f\xba\xeb\xb9\x60\x08\x78\x85\x91\xa0\x0f\x9d\xd2\x9e\x4e\xa1\xe8\x13\x72\xf3\x78\x43\xbf\x61\x07\x9b\x9e\x6b\xfe\xe7\x9a\xff\xb9\xe6\x7f\xae\xf9\x9f\x6b\xfe\xe7\x9a\xff\xbf\x5f\xf3\xef\x8f\x91\x7d\xea\xff\x2f\xef\x02\xf4\xc8\xc9\x7a\x74\x04\x9e\xfb\x02\xcf\x7d\x81\x3f\x56\x5f\xa0\xb7\xde\x6f\xeb\x11\xfc\x51\x3a\x05\xbd\x09\xdd\xd6\x35\xf8\x9d\xf6\x0e\x0e\xa2\x6b\x6b\x1f\xe1\x77\xdc\x4d\xe8\x4d\x60\x8f\xce\xc2\xff\x53\x7f\xa1\x37\xdf\xb6\xf4\x1a\x7e\x97\x1d\x87\x9e\x44\x6d\xed\x3e\xec\xea\x41\x84\x1a\x38\xa3\xe2\x60\x6f\x25\xfe\x36\x6b\x6a\x88\x6e\x30\xd2\x61\x82\xbe\xee\xa7\x0a\x75\xb1\x5e\x8f\x7f\x49\x1d\xbe\x87\xf4\x5d\x94\xf9\xe6\xc5\x5e\xca\x42\xdf\x83\xca\xc5\xd8\x27\xcc\x14\xfe\xab\x32\x36\x4b\x5f\xd7\x21\xc7\xdf\x5d\xa3\x3c\x63\x03\xe8\xf5\xab\x10\xf5\xb5\x87\x95\x2b\x6f\xdd\xc9\xce\xbd\xb3\xee\xd4\xb6\xcb\x64\x07\x74\x20\x31\xde\x43\xc4\x37\x7e\x5d\x73\x13\xa4\x42\xce\x6f\x7d\xe4\xf6\x13\x75\x22\xe2\xe5\x58\x8b\x1a\x29\x33\x5d\x44\x8c\xd5\xe6\x46\x8c\xcf\xd6\xf5\x26\x5e\xf9\x9e\xc9\x98\x8a\xb3\x8b\xf3\xad\x54\x51\xe9\x36\xdb\xa0\x36\x56\x3d\xe8\x5e\x1d\xd5\x9b\xb0\xb0\x26\xec\x3b\x4d\x25\x4a\x96\x69\x6e\xcc\x30\x45\x73\xca\x46\xea\xab\x91\x5f\xa6\xcb\x3b\x45\xe1\x1d\xee\x5e\x8c\x6f\x7d\x45\xe9\xef\xb3\xb4\x6e\xe2\x6c\xba\x11\x77\x30\x0a\x25\xaa\x2d\xb5\xbf\xdb\x7a\xeb\xd7\x71\xe9\xc5\xf9\xe6\x2a\x27\x38\x47\xaf\xe2\x7b\xce\x74\xa7\xa4\x10\x45\xb8\x71\xb9\xde\x7b\x63\xcc\x53\x83\x14\x8d\xb3\xd7\x9c\xfb\x54\xf5\x72\x9c\x2b\x93\x14\xce\xe4\x50\x52\xca\xe6\x2e\xf0\x3e\x62\x9a\x1b\x8e\x2b\x66\x4e\x10\xda\x8d\xce\x47\x49\x95\x22\x6a\xdc\xc1\xe1\x35\x5b\x2e\x73\xb3\xb5\x9c\xeb\x60\x73\x6d\xf2\x61\xae\xc8\xa4\x91\x2f\x4f\xad\x0c\xed\x25\xca\x86\x85\x53\x70\xad\xa8\x68\x4a\x0f\xcf\x7f\xa2\x48\x9d\x56\xbe\xb3\x73\x5d\x6f\x0c\x73\xfe\x42\xa0\x4f\xda\x97\xb0\xb8\xc7\xe4\x4a\xdf\x87\x79\xa0\xfe\x5d\xeb\x48\x86\x5c\x6f\xe4\x62\x22\x06\xcd\xdc\xe6\x7d\xa4\xc6\x0e\x65\xd1\x60\x0c\x22\x5d\xe8\x54\xf1\xd5\xba\x53\x4a\x10\x2e\x2f\x2f\x4f\xc2\xaf\x6f\x19\x3a\x00\x0c\x75\x81\xcf\xcb\xe9\x56\xde\x84\x6a\x1e\xd8\x97\x6f\x4c\xf8\xde\x4d\xf0\x9c\x0a\xfc\xf7\xea\x41\x21\x44\x3d\x6d\x2d\x4e\xf7\x39\x87\xfd\x0e\x82\xb5\x9d\x99\xd4\x4f\x2c\x37\xcd\x1d\xc3\x7a\x1b\x89\xe4\xe3\xdd\x95\x57\x13\xef\xe3\x3e\xe6\xfa\x49\xa8\xc2\x20\x4d\x3a\xbb\xfc\xe6\x74\x78\x7a\x86\x9f\xbb\xd3\xd3\x31\xff\x7c\xbf\xca\xb3\x53\x9e\xef\x2c\x09\x6c\xbc\x1c\x9e\x9d\x0f\x2f\xce\xee\xce\x2f\xc6\x2f\x2f\xf1\xf3\x7d\x8b\x9f\xfb\x59\xf2\xe7\xaf\x3f\x93\x25\xa1\xeb\x95\x6c\x8e\x51\xac\x19\x1b\x67\x02\x47\x06\x07\xe5\x1e\x07\x7e\x29\x12\xae\x38\x1f\xf4\xa5\xc8\xad\xdf\xb3\xfc\xda\x42\xd6\x60\x7c\xe4\xe2\xaf\x59\x06\xfd\xec\xb8\xbe\x5a\xbd\xce\x9b\x0e\x12\xf5\x65\xeb\xee\x57\x25\xde\x84\xc8\x86\x61\x73\xfe\xc1\x81\xa4\x81\xb8\xd1\x7b\xd6\x09\x01\x14\x62\x70\x80\xdf\xab\xb7\xed\xc1\xb2\xbe\x4c\xbf\x05\xcb\x36\x32\xc1\x09\x34\xf8\xb8\x8a\xfb\x29\xd3\x2a\x4d\x17\x87\x60\xe6\xc3\xfd\x1e\xbc\x7c\x0e\xd3\x1f\xab\x90\x43\xc0\x68\x3c\x7e\x87\x20\xb4\x7c\x36\x60\x0f\x52\xcb\xa7\x05\xfa\x23\x26\x6d\xd3\x6f\xc0\x1e\x6e\x40\x61\xe1\x44\x91\x7b\xdb\x9a\xeb\xed\x40\x76\x2d\xcf\xdb\x83\xf3\xda\x63\x04\x9f\xc3\xd3\xfa\x50\xff\x3d\xca\xaa\xde\xf6\x47\x7f\xa3\xa5\xaf\xfb\x9a\xa1\x58\x7b\x8c\x64\xc3\x5e\x47\x17\xbc\x93\xb1\x28\x6d\xe5\x57\x39\x54\x90\xc0\xab\x3d\x42\xcf\xc1\xd4\xdf\x22\x8a\x9f\x7f\x19\x50\xef\xaf\x62\x31\x53\x33\xb0\x40\xe8\xb9\x5e\x7d\x58\xe7\xe8\xa8\xf3\xdc\x0d\x7f\x24\xa7\xa6\xfd\x63\x49\xe2\x87\x1f\x07\xfe\x28\x95\x7c\xaa\x9f\x92\xa1\xc1\xff\x00\x88\x21\x0d\x44\x10\x35\x00\x00")
 
 func deployManagedCommonAppsOpenClusterManagementIo_subscriptionreports_crd_v1alpha1YamlBytes() ([]byte, error) {
 	return bindataRead(
@@ -143,12 +143,12 @@ func deployManagedCommonAppsOpenClusterManagementIo_subscriptionreports_crd_v1al
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "deploy/managed-common/apps.open-cluster-management.io_subscriptionreports_crd_v1alpha1.yaml", size: 8781, mode: os.FileMode(436), modTime: time.Unix(1646042069, 0)}
+	info := bindataFileInfo{name: "deploy/managed-common/apps.open-cluster-management.io_subscriptionreports_crd_v1alpha1.yaml", size: 13584, mode: os.FileMode(436), modTime: time.Unix(1792027268, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _deployManagedCommonAppsOpenClusterManagementIo_subscriptions_crd_v1Yaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x3c\x7f\x6f\xe3\x36\x96\xff\xcf\xa7\x78\x48\x0f\x98\x0e\xd6\x96\x3b\xed\x2d\xee\x2e\xb8\xbb\x45\x3a\x3f\x76\x73\x3b\x9b\x19\x4c\xd2\xed\x01\x9d\x62\x40\x4b\xcf\x36\x1b\x89\xd4\x92\x54\x1c\xb7\xd7\xef\x7e\x78\x8f\x94\x2c\xdb\xa2\xa4\x38\x93\x76\x16\x08\xff\x49\x2c\x91\x8f\x8f\x8f\xef\x37\xf9\x24\x4a\xf9\x77\x34\x56\x6a\x75\x0a\xa2\x94\x78\xeb\x50\xd1\x2f\x9b\x5c\xff\xbb\x4d\xa4\x9e\xdd\x3c\x7f\x72\x2d\x55\x76\x0a\x2f\x2a\xeb\x74\xf1\x1e\xad\xae\x4c\x8a\x2f\x71\x21\x95\x74\x52\xab\x27\x05\x3a\x91\x09\x27\x4e\x9f\x00\x28\x51\xe0\x29\xd8\x6a\x6e\x53\x23\x4b\xc7\x80\x44\x59\xda\x44\x97\xa8\xa6\x69\x5e\x59\x87\x66\x5a\x08\x25\x96\x58\xa0\x72\x89\xd4\x4f\x6c\x89\x29\x8d\x5d\x1a\x5d\x95\x84\x45\x7f\x77\x3f\x89\xa5\x11\x00\x1e\xb5\xcb\xd6\x7c\xfc\x38\x97\xd6\xfd\xf5\xe0\xd5\x1b\x69\x1d\xbf\x2e\xf3\xca\x88\x7c\x0f\x4f\x7e\x63\x57\xda\xb8\x8b\x2d\xfc\x29\xa3\x53\xcd\xfd\x4b\xa9\x96\x55\x2e\xcc\xee\xc0\x27\x00\x36\xd5\x25\x9e\x02\x8f\x2b\x45\x8a\xd9\x13\x80\x1b\x4f\x55\x86\x33\x05\x91\x65\x4c\x2c\x91\xbf\x33\x52\x39\x34\x2f\x74\x5e\x15\xaa\x99\x25\xc3\x06\xde\x2e\x74\xb0\x4e\xb8\xca\x23\x07\xf0\x93\xd5\xea\x9d\x70\xab\x53\x48\xfc\xf3\xa4\x5c\x09\x8b\xe1\xad\x27\xfe\x65\x7b\x80\xdb\x10\x62\xd6\x19\xa9\x96\x61\xaa\x16\x8c\x7a\xe7\x92\xd4\xa0\xa0\xd9\xae\x64\x81\xd6\x89\xa2\xdc\x81\x78\xb6\xc4\x1d\x70\x99\x70\x78\x08\x8c\xb6\x31\x29\x73\x91\xfa\x9d\xca\x75\x2a\xf2\x1d\x30\x6f\xe8\x09\x34\x3d\x76\x40\xce\xb5\xce\x51\xa8\x08\x54\x27\x0b\x5c\x4b\x95\xe9\x75\xe2\xff\xd0\xa0\x1d\xd8\x84\x38\xf8\x77\xb1\x95\xfb\x8e\x37\xcf\xfd\x56\xa6\x2b\x2c\xc4\x69\xe8\x4b\xdc\x76\xf6\xee\xfc\xef\xdf\x5c\xee\x3c\x86\xdd\x6d\x69\xb3\x12\x48\x0b\x6e\x85\xe0\x07\xc0\x42\x1b\xfe\xb9\xc3\x50\x70\xf6\xee\xbc\x81\x54\x1a\x5d\xa2\x71\xb2\x66\x2c\xdf\x5a\xd2\xd7\x7a\xba\x37\xef\x53\x42\xcd\xf7\x82\x8c\xc4\x0e\xfd\xdc\x81\xc3\x30\x0b\xab\x01\xbd\x00\xb7\x92\x16\x0c\x96\x06\x2d\x2a\x27\x1a\x81\xd8\x36\xbd\x00\xa1\x40\xcf\x7f\xc2\xd4\x25\x70\x89\x86\xc0\x10\xdf\x57\x79\x06\xa9\x56\x37\x68\x1c\x18\x4c\xf5\x52\xc9\x9f\x1b\xd8\x16\x9c\xe6\x49\x73\xe1\xd0\xba\x3d\x98\xcc\xd1\x4a\xe4\x70\x23\xf2\x0a\x27\x20\x54\x06\x85\xd8\x80\x41\x9a\x05\x2a\xd5\x82\xc7\x5d\x6c\x02\x7f\xd3\x06\x41\xaa\x85\x3e\x85\x95\x73\xa5\x3d\x9d\xcd\x96\xd2\xd5\x5a\x27\xd5\x45\x51\x29\xe9\x36\xb3\x54\x2b\x67\xe4\xbc\x72\xda\xd8\x59\x86\x37\x98\xcf\xac\x5c\x4e\x85\x49\x57\xd2\x61\xea\x2a\x83\x33\x51\xca\x29\xa3\xae\xbc\xc6\x29\xb2\x2f\x4c\xd0\x53\xf6\xe9\x0e\xae\x07\x5c\xe1\x1b\xab\x91\x9e\x1d\x20\x5d\x42\x5b\x2e\xc2\x50\xbf\x8a\x2d\xa1\xe9\x11\x51\xe7\xfd\xab\xcb\x2b\xa8\xa7\xe6\xcd\xd8\xa7\x3e\xd3\x7d\x3b\xd0\x6e\xb7\x80\x08\x26\xd5\x02\x8d\xdf\xc4\x85\xd1\x05\xc3\x44\x95\x95\x5a\x2a\xc7\x3f\xd2\x5c\x6e\x45\xa7\x6e\xb6\x9a\x17\xd2\xd1\xbe\xff\xa3\x42\xeb\x68\xaf\x12\x78\x21\x94\xd2\x0e\xe6\x08\x55\x49\x02\x9b\x25\x70\xae\xe0\x85\x28\x30\x7f\x21\x2c\x3e\xf8\x06\x10\xa5\xed\x94\x08\x3b\x6e\x0b\xda\x56\x64\xbf\xb3\xa7\x5a\xeb\x45\x6d\x32\x22\xfb\xd5\x96\xd4\xcb\x12\xd3\x1d\xb1\xc9\xd0\x4a\x43\x8c\xed\x84\x43\x12\x87\x03\xeb\x51\xb7\x6e\x99\xa5\x96\xae\x84\x52\x98\xef\x3f\x8e\x2e\x8e\x71\xc6\x54\xab\x4c\x98\xcd\x8b\x23\x06\xaf\xb4\xbe\xb6\x98\x1a\x74\x06\x17\x87\x23\x77\xb9\xf5\x2d\x93\xeb\x3d\x2e\xd0\xa0\x4a\x91\xa4\xda\x09\xa9\x2c\xa0\xd2\xd5\x72\xc5\x9b\x6e\x0a\x56\x0e\x24\xd6\x39\x3a\xd8\xe8\xea\x00\x28\xc9\x35\x11\xda\x81\x36\x50\xe8\x4c\x2e\x36\x4c\x40\x43\x80\x89\x82\xb5\x12\x99\x4e\xa7\x70\x81\x6b\xa8\x2c\xda\x46\x09\xb5\x54\x74\xbb\x09\x83\x90\x49\x9b\xea\xca\x88\x25\x66\x30\xc7\x54\x54\x96\xf7\x21\x93\x8b\x85\x4c\xab\xdc\x6d\xc2\x7a\xe6\x24\x56\xc4\xd8\x95\x15\x4b\x84\xf5\x0a\xf7\x95\x19\x35\x2c\xe6\x98\x65\x98\x81\x54\xa4\x71\x6d\x02\xf0\x3c\x81\xf3\xa5\xd2\x84\xe3\x42\x62\x9e\xd1\xb3\x73\x07\x52\xa5\x79\x95\x21\x89\x9a\xda\x84\x37\xb0\x5e\xc9\x74\x15\x41\x94\x04\x68\x89\x0a\x8d\xc8\xf3\x0d\xac\x34\x83\x4c\x00\x5e\x6b\x43\xb4\x71\x42\xa5\x38\x81\xda\x25\xaa\x75\x34\x69\xbf\xd7\x04\x9c\x4c\x58\x04\xf2\x5c\xbb\x15\x29\xf0\x0d\x18\x61\x30\xdf\x90\x42\x91\xbc\x04\x91\xba\x4a\xe4\x7e\xc9\x09\xc0\xd7\x24\xb6\xfe\xa5\xa7\xc2\x0a\xf3\x92\x97\xd3\xb5\x5f\x16\x64\x51\x6a\x6b\xe5\x3c\x47\xda\x5a\x91\x65\x2c\x2b\x72\x21\x53\x1e\xc9\x96\x4a\xaa\x4c\xde\xc8\xac\x3d\xcd\xb9\x82\x42\x1f\xe8\xf5\x1d\xf2\x72\x57\x3b\x21\x16\x30\xc8\x8b\x28\x85\x71\xb4\x61\xc2\x70\x4f\x83\xc4\xba\xa9\xb7\x7d\xb9\xbc\xc6\x09\x9c\x14\x55\x27\x50\x66\x21\xd0\x2a\xdf\xb0\x5d\x21\x55\x01\x67\x4c\xb8\x6f\x4f\x88\xdb\x4e\xbe\x3b\x7f\xc9\xd4\x0f\x34\xf7\x0f\xc9\x82\x43\x04\xe2\x1c\x9b\xf9\x31\x3b\x49\xf8\xd9\xd5\x4a\x5b\x84\xb4\x51\x84\x6b\xcc\xf3\x9a\xb5\x30\x63\x7e\x6a\x96\x97\x00\x7c\x93\x74\xc0\x3d\x57\xa9\x56\x56\x5a\x87\xca\xf9\x4d\x62\xb9\x49\x00\xbe\x0d\x9c\x4b\x22\xe1\x69\x13\x98\x7b\xc1\x72\xe7\x98\x52\x1d\x10\xb7\x40\xc0\x54\xf9\xfe\x28\x98\x6f\x3c\xb4\x89\xe7\x4c\x28\xc4\x35\x5a\x90\x0e\x56\xc2\x64\xb4\x7d\x1d\x20\x2b\x4b\x06\xc4\x69\x28\x0d\x66\x32\x75\xb0\x5e\x09\x07\x6b\x99\xe7\xb0\x12\x65\x89\x84\xee\xbf\x26\x70\xb5\xc2\x9a\xeb\x1b\x1e\x94\x45\x69\x30\x95\xb6\x53\x56\x55\x06\xfa\x06\x4d\xbe\x81\xd0\x29\x01\xa8\x4d\x21\xd1\x54\xd4\xcf\xa1\x10\x65\xc9\x46\x50\x83\x80\xef\xde\xbf\xa1\xc9\x0e\x8c\x1f\xb5\x54\x28\xd2\xab\x59\x95\x22\x88\x62\x2e\x97\x95\x74\x1b\x7e\x93\x55\x6c\x59\xd9\x97\x28\x0d\x7a\xe7\x85\x71\x20\xbb\x26\x89\xe7\xd8\xbe\x76\x00\x0d\xb3\x6f\xf9\x18\x52\x61\x03\xaf\x42\x86\x25\xaa\x0c\x55\xba\x21\xb4\x49\xe5\xad\xd0\xc7\x1a\x93\xda\x52\x77\x80\x74\x55\x99\x63\x43\x85\x96\xbb\xe5\x15\x1c\xd6\x72\x6a\x9d\xa9\x52\xc7\x92\x67\x0c\xe6\x78\x23\x94\x4b\x00\xfe\xd8\xc5\x4b\xdf\x37\xcc\x88\xc2\xca\x7c\xc3\x66\x64\x89\x20\xdd\x0e\x3b\x05\xe5\x49\x30\xdb\xba\x8d\x94\x56\x07\x50\xf2\xb3\x59\xe4\x26\xc1\xd0\x07\x57\xad\x86\x42\x8d\x39\x41\x2c\x16\xa4\xcb\x55\x55\xa0\xd1\x95\xad\x1d\xbb\x04\xe0\xa5\x56\x4f\x9f\x76\x09\x15\xf1\x1e\x28\x5c\xb3\x5e\xf5\xc8\x90\xf3\x58\xa9\x0c\x4d\x50\x2b\x98\xd1\x4b\x3f\x95\x5b\xe1\x06\x32\xcd\xac\xc1\x5e\x83\xce\xbb\x45\xca\x3a\x14\x19\x11\xb2\xb2\xde\x73\x0a\xc8\x4e\x80\x03\x11\xda\x69\x0e\x1f\x98\xf1\xf4\x8d\xcc\x78\x5e\x52\x41\x98\xc5\x0c\x8b\x23\x96\x97\x96\x85\x7c\xba\xd0\x29\xf7\xd5\x8a\x2c\x9b\xf1\xfa\x86\x6c\x61\xc2\xba\x1b\x6f\x45\x51\xe6\x38\x61\xdf\x4b\xa6\xd8\x98\xca\x2e\x8e\x25\x8d\x29\xb2\x42\x5a\xde\x7d\x83\x4b\x69\x9d\x11\xde\xd4\xb6\x1c\xa7\x55\x35\x4f\x52\x5d\xcc\xae\xab\x39\x1a\x85\x0e\x2d\x79\x45\xb3\x79\xae\xe7\x33\x62\x0c\x61\x71\xfa\x3c\x79\xfe\x6f\xb3\x06\x56\x1b\xd4\xec\xe6\xf9\x8c\xd5\x60\xb2\xd4\x5f\xbc\xf9\xe3\x37\xdf\x74\x20\x92\x3c\x3d\x78\x18\xf7\x50\xa0\x27\xba\xa8\xdb\x8e\xd7\x40\xbb\xb8\xc7\xe2\x81\x6a\xae\x6b\x13\x7b\xbd\x15\x26\x5b\x6d\x01\x47\xcc\xfd\xf4\x7c\x11\xbc\x8a\x46\x87\x94\x12\x53\xdc\x09\x56\xd8\xe2\x7a\xbe\xe9\x84\x48\x92\x0a\xe4\x80\x1a\x0c\x23\x26\x9e\xb3\x82\xcb\xbe\x0d\x71\xc8\x19\x02\x11\x4c\xee\xff\x5c\xbe\xbd\x98\xfd\x59\x47\x40\xf2\x2a\x40\xa4\x29\x5a\xeb\x3d\xc6\x82\x55\xbb\xad\xd2\x15\x08\x5b\x3b\x93\x14\x73\x63\x52\x08\x25\x17\x68\x5d\x12\xe6\x40\x63\x7f\xf8\xfa\xc7\x6e\xea\xc1\x2e\x23\x4a\x4f\xf1\x26\x3c\xa8\x5d\x37\x69\x3d\x39\x1a\x88\xb0\x96\x6e\x25\xbb\xfc\x20\xa6\x00\x94\x3a\x0b\xcb\x5e\xf3\x72\x1d\x89\xb0\x0e\xcb\xad\x90\xed\xf2\x29\x9c\x70\x58\xbd\x45\xf3\x17\x32\xad\xbf\x9e\x44\xa0\x7e\xb9\x66\x93\xcf\xf6\xf7\xc4\x23\xd7\xc4\x83\x6c\x93\x03\xbf\x6c\x91\x64\x61\x74\x46\x2e\x97\x68\x38\x1b\xd2\xd5\x38\xb8\xa1\x90\xe1\x19\x59\x77\xb9\x00\xa5\x5b\x20\x18\x30\xed\x5e\xa3\x67\xf6\x91\xfe\xe1\xeb\x1f\xa3\x18\xef\xd2\x8b\x3c\x1e\xbc\x85\xaf\x49\x8d\x32\x6d\x4a\x9d\x3d\xf3\x26\x0a\xec\x46\x39\x71\x4b\x33\xa5\xe4\x2e\xc4\x28\x5b\xfb\x2a\x2b\x71\x83\x60\x75\xe1\xbd\x89\xa9\x0f\x2c\x32\x58\x8b\x0d\x51\xa1\xde\x38\xe2\x37\xc1\xfe\x51\x2f\xb7\xd6\x0e\xf4\xd5\xdb\x97\x6f\x4f\x3d\x66\xc4\x50\x4b\x55\x1b\xd8\x85\xa4\xa8\xda\x5b\x20\x8a\x09\x99\x1b\x3b\xed\x2a\xf8\x38\x90\xd9\xc7\xe9\xc6\xb2\x78\x6b\xb7\xa8\x28\x4a\xeb\xd0\x1f\x30\x2c\xc7\x87\xa1\x71\xdd\x3a\x42\xe4\x7d\xc5\xf1\xbb\x05\x99\x23\x17\xc7\x39\xa1\x11\x8b\xbb\x68\x71\x79\xef\xe2\xb6\xda\x9f\xd6\x97\xe9\xd4\xd2\xd2\x52\x2c\x9d\x9d\x91\x2b\x75\x23\x71\x3d\x5b\x6b\x73\x2d\xd5\x72\x4a\xac\x39\xf5\x3c\x60\x67\x9c\xd4\x9c\x7d\xc1\x7f\x8e\x5e\x0b\x67\x1f\xc7\x2e\x88\x3b\xff\x16\xab\xa2\x79\xec\xec\xa8\x45\x99\xdd\xd8\x6a\xcc\xd2\x2e\xeb\x78\x67\x6f\x2c\x89\x85\x77\xa9\x43\x92\x2c\xe8\xd8\x88\x30\x49\x0a\x13\x33\xaf\x9a\x85\xda\x3c\x38\x2b\x13\x41\x2b\x43\x18\x6d\xa6\xc1\x79\x9a\x0a\x95\x4d\x9b\xf0\x23\xdd\x1c\x45\xc1\x4a\x8e\x12\x5f\x0a\xb8\x7e\x13\x06\xaf\xe4\x51\xb2\x1a\x49\x04\x41\x54\x88\x77\x96\x77\xa5\x83\x1d\xd9\xc0\x73\x28\x45\x7a\x2d\xbc\x72\x0c\x79\x9c\xbb\x64\x62\x68\x91\x46\x66\x5d\x1e\xd7\xce\x94\xe4\x36\xae\xaa\x39\x70\x72\x23\x18\x8f\x1a\x07\x36\xf5\x35\x1c\x1f\x87\x8a\xb2\xcc\xbb\xdc\x7b\xd2\xe5\xfe\x18\xe4\x50\xeb\x4b\x87\x45\xa7\xe3\xb7\x83\xc8\xdb\x66\xa2\x60\x3e\x14\x85\x44\xb9\xde\x88\x79\xde\xc5\xfc\xfd\x3e\x25\xd4\xe8\x5c\x44\x55\xe7\x20\x4b\x36\x30\xde\xc6\x69\x39\xb0\xc2\xf6\x3c\x9d\x4c\xb1\x6d\xb7\xd3\x2d\xcf\x4e\x39\xed\x6a\x6e\x70\x5a\xa9\x6b\xa5\xd7\x6a\xea\xe3\xe1\x53\x70\xa6\x8a\x69\x82\x42\xaa\x73\xc6\x03\x9e\xf7\xae\x57\x18\x23\xba\xb6\x30\x84\xaf\x9d\x62\x38\x6d\x93\xb3\xef\x7d\x43\xaa\x2e\x1e\xe9\x23\x43\x1c\xb7\x20\x07\xaf\x65\xee\xd0\x8c\x17\xa0\x82\x94\x82\x5b\x09\x35\x4e\x94\x06\x42\x14\x0a\x87\x7d\xfc\xda\xbd\xcd\xed\x83\xb3\x7e\xbe\x1c\xc1\x76\x03\xfc\xb2\x60\x4a\xbc\xef\x4a\xb0\x1e\x10\x84\x0f\xb3\xee\x90\x68\x8d\xa1\xdc\xa4\x5f\x7d\x20\x8f\x6d\x0d\x9c\x36\x39\x56\x8a\x7d\x64\xe6\x5f\x5a\xf2\x45\x1a\xbb\xda\x1d\x5d\x0c\xc9\x70\x9f\xe7\x73\xb0\xd4\xdf\xda\xfb\x89\x62\x05\xc1\x51\x3e\xcb\x32\xd0\x6e\x85\x86\x94\xeb\xa2\xca\x9b\x24\xef\x36\xe0\x9d\xb0\xdf\x3a\x21\xeb\xf7\xa7\x6e\x6b\x03\x9f\x80\x61\x72\x31\xc7\xfc\x12\x73\x4c\x9d\xee\x90\xa0\x03\x4a\x9e\xf9\x11\x60\xc3\x10\x7f\xa4\xe4\x9f\xfd\xa3\x42\xb3\x61\xab\x00\x02\x2c\x3a\x1f\x4e\x84\x43\xac\x58\x0c\x79\xc5\x3b\x62\xab\x9c\xbb\x17\xc2\xa5\xab\x37\x04\xcd\x86\x23\x38\x97\xae\x5e\xdd\x92\xce\xe3\xa3\x68\xce\xfa\x9d\x5d\xbc\xc4\x2c\x81\xb3\x18\x47\x62\x51\xba\xcd\x3e\x9e\x0c\x09\x2d\x88\x3c\x0f\xd4\xb0\x09\x9c\x81\xaa\xf2\x7c\xaf\x6b\x4c\x87\x06\x00\x4a\x37\xe3\x8f\x64\xdc\xfd\x45\x8d\x64\xe2\x03\x5a\x78\xd2\x4b\xcb\x94\x1b\xb5\x06\xd8\xea\xf2\xc2\x1f\xdd\x79\xf2\x6f\x9f\xb4\x08\x1c\x85\x31\x60\xd2\x86\x38\xa6\x35\x5d\x38\x90\x1c\x46\x3a\x24\xc7\x1a\xed\xe4\x8f\x60\x27\x20\xe0\x1a\x37\xfe\xb4\x96\x82\xd1\x12\x8d\x70\x3a\x04\xef\x06\xf9\xa4\x77\x00\x2a\x12\x04\x06\x10\x8e\x75\x7b\xfa\x0f\x6f\xad\x6f\xd7\xb8\xe9\xef\xb0\x47\x22\xc2\x20\x9c\xc6\x7b\x5a\xd1\x03\x9f\x80\x20\x6d\x39\x86\x3c\xc0\xc9\xb2\x32\x97\x9c\x3c\xed\x5b\x04\x8c\xd1\x1a\x75\xab\x29\x7a\xa7\xe5\x34\xdb\xb0\x3d\x23\xf6\x1b\xf5\xd4\xfa\x4d\x21\xee\x5d\xc9\x72\x70\x41\x9c\x37\x0a\x8a\xa4\x3e\x74\xff\x3b\xa7\xbc\xea\x29\x3c\xbf\x9e\xab\x09\x5c\x68\x77\xae\x26\x83\x20\x5f\xdd\x4a\xeb\xbc\x6e\x79\xa9\xd1\x5e\x68\xc7\x4f\x3e\x19\xc1\x3c\x9a\x77\x22\x97\x1f\xc2\xa2\xa0\xbc\x97\x43\xeb\x6d\x9f\xd2\xdb\x04\xce\x63\x39\x98\x16\x8e\xe4\x92\xd7\xa4\x97\x16\xce\x15\x68\x13\xe8\xe2\xd3\xff\x7e\x22\x3f\x45\xe4\x10\x6a\xb7\xcd\x11\x94\x56\x53\x56\xa8\x84\xc3\xc1\x1c\x81\x9c\xda\xec\x50\x73\x78\x1b\x3a\xd1\xa1\xe9\xc2\x54\x9c\xd7\xf2\x6f\xfc\x6d\x90\x3c\xdc\x49\x1a\xa0\xab\x3f\x80\xe1\x3b\x0e\xc2\xe1\x52\xa6\x50\xa0\x59\x22\x94\xa4\x3b\x87\x36\x79\x50\xaf\x05\xdc\xc7\xf2\xc2\x90\x57\x5d\xb7\x3e\xef\xba\x6e\x53\x92\x9f\xde\xf7\xf5\xb6\xf4\x74\x1a\x15\x6c\x0c\xe3\xdc\x32\xd2\x71\x94\xef\xe2\xf5\xc2\x58\xaa\x1e\xda\xc3\xe0\x2b\xb0\x1d\x29\x44\x49\x92\xf3\x0b\x99\x04\x66\xae\x5f\xa1\x14\xd2\x90\x9d\xef\x99\xd8\x4a\xb5\xcc\x71\x67\x54\xc8\x39\xb6\x27\x20\xd8\xd2\x02\xed\xd4\x8d\xc8\x0f\x2f\xb0\xec\x2c\x45\x73\x1e\x3f\xf7\x26\xae\xf6\x6a\x5a\x96\x7b\x02\x6b\x3e\xd7\x25\x35\x5f\x27\x44\xe1\xe4\x1a\x37\x27\x7d\x92\xb3\x2f\x7b\x27\xe7\xea\x64\xb2\x3d\xdb\x6b\x4b\x53\x63\x27\x29\x6c\xef\x01\x79\xc2\xa3\x4e\x8e\x73\x03\x06\xb9\x69\xa0\xc3\x4d\x5f\x42\xac\x14\xce\xa1\x51\xa7\xf0\xe5\x0f\x5f\x4d\xff\xe3\xc7\x3f\x3c\xfb\xf2\xcb\x0f\x49\xfd\x6f\xf3\xdf\xff\x6d\xff\xfd\x13\xfd\x7b\xfb\xbf\x3f\x3e\x7b\xf6\x2f\x9f\x34\x35\x13\xe2\xc3\x9e\x38\x7f\x3f\xca\x0c\xe7\x7d\xb0\xc8\xf1\x56\xce\x65\x2e\x1d\x67\x4e\xea\x6c\xc9\x98\x88\x13\x7c\xce\x9f\x4f\x10\x41\xaa\xb2\x3a\xa4\xdf\xef\x93\x39\x09\xb8\x9f\xe5\x52\x1c\x1f\xc3\x06\x20\xf7\x4a\xbf\x0c\x6f\x8b\x6f\x9f\x45\xfa\xe5\x3e\xc9\x95\x16\xb1\x3e\x5d\xde\x44\xe4\xb9\x5e\x0f\x73\x32\x77\x0b\x0c\x53\xeb\x32\x8a\x37\x30\xdb\xc6\x75\x47\x32\xe6\xa5\xf7\xea\xb6\x84\xf5\xf7\x19\xb6\x70\xfd\xe4\x98\x91\xe8\xcc\x31\x20\xd1\xe9\x02\x0c\xf1\xec\xd0\x19\xf2\x08\x6e\xe3\xe3\x99\x7b\xb1\x58\xaf\x61\xbb\x0f\x7f\x6c\x57\xd7\xf9\x9a\x31\xff\x74\x8c\x93\xa1\xea\x08\x6d\xf6\xf9\x86\x7a\xfd\x5e\x6c\xc3\x97\x7a\x1e\x59\xc7\xb7\xcf\x89\x75\xd6\xe4\x04\xfd\x05\xf3\xa2\x39\xb8\xbb\x4c\x75\x89\x59\x7d\xf7\x70\xc8\xb2\x7e\x3f\x34\x9e\x7c\x22\x7f\xbd\x46\x03\x2a\x32\x70\x7e\x4e\x8a\x08\x9a\x64\xa3\x2f\x74\x00\x82\x43\xd6\x97\x2f\x8a\xc7\x58\xf2\xf0\x5e\xff\xb6\x35\x35\x00\x03\x58\xbf\xde\x3b\x43\x99\xb4\x0f\x51\xfc\x59\x5e\x7d\x38\x42\x6f\x96\x1a\xdc\xe1\x45\x8e\x7e\x36\x0d\xe3\x1f\x93\x78\x8f\x49\xbc\xc7\x24\xde\x63\x12\xef\xb0\x3d\x26\xf1\x02\x26\x8f\x49\xbc\xc7\x24\x5e\x8d\xfb\x63\x12\xef\x31\x89\xf7\x98\xc4\xbb\x77\x12\xaf\x76\x5e\xbb\xb9\xa2\x57\x18\x77\xf8\xe0\xcf\xa8\xd0\xc8\xf4\x85\x07\xb7\xbd\x8f\x30\xe5\x82\xa2\x5c\x2e\x15\xef\x03\xa7\xc5\x28\xfa\x5b\x44\x15\xc9\x18\xfb\xde\x7f\x75\x60\x14\x1f\x0f\xc9\xfb\x94\x27\xe9\x8d\xda\x06\xa9\x1e\x93\x5f\xce\x0b\x76\x4f\xdc\x17\xb3\x40\x3b\x6e\x19\x77\x47\xe4\x88\x42\xbc\xc8\x92\x37\xba\xba\x4f\x31\x5e\x0f\x21\xef\x51\x90\x17\x81\xba\x53\x56\x75\xc7\xa2\xbc\x08\xc8\x76\xa9\xde\xf1\x85\x79\x11\xe0\x3b\xe5\x7a\x77\x2d\xce\x8b\xc0\x8c\x94\xec\x8d\x2c\xd0\x8b\xe5\x3b\xa2\x65\x7b\x47\x16\xe9\x45\xe6\x69\x95\xee\xdd\xbd\x50\x2f\x02\x73\xa7\x7c\xef\x88\x62\xbd\x31\xbc\xc6\x25\x7c\x77\x2a\xd8\x8b\x71\xc4\x41\x19\xdf\xe8\xa2\xbd\x28\x9e\x9d\xa5\x7c\x23\x0b\xf7\x7a\xf2\x06\xd1\x72\xbe\xc1\xe2\xbd\xd8\xda\x07\x4a\xfa\x06\x0b\xf8\xa2\xcc\x3b\x50\xd6\xd7\x5b\xc4\x17\x35\x82\x83\xa5\x7d\xf1\x42\xbe\x18\xa7\x8e\x2b\xef\x8b\x15\xf3\x45\x73\x95\x63\x4b\xfc\x3a\x0a\xfa\x62\xea\xfb\xa8\x32\x3f\xe6\xc2\x08\xc4\x4f\x5e\xea\x07\xf7\x2e\xf7\xeb\x33\x5d\x0f\x56\xf2\x07\x9f\x53\xd9\x1f\x74\x97\xfe\xc1\x28\x6f\x6d\x38\x07\x7f\xdf\x32\x40\x18\xe7\xf1\x0d\x94\x03\x1e\xe0\x71\xa7\x92\xc0\x1e\x67\xd4\x77\xbf\x73\x59\x60\x0f\xc4\x50\x30\xf8\x90\xa5\x81\xf0\x40\xe5\x81\xf0\x60\x25\x82\xf0\x70\x65\x82\xf0\xb0\xa5\x82\xf0\x20\xe5\x82\x70\x8f\x92\xc1\x41\x6e\x3e\xaa\x6c\xb0\x07\x2a\x2d\xeb\xa8\xd2\x41\x18\x27\xfb\xf1\x12\x42\xf8\x27\x29\x23\x1c\xb9\xd0\xcf\xf8\x52\xfd\xbd\xd7\xd5\x53\x5a\xd8\xbd\xb8\xcf\xa2\xbc\x70\xe4\x02\x47\x95\x19\x1e\x2e\xf3\x13\x95\x1a\xc2\x3f\x53\xb9\xe1\x48\x8a\x46\xcb\x0e\x0f\xa9\xf8\x19\x94\x1e\x8e\x5a\xd4\x88\xa3\xfb\xce\x97\xdb\xaf\xd7\x0d\x1c\x77\x73\xfc\x4f\x21\x61\xed\x52\xfb\xf8\x76\xff\xc3\x72\xde\xcf\x67\xab\xed\x9d\xfd\x3b\x1e\x79\x67\x62\x63\xf5\x62\x8d\x78\x3d\x22\x87\x45\xdd\x68\x00\xd4\x66\x8b\x3f\xa9\xe2\x4d\x17\xfd\x4b\xef\xc3\xe7\xef\xc8\x19\x91\xd1\xac\x9d\xa7\xc0\x96\x97\x75\x2e\xd4\x32\xd1\x66\x39\x2b\xaf\x97\x33\x1a\x38\xfb\xe2\x7b\x3f\xd9\xdd\xb3\xa1\x23\xf7\x2e\x96\x12\x5c\xe9\xea\xfe\x49\xd8\xbf\xe8\xca\xbc\x67\xd3\x49\x8b\x01\x9f\xd9\x63\xd2\xa0\x20\x4d\xe0\xbf\x50\x98\xe7\x30\x47\xf8\xab\x74\x69\xf7\xd7\xac\x7c\xf3\x83\x27\x0d\xd1\x85\xeb\x27\x5c\x79\xbd\x64\xc9\x75\x42\xb9\x58\x40\x33\x26\xb5\x8b\x7d\x86\x7a\xe4\x09\x85\x75\xc2\x74\x5c\xec\xb8\x13\x94\x4f\x90\xe2\x75\xe3\xca\xc5\x6b\xb2\xa2\x4a\xd6\xf2\x5a\x96\x98\x49\xc1\xc4\xa5\x5f\xb3\x37\xd2\xba\x8f\x7a\xf1\xd1\xfd\xfc\x31\x13\x4e\xcc\x85\xc5\x8f\x44\xf1\x8f\x3f\x6b\x15\x89\x1c\x07\x56\xb7\xfd\x7e\xe5\x98\x04\xb2\x48\x9d\xbc\xc1\x9a\x77\x58\x80\xb4\x21\x17\xcf\x87\x04\x8d\x62\xe1\xd3\x1f\xee\x1b\xcb\x40\x39\xd2\x24\xfe\x32\x97\xe7\x42\xf6\x4e\xeb\xf3\xf2\x70\x6a\xe8\x56\x68\xeb\x89\x2c\x5c\x68\xc7\xf6\xa8\x27\x27\xbd\x16\x8a\x7d\x46\x9f\x8b\x65\xe5\x94\x9a\xcc\x3b\xd1\xf5\x41\xcd\xd4\x66\xd7\x70\xf3\x55\xf2\xfc\xab\xe4\xab\x89\xc7\x23\x9e\xd1\x59\xe8\x3c\xd7\x6b\xc2\x25\x97\x0a\xeb\xe0\x6c\x8e\xa7\xf0\x9f\x7f\x20\xfd\x3f\xaf\x64\x9e\xa1\x39\xdd\xe6\xe3\x4e\x5f\xa9\xaa\xf8\xaf\xb0\xf8\x79\xae\xd3\x6b\xcc\x26\x67\xfe\xe7\xb7\xfe\xe7\x7f\x77\x2b\x7d\x54\x55\xd1\xbd\x09\xd3\x40\xcc\xc8\xcb\x30\x4b\xe4\xed\x59\xdf\xd0\x6f\x7b\x86\x1e\x77\xc9\xba\xfb\x28\x65\xda\x79\x3b\x3a\xf6\x35\x45\xfe\x62\x6c\xcf\xf7\x14\x4f\x76\x3e\xa8\xc8\xbd\x77\x3e\xa9\xa8\xe7\x7c\xab\x77\xcc\x37\x15\x01\x5e\xf9\xa0\xd6\xc2\x34\x4c\xcc\x51\xcd\xae\x85\xd3\x8a\xef\x7d\xf9\xa9\x4e\xe1\x83\xe3\xcf\xdc\x9e\xc2\x3b\xa3\x4b\xb1\x14\xee\x80\x82\x1f\x9c\x87\x85\xdc\x1b\x60\x2d\xec\x2a\x4b\xe9\xff\x0f\x2e\x5c\x02\xb6\xfe\x17\x80\x5a\x4a\x75\xeb\x7f\x34\x80\x03\xbe\x87\x99\x66\x3f\xa4\xd0\x6a\xa9\xb3\xf9\xde\xa0\xd7\x42\xe6\x98\x85\x67\xef\x51\x58\xa2\xd5\x87\x13\xbe\x44\x59\xb9\x95\x36\xf2\x67\xcc\x3e\xec\x07\x9e\xbe\xfb\xdf\xd0\x5a\xb1\x44\xea\xcf\x16\xff\xf6\xf6\x16\x32\x1d\xae\x60\x72\xc4\x58\xa2\xa9\xb3\x4f\x4e\x7b\xad\x4a\x81\xe8\x87\x93\x00\xa1\xf6\x39\x2f\x3b\x76\x0f\xe0\x97\x5f\x79\xc3\xb5\xd1\xca\xe9\x63\xe8\xc0\xcf\xf7\x51\x8f\x10\xa2\x35\xea\xb2\x67\x4b\xfd\x77\x9c\xf7\x29\x1c\x0e\x41\x5b\x5a\x89\x97\xff\xbc\x79\xd1\x9c\x45\x97\xc9\x2e\x2d\xe3\x16\x4d\x28\x3e\x61\xf9\x49\xcf\x3b\x8c\x5d\xbf\x21\xcc\x85\x75\xa5\xb6\x6e\xa5\xf5\xf5\x4f\x7a\xde\x77\x50\x18\x55\xf2\x0c\xc3\xe0\x7d\x40\xb4\x50\xb0\x2b\x69\x9d\x36\x91\x0b\x55\x0f\xe9\x17\x6d\xd7\xf0\x7b\xe1\xd0\xe3\x08\x10\x91\xbf\xe3\x04\xf9\x95\xec\x0a\xbb\xbd\x0f\xe5\xbf\x56\x3d\xed\x74\x4c\x7b\x30\x2b\x82\x78\xde\x65\x8c\x17\x86\x7e\x17\xff\xe9\xf9\xc5\xe5\xab\xf7\x57\x70\xf6\xf2\xe5\xf9\xd5\xf9\xdb\x8b\xb3\x37\x70\x79\x75\x76\xf5\xdd\x25\xbc\x3e\x7f\xf5\xe6\x25\x7f\x09\x9c\xf4\xea\x9e\x4a\xed\xa0\x9b\x5e\x34\x02\x72\x5e\x94\xda\x90\xeb\x77\x0a\xef\x2b\x05\x27\x85\xb8\xc6\x13\x52\x1a\x06\x83\x61\x46\x48\x75\x46\xe1\x02\x75\xf7\xa7\xc7\xdd\xbb\x11\xf2\x45\x39\x1e\x9a\xcc\x9e\x95\x1b\xaf\xfb\xee\x32\xa4\x51\xd7\x07\x83\xc6\x5e\x9b\x89\x7e\xf1\xf7\x1d\x9a\x70\x13\x62\xcf\x54\x05\x73\x43\x2e\xb9\x8d\xdb\x27\xdf\xa4\xf2\x6e\x7b\xa0\xf1\xa4\xbe\xa1\x58\xd7\x1f\x45\xee\x27\x8c\x2c\x01\x8a\xe7\xeb\xef\x74\x67\x28\x4a\x82\xef\x94\x74\xdd\x8b\x67\xc5\x5c\x29\xd9\x97\x65\xfc\x72\x57\x71\x9b\x1a\xeb\x67\xd1\x31\xe3\xae\x85\x0e\x49\x6c\xbb\x0d\x4b\x6f\xbb\x8d\xbc\x90\x16\x95\xea\x23\x60\x45\xa4\xbd\xdd\x76\xb6\xe7\x1d\xf5\xe7\x83\xda\xad\x13\x43\x4e\xbc\xf4\xa7\x8b\xde\xe1\xd1\x26\xee\x89\xec\xb7\xf6\xd8\xfa\x1b\xe1\x9f\x62\x61\x31\x61\x3e\x0a\x54\x9f\x8b\xd2\x05\x72\xe0\x66\x1e\x7c\x8a\x82\xb2\x31\xd7\x0d\xa7\x7b\xcc\x3a\x90\x35\xea\x45\x7b\xa0\x4b\xef\xeb\x83\x72\x88\x7a\xa7\x27\x61\xf3\x39\x39\xd4\x88\x76\x5b\x70\x6b\x95\xd5\xa5\xa5\xbc\x3c\x93\x0e\x9b\xd4\x45\x16\x0c\x50\x2c\x97\x06\x97\xfc\x11\x59\x91\xe7\x01\x70\xa3\xfb\x6a\x7b\xd3\xa9\xfb\xa2\xcb\xe8\x7c\x71\xb8\x03\x53\x3e\xbe\x79\x12\x1d\xe5\xcd\x61\x6b\x63\xc9\x1f\x61\x0f\x7a\xfb\xa4\x9a\x9b\xfd\x7a\x98\x10\xdb\xc0\x2f\xbf\x3e\xf9\xff\x00\x00\x00\xff\xff\x7b\x8d\x6d\x64\xeb\x64\x00\x00")
+var _deployManagedCommonAppsOpenClusterManagementIo_subscriptions_crd_v1Yaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xed\x3d\x69\x73\x1b\x37\x96\xdf\xf5\x2b\xba\x94\xa9\x52\x9c\xe5\x61\xd9\x49\x66\x46\x35\x47\xc9\x96\xed\x68\x47\xb1\x55\x92\x9d\xd4\x6e\x92\x4d\x81\x6c\x90\xc4\xa8\x0f\x4e\x1f\x92\x98\x4c\xf6\xb7\xef\x3b\x00\x74\x37\xd9\x07\x9a\x47\x92\xaa\xb5\x2a\x15\x4b\x24\xf0\x80\xf7\xf0\xf0\x2e\x3c\x3c\x88\xa5\xfa\x46\x26\xa9\x8a\xa3\x33\x4f\x2c\x95\x7c\xcc\x64\x84\x7f\xa5\xa3\xbb\x3f\xa5\x23\x15\x8f\xef\x4f\x8f\xee\x54\xe4\x9f\x79\x2f\xf3\x34\x8b\xc3\x1b\x99\xc6\x79\x32\x95\x17\x72\xa6\x22\x95\x41\xcb\xa3\x50\x66\xc2\x17\x99\x38\x3b\xf2\xbc\x48\x84\xf2\xcc\x4b\xf3\x49\x3a\x4d\xd4\x32\x23\x40\x62\xb9\x4c\x47\xf1\x52\x46\xc3\x69\x00\x30\x64\x32\x0c\x45\x24\xe6\x32\x94\x51\x06\x23\x1c\xa5\x4b\x39\xc5\xbe\xf3\x24\xce\x97\x38\x8b\xf6\xe6\x3c\x48\x8a\x3d\x3c\x8f\xa7\x76\x5b\x1a\x8f\x3e\x0e\x54\x9a\xfd\x63\xe3\xab\x2b\xf8\x94\xbe\x5e\x06\x79\x22\x82\xb5\x79\xd2\x37\xe9\x22\x4e\xb2\xb7\x05\xfc\x21\x4d\x27\x9f\xf0\x97\x2a\x9a\xe7\x81\x48\xaa\x1d\xe1\xab\x74\x0a\xf3\x3d\xf3\xa8\xdf\x52\x4c\xa5\x0f\x9f\xdd\x33\x55\x09\x0e\x40\xf1\x7d\x22\x96\x08\xae\x13\x15\x01\x52\x2f\xe3\x20\x0f\x23\x3b\x8a\x2f\x2d\xbc\x2a\x74\x2f\xcd\x44\x96\xf3\xe4\x3c\xef\x9f\x69\x1c\x5d\x8b\x6c\x71\xe6\x8d\xf8\xf3\xd1\x72\x21\x52\xa9\xbf\x65\xe2\xdf\x96\x3b\x64\x2b\x9c\x58\x9a\xc1\xa0\x73\x3d\x54\x09\x86\x59\xb9\xd1\x34\x91\x02\x47\x7b\xaf\x00\x83\x4c\x84\xcb\x0a\xc4\xf3\xb9\xac\x80\x83\x2e\x72\x13\x18\x2e\xe3\x68\x19\x00\xfa\xb4\x52\x41\x3c\x15\x41\x05\xcc\x15\x7e\xe2\xd9\x16\x15\x90\x93\x38\x0e\xa4\x88\x1a\xa0\x66\x30\xad\x07\x58\xce\xf8\x61\xc4\xff\x60\xa7\x0a\x6c\x9c\xb8\xc7\xdf\x35\x61\xce\x0d\x81\x9d\x69\x29\xa7\x0b\x19\x8a\x33\xdd\x16\xb9\xed\xfc\xfa\xf2\x9b\xe7\xb7\x95\x8f\xbd\xea\xb2\x94\x59\xc9\x53\xa9\x97\x2d\xa4\xc7\x1d\xbc\x59\x9c\xd0\x9f\x15\x86\xf2\x00\xa4\x85\xb4\x4c\x60\x90\x24\x53\x86\xb1\xf8\x47\x14\xbb\xaf\xf4\xe9\xda\xb8\x27\x38\x35\x6e\x05\x5f\xc0\xb6\x93\x3c\xb6\xe6\x30\xe9\x6b\x6c\xbc\x78\x06\x9f\xc3\xc4\x12\xb9\x4c\x64\x0a\x24\x16\x76\x43\x14\x3f\xd0\x48\x44\x5e\x3c\xf9\xa7\x9c\x66\x23\xef\x56\x26\x08\x06\xf9\x3e\x0f\x7c\x6f\x1a\x47\xf0\x67\x06\x10\xa6\xf1\x3c\x52\x3f\x59\xd8\x30\x62\x4c\x83\x06\xb0\xf6\x69\xb6\x06\x93\x38\x1a\x78\xdb\xbb\x17\x41\x2e\x07\x30\x80\xef\x85\x62\x05\x60\x70\x14\x2f\x8f\x4a\xf0\xa8\x49\x3a\xf2\xbe\x8e\x13\x09\x1d\x67\xf1\x99\xb7\xc8\xb2\x65\x7a\x36\x1e\xcf\x55\x66\xa4\xce\x34\x0e\xc3\x1c\xe4\xcb\x0a\x7e\x8b\x60\x0d\x27\x79\x16\x27\xe9\xd8\x97\xf7\x32\x18\xa7\x6a\x3e\x14\xc9\x74\xa1\x32\x80\x9e\x27\x72\x0c\x64\x1c\xd2\xd4\x23\x96\x38\xa1\xff\x49\xa2\xe5\x54\x7a\x52\x99\xeb\x06\x57\xf0\x0f\x89\x91\x96\x15\x40\x59\x82\x4b\x2e\x74\x57\xc6\xa2\x20\x34\x7e\x84\xd4\xb9\x79\x75\xfb\xde\x33\x43\xd3\x62\xac\x53\x9f\xe8\x5e\x74\x4c\x8b\x25\x40\x82\x01\x3d\x64\xc2\x8b\x38\x4b\xe2\x90\x60\xca\xc8\x5f\xc6\x40\x61\xfa\x63\x1a\xa8\x62\xeb\x98\x1f\xe0\xba\x50\x65\xb8\xee\xff\x02\xd2\x66\xb8\x56\x23\xef\xa5\x88\xa2\x38\xf3\x26\xd2\xcb\x97\xb8\x61\xfd\x91\x77\x19\xc1\xa7\xa1\x0c\x5e\x82\xc8\x38\xf8\x02\x20\xa5\xd3\x21\x12\xd6\x6d\x09\xca\x5a\x64\xbd\x31\x53\xad\xf4\x85\x51\x19\x0d\xeb\x55\xde\xa9\xb7\xd0\xb4\xb2\x6d\xa0\xa5\x4a\x90\xb1\x61\x7b\x48\xdc\x0e\x1b\xda\xa3\x7d\xcf\xe2\xcf\x74\x01\xd4\x95\xc1\xfa\xc7\x8d\xc8\xd1\x9c\x61\x0f\x44\xbe\x48\x56\x2f\xb7\xe8\xbc\x88\xe3\x3b\x00\x90\xc8\x2c\x91\xb3\xcd\x9e\x55\x6e\x7d\x47\xe4\xba\x91\xc0\x4b\x32\x02\x3e\xc4\x15\x14\x0a\x04\x92\x8c\xe2\x7c\xbe\xa0\x45\x4f\x42\x12\x0e\xb8\xad\x03\x99\x79\xab\x38\xdf\x00\x8a\xfb\x1a\x09\x9d\x79\x20\xdd\xc2\xd8\x57\xb3\x15\x11\x30\x41\xc0\x48\x41\x23\x44\x86\xc3\xa1\xf7\x56\x3e\x78\x79\x0a\x24\x36\x42\xa8\x24\xa2\xcb\x3f\x02\x98\xce\x57\xa0\x30\x41\x03\xcf\x01\xc6\x44\x4e\x05\xf4\xc3\x6e\x30\xc0\x4c\x4d\xf3\x20\x5b\x69\x7c\x26\xb8\xad\x90\xb1\xf3\x14\xda\x7a\x0f\x0b\x19\xd5\x40\x94\xe1\x44\xfa\x3e\x80\x52\x11\x4a\x5c\xd8\x51\xde\x29\xf0\xfa\x3c\x8a\x71\x8e\x33\x25\x03\x1f\x3f\xbb\xcc\xa0\x01\x58\x14\x00\x1a\xb6\x5a\xb4\xd2\xdf\x00\x54\x35\x5d\x34\x4c\x14\x37\xd0\x5c\x46\x12\x8c\x85\x60\x05\x6b\x40\x20\x01\xd6\x6b\x20\x08\xd0\x26\x13\x40\xdd\x81\x67\x4c\x22\x23\xa3\x51\xfa\xbd\x46\xe0\xa8\xc2\x1a\x20\x4f\xe2\x6c\x81\x02\x1c\x64\x24\xfc\x09\xc0\x41\xa0\x28\x42\x41\xc0\x8e\x02\x49\x4a\x28\xc3\x50\xcf\x70\xdb\xf2\x97\x4c\x85\x85\x0c\x96\x84\x4e\xdd\x7a\xa5\x9e\x0a\x97\x71\x9a\xaa\x49\x20\x71\x69\xc1\xec\xa0\xbd\xa2\x80\xb0\xd4\x93\x34\x15\xec\x49\x75\xaf\xfc\xf2\x30\x20\x1a\xc2\x38\xcd\xda\xc8\x4b\x4d\xd3\x01\xb2\x00\x20\x80\x48\x2c\x05\x6c\x8e\x29\x5a\x44\xd4\x12\x24\x1a\xb0\xee\x94\x75\x5f\xa0\xee\x80\x34\xc7\x61\x5e\x0b\x94\x58\xc8\x8b\x23\x40\x1c\xf5\x0a\x8a\x0a\xef\x9c\x08\xf7\xe2\x18\xb9\xed\xf8\xc3\xe5\x05\x51\x5f\xd3\x9c\x3f\x44\x0d\xee\x35\x40\x9c\x48\x3b\x3e\x34\x1f\xd1\x67\xef\x17\x31\x70\xd6\xd4\x0a\xc2\x07\x19\x04\x86\xb5\x00\x21\xe4\x27\x8b\x1e\xf4\x78\x3e\xaa\x81\x7b\x19\xc1\xee\x49\xc1\x70\x04\xd1\xc6\x8b\x44\xfb\x06\x9a\xbf\xd0\x9c\x8b\x5b\x82\x69\xa3\x99\x7b\x46\xfb\x2e\x23\x4a\xd5\x40\x2c\x80\x78\x49\x1e\xac\xf7\xf2\x26\x2b\x86\x36\x60\xce\x04\x5e\xbd\x83\x36\x0a\x48\x21\x12\x1f\x97\xaf\x06\x24\x4c\x23\x21\x0d\x0d\x5a\xc5\x07\x0a\x40\x57\x01\xff\x53\x80\xee\x02\xec\x57\x89\xd3\xfd\x7c\x04\xf4\x90\x86\xeb\x2d\x0f\x02\xbf\x80\x72\x56\x69\xed\x5e\x85\xf5\x88\x81\x49\x61\x95\x74\x23\x80\x63\x54\x21\xd2\x54\x98\xcf\x61\x96\xcb\x25\x29\x41\xe0\x39\xef\xc3\xcd\x15\x0e\xb6\xa1\xfc\x48\x72\x82\xf1\x01\x72\xd5\xcf\x41\x2e\x89\x70\xa2\xe6\x39\xe8\x18\x96\x61\x39\x69\x56\xb2\x25\x00\x2c\x1b\x2f\x34\x07\xd4\x6b\x0a\x79\x8e\xf4\x6b\x0d\x50\x3d\x7a\xc1\xc7\x30\x4c\xaa\x79\x15\x16\x1c\x08\xe0\x83\x20\x5c\xe1\xb4\x51\xe4\xc1\x87\xe4\x6b\x0c\x8c\xa6\xae\x01\x99\xe5\x4b\xd8\x42\x86\x0a\x25\x73\x8b\x05\x9c\x34\xfb\x14\x58\x2e\x07\x82\xe3\xce\x03\x99\x18\xc8\x7b\x01\xb6\xaf\xe7\x7d\x51\xc7\x4b\xdf\x5a\x66\x94\x22\x55\x40\x55\x54\x23\xb0\xa5\x55\x56\x61\x27\x2d\x3c\x11\x66\x59\xb6\xa1\xd0\xaa\x01\x8a\x76\x36\x6d\xb9\x81\x56\xf4\xda\x54\x33\x50\xf0\x87\x38\x41\x00\x87\xc1\x4c\xa3\x3c\x94\x80\x7c\x6a\x0c\x3b\x18\xfa\x22\x8e\x4e\x4e\xb2\x5a\xba\xde\x81\x10\x04\xc9\x8e\x72\x95\x27\x83\xc6\x63\x0e\xe4\x4c\xb4\x58\x81\x4f\xe0\x4b\x1e\x0a\xc8\x02\xa2\x3b\x26\xd6\x20\xab\x21\x0e\xea\xb7\x14\xec\x26\xe1\x23\x21\xf3\x94\x2d\x27\x3d\xd9\x81\x47\x8e\x08\xae\x34\xb9\x0f\xc4\x78\x31\x88\x2a\x1a\x17\x45\x10\xfc\xd2\xa0\x58\x32\x64\x79\x80\x83\x9b\x7c\x38\x8b\xa7\xd4\x16\x96\x0b\x34\x5b\xc2\xf2\x06\x75\xe1\x88\x64\xb7\x7c\x04\x17\x27\x80\xe1\xd0\xf6\x52\x53\x69\x55\x65\x1d\xc7\xa2\xc4\x14\x7e\xa8\x52\x5a\xfd\x44\xce\x41\x18\x24\x82\x55\x6d\xc9\x70\x5a\xe4\x93\x11\x18\x4d\xe3\xbb\x7c\x02\xb6\xb0\x84\x75\x40\xab\x68\x3c\x09\xe2\xc9\x18\x19\x03\x18\x72\x78\x3a\x3a\xfd\xe3\xd8\xc2\x2a\x83\x02\x37\x7b\x4c\x62\x70\x34\x8f\x3f\xb9\xfa\xe2\xf9\xf3\x9a\x89\x8c\x4e\x36\x3e\x6c\xb6\x50\xda\xbc\x8b\x5a\xab\x01\x57\x71\x8d\xc5\x35\xd5\xb2\x51\x6d\xef\x16\x6b\x85\xc8\x66\x34\xa0\xc3\xd8\x27\x97\x33\x6d\x55\x58\x19\xb2\x54\x72\x2a\x2b\xce\x0a\x69\x5c\xe6\x9b\x5a\x88\xb8\x53\x3d\x34\x40\x41\x52\x70\x8f\x01\x73\x96\x36\xd9\x0b\x17\x07\x8d\x21\x18\x82\xb5\xea\x7f\xde\xbe\x7b\x3b\x7e\x13\x37\x80\x24\x2c\x60\xaf\x03\x6b\xa4\x6c\x31\x86\x24\xda\xd3\x1c\x44\x33\x78\x45\xda\x98\x44\x9f\x5b\x8e\x60\x87\xaa\x19\x28\xa1\x91\x1e\x03\xa8\xf9\xdd\xb3\x1f\x46\x0d\xa0\x2b\x8c\xa8\x98\xe2\xd6\x3d\x30\xa6\x9b\x4a\x99\x1c\x16\x22\xec\x65\x40\x2a\x6a\xa2\x80\xb7\x8c\x7d\x8d\xf6\x03\xa1\x9b\xe1\x16\x8e\x35\xba\xe0\xb2\xa0\x5e\x3e\xf3\x8e\xc9\xad\x2e\xa6\xf9\x33\xaa\xd6\x5f\x8e\x1b\xa0\x7e\xfa\x40\x2a\x9f\xf4\xef\x31\x4f\xce\xfa\x83\xa4\x93\x35\xbf\x14\x93\xa4\xcd\x08\x64\x9f\xcf\xa1\xa3\xdf\x00\x96\x9c\x1b\x74\x19\x9e\xa0\x76\x07\x0a\x44\x71\x09\x04\x01\xc6\xd5\xb3\x72\x66\x7d\xd2\x40\xdb\xc6\x19\x57\xe9\x85\x16\x8f\x7c\xf4\x9e\xa1\x18\x25\xda\x00\x95\x9e\xb0\x8a\xf2\xd2\x15\xb4\x7c\xc4\x91\xa6\x68\x2e\x34\x51\xd6\xd8\x2a\x0b\x71\x0f\x6e\x7e\x1c\xb2\x35\x31\x64\xc7\x02\x6c\x09\x70\xde\xe2\x99\x5d\x38\xe4\x37\x41\xf6\x51\x2b\xb7\x1a\x03\xfa\xfd\xbb\x8b\x77\x67\x3c\x33\x64\xa8\x79\x64\x14\x2c\x00\x07\x1d\xc3\x1a\x08\x7d\x42\xe2\xc6\x5a\xbd\xaa\xfd\x40\x62\x1f\x98\xa6\xd1\x2c\xac\xed\x66\x39\x7a\x69\x35\xf2\xc3\x61\x1f\x6f\xba\xc6\x2d\x2e\xf2\xba\xe0\xf8\xcd\x9c\x4c\x47\xe4\x28\x26\xe4\x80\xdc\xdb\x12\x97\xb7\x22\x57\x48\x7f\xc4\xcf\x8f\xa7\x29\xa2\x36\x95\xcb\x2c\x1d\xa3\x29\x75\xaf\xe4\xc3\xf8\x21\x4e\x60\xca\xf3\x21\xb2\xe6\x90\x79\x20\x1d\x53\x50\x73\xfc\x09\xfd\xb3\x35\x2e\x14\x7d\x74\x45\x88\x1a\xff\x1a\x58\xe1\x38\xe9\x78\x2b\xa4\x92\xaa\x6f\xe5\x82\xda\xad\xf1\x77\xd6\xfa\xe2\xb6\x60\x93\x5a\x07\xc9\xb4\x8c\x6d\xd8\x4c\x0a\xdd\x44\x9f\x45\x33\x58\x5e\x07\x67\x65\x24\x68\x9e\xe0\x8c\x56\x43\x6d\x3c\x0d\x61\xe3\x0f\xad\xfb\x31\x5d\x6d\x45\xc1\x5c\x39\x6d\x5f\x74\xb8\x7e\x15\x06\x87\xf9\x6c\xc3\xdf\x0d\x81\xa0\x72\xe4\xe8\x1a\x8c\x21\x31\x67\xcb\xac\x3d\x42\xf2\x9e\x02\x99\x13\x19\xa4\x24\x5b\xc9\x26\x67\x23\xda\xd0\xa0\x1a\x82\x97\x19\xa9\xd0\x20\xa8\xf3\xb9\x33\x14\xda\xcb\x20\x5e\x49\xdf\xb2\x5c\xda\xd7\x52\x2b\x66\xe0\x62\xaa\x95\xe6\x9b\x82\x69\x39\xcd\x38\xb2\xb5\xd4\x04\x90\x15\x94\x06\xa8\x57\x8b\xbf\x9b\xd9\xbd\xd4\x9d\x94\x71\x86\x88\xd7\x36\x6f\x47\x86\xa2\x06\x8f\x14\x6a\x69\xfa\x7a\x0d\xa1\x57\xdc\x9a\xbc\x52\x44\xe4\x4e\xae\x40\xff\xc1\xd6\x49\x4a\x93\x1a\x90\xb1\x80\x53\xd3\x71\x1c\xbf\x11\x38\xec\xb3\x30\x6d\x1e\xbb\x73\xcf\x94\x1b\x89\x24\x11\xab\x26\xa2\x45\x7d\xb0\xbc\x8c\xaa\x58\x96\xe8\x8d\x08\x0f\x90\xc1\x0a\xf4\x29\xc0\x62\x5b\xb4\xe0\x02\x04\x91\xe1\x32\x5b\xfd\xc6\xd4\x68\xd9\xa0\xe4\xa6\xd2\x76\x73\x60\xee\x2b\xde\x97\x0d\x7c\xcd\x60\x0a\x52\x99\x5d\x9c\x34\x89\xf1\x8f\x4c\xfd\x91\xa9\x0f\xc6\xd4\xa8\xf0\x1e\x12\x98\x8a\x03\x5f\xbf\x33\x6d\x91\x01\x1b\xf8\x7a\x43\x1b\x21\x73\x34\x4c\x9e\xa2\x97\x08\x05\x08\xb1\xa4\x53\xb9\x42\x7f\x6b\x25\x44\x8a\x0b\x3e\x09\x5b\x70\x2b\x1f\xbc\x3a\x62\x5e\x6f\x34\x57\x15\x6c\xac\xfd\xb6\x95\x77\x0a\x6e\xd0\xf4\x4e\xb0\x33\xa2\xcf\x4d\xfa\x9c\x7c\x58\x0b\xf2\xa5\x3e\xa3\xde\x59\xb7\x53\xf8\x05\xe3\x0c\x22\x99\x03\x81\x0a\x13\xb5\x2e\x5c\x49\xf1\x28\xdf\x84\x0d\x39\x13\xc1\xf7\x74\x66\xc2\xbe\xb5\x7c\x39\x4b\xa0\x4b\x18\x75\xf2\x77\xa3\xc9\x80\x9b\x50\x9b\x34\xe4\xb5\x6b\x14\x5b\xe9\xd0\xb9\x15\x7c\x95\x8a\x49\x20\x5d\x6c\xcd\x0b\xdd\xd4\x9b\x09\x15\xa4\x6b\x2c\xcb\x8b\xc2\xfe\xb3\x59\x28\x3b\xb3\x46\x89\x65\x03\x8a\x9c\xc8\xa0\x4f\x63\xdb\xfb\xb5\xf3\x7f\xbb\xc6\x3a\xdc\x42\x5d\x59\x85\xd6\xbe\x46\xa0\x38\x46\xf3\x51\xc3\x11\x03\xb1\x62\xec\x0f\x53\x09\x2e\x05\xc6\xd7\x75\x7a\x02\xf8\x11\x2a\x1e\xaa\x08\xd7\x10\x0d\x5b\x46\x71\x9b\xe5\x5e\x26\x79\xe4\x22\xf5\xae\xb1\x1d\x7c\x14\xc8\x4c\x1f\xbf\x16\x28\x58\xac\x26\xab\x0d\x9b\xbb\x4b\xe8\xa5\x6b\xb9\x18\x3c\x82\xbf\x5f\x41\x87\xe2\x3d\x51\x7e\xdd\xd2\x56\x90\xc4\x08\xf1\x22\x9f\x78\x74\x8e\xa9\xe3\x44\x46\xfc\x51\x54\xcf\xc0\xe1\xf9\x8b\xe5\x32\xa8\x53\x37\x18\xb6\x61\xb9\xb2\xb9\x24\x8d\xea\x6e\x43\xc7\xf0\x40\x3a\x52\x14\x69\xd7\x04\xf7\xdb\x51\x7f\x93\x47\x4f\xe7\x6d\x63\x94\xc4\x81\xbb\x35\x8c\x77\xcd\xb4\x74\x52\xe8\x1d\xfc\xc8\x3f\x8f\xc3\xc2\x3d\x1d\x52\x86\x45\x72\x2f\x87\x79\x74\x17\xc5\x0f\xd1\x90\x8f\xbe\xce\xbc\x2c\xc9\x9b\x36\x4d\xa8\xa2\x4b\x9a\x87\x77\x7a\xb4\x8d\xc5\xa0\x4f\xaa\x6a\xa5\xe0\xb0\x4c\xce\xb6\xef\x2d\xa9\x8e\x7a\x92\xa1\x79\x6e\x86\x03\x6f\xf0\xc0\xb1\x83\x9b\x5f\xaf\x71\x33\x9f\xa2\x19\x25\x5e\xf0\x72\xc9\x7a\xc9\xe2\xa3\xfa\xf0\xae\xe1\x67\x50\x9c\xd9\x74\x61\xe4\x32\x1d\x7b\xa2\xe5\x2b\x82\x9c\x2d\xf3\x08\x24\x94\x5f\xa3\x4f\xfb\xb1\x3d\x62\x57\x71\x1c\xd6\xd4\x35\xe5\xef\xa0\x50\x9d\x19\x74\x6a\x17\xd9\xa2\x38\x40\xb9\x29\xbc\x37\x2a\xf3\x26\x89\x88\x30\xe2\x0f\x16\xc5\x52\x64\x8b\xc1\x9a\xbf\x9c\xc5\x03\x23\xc5\x9a\x4c\x83\x42\xa5\x18\x69\x6c\x33\xe1\x00\x9b\xa9\xa2\x10\x15\x1d\x4b\xda\x70\x3a\x43\x1a\x79\x2f\xf0\xa0\x16\xcf\xbd\x99\x8c\x47\x8d\x92\x91\x4e\x74\xb5\xea\xd8\x7e\xbb\xdf\x12\x05\xe3\xa4\x69\x33\x56\x68\x5f\x72\xc2\x8c\x79\xb5\x46\xf4\x16\xc7\xac\x5d\x71\x7a\x8c\xee\xab\x47\xdc\xc9\x69\xb3\xd5\x54\x33\xab\xf5\x8e\x9c\xbb\x85\x59\xa1\x38\x4b\x9a\xaf\x66\x94\x38\x69\x81\x69\x37\x74\xc8\xa9\x5a\xef\xc9\x54\x29\x3e\x21\x5a\x9f\xbf\xbd\x90\xfe\xa8\xcd\x97\xe9\x72\x55\xd6\x6d\xb5\xb5\x09\x96\x87\xd4\x49\x68\x2e\x53\xd7\x47\xa2\x36\xf9\x87\x13\xef\xc0\x21\x43\x57\x6c\xa0\x8d\x63\x4c\x7d\x4c\x44\x16\xeb\x23\x9b\x44\x92\x27\xd1\x09\x97\xdc\x39\x02\xa1\xd3\xf9\x5a\x7b\xb8\x2c\xb5\x3e\x6c\x90\xab\xae\x26\x6b\xc4\xc2\x79\xe8\x5c\x4c\xa6\x1a\x7e\xc0\xc7\x4f\x0b\xe9\x4a\x28\x3e\x2c\x5d\x06\x8a\x0e\xcf\x47\x9d\xad\x9d\x7c\x4b\x9b\x5a\x4a\xf4\xed\x89\x96\x5d\x96\x22\x53\x90\x17\xee\x24\xe5\x45\x42\xae\x5e\xa8\xa5\x03\x62\x74\x7e\xa8\xc5\x9e\x49\xbe\xfc\x86\x8e\x3e\xcd\x20\xcc\xc7\x97\xd1\xc0\x7b\x1b\x67\xf0\x8f\x03\xd0\x57\x8f\x0a\x13\x0d\x91\x03\x2e\x62\x99\x42\x3f\xfa\x64\xaf\xa4\xe3\xc9\xf6\x24\x1c\x77\xa2\x6d\x12\xb1\x2a\x44\xbc\xcb\x59\x9b\x80\xfe\xe5\xcc\x85\x6e\x68\xb9\x99\x65\x00\x78\x97\xa8\xa4\x34\x85\x38\x21\x84\x87\xe2\x41\x1a\xd2\x92\x6a\xd2\x94\xa2\x38\x1a\x52\x6c\x03\xe7\xb1\x31\x8a\x26\x2c\xfc\x51\xa6\xeb\xc0\x71\xbe\x1b\x53\xc2\x01\xf5\x60\x74\xda\xc9\xdf\x70\x8e\x70\xa0\x33\xd5\x3b\xe9\xcb\x89\x39\x94\xfb\x0a\xd2\x61\xae\xa6\x5e\x28\xc1\x4b\x43\x4d\x38\x5d\x74\x2f\xb9\x83\xfc\xeb\xcd\x1b\x2e\x81\xac\x6e\x93\xac\x6c\x7c\xc1\xde\xea\x68\x61\x96\xe9\xa8\x7b\x5a\x1d\x76\xaa\xdb\xec\x49\x89\x5d\xb5\x78\xa1\xfd\x7d\xd1\x5e\x74\xde\xd4\xa8\xda\x39\x25\x1d\x14\x0a\x32\x54\x7e\x46\x65\x42\x6c\xf7\x0b\xf0\x83\x4a\x60\x77\x9d\xb7\x0e\x8e\x1e\x3d\x98\x69\xe5\x7e\xca\x04\x57\x8a\x21\x10\x3a\xe6\x3f\xc1\xda\x41\xa3\xcd\x94\xe7\x4d\x11\x17\x79\x20\xeb\x49\x45\xc2\xac\xd6\xf5\x3f\x26\xd3\x61\x2e\x20\x2a\x07\x73\x88\xee\x1d\xc3\x5f\xc7\xed\x3b\x6b\x7d\x77\x1e\x5f\x46\xc7\x83\x22\x23\xac\xbc\xdb\xac\x9e\x45\x9b\xb9\x15\xe8\x31\xf5\x3b\xde\xd6\x9c\x70\xe0\xb0\xce\x26\xc6\xe0\x7c\x43\x57\x6e\x5c\xed\xbc\x56\x33\x35\x35\xcb\x68\xcd\xda\x1b\x39\x6b\xa6\xae\x4d\x6f\x37\x40\x87\x04\x74\x48\x69\x1f\xac\xc9\x35\xfc\x6b\x03\xef\x42\xb7\x4c\xb7\x75\x45\xe7\x2a\x7b\x41\x76\xbc\x13\xc6\xb8\x3a\x25\xd3\xbf\x82\xaa\x0e\x3f\x4c\x28\xc9\xb6\x14\x7e\x6a\x0e\xc7\xd0\xe8\x43\xeb\x46\x98\x68\x5c\xdd\x79\xe3\x0e\xe8\x35\xe7\x5b\x35\x20\x87\xce\xcc\x7e\x50\x23\x48\x87\x41\x4c\x3b\x6b\x9d\x21\x84\x0d\x0c\x37\x9d\x56\xad\xf6\x36\x63\x48\x6d\x0e\x61\xc5\x29\xb4\x88\x21\xa3\xea\x5e\xe9\x76\x31\x8d\x9d\x62\x37\x7d\x4c\x6b\x3d\xcd\xf3\x40\x89\xfd\xa8\x06\x0d\xb0\x2d\x30\xb4\x0d\xbc\xce\x15\xee\x61\x50\x38\x2a\xe2\xbd\x84\x8d\xfa\x58\x24\x64\x34\xc9\x3e\xfe\xe0\x35\xf7\xd0\x1e\x4a\x11\x2d\x31\x29\x78\x09\xa6\x37\x27\xe5\xec\x03\x50\x51\xb3\xa6\x18\x44\x55\xb9\x6d\xec\x90\xdd\x09\xbf\x36\x77\x82\x4f\x28\xd8\xd4\xfb\x94\x72\xe3\xf0\x13\x83\xd2\x1a\x26\x9d\xc6\x5f\x71\x5e\x80\x19\xa2\x9d\x9b\xb7\xaf\x1b\xaa\x57\xc9\xc5\x66\xad\x91\x39\x88\x18\xac\xd1\x7f\x9d\x7f\x7d\x85\x76\x3c\x26\x98\x72\xda\x31\x5e\x6b\xfc\xf2\xcf\x4f\x9f\x0d\x30\x9d\x9f\x9d\x56\x87\x11\x3c\x1b\xb7\x60\xd0\x6c\x8f\xb4\xe8\xc1\xed\x4d\x6a\x3a\x7b\xe9\x8d\x35\x2d\xee\x7b\x3e\x4c\x2b\x47\xdf\x36\xf9\xd2\xac\xbb\x13\xd6\x2a\x2d\xb1\xc7\x80\xbd\x18\x73\x7d\x81\xec\x3b\x3c\x1d\x4d\x56\x98\x0d\x3b\x77\x00\xd8\x67\xf5\x49\xa7\xb5\x99\x46\x3b\x91\x99\x02\x1d\x8d\x59\x95\x7b\x00\x1e\x75\x8a\xe5\x16\x23\xcf\x48\x04\x93\x7f\x4b\x79\xf4\x9d\xc7\xf1\x7b\x98\x71\x4b\xee\xe2\x1e\x46\xb8\x6f\x4b\x20\xdc\x19\x7e\x0f\x65\xa3\x1b\x3b\x00\x95\x51\x1e\xba\xb4\x1b\x16\x8e\xf9\xd7\xe8\x97\x3b\x75\x31\xb2\xc8\xb9\x31\x81\xbe\x76\xdc\xbd\x3d\xc8\xe7\xe6\x9f\xe3\x2c\x5c\x44\xc7\xb0\xe9\x7a\xc8\x56\xab\xe5\xa6\xd1\xf5\xed\x8e\x6e\x63\xa8\xb2\xd7\x6e\x8a\x5e\x26\x80\x59\xce\xa5\x5f\xc8\x20\x34\x90\xcd\x67\x7a\x6b\x0e\x1c\xf4\xba\x2f\x67\x22\x0f\xb2\x75\xc8\x98\xac\xf8\x15\x40\xd6\x83\xef\xc3\x74\x63\xa5\xf1\xd6\x6d\x03\x57\xb5\x65\xb5\x67\x79\xae\xfc\x81\xa5\xc3\x74\xc1\x99\xf4\x0e\x1a\xd3\x26\x9b\xa4\x7c\x43\x33\x08\xc8\x62\x1a\xd4\x51\xa5\xe2\x00\x74\x25\x19\xec\x42\x94\xee\x20\xce\x36\x61\x9c\x1e\xdb\xac\x8d\xee\xa5\x8c\x83\xcd\xc3\x1c\x46\xe4\xc8\x51\x7a\xf3\x29\x14\x1b\x37\xfa\x70\x7f\x1f\xc1\xb2\x7c\x39\x4f\x84\x2f\x5f\xde\x5c\xf4\x31\x9b\x3f\x14\xbd\x8a\xe0\x3e\xa0\x44\x1f\x94\x77\x19\x73\xd7\x34\x01\xbb\x62\x16\x07\x7e\xa7\xdd\x5c\xdd\x8d\xde\x44\xce\x28\x4d\x9a\x2e\xe0\xf3\x7e\xd5\x13\x4e\x1d\x90\x6f\xce\x3f\x71\x15\x8f\xc3\xb2\x37\xb6\xcb\xf9\x79\xbb\xb4\xdb\xf2\xe4\x59\xcf\xed\xb5\x0a\xc0\x21\x71\xcf\x1a\x0b\x63\x62\x47\x11\xb9\xe5\x8f\xfd\x9e\xf2\xae\x3a\x28\x5d\x4c\xa6\xfd\x9c\xb5\x5a\xd0\x80\x9a\xb2\xd0\xe2\xda\x12\xe6\xae\x19\x1d\x15\x10\x07\xa7\xfa\x04\x5a\x25\xd5\xec\xef\x8d\xb3\x50\x3a\x20\xce\x97\xcb\x38\x69\xda\x9f\xdd\xb6\xb2\xfb\xc9\x6c\xa7\xcf\xe8\x6a\x98\x3b\x9c\x0d\x3a\xcb\x43\xd7\x53\x39\x67\x80\x6e\x67\x55\x8e\x27\x21\x3d\xac\x27\x57\x0b\xa5\xdb\xc6\xea\x3a\x01\x71\x3a\xff\x70\x12\xe8\xdd\x73\x76\x3a\xf9\xe8\xab\x30\x7b\x24\x04\xb7\x20\xd0\xd1\x80\x77\xa2\xc3\x8e\x7e\xc9\x5b\xd6\xf8\xcb\x29\xfa\xb2\x22\x30\x3b\x99\xa2\x1a\xda\x04\x5c\xc6\x46\xec\x81\x13\x2c\xc0\xed\xe5\x1b\x8d\xd8\xea\x21\x32\x7e\x0d\xe6\x59\xc7\xd1\x9c\xbf\x23\x31\x41\xd5\x98\xb4\xe4\x24\xa8\x47\xbd\x39\x72\x73\xc2\x2c\xc6\xed\xac\x45\x61\x99\xd5\x4c\x17\xe5\x11\x4e\x93\x8c\x4f\xca\x15\xe0\xb9\xee\x90\x10\xd2\xe5\xd9\x56\x66\x5c\xbe\xb4\xd7\x4c\x81\x1e\xcc\xe1\xe0\x43\xae\x49\xed\x10\xb3\xfd\xf1\x06\x17\x38\x68\x2a\xca\x2a\xb3\xb1\x15\xc2\x06\x75\xe5\x05\xcc\xd2\xcd\x98\xe2\x3d\x1c\x71\x07\x54\xba\xe4\xc1\x90\x08\x7d\xb4\xd3\x16\x69\xda\xdf\xfa\x12\x05\x9e\x54\xb8\xec\x93\x37\x41\x3c\x49\x0b\xa2\x40\x27\x0c\x16\x6a\x20\xe5\x1c\x32\x8a\x78\x9a\xcb\x0b\x04\xbd\x3f\xc3\x3b\xea\xf6\x26\xd4\xca\x83\x6f\x8d\x9a\x06\x32\xd0\x69\x1e\xf7\xd2\x04\x47\xe7\xe6\xb8\x06\x14\xfe\x24\x9f\xde\xc9\x4c\x9b\xab\x23\xef\xf8\xb3\xcf\x8e\x59\x68\x62\x8c\x2d\x5a\x61\x91\x87\x09\x16\x58\xc1\xa2\x3a\x09\x19\x19\x4a\xfe\x76\xf4\x48\xa4\x36\x8c\x5d\xa8\x72\xb9\xd1\x09\x0b\x14\x55\x2a\x9e\x95\x76\x94\x09\x05\xf2\x75\x0a\xd3\x65\x6d\x83\x6d\x95\xfe\x8d\x3b\xe0\x46\xce\xe5\xa3\xc3\x9c\xa1\x1d\x55\xc1\x91\xd6\x20\xaa\x64\xb5\x5b\x13\xcd\xb3\x15\x6c\x5a\x32\xf9\x3a\xeb\x2a\xa0\x40\xb8\xa9\xab\x02\xb5\x31\x2f\xaa\xb8\xd7\xa3\x1a\xd4\x51\xe3\xf1\xba\xae\x11\xc5\xd5\x46\x64\xf9\x9a\xe8\xd4\x16\x82\x42\x87\x5b\xf9\xb2\x38\x1d\xb3\x7e\xe1\x68\x4b\x2b\xb3\x5d\xdc\xff\xa6\x57\xb4\x5b\x04\x30\xdf\xe6\x3f\xf7\x81\x2e\x58\x2b\x09\x05\xf7\x2c\x0f\x6c\x25\xaa\xa2\x2a\xc7\x80\xc2\xc0\x03\xbc\xa2\xfb\xf7\x93\xa3\xad\xe5\xb9\xcb\xcd\xbb\x1e\xbe\xc6\x46\xfa\x21\xe7\x4e\xd2\x67\xff\xca\xb1\x64\x15\x52\xa9\xc8\x29\xb3\xd1\xfd\xa6\xa4\x05\x4e\x73\x48\x31\xf0\x62\x52\x33\xae\x8a\x0b\x3a\xb5\xee\x09\x27\x41\x78\xe7\x4d\x1c\xc9\x87\x01\x6b\xf3\xb4\x02\x30\x08\x34\x35\x30\x19\x05\x64\x21\xfc\xed\x94\xf2\x69\x00\x44\xb1\xed\x7f\x70\xf7\xe8\x10\x69\xab\xbb\x27\xad\x76\x3a\x2a\x87\x48\x58\x3d\x4c\xba\x6a\xbf\x64\xd5\x3d\x3a\xa3\x87\x48\x53\x75\x4f\x52\xdd\xbb\x2b\x7c\x98\xf4\xd4\x03\x24\xa7\x6e\x99\x9a\xba\x67\x57\xff\x20\x49\xa9\x07\x48\x49\x3d\x58\x42\xea\x81\xd2\x51\x77\x4b\x46\xfd\x18\x80\xf9\xbd\x07\x60\x0e\x91\x74\xba\xff\x94\xd3\x03\x25\x9c\x1e\x20\xdd\x74\xa7\x64\xd3\x5d\xa3\x61\xf7\xce\x55\x7b\x9c\x43\x25\x74\xeb\xf5\xf8\x7f\x4f\x47\xcf\x80\x32\xc7\x7f\xfb\x2b\xfc\xe2\xfd\xe5\x19\xd7\x07\x85\xdf\x47\x8f\xde\xbf\xff\xed\x9d\x8e\x3e\x1f\x3d\x1e\xef\xb5\xc8\x4c\x77\x86\xda\xfa\x51\x86\xae\x5c\xe8\xcd\x02\xf9\xa8\x26\x2a\xc0\x7b\xb8\xc0\x37\x26\xd3\xca\xe5\x58\xc3\xe3\x78\x1e\xd5\x42\x84\x76\xcb\x3c\xfb\x9d\x5c\x0c\x75\x49\x28\x74\x4d\xeb\xdc\xe9\x76\xa9\x6b\xe2\xe0\xef\xe2\x76\x69\x97\xdc\xed\x48\x0d\x3c\x70\x52\x60\x9f\x74\xc0\x3e\x89\xad\x7b\x4f\x01\xec\x99\xfc\xe7\x9a\x22\xdb\x99\xf0\x77\xd8\x54\xbf\xad\x92\xfc\x7a\xa5\x23\xf4\x4d\x39\xdd\x67\x4a\xdf\xde\x92\xf9\xdc\xd3\xf8\x1c\x13\xf8\x7a\xd8\x7a\x6e\x49\x7b\x3d\x00\xba\x25\xea\xed\x39\x45\xaf\xe7\xfc\x1c\xd3\xf2\xfa\xdc\xeb\x73\x4d\xc5\x73\x86\xd9\xe7\xd2\x55\x2b\xa0\xee\x94\xbb\x5e\xc9\x76\x8e\x69\x76\xbd\x12\xec\x9c\x88\xe2\x96\x35\xd2\x36\x54\x47\x22\xdd\xce\xe9\x24\x4e\x69\x73\xfb\x4a\x98\x6b\x15\xbf\x3b\xa5\xca\x75\x07\x67\xdd\xd2\xe3\xf6\x90\x18\x57\x4a\x7d\x6b\x8e\xeb\xed\x35\x25\xae\x2f\xf2\xed\x0e\x65\x3f\x77\xd2\x61\x1b\xec\x98\xf4\xb6\x96\xd6\xd6\x12\x2b\xed\x4e\x77\xeb\xdc\x2e\x0e\x29\x6e\x7b\x4a\x6e\xeb\x4a\xa3\xdc\x3e\xad\xad\xeb\x44\xad\xbd\x38\x4a\x7b\x12\xdb\x96\xd9\x67\xc0\xeb\xf1\x43\xb7\xab\x46\xcd\xb4\x47\x64\x3c\x7a\xb4\xc7\x5a\x2b\x7a\xba\x79\x5e\xb7\x1c\xdb\x2c\x3c\x07\x7d\x79\xb2\x64\x40\xe1\xe0\x6c\x00\x4f\xa4\x2d\x25\xba\x85\x53\xd6\x55\xee\xdd\x61\xd3\x50\x25\xe5\x9d\x7c\xa8\xd6\x1d\xb9\x4b\xf1\x9c\x02\xbb\xda\xaf\x69\xe6\xfb\x63\x1c\x5f\x46\xab\x6e\xbe\xc1\x56\xbf\x15\xdb\xd0\xfb\x1b\x1f\x59\xe7\xf7\xc7\x3a\xcb\x44\xc5\x58\x74\xad\x83\x7d\x6e\x2b\x4f\xc5\x51\x9c\x47\x78\x0b\x35\x5f\x50\x7d\x50\x06\x41\xfa\x09\xdf\x4e\x8b\xa6\x8a\x0a\xe6\xa9\x24\xcd\x4c\xa1\x21\x10\x96\xf8\x12\x01\x05\xc4\x38\xf9\xc5\x7e\x4a\x4f\xe1\x60\x22\xd9\xc6\x14\xf8\xa8\xff\x0c\x5f\x59\x79\xfe\xac\x01\x29\x7c\x81\x65\xbe\x11\x28\xe0\x67\x54\xd2\x77\x2e\xf5\x17\xab\xcf\xe0\xd1\x31\x97\x89\xfe\xdb\x52\xc9\xa6\xb2\x1d\xdf\x3b\x36\xea\x86\x1f\x6c\x58\x2b\xee\xa6\x3d\xc7\x6d\xb7\x52\x09\xda\x45\xf1\x16\x4c\xf1\x8c\x81\xa8\x0e\x58\x3f\x5d\x3b\xbf\xe2\x41\x99\xcc\x79\xa2\x0e\xa5\x97\xe2\x88\xad\x1f\xe7\xbb\xcd\xb6\x47\x6d\xc9\x6a\xac\x75\x2e\x14\x66\x08\x25\x03\xef\xc2\x22\xb1\x32\xf6\xde\x08\x6c\x5b\x11\x64\x0b\x60\xb1\x20\xe5\xb6\xa9\x79\xc5\xb0\xc5\x52\xd6\x70\x30\x3b\x22\x88\x85\x9f\x6a\xc9\x03\xb6\x8f\xdf\xe4\x78\xb6\xb9\x54\x43\x3b\xb5\xc6\x06\x7a\x9a\xdb\x4a\xa5\x68\x97\xe0\x62\xd4\xcb\x6a\xaf\x9a\xe8\x7a\x51\x9a\x79\xc5\xc5\x69\xef\x2c\x8e\xdf\x26\xf5\xa2\xbd\x5a\x52\xc0\xc0\x61\xec\x50\x7e\x75\xb3\x7a\x1c\xf7\xd4\xf6\x29\xd6\x1c\xc0\x52\xf9\x8a\x02\x4a\x09\x25\x20\x21\x76\x64\xb4\x56\xeb\x4b\xf4\xad\xa1\x1e\x8a\xc7\xd7\x42\x05\x79\x22\xaf\x25\x68\xc8\x28\x03\x51\xe8\x70\xf2\x40\x81\x3c\xdb\x61\xa3\xa8\x05\x85\x18\xf5\xb3\x34\x01\xc6\xe4\x58\xdd\x62\xe9\xd2\x81\x07\xd3\x8c\xf0\x6d\x22\x3f\x7e\x88\xf4\xf3\x5a\x82\x04\x75\x51\xf7\x0e\xe5\x08\xc8\xe2\x55\xfb\xbb\x2d\x54\xb8\x0d\xe8\x21\xd3\x45\xcc\x8f\xd2\xc9\xa8\x84\x10\x9f\x7e\xe1\x46\x6d\x2a\x5e\xdd\x2e\xd7\x35\x79\x54\x08\x5b\xd1\x3b\x7d\xfa\xb4\xbe\x81\x8a\xb8\xc1\xd3\x96\x83\x93\x7a\xdd\x50\x25\x7f\xea\x48\xf5\x22\x49\x71\x9d\x6a\x05\xa9\xe9\xa1\x1e\x24\xff\x40\xbf\x30\x83\xac\x63\xd3\xbc\xe8\xbd\x30\x7c\x97\x07\xdf\xb7\xd2\x7c\x66\x23\xc9\x91\x7c\xcc\xa8\x49\xd3\xf9\xd6\xdb\x78\x6d\x60\x7e\x6d\xc7\x0e\x3d\x03\x10\x8a\x32\xb8\xca\x2b\x11\xc5\x49\x2d\xab\xd1\x44\xb6\x5f\x9d\xdd\x88\x4f\x5b\xc8\x91\xec\xbc\xdd\x4c\xb1\x16\xb3\xad\xf9\xf4\xc0\xbc\x11\x87\x66\x06\x51\x97\x45\x7e\x4d\x6c\x80\x57\xa2\xbc\x2a\xd6\xef\xb4\x84\x6f\xad\xcb\xae\xa2\x88\xd7\x97\x40\xd3\x9b\xc5\x5c\x5e\xd1\x2c\xa3\xad\x10\xb3\x4c\xe4\xbd\xc2\x17\xcb\x1a\x05\xb5\x7b\x16\xfb\xb5\xc1\xf7\x46\xf3\x8e\xde\xdc\x26\x84\x62\xbe\xde\x21\x47\x1d\x8c\x80\x24\xbe\x17\x01\x3e\x49\x1c\xe7\x99\x73\x16\xd5\x57\xe0\x82\x52\x0e\x3f\x4e\xe4\x5c\x03\xb9\xe1\x27\x5d\x59\x0c\x69\xee\xc7\x27\xf4\x40\xe9\xf2\x38\x58\x42\x1e\x3e\xf3\x63\x99\x46\x27\x19\x66\xa0\xe2\xfb\x57\x85\xa2\xdf\xfd\x3c\xde\xa1\x52\x50\xad\x2e\xec\xa8\x16\x54\xda\xc3\x83\x43\x54\x03\x72\xc6\x30\x14\x51\x2e\x02\x43\x70\x67\x14\xdf\x97\x18\x1f\xcd\x74\x2a\xfc\x5b\x95\x41\x2a\xd5\xcc\x85\x69\xb7\x66\xb9\x8c\xf9\x6e\x59\x6d\x68\xbf\x29\x57\xcf\x49\x4c\xbb\x75\x4e\xa0\xc8\x0e\x28\x58\x0e\x38\x59\xa3\x4d\xcf\xa5\x93\x18\x5d\xf7\x0d\xd3\x58\xdc\x5d\xe4\x89\xc8\xfa\x5c\x74\xb0\xac\x6b\x8a\xfd\xaf\xaf\x72\x65\x0b\xd7\xfa\x02\x96\xbb\x6d\xdc\x49\x97\x58\xdd\xa4\xe7\xce\x77\x7c\xec\x6b\xe2\x87\x58\x6c\xd6\xf3\x1c\x47\xb4\x03\xed\x21\xe3\xd0\x17\xab\x34\x9e\x3d\x48\x79\xd7\x2b\xf5\x0c\x3b\x60\x57\xcf\xbc\x54\x46\xbb\x8c\x13\xd0\xf0\x57\xfc\x5e\xf3\x10\x6a\x3f\x15\x76\x97\x0f\x60\x94\x8a\x47\x90\xe2\x40\x80\x92\x8d\x93\xf9\x78\x79\x37\x1f\x23\x88\xf1\x27\xdf\xf2\xb0\xbf\xd3\x74\x2c\x7c\x4f\x39\x4f\xf6\x75\x5d\x6f\x6d\x2b\xe4\xc9\x0d\xbd\xbd\x86\x84\xd0\x9a\x9f\x08\x4c\x77\xb7\x32\x7e\xa7\x1e\xb6\x09\xf0\xff\x3f\x14\x9e\xe1\x47\x0e\xf5\x14\x18\xcc\xc0\x2e\x22\x80\x6c\x25\x3f\xfc\x46\x0f\x47\x65\x22\xca\xba\xcb\xc4\xf4\x2b\x8d\x22\x5d\x2b\x97\xf4\xac\xa1\x41\x11\x8b\x03\x40\xee\x51\x9b\xc3\x95\x7b\xcc\x9b\xab\xbd\x76\xa2\x59\x30\x19\x8d\x1e\xd4\x9d\x5a\x4a\x5f\x09\x5a\x36\xfc\x6b\x7c\xa5\xd2\xec\xc7\x78\xf6\x63\xf6\xd3\x8f\xf8\x96\xd5\x44\xa4\xf2\x47\x5c\xcb\x1f\x7f\x8a\xa3\xee\x82\xbc\xae\xf4\xe0\xad\xeb\x52\x80\xa4\x7a\xb9\x42\x4c\xf9\x26\x12\x73\x2f\x6d\x7f\xe0\x68\xd0\x56\xfc\x72\xa5\x95\x73\x64\x51\x51\x5b\xa7\x04\x51\x93\x85\xc4\x3b\x82\x84\xaa\x39\xcc\xd0\x79\x9e\xd0\x28\x35\x43\xa6\x98\xe9\x4a\x0f\xb2\x75\xc2\xc6\xab\x2a\x0f\xc0\xfb\x28\x9a\xf9\x01\x6f\x36\x5a\x13\x9f\x23\x6d\x26\xc9\x6e\x98\xfa\x77\xde\xfd\xd3\xd1\xe9\xd3\xd1\xd3\x01\xcf\xc8\xa5\x28\xd3\x2c\xc6\xc0\x2b\xce\x2f\x80\xed\x68\x5e\x16\x9d\xc0\x1a\xfc\xe5\x3f\x30\x64\x3b\xc9\x15\x1e\xb5\x9c\x15\x4f\x3d\x9f\xbd\x02\x6f\xe7\xaf\x9a\x34\x13\xe0\x9f\x3b\x30\xdc\xce\xf9\xcf\x17\xfc\xe7\xdf\x4e\x8e\xba\x36\x5e\x77\x3d\x98\xa1\x26\x7f\x67\x33\x3d\x87\xce\x76\xe7\x6e\xe0\x5e\x38\x81\x73\x4d\x57\xe9\xde\xb1\xdd\x37\x18\x2b\x86\xeb\xb6\xc7\x72\x5d\xef\x01\xb4\x09\x8c\xe6\x29\x0e\xd9\x3b\xea\x93\x7e\x98\xc8\x2c\x59\xf5\x0e\xbd\x60\x2f\x73\x32\x68\x4d\x32\xf4\x79\xf5\x33\xbb\x7a\xf7\x75\xd6\x69\x6c\x57\x10\x13\x31\xbd\x8b\x67\x33\x47\xf7\x93\xc2\x92\xa5\x68\xaa\x76\x36\x11\x3f\xf6\x72\xaa\x3e\x39\xe8\xbc\x38\xa7\xd7\x62\x38\xc6\x4d\x5a\x94\x5a\x0f\xbc\x2f\xc2\x2e\x47\xa7\x83\xe1\x54\x84\x4e\xda\xb7\x2d\xb6\xe0\xc6\xf4\xd7\x43\x15\xd6\x1a\x64\x5a\x5b\x8f\xb6\x24\x13\x5d\x2b\x61\x76\x19\xe8\xa1\x78\x7c\xd1\x8b\xd4\x3a\xe4\x63\x48\x9e\x81\xad\x07\x93\x7b\x88\x2d\x5f\xb0\x57\x69\x08\x7d\xba\xd8\x91\x9c\x30\xde\x0d\x43\xee\x1d\x01\xaa\xcc\x68\x9d\x03\x2a\xec\xcb\x47\x22\x4c\xeb\x3c\xca\x54\xa0\x9f\x1d\xaf\x67\xe5\xae\x47\xdf\x0e\x1c\x99\x69\x4b\x28\x4e\xe2\x39\x66\x8b\x5f\x48\xe1\xa3\x16\x71\x38\x56\x21\xf5\xbb\x81\x61\x28\x56\x16\x18\x7b\x52\x6b\x2f\x30\xd4\xa9\x62\xa6\x1c\xba\x6b\x7c\xc0\x6f\x0f\xa3\x93\x92\x5b\x86\xe1\x98\x05\xc7\xdf\x07\x55\xbf\x4c\x67\x91\xf4\x79\xc7\xeb\x01\xd3\x8d\x30\xa1\xc6\xe6\x60\xdc\x4e\x41\xaa\xf8\x37\x66\xec\x0e\x02\x7c\xdb\xd5\xdf\x06\xd5\xf1\x7e\x78\x84\x89\xcd\x3c\x66\xe5\x05\x29\x2f\xc5\x4e\x94\xd8\xa3\x53\x23\x9a\x4f\x6a\xdb\xf6\xa3\x2d\x72\xdc\xff\x31\x95\xa2\xc8\x0d\xbf\x46\x5b\x8a\x7a\x7a\xf3\xb8\xee\x2d\x95\x76\xf1\xeb\xf4\x6a\xc7\xc7\x1b\x9e\x5a\x42\x7d\xbc\xe1\xe9\xe8\xdb\x7e\xbc\xe1\xf9\xf1\x86\xe7\xc7\x1b\x9e\xae\xe4\xfa\x78\xc3\xf3\xe3\x0d\xcf\x8f\x37\x3c\x3f\xde\xf0\xfc\xff\x76\xc3\xd3\x18\xaf\x67\x3b\x9e\xcb\xbe\xc1\x20\x9d\x9a\xbe\x64\x70\x45\xb1\x9a\x21\x65\x00\x04\x6a\x1e\xd1\x3a\x50\xf0\x0e\x23\x6c\xb3\x46\x41\xb2\x8f\x32\x62\xbf\xf3\x12\x5a\xe0\x50\x38\x58\xf9\x5f\x81\xdb\xd1\x78\x7c\xaf\x73\x24\xe8\x20\xd1\x24\x3f\x64\x60\xa4\x34\xbe\xd8\x62\x5b\x03\x57\x26\x31\x38\x7f\x09\xa7\xbb\x61\x89\x37\x5a\x96\x38\x07\x4b\x30\xc4\xa2\x92\xe6\x99\x47\x6c\x2e\xd2\xc2\x1f\x6d\x80\xdc\xf6\x60\x62\xe7\x1b\xbd\x78\x33\xf6\x6c\xab\xae\xe5\x67\x6a\x1c\xa8\x79\xd2\xa3\x8c\x92\x2e\x98\x74\xd4\x1c\x9b\x86\x7e\x4b\xac\x98\x84\x3e\x4d\xec\x9b\x77\x62\x29\x25\x31\xb1\xf5\x94\x46\xde\x70\x38\xf4\xde\xca\x07\xf4\x1e\xf5\x51\xaa\x6a\x4b\xbe\x67\xef\x5d\x81\x8b\x9b\x27\x14\x00\x98\xc8\xa9\xc8\xf9\x3a\x0c\x0c\x32\x53\x53\xf0\xd0\x56\xe6\x0e\x26\x25\x40\x64\xe8\x33\x37\xdf\x9e\xa4\x3c\x24\x19\x4e\xa4\xef\x73\x68\xeb\xfc\xfa\x12\x44\xb1\x77\x0a\x86\xc4\x3c\x8a\x13\xca\x49\xe5\xb2\x46\xde\x65\x66\x0a\x7e\xa1\xa4\x8d\x9a\x44\x96\xbe\xf4\xc7\xbe\x2f\x4e\x18\xc3\x33\x1c\xa3\x0f\x80\x9b\x16\x31\x81\x05\x78\xe8\x3a\xd3\x7d\x15\xa0\xf6\xc0\x33\x6e\xfe\x37\xad\x35\x04\x51\x90\xbe\xc6\x01\xae\x85\x7e\x09\x93\x32\xab\xf0\x6a\xa1\x97\xc0\x9f\x30\x00\xc5\xe4\x49\xaa\x4c\xb3\x5c\x04\x8c\x3e\x0c\xf7\xac\x49\x48\x5e\x46\xdc\x85\x1a\xe2\xa5\x8a\xa5\x46\x36\x25\x7e\x07\x6d\x80\xa1\x06\xd4\x17\xbe\xaf\x5d\x7b\x30\x59\xb0\x1d\x46\x97\x1a\x9f\xac\xf6\xd5\xbd\xf2\xcb\x13\x00\x53\x2f\x8c\xc1\x80\xb2\xe4\xa6\x2f\xb8\x3a\x60\xc2\x6b\xbb\x14\xb0\xbb\xa6\x54\x6a\x8c\x65\x10\x8a\xa8\x69\xdb\x35\xd1\x40\xdd\x01\xf1\x8e\xc9\x32\x23\xf6\xb2\x0f\x14\x23\xc3\xa4\xe0\xd8\x21\xc9\x5e\xf0\xfd\xf9\x0f\x97\x17\xb4\x1a\x7a\x0d\x8e\x1b\x60\x62\xd3\xa2\x90\x19\xa5\x7f\xf2\x3c\xa0\xcb\x48\xc7\x03\x50\x33\x4e\x29\x9f\x01\x1b\x3c\x48\x70\xd0\xcd\xd5\x5f\xdf\x85\xd7\x00\xce\xf3\x11\xd6\x82\x03\xd4\x30\xa3\x1e\x64\x7f\x71\x98\x02\x5f\xbe\xd0\x9c\x8d\xdb\x86\xe9\xd4\xc4\x11\xb4\x25\x66\x5c\x0f\xcc\x9a\xd0\x1a\x10\xbf\x44\x5b\x6d\x83\x31\x50\x82\x38\x68\x9c\x27\x72\x6e\x28\xee\xd0\xde\x07\x62\x89\xc4\xa7\xf3\x5d\x98\x0e\x07\x71\xc0\x44\xf0\x81\x1a\xd0\x50\x64\x7c\xb2\xb5\x00\x49\x29\x71\xda\x9f\xb7\x45\x50\xf4\xce\xb0\x5c\x0b\xdc\x95\xe0\x21\x06\x17\xcf\xc4\x90\x0c\x25\x1d\xd0\x47\x00\xeb\x1f\xc0\x43\xc8\x84\x40\xe3\x26\xdc\x4d\x6b\xb4\x7c\x96\x3a\xe6\x2f\xbc\x0f\x37\x57\xda\x38\xc7\x4c\x22\xd0\x97\x7e\x8e\xf9\x29\xe1\x44\xcd\x73\x4c\x3a\x2f\x8c\xf0\x46\xe6\x05\x79\x0d\xa0\x75\xae\x0a\xce\x4e\xab\x43\x80\x7e\xf3\xea\xf6\xbd\x19\xaf\xc4\xd5\x53\xac\xe4\xd7\x76\x11\xcf\x2f\x52\xb3\x55\x6a\xb2\xc3\x29\x0b\x68\x60\xeb\xe8\x65\xf9\x12\xb6\x9a\xd9\xe8\xa5\x8a\x80\x4d\x9c\xca\xce\x8e\xde\xe9\xc0\xa4\xf9\x94\xf7\x6d\x82\x57\x9a\xee\x05\x56\x88\xf3\xbe\x18\x79\xdf\x5a\x66\x95\x22\x55\x40\x65\x2c\xc8\xd0\x28\x14\x55\x56\x61\x40\x74\xfa\x51\xf8\xa2\x65\x59\x92\x94\x28\x02\xed\x41\x31\x60\x9e\x2f\xfd\x96\x48\x86\x49\x21\x21\x48\x7c\x52\x0b\x7c\x23\x80\x27\x61\xc6\x51\x0e\x0e\x10\x66\xcf\xa4\xd3\x85\x0c\x05\x0a\xdb\x8b\x38\x3a\x39\xc9\x88\x0b\x9b\x6a\xe7\x81\xde\x40\x69\xcd\x93\x42\x63\x16\x73\x55\x13\x2d\xa0\xe0\x13\xf8\x92\x87\xe3\xf8\x79\x4c\x5b\x5f\xab\xf7\x66\x59\x68\xdf\x03\xcb\x53\xd6\xf3\x7a\xd2\x03\x9d\x90\x84\xd1\x2c\x54\xca\x9c\x1f\x83\x05\x30\x38\x8e\x8c\x91\xd9\x36\xd5\x45\x91\x12\x80\x85\xa2\x62\x38\x8b\xa7\x14\xc9\x05\x2e\x00\x6d\x99\x14\xa5\x06\xf9\x72\xbd\x7c\x14\x21\x30\xc2\xc0\xbb\xc5\x6a\x7d\x53\x69\x95\x72\x13\x75\x71\x7b\x0a\x3f\x54\x5c\x9a\x31\x01\x9f\x92\x7c\xcb\xca\xb1\xfc\x1c\x6c\x98\x7c\x32\x9a\xc6\x61\xa9\x42\xe0\x58\x2c\xd5\x78\x12\xc4\x93\xb1\xbe\x03\x37\x3c\x1d\x9d\xfe\x71\x6c\x61\x95\x41\x8d\xef\x4f\xc7\x24\x54\x47\xf3\xf8\x93\xab\x2f\x9e\x3f\x6f\x98\xcc\xe8\x64\xcb\x90\x63\xf7\x25\x9d\xf5\xa8\x1d\xac\xf0\x5a\x59\x59\x5b\x19\x71\x97\xbb\x38\x33\xa3\x61\x5d\x6b\x33\x5e\xce\xb4\x6d\x63\x65\xd0\x52\x49\x4e\x92\xc7\x88\x9f\xad\x1e\x89\xbc\xd5\x62\x96\x73\x73\x98\x3d\x4a\x1a\xee\x35\xd0\x37\x46\x38\xd0\xa2\x0f\xe2\xb5\x69\x06\xc3\x90\xa8\x6f\x81\x88\x05\x1b\xc6\x6f\x62\xed\xc0\x89\xe9\x14\xcf\x50\x30\x19\x95\x2c\xc3\x01\xd8\xcc\x68\xa4\x60\xf2\x54\x8a\xc6\xfe\x2d\x7e\x33\x32\xa5\xd7\x47\x7a\x1c\xa0\xf0\x77\xcf\x7e\x68\x0b\x4c\x54\x98\x56\xe9\x08\x0c\x63\x6d\x59\x9b\x93\x8f\x29\x0f\x4e\x43\x6d\x4f\x19\xd7\x02\x3e\xf6\x35\x09\x1e\x08\xf5\x0c\x04\x02\x1f\x02\x91\x57\x48\xfa\xff\xcc\x3b\xa6\x6a\xd0\xc5\x74\x7f\x46\xf5\xfd\xcb\x71\x0b\xe4\x4f\x1f\xc8\xe8\x20\x3d\x7f\x5c\xba\x2b\xb3\x7e\xeb\xba\x98\x2c\x87\x3a\x13\x35\x9f\x77\x94\xef\xc0\x6e\xf2\x1e\xc8\xfb\x04\x2d\x09\x3a\x9f\x2b\x81\x31\x35\x0d\x0a\x39\xb5\x3e\x79\xa0\x75\xeb\xcc\xab\xf4\x43\x4b\x4b\x3e\x7a\xcf\x38\x1e\x80\x29\x85\xb1\xff\x44\xab\xbf\x74\x05\x2d\x1f\xe9\xf6\x2e\x1a\x2c\x6d\x94\x36\x56\xd3\x42\xdc\x63\x09\xfe\x90\x6d\x9a\xa1\xc9\xc7\x7a\xe0\x78\x9f\x59\x4c\x8e\x5f\x2d\xdb\x4b\x4e\x97\x99\x7f\xa4\x8b\x86\xd2\x0c\x91\xd9\xe6\x91\x56\xed\xc0\x9a\x11\xe8\x2e\xd6\x77\x4a\xd7\xef\x69\x7b\x6c\x9c\xae\x4f\x11\x6b\x65\xb1\xd6\x63\x26\x16\x32\xcb\xb3\x3c\x91\xa3\x93\x5d\xf6\x7e\x7b\x0d\x8c\xea\xb6\x27\x0b\xc5\xa5\x24\x2b\xc8\xde\xd1\xdd\x9f\xa8\x1e\x2b\x66\x59\xe6\x11\x98\x21\x63\x52\x45\x6a\x92\x63\x84\x78\xec\x03\xc7\x04\x63\xa0\xcb\x50\x24\xd3\x85\xca\x00\x3f\x40\x05\xc5\x33\xe6\xb9\x21\x37\xa1\x96\x1d\x85\xfe\x27\x24\x82\x87\x74\xa7\x6f\x27\x44\x7f\xc7\xb5\x67\x77\xc6\xab\xa3\xa6\xc7\x26\x72\x95\x3b\x4d\x87\xc4\x10\xc7\x49\xc7\x3b\x21\x98\x54\x3d\x45\x57\x34\x6f\x8d\xe7\xb6\xd6\x9f\xae\xd2\x91\xc9\xcf\x39\xbe\x46\x5e\xb7\xef\xc1\x50\xf8\x2c\xea\xc1\x0a\x3c\x38\xcb\x23\x81\xf3\x04\x67\xb5\x1a\x6a\x03\x6e\x08\x02\x63\x68\x9d\xa7\xe9\x6a\x27\x8a\xe6\xca\x79\xcb\xa3\x0b\xf9\xab\x6c\x04\x98\x53\x7a\xa8\x9a\xc9\x2d\x5f\xb6\xe5\x8e\x57\xb3\x3d\xd1\xff\x47\x97\xd0\x98\xd4\xec\xdf\x6e\x04\xe2\xc8\xce\x27\xad\xcd\xc6\x7e\xcf\xc3\xff\xae\xac\xf0\xc3\xe4\x82\xef\x9a\x01\x7e\xc8\xa2\xf3\x2d\x99\xdd\xee\xe1\xe8\x7d\x66\x71\x1f\x22\x77\xdb\x25\xc8\xdd\x91\xa7\xed\x74\x56\xd3\x99\x93\xed\x7c\x73\x7f\xeb\x60\x77\x7b\xae\xf5\x61\x33\xac\x3b\xb0\xeb\xca\xa6\x3e\x54\x0e\xf5\xfe\x33\xa7\x0f\x92\x2f\xfd\x6b\x66\x49\x37\xe7\x46\xb7\x66\x44\xb7\xe7\x41\xb7\x66\x3f\xb7\xe7\x3c\x6f\x57\x66\xb4\xfe\x50\x69\x58\x5b\x1f\xb4\x01\x08\xdf\xa4\xac\x02\xa8\xf0\xe1\x71\xb9\x1e\xc2\x2d\xdf\xbb\x64\xd1\x94\x6a\xbf\x94\xea\x5a\xfa\xec\x06\xa3\x6a\xb8\x6d\x4e\x5c\x7d\xc5\x4e\x6d\x4a\x65\xd1\x08\x14\x25\x70\x56\x34\x1c\xfc\x87\x27\x41\x3c\xd4\x99\xf7\x7d\xb6\x5c\xc0\xd6\xa3\xab\x98\x4b\x31\xaf\x29\x9a\xf4\x7d\xc6\xb0\x24\xb5\xc6\xcc\xc1\x74\xe1\x4f\xf1\x77\xe8\xab\x1f\x5c\xe7\xbf\xc0\x9a\x9d\xab\xe8\x91\xff\xb0\x80\x6f\xcd\x0b\xf6\x9b\x80\xb1\x4b\x18\x47\xf3\xd8\x9f\xac\x75\x7a\xcd\x39\xa7\xfc\xd9\x8d\x14\x29\xd2\xea\xfb\x63\xaa\xb2\x92\x67\x8b\x38\x51\x3f\x49\xff\xfb\xe3\x1a\x88\xdf\x67\x5f\xcb\x14\x03\xc6\xd8\x9e\x34\xfe\xe3\xe3\x23\x5d\xc8\xe4\x60\x3a\x7a\x8c\xb0\x7d\x4c\xf4\x09\xf3\xff\x50\xaa\xa2\x23\xfa\xfd\xb1\x86\x60\x6c\xce\xdb\x9a\xd5\xf3\xbc\x9f\x7f\xe1\x10\x61\x02\x96\x44\xbc\x0d\x1d\xe8\xf3\xf5\xa9\x37\x10\xa2\xd4\xeb\xb6\x65\x49\xeb\xcf\xf4\xcc\x59\x62\x21\x95\x08\xfd\xd3\xe2\x90\xd1\x9c\xca\x2f\x47\x55\x5a\x36\x6b\x34\x11\xd1\x09\xcb\x3f\x81\x31\xcf\x7a\x5a\x47\x81\x48\xb3\x65\x9c\xc2\xf2\xc5\x77\xd0\xff\x6c\x1b\x21\x4f\x30\x12\xb9\x0b\x88\xd2\x14\x52\xb0\xac\x60\x21\x57\x67\xbf\xba\x5d\x54\xe0\xf0\x5b\xcd\xa1\xc5\x10\xb0\xc5\x4c\xba\xb2\x80\x5f\xda\x86\xb5\x65\x4f\x62\xc3\x5e\xd5\x04\x68\x2e\x92\xf3\xad\x50\x78\x36\xfd\x3a\x4e\x8a\x32\x30\x5b\xd6\x94\xb1\xd3\x28\x8e\x81\x7d\x09\xff\x06\x7c\x1f\x17\x0c\x0a\x4f\xe8\x13\x5e\x1d\x27\x23\xff\x2c\x2b\x84\x2a\x99\xd8\x18\xa0\x35\x07\x9b\xa3\x2d\xaa\xc6\x20\x6f\xbe\x4f\x70\x83\xe0\x5c\xf0\x2e\x83\x93\x75\xbb\xd9\xad\xc8\x5c\xc4\x1b\xc6\x26\xcd\xbc\x28\x31\x93\xd9\xd6\xe6\x62\x3e\xa2\xa8\x45\x3e\xa5\xca\xd0\x53\x37\xa3\xa3\x36\x13\xf8\xcc\xc3\x13\x90\x61\x8b\x5f\xd1\xc9\x5e\xa1\x96\xb4\x2e\x58\xea\xb6\x9c\x56\xb4\xc8\x81\x27\xa8\x4e\x0d\x25\x86\xdb\xef\x00\x41\x34\x2f\xa9\xd2\x01\xaf\x9f\x98\x60\x26\x03\x5d\xe1\xb0\x48\x8f\x1a\x93\xa8\x1e\xaf\x64\x34\xcf\x16\x67\xde\xf3\x67\x7f\xfc\xf2\x4f\xdb\xa2\x65\x74\xee\x1b\x6b\x7a\x39\x61\xb8\xd9\xad\x9c\xad\x89\x28\x8c\x42\xc0\x0a\xad\xdd\x51\xc9\xaa\xb3\x49\xa9\xc5\xfa\x82\x8a\xa5\x1c\x4d\x34\x8a\x7d\x2f\x5f\x36\xa3\x5c\xba\x30\xf1\xe5\xe7\x4d\x64\x69\xbf\x32\xd1\x5d\xce\x02\x2d\x21\xd2\xc0\x2e\x54\xe0\xa6\xc5\x46\x14\x7c\x1b\x42\x84\x98\x8c\x31\xf5\x14\x56\xdf\xc1\x48\x72\x52\x5e\x6d\x0e\x50\x50\xc7\xe2\x02\xbd\xa6\xc6\x49\xaa\xf7\x41\x9f\xf5\x3f\x7d\xfa\xac\x85\x1c\xb6\x55\x93\x3f\x27\x32\x90\x53\x80\xcc\xff\x7c\x77\x3e\xfc\x6f\x31\xfc\xe9\x87\x4f\xf5\x2f\x4f\x87\x7f\xfe\x71\x70\xf6\xc3\x67\xa5\x3f\x7f\x78\xf2\xf7\x3f\x6c\xcb\x69\x69\xad\x81\x51\x4b\xd7\xc2\xa0\xab\x50\x67\x40\x5b\x1f\x3e\x7d\x9f\xe4\xe0\x52\xbc\x16\x41\x0a\xff\x7c\xe0\x2a\xe8\xa3\xad\x8a\x40\x1d\x23\xa8\xe3\xe6\xaf\x69\x8c\xe6\xef\xf5\xd8\x3b\xa9\x2c\xa7\x02\x4f\x78\x4e\x09\x88\x17\xdb\x06\xac\x99\x97\x22\x94\xc1\x4b\xaa\x39\x4b\xd6\xcd\x2c\x8e\x47\xfa\xa8\x87\xce\x12\xed\xf7\x2e\x32\xe4\xf4\xcb\x4e\xfe\xf8\xf4\x3b\xe6\x02\x60\x90\xa1\xfe\xed\x33\xf3\xd1\x93\xbf\x7f\xfa\xfd\xa8\xf5\xfb\x27\x9f\x8d\xa1\x51\x89\xb7\x7e\xf8\x6e\x58\x30\xd6\xe8\x87\xcf\x9e\xfc\xbd\xf4\xdd\x93\x3f\x1c\xa2\x2e\xd5\xa6\xf6\xa9\x6d\xa6\x45\x74\xed\x77\xbc\x73\x6b\xbf\x62\xae\xad\xfd\xaa\xa1\xa0\xf1\x96\x05\xb1\xe6\x0a\xdc\x04\xac\xff\xd0\x61\xb1\xbc\x31\xed\x8c\x8e\xfd\x70\x73\x65\x36\x15\x3e\xe9\x98\xf0\x97\x2c\x90\xee\x55\xf9\xb0\x96\xa4\xd0\x4c\x62\x75\x74\x14\xd0\xd3\xc0\xaa\xdf\x81\x7d\x33\x32\xaa\x3e\x72\xca\xcf\xc4\xea\x1d\x8a\xd9\x5d\xa1\x4a\x12\x4c\xb1\xd7\x42\x2e\xc5\x62\x82\xbe\x48\x56\x8d\x6f\x4e\xb4\xac\x2d\xce\xe7\x03\xa5\x31\xd4\x9b\x1a\xdd\x6a\xbe\x05\x78\xa3\x6a\x6f\xe9\xc3\x2e\xcb\x51\xc7\xe1\xf3\xdb\xdb\x57\x37\xef\xbd\xf3\x8b\x8b\xcb\xf7\x97\xef\xde\x9e\x5f\x79\xb7\xef\xcf\xdf\x7f\xb8\xf5\x5e\x5f\xbe\xba\xba\xa0\x9b\xc2\xe8\xfd\xae\x39\xbe\x47\xb5\x07\x76\xc6\x8d\xb9\xa4\x44\x47\x11\x01\xb2\x37\x79\xe4\x1d\x63\x1e\xc6\x31\x1a\x42\x89\xd4\x8a\x16\x25\xa6\x2f\xf5\xa5\x55\xce\xf1\xab\xdf\x2c\xfa\x54\x2f\x90\x27\x7d\x30\x6f\xd2\x8f\x1d\xab\x77\xce\x05\x0a\xeb\x97\xaf\xfa\x74\x64\xb5\x35\xa5\x66\xd4\x06\xb0\xeb\x4d\x6e\xe6\x5d\xfb\x38\x45\xf9\x8e\xe1\x7e\xd9\x06\xc7\x79\x8d\x5b\xc4\x11\x27\xdb\x76\x3b\x8c\x68\x37\x6a\x8c\x18\x9f\xfd\xa2\x83\xb5\xb3\x6e\x4c\xc1\x4f\x07\x94\xde\xae\xb7\xef\x8d\x16\x63\x94\x96\x30\xa2\x49\x0c\xcc\xb5\x59\x93\xaf\x60\xcb\x90\x7a\xc4\xdd\x98\xa3\x35\x9b\xed\x17\x79\x23\xff\x6a\xec\x13\xd7\x9b\x08\xad\x9a\x69\xad\x9c\xbc\x1e\xcc\x16\x01\xb7\xc3\x13\xaf\x06\xf7\x45\x91\xd2\x62\xe9\x07\xe5\x7b\x5e\x26\x2b\x0d\xed\x82\x21\x09\xf3\x6a\x31\x26\x7d\x81\x36\x08\xe9\x55\x6c\xfb\x5d\xfb\xdb\xb4\xad\x45\x00\x78\x5a\xfa\x64\xf2\x57\x22\x53\x75\x4c\x4b\xad\x2a\x1a\x36\xe1\xdb\x92\x8e\x72\xae\x4a\x8f\x82\x97\x79\xb1\x42\xc5\xe2\x65\xdc\x9e\xd7\xa7\x9d\xea\x51\xda\x82\x6f\x76\x38\x5b\x42\x90\x4b\x09\x94\xaa\xbf\xa1\xc7\xde\x52\x02\xce\x2d\x34\x50\xa9\x30\xa7\x23\x68\xd5\xa1\xfd\x52\x39\x56\x51\xaa\xc0\xd7\x14\x3d\xea\xac\xdd\x5c\x29\x10\xe6\x64\xca\xae\x17\x15\xab\x79\x4f\xa1\xa6\x02\x1d\xd9\x1c\x12\x8c\x5f\x20\x59\x6b\xb9\xb1\x4e\x9b\x9b\x7b\xbb\xbd\x3b\xcf\x44\x6b\x26\x61\x53\x55\xb9\x6d\xe7\xe6\x54\xf1\xae\x13\x0a\xd7\x30\x78\xd9\x7a\x3d\x66\xfd\xae\x78\xa5\x4b\xb1\xd1\x6a\x70\x6b\x28\xe2\x61\x8a\x32\xf2\xde\xe3\xc2\x76\xfa\x18\x66\xd8\x58\x04\xe5\xd0\xb5\xb9\x3d\x53\x43\xe0\x56\x45\x8e\xa5\x74\xbf\x2a\x75\xb0\x0a\xad\xa9\xb6\x1c\x57\x5d\xc3\x6c\x35\x16\xd3\x58\x93\xd5\x32\xc8\x00\x9f\x8a\x97\x4b\x8e\x77\x34\x96\x19\x6d\x44\x5e\xb3\xd7\x8c\x8b\x7d\x96\x2a\xb4\x9a\x77\x9d\x8c\x92\x7c\x58\xa0\x66\x14\xe5\x4a\xa2\xcd\x79\xd9\x7b\x0b\x87\xe9\xcd\xf8\xd2\x7d\x37\x5d\x97\x7b\x6c\x8a\xc4\xcd\x5a\x7d\x0f\x1c\x34\xd5\x85\x9e\xd7\x6b\xac\xee\xb2\xcf\x1a\x8c\xf7\x36\x91\x7a\x8d\x5d\x2a\xa7\x55\x04\x44\xbf\x03\xd6\x25\x48\xdd\x66\xa5\x31\x74\x0e\xa6\x5e\x97\x3a\x54\x8c\x2f\x4d\x55\xf4\xdc\x7a\x90\x6d\x4f\xbc\xd1\xee\x82\x77\xd5\x46\xda\xd2\x25\x4e\x9a\x8a\xbf\xac\x59\x14\x5c\xea\xa5\x30\xbb\x6c\xe9\x97\x9e\x75\x8a\xf8\x6c\xda\x6a\x6e\xaa\x0a\xb4\x6d\x40\xdf\x5c\x3c\x04\x10\x55\xa5\x5d\x57\x2b\x67\x9b\x1a\x4a\x0e\x35\xe0\x19\xf8\x8e\x2a\xc7\x5d\xca\xbe\x2e\xda\x57\x19\xd7\x1c\xe2\x89\x54\x4b\x53\x86\x5c\x2f\xf3\xca\x3d\x28\x79\xb5\xb5\xa0\xfb\xde\x44\x1f\xca\x7a\x5a\x2d\xe7\x8d\x7a\x55\xee\xd1\x88\x30\xe9\x10\x5d\x5f\xe8\xd0\x38\xb0\xb3\xd7\x07\x87\xb7\xe5\x1e\x15\x1c\x36\x2b\x59\x9b\x22\x49\xec\xcd\x55\x97\x8e\x2a\xd2\xb2\x57\x5e\x6c\x46\xf9\xb8\x10\xd8\xfb\xe0\x78\x27\x6d\x45\xa2\x1a\xa4\x85\xb1\x50\x37\x2a\x46\xa5\xc4\xbf\xe5\x65\xac\x72\x2b\x1e\x87\x68\x84\xb7\x63\xd5\xe6\xd2\x50\x0e\x75\xb9\x5b\xc5\x70\xf3\xcd\xd7\x6d\x05\x30\x98\x7a\xf9\xf2\x36\x0f\x43\xd1\x59\x2d\xee\xa6\xdc\xd6\x13\xf3\x79\x22\xe7\xc2\x94\xe9\x2f\xbd\x69\xc3\x75\x7d\x58\xfe\x05\x41\xcb\x15\xf0\x81\xbe\xfe\x69\x8e\x57\xac\x20\x9f\xc8\x85\xa2\x2b\x62\x73\xf2\x4c\xd1\x4d\x4c\xb7\xab\x71\xe4\x52\x57\xcc\xda\xcf\xfa\xfa\x91\x31\x71\x72\xbe\xc6\x8f\x56\x64\x61\x00\xba\x94\x65\xf3\xca\xbc\x52\x57\x9e\xc9\xed\x58\xac\x8b\x55\xcc\x08\x0e\x28\xda\xf7\x3c\xea\x51\xec\x8b\x9e\xc5\x2d\xcd\xe9\x02\xcc\x2c\x0f\xe8\xb2\xc3\x81\x10\xe5\xed\xe9\x80\xa6\x4e\xc9\xd9\x0f\x92\x5a\x3e\x96\x14\xf6\xa1\xf0\x53\xd1\xb5\xae\xc6\xe6\x80\xe3\xa5\x6d\xbc\x27\x3c\x55\xb9\xb4\x3f\x5d\x67\x02\x20\x13\x59\xd4\xf2\x3f\x24\x0f\x2f\x75\x4a\x17\x4c\xef\xb5\xeb\x2a\x5f\xaf\xf7\x71\x20\x44\x03\xee\x15\xdb\xb0\x58\x70\x33\x2b\x79\x28\xc4\xdb\x2a\x7d\x9a\x69\x1f\xd5\x59\xe1\x0d\xfa\x67\xa8\x27\x5f\xf3\x45\xc1\x5c\x35\x5f\x6e\x90\xbf\x4f\x40\xcd\xe6\xdc\x6d\x1d\x54\x6c\x7c\x75\xe9\x5a\x26\x5a\x2a\xaf\xe5\x1b\xea\x23\x66\x0c\x57\x74\xb2\x36\x70\x35\x05\xec\x6c\x4e\x8f\x0e\x21\x96\x1f\xc3\xdd\xfe\x25\xf3\x3d\xbd\x4a\xd9\x48\x82\x0f\x91\xca\xea\x91\x27\xbf\x02\xaf\x7f\xb4\xdd\x69\xab\x06\xf2\x13\x33\xeb\x27\x3b\x3e\x7a\xdd\x75\xa0\xb7\x8d\xf5\xd7\x23\xbb\xbc\x33\x9f\xa7\x17\xac\xd6\x78\x42\x9d\xe0\xa1\xd0\x01\x1a\xbe\x45\x26\x2a\x1e\x76\xb0\xd9\xa8\x38\x6b\x15\x68\xdd\x98\x4e\xba\xc1\xa1\xa5\xbe\x32\xf2\x97\xb1\x8a\xb2\x7d\x20\xd6\x9e\x0b\xd3\x13\x54\x5b\x9e\x69\x0f\x1b\xb4\xfc\xf3\x38\x2c\x6e\xf3\x0c\x29\xfd\x28\xb9\x97\xc3\x9c\xb3\x32\x86\x5c\xd5\xe0\xcc\xcb\x92\x5c\xee\xf4\x5e\x71\x95\x59\x77\x7f\x99\xb8\xb1\x49\xeb\xd7\x1b\xd5\x3d\xcd\x4a\x97\x5e\x7a\x2f\x6d\xed\xba\x53\x8f\xa3\x46\x29\x84\x32\x6c\x60\x6a\x86\x12\xc0\x92\x5d\x8e\x96\x2b\x03\xb6\xb2\xcf\x38\x33\xfd\x0e\x4f\x6a\xbf\xd8\x5c\x81\x21\x45\x55\x8e\x1a\x7b\xf1\x69\x79\x69\x61\x31\xa9\x94\xd2\xa0\x8b\x4f\xf2\x49\xb2\x5e\xde\x55\x67\x20\x79\x3f\xff\x72\xf4\x7f\xd6\x1d\x76\x0c\x7c\xe2\x00\x00")
 
 func deployManagedCommonAppsOpenClusterManagementIo_subscriptions_crd_v1YamlBytes() ([]byte, error) {
 	return bindataRead(
@@ -163,12 +163,12 @@ func deployManagedCommonAppsOpenClusterManagementIo_subscriptions_crd_v1Yaml() (
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "deploy/managed-common/apps.open-cluster-management.io_subscriptions_crd_v1.yaml", size: 25835, mode: os.FileMode(436), modTime: time.Unix(1661072854, 0)}
+	info := bindataFileInfo{name: "deploy/managed-common/apps.open-cluster-management.io_subscriptions_crd_v1.yaml", size: 57980, mode: os.FileMode(436), modTime: time.Unix(1792029813, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _deployManagedCommonAppsOpenClusterManagementIo_subscriptionstatuses_crd_v1alpha1Yaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb4\x56\xcd\x8e\x23\x35\x10\xbe\xf7\x53\x94\x96\xc3\x5e\xe8\xce\xae\x10\x12\xea\x1b\x1a\x38\xac\x80\xd5\x68\xb3\x3b\x17\xc4\xa1\x62\x57\x12\x33\xfe\xc3\x55\x8e\x08\x88\x77\x47\xb6\x3b\x49\x27\x93\xcc\xc2\x20\x72\x8a\xcb\x55\xf5\x55\x7d\xf5\xd3\xee\xfa\xbe\xef\x30\x9a\x07\x4a\x6c\x82\x1f\x01\xa3\xa1\xdf\x85\x7c\x39\xf1\xf0\xf8\x0d\x0f\x26\x2c\x76\x6f\xbb\x47\xe3\xf5\x08\x77\x99\x25\xb8\x0f\xc4\x21\x27\x45\xdf\xd1\xda\x78\x23\x26\xf8\xce\x91\xa0\x46\xc1\xb1\x03\x40\xef\x83\x60\x11\x73\x39\x02\xa8\xe0\x25\x05\x6b\x29\xf5\x1b\xf2\xc3\x63\x5e\xd1\x2a\x1b\xab\x29\x55\xe7\x07\xe8\xdd\x9b\xe1\xeb\xe1\x4d\x07\xa0\x12\x55\xf3\x8f\xc6\x11\x0b\xba\x38\x82\xcf\xd6\x76\x00\x1e\x1d\x8d\xc0\x79\xc5\x2a\x99\x58\x21\x04\x25\x33\xf1\x80\x31\xf2\x10\x22\xf9\x5e\xd9\xcc\x42\xa9\x77\xe8\x71\x43\x8e\xbc\x0c\x26\x74\x1c\x49\x95\x70\x36\x29\xe4\x58\xf2\x7c\x5e\xbd\x61\x4d\x09\xb4\xe4\x97\x33\xd8\x65\x85\xad\x97\xd6\xb0\xfc\x70\x43\xe1\x47\xc3\x52\x95\xa2\xcd\x09\xed\xf5\xd0\xab\x02\x6f\x43\x92\xf7\x27\xc8\xbe\x46\x98\x57\x7c\x02\x62\xe3\x37\xd9\x62\xba\xe6\xa5\x03\x60\x15\x22\x8d\x50\x7d\x44\x54\xa4\x3b\x80\x89\xdb\xea\xb3\x9f\xd8\xdb\xbd\x45\x1b\xb7\xf8\xb6\xf9\x54\x5b\x72\xd8\x20\x01\x0a\x1f\xdf\xde\xbf\x7b\xf8\x6a\x79\x26\x06\xd0\x74\xc4\xbb\x96\x26\xe8\xd2\x09\xc4\x20\x5b\x82\x16\x10\x84\x35\x44\x54\x8f\xb8\x21\xd0\x14\x6d\xd8\x17\x66\xf9\xe8\x31\xa6\x10\x29\x89\x39\xe4\xdb\x7e\xb3\x4e\x9c\x49\x2f\xf0\x5f\x97\x10\x9b\xd6\x19\xf0\x94\x2c\xe9\x29\xab\x12\x82\x6c\x0d\x43\xa2\x98\x88\xc9\xb7\xa6\x2c\x62\xf4\x10\x56\xbf\x92\x92\x01\x96\x94\x8a\x61\x29\x40\xb6\xba\xf4\xea\x8e\x92\x40\x22\x15\x36\xde\xfc\x71\xf4\xc6\x20\xa1\xc2\x58\x14\x62\x01\xe3\x85\x92\x47\x0b\x3b\xb4\x99\xbe\x04\xf4\x1a\x1c\xee\x21\x51\xf1\x0b\xd9\xcf\x3c\x54\x15\x1e\xe0\xa7\x90\x08\x8c\x5f\x87\x11\xb6\x22\x91\xc7\xc5\x62\x63\xe4\x30\x65\x2a\x38\x97\xbd\x91\xfd\xa2\x0e\x8c\x59\x65\x09\x89\x17\x9a\x76\x64\x17\x6c\x36\x3d\x26\xb5\x35\x42\x4a\x72\xa2\x05\x46\xd3\xd7\x60\x7d\xed\x82\xc1\xe9\x2f\xd2\x34\x97\xfc\xfa\x8c\x3c\xd9\x97\xbe\x60\x49\xc6\x6f\x66\x17\xb5\xa9\x9f\x61\xb9\xf4\x34\x18\x06\x9c\x4c\x5b\x16\x27\x32\x8b\xa8\xf0\xf1\xe1\xfb\xe5\x47\x38\x40\x37\xc2\x1b\xb7\x27\x55\x3e\xd1\x5c\x28\x32\x7e\x4d\xa9\x69\xae\x53\x70\xd5\x0b\x79\x1d\x83\xf1\x52\x0f\xca\x1a\xf2\x52\xda\xdc\x19\x29\xf5\xfb\x2d\x13\x4b\xa9\xc0\x00\x77\x75\xbd\xc0\x8a\x20\x47\x8d\x42\x7a\x80\x77\x1e\xee\xd0\x91\xbd\x43\xa6\xff\x9d\xe4\xc2\x26\xf7\x85\xbc\x7f\x46\xf3\x7c\x33\x5e\x2a\x37\x9e\x66\x17\x87\x95\xf0\x4c\x5d\x96\x93\xca\x8c\x5d\x40\x6b\x2b\x6f\xc7\x06\x98\x46\x8e\x34\xac\xf6\x6d\x28\x67\x33\x0b\x91\x12\x4c\x5b\xef\x0c\xe7\xfa\x4c\xd6\x9b\x36\xc9\x4f\xe4\x00\x46\xc8\x5d\x11\x3f\xb3\x32\x3e\x79\x23\x17\x6b\xe3\xb4\x32\xf0\xca\xd2\x18\xae\x78\xbf\x1d\x6a\xfb\xdd\x5a\x24\x97\x15\x78\x52\xae\xd3\xef\xe9\x7c\xfc\x0b\x63\x8b\x2c\x9f\x6a\x7f\x96\x8f\xd8\x2d\x37\xeb\x90\x1c\xca\x08\x45\xaf\x17\xe3\xe8\xa5\x70\x8e\x98\x71\x73\x13\xe7\xb3\xf6\xf5\xcb\xf0\x5f\x8c\xeb\x17\xe7\xc5\x1e\xe2\x16\xf9\xa6\xf5\x59\x23\xdd\xb7\xee\xb8\x2f\x06\x67\xcb\xbf\xb8\x28\x1b\xa9\xf6\xd0\xa4\xf5\xb2\x70\xca\xb6\x31\x89\xae\xd6\xbe\xbf\x28\xec\x15\x95\x1b\x93\x3d\xbf\xc4\x94\x70\xff\xf9\x75\xf0\x44\xc8\x65\x87\xea\x11\x24\xe5\x86\xcc\x12\x52\x29\xfb\x4c\x92\x57\xc7\x25\x70\x48\xa0\x4d\xd7\x08\x7f\xfe\xd5\x4d\x7f\xcb\x23\x4d\x29\x8a\x42\xfa\xfd\xe5\x2b\xe7\xd5\xab\xb3\x07\x4b\x3d\xaa\xe0\xb5\x69\x0f\x3a\xf8\xf9\x97\xae\x01\x93\x7e\x38\xbc\x2e\x8a\xf0\xef\x00\x00\x00\xff\xff\xd6\x1f\x83\xeb\x4a\x0a\x00\x00")
+var _deployManagedCommonAppsOpenClusterManagementIo_subscriptionstatuses_crd_v1alpha1Yaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xc5\x59\x5b\x73\xdb\xb8\x15\x7e\xd7\xaf\xc0\x38\x0f\xee\xce\x88\xd4\x3a\x69\xa6\x1d\xbd\x65\x93\x26\x75\x9b\x75\x3c\x76\x92\x3e\xec\xec\x03\x44\x1e\x49\xa8\x49\x82\x05\x48\xd9\xea\xce\xfe\xf7\x7e\xe7\x00\xa4\x28\x59\x96\x95\x8b\xb7\x7e\xb0\x2d\xe0\xe0\x5c\xbe\x73\x05\x34\x4a\x92\x64\xa4\x6b\xf3\x99\x9c\x37\xb6\x9a\x2a\xfc\x4f\x77\x0d\x55\xfc\xc9\xa7\x37\x7f\xf5\xa9\xb1\x93\xd5\xd9\xe8\xc6\x54\xf9\x54\xbd\x6e\x7d\x63\xcb\x2b\xf2\xb6\x75\x19\xbd\xa1\xb9\xa9\x4c\x03\xca\x51\x49\x8d\xce\x75\xa3\xa7\x23\xa5\x74\x55\xd9\x46\xf3\xb2\xe7\x8f\x4a\x65\xb6\x6a\x9c\x2d\x0a\x72\xc9\x82\xaa\xf4\xa6\x9d\xd1\xac\x35\x45\x4e\x4e\x98\x77\xa2\x57\x3f\xa6\x2f\xd3\x1f\x71\x22\x73\x24\xc7\x3f\x9a\x92\x7c\xa3\xcb\x7a\xaa\xaa\xb6\x28\xb0\x53\xe9\x92\xa6\xca\xb7\x33\x9f\x39\x53\x8b\x08\x48\x6a\x3d\xf9\x54\xd7\xb5\x4f\x6d\x4d\x55\x92\x15\xd0\x12\xb2\x4a\x5d\xe9\x05\x95\x54\x35\x10\x33\xf2\x35\x65\xac\xce\xc2\xd9\xb6\x66\x3b\x0f\x93\x07\x59\xd1\x80\x60\xfc\xf5\x40\xec\xb5\x88\x95\xcd\xc2\xf8\xe6\x9f\x0f\x10\xbc\xc7\x9e\x10\xd5\x45\xeb\x74\xb1\x5f\x75\x21\xf0\x4b\xeb\x9a\x8b\x8d\xc8\x44\x34\x04\xf5\x46\x90\x37\xd5\xa2\x2d\xb4\xdb\xc7\x05\x04\x3e\x83\x39\x53\x25\x3c\x6a\x9d\x51\x8e\xb5\x88\xad\xf0\x4c\x22\x7a\xab\x33\x5d\xd4\x4b\x7d\x16\x78\x66\x4b\x2a\x75\x10\xa9\x14\xe3\xf1\xea\xf2\xfc\xf3\x8b\xeb\xad\x65\xa5\x72\xea\xe5\xed\x33\x13\xfb\x88\x04\xf2\xaa\x59\x92\x0a\x0a\x29\x3b\x57\xd0\xe2\x06\x90\x62\xb7\x2e\xec\x9a\x91\xf5\x3d\xc7\xda\x41\x98\x6b\x4c\x67\x6f\xf8\x19\x44\xe2\x60\x75\x47\xfe\x29\xab\x18\xa8\xb6\x04\x47\x63\x29\x8f\x56\xb1\x0a\xcd\xd2\x78\xe5\xa8\x76\xe4\x21\x5e\xa2\x8a\x97\x35\x7e\xcf\xfe\x4d\x59\x93\xaa\x6b\x72\x7c\x90\x1d\xd0\x16\x39\xc7\x2a\x3e\x36\x38\x93\xd9\x45\x65\xfe\xdb\x73\x83\x0c\x2b\x62\x0a\xdd\x20\x2a\x95\xa9\x10\x34\x95\x2e\xd4\x4a\x17\x2d\x8d\xc1\x32\x57\xa5\x5e\xe3\x20\xf3\x55\x6d\x35\xe0\x20\x24\x3e\x55\x3f\x5b\x47\x38\x38\xb7\x53\xb5\x6c\x9a\xda\x4f\x27\x93\x85\x69\xba\x2c\xcb\x6c\x59\xb6\xc8\xa7\xf5\x44\x12\xc6\xcc\xda\xc6\x3a\x3f\xc9\x69\x45\xc5\xc4\x9b\x45\xa2\x5d\xb6\x34\x0d\xb8\xb7\x8e\x26\x80\x2a\x11\x65\x2b\x89\x82\xb4\xcc\x9f\xb9\x98\x97\xfe\x74\x0b\xbc\x66\xcd\x71\xe1\xc1\xb1\x5a\x0c\x36\x24\xa8\x0f\xa0\xcc\x31\xad\x80\x9e\x8e\x47\x83\x15\x1b\x30\x79\x89\xf1\xb8\xfa\xdb\xf5\x47\xd5\x89\x0e\x80\x07\x6c\x37\xa4\x7e\x03\x33\x43\x04\x04\xc8\x05\xca\xb9\xb3\xa5\x70\xa1\x2a\xaf\x2d\x30\x95\x0f\x59\x61\x70\x8a\xc3\xbc\x34\x0d\xfb\xef\x3f\x80\xaf\x61\x0f\xa4\xea\xb5\x94\x17\x35\x23\xd5\xd6\xa8\x38\x94\xa7\xea\xbc\xc2\x6a\x49\xc5\x6b\xed\xe9\xc9\x41\x66\x34\x7d\xc2\xe0\x1d\x07\xf3\xb0\x32\xee\x12\x07\x9c\x06\x1b\x5d\x49\x38\xe0\x97\xeb\x48\x32\x40\x57\xe9\xa2\x10\xdc\xfa\x00\x88\x29\x87\xd0\x9b\xad\x43\x52\x0e\x72\x56\x21\xf1\x54\xac\x7a\x5b\x72\xf6\xe7\xa4\x54\x70\xae\xc8\x94\xf7\xb5\xe5\x1e\xc1\x8e\x92\xaf\x77\xe9\x95\x76\x24\x8a\x94\xc6\x7b\x89\x1c\xed\x16\xd4\x84\x22\x1b\x28\xa2\x8c\x4e\xe3\x50\xfe\xee\x89\x81\x6b\xca\x3d\xd2\x1f\x84\x7f\xb8\xa9\x9d\xd3\xeb\x9d\xbd\xb9\x36\x05\xe5\x5d\x47\x7b\xcc\xae\xb7\xdb\xd4\xbd\x55\xb1\xd2\x79\x61\x27\xe6\x59\x36\xa0\x58\x2b\x3d\x6f\x24\xd6\xc9\x38\xb8\x07\xda\x91\x3f\xde\xa6\xed\x84\xfc\x20\xd1\x72\x45\x48\x1e\xaa\x90\x6a\x1c\xc0\xda\x54\x1e\xb9\x63\xdb\xc5\x52\x62\xde\x95\xa1\xc6\x41\x7e\x01\x78\xd7\xb6\xc5\x32\xb7\xbe\x46\x59\xa7\x4a\x9b\x9b\xf9\x3a\x86\x0a\xd8\x38\xc0\xdd\xd5\x41\x0c\x02\xea\x82\x6e\x95\xc4\x56\x57\x39\x19\x37\x31\x32\x37\x68\x2f\x68\x61\x0b\x76\x10\x65\x1a\x54\x4c\x04\x76\x73\x93\xb5\x45\xb3\x8e\xba\xce\xd8\x78\xce\xd9\xd6\x73\xe5\xbf\x5d\x52\xa5\xa8\x9c\x51\x9e\xe3\xa0\xa9\x14\x4a\x37\x8a\x81\x3a\x43\xd2\x2e\x2a\xcb\xf2\xe7\x86\x8a\x9c\xd7\xce\xb9\xa6\x22\x2c\xc1\x08\x55\xa2\x5a\xc7\x1d\xf0\x30\xd9\x52\x94\xe0\xbc\xc7\x00\x41\xe8\xa4\x40\x76\x69\x85\x01\x4e\xbe\x85\x69\x86\x1b\x21\x50\x19\xab\xce\x3b\x5d\x8b\xe0\xc2\xfc\x96\x59\x5d\xea\x26\xf0\x99\x59\xfc\x83\x6a\x84\x62\x8d\x8f\x60\x85\xca\x66\x44\x3d\x8d\xb4\x47\x49\x17\xe5\xc1\xf8\x39\xd7\x96\xb0\x19\xec\x59\x52\x51\x47\x55\xbd\x32\x65\x6d\x11\xcc\xb3\x82\xc4\xdd\x39\x3a\x05\x80\x36\x00\x44\xe8\xd4\x5c\xd4\xca\xcd\xca\xe4\x43\xa6\xa8\x56\xa5\x45\x03\xe9\x61\x91\x0d\x3f\x66\xb7\xb8\x80\x76\xad\x91\x85\x19\xf7\x7a\x09\x03\xa4\x35\x02\x27\x93\x12\x84\x71\xe3\x06\x46\x9e\x94\x48\xdf\xe0\x44\x65\x2b\x98\xc0\xcd\x89\x2b\x93\x7a\x25\x06\xff\x74\xc2\xfe\x3e\xf9\x74\xfe\x46\x50\x8b\x58\x85\x45\xce\x39\x25\xe7\x67\xd4\xf3\xc6\x66\x2a\xc2\x3e\x2e\x2d\x7c\x9b\xf5\x55\xf6\x96\x50\x5c\xa2\x73\xa1\xec\x96\x47\x71\xe2\x05\x43\x84\x48\xf4\x98\x74\xb8\x66\x0b\x5a\x12\x83\xd8\xfc\x29\x46\x0a\x07\x5c\xb0\x32\x06\xd3\x5c\x62\xb8\x19\x87\xbe\xdd\x1f\x51\xae\x2d\x76\x69\xb8\x1e\xc8\xd9\x71\x8c\x84\x52\xdf\x80\xc6\xc0\x28\xed\x72\x01\x19\x22\x9c\xb4\x67\x14\xc4\x1c\xb6\x80\x50\xe3\x97\x81\xe2\x4b\x64\x21\xb1\x2a\x7f\x4e\x61\x19\x75\x31\xd5\x47\x01\x7c\x88\x3e\x6d\xa0\x23\xa3\x66\x11\x14\xc0\x32\x2e\xe1\x54\xd7\x03\x19\x0b\xdd\xad\x43\x83\xba\xee\x92\x5c\x7d\xba\x7a\xcf\xac\x41\x04\xcc\xb8\x84\xe6\x2d\x72\x53\x97\x33\xb3\x68\xd1\x66\x42\x1e\xb7\xd2\x40\x65\x64\x00\x93\x38\x87\xb0\x44\x6e\x6d\x86\xbd\x1e\xda\x68\xe4\x3c\x88\x92\x0c\x3d\x2d\xc4\x06\xd7\x74\x74\x49\xa4\xfe\x9a\x55\xe2\x24\xc7\xa2\x8c\xb3\xe3\x4d\xfb\x6d\x6b\x84\xa3\x8c\x52\xe0\x3e\x98\x8a\x42\x4a\x53\x17\xe1\x70\x7a\x9b\x85\x28\x46\x15\x28\x68\xa5\x31\xf6\x2a\xf5\x32\x55\xff\xea\x9d\x4f\xda\x1b\xa0\x91\x2d\x75\x85\xd0\x37\xcd\x96\x43\xbb\xe2\x80\xbf\xc3\xfc\x96\xc4\x2d\x6c\x16\xc6\xff\x71\xec\xd1\x71\x76\xea\xce\xf0\x8f\x78\x47\xc3\xc7\xd0\xa2\x6a\x4b\x82\x19\xbe\x9b\xb4\x20\xe8\x8d\xad\x4e\x4f\x1b\xf1\xb5\xaa\x50\x95\xb8\x6e\x04\x41\x3c\xbb\xb5\x80\xc1\xc5\x64\xc3\x0a\x36\x03\x63\x18\x88\x42\x64\xc5\x5d\xf1\xd2\xc1\xe1\x89\xc8\xd4\x39\x03\xd0\x86\xd6\xd3\x29\x32\x8e\x3d\x07\xe8\xb3\xca\x85\xb8\xde\x22\x5d\x45\x0a\x27\x26\xfe\x89\x8c\xb5\x80\xc5\xc9\x90\xcc\x6d\x26\x3b\x00\x15\xf5\xd5\x85\x2c\xe4\x8a\x9c\x4a\x25\xa2\x3b\xdc\x58\x0a\x30\xe7\x91\xc7\x64\xd4\x17\x6c\x2f\xc1\xaa\x73\x69\x81\x1c\xec\xb4\x40\xd2\x38\x1d\xca\xfb\x60\x56\x59\xb6\xb3\x14\x73\xca\x84\x2f\x4a\xae\x22\xe0\xc7\x83\xc8\x64\x56\xd8\xd9\x84\x9d\x85\x90\x48\xce\xd2\xb3\xbf\x4c\x7a\x5e\x43\x56\xb8\xad\x4d\xa4\x14\xa4\x0b\xfb\xec\xfd\xcb\x17\x2f\x54\x7a\xba\xa7\xb7\x3c\xdc\xec\x0f\x8f\xe2\x0f\x74\x27\xf6\xc1\x4e\xa8\x45\x5c\x9a\xf4\x81\xf3\x07\x3b\xb6\xf4\xe6\xae\x6a\x1f\xa5\xc1\xe9\xf9\x3c\x76\xb5\x3e\x37\x71\x97\xcd\x68\x6b\xde\x97\x3e\x11\xa3\x01\x8b\x3c\xd7\x21\xfb\xc2\xde\x38\x44\x46\x9c\x76\x37\xf7\x01\x6e\xb2\x60\x16\xfa\xc0\x3f\xae\x3f\x5c\x4c\xde\xd9\xa0\x1b\xb2\x09\x6e\xf5\x32\xb6\xc9\xcd\x71\x8c\x41\x8b\x9b\x15\x0f\x60\x1e\x9c\x73\x9e\xd6\x28\x45\x56\x98\x39\x0a\x6d\x1a\xb9\x01\xa7\x5f\x9e\xff\xba\x13\x2e\x26\xa0\xd6\xcf\xce\x5d\x9b\x37\x3e\x18\xd3\x9f\x45\xee\x40\x51\x56\xa9\xb6\x79\x54\xfa\x56\x94\x6d\x38\x5d\x6c\x54\x16\xb3\x3a\xf7\x8a\xa9\x3a\xe1\x4c\x19\x88\xfe\x8d\x1b\xc0\xef\x27\xea\x4f\xb7\xd2\x70\xa4\x1f\x9c\x04\x81\xfd\x25\x47\x7a\x44\xf4\xe3\x46\xb0\xa4\x01\xe0\x59\x2c\x88\x5b\xb7\xcc\xed\x3c\x1b\xff\xc0\x7d\x05\xfa\x57\x76\x40\x2c\x2c\x18\xcf\x3e\x4f\x77\x15\x01\x06\xd0\x62\xdb\x2e\xee\x98\x74\xa7\x9e\x73\x31\x11\xcb\x60\xe3\x0f\xb1\xc0\xfa\x35\x28\xef\x98\x67\xc6\x4d\xaa\xea\x3b\xdf\x52\xaf\x30\xe2\xda\x32\x74\xab\x24\x5c\x0a\xd1\xab\x70\xd7\xb0\xf3\x1e\x4a\xf6\xaa\x96\xde\xba\x73\x05\xfc\xf8\xe1\xcd\x87\x69\x90\xc6\x6e\x5b\x54\x5d\xc9\x07\x1b\xd4\xca\x50\x49\xf9\xb2\x22\x3e\x67\x45\xda\xe0\x24\x88\xee\xaa\x63\xa8\xc6\xf3\x96\xaf\x0d\x7b\xf3\xed\xa8\x88\xbf\x7f\x27\x3b\x78\x3b\xdb\x4d\xb4\xff\xdb\xdd\xe7\x0b\x4c\x94\x47\x88\xa3\x4c\xbc\x18\xc4\xe0\x41\x13\x37\x75\x92\xad\xcc\x6d\xe6\xd9\xc0\x8c\xea\xc6\x4f\xb8\xa5\xaf\x0c\xdd\x4e\x6e\xad\x83\xe2\x8b\x84\x83\x2c\x09\x9e\xf7\x13\xb9\x7e\x4c\x9e\xc9\x9f\x6f\xb2\x48\xee\x30\xc7\x9b\x25\xe4\x7f\x84\x6d\x72\xb5\x9a\x7c\xb5\x69\x6e\x7b\x96\x3e\xce\xc0\xeb\x6e\x0a\xde\x39\xcd\xe9\x12\x46\xb8\xf8\x2a\x33\xa8\x70\xa5\xce\x43\x09\xc4\xfc\xf0\xe4\x61\xcc\x00\xb6\x8e\x65\xaf\x93\x38\x26\x24\x48\xf2\xa4\x1f\x63\xb3\xf5\x57\x23\xd6\x9a\x23\x13\x98\x47\xf3\x3f\x24\xb8\xa1\xd1\xd7\xc6\xf6\x03\xaf\x14\x8f\xdd\xa9\xe1\xab\x2b\x2a\x6d\x43\x8f\xdc\xa6\xdf\x75\x74\xd2\xe3\x00\x04\x66\xe9\x0e\x13\xf0\x00\x2e\xb2\x19\x20\x5a\x99\xe1\x6c\x51\x68\xcc\xc6\x73\x6a\x10\x4c\xb7\xe8\xb6\x59\x21\xef\x7e\xfc\x9a\x34\xfa\x02\x1b\x4d\xc9\x77\x92\x47\xb4\x3c\x2f\xfb\x8b\xcb\x76\x37\x0c\xa7\xfb\xc1\x1a\xd7\x7d\x6e\x72\xdd\x53\xc0\x93\xbf\x5c\x30\x04\xaf\x82\x50\x7e\x28\x7f\xc4\x8a\xf7\xdb\xd4\x32\xcc\xf2\x55\x6e\xf3\xd4\x12\x30\xed\xcc\xe0\x3b\xfc\x83\xa6\x84\x77\x86\xa9\xe2\xd9\x3e\x69\xc0\xee\x4b\x40\x67\x31\x6f\xd9\x73\x47\x6a\xdd\xd3\x3e\xa8\xb3\xc4\x41\xd4\x39\x54\x9c\xef\xab\x71\x45\x77\x08\x54\xce\x3a\x53\xd0\x11\x5a\x5f\xec\xd2\xef\xd3\x3c\x28\xed\x07\x4a\x8b\x9c\xef\xab\x79\xe7\xc1\xe9\x57\xbe\x39\x0d\x9f\xfa\x3f\xa1\xe4\xee\x3c\xf7\x6f\x9e\xfa\xf5\x9e\xc7\xfe\xf4\x49\x6e\x1d\x8f\x56\xe0\xa5\xf6\xc7\x5d\x18\xfe\x0e\xc2\xae\xf2\xf0\xa1\x07\x32\x59\x75\xd3\xfb\x98\x1f\x1d\xc2\xbc\xe7\xc3\xac\x3a\xac\x4c\x3e\x6c\x75\xaf\xe2\xf1\xf0\x53\x0c\x82\x8f\x1e\xe6\xa4\xf8\x24\x17\xef\xfd\xc1\x7a\x6c\x60\x1d\x29\x0e\x5d\x86\xdf\x67\xa6\x4f\x31\x11\x7e\xfb\xf0\xf5\x28\x87\x1a\xbe\x3f\x6e\x74\xbb\x0c\x3e\xbd\xe4\x03\x5b\xdf\x3c\x31\x0b\x76\xbc\x24\xc2\xe5\xb7\x78\x3e\xbe\x07\xed\xf5\x7d\xb2\xe3\xd8\xef\xd7\xb0\xbb\x18\x7e\xa4\xae\x5d\x75\x4d\x38\x26\xcd\x3b\xce\x07\x0c\x63\x46\x1e\x97\xa5\x39\x2e\xf9\x76\xd5\x7d\xeb\x78\x74\x6b\x3c\x00\x4a\x9f\x5e\xf7\x75\xd3\x79\x2e\x5f\x3e\xeb\xe2\xf2\x60\x55\x39\x08\xf9\x5e\x03\x37\xdd\x7e\x93\xdd\x3c\xcc\x16\xab\xf0\xec\xb3\x3d\x81\x8c\xd5\x0d\xad\x3b\x4c\xba\x77\x38\x7e\x35\x4a\x64\x8a\x01\x34\x4b\x2a\x4a\x47\xb5\x1d\x1d\xed\xaf\xbd\x1b\xf7\x16\x3d\x7f\xa9\x96\xe3\xea\xea\xda\x10\x0d\x1e\x93\x30\xa7\xe2\x60\xa5\x9d\xb9\xdd\x2f\x37\x42\xd9\x9e\xaa\xdf\x7e\x1f\xc5\x7f\xf9\x5b\xfb\x8c\x67\xca\xf8\x9d\xcd\xf0\x6b\xef\x93\x93\xad\x6f\xb0\xe5\x23\x7a\x5b\xc0\x1e\x5c\x7e\xf9\x75\x14\x04\x53\xfe\xb9\xfb\xba\x99\x17\xff\x07\x39\x88\x32\xcd\x5b\x20\x00\x00")
 
 func deployManagedCommonAppsOpenClusterManagementIo_subscriptionstatuses_crd_v1alpha1YamlBytes() ([]byte, error) {
 	return bindataRead(
@@ -183,7 +183,7 @@ func deployManagedCommonAppsOpenClusterManagementIo_subscriptionstatuses_crd_v1a
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "deploy/managed-common/apps.open-cluster-management.io_subscriptionstatuses_crd_v1alpha1.yaml", size: 2634, mode: os.FileMode(436), modTime: time.Unix(1646042069, 0)}
+	info := bindataFileInfo{name: "deploy/managed-common/apps.open-cluster-management.io_subscriptionstatuses_crd_v1alpha1.yaml", size: 8283, mode: os.FileMode(436), modTime: time.Unix(1792029813, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -125,6 +125,8 @@ type AnsibleJobStatus struct {
 	Conditions       []Condition `json:"conditions,omitempty"`
 	K8sJob           `json:"k8sJob,omitempty"`
 	Message          string `json:"message,omitempty"`
+	// Artifacts are the artifacts of the job, set by the set_stats module in the playbook
+	Artifacts json.RawMessage `json:"artifacts,omitempty"`
 }
 
 // +kubebuilder:object:root=true
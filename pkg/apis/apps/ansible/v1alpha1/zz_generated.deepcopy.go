@@ -129,6 +129,11 @@ func (in *AnsibleJobStatus) DeepCopyInto(out *AnsibleJobStatus) {
 		}
 	}
 	out.K8sJob = in.K8sJob
+	if in.Artifacts != nil {
+		in, out := &in.Artifacts, &out.Artifacts
+		*out = make(json.RawMessage, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnsibleJobStatus.
@@ -62,6 +62,14 @@ type Git struct {
 //HelmRepo provides the urls to retrieve the helm-chart
 type HelmRepo struct {
 	Urls []string `json:"urls,omitempty"`
+	// Mirrors of the helm repo, the urls starting with the URL of a mirror are retrieved with the secret of the mirror
+	Mirrors []HelmRepoMirror `json:"mirrors,omitempty"`
+}
+
+//HelmRepoMirror provides the secret of the urls of a helm repo mirror
+type HelmRepoMirror struct {
+	URL       string                  `json:"url"`
+	SecretRef *corev1.ObjectReference `json:"secretRef,omitempty"`
 }
 
 //Source holds the different types of repository
@@ -120,6 +128,10 @@ func (repo HelmReleaseRepo) Clone() HelmReleaseRepo {
 		InsecureSkipVerify:            repo.InsecureSkipVerify,
 		Source:                        repo.Source,
 		WatchNamespaceScopedResources: repo.WatchNamespaceScopedResources,
+		ReleaseName:                   repo.ReleaseName,
+		TargetNamespace:               repo.TargetNamespace,
+		TargetNamespaceLabels:         repo.TargetNamespaceLabels,
+		UpgradeCRDs:                   repo.UpgradeCRDs,
 	}
 }
 
@@ -129,6 +141,10 @@ func (repo HelmReleaseRepo) AltSourceToSource() HelmReleaseRepo {
 		Version:                       repo.Version,
 		Digest:                        repo.Digest,
 		WatchNamespaceScopedResources: repo.WatchNamespaceScopedResources,
+		ReleaseName:                   repo.ReleaseName,
+		TargetNamespace:               repo.TargetNamespace,
+		TargetNamespaceLabels:         repo.TargetNamespaceLabels,
+		UpgradeCRDs:                   repo.UpgradeCRDs,
 		AltSource:                     repo.AltSource,
 		SecretRef:                     repo.AltSource.SecretRef,
 		ConfigMapRef:                  repo.AltSource.ConfigMapRef,
@@ -166,6 +182,14 @@ type HelmReleaseRepo struct {
 	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
 	// WatchNamespaceScopedResources is used to enable watching namespace scope Helm chart resources
 	WatchNamespaceScopedResources bool `json:"watchNamespaceScopedResources,omitempty"`
+	// ReleaseName is the name of the helm release, the default is the name of the HelmRelease
+	ReleaseName string `json:"releaseName,omitempty"`
+	// TargetNamespace is the namespace the chart is installed in, the default is the namespace of the HelmRelease
+	TargetNamespace string `json:"targetNamespace,omitempty"`
+	// TargetNamespaceLabels are the labels of the target namespace when it is created
+	TargetNamespaceLabels map[string]string `json:"targetNamespaceLabels,omitempty"`
+	// UpgradeCRDs applies the CRDs of the chart crds folder before the release upgrades, helm only creates them on install
+	UpgradeCRDs bool `json:"upgradeCRDs,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -193,6 +217,24 @@ type HelmReleaseList struct {
 	Items           []HelmRelease `json:"items"`
 }
 
+// GetReleaseName returns the name of the helm release, the default is the name of the HelmRelease
+func (hr *HelmRelease) GetReleaseName() string {
+	if hr.Repo.ReleaseName != "" {
+		return hr.Repo.ReleaseName
+	}
+
+	return hr.GetName()
+}
+
+// GetTargetNamespace returns the namespace the chart is installed in, the default is the namespace of the HelmRelease
+func (hr *HelmRelease) GetTargetNamespace() string {
+	if hr.Repo.TargetNamespace != "" {
+		return hr.Repo.TargetNamespace
+	}
+
+	return hr.GetNamespace()
+}
+
 func init() {
 	SchemeBuilder.Register(&HelmRelease{}, &HelmReleaseList{})
 }
@@ -236,11 +278,31 @@ const (
 	ReasonUpgradeError        HelmAppConditionReason = "UpgradeError"
 	ReasonReconcileError      HelmAppConditionReason = "ReconcileError"
 	ReasonUninstallError      HelmAppConditionReason = "UninstallError"
+	ReasonCRDUpgradeError     HelmAppConditionReason = "CRDUpgradeError"
+)
+
+// HelmAppCRD is the result of the upgrade of a CRD of the chart
+type HelmAppCRD struct {
+	Name   string          `json:"name"`
+	Phase  HelmAppCRDPhase `json:"phase"`
+	Reason string          `json:"reason,omitempty"`
+}
+
+// HelmAppCRDPhase is the phase of a CRD upgrade
+type HelmAppCRDPhase string
+
+const (
+	// CRDUpdated means the CRD is created or updated
+	CRDUpdated HelmAppCRDPhase = "Updated"
+	// CRDSkipped means the CRD is not applied, the reason tells why
+	CRDSkipped HelmAppCRDPhase = "Skipped"
 )
 
 type HelmAppStatus struct {
 	Conditions      []HelmAppCondition `json:"conditions"`
 	DeployedRelease *HelmAppRelease    `json:"deployedRelease,omitempty"`
+	// CRDs are the CRDs of the chart applied or skipped by the last release upgrade
+	CRDs []HelmAppCRD `json:"crds,omitempty"`
 }
 
 func (s *HelmAppStatus) ToMap() (map[string]interface{}, error) {
@@ -121,6 +121,21 @@ func (in *HelmAppCondition) DeepCopy() *HelmAppCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelmAppCRD) DeepCopyInto(out *HelmAppCRD) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmAppCRD.
+func (in *HelmAppCRD) DeepCopy() *HelmAppCRD {
+	if in == nil {
+		return nil
+	}
+	out := new(HelmAppCRD)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HelmAppRelease) DeepCopyInto(out *HelmAppRelease) {
 	*out = *in
@@ -151,6 +166,11 @@ func (in *HelmAppStatus) DeepCopyInto(out *HelmAppStatus) {
 		*out = new(HelmAppRelease)
 		**out = **in
 	}
+	if in.CRDs != nil {
+		in, out := &in.CRDs, &out.CRDs
+		*out = make([]HelmAppCRD, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmAppStatus.
@@ -253,6 +273,13 @@ func (in *HelmReleaseRepo) DeepCopyInto(out *HelmReleaseRepo) {
 		*out = new(corev1.ObjectReference)
 		**out = **in
 	}
+	if in.TargetNamespaceLabels != nil {
+		in, out := &in.TargetNamespaceLabels, &out.TargetNamespaceLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmReleaseRepo.
@@ -273,6 +300,13 @@ func (in *HelmRepo) DeepCopyInto(out *HelmRepo) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Mirrors != nil {
+		in, out := &in.Mirrors, &out.Mirrors
+		*out = make([]HelmRepoMirror, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmRepo.
@@ -285,6 +319,26 @@ func (in *HelmRepo) DeepCopy() *HelmRepo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelmRepoMirror) DeepCopyInto(out *HelmRepoMirror) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(corev1.ObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmRepoMirror.
+func (in *HelmRepoMirror) DeepCopy() *HelmRepoMirror {
+	if in == nil {
+		return nil
+	}
+	out := new(HelmRepoMirror)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Source) DeepCopyInto(out *Source) {
 	*out = *in
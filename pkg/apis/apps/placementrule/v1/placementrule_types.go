@@ -38,6 +38,9 @@ type Placement struct {
 	GenericPlacementFields `json:",inline"`
 	PlacementRef           *corev1.ObjectReference `json:"placementRef,omitempty"`
 	Local                  *bool                   `json:"local,omitempty"`
+	// Hub deploys the subscription to the hub cluster itself, the hub controllers apply it without importing the hub
+	// as a managed cluster
+	Hub *bool `json:"hub,omitempty"`
 }
 
 // ClusterConditionFilter defines filter to filter cluster condition
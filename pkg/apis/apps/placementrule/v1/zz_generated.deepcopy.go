@@ -96,6 +96,11 @@ func (in *Placement) DeepCopyInto(out *Placement) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Hub != nil {
+		in, out := &in.Hub, &out.Hub
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
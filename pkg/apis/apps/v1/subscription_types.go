@@ -37,6 +37,8 @@ var (
 	AnnotationTopo = SchemeGroupVersion.Group + "/topo"
 	// AnnotationHosting defines the subscription hosting the resource
 	AnnotationHosting = SchemeGroupVersion.Group + "/hosting-subscription"
+	// AnnotationHubLocal marks the subscription created on the hub by a subscription with the hub placement
+	AnnotationHubLocal = SchemeGroupVersion.Group + "/hub-local"
 	// AnnotationChannelGeneration defines the channel generation
 	AnnotationChannelGeneration = SchemeGroupVersion.Group + "/channel-generation"
 	// AnnotationWebhookEnabled indicates webhook event notification is enabled
@@ -45,6 +47,19 @@ var (
 	AnnotationWebhookEventCount = SchemeGroupVersion.Group + "/webhook-event-count"
 	// AnnotationWebhookSecret defines webhook secret
 	AnnotationWebhookSecret = SchemeGroupVersion.Group + "/webhook-secret"
+	// AnnotationPackageOverridesSchema is the ConfigMap in the namespace of a channel with the JSON schema the package
+	// overrides of the subscriptions of the channel are validated against
+	AnnotationPackageOverridesSchema = SchemeGroupVersion.Group + "/package-overrides-schema"
+	// PackageOverridesSchemaKey is the key of the JSON schema in the package overrides schema ConfigMap
+	PackageOverridesSchemaKey = "schema.json"
+	// AnnotationHelmRepoMirrors lists the mirrors of a helm repo channel, a JSON list of HelmRepoMirror, the index and
+	// the charts are retrieved from the mirrors in order when the pathname of the channel isn't available
+	AnnotationHelmRepoMirrors = SchemeGroupVersion.Group + "/helm-repo-mirrors"
+	// AnnotationGitMirrors lists the mirror remotes of a git channel, a JSON list of GitMirror, the mirrors are cloned
+	// in order when the pathname of the channel can't be cloned
+	AnnotationGitMirrors = SchemeGroupVersion.Group + "/git-mirrors"
+	// AnnotationGitExcludePaths lists the comma separated path globs of a git channel that are never deployed
+	AnnotationGitExcludePaths = SchemeGroupVersion.Group + "/git-exclude-paths"
 	// AnnotationGithubPath defines webhook secret
 	AnnotationGithubPath = SchemeGroupVersion.Group + "/github-path"
 	// AnnotationGithubBranch defines webhook secret
@@ -63,6 +78,15 @@ var (
 	AnnotationGitTargetCommit = SchemeGroupVersion.Group + "/git-desired-commit"
 	// AnnotationGitTag defines Git repo revision tag
 	AnnotationGitTag = SchemeGroupVersion.Group + "/git-tag"
+	// AnnotationClusterRevisionPins pins managed clusters of a Git subscription to their own revision, a JSON map of
+	// ClusterRevisionPin by managed cluster name. It takes precedence over the revision of the subscription
+	AnnotationClusterRevisionPins = SchemeGroupVersion.Group + "/cluster-revision-pins"
+	// AnnotationGitPullRequest defines the GitHub pull request or GitLab merge request number to deploy as a preview
+	AnnotationGitPullRequest = SchemeGroupVersion.Group + "/git-pull-request"
+	// AnnotationGitProvider defines the Git provider, github or gitlab, it is detected from the repo URL by default
+	AnnotationGitProvider = SchemeGroupVersion.Group + "/git-provider"
+	// AnnotationGitCommitStatus enables posting the deployment status of the current commit per cluster to the Git provider
+	AnnotationGitCommitStatus = SchemeGroupVersion.Group + "/git-commit-status"
 	// AnnotationClusterAdmin indicates the subscription has cluster admin access
 	AnnotationClusterAdmin = SchemeGroupVersion.Group + "/cluster-admin"
 	// AnnotationChannelType indicates the channel type for subscription
@@ -74,24 +98,145 @@ var (
 	// AnnotationResourceReconcileOption is for reconciling existing resource
 	AnnotationResourceReconcileOption   = SchemeGroupVersion.Group + "/reconcile-option"
 	AnnotationResourceDoNotDeleteOption = SchemeGroupVersion.Group + "/do-not-delete"
+	// AnnotationResourceRecreateOnImmutableChange recreates the resource when its update changes an immutable field
+	AnnotationResourceRecreateOnImmutableChange = SchemeGroupVersion.Group + "/recreate-on-immutable-change"
+	// AnnotationKustomizeGenerated is the name without its hash suffix of a ConfigMap or Secret generated by a kustomize
+	// generator, the previous generated resources are kept until no workload references them
+	AnnotationKustomizeGenerated = SchemeGroupVersion.Group + "/kustomize-generated"
+	// AnnotationGeneratedRevisionHistoryLimit is the number of previous revisions of each kustomize generated ConfigMap
+	// or Secret kept for the rollbacks, 0 by default
+	AnnotationGeneratedRevisionHistoryLimit = SchemeGroupVersion.Group + "/generated-revision-history-limit"
+	// AnnotationRevisionNamespace is the prefix of the namespaces of the Git subscription revisions, each commit deploys
+	// the namespaced resources into a fresh <prefix>-<short commit> namespace, the previous namespace is pruned once the
+	// resources of the new revision are healthy
+	AnnotationRevisionNamespace = SchemeGroupVersion.Group + "/revision-namespace"
+	// AnnotationBlueGreen deploys the Deployments selected by a Service of the subscription with a blue/green strategy,
+	// the new revision is deployed alongside the previous one and the Service is switched to it once it is healthy
+	AnnotationBlueGreen = SchemeGroupVersion.Group + "/blue-green"
+	// AnnotationBlueGreenTimeout is how long the new color of a blue/green rollout has to become healthy and pass its
+	// smoke tests before the rollout is aborted, 5m by default
+	AnnotationBlueGreenTimeout = SchemeGroupVersion.Group + "/blue-green-timeout"
+	// AnnotationBlueGreenSmokeTest marks a Job of the subscription as a smoke test run against the new color of a
+	// blue/green rollout before the Service is switched to it
+	AnnotationBlueGreenSmokeTest = SchemeGroupVersion.Group + "/blue-green-smoke-test"
+	// AnnotationBlueGreenActiveColor is the color selected by a blue/green Service, set by the subscription agent
+	AnnotationBlueGreenActiveColor = SchemeGroupVersion.Group + "/blue-green-active-color"
+	// AnnotationBlueGreenAbortedHash is the hash of the last aborted revision of a blue/green Service, the aborted
+	// revision isn't rolled out again, set by the subscription agent
+	AnnotationBlueGreenAbortedHash = SchemeGroupVersion.Group + "/blue-green-aborted-hash"
+	// AnnotationBlueGreenHash is the hash of the revision of a blue/green Deployment, set by the subscription agent
+	AnnotationBlueGreenHash = SchemeGroupVersion.Group + "/blue-green-hash"
+	// LabelBlueGreenColor sits in the selector and the pod template of the blue/green Deployments and in the selector of
+	// their Services, it is blue or green
+	LabelBlueGreenColor = SchemeGroupVersion.Group + "/blue-green-color"
+	// AnnotationSetOwnerReference parents the resources in the subscription namespace to the subscription, it is set in
+	// the subscription for all its resources or in a resource
+	AnnotationSetOwnerReference = SchemeGroupVersion.Group + "/set-owner-reference"
+	// AnnotationExcludeClusters skips the resource on the managed clusters matching its label selector
+	AnnotationExcludeClusters = SchemeGroupVersion.Group + "/exclude-clusters"
+	// AnnotationClusterLabels is the JSON of the managed cluster labels, set by the hub in the propagated subscription
+	AnnotationClusterLabels = SchemeGroupVersion.Group + "/cluster-labels"
+	// AnnotationCheckImageArchitecture checks the architectures of the images against the nodes of the cluster
+	// before applying the resources of the subscription
+	AnnotationCheckImageArchitecture = SchemeGroupVersion.Group + "/check-image-architecture"
+	// AnnotationRestartOnConfigChange stamps the checksum of the ConfigMaps and Secrets of the subscription in the pod
+	// templates of the Deployments, StatefulSets and DaemonSets referencing them, so a config change rolls their pods
+	AnnotationRestartOnConfigChange = SchemeGroupVersion.Group + "/restart-on-config-change"
+	// AnnotationConfigChecksum is the checksum of the ConfigMaps and Secrets of the subscription referenced by a pod
+	// template, set by the subscription agent
+	AnnotationConfigChecksum = SchemeGroupVersion.Group + "/config-checksum"
+	// AnnotationContentLock records the content the declared versions of the subscription resolve to in its lock, and
+	// refuses to deploy a declared version resolving to a different content
+	AnnotationContentLock = SchemeGroupVersion.Group + "/content-lock"
+	// AnnotationConflictPrecedence is the precedence of the subscription over the other subscriptions deploying the same
+	// cluster-scoped resources, 0 by default. It is also set on the cluster-scoped resources the subscription deploys
+	AnnotationConflictPrecedence = SchemeGroupVersion.Group + "/conflict-precedence"
+	// AnnotationAllowAdoption allows the subscription, or a resource of the subscription, to update the resources managed
+	// by other controllers like Helm, Argo CD or Flux
+	AnnotationAllowAdoption = SchemeGroupVersion.Group + "/allow-adoption"
 	// AnnotationResourceReconcileLevel is for resource reconciliation frequency
 	AnnotationResourceReconcileLevel = SchemeGroupVersion.Group + "/reconcile-rate"
 	// AnnotationManualReconcileTime is the time user triggers a manual resource reconcile
 	AnnotationManualReconcileTime = SchemeGroupVersion.Group + "/manual-refresh-time"
+	// AnnotationRetryTime is the time the hub last retried the cluster failing to deploy the subscription, the agent
+	// reconciles the resources again when it changes like on a manual reconcile
+	AnnotationRetryTime = SchemeGroupVersion.Group + "/retry-time"
 	//LabelSubscriptionPause sits in subscription label to identify if the subscription is paused or not
 	LabelSubscriptionPause = "subscription-pause"
-	//LabelSubscriptionName is the subscription name
+	//LabelSubscriptionName is the subscription name, <namespace>.<name> of the hosting subscription on the deployed resources
 	LabelSubscriptionName = SchemeGroupVersion.Group + "/subscription"
+	// LabelApplication is the application of the subscription on the deployed resources, the app label of the subscription
+	LabelApplication = SchemeGroupVersion.Group + "/application"
+	// LabelCluster is the cluster the resources are deployed to
+	LabelCluster = SchemeGroupVersion.Group + "/cluster"
+	// LabelRevision is the revision of the channel the resources are deployed from
+	LabelRevision = SchemeGroupVersion.Group + "/revision"
 	// AnnotationHookType defines ansible hook job type - prehook/posthook
 	AnnotationHookType = SchemeGroupVersion.Group + "/hook-type"
+	// AnnotationHookScope defines where an ansible hook job runs - hub/cluster/decision-group, it is set in the hook job
+	AnnotationHookScope = SchemeGroupVersion.Group + "/hook-scope"
+	// AnnotationApplyRetries is the number of times a resource failing to apply is retried in the same apply, 0 by default
+	AnnotationApplyRetries = SchemeGroupVersion.Group + "/apply-retries"
+	// AnnotationApplyRetryBackoff is the wait before the first retry of a failed resource, doubled on each retry, 1s by default
+	AnnotationApplyRetryBackoff = SchemeGroupVersion.Group + "/apply-retry-backoff"
+	// AnnotationHookSchedule defines when an ansible hook job runs relative to the timewindow of the subscription -
+	// immediate/window-open/window-close, it is set in the hook job
+	AnnotationHookSchedule = SchemeGroupVersion.Group + "/hook-schedule"
+	// AnnotationHookScheduleLead is the duration an ansible hook job runs before the window opens or closes, e.g. 15m
+	AnnotationHookScheduleLead = SchemeGroupVersion.Group + "/hook-schedule-lead"
 	// AnnotationBucketPath defines s3 object bucket subfolder path
 	AnnotationBucketPath = SchemeGroupVersion.Group + "/bucket-path"
+	// AnnotationBucketLayout defines the layout of the s3 object bucket, flat by default or folders for a folder per package
+	AnnotationBucketLayout = SchemeGroupVersion.Group + "/bucket-layout"
 	// AnnotationManagedCluster identifies this is a deployable for managed cluster
 	AnnotationManagedCluster = SchemeGroupVersion.Group + "/managed-cluster"
 	// AnnotationHostingDeployable sits in templated resource, gives name of hosting deployable, legacy annotation
 	AnnotationHostingDeployable = SchemeGroupVersion.Group + "/hosting-deployable"
 	// AnnotationCurrentNamespaceScoped specifies to deloy resources into subscription namespace
 	AnnotationCurrentNamespaceScoped = SchemeGroupVersion.Group + "/current-namespace-scoped"
+	// AnnotationAutoGenerateApplication enables the hub to create and maintain an app.k8s.io Application for the subscription
+	AnnotationAutoGenerateApplication = SchemeGroupVersion.Group + "/auto-generate-application"
+	// AnnotationSubscriptions lists the subscriptions an Application is composed of, used by topology views
+	AnnotationSubscriptions = SchemeGroupVersion.Group + "/subscriptions"
+	// AnnotationApplicationGenerated marks an Application that has been generated from a subscription
+	AnnotationApplicationGenerated = SchemeGroupVersion.Group + "/generated-application"
+	// AnnotationHubOfHubs enables propagating the subscription to regional hubs which re-propagate it to their managed clusters
+	AnnotationHubOfHubs = SchemeGroupVersion.Group + "/hub-of-hubs"
+	// AnnotationHubOfHubsParent sits in the subscription propagated to a regional hub, gives the subscription on the top-level hub
+	AnnotationHubOfHubsParent = SchemeGroupVersion.Group + "/hub-of-hubs-parent"
+	// LabelRegionalHub sits in managed cluster label to identify the managed cluster is a regional hub
+	LabelRegionalHub = SchemeGroupVersion.Group + "/regional-hub"
+	// AnnotationHubName identifies which hub the subscription is reconciled against when the agent syncs to multiple hubs
+	AnnotationHubName = SchemeGroupVersion.Group + "/hub-name"
+	// AnnotationImageMirrorConfigMap gives the ConfigMap of the registry mirrors used to rewrite the images of the deployed resources
+	AnnotationImageMirrorConfigMap = SchemeGroupVersion.Group + "/image-mirror-configmap"
+	// AnnotationArgoRolloutsConfigMap gives the ConfigMap of the canary strategy the Deployments are converted to Argo
+	// Rollouts with
+	AnnotationArgoRolloutsConfigMap = SchemeGroupVersion.Group + "/argo-rollouts-configmap"
+	// LabelRequiredImagesOf sits in the ConfigMaps listing the images required by the subscription
+	LabelRequiredImagesOf = SchemeGroupVersion.Group + "/required-images-of"
+	// LabelCheckpointOf sits in the ConfigMaps holding the render and apply progress of the subscription
+	LabelCheckpointOf = SchemeGroupVersion.Group + "/checkpoint-of"
+	// LabelInjectCABundle sits in the webhook configurations and CRDs that get the CA bundle of the webhook service it gives
+	LabelInjectCABundle = SchemeGroupVersion.Group + "/inject-cabundle"
+	// AnnotationHelmVersionHold freezes the chart versions of a helm repo subscription to its status resolvedVersions when "true"
+	AnnotationHelmVersionHold = SchemeGroupVersion.Group + "/helm-version-hold"
+	// AnnotationRenderedManifests stores the manifests rendered for each cluster in the cluster namespaces of the hub when "true"
+	AnnotationRenderedManifests = SchemeGroupVersion.Group + "/rendered-manifests"
+	// AnnotationHelmClusterCapabilities renders the Helm charts of the rendered manifests of each cluster with the
+	// Kubernetes version and the API versions its agent reports when "true"
+	AnnotationHelmClusterCapabilities = SchemeGroupVersion.Group + "/helm-cluster-capabilities"
+	// AnnotationPromotionApproved approves the promotions waiting for a manual approval, it is a comma separated list of
+	// <decision group>=<commit>
+	AnnotationPromotionApproved = SchemeGroupVersion.Group + "/promotion-approved"
+	// AnnotationChangeFreezeOverride lets the hub propagate the subscription during the ChangeFreezes, it is a comma
+	// separated list of the names of the overridden ChangeFreezes, or * for all of them
+	AnnotationChangeFreezeOverride = SchemeGroupVersion.Group + "/change-freeze-override"
+	// AnnotationTemplateRefreshDebounce delays the propagation of the subscription after a change of a ConfigMap or a
+	// Secret read by its hub templates, e.g. 30s, the changes during the delay are propagated together
+	AnnotationTemplateRefreshDebounce = SchemeGroupVersion.Group + "/template-refresh-debounce"
+	// LabelRenderedManifestsOf sits in the ConfigMaps holding the manifests rendered for a cluster by the hub subscription
+	LabelRenderedManifestsOf = SchemeGroupVersion.Group + "/rendered-manifests-of"
 )
 
 const (
@@ -120,9 +265,54 @@ const (
 type PackageFilter struct {
 	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
 	Annotations   map[string]string     `json:"annotations,omitempty"`
-	// +kubebuilder:validation:Pattern=([0-9]+)((\.[0-9]+)(\.[0-9]+)|(\.[0-9]+)?(\.[xX]))$
+	// Select the source manifests or charts by their annotations, matchExpressions are supported
+	AnnotationSelector *metav1.LabelSelector `json:"annotationSelector,omitempty"`
+	// Regular expression the resource or chart name must match
+	NameRegex string `json:"nameRegex,omitempty"`
+	// Globs of the paths to include, relative to the git path or bucket folder. "**" matches any number of directories
+	IncludePaths []string `json:"includePaths,omitempty"`
+	// Globs of the paths to exclude, evaluated after includePaths
+	ExcludePaths []string `json:"excludePaths,omitempty"`
+	// Semver constraint of the chart versions, e.g. "~1.2", ">=1.2 <2" or "1.2.x || 1.4.x"
 	Version   string                       `json:"version,omitempty"`
 	FilterRef *corev1.LocalObjectReference `json:"filterRef,omitempty"`
+	// IncludePrereleases lets the version constraints match the prerelease chart versions
+	IncludePrereleases bool `json:"includePrereleases,omitempty"`
+	// Charts selects several charts of a helm repo channel, each with its own version, along with the spec.package chart
+	Charts []ChartFilter `json:"charts,omitempty"`
+}
+
+// HelmRepoMirror is a mirror of the helm repo of a channel
+type HelmRepoMirror struct {
+	// The URL of the mirror, the chart URLs of the index under the pathname of the channel are moved under it
+	URL string `json:"url"`
+	// The secret in the namespace of the channel with the credentials of the mirror, user and password or authHeader
+	// +optional
+	SecretRef *corev1.LocalObjectReference `json:"secretRef,omitempty"`
+}
+
+// ClusterRevisionPin is the Git revision a managed cluster is pinned to, the tag takes precedence over the commit
+type ClusterRevisionPin struct {
+	Commit string `json:"commit,omitempty"`
+	Tag    string `json:"tag,omitempty"`
+}
+
+// GitMirror is a mirror remote of the git repo of a channel
+type GitMirror struct {
+	// The URL of the mirror remote
+	URL string `json:"url"`
+	// The secret in the namespace of the channel with the credentials of the mirror, the same keys as the secret of
+	// the channel. The mirror is cloned without credentials if not set
+	// +optional
+	SecretRef *corev1.LocalObjectReference `json:"secretRef,omitempty"`
+}
+
+// ChartFilter selects a chart of a helm repo channel by its name and version
+type ChartFilter struct {
+	// Name of the chart
+	Name string `json:"name"`
+	// Semver constraint of the chart versions, the version of the package filter is used if empty
+	Version string `json:"version,omitempty"`
 }
 
 // PackageOverride describes rules for override
@@ -130,11 +320,52 @@ type PackageOverride struct {
 	runtime.RawExtension `json:",inline"`
 }
 
+// PatchType defines how a package patch is applied to the rendered resources
+type PatchType string
+
+const (
+	// PatchTypeStrategicMerge applies a strategic merge patch, a JSON merge patch is used for the kinds without a schema
+	PatchTypeStrategicMerge PatchType = "strategicMerge"
+	// PatchTypeJSON6902 applies a list of RFC 6902 JSON patch operations
+	PatchTypeJSON6902 PatchType = "json6902"
+	// PatchTypeJSONMergePatch applies a RFC 7386 JSON merge patch
+	PatchTypeJSONMergePatch PatchType = "jsonMergePatch"
+)
+
+// PatchTarget selects the rendered resources a patch is applied to, empty fields match everything
+type PatchTarget struct {
+	Group   string `json:"group,omitempty"`
+	Version string `json:"version,omitempty"`
+	Kind    string `json:"kind,omitempty"`
+	// the package name is used if empty
+	Name      string `json:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// PackagePatch describes a patch applied to the rendered resources of a package
+type PackagePatch struct {
+	// +kubebuilder:validation:Enum=strategicMerge;json6902;jsonMergePatch
+	Type   PatchType    `json:"type"`
+	Target *PatchTarget `json:"target,omitempty"`
+	// The patch in YAML or JSON. For json6902, it is the list of patch operations
+	Patch string `json:"patch"`
+}
+
 // Overrides field in deployable
 type Overrides struct {
 	PackageAlias     string            `json:"packageAlias,omitempty"`
 	PackageName      string            `json:"packageName"`
 	PackageOverrides []PackageOverride `json:"packageOverrides,omitempty"` // To be added
+	// Patches applied in order to the rendered resources, after the package overrides
+	Patches []PackagePatch `json:"patches,omitempty"`
+	// ReleaseName is the name of the helm release of the package, the default is the name of its HelmRelease
+	ReleaseName string `json:"releaseName,omitempty"`
+	// TargetNamespace is the namespace the helm chart of the package is installed in, the default is the subscription namespace
+	TargetNamespace string `json:"targetNamespace,omitempty"`
+	// TargetNamespaceLabels are the labels of the target namespace when it is created
+	TargetNamespaceLabels map[string]string `json:"targetNamespaceLabels,omitempty"`
+	// UpgradeCRDs applies the CRDs of the helm chart crds folder of the package before its release upgrades
+	UpgradeCRDs bool `json:"upgradeCRDs,omitempty"`
 }
 
 // AllowDenyItem is a group resources allowed or denied for deployment
@@ -175,6 +406,178 @@ type ClusterOverrides struct {
 	ClusterOverrides []ClusterOverride `json:"clusterOverrides"` // To be added
 }
 
+// OverrideRule selects the managed clusters a set of package overrides, or a Git branch and path, is propagated to, by
+// the cluster labels or the placement decision group of the cluster. Both must match when both are set
+type OverrideRule struct {
+	// the labels of the managed clusters
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+	// the decision group of the clusters in the placementRef, from the decision-group-name label of the PlacementDecisions
+	DecisionGroup string `json:"decisionGroup,omitempty"`
+	// The package overrides replace the subscription package overrides of the same packages
+	PackageOverrides []*Overrides `json:"packageOverrides,omitempty"`
+	// The Git branch the clusters subscribe to instead of the git-branch annotation of the subscription
+	GitBranch string `json:"gitBranch,omitempty"`
+	// The Git path the clusters subscribe to instead of the git-path annotation of the subscription
+	GitPath string `json:"gitPath,omitempty"`
+}
+
+// Promotion promotes the Git commit of a ring of clusters to the next ring once the ring was healthy on the commit during
+// its soak duration. The rings are decision groups of the placementRef
+type Promotion struct {
+	// The rings in the promotion order. The first ring deploys the subscription commit, the commits of the next rings are
+	// pinned in the status and promoted from the previous ring
+	//+kubebuilder:validation:MinItems=1
+	Rings []PromotionRing `json:"rings"`
+	// The number of failed clusters tolerated in a ring, the commit of the ring is still promoted to the next ring.
+	// No failed cluster is tolerated if neither maxFailures nor maxFailurePercentage is set
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxFailures *int32 `json:"maxFailures,omitempty"`
+	// The percentage of the clusters of a ring tolerated to fail, rounded down. The failed clusters must stay within
+	// both thresholds when maxFailures is also set
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxFailurePercentage *int32 `json:"maxFailurePercentage,omitempty"`
+}
+
+// PromotionRing is a ring of the promotion
+type PromotionRing struct {
+	// the decision group of the clusters of the ring, from the decision-group-name label of the PlacementDecisions
+	DecisionGroup string `json:"decisionGroup"`
+	// How long all the clusters of the previous ring must be deployed on a commit before it is promoted to this ring
+	// +optional
+	SoakDuration metav1.Duration `json:"soakDuration,omitempty"`
+	// The commits are only promoted to this ring within the timewindow
+	// +optional
+	TimeWindow *TimeWindow `json:"timewindow,omitempty"`
+	// The commits are only promoted to this ring once approved by the promotion-approved annotation or by the
+	// ApprovalRequest the hub creates for the commit
+	// +optional
+	ManualApproval bool `json:"manualApproval,omitempty"`
+	// How long the ApprovalRequest of a commit can be approved, it doesn't expire by default
+	// +optional
+	ApprovalTimeout metav1.Duration `json:"approvalTimeout,omitempty"`
+}
+
+// PromotionPhase defines the phase of a promotion ring
+type PromotionPhase string
+
+const (
+	// PromotionCurrent means the ring deploys the commit of the previous ring
+	PromotionCurrent PromotionPhase = "Current"
+	// PromotionSoaking means the previous ring is not yet healthy on its commit during the soak duration of the ring
+	PromotionSoaking PromotionPhase = "Soaking"
+	// PromotionWaitingForWindow means the commit of the previous ring waits for the timewindow of the ring
+	PromotionWaitingForWindow PromotionPhase = "WaitingForWindow"
+	// PromotionWaitingForApproval means the commit of the previous ring waits for the promotion-approved annotation or
+	// the approval of its ApprovalRequest
+	PromotionWaitingForApproval PromotionPhase = "WaitingForApproval"
+	// PromotionHalted means the commit of the previous ring is not promoted, more clusters of the previous ring failed
+	// than the failure thresholds of the promotion tolerate
+	PromotionHalted PromotionPhase = "Halted"
+)
+
+// PromotionRingStatus is the commit deployed by a promotion ring
+type PromotionRingStatus struct {
+	DecisionGroup string         `json:"decisionGroup"`
+	Phase         PromotionPhase `json:"phase,omitempty"`
+	// Commit is the commit deployed by the clusters of the ring
+	Commit string `json:"commit,omitempty"`
+	// PromotedTime is when the commit was promoted to the ring
+	PromotedTime metav1.Time `json:"promotedTime,omitempty"`
+	// HealthySince is when all the clusters of the ring were last found deployed, except the failed clusters tolerated
+	// by the failure thresholds, it is not set while a cluster is not
+	HealthySince *metav1.Time `json:"healthySince,omitempty"`
+	// FailedClusters are the clusters of the ring failing to deploy the commit, for a manual follow-up
+	FailedClusters []string `json:"failedClusters,omitempty"`
+	// PendingCommit is the commit of the previous ring waiting to be promoted to the ring
+	PendingCommit string `json:"pendingCommit,omitempty"`
+	// ApprovalRequest is the name of the ApprovalRequest of the pending commit
+	ApprovalRequest string `json:"approvalRequest,omitempty"`
+}
+
+// RetryPolicy retries the clusters failing to deploy the subscription with an exponential backoff
+type RetryPolicy struct {
+	// The wait before the first retry of a failed cluster, doubled after each retry, 5m by default
+	// +optional
+	Backoff metav1.Duration `json:"backoff,omitempty"`
+	// The maximum wait between two retries of a cluster, 1h by default
+	// +optional
+	MaxBackoff metav1.Duration `json:"maxBackoff,omitempty"`
+	// The number of retries of a failed cluster, the clusters are retried until they deploy the subscription if not set
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxRetries int32 `json:"maxRetries,omitempty"`
+	// The failed clusters are only retried in the timewindow of the subscription
+	// +optional
+	InTimeWindow bool `json:"inTimeWindow,omitempty"`
+}
+
+// ClusterRetryStatus is the retries of a cluster failing to deploy the subscription
+type ClusterRetryStatus struct {
+	Cluster string `json:"cluster"`
+	// Retries is the number of retries since the cluster failed, it is reset once the cluster is deployed
+	Retries int32 `json:"retries,omitempty"`
+	// FailedSince is when the cluster was found failed, it is not set while the cluster is deployed
+	FailedSince *metav1.Time `json:"failedSince,omitempty"`
+	// LastRetryTime is when the cluster was last retried
+	LastRetryTime *metav1.Time `json:"lastRetryTime,omitempty"`
+	// NextRetryTime is when the failed cluster is retried next, it is not set once its retries are exhausted
+	NextRetryTime *metav1.Time `json:"nextRetryTime,omitempty"`
+}
+
+// DependencyCondition defines the condition of a subscription dependency to wait for
+type DependencyCondition string
+
+const (
+	// DependencyDeployed waits for all the resources of the subscription to be deployed
+	DependencyDeployed DependencyCondition = "Deployed"
+	// DependencyHealthy also waits for the deployed workloads to be ready
+	DependencyHealthy DependencyCondition = "Healthy"
+)
+
+// SubscriptionDependency refers to a subscription that must be deployed before the dependent subscription is applied
+type SubscriptionDependency struct {
+	Name string `json:"name"`
+	// the namespace of the dependent subscription is used if empty
+	Namespace string `json:"namespace,omitempty"`
+	// The condition of the subscription to wait for, Deployed by default. Healthy also waits for the
+	// deployed workloads to be ready
+	// +kubebuilder:validation:Enum=Deployed;Healthy
+	Condition DependencyCondition `json:"condition,omitempty"`
+}
+
+// NamespaceCreation configures the target namespaces created by the subscription on the managed cluster
+type NamespaceCreation struct {
+	// Disabled fails the resources targeting a missing namespace instead of creating the namespace
+	Disabled bool `json:"disabled,omitempty"`
+	// Labels are set on the created namespaces, e.g. the pod-security or the istio-injection labels
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations are set on the created namespaces
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Prune deletes the namespaces created by the subscription when the subscription is deleted
+	Prune bool `json:"prune,omitempty"`
+}
+
+// MetadataPropagation selects the labels and annotations of the subscription set on all its deployed resources
+type MetadataPropagation struct {
+	// Labels selects the propagated labels, all the labels are propagated if not set
+	Labels *MetadataKeySelector `json:"labels,omitempty"`
+	// Annotations selects the propagated annotations, no annotation is propagated if not set
+	Annotations *MetadataKeySelector `json:"annotations,omitempty"`
+	// Overwrite sets the propagated labels and annotations even when the templates of the resources set them
+	Overwrite bool `json:"overwrite,omitempty"`
+}
+
+// MetadataKeySelector selects label or annotation keys, a key ending with '*' matches all the keys with its prefix
+type MetadataKeySelector struct {
+	// Include are the propagated keys, all the keys are propagated if empty
+	Include []string `json:"include,omitempty"`
+	// Exclude are the keys never propagated, even if included
+	Exclude []string `json:"exclude,omitempty"`
+}
+
 // SubscriptionSpec defines the desired state of Subscription
 type SubscriptionSpec struct {
 	Channel string `json:"channel"`
@@ -190,6 +593,8 @@ type SubscriptionSpec struct {
 	Placement *plrv1alpha1.Placement `json:"placement,omitempty"`
 	// for hub use only to specify the overrides when apply to clusters
 	Overrides []ClusterOverrides `json:"overrides,omitempty"`
+	// For hub use only, the package overrides propagated to the clusters matching the rules, evaluated in order
+	OverrideRules []OverrideRule `json:"overrideRules,omitempty"`
 	// help user control when the subscription will take affect
 	TimeWindow *TimeWindow `json:"timewindow,omitempty"`
 	// +optional
@@ -198,6 +603,21 @@ type SubscriptionSpec struct {
 	Deny          []*AllowDenyItem        `json:"deny,omitempty"`
 	// WatchHelmNamespaceScopedResources is used to enable watching namespace scope Helm chart resources
 	WatchHelmNamespaceScopedResources bool `json:"watchHelmNamespaceScopedResources,omitempty"`
+	// Subscriptions with a higher priority are reconciled first by the agent, e.g. after the agent restarts
+	Priority int32 `json:"priority,omitempty"`
+	// The subscriptions that must be deployed on the cluster before this subscription is applied
+	DependsOn []SubscriptionDependency `json:"dependsOn,omitempty"`
+	// For hub use only, promotes the Git commits through rings of decision groups
+	Promotion *Promotion `json:"promotion,omitempty"`
+	// For hub use only, retries the clusters failing to deploy the subscription
+	Retry *RetryPolicy `json:"retry,omitempty"`
+	// The labels and annotations of the missing target namespaces created on the managed cluster
+	NamespaceCreation *NamespaceCreation `json:"namespaceCreation,omitempty"`
+	// The labels and annotations of the subscription set on all its deployed resources
+	MetadataPropagation *MetadataPropagation `json:"metadataPropagation,omitempty"`
+	// The time the subscription may progress on a managed cluster, until all its resources are deployed and healthy,
+	// before it is stalled
+	ProgressDeadline *metav1.Duration `json:"progressDeadline,omitempty"`
 }
 
 // SubscriptionPhase defines the phasing of a Subscription
@@ -216,6 +636,85 @@ const (
 	SubscriptionPropagationFailed SubscriptionPhase = "PropagationFailed"
 )
 
+const (
+	// ConditionWaitingForDependency is true while the agent delays applying the subscription until the subscriptions
+	// it depends on are ready, the message names the blocking subscription
+	ConditionWaitingForDependency = "WaitingForDependency"
+	// ReasonDependencyNotReady is the reason of the WaitingForDependency condition while a dependency is not ready
+	ReasonDependencyNotReady = "DependencyNotReady"
+	// ReasonDependenciesReady is the reason of the WaitingForDependency condition once all the dependencies are ready
+	ReasonDependenciesReady = "DependenciesReady"
+
+	// ConditionQuotaExceeded is true while the hub doesn't propagate the subscription because it exceeds a
+	// SubscriptionQuota of its namespace, the message names the quota and the exceeded limit
+	ConditionQuotaExceeded = "QuotaExceeded"
+	// ReasonOverQuota is the reason of the QuotaExceeded condition while the subscription exceeds a quota
+	ReasonOverQuota = "OverQuota"
+	// ConditionPermissionDenied is true while the hub doesn't propagate the subscription because the user who created
+	// it isn't allowed to deploy some of its resource kinds to some of its clusters
+	ConditionPermissionDenied = "PermissionDenied"
+	// ReasonAccessReviewDenied is the reason of the PermissionDenied condition while a SubjectAccessReview is denied
+	ReasonAccessReviewDenied = "AccessReviewDenied"
+	// ReasonMissingUserIdentity is the reason of the PermissionDenied condition while the subscription has no user
+	// identity annotation to review the access of
+	ReasonMissingUserIdentity = "MissingUserIdentity"
+	// ConditionValuesSchemaInvalid is true while the hub doesn't propagate the subscription because the values of a
+	// helm chart don't match the values.schema.json of the chart, the message holds the schema errors
+	ConditionValuesSchemaInvalid = "ValuesSchemaInvalid"
+	// ReasonSchemaValidationFailed is the reason of the ValuesSchemaInvalid condition while the values are invalid
+	ReasonSchemaValidationFailed = "SchemaValidationFailed"
+	// ConditionChangeFrozen is true while the hub doesn't propagate the new revisions of the subscription to some of
+	// its clusters because of a ChangeFreeze, the message names the freezes and the number of the frozen clusters
+	ConditionChangeFrozen = "ChangeFrozen"
+	// ReasonChangeFreezeActive is the reason of the ChangeFrozen condition while a ChangeFreeze is active
+	ReasonChangeFreezeActive = "ChangeFreezeActive"
+	// ConditionAgentNotReporting is true while the agents of some clusters of the subscription haven't renewed their
+	// heartbeat lease on the hub for the agent heartbeat timeout, the message names the clusters
+	ConditionAgentNotReporting = "AgentNotReporting"
+	// ReasonAgentHeartbeatExpired is the reason of the AgentNotReporting condition while a heartbeat lease is expired
+	ReasonAgentHeartbeatExpired = "AgentHeartbeatExpired"
+	// ConditionUnsupportedAgentVersion is true while the agents of some clusters of the subscription have a version not
+	// supported by the hub or too old for the spec features of the subscription, the message names the clusters
+	ConditionUnsupportedAgentVersion = "UnsupportedAgentVersion"
+	// ReasonAgentVersionSkew is the reason of the UnsupportedAgentVersion condition while an agent version is skewed
+	ReasonAgentVersionSkew = "AgentVersionSkew"
+	// ConditionUnsupportedArchitecture is true while the agent doesn't apply some resources of the subscription because
+	// their images aren't built for the architectures of the nodes they run on, the message names the resources
+	ConditionUnsupportedArchitecture = "UnsupportedArchitecture"
+	// ReasonImageArchitectureMismatch is the reason of the UnsupportedArchitecture condition while an image mismatches
+	ReasonImageArchitectureMismatch = "ImageArchitectureMismatch"
+	// ReasonImageArchitecturesSupported is the reason of the UnsupportedArchitecture condition once all the images match
+	ReasonImageArchitecturesSupported = "ImageArchitecturesSupported"
+	// ConditionSharedResourceConflict is true while the agent doesn't apply some cluster-scoped resources of the
+	// subscription because they are deployed by other subscriptions with a higher or the same precedence, the message
+	// names the resources and their subscriptions
+	ConditionSharedResourceConflict = "SharedResourceConflict"
+	// ReasonOwnedByOtherSubscription is the reason of the SharedResourceConflict condition while a resource conflicts
+	ReasonOwnedByOtherSubscription = "OwnedByOtherSubscription"
+	// ReasonNoSharedResourceConflict is the reason of the SharedResourceConflict condition once the conflicts are resolved
+	ReasonNoSharedResourceConflict = "NoSharedResourceConflict"
+	// ConditionProgressing is true while the subscription with a progress deadline waits on the managed cluster for its
+	// dependencies or for its resources to be deployed and healthy, the message names what it waits for
+	ConditionProgressing = "Progressing"
+	// ReasonWaitingForResources is the reason of the Progressing and Stalled conditions while the subscription is not
+	// ready, before the deadline for the Stalled condition
+	ReasonWaitingForResources = "WaitingForResources"
+	// ReasonResourcesReady is the reason of the Progressing and Stalled conditions once all the resources are ready
+	ReasonResourcesReady = "ResourcesReady"
+	// ConditionStalled is true once the subscription has been progressing for longer than its progress deadline, the
+	// message names the dependency or the resource it is stuck on
+	ConditionStalled = "Stalled"
+	// ReasonProgressDeadlineExceeded is the reason of the Stalled condition while the subscription is stalled
+	ReasonProgressDeadlineExceeded = "ProgressDeadlineExceeded"
+	// ConditionLockMismatch is true while the agent refuses to deploy the subscription because some declared versions
+	// resolve to a different content than recorded in the lock of the subscription, the message names the versions
+	ConditionLockMismatch = "LockMismatch"
+	// ReasonResolvedContentChanged is the reason of the LockMismatch condition while a declared version mismatches
+	ReasonResolvedContentChanged = "ResolvedContentChanged"
+	// ReasonResolvedContentLocked is the reason of the LockMismatch condition once the declared versions match the lock
+	ReasonResolvedContentLocked = "ResolvedContentLocked"
+)
+
 // SubscriptionUnitStatus defines status of a unit (subscription or package)
 type SubscriptionUnitStatus struct {
 	// Phase are Propagated if it is in hub or Subscribed if it is in endpoint
@@ -277,9 +776,66 @@ type SubscriptionStatus struct {
 
 	// +optional
 	AnsibleJobsStatus AnsibleJobsStatus `json:"ansiblejobs,omitempty"`
+
+	// Conditions of the subscription on the managed cluster, e.g. WaitingForDependency
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 	// For endpoint, it is the status of subscription, key is packagename,
 	// For hub, it aggregates all status, key is cluster name
 	Statuses SubscriptionClusterStatusMap `json:"statuses,omitempty"`
+
+	// RollupSummary aggregates the deployment results of all clusters, including the clusters behind regional hubs
+	// +optional
+	RollupSummary *SubscriptionRollupSummary `json:"rollupSummary,omitempty"`
+
+	// ResolvedVersions are the chart versions the hub resolved for a helm repo subscription, key is the chart name
+	// +optional
+	ResolvedVersions map[string]string `json:"resolvedVersions,omitempty"`
+
+	// Promotion is the commit of each ring of the spec promotion
+	// +optional
+	Promotion []PromotionRingStatus `json:"promotion,omitempty"`
+
+	// Retries are the retries of the clusters failing to deploy the subscription with the spec retry
+	// +optional
+	Retries []ClusterRetryStatus `json:"retries,omitempty"`
+
+	// LastFetchTime is when the subscription on the managed cluster last fetched its source
+	// +optional
+	LastFetchTime *metav1.Time `json:"lastFetchTime,omitempty"`
+
+	// LastAppliedTime is when the subscription on the managed cluster last applied its resources
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+
+	// NextReconcileTime is when the subscription on the managed cluster fetches its source next, not set if the
+	// reconcile rate is off
+	// +optional
+	NextReconcileTime *metav1.Time `json:"nextReconcileTime,omitempty"`
+
+	// Revisions are the revisions resolved on the last fetch, key is the source type - git for the commit and
+	// helmrepo for the chart versions
+	// +optional
+	Revisions map[string]string `json:"revisions,omitempty"`
+
+	// GitRemote is the URL of the git remote the revision of the last fetch was cloned from, the pathname of the
+	// channel, one of its mirrors or the secondary channel
+	// +optional
+	GitRemote string `json:"gitRemote,omitempty"`
+}
+
+// SubscriptionRollupSummary defines the deployment result counts rolled up from regional hubs in hub-of-hubs mode
+type SubscriptionRollupSummary struct {
+	// Deployed provides the count of clusters the subscription deployed successfully to
+	Deployed int64 `json:"deployed"`
+	// InProgress provides the count of clusters the subscription is in the process of being deployed to
+	InProgress int64 `json:"inProgress"`
+	// Failed provides the count of clusters the subscription failed to deploy to
+	Failed int64 `json:"failed"`
+	// PropagationFailed provides the count of clusters the subscription failed to propagate to
+	PropagationFailed int64 `json:"propagationFailed"`
+	// Clusters provides the count of all clusters the subscription is deployed to
+	Clusters int64 `json:"clusters"`
 }
 
 // +genclient
@@ -295,6 +851,7 @@ type SubscriptionStatus struct {
 // +kubebuilder:printcolumn:name="Local placement",type="boolean",JSONPath=".spec.placement.local"
 // +kubebuilder:printcolumn:name="Time window",type="string",JSONPath=".spec.timewindow.windowtype"
 // +kubebuilder:resource:shortName=appsub
+// +kubebuilder:storageversion
 type Subscription struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -0,0 +1,155 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AnnotationAgentUpgradeImage is set by the hub on the application-manager ManagedClusterAddOn of the clusters of the
+// started waves, the addon deploys the agent image of the annotation
+const AnnotationAgentUpgradeImage = "apps.open-cluster-management.io/agent-upgrade-image"
+
+// AgentUpgradeSpec defines the agent rolled out and the waves of the clusters rolled out to
+type AgentUpgradeSpec struct {
+	// Image is the image of the application-manager agent rolled out to the clusters
+	Image string `json:"image"`
+
+	// Version is the version the agent of the image reports in its heartbeat lease, the agent of a cluster is upgraded
+	// once it reports the version
+	Version string `json:"version"`
+
+	// Waves are the waves of the clusters upgraded one after the other, a cluster belongs to the first wave selecting it
+	// +kubebuilder:validation:MinItems=1
+	Waves []AgentUpgradeWave `json:"waves"`
+
+	// Paused holds the next waves, the started waves keep upgrading
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// UpgradeTimeout is how long the agent of a cluster can take to report the version once its wave started, the
+	// upgrade stalls on the clusters not upgraded in time. It is 15 minutes if not set
+	// +optional
+	UpgradeTimeout metav1.Duration `json:"upgradeTimeout,omitempty"`
+}
+
+// AgentUpgradeWave is a wave of the clusters upgraded together
+type AgentUpgradeWave struct {
+	// Name is the name of the wave
+	Name string `json:"name"`
+
+	// ClusterSelector selects the managed clusters of the wave by their labels, all the clusters not selected by the
+	// previous waves if not set
+	// +optional
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+
+	// SoakDuration is how long the agents of the previous wave must be upgraded before the wave starts
+	// +optional
+	SoakDuration metav1.Duration `json:"soakDuration,omitempty"`
+}
+
+// AgentUpgradePhase defines the phase of an agent upgrade
+type AgentUpgradePhase string
+
+const (
+	// AgentUpgradeProgressing means the waves are being upgraded
+	AgentUpgradeProgressing AgentUpgradePhase = "Progressing"
+	// AgentUpgradePaused means the next waves are held by the paused spec
+	AgentUpgradePaused AgentUpgradePhase = "Paused"
+	// AgentUpgradeStalled means the agents of some clusters were not upgraded within the upgrade timeout, the next
+	// waves are held
+	AgentUpgradeStalled AgentUpgradePhase = "Stalled"
+	// AgentUpgradeCompleted means the agents of all the waves are upgraded
+	AgentUpgradeCompleted AgentUpgradePhase = "Completed"
+)
+
+// AgentUpgradeWavePhase defines the phase of a wave of an agent upgrade
+type AgentUpgradeWavePhase string
+
+const (
+	// AgentUpgradeWavePending means the previous wave is not upgraded
+	AgentUpgradeWavePending AgentUpgradeWavePhase = "Pending"
+	// AgentUpgradeWaveSoaking means the previous wave is not yet upgraded during the soak duration of the wave
+	AgentUpgradeWaveSoaking AgentUpgradeWavePhase = "Soaking"
+	// AgentUpgradeWaveUpgrading means the agents of the wave are rolled out the image
+	AgentUpgradeWaveUpgrading AgentUpgradeWavePhase = "Upgrading"
+	// AgentUpgradeWaveUpgraded means all the agents of the wave report the version
+	AgentUpgradeWaveUpgraded AgentUpgradeWavePhase = "Upgraded"
+)
+
+// AgentUpgradeWaveStatus is the status of a wave
+type AgentUpgradeWaveStatus struct {
+	Name  string                `json:"name"`
+	Phase AgentUpgradeWavePhase `json:"phase,omitempty"`
+	// Clusters is the number of the clusters of the wave
+	Clusters int `json:"clusters"`
+	// Upgraded is the number of the clusters of the wave whose agent reports the version
+	Upgraded int `json:"upgraded"`
+	// StalledClusters are the clusters whose agent was not upgraded within the upgrade timeout
+	// +optional
+	StalledClusters []string `json:"stalledClusters,omitempty"`
+	// StartedTime is when the image was rolled out to the wave
+	// +optional
+	StartedTime *metav1.Time `json:"startedTime,omitempty"`
+	// UpgradedTime is when all the agents of the wave were found upgraded
+	// +optional
+	UpgradedTime *metav1.Time `json:"upgradedTime,omitempty"`
+}
+
+// AgentUpgradeStatus defines the observed state of an agent upgrade
+type AgentUpgradeStatus struct {
+	Phase AgentUpgradePhase `json:"phase,omitempty"`
+	// Version is the version the waves are upgraded to, the waves start over once the version of the spec changes
+	// +optional
+	Version string                   `json:"version,omitempty"`
+	Waves   []AgentUpgradeWaveStatus `json:"waves,omitempty"`
+	// LastUpdateTime is when the hub last updated the status
+	// +optional
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope="Cluster"
+// +kubebuilder:resource:shortName=appagentupgrade
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.spec.version`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// AgentUpgrade rolls out an application-manager agent image to the managed clusters in waves. A wave starts once the
+// agents of the previous wave report the version in their heartbeat lease during its soak duration.
+type AgentUpgrade struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AgentUpgradeSpec   `json:"spec,omitempty"`
+	Status AgentUpgradeStatus `json:"status,omitempty"`
+}
+
+// AgentUpgradeList contains a list of AgentUpgrade
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type AgentUpgradeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AgentUpgrade `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AgentUpgrade{}, &AgentUpgradeList{})
+}
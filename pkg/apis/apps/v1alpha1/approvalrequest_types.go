@@ -0,0 +1,127 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ApprovalDecision is the decision of the approver of an ApprovalRequest
+type ApprovalDecision string
+
+const (
+	// ApprovalApproved lets the hub promote the commit of the request
+	ApprovalApproved ApprovalDecision = "Approved"
+	// ApprovalRejected keeps the commit of the request away from the gated clusters
+	ApprovalRejected ApprovalDecision = "Rejected"
+)
+
+// ApprovalPhase is the phase of an ApprovalRequest observed by the hub
+type ApprovalPhase string
+
+const (
+	// ApprovalPending means the request waits for a decision
+	ApprovalPending ApprovalPhase = "Pending"
+	// ApprovalPhaseApproved means the hub took the approval of the request
+	ApprovalPhaseApproved ApprovalPhase = "Approved"
+	// ApprovalPhaseRejected means the hub took the rejection of the request
+	ApprovalPhaseRejected ApprovalPhase = "Rejected"
+	// ApprovalExpired means the request had no decision before its expiration time, it can't be approved anymore
+	ApprovalExpired ApprovalPhase = "Expired"
+)
+
+// ApprovalRequestSpec defines the promotion waiting for a sign-off and the decision of the approver
+type ApprovalRequestSpec struct {
+	// Subscription is the name of the subscription of the request, in the namespace of the request
+	Subscription string `json:"subscription"`
+
+	// DecisionGroup is the promotion ring gated by the request
+	DecisionGroup string `json:"decisionGroup"`
+
+	// Commit is the Git commit waiting to be promoted to the ring
+	Commit string `json:"commit"`
+
+	// Clusters are the clusters of the ring when the request was created
+	// +optional
+	Clusters []string `json:"clusters,omitempty"`
+
+	// ExpirationTime is when the request can't be approved anymore, it doesn't expire if not set
+	// +optional
+	ExpirationTime *metav1.Time `json:"expirationTime,omitempty"`
+
+	// Decision is set by the approver to Approved or Rejected
+	// +optional
+	// +kubebuilder:validation:Enum=Approved;Rejected
+	Decision ApprovalDecision `json:"decision,omitempty"`
+
+	// Approver is the identity of the user or the system setting the decision
+	// +optional
+	Approver string `json:"approver,omitempty"`
+
+	// Reason is the comment of the approver on the decision
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// ApprovalRequestStatus defines the decision taken by the hub
+type ApprovalRequestStatus struct {
+	// Phase is Pending until the hub takes the decision or the request expires
+	// +optional
+	Phase ApprovalPhase `json:"phase,omitempty"`
+
+	// DecidedBy is the approver of the decision taken by the hub
+	// +optional
+	DecidedBy string `json:"decidedBy,omitempty"`
+
+	// DecisionTime is when the hub took the decision or found the request expired
+	// +optional
+	DecisionTime *metav1.Time `json:"decisionTime,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope="Namespaced"
+// +kubebuilder:resource:shortName=appsubapproval
+// +kubebuilder:printcolumn:name="Subscription",type=string,JSONPath=`.spec.subscription`
+// +kubebuilder:printcolumn:name="DecisionGroup",type=string,JSONPath=`.spec.decisionGroup`
+// +kubebuilder:printcolumn:name="Commit",type=string,JSONPath=`.spec.commit`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="DecidedBy",type=string,JSONPath=`.status.decidedBy`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ApprovalRequest is created by the hub when a promotion ring of a subscription requires a manual approval of a commit.
+// The commit is promoted to the clusters of the ring once the request is approved.
+type ApprovalRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ApprovalRequestSpec   `json:"spec,omitempty"`
+	Status ApprovalRequestStatus `json:"status,omitempty"`
+}
+
+// ApprovalRequestList contains a list of ApprovalRequest
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ApprovalRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ApprovalRequest `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ApprovalRequest{}, &ApprovalRequestList{})
+}
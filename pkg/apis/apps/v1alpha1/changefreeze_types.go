@@ -0,0 +1,88 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ChangeFreezeSpec defines the subscriptions and the clusters frozen and when
+type ChangeFreezeSpec struct {
+	// Namespaces are the namespaces of the frozen subscriptions, the subscriptions of all the namespaces are frozen
+	// if not set
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// ClusterSelector selects the frozen managed clusters by their labels, all the clusters are frozen if not set
+	// +optional
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+
+	// Start is when the freeze starts, it starts on its creation if not set
+	// +optional
+	Start *metav1.Time `json:"start,omitempty"`
+
+	// End is when the freeze ends, it lasts until it is deleted if not set
+	// +optional
+	End *metav1.Time `json:"end,omitempty"`
+
+	// Reason is the reason of the freeze, the incident or the holiday, shown in the conditions of the frozen subscriptions
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope="Cluster"
+// +kubebuilder:resource:shortName=appsubfreeze
+// +kubebuilder:printcolumn:name="Start",type=string,JSONPath=`.spec.start`
+// +kubebuilder:printcolumn:name="End",type=string,JSONPath=`.spec.end`
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.spec.reason`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ChangeFreeze blocks the new revisions of the subscriptions of its namespaces from propagating to its clusters while
+// it is active. The clusters keep reporting their status, a subscription overrides the freeze with the
+// change-freeze-override annotation.
+type ChangeFreeze struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ChangeFreezeSpec `json:"spec,omitempty"`
+}
+
+// IsActive checks if the freeze is active at the time
+func (f *ChangeFreeze) IsActive(now time.Time) bool {
+	if f.Spec.Start != nil && now.Before(f.Spec.Start.Time) {
+		return false
+	}
+
+	return f.Spec.End == nil || now.Before(f.Spec.End.Time)
+}
+
+// ChangeFreezeList contains a list of ChangeFreeze
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ChangeFreezeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ChangeFreeze `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ChangeFreeze{}, &ChangeFreezeList{})
+}
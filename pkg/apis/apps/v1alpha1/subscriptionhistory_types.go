@@ -0,0 +1,125 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SubscriptionHistoryEventType is the type of a significant event of a subscription
+type SubscriptionHistoryEventType string
+
+const (
+	// HistoryRolloutStarted is recorded when a new revision of the subscription is applied on a first cluster
+	HistoryRolloutStarted SubscriptionHistoryEventType = "RolloutStarted"
+	// HistoryRolloutFinished is recorded when the revision is deployed on all the clusters of the subscription
+	HistoryRolloutFinished SubscriptionHistoryEventType = "RolloutFinished"
+	// HistoryFailed is recorded when the subscription starts failing on some of its clusters
+	HistoryFailed SubscriptionHistoryEventType = "Failed"
+	// HistoryRecovered is recorded when the subscription is not failing on any cluster anymore
+	HistoryRecovered SubscriptionHistoryEventType = "Recovered"
+	// HistoryFrozen is recorded when a ChangeFreeze starts holding the new revisions of the subscription
+	HistoryFrozen SubscriptionHistoryEventType = "Frozen"
+	// HistoryUnfrozen is recorded when the new revisions of the subscription are not held anymore
+	HistoryUnfrozen SubscriptionHistoryEventType = "Unfrozen"
+)
+
+// SubscriptionHistoryEvent is a significant event of a subscription
+type SubscriptionHistoryEvent struct {
+	// Type of the event
+	// +kubebuilder:validation:Enum=RolloutStarted;RolloutFinished;Failed;Recovered;Frozen;Unfrozen
+	Type SubscriptionHistoryEventType `json:"type"`
+
+	// Time is when the hub detected the event
+	Time metav1.Time `json:"time"`
+
+	// Revision is the revision of the subscription the event is about
+	// +optional
+	Revision string `json:"revision,omitempty"`
+
+	// PreviousRevision is the revision rolled out before, for the RolloutStarted events
+	// +optional
+	PreviousRevision string `json:"previousRevision,omitempty"`
+
+	// Clusters is the number of the clusters of the event, like the failing clusters
+	// +optional
+	Clusters int `json:"clusters,omitempty"`
+
+	// Message describes the event
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// SubscriptionHistoryState is the state of the subscription the next aggregation of the reports is compared to
+type SubscriptionHistoryState struct {
+	// Revision is the last revision rolled out
+	// +optional
+	Revision string `json:"revision,omitempty"`
+
+	// Revisions are the revisions applied on the clusters
+	// +optional
+	Revisions []string `json:"revisions,omitempty"`
+
+	// RolledOut is true once the revision is deployed on all the clusters
+	// +optional
+	RolledOut bool `json:"rolledOut,omitempty"`
+
+	// FailedClusters is the number of the clusters the subscription failed on
+	// +optional
+	FailedClusters int `json:"failedClusters,omitempty"`
+
+	// Frozen is true while a ChangeFreeze holds the new revisions of the subscription
+	// +optional
+	Frozen bool `json:"frozen,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope="Namespaced"
+// +kubebuilder:resource:shortName=appsubhistory
+// +kubebuilder:printcolumn:name="Revision",type=string,JSONPath=`.state.revision`
+// +kubebuilder:printcolumn:name="RolledOut",type=boolean,JSONPath=`.state.rolledOut`
+// +kubebuilder:printcolumn:name="FailedClusters",type=integer,JSONPath=`.state.failedClusters`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// SubscriptionHistory is the timeline of the significant events of the subscription of the same name, for the
+// deployment timelines of the UIs. The hub compares the subscription reports of the clusters at each aggregation of
+// the reports and keeps the last events, the events don't expire like the Kubernetes events.
+type SubscriptionHistory struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Events are the last events of the subscription, the oldest first
+	// +optional
+	Events []SubscriptionHistoryEvent `json:"events,omitempty"`
+
+	// State is the state of the subscription at the last event
+	// +optional
+	State SubscriptionHistoryState `json:"state,omitempty"`
+}
+
+// SubscriptionHistoryList contains a list of SubscriptionHistory
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type SubscriptionHistoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SubscriptionHistory `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SubscriptionHistory{}, &SubscriptionHistoryList{})
+}
@@ -0,0 +1,131 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SubscriptionQueryResource selects a resource of the subscriptions
+type SubscriptionQueryResource struct {
+	// APIVersion of the resource, any version if not set
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+
+	// Kind of the resource
+	Kind string `json:"kind"`
+
+	// Name of the resource
+	Name string `json:"name"`
+
+	// Namespace of the resource, not set for the cluster scoped resources
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// SubscriptionQuerySpec defines the conditions the clusters of the subscriptions must match, all the conditions set
+// must be matched
+type SubscriptionQuerySpec struct {
+	// Subscription restricts the query to the subscription of that name, all the subscriptions in the namespace of
+	// the query are queried if not set
+	// +optional
+	Subscription string `json:"subscription,omitempty"`
+
+	// Revision matches the clusters where the subscription applied the revision
+	// +optional
+	Revision string `json:"revision,omitempty"`
+
+	// NotRevision matches the clusters where the subscription didn't apply the revision
+	// +optional
+	NotRevision string `json:"notRevision,omitempty"`
+
+	// Image matches the clusters where the subscription applied the container image
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Result matches the clusters by the result of the subscription
+	// +optional
+	Result SubscriptionResult `json:"result,omitempty"`
+
+	// FailedResource matches the clusters where the resource of the subscription failed
+	// +optional
+	FailedResource *SubscriptionQueryResource `json:"failedResource,omitempty"`
+}
+
+// SubscriptionQueryCluster is a cluster of a subscription matching the query
+type SubscriptionQueryCluster struct {
+	// Cluster is the name of the managed cluster
+	Cluster string `json:"cluster"`
+
+	// Subscription is the name of the subscription on the cluster
+	Subscription string `json:"subscription"`
+
+	// Result is the result of the subscription on the cluster
+	// +optional
+	Result SubscriptionResult `json:"result,omitempty"`
+
+	// Revision is the revision applied by the subscription on the cluster
+	// +optional
+	Revision string `json:"revision,omitempty"`
+}
+
+// SubscriptionQueryStatus defines the clusters matching the query
+type SubscriptionQueryStatus struct {
+	// Matched is the number of the clusters matching the query
+	// +optional
+	Matched int `json:"matched,omitempty"`
+
+	// Clusters are the clusters matching the query, sorted by cluster and subscription. The list is truncated to the
+	// first 1000 clusters
+	// +optional
+	Clusters []SubscriptionQueryCluster `json:"clusters,omitempty"`
+
+	// LastUpdateTime is when the clusters matching the query last changed
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope="Namespaced"
+// +kubebuilder:resource:shortName=appsubquery
+// +kubebuilder:printcolumn:name="Matched",type=integer,JSONPath=`.status.matched`
+// +kubebuilder:printcolumn:name="LastUpdateTime",type="date",JSONPath=".status.lastUpdateTime"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// SubscriptionQuery lists the clusters of the subscriptions of its namespace matching the query. The hub evaluates
+// the query on the subscription reports of the clusters at each aggregation of the reports.
+type SubscriptionQuery struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SubscriptionQuerySpec   `json:"spec,omitempty"`
+	Status SubscriptionQueryStatus `json:"status,omitempty"`
+}
+
+// SubscriptionQueryList contains a list of SubscriptionQuery
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type SubscriptionQueryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SubscriptionQuery `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SubscriptionQuery{}, &SubscriptionQueryList{})
+}
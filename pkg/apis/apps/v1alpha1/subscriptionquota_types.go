@@ -0,0 +1,99 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// QuotaConditionExceeded is the condition of a quota whose usage exceeds one of its limits
+	QuotaConditionExceeded = "Exceeded"
+)
+
+// SubscriptionQuotaSpec defines the limits of the subscriptions of a namespace, an unset limit is unlimited
+type SubscriptionQuotaSpec struct {
+	// MaxSubscriptions is the maximum number of subscriptions in the namespace
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxSubscriptions *int64 `json:"maxSubscriptions,omitempty"`
+
+	// MaxClusters is the maximum total number of clusters the subscriptions of the namespace are propagated to
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxClusters *int64 `json:"maxClusters,omitempty"`
+
+	// MaxResources is the maximum total number of resources deployed by the subscriptions of the namespace
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxResources *int64 `json:"maxResources,omitempty"`
+}
+
+// SubscriptionQuotaUsage is the usage of the subscriptions of a namespace
+type SubscriptionQuotaUsage struct {
+	// Subscriptions is the number of subscriptions in the namespace
+	Subscriptions int64 `json:"subscriptions"`
+
+	// Clusters is the total number of clusters the subscriptions of the namespace are propagated to
+	Clusters int64 `json:"clusters"`
+
+	// Resources is the total number of resources deployed by the subscriptions of the namespace
+	Resources int64 `json:"resources"`
+}
+
+// SubscriptionQuotaStatus defines the observed usage of the quota
+type SubscriptionQuotaStatus struct {
+	// Used is the current usage of the subscriptions of the namespace
+	// +optional
+	Used SubscriptionQuotaUsage `json:"used,omitempty"`
+
+	// Conditions of the quota, Exceeded is true if the usage exceeds one of the limits
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope="Namespaced"
+// +kubebuilder:resource:shortName=appsubquota
+// +kubebuilder:printcolumn:name="Subscriptions",type=integer,JSONPath=`.status.used.subscriptions`
+// +kubebuilder:printcolumn:name="Clusters",type=integer,JSONPath=`.status.used.clusters`
+// +kubebuilder:printcolumn:name="Resources",type=integer,JSONPath=`.status.used.resources`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// SubscriptionQuota limits the subscriptions of its namespace on the hub. The subscriptions over the quota are not
+// propagated to the managed clusters.
+type SubscriptionQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SubscriptionQuotaSpec   `json:"spec,omitempty"`
+	Status SubscriptionQuotaStatus `json:"status,omitempty"`
+}
+
+// SubscriptionQuotaList contains a list of SubscriptionQuota
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type SubscriptionQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SubscriptionQuota `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SubscriptionQuota{}, &SubscriptionQuotaList{})
+}
@@ -0,0 +1,67 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope="Namespaced"
+// +kubebuilder:resource:shortName=appsubresources
+// +kubebuilder:printcolumn:name="Subscription",type=string,JSONPath=`.subscription`
+// +kubebuilder:printcolumn:name="Page",type=integer,JSONPath=`.page`
+// +kubebuilder:printcolumn:name="Pages",type=integer,JSONPath=`.pages`
+// +kubebuilder:printcolumn:name="Total",type=integer,JSONPath=`.total`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// SubscriptionResourcePage is a page of the resources deployed by a subscription. The hub splits the resources of each
+// subscription in pages of up to 500 resources, named <subscription>-resources-<page>
+type SubscriptionResourcePage struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Subscription is the name of the subscription of the resources
+	Subscription string `json:"subscription"`
+
+	// Page is the number of the page, starting at 1
+	Page int `json:"page"`
+
+	// Pages is the number of pages of the subscription
+	Pages int `json:"pages"`
+
+	// Total is the number of resources of the subscription
+	Total int `json:"total"`
+
+	// Resources are the resources of the page, sorted by API version, kind, namespace and name
+	// +optional
+	Resources []corev1.ObjectReference `json:"resources,omitempty"`
+}
+
+// SubscriptionResourcePageList contains a list of SubscriptionResourcePage
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type SubscriptionResourcePageList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SubscriptionResourcePage `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SubscriptionResourcePage{}, &SubscriptionResourcePageList{})
+}
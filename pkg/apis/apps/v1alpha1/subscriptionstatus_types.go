@@ -42,6 +42,25 @@ type SubscriptionStatusList struct {
 
 // SubscriptionClusterStatusMap defines the status of packages in a cluster.
 type SubscriptionClusterStatusMap struct {
+	// Revision is the Git commit or the chart versions of the applied packages
+	Revision string `json:"revision,omitempty"`
+	// Images are the container images of the applied packages
+	Images []string `json:"images,omitempty"`
+	// Revisions are the revisions resolved on the last fetch, key is the source type - git or helmrepo
+	Revisions map[string]string `json:"revisions,omitempty"`
+	// GitRemote is the URL of the git remote the revision of the last fetch was cloned from
+	GitRemote string `json:"gitRemote,omitempty"`
+	// LastFetchTime is when the appsub last fetched its source
+	LastFetchTime *metav1.Time `json:"lastFetchTime,omitempty"`
+	// LastAppliedTime is when the appsub last applied its packages
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+	// NextReconcileTime is when the appsub fetches its source next
+	NextReconcileTime *metav1.Time `json:"nextReconcileTime,omitempty"`
+	// FailedResources are the packages failing to apply after their retries
+	FailedResources []corev1.ObjectReference `json:"failedResources,omitempty"`
+	// CreatedNamespaces are the missing target namespaces created by the appsub
+	CreatedNamespaces []string `json:"createdNamespaces,omitempty"`
+
 	SubscriptionStatus []SubscriptionUnitStatus `json:"packages,omitempty"`
 }
 
@@ -54,6 +73,8 @@ type SubscriptionUnitStatus struct {
 	Phase          PackagePhase `json:"phase,omitempty"`
 	Message        string       `json:"message,omitempty"`
 	LastUpdateTime metav1.Time  `json:"lastUpdateTime"`
+	// Hash is the hash of the applied package manifest, it changes with the revisions changing the package
+	Hash string `json:"hash,omitempty"`
 }
 
 // PackagePhase defines the phasing of a Package
@@ -113,6 +134,31 @@ type SubscriptionReportResult struct {
 
 	// Result indicates the outcome of the subscription deployment
 	Result SubscriptionResult `json:"result,omitempty"`
+
+	// Revision is the Git commit or the chart versions applied by the subscription on the cluster
+	// +optional
+	Revision string `json:"revision,omitempty"`
+
+	// Images are the container images applied by the subscription on the cluster
+	// +optional
+	Images []string `json:"images,omitempty"`
+
+	// FailedResources are the resources of the subscription failing on the cluster
+	// +optional
+	FailedResources []corev1.ObjectReference `json:"failedResources,omitempty"`
+
+	// Stalled is what the subscription is stuck on after progressing on the cluster for longer than its progress deadline
+	// +optional
+	Stalled string `json:"stalled,omitempty"`
+
+	// Conflict is the hub taking precedence over this hub for the subscription propagated by both to the cluster, primary
+	// for the hub of the agent's hub-cluster-configfile
+	// +optional
+	Conflict string `json:"conflict,omitempty"`
+
+	// Retries is the number of retries of the cluster by the hub since the subscription failed on it
+	// +optional
+	Retries int32 `json:"retries,omitempty"`
 }
 
 // SubscriptionReportType has one of the following values:
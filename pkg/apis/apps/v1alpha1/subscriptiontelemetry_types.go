@@ -0,0 +1,115 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SubscriptionTelemetryName is the name of the single SubscriptionTelemetry of the hub
+const SubscriptionTelemetryName = "subscription-telemetry"
+
+// TelemetryCount is the number of the objects of a kind, a version or a type
+type TelemetryCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// TelemetryErrorSignature is a redacted error message and the number of the subscriptions failing with it. The names,
+// the namespaces, the URLs, the addresses and the numbers of the messages are redacted
+type TelemetryErrorSignature struct {
+	Signature string `json:"signature"`
+	Count     int    `json:"count"`
+}
+
+// SubscriptionTelemetrySummary summarizes the subscriptions, the channels and the agents across the hub
+type SubscriptionTelemetrySummary struct {
+	// HubVersion is the version of the hub controllers
+	HubVersion string `json:"hubVersion,omitempty"`
+
+	// Subscriptions is the number of the hub subscriptions
+	Subscriptions int `json:"subscriptions"`
+
+	// SubscriptionPhases are the number of the hub subscriptions of each phase
+	// +optional
+	SubscriptionPhases []TelemetryCount `json:"subscriptionPhases,omitempty"`
+
+	// SubscriptionConditions are the number of the hub subscriptions with each condition true
+	// +optional
+	SubscriptionConditions []TelemetryCount `json:"subscriptionConditions,omitempty"`
+
+	// Channels is the number of the channels
+	Channels int `json:"channels"`
+
+	// ChannelTypes are the number of the channels of each type
+	// +optional
+	ChannelTypes []TelemetryCount `json:"channelTypes,omitempty"`
+
+	// Clusters is the number of the managed clusters reporting the subscriptions
+	Clusters int `json:"clusters"`
+
+	// ClusterResults are the number of the subscription deployments on the clusters of each result
+	// +optional
+	ClusterResults []TelemetryCount `json:"clusterResults,omitempty"`
+
+	// AgentVersions are the number of the agents of each version reported in their heartbeat lease
+	// +optional
+	AgentVersions []TelemetryCount `json:"agentVersions,omitempty"`
+
+	// ErrorSignatures are the most frequent redacted errors of the failed hub subscriptions
+	// +optional
+	ErrorSignatures []TelemetryErrorSignature `json:"errorSignatures,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope="Cluster"
+// +kubebuilder:resource:shortName=appsubtelemetry
+// +kubebuilder:printcolumn:name="HubVersion",type=string,JSONPath=`.summary.hubVersion`
+// +kubebuilder:printcolumn:name="Subscriptions",type=integer,JSONPath=`.summary.subscriptions`
+// +kubebuilder:printcolumn:name="Channels",type=integer,JSONPath=`.summary.channels`
+// +kubebuilder:printcolumn:name="Clusters",type=integer,JSONPath=`.summary.clusters`
+// +kubebuilder:printcolumn:name="LastUpdateTime",type="date",JSONPath=".lastUpdateTime"
+
+// SubscriptionTelemetry is the redacted summary of the subscriptions of the hub for the support bundles. The
+// appsubsummary controller writes the single subscription-telemetry object while the telemetry summary is enabled,
+// it holds no name, namespace or URL of the subscriptions.
+type SubscriptionTelemetry struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Summary summarizes the subscriptions, the channels and the agents of the hub
+	// +optional
+	Summary SubscriptionTelemetrySummary `json:"summary,omitempty"`
+
+	// LastUpdateTime is when the summary was last updated
+	// +optional
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// SubscriptionTelemetryList contains a list of SubscriptionTelemetry
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type SubscriptionTelemetryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SubscriptionTelemetry `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SubscriptionTelemetry{}, &SubscriptionTelemetryList{})
+}
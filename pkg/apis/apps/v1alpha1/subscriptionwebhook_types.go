@@ -0,0 +1,119 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// WebhookSecretToken is the key of the webhook secret with the bearer token of the endpoint
+	WebhookSecretToken = "token"
+	// WebhookSecretUser is the key of the webhook secret with the basic auth user of the endpoint
+	WebhookSecretUser = "user"
+	// WebhookSecretPassword is the key of the webhook secret with the basic auth password of the endpoint
+	WebhookSecretPassword = "password"
+	// WebhookSecretHMACKey is the key of the webhook secret with the key signing the posted documents
+	WebhookSecretHMACKey = "hmacKey"
+
+	// WebhookResultRemoved is the result posted when the subscription is removed from a cluster
+	WebhookResultRemoved = "removed"
+)
+
+// SubscriptionWebhookSpec defines the endpoint the status changes of the subscriptions are posted to
+type SubscriptionWebhookSpec struct {
+	// URL of the endpoint the status change documents are posted to
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+
+	// Subscription restricts the webhook to the subscription of that name, the status changes of all the
+	// subscriptions in the namespace of the webhook are posted if not set
+	// +optional
+	Subscription string `json:"subscription,omitempty"`
+
+	// Results restricts the webhook to the status changes to these results, all the changes are posted if empty
+	// +optional
+	Results []SubscriptionResult `json:"results,omitempty"`
+
+	// SecretRef is the secret in the namespace of the webhook with the credentials of the endpoint, the token key is
+	// sent as a bearer token, the user and password keys as basic auth, and the documents are signed with the
+	// hmacKey key in the X-Subscription-Signature header
+	// +optional
+	SecretRef *corev1.LocalObjectReference `json:"secretRef,omitempty"`
+
+	// InsecureSkipVerify skips the verification of the endpoint certificate
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// SubscriptionWebhookStatus defines the deliveries of the status changes to the endpoint
+type SubscriptionWebhookStatus struct {
+	// Delivered is the number of the status changes delivered to the endpoint
+	// +optional
+	Delivered int64 `json:"delivered,omitempty"`
+
+	// Failed is the number of the failed deliveries, a failed status change is posted again
+	// at the next aggregation of the reports
+	// +optional
+	Failed int64 `json:"failed,omitempty"`
+
+	// LastDeliveryTime is when a status change was last delivered to the endpoint
+	// +optional
+	LastDeliveryTime *metav1.Time `json:"lastDeliveryTime,omitempty"`
+
+	// LastFailureTime is when a delivery last failed
+	// +optional
+	LastFailureTime *metav1.Time `json:"lastFailureTime,omitempty"`
+
+	// LastError is the error of the last failed delivery, cleared once a status change is delivered
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope="Namespaced"
+// +kubebuilder:resource:shortName=appsubwebhook
+// +kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.spec.url`
+// +kubebuilder:printcolumn:name="Delivered",type=integer,JSONPath=`.status.delivered`
+// +kubebuilder:printcolumn:name="Failed",type=integer,JSONPath=`.status.failed`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// SubscriptionWebhook posts the status changes of the subscriptions of its namespace on the clusters to an external
+// endpoint, like a CMDB. The hub compares the subscription reports of the clusters at each aggregation of the reports
+// and posts a JSON document per changed cluster.
+type SubscriptionWebhook struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SubscriptionWebhookSpec   `json:"spec,omitempty"`
+	Status SubscriptionWebhookStatus `json:"status,omitempty"`
+}
+
+// SubscriptionWebhookList contains a list of SubscriptionWebhook
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type SubscriptionWebhookList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SubscriptionWebhook `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SubscriptionWebhook{}, &SubscriptionWebhookList{})
+}
@@ -20,27 +20,770 @@ package v1alpha1
 
 import (
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentUpgrade) DeepCopyInto(out *AgentUpgrade) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentUpgrade.
+func (in *AgentUpgrade) DeepCopy() *AgentUpgrade {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentUpgrade)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AgentUpgrade) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentUpgradeList) DeepCopyInto(out *AgentUpgradeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AgentUpgrade, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentUpgradeList.
+func (in *AgentUpgradeList) DeepCopy() *AgentUpgradeList {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentUpgradeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AgentUpgradeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentUpgradeSpec) DeepCopyInto(out *AgentUpgradeSpec) {
+	*out = *in
+	if in.Waves != nil {
+		in, out := &in.Waves, &out.Waves
+		*out = make([]AgentUpgradeWave, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.UpgradeTimeout = in.UpgradeTimeout
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentUpgradeSpec.
+func (in *AgentUpgradeSpec) DeepCopy() *AgentUpgradeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentUpgradeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentUpgradeStatus) DeepCopyInto(out *AgentUpgradeStatus) {
+	*out = *in
+	if in.Waves != nil {
+		in, out := &in.Waves, &out.Waves
+		*out = make([]AgentUpgradeWaveStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentUpgradeStatus.
+func (in *AgentUpgradeStatus) DeepCopy() *AgentUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentUpgradeWave) DeepCopyInto(out *AgentUpgradeWave) {
+	*out = *in
+	if in.ClusterSelector != nil {
+		in, out := &in.ClusterSelector, &out.ClusterSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	out.SoakDuration = in.SoakDuration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentUpgradeWave.
+func (in *AgentUpgradeWave) DeepCopy() *AgentUpgradeWave {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentUpgradeWave)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentUpgradeWaveStatus) DeepCopyInto(out *AgentUpgradeWaveStatus) {
+	*out = *in
+	if in.StalledClusters != nil {
+		in, out := &in.StalledClusters, &out.StalledClusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartedTime != nil {
+		in, out := &in.StartedTime, &out.StartedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.UpgradedTime != nil {
+		in, out := &in.UpgradedTime, &out.UpgradedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentUpgradeWaveStatus.
+func (in *AgentUpgradeWaveStatus) DeepCopy() *AgentUpgradeWaveStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentUpgradeWaveStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApprovalRequest) DeepCopyInto(out *ApprovalRequest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApprovalRequest.
+func (in *ApprovalRequest) DeepCopy() *ApprovalRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(ApprovalRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ApprovalRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApprovalRequestList) DeepCopyInto(out *ApprovalRequestList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ApprovalRequest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApprovalRequestList.
+func (in *ApprovalRequestList) DeepCopy() *ApprovalRequestList {
+	if in == nil {
+		return nil
+	}
+	out := new(ApprovalRequestList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ApprovalRequestList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApprovalRequestSpec) DeepCopyInto(out *ApprovalRequestSpec) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExpirationTime != nil {
+		in, out := &in.ExpirationTime, &out.ExpirationTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApprovalRequestSpec.
+func (in *ApprovalRequestSpec) DeepCopy() *ApprovalRequestSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ApprovalRequestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApprovalRequestStatus) DeepCopyInto(out *ApprovalRequestStatus) {
+	*out = *in
+	if in.DecisionTime != nil {
+		in, out := &in.DecisionTime, &out.DecisionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApprovalRequestStatus.
+func (in *ApprovalRequestStatus) DeepCopy() *ApprovalRequestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ApprovalRequestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChangeFreeze) DeepCopyInto(out *ChangeFreeze) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChangeFreeze.
+func (in *ChangeFreeze) DeepCopy() *ChangeFreeze {
+	if in == nil {
+		return nil
+	}
+	out := new(ChangeFreeze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ChangeFreeze) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChangeFreezeList) DeepCopyInto(out *ChangeFreezeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ChangeFreeze, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChangeFreezeList.
+func (in *ChangeFreezeList) DeepCopy() *ChangeFreezeList {
+	if in == nil {
+		return nil
+	}
+	out := new(ChangeFreezeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ChangeFreezeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChangeFreezeSpec) DeepCopyInto(out *ChangeFreezeSpec) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterSelector != nil {
+		in, out := &in.ClusterSelector, &out.ClusterSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Start != nil {
+		in, out := &in.Start, &out.Start
+		*out = (*in).DeepCopy()
+	}
+	if in.End != nil {
+		in, out := &in.End, &out.End
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChangeFreezeSpec.
+func (in *ChangeFreezeSpec) DeepCopy() *ChangeFreezeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ChangeFreezeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubscriptionClusterStatusMap) DeepCopyInto(out *SubscriptionClusterStatusMap) {
 	*out = *in
-	if in.SubscriptionStatus != nil {
-		in, out := &in.SubscriptionStatus, &out.SubscriptionStatus
-		*out = make([]SubscriptionUnitStatus, len(*in))
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Revisions != nil {
+		in, out := &in.Revisions, &out.Revisions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LastFetchTime != nil {
+		in, out := &in.LastFetchTime, &out.LastFetchTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastAppliedTime != nil {
+		in, out := &in.LastAppliedTime, &out.LastAppliedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextReconcileTime != nil {
+		in, out := &in.NextReconcileTime, &out.NextReconcileTime
+		*out = (*in).DeepCopy()
+	}
+	if in.FailedResources != nil {
+		in, out := &in.FailedResources, &out.FailedResources
+		*out = make([]v1.ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.CreatedNamespaces != nil {
+		in, out := &in.CreatedNamespaces, &out.CreatedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SubscriptionStatus != nil {
+		in, out := &in.SubscriptionStatus, &out.SubscriptionStatus
+		*out = make([]SubscriptionUnitStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionClusterStatusMap.
+func (in *SubscriptionClusterStatusMap) DeepCopy() *SubscriptionClusterStatusMap {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionClusterStatusMap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionHistory) DeepCopyInto(out *SubscriptionHistory) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]SubscriptionHistoryEvent, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.State.DeepCopyInto(&out.State)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionHistory.
+func (in *SubscriptionHistory) DeepCopy() *SubscriptionHistory {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionHistory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SubscriptionHistory) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionHistoryEvent) DeepCopyInto(out *SubscriptionHistoryEvent) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionHistoryEvent.
+func (in *SubscriptionHistoryEvent) DeepCopy() *SubscriptionHistoryEvent {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionHistoryEvent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionHistoryList) DeepCopyInto(out *SubscriptionHistoryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SubscriptionHistory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionHistoryList.
+func (in *SubscriptionHistoryList) DeepCopy() *SubscriptionHistoryList {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionHistoryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SubscriptionHistoryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionHistoryState) DeepCopyInto(out *SubscriptionHistoryState) {
+	*out = *in
+	if in.Revisions != nil {
+		in, out := &in.Revisions, &out.Revisions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionHistoryState.
+func (in *SubscriptionHistoryState) DeepCopy() *SubscriptionHistoryState {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionHistoryState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionQuery) DeepCopyInto(out *SubscriptionQuery) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionQuery.
+func (in *SubscriptionQuery) DeepCopy() *SubscriptionQuery {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionQuery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SubscriptionQuery) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionQueryCluster) DeepCopyInto(out *SubscriptionQueryCluster) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionQueryCluster.
+func (in *SubscriptionQueryCluster) DeepCopy() *SubscriptionQueryCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionQueryCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionQueryList) DeepCopyInto(out *SubscriptionQueryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SubscriptionQuery, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionQueryList.
+func (in *SubscriptionQueryList) DeepCopy() *SubscriptionQueryList {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionQueryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SubscriptionQueryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionQueryResource) DeepCopyInto(out *SubscriptionQueryResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionQueryResource.
+func (in *SubscriptionQueryResource) DeepCopy() *SubscriptionQueryResource {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionQueryResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionQuerySpec) DeepCopyInto(out *SubscriptionQuerySpec) {
+	*out = *in
+	if in.FailedResource != nil {
+		in, out := &in.FailedResource, &out.FailedResource
+		*out = new(SubscriptionQueryResource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionQuerySpec.
+func (in *SubscriptionQuerySpec) DeepCopy() *SubscriptionQuerySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionQuerySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionQueryStatus) DeepCopyInto(out *SubscriptionQueryStatus) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]SubscriptionQueryCluster, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionQueryStatus.
+func (in *SubscriptionQueryStatus) DeepCopy() *SubscriptionQueryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionQueryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionQuota) DeepCopyInto(out *SubscriptionQuota) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionQuota.
+func (in *SubscriptionQuota) DeepCopy() *SubscriptionQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SubscriptionQuota) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionQuotaList) DeepCopyInto(out *SubscriptionQuotaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SubscriptionQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionQuotaList.
+func (in *SubscriptionQuotaList) DeepCopy() *SubscriptionQuotaList {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionQuotaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SubscriptionQuotaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionQuotaSpec) DeepCopyInto(out *SubscriptionQuotaSpec) {
+	*out = *in
+	if in.MaxSubscriptions != nil {
+		in, out := &in.MaxSubscriptions, &out.MaxSubscriptions
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxClusters != nil {
+		in, out := &in.MaxClusters, &out.MaxClusters
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxResources != nil {
+		in, out := &in.MaxResources, &out.MaxResources
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionQuotaSpec.
+func (in *SubscriptionQuotaSpec) DeepCopy() *SubscriptionQuotaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionQuotaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionQuotaStatus) DeepCopyInto(out *SubscriptionQuotaStatus) {
+	*out = *in
+	out.Used = in.Used
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionClusterStatusMap.
-func (in *SubscriptionClusterStatusMap) DeepCopy() *SubscriptionClusterStatusMap {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionQuotaStatus.
+func (in *SubscriptionQuotaStatus) DeepCopy() *SubscriptionQuotaStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(SubscriptionClusterStatusMap)
+	out := new(SubscriptionQuotaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionQuotaUsage) DeepCopyInto(out *SubscriptionQuotaUsage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionQuotaUsage.
+func (in *SubscriptionQuotaUsage) DeepCopy() *SubscriptionQuotaUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionQuotaUsage)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -58,7 +801,7 @@ func (in *SubscriptionReport) DeepCopyInto(out *SubscriptionReport) {
 			if (*in)[i] != nil {
 				in, out := &(*in)[i], &(*out)[i]
 				*out = new(SubscriptionReportResult)
-				**out = **in
+				(*in).DeepCopyInto(*out)
 			}
 		}
 	}
@@ -129,6 +872,16 @@ func (in *SubscriptionReportList) DeepCopyObject() runtime.Object {
 func (in *SubscriptionReportResult) DeepCopyInto(out *SubscriptionReportResult) {
 	*out = *in
 	out.Timestamp = in.Timestamp
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FailedResources != nil {
+		in, out := &in.FailedResources, &out.FailedResources
+		*out = make([]v1.ObjectReference, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionReportResult.
@@ -156,6 +909,68 @@ func (in *SubscriptionReportSummary) DeepCopy() *SubscriptionReportSummary {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionResourcePage) DeepCopyInto(out *SubscriptionResourcePage) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]v1.ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionResourcePage.
+func (in *SubscriptionResourcePage) DeepCopy() *SubscriptionResourcePage {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionResourcePage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SubscriptionResourcePage) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionResourcePageList) DeepCopyInto(out *SubscriptionResourcePageList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SubscriptionResourcePage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionResourcePageList.
+func (in *SubscriptionResourcePageList) DeepCopy() *SubscriptionResourcePageList {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionResourcePageList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SubscriptionResourcePageList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubscriptionStatus) DeepCopyInto(out *SubscriptionStatus) {
 	*out = *in
@@ -214,6 +1029,110 @@ func (in *SubscriptionStatusList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionTelemetry) DeepCopyInto(out *SubscriptionTelemetry) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Summary.DeepCopyInto(&out.Summary)
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionTelemetry.
+func (in *SubscriptionTelemetry) DeepCopy() *SubscriptionTelemetry {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionTelemetry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SubscriptionTelemetry) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionTelemetryList) DeepCopyInto(out *SubscriptionTelemetryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SubscriptionTelemetry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionTelemetryList.
+func (in *SubscriptionTelemetryList) DeepCopy() *SubscriptionTelemetryList {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionTelemetryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SubscriptionTelemetryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionTelemetrySummary) DeepCopyInto(out *SubscriptionTelemetrySummary) {
+	*out = *in
+	if in.SubscriptionPhases != nil {
+		in, out := &in.SubscriptionPhases, &out.SubscriptionPhases
+		*out = make([]TelemetryCount, len(*in))
+		copy(*out, *in)
+	}
+	if in.SubscriptionConditions != nil {
+		in, out := &in.SubscriptionConditions, &out.SubscriptionConditions
+		*out = make([]TelemetryCount, len(*in))
+		copy(*out, *in)
+	}
+	if in.ChannelTypes != nil {
+		in, out := &in.ChannelTypes, &out.ChannelTypes
+		*out = make([]TelemetryCount, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterResults != nil {
+		in, out := &in.ClusterResults, &out.ClusterResults
+		*out = make([]TelemetryCount, len(*in))
+		copy(*out, *in)
+	}
+	if in.AgentVersions != nil {
+		in, out := &in.AgentVersions, &out.AgentVersions
+		*out = make([]TelemetryCount, len(*in))
+		copy(*out, *in)
+	}
+	if in.ErrorSignatures != nil {
+		in, out := &in.ErrorSignatures, &out.ErrorSignatures
+		*out = make([]TelemetryErrorSignature, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionTelemetrySummary.
+func (in *SubscriptionTelemetrySummary) DeepCopy() *SubscriptionTelemetrySummary {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionTelemetrySummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubscriptionUnitStatus) DeepCopyInto(out *SubscriptionUnitStatus) {
 	*out = *in
@@ -229,3 +1148,140 @@ func (in *SubscriptionUnitStatus) DeepCopy() *SubscriptionUnitStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionWebhook) DeepCopyInto(out *SubscriptionWebhook) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionWebhook.
+func (in *SubscriptionWebhook) DeepCopy() *SubscriptionWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SubscriptionWebhook) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionWebhookList) DeepCopyInto(out *SubscriptionWebhookList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SubscriptionWebhook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionWebhookList.
+func (in *SubscriptionWebhookList) DeepCopy() *SubscriptionWebhookList {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionWebhookList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SubscriptionWebhookList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionWebhookSpec) DeepCopyInto(out *SubscriptionWebhookSpec) {
+	*out = *in
+	if in.Results != nil {
+		in, out := &in.Results, &out.Results
+		*out = make([]SubscriptionResult, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionWebhookSpec.
+func (in *SubscriptionWebhookSpec) DeepCopy() *SubscriptionWebhookSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionWebhookSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionWebhookStatus) DeepCopyInto(out *SubscriptionWebhookStatus) {
+	*out = *in
+	if in.LastDeliveryTime != nil {
+		in, out := &in.LastDeliveryTime, &out.LastDeliveryTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastFailureTime != nil {
+		in, out := &in.LastFailureTime, &out.LastFailureTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionWebhookStatus.
+func (in *SubscriptionWebhookStatus) DeepCopy() *SubscriptionWebhookStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionWebhookStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TelemetryCount) DeepCopyInto(out *TelemetryCount) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TelemetryCount.
+func (in *TelemetryCount) DeepCopy() *TelemetryCount {
+	if in == nil {
+		return nil
+	}
+	out := new(TelemetryCount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TelemetryErrorSignature) DeepCopyInto(out *TelemetryErrorSignature) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TelemetryErrorSignature.
+func (in *TelemetryErrorSignature) DeepCopy() *TelemetryErrorSignature {
+	if in == nil {
+		return nil
+	}
+	out := new(TelemetryErrorSignature)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,254 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+// ConvertChannelToV1 converts the v1beta1 channel to v1, the configuration block of the channel type gives the v1
+// pathname, secretRef, configMapRef and insecureSkipVerify, the polling, webhook, git and helm repo mirrors and package overrides
+// schema settings are set in the v1 annotations. The fields take precedence over the same annotations set in the v1beta1 metadata.
+func ConvertChannelToV1(src *Channel) (*chnv1.Channel, error) {
+	dst := &chnv1.Channel{
+		ObjectMeta: *src.ObjectMeta.DeepCopy(),
+		Spec:       chnv1.ChannelSpec{Type: src.Spec.Type},
+		Status:     src.Status,
+	}
+
+	dst.SetGroupVersionKind(chnv1.SchemeGroupVersion.WithKind("Channel"))
+
+	annotations := dst.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	var (
+		auth    *ChannelAuth
+		tls     *ChannelTLS
+		polling *ChannelPolling
+	)
+
+	spec := src.Spec.DeepCopy()
+
+	switch strings.ToLower(string(spec.Type)) {
+	case chnv1.ChannelTypeNamespace:
+		if spec.Namespace == nil {
+			return nil, fmt.Errorf("channel %v/%v of type %v has no namespace configuration", src.Namespace, src.Name, spec.Type)
+		}
+
+		dst.Spec.Pathname = spec.Namespace.Name
+		dst.Spec.Gates = spec.Namespace.Gates
+		dst.Spec.SourceNamespaces = spec.Namespace.SourceNamespaces
+	case chnv1.ChannelTypeGit, chnv1.ChannelTypeGitHub:
+		if spec.Git == nil {
+			return nil, fmt.Errorf("channel %v/%v of type %v has no git configuration", src.Namespace, src.Name, spec.Type)
+		}
+
+		dst.Spec.Pathname = spec.Git.URL
+		dst.Spec.ConfigMapRef = spec.Git.ConfigMapRef
+		auth, tls, polling = spec.Git.Auth, spec.Git.TLS, spec.Git.Polling
+
+		if webhook := spec.Git.Webhook; webhook != nil {
+			if webhook.Enabled {
+				annotations[appv1.AnnotationWebhookEnabled] = "true"
+			}
+
+			if webhook.SecretName != "" {
+				annotations[appv1.AnnotationWebhookSecret] = webhook.SecretName
+			}
+		}
+
+		if len(spec.Git.Mirrors) > 0 {
+			mirrors, err := json.Marshal(spec.Git.Mirrors)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal the mirrors of channel %v/%v: %w", src.Namespace, src.Name, err)
+			}
+
+			annotations[appv1.AnnotationGitMirrors] = string(mirrors)
+		}
+	case chnv1.ChannelTypeHelmRepo:
+		if spec.HelmRepo == nil {
+			return nil, fmt.Errorf("channel %v/%v of type %v has no helmRepo configuration", src.Namespace, src.Name, spec.Type)
+		}
+
+		dst.Spec.Pathname = spec.HelmRepo.URL
+		dst.Spec.ConfigMapRef = spec.HelmRepo.ConfigMapRef
+		auth, tls, polling = spec.HelmRepo.Auth, spec.HelmRepo.TLS, spec.HelmRepo.Polling
+
+		if len(spec.HelmRepo.Mirrors) > 0 {
+			mirrors, err := json.Marshal(spec.HelmRepo.Mirrors)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal the mirrors of channel %v/%v: %w", src.Namespace, src.Name, err)
+			}
+
+			annotations[appv1.AnnotationHelmRepoMirrors] = string(mirrors)
+		}
+	case chnv1.ChannelTypeObjectBucket:
+		if spec.ObjectBucket == nil {
+			return nil, fmt.Errorf("channel %v/%v of type %v has no objectBucket configuration", src.Namespace, src.Name, spec.Type)
+		}
+
+		dst.Spec.Pathname = spec.ObjectBucket.URL
+		dst.Spec.ConfigMapRef = spec.ObjectBucket.ConfigMapRef
+		auth, tls, polling = spec.ObjectBucket.Auth, spec.ObjectBucket.TLS, spec.ObjectBucket.Polling
+	case channelTypeBundle:
+		if spec.Bundle == nil {
+			return nil, fmt.Errorf("channel %v/%v of type %v has no bundle configuration", src.Namespace, src.Name, spec.Type)
+		}
+
+		dst.Spec.Pathname = spec.Bundle.URL
+		auth, tls, polling = spec.Bundle.Auth, spec.Bundle.TLS, spec.Bundle.Polling
+	default:
+		return nil, fmt.Errorf("unsupported type %v of channel %v/%v", spec.Type, src.Namespace, src.Name)
+	}
+
+	if auth != nil {
+		dst.Spec.SecretRef = auth.SecretRef
+	}
+
+	if tls != nil {
+		dst.Spec.InsecureSkipVerify = tls.InsecureSkipVerify
+	}
+
+	if polling != nil && polling.ReconcileRate != "" {
+		annotations[appv1.AnnotationResourceReconcileLevel] = polling.ReconcileRate
+	}
+
+	if ref := spec.PackageOverridesSchemaRef; ref != nil && ref.Name != "" {
+		annotations[appv1.AnnotationPackageOverridesSchema] = ref.Name
+	}
+
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+
+	dst.SetAnnotations(annotations)
+
+	return dst, nil
+}
+
+// ConvertChannelFromV1 converts the v1 channel to v1beta1, the settings of the channel type are moved to its
+// configuration block. The webhook-enabled, reconcile-rate, git-mirrors and helm-repo-mirrors annotations which are
+// not valid stay in the annotations.
+func ConvertChannelFromV1(src *chnv1.Channel) (*Channel, error) {
+	dst := &Channel{
+		ObjectMeta: *src.ObjectMeta.DeepCopy(),
+		Spec:       ChannelSpec{Type: src.Spec.Type},
+		Status:     src.Status,
+	}
+
+	dst.SetGroupVersionKind(SchemeGroupVersion.WithKind("Channel"))
+
+	spec := src.Spec.DeepCopy()
+	annotations := dst.GetAnnotations()
+
+	var auth *ChannelAuth
+	if spec.SecretRef != nil {
+		auth = &ChannelAuth{SecretRef: spec.SecretRef}
+	}
+
+	var tls *ChannelTLS
+	if spec.InsecureSkipVerify {
+		tls = &ChannelTLS{InsecureSkipVerify: true}
+	}
+
+	if schema := annotations[appv1.AnnotationPackageOverridesSchema]; schema != "" {
+		dst.Spec.PackageOverridesSchemaRef = &corev1.LocalObjectReference{Name: schema}
+
+		delete(annotations, appv1.AnnotationPackageOverridesSchema)
+	}
+
+	var polling *ChannelPolling
+
+	switch rate := annotations[appv1.AnnotationResourceReconcileLevel]; rate {
+	case "off", "low", "medium", "high":
+		polling = &ChannelPolling{ReconcileRate: rate}
+	}
+
+	switch strings.ToLower(string(spec.Type)) {
+	case chnv1.ChannelTypeNamespace:
+		dst.Spec.Namespace = &NamespaceChannel{
+			Name:             spec.Pathname,
+			Gates:            spec.Gates,
+			SourceNamespaces: spec.SourceNamespaces,
+		}
+
+		// the namespace channels are not polled
+		if len(annotations) == 0 {
+			annotations = nil
+		}
+
+		dst.SetAnnotations(annotations)
+
+		return dst, nil
+	case chnv1.ChannelTypeGit, chnv1.ChannelTypeGitHub:
+		dst.Spec.Git = &GitChannel{URL: spec.Pathname, Auth: auth, TLS: tls, Polling: polling, ConfigMapRef: spec.ConfigMapRef}
+
+		webhook := &ChannelWebhook{SecretName: annotations[appv1.AnnotationWebhookSecret]}
+		delete(annotations, appv1.AnnotationWebhookSecret)
+
+		if annotations[appv1.AnnotationWebhookEnabled] == "true" {
+			webhook.Enabled = true
+
+			delete(annotations, appv1.AnnotationWebhookEnabled)
+		}
+
+		if *webhook != (ChannelWebhook{}) {
+			dst.Spec.Git.Webhook = webhook
+		}
+
+		mirrors := []appv1.GitMirror{}
+		if err := json.Unmarshal([]byte(annotations[appv1.AnnotationGitMirrors]), &mirrors); err == nil && len(mirrors) > 0 {
+			dst.Spec.Git.Mirrors = mirrors
+
+			delete(annotations, appv1.AnnotationGitMirrors)
+		}
+	case chnv1.ChannelTypeHelmRepo:
+		dst.Spec.HelmRepo = &HelmRepoChannel{URL: spec.Pathname, Auth: auth, TLS: tls, Polling: polling, ConfigMapRef: spec.ConfigMapRef}
+
+		mirrors := []appv1.HelmRepoMirror{}
+		if err := json.Unmarshal([]byte(annotations[appv1.AnnotationHelmRepoMirrors]), &mirrors); err == nil && len(mirrors) > 0 {
+			dst.Spec.HelmRepo.Mirrors = mirrors
+
+			delete(annotations, appv1.AnnotationHelmRepoMirrors)
+		}
+	case chnv1.ChannelTypeObjectBucket:
+		dst.Spec.ObjectBucket = &ObjectBucketChannel{URL: spec.Pathname, Auth: auth, TLS: tls, Polling: polling, ConfigMapRef: spec.ConfigMapRef}
+	case channelTypeBundle:
+		dst.Spec.Bundle = &BundleChannel{URL: spec.Pathname, Auth: auth, TLS: tls, Polling: polling}
+	default:
+		return nil, fmt.Errorf("unsupported type %v of channel %v/%v", spec.Type, src.Namespace, src.Name)
+	}
+
+	if polling != nil {
+		delete(annotations, appv1.AnnotationResourceReconcileLevel)
+	}
+
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+
+	dst.SetAnnotations(annotations)
+
+	return dst, nil
+}
@@ -0,0 +1,214 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func newV1Channel(chType chnv1.ChannelType, pathname string, annotations map[string]string) *chnv1.Channel {
+	return &chnv1.Channel{
+		TypeMeta:   metav1.TypeMeta{APIVersion: chnv1.SchemeGroupVersion.String(), Kind: "Channel"},
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "ch-ns", Annotations: annotations},
+		Spec: chnv1.ChannelSpec{
+			Type:               chType,
+			Pathname:           pathname,
+			InsecureSkipVerify: true,
+			SecretRef:          &corev1.ObjectReference{Name: "demo-secret"},
+			ConfigMapRef:       &corev1.ObjectReference{Name: "demo-config"},
+		},
+	}
+}
+
+func TestChannelConversion(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	gitChannel := newV1Channel(chnv1.ChannelTypeGit, "https://github.com/org/repo.git", map[string]string{
+		appv1.AnnotationWebhookEnabled:         "true",
+		appv1.AnnotationWebhookSecret:          "webhook-secret",
+		appv1.AnnotationResourceReconcileLevel: "high",
+		appv1.AnnotationPackageOverridesSchema: "overrides-schema",
+		"owner":                                "demo",
+	})
+
+	converted, err := ConvertChannelFromV1(gitChannel)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(converted.APIVersion).To(gomega.Equal(SchemeGroupVersion.String()))
+	g.Expect(converted.Spec.Git).To(gomega.Equal(&GitChannel{
+		URL:          "https://github.com/org/repo.git",
+		Auth:         &ChannelAuth{SecretRef: &corev1.ObjectReference{Name: "demo-secret"}},
+		TLS:          &ChannelTLS{InsecureSkipVerify: true},
+		Polling:      &ChannelPolling{ReconcileRate: "high"},
+		Webhook:      &ChannelWebhook{Enabled: true, SecretName: "webhook-secret"},
+		ConfigMapRef: &corev1.ObjectReference{Name: "demo-config"},
+	}))
+	g.Expect(converted.Spec.PackageOverridesSchemaRef).To(gomega.Equal(&corev1.LocalObjectReference{Name: "overrides-schema"}))
+	g.Expect(converted.Annotations).To(gomega.Equal(map[string]string{"owner": "demo"}))
+
+	roundTrip, err := ConvertChannelToV1(converted)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(roundTrip).To(gomega.Equal(gitChannel))
+
+	for _, v1Channel := range []*chnv1.Channel{
+		newV1Channel(chnv1.ChannelTypeHelmRepo, "https://charts.example.com", nil),
+		newV1Channel(chnv1.ChannelTypeObjectBucket, "https://s3.amazonaws.com/bucket", map[string]string{appv1.AnnotationResourceReconcileLevel: "unknown"}),
+		newV1Channel("bundle", "oci://registry.local/app:v1", nil),
+	} {
+		v1Channel.Spec.ConfigMapRef = nil
+
+		converted, err := ConvertChannelFromV1(v1Channel)
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+
+		roundTrip, err := ConvertChannelToV1(converted)
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+		g.Expect(roundTrip).To(gomega.Equal(v1Channel))
+	}
+
+	helmChannel := newV1Channel(chnv1.ChannelTypeHelmRepo, "https://charts.example.com", map[string]string{
+		appv1.AnnotationHelmRepoMirrors: `[{"url":"https://mirror.example.com/charts","secretRef":{"name":"mirror-auth"}}]`,
+	})
+
+	converted, err = ConvertChannelFromV1(helmChannel)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(converted.Spec.HelmRepo.Mirrors).To(gomega.Equal([]appv1.HelmRepoMirror{{
+		URL:       "https://mirror.example.com/charts",
+		SecretRef: &corev1.LocalObjectReference{Name: "mirror-auth"},
+	}}))
+	g.Expect(converted.Annotations).To(gomega.BeEmpty())
+
+	roundTrip, err = ConvertChannelToV1(converted)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(roundTrip).To(gomega.Equal(helmChannel))
+
+	mirroredGitChannel := newV1Channel(chnv1.ChannelTypeGit, "https://github.com/example/app.git", map[string]string{
+		appv1.AnnotationGitMirrors: `[{"url":"https://git.internal.example.com/example/app.git","secretRef":{"name":"mirror-auth"}}]`,
+	})
+
+	converted, err = ConvertChannelFromV1(mirroredGitChannel)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(converted.Spec.Git.Mirrors).To(gomega.Equal([]appv1.GitMirror{{
+		URL:       "https://git.internal.example.com/example/app.git",
+		SecretRef: &corev1.LocalObjectReference{Name: "mirror-auth"},
+	}}))
+	g.Expect(converted.Annotations).To(gomega.BeEmpty())
+
+	roundTrip, err = ConvertChannelToV1(converted)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(roundTrip).To(gomega.Equal(mirroredGitChannel))
+
+	nsChannel := &chnv1.Channel{
+		TypeMeta:   metav1.TypeMeta{APIVersion: chnv1.SchemeGroupVersion.String(), Kind: "Channel"},
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "ch-ns"},
+		Spec: chnv1.ChannelSpec{
+			Type:             chnv1.ChannelTypeNamespace,
+			Pathname:         "deployables",
+			SourceNamespaces: []string{"dev"},
+		},
+	}
+
+	converted, err = ConvertChannelFromV1(nsChannel)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(converted.Spec.Namespace).To(gomega.Equal(&NamespaceChannel{Name: "deployables", SourceNamespaces: []string{"dev"}}))
+
+	roundTrip, err = ConvertChannelToV1(converted)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(roundTrip).To(gomega.Equal(nsChannel))
+
+	// the configuration block of the channel type is required
+	converted.Spec = ChannelSpec{Type: chnv1.ChannelTypeHelmRepo, Git: &GitChannel{URL: "https://github.com/org/repo.git"}}
+	_, err = ConvertChannelToV1(converted)
+	g.Expect(err).To(gomega.HaveOccurred())
+}
+
+func TestChannelConversionWebhook(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	v1Channel := newV1Channel(chnv1.ChannelTypeHelmRepo, "https://charts.example.com", nil)
+	raw, err := json.Marshal(v1Channel)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	invalid, err := json.Marshal(&Channel{
+		TypeMeta:   metav1.TypeMeta{APIVersion: SchemeGroupVersion.String(), Kind: "Channel"},
+		ObjectMeta: metav1.ObjectMeta{Name: "invalid", Namespace: "ch-ns"},
+		Spec:       ChannelSpec{Type: chnv1.ChannelTypeGit},
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	review := func(desiredAPIVersion string, objects ...[]byte) *apiextensionsv1.ConversionResponse {
+		req := &apiextensionsv1.ConversionReview{
+			TypeMeta: metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "ConversionReview"},
+			Request:  &apiextensionsv1.ConversionRequest{UID: "uid-1", DesiredAPIVersion: desiredAPIVersion},
+		}
+
+		for _, obj := range objects {
+			req.Request.Objects = append(req.Request.Objects, runtime.RawExtension{Raw: obj})
+		}
+
+		body, err := json.Marshal(req)
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+
+		w := httptest.NewRecorder()
+		(&channelConversionWebhook{}).ServeHTTP(w, httptest.NewRequest(http.MethodPost, ChannelConversionPath, bytes.NewReader(body)))
+		g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+
+		resp := &apiextensionsv1.ConversionReview{}
+		g.Expect(json.Unmarshal(w.Body.Bytes(), resp)).To(gomega.Succeed())
+		g.Expect(resp.Response.UID).To(gomega.BeEquivalentTo("uid-1"))
+
+		return resp.Response
+	}
+
+	resp := review(SchemeGroupVersion.String(), raw)
+	g.Expect(resp.Result.Status).To(gomega.Equal(metav1.StatusSuccess))
+	g.Expect(resp.ConvertedObjects).To(gomega.HaveLen(1))
+
+	converted := &Channel{}
+	g.Expect(json.Unmarshal(resp.ConvertedObjects[0].Raw, converted)).To(gomega.Succeed())
+	g.Expect(converted.APIVersion).To(gomega.Equal(SchemeGroupVersion.String()))
+	g.Expect(converted.Spec.HelmRepo.URL).To(gomega.Equal("https://charts.example.com"))
+
+	// the untyped status of the channel is kept
+	gitChannel := map[string]interface{}{}
+	g.Expect(json.Unmarshal(raw, &gitChannel)).To(gomega.Succeed())
+	gitChannel["status"] = map[string]interface{}{"lastGitFetch": map[string]interface{}{"result": "Succeeded"}}
+
+	rawWithStatus, err := json.Marshal(gitChannel)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	resp = review(SchemeGroupVersion.String(), rawWithStatus)
+	g.Expect(resp.Result.Status).To(gomega.Equal(metav1.StatusSuccess))
+	g.Expect(string(resp.ConvertedObjects[0].Raw)).To(gomega.ContainSubstring(`"status":{"lastGitFetch":{"result":"Succeeded"}}`))
+
+	resp = review(chnv1.SchemeGroupVersion.String(), invalid)
+	g.Expect(resp.Result.Status).To(gomega.Equal(metav1.StatusFailure))
+	g.Expect(resp.Result.Message).To(gomega.ContainSubstring("has no git configuration"))
+
+	w := httptest.NewRecorder()
+	(&channelConversionWebhook{}).ServeHTTP(w, httptest.NewRequest(http.MethodPost, ChannelConversionPath, bytes.NewReader([]byte("{}"))))
+	g.Expect(w.Code).To(gomega.Equal(http.StatusBadRequest))
+}
@@ -0,0 +1,190 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+// channelTypeBundle is the channel type of the offline bundles
+const channelTypeBundle = "bundle"
+
+// ChannelAuth defines the credentials of the channel
+type ChannelAuth struct {
+	// The secret of the credentials, user and accessToken for Git and Helm repositories and bundles, AccessKeyID
+	// and SecretAccessKey for object buckets
+	SecretRef *corev1.ObjectReference `json:"secretRef,omitempty"`
+}
+
+// ChannelTLS defines the TLS settings of the channel connection
+type ChannelTLS struct {
+	// Skip the verification of the server certificate
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// ChannelPolling defines how often the subscriptions of the channel reconcile its resources
+type ChannelPolling struct {
+	// The reconcile rate of the subscriptions of the channel, medium by default
+	// +kubebuilder:validation:Enum=off;low;medium;high
+	ReconcileRate string `json:"reconcileRate,omitempty"`
+}
+
+// ChannelWebhook defines the Git webhook event notifications of the channel
+type ChannelWebhook struct {
+	// Enabled reconciles the subscriptions of the channel on the Git webhook events
+	Enabled bool `json:"enabled,omitempty"`
+	// The secret of the webhook events, in the channel namespace
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// NamespaceChannel defines the configuration of a Namespace channel
+type NamespaceChannel struct {
+	// The namespace of the channel deployables
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Criteria for promoting a Deployable from the sourceNamespaces to Channel.
+	// +optional
+	Gates *chnv1.ChannelGate `json:"gates,omitempty"`
+	// A list of namespace names from which Deployables can be promoted.
+	// +optional
+	// +listType=set
+	SourceNamespaces []string `json:"sourceNamespaces,omitempty"`
+}
+
+// GitChannel defines the configuration of a Git channel
+type GitChannel struct {
+	// The URL of the Git repository
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+	// +optional
+	Auth *ChannelAuth `json:"auth,omitempty"`
+	// +optional
+	TLS *ChannelTLS `json:"tls,omitempty"`
+	// +optional
+	Polling *ChannelPolling `json:"polling,omitempty"`
+	// +optional
+	Webhook *ChannelWebhook `json:"webhook,omitempty"`
+	// The ConfigMap of the connection settings, caCerts for the CA certificates of the repository
+	// +optional
+	ConfigMapRef *corev1.ObjectReference `json:"configMapRef,omitempty"`
+	// The mirror remotes of the repository, cloned in order when the url can't be cloned
+	// +optional
+	Mirrors []appv1.GitMirror `json:"mirrors,omitempty"`
+}
+
+// HelmRepoChannel defines the configuration of a Helm repository channel
+type HelmRepoChannel struct {
+	// The URL of the Helm repository, several URLs are separated by spaces
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+	// +optional
+	Auth *ChannelAuth `json:"auth,omitempty"`
+	// +optional
+	TLS *ChannelTLS `json:"tls,omitempty"`
+	// +optional
+	Polling *ChannelPolling `json:"polling,omitempty"`
+	// The ConfigMap of the connection settings, caCerts for the CA certificates of the repository
+	// +optional
+	ConfigMapRef *corev1.ObjectReference `json:"configMapRef,omitempty"`
+	// The mirrors of the repository, tried in order when the url isn't available
+	// +optional
+	Mirrors []appv1.HelmRepoMirror `json:"mirrors,omitempty"`
+}
+
+// ObjectBucketChannel defines the configuration of an object bucket channel
+type ObjectBucketChannel struct {
+	// The URL of the bucket, the bucket name is the last element of the path
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+	// +optional
+	Auth *ChannelAuth `json:"auth,omitempty"`
+	// +optional
+	TLS *ChannelTLS `json:"tls,omitempty"`
+	// +optional
+	Polling *ChannelPolling `json:"polling,omitempty"`
+	// The ConfigMap of the connection settings, addressingStyle, region and caCerts
+	// +optional
+	ConfigMapRef *corev1.ObjectReference `json:"configMapRef,omitempty"`
+}
+
+// BundleChannel defines the configuration of an offline bundle channel
+type BundleChannel struct {
+	// The local directory, file:///path, or the OCI artifact, oci://registry/repository:tag, of the bundle
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+	// +optional
+	Auth *ChannelAuth `json:"auth,omitempty"`
+	// +optional
+	TLS *ChannelTLS `json:"tls,omitempty"`
+	// +optional
+	Polling *ChannelPolling `json:"polling,omitempty"`
+}
+
+// ChannelSpec defines the desired state of Channel, the configuration block of the channel type is used
+type ChannelSpec struct {
+	// +kubebuilder:validation:Enum={Namespace,HelmRepo,ObjectBucket,GitHub,Git,Bundle,namespace,helmrepo,objectbucket,github,git,bundle}
+	Type chnv1.ChannelType `json:"type"`
+	// The configuration of a Namespace channel
+	// +optional
+	Namespace *NamespaceChannel `json:"namespace,omitempty"`
+	// The configuration of a Git or GitHub channel
+	// +optional
+	Git *GitChannel `json:"git,omitempty"`
+	// The configuration of a HelmRepo channel
+	// +optional
+	HelmRepo *HelmRepoChannel `json:"helmRepo,omitempty"`
+	// The configuration of an ObjectBucket channel
+	// +optional
+	ObjectBucket *ObjectBucketChannel `json:"objectBucket,omitempty"`
+	// The configuration of a Bundle channel
+	// +optional
+	Bundle *BundleChannel `json:"bundle,omitempty"`
+	// The ConfigMap with the JSON schema, in its schema.json key, the package overrides of the subscriptions of the
+	// channel are validated against
+	// +optional
+	PackageOverridesSchemaRef *corev1.LocalObjectReference `json:"packageOverridesSchemaRef,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// Channel is the Schema for the channels API, each channel type has a typed configuration block
+// +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.type",description="type of the channel"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Namespaced
+type Channel struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ChannelSpec         `json:"spec"`
+	Status chnv1.ChannelStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ChannelList contains a list of Channel
+type ChannelList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Channel `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Channel{}, &ChannelList{})
+}
@@ -0,0 +1,151 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// ChannelConversionPath is the path of the channel conversion webhook
+const ChannelConversionPath = "/convert-channel"
+
+// SetupWebhookWithManager registers the conversion webhook of the channels on the webhook server of the manager. The v1
+// channel API is defined by the channel operator module, so the channels are not converted by the conversion webhook of
+// controller-runtime, which requires the v1 type to implement the conversion hub.
+func (r *Channel) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	mgr.GetWebhookServer().Register(ChannelConversionPath, &channelConversionWebhook{})
+
+	return nil
+}
+
+type channelConversionWebhook struct{}
+
+func (wh *channelConversionWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	review := &apiextensionsv1.ConversionReview{}
+
+	if err := json.NewDecoder(r.Body).Decode(review); err != nil || review.Request == nil {
+		klog.Error("failed to decode the channel conversion review, error: ", err)
+		http.Error(w, "invalid conversion review", http.StatusBadRequest)
+
+		return
+	}
+
+	review.Response = convertChannels(review.Request)
+	review.Request = nil
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		klog.Error("failed to write the channel conversion review, error: ", err)
+	}
+}
+
+func convertChannels(req *apiextensionsv1.ConversionRequest) *apiextensionsv1.ConversionResponse {
+	resp := &apiextensionsv1.ConversionResponse{UID: req.UID}
+
+	for _, obj := range req.Objects {
+		converted, err := convertChannel(obj.Raw, req.DesiredAPIVersion)
+		if err != nil {
+			klog.Error("failed to convert the channel, error: ", err)
+
+			resp.Result = metav1.Status{Status: metav1.StatusFailure, Message: err.Error()}
+
+			return resp
+		}
+
+		resp.ConvertedObjects = append(resp.ConvertedObjects, runtime.RawExtension{Raw: converted})
+	}
+
+	resp.Result = metav1.Status{Status: metav1.StatusSuccess}
+
+	return resp
+}
+
+func convertChannel(raw []byte, desiredAPIVersion string) ([]byte, error) {
+	typeMeta := &metav1.TypeMeta{}
+	if err := json.Unmarshal(raw, typeMeta); err != nil {
+		return nil, err
+	}
+
+	if typeMeta.APIVersion == desiredAPIVersion {
+		return raw, nil
+	}
+
+	switch {
+	case typeMeta.APIVersion == chnv1.SchemeGroupVersion.String() && desiredAPIVersion == SchemeGroupVersion.String():
+		src := &chnv1.Channel{}
+		if err := json.Unmarshal(raw, src); err != nil {
+			return nil, err
+		}
+
+		dst, err := ConvertChannelFromV1(src)
+		if err != nil {
+			return nil, err
+		}
+
+		return marshalWithStatus(dst, raw)
+	case typeMeta.APIVersion == SchemeGroupVersion.String() && desiredAPIVersion == chnv1.SchemeGroupVersion.String():
+		src := &Channel{}
+		if err := json.Unmarshal(raw, src); err != nil {
+			return nil, err
+		}
+
+		dst, err := ConvertChannelToV1(src)
+		if err != nil {
+			return nil, err
+		}
+
+		return marshalWithStatus(dst, raw)
+	}
+
+	return nil, fmt.Errorf("unsupported conversion of channel %v to %v", typeMeta.APIVersion, desiredAPIVersion)
+}
+
+// marshalWithStatus marshals the converted channel with the status of the raw channel, the status fields such as the
+// lastGitFetch of the Git channels are not typed in the v1 channel API
+func marshalWithStatus(dst interface{}, raw []byte) ([]byte, error) {
+	converted, err := json.Marshal(dst)
+	if err != nil {
+		return nil, err
+	}
+
+	src := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &src); err != nil {
+		return nil, err
+	}
+
+	status, ok := src["status"]
+	if !ok {
+		return converted, nil
+	}
+
+	obj := map[string]json.RawMessage{}
+	if err := json.Unmarshal(converted, &obj); err != nil {
+		return nil, err
+	}
+
+	obj["status"] = status
+
+	return json.Marshal(obj)
+}
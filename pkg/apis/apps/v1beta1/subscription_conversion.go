@@ -0,0 +1,193 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"fmt"
+	"strconv"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+// promotedAnnotations are the v1 annotations held by the v1beta1 spec fields
+var promotedAnnotations = []string{
+	appv1.AnnotationGitBranch,
+	appv1.AnnotationGitPath,
+	appv1.AnnotationGitTag,
+	appv1.AnnotationGitTargetCommit,
+	appv1.AnnotationGitCloneDepth,
+	appv1.AnnotationBucketPath,
+	appv1.AnnotationBucketLayout,
+	appv1.AnnotationResourceReconcileLevel,
+}
+
+// ConvertTo converts the v1beta1 subscription to v1, the git, bucket and reconcileRate fields are set in the v1
+// annotations. The fields take precedence over the same annotations set in the v1beta1 metadata.
+func (src *Subscription) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*appv1.Subscription)
+	if !ok {
+		return fmt.Errorf("unsupported conversion of subscription %v/%v to %T", src.Namespace, src.Name, dstRaw)
+	}
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Status = *src.Status.DeepCopy()
+
+	spec := src.Spec.DeepCopy()
+	dst.Spec = appv1.SubscriptionSpec{
+		Channel:                           spec.Channel,
+		SecondaryChannel:                  spec.SecondaryChannel,
+		Package:                           spec.Package,
+		PackageFilter:                     spec.PackageFilter,
+		PackageOverrides:                  spec.PackageOverrides,
+		Placement:                         spec.Placement,
+		Overrides:                         spec.Overrides,
+		OverrideRules:                     spec.OverrideRules,
+		TimeWindow:                        spec.TimeWindow,
+		HookSecretRef:                     spec.HookSecretRef,
+		Allow:                             spec.Allow,
+		Deny:                              spec.Deny,
+		WatchHelmNamespaceScopedResources: spec.WatchHelmNamespaceScopedResources,
+		Priority:                          spec.Priority,
+		DependsOn:                         spec.DependsOn,
+		Promotion:                         spec.Promotion,
+		Retry:                             spec.Retry,
+		NamespaceCreation:                 spec.NamespaceCreation,
+		MetadataPropagation:               spec.MetadataPropagation,
+		ProgressDeadline:                  spec.ProgressDeadline,
+	}
+
+	annotations := dst.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	setAnnotation := func(key, value string) {
+		if value != "" {
+			annotations[key] = value
+		}
+	}
+
+	if git := spec.Git; git != nil {
+		setAnnotation(appv1.AnnotationGitBranch, git.Branch)
+		setAnnotation(appv1.AnnotationGitPath, git.Path)
+		setAnnotation(appv1.AnnotationGitTag, git.Tag)
+		setAnnotation(appv1.AnnotationGitTargetCommit, git.DesiredCommit)
+
+		if git.CloneDepth > 0 {
+			setAnnotation(appv1.AnnotationGitCloneDepth, strconv.Itoa(git.CloneDepth))
+		}
+	}
+
+	if bucket := spec.Bucket; bucket != nil {
+		setAnnotation(appv1.AnnotationBucketPath, bucket.Path)
+		setAnnotation(appv1.AnnotationBucketLayout, bucket.Layout)
+	}
+
+	setAnnotation(appv1.AnnotationResourceReconcileLevel, spec.ReconcileRate)
+
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+
+	dst.SetAnnotations(annotations)
+
+	return nil
+}
+
+// ConvertFrom converts the v1 subscription to v1beta1, the promoted annotations are moved to the spec fields. A
+// git-clone-depth annotation which is not a number stays in the annotations, so it is kept when converted back to v1.
+func (dst *Subscription) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*appv1.Subscription)
+	if !ok {
+		return fmt.Errorf("unsupported conversion of %T to subscription %v/%v", srcRaw, dst.Namespace, dst.Name)
+	}
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Status = *src.Status.DeepCopy()
+
+	spec := src.Spec.DeepCopy()
+	dst.Spec = SubscriptionSpec{
+		Channel:                           spec.Channel,
+		SecondaryChannel:                  spec.SecondaryChannel,
+		Package:                           spec.Package,
+		PackageFilter:                     spec.PackageFilter,
+		PackageOverrides:                  spec.PackageOverrides,
+		Placement:                         spec.Placement,
+		Overrides:                         spec.Overrides,
+		OverrideRules:                     spec.OverrideRules,
+		TimeWindow:                        spec.TimeWindow,
+		HookSecretRef:                     spec.HookSecretRef,
+		Allow:                             spec.Allow,
+		Deny:                              spec.Deny,
+		WatchHelmNamespaceScopedResources: spec.WatchHelmNamespaceScopedResources,
+		Priority:                          spec.Priority,
+		DependsOn:                         spec.DependsOn,
+		Promotion:                         spec.Promotion,
+		Retry:                             spec.Retry,
+		NamespaceCreation:                 spec.NamespaceCreation,
+		MetadataPropagation:               spec.MetadataPropagation,
+		ProgressDeadline:                  spec.ProgressDeadline,
+	}
+
+	annotations := dst.GetAnnotations()
+
+	git := &GitSource{
+		Branch:        annotations[appv1.AnnotationGitBranch],
+		Path:          annotations[appv1.AnnotationGitPath],
+		Tag:           annotations[appv1.AnnotationGitTag],
+		DesiredCommit: annotations[appv1.AnnotationGitTargetCommit],
+	}
+
+	keep := map[string]bool{}
+
+	if depth := annotations[appv1.AnnotationGitCloneDepth]; depth != "" {
+		if n, err := strconv.Atoi(depth); err == nil && n > 0 && strconv.Itoa(n) == depth {
+			git.CloneDepth = n
+		} else {
+			keep[appv1.AnnotationGitCloneDepth] = true
+		}
+	}
+
+	if *git != (GitSource{}) {
+		dst.Spec.Git = git
+	}
+
+	bucket := &BucketSource{
+		Path:   annotations[appv1.AnnotationBucketPath],
+		Layout: annotations[appv1.AnnotationBucketLayout],
+	}
+
+	if *bucket != (BucketSource{}) {
+		dst.Spec.Bucket = bucket
+	}
+
+	dst.Spec.ReconcileRate = annotations[appv1.AnnotationResourceReconcileLevel]
+
+	for _, key := range promotedAnnotations {
+		if !keep[key] {
+			delete(annotations, key)
+		}
+	}
+
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+
+	dst.SetAnnotations(annotations)
+
+	return nil
+}
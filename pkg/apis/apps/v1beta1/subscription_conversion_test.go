@@ -0,0 +1,127 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	plrv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func TestSubscriptionConvertible(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(appv1.SchemeBuilder.AddToScheme(scheme)).To(gomega.Succeed())
+	g.Expect(SchemeBuilder.AddToScheme(scheme)).To(gomega.Succeed())
+
+	ok, err := conversion.IsConvertible(scheme, &Subscription{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(ok).To(gomega.BeTrue())
+}
+
+func TestSubscriptionConversion(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	local := true
+	v1Sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "demo",
+			Namespace: "demo-ns",
+			Annotations: map[string]string{
+				appv1.AnnotationGitBranch:              "main",
+				appv1.AnnotationGitPath:                "apps/demo",
+				appv1.AnnotationGitTargetCommit:        "0123abc",
+				appv1.AnnotationGitCloneDepth:          "20",
+				appv1.AnnotationResourceReconcileLevel: "off",
+				appv1.AnnotationWebhookEnabled:         "true",
+			},
+		},
+		Spec: appv1.SubscriptionSpec{
+			Channel:    "ch-ns/git",
+			Placement:  &plrv1.Placement{Local: &local},
+			TimeWindow: &appv1.TimeWindow{WindowType: "active", Daysofweek: []string{"Monday"}},
+			Priority:   10,
+			NamespaceCreation: &appv1.NamespaceCreation{
+				Labels: map[string]string{"pod-security.kubernetes.io/enforce": "restricted"},
+				Prune:  true,
+			},
+		},
+		Status: appv1.SubscriptionStatus{Phase: appv1.SubscriptionSubscribed},
+	}
+
+	v1beta1Sub := &Subscription{}
+	g.Expect(v1beta1Sub.ConvertFrom(v1Sub)).To(gomega.Succeed())
+
+	g.Expect(v1beta1Sub.Spec.Git).To(gomega.Equal(&GitSource{Branch: "main", Path: "apps/demo", DesiredCommit: "0123abc", CloneDepth: 20}))
+	g.Expect(v1beta1Sub.Spec.Bucket).To(gomega.BeNil())
+	g.Expect(v1beta1Sub.Spec.ReconcileRate).To(gomega.Equal("off"))
+	g.Expect(v1beta1Sub.Spec.TimeWindow).To(gomega.Equal(v1Sub.Spec.TimeWindow))
+	g.Expect(v1beta1Sub.Spec.Priority).To(gomega.Equal(int32(10)))
+	g.Expect(v1beta1Sub.Spec.NamespaceCreation).To(gomega.Equal(v1Sub.Spec.NamespaceCreation))
+	g.Expect(v1beta1Sub.Annotations).To(gomega.Equal(map[string]string{appv1.AnnotationWebhookEnabled: "true"}))
+	g.Expect(v1beta1Sub.Status.Phase).To(gomega.Equal(appv1.SubscriptionSubscribed))
+
+	roundTrip := &appv1.Subscription{}
+	g.Expect(v1beta1Sub.ConvertTo(roundTrip)).To(gomega.Succeed())
+	g.Expect(roundTrip).To(gomega.Equal(v1Sub))
+
+	// the converted subscription doesn't share the source fields
+	v1beta1Sub.Spec.TimeWindow.WindowType = "blocked"
+	g.Expect(roundTrip.Spec.TimeWindow.WindowType).To(gomega.Equal("active"))
+}
+
+func TestSubscriptionConversionPrecedence(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	v1beta1Sub := &Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "demo",
+			Namespace:   "demo-ns",
+			Annotations: map[string]string{appv1.AnnotationBucketPath: "old", appv1.AnnotationGitTag: "v1"},
+		},
+		Spec: SubscriptionSpec{
+			Channel: "ch-ns/bucket",
+			Bucket:  &BucketSource{Path: "apps", Layout: "folders"},
+		},
+	}
+
+	v1Sub := &appv1.Subscription{}
+	g.Expect(v1beta1Sub.ConvertTo(v1Sub)).To(gomega.Succeed())
+	g.Expect(v1Sub.Annotations).To(gomega.Equal(map[string]string{
+		appv1.AnnotationBucketPath:   "apps",
+		appv1.AnnotationBucketLayout: "folders",
+		appv1.AnnotationGitTag:       "v1",
+	}))
+
+	// an invalid clone depth stays in the annotations
+	v1Sub.Annotations[appv1.AnnotationGitCloneDepth] = "deep"
+
+	converted := &Subscription{}
+	g.Expect(converted.ConvertFrom(v1Sub)).To(gomega.Succeed())
+	g.Expect(converted.Spec.Git).To(gomega.Equal(&GitSource{Tag: "v1"}))
+	g.Expect(converted.Spec.Bucket).To(gomega.Equal(&BucketSource{Path: "apps", Layout: "folders"}))
+	g.Expect(converted.Annotations).To(gomega.Equal(map[string]string{appv1.AnnotationGitCloneDepth: "deep"}))
+
+	roundTrip := &appv1.Subscription{}
+	g.Expect(converted.ConvertTo(roundTrip)).To(gomega.Succeed())
+	g.Expect(roundTrip.Annotations).To(gomega.Equal(v1Sub.Annotations))
+}
@@ -0,0 +1,127 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	plrv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+// GitSource defines the revision and the path of a Git channel to deploy
+type GitSource struct {
+	// The branch to deploy, the default branch of the repository if empty
+	Branch string `json:"branch,omitempty"`
+	// The path of the resources in the repository, the repository root if empty
+	Path string `json:"path,omitempty"`
+	// The tag to deploy, it takes precedence over the branch
+	Tag string `json:"tag,omitempty"`
+	// The commit to deploy, it takes precedence over the tag and the branch
+	DesiredCommit string `json:"desiredCommit,omitempty"`
+	// The depth of the clone, it must be deep enough to check out the desired commit
+	// +kubebuilder:validation:Minimum=1
+	CloneDepth int `json:"cloneDepth,omitempty"`
+}
+
+// BucketSource defines the objects of an object bucket channel to deploy
+type BucketSource struct {
+	// The folder of the objects in the bucket
+	Path string `json:"path,omitempty"`
+	// The layout of the bucket, flat by default or folders for a folder per package
+	Layout string `json:"layout,omitempty"`
+}
+
+// SubscriptionSpec defines the desired state of Subscription
+type SubscriptionSpec struct {
+	Channel string `json:"channel"`
+	// When fails to connect to the channel, connect to the secondary channel
+	SecondaryChannel string `json:"secondaryChannel,omitempty"`
+	// To specify 1 package in channel
+	Package string `json:"name,omitempty"`
+	// To specify more than 1 package in channel
+	PackageFilter *appv1.PackageFilter `json:"packageFilter,omitempty"`
+	// To provide flexibility to override package in channel with local input
+	PackageOverrides []*appv1.Overrides `json:"packageOverrides,omitempty"`
+	// For hub use only, to specify which clusters to go to
+	Placement *plrv1.Placement `json:"placement,omitempty"`
+	// for hub use only to specify the overrides when apply to clusters
+	Overrides []appv1.ClusterOverrides `json:"overrides,omitempty"`
+	// For hub use only, the package overrides propagated to the clusters matching the rules, evaluated in order
+	OverrideRules []appv1.OverrideRule `json:"overrideRules,omitempty"`
+	// help user control when the subscription will take affect
+	TimeWindow *appv1.TimeWindow `json:"timeWindow,omitempty"`
+	// +optional
+	HookSecretRef *corev1.ObjectReference `json:"hookSecretRef,omitempty"`
+	Allow         []*appv1.AllowDenyItem  `json:"allow,omitempty"`
+	Deny          []*appv1.AllowDenyItem  `json:"deny,omitempty"`
+	// WatchHelmNamespaceScopedResources is used to enable watching namespace scope Helm chart resources
+	WatchHelmNamespaceScopedResources bool `json:"watchHelmNamespaceScopedResources,omitempty"`
+	// Subscriptions with a higher priority are reconciled first by the agent, e.g. after the agent restarts
+	Priority int32 `json:"priority,omitempty"`
+	// The subscriptions that must be deployed on the cluster before this subscription is applied
+	DependsOn []appv1.SubscriptionDependency `json:"dependsOn,omitempty"`
+	// For hub use only, promotes the Git commits through rings of decision groups
+	Promotion *appv1.Promotion `json:"promotion,omitempty"`
+	// For hub use only, retries the clusters failing to deploy the subscription
+	Retry *appv1.RetryPolicy `json:"retry,omitempty"`
+	// The labels and annotations of the missing target namespaces created on the managed cluster
+	NamespaceCreation *appv1.NamespaceCreation `json:"namespaceCreation,omitempty"`
+	// The labels and annotations of the subscription set on all its deployed resources
+	MetadataPropagation *appv1.MetadataPropagation `json:"metadataPropagation,omitempty"`
+	// The time the subscription may progress on a managed cluster, until all its resources are deployed and healthy,
+	// before it is stalled
+	ProgressDeadline *metav1.Duration `json:"progressDeadline,omitempty"`
+	// The revision and the path of a Git channel, the git-* annotations of v1
+	// +optional
+	Git *GitSource `json:"git,omitempty"`
+	// The objects of an object bucket channel, the bucket-* annotations of v1
+	// +optional
+	Bucket *BucketSource `json:"bucket,omitempty"`
+	// off turns off the periodic reconcile of the channel resources, the reconcile-rate annotation of v1
+	// +optional
+	ReconcileRate string `json:"reconcileRate,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// Subscription is the Schema for the subscriptions API, the annotations of the v1 API are promoted to spec fields
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="SubscriptionState",type="string",JSONPath=".status.phase",description="subscription state"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Local placement",type="boolean",JSONPath=".spec.placement.local"
+// +kubebuilder:printcolumn:name="Time window",type="string",JSONPath=".spec.timeWindow.windowtype"
+// +kubebuilder:resource:shortName=appsub
+type Subscription struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SubscriptionSpec         `json:"spec"`
+	Status appv1.SubscriptionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SubscriptionList contains a list of Subscription
+type SubscriptionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Subscription `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Subscription{}, &SubscriptionList{})
+}
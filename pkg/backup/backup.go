@@ -0,0 +1,609 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package backup exports the subscription state of a hub into a portable bundle and restores it on a new hub.
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	clusterapi "open-cluster-management.io/api/cluster/v1beta1"
+	manifestWorkV1 "open-cluster-management.io/api/work/v1"
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	plrv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+const (
+	// BundleKind is the kind of the backup bundle
+	BundleKind = "SubscriptionBackup"
+	// BundleAPIVersion is the version of the backup bundle format
+	BundleAPIVersion = "apps.open-cluster-management.io/v1"
+)
+
+// Bundle holds the subscription state of a hub. Secrets are only referenced, their content is never exported.
+type Bundle struct {
+	metav1.TypeMeta `json:",inline"`
+	CreatedAt       metav1.Time            `json:"createdAt"`
+	Subscriptions   []appv1.Subscription   `json:"subscriptions,omitempty"`
+	Channels        []chnv1.Channel        `json:"channels,omitempty"`
+	Placements      []clusterapi.Placement `json:"placements,omitempty"`
+	PlacementRules  []plrv1.PlacementRule  `json:"placementRules,omitempty"`
+	SecretRefs      []ObjectRef            `json:"secretRefs,omitempty"`
+	ConfigMapRefs   []ObjectRef            `json:"configMapRefs,omitempty"`
+	RevisionPins    []RevisionPin          `json:"revisionPins,omitempty"`
+}
+
+// ObjectRef references a secret or configmap the subscriptions and channels depend on
+type ObjectRef struct {
+	Namespace    string `json:"namespace"`
+	Name         string `json:"name"`
+	ReferencedBy string `json:"referencedBy"`
+}
+
+// RevisionPin records the git revision a managed cluster is pinned to by a subscription
+type RevisionPin struct {
+	Subscription string `json:"subscription"`
+	Cluster      string `json:"cluster"`
+	Commit       string `json:"commit,omitempty"`
+	Tag          string `json:"tag,omitempty"`
+}
+
+// ImportOptions defines how the bundle is restored on the new hub
+type ImportOptions struct {
+	// NamespaceMap renames the namespaces of the subscriptions, channels and their references
+	NamespaceMap map[string]string
+	// ClusterMap renames the managed clusters in placements, placementRules, overrides and revision pins
+	ClusterMap map[string]string
+	DryRun     bool
+}
+
+// annotations computed by the hub, they are regenerated after the import
+var hubComputedAnnotations = []string{
+	appv1.AnnotationDeployables,
+	appv1.AnnotationTopo,
+	appv1.AnnotationGitCommit,
+	appv1.AnnotationChannelGeneration,
+	appv1.AnnotationWebhookEventCount,
+	"kubectl.kubernetes.io/last-applied-configuration",
+}
+
+// Export collects the hub subscriptions in the namespace (all namespaces if empty), their channels, placements,
+// secret references and the revision each managed cluster is pinned to
+func Export(ctx context.Context, c client.Client, namespace string) (*Bundle, error) {
+	bundle := &Bundle{
+		TypeMeta:  metav1.TypeMeta{Kind: BundleKind, APIVersion: BundleAPIVersion},
+		CreatedAt: metav1.Now(),
+	}
+
+	subList := &appv1.SubscriptionList{}
+	if err := c.List(ctx, subList, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list subscriptions, err: %w", err)
+	}
+
+	channels := map[string]*chnv1.Channel{}
+	placements := map[string]bool{}
+	secretRefs := map[string]ObjectRef{}
+	configMapRefs := map[string]ObjectRef{}
+
+	for i := range subList.Items {
+		sub := subList.Items[i]
+
+		// subscriptions propagated from another subscription are regenerated, skip them
+		if utils.IsHostingAppsub(&sub) {
+			continue
+		}
+
+		subKey := types.NamespacedName{Namespace: sub.Namespace, Name: sub.Name}.String()
+
+		pins, err := getRevisionPins(ctx, c, &sub)
+		if err != nil {
+			klog.Warningf("failed to get the revision pins of subscription %v, err: %v", subKey, err)
+		}
+
+		bundle.RevisionPins = append(bundle.RevisionPins, pins...)
+
+		if err := addPlacement(ctx, c, bundle, placements, &sub); err != nil {
+			klog.Warningf("failed to get the placement of subscription %v, err: %v", subKey, err)
+		}
+
+		if sub.Spec.HookSecretRef != nil {
+			addRef(secretRefs, sub.Namespace, sub.Spec.HookSecretRef.Name, subKey)
+		}
+
+		for _, chnName := range []string{sub.Spec.Channel, sub.Spec.SecondaryChannel} {
+			if chnName == "" {
+				continue
+			}
+
+			chnKey := utils.NamespacedNameFormat(chnName)
+			if _, ok := channels[chnKey.String()]; ok {
+				continue
+			}
+
+			chn := &chnv1.Channel{}
+			if err := c.Get(ctx, chnKey, chn); err != nil {
+				klog.Warningf("failed to get channel %v of subscription %v, err: %v", chnKey, subKey, err)
+				continue
+			}
+
+			channels[chnKey.String()] = chn
+
+			if chn.Spec.SecretRef != nil {
+				addRef(secretRefs, chn.Namespace, chn.Spec.SecretRef.Name, "channel "+chnKey.String())
+			}
+
+			if chn.Spec.ConfigMapRef != nil {
+				addRef(configMapRefs, chn.Namespace, chn.Spec.ConfigMapRef.Name, "channel "+chnKey.String())
+			}
+		}
+
+		bundle.Subscriptions = append(bundle.Subscriptions, *cleanSubscription(&sub))
+	}
+
+	for _, chn := range channels {
+		bundle.Channels = append(bundle.Channels, *cleanChannel(chn))
+	}
+
+	sort.Slice(bundle.Channels, func(i, j int) bool {
+		return bundle.Channels[i].Namespace+"/"+bundle.Channels[i].Name < bundle.Channels[j].Namespace+"/"+bundle.Channels[j].Name
+	})
+
+	bundle.SecretRefs = sortedRefs(secretRefs)
+	bundle.ConfigMapRefs = sortedRefs(configMapRefs)
+
+	return bundle, nil
+}
+
+// addPlacement adds the Placement or PlacementRule of the placementRef of the subscription to the bundle once
+func addPlacement(ctx context.Context, c client.Client, bundle *Bundle, added map[string]bool,
+	sub *appv1.Subscription) error {
+	if sub.Spec.Placement == nil || sub.Spec.Placement.PlacementRef == nil || sub.Spec.Placement.PlacementRef.Name == "" {
+		return nil
+	}
+
+	ref := sub.Spec.Placement.PlacementRef
+	key := types.NamespacedName{Namespace: sub.Namespace, Name: ref.Name}
+	isPlacement := strings.EqualFold(ref.Kind, "Placement")
+
+	addedKey := "PlacementRule/" + key.String()
+	if isPlacement {
+		addedKey = "Placement/" + key.String()
+	}
+
+	if added[addedKey] {
+		return nil
+	}
+
+	if isPlacement {
+		placement := &clusterapi.Placement{}
+		if err := c.Get(ctx, key, placement); err != nil {
+			return err
+		}
+
+		bundle.Placements = append(bundle.Placements, clusterapi.Placement{
+			TypeMeta:   metav1.TypeMeta{Kind: "Placement", APIVersion: clusterapi.GroupVersion.String()},
+			ObjectMeta: cleanObjectMeta(placement.ObjectMeta),
+			Spec:       *placement.Spec.DeepCopy(),
+		})
+	} else {
+		placementRule := &plrv1.PlacementRule{}
+		if err := c.Get(ctx, key, placementRule); err != nil {
+			return err
+		}
+
+		bundle.PlacementRules = append(bundle.PlacementRules, plrv1.PlacementRule{
+			TypeMeta:   metav1.TypeMeta{Kind: "PlacementRule", APIVersion: plrv1.SchemeGroupVersion.String()},
+			ObjectMeta: cleanObjectMeta(placementRule.ObjectMeta),
+			Spec:       *placementRule.Spec.DeepCopy(),
+		})
+	}
+
+	added[addedKey] = true
+
+	return nil
+}
+
+func addRef(refs map[string]ObjectRef, namespace, name, referencedBy string) {
+	key := namespace + "/" + name
+
+	if ref, ok := refs[key]; ok {
+		ref.ReferencedBy += "," + referencedBy
+		refs[key] = ref
+
+		return
+	}
+
+	refs[key] = ObjectRef{Namespace: namespace, Name: name, ReferencedBy: referencedBy}
+}
+
+func sortedRefs(refs map[string]ObjectRef) []ObjectRef {
+	res := make([]ObjectRef, 0, len(refs))
+
+	for _, ref := range refs {
+		res = append(res, ref)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Namespace+"/"+res[i].Name < res[j].Namespace+"/"+res[j].Name
+	})
+
+	return res
+}
+
+// getRevisionPins reads the subscription propagated to each managed cluster through the manifestWorks
+func getRevisionPins(ctx context.Context, c client.Client, sub *appv1.Subscription) ([]RevisionPin, error) {
+	mwList := &manifestWorkV1.ManifestWorkList{}
+	hosting := fmt.Sprintf("%.63s", sub.GetNamespace()+"."+sub.GetName())
+
+	if err := c.List(ctx, mwList, client.MatchingLabels{appv1.AnnotationHosting: hosting}); err != nil {
+		return nil, err
+	}
+
+	subKey := types.NamespacedName{Namespace: sub.Namespace, Name: sub.Name}.String()
+	pins := []RevisionPin{}
+
+	for _, mw := range mwList.Items {
+		if mw.GetName() != sub.GetNamespace()+"-"+sub.GetName() {
+			continue
+		}
+
+		pin := RevisionPin{
+			Subscription: subKey,
+			Cluster:      mw.GetNamespace(),
+			Commit:       sub.GetAnnotations()[appv1.AnnotationGitCommit],
+		}
+
+		for _, manifest := range mw.Spec.Workload.Manifests {
+			managedSub := &appv1.Subscription{}
+			if err := json.Unmarshal(manifest.Raw, managedSub); err != nil || managedSub.Kind != "Subscription" {
+				continue
+			}
+
+			annotations := managedSub.GetAnnotations()
+			if annotations[appv1.AnnotationGitTargetCommit] != "" {
+				pin.Commit = annotations[appv1.AnnotationGitTargetCommit]
+			}
+
+			pin.Tag = annotations[appv1.AnnotationGitTag]
+		}
+
+		if pin.Commit == "" && pin.Tag == "" {
+			continue
+		}
+
+		pins = append(pins, pin)
+	}
+
+	sort.Slice(pins, func(i, j int) bool { return pins[i].Cluster < pins[j].Cluster })
+
+	return pins, nil
+}
+
+func cleanObjectMeta(meta metav1.ObjectMeta) metav1.ObjectMeta {
+	annotations := map[string]string{}
+
+	for k, v := range meta.Annotations {
+		annotations[k] = v
+	}
+
+	for _, k := range hubComputedAnnotations {
+		delete(annotations, k)
+	}
+
+	return metav1.ObjectMeta{
+		Name:        meta.Name,
+		Namespace:   meta.Namespace,
+		Labels:      meta.Labels,
+		Annotations: annotations,
+	}
+}
+
+func cleanSubscription(sub *appv1.Subscription) *appv1.Subscription {
+	return &appv1.Subscription{
+		TypeMeta:   metav1.TypeMeta{Kind: "Subscription", APIVersion: appv1.SchemeGroupVersion.String()},
+		ObjectMeta: cleanObjectMeta(sub.ObjectMeta),
+		Spec:       *sub.Spec.DeepCopy(),
+	}
+}
+
+func cleanChannel(chn *chnv1.Channel) *chnv1.Channel {
+	return &chnv1.Channel{
+		TypeMeta:   metav1.TypeMeta{Kind: "Channel", APIVersion: chnv1.SchemeGroupVersion.String()},
+		ObjectMeta: cleanObjectMeta(chn.ObjectMeta),
+		Spec:       *chn.Spec.DeepCopy(),
+	}
+}
+
+func remap(m map[string]string, s string) string {
+	if v, ok := m[s]; ok && v != "" {
+		return v
+	}
+
+	return s
+}
+
+// remapChannelName remaps the namespace of a "namespace/name" channel reference
+func remapChannelName(m map[string]string, chnName string) string {
+	if chnName == "" {
+		return chnName
+	}
+
+	key := utils.NamespacedNameFormat(chnName)
+	if key.Namespace == "" {
+		return chnName
+	}
+
+	return remap(m, key.Namespace) + "/" + key.Name
+}
+
+// Remap applies the namespace and cluster identity mapping to the bundle
+func Remap(bundle *Bundle, opts ImportOptions) *Bundle {
+	out := &Bundle{
+		TypeMeta:  bundle.TypeMeta,
+		CreatedAt: bundle.CreatedAt,
+	}
+
+	for i := range bundle.Subscriptions {
+		sub := bundle.Subscriptions[i].DeepCopy()
+		sub.Namespace = remap(opts.NamespaceMap, sub.Namespace)
+		sub.Spec.Channel = remapChannelName(opts.NamespaceMap, sub.Spec.Channel)
+		sub.Spec.SecondaryChannel = remapChannelName(opts.NamespaceMap, sub.Spec.SecondaryChannel)
+
+		if sub.Spec.Placement != nil {
+			for j := range sub.Spec.Placement.Clusters {
+				sub.Spec.Placement.Clusters[j].Name = remap(opts.ClusterMap, sub.Spec.Placement.Clusters[j].Name)
+			}
+		}
+
+		for j := range sub.Spec.Overrides {
+			sub.Spec.Overrides[j].ClusterName = remap(opts.ClusterMap, sub.Spec.Overrides[j].ClusterName)
+		}
+
+		out.Subscriptions = append(out.Subscriptions, *sub)
+	}
+
+	for i := range bundle.Channels {
+		chn := bundle.Channels[i].DeepCopy()
+		chn.Namespace = remap(opts.NamespaceMap, chn.Namespace)
+		out.Channels = append(out.Channels, *chn)
+	}
+
+	for i := range bundle.Placements {
+		placement := bundle.Placements[i].DeepCopy()
+		placement.Namespace = remap(opts.NamespaceMap, placement.Namespace)
+		out.Placements = append(out.Placements, *placement)
+	}
+
+	for i := range bundle.PlacementRules {
+		placementRule := bundle.PlacementRules[i].DeepCopy()
+		placementRule.Namespace = remap(opts.NamespaceMap, placementRule.Namespace)
+
+		for j := range placementRule.Spec.Clusters {
+			placementRule.Spec.Clusters[j].Name = remap(opts.ClusterMap, placementRule.Spec.Clusters[j].Name)
+		}
+
+		out.PlacementRules = append(out.PlacementRules, *placementRule)
+	}
+
+	for _, ref := range bundle.SecretRefs {
+		ref.Namespace = remap(opts.NamespaceMap, ref.Namespace)
+		out.SecretRefs = append(out.SecretRefs, ref)
+	}
+
+	for _, ref := range bundle.ConfigMapRefs {
+		ref.Namespace = remap(opts.NamespaceMap, ref.Namespace)
+		out.ConfigMapRefs = append(out.ConfigMapRefs, ref)
+	}
+
+	for _, pin := range bundle.RevisionPins {
+		key := utils.NamespacedNameFormat(pin.Subscription)
+		pin.Subscription = types.NamespacedName{Namespace: remap(opts.NamespaceMap, key.Namespace), Name: key.Name}.String()
+		pin.Cluster = remap(opts.ClusterMap, pin.Cluster)
+		out.RevisionPins = append(out.RevisionPins, pin)
+	}
+
+	return out
+}
+
+// ApplyRevisionPins pins the subscription to the revision recorded for its clusters, so the fleet doesn't move
+// to a new commit when the new hub starts. If the clusters are pinned to different revisions, each cluster is pinned to
+// its own revision by the cluster-revision-pins annotation of the subscription.
+func ApplyRevisionPins(sub *appv1.Subscription, pins []RevisionPin) {
+	subKey := types.NamespacedName{Namespace: sub.Namespace, Name: sub.Name}.String()
+	clusterPins := map[string]appv1.ClusterRevisionPin{}
+	commit, tag := "", ""
+	same := true
+
+	for _, pin := range pins {
+		if pin.Subscription != subKey {
+			continue
+		}
+
+		if len(clusterPins) == 0 {
+			commit, tag = pin.Commit, pin.Tag
+		} else if pin.Commit != commit || pin.Tag != tag {
+			same = false
+		}
+
+		clusterPins[pin.Cluster] = appv1.ClusterRevisionPin{Commit: pin.Commit, Tag: pin.Tag}
+	}
+
+	if len(clusterPins) == 0 {
+		return
+	}
+
+	annotations := sub.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	if !same {
+		// the pins only hold strings, json.Marshal never fails on them
+		pinsJSON, _ := json.Marshal(clusterPins)
+		annotations[appv1.AnnotationClusterRevisionPins] = string(pinsJSON)
+		sub.SetAnnotations(annotations)
+
+		return
+	}
+
+	if tag != "" {
+		annotations[appv1.AnnotationGitTag] = tag
+	}
+
+	if commit != "" && annotations[appv1.AnnotationGitTag] == "" {
+		annotations[appv1.AnnotationGitTargetCommit] = commit
+	}
+
+	sub.SetAnnotations(annotations)
+}
+
+// Import restores the bundle on the hub. Secrets and configmaps must be restored separately, the missing ones are reported.
+func Import(ctx context.Context, c client.Client, bundle *Bundle, opts ImportOptions) error {
+	if bundle.Kind != BundleKind {
+		return fmt.Errorf("invalid bundle kind %v, expecting %v", bundle.Kind, BundleKind)
+	}
+
+	remapped := Remap(bundle, opts)
+	errs := []string{}
+
+	for _, ref := range remapped.SecretRefs {
+		if err := c.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, &corev1.Secret{}); err != nil {
+			klog.Warningf("secret %v/%v referenced by %v is not found on the hub, restore it before the subscriptions can work",
+				ref.Namespace, ref.Name, ref.ReferencedBy)
+		}
+	}
+
+	for _, ref := range remapped.ConfigMapRefs {
+		if err := c.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, &corev1.ConfigMap{}); err != nil {
+			klog.Warningf("configmap %v/%v referenced by %v is not found on the hub, restore it before the subscriptions can work",
+				ref.Namespace, ref.Name, ref.ReferencedBy)
+		}
+	}
+
+	namespaces := map[string]bool{}
+
+	for _, chn := range remapped.Channels {
+		namespaces[chn.Namespace] = true
+	}
+
+	for _, placement := range remapped.Placements {
+		namespaces[placement.Namespace] = true
+	}
+
+	for _, placementRule := range remapped.PlacementRules {
+		namespaces[placementRule.Namespace] = true
+	}
+
+	for _, sub := range remapped.Subscriptions {
+		namespaces[sub.Namespace] = true
+	}
+
+	// the namespaces created by a dry run are not persisted, the objects in them fail with NotFound and are only
+	// reported as would be created
+	dryRunNamespaces := map[string]bool{}
+
+	for ns := range namespaces {
+		created, err := createIfNotExist(ctx, c, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}}, opts.DryRun, nil)
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+
+		if created && opts.DryRun {
+			dryRunNamespaces[ns] = true
+		}
+	}
+
+	for i := range remapped.Channels {
+		if _, err := createIfNotExist(ctx, c, &remapped.Channels[i], opts.DryRun, dryRunNamespaces); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	for i := range remapped.Placements {
+		if _, err := createIfNotExist(ctx, c, &remapped.Placements[i], opts.DryRun, dryRunNamespaces); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	for i := range remapped.PlacementRules {
+		if _, err := createIfNotExist(ctx, c, &remapped.PlacementRules[i], opts.DryRun, dryRunNamespaces); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	for i := range remapped.Subscriptions {
+		sub := &remapped.Subscriptions[i]
+
+		ApplyRevisionPins(sub, remapped.RevisionPins)
+
+		if _, err := createIfNotExist(ctx, c, sub, opts.DryRun, dryRunNamespaces); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to import the bundle: %v", strings.Join(errs, ";"))
+	}
+
+	return nil
+}
+
+// createIfNotExist never overwrites the existing objects on the new hub. It returns true if the object is created, or
+// would be created by a dry run
+func createIfNotExist(ctx context.Context, c client.Client, obj client.Object, dryRun bool,
+	dryRunNamespaces map[string]bool) (bool, error) {
+	opts := []client.CreateOption{}
+	if dryRun {
+		opts = append(opts, client.DryRunAll)
+	}
+
+	err := c.Create(ctx, obj, opts...)
+	if kerrors.IsAlreadyExists(err) {
+		klog.Infof("%T %v/%v already exists, skip", obj, obj.GetNamespace(), obj.GetName())
+
+		return false, nil
+	}
+
+	if kerrors.IsNotFound(err) && dryRunNamespaces[obj.GetNamespace()] {
+		klog.Infof("%T %v/%v would be created in the new namespace %v", obj, obj.GetNamespace(), obj.GetName(),
+			obj.GetNamespace())
+
+		return true, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("failed to create %T %v/%v, err: %w", obj, obj.GetNamespace(), obj.GetName(), err)
+	}
+
+	if dryRun {
+		klog.Infof("%T %v/%v would be restored", obj, obj.GetNamespace(), obj.GetName())
+	} else {
+		klog.Infof("%T %v/%v restored", obj, obj.GetNamespace(), obj.GetName())
+	}
+
+	return true, nil
+}
@@ -0,0 +1,308 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	clusterapi "open-cluster-management.io/api/cluster/v1beta1"
+	manifestWorkV1 "open-cluster-management.io/api/work/v1"
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	subapis "open-cluster-management.io/multicloud-operators-subscription/pkg/apis"
+	plrv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := subapis.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	return scheme
+}
+
+func TestRemap(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	bundle := &Bundle{
+		TypeMeta: metav1.TypeMeta{Kind: BundleKind, APIVersion: BundleAPIVersion},
+		Subscriptions: []appv1.Subscription{{
+			ObjectMeta: metav1.ObjectMeta{Name: "sub", Namespace: "old-ns"},
+			Spec: appv1.SubscriptionSpec{
+				Channel:          "old-chn-ns/chn",
+				SecondaryChannel: "other/chn2",
+				Placement: &plrv1.Placement{
+					GenericPlacementFields: plrv1.GenericPlacementFields{
+						Clusters: []plrv1.GenericClusterReference{{Name: "cluster1"}, {Name: "cluster2"}},
+					},
+				},
+				Overrides: []appv1.ClusterOverrides{{ClusterName: "cluster1"}},
+			},
+		}},
+		Channels:   []chnv1.Channel{{ObjectMeta: metav1.ObjectMeta{Name: "chn", Namespace: "old-chn-ns"}}},
+		Placements: []clusterapi.Placement{{ObjectMeta: metav1.ObjectMeta{Name: "placement", Namespace: "old-ns"}}},
+		PlacementRules: []plrv1.PlacementRule{{
+			ObjectMeta: metav1.ObjectMeta{Name: "rule", Namespace: "old-ns"},
+			Spec: plrv1.PlacementRuleSpec{
+				GenericPlacementFields: plrv1.GenericPlacementFields{
+					Clusters: []plrv1.GenericClusterReference{{Name: "cluster1"}},
+				},
+			},
+		}},
+		SecretRefs: []ObjectRef{{Namespace: "old-chn-ns", Name: "git-secret"}},
+		RevisionPins: []RevisionPin{
+			{Subscription: "old-ns/sub", Cluster: "cluster1", Commit: "abc"},
+		},
+	}
+
+	out := Remap(bundle, ImportOptions{
+		NamespaceMap: map[string]string{"old-ns": "new-ns", "old-chn-ns": "new-chn-ns"},
+		ClusterMap:   map[string]string{"cluster1": "new-cluster1"},
+	})
+
+	sub := out.Subscriptions[0]
+	g.Expect(sub.Namespace).To(gomega.Equal("new-ns"))
+	g.Expect(sub.Spec.Channel).To(gomega.Equal("new-chn-ns/chn"))
+	g.Expect(sub.Spec.SecondaryChannel).To(gomega.Equal("other/chn2"))
+	g.Expect(sub.Spec.Placement.Clusters[0].Name).To(gomega.Equal("new-cluster1"))
+	g.Expect(sub.Spec.Placement.Clusters[1].Name).To(gomega.Equal("cluster2"))
+	g.Expect(sub.Spec.Overrides[0].ClusterName).To(gomega.Equal("new-cluster1"))
+	g.Expect(out.Channels[0].Namespace).To(gomega.Equal("new-chn-ns"))
+	g.Expect(out.Placements[0].Namespace).To(gomega.Equal("new-ns"))
+	g.Expect(out.PlacementRules[0].Namespace).To(gomega.Equal("new-ns"))
+	g.Expect(out.PlacementRules[0].Spec.Clusters[0].Name).To(gomega.Equal("new-cluster1"))
+	g.Expect(out.SecretRefs[0].Namespace).To(gomega.Equal("new-chn-ns"))
+	g.Expect(out.RevisionPins[0]).To(gomega.Equal(RevisionPin{Subscription: "new-ns/sub", Cluster: "new-cluster1", Commit: "abc"}))
+
+	// the source bundle is left untouched
+	g.Expect(bundle.Subscriptions[0].Namespace).To(gomega.Equal("old-ns"))
+	g.Expect(bundle.Subscriptions[0].Spec.Placement.Clusters[0].Name).To(gomega.Equal("cluster1"))
+	g.Expect(bundle.PlacementRules[0].Spec.Clusters[0].Name).To(gomega.Equal("cluster1"))
+}
+
+func TestApplyRevisionPins(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	sub := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "sub", Namespace: "ns"}}
+
+	ApplyRevisionPins(sub, []RevisionPin{
+		{Subscription: "ns/sub", Cluster: "c1", Commit: "abc"},
+		{Subscription: "ns/sub", Cluster: "c2", Commit: "abc"},
+		{Subscription: "ns/other", Cluster: "c1", Commit: "def"},
+	})
+	g.Expect(sub.GetAnnotations()[appv1.AnnotationGitTargetCommit]).To(gomega.Equal("abc"))
+	g.Expect(sub.GetAnnotations()).NotTo(gomega.HaveKey(appv1.AnnotationClusterRevisionPins))
+
+	// the clusters pinned to different revisions keep their own revision
+	sub = &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "sub", Namespace: "ns"}}
+
+	ApplyRevisionPins(sub, []RevisionPin{
+		{Subscription: "ns/sub", Cluster: "c1", Commit: "abc"},
+		{Subscription: "ns/sub", Cluster: "c2", Commit: "def", Tag: "v2.0"},
+	})
+	g.Expect(sub.GetAnnotations()).NotTo(gomega.HaveKey(appv1.AnnotationGitTargetCommit))
+	g.Expect(sub.GetAnnotations()[appv1.AnnotationClusterRevisionPins]).To(gomega.MatchJSON(
+		`{"c1":{"commit":"abc"},"c2":{"commit":"def","tag":"v2.0"}}`))
+
+	sub = &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "sub", Namespace: "ns"}}
+
+	ApplyRevisionPins(sub, []RevisionPin{
+		{Subscription: "ns/sub", Cluster: "c1", Commit: "abc", Tag: "v1.0"},
+	})
+	g.Expect(sub.GetAnnotations()[appv1.AnnotationGitTag]).To(gomega.Equal("v1.0"))
+	g.Expect(sub.GetAnnotations()).NotTo(gomega.HaveKey(appv1.AnnotationGitTargetCommit))
+
+	sub = &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "sub", Namespace: "ns"}}
+
+	ApplyRevisionPins(sub, nil)
+	g.Expect(sub.GetAnnotations()).To(gomega.BeEmpty())
+}
+
+func TestExportImport(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	ctx := context.TODO()
+
+	chn := &chnv1.Channel{
+		ObjectMeta: metav1.ObjectMeta{Name: "chn", Namespace: "chn-ns", ResourceVersion: "10"},
+		Spec: chnv1.ChannelSpec{
+			Type:      chnv1.ChannelTypeGit,
+			Pathname:  "https://github.com/open-cluster-management-io/multicloud-operators-subscription",
+			SecretRef: &corev1.ObjectReference{Name: "git-secret"},
+		},
+	}
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sub",
+			Namespace: "app-ns",
+			UID:       types.UID("1234"),
+			Annotations: map[string]string{
+				appv1.AnnotationGitCommit:   "current",
+				appv1.AnnotationGitBranch:   "main",
+				appv1.AnnotationDeployables: "app-ns/dpl",
+			},
+		},
+		Spec: appv1.SubscriptionSpec{
+			Channel: "chn-ns/chn",
+			Placement: &plrv1.Placement{
+				PlacementRef: &corev1.ObjectReference{Kind: "Placement", Name: "placement"},
+			},
+		},
+	}
+
+	placement := &clusterapi.Placement{
+		ObjectMeta: metav1.ObjectMeta{Name: "placement", Namespace: "app-ns", ResourceVersion: "5"},
+		Spec:       clusterapi.PlacementSpec{ClusterSets: []string{"prod"}},
+	}
+
+	manifestWork := func(cluster, commit string) *manifestWorkV1.ManifestWork {
+		managedSub := &appv1.Subscription{
+			TypeMeta: metav1.TypeMeta{Kind: "Subscription", APIVersion: appv1.SchemeGroupVersion.String()},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "sub",
+				Namespace:   "app-ns",
+				Annotations: map[string]string{appv1.AnnotationGitTargetCommit: commit},
+			},
+		}
+
+		raw, err := json.Marshal(managedSub)
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+
+		return &manifestWorkV1.ManifestWork{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "app-ns-sub",
+				Namespace: cluster,
+				Labels:    map[string]string{appv1.AnnotationHosting: "app-ns.sub"},
+			},
+			Spec: manifestWorkV1.ManifestWorkSpec{
+				Workload: manifestWorkV1.ManifestsTemplate{
+					Manifests: []manifestWorkV1.Manifest{{RawExtension: runtime.RawExtension{Raw: raw}}},
+				},
+			},
+		}
+	}
+
+	scheme := newScheme(t)
+	src := fake.NewClientBuilder().WithScheme(scheme).WithObjects(chn, sub, placement,
+		manifestWork("cluster1", "pinned1"), manifestWork("cluster2", "pinned2")).Build()
+
+	bundle, err := Export(ctx, src, "")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(bundle.Subscriptions).To(gomega.HaveLen(1))
+	g.Expect(bundle.Subscriptions[0].UID).To(gomega.BeEmpty())
+	g.Expect(bundle.Subscriptions[0].Annotations).NotTo(gomega.HaveKey(appv1.AnnotationDeployables))
+	g.Expect(bundle.Subscriptions[0].Annotations).To(gomega.HaveKey(appv1.AnnotationGitBranch))
+	g.Expect(bundle.Channels).To(gomega.HaveLen(1))
+	g.Expect(bundle.Channels[0].ResourceVersion).To(gomega.BeEmpty())
+	g.Expect(bundle.SecretRefs).To(gomega.Equal([]ObjectRef{{Namespace: "chn-ns", Name: "git-secret", ReferencedBy: "channel chn-ns/chn"}}))
+	g.Expect(bundle.Placements).To(gomega.HaveLen(1))
+	g.Expect(bundle.Placements[0].ResourceVersion).To(gomega.BeEmpty())
+	g.Expect(bundle.RevisionPins).To(gomega.Equal([]RevisionPin{
+		{Subscription: "app-ns/sub", Cluster: "cluster1", Commit: "pinned1"},
+		{Subscription: "app-ns/sub", Cluster: "cluster2", Commit: "pinned2"},
+	}))
+
+	dst := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	err = Import(ctx, dst, bundle, ImportOptions{
+		NamespaceMap: map[string]string{"app-ns": "new-app-ns"},
+		ClusterMap:   map[string]string{"cluster1": "new-cluster1"},
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	restored := &appv1.Subscription{}
+	g.Expect(dst.Get(ctx, types.NamespacedName{Name: "sub", Namespace: "new-app-ns"}, restored)).To(gomega.Succeed())
+	g.Expect(restored.Spec.Channel).To(gomega.Equal("chn-ns/chn"))
+	g.Expect(restored.GetAnnotations()[appv1.AnnotationClusterRevisionPins]).To(gomega.MatchJSON(
+		`{"new-cluster1":{"commit":"pinned1"},"cluster2":{"commit":"pinned2"}}`))
+
+	restoredPlacement := &clusterapi.Placement{}
+	g.Expect(dst.Get(ctx, types.NamespacedName{Name: "placement", Namespace: "new-app-ns"}, restoredPlacement)).To(gomega.Succeed())
+	g.Expect(restoredPlacement.Spec.ClusterSets).To(gomega.Equal([]string{"prod"}))
+
+	restoredChn := &chnv1.Channel{}
+	g.Expect(dst.Get(ctx, types.NamespacedName{Name: "chn", Namespace: "chn-ns"}, restoredChn)).To(gomega.Succeed())
+
+	// importing again doesn't fail on the existing objects
+	g.Expect(Import(ctx, dst, bundle, ImportOptions{NamespaceMap: map[string]string{"app-ns": "new-app-ns"}})).To(gomega.Succeed())
+}
+
+// namespacedClient fails the creation of the objects in the missing namespaces like the API server does
+type namespacedClient struct {
+	client.Client
+}
+
+func (c namespacedClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if obj.GetNamespace() != "" {
+		if err := c.Get(ctx, types.NamespacedName{Name: obj.GetNamespace()}, &corev1.Namespace{}); err != nil {
+			return kerrors.NewNotFound(corev1.Resource("namespaces"), obj.GetNamespace())
+		}
+	}
+
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func TestImportDryRun(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	ctx := context.TODO()
+
+	bundle := &Bundle{
+		TypeMeta: metav1.TypeMeta{Kind: BundleKind, APIVersion: BundleAPIVersion},
+		Channels: []chnv1.Channel{{
+			ObjectMeta: metav1.ObjectMeta{Name: "chn", Namespace: "chn-ns"},
+			Spec:       chnv1.ChannelSpec{Type: chnv1.ChannelTypeGit, Pathname: "https://github.com/example/repo"},
+		}},
+		Subscriptions: []appv1.Subscription{{
+			ObjectMeta: metav1.ObjectMeta{Name: "sub", Namespace: "app-ns"},
+			Spec:       appv1.SubscriptionSpec{Channel: "chn-ns/chn"},
+		}},
+	}
+
+	existingNs := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "chn-ns"}}
+	dst := namespacedClient{fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(existingNs).Build()}
+
+	// the subscription namespace doesn't exist yet, the dry run doesn't create it
+	g.Expect(Import(ctx, dst, bundle, ImportOptions{DryRun: true})).To(gomega.Succeed())
+	g.Expect(dst.Get(ctx, types.NamespacedName{Name: "app-ns"}, &corev1.Namespace{})).NotTo(gomega.Succeed())
+	g.Expect(dst.Get(ctx, types.NamespacedName{Name: "sub", Namespace: "app-ns"}, &appv1.Subscription{})).NotTo(gomega.Succeed())
+	g.Expect(dst.Get(ctx, types.NamespacedName{Name: "chn", Namespace: "chn-ns"}, &chnv1.Channel{})).NotTo(gomega.Succeed())
+
+	g.Expect(Import(ctx, dst, bundle, ImportOptions{})).To(gomega.Succeed())
+	g.Expect(dst.Get(ctx, types.NamespacedName{Name: "sub", Namespace: "app-ns"}, &appv1.Subscription{})).To(gomega.Succeed())
+
+	// NotFound errors are reported when the namespace is not created by the dry run
+	bundle.Subscriptions[0].Spec.Channel = "missing-ns/chn"
+	bundle.Subscriptions[0].Namespace = "other-ns"
+	g.Expect(createIfNotExist(ctx, dst, &bundle.Subscriptions[0], true, map[string]bool{"app-ns": true})).Error().To(
+		gomega.HaveOccurred())
+}
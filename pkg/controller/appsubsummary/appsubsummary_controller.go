@@ -20,6 +20,7 @@ import (
 	"runtime"
 	"sort"
 	"strconv"
+	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -41,11 +42,30 @@ import (
 type ReconcileAppSubSummary struct {
 	client.Client
 	Interval int
+
+	// gitCommitStatuses are the commit statuses posted per appsub cluster, the key is appsub/cluster. They are shared
+	// by the aggregation and the delivery workers of gitCommitDeliveries with the targets to post per appsub
+	gitCommitMtx        sync.Mutex
+	gitCommitStatuses   map[string]string
+	gitCommitTargets    map[string]*gitCommitTarget
+	gitCommitDeliveries *deliveryQueue
+
+	// subscriptionWebhooks are the appsub cluster states delivered per webhook, the key is namespace/name. They are
+	// shared by the aggregation and the delivery workers of webhookDeliveries
+	webhookMtx           sync.Mutex
+	subscriptionWebhooks map[string]*webhookState
+	webhookDeliveries    *deliveryQueue
 }
 
 type AppSubClusterStatus struct {
-	Cluster string
-	Phase   string
+	Cluster  string
+	Phase    string
+	Revision string
+	Images   []string
+
+	FailedResources []corev1.ObjectReference
+	Stalled         string
+	Retries         int32
 }
 
 // appsub cluster statuses per appsub.
@@ -72,14 +92,31 @@ func (a AppSubClusterStatusSorter) Less(i, j int) bool { return a[i].Cluster < a
 
 func Add(mgr manager.Manager, interval int) error {
 	dsRS := &ReconcileAppSubSummary{
-		Client:   mgr.GetClient(),
+		Client:   subutils.ControllerClient(mgr, "appsubsummary-controller"),
 		Interval: interval,
 	}
 
+	dsRS.gitCommitDeliveries = newDeliveryQueue("git commit status", dsRS.deliverGitCommitStatuses)
+	dsRS.webhookDeliveries = newDeliveryQueue("subscription webhook", dsRS.deliverSubscriptionWebhook)
+
+	if telemetrySummary {
+		if err := mgr.Add(&telemetrySummarizer{Client: dsRS.Client, interval: telemetryInterval}); err != nil {
+			return err
+		}
+	}
+
 	return mgr.Add(dsRS)
 }
 
 func (r *ReconcileAppSubSummary) Start(ctx context.Context) error {
+	if r.gitCommitDeliveries != nil {
+		r.gitCommitDeliveries.start(ctx)
+	}
+
+	if r.webhookDeliveries != nil {
+		r.webhookDeliveries.start(ctx)
+	}
+
 	go wait.Until(func() {
 		r.houseKeeping()
 	}, time.Duration(r.Interval)*time.Second, ctx.Done())
@@ -154,6 +191,20 @@ func (r *ReconcileAppSubSummary) generateAppSubSummary() error {
 
 	r.createOrUpdateAppSubReport(appSubClusterStatusMap)
 
+	r.postGitCommitStatuses(appSubClusterStatusMap)
+
+	if subutils.IsReadySubscriptionQuery(r.Client) {
+		r.evaluateSubscriptionQueries(appSubClusterStatusMap)
+	}
+
+	if subutils.IsReadySubscriptionWebhook(r.Client) {
+		r.postSubscriptionWebhooks(appSubClusterStatusMap)
+	}
+
+	if historyLimit > 0 && subutils.IsReadySubscriptionHistory(r.Client) {
+		r.recordSubscriptionHistories(appSubClusterStatusMap)
+	}
+
 	if subutils.IsReadyManagedClusterView(r.Client) {
 		r.RefreshManagedClusterViews(appSubClusterStatusMap)
 	}
@@ -177,8 +228,14 @@ func (r *ReconcileAppSubSummary) UpdateAppSubMapsPerCluster(appsubReportPerClust
 		}
 
 		cs := AppSubClusterStatus{
-			Cluster: cluster,
-			Phase:   string(result.Result),
+			Cluster:  cluster,
+			Phase:    string(result.Result),
+			Revision: result.Revision,
+			Images:   result.Images,
+
+			FailedResources: result.FailedResources,
+			Stalled:         result.Stalled,
+			Retries:         result.Retries,
 		}
 
 		if clusterStatus, ok := appSubClusterStatusMap[result.Source]; ok {
@@ -438,8 +495,14 @@ func (r *ReconcileAppSubSummary) newAppSubReport(appsubNs, appsubName string,
 
 	for _, ClusterStatus := range clustersStatus.Clusters {
 		newAppsubReportResult := &appsubReportV1alpha1.SubscriptionReportResult{
-			Source: ClusterStatus.Cluster,
-			Result: appsubReportV1alpha1.SubscriptionResult(ClusterStatus.Phase),
+			Source:   ClusterStatus.Cluster,
+			Result:   appsubReportV1alpha1.SubscriptionResult(ClusterStatus.Phase),
+			Revision: ClusterStatus.Revision,
+			Images:   ClusterStatus.Images,
+
+			FailedResources: ClusterStatus.FailedResources,
+			Stalled:         ClusterStatus.Stalled,
+			Retries:         ClusterStatus.Retries,
 		}
 		newAppsubReportResults = append(newAppsubReportResults, newAppsubReportResult)
 	}
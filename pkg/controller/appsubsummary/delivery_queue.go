@@ -0,0 +1,104 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package appsubsummary
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// deliveryWorkers is the number of the concurrent deliveries of a queue
+	deliveryWorkers = 4
+	// deliveryBaseBackoff and deliveryMaxBackoff bound the exponential backoff of a failing key
+	deliveryBaseBackoff = 10 * time.Second
+	deliveryMaxBackoff  = time.Hour
+)
+
+// deliveryQueue runs the deliveries to the external endpoints out of the aggregation of the reports, a slow or failing
+// endpoint doesn't delay the reports of the hub. A key is delivered by one worker at a time. A failed key is retried
+// with an exponential backoff and is not enqueued again by the aggregation until its retry
+type deliveryQueue struct {
+	name    string
+	queue   workqueue.RateLimitingInterface
+	deliver func(key string) error
+}
+
+func newDeliveryQueue(name string, deliver func(key string) error) *deliveryQueue {
+	return &deliveryQueue{
+		name: name,
+		queue: workqueue.NewNamedRateLimitingQueue(
+			workqueue.NewItemExponentialFailureRateLimiter(deliveryBaseBackoff, deliveryMaxBackoff), name),
+		deliver: deliver,
+	}
+}
+
+// enqueue adds the key unless it waits for a retry, the retry delivers the latest changes of the key
+func (q *deliveryQueue) enqueue(key string) {
+	if q.queue.NumRequeues(key) > 0 {
+		klog.V(1).Infof("%v %v waits for its retry", q.name, key)
+
+		return
+	}
+
+	q.queue.Add(key)
+}
+
+// start runs the workers until the context is done
+func (q *deliveryQueue) start(ctx context.Context) {
+	for i := 0; i < deliveryWorkers; i++ {
+		go wait.Until(func() {
+			for q.processNext() {
+			}
+		}, time.Second, ctx.Done())
+	}
+
+	go func() {
+		<-ctx.Done()
+		q.queue.ShutDown()
+	}()
+}
+
+// processNext delivers the next key, it returns false once the queue is shut down
+func (q *deliveryQueue) processNext() bool {
+	item, shutdown := q.queue.Get()
+	if shutdown {
+		return false
+	}
+
+	defer q.queue.Done(item)
+
+	key, ok := item.(string)
+	if !ok {
+		q.queue.Forget(item)
+
+		return true
+	}
+
+	if err := q.deliver(key); err != nil {
+		klog.Warningf("failed to deliver %v %v, failures: %v, err: %v", q.name, key, q.queue.NumRequeues(key)+1, err)
+		q.queue.AddRateLimited(key)
+
+		return true
+	}
+
+	q.queue.Forget(key)
+
+	return true
+}
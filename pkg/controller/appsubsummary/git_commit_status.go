@@ -0,0 +1,222 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package appsubsummary
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	appsubv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	subutils "open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+// the hub suffixes the commit annotation until the new commit is propagated to the clusters
+const fakeCommitIDSuffix = "-new"
+
+// gitCommitTarget is the commit of a git appsub and the statuses of its clusters to post to the Git provider, from the
+// latest aggregation of the reports
+type gitCommitTarget struct {
+	provider           string
+	repoURL            string
+	token              string
+	insecureSkipVerify bool
+	commit             string
+	clusters           []AppSubClusterStatus
+}
+
+// postGitCommitStatuses records the commit statuses to post for the git appsubs with the git-commit-status annotation,
+// the appsubs with changed statuses are enqueued and their statuses are posted by the delivery workers. A status is
+// posted once per commit and cluster state.
+func (r *ReconcileAppSubSummary) postGitCommitStatuses(appSubClusterStatusMap map[string]AppSubClustersStatus) {
+	targets := map[string]*gitCommitTarget{}
+
+	for appsub, clustersStatus := range appSubClusterStatusMap {
+		appsubNs, appsubName := subutils.ParseNamespacedName(appsub)
+		if appsubName == "" && appsubNs == "" {
+			continue
+		}
+
+		sub := &appsubv1.Subscription{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: appsubName, Namespace: appsubNs}, sub); err != nil {
+			continue
+		}
+
+		if !strings.EqualFold(sub.GetAnnotations()[appsubv1.AnnotationGitCommitStatus], "true") {
+			continue
+		}
+
+		commit := sub.GetAnnotations()[appsubv1.AnnotationGitCommit]
+		if commit == "" || strings.HasSuffix(commit, fakeCommitIDSuffix) {
+			klog.V(1).Infof("skip posting commit statuses of appsub %v, commit %q not propagated", appsub, commit)
+
+			continue
+		}
+
+		chnNs, chnName := subutils.ParseNamespacedName(sub.Spec.Channel)
+		chn := &chnv1.Channel{}
+
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: chnName, Namespace: chnNs}, chn); err != nil {
+			klog.Errorf("failed to get channel %v of appsub %v, err: %v", sub.Spec.Channel, appsub, err)
+
+			continue
+		}
+
+		cType := string(chn.Spec.Type)
+		if !strings.EqualFold(cType, chnv1.ChannelTypeGit) && !strings.EqualFold(cType, chnv1.ChannelTypeGitHub) {
+			continue
+		}
+
+		provider, err := subutils.GetSubscriptionGitProvider(sub, chn.Spec.Pathname)
+		if err != nil {
+			klog.Errorf("failed to post commit statuses of appsub %v, err: %v", appsub, err)
+
+			continue
+		}
+
+		_, token, _, _, _, _, err := subutils.GetChannelSecret(r.Client, chn)
+		if err != nil {
+			klog.Errorf("failed to get the secret of channel %v, err: %v", sub.Spec.Channel, err)
+
+			continue
+		}
+
+		targets[appsub] = &gitCommitTarget{
+			provider:           provider,
+			repoURL:            chn.Spec.Pathname,
+			token:              token,
+			insecureSkipVerify: chn.Spec.InsecureSkipVerify,
+			commit:             commit,
+			clusters:           clustersStatus.Clusters,
+		}
+	}
+
+	changed := []string{}
+	posted := map[string]string{}
+
+	r.gitCommitMtx.Lock()
+
+	for appsub, target := range targets {
+		if len(r.pendingGitCommitStatuses(appsub, target)) > 0 {
+			changed = append(changed, appsub)
+		}
+
+		for _, clusterStatus := range target.clusters {
+			key := appsub + "/" + clusterStatus.Cluster
+			if status, ok := r.gitCommitStatuses[key]; ok {
+				posted[key] = status
+			}
+		}
+	}
+
+	// forget the deleted appsubs and clusters
+	r.gitCommitTargets = targets
+	r.gitCommitStatuses = posted
+
+	r.gitCommitMtx.Unlock()
+
+	for _, appsub := range changed {
+		if r.gitCommitDeliveries != nil {
+			r.gitCommitDeliveries.enqueue(appsub)
+		}
+	}
+}
+
+// pendingGitCommitStatuses returns the commit statuses of the appsub clusters not posted yet, gitCommitMtx is held
+func (r *ReconcileAppSubSummary) pendingGitCommitStatuses(appsub string,
+	target *gitCommitTarget) []AppSubClusterStatus {
+	pending := []AppSubClusterStatus{}
+
+	for _, clusterStatus := range target.clusters {
+		state := gitCommitState(clusterStatus, target.commit)
+
+		if r.gitCommitStatuses[appsub+"/"+clusterStatus.Cluster] != target.commit+"/"+state {
+			pending = append(pending, clusterStatus)
+		}
+	}
+
+	return pending
+}
+
+// deliverGitCommitStatuses posts the pending commit statuses of the appsub, the statuses left after a failed post are
+// posted by the retry of the appsub
+func (r *ReconcileAppSubSummary) deliverGitCommitStatuses(appsub string) error {
+	r.gitCommitMtx.Lock()
+
+	target := r.gitCommitTargets[appsub]
+	if target == nil {
+		r.gitCommitMtx.Unlock()
+
+		return nil
+	}
+
+	pending := r.pendingGitCommitStatuses(appsub, target)
+
+	r.gitCommitMtx.Unlock()
+
+	for _, clusterStatus := range pending {
+		key := appsub + "/" + clusterStatus.Cluster
+		state := gitCommitState(clusterStatus, target.commit)
+
+		description := fmt.Sprintf("appsub %v %v on cluster %v", appsub, clusterStatus.Phase, clusterStatus.Cluster)
+		if state == subutils.GitCommitStatusPending && clusterStatus.Revision != target.commit {
+			description = fmt.Sprintf("appsub %v deploying the commit on cluster %v", appsub, clusterStatus.Cluster)
+		}
+
+		status := &subutils.GitCommitStatus{
+			Provider:    target.provider,
+			RepoURL:     target.repoURL,
+			Commit:      target.commit,
+			Context:     "open-cluster-management/" + key,
+			State:       state,
+			Description: description,
+		}
+
+		if err := status.Post(target.token, target.insecureSkipVerify); err != nil {
+			return fmt.Errorf("failed to post commit status of appsub %v on cluster %v, err: %w", appsub,
+				clusterStatus.Cluster, err)
+		}
+
+		r.gitCommitMtx.Lock()
+		r.gitCommitStatuses[key] = target.commit + "/" + state
+		r.gitCommitMtx.Unlock()
+	}
+
+	return nil
+}
+
+// gitCommitState returns the state of the commit on the cluster. A cluster that hasn't reported the commit yet is
+// pending, even if it is deployed with the previous commit.
+func gitCommitState(clusterStatus AppSubClusterStatus, commit string) string {
+	if clusterStatus.Phase == "propagationFailed" {
+		return subutils.GitCommitStatusFailure
+	}
+
+	if clusterStatus.Revision != commit {
+		return subutils.GitCommitStatusPending
+	}
+
+	switch clusterStatus.Phase {
+	case "deployed":
+		return subutils.GitCommitStatusSuccess
+	case "failed":
+		return subutils.GitCommitStatusFailure
+	default:
+		return subutils.GitCommitStatusPending
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package appsubsummary
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	appsubv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	subutils "open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPostGitCommitStatuses(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	posts := map[string]int{}
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		g.Expect(r.Header.Get("Authorization")).To(gomega.Equal("token secret"))
+
+		posts[r.URL.Path]++
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	defer ts.Close()
+
+	scheme := runtime.NewScheme()
+	g.Expect(appsubv1.SchemeBuilder.AddToScheme(scheme)).To(gomega.Succeed())
+	g.Expect(chnv1.SchemeBuilder.AddToScheme(scheme)).To(gomega.Succeed())
+	g.Expect(corev1.AddToScheme(scheme)).To(gomega.Succeed())
+
+	chn := &chnv1.Channel{
+		ObjectMeta: metav1.ObjectMeta{Name: "git", Namespace: "chn-ns"},
+		Spec: chnv1.ChannelSpec{
+			Type:               chnv1.ChannelTypeGit,
+			Pathname:           ts.URL + "/org/app.git",
+			SecretRef:          &corev1.ObjectReference{Name: "git-secret"},
+			InsecureSkipVerify: true,
+		},
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "git-secret", Namespace: "chn-ns"},
+		Data:       map[string][]byte{"user": []byte("admin"), "accessToken": []byte("secret")},
+	}
+
+	sub := &appsubv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "app-ns",
+			Annotations: map[string]string{
+				appsubv1.AnnotationGitCommitStatus: "true",
+				appsubv1.AnnotationGitCommit:       "abc123",
+			},
+		},
+		Spec: appsubv1.SubscriptionSpec{Channel: "chn-ns/git"},
+	}
+
+	// the other appsub doesn't post commit statuses
+	other := &appsubv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "other",
+			Namespace:   "app-ns",
+			Annotations: map[string]string{appsubv1.AnnotationGitCommit: "abc123"},
+		},
+		Spec: appsubv1.SubscriptionSpec{Channel: "chn-ns/git"},
+	}
+
+	r := &ReconcileAppSubSummary{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(chn, secret, sub, other).Build()}
+	r.gitCommitDeliveries = newDeliveryQueue("git commit status", r.deliverGitCommitStatuses)
+
+	defer r.gitCommitDeliveries.queue.ShutDown()
+
+	// post runs the aggregation and the enqueued deliveries
+	post := func(appSubClusterStatusMap map[string]AppSubClustersStatus) {
+		r.postGitCommitStatuses(appSubClusterStatusMap)
+
+		for r.gitCommitDeliveries.queue.Len() > 0 {
+			r.gitCommitDeliveries.processNext()
+		}
+	}
+
+	statusMap := map[string]AppSubClustersStatus{
+		"app-ns/app": {Clusters: []AppSubClusterStatus{
+			{Cluster: "cluster1", Phase: "deployed", Revision: "abc123"},
+			{Cluster: "cluster2", Phase: "failed", Revision: "abc123"},
+			{Cluster: "cluster3", Phase: "deployed", Revision: "old456"},
+		}},
+		"app-ns/other": {Clusters: []AppSubClusterStatus{{Cluster: "cluster1", Phase: "deployed", Revision: "abc123"}}},
+	}
+
+	// the cluster still deployed with the previous commit is pending
+	post(statusMap)
+	g.Expect(posts).To(gomega.Equal(map[string]int{"/api/v3/repos/org/app/statuses/abc123": 3}))
+	g.Expect(r.gitCommitStatuses).To(gomega.Equal(map[string]string{
+		"app-ns/app/cluster1": "abc123/success",
+		"app-ns/app/cluster2": "abc123/failure",
+		"app-ns/app/cluster3": "abc123/pending",
+	}))
+
+	// the unchanged statuses are not enqueued nor posted again
+	r.postGitCommitStatuses(statusMap)
+	g.Expect(r.gitCommitDeliveries.queue.Len()).To(gomega.Equal(0))
+	g.Expect(posts["/api/v3/repos/org/app/statuses/abc123"]).To(gomega.Equal(3))
+
+	statusMap["app-ns/app"].Clusters[1].Phase = "deployed"
+	statusMap["app-ns/app"].Clusters[2].Revision = "abc123"
+
+	post(statusMap)
+	g.Expect(posts["/api/v3/repos/org/app/statuses/abc123"]).To(gomega.Equal(5))
+	g.Expect(r.gitCommitStatuses["app-ns/app/cluster2"]).To(gomega.Equal("abc123/success"))
+	g.Expect(r.gitCommitStatuses["app-ns/app/cluster3"]).To(gomega.Equal("abc123/success"))
+
+	// the removed clusters are forgotten
+	statusMap["app-ns/app"] = AppSubClustersStatus{Clusters: statusMap["app-ns/app"].Clusters[:1]}
+
+	post(statusMap)
+	g.Expect(r.gitCommitStatuses).To(gomega.HaveLen(1))
+}
+
+func TestGitCommitState(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	g.Expect(gitCommitState(AppSubClusterStatus{Phase: "deployed", Revision: "abc123"}, "abc123")).To(
+		gomega.Equal(subutils.GitCommitStatusSuccess))
+	g.Expect(gitCommitState(AppSubClusterStatus{Phase: "deployed", Revision: "old456"}, "abc123")).To(
+		gomega.Equal(subutils.GitCommitStatusPending))
+	g.Expect(gitCommitState(AppSubClusterStatus{Phase: "failed", Revision: "abc123"}, "abc123")).To(
+		gomega.Equal(subutils.GitCommitStatusFailure))
+	g.Expect(gitCommitState(AppSubClusterStatus{Phase: "failed", Revision: "old456"}, "abc123")).To(
+		gomega.Equal(subutils.GitCommitStatusPending))
+	g.Expect(gitCommitState(AppSubClusterStatus{Phase: "propagationFailed"}, "abc123")).To(
+		gomega.Equal(subutils.GitCommitStatusFailure))
+}
@@ -0,0 +1,247 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package appsubsummary
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	appsubv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appsubReportV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+	subutils "open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+// maxHistoryClusterNames is the number of the cluster names in the message of a Failed event
+const maxHistoryClusterNames = 5
+
+// historyLimit is the number of the events kept in each SubscriptionHistory, the histories are not recorded if 0
+var historyLimit = 50
+
+// SetSubscriptionHistoryLimit sets the number of the events kept in each SubscriptionHistory, it is called once before
+// the controllers are set up
+func SetSubscriptionHistoryLimit(limit int) {
+	historyLimit = limit
+}
+
+// recordSubscriptionHistories records the significant events of each appsub in its SubscriptionHistory
+func (r *ReconcileAppSubSummary) recordSubscriptionHistories(appSubClusterStatusMap map[string]AppSubClustersStatus) {
+	if historyLimit <= 0 {
+		return
+	}
+
+	now := metav1.Now()
+
+	for appsub, clustersStatus := range appSubClusterStatusMap {
+		appsubNs, appsubName := subutils.ParseNamespacedName(appsub)
+		if appsubName == "" && appsubNs == "" {
+			continue
+		}
+
+		if err := r.recordSubscriptionHistory(appsubNs, appsubName, clustersStatus, now); err != nil {
+			klog.Errorf("failed to record the history of appsub %v, err: %v", appsub, err)
+		}
+	}
+}
+
+func (r *ReconcileAppSubSummary) recordSubscriptionHistory(appsubNs, appsubName string,
+	clustersStatus AppSubClustersStatus, now metav1.Time) error {
+	key := types.NamespacedName{Namespace: appsubNs, Name: appsubName}
+
+	appsub := &appsubv1.Subscription{}
+	if err := r.Get(context.TODO(), key, appsub); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	history := &appsubReportV1alpha1.SubscriptionHistory{}
+	found := true
+
+	if err := r.Get(context.TODO(), key, history); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+
+		found = false
+		history = &appsubReportV1alpha1.SubscriptionHistory{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      appsubName,
+				Namespace: appsubNs,
+				Labels: map[string]string{
+					"apps.open-cluster-management.io/hosting-subscription": fmt.Sprintf("%.63s", appsubNs+"."+appsubName),
+				},
+			},
+		}
+
+		history.SetOwnerReferences([]metav1.OwnerReference{
+			*metav1.NewControllerRef(appsub, appsubv1.SchemeGroupVersion.WithKind("Subscription"))})
+	}
+
+	frozen := meta.FindStatusCondition(appsub.Status.Conditions, appsubv1.ConditionChangeFrozen)
+
+	state, events := nextSubscriptionHistory(history.State, clustersStatus, frozen, now)
+
+	if found && len(events) == 0 && equality.Semantic.DeepEqual(state, history.State) {
+		return nil
+	}
+
+	history.State = state
+	history.Events = append(history.Events, events...)
+
+	if len(history.Events) > historyLimit {
+		history.Events = history.Events[len(history.Events)-historyLimit:]
+	}
+
+	if !found {
+		return r.Create(context.TODO(), history)
+	}
+
+	return r.Update(context.TODO(), history)
+}
+
+// nextSubscriptionHistory compares the cluster statuses of the appsub to the state of its last aggregation and returns
+// the new state with the events between them. A revision not applied on any cluster at the last aggregation starts a
+// rollout, which is finished once the revision is deployed on all the clusters.
+func nextSubscriptionHistory(prev appsubReportV1alpha1.SubscriptionHistoryState, clustersStatus AppSubClustersStatus,
+	frozen *metav1.Condition, now metav1.Time) (appsubReportV1alpha1.SubscriptionHistoryState,
+	[]appsubReportV1alpha1.SubscriptionHistoryEvent) {
+	next := appsubReportV1alpha1.SubscriptionHistoryState{Revision: prev.Revision, RolledOut: prev.RolledOut}
+	events := []appsubReportV1alpha1.SubscriptionHistoryEvent{}
+
+	clusters := len(clustersStatus.Clusters)
+	revisions := map[string]int{}
+	failedClusters := []string{}
+
+	for _, cs := range clustersStatus.Clusters {
+		if cs.Revision != "" {
+			revisions[cs.Revision]++
+		}
+
+		if cs.Phase == "failed" || cs.Phase == "propagationFailed" {
+			failedClusters = append(failedClusters, cs.Cluster)
+		}
+	}
+
+	for revision := range revisions {
+		next.Revisions = append(next.Revisions, revision)
+	}
+
+	sort.Strings(next.Revisions)
+
+	next.Frozen = frozen != nil && frozen.Status == metav1.ConditionTrue
+
+	if next.Frozen && !prev.Frozen {
+		events = append(events, appsubReportV1alpha1.SubscriptionHistoryEvent{
+			Type:     appsubReportV1alpha1.HistoryFrozen,
+			Time:     now,
+			Revision: next.Revision,
+			Message:  frozen.Message,
+		})
+	} else if !next.Frozen && prev.Frozen {
+		events = append(events, appsubReportV1alpha1.SubscriptionHistoryEvent{
+			Type:     appsubReportV1alpha1.HistoryUnfrozen,
+			Time:     now,
+			Revision: next.Revision,
+			Message:  "the new revisions are propagated to all the clusters again",
+		})
+	}
+
+	// the new revision applied on the most clusters if there are several
+	newRevision := ""
+
+	for _, revision := range next.Revisions {
+		if revision == prev.Revision || containsString(prev.Revisions, revision) {
+			continue
+		}
+
+		if newRevision == "" || revisions[revision] > revisions[newRevision] {
+			newRevision = revision
+		}
+	}
+
+	if newRevision != "" {
+		events = append(events, appsubReportV1alpha1.SubscriptionHistoryEvent{
+			Type:             appsubReportV1alpha1.HistoryRolloutStarted,
+			Time:             now,
+			Revision:         newRevision,
+			PreviousRevision: prev.Revision,
+			Clusters:         revisions[newRevision],
+			Message: fmt.Sprintf("revision %v applied on %v of %v clusters", newRevision, revisions[newRevision],
+				clusters),
+		})
+
+		next.Revision = newRevision
+		next.RolledOut = false
+	}
+
+	deployed := 0
+
+	for _, cs := range clustersStatus.Clusters {
+		if cs.Revision == next.Revision && cs.Phase == "deployed" {
+			deployed++
+		}
+	}
+
+	if !next.RolledOut && next.Revision != "" && clusters > 0 && deployed == clusters {
+		events = append(events, appsubReportV1alpha1.SubscriptionHistoryEvent{
+			Type:     appsubReportV1alpha1.HistoryRolloutFinished,
+			Time:     now,
+			Revision: next.Revision,
+			Clusters: clusters,
+			Message:  fmt.Sprintf("revision %v deployed on all the %v clusters", next.Revision, clusters),
+		})
+
+		next.RolledOut = true
+	}
+
+	next.FailedClusters = len(failedClusters)
+
+	if next.FailedClusters > 0 && prev.FailedClusters == 0 {
+		sort.Strings(failedClusters)
+
+		names := failedClusters
+		if len(names) > maxHistoryClusterNames {
+			names = append(names[:maxHistoryClusterNames:maxHistoryClusterNames], "...")
+		}
+
+		events = append(events, appsubReportV1alpha1.SubscriptionHistoryEvent{
+			Type:     appsubReportV1alpha1.HistoryFailed,
+			Time:     now,
+			Revision: next.Revision,
+			Clusters: next.FailedClusters,
+			Message: fmt.Sprintf("failed on %v of %v clusters: %v", next.FailedClusters, clusters,
+				strings.Join(names, ", ")),
+		})
+	} else if next.FailedClusters == 0 && prev.FailedClusters > 0 {
+		events = append(events, appsubReportV1alpha1.SubscriptionHistoryEvent{
+			Type:     appsubReportV1alpha1.HistoryRecovered,
+			Time:     now,
+			Revision: next.Revision,
+			Message:  "not failing on any cluster anymore",
+		})
+	}
+
+	return next, events
+}
@@ -0,0 +1,132 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package appsubsummary
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	appsubv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appsubReportV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRecordSubscriptionHistories(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	defer SetSubscriptionHistoryLimit(historyLimit)
+	SetSubscriptionHistoryLimit(4)
+
+	scheme := runtime.NewScheme()
+	g.Expect(appsubv1.SchemeBuilder.AddToScheme(scheme)).To(gomega.Succeed())
+	g.Expect(appsubReportV1alpha1.SchemeBuilder.AddToScheme(scheme)).To(gomega.Succeed())
+
+	appsub := &appsubv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns", UID: "demo-uid"},
+	}
+
+	r := &ReconcileAppSubSummary{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(appsub).Build()}
+
+	key := types.NamespacedName{Namespace: "demo-ns", Name: "demo"}
+
+	record := func(clusters ...AppSubClusterStatus) *appsubReportV1alpha1.SubscriptionHistory {
+		r.recordSubscriptionHistories(map[string]AppSubClustersStatus{"demo-ns/demo": {Clusters: clusters}})
+
+		history := &appsubReportV1alpha1.SubscriptionHistory{}
+		g.Expect(r.Get(context.TODO(), key, history)).To(gomega.Succeed())
+
+		return history
+	}
+
+	eventTypes := func(history *appsubReportV1alpha1.SubscriptionHistory) []appsubReportV1alpha1.SubscriptionHistoryEventType {
+		types := []appsubReportV1alpha1.SubscriptionHistoryEventType{}
+		for _, event := range history.Events {
+			types = append(types, event.Type)
+		}
+
+		return types
+	}
+
+	// the first revision rolls out to one of the two clusters
+	history := record(
+		AppSubClusterStatus{Cluster: "cluster1", Phase: "deployed", Revision: "aaa"},
+		AppSubClusterStatus{Cluster: "cluster2"},
+	)
+	g.Expect(metav1.IsControlledBy(history, appsub)).To(gomega.BeTrue())
+	g.Expect(history.Labels).To(gomega.HaveKeyWithValue("apps.open-cluster-management.io/hosting-subscription", "demo-ns.demo"))
+	g.Expect(history.Events).To(gomega.HaveLen(1))
+	g.Expect(history.Events[0].Type).To(gomega.Equal(appsubReportV1alpha1.HistoryRolloutStarted))
+	g.Expect(history.Events[0].Revision).To(gomega.Equal("aaa"))
+	g.Expect(history.Events[0].Message).To(gomega.Equal("revision aaa applied on 1 of 2 clusters"))
+	g.Expect(history.State.RolledOut).To(gomega.BeFalse())
+
+	// nothing is recorded while nothing changes
+	history = record(
+		AppSubClusterStatus{Cluster: "cluster1", Phase: "deployed", Revision: "aaa"},
+		AppSubClusterStatus{Cluster: "cluster2"},
+	)
+	g.Expect(history.Events).To(gomega.HaveLen(1))
+
+	// the rollout finishes once the revision is deployed on all the clusters
+	history = record(
+		AppSubClusterStatus{Cluster: "cluster1", Phase: "deployed", Revision: "aaa"},
+		AppSubClusterStatus{Cluster: "cluster2", Phase: "deployed", Revision: "aaa"},
+	)
+	g.Expect(eventTypes(history)).To(gomega.Equal([]appsubReportV1alpha1.SubscriptionHistoryEventType{
+		appsubReportV1alpha1.HistoryRolloutStarted, appsubReportV1alpha1.HistoryRolloutFinished}))
+	g.Expect(history.State.RolledOut).To(gomega.BeTrue())
+
+	// a new revision fails on a cluster while a freeze holds the other one
+	appsub.Status.Conditions = []metav1.Condition{{
+		Type:    appsubv1.ConditionChangeFrozen,
+		Status:  metav1.ConditionTrue,
+		Reason:  appsubv1.ReasonChangeFreezeActive,
+		Message: "1 clusters frozen by ChangeFreeze demo-ns/weekend",
+	}}
+	g.Expect(r.Status().Update(context.TODO(), appsub)).To(gomega.Succeed())
+
+	history = record(
+		AppSubClusterStatus{Cluster: "cluster1", Phase: "failed", Revision: "bbb"},
+		AppSubClusterStatus{Cluster: "cluster2", Phase: "deployed", Revision: "aaa"},
+	)
+	g.Expect(eventTypes(history)).To(gomega.Equal([]appsubReportV1alpha1.SubscriptionHistoryEventType{
+		appsubReportV1alpha1.HistoryRolloutFinished, appsubReportV1alpha1.HistoryFrozen,
+		appsubReportV1alpha1.HistoryRolloutStarted, appsubReportV1alpha1.HistoryFailed}))
+
+	started := history.Events[2]
+	g.Expect(started.Revision).To(gomega.Equal("bbb"))
+	g.Expect(started.PreviousRevision).To(gomega.Equal("aaa"))
+	g.Expect(history.Events[1].Message).To(gomega.Equal("1 clusters frozen by ChangeFreeze demo-ns/weekend"))
+	g.Expect(history.Events[3].Message).To(gomega.Equal("failed on 1 of 2 clusters: cluster1"))
+	g.Expect(history.State.Revisions).To(gomega.Equal([]string{"aaa", "bbb"}))
+
+	// the freeze ends and the revision is deployed everywhere, the oldest events are dropped past the limit
+	appsub.Status.Conditions = nil
+	g.Expect(r.Status().Update(context.TODO(), appsub)).To(gomega.Succeed())
+
+	history = record(
+		AppSubClusterStatus{Cluster: "cluster1", Phase: "deployed", Revision: "bbb"},
+		AppSubClusterStatus{Cluster: "cluster2", Phase: "deployed", Revision: "bbb"},
+	)
+	g.Expect(eventTypes(history)).To(gomega.Equal([]appsubReportV1alpha1.SubscriptionHistoryEventType{
+		appsubReportV1alpha1.HistoryFailed, appsubReportV1alpha1.HistoryUnfrozen,
+		appsubReportV1alpha1.HistoryRolloutFinished, appsubReportV1alpha1.HistoryRecovered}))
+	g.Expect(history.State).To(gomega.Equal(appsubReportV1alpha1.SubscriptionHistoryState{
+		Revision: "bbb", Revisions: []string{"bbb"}, RolledOut: true}))
+}
@@ -0,0 +1,150 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package appsubsummary
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	appsubReportV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+	subutils "open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+// the status of a query lists up to maxQueryClusters clusters, the matched count is not truncated
+const maxQueryClusters = 1000
+
+// evaluateSubscriptionQueries sets the clusters matching each SubscriptionQuery from the appsub cluster statuses
+// aggregated from the cluster appsubReports. A query status is only updated when its clusters change.
+func (r *ReconcileAppSubSummary) evaluateSubscriptionQueries(appSubClusterStatusMap map[string]AppSubClustersStatus) {
+	queryList := &appsubReportV1alpha1.SubscriptionQueryList{}
+
+	if err := r.List(context.TODO(), queryList); err != nil {
+		klog.Errorf("failed to list subscription queries, err: %v", err)
+
+		return
+	}
+
+	for i := range queryList.Items {
+		query := &queryList.Items[i]
+
+		clusters := matchSubscriptionQuery(query, appSubClusterStatusMap)
+		matched := len(clusters)
+
+		if matched > maxQueryClusters {
+			clusters = clusters[:maxQueryClusters]
+		}
+
+		if query.Status.LastUpdateTime != nil && query.Status.Matched == matched &&
+			equality.Semantic.DeepEqual(query.Status.Clusters, clusters) {
+			continue
+		}
+
+		now := metav1.NewTime(time.Now())
+		query.Status = appsubReportV1alpha1.SubscriptionQueryStatus{
+			Matched:        matched,
+			Clusters:       clusters,
+			LastUpdateTime: &now,
+		}
+
+		if err := r.Status().Update(context.TODO(), query); err != nil {
+			klog.Errorf("failed to update subscription query %v/%v, err: %v", query.Namespace, query.Name, err)
+
+			continue
+		}
+
+		klog.Infof("subscription query %v/%v matched %v clusters", query.Namespace, query.Name, matched)
+	}
+}
+
+// matchSubscriptionQuery returns the clusters of the appsubs in the namespace of the query matching all its conditions,
+// sorted by cluster and appsub
+func matchSubscriptionQuery(query *appsubReportV1alpha1.SubscriptionQuery,
+	appSubClusterStatusMap map[string]AppSubClustersStatus) []appsubReportV1alpha1.SubscriptionQueryCluster {
+	var clusters []appsubReportV1alpha1.SubscriptionQueryCluster
+
+	spec := query.Spec
+
+	for appsub, clustersStatus := range appSubClusterStatusMap {
+		appsubNs, appsubName := subutils.ParseNamespacedName(appsub)
+		if appsubNs != query.Namespace || spec.Subscription != "" && appsubName != spec.Subscription {
+			continue
+		}
+
+		for _, clusterStatus := range clustersStatus.Clusters {
+			if spec.Revision != "" && clusterStatus.Revision != spec.Revision {
+				continue
+			}
+
+			if spec.NotRevision != "" && clusterStatus.Revision == spec.NotRevision {
+				continue
+			}
+
+			if spec.Result != "" && clusterStatus.Phase != string(spec.Result) {
+				continue
+			}
+
+			if spec.Image != "" && !containsString(clusterStatus.Images, spec.Image) {
+				continue
+			}
+
+			if spec.FailedResource != nil && !containsResource(clusterStatus.FailedResources, *spec.FailedResource) {
+				continue
+			}
+
+			clusters = append(clusters, appsubReportV1alpha1.SubscriptionQueryCluster{
+				Cluster:      clusterStatus.Cluster,
+				Subscription: appsubName,
+				Result:       appsubReportV1alpha1.SubscriptionResult(clusterStatus.Phase),
+				Revision:     clusterStatus.Revision,
+			})
+		}
+	}
+
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].Cluster != clusters[j].Cluster {
+			return clusters[i].Cluster < clusters[j].Cluster
+		}
+
+		return clusters[i].Subscription < clusters[j].Subscription
+	})
+
+	return clusters
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsResource(refs []corev1.ObjectReference, resource appsubReportV1alpha1.SubscriptionQueryResource) bool {
+	for _, ref := range refs {
+		if ref.Kind == resource.Kind && ref.Name == resource.Name && ref.Namespace == resource.Namespace &&
+			(resource.APIVersion == "" || ref.APIVersion == resource.APIVersion) {
+			return true
+		}
+	}
+
+	return false
+}
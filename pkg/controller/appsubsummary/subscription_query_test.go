@@ -0,0 +1,116 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package appsubsummary
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	appsubReportV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestEvaluateSubscriptionQueries(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(appsubReportV1alpha1.AddToScheme(scheme)).To(gomega.Succeed())
+
+	clusterReport := func(cluster string, results ...*appsubReportV1alpha1.SubscriptionReportResult) appsubReportV1alpha1.SubscriptionReport {
+		return appsubReportV1alpha1.SubscriptionReport{
+			ObjectMeta: metav1.ObjectMeta{Name: cluster, Namespace: cluster},
+			ReportType: "Cluster",
+			Results:    results,
+		}
+	}
+
+	frontend := corev1.ObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "frontend", Namespace: "web"}
+
+	reports := []appsubReportV1alpha1.SubscriptionReport{
+		clusterReport("cluster1",
+			&appsubReportV1alpha1.SubscriptionReportResult{Source: "app-ns/app", Result: "deployed", Revision: "c2",
+				Images: []string{"quay.io/demo/frontend:2.0"}},
+			&appsubReportV1alpha1.SubscriptionReportResult{Source: "other-ns/app", Result: "deployed", Revision: "c1"}),
+		clusterReport("cluster2",
+			&appsubReportV1alpha1.SubscriptionReportResult{Source: "app-ns/app", Result: "failed", Revision: "c1",
+				Images: []string{"quay.io/demo/frontend:1.0"}, FailedResources: []corev1.ObjectReference{frontend}}),
+		clusterReport("cluster3",
+			&appsubReportV1alpha1.SubscriptionReportResult{Source: "app-ns/app", Result: "deployed", Revision: "c1",
+				Images: []string{"quay.io/demo/frontend:1.0"}},
+			&appsubReportV1alpha1.SubscriptionReportResult{Source: "app-ns/db", Result: "deployed", Revision: "c7"}),
+	}
+
+	query := func(name string, spec appsubReportV1alpha1.SubscriptionQuerySpec) *appsubReportV1alpha1.SubscriptionQuery {
+		return &appsubReportV1alpha1.SubscriptionQuery{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "app-ns"},
+			Spec:       spec,
+		}
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		query("not-revision", appsubReportV1alpha1.SubscriptionQuerySpec{Subscription: "app", NotRevision: "c2"}),
+		query("image", appsubReportV1alpha1.SubscriptionQuerySpec{Image: "quay.io/demo/frontend:1.0"}),
+		query("failed-resource", appsubReportV1alpha1.SubscriptionQuerySpec{
+			FailedResource: &appsubReportV1alpha1.SubscriptionQueryResource{Kind: "Deployment", Name: "frontend", Namespace: "web"},
+		}),
+		query("deployed", appsubReportV1alpha1.SubscriptionQuerySpec{Result: "deployed"}),
+	).Build()
+
+	r := &ReconcileAppSubSummary{Client: c}
+
+	appSubClusterStatusMap := make(map[string]AppSubClustersStatus)
+	for _, report := range reports {
+		r.UpdateAppSubMapsPerCluster(report, appSubClusterStatusMap)
+	}
+
+	r.evaluateSubscriptionQueries(appSubClusterStatusMap)
+
+	getQuery := func(name string) *appsubReportV1alpha1.SubscriptionQuery {
+		query := &appsubReportV1alpha1.SubscriptionQuery{}
+		g.Expect(c.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: "app-ns"}, query)).To(gomega.Succeed())
+
+		return query
+	}
+
+	notRevision := getQuery("not-revision")
+	g.Expect(notRevision.Status.Matched).To(gomega.Equal(2))
+	g.Expect(notRevision.Status.Clusters).To(gomega.Equal([]appsubReportV1alpha1.SubscriptionQueryCluster{
+		{Cluster: "cluster2", Subscription: "app", Result: "failed", Revision: "c1"},
+		{Cluster: "cluster3", Subscription: "app", Result: "deployed", Revision: "c1"},
+	}))
+	g.Expect(notRevision.Status.LastUpdateTime).NotTo(gomega.BeNil())
+
+	g.Expect(getQuery("image").Status.Matched).To(gomega.Equal(2))
+
+	failedResource := getQuery("failed-resource")
+	g.Expect(failedResource.Status.Matched).To(gomega.Equal(1))
+	g.Expect(failedResource.Status.Clusters[0].Cluster).To(gomega.Equal("cluster2"))
+
+	// the queries don't match the appsubs of the other namespaces
+	deployed := getQuery("deployed")
+	g.Expect(deployed.Status.Matched).To(gomega.Equal(3))
+	g.Expect(deployed.Status.Clusters[2]).To(gomega.Equal(appsubReportV1alpha1.SubscriptionQueryCluster{
+		Cluster: "cluster3", Subscription: "db", Result: "deployed", Revision: "c7",
+	}))
+
+	// the status is not updated while the clusters of the query don't change
+	r.evaluateSubscriptionQueries(appSubClusterStatusMap)
+	g.Expect(getQuery("deployed").ResourceVersion).To(gomega.Equal(deployed.ResourceVersion))
+}
@@ -0,0 +1,323 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package appsubsummary
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	appsubv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appsubReportV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+	subutils "open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// maxTelemetryErrorSignatures is the number of the most frequent error signatures of the summary
+	maxTelemetryErrorSignatures = 20
+	// maxTelemetrySignatureLength is the length the error signatures are truncated to
+	maxTelemetrySignatureLength = 256
+)
+
+var (
+	// telemetrySummary enables the SubscriptionTelemetry summary, it is opt-in
+	telemetrySummary bool
+	// telemetryInterval is how often the summary is updated, it lists all the subscriptions and channels of the hub
+	telemetryInterval = 10 * time.Minute
+)
+
+// SetTelemetrySummary enables the SubscriptionTelemetry summary updated every interval, it is called once before the
+// controllers are set up
+func SetTelemetrySummary(enabled bool, interval time.Duration) {
+	telemetrySummary = enabled
+
+	if interval > 0 {
+		telemetryInterval = interval
+	}
+}
+
+// the redactions of the error signatures, in order
+var telemetryRedactions = []struct {
+	re          *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^\s"',]+`), "<url>"},
+	{regexp.MustCompile(`"[^"]*"|'[^']*'`), "<value>"},
+	{regexp.MustCompile(`\b\d{1,3}(\.\d{1,3}){3}(:\d+)?\b`), "<ip>"},
+	{regexp.MustCompile(`[\w.:<>-]+(/[\w.:<>-]+)+`), "<path>"},
+	{regexp.MustCompile(`\b[0-9a-f]{7,}\b`), "<hash>"},
+	{regexp.MustCompile(`\d+`), "<n>"},
+	{regexp.MustCompile(`\s+`), " "},
+}
+
+// telemetrySummarizer writes the redacted summary of the subscriptions, the channels and the agents of the hub to the
+// subscription-telemetry SubscriptionTelemetry
+type telemetrySummarizer struct {
+	client.Client
+	interval time.Duration
+}
+
+func (t *telemetrySummarizer) Start(ctx context.Context) error {
+	go wait.Until(func() {
+		t.summarize()
+	}, t.interval, ctx.Done())
+
+	return nil
+}
+
+func (t *telemetrySummarizer) summarize() {
+	if !subutils.IsReadySubscriptionTelemetry(t.Client) {
+		return
+	}
+
+	summary, err := t.getTelemetrySummary()
+	if err != nil {
+		klog.Warning("error while summarizing the subscription telemetry: ", err)
+
+		return
+	}
+
+	if err := t.updateSubscriptionTelemetry(summary); err != nil {
+		klog.Warning("error while updating the subscription telemetry: ", err)
+	}
+}
+
+// getTelemetrySummary summarizes the hub subscriptions, the channels, the SubscriptionReports and the heartbeat
+// leases of the agents
+func (t *telemetrySummarizer) getTelemetrySummary() (appsubReportV1alpha1.SubscriptionTelemetrySummary, error) {
+	summary := appsubReportV1alpha1.SubscriptionTelemetrySummary{HubVersion: subutils.Version()}
+
+	subList := &appsubv1.SubscriptionList{}
+	if err := t.List(context.TODO(), subList); err != nil {
+		return summary, err
+	}
+
+	phases := map[string]int{}
+	conditions := map[string]int{}
+	signatures := map[string]int{}
+
+	for i := range subList.Items {
+		sub := &subList.Items[i]
+
+		// the subscriptions deployed by the agent of the hub cluster are reported by the SubscriptionReports
+		if sub.GetAnnotations()[appsubv1.AnnotationHosting] != "" {
+			continue
+		}
+
+		summary.Subscriptions++
+
+		phase := string(sub.Status.Phase)
+		if phase == "" {
+			phase = "Unknown"
+		}
+
+		phases[phase]++
+
+		for _, condition := range sub.Status.Conditions {
+			if condition.Status == metav1.ConditionTrue {
+				conditions[condition.Type]++
+			}
+		}
+
+		if sub.Status.Phase == appsubv1.SubscriptionFailed || sub.Status.Phase == appsubv1.SubscriptionPropagationFailed {
+			msg := sub.Status.Reason
+			if msg == "" {
+				msg = sub.Status.Message
+			}
+
+			if signature := redactErrorSignature(sub, msg); signature != "" {
+				signatures[signature]++
+			}
+		}
+	}
+
+	summary.SubscriptionPhases = toTelemetryCounts(phases)
+	summary.SubscriptionConditions = toTelemetryCounts(conditions)
+	summary.ErrorSignatures = toTelemetryErrorSignatures(signatures)
+
+	chnList := &chnv1.ChannelList{}
+	if err := t.List(context.TODO(), chnList); err != nil {
+		return summary, err
+	}
+
+	channelTypes := map[string]int{}
+
+	for _, chn := range chnList.Items {
+		channelTypes[strings.ToLower(string(chn.Spec.Type))]++
+	}
+
+	summary.Channels = len(chnList.Items)
+	summary.ChannelTypes = toTelemetryCounts(channelTypes)
+
+	reportList := &appsubReportV1alpha1.SubscriptionReportList{}
+	if err := t.List(context.TODO(), reportList); err != nil {
+		return summary, err
+	}
+
+	results := map[string]int{}
+
+	for _, report := range reportList.Items {
+		switch report.ReportType {
+		case "Cluster":
+			summary.Clusters++
+		case "Application":
+			for result, count := range map[string]string{
+				"deployed":          report.Summary.Deployed,
+				"inProgress":        report.Summary.InProgress,
+				"failed":            report.Summary.Failed,
+				"propagationFailed": report.Summary.PropagationFailed,
+			} {
+				if n, err := strconv.Atoi(count); err == nil && n > 0 {
+					results[result] += n
+				}
+			}
+		}
+	}
+
+	summary.ClusterResults = toTelemetryCounts(results)
+
+	leaseList := &coordinationv1.LeaseList{}
+	if err := t.List(context.TODO(), leaseList, client.MatchingLabels{subutils.LabelAgentHeartbeat: "true"}); err != nil {
+		return summary, err
+	}
+
+	versions := map[string]int{}
+
+	for _, lease := range leaseList.Items {
+		if lease.Name != subutils.AgentHeartbeatLeaseName {
+			continue
+		}
+
+		version := lease.GetAnnotations()[subutils.AnnotationAgentVersion]
+		if version == "" {
+			version = subutils.UnknownVersion
+		}
+
+		versions[version]++
+	}
+
+	summary.AgentVersions = toTelemetryCounts(versions)
+
+	return summary, nil
+}
+
+func (t *telemetrySummarizer) updateSubscriptionTelemetry(summary appsubReportV1alpha1.SubscriptionTelemetrySummary) error {
+	telemetry := &appsubReportV1alpha1.SubscriptionTelemetry{}
+
+	err := t.Get(context.TODO(), types.NamespacedName{Name: appsubReportV1alpha1.SubscriptionTelemetryName}, telemetry)
+	if errors.IsNotFound(err) {
+		telemetry = &appsubReportV1alpha1.SubscriptionTelemetry{
+			ObjectMeta:     metav1.ObjectMeta{Name: appsubReportV1alpha1.SubscriptionTelemetryName},
+			Summary:        summary,
+			LastUpdateTime: metav1.Now(),
+		}
+
+		klog.Info("creating the subscription telemetry")
+
+		return t.Create(context.TODO(), telemetry)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if equality.Semantic.DeepEqual(telemetry.Summary, summary) {
+		return nil
+	}
+
+	telemetry.Summary = summary
+	telemetry.LastUpdateTime = metav1.Now()
+
+	return t.Update(context.TODO(), telemetry)
+}
+
+// redactErrorSignature redacts the names of the subscription and its channel, the URLs, the quoted values, the
+// addresses, the paths, the hashes and the numbers of the error message
+func redactErrorSignature(sub *appsubv1.Subscription, msg string) string {
+	names := []string{sub.Namespace, sub.Name}
+
+	if chnNamespace, chnName, found := strings.Cut(sub.Spec.Channel, "/"); found {
+		names = append(names, chnNamespace, chnName)
+	}
+
+	// the longer names first, a name can contain another one
+	sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+
+	for _, name := range names {
+		if name != "" {
+			msg = strings.ReplaceAll(msg, name, "<name>")
+		}
+	}
+
+	for _, redaction := range telemetryRedactions {
+		msg = redaction.re.ReplaceAllString(msg, redaction.replacement)
+	}
+
+	msg = strings.TrimSpace(msg)
+
+	if len(msg) > maxTelemetrySignatureLength {
+		msg = msg[:maxTelemetrySignatureLength]
+	}
+
+	return msg
+}
+
+// toTelemetryCounts returns the counts sorted by name
+func toTelemetryCounts(counts map[string]int) []appsubReportV1alpha1.TelemetryCount {
+	telemetryCounts := []appsubReportV1alpha1.TelemetryCount{}
+
+	for name, count := range counts {
+		telemetryCounts = append(telemetryCounts, appsubReportV1alpha1.TelemetryCount{Name: name, Count: count})
+	}
+
+	sort.Slice(telemetryCounts, func(i, j int) bool { return telemetryCounts[i].Name < telemetryCounts[j].Name })
+
+	return telemetryCounts
+}
+
+// toTelemetryErrorSignatures returns the most frequent error signatures first
+func toTelemetryErrorSignatures(signatures map[string]int) []appsubReportV1alpha1.TelemetryErrorSignature {
+	errorSignatures := []appsubReportV1alpha1.TelemetryErrorSignature{}
+
+	for signature, count := range signatures {
+		errorSignatures = append(errorSignatures, appsubReportV1alpha1.TelemetryErrorSignature{Signature: signature, Count: count})
+	}
+
+	sort.Slice(errorSignatures, func(i, j int) bool {
+		if errorSignatures[i].Count != errorSignatures[j].Count {
+			return errorSignatures[i].Count > errorSignatures[j].Count
+		}
+
+		return errorSignatures[i].Signature < errorSignatures[j].Signature
+	})
+
+	if len(errorSignatures) > maxTelemetryErrorSignatures {
+		errorSignatures = errorSignatures[:maxTelemetryErrorSignatures]
+	}
+
+	return errorSignatures
+}
@@ -0,0 +1,321 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package appsubsummary
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	appsubReportV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+	subutils "open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+// allowInternalWebhooks allows the webhook endpoints on the loopback, link-local, private and cluster service addresses
+var allowInternalWebhooks bool
+
+// SetSubscriptionWebhookAllowInternal allows the SubscriptionWebhooks to post to the internal addresses, it is called
+// once before the controllers are set up
+func SetSubscriptionWebhookAllowInternal(allowed bool) {
+	allowInternalWebhooks = allowed
+}
+
+// webhookClusterKey is an appsub on a cluster
+type webhookClusterKey struct {
+	namespace    string
+	subscription string
+	cluster      string
+}
+
+// webhookClusterState is the result and revision of an appsub on a cluster last delivered to a webhook
+type webhookClusterState struct {
+	result   string
+	revision string
+}
+
+// webhookState are the states delivered to a webhook per appsub cluster, the states are delivered again when the
+// webhook spec changes. The webhook and the current states are the latest of the aggregation of the reports.
+type webhookState struct {
+	generation int64
+	webhook    *appsubReportV1alpha1.SubscriptionWebhook
+	current    map[webhookClusterKey]webhookClusterState
+	clusters   map[webhookClusterKey]webhookClusterState
+	// invalidURL is the validation error of the webhook URL reported in the status of the webhook
+	invalidURL string
+}
+
+// postSubscriptionWebhooks records the states of the appsub clusters of each SubscriptionWebhook and enqueues the
+// webhook, the changes are posted by the delivery workers. All the appsub clusters are posted when the webhook is
+// created or changed, then only the changed and removed ones. A failed webhook is retried with an exponential backoff.
+func (r *ReconcileAppSubSummary) postSubscriptionWebhooks(appSubClusterStatusMap map[string]AppSubClustersStatus) {
+	webhookList := &appsubReportV1alpha1.SubscriptionWebhookList{}
+
+	if err := r.List(context.TODO(), webhookList); err != nil {
+		klog.Errorf("failed to list subscription webhooks, err: %v", err)
+
+		return
+	}
+
+	states := map[string]*webhookState{}
+
+	r.webhookMtx.Lock()
+
+	for i := range webhookList.Items {
+		webhook := &webhookList.Items[i]
+		key := webhook.Namespace + "/" + webhook.Name
+
+		state := r.subscriptionWebhooks[key]
+		if state == nil || state.generation != webhook.Generation {
+			state = &webhookState{generation: webhook.Generation, clusters: map[webhookClusterKey]webhookClusterState{}}
+		}
+
+		state.webhook = webhook
+		state.current = webhookClusterStates(webhook, appSubClusterStatusMap)
+		states[key] = state
+	}
+
+	// forget the deleted webhooks, their pending retries find no state
+	r.subscriptionWebhooks = states
+
+	r.webhookMtx.Unlock()
+
+	for key := range states {
+		if r.webhookDeliveries != nil {
+			r.webhookDeliveries.enqueue(key)
+		}
+	}
+}
+
+// webhookClusterStates returns the states of the appsub clusters selected by the webhook
+func webhookClusterStates(webhook *appsubReportV1alpha1.SubscriptionWebhook,
+	appSubClusterStatusMap map[string]AppSubClustersStatus) map[webhookClusterKey]webhookClusterState {
+	current := map[webhookClusterKey]webhookClusterState{}
+
+	for appsub, clustersStatus := range appSubClusterStatusMap {
+		appsubNs, appsubName := subutils.ParseNamespacedName(appsub)
+		if appsubNs != webhook.Namespace || webhook.Spec.Subscription != "" && appsubName != webhook.Spec.Subscription {
+			continue
+		}
+
+		for _, clusterStatus := range clustersStatus.Clusters {
+			key := webhookClusterKey{namespace: appsubNs, subscription: appsubName, cluster: clusterStatus.Cluster}
+			current[key] = webhookClusterState{result: clusterStatus.Phase, revision: clusterStatus.Revision}
+		}
+	}
+
+	return current
+}
+
+// deliverSubscriptionWebhook posts the changes of the webhook, the changes left after a failed post are posted by the
+// retry of the webhook
+func (r *ReconcileAppSubSummary) deliverSubscriptionWebhook(key string) error {
+	r.webhookMtx.Lock()
+
+	state := r.subscriptionWebhooks[key]
+	if state == nil {
+		r.webhookMtx.Unlock()
+
+		return nil
+	}
+
+	webhook := state.webhook
+	current := state.current
+	changed := []webhookClusterKey{}
+
+	for clusterKey, cs := range current {
+		if previous, ok := state.clusters[clusterKey]; !ok || previous != cs {
+			changed = append(changed, clusterKey)
+		}
+	}
+
+	for clusterKey := range state.clusters {
+		if _, ok := current[clusterKey]; !ok {
+			changed = append(changed, clusterKey)
+		}
+	}
+
+	r.webhookMtx.Unlock()
+
+	if len(changed) == 0 {
+		return nil
+	}
+
+	if err := subutils.ValidateSubscriptionWebhookURL(webhook.Spec.URL, allowInternalWebhooks); err != nil {
+		klog.Errorf("subscription webhook %v is not delivered, err: %v", key, err)
+
+		// the invalid URL is reported once, the webhook is delivered again once its spec changes
+		if r.setWebhookInvalidURL(state, err.Error()) {
+			r.updateSubscriptionWebhookStatus(webhook, 0, err)
+		}
+
+		return nil
+	}
+
+	sort.Slice(changed, func(i, j int) bool {
+		if changed[i].cluster != changed[j].cluster {
+			return changed[i].cluster < changed[j].cluster
+		}
+
+		return changed[i].subscription < changed[j].subscription
+	})
+
+	auth, err := r.getSubscriptionWebhookAuth(webhook)
+	if err != nil {
+		r.updateSubscriptionWebhookStatus(webhook, 0, err)
+
+		return err
+	}
+
+	var delivered int64
+
+	for _, clusterKey := range changed {
+		cs, ok := current[clusterKey]
+		if !ok {
+			cs.result = appsubReportV1alpha1.WebhookResultRemoved
+		}
+
+		r.webhookMtx.Lock()
+		previous := state.clusters[clusterKey]
+		r.webhookMtx.Unlock()
+
+		if matchWebhookResult(webhook, cs.result) {
+			event := &subutils.SubscriptionWebhookEvent{
+				Webhook:          key,
+				Subscription:     clusterKey.subscription,
+				Namespace:        clusterKey.namespace,
+				Cluster:          clusterKey.cluster,
+				Revision:         cs.revision,
+				Result:           cs.result,
+				PreviousRevision: previous.revision,
+				PreviousResult:   previous.result,
+				Timestamp:        time.Now().UTC().Format(time.RFC3339),
+			}
+
+			if err := event.Post(webhook.Spec.URL, auth, webhook.Spec.InsecureSkipVerify, allowInternalWebhooks); err != nil {
+				klog.Errorf("failed to post to subscription webhook %v, err: %v", key, err)
+
+				r.updateSubscriptionWebhookStatus(webhook, delivered, err)
+
+				return err
+			}
+
+			delivered++
+		}
+
+		r.webhookMtx.Lock()
+
+		if ok {
+			state.clusters[clusterKey] = cs
+		} else {
+			delete(state.clusters, clusterKey)
+		}
+
+		r.webhookMtx.Unlock()
+	}
+
+	if delivered > 0 {
+		r.updateSubscriptionWebhookStatus(webhook, delivered, nil)
+	}
+
+	return nil
+}
+
+// setWebhookInvalidURL records the URL error of the webhook, it returns false if the error is already recorded
+func (r *ReconcileAppSubSummary) setWebhookInvalidURL(state *webhookState, urlErr string) bool {
+	r.webhookMtx.Lock()
+	defer r.webhookMtx.Unlock()
+
+	if state.invalidURL == urlErr {
+		return false
+	}
+
+	state.invalidURL = urlErr
+
+	return true
+}
+
+// matchWebhookResult checks if the webhook posts the changes to the result, the removals are always posted
+func matchWebhookResult(webhook *appsubReportV1alpha1.SubscriptionWebhook, result string) bool {
+	if len(webhook.Spec.Results) == 0 || result == appsubReportV1alpha1.WebhookResultRemoved {
+		return true
+	}
+
+	for _, r := range webhook.Spec.Results {
+		if string(r) == result {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getSubscriptionWebhookAuth returns the credentials of the webhook endpoint from the webhook secret
+func (r *ReconcileAppSubSummary) getSubscriptionWebhookAuth(
+	webhook *appsubReportV1alpha1.SubscriptionWebhook) (subutils.SubscriptionWebhookAuth, error) {
+	auth := subutils.SubscriptionWebhookAuth{}
+
+	if webhook.Spec.SecretRef == nil {
+		return auth, nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: webhook.Spec.SecretRef.Name, Namespace: webhook.Namespace},
+		secret); err != nil {
+		klog.Errorf("failed to get the secret of subscription webhook %v/%v, err: %v", webhook.Namespace, webhook.Name, err)
+
+		return auth, err
+	}
+
+	auth.Token = string(secret.Data[appsubReportV1alpha1.WebhookSecretToken])
+	auth.User = string(secret.Data[appsubReportV1alpha1.WebhookSecretUser])
+	auth.Password = string(secret.Data[appsubReportV1alpha1.WebhookSecretPassword])
+	auth.HMACKey = string(secret.Data[appsubReportV1alpha1.WebhookSecretHMACKey])
+
+	return auth, nil
+}
+
+// updateSubscriptionWebhookStatus counts the delivered changes and the failed delivery in the webhook status, the
+// latest webhook is read as the deliveries run after the aggregation that listed the webhook
+func (r *ReconcileAppSubSummary) updateSubscriptionWebhookStatus(webhook *appsubReportV1alpha1.SubscriptionWebhook,
+	delivered int64, deliveryErr error) {
+	latest := &appsubReportV1alpha1.SubscriptionWebhook{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: webhook.Name, Namespace: webhook.Namespace}, latest); err != nil {
+		klog.Errorf("failed to get subscription webhook %v/%v, err: %v", webhook.Namespace, webhook.Name, err)
+
+		return
+	}
+
+	now := metav1.NewTime(time.Now())
+
+	if delivered > 0 {
+		latest.Status.Delivered += delivered
+		latest.Status.LastDeliveryTime = &now
+		latest.Status.LastError = ""
+	}
+
+	if deliveryErr != nil {
+		latest.Status.Failed++
+		latest.Status.LastFailureTime = &now
+		latest.Status.LastError = deliveryErr.Error()
+	}
+
+	if err := r.Status().Update(context.TODO(), latest); err != nil {
+		klog.Errorf("failed to update subscription webhook %v/%v, err: %v", webhook.Namespace, webhook.Name, err)
+	}
+}
@@ -0,0 +1,251 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package appsubsummary
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	appsubReportV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+	subutils "open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPostSubscriptionWebhooks(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	var events []subutils.SubscriptionWebhookEvent
+
+	failing := false
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mac := hmac.New(sha256.New, []byte("hmac-key"))
+		mac.Write(body)
+
+		if failing || r.Header.Get("Authorization") != "Bearer demo-token" ||
+			r.Header.Get(subutils.SubscriptionWebhookSignatureHeader) != "sha256="+hex.EncodeToString(mac.Sum(nil)) {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		event := subutils.SubscriptionWebhookEvent{}
+		g.Expect(json.Unmarshal(body, &event)).To(gomega.Succeed())
+
+		events = append(events, event)
+
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	scheme := runtime.NewScheme()
+	g.Expect(appsubReportV1alpha1.AddToScheme(scheme)).To(gomega.Succeed())
+	g.Expect(corev1.AddToScheme(scheme)).To(gomega.Succeed())
+
+	// the test server listens on the loopback address
+	SetSubscriptionWebhookAllowInternal(true)
+	defer SetSubscriptionWebhookAllowInternal(false)
+
+	webhook := &appsubReportV1alpha1.SubscriptionWebhook{
+		ObjectMeta: metav1.ObjectMeta{Name: "cmdb", Namespace: "app-ns"},
+		Spec: appsubReportV1alpha1.SubscriptionWebhookSpec{
+			URL:                ts.URL,
+			SecretRef:          &corev1.LocalObjectReference{Name: "cmdb-secret"},
+			InsecureSkipVerify: true,
+		},
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "cmdb-secret", Namespace: "app-ns"},
+		Data: map[string][]byte{
+			appsubReportV1alpha1.WebhookSecretToken:   []byte("demo-token"),
+			appsubReportV1alpha1.WebhookSecretHMACKey: []byte("hmac-key"),
+		},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(webhook, secret).Build()
+	r := &ReconcileAppSubSummary{Client: c}
+	r.webhookDeliveries = newDeliveryQueue("subscription webhook", r.deliverSubscriptionWebhook)
+
+	defer r.webhookDeliveries.queue.ShutDown()
+
+	// post runs the aggregation and the enqueued deliveries
+	post := func(appSubClusterStatusMap map[string]AppSubClustersStatus) {
+		r.postSubscriptionWebhooks(appSubClusterStatusMap)
+
+		for r.webhookDeliveries.queue.Len() > 0 {
+			r.webhookDeliveries.processNext()
+		}
+	}
+
+	statusMap := func(clusters map[string]AppSubClusterStatus) map[string]AppSubClustersStatus {
+		appSubClusterStatusMap := map[string]AppSubClustersStatus{}
+
+		for appsub, cs := range clusters {
+			status := appSubClusterStatusMap[appsub]
+			status.Clusters = append(status.Clusters, cs)
+			appSubClusterStatusMap[appsub] = status
+		}
+
+		return appSubClusterStatusMap
+	}
+
+	getWebhook := func() *appsubReportV1alpha1.SubscriptionWebhook {
+		wh := &appsubReportV1alpha1.SubscriptionWebhook{}
+		g.Expect(c.Get(context.TODO(), types.NamespacedName{Name: "cmdb", Namespace: "app-ns"}, wh)).To(gomega.Succeed())
+
+		return wh
+	}
+
+	// all the appsub clusters of the namespace of the webhook are posted first
+	post(statusMap(map[string]AppSubClusterStatus{
+		"app-ns/app":   {Cluster: "cluster1", Phase: "deployed", Revision: "c1"},
+		"other-ns/app": {Cluster: "cluster1", Phase: "deployed", Revision: "c1"},
+	}))
+
+	g.Expect(events).To(gomega.HaveLen(1))
+	g.Expect(events[0].Webhook).To(gomega.Equal("app-ns/cmdb"))
+	g.Expect(events[0].Subscription).To(gomega.Equal("app"))
+	g.Expect(events[0].Namespace).To(gomega.Equal("app-ns"))
+	g.Expect(events[0].Cluster).To(gomega.Equal("cluster1"))
+	g.Expect(events[0].Result).To(gomega.Equal("deployed"))
+	g.Expect(events[0].Revision).To(gomega.Equal("c1"))
+	g.Expect(events[0].PreviousResult).To(gomega.BeEmpty())
+	g.Expect(events[0].Timestamp).NotTo(gomega.BeEmpty())
+	g.Expect(getWebhook().Status.Delivered).To(gomega.Equal(int64(1)))
+
+	// nothing is posted while the appsub clusters don't change
+	post(statusMap(map[string]AppSubClusterStatus{
+		"app-ns/app": {Cluster: "cluster1", Phase: "deployed", Revision: "c1"},
+	}))
+	g.Expect(events).To(gomega.HaveLen(1))
+
+	// a failed webhook waits for its retry, the next aggregations don't post it again
+	failing = true
+
+	changed := statusMap(map[string]AppSubClusterStatus{
+		"app-ns/app": {Cluster: "cluster1", Phase: "failed", Revision: "c2"},
+	})
+
+	post(changed)
+	g.Expect(events).To(gomega.HaveLen(1))
+	g.Expect(r.webhookDeliveries.queue.NumRequeues("app-ns/cmdb")).To(gomega.Equal(1))
+
+	wh := getWebhook()
+	g.Expect(wh.Status.Failed).To(gomega.Equal(int64(1)))
+	g.Expect(wh.Status.LastError).To(gomega.ContainSubstring("401 Unauthorized"))
+
+	failing = false
+
+	post(changed)
+	g.Expect(events).To(gomega.HaveLen(1))
+
+	// the retry posts the latest changes
+	g.Expect(r.deliverSubscriptionWebhook("app-ns/cmdb")).To(gomega.Succeed())
+	r.webhookDeliveries.queue.Forget("app-ns/cmdb")
+	g.Expect(events).To(gomega.HaveLen(2))
+	g.Expect(events[1].Result).To(gomega.Equal("failed"))
+	g.Expect(events[1].Revision).To(gomega.Equal("c2"))
+	g.Expect(events[1].PreviousResult).To(gomega.Equal("deployed"))
+	g.Expect(events[1].PreviousRevision).To(gomega.Equal("c1"))
+
+	wh = getWebhook()
+	g.Expect(wh.Status.Delivered).To(gomega.Equal(int64(2)))
+	g.Expect(wh.Status.LastError).To(gomega.BeEmpty())
+
+	// the appsub removed from a cluster is posted as removed
+	post(map[string]AppSubClustersStatus{})
+	g.Expect(events).To(gomega.HaveLen(3))
+	g.Expect(events[2].Result).To(gomega.Equal(appsubReportV1alpha1.WebhookResultRemoved))
+	g.Expect(events[2].PreviousResult).To(gomega.Equal("failed"))
+
+	// the changes to the results not selected by the webhook are not posted
+	wh = getWebhook()
+	wh.Spec.Results = []appsubReportV1alpha1.SubscriptionResult{"failed"}
+	g.Expect(c.Update(context.TODO(), wh)).To(gomega.Succeed())
+
+	// the fake client doesn't bump the generation of the webhook
+	r.subscriptionWebhooks = map[string]*webhookState{}
+
+	post(statusMap(map[string]AppSubClusterStatus{
+		"app-ns/app": {Cluster: "cluster2", Phase: "deployed", Revision: "c2"},
+	}))
+	g.Expect(events).To(gomega.HaveLen(3))
+
+	post(statusMap(map[string]AppSubClusterStatus{
+		"app-ns/app": {Cluster: "cluster2", Phase: "failed", Revision: "c3"},
+	}))
+	g.Expect(events).To(gomega.HaveLen(4))
+	g.Expect(events[3].PreviousResult).To(gomega.Equal("deployed"))
+}
+
+func TestPostSubscriptionWebhookInvalidURL(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	posted := 0
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posted++
+
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	scheme := runtime.NewScheme()
+	g.Expect(appsubReportV1alpha1.AddToScheme(scheme)).To(gomega.Succeed())
+
+	webhook := &appsubReportV1alpha1.SubscriptionWebhook{
+		ObjectMeta: metav1.ObjectMeta{Name: "cmdb", Namespace: "app-ns"},
+		Spec:       appsubReportV1alpha1.SubscriptionWebhookSpec{URL: ts.URL, InsecureSkipVerify: true},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(webhook).Build()
+	r := &ReconcileAppSubSummary{Client: c}
+	r.webhookDeliveries = newDeliveryQueue("subscription webhook", r.deliverSubscriptionWebhook)
+
+	defer r.webhookDeliveries.queue.ShutDown()
+
+	statusMap := map[string]AppSubClustersStatus{
+		"app-ns/app": {Clusters: []AppSubClusterStatus{{Cluster: "cluster1", Phase: "deployed", Revision: "c1"}}},
+	}
+
+	// the loopback endpoint of the test server is rejected, the error is reported once
+	for i := 0; i < 2; i++ {
+		r.postSubscriptionWebhooks(statusMap)
+		g.Expect(r.webhookDeliveries.processNext()).To(gomega.BeTrue())
+	}
+
+	g.Expect(posted).To(gomega.Equal(0))
+	g.Expect(r.webhookDeliveries.queue.NumRequeues("app-ns/cmdb")).To(gomega.Equal(0))
+
+	wh := &appsubReportV1alpha1.SubscriptionWebhook{}
+	g.Expect(c.Get(context.TODO(), types.NamespacedName{Name: "cmdb", Namespace: "app-ns"}, wh)).To(gomega.Succeed())
+	g.Expect(wh.Status.Failed).To(gomega.Equal(int64(1)))
+	g.Expect(wh.Status.LastError).To(gomega.ContainSubstring("internal address"))
+}
@@ -0,0 +1,346 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	authv1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+// propagationAccessReview enables the SubjectAccessReviews of the subscription users before the propagation
+var propagationAccessReview bool
+
+// SetPropagationAccessReview enables the access review of the user who created a subscription before the hub propagates
+// it, it is called once before the controllers are set up
+func SetPropagationAccessReview(enabled bool) {
+	propagationAccessReview = enabled
+}
+
+// accessReviewCacheTTL is how long the result of a SubjectAccessReview is reused, the subscriptions of a user deploying
+// the same kinds to the same clusters don't review them again on every reconcile
+const accessReviewCacheTTL = time.Minute
+
+// accessReviewer returns if the SubjectAccessReview is allowed
+type accessReviewer func(sar *authv1.SubjectAccessReview) (bool, error)
+
+// accessResource is a resource kind deployed by a subscription
+type accessResource struct {
+	gvr        schema.GroupVersionResource
+	kind       string
+	namespaced bool
+}
+
+type accessReviewEntry struct {
+	allowed bool
+	expires time.Time
+}
+
+// accessReviewCache keeps the results of the SubjectAccessReviews by user, groups, resource and namespace for
+// accessReviewCacheTTL. A nil cache caches nothing
+type accessReviewCache struct {
+	mtx     sync.Mutex
+	entries map[string]accessReviewEntry
+}
+
+func newAccessReviewCache() *accessReviewCache {
+	return &accessReviewCache{entries: map[string]accessReviewEntry{}}
+}
+
+func accessReviewKey(sar *authv1.SubjectAccessReview) string {
+	attrs := sar.Spec.ResourceAttributes
+
+	return strings.Join([]string{sar.Spec.User, strings.Join(sar.Spec.Groups, ","), attrs.Verb, attrs.Group,
+		attrs.Resource, attrs.Namespace}, "|")
+}
+
+func (c *accessReviewCache) get(key string, now time.Time) (allowed, found bool) {
+	if c == nil {
+		return false, false
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || !now.Before(entry.expires) {
+		return false, false
+	}
+
+	return entry.allowed, true
+}
+
+// set records the result of a review and drops the expired results
+func (c *accessReviewCache) set(key string, allowed bool, now time.Time) {
+	if c == nil {
+		return
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for k, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+
+	c.entries[key] = accessReviewEntry{allowed: allowed, expires: now.Add(accessReviewCacheTTL)}
+}
+
+// reviewAccess creates the SubjectAccessReview on the hub, the results are cached for accessReviewCacheTTL and the
+// failed reviews are not cached
+func (r *ReconcileSubscription) reviewAccess(sar *authv1.SubjectAccessReview) (bool, error) {
+	key := accessReviewKey(sar)
+
+	if allowed, found := r.accessReviews.get(key, time.Now()); found {
+		return allowed, nil
+	}
+
+	var allowed bool
+
+	if r.accessReviewer != nil {
+		var err error
+
+		if allowed, err = r.accessReviewer(sar); err != nil {
+			return false, err
+		}
+	} else {
+		if err := r.Create(context.TODO(), sar); err != nil {
+			return false, err
+		}
+
+		allowed = sar.Status.Allowed
+	}
+
+	r.accessReviews.set(key, allowed, time.Now())
+
+	return allowed, nil
+}
+
+// getAccessResources returns the distinct resource kinds of the subscription resources sorted by group and kind
+func (r *ReconcileSubscription) getAccessResources(resources []*v1.ObjectReference) []accessResource {
+	found := map[schema.GroupKind]accessResource{}
+
+	for _, res := range resources {
+		if res == nil || res.Kind == "" {
+			continue
+		}
+
+		gvk := schema.FromAPIVersionAndKind(res.APIVersion, res.Kind)
+		if _, ok := found[gvk.GroupKind()]; ok {
+			continue
+		}
+
+		// the kinds unknown to the hub are reviewed as namespaced resources
+		gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+		accessRes := accessResource{gvr: gvr, kind: gvk.Kind, namespaced: true}
+
+		if r.restMapper != nil {
+			if mapping, err := r.restMapper.RESTMapping(gvk.GroupKind(), gvk.Version); err == nil {
+				accessRes.gvr = mapping.Resource
+				accessRes.namespaced = mapping.Scope.Name() == meta.RESTScopeNameNamespace
+			}
+		}
+
+		found[gvk.GroupKind()] = accessRes
+	}
+
+	accessResources := make([]accessResource, 0, len(found))
+	for _, accessRes := range found {
+		accessResources = append(accessResources, accessRes)
+	}
+
+	sort.Slice(accessResources, func(i, j int) bool {
+		if accessResources[i].gvr.Group != accessResources[j].gvr.Group {
+			return accessResources[i].gvr.Group < accessResources[j].gvr.Group
+		}
+
+		return accessResources[i].kind < accessResources[j].kind
+	})
+
+	return accessResources
+}
+
+// checkPropagationAccess reviews if the user who created the subscription can create its resource kinds in the namespaces
+// of the managed clusters on the hub. The namespaced kinds are allowed by the permissions in all the namespaces or in the
+// namespace of each cluster, the cluster scoped kinds by the cluster wide permissions. The subscriptions of the
+// subscription admins and the subscriptions deployed by a hosting subscription are not reviewed, the other subscriptions
+// without user identity are denied. The PermissionDenied condition of the subscription is updated, an error is returned
+// if the propagation is denied.
+func (r *ReconcileSubscription) checkPropagationAccess(sub *appv1.Subscription, clusters []ManageClusters,
+	resources []*v1.ObjectReference, isAdmin bool) error {
+	if !propagationAccessReview || isAdmin {
+		meta.RemoveStatusCondition(&sub.Status.Conditions, appv1.ConditionPermissionDenied)
+
+		return nil
+	}
+
+	annotations := sub.GetAnnotations()
+
+	user := ""
+	if encoded := strings.TrimSpace(annotations[appv1.AnnotationUserIdentity]); encoded != "" {
+		user = utils.Base64StringDecode(encoded)
+	}
+
+	if user == "" {
+		if r.isHostedSubscription(sub) {
+			klog.V(1).Infof("subscription %v/%v is created by its hosting subscription, skip the access review",
+				sub.Namespace, sub.Name)
+			meta.RemoveStatusCondition(&sub.Status.Conditions, appv1.ConditionPermissionDenied)
+
+			return nil
+		}
+
+		return r.denyPropagation(sub, appv1.ReasonMissingUserIdentity,
+			fmt.Errorf("subscription %v/%v has no user identity to review the access of", sub.Namespace, sub.Name))
+	}
+
+	groups := []string{}
+
+	if encoded := strings.TrimSpace(annotations[appv1.AnnotationUserGroup]); encoded != "" {
+		for _, group := range strings.Split(utils.Base64StringDecode(encoded), ",") {
+			if group = strings.TrimSpace(group); group != "" {
+				groups = append(groups, group)
+			}
+		}
+	}
+
+	denied := []string{}
+
+	for _, accessRes := range r.getAccessResources(resources) {
+		deniedClusters, err := r.reviewResourceAccess(user, groups, accessRes, clusters)
+		if err != nil {
+			return fmt.Errorf("failed to review the access of user %v to %v: %w", user, accessRes.gvr.GroupResource(), err)
+		}
+
+		if len(deniedClusters) > 0 {
+			denied = append(denied, fmt.Sprintf("%v on clusters %v", accessRes.gvr.GroupResource(), strings.Join(deniedClusters, ",")))
+		}
+	}
+
+	if len(denied) == 0 {
+		meta.RemoveStatusCondition(&sub.Status.Conditions, appv1.ConditionPermissionDenied)
+
+		return nil
+	}
+
+	return r.denyPropagation(sub, appv1.ReasonAccessReviewDenied,
+		fmt.Errorf("user %v is not allowed to deploy %v", user, strings.Join(denied, "; ")))
+}
+
+// denyPropagation sets the PermissionDenied condition of the subscription, records a warning event and returns the
+// denial error
+func (r *ReconcileSubscription) denyPropagation(sub *appv1.Subscription, reason string, deniedErr error) error {
+	meta.SetStatusCondition(&sub.Status.Conditions, metav1.Condition{
+		Type:               appv1.ConditionPermissionDenied,
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		Message:            deniedErr.Error(),
+		ObservedGeneration: sub.GetGeneration(),
+	})
+
+	if r.eventRecorder != nil {
+		r.eventRecorder.RecordEvent(sub, appv1.ConditionPermissionDenied, deniedErr.Error(), deniedErr)
+	}
+
+	return deniedErr
+}
+
+// isHostedSubscription checks if the subscription is deployed by another subscription of the hub, from the hosting
+// subscription annotation. The hosting subscription must exist, so the annotation alone doesn't skip the review
+func (r *ReconcileSubscription) isHostedSubscription(sub *appv1.Subscription) bool {
+	hosting := strings.Split(sub.GetAnnotations()[appv1.AnnotationHosting], "/")
+	if len(hosting) != 2 || hosting[0] == "" || hosting[1] == "" {
+		return false
+	}
+
+	if hosting[0] == sub.Namespace && hosting[1] == sub.Name {
+		return false
+	}
+
+	hostingSub := &appv1.Subscription{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Namespace: hosting[0], Name: hosting[1]}, hostingSub); err != nil {
+		klog.V(1).Infof("hosting subscription %v/%v of subscription %v/%v is not found, err: %v", hosting[0], hosting[1],
+			sub.Namespace, sub.Name, err)
+
+		return false
+	}
+
+	return true
+}
+
+// reviewResourceAccess returns the clusters the user can't deploy the resource kind to, the namespace of each cluster
+// is only reviewed if the user can't create the resource in all the namespaces
+func (r *ReconcileSubscription) reviewResourceAccess(user string, groups []string, accessRes accessResource,
+	clusters []ManageClusters) ([]string, error) {
+	newSAR := func(namespace string) *authv1.SubjectAccessReview {
+		return &authv1.SubjectAccessReview{
+			Spec: authv1.SubjectAccessReviewSpec{
+				User:   user,
+				Groups: groups,
+				ResourceAttributes: &authv1.ResourceAttributes{
+					Namespace: namespace,
+					Verb:      "create",
+					Group:     accessRes.gvr.Group,
+					Version:   accessRes.gvr.Version,
+					Resource:  accessRes.gvr.Resource,
+				},
+			},
+		}
+	}
+
+	allowed, err := r.reviewAccess(newSAR(""))
+	if err != nil {
+		return nil, err
+	}
+
+	if allowed {
+		return nil, nil
+	}
+
+	if !accessRes.namespaced {
+		return []string{"*"}, nil
+	}
+
+	deniedClusters := []string{}
+
+	for _, cluster := range clusters {
+		allowed, err := r.reviewAccess(newSAR(cluster.Cluster))
+		if err != nil {
+			return nil, err
+		}
+
+		if !allowed {
+			deniedClusters = append(deniedClusters, cluster.Cluster)
+		}
+	}
+
+	return deniedClusters, nil
+}
@@ -0,0 +1,181 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	authv1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func TestCheckPropagationAccess(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	SetPropagationAccessReview(true)
+	defer SetPropagationAccessReview(false)
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "demo",
+			Namespace: "demo-ns",
+			Annotations: map[string]string{
+				appv1.AnnotationUserIdentity: base64.StdEncoding.EncodeToString([]byte("alice")),
+				appv1.AnnotationUserGroup:    base64.StdEncoding.EncodeToString([]byte("devs,system:authenticated")),
+			},
+		},
+	}
+
+	clusters := []ManageClusters{{Cluster: "cluster1"}, {Cluster: "cluster2"}}
+	resources := []*v1.ObjectReference{
+		{APIVersion: "v1", Kind: "ConfigMap", Namespace: "demo-ns", Name: "cm1"},
+		{APIVersion: "v1", Kind: "ConfigMap", Namespace: "demo-ns", Name: "cm2"},
+		{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "demo-ns", Name: "web"},
+	}
+
+	reviews := []authv1.ResourceAttributes{}
+
+	// alice can create configmaps everywhere and deployments in the namespace of cluster1
+	r := &ReconcileSubscription{accessReviewer: func(sar *authv1.SubjectAccessReview) (bool, error) {
+		g.Expect(sar.Spec.User).To(gomega.Equal("alice"))
+		g.Expect(sar.Spec.Groups).To(gomega.Equal([]string{"devs", "system:authenticated"}))
+
+		attrs := sar.Spec.ResourceAttributes
+		reviews = append(reviews, *attrs)
+
+		return attrs.Resource == "configmaps" || attrs.Namespace == "cluster1", nil
+	}}
+
+	err := r.checkPropagationAccess(sub, clusters, resources, false)
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.Equal("user alice is not allowed to deploy deployments.apps on clusters cluster2"))
+	g.Expect(meta.IsStatusConditionTrue(sub.Status.Conditions, appv1.ConditionPermissionDenied)).To(gomega.BeTrue())
+	g.Expect(reviews).To(gomega.Equal([]authv1.ResourceAttributes{
+		{Verb: "create", Version: "v1", Resource: "configmaps"},
+		{Verb: "create", Group: "apps", Version: "v1", Resource: "deployments"},
+		{Namespace: "cluster1", Verb: "create", Group: "apps", Version: "v1", Resource: "deployments"},
+		{Namespace: "cluster2", Verb: "create", Group: "apps", Version: "v1", Resource: "deployments"},
+	}))
+
+	// the subscription admins are not reviewed
+	g.Expect(r.checkPropagationAccess(sub, clusters, resources, true)).To(gomega.Succeed())
+	g.Expect(meta.FindStatusCondition(sub.Status.Conditions, appv1.ConditionPermissionDenied)).To(gomega.BeNil())
+
+	g.Expect(r.checkPropagationAccess(sub, clusters[:1], resources, false)).To(gomega.Succeed())
+
+	// the subscriptions without user identity are denied
+	sub.SetAnnotations(nil)
+	g.Expect(r.checkPropagationAccess(sub, clusters, resources, false)).NotTo(gomega.Succeed())
+
+	cond := meta.FindStatusCondition(sub.Status.Conditions, appv1.ConditionPermissionDenied)
+	g.Expect(cond).NotTo(gomega.BeNil())
+	g.Expect(cond.Reason).To(gomega.Equal(appv1.ReasonMissingUserIdentity))
+}
+
+func TestCheckPropagationAccessHostedSubscription(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	SetPropagationAccessReview(true)
+	defer SetPropagationAccessReview(false)
+
+	scheme := runtime.NewScheme()
+	g.Expect(appv1.SchemeBuilder.AddToScheme(scheme)).To(gomega.Succeed())
+
+	parent := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "parent", Namespace: "demo-ns"}}
+	r := &ReconcileSubscription{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(parent).Build(),
+		accessReviewer: func(sar *authv1.SubjectAccessReview) (bool, error) {
+			return false, nil
+		},
+	}
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "child",
+			Namespace:   "demo-ns",
+			Annotations: map[string]string{appv1.AnnotationHosting: "demo-ns/parent"},
+		},
+	}
+
+	clusters := []ManageClusters{{Cluster: "cluster1"}}
+	resources := []*v1.ObjectReference{{APIVersion: "v1", Kind: "ConfigMap", Namespace: "demo-ns", Name: "cm"}}
+
+	// the subscriptions deployed by a hosting subscription of the hub are not reviewed
+	g.Expect(r.checkPropagationAccess(sub, clusters, resources, false)).To(gomega.Succeed())
+	g.Expect(meta.FindStatusCondition(sub.Status.Conditions, appv1.ConditionPermissionDenied)).To(gomega.BeNil())
+
+	// the hosting annotation of a missing subscription doesn't skip the review
+	sub.Annotations[appv1.AnnotationHosting] = "demo-ns/missing"
+	g.Expect(r.checkPropagationAccess(sub, clusters, resources, false)).NotTo(gomega.Succeed())
+	g.Expect(meta.IsStatusConditionTrue(sub.Status.Conditions, appv1.ConditionPermissionDenied)).To(gomega.BeTrue())
+}
+
+func TestReviewAccessCache(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	calls := 0
+	r := &ReconcileSubscription{
+		accessReviews: newAccessReviewCache(),
+		accessReviewer: func(sar *authv1.SubjectAccessReview) (bool, error) {
+			calls++
+
+			return sar.Spec.ResourceAttributes.Namespace == "cluster1", nil
+		},
+	}
+
+	newSAR := func(user, namespace string) *authv1.SubjectAccessReview {
+		return &authv1.SubjectAccessReview{Spec: authv1.SubjectAccessReviewSpec{
+			User: user,
+			ResourceAttributes: &authv1.ResourceAttributes{
+				Namespace: namespace, Verb: "create", Group: "apps", Version: "v1", Resource: "deployments",
+			},
+		}}
+	}
+
+	for i := 0; i < 3; i++ {
+		allowed, err := r.reviewAccess(newSAR("alice", "cluster1"))
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+		g.Expect(allowed).To(gomega.BeTrue())
+
+		allowed, err = r.reviewAccess(newSAR("alice", "cluster2"))
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+		g.Expect(allowed).To(gomega.BeFalse())
+	}
+
+	// each user and cluster is reviewed once
+	g.Expect(calls).To(gomega.Equal(2))
+
+	_, err := r.reviewAccess(newSAR("bob", "cluster1"))
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(calls).To(gomega.Equal(3))
+
+	// the results expire after the TTL
+	key := accessReviewKey(newSAR("alice", "cluster1"))
+
+	_, found := r.accessReviews.get(key, time.Now())
+	g.Expect(found).To(gomega.BeTrue())
+
+	_, found = r.accessReviews.get(key, time.Now().Add(accessReviewCacheTTL))
+	g.Expect(found).To(gomega.BeFalse())
+}
@@ -0,0 +1,270 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	manifestWorkV1 "open-cluster-management.io/api/work/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	appSubV1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+var (
+	// agentHeartbeatTimeout is how long an agent can go without renewing its heartbeat lease before the hub flags the
+	// subscriptions of its cluster
+	agentHeartbeatTimeout = 15 * time.Minute
+	// agentHeartbeatCheckInterval is how often the hub checks the heartbeat leases, an expiring lease doesn't trigger
+	// a reconcile
+	agentHeartbeatCheckInterval = time.Minute
+)
+
+// the conditions of the agents name the first clusters only
+const maxConditionClusters = 10
+
+// SetAgentHeartbeatTimeout sets how long an agent can go without renewing its heartbeat lease, it is called once
+// before the controllers are set up
+func SetAgentHeartbeatTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		agentHeartbeatTimeout = timeout
+	}
+}
+
+// setAgentNotReportingCondition sets the AgentNotReporting condition of the subscription from the heartbeat leases of
+// its clusters. The clusters without a heartbeat lease run an agent that doesn't report one and are not flagged
+func (r *ReconcileSubscription) setAgentNotReportingCondition(sub *appSubV1.Subscription, clusters []ManageClusters,
+	now time.Time) {
+	expired := []string{}
+
+	for _, cluster := range clusters {
+		if lease := r.getHeartbeatLease(cluster.Cluster); lease != nil &&
+			utils.IsAgentHeartbeatExpired(lease, agentHeartbeatTimeout, now) {
+			expired = append(expired, cluster.Cluster)
+		}
+	}
+
+	if len(expired) == 0 {
+		meta.RemoveStatusCondition(&sub.Status.Conditions, appSubV1.ConditionAgentNotReporting)
+
+		return
+	}
+
+	meta.SetStatusCondition(&sub.Status.Conditions, metav1.Condition{
+		Type:   appSubV1.ConditionAgentNotReporting,
+		Status: metav1.ConditionTrue,
+		Reason: appSubV1.ReasonAgentHeartbeatExpired,
+		Message: fmt.Sprintf("the agents of %v clusters haven't reported for %v: %v", len(expired),
+			agentHeartbeatTimeout, joinConditionClusters(expired)),
+		ObservedGeneration: sub.GetGeneration(),
+	})
+}
+
+// getHeartbeatLease returns the heartbeat lease of the agent of the cluster, nil if the agent doesn't report one
+func (r *ReconcileSubscription) getHeartbeatLease(cluster string) *coordinationv1.Lease {
+	lease := &coordinationv1.Lease{}
+
+	err := r.Get(context.TODO(), types.NamespacedName{Namespace: cluster, Name: utils.AgentHeartbeatLeaseName}, lease)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+
+	if err != nil {
+		klog.Warningf("failed to get the heartbeat lease of cluster %v, err: %v", cluster, err)
+
+		return nil
+	}
+
+	return lease
+}
+
+// joinConditionClusters joins the sorted names of the first clusters for the message of a condition
+func joinConditionClusters(clusters []string) string {
+	sort.Strings(clusters)
+
+	if len(clusters) > maxConditionClusters {
+		return fmt.Sprintf("%v and %v more", strings.Join(clusters[:maxConditionClusters], ", "),
+			len(clusters)-maxConditionClusters)
+	}
+
+	return strings.Join(clusters, ", ")
+}
+
+// agentHeartbeatMonitor checks the heartbeat leases of the agents and enqueues the subscriptions of the clusters whose
+// lease expired, is renewed again or reports another agent version, the hub updates the conditions of their agents
+type agentHeartbeatMonitor struct {
+	client.Client
+	events chan event.GenericEvent
+	// states are the expiry and the version of the agent of each cluster at the last check
+	states map[string]agentState
+}
+
+type agentState struct {
+	expired bool
+	version string
+}
+
+func newAgentHeartbeatMonitor(c client.Client) *agentHeartbeatMonitor {
+	return &agentHeartbeatMonitor{
+		Client: c,
+		events: make(chan event.GenericEvent, 1024),
+	}
+}
+
+// Start checks the heartbeat leases until the context is done
+func (m *agentHeartbeatMonitor) Start(ctx context.Context) error {
+	ticker := time.NewTicker(agentHeartbeatCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			m.check(time.Now())
+		}
+	}
+}
+
+// NeedLeaderElection runs the monitor on the leader only, the subscriptions are reconciled by the leader
+func (m *agentHeartbeatMonitor) NeedLeaderElection() bool {
+	return true
+}
+
+// check enqueues the subscriptions of the clusters whose agent state changed since the last check and sets the
+// agent version metrics. The first check only records the states, all the subscriptions are reconciled when the hub
+// starts
+func (m *agentHeartbeatMonitor) check(now time.Time) {
+	leaseList := &coordinationv1.LeaseList{}
+
+	if err := m.List(context.TODO(), leaseList, client.MatchingLabels{utils.LabelAgentHeartbeat: "true"}); err != nil {
+		klog.Errorf("failed to list the heartbeat leases, err: %v", err)
+
+		return
+	}
+
+	states := map[string]agentState{}
+
+	for i := range leaseList.Items {
+		lease := &leaseList.Items[i]
+		if lease.Name != utils.AgentHeartbeatLeaseName {
+			continue
+		}
+
+		states[lease.Namespace] = agentState{
+			expired: utils.IsAgentHeartbeatExpired(lease, agentHeartbeatTimeout, now),
+			version: lease.GetAnnotations()[utils.AnnotationAgentVersion],
+		}
+	}
+
+	setAgentVersionMetrics(states)
+
+	previous := m.states
+	m.states = states
+
+	if previous == nil {
+		return
+	}
+
+	changed := []string{}
+
+	for cluster, state := range states {
+		last, ok := previous[cluster]
+
+		switch {
+		case ok && last == state:
+			continue
+		case state.expired && !last.expired:
+			klog.Warningf("the agent of cluster %v hasn't renewed its heartbeat lease for %v", cluster, agentHeartbeatTimeout)
+		case !state.expired && last.expired:
+			klog.Infof("the agent of cluster %v renewed its heartbeat lease", cluster)
+		case ok:
+			klog.Infof("the agent of cluster %v is upgraded from %v to %v", cluster, last.version, state.version)
+		}
+
+		changed = append(changed, cluster)
+	}
+
+	for cluster := range previous {
+		if _, ok := states[cluster]; !ok {
+			changed = append(changed, cluster)
+		}
+	}
+
+	if len(changed) > 0 {
+		m.enqueueClusterSubscriptions(changed)
+	}
+}
+
+// enqueueClusterSubscriptions enqueues the hub subscriptions with a ManifestWork in the cluster namespaces
+func (m *agentHeartbeatMonitor) enqueueClusterSubscriptions(clusters []string) {
+	subList := &appSubV1.SubscriptionList{}
+	if err := m.List(context.TODO(), subList); err != nil {
+		klog.Errorf("failed to list the subscriptions, err: %v", err)
+
+		return
+	}
+
+	// the ManifestWork of a subscription is named <namespace>-<name>
+	subs := map[string]*appSubV1.Subscription{}
+
+	for i := range subList.Items {
+		sub := &subList.Items[i]
+		subs[sub.Namespace+"-"+sub.Name] = sub
+	}
+
+	enqueued := map[types.NamespacedName]bool{}
+
+	for _, cluster := range clusters {
+		manifestWorkList := &manifestWorkV1.ManifestWorkList{}
+		if err := m.List(context.TODO(), manifestWorkList, client.InNamespace(cluster)); err != nil {
+			klog.Errorf("failed to list the manifestWorks of cluster %v, err: %v", cluster, err)
+
+			continue
+		}
+
+		for _, manifestWork := range manifestWorkList.Items {
+			sub, ok := subs[manifestWork.Name]
+			if !ok || manifestWork.GetLabels()[appSubV1.AnnotationHosting] == "" {
+				continue
+			}
+
+			key := types.NamespacedName{Namespace: sub.Namespace, Name: sub.Name}
+			if enqueued[key] {
+				continue
+			}
+
+			enqueued[key] = true
+
+			select {
+			case m.events <- event.GenericEvent{Object: sub}:
+			default:
+				klog.Warningf("the heartbeat events are full, subscription %v is updated on its next reconcile", key)
+			}
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	manifestWorkV1 "open-cluster-management.io/api/work/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+func heartbeatLease(cluster string, renewTime time.Time) *coordinationv1.Lease {
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      utils.AgentHeartbeatLeaseName,
+			Namespace: cluster,
+			Labels:    map[string]string{utils.LabelAgentHeartbeat: "true"},
+		},
+		Spec: coordinationv1.LeaseSpec{RenewTime: &metav1.MicroTime{Time: renewTime}},
+	}
+}
+
+func TestAgentNotReportingCondition(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(coordinationv1.AddToScheme(scheme)).To(gomega.Succeed())
+
+	now := time.Now()
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		heartbeatLease("cluster1", now.Add(-time.Minute)),
+		heartbeatLease("cluster2", now.Add(-time.Hour)),
+	).Build()
+
+	r := &ReconcileSubscription{Client: c}
+
+	sub := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns"}}
+
+	// cluster3 runs an agent without a heartbeat lease, it isn't flagged
+	r.setAgentNotReportingCondition(sub, []ManageClusters{{Cluster: "cluster1"}, {Cluster: "cluster2"}, {Cluster: "cluster3"}}, now)
+
+	cond := meta.FindStatusCondition(sub.Status.Conditions, appv1.ConditionAgentNotReporting)
+	g.Expect(cond).NotTo(gomega.BeNil())
+	g.Expect(cond.Status).To(gomega.Equal(metav1.ConditionTrue))
+	g.Expect(cond.Reason).To(gomega.Equal(appv1.ReasonAgentHeartbeatExpired))
+	g.Expect(cond.Message).To(gomega.Equal("the agents of 1 clusters haven't reported for 15m0s: cluster2"))
+
+	// the condition is removed once the agents report again
+	r.setAgentNotReportingCondition(sub, []ManageClusters{{Cluster: "cluster1"}}, now)
+	g.Expect(meta.FindStatusCondition(sub.Status.Conditions, appv1.ConditionAgentNotReporting)).To(gomega.BeNil())
+}
+
+func TestAgentHeartbeatMonitor(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(coordinationv1.AddToScheme(scheme)).To(gomega.Succeed())
+	g.Expect(appv1.SchemeBuilder.AddToScheme(scheme)).To(gomega.Succeed())
+	g.Expect(manifestWorkV1.AddToScheme(scheme)).To(gomega.Succeed())
+
+	now := time.Now()
+
+	lease := heartbeatLease("cluster1", now)
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		lease,
+		heartbeatLease("cluster2", now),
+		&appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns"}},
+		&appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "demo-ns"}},
+		&manifestWorkV1.ManifestWork{ObjectMeta: metav1.ObjectMeta{
+			Name:      "demo-ns-demo",
+			Namespace: "cluster1",
+			Labels:    map[string]string{appv1.AnnotationHosting: "demo-ns.demo"},
+		}},
+		&manifestWorkV1.ManifestWork{ObjectMeta: metav1.ObjectMeta{
+			Name:      "demo-ns-other",
+			Namespace: "cluster2",
+			Labels:    map[string]string{appv1.AnnotationHosting: "demo-ns.other"},
+		}},
+	).Build()
+
+	monitor := newAgentHeartbeatMonitor(c)
+
+	// the first check records the expired leases only
+	monitor.check(now.Add(time.Hour))
+	g.Expect(monitor.events).To(gomega.BeEmpty())
+
+	// cluster1 renews its lease, its subscription is enqueued to clear the condition
+	lease.Spec.RenewTime = &metav1.MicroTime{Time: now.Add(time.Hour)}
+	g.Expect(c.Update(context.TODO(), lease)).To(gomega.Succeed())
+
+	monitor.check(now.Add(time.Hour))
+	g.Expect(monitor.events).To(gomega.HaveLen(1))
+
+	evt := <-monitor.events
+	g.Expect(evt.Object.GetName()).To(gomega.Equal("demo"))
+
+	// nothing changed since the last check
+	monitor.check(now.Add(time.Hour))
+	g.Expect(monitor.events).To(gomega.BeEmpty())
+	// the agent of cluster2 is upgraded
+	upgraded := heartbeatLease("cluster2", now.Add(time.Hour))
+	g.Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(upgraded), upgraded)).To(gomega.Succeed())
+	upgraded.Spec.RenewTime = &metav1.MicroTime{Time: now.Add(time.Hour)}
+	upgraded.Annotations = map[string]string{utils.AnnotationAgentVersion: "2.8.0"}
+	g.Expect(c.Update(context.TODO(), upgraded)).To(gomega.Succeed())
+
+	monitor.check(now.Add(time.Hour))
+	g.Expect(monitor.events).To(gomega.HaveLen(1))
+
+	evt = <-monitor.events
+	g.Expect(evt.Object.GetName()).To(gomega.Equal("other"))
+}
@@ -0,0 +1,401 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+	addonV1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	spokeClusterV1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	appSubV1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appSubStatusV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+const (
+	agentUpgradeControllerName = "mcmhub-agent-upgrade-controller"
+	// agentAddonName is the name of the ManagedClusterAddOn of the agent
+	agentAddonName = "application-manager"
+	// defaultAgentUpgradeTimeout is how long the agent of a cluster can take to report the version by default
+	defaultAgentUpgradeTimeout = 15 * time.Minute
+)
+
+var (
+	// agentUpgradeRequeueInterval is how often the hub checks the heartbeat leases of the agents while an upgrade is
+	// in progress, the leases are not watched
+	agentUpgradeRequeueInterval = time.Minute
+	// agentUpgradeResyncInterval is how often the hub rolls out a completed upgrade to the clusters joining its waves
+	agentUpgradeResyncInterval = 10 * time.Minute
+)
+
+// blank assignment to verify that ReconcileAgentUpgrade implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileAgentUpgrade{}
+
+// ReconcileAgentUpgrade rolls out the agent image of the AgentUpgrades to the managed clusters wave by wave. The image
+// is set on the application-manager ManagedClusterAddOn of the clusters of a wave once the wave starts, a wave is
+// upgraded once all its agents report the version in their heartbeat lease. The next wave starts once the previous
+// one has been upgraded during its soak duration, the same way the promotion rings of a subscription are promoted
+type ReconcileAgentUpgrade struct {
+	client.Client
+	clk clock
+}
+
+// addAgentUpgrade adds the AgentUpgrade controller to mgr
+func addAgentUpgrade(mgr manager.Manager) error {
+	r := &ReconcileAgentUpgrade{
+		Client: utils.ControllerClient(mgr, agentUpgradeControllerName),
+		clk:    time.Now,
+	}
+
+	c, err := controller.New(agentUpgradeControllerName, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	return c.Watch(&source.Kind{Type: &appSubStatusV1alpha1.AgentUpgrade{}}, &handler.EnqueueRequestForObject{},
+		predicate.GenerationChangedPredicate{})
+}
+
+// Reconcile updates the waves of the AgentUpgrade and rolls out its image to the clusters of the started waves
+func (r *ReconcileAgentUpgrade) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	upgrade := &appSubStatusV1alpha1.AgentUpgrade{}
+	if err := r.Get(ctx, request.NamespacedName, upgrade); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+
+		return reconcile.Result{}, err
+	}
+
+	waveClusters, err := r.getAgentUpgradeWaveClusters(upgrade)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	leases, err := r.getHeartbeatLeases()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	now := r.clk()
+	status := getAgentUpgradeStatus(upgrade, waveClusters, leases, now)
+
+	for i, waveStatus := range status.Waves {
+		if waveStatus.StartedTime == nil {
+			continue
+		}
+
+		for _, cluster := range waveClusters[i] {
+			if err := r.setAgentUpgradeImage(cluster, upgrade.Spec.Image); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+	}
+
+	if !equality.Semantic.DeepEqual(status, upgrade.Status) {
+		for _, waveStatus := range status.Waves {
+			if waveStatus.Phase == appSubStatusV1alpha1.AgentUpgradeWaveUpgrading && len(waveStatus.StalledClusters) > 0 {
+				klog.Warningf("the agent upgrade %v stalled, the agents of clusters %v of wave %v don't report version %v",
+					upgrade.Name, joinConditionClusters(waveStatus.StalledClusters), waveStatus.Name, upgrade.Spec.Version)
+			}
+		}
+
+		status.LastUpdateTime = metav1.NewTime(now)
+		upgrade.Status = status
+
+		if err := r.Status().Update(ctx, upgrade); err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to update the status of agent upgrade %v, err: %w", upgrade.Name, err)
+		}
+	}
+
+	if status.Phase == appSubStatusV1alpha1.AgentUpgradeCompleted {
+		return reconcile.Result{RequeueAfter: agentUpgradeResyncInterval}, nil
+	}
+
+	return reconcile.Result{RequeueAfter: agentUpgradeRequeueInterval}, nil
+}
+
+// getAgentUpgradeWaveClusters returns the sorted clusters of each wave of the upgrade, a cluster belongs to the first
+// wave selecting it. The clusters without the agent addon are not upgraded
+func (r *ReconcileAgentUpgrade) getAgentUpgradeWaveClusters(upgrade *appSubStatusV1alpha1.AgentUpgrade) ([][]string, error) {
+	clusterList := &spokeClusterV1.ManagedClusterList{}
+	if err := r.List(context.TODO(), clusterList); err != nil {
+		return nil, fmt.Errorf("failed to list the managed clusters, err: %w", err)
+	}
+
+	addonList := &addonV1alpha1.ManagedClusterAddOnList{}
+	if err := r.List(context.TODO(), addonList); err != nil {
+		return nil, fmt.Errorf("failed to list the managed cluster addons, err: %w", err)
+	}
+
+	agents := map[string]bool{}
+
+	for _, addon := range addonList.Items {
+		if addon.Name == agentAddonName {
+			agents[addon.Namespace] = true
+		}
+	}
+
+	selectors := make([]labels.Selector, len(upgrade.Spec.Waves))
+
+	for i, wave := range upgrade.Spec.Waves {
+		selector := labels.Everything()
+
+		if wave.ClusterSelector != nil {
+			var err error
+
+			selector, err = metav1.LabelSelectorAsSelector(wave.ClusterSelector)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cluster selector of wave %v, err: %w", wave.Name, err)
+			}
+		}
+
+		selectors[i] = selector
+	}
+
+	waveClusters := make([][]string, len(upgrade.Spec.Waves))
+
+	for _, cluster := range clusterList.Items {
+		if !agents[cluster.Name] {
+			continue
+		}
+
+		for i, selector := range selectors {
+			if selector.Matches(labels.Set(cluster.GetLabels())) {
+				waveClusters[i] = append(waveClusters[i], cluster.Name)
+
+				break
+			}
+		}
+	}
+
+	for _, clusters := range waveClusters {
+		sort.Strings(clusters)
+	}
+
+	return waveClusters, nil
+}
+
+// getHeartbeatLeases returns the heartbeat lease of the agent of each cluster
+func (r *ReconcileAgentUpgrade) getHeartbeatLeases() (map[string]*coordinationv1.Lease, error) {
+	leaseList := &coordinationv1.LeaseList{}
+	if err := r.List(context.TODO(), leaseList, client.MatchingLabels{utils.LabelAgentHeartbeat: "true"}); err != nil {
+		return nil, fmt.Errorf("failed to list the heartbeat leases, err: %w", err)
+	}
+
+	leases := map[string]*coordinationv1.Lease{}
+
+	for i := range leaseList.Items {
+		if lease := &leaseList.Items[i]; lease.Name == utils.AgentHeartbeatLeaseName {
+			leases[lease.Namespace] = lease
+		}
+	}
+
+	return leases, nil
+}
+
+// setAgentUpgradeImage sets the image of the agent of the cluster on its addon
+func (r *ReconcileAgentUpgrade) setAgentUpgradeImage(cluster, image string) error {
+	addon := &addonV1alpha1.ManagedClusterAddOn{}
+	if err := r.Get(context.TODO(), client.ObjectKey{Namespace: cluster, Name: agentAddonName}, addon); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to get the agent addon of cluster %v, err: %w", cluster, err)
+	}
+
+	if addon.GetAnnotations()[appSubStatusV1alpha1.AnnotationAgentUpgradeImage] == image {
+		return nil
+	}
+
+	patch := client.MergeFrom(addon.DeepCopy())
+
+	annotations := addon.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	annotations[appSubStatusV1alpha1.AnnotationAgentUpgradeImage] = image
+	addon.SetAnnotations(annotations)
+
+	if err := r.Patch(context.TODO(), addon, patch); err != nil {
+		return fmt.Errorf("failed to set the agent image of cluster %v, err: %w", cluster, err)
+	}
+
+	klog.Infof("rolled out the agent image %v to cluster %v", image, cluster)
+
+	return nil
+}
+
+// isAgentUpgraded checks if the agent of the cluster reports the version and still renews its heartbeat lease
+func isAgentUpgraded(lease *coordinationv1.Lease, version string, now time.Time) bool {
+	return lease != nil && lease.GetAnnotations()[utils.AnnotationAgentVersion] == version &&
+		!utils.IsAgentHeartbeatExpired(lease, agentHeartbeatTimeout, now)
+}
+
+// getAgentUpgradeStatus returns the status of the waves of the upgrade. A started wave is upgraded once all its agents
+// report the version, the agents not upgraded within the upgrade timeout stall the upgrade. The next wave starts once
+// the previous wave has been upgraded during its soak duration and the upgrade is not paused. A wave without clusters
+// is upgraded once it starts
+func getAgentUpgradeStatus(upgrade *appSubStatusV1alpha1.AgentUpgrade, waveClusters [][]string,
+	leases map[string]*coordinationv1.Lease, now time.Time) appSubStatusV1alpha1.AgentUpgradeStatus {
+	timeout := upgrade.Spec.UpgradeTimeout.Duration
+	if timeout <= 0 {
+		timeout = defaultAgentUpgradeTimeout
+	}
+
+	// the waves start over on a new version
+	previous := map[string]appSubStatusV1alpha1.AgentUpgradeWaveStatus{}
+
+	if upgrade.Status.Version == upgrade.Spec.Version {
+		for _, waveStatus := range upgrade.Status.Waves {
+			previous[waveStatus.Name] = waveStatus
+		}
+	}
+
+	status := appSubStatusV1alpha1.AgentUpgradeStatus{
+		Version:        upgrade.Spec.Version,
+		LastUpdateTime: upgrade.Status.LastUpdateTime,
+	}
+
+	stalled := false
+
+	for i, wave := range upgrade.Spec.Waves {
+		waveStatus := appSubStatusV1alpha1.AgentUpgradeWaveStatus{
+			Name:         wave.Name,
+			Clusters:     len(waveClusters[i]),
+			StartedTime:  previous[wave.Name].StartedTime,
+			UpgradedTime: previous[wave.Name].UpgradedTime,
+		}
+
+		for _, cluster := range waveClusters[i] {
+			if isAgentUpgraded(leases[cluster], upgrade.Spec.Version, now) {
+				waveStatus.Upgraded++
+			}
+		}
+
+		if waveStatus.StartedTime == nil {
+			waveStatus.Phase = getAgentUpgradeWavePhase(wave, status.Waves, now)
+
+			if waveStatus.Phase == appSubStatusV1alpha1.AgentUpgradeWaveUpgrading && (upgrade.Spec.Paused || stalled) {
+				waveStatus.Phase = appSubStatusV1alpha1.AgentUpgradeWavePending
+			}
+
+			if waveStatus.Phase == appSubStatusV1alpha1.AgentUpgradeWaveUpgrading {
+				klog.Infof("starting wave %v of agent upgrade %v to version %v", wave.Name, upgrade.Name, upgrade.Spec.Version)
+
+				startedTime := metav1.NewTime(now)
+				waveStatus.StartedTime = &startedTime
+			}
+		}
+
+		if waveStatus.StartedTime != nil {
+			if waveStatus.Upgraded < waveStatus.Clusters {
+				waveStatus.Phase = appSubStatusV1alpha1.AgentUpgradeWaveUpgrading
+				waveStatus.UpgradedTime = nil
+
+				if now.Sub(waveStatus.StartedTime.Time) >= timeout {
+					for _, cluster := range waveClusters[i] {
+						if !isAgentUpgraded(leases[cluster], upgrade.Spec.Version, now) {
+							waveStatus.StalledClusters = append(waveStatus.StalledClusters, cluster)
+						}
+					}
+				}
+			} else {
+				waveStatus.Phase = appSubStatusV1alpha1.AgentUpgradeWaveUpgraded
+
+				if waveStatus.UpgradedTime == nil {
+					upgradedTime := metav1.NewTime(now)
+					waveStatus.UpgradedTime = &upgradedTime
+				}
+			}
+		}
+
+		stalled = stalled || len(waveStatus.StalledClusters) > 0
+		status.Waves = append(status.Waves, waveStatus)
+	}
+
+	status.Phase = getAgentUpgradePhase(upgrade, status.Waves, stalled)
+
+	return status
+}
+
+// getAgentUpgradeWavePhase returns the phase of a wave not started, it is Upgrading when the wave can start. The soak
+// duration of the wave is checked by the promotion of the rings, the previous wave is healthy once it is upgraded
+func getAgentUpgradeWavePhase(wave appSubStatusV1alpha1.AgentUpgradeWave,
+	previousWaves []appSubStatusV1alpha1.AgentUpgradeWaveStatus, now time.Time) appSubStatusV1alpha1.AgentUpgradeWavePhase {
+	if len(previousWaves) == 0 {
+		return appSubStatusV1alpha1.AgentUpgradeWaveUpgrading
+	}
+
+	previous := previousWaves[len(previousWaves)-1]
+	if previous.Phase != appSubStatusV1alpha1.AgentUpgradeWaveUpgraded {
+		return appSubStatusV1alpha1.AgentUpgradeWavePending
+	}
+
+	ring := appSubV1.PromotionRing{SoakDuration: wave.SoakDuration}
+
+	if getPromotionPhase(ring, appSubV1.PromotionRingStatus{HealthySince: previous.UpgradedTime}, true,
+		now) != appSubV1.PromotionCurrent {
+		return appSubStatusV1alpha1.AgentUpgradeWaveSoaking
+	}
+
+	return appSubStatusV1alpha1.AgentUpgradeWaveUpgrading
+}
+
+// getAgentUpgradePhase returns the phase of the upgrade from the status of its waves
+func getAgentUpgradePhase(upgrade *appSubStatusV1alpha1.AgentUpgrade, waves []appSubStatusV1alpha1.AgentUpgradeWaveStatus,
+	stalled bool) appSubStatusV1alpha1.AgentUpgradePhase {
+	if stalled {
+		return appSubStatusV1alpha1.AgentUpgradeStalled
+	}
+
+	completed := true
+
+	for _, waveStatus := range waves {
+		if waveStatus.Phase != appSubStatusV1alpha1.AgentUpgradeWaveUpgraded {
+			completed = false
+
+			break
+		}
+	}
+
+	switch {
+	case completed:
+		return appSubStatusV1alpha1.AgentUpgradeCompleted
+	case upgrade.Spec.Paused:
+		return appSubStatusV1alpha1.AgentUpgradePaused
+	}
+
+	return appSubStatusV1alpha1.AgentUpgradeProgressing
+}
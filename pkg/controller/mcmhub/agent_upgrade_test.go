@@ -0,0 +1,178 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	addonV1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	spokeClusterV1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appSubStatusV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+func agentUpgradeObjects(cluster, wave string) []client.Object {
+	return []client.Object{
+		&spokeClusterV1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{
+			Name:   cluster,
+			Labels: map[string]string{"wave": wave},
+		}},
+		&addonV1alpha1.ManagedClusterAddOn{ObjectMeta: metav1.ObjectMeta{Name: agentAddonName, Namespace: cluster}},
+	}
+}
+
+// upgradeAgent renews the heartbeat lease of the agent of the cluster with the version
+func upgradeAgent(g *gomega.WithT, c client.Client, cluster, version string, now time.Time) {
+	lease := heartbeatLease(cluster, now)
+	lease.Annotations = map[string]string{utils.AnnotationAgentVersion: version}
+
+	existing := &coordinationv1.Lease{}
+	if err := c.Get(context.TODO(), client.ObjectKeyFromObject(lease), existing); err != nil {
+		g.Expect(c.Create(context.TODO(), lease)).To(gomega.Succeed())
+
+		return
+	}
+
+	existing.Annotations = lease.Annotations
+	existing.Spec.RenewTime = lease.Spec.RenewTime
+	g.Expect(c.Update(context.TODO(), existing)).To(gomega.Succeed())
+}
+
+func TestAgentUpgrade(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(coordinationv1.AddToScheme(scheme)).To(gomega.Succeed())
+	g.Expect(appSubStatusV1alpha1.SchemeBuilder.AddToScheme(scheme)).To(gomega.Succeed())
+	g.Expect(spokeClusterV1.AddToScheme(scheme)).To(gomega.Succeed())
+	g.Expect(addonV1alpha1.AddToScheme(scheme)).To(gomega.Succeed())
+
+	upgrade := &appSubStatusV1alpha1.AgentUpgrade{
+		ObjectMeta: metav1.ObjectMeta{Name: "upgrade"},
+		Spec: appSubStatusV1alpha1.AgentUpgradeSpec{
+			Image:   "quay.io/test/multicluster_operators_subscription:2.9.0",
+			Version: "2.9.0",
+			Waves: []appSubStatusV1alpha1.AgentUpgradeWave{
+				{
+					Name:            "canary",
+					ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"wave": "canary"}},
+				},
+				{
+					Name:         "fleet",
+					SoakDuration: metav1.Duration{Duration: time.Hour},
+				},
+			},
+		},
+	}
+
+	objs := []client.Object{upgrade}
+	objs = append(objs, agentUpgradeObjects("cluster1", "canary")...)
+	objs = append(objs, agentUpgradeObjects("cluster2", "fleet")...)
+	objs = append(objs, agentUpgradeObjects("cluster3", "fleet")...)
+	// cluster4 has no agent, it isn't upgraded
+	objs = append(objs, &spokeClusterV1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster4"}})
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+
+	now := time.Now()
+	r := &ReconcileAgentUpgrade{Client: c, clk: func() time.Time { return now }}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "upgrade"}}
+
+	reconcileStatus := func() appSubStatusV1alpha1.AgentUpgradeStatus {
+		_, err := r.Reconcile(context.TODO(), req)
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+
+		g.Expect(c.Get(context.TODO(), req.NamespacedName, upgrade)).To(gomega.Succeed())
+
+		return upgrade.Status
+	}
+
+	agentImage := func(cluster string) string {
+		addon := &addonV1alpha1.ManagedClusterAddOn{}
+		g.Expect(c.Get(context.TODO(), types.NamespacedName{Namespace: cluster, Name: agentAddonName}, addon)).To(gomega.Succeed())
+
+		return addon.GetAnnotations()[appSubStatusV1alpha1.AnnotationAgentUpgradeImage]
+	}
+
+	// the canary wave starts
+	status := reconcileStatus()
+	g.Expect(status.Phase).To(gomega.Equal(appSubStatusV1alpha1.AgentUpgradeProgressing))
+	g.Expect(status.Waves[0].Phase).To(gomega.Equal(appSubStatusV1alpha1.AgentUpgradeWaveUpgrading))
+	g.Expect(status.Waves[0].Clusters).To(gomega.Equal(1))
+	g.Expect(status.Waves[1].Phase).To(gomega.Equal(appSubStatusV1alpha1.AgentUpgradeWavePending))
+	g.Expect(status.Waves[1].Clusters).To(gomega.Equal(2))
+	g.Expect(agentImage("cluster1")).To(gomega.Equal(upgrade.Spec.Image))
+	g.Expect(agentImage("cluster2")).To(gomega.BeEmpty())
+
+	// the canary agent reports the version, the fleet wave soaks
+	upgradeAgent(g, c, "cluster1", "2.9.0", now)
+
+	status = reconcileStatus()
+	g.Expect(status.Waves[0].Phase).To(gomega.Equal(appSubStatusV1alpha1.AgentUpgradeWaveUpgraded))
+	g.Expect(status.Waves[1].Phase).To(gomega.Equal(appSubStatusV1alpha1.AgentUpgradeWaveSoaking))
+	g.Expect(agentImage("cluster2")).To(gomega.BeEmpty())
+
+	// the paused upgrade holds the fleet wave after the soak duration
+	upgrade.Spec.Paused = true
+	g.Expect(c.Update(context.TODO(), upgrade)).To(gomega.Succeed())
+
+	now = now.Add(2 * time.Hour)
+	upgradeAgent(g, c, "cluster1", "2.9.0", now)
+
+	status = reconcileStatus()
+	g.Expect(status.Phase).To(gomega.Equal(appSubStatusV1alpha1.AgentUpgradePaused))
+	g.Expect(status.Waves[1].Phase).To(gomega.Equal(appSubStatusV1alpha1.AgentUpgradeWavePending))
+
+	// the resumed upgrade starts the fleet wave
+	upgrade.Spec.Paused = false
+	g.Expect(c.Update(context.TODO(), upgrade)).To(gomega.Succeed())
+
+	status = reconcileStatus()
+	g.Expect(status.Waves[1].Phase).To(gomega.Equal(appSubStatusV1alpha1.AgentUpgradeWaveUpgrading))
+	g.Expect(agentImage("cluster2")).To(gomega.Equal(upgrade.Spec.Image))
+	g.Expect(agentImage("cluster3")).To(gomega.Equal(upgrade.Spec.Image))
+
+	// cluster3 doesn't report the version within the upgrade timeout
+	upgradeAgent(g, c, "cluster2", "2.9.0", now)
+
+	now = now.Add(20 * time.Minute)
+	upgradeAgent(g, c, "cluster1", "2.9.0", now)
+	upgradeAgent(g, c, "cluster2", "2.9.0", now)
+	upgradeAgent(g, c, "cluster3", "2.8.0", now)
+
+	status = reconcileStatus()
+	g.Expect(status.Phase).To(gomega.Equal(appSubStatusV1alpha1.AgentUpgradeStalled))
+	g.Expect(status.Waves[1].Upgraded).To(gomega.Equal(1))
+	g.Expect(status.Waves[1].StalledClusters).To(gomega.Equal([]string{"cluster3"}))
+
+	// the upgrade completes once cluster3 reports the version
+	upgradeAgent(g, c, "cluster3", "2.9.0", now)
+
+	status = reconcileStatus()
+	g.Expect(status.Phase).To(gomega.Equal(appSubStatusV1alpha1.AgentUpgradeCompleted))
+	g.Expect(status.Waves[1].Phase).To(gomega.Equal(appSubStatusV1alpha1.AgentUpgradeWaveUpgraded))
+	g.Expect(status.Waves[1].StalledClusters).To(gomega.BeEmpty())
+}
@@ -0,0 +1,208 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	manifestWorkV1 "open-cluster-management.io/api/work/v1"
+
+	appSubV1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/metrics"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+// hubVersion returns the version of the hub controllers the agent versions are checked against
+var hubVersion = utils.Version
+
+// agentVersionGating holds the propagation of the subscriptions to the agents too old for their spec features
+var agentVersionGating bool
+
+// SetAgentVersionGating enables the hold of the subscriptions using the spec features the agents of their clusters
+// would ignore, it is called once before the controllers are set up
+func SetAgentVersionGating(enabled bool) {
+	agentVersionGating = enabled
+}
+
+// agentSpecFeature is a spec field of the subscriptions applied by the agents, the agents older than its version
+// silently ignore it
+type agentSpecFeature struct {
+	name       string
+	minVersion string
+	used       func(sub *appSubV1.Subscription) bool
+}
+
+// agentSpecFeatures are the spec features applied by the agents, a new spec field applied by the agent adds its
+// feature here with the agent version that applies it
+var agentSpecFeatures = []agentSpecFeature{
+	{
+		name:       "priority",
+		minVersion: "2.8.0",
+		used:       func(sub *appSubV1.Subscription) bool { return sub.Spec.Priority != 0 },
+	},
+	{
+		name:       "dependsOn",
+		minVersion: "2.8.0",
+		used:       func(sub *appSubV1.Subscription) bool { return len(sub.Spec.DependsOn) > 0 },
+	},
+	{
+		name:       "namespaceCreation",
+		minVersion: "2.8.0",
+		used:       func(sub *appSubV1.Subscription) bool { return sub.Spec.NamespaceCreation != nil },
+	},
+	{
+		name:       "metadataPropagation",
+		minVersion: "2.8.0",
+		used:       func(sub *appSubV1.Subscription) bool { return sub.Spec.MetadataPropagation != nil },
+	},
+	{
+		name:       "progressDeadline",
+		minVersion: "2.8.0",
+		used:       func(sub *appSubV1.Subscription) bool { return sub.Spec.ProgressDeadline != nil },
+	},
+	{
+		name:       "retry",
+		minVersion: "2.8.0",
+		used:       func(sub *appSubV1.Subscription) bool { return sub.Spec.Retry != nil },
+	},
+}
+
+// unsupportedSpecFeatures returns the spec features of the subscription the agent version would ignore
+func unsupportedSpecFeatures(sub *appSubV1.Subscription, agentVersion string) []string {
+	features := []string{}
+
+	for _, feature := range agentSpecFeatures {
+		if feature.used(sub) && utils.IsAgentVersionOlder(agentVersion, feature.minVersion) {
+			features = append(features, feature.name)
+		}
+	}
+
+	return features
+}
+
+// holdUnsupportedAgentManifestWorks sets the UnsupportedAgentVersion condition of the subscription from the agent
+// versions of the heartbeat leases of its clusters. While the agent version gating is enabled, the ManifestWorks of
+// the clusters whose agent would ignore some spec features of the subscription are neither updated nor deleted. It
+// returns the clusters to propagate the subscription to
+func (r *ReconcileSubscription) holdUnsupportedAgentManifestWorks(instance *appSubV1.Subscription, clusters []ManageClusters,
+	familymap map[string]*manifestWorkV1.ManifestWork) []ManageClusters {
+	version := hubVersion()
+	skewed := []string{}
+	ignored := map[string][]string{}
+	held := map[string]bool{}
+
+	for _, cluster := range clusters {
+		lease := r.getHeartbeatLease(cluster.Cluster)
+		if lease == nil {
+			continue
+		}
+
+		agentVersion := lease.GetAnnotations()[utils.AnnotationAgentVersion]
+
+		if err := utils.CheckAgentVersionSkew(version, agentVersion); err != nil {
+			skewed = append(skewed, fmt.Sprintf("%v (%v)", cluster.Cluster, agentVersion))
+		}
+
+		for _, feature := range unsupportedSpecFeatures(instance, agentVersion) {
+			ignored[feature] = append(ignored[feature], fmt.Sprintf("%v (%v)", cluster.Cluster, agentVersion))
+			held[cluster.Cluster] = true
+		}
+	}
+
+	setUnsupportedAgentVersionCondition(instance, version, skewed, ignored)
+
+	if !agentVersionGating || len(held) == 0 {
+		return clusters
+	}
+
+	for key, manifestWork := range familymap {
+		if held[manifestWork.GetNamespace()] {
+			klog.Infof("hold manifestWork %v/%v, the agent ignores some spec features of the subscription",
+				manifestWork.GetNamespace(), manifestWork.GetName())
+
+			delete(familymap, key)
+		}
+	}
+
+	propagated := []ManageClusters{}
+
+	for _, cluster := range clusters {
+		if !held[cluster.Cluster] {
+			propagated = append(propagated, cluster)
+		}
+	}
+
+	return propagated
+}
+
+func setUnsupportedAgentVersionCondition(sub *appSubV1.Subscription, version string, skewed []string,
+	ignored map[string][]string) {
+	if len(skewed) == 0 && len(ignored) == 0 {
+		meta.RemoveStatusCondition(&sub.Status.Conditions, appSubV1.ConditionUnsupportedAgentVersion)
+
+		return
+	}
+
+	msgs := []string{}
+
+	if len(skewed) > 0 {
+		msgs = append(msgs, fmt.Sprintf("the agents of clusters %v are not supported by hub %v",
+			joinConditionClusters(skewed), version))
+	}
+
+	features := []string{}
+	for feature := range ignored {
+		features = append(features, feature)
+	}
+
+	sort.Strings(features)
+
+	for _, feature := range features {
+		msg := fmt.Sprintf("the agents of clusters %v ignore the %v of the subscription", joinConditionClusters(ignored[feature]),
+			feature)
+		if agentVersionGating {
+			msg += ", it is not propagated to them"
+		}
+
+		msgs = append(msgs, msg)
+	}
+
+	meta.SetStatusCondition(&sub.Status.Conditions, metav1.Condition{
+		Type:               appSubV1.ConditionUnsupportedAgentVersion,
+		Status:             metav1.ConditionTrue,
+		Reason:             appSubV1.ReasonAgentVersionSkew,
+		Message:            strings.Join(msgs, "; "),
+		ObservedGeneration: sub.GetGeneration(),
+	})
+}
+
+// setAgentVersionMetrics sets the agent_version_unsupported metric of the clusters whose agent version is not
+// supported by the hub
+func setAgentVersionMetrics(states map[string]agentState) {
+	metrics.AgentVersionUnsupported.Reset()
+
+	version := hubVersion()
+
+	for cluster, state := range states {
+		if err := utils.CheckAgentVersionSkew(version, state.version); err != nil {
+			metrics.AgentVersionUnsupported.WithLabelValues(cluster, state.version, version).Set(1)
+		}
+	}
+}
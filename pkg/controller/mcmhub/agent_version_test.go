@@ -0,0 +1,96 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	manifestWorkV1 "open-cluster-management.io/api/work/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+func TestUnsupportedAgentVersion(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	defer func() {
+		hubVersion = utils.Version
+		agentVersionGating = false
+	}()
+
+	hubVersion = func() string { return "2.8.0" }
+
+	scheme := runtime.NewScheme()
+	g.Expect(coordinationv1.AddToScheme(scheme)).To(gomega.Succeed())
+
+	agentLease := func(cluster, version string) *coordinationv1.Lease {
+		lease := heartbeatLease(cluster, time.Now())
+		lease.Annotations = map[string]string{utils.AnnotationAgentVersion: version}
+
+		return lease
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		agentLease("current", "2.8.0"),
+		agentLease("old", "2.7.0"),
+		agentLease("unsupported", "2.4.0"),
+	).Build()
+
+	r := &ReconcileSubscription{Client: c}
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns"},
+		Spec:       appv1.SubscriptionSpec{DependsOn: []appv1.SubscriptionDependency{{Name: "db"}}},
+	}
+
+	clusters := []ManageClusters{{Cluster: "current"}, {Cluster: "old"}, {Cluster: "unsupported"}, {Cluster: "no-heartbeat"}}
+
+	manifestWork := func(cluster string) *manifestWorkV1.ManifestWork {
+		return &manifestWorkV1.ManifestWork{ObjectMeta: metav1.ObjectMeta{Name: "demo-ns-demo", Namespace: cluster}}
+	}
+
+	familymap := map[string]*manifestWorkV1.ManifestWork{"old-demo-ns-demo": manifestWork("old")}
+
+	// without the gating the subscription is propagated to all the clusters
+	g.Expect(r.holdUnsupportedAgentManifestWorks(sub, clusters, familymap)).To(gomega.HaveLen(4))
+	g.Expect(familymap).To(gomega.HaveLen(1))
+
+	cond := meta.FindStatusCondition(sub.Status.Conditions, appv1.ConditionUnsupportedAgentVersion)
+	g.Expect(cond).NotTo(gomega.BeNil())
+	g.Expect(cond.Reason).To(gomega.Equal(appv1.ReasonAgentVersionSkew))
+	g.Expect(cond.Message).To(gomega.Equal("the agents of clusters unsupported (2.4.0) are not supported by hub 2.8.0; " +
+		"the agents of clusters old (2.7.0), unsupported (2.4.0) ignore the dependsOn of the subscription"))
+
+	// the gating holds the ManifestWorks of the old agents
+	agentVersionGating = true
+
+	propagated := r.holdUnsupportedAgentManifestWorks(sub, clusters, familymap)
+	g.Expect(propagated).To(gomega.Equal([]ManageClusters{{Cluster: "current"}, {Cluster: "no-heartbeat"}}))
+	g.Expect(familymap).To(gomega.BeEmpty())
+
+	// the condition is removed when the subscription only uses the features of all the agents
+	sub.Spec.DependsOn = nil
+
+	g.Expect(r.holdUnsupportedAgentManifestWorks(sub, []ManageClusters{{Cluster: "current"}, {Cluster: "old"}}, familymap)).To(gomega.HaveLen(2))
+	g.Expect(meta.FindStatusCondition(sub.Status.Conditions, appv1.ConditionUnsupportedAgentVersion)).To(gomega.BeNil())
+}
@@ -23,6 +23,7 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	kerr "k8s.io/apimachinery/pkg/api/errors"
@@ -54,15 +55,28 @@ type appliedJobs struct {
 func (jIns *JobInstances) registryJobs(gClt GitOps, subIns *subv1.Subscription,
 	suffixFunc SuffixFunc, jobs []ansiblejob.AnsibleJob, kubeclient client.Client,
 	logger logr.Logger, placementDecisionUpdated bool, placementRuleRv string, hookType string,
-	commitIDChanged bool) error {
+	commitIDChanged bool, hookCtx *HookContext) error {
 	logger.Info(fmt.Sprintf("In registryJobs, placementDecisionUpdated = %v, commitIDChanged = %v", placementDecisionUpdated, commitIDChanged))
 
 	for _, job := range jobs {
+		if _, _, err := parseHookSchedule(job); err != nil {
+			return err
+		}
+	}
+
+	scopedJobs, err := scopeHookJobs(subIns, jobs, kubeclient, logger)
+	if err != nil {
+		return err
+	}
+
+	for _, scopedJob := range scopedJobs {
+		job := scopedJob.job
+
 		logger.Info("registering " + job.GetNamespace() + "/" + job.GetName())
 
 		jobKey := types.NamespacedName{Name: job.GetName(), Namespace: job.GetNamespace()}
 
-		ins, err := overrideAnsibleInstance(subIns, job, kubeclient, logger, hookType)
+		ins, err := overrideAnsibleInstance(subIns, scopedJob, kubeclient, logger, hookType, hookCtx)
 
 		if err != nil {
 			return err
@@ -259,6 +273,18 @@ func (jIns *JobInstances) applyJobs(clt client.Client, subIns *subv1.Subscriptio
 				return fmt.Errorf("failed to get job %v, err: %v", jKey, err)
 			}
 
+			// the job waits for its schedule in the timewindow of the subscription
+			delay, err := hookScheduleDelay(nx, subIns.Spec.TimeWindow, time.Now())
+			if err != nil {
+				return err
+			}
+
+			if delay > 0 {
+				logger.Info(fmt.Sprintf("ansiblejob %s/%s is scheduled to run in %v", nx.GetNamespace(), nx.GetName(), delay))
+
+				continue
+			}
+
 			if err := clt.Create(context.TODO(), &nx); err != nil {
 				if !kerr.IsAlreadyExists(err) {
 					return fmt.Errorf("failed to apply job %v, err: %v", k.String(), err)
@@ -0,0 +1,193 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+var applicationGVK = schema.GroupVersionKind{
+	Group:   "app.k8s.io",
+	Version: "v1beta1",
+	Kind:    "Application",
+}
+
+// isAutoGenerateApplication checks if the subscription asks the hub to maintain an Application for it
+func isAutoGenerateApplication(sub *appv1.Subscription) bool {
+	return strings.EqualFold(sub.GetAnnotations()[appv1.AnnotationAutoGenerateApplication], "true")
+}
+
+// getApplicationComponentKinds returns the sorted, unique group/kind list of the subscription and its resources
+func getApplicationComponentKinds(resources []*v1.ObjectReference) []interface{} {
+	kinds := map[string]schema.GroupKind{
+		appv1.SchemeGroupVersion.Group + "/Subscription": {Group: appv1.SchemeGroupVersion.Group, Kind: "Subscription"},
+	}
+
+	for _, res := range resources {
+		if res == nil || res.Kind == "" {
+			continue
+		}
+
+		gk := schema.FromAPIVersionAndKind(res.APIVersion, res.Kind).GroupKind()
+		kinds[gk.Group+"/"+gk.Kind] = gk
+	}
+
+	keys := make([]string, 0, len(kinds))
+	for k := range kinds {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	componentKinds := make([]interface{}, 0, len(keys))
+
+	for _, k := range keys {
+		componentKinds = append(componentKinds, map[string]interface{}{
+			"group": kinds[k].Group,
+			"kind":  kinds[k].Kind,
+		})
+	}
+
+	return componentKinds
+}
+
+// getApplicationTopo builds the topology annotation value listing all the resources deployed by the subscription
+func getApplicationTopo(sub *appv1.Subscription, resources []*v1.ObjectReference) string {
+	parent := fmt.Sprintf("%v/%v", sub.GetNamespace(), sub.GetName())
+	topo := make([]string, 0, len(resources))
+
+	for _, res := range resources {
+		if res == nil || res.Kind == "" {
+			continue
+		}
+
+		gk := schema.FromAPIVersionAndKind(res.APIVersion, res.Kind).GroupKind()
+		topo = append(topo, fmt.Sprintf("%v/%v/%v/%v/%v/%v", parent, gk.Group, gk.Kind, res.Namespace, res.Name, 0))
+	}
+
+	sort.Strings(topo)
+
+	return strings.Join(topo, ",")
+}
+
+// getDeployedResourceLabels returns the labels the subscription agent sets on all the resources deployed by the
+// subscription: the application, the app label of the subscription or its name, and the subscription
+func getDeployedResourceLabels(sub *appv1.Subscription) map[string]interface{} {
+	app := sub.GetLabels()["app"]
+	if app == "" {
+		app = sub.GetName()
+	}
+
+	return map[string]interface{}{
+		appv1.LabelApplication:      utils.SafeLabelValue(app),
+		appv1.LabelSubscriptionName: utils.SafeLabelValue(sub.GetNamespace() + "." + sub.GetName()),
+	}
+}
+
+func newGeneratedApplication(sub *appv1.Subscription, resources []*v1.ObjectReference) *unstructured.Unstructured {
+	app := &unstructured.Unstructured{}
+	app.SetGroupVersionKind(applicationGVK)
+	app.SetName(sub.GetName())
+	app.SetNamespace(sub.GetNamespace())
+	app.SetLabels(map[string]string{
+		"app": sub.GetLabels()["app"],
+	})
+	app.SetAnnotations(map[string]string{
+		appv1.AnnotationApplicationGenerated: "true",
+		appv1.AnnotationSubscriptions:        fmt.Sprintf("%v/%v", sub.GetNamespace(), sub.GetName()),
+		appv1.AnnotationTopo:                 getApplicationTopo(sub, resources),
+	})
+	app.SetOwnerReferences([]metav1.OwnerReference{
+		*metav1.NewControllerRef(sub, appv1.SchemeGroupVersion.WithKind("Subscription")),
+	})
+
+	spec := map[string]interface{}{
+		"componentKinds": getApplicationComponentKinds(resources),
+		"selector": map[string]interface{}{
+			"matchLabels": getDeployedResourceLabels(sub),
+		},
+	}
+
+	_ = unstructured.SetNestedMap(app.Object, spec, "spec")
+
+	return app
+}
+
+// syncApplication creates or updates the Application generated for the subscription.
+// Applications created by users are left untouched.
+func (r *ReconcileSubscription) syncApplication(sub *appv1.Subscription, resources []*v1.ObjectReference) error {
+	if !isAutoGenerateApplication(sub) {
+		return nil
+	}
+
+	desired := newGeneratedApplication(sub, resources)
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(applicationGVK)
+
+	err := r.Get(context.TODO(), types.NamespacedName{Name: sub.GetName(), Namespace: sub.GetNamespace()}, existing)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			klog.Infof("Application CRD is not installed on the hub, skip generating application for %v", ObjectString(sub))
+
+			return nil
+		}
+
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		klog.Infof("creating application %v", ObjectString(desired))
+
+		return r.Create(context.TODO(), desired)
+	}
+
+	if !strings.EqualFold(existing.GetAnnotations()[appv1.AnnotationApplicationGenerated], "true") {
+		klog.V(1).Infof("application %v is not generated by subscription, skip", ObjectString(existing))
+
+		return nil
+	}
+
+	if reflect.DeepEqual(existing.Object["spec"], desired.Object["spec"]) &&
+		reflect.DeepEqual(existing.GetAnnotations(), desired.GetAnnotations()) &&
+		reflect.DeepEqual(existing.GetLabels(), desired.GetLabels()) {
+		return nil
+	}
+
+	existing.Object["spec"] = desired.Object["spec"]
+	existing.SetAnnotations(desired.GetAnnotations())
+	existing.SetLabels(desired.GetLabels())
+	existing.SetOwnerReferences(desired.GetOwnerReferences())
+
+	klog.Infof("updating application %v", ObjectString(existing))
+
+	return r.Update(context.TODO(), existing)
+}
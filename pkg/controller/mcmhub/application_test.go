@@ -0,0 +1,76 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func TestNewGeneratedApplication(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "demo",
+			Namespace: "demo-ns",
+			UID:       "dummyid",
+			Labels:    map[string]string{"app": "demo-app"},
+			Annotations: map[string]string{
+				appv1.AnnotationAutoGenerateApplication: "true",
+			},
+		},
+	}
+
+	resources := []*v1.ObjectReference{
+		{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "demo-ns", Name: "web"},
+		{APIVersion: "v1", Kind: "Service", Namespace: "demo-ns", Name: "web"},
+		{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "demo-ns", Name: "db"},
+	}
+
+	g.Expect(isAutoGenerateApplication(sub)).To(gomega.BeTrue())
+
+	app := newGeneratedApplication(sub, resources)
+	g.Expect(app.GetName()).To(gomega.Equal("demo"))
+	g.Expect(app.GetNamespace()).To(gomega.Equal("demo-ns"))
+	g.Expect(app.GetOwnerReferences()).To(gomega.HaveLen(1))
+	g.Expect(app.GetAnnotations()[appv1.AnnotationSubscriptions]).To(gomega.Equal("demo-ns/demo"))
+	g.Expect(app.GetAnnotations()[appv1.AnnotationTopo]).To(gomega.Equal(
+		"demo-ns/demo//Service/demo-ns/web/0,demo-ns/demo/apps/Deployment/demo-ns/db/0,demo-ns/demo/apps/Deployment/demo-ns/web/0"))
+
+	kinds, found, err := unstructured.NestedSlice(app.Object, "spec", "componentKinds")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(kinds).To(gomega.Equal([]interface{}{
+		map[string]interface{}{"group": "", "kind": "Service"},
+		map[string]interface{}{"group": "apps.open-cluster-management.io", "kind": "Subscription"},
+		map[string]interface{}{"group": "apps", "kind": "Deployment"},
+	}))
+
+	// the selector matches the labels the agent sets on the deployed resources
+	selector, found, err := unstructured.NestedStringMap(app.Object, "spec", "selector", "matchLabels")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(selector).To(gomega.Equal(map[string]string{
+		appv1.LabelApplication:      "demo-app",
+		appv1.LabelSubscriptionName: "demo-ns.demo",
+	}))
+}
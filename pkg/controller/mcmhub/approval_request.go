@@ -0,0 +1,219 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appSubV1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appSubStatusV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+)
+
+// getApprovalRequestName returns the name of the ApprovalRequest of a commit waiting to be promoted to a ring
+func getApprovalRequestName(sub *appSubV1.Subscription, decisionGroup, commit string) string {
+	if len(commit) > 12 {
+		commit = commit[:12]
+	}
+
+	name := strings.ToLower(strings.ReplaceAll(fmt.Sprintf("%s-%s-%s", sub.Name, decisionGroup, commit), "_", "-"))
+	if len(name) > 253 {
+		name = name[len(name)-253:]
+	}
+
+	return strings.Trim(name, "-.")
+}
+
+// syncApprovalRequest creates the ApprovalRequest of the commit waiting for the manual approval of the ring, and takes
+// its decision once the approver sets it. It returns the name of the request and if the commit is approved
+func (r *ReconcileSubscription) syncApprovalRequest(sub *appSubV1.Subscription, ring appSubV1.PromotionRing, commit string,
+	clusters []string, now time.Time) (string, bool, error) {
+	name := getApprovalRequestName(sub, ring.DecisionGroup, commit)
+
+	request := &appSubStatusV1alpha1.ApprovalRequest{}
+
+	err := r.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: sub.Namespace}, request)
+	if meta.IsNoMatchError(err) {
+		klog.Infof("the ApprovalRequest API is not installed, the commit %v of subscription %v/%v waits for the %v annotation",
+			commit, sub.Namespace, sub.Name, appSubV1.AnnotationPromotionApproved)
+
+		return "", false, nil
+	}
+
+	if errors.IsNotFound(err) {
+		return name, false, r.createApprovalRequest(sub, ring, name, commit, clusters, now)
+	}
+
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get approval request %v/%v, err: %w", sub.Namespace, name, err)
+	}
+
+	phase := request.Status.Phase
+
+	if phase == "" || phase == appSubStatusV1alpha1.ApprovalPending {
+		switch {
+		case request.Spec.ExpirationTime != nil && !now.Before(request.Spec.ExpirationTime.Time):
+			// a decision taken after the expiration is not taken, the hub is notified of the decisions on the spec update
+			phase = appSubStatusV1alpha1.ApprovalExpired
+		case request.Spec.Decision == appSubStatusV1alpha1.ApprovalApproved:
+			phase = appSubStatusV1alpha1.ApprovalPhaseApproved
+		case request.Spec.Decision == appSubStatusV1alpha1.ApprovalRejected:
+			phase = appSubStatusV1alpha1.ApprovalPhaseRejected
+		default:
+			phase = appSubStatusV1alpha1.ApprovalPending
+		}
+	}
+
+	if phase != request.Status.Phase {
+		request.Status.Phase = phase
+
+		if phase != appSubStatusV1alpha1.ApprovalPending {
+			decisionTime := metav1.NewTime(now)
+			request.Status.DecisionTime = &decisionTime
+
+			if phase != appSubStatusV1alpha1.ApprovalExpired {
+				request.Status.DecidedBy = request.Spec.Approver
+			}
+
+			msg := fmt.Sprintf("approval request %v of commit %v for decision group %v is %v", name, commit,
+				ring.DecisionGroup, strings.ToLower(string(phase)))
+			if request.Status.DecidedBy != "" {
+				msg += " by " + request.Status.DecidedBy
+			}
+
+			klog.Infof("subscription %v/%v: %v", sub.Namespace, sub.Name, msg)
+
+			if r.eventRecorder != nil {
+				r.eventRecorder.RecordEvent(sub, "ApprovalRequest", msg, nil)
+				r.eventRecorder.RecordEvent(request, "ApprovalRequest", msg, nil)
+			}
+		}
+
+		if err := r.Status().Update(context.TODO(), request); err != nil {
+			return "", false, fmt.Errorf("failed to update the status of approval request %v/%v, err: %w", sub.Namespace, name, err)
+		}
+	}
+
+	return name, phase == appSubStatusV1alpha1.ApprovalPhaseApproved, nil
+}
+
+func (r *ReconcileSubscription) createApprovalRequest(sub *appSubV1.Subscription, ring appSubV1.PromotionRing,
+	name, commit string, clusters []string, now time.Time) error {
+	request := &appSubStatusV1alpha1.ApprovalRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: sub.Namespace,
+			Labels:    map[string]string{appSubV1.LabelSubscriptionName: sub.Name},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(sub, appSubV1.SchemeGroupVersion.WithKind("Subscription")),
+			},
+		},
+		Spec: appSubStatusV1alpha1.ApprovalRequestSpec{
+			Subscription:  sub.Name,
+			DecisionGroup: ring.DecisionGroup,
+			Commit:        commit,
+			Clusters:      clusters,
+		},
+	}
+
+	if ring.ApprovalTimeout.Duration > 0 {
+		expirationTime := metav1.NewTime(now.Add(ring.ApprovalTimeout.Duration))
+		request.Spec.ExpirationTime = &expirationTime
+	}
+
+	if err := r.Create(context.TODO(), request); err != nil {
+		return fmt.Errorf("failed to create approval request %v/%v, err: %w", sub.Namespace, name, err)
+	}
+
+	request.Status.Phase = appSubStatusV1alpha1.ApprovalPending
+
+	if err := r.Status().Update(context.TODO(), request); err != nil {
+		return fmt.Errorf("failed to update the status of approval request %v/%v, err: %w", sub.Namespace, name, err)
+	}
+
+	msg := fmt.Sprintf("approval request %v is created for the promotion of commit %v to decision group %v", name, commit,
+		ring.DecisionGroup)
+
+	klog.Infof("subscription %v/%v: %v", sub.Namespace, sub.Name, msg)
+
+	if r.eventRecorder != nil {
+		r.eventRecorder.RecordEvent(sub, "ApprovalRequest", msg, nil)
+	}
+
+	return nil
+}
+
+// deleteStaleApprovalRequests deletes the pending ApprovalRequests of the subscription no promotion waits for anymore,
+// the decided and expired ones are kept until the subscription is deleted
+func (r *ReconcileSubscription) deleteStaleApprovalRequests(sub *appSubV1.Subscription, requests map[string]bool) error {
+	requestList := &appSubStatusV1alpha1.ApprovalRequestList{}
+
+	err := r.List(context.TODO(), requestList, client.InNamespace(sub.Namespace),
+		client.MatchingLabels{appSubV1.LabelSubscriptionName: sub.Name})
+	if meta.IsNoMatchError(err) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to list the approval requests of subscription %v/%v, err: %w", sub.Namespace, sub.Name, err)
+	}
+
+	for i := range requestList.Items {
+		request := &requestList.Items[i]
+
+		if requests[request.Name] || request.Spec.Subscription != sub.Name {
+			continue
+		}
+
+		if phase := request.Status.Phase; phase != "" && phase != appSubStatusV1alpha1.ApprovalPending {
+			continue
+		}
+
+		if err := r.Delete(context.TODO(), request); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete approval request %v/%v, err: %w", request.Namespace, request.Name, err)
+		}
+
+		klog.Infof("stale approval request %v/%v deleted", request.Namespace, request.Name)
+	}
+
+	return nil
+}
+
+type approvalRequestMapper struct {
+	client.Client
+}
+
+// Map enqueues the subscription of the approval request, the promotion resumes once the request is approved
+func (mapper *approvalRequestMapper) Map(obj client.Object) []reconcile.Request {
+	request, ok := obj.(*appSubStatusV1alpha1.ApprovalRequest)
+	if !ok || request.Spec.Subscription == "" {
+		return nil
+	}
+
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{
+		Name:      request.Spec.Subscription,
+		Namespace: request.GetNamespace(),
+	}}}
+}
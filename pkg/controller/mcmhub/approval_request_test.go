@@ -0,0 +1,173 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clusterapi "open-cluster-management.io/api/cluster/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	plrv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appsubreportv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+)
+
+func TestPromotionApprovalRequest(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(clusterapi.AddToScheme(scheme)).To(gomega.Succeed())
+	g.Expect(appsubreportv1alpha1.AddToScheme(scheme)).To(gomega.Succeed())
+
+	decision := func(name, group, cluster string) *clusterapi.PlacementDecision {
+		return &clusterapi.PlacementDecision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "demo-ns",
+				Labels:    map[string]string{placementLabel: "demo-placement", decisionGroupNameLabel: group},
+			},
+			Status: clusterapi.PlacementDecisionStatus{Decisions: []clusterapi.ClusterDecision{{ClusterName: cluster}}},
+		}
+	}
+
+	report := &appsubreportv1alpha1.SubscriptionReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns"},
+		ReportType: "Application",
+		Results:    []*appsubreportv1alpha1.SubscriptionReportResult{{Source: "canary1", Result: "deployed"}},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		decision("demo-placement-decision-1", "canary", "canary1"),
+		decision("demo-placement-decision-2", "prod", "prod1"),
+		report,
+	).Build()
+	r := &ReconcileSubscription{Client: c}
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "demo",
+			Namespace:   "demo-ns",
+			UID:         "demo-uid",
+			Annotations: map[string]string{appv1.AnnotationGitCommit: "c2"},
+		},
+		Spec: appv1.SubscriptionSpec{
+			Placement: &plrv1.Placement{
+				PlacementRef: &corev1.ObjectReference{Kind: "Placement", Name: "demo-placement"},
+			},
+			Promotion: &appv1.Promotion{
+				Rings: []appv1.PromotionRing{
+					{DecisionGroup: "canary"},
+					{DecisionGroup: "prod", ManualApproval: true, ApprovalTimeout: metav1.Duration{Duration: time.Hour}},
+				},
+			},
+		},
+		Status: appv1.SubscriptionStatus{
+			Promotion: []appv1.PromotionRingStatus{
+				{DecisionGroup: "canary", Commit: "c2", HealthySince: &metav1.Time{Time: time.Now().Add(-time.Hour)}},
+				{DecisionGroup: "prod", Commit: "c1"},
+			},
+		},
+	}
+
+	clusters := []ManageClusters{{Cluster: "canary1", DecisionGroup: "canary"}, {Cluster: "prod1", DecisionGroup: "prod"}}
+	now := time.Now()
+
+	getRequest := func(name string) (*appsubreportv1alpha1.ApprovalRequest, error) {
+		request := &appsubreportv1alpha1.ApprovalRequest{}
+		err := c.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: "demo-ns"}, request)
+
+		return request, err
+	}
+
+	// the hub asks for the approval of the commit waiting for the manual approval
+	g.Expect(r.syncPromotion(sub, chnv1.ChannelTypeGit, clusters, now)).To(gomega.Succeed())
+	g.Expect(sub.Status.Promotion[1].Phase).To(gomega.Equal(appv1.PromotionWaitingForApproval))
+	g.Expect(sub.Status.Promotion[1].ApprovalRequest).To(gomega.Equal("demo-prod-c2"))
+
+	request, err := getRequest("demo-prod-c2")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(request.Spec.Subscription).To(gomega.Equal("demo"))
+	g.Expect(request.Spec.Commit).To(gomega.Equal("c2"))
+	g.Expect(request.Spec.Clusters).To(gomega.Equal([]string{"prod1"}))
+	g.Expect(request.Spec.ExpirationTime.Time).To(gomega.BeTemporally("~", now.Add(time.Hour), time.Second))
+	g.Expect(request.Status.Phase).To(gomega.Equal(appsubreportv1alpha1.ApprovalPending))
+	g.Expect(request.OwnerReferences).To(gomega.HaveLen(1))
+	g.Expect(request.OwnerReferences[0].UID).To(gomega.Equal(types.UID("demo-uid")))
+
+	// the approval of the request promotes the commit
+	request.Spec.Decision = appsubreportv1alpha1.ApprovalApproved
+	request.Spec.Approver = "release-manager"
+	g.Expect(c.Update(context.TODO(), request)).To(gomega.Succeed())
+
+	g.Expect(r.syncPromotion(sub, chnv1.ChannelTypeGit, clusters, now.Add(time.Minute))).To(gomega.Succeed())
+	g.Expect(sub.Status.Promotion[1].Phase).To(gomega.Equal(appv1.PromotionCurrent))
+	g.Expect(sub.Status.Promotion[1].Commit).To(gomega.Equal("c2"))
+	g.Expect(sub.Status.Promotion[1].ApprovalRequest).To(gomega.BeEmpty())
+
+	request, err = getRequest("demo-prod-c2")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(request.Status.Phase).To(gomega.Equal(appsubreportv1alpha1.ApprovalPhaseApproved))
+	g.Expect(request.Status.DecidedBy).To(gomega.Equal("release-manager"))
+
+	// the request of a commit is not approved after its expiration
+	sub.Annotations[appv1.AnnotationGitCommit] = "c3"
+	sub.Status.Promotion[0] = appv1.PromotionRingStatus{
+		DecisionGroup: "canary", Commit: "c3", HealthySince: &metav1.Time{Time: now.Add(-time.Hour)},
+	}
+
+	g.Expect(r.syncPromotion(sub, chnv1.ChannelTypeGit, clusters, now)).To(gomega.Succeed())
+	g.Expect(sub.Status.Promotion[1].ApprovalRequest).To(gomega.Equal("demo-prod-c3"))
+
+	request, err = getRequest("demo-prod-c3")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	request.Spec.Decision = appsubreportv1alpha1.ApprovalApproved
+	g.Expect(c.Update(context.TODO(), request)).To(gomega.Succeed())
+
+	g.Expect(r.syncPromotion(sub, chnv1.ChannelTypeGit, clusters, now.Add(2*time.Hour))).To(gomega.Succeed())
+	g.Expect(sub.Status.Promotion[1].Phase).To(gomega.Equal(appv1.PromotionWaitingForApproval))
+	g.Expect(sub.Status.Promotion[1].Commit).To(gomega.Equal("c2"))
+
+	request, err = getRequest("demo-prod-c3")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(request.Status.Phase).To(gomega.Equal(appsubreportv1alpha1.ApprovalExpired))
+
+	// the pending request of a commit nothing waits for anymore is deleted
+	sub.Annotations[appv1.AnnotationGitCommit] = "c4"
+	sub.Status.Promotion[0] = appv1.PromotionRingStatus{
+		DecisionGroup: "canary", Commit: "c4", HealthySince: &metav1.Time{Time: now.Add(-time.Hour)},
+	}
+
+	g.Expect(r.syncPromotion(sub, chnv1.ChannelTypeGit, clusters, now)).To(gomega.Succeed())
+
+	sub.Annotations[appv1.AnnotationPromotionApproved] = "prod=c4"
+	g.Expect(r.syncPromotion(sub, chnv1.ChannelTypeGit, clusters, now)).To(gomega.Succeed())
+	g.Expect(sub.Status.Promotion[1].Commit).To(gomega.Equal("c4"))
+
+	_, err = getRequest("demo-prod-c4")
+	g.Expect(errors.IsNotFound(err)).To(gomega.BeTrue())
+
+	_, err = getRequest("demo-prod-c3")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+}
@@ -0,0 +1,356 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	spokeClusterV1 "open-cluster-management.io/api/cluster/v1"
+	manifestWorkV1 "open-cluster-management.io/api/work/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appSubV1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appSubStatusV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+// changeFreezeRequeueInterval is how often the hub checks the end of the ChangeFreezes of a frozen subscription, the
+// end of a freeze doesn't trigger a reconcile
+var changeFreezeRequeueInterval = time.Minute
+
+// getActiveChangeFreezes returns the ChangeFreezes active at the time for the namespace of the subscription and not
+// overridden by its change-freeze-override annotation
+func (r *ReconcileSubscription) getActiveChangeFreezes(sub *appSubV1.Subscription,
+	now time.Time) ([]appSubStatusV1alpha1.ChangeFreeze, error) {
+	freezeList := &appSubStatusV1alpha1.ChangeFreezeList{}
+
+	err := r.List(context.TODO(), freezeList)
+	if meta.IsNoMatchError(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the change freezes, err: %w", err)
+	}
+
+	overrides := map[string]bool{}
+
+	for _, name := range strings.Split(sub.GetAnnotations()[appSubV1.AnnotationChangeFreezeOverride], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			overrides[name] = true
+		}
+	}
+
+	var freezes []appSubStatusV1alpha1.ChangeFreeze
+
+	for _, freeze := range freezeList.Items {
+		if !freeze.IsActive(now) || !isChangeFreezeNamespace(freeze, sub.Namespace) {
+			continue
+		}
+
+		if overrides["*"] || overrides[freeze.Name] {
+			klog.Infof("subscription %v/%v overrides change freeze %v", sub.Namespace, sub.Name, freeze.Name)
+
+			continue
+		}
+
+		freezes = append(freezes, freeze)
+	}
+
+	sort.Slice(freezes, func(i, j int) bool { return freezes[i].Name < freezes[j].Name })
+
+	return freezes, nil
+}
+
+func isChangeFreezeNamespace(freeze appSubStatusV1alpha1.ChangeFreeze, namespace string) bool {
+	if len(freeze.Spec.Namespaces) == 0 {
+		return true
+	}
+
+	for _, ns := range freeze.Spec.Namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getFrozenClusters returns the freeze of each cluster frozen by the active ChangeFreezes and sets the ChangeFrozen
+// condition of the subscription
+func (r *ReconcileSubscription) getFrozenClusters(sub *appSubV1.Subscription, clusters []string,
+	now time.Time) (map[string]string, error) {
+	freezes, err := r.getActiveChangeFreezes(sub, now)
+	if err != nil {
+		return nil, err
+	}
+
+	frozen := map[string]string{}
+	labels := map[string]map[string]string{}
+
+	for _, cluster := range clusters {
+		for _, freeze := range freezes {
+			if freeze.Spec.ClusterSelector != nil {
+				if _, ok := labels[cluster]; !ok {
+					managedCluster := &spokeClusterV1.ManagedCluster{}
+
+					if err := r.Get(context.TODO(), types.NamespacedName{Name: cluster}, managedCluster); err != nil {
+						klog.Warningf("failed to get managed cluster %v to evaluate the change freezes, err: %v", cluster, err)
+					}
+
+					labels[cluster] = managedCluster.GetLabels()
+				}
+
+				if !utils.LabelChecker(freeze.Spec.ClusterSelector, labels[cluster]) {
+					continue
+				}
+			}
+
+			frozen[cluster] = freeze.Name
+
+			break
+		}
+	}
+
+	setChangeFrozenCondition(sub, freezes, frozen)
+
+	return frozen, nil
+}
+
+func setChangeFrozenCondition(sub *appSubV1.Subscription, freezes []appSubStatusV1alpha1.ChangeFreeze,
+	frozen map[string]string) {
+	if len(frozen) == 0 {
+		meta.RemoveStatusCondition(&sub.Status.Conditions, appSubV1.ConditionChangeFrozen)
+
+		return
+	}
+
+	counts := map[string]int{}
+	for _, freeze := range frozen {
+		counts[freeze]++
+	}
+
+	msgs := []string{}
+
+	for _, freeze := range freezes {
+		if counts[freeze.Name] == 0 {
+			continue
+		}
+
+		msg := fmt.Sprintf("change freeze %v freezes %v clusters", freeze.Name, counts[freeze.Name])
+		if freeze.Spec.Reason != "" {
+			msg += ": " + freeze.Spec.Reason
+		}
+
+		msgs = append(msgs, msg)
+	}
+
+	meta.SetStatusCondition(&sub.Status.Conditions, metav1.Condition{
+		Type:               appSubV1.ConditionChangeFrozen,
+		Status:             metav1.ConditionTrue,
+		Reason:             appSubV1.ReasonChangeFreezeActive,
+		Message:            strings.Join(msgs, "; "),
+		ObservedGeneration: sub.GetGeneration(),
+	})
+}
+
+// isChangeFrozen checks if the new revisions of the subscription are held back from some clusters by a ChangeFreeze
+func isChangeFrozen(sub *appSubV1.Subscription) bool {
+	return meta.IsStatusConditionTrue(sub.Status.Conditions, appSubV1.ConditionChangeFrozen)
+}
+
+// changeFreezeRequeueAfter returns when to check the end of the change freezes, zero if the subscription is not frozen
+func changeFreezeRequeueAfter(sub *appSubV1.Subscription) time.Duration {
+	if isChangeFrozen(sub) {
+		return changeFreezeRequeueInterval
+	}
+
+	return 0
+}
+
+// pinFrozenManifestWork pins the subscription of the ManifestWork of a frozen cluster to the revision applied by the
+// cluster, the agent would pull the new commits of the Git branch or the new chart versions otherwise. The Git
+// revisions are commits and the Helm revisions are the <chart>:<version> of the charts. It returns if the ManifestWork
+// is changed
+func pinFrozenManifestWork(manifestWork *manifestWorkV1.ManifestWork, revision string) (bool, error) {
+	if revision == "" {
+		return false, nil
+	}
+
+	charts := map[string]string{}
+
+	if strings.Contains(revision, ":") {
+		for _, chart := range strings.Split(revision, ",") {
+			if name, version, found := strings.Cut(chart, ":"); found {
+				charts[name] = version
+			}
+		}
+	}
+
+	changed := false
+
+	for i, manifest := range manifestWork.Spec.Workload.Manifests {
+		appsub := &appSubV1.Subscription{}
+		if err := json.Unmarshal(manifest.Raw, appsub); err != nil || appsub.Kind != "Subscription" {
+			continue
+		}
+
+		if !pinSubscriptionRevision(appsub, revision, charts) {
+			continue
+		}
+
+		raw, err := json.Marshal(appsub)
+		if err != nil {
+			return false, err
+		}
+
+		manifestWork.Spec.Workload.Manifests[i].Raw = raw
+		changed = true
+	}
+
+	return changed, nil
+}
+
+func pinSubscriptionRevision(appsub *appSubV1.Subscription, revision string, charts map[string]string) bool {
+	if len(charts) == 0 {
+		if appsub.GetAnnotations()[appSubV1.AnnotationGitTargetCommit] != "" {
+			return false
+		}
+
+		annotations := appsub.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+
+		annotations[appSubV1.AnnotationGitTargetCommit] = revision
+		appsub.SetAnnotations(annotations)
+
+		return true
+	}
+
+	filter := &appSubV1.PackageFilter{}
+	if appsub.Spec.PackageFilter != nil {
+		filter = appsub.Spec.PackageFilter.DeepCopy()
+	}
+
+	pinned := map[string]bool{}
+
+	for i, chart := range filter.Charts {
+		if version, ok := charts[chart.Name]; ok {
+			filter.Charts[i].Version = version
+			pinned[chart.Name] = true
+		}
+	}
+
+	if version, ok := charts[appsub.Spec.Package]; ok && appsub.Spec.Package != "" && !pinned[appsub.Spec.Package] {
+		filter.Charts = append(filter.Charts, appSubV1.ChartFilter{Name: appsub.Spec.Package, Version: version})
+	}
+
+	if appsub.Spec.PackageFilter != nil && equalChartFilters(appsub.Spec.PackageFilter.Charts, filter.Charts) {
+		return false
+	}
+
+	appsub.Spec.PackageFilter = filter
+
+	return true
+}
+
+func equalChartFilters(a, b []appSubV1.ChartFilter) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// getClusterRevisions returns the revision applied by the subscription on each cluster from the app appsubReport
+func (r *ReconcileSubscription) getClusterRevisions(sub *appSubV1.Subscription) map[string]string {
+	revisions := map[string]string{}
+
+	appsubReport := &appSubStatusV1alpha1.SubscriptionReport{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: sub.Name, Namespace: sub.Namespace}, appsubReport); err != nil {
+		klog.V(1).Infof("failed to get the app appsubReport %v/%v, err: %v", sub.Namespace, sub.Name, err)
+
+		return revisions
+	}
+
+	for _, result := range appsubReport.Results {
+		if result != nil && result.Revision != "" {
+			revisions[result.Source] = result.Revision
+		}
+	}
+
+	return revisions
+}
+
+type changeFreezeMapper struct {
+	client.Client
+}
+
+// Map enqueues the hub subscriptions of the namespaces of the freeze, the frozen subscriptions are propagated again
+// when the freeze is changed or deleted
+func (mapper *changeFreezeMapper) Map(obj client.Object) []reconcile.Request {
+	freeze, ok := obj.(*appSubStatusV1alpha1.ChangeFreeze)
+	if !ok {
+		return nil
+	}
+
+	namespaces := freeze.Spec.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+
+	var requests []reconcile.Request
+
+	for _, ns := range namespaces {
+		subList := &appSubV1.SubscriptionList{}
+		if err := mapper.List(context.TODO(), subList, client.InNamespace(ns)); err != nil {
+			klog.Error("Listing the subscriptions in changeFreezeMapper and got error:", err)
+
+			continue
+		}
+
+		for i := range subList.Items {
+			if !isQuotaSubscription(&subList.Items[i]) {
+				continue
+			}
+
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+				Name:      subList.Items[i].GetName(),
+				Namespace: subList.Items[i].GetNamespace(),
+			}})
+		}
+	}
+
+	klog.V(1).Info("Out change freeze mapper with requests:", requests)
+
+	return requests
+}
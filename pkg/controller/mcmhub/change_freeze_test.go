@@ -0,0 +1,150 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	spokeClusterV1 "open-cluster-management.io/api/cluster/v1"
+	manifestWorkV1 "open-cluster-management.io/api/work/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appsubreportv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+)
+
+func TestChangeFreeze(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(appsubreportv1alpha1.AddToScheme(scheme)).To(gomega.Succeed())
+	g.Expect(spokeClusterV1.AddToScheme(scheme)).To(gomega.Succeed())
+	g.Expect(manifestWorkV1.AddToScheme(scheme)).To(gomega.Succeed())
+
+	now := time.Now()
+
+	managedCluster := func(name, env string) *spokeClusterV1.ManagedCluster {
+		return &spokeClusterV1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"env": env}}}
+	}
+
+	hostedSub, err := json.Marshal(&appv1.Subscription{
+		TypeMeta:   metav1.TypeMeta{Kind: "Subscription", APIVersion: "apps.open-cluster-management.io/v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns"},
+		Spec:       appv1.SubscriptionSpec{Channel: "chn-ns/git"},
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	manifestWork := func(cluster string) *manifestWorkV1.ManifestWork {
+		return &manifestWorkV1.ManifestWork{
+			ObjectMeta: metav1.ObjectMeta{Name: "demo-ns-demo", Namespace: cluster},
+			Spec: manifestWorkV1.ManifestWorkSpec{Workload: manifestWorkV1.ManifestsTemplate{
+				Manifests: []manifestWorkV1.Manifest{{RawExtension: runtime.RawExtension{Raw: hostedSub}}},
+			}},
+		}
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		managedCluster("prod1", "prod"), managedCluster("prod2", "prod"), managedCluster("dev1", "dev"),
+		manifestWork("prod1"), manifestWork("prod2"),
+		&appsubreportv1alpha1.ChangeFreeze{
+			ObjectMeta: metav1.ObjectMeta{Name: "holiday"},
+			Spec: appsubreportv1alpha1.ChangeFreezeSpec{
+				Namespaces:      []string{"demo-ns"},
+				ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+				End:             &metav1.Time{Time: now.Add(time.Hour)},
+				Reason:          "holiday season",
+			},
+		},
+		&appsubreportv1alpha1.ChangeFreeze{
+			ObjectMeta: metav1.ObjectMeta{Name: "later"},
+			Spec:       appsubreportv1alpha1.ChangeFreezeSpec{Start: &metav1.Time{Time: now.Add(time.Hour)}},
+		},
+		&appsubreportv1alpha1.SubscriptionReport{
+			ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns"},
+			ReportType: "Application",
+			Results:    []*appsubreportv1alpha1.SubscriptionReportResult{{Source: "prod1", Result: "deployed", Revision: "c1"}},
+		},
+	).Build()
+
+	r := &ReconcileSubscription{Client: c}
+
+	sub := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns"}}
+
+	familymap := func() map[string]*manifestWorkV1.ManifestWork {
+		familymap := map[string]*manifestWorkV1.ManifestWork{}
+
+		for _, cluster := range []string{"prod1", "prod2"} {
+			mw := &manifestWorkV1.ManifestWork{}
+			g.Expect(c.Get(context.TODO(), types.NamespacedName{Name: "demo-ns-demo", Namespace: cluster}, mw)).To(gomega.Succeed())
+			familymap[cluster+"-demo-ns-demo"] = mw
+		}
+
+		return familymap
+	}
+
+	// the prod clusters are frozen, the expired manifestWork of prod2 is kept and prod1 is pinned to its commit
+	expired := familymap()
+	clusters, err := r.holdFrozenManifestWorks(sub, []ManageClusters{{Cluster: "prod1"}, {Cluster: "dev1"}}, expired)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(clusters).To(gomega.Equal([]ManageClusters{{Cluster: "dev1"}}))
+	g.Expect(expired).To(gomega.BeEmpty())
+
+	condition := meta.FindStatusCondition(sub.Status.Conditions, appv1.ConditionChangeFrozen)
+	g.Expect(condition).NotTo(gomega.BeNil())
+	g.Expect(condition.Message).To(gomega.Equal("change freeze holiday freezes 2 clusters: holiday season"))
+	g.Expect(changeFreezeRequeueAfter(sub)).To(gomega.Equal(changeFreezeRequeueInterval))
+
+	pinnedSub := &appv1.Subscription{}
+	g.Expect(json.Unmarshal(familymap()["prod1-demo-ns-demo"].Spec.Workload.Manifests[0].Raw, pinnedSub)).To(gomega.Succeed())
+	g.Expect(pinnedSub.GetAnnotations()[appv1.AnnotationGitTargetCommit]).To(gomega.Equal("c1"))
+
+	prod2Sub := &appv1.Subscription{}
+	g.Expect(json.Unmarshal(familymap()["prod2-demo-ns-demo"].Spec.Workload.Manifests[0].Raw, prod2Sub)).To(gomega.Succeed())
+	g.Expect(prod2Sub.GetAnnotations()).To(gomega.BeEmpty())
+
+	// the subscription overriding the freeze is propagated to all its clusters
+	sub.SetAnnotations(map[string]string{appv1.AnnotationChangeFreezeOverride: "holiday"})
+
+	expired = familymap()
+	clusters, err = r.holdFrozenManifestWorks(sub, []ManageClusters{{Cluster: "prod1"}, {Cluster: "dev1"}}, expired)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(clusters).To(gomega.HaveLen(2))
+	g.Expect(expired).To(gomega.HaveLen(2))
+	g.Expect(isChangeFrozen(sub)).To(gomega.BeFalse())
+
+	// the helm subscriptions are pinned to the chart versions applied by the cluster
+	helmSub := &appv1.Subscription{
+		Spec: appv1.SubscriptionSpec{
+			Package:       "nginx",
+			PackageFilter: &appv1.PackageFilter{Charts: []appv1.ChartFilter{{Name: "redis", Version: ">=7.0.0"}}},
+		},
+	}
+
+	g.Expect(pinSubscriptionRevision(helmSub, "nginx:1.2.0,redis:7.0.1",
+		map[string]string{"nginx": "1.2.0", "redis": "7.0.1"})).To(gomega.BeTrue())
+	g.Expect(helmSub.Spec.PackageFilter.Charts).To(gomega.Equal([]appv1.ChartFilter{
+		{Name: "redis", Version: "7.0.1"}, {Name: "nginx", Version: "1.2.0"},
+	}))
+	g.Expect(pinSubscriptionRevision(helmSub, "nginx:1.2.0,redis:7.0.1",
+		map[string]string{"nginx": "1.2.0", "redis": "7.0.1"})).To(gomega.BeFalse())
+}
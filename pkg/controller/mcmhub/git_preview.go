@@ -0,0 +1,84 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	subv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+// pullRequestInfo is the pull request of the preview subscriptions of a branch, with the channel credentials to get its state
+type pullRequestInfo struct {
+	pr                 *utils.GitPullRequest
+	token              string
+	insecureSkipVerify bool
+}
+
+// pullRequestStateFunc returns true if the pull request is open
+type pullRequestStateFunc func(pr *utils.GitPullRequest, token string, insecureSkipVerify bool) (bool, error)
+
+// teardownClosedPullRequest deletes the preview subscriptions of a closed pull request, their resources are then
+// removed from the managed clusters. It returns true if the pull request is closed
+func (h *HubGitOps) teardownClosedPullRequest(branchInfo *branchInfo) bool {
+	prInfo := branchInfo.pullRequest
+
+	open, err := h.pullRequestState(prInfo.pr, prInfo.token, prInfo.insecureSkipVerify)
+	if err != nil {
+		h.logger.Error(err, fmt.Sprintf("failed to get the state of pull request %v of %v", prInfo.pr.Number, prInfo.pr.RepoURL))
+
+		return false
+	}
+
+	if open {
+		return false
+	}
+
+	for subKey := range branchInfo.registeredSub {
+		h.logger.Info(fmt.Sprintf("pull request %v of %v is closed, deleting preview subscription %v", prInfo.pr.Number,
+			prInfo.pr.RepoURL, subKey.String()))
+
+		if err := deletePreviewSubscription(h.clt, subKey, prInfo.pr.Number); err != nil {
+			h.logger.Error(err, fmt.Sprintf("failed to delete preview subscription %v", subKey.String()))
+		}
+	}
+
+	return true
+}
+
+func isPullRequestOpen(pr *utils.GitPullRequest, token string, insecureSkipVerify bool) (bool, error) {
+	return pr.IsOpen(token, insecureSkipVerify)
+}
+
+// deletePreviewSubscription deletes the subscription if it is still the preview of the pull request
+func deletePreviewSubscription(clt client.Client, subKey types.NamespacedName, prNumber int) error {
+	subIns := &subv1.Subscription{}
+
+	if err := clt.Get(context.TODO(), subKey, subIns); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	if subIns.GetAnnotations()[subv1.AnnotationGitPullRequest] != strconv.Itoa(prNumber) {
+		return nil
+	}
+
+	return client.IgnoreNotFound(clt.Delete(context.TODO(), subIns))
+}
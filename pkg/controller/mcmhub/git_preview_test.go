@@ -0,0 +1,83 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+func TestTeardownClosedPullRequest(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(appv1.SchemeBuilder.AddToScheme(scheme)).To(gomega.Succeed())
+
+	preview := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{
+		Name:        "app-pr-42",
+		Namespace:   "app-pr-42",
+		Annotations: map[string]string{appv1.AnnotationGitPullRequest: "42"},
+	}}
+
+	// the subscription now previews another pull request
+	moved := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{
+		Name:        "app-pr-next",
+		Namespace:   "app-pr-42",
+		Annotations: map[string]string{appv1.AnnotationGitPullRequest: "43"},
+	}}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(preview, moved).Build()
+
+	prOpen := true
+	h := NewHookGit(c, setHubGitOpsLogger(logr.Discard()), setPullRequestStateFunc(func(pr *utils.GitPullRequest, token string, insecureSkipVerify bool) (bool, error) {
+		g.Expect(token).To(gomega.Equal("token"))
+
+		return prOpen, nil
+	}))
+
+	bInfo := &branchInfo{
+		registeredSub: map[types.NamespacedName]struct{}{
+			{Name: "app-pr-42", Namespace: "app-pr-42"}:   {},
+			{Name: "app-pr-next", Namespace: "app-pr-42"}: {},
+		},
+		pullRequest: &pullRequestInfo{
+			pr:    &utils.GitPullRequest{Provider: utils.GitProviderGitHub, RepoURL: "https://github.com/org/app", Number: 42},
+			token: "token",
+		},
+	}
+
+	g.Expect(h.teardownClosedPullRequest(bInfo)).To(gomega.BeFalse())
+
+	subList := &appv1.SubscriptionList{}
+	g.Expect(c.List(context.TODO(), subList)).To(gomega.Succeed())
+	g.Expect(subList.Items).To(gomega.HaveLen(2))
+
+	prOpen = false
+
+	g.Expect(h.teardownClosedPullRequest(bInfo)).To(gomega.BeTrue())
+	g.Expect(c.List(context.TODO(), subList)).To(gomega.Succeed())
+	g.Expect(subList.Items).To(gomega.HaveLen(1))
+	g.Expect(subList.Items[0].Name).To(gomega.Equal("app-pr-next"))
+}
@@ -150,7 +150,8 @@ func getResourcePath(localFolderFunc func(*appv1.Subscription) string, sub *appv
 
 func (r *ReconcileSubscription) processRepo(chn *chnv1.Channel, sub *appv1.Subscription,
 	localRepoRoot, subPath, baseDir string, isAdmin bool) ([]*v1.ObjectReference, error) {
-	chartDirs, kustomizeDirs, crdsAndNamespaceFiles, rbacFiles, otherFiles, err := utils.SortResources(localRepoRoot, subPath)
+	chartDirs, kustomizeDirs, crdsAndNamespaceFiles, rbacFiles, otherFiles, err := utils.SortResources(localRepoRoot, subPath,
+		utils.ExcludePathsSkipFunc(localRepoRoot, utils.GitExcludePaths(chn), nil))
 
 	if err != nil {
 		klog.Error(err, "Failed to sort kubernetes resources and helm charts.")
@@ -0,0 +1,63 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chartutil"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/klog/v2"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+// isHelmClusterCapabilitiesEnabled checks if the helm-cluster-capabilities annotation of the subscription is "true"
+func isHelmClusterCapabilitiesEnabled(sub *appv1.Subscription) bool {
+	return strings.EqualFold(sub.GetAnnotations()[appv1.AnnotationHelmClusterCapabilities], "true")
+}
+
+// clusterHelmCapabilities returns the capabilities the agent of a cluster reports on its heartbeat lease, with the key
+// of the clusters rendered the same. The capabilities are nil, and the charts rendered with the default capabilities of
+// helm, if the agent doesn't report them
+func clusterHelmCapabilities(lease *coordinationv1.Lease) (*chartutil.Capabilities, string) {
+	if lease == nil {
+		return nil, ""
+	}
+
+	version := lease.GetAnnotations()[utils.AnnotationAgentKubeVersion]
+	if version == "" {
+		return nil, ""
+	}
+
+	kubeVersion, err := chartutil.ParseKubeVersion(version)
+	if err != nil {
+		klog.Warningf("invalid kubernetes version %q on the heartbeat lease of cluster %v, err: %v", version,
+			lease.GetNamespace(), err)
+
+		return nil, ""
+	}
+
+	apiVersions := lease.GetAnnotations()[utils.AnnotationAgentAPIVersions]
+
+	caps := &chartutil.Capabilities{KubeVersion: *kubeVersion}
+
+	if apiVersions != "" {
+		caps.APIVersions = strings.Split(apiVersions, ",")
+	}
+
+	return caps, version + "|" + apiVersions
+}
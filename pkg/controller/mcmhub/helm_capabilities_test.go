@@ -0,0 +1,73 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+func TestClusterHelmCapabilities(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	sub := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{appv1.AnnotationHelmClusterCapabilities: "True"},
+	}}
+	g.Expect(isHelmClusterCapabilitiesEnabled(sub)).To(gomega.BeTrue())
+
+	lease := func(annotations map[string]string) *coordinationv1.Lease {
+		return &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{
+			Name:        utils.AgentHeartbeatLeaseName,
+			Namespace:   "cluster1",
+			Annotations: annotations,
+		}}
+	}
+
+	// the clusters without lease, or whose agent doesn't report the capabilities, are rendered with the defaults
+	caps, key := clusterHelmCapabilities(nil)
+	g.Expect(caps).To(gomega.BeNil())
+	g.Expect(key).To(gomega.BeEmpty())
+
+	caps, _ = clusterHelmCapabilities(lease(map[string]string{utils.AnnotationAgentVersion: "2.7.0"}))
+	g.Expect(caps).To(gomega.BeNil())
+
+	caps, _ = clusterHelmCapabilities(lease(map[string]string{utils.AnnotationAgentKubeVersion: "not-a-version"}))
+	g.Expect(caps).To(gomega.BeNil())
+
+	caps, key = clusterHelmCapabilities(lease(map[string]string{
+		utils.AnnotationAgentKubeVersion: "v1.27.3",
+		utils.AnnotationAgentAPIVersions: "apps/v1,apps/v1/Deployment,policy/v1,policy/v1/PodDisruptionBudget,v1",
+	}))
+	g.Expect(caps).NotTo(gomega.BeNil())
+	g.Expect(caps.KubeVersion.Major).To(gomega.Equal("1"))
+	g.Expect(caps.KubeVersion.Minor).To(gomega.Equal("27"))
+	g.Expect(caps.APIVersions.Has("policy/v1")).To(gomega.BeTrue())
+	g.Expect(caps.APIVersions.Has("policy/v1beta1")).To(gomega.BeFalse())
+	g.Expect(caps.APIVersions.Has("policy/v1/PodDisruptionBudget")).To(gomega.BeTrue())
+	g.Expect(caps.APIVersions.Has("policy/v1beta1/PodDisruptionBudget")).To(gomega.BeFalse())
+
+	// the clusters with other capabilities are rendered apart
+	_, otherKey := clusterHelmCapabilities(lease(map[string]string{
+		utils.AnnotationAgentKubeVersion: "v1.24.6",
+		utils.AnnotationAgentAPIVersions: "apps/v1,policy/v1,policy/v1beta1,v1",
+	}))
+	g.Expect(otherKey).NotTo(gomega.Equal(key))
+}
@@ -71,6 +71,9 @@ type HookProcessor interface {
 	IsPostHooksCompleted(types.NamespacedName) (bool, error)
 
 	HasHooks(string, types.NamespacedName) bool
+	//HookScheduleRequeueAfter returns when the next hook waiting for its
+	//timewindow schedule runs
+	HookScheduleRequeueAfter(types.NamespacedName) time.Duration
 	//WriteStatusToSubscription gets the status at the entry of the reconcile,
 	//also the procssed subscription(which should carry all the update status on
 	//the given reconciel), then  WriteStatusToSubscription will append the hook
@@ -89,6 +92,10 @@ type Hooks struct {
 
 	//store last subscription instance used for the hook operation
 	lastSub *subv1.Subscription
+
+	//store the commit of the last hook operation and the commit before it
+	lastCommit     string
+	previousCommit string
 }
 
 func (h *Hooks) ConstructStatus() subv1.AnsibleJobsStatus {
@@ -317,7 +324,7 @@ func suffixBasedOnSpecAndCommitID(gClt GitOps, subIns *subv1.Subscription) strin
 
 func (a *AnsibleHooks) registerHook(subIns *subv1.Subscription, hookFlag string,
 	jobs []ansiblejob.AnsibleJob, placementDecisionUpdated bool, placementRuleRv string,
-	commitIDChanged bool) error {
+	commitIDChanged bool, hookCtx *HookContext) error {
 	subKey := types.NamespacedName{Name: subIns.GetName(), Namespace: subIns.GetNamespace()}
 
 	if hookFlag == PreHookType {
@@ -326,7 +333,7 @@ func (a *AnsibleHooks) registerHook(subIns *subv1.Subscription, hookFlag string,
 		}
 
 		err := a.registry[subKey].preHooks.registryJobs(a.gitClt, subIns, a.suffixFunc, jobs, a.clt, a.logger,
-			placementDecisionUpdated, placementRuleRv, "prehook", commitIDChanged)
+			placementDecisionUpdated, placementRuleRv, "prehook", commitIDChanged, hookCtx)
 
 		return err
 	}
@@ -336,7 +343,7 @@ func (a *AnsibleHooks) registerHook(subIns *subv1.Subscription, hookFlag string,
 	}
 
 	err := a.registry[subKey].postHooks.registryJobs(a.gitClt, subIns, a.suffixFunc, jobs, a.clt, a.logger,
-		placementDecisionUpdated, placementRuleRv, "posthook", commitIDChanged)
+		placementDecisionUpdated, placementRuleRv, "posthook", commitIDChanged, hookCtx)
 
 	return err
 }
@@ -373,19 +380,34 @@ func (a *AnsibleHooks) addHookToRegisitry(subIns *subv1.Subscription, placementD
 		a.logger.Error(fmt.Errorf("posthook"), "failed to find hook:")
 	}
 
-	if len(preJobs) != 0 || len(postJobs) != 0 {
-		subKey := types.NamespacedName{Name: subIns.GetName(), Namespace: subIns.GetNamespace()}
-		a.registry[subKey].lastSub = subIns
+	if len(preJobs) == 0 && len(postJobs) == 0 {
+		return nil
+	}
+
+	subKey := types.NamespacedName{Name: subIns.GetName(), Namespace: subIns.GetNamespace()}
+	hooks := a.registry[subKey]
+	hooks.lastSub = subIns
+
+	commit, err := a.gitClt.GetLatestCommitID(subIns)
+	if err != nil {
+		a.logger.Error(err, "failed to get the latest commit for the hook context")
+	}
+
+	if commit != "" && commit != hooks.lastCommit {
+		hooks.previousCommit = hooks.lastCommit
+		hooks.lastCommit = commit
 	}
 
+	hookCtx := a.newHookContext(subIns, hooks.lastCommit, hooks.previousCommit)
+
 	if len(preJobs) != 0 {
-		if err := a.registerHook(subIns, PreHookType, preJobs, placementDecisionUpdated, placementRuleRv, commitIDChanged); err != nil {
+		if err := a.registerHook(subIns, PreHookType, preJobs, placementDecisionUpdated, placementRuleRv, commitIDChanged, hookCtx); err != nil {
 			return err
 		}
 	}
 
 	if len(postJobs) != 0 {
-		if err := a.registerHook(subIns, PostHookType, postJobs, placementDecisionUpdated, placementRuleRv, commitIDChanged); err != nil {
+		if err := a.registerHook(subIns, PostHookType, postJobs, placementDecisionUpdated, placementRuleRv, commitIDChanged, hookCtx); err != nil {
 			return err
 		}
 	}
@@ -412,9 +434,11 @@ func addingHostingSubscriptionAnno(job ansiblejob.AnsibleJob, subKey types.Names
 }
 
 // overrideAnsibleInstance adds the owner reference to job, and also reset the
-// secret file of ansibleJob
-func overrideAnsibleInstance(subIns *subv1.Subscription, job ansiblejob.AnsibleJob,
-	kubeclient client.Client, logger logr.Logger, hookType string) (ansiblejob.AnsibleJob, error) {
+// secret file of ansibleJob. The target clusters of the job scope and the hook
+// context are set in the extra vars
+func overrideAnsibleInstance(subIns *subv1.Subscription, scopedJob scopedHookJob,
+	kubeclient client.Client, logger logr.Logger, hookType string, hookCtx *HookContext) (ansiblejob.AnsibleJob, error) {
+	job := scopedJob.job
 	job.SetResourceVersion("")
 	// avoid the error:
 	// status.conditions.lastTransitionTime in body must be of type string: \"null\""
@@ -426,9 +450,15 @@ func overrideAnsibleInstance(subIns *subv1.Subscription, job ansiblejob.AnsibleJ
 
 	if subIns.Spec.Placement != nil &&
 		(subIns.Spec.Placement.Local == nil || !*subIns.Spec.Placement.Local) {
-		clusters, err := GetClustersByPlacement(subIns, kubeclient, logger)
-		if err != nil {
-			return job, err
+		clusters := scopedJob.clusters
+
+		if clusters == nil {
+			var err error
+
+			clusters, err = GetClustersByPlacement(subIns, kubeclient, logger)
+			if err != nil {
+				return job, err
+			}
 		}
 
 		if len(clusters) > 0 {
@@ -449,6 +479,16 @@ func overrideAnsibleInstance(subIns *subv1.Subscription, job ansiblejob.AnsibleJ
 
 			extraVarsMap["target_clusters"] = targetClusters
 
+			if hookCtx != nil {
+				jobCtx := *hookCtx
+				jobCtx.HookType = hookType
+				jobCtx.HookScope = scopedJob.scope
+				jobCtx.DecisionGroup = scopedJob.decisionGroup
+				jobCtx.Clusters = getHookClusters(kubeclient, clusters)
+
+				extraVarsMap[HookContextVar] = jobCtx
+			}
+
 			extraVars, err := json.Marshal(extraVarsMap)
 			if err != nil {
 				return job, err
@@ -648,6 +688,10 @@ func isJobRunning(job *ansiblejob.AnsibleJob, logger logr.Logger) bool {
 func GetClustersByPlacement(instance *subv1.Subscription, kubeclient client.Client, logger logr.Logger) ([]types.NamespacedName, error) {
 	var clusters []types.NamespacedName
 
+	if utils.IsHubPlacement(instance) {
+		return []types.NamespacedName{{Name: utils.HubCluster, Namespace: utils.HubCluster}}, nil
+	}
+
 	if instance.Spec.Placement != nil {
 		var err error
 
@@ -0,0 +1,140 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"time"
+
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+
+	"k8s.io/apimachinery/pkg/types"
+	spokeClusterV1 "open-cluster-management.io/api/cluster/v1"
+	subv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// HookContextVar is the extra var of the hook context in the AnsibleJob hooks
+	HookContextVar = "hook_context"
+
+	TimeWindowNone    = "none"
+	TimeWindowActive  = "active"
+	TimeWindowBlocked = "blocked"
+)
+
+// HookContext is the deployment context of the subscription passed to the AnsibleJob hooks in the
+// hook_context extra var, along with the target_clusters extra var
+type HookContext struct {
+	// Subscription is the namespace/name of the subscription
+	Subscription string `json:"subscription"`
+	// HookType is prehook or posthook
+	HookType string `json:"hook_type"`
+	// HookScope is hub, cluster or decision-group
+	HookScope string `json:"hook_scope"`
+	// DecisionGroup is the decision group of the clusters of a decision-group hook
+	DecisionGroup string `json:"decision_group,omitempty"`
+	// Channel is the namespace/name of the channel
+	Channel string `json:"channel"`
+	// ChannelURL is the Git repository URL of the channel
+	ChannelURL string `json:"channel_url"`
+	GitBranch  string `json:"git_branch,omitempty"`
+	GitPath    string `json:"git_path,omitempty"`
+	GitTag     string `json:"git_tag,omitempty"`
+	// GitCommit is the commit being deployed
+	GitCommit string `json:"git_commit"`
+	// PreviousGitCommit is the commit deployed before, it is empty for the first deployment
+	PreviousGitCommit string `json:"previous_git_commit,omitempty"`
+	// TimeWindow is none if the subscription doesn't have a time window, active or blocked otherwise
+	TimeWindow string `json:"time_window"`
+	// Clusters are the target clusters of the subscription
+	Clusters []HookCluster `json:"clusters"`
+}
+
+// HookCluster is a target cluster of the hook context
+type HookCluster struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Claims map[string]string `json:"claims,omitempty"`
+}
+
+// newHookContext returns the hook context of the subscription, without the hook type and the clusters
+func (a *AnsibleHooks) newHookContext(subIns *subv1.Subscription, commit, previousCommit string) *HookContext {
+	an := subIns.GetAnnotations()
+	branch, _, tag, _ := getBranchCommitDepthAndTag(subIns)
+
+	gitPath := an[subv1.AnnotationGitPath]
+	if an[subv1.AnnotationGithubPath] != "" {
+		gitPath = an[subv1.AnnotationGithubPath]
+	}
+
+	hookCtx := &HookContext{
+		Subscription:      types.NamespacedName{Name: subIns.GetName(), Namespace: subIns.GetNamespace()}.String(),
+		Channel:           subIns.Spec.Channel,
+		GitBranch:         branch,
+		GitPath:           gitPath,
+		GitTag:            tag,
+		GitCommit:         commit,
+		PreviousGitCommit: previousCommit,
+		TimeWindow:        getTimeWindowState(subIns.Spec.TimeWindow, time.Now()),
+	}
+
+	chn := &chnv1.Channel{}
+	if err := a.clt.Get(context.TODO(), utils.NamespacedNameFormat(subIns.Spec.Channel), chn); err == nil {
+		hookCtx.ChannelURL = chn.Spec.Pathname
+	}
+
+	return hookCtx
+}
+
+func getTimeWindowState(tw *subv1.TimeWindow, t time.Time) string {
+	if tw == nil {
+		return TimeWindowNone
+	}
+
+	if utils.IsInWindow(tw, t) {
+		return TimeWindowActive
+	}
+
+	return TimeWindowBlocked
+}
+
+// getHookClusters returns the labels and claims of the target clusters, only the name is set if the managed cluster
+// can't be found
+func getHookClusters(kubeclient client.Client, clusters []types.NamespacedName) []HookCluster {
+	hookClusters := []HookCluster{}
+
+	for _, cluster := range clusters {
+		hookCluster := HookCluster{Name: cluster.Name}
+
+		managedCluster := &spokeClusterV1.ManagedCluster{}
+		if err := kubeclient.Get(context.TODO(), types.NamespacedName{Name: cluster.Name}, managedCluster); err == nil {
+			hookCluster.Labels = managedCluster.GetLabels()
+
+			if len(managedCluster.Status.ClusterClaims) > 0 {
+				hookCluster.Claims = map[string]string{}
+
+				for _, claim := range managedCluster.Status.ClusterClaims {
+					hookCluster.Claims[claim.Name] = claim.Value
+				}
+			}
+		}
+
+		hookClusters = append(hookClusters, hookCluster)
+	}
+
+	return hookClusters
+}
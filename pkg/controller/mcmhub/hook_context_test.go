@@ -0,0 +1,119 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	spokeClusterV1 "open-cluster-management.io/api/cluster/v1"
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ansiblejob "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/ansible/v1alpha1"
+	plrv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
+	subv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func TestHookContext(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	testScheme := runtime.NewScheme()
+	g.Expect(subv1.SchemeBuilder.AddToScheme(testScheme)).To(gomega.Succeed())
+	g.Expect(chnv1.SchemeBuilder.AddToScheme(testScheme)).To(gomega.Succeed())
+	g.Expect(spokeClusterV1.AddToScheme(testScheme)).To(gomega.Succeed())
+	g.Expect(subv1.SchemeBuilder.AddToScheme(scheme.Scheme)).To(gomega.Succeed())
+
+	chn := &chnv1.Channel{
+		ObjectMeta: metav1.ObjectMeta{Name: "git", Namespace: "chn-ns"},
+		Spec:       chnv1.ChannelSpec{Type: chnv1.ChannelTypeGit, Pathname: "https://github.com/org/app.git"},
+	}
+
+	cluster := &spokeClusterV1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster1", Labels: map[string]string{"name": "cluster1", "env": "prod"}},
+		Status: spokeClusterV1.ManagedClusterStatus{
+			ClusterClaims: []spokeClusterV1.ManagedClusterClaim{{Name: "region.open-cluster-management.io", Value: "us-east-1"}},
+		},
+	}
+
+	subIns := &subv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "app-ns",
+			Annotations: map[string]string{
+				subv1.AnnotationGitBranch: "main",
+				subv1.AnnotationGitPath:   "apps/nginx",
+			},
+		},
+		Spec: subv1.SubscriptionSpec{
+			Channel: "chn-ns/git",
+			Placement: &plrv1.Placement{
+				GenericPlacementFields: plrv1.GenericPlacementFields{
+					Clusters: []plrv1.GenericClusterReference{{Name: "cluster1"}},
+				},
+			},
+		},
+	}
+
+	clt := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(chn, cluster, subIns).Build()
+	a := NewAnsibleHooks(clt, time.Second, setLogger(logr.Discard()))
+
+	hookCtx := a.newHookContext(subIns, "def456", "abc123")
+	g.Expect(hookCtx).To(gomega.Equal(&HookContext{
+		Subscription:      "app-ns/app",
+		Channel:           "chn-ns/git",
+		ChannelURL:        "https://github.com/org/app.git",
+		GitBranch:         "main",
+		GitPath:           "apps/nginx",
+		GitCommit:         "def456",
+		PreviousGitCommit: "abc123",
+		TimeWindow:        TimeWindowNone,
+	}))
+
+	job, err := overrideAnsibleInstance(subIns, scopedHookJob{job: ansiblejob.AnsibleJob{ObjectMeta: metav1.ObjectMeta{Name: "job"}}},
+		clt, logr.Discard(), "prehook", hookCtx)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	extraVars := struct {
+		TargetClusters []string    `json:"target_clusters"`
+		HookContext    HookContext `json:"hook_context"`
+	}{}
+
+	g.Expect(json.Unmarshal(job.Spec.ExtraVars, &extraVars)).To(gomega.Succeed())
+	g.Expect(extraVars.TargetClusters).To(gomega.Equal([]string{"cluster1"}))
+	g.Expect(extraVars.HookContext.HookType).To(gomega.Equal("prehook"))
+	g.Expect(extraVars.HookContext.GitCommit).To(gomega.Equal("def456"))
+	g.Expect(extraVars.HookContext.Clusters).To(gomega.Equal([]HookCluster{{
+		Name:   "cluster1",
+		Labels: map[string]string{"name": "cluster1", "env": "prod"},
+		Claims: map[string]string{"region.open-cluster-management.io": "us-east-1"},
+	}}))
+
+	// the shared context is not changed by the jobs
+	g.Expect(hookCtx.HookType).To(gomega.BeEmpty())
+	g.Expect(hookCtx.Clusters).To(gomega.BeNil())
+
+	tw := &subv1.TimeWindow{WindowType: "active", Daysofweek: []string{"Monday"}}
+	monday := time.Date(2022, time.August, 1, 10, 0, 0, 0, time.UTC)
+
+	g.Expect(getTimeWindowState(tw, monday)).To(gomega.Equal(TimeWindowActive))
+	g.Expect(getTimeWindowState(tw, monday.AddDate(0, 0, 1))).To(gomega.Equal(TimeWindowBlocked))
+}
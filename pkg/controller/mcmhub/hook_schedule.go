@@ -0,0 +1,153 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ansiblejob "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/ansible/v1alpha1"
+	subv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+const (
+	// HookScheduleImmediate runs the hook as soon as it is registered, it is the default schedule
+	HookScheduleImmediate = "immediate"
+	// HookScheduleWindowOpen runs the hook when the timewindow of the subscription opens
+	HookScheduleWindowOpen = "window-open"
+	// HookScheduleWindowClose runs the hook when the timewindow of the subscription closes
+	HookScheduleWindowClose = "window-close"
+)
+
+// parseHookSchedule returns the hook-schedule and the hook-schedule-lead annotations of the hook job
+func parseHookSchedule(job ansiblejob.AnsibleJob) (string, time.Duration, error) {
+	an := job.GetAnnotations()
+	schedule := strings.ToLower(strings.TrimSpace(an[subv1.AnnotationHookSchedule]))
+
+	switch schedule {
+	case "", HookScheduleImmediate:
+		return HookScheduleImmediate, 0, nil
+	case HookScheduleWindowOpen, HookScheduleWindowClose:
+	default:
+		return "", 0, fmt.Errorf("unsupported hook schedule %v of hook %v, it must be %v, %v or %v", schedule, job.GetName(),
+			HookScheduleImmediate, HookScheduleWindowOpen, HookScheduleWindowClose)
+	}
+
+	lead := time.Duration(0)
+
+	if v := strings.TrimSpace(an[subv1.AnnotationHookScheduleLead]); v != "" {
+		var err error
+
+		lead, err = time.ParseDuration(v)
+		if err != nil || lead < 0 {
+			return "", 0, fmt.Errorf("invalid hook schedule lead %v of hook %v, it must be a positive duration", v, job.GetName())
+		}
+	}
+
+	return schedule, lead, nil
+}
+
+// hookScheduleDelay returns how long the hook job waits for its schedule, zero to run the job now. The schedules are
+// ignored by the subscriptions without a timewindow. A window-open job runs the lead before the window opens, or
+// right away while the window is open. A window-close job runs the lead before the open window closes, or right away
+// while the window is closed
+func hookScheduleDelay(job ansiblejob.AnsibleJob, tw *subv1.TimeWindow, now time.Time) (time.Duration, error) {
+	schedule, lead, err := parseHookSchedule(job)
+	if err != nil {
+		return 0, err
+	}
+
+	if schedule == HookScheduleImmediate || tw == nil {
+		return 0, nil
+	}
+
+	inWindow := utils.IsInWindow(tw, now)
+
+	var delay time.Duration
+
+	switch schedule {
+	case HookScheduleWindowOpen:
+		if inWindow {
+			return 0, nil
+		}
+
+		delay = utils.NextStartPoint(tw, now)
+	case HookScheduleWindowClose:
+		if !inWindow {
+			return 0, nil
+		}
+
+		// NextStatusReconcile is a minute after the window closes, it is zero if the window never closes
+		next := utils.NextStatusReconcile(tw, now)
+		if next == 0 {
+			return 0, nil
+		}
+
+		delay = next - time.Minute
+	}
+
+	if delay -= lead; delay < 0 {
+		return 0, nil
+	}
+
+	return delay, nil
+}
+
+// HookScheduleRequeueAfter returns when the next hook job of the subscription waiting for its schedule runs, zero if
+// no hook job waits
+func (a *AnsibleHooks) HookScheduleRequeueAfter(subKey types.NamespacedName) time.Duration {
+	if !a.isRegistered(subKey) {
+		return 0
+	}
+
+	hooks := a.registry[subKey]
+	now := time.Now()
+	after := time.Duration(0)
+
+	for _, jIns := range []*JobInstances{hooks.preHooks, hooks.postHooks} {
+		if jIns == nil {
+			continue
+		}
+
+		for _, j := range *jIns {
+			if len(j.Instance) == 0 {
+				continue
+			}
+
+			nx := j.Instance[len(j.Instance)-1]
+
+			delay, err := hookScheduleDelay(nx, hooks.lastSub.Spec.TimeWindow, now)
+			if err != nil || delay == 0 || (after != 0 && delay >= after) {
+				continue
+			}
+
+			// the job waiting for the next window is already applied
+			err = a.clt.Get(context.TODO(), types.NamespacedName{Name: nx.GetName(), Namespace: nx.GetNamespace()},
+				&ansiblejob.AnsibleJob{})
+			if !kerr.IsNotFound(err) {
+				continue
+			}
+
+			after = delay
+		}
+	}
+
+	return after
+}
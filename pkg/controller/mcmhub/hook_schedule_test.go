@@ -0,0 +1,83 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ansiblejob "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/ansible/v1alpha1"
+	subv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func TestHookScheduleDelay(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	hookJob := func(schedule, lead string) ansiblejob.AnsibleJob {
+		an := map[string]string{}
+
+		if schedule != "" {
+			an[subv1.AnnotationHookSchedule] = schedule
+		}
+
+		if lead != "" {
+			an[subv1.AnnotationHookScheduleLead] = lead
+		}
+
+		return ansiblejob.AnsibleJob{ObjectMeta: metav1.ObjectMeta{Name: "change-ticket", Annotations: an}}
+	}
+
+	tw := &subv1.TimeWindow{
+		WindowType: "active",
+		Location:   "UTC",
+		Hours:      []subv1.HourRange{{Start: "9:00AM", End: "5:00PM"}},
+	}
+
+	beforeOpen := time.Date(2022, time.March, 1, 8, 0, 0, 0, time.UTC)
+	inWindow := time.Date(2022, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc     string
+		job      ansiblejob.AnsibleJob
+		tw       *subv1.TimeWindow
+		now      time.Time
+		expected time.Duration
+	}{
+		{desc: "immediate", job: hookJob("", ""), tw: tw, now: beforeOpen, expected: 0},
+		{desc: "no timewindow", job: hookJob(HookScheduleWindowOpen, ""), tw: nil, now: beforeOpen, expected: 0},
+		{desc: "window open", job: hookJob(HookScheduleWindowOpen, ""), tw: tw, now: beforeOpen, expected: time.Hour},
+		{desc: "before window open", job: hookJob(HookScheduleWindowOpen, "15m"), tw: tw, now: beforeOpen, expected: 45 * time.Minute},
+		{desc: "lead longer than the wait", job: hookJob(HookScheduleWindowOpen, "2h"), tw: tw, now: beforeOpen, expected: 0},
+		{desc: "open window", job: hookJob(HookScheduleWindowOpen, ""), tw: tw, now: inWindow, expected: 0},
+		{desc: "window close", job: hookJob(HookScheduleWindowClose, ""), tw: tw, now: inWindow, expected: 7 * time.Hour},
+		{desc: "before window close", job: hookJob(HookScheduleWindowClose, "30m"), tw: tw, now: inWindow, expected: 6*time.Hour + 30*time.Minute},
+		{desc: "closed window", job: hookJob(HookScheduleWindowClose, ""), tw: tw, now: beforeOpen, expected: 0},
+	}
+
+	for _, tC := range testCases {
+		delay, err := hookScheduleDelay(tC.job, tC.tw, tC.now)
+		g.Expect(err).NotTo(gomega.HaveOccurred(), tC.desc)
+		g.Expect(delay).To(gomega.Equal(tC.expected), tC.desc)
+	}
+
+	_, err := hookScheduleDelay(hookJob("at-noon", ""), tw, inWindow)
+	g.Expect(err).To(gomega.HaveOccurred())
+
+	_, err = hookScheduleDelay(hookJob(HookScheduleWindowOpen, "-15m"), tw, inWindow)
+	g.Expect(err).To(gomega.HaveOccurred())
+}
@@ -0,0 +1,173 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	ansiblejob "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/ansible/v1alpha1"
+	subv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// HookScopeHub runs the hook once on the hub for all the target clusters, it is the default scope
+	HookScopeHub = "hub"
+	// HookScopeCluster runs the hook once per target cluster
+	HookScopeCluster = "cluster"
+	// HookScopeDecisionGroup runs the hook once per decision group of the placement
+	HookScopeDecisionGroup = "decision-group"
+)
+
+var invalidJobNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// scopedHookJob is a hook job of a scope, the clusters are the target clusters of the job, nil is all the target
+// clusters of the subscription
+type scopedHookJob struct {
+	job           ansiblejob.AnsibleJob
+	scope         string
+	decisionGroup string
+	clusters      []types.NamespacedName
+}
+
+// scopeHookJobs expands the hook jobs by the hook-scope annotation of the jobs. A cluster scope job is expanded to a
+// job per target cluster named after the cluster, a decision-group scope job to a job per decision group of the
+// placementRef named after the group
+func scopeHookJobs(subIns *subv1.Subscription, jobs []ansiblejob.AnsibleJob, kubeclient client.Client,
+	logger logr.Logger) ([]scopedHookJob, error) {
+	scopedJobs := []scopedHookJob{}
+
+	var clusters []types.NamespacedName
+
+	clustersLoaded := false
+
+	for _, job := range jobs {
+		scope := strings.ToLower(strings.TrimSpace(job.GetAnnotations()[subv1.AnnotationHookScope]))
+
+		switch scope {
+		case "", HookScopeHub:
+			scopedJobs = append(scopedJobs, scopedHookJob{job: job, scope: HookScopeHub})
+
+			continue
+		case HookScopeCluster, HookScopeDecisionGroup:
+		default:
+			return nil, fmt.Errorf("unsupported hook scope %v of hook %v, it must be %v, %v or %v", scope, job.GetName(),
+				HookScopeHub, HookScopeCluster, HookScopeDecisionGroup)
+		}
+
+		// the hooks of the local subscriptions have no target clusters
+		if subIns.Spec.Placement == nil || (subIns.Spec.Placement.Local != nil && *subIns.Spec.Placement.Local) {
+			scopedJobs = append(scopedJobs, scopedHookJob{job: job, scope: scope})
+
+			continue
+		}
+
+		if !clustersLoaded {
+			var err error
+
+			clusters, err = GetClustersByPlacement(subIns, kubeclient, logger)
+			if err != nil {
+				return nil, err
+			}
+
+			sort.Slice(clusters, func(i, j int) bool { return clusters[i].Name < clusters[j].Name })
+
+			clustersLoaded = true
+		}
+
+		if scope == HookScopeCluster {
+			for _, cluster := range clusters {
+				scopedJobs = append(scopedJobs, scopedHookJob{
+					job:      renameHookJob(job, cluster.Name),
+					scope:    scope,
+					clusters: []types.NamespacedName{cluster},
+				})
+			}
+
+			continue
+		}
+
+		groupJobs, err := scopeDecisionGroupJob(subIns, job, clusters, kubeclient)
+		if err != nil {
+			return nil, err
+		}
+
+		scopedJobs = append(scopedJobs, groupJobs...)
+	}
+
+	return scopedJobs, nil
+}
+
+// scopeDecisionGroupJob returns a job per decision group, the clusters without a decision group share the job named
+// after the hook. The subscriptions without a placementRef have no decision groups
+func scopeDecisionGroupJob(subIns *subv1.Subscription, job ansiblejob.AnsibleJob, clusters []types.NamespacedName,
+	kubeclient client.Client) ([]scopedHookJob, error) {
+	if subIns.Spec.Placement.PlacementRef == nil {
+		return []scopedHookJob{{job: job, scope: HookScopeDecisionGroup, clusters: clusters}}, nil
+	}
+
+	groups, err := getDecisionGroupsFromPlacementRef(subIns.Spec.Placement.PlacementRef, subIns.GetNamespace(), kubeclient)
+	if err != nil {
+		return nil, err
+	}
+
+	groupClusters := map[string][]types.NamespacedName{}
+	groupNames := []string{}
+
+	for _, cluster := range clusters {
+		group := groups[cluster.Name]
+
+		if _, ok := groupClusters[group]; !ok {
+			groupNames = append(groupNames, group)
+		}
+
+		groupClusters[group] = append(groupClusters[group], cluster)
+	}
+
+	sort.Strings(groupNames)
+
+	scopedJobs := []scopedHookJob{}
+
+	for _, group := range groupNames {
+		groupJob := job
+
+		if group != "" {
+			groupJob = renameHookJob(job, group)
+		}
+
+		scopedJobs = append(scopedJobs, scopedHookJob{
+			job:           groupJob,
+			scope:         HookScopeDecisionGroup,
+			decisionGroup: group,
+			clusters:      groupClusters[group],
+		})
+	}
+
+	return scopedJobs, nil
+}
+
+func renameHookJob(job ansiblejob.AnsibleJob, suffix string) ansiblejob.AnsibleJob {
+	renamed := *job.DeepCopy()
+	suffix = strings.Trim(invalidJobNameChars.ReplaceAllString(strings.ToLower(suffix), "-"), "-")
+
+	renamed.SetName(job.GetName() + "-" + suffix)
+
+	return renamed
+}
@@ -0,0 +1,113 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clusterapi "open-cluster-management.io/api/cluster/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ansiblejob "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/ansible/v1alpha1"
+	plrv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
+	subv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func TestScopeHookJobs(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	testScheme := runtime.NewScheme()
+	g.Expect(clusterapi.AddToScheme(testScheme)).To(gomega.Succeed())
+
+	newDecision := func(name, group string, clusters ...string) *clusterapi.PlacementDecision {
+		decision := &clusterapi.PlacementDecision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "app-ns",
+				Labels:    map[string]string{placementLabel: "prod", decisionGroupNameLabel: group},
+			},
+		}
+
+		for _, cluster := range clusters {
+			decision.Status.Decisions = append(decision.Status.Decisions, clusterapi.ClusterDecision{ClusterName: cluster})
+		}
+
+		return decision
+	}
+
+	clt := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(
+		newDecision("prod-1", "Canary", "cluster1"),
+		newDecision("prod-2", "", "cluster2", "cluster3"),
+	).Build()
+
+	subIns := &subv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "app-ns"},
+		Spec: subv1.SubscriptionSpec{
+			Placement: &plrv1.Placement{
+				PlacementRef: &corev1.ObjectReference{Name: "prod", Kind: "Placement"},
+			},
+		},
+	}
+
+	newJob := func(name, scope string) ansiblejob.AnsibleJob {
+		job := ansiblejob.AnsibleJob{ObjectMeta: metav1.ObjectMeta{Name: name}}
+
+		if scope != "" {
+			job.SetAnnotations(map[string]string{subv1.AnnotationHookScope: scope})
+		}
+
+		return job
+	}
+
+	jobs := []ansiblejob.AnsibleJob{
+		newJob("ticket", ""),
+		newJob("configure", HookScopeCluster),
+		newJob("rollout", HookScopeDecisionGroup),
+	}
+
+	scopedJobs, err := scopeHookJobs(subIns, jobs, clt, logr.Discard())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	names := []string{}
+	for _, scopedJob := range scopedJobs {
+		names = append(names, scopedJob.job.GetName())
+	}
+
+	g.Expect(names).To(gomega.Equal([]string{
+		"ticket",
+		"configure-cluster1", "configure-cluster2", "configure-cluster3",
+		"rollout", "rollout-canary",
+	}))
+
+	g.Expect(scopedJobs[0].scope).To(gomega.Equal(HookScopeHub))
+	g.Expect(scopedJobs[0].clusters).To(gomega.BeNil())
+	g.Expect(scopedJobs[1].clusters).To(gomega.Equal([]types.NamespacedName{{Name: "cluster1", Namespace: "cluster1"}}))
+	g.Expect(scopedJobs[4].decisionGroup).To(gomega.BeEmpty())
+	g.Expect(scopedJobs[4].clusters).To(gomega.HaveLen(2))
+	g.Expect(scopedJobs[5].decisionGroup).To(gomega.Equal("Canary"))
+	g.Expect(scopedJobs[5].clusters).To(gomega.Equal([]types.NamespacedName{{Name: "cluster1", Namespace: "cluster1"}}))
+
+	// the original hook jobs are not renamed
+	g.Expect(jobs[1].GetName()).To(gomega.Equal("configure"))
+
+	_, err = scopeHookJobs(subIns, []ansiblejob.AnsibleJob{newJob("ticket", "fleet")}, clt, logr.Discard())
+	g.Expect(err).To(gomega.HaveOccurred())
+}
@@ -20,8 +20,8 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
-	gerr "github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -34,6 +34,7 @@ import (
 	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
 
 	"github.com/ghodss/yaml"
+	releasev1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/helmrelease/v1"
 	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
 	appsubreportv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
 	helmops "open-cluster-management.io/multicloud-operators-subscription/pkg/subscriber/helmrepo"
@@ -93,20 +94,41 @@ func (r *ReconcileSubscription) doMCMHubReconcile(sub *appv1.Subscription) error
 	// Add or sync application labels
 	r.AddAppLabels(sub)
 
-	var resources []*v1.ObjectReference
+	var (
+		resources []*v1.ObjectReference
+		helmRls   []*releasev1.HelmRelease
+	)
 
-	switch tp := strings.ToLower(string(primaryChannel.Spec.Type)); tp {
+	// only the helm repo subscriptions are validated against the values schema of their charts
+	setValuesSchemaCondition(sub, nil)
+
+	tp := strings.ToLower(string(primaryChannel.Spec.Type))
+
+	switch tp {
 	case chnv1.ChannelTypeGit, chnv1.ChannelTypeGitHub:
 		resources, err = r.GetGitResources(sub, isAdmin)
 	case chnv1.ChannelTypeHelmRepo:
-		helmRls, err := helmops.GetSubscriptionChartsOnHub(r.Client, primaryChannel, secondaryChannel, sub)
+		// the chart versions resolved before are kept while the versions are on hold
+		chartSub := sub.DeepCopy()
+		chartSub.Spec.PackageFilter = utils.GetHeldPackageFilter(sub)
+
+		helmRls, err = helmops.GetSubscriptionChartsOnHub(r.Client, primaryChannel, secondaryChannel, chartSub)
 		if err != nil {
 			klog.Error("failed to get the chart index for helm subscription %v, err: %v", ObjectString(sub), err)
 
 			return err
 		}
 
-		resources = getHelmTopoResources(helmRls, r.Client, r.cfg, r.restMapper, sub, isAdmin)
+		sub.Status.ResolvedVersions = getResolvedVersions(helmRls)
+
+		resources, err = getHelmTopoResources(helmRls, r.Client, r.cfg, r.restMapper, sub, isAdmin)
+		setValuesSchemaCondition(sub, err)
+
+		if err != nil {
+			klog.Errorf("subscription %v is not propagated, err: %v", substr, err)
+
+			return err
+		}
 	case chnv1.ChannelTypeObjectBucket:
 		resources, err = r.getObjectBucketResources(sub, primaryChannel, secondaryChannel, isAdmin)
 	}
@@ -117,6 +139,11 @@ func (r *ReconcileSubscription) doMCMHubReconcile(sub *appv1.Subscription) error
 		return err
 	}
 
+	// Generated application is only a view of the subscription for the console, don't block the propagation
+	if err := r.syncApplication(sub, resources); err != nil {
+		klog.Errorf("failed to sync the application for subscription %v, err: %v", substr, err)
+	}
+
 	// get all managed clusters
 	clusters, err := r.getClustersByPlacement(sub)
 
@@ -130,14 +157,53 @@ func (r *ReconcileSubscription) doMCMHubReconcile(sub *appv1.Subscription) error
 		return err
 	}
 
+	if err := r.checkPropagationAccess(sub, clusters, resources, isAdmin); err != nil {
+		klog.Errorf("subscription %v is not propagated, err: %v", substr, err)
+
+		return err
+	}
+
+	if err := r.checkSubscriptionQuota(sub, len(clusters), len(resources)); err != nil {
+		klog.Errorf("subscription %v is not propagated, err: %v", substr, err)
+
+		return err
+	}
+
 	if err := r.createAppAppsubReport(sub, resources, 0, len(clusters)); err != nil {
 		klog.Error(err, "Error creating app appsubReport")
 
 		return err
 	}
 
+	// the resource pages are only a view for the console, don't block the propagation
+	if err := r.syncResourcePages(sub, resources); err != nil {
+		klog.Errorf("subscription %v, err: %v", substr, err)
+	}
+
+	if err := r.syncPromotion(sub, tp, clusters, time.Now()); err != nil {
+		klog.Errorf("subscription %v is not propagated, err: %v", substr, err)
+
+		return err
+	}
+
+	if err := r.syncClusterRetries(sub, clusters, time.Now()); err != nil {
+		klog.Errorf("subscription %v is not propagated, err: %v", substr, err)
+
+		return err
+	}
+
 	err = r.PropagateAppSubManifestWork(sub, clusters)
 
+	// the rendered manifests are only a view for the troubleshooting, don't block the propagation
+	if err := r.syncRenderedManifests(sub, tp, helmRls, clusters, isAdmin); err != nil {
+		klog.Errorf("subscription %v, err: %v", substr, err)
+	}
+
+	// flag the clusters whose agent stopped reporting, their status in the rollup is not updated anymore
+	r.setAgentNotReportingCondition(sub, clusters, time.Now())
+
+	r.rollupSubscriptionStatus(sub, clusters)
+
 	return err
 }
 
@@ -351,84 +417,7 @@ func (r *ReconcileSubscription) createAppAppsubReport(sub *appv1.Subscription, r
 }
 
 func (r *ReconcileSubscription) initObjectStore(channel *chnv1.Channel) (*awsutils.Handler, string, error) {
-	var err error
-
-	awshandler := &awsutils.Handler{}
-
-	pathName := channel.Spec.Pathname
-
-	if pathName == "" {
-		errmsg := "Empty Pathname in channel " + channel.Spec.Pathname
-		klog.Error(errmsg)
-
-		return nil, "", errors.New(errmsg)
-	}
-
-	if strings.HasSuffix(pathName, "/") {
-		last := len(pathName) - 1
-		pathName = pathName[:last]
-	}
-
-	loc := strings.LastIndex(pathName, "/")
-	endpoint := pathName[:loc]
-	bucket := pathName[loc+1:]
-
-	accessKeyID := ""
-	secretAccessKey := ""
-	region := ""
-
-	if channel.Spec.SecretRef != nil {
-		channelSecret := &v1.Secret{}
-		chnseckey := types.NamespacedName{
-			Name:      channel.Spec.SecretRef.Name,
-			Namespace: channel.Namespace,
-		}
-
-		if err := r.Get(context.TODO(), chnseckey, channelSecret); err != nil {
-			return nil, "", gerr.Wrap(err, "failed to get reference secret from channel")
-		}
-
-		err = yaml.Unmarshal(channelSecret.Data[awsutils.SecretMapKeyAccessKeyID], &accessKeyID)
-		if err != nil {
-			klog.Error("Failed to unmashall accessKey from secret with error:", err)
-
-			return nil, "", err
-		}
-
-		err = yaml.Unmarshal(channelSecret.Data[awsutils.SecretMapKeySecretAccessKey], &secretAccessKey)
-		if err != nil {
-			klog.Error("Failed to unmashall secretaccessKey from secret with error:", err)
-
-			return nil, "", err
-		}
-
-		regionData := channelSecret.Data[awsutils.SecretMapKeyRegion]
-
-		if len(regionData) > 0 {
-			err = yaml.Unmarshal(regionData, &region)
-			if err != nil {
-				klog.Error("Failed to unmashall region from secret with error:", err)
-
-				return nil, "", err
-			}
-		}
-	}
-
-	klog.V(1).Info("Trying to connect to object bucket ", endpoint, "|", bucket)
-
-	if err := awshandler.InitObjectStoreConnection(endpoint, accessKeyID, secretAccessKey, region); err != nil {
-		klog.Error(err, "unable initialize object store settings")
-
-		return nil, "", err
-	}
-	// Check whether the connection is setup successfully
-	if err := awshandler.Exists(bucket); err != nil {
-		klog.Error(err, "Unable to access object store bucket ", bucket, " for channel ", channel.Name)
-
-		return nil, "", err
-	}
-
-	return awshandler, bucket, nil
+	return utils.InitObjectStore(r.Client, channel)
 }
 
 func (r *ReconcileSubscription) getObjectBucketResources(sub *appv1.Subscription, channel, secondaryChannel *chnv1.Channel,
@@ -477,7 +466,20 @@ func (r *ReconcileSubscription) getObjectBucketResources(sub *appv1.Subscription
 
 	resources := []*v1.ObjectReference{}
 
-	for _, key := range keys {
+	objects, err := utils.SelectBucketObjects(sub, keys, bucketPath, func(key string) ([]byte, error) {
+		obj, err := awsHandler.Get(bucket, key)
+
+		return obj.Content, err
+	})
+	if err != nil {
+		klog.Error("Failed to select the objects in bucket ", bucket)
+
+		return nil, err
+	}
+
+	for _, object := range objects {
+		key := object.Key
+
 		tplb, err := awsHandler.Get(bucket, key)
 		if err != nil {
 			klog.Error("Failed to get object ", key, " in bucket ", bucket)
@@ -490,36 +492,52 @@ func (r *ReconcileSubscription) getObjectBucketResources(sub *appv1.Subscription
 			continue
 		}
 
-		template := &unstructured.Unstructured{}
-		err = yaml.Unmarshal(tplb.Content, template)
+		templates := []*unstructured.Unstructured{}
 
-		if err != nil {
-			klog.V(5).Infof("Error in unmarshall template, err:%v |template: %v", err, string(tplb.Content))
-			continue
-		}
+		if utils.IsBucketArchive(key) {
+			templates, _, err = utils.ExpandBucketArchive(sub, key, tplb.Content)
+			if err != nil {
+				klog.Error("Failed to expand object ", key, " in bucket ", bucket, ", err: ", err)
+				errMsgs = append(errMsgs, err.Error())
 
-		resource := &v1.ObjectReference{
-			Kind:       template.GetKind(),
-			Namespace:  template.GetNamespace(),
-			Name:       template.GetName(),
-			APIVersion: template.GetAPIVersion(),
-		}
+				continue
+			}
+		} else {
+			template := &unstructured.Unstructured{}
+			err = yaml.Unmarshal(tplb.Content, template)
 
-		// No need to save the namespace object to the resource list of the appsub
-		if resource.Kind == "Namespace" {
-			continue
+			if err != nil {
+				klog.V(5).Infof("Error in unmarshall template, err:%v |template: %v", err, string(tplb.Content))
+				continue
+			}
+
+			templates = append(templates, template)
 		}
 
-		// respect object customized namespace if the appsub user is subscription admin, or apply it to appsub namespace
-		if isAdmin {
-			if resource.Namespace == "" {
+		for _, template := range templates {
+			resource := &v1.ObjectReference{
+				Kind:       template.GetKind(),
+				Namespace:  template.GetNamespace(),
+				Name:       template.GetName(),
+				APIVersion: template.GetAPIVersion(),
+			}
+
+			// No need to save the namespace object to the resource list of the appsub
+			if resource.Kind == "Namespace" {
+				continue
+			}
+
+			// respect object customized namespace if the appsub user is subscription admin, or apply it to appsub namespace
+			if isAdmin {
+				if resource.Namespace == "" {
+					resource.Namespace = sub.Namespace
+				}
+			} else {
 				resource.Namespace = sub.Namespace
 			}
-		} else {
-			resource.Namespace = sub.Namespace
-		}
 
-		resources = append(resources, resource)
+			resources = append(resources, resource)
+		}
 	}
 
 	if len(errMsgs) > 0 {
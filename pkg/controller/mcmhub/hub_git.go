@@ -82,6 +82,8 @@ type branchInfo struct {
 	gitCloneOptions utils.GitCloneOption
 	lastCommitID    string
 	registeredSub   map[types.NamespacedName]struct{}
+	// the pull request of the preview subscriptions, they are deleted when it is closed
+	pullRequest *pullRequestInfo
 }
 
 type RepoRegistery struct {
@@ -102,6 +104,7 @@ type HubGitOps struct {
 	repoRecords         map[string]*RepoRegistery
 	downloadDirResolver dirResolver
 	cloneFunc           cloneFunc
+	pullRequestState    pullRequestStateFunc
 }
 
 var _ GitOps = (*HubGitOps)(nil)
@@ -132,6 +135,12 @@ func setGetCloneFunc(cFunc cloneFunc) HubGitOption {
 	}
 }
 
+func setPullRequestStateFunc(sFunc pullRequestStateFunc) HubGitOption {
+	return func(a *HubGitOps) {
+		a.pullRequestState = sFunc
+	}
+}
+
 func NewHookGit(clt client.Client, ops ...HubGitOption) *HubGitOps {
 	hGit := &HubGitOps{
 		clt:                 clt,
@@ -141,6 +150,7 @@ func NewHookGit(clt client.Client, ops ...HubGitOption) *HubGitOps {
 		repoRecords:         map[string]*RepoRegistery{},
 		downloadDirResolver: utils.GetLocalGitFolder,
 		cloneFunc:           cloneGitRepoBranch,
+		pullRequestState:    isPullRequestOpen,
 	}
 
 	for _, op := range ops {
@@ -178,12 +188,20 @@ func (h *HubGitOps) GitWatch(ctx context.Context) {
 		url := repoRegistery.url
 		// need to figure out a way to separate the private repo
 		for branchInfoName, branchInfo := range repoRegistery.branchs {
+			if branchInfo.pullRequest != nil && h.teardownClosedPullRequest(branchInfo) {
+				continue
+			}
+
 			// If commitHash is provided, compare this to the currently deployed commit
 			// If tag is provided, resolve tag to commit SHA and compare it to the currently deployed commit
 			// Otherwise, compare the latest commit of the repo branch to the currently deployed commit
 			h.logger.Info(fmt.Sprintf("Checking commit for Git: %s Branch: %s", url, branchInfoName))
+
+			start := time.Now()
 			newCommit, err := h.cloneFunc(&branchInfo.gitCloneOptions)
 
+			h.setChannelGitFetchStatus(&branchInfo.gitCloneOptions, start, newCommit, err)
+
 			if err != nil {
 				h.logger.Error(err, " failed to get the commit SHA")
 			}
@@ -299,6 +317,11 @@ func genBranchString(subIns *subv1.Subscription) string {
 
 	branch, commit, tag, _ := getBranchCommitDepthAndTag(subIns)
 
+	// the preview subscriptions deploy the head of the pull request
+	if pr := an[subv1.AnnotationGitPullRequest]; pr != "" {
+		return "pull-request-" + pr
+	}
+
 	// Honor commit first, then tag, then branch. These are mutually exclusive
 	if commit != "" {
 		return commit
@@ -358,8 +381,8 @@ func (h *HubGitOps) RegisterBranch(subIns *subv1.Subscription) error {
 	h.subRecords[subKey] = repoName
 	subscriptionRepoInfo, ok := h.repoRecords[repoName]
 
-	// Start with git clone depth 0.
-	depthInt := 0
+	// Start with git clone depth 0, or the clone depth of the controller config.
+	depthInt := utils.DefaultGitCloneDepth(0)
 
 	if depth != "" {
 		depthInt2, err2 := strconv.Atoi(depth)
@@ -367,7 +390,7 @@ func (h *HubGitOps) RegisterBranch(subIns *subv1.Subscription) error {
 		if err2 != nil {
 			h.logger.Error(err2, " failed to convert git-clone-depth to integer")
 
-			depthInt2 = 0
+			depthInt2 = utils.DefaultGitCloneDepth(0)
 		}
 
 		depthInt = depthInt2
@@ -392,6 +415,8 @@ func (h *HubGitOps) RegisterBranch(subIns *subv1.Subscription) error {
 		return nil
 	}
 
+	cloneOptions.Channel = types.NamespacedName{Namespace: primaryChannel.Namespace, Name: primaryChannel.Name}
+
 	user, pwd, sshKey, passphrase, clientkey, clientcert, err := utils.GetChannelSecret(h.clt, primaryChannel)
 
 	if err != nil {
@@ -430,6 +455,19 @@ func (h *HubGitOps) RegisterBranch(subIns *subv1.Subscription) error {
 
 	cloneOptions.PrimaryConnectionOption = primaryChannelConnectionConfig
 
+	pr, err := utils.GetSubscriptionPullRequest(subIns, primaryChannel.Spec.Pathname)
+	if err != nil {
+		h.logger.Error(err, "failed to register subscription to git watcher register")
+		return err
+	}
+
+	var prInfo *pullRequestInfo
+
+	if pr != nil {
+		cloneOptions.Branch = pr.Ref()
+		prInfo = &pullRequestInfo{pr: pr, token: pwd, insecureSkipVerify: skipCertVerify}
+	}
+
 	if secondaryChannel != nil {
 		user, pwd, sshKey, passphrase, clientkey, clientcert, err := utils.GetChannelSecret(h.clt, secondaryChannel)
 
@@ -470,7 +508,14 @@ func (h *HubGitOps) RegisterBranch(subIns *subv1.Subscription) error {
 		cloneOptions.SecondaryConnectionOption = secondaryChannelConnectionConfig
 	}
 
+	cloneOptions.MirrorConnectionOptions = utils.GetGitMirrorConnectionConfigs(primaryChannel,
+		utils.FetchGitMirrorSecrets(h.clt, primaryChannel), channelConfig)
+
+	start := time.Now()
 	commitID, err := h.cloneFunc(cloneOptions)
+
+	h.setChannelGitFetchStatus(cloneOptions, start, commitID, err)
+
 	if err != nil {
 		h.logger.Error(err, "failed to get commitID from initialDownload")
 		return err
@@ -487,6 +532,7 @@ func (h *HubGitOps) RegisterBranch(subIns *subv1.Subscription) error {
 					registeredSub: map[types.NamespacedName]struct{}{
 						subKey: {},
 					},
+					pullRequest: prInfo,
 				},
 			},
 		}
@@ -501,6 +547,7 @@ func (h *HubGitOps) RegisterBranch(subIns *subv1.Subscription) error {
 			registeredSub: map[types.NamespacedName]struct{}{
 				subKey: {},
 			},
+			pullRequest: prInfo,
 		}
 
 		return nil
@@ -512,12 +559,27 @@ func (h *HubGitOps) RegisterBranch(subIns *subv1.Subscription) error {
 
 	// Pick up new channel configurations
 	subscriptionRepoInfo.branchs[branchInfoName].gitCloneOptions = *cloneOptions
+	subscriptionRepoInfo.branchs[branchInfoName].pullRequest = prInfo
 
 	subscriptionRepoInfo.branchs[branchInfoName].registeredSub[subKey] = struct{}{}
 
 	return nil
 }
 
+// setChannelGitFetchStatus sets the outcome of the git clone in the lastGitFetch status of its channel
+func (h *HubGitOps) setChannelGitFetchStatus(cloneOptions *utils.GitCloneOption, start time.Time, commit string, err error) {
+	repoURL := ""
+	if cloneOptions.PrimaryConnectionOption != nil {
+		repoURL = cloneOptions.PrimaryConnectionOption.RepoURL
+	}
+
+	status := utils.NewGitFetchStatus(repoURL, start, commit, err)
+
+	if err := utils.SetChannelGitFetchStatus(h.clt, cloneOptions.Channel, status); err != nil {
+		h.logger.Error(err, "failed to set the git fetch status of channel "+cloneOptions.Channel.String())
+	}
+}
+
 func fakeCommitID(c string) string {
 	return fmt.Sprintf("%s%s", c, commitIDSuffix)
 }
@@ -558,6 +620,8 @@ func (h *HubGitOps) DeregisterBranch(subKey types.NamespacedName) {
 		delete(h.repoRecords[repoName].branchs[bName].registeredSub, subKey)
 
 		if len(h.repoRecords[repoName].branchs[bName].registeredSub) == 0 {
+			utils.ForgetCacheSize(utils.CacheGitClone, h.repoRecords[repoName].branchs[bName].gitCloneOptions.DestDir)
+
 			delete(h.repoRecords[repoName].branchs, bName)
 		}
 
@@ -613,7 +677,13 @@ func (h *HubGitOps) DownloadAnsibleHookResource(subIns *subv1.Subscription) erro
 }
 
 func cloneGitRepoBranch(cloneOptions *utils.GitCloneOption) (string, error) {
-	return utils.CloneGitRepo(cloneOptions)
+	commitID, err := utils.CloneGitRepo(cloneOptions)
+
+	if err == nil && utils.IsProfilingEnabled() {
+		utils.RecordCacheSize(utils.CacheGitClone, cloneOptions.DestDir, utils.DirSize(cloneOptions.DestDir))
+	}
+
+	return commitID, err
 }
 
 type gitSortResult struct {
@@ -0,0 +1,163 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	appSubV1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+// the suffix of the subscriptions deployed to the hub, like the subscriptions propagated to the local-cluster
+const hubLocalSuffix = "-local"
+
+// hubCluster is the cluster the subscriptions with the hub placement are deployed to
+func hubCluster() ManageClusters {
+	return ManageClusters{Cluster: utils.HubCluster, IsLocalCluster: true, IsHub: true}
+}
+
+// hubLocalSubscriptionKey returns the key of the subscription deployed to the hub by a subscription
+func hubLocalSubscriptionKey(hosting types.NamespacedName) types.NamespacedName {
+	return types.NamespacedName{Namespace: hosting.Namespace, Name: hosting.Name + hubLocalSuffix}
+}
+
+// applyHubLocalSubscription creates or updates the local subscription of a subscription with the hub placement. It is
+// the subscription a ManifestWork would propagate to the local-cluster, the standalone subscription controller of the
+// hub applies it and reports its status like the agents of the managed clusters
+func (r *ReconcileSubscription) applyHubLocalSubscription(cluster ManageClusters, hosting types.NamespacedName,
+	instance *appSubV1.Subscription) error {
+	if err := r.ensureHubClusterNamespace(); err != nil {
+		return err
+	}
+
+	appsubString := manifestAppsubString
+
+	if clusterAppsub := getClusterSubscription(instance, cluster); clusterAppsub != nil {
+		var err error
+
+		appsubString, err = r.prepareManifestWorkAppsub(clusterAppsub, hosting)
+		if err != nil {
+			return err
+		}
+	}
+
+	localSub := &appSubV1.Subscription{}
+	if err := json.Unmarshal([]byte(appsubString), localSub); err != nil {
+		return err
+	}
+
+	key := hubLocalSubscriptionKey(hosting)
+
+	localSub.SetName(key.Name)
+
+	annotations := localSub.GetAnnotations()
+	annotations[appSubV1.AnnotationHubLocal] = "true"
+	localSub.SetAnnotations(annotations)
+
+	existing := &appSubV1.Subscription{}
+	if err := r.Get(context.TODO(), key, existing); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+
+		err = r.Create(context.TODO(), localSub)
+		klog.Infof("Creating hub-local subscription: %v, err: %v", key.String(), err)
+
+		return err
+	}
+
+	if !utils.IsHubLocalAppsub(existing) || existing.GetAnnotations()[appSubV1.AnnotationHosting] != hosting.String() {
+		return fmt.Errorf("subscription %v already exists and isn't deployed to the hub by subscription %v", key.String(),
+			hosting.String())
+	}
+
+	updated := existing.DeepCopy()
+	updated.Spec = localSub.Spec
+	updated.SetLabels(localSub.GetLabels())
+
+	// the annotations set on the hub-local subscription by the subscription controller are kept
+	updatedAnnotations := updated.GetAnnotations()
+	for k, v := range localSub.GetAnnotations() {
+		updatedAnnotations[k] = v
+	}
+
+	updated.SetAnnotations(updatedAnnotations)
+
+	if equality.Semantic.DeepEqual(existing.Spec, updated.Spec) &&
+		equality.Semantic.DeepEqual(existing.GetLabels(), updated.GetLabels()) &&
+		equality.Semantic.DeepEqual(existing.GetAnnotations(), updated.GetAnnotations()) {
+		klog.Infof("Same existing hub-local subscription, no need to update: %v", key.String())
+
+		return nil
+	}
+
+	err := r.Update(context.TODO(), updated)
+	klog.Infof("Updating existing hub-local subscription: %v, err: %v", key.String(), err)
+
+	return err
+}
+
+// deleteHubLocalSubscription deletes the local subscription of a subscription once it isn't deployed to the hub anymore
+func (r *ReconcileSubscription) deleteHubLocalSubscription(hosting types.NamespacedName) error {
+	key := hubLocalSubscriptionKey(hosting)
+
+	existing := &appSubV1.Subscription{}
+	if err := r.Get(context.TODO(), key, existing); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	if !utils.IsHubLocalAppsub(existing) || existing.GetAnnotations()[appSubV1.AnnotationHosting] != hosting.String() {
+		return nil
+	}
+
+	if err := r.Delete(context.TODO(), existing); err != nil && !errors.IsNotFound(err) {
+		klog.Warningf("Error in deleting hub-local subscription: %v, err: %v", key.String(), err)
+
+		return err
+	}
+
+	klog.Infof("hub-local subscription deleted: %v", key.String())
+
+	return nil
+}
+
+// ensureHubClusterNamespace creates the namespace of the hub cluster report when the hub isn't a managed cluster
+func (r *ReconcileSubscription) ensureHubClusterNamespace() error {
+	ns := &coreV1.Namespace{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: utils.HubCluster}, ns); err == nil || !errors.IsNotFound(err) {
+		return err
+	}
+
+	ns = &coreV1.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: utils.HubCluster}}
+	if err := r.Create(context.TODO(), ns); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	return nil
+}
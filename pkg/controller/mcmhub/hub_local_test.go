@@ -0,0 +1,122 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	manifestWorkV1 "open-cluster-management.io/api/work/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	plrv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+func TestHubLocalSubscription(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(appv1.SchemeBuilder.AddToScheme(scheme)).To(gomega.Succeed())
+	g.Expect(v1.AddToScheme(scheme)).To(gomega.Succeed())
+	g.Expect(manifestWorkV1.AddToScheme(scheme)).To(gomega.Succeed())
+
+	hub := true
+	local := true
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns", Annotations: map[string]string{}},
+		Spec: appv1.SubscriptionSpec{
+			Channel:   "chn-ns/git",
+			Placement: &plrv1.Placement{Hub: &hub},
+			TimeWindow: &appv1.TimeWindow{
+				WindowType: "active",
+				Daysofweek: []string{"Monday"},
+			},
+		},
+	}
+
+	// the hub placement is exclusive
+	g.Expect(utils.ValidatePlacement(sub)).To(gomega.Succeed())
+	g.Expect(utils.ValidatePlacement(&appv1.Subscription{Spec: appv1.SubscriptionSpec{
+		Placement: &plrv1.Placement{Hub: &hub, Local: &local}}})).NotTo(gomega.Succeed())
+	g.Expect(utils.ValidatePlacement(&appv1.Subscription{Spec: appv1.SubscriptionSpec{
+		Placement: &plrv1.Placement{Hub: &hub, GenericPlacementFields: plrv1.GenericPlacementFields{
+			Clusters: []plrv1.GenericClusterReference{{Name: "cluster1"}}}}}})).NotTo(gomega.Succeed())
+
+	clt := fake.NewClientBuilder().WithScheme(scheme).WithObjects(sub).Build()
+	r := &ReconcileSubscription{Client: clt}
+
+	clusters, err := r.getClustersByPlacement(sub)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(clusters).To(gomega.Equal([]ManageClusters{{Cluster: utils.HubCluster, IsLocalCluster: true, IsHub: true}}))
+
+	// the hub-local subscription is created without a ManifestWork, with the namespace of the hub cluster report
+	_, err = r.propagateManifestWorks(clusters, sub, map[string]*manifestWorkV1.ManifestWork{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	hubLocal := func() *appv1.Subscription {
+		got := &appv1.Subscription{}
+		g.Expect(clt.Get(context.TODO(), types.NamespacedName{Name: "demo-local", Namespace: "demo-ns"}, got)).To(gomega.Succeed())
+
+		return got
+	}
+
+	got := hubLocal()
+	g.Expect(utils.IsHubLocalAppsub(got)).To(gomega.BeTrue())
+	g.Expect(got.GetAnnotations()[appv1.AnnotationHosting]).To(gomega.Equal("demo-ns/demo"))
+	g.Expect(*got.Spec.Placement.Local).To(gomega.BeTrue())
+	g.Expect(got.Spec.Placement.Hub).To(gomega.BeNil())
+	g.Expect(got.Spec.TimeWindow).To(gomega.Equal(sub.Spec.TimeWindow))
+
+	g.Expect(clt.Get(context.TODO(), types.NamespacedName{Name: utils.HubCluster}, &v1.Namespace{})).To(gomega.Succeed())
+
+	manifestWorks := &manifestWorkV1.ManifestWorkList{}
+	g.Expect(clt.List(context.TODO(), manifestWorks)).To(gomega.Succeed())
+	g.Expect(manifestWorks.Items).To(gomega.BeEmpty())
+
+	// the hub-local subscription follows the subscription, the annotations of the subscription controller are kept
+	got.Annotations[appv1.AnnotationTopo] = "topo"
+	g.Expect(clt.Update(context.TODO(), got)).To(gomega.Succeed())
+
+	sub.Spec.Channel = "chn-ns/helm"
+
+	_, err = r.propagateManifestWorks(clusters, sub, map[string]*manifestWorkV1.ManifestWork{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(hubLocal().Spec.Channel).To(gomega.Equal("chn-ns/helm"))
+	g.Expect(hubLocal().GetAnnotations()[appv1.AnnotationTopo]).To(gomega.Equal("topo"))
+
+	// the subscriptions not deployed by the subscription are neither updated nor deleted
+	other := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "other-local", Namespace: "demo-ns"}}
+	g.Expect(clt.Create(context.TODO(), other)).To(gomega.Succeed())
+
+	otherKey := types.NamespacedName{Name: "other", Namespace: "demo-ns"}
+	g.Expect(r.applyHubLocalSubscription(hubCluster(), otherKey, sub)).NotTo(gomega.Succeed())
+	g.Expect(r.deleteHubLocalSubscription(otherKey)).To(gomega.Succeed())
+	g.Expect(clt.Get(context.TODO(), types.NamespacedName{Name: "other-local", Namespace: "demo-ns"}, other)).To(gomega.Succeed())
+
+	// the hub-local subscription is deleted with the subscription
+	g.Expect(r.cleanupManifestWork(types.NamespacedName{Name: "demo", Namespace: "demo-ns"})).To(gomega.Succeed())
+
+	err = clt.Get(context.TODO(), types.NamespacedName{Name: "demo-local", Namespace: "demo-ns"}, &appv1.Subscription{})
+	g.Expect(errors.IsNotFound(err)).To(gomega.BeTrue())
+}
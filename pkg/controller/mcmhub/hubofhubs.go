@@ -0,0 +1,404 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	spokeClusterV1 "open-cluster-management.io/api/cluster/v1"
+	clusterapi "open-cluster-management.io/api/cluster/v1beta1"
+	manifestWorkV1 "open-cluster-management.io/api/work/v1"
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	placementV1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
+	appSubV1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appSubStatusV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+)
+
+// In hub-of-hubs mode, the top-level hub propagates the hub form of the subscription, together with its channels and
+// placement, to the managed clusters labeled as regional hubs. The regional hubs then propagate the subscription to
+// their own managed clusters. Each regional hub rolls up the results of its clusters into status.rollupSummary of its
+// subscription, which is sent back to the top-level hub through the manifestWork status feedback.
+
+var rollupFeedbackFields = []string{"deployed", "inProgress", "failed", "propagationFailed", "clusters"}
+
+// isHubOfHubsSub checks if the subscription is propagated to regional hubs as hub subscriptions
+func isHubOfHubsSub(sub *appSubV1.Subscription) bool {
+	return strings.EqualFold(sub.GetAnnotations()[appSubV1.AnnotationHubOfHubs], "true")
+}
+
+// isRegionalHubSub checks if the subscription has been propagated from a top-level hub
+func isRegionalHubSub(sub *appSubV1.Subscription) bool {
+	return sub.GetAnnotations()[appSubV1.AnnotationHubOfHubsParent] != ""
+}
+
+func isRegionalHubByLabels(clusterLabels map[string]string) bool {
+	if clusterLabels == nil {
+		return false
+	}
+
+	return strings.EqualFold(clusterLabels[appSubV1.LabelRegionalHub], "true")
+}
+
+func (r *ReconcileSubscription) isRegionalHubCluster(clusterName string) bool {
+	managedCluster := &spokeClusterV1.ManagedCluster{}
+
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: clusterName}, managedCluster); err != nil {
+		klog.Errorf("Failed to find managed cluster: %v, error: %v ", clusterName, err)
+		return false
+	}
+
+	return isRegionalHubByLabels(managedCluster.GetLabels())
+}
+
+// prepareRegionalHubAppsub builds the hub form of the subscription for the regional hub
+func prepareRegionalHubAppsub(appsub *appSubV1.Subscription, hosting types.NamespacedName) *appSubV1.Subscription {
+	subep := &appSubV1.Subscription{
+		TypeMeta: metaV1.TypeMeta{
+			Kind:       "Subscription",
+			APIVersion: "apps.open-cluster-management.io/v1",
+		},
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      appsub.GetName(),
+			Namespace: appsub.GetNamespace(),
+			Labels:    appsub.GetLabels(),
+		},
+		Spec: *appsub.Spec.DeepCopy(),
+	}
+
+	subepanno := map[string]string{}
+
+	for k, v := range appsub.GetAnnotations() {
+		subepanno[k] = v
+	}
+
+	// The regional hub computes these on its own. The regional hub doesn't propagate to another level of hubs. The
+	// agents of the regional hub don't sync to the top-level hub, the hub name is the one of the regional hub.
+	for _, k := range []string{appSubV1.AnnotationHubOfHubs, appSubV1.AnnotationHosting, appSubV1.AnnotationDeployables,
+		appSubV1.AnnotationTopo, appSubV1.AnnotationGitCommit, appSubV1.AnnotationHubName,
+		"kubectl.kubernetes.io/last-applied-configuration"} {
+		delete(subepanno, k)
+	}
+
+	subepanno[appSubV1.AnnotationHubOfHubsParent] = hosting.String()
+	subep.SetAnnotations(subepanno)
+
+	return subep
+}
+
+// prepareRegionalHubManifests returns all the manifests the regional hub needs to propagate the subscription on its own:
+// the subscription namespace, the channels with their namespaces, secrets and configmaps, the placement and the subscription
+func (r *ReconcileSubscription) prepareRegionalHubManifests(appsub *appSubV1.Subscription, hosting types.NamespacedName) ([]manifestWorkV1.Manifest, error) {
+	objs := []runtime.Object{}
+	namespaces := map[string]bool{}
+
+	addNamespace := func(ns string) {
+		if ns == "" || namespaces[ns] {
+			return
+		}
+
+		namespaces[ns] = true
+
+		objs = append(objs, &coreV1.Namespace{
+			TypeMeta: metaV1.TypeMeta{Kind: "Namespace", APIVersion: "v1"},
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:        ns,
+				Annotations: map[string]string{appSubV1.AnnotationHosting: hosting.String()},
+			},
+		})
+	}
+
+	addNamespace(appsub.GetNamespace())
+
+	primaryChannel, secondaryChannel, err := r.getChannel(appsub)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, chn := range []*chnv1.Channel{primaryChannel, secondaryChannel} {
+		if chn == nil {
+			continue
+		}
+
+		addNamespace(chn.GetNamespace())
+
+		refObjs, err := r.getChannelReferredObjects(chn)
+		if err != nil {
+			return nil, err
+		}
+
+		objs = append(objs, refObjs...)
+
+		objs = append(objs, &chnv1.Channel{
+			TypeMeta: metaV1.TypeMeta{Kind: "Channel", APIVersion: chnv1.SchemeGroupVersion.String()},
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:        chn.GetName(),
+				Namespace:   chn.GetNamespace(),
+				Labels:      chn.GetLabels(),
+				Annotations: chn.GetAnnotations(),
+			},
+			Spec: *chn.Spec.DeepCopy(),
+		})
+	}
+
+	placementObj, err := r.getRegionalHubPlacement(appsub)
+	if err != nil {
+		return nil, err
+	}
+
+	if placementObj != nil {
+		objs = append(objs, placementObj)
+	}
+
+	objs = append(objs, prepareRegionalHubAppsub(appsub, hosting))
+
+	manifests := []manifestWorkV1.Manifest{}
+
+	for _, obj := range objs {
+		raw, err := json.Marshal(obj)
+		if err != nil {
+			klog.Info("Error in mashalling regional hub obj ", err)
+			return nil, err
+		}
+
+		manifests = append(manifests, manifestWorkV1.Manifest{RawExtension: runtime.RawExtension{Raw: raw}})
+	}
+
+	return manifests, nil
+}
+
+// getChannelReferredObjects returns the copies of the secret and configmap referred by the channel
+func (r *ReconcileSubscription) getChannelReferredObjects(chn *chnv1.Channel) ([]runtime.Object, error) {
+	objs := []runtime.Object{}
+
+	if chn.Spec.SecretRef != nil {
+		secret := &coreV1.Secret{}
+		key := types.NamespacedName{Name: chn.Spec.SecretRef.Name, Namespace: chn.GetNamespace()}
+
+		if err := r.Get(context.TODO(), key, secret); err != nil {
+			klog.Errorf("failed to get channel secret %v, err: %v", key.String(), err)
+			return nil, err
+		}
+
+		objs = append(objs, &coreV1.Secret{
+			TypeMeta:   metaV1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+			ObjectMeta: metaV1.ObjectMeta{Name: secret.GetName(), Namespace: secret.GetNamespace(), Labels: secret.GetLabels()},
+			Type:       secret.Type,
+			Data:       secret.Data,
+		})
+	}
+
+	if chn.Spec.ConfigMapRef != nil {
+		cm := &coreV1.ConfigMap{}
+		key := types.NamespacedName{Name: chn.Spec.ConfigMapRef.Name, Namespace: chn.GetNamespace()}
+
+		if err := r.Get(context.TODO(), key, cm); err != nil {
+			klog.Errorf("failed to get channel configmap %v, err: %v", key.String(), err)
+			return nil, err
+		}
+
+		objs = append(objs, &coreV1.ConfigMap{
+			TypeMeta:   metaV1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+			ObjectMeta: metaV1.ObjectMeta{Name: cm.GetName(), Namespace: cm.GetNamespace(), Labels: cm.GetLabels()},
+			Data:       cm.Data,
+		})
+	}
+
+	return objs, nil
+}
+
+// getRegionalHubPlacement returns the copy of the placement or placementrule referred by the subscription.
+// The ManagedClusterSetBindings required by the placement have to be set up on the regional hub beforehand.
+func (r *ReconcileSubscription) getRegionalHubPlacement(appsub *appSubV1.Subscription) (runtime.Object, error) {
+	if appsub.Spec.Placement == nil || appsub.Spec.Placement.PlacementRef == nil {
+		return nil, nil
+	}
+
+	ref := appsub.Spec.Placement.PlacementRef
+	key := types.NamespacedName{Name: ref.Name, Namespace: appsub.GetNamespace()}
+
+	if strings.EqualFold(ref.Kind, "Placement") {
+		placement := &clusterapi.Placement{}
+		if err := r.Get(context.TODO(), key, placement); err != nil {
+			klog.Errorf("failed to get placement %v, err: %v", key.String(), err)
+			return nil, err
+		}
+
+		return &clusterapi.Placement{
+			TypeMeta:   metaV1.TypeMeta{Kind: "Placement", APIVersion: clusterapi.GroupVersion.String()},
+			ObjectMeta: metaV1.ObjectMeta{Name: placement.GetName(), Namespace: placement.GetNamespace(), Labels: placement.GetLabels()},
+			Spec:       *placement.Spec.DeepCopy(),
+		}, nil
+	}
+
+	placementRule := &placementV1.PlacementRule{}
+	if err := r.Get(context.TODO(), key, placementRule); err != nil {
+		klog.Errorf("failed to get placementRule %v, err: %v", key.String(), err)
+		return nil, err
+	}
+
+	return &placementV1.PlacementRule{
+		TypeMeta:   metaV1.TypeMeta{Kind: "PlacementRule", APIVersion: placementV1.SchemeGroupVersion.String()},
+		ObjectMeta: metaV1.ObjectMeta{Name: placementRule.GetName(), Namespace: placementRule.GetNamespace(), Labels: placementRule.GetLabels()},
+		Spec:       *placementRule.Spec.DeepCopy(),
+	}, nil
+}
+
+// regionalHubManifestConfigs returns the status feedback rules syncing the rollup summary of the regional hub subscription back
+func regionalHubManifestConfigs(appsub *appSubV1.Subscription) []manifestWorkV1.ManifestConfigOption {
+	jsonPaths := []manifestWorkV1.JsonPath{}
+
+	for _, field := range rollupFeedbackFields {
+		jsonPaths = append(jsonPaths, manifestWorkV1.JsonPath{Name: field, Path: ".rollupSummary." + field})
+	}
+
+	return []manifestWorkV1.ManifestConfigOption{
+		{
+			ResourceIdentifier: manifestWorkV1.ResourceIdentifier{
+				Group:     appSubV1.SchemeGroupVersion.Group,
+				Resource:  "subscriptions",
+				Name:      appsub.GetName(),
+				Namespace: appsub.GetNamespace(),
+			},
+			FeedbackRules: []manifestWorkV1.FeedbackRule{
+				{
+					Type:      manifestWorkV1.JSONPathsType,
+					JsonPaths: jsonPaths,
+				},
+			},
+			UpdateStrategy: &manifestWorkV1.UpdateStrategy{
+				Type: manifestWorkV1.UpdateStrategyTypeUpdate,
+			},
+		},
+	}
+}
+
+// getRegionalHubFeedback reads the rollup summary reported back by the regional hub through the manifestWork status
+func getRegionalHubFeedback(manifestWork *manifestWorkV1.ManifestWork, appsub *appSubV1.Subscription) *appSubV1.SubscriptionRollupSummary {
+	for _, manifest := range manifestWork.Status.ResourceStatus.Manifests {
+		meta := manifest.ResourceMeta
+		if meta.Kind != "Subscription" || meta.Name != appsub.GetName() || meta.Namespace != appsub.GetNamespace() {
+			continue
+		}
+
+		if len(manifest.StatusFeedbacks.Values) == 0 {
+			return nil
+		}
+
+		summary := &appSubV1.SubscriptionRollupSummary{}
+
+		for _, v := range manifest.StatusFeedbacks.Values {
+			if v.Value.Integer == nil {
+				continue
+			}
+
+			switch v.Name {
+			case "deployed":
+				summary.Deployed = *v.Value.Integer
+			case "inProgress":
+				summary.InProgress = *v.Value.Integer
+			case "failed":
+				summary.Failed = *v.Value.Integer
+			case "propagationFailed":
+				summary.PropagationFailed = *v.Value.Integer
+			case "clusters":
+				summary.Clusters = *v.Value.Integer
+			}
+		}
+
+		return summary
+	}
+
+	return nil
+}
+
+func parseSummaryCount(count string) int64 {
+	n, err := strconv.ParseInt(count, 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return n
+}
+
+// rollupSubscriptionStatus computes status.rollupSummary from the app appsubReport of the directly managed clusters,
+// where each regional hub is replaced by the summary it reports back. A regional hub without feedback yet stays in progress.
+func (r *ReconcileSubscription) rollupSubscriptionStatus(sub *appSubV1.Subscription, clusters []ManageClusters) {
+	if !isHubOfHubsSub(sub) && !isRegionalHubSub(sub) {
+		sub.Status.RollupSummary = nil
+
+		return
+	}
+
+	summary := &appSubV1.SubscriptionRollupSummary{Clusters: int64(len(clusters)), InProgress: int64(len(clusters))}
+
+	appsubReport := &appSubStatusV1alpha1.SubscriptionReport{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: sub.Name, Namespace: sub.Namespace}, appsubReport); err == nil {
+		summary.Deployed = parseSummaryCount(appsubReport.Summary.Deployed)
+		summary.InProgress = parseSummaryCount(appsubReport.Summary.InProgress)
+		summary.Failed = parseSummaryCount(appsubReport.Summary.Failed)
+		summary.PropagationFailed = parseSummaryCount(appsubReport.Summary.PropagationFailed)
+		summary.Clusters = parseSummaryCount(appsubReport.Summary.Clusters)
+	} else if !errors.IsNotFound(err) {
+		klog.Errorf("failed to get app appsubReport %v/%v, err: %v", sub.Namespace, sub.Name, err)
+	}
+
+	if isHubOfHubsSub(sub) {
+		for _, cluster := range clusters {
+			if !cluster.IsRegionalHub {
+				continue
+			}
+
+			manifestWork := &manifestWorkV1.ManifestWork{}
+			key := types.NamespacedName{Name: sub.GetNamespace() + "-" + sub.GetName(), Namespace: cluster.Cluster}
+
+			if err := r.Get(context.TODO(), key, manifestWork); err != nil {
+				klog.V(1).Infof("failed to get regional hub manifestWork %v, err: %v", key.String(), err)
+
+				continue
+			}
+
+			feedback := getRegionalHubFeedback(manifestWork, sub)
+			if feedback == nil {
+				continue
+			}
+
+			// the regional hub itself is counted as one in-progress cluster in the app appsubReport
+			summary.Clusters += feedback.Clusters - 1
+			summary.InProgress += feedback.InProgress - 1
+			summary.Deployed += feedback.Deployed
+			summary.Failed += feedback.Failed
+			summary.PropagationFailed += feedback.PropagationFailed
+		}
+	}
+
+	if summary.InProgress < 0 {
+		summary.InProgress = 0
+	}
+
+	if summary.Clusters < 0 {
+		summary.Clusters = 0
+	}
+
+	sub.Status.RollupSummary = summary
+}
@@ -0,0 +1,91 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	manifestWorkV1 "open-cluster-management.io/api/work/v1"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func TestPrepareRegionalHubAppsub(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "demo",
+			Namespace: "demo-ns",
+			Annotations: map[string]string{
+				appv1.AnnotationHubOfHubs:   "true",
+				appv1.AnnotationDeployables: "demo-ns/dpl",
+				appv1.AnnotationGitBranch:   "main",
+				appv1.AnnotationHubName:     "top-hub",
+			},
+		},
+		Spec: appv1.SubscriptionSpec{
+			Channel: "ch-ns/ch",
+		},
+	}
+
+	g.Expect(isHubOfHubsSub(sub)).To(gomega.BeTrue())
+
+	regional := prepareRegionalHubAppsub(sub, types.NamespacedName{Name: "demo", Namespace: "demo-ns"})
+	g.Expect(regional.Spec.Channel).To(gomega.Equal("ch-ns/ch"))
+	g.Expect(regional.GetAnnotations()).NotTo(gomega.HaveKey(appv1.AnnotationHubOfHubs))
+	g.Expect(regional.GetAnnotations()).NotTo(gomega.HaveKey(appv1.AnnotationDeployables))
+	g.Expect(regional.GetAnnotations()).NotTo(gomega.HaveKey(appv1.AnnotationHubName))
+	g.Expect(regional.GetAnnotations()[appv1.AnnotationGitBranch]).To(gomega.Equal("main"))
+	g.Expect(regional.GetAnnotations()[appv1.AnnotationHubOfHubsParent]).To(gomega.Equal("demo-ns/demo"))
+	g.Expect(isRegionalHubSub(regional)).To(gomega.BeTrue())
+	g.Expect(isHubOfHubsSub(regional)).To(gomega.BeFalse())
+}
+
+func TestGetRegionalHubFeedback(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	sub := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns"}}
+
+	deployed := int64(8)
+	clusters := int64(10)
+
+	mw := &manifestWorkV1.ManifestWork{}
+	mw.Status.ResourceStatus.Manifests = []manifestWorkV1.ManifestCondition{
+		{
+			ResourceMeta: manifestWorkV1.ManifestResourceMeta{Kind: "Channel", Name: "ch", Namespace: "ch-ns"},
+		},
+		{
+			ResourceMeta: manifestWorkV1.ManifestResourceMeta{Kind: "Subscription", Name: "demo", Namespace: "demo-ns"},
+			StatusFeedbacks: manifestWorkV1.StatusFeedbackResult{
+				Values: []manifestWorkV1.FeedbackValue{
+					{Name: "deployed", Value: manifestWorkV1.FieldValue{Type: manifestWorkV1.Integer, Integer: &deployed}},
+					{Name: "clusters", Value: manifestWorkV1.FieldValue{Type: manifestWorkV1.Integer, Integer: &clusters}},
+				},
+			},
+		},
+	}
+
+	summary := getRegionalHubFeedback(mw, sub)
+	g.Expect(summary).NotTo(gomega.BeNil())
+	g.Expect(summary.Deployed).To(gomega.Equal(int64(8)))
+	g.Expect(summary.Clusters).To(gomega.Equal(int64(10)))
+	g.Expect(summary.Failed).To(gomega.Equal(int64(0)))
+
+	g.Expect(getRegionalHubFeedback(&manifestWorkV1.ManifestWork{}, sub)).To(gomega.BeNil())
+}
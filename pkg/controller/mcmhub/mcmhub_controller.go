@@ -22,6 +22,7 @@ import (
 	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -35,6 +36,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
@@ -90,7 +92,16 @@ var defaulRequeueInterval = time.Second * 3
 // Add creates a new Subscription Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager) error {
-	return add(mgr, newReconciler(mgr))
+	if err := add(mgr, newReconciler(mgr)); err != nil {
+		return err
+	}
+
+	// in hub, roll out the agent upgrades
+	if utils.IsReadyAgentUpgrade(mgr.GetAPIReader()) {
+		return addAgentUpgrade(mgr)
+	}
+
+	return nil
 }
 
 // Option provide easy way to test the reconciler
@@ -107,11 +118,13 @@ func newReconciler(mgr manager.Manager, op ...Option) reconcile.Reconciler {
 	erecorder, _ := utils.NewEventRecorder(mgr.GetConfig(), mgr.GetScheme())
 	logger := klogr.New().WithName(reconcileName)
 
-	gitOps := NewHookGit(mgr.GetClient(), setHubGitOpsLogger(logger))
+	hubClient := utils.ControllerClient(mgr, "mcmhub-subscription-controller")
+
+	gitOps := NewHookGit(hubClient, setHubGitOpsLogger(logger))
 
 	rec := &ReconcileSubscription{
 		name:   reconcileName,
-		Client: mgr.GetClient(),
+		Client: hubClient,
 		// used for the helm to run get the resource list
 		cfg:                 mgr.GetConfig(),
 		scheme:              mgr.GetScheme(),
@@ -119,9 +132,10 @@ func newReconciler(mgr manager.Manager, op ...Option) reconcile.Reconciler {
 		eventRecorder:       erecorder,
 		logger:              logger,
 		hookRequeueInterval: defaultHookRequeueInterval,
-		hooks:               NewAnsibleHooks(mgr.GetClient(), defaultHookRequeueInterval, setLogger(logger), setGitOps(gitOps)),
+		hooks:               NewAnsibleHooks(hubClient, defaultHookRequeueInterval, setLogger(logger), setGitOps(gitOps)),
 		hubGitOps:           gitOps,
 		clk:                 time.Now,
+		accessReviews:       newAccessReviewCache(),
 	}
 
 	for _, f := range op {
@@ -358,6 +372,65 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		}
 	}
 
+	// in hub, watch for subscription quota changes
+	if utils.IsReadySubscriptionQuota(mgr.GetAPIReader()) {
+		qMapper := &quotaMapper{mgr.GetClient()}
+		err = c.Watch(
+			&source.Kind{Type: &appSubStatusV1alpha1.SubscriptionQuota{}},
+			handler.EnqueueRequestsFromMapFunc(qMapper.Map), predicate.GenerationChangedPredicate{})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	// in hub, watch for change freeze changes
+	if utils.IsReadyChangeFreeze(mgr.GetAPIReader()) {
+		cfMapper := &changeFreezeMapper{mgr.GetClient()}
+		err = c.Watch(
+			&source.Kind{Type: &appSubStatusV1alpha1.ChangeFreeze{}},
+			handler.EnqueueRequestsFromMapFunc(cfMapper.Map), predicate.GenerationChangedPredicate{})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	// in hub, watch for the changes of the ConfigMaps and Secrets read by the hub templates, only their metadata is cached
+	trWatcher := newTemplateRefWatcher(mgr.GetClient(), mgr.GetAPIReader())
+
+	for _, kind := range []string{"ConfigMap", "Secret"} {
+		obj := &metav1.PartialObjectMetadata{}
+		obj.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind(kind))
+
+		if err := c.Watch(&source.Kind{Type: obj}, trWatcher.handler(kind)); err != nil {
+			return err
+		}
+	}
+
+	// in hub, watch for the agents whose heartbeat lease expires or is renewed again
+	heartbeatMonitor := newAgentHeartbeatMonitor(mgr.GetClient())
+	if err := mgr.Add(heartbeatMonitor); err != nil {
+		return err
+	}
+
+	err = c.Watch(&source.Channel{Source: heartbeatMonitor.events}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// in hub, watch for the decisions of the promotion approval requests
+	if utils.IsReadyApprovalRequest(mgr.GetAPIReader()) {
+		arMapper := &approvalRequestMapper{mgr.GetClient()}
+		err = c.Watch(
+			&source.Kind{Type: &appSubStatusV1alpha1.ApprovalRequest{}},
+			handler.EnqueueRequestsFromMapFunc(arMapper.Map), predicate.GenerationChangedPredicate{})
+
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -381,6 +454,8 @@ type ReconcileSubscription struct {
 	hubGitOps           GitOps
 	restMapper          meta.RESTMapper
 	clk                 clock
+	accessReviewer      accessReviewer
+	accessReviews       *accessReviewCache
 }
 
 // CreateSubscriptionAdminRBAC checks existence of subscription-admin clusterrole and clusterrolebinding
@@ -516,6 +591,10 @@ func (r *ReconcileSubscription) Reconcile(ctx context.Context, request reconcile
 				klog.Warning("error while cleanup manifestwork ", cleanupErr)
 			}
 
+			if cleanupErr := r.cleanupRenderedManifests(request.NamespacedName, nil); cleanupErr != nil {
+				klog.Warning("error while cleanup rendered manifests ", cleanupErr)
+			}
+
 			// Object not found, delete existing subscriberitem if any
 			if err := r.hooks.DeregisterSubscription(request.NamespacedName); err != nil {
 				return reconcile.Result{}, err
@@ -538,22 +617,24 @@ func (r *ReconcileSubscription) Reconcile(ctx context.Context, request reconcile
 	klog.V(2).Infof("Subscription: %v with placement %#v", request.NamespacedName.String(), pl)
 
 	//status changes below show override the prehook status
-	if pl == nil {
+	if err := utils.ValidatePlacement(instance); err != nil {
+		logger.Info(fmt.Sprintf("invalid placement, err: %v", err))
 		instance.Status.Phase = appv1.SubscriptionPropagationFailed
-		instance.Status.Reason = "Placement must be specified"
+		instance.Status.Reason = err.Error()
 
 		metrics.PropagationFailedPullTime.
 			WithLabelValues(instance.Namespace, instance.Name).
 			Observe(0)
-	} else if pl != nil && (pl.PlacementRef != nil || pl.Clusters != nil || pl.ClusterSelector != nil) && (pl.Local != nil && *pl.Local) {
-		logger.Info("both local placement and remote placement are defined in the subscription")
+	} else if err := utils.ValidatePackagePatches(instance); err != nil {
+		logger.Info(fmt.Sprintf("invalid package patches, err: %v", err))
 		instance.Status.Phase = appv1.SubscriptionPropagationFailed
-		instance.Status.Reason = "local placement and remote placement cannot be used together"
+		instance.Status.Reason = err.Error()
 
 		metrics.PropagationFailedPullTime.
 			WithLabelValues(instance.Namespace, instance.Name).
 			Observe(0)
-	} else if pl != nil && (pl.PlacementRef != nil || pl.Clusters != nil || pl.ClusterSelector != nil) {
+	} else if pl != nil && (pl.PlacementRef != nil || pl.Clusters != nil || pl.ClusterSelector != nil ||
+		utils.IsHubPlacement(instance)) {
 		primaryChannel, _, err := r.getChannel(instance)
 		if err != nil {
 			klog.Errorf("Failed to find a channel for subscription: %s", instance.GetName())
@@ -656,6 +737,21 @@ func (r *ReconcileSubscription) Reconcile(ctx context.Context, request reconcile
 			instance.Status.Phase = appv1.SubscriptionPropagated
 			instance.Status.Message = ""
 			instance.Status.Reason = ""
+
+			// check the health of the promotion rings again while a commit waits to be promoted
+			if after := promotionRequeueAfter(instance); after > 0 && (result.RequeueAfter == 0 || after < result.RequeueAfter) {
+				result.RequeueAfter = after
+			}
+
+			// check the results of the clusters again for their retries
+			if after := retryRequeueAfter(instance, time.Now()); after > 0 && (result.RequeueAfter == 0 || after < result.RequeueAfter) {
+				result.RequeueAfter = after
+			}
+
+			// check the end of the change freezes while the subscription is frozen
+			if after := changeFreezeRequeueAfter(instance); after > 0 && (result.RequeueAfter == 0 || after < result.RequeueAfter) {
+				result.RequeueAfter = after
+			}
 		}
 	} else { //local: true and handle change true to false
 		// no longer hub subscription
@@ -669,6 +765,14 @@ func (r *ReconcileSubscription) Reconcile(ctx context.Context, request reconcile
 			if cleanupErr != nil {
 				klog.Warning("error while cleanup manifestwork ", cleanupErr)
 			}
+
+			cleanupErr = r.cleanupRenderedManifests(types.NamespacedName{
+				Namespace: instance.Namespace,
+				Name:      instance.Name,
+			}, nil)
+			if cleanupErr != nil {
+				klog.Warning("error while cleanup rendered manifests ", cleanupErr)
+			}
 		}
 
 		if instance.Status.Phase != appv1.SubscriptionFailed && instance.Status.Phase != appv1.SubscriptionSubscribed {
@@ -879,6 +983,12 @@ func (r *ReconcileSubscription) finalCommit(passedBranchRegistration bool, passe
 		r.logger.Error(err, "failed to apply postHook, skip the subscription reconcile, err:")
 	}
 
+	// requeue for the post hooks waiting for their timewindow schedule
+	if after := r.hooks.HookScheduleRequeueAfter(request.NamespacedName); after > 0 &&
+		(res.RequeueAfter == 0 || after < res.RequeueAfter) {
+		res.RequeueAfter = after
+	}
+
 	nIns.Status = r.hooks.AppendStatusToSubscription(nIns)
 	nIns.Status.LastUpdateTime = metav1.Now()
 
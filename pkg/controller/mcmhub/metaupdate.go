@@ -19,11 +19,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/ghodss/yaml"
 	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
 	"helm.sh/helm/v3/pkg/kube"
+	"helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/releaseutil"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -121,7 +125,9 @@ func downloadChart(client client.Client, s *releasev1.HelmRelease) (string, erro
 		chartsDir = "/tmp/hr-charts"
 	}
 
-	chartDir, err := rUtils.DownloadChart(configMap, secret, chartsDir, s)
+	mirrorSecrets := rUtils.GetMirrorSecrets(client, s.Namespace, s)
+
+	chartDir, err := rUtils.DownloadChart(configMap, secret, mirrorSecrets, chartsDir, s)
 	klog.V(3).Info("ChartDir: ", chartDir)
 
 	if err != nil {
@@ -132,13 +138,25 @@ func downloadChart(client client.Client, s *releasev1.HelmRelease) (string, erro
 	return chartDir, nil
 }
 
+// getHelmTopoResources returns the resources of the HelmReleases of the subscription, an error is returned if the
+// values of a HelmRelease don't match the values.schema.json of its chart
 func getHelmTopoResources(helmRls []*releasev1.HelmRelease, hubClt client.Client, hubCfg *rest.Config, rm meta.RESTMapper,
-	sub *subv1.Subscription, isAdmin bool) []*v1.ObjectReference {
+	sub *subv1.Subscription, isAdmin bool) ([]*v1.ObjectReference, error) {
 	resources := []*v1.ObjectReference{}
 	cfg := rest.CopyConfig(hubCfg)
+	schemaErrs := []string{}
 
 	for _, helmRl := range helmRls {
-		objList := generateResourceList(hubClt, rm, cfg, helmRl)
+		if !isAdmin {
+			helmRl.Repo.TargetNamespace = ""
+		}
+
+		objList, err := generateResourceList(hubClt, rm, cfg, helmRl)
+		if err != nil {
+			schemaErrs = append(schemaErrs, err.Error())
+
+			continue
+		}
 
 		for _, obj := range objList {
 			// No need to save the namespace object to the resource list of the appsub
@@ -159,71 +177,74 @@ func getHelmTopoResources(helmRls []*releasev1.HelmRelease, hubClt client.Client
 		}
 	}
 
-	return resources
-}
-
-// generateResourceList generates the resource list for given HelmRelease
-func generateResourceList(client client.Client, rm meta.RESTMapper, cfg *rest.Config, s *releasev1.HelmRelease) []*v1.ObjectReference {
-	chartDir, err := downloadChart(client, s)
-	if err != nil {
-		klog.Warning(err, " - Failed to download the chart")
-
-		return nil
+	if len(schemaErrs) > 0 {
+		return resources, fmt.Errorf("%v", strings.Join(schemaErrs, "; "))
 	}
 
-	var values map[string]interface{}
-
-	reqBodyBytes := new(bytes.Buffer)
+	return resources, nil
+}
 
-	err = json.NewEncoder(reqBodyBytes).Encode(s.Spec)
-	if err != nil {
-		klog.Warning(err, " - Failed to encode spec")
+// getResolvedVersions returns the chart versions of the HelmReleases of the subscription, key is the chart name
+func getResolvedVersions(helmRls []*releasev1.HelmRelease) map[string]string {
+	var versions map[string]string
 
-		return nil
-	}
+	for _, helmRl := range helmRls {
+		if helmRl.Repo.Version == "" {
+			continue
+		}
 
-	err = yaml.Unmarshal(reqBodyBytes.Bytes(), &values)
-	if err != nil {
-		klog.Warning(err, " - Failed to Unmarshal the spec ", s.Spec)
+		if versions == nil {
+			versions = map[string]string{}
+		}
 
-		return nil
+		versions[helmRl.Repo.ChartName] = helmRl.Repo.Version
 	}
 
-	klog.V(3).Info("ChartDir: ", chartDir)
+	return versions
+}
 
-	chart, err := loader.LoadDir(chartDir)
-	if err != nil {
-		klog.Warning("failed to load chart dir: %w", err)
+// setValuesSchemaCondition updates the ValuesSchemaInvalid condition of the subscription with the values schema error of
+// its HelmReleases, the condition is removed if the error is nil
+func setValuesSchemaCondition(sub *subv1.Subscription, schemaErr error) {
+	if schemaErr == nil {
+		meta.RemoveStatusCondition(&sub.Status.Conditions, subv1.ConditionValuesSchemaInvalid)
 
-		return nil
+		return
 	}
 
-	rcg, err := newRESTClientGetter(rm, cfg, s.Namespace)
+	meta.SetStatusCondition(&sub.Status.Conditions, metav1.Condition{
+		Type:               subv1.ConditionValuesSchemaInvalid,
+		Status:             metav1.ConditionTrue,
+		Reason:             subv1.ReasonSchemaValidationFailed,
+		Message:            schemaErr.Error(),
+		ObservedGeneration: sub.GetGeneration(),
+	})
+}
+
+// validateChartValues validates the values of the HelmRelease merged with the chart default values against the
+// values.schema.json of the chart and its subcharts, nil is returned if the chart has no values schema
+func validateChartValues(chrt *chart.Chart, s *releasev1.HelmRelease, values map[string]interface{}) error {
+	vals, err := chartutil.CoalesceValues(chrt, values)
 	if err != nil {
-		klog.Warning("failed to get REST client getter from manager: %w", err)
+		klog.Warning("failed to merge the values of the helm release ", s.Namespace, "/", s.Name, ": ", err)
 
 		return nil
 	}
 
-	kubeClient := kube.New(rcg)
-
-	actionConfig := &action.Configuration{}
-	if err := actionConfig.Init(rcg, s.GetNamespace(), "secret", func(_ string, _ ...interface{}) {}); err != nil {
-		klog.Warning("failed to initialized actionConfig: %w", err)
-
-		return nil
+	if err := chartutil.ValidateAgainstSchema(chrt, vals); err != nil {
+		return fmt.Errorf("the values of helm release %v/%v don't match the values schema of chart %v: %v",
+			s.Namespace, s.Name, chrt.Name(), strings.TrimSpace(err.Error()))
 	}
 
-	install := action.NewInstall(actionConfig)
-	install.ReleaseName = s.Name
-	install.Namespace = s.Namespace
-	install.DryRun = true
-	install.ClientOnly = true
-	install.Replace = true
+	return nil
+}
 
-	release, err := install.Run(chart, values)
-	if err != nil || release == nil {
-		return nil
+// generateResourceList generates the resource list for given HelmRelease, the failures to render the chart are only
+// logged but the error of the values schema validation is returned
+func generateResourceList(client client.Client, rm meta.RESTMapper, cfg *rest.Config, s *releasev1.HelmRelease) ([]*v1.ObjectReference, error) {
+	rel, actionConfig, kubeClient, err := installDryRun(client, rm, cfg, s, nil)
+	if err != nil || rel == nil {
+		return nil, err
 	}
 
 	// parse the manifest into individual yaml content
@@ -231,16 +252,16 @@ func generateResourceList(client client.Client, rm meta.RESTMapper, cfg *rest.Co
 	if err != nil {
 		klog.Warning("failed to helm install dry-run: %w", err)
 
-		return nil
+		return nil, nil
 	}
 
-	manifests := releaseutil.SplitManifests(release.Manifest)
+	manifests := releaseutil.SplitManifests(rel.Manifest)
 
 	_, files, err := releaseutil.SortManifests(manifests, caps.APIVersions, releaseutil.InstallOrder)
 	if err != nil {
 		klog.Warning("failed to sort manifests: %w", err)
 
-		return nil
+		return nil, nil
 	}
 
 	resources := []*v1.ObjectReference{}
@@ -269,7 +290,7 @@ func generateResourceList(client client.Client, rm meta.RESTMapper, cfg *rest.Co
 					Kind:       file.Head.Kind,
 					APIVersion: file.Head.Version,
 					Name:       file.Name,
-					Namespace:  s.Namespace,
+					Namespace:  s.GetTargetNamespace(),
 				}
 
 				resources = append(resources, res)
@@ -277,7 +298,91 @@ func generateResourceList(client client.Client, rm meta.RESTMapper, cfg *rest.Co
 		}
 	}
 
-	return resources
+	return resources, nil
+}
+
+// installDryRun renders the chart of the HelmRelease with a client only helm install dry-run, with the Kubernetes
+// version and the API versions of the capabilities added to the default ones of helm if they are set. A nil release is
+// returned if the chart can't be rendered, the error is only reported for the values schema violations
+func installDryRun(client client.Client, rm meta.RESTMapper, cfg *rest.Config, s *releasev1.HelmRelease,
+	caps *chartutil.Capabilities) (*release.Release, *action.Configuration, *kube.Client, error) {
+	chartDir, err := downloadChart(client, s)
+	if err != nil {
+		klog.Warning(err, " - Failed to download the chart")
+
+		return nil, nil, nil, nil
+	}
+
+	var values map[string]interface{}
+
+	reqBodyBytes := new(bytes.Buffer)
+
+	err = json.NewEncoder(reqBodyBytes).Encode(s.Spec)
+	if err != nil {
+		klog.Warning(err, " - Failed to encode spec")
+
+		return nil, nil, nil, nil
+	}
+
+	err = yaml.Unmarshal(reqBodyBytes.Bytes(), &values)
+	if err != nil {
+		klog.Warning(err, " - Failed to Unmarshal the spec ", s.Spec)
+
+		return nil, nil, nil, nil
+	}
+
+	klog.V(3).Info("ChartDir: ", chartDir)
+
+	chart, err := loader.LoadDir(chartDir)
+	if err != nil {
+		klog.Warning("failed to load chart dir: %w", err)
+
+		return nil, nil, nil, nil
+	}
+
+	rcg, err := newRESTClientGetter(rm, cfg, s.GetTargetNamespace())
+	if err != nil {
+		klog.Warning("failed to get REST client getter from manager: %w", err)
+
+		return nil, nil, nil, nil
+	}
+
+	kubeClient := kube.New(rcg)
+
+	actionConfig := &action.Configuration{}
+	if err := actionConfig.Init(rcg, s.GetTargetNamespace(), "secret", func(_ string, _ ...interface{}) {}); err != nil {
+		klog.Warning("failed to initialized actionConfig: %w", err)
+
+		return nil, nil, nil, nil
+	}
+
+	install := action.NewInstall(actionConfig)
+	install.ReleaseName = s.GetReleaseName()
+	install.Namespace = s.GetTargetNamespace()
+	install.DryRun = true
+	install.ClientOnly = true
+	install.Replace = true
+
+	if caps != nil {
+		install.KubeVersion = &caps.KubeVersion
+		install.APIVersions = caps.APIVersions
+	}
+
+	rel, err := install.Run(chart, values)
+	if err != nil || rel == nil {
+		// the install processed the chart dependencies, the error is reported if it is a values schema violation
+		if err != nil {
+			if schemaErr := validateChartValues(chart, s, values); schemaErr != nil {
+				return nil, nil, nil, schemaErr
+			}
+
+			klog.Warning("failed to helm install dry-run: ", err)
+		}
+
+		return nil, nil, nil, nil
+	}
+
+	return rel, actionConfig, kubeClient, nil
 }
 
 func (r *ReconcileSubscription) overridePrehookTopoAnnotation(subIns *subv1.Subscription) {
@@ -0,0 +1,72 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	releasev1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/helmrelease/v1"
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+const nginxValuesSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "required": ["replicaCount"],
+  "properties": {
+    "replicaCount": {"type": "integer", "minimum": 1}
+  }
+}`
+
+func TestValidateChartValues(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	hr := &releasev1.HelmRelease{ObjectMeta: metav1.ObjectMeta{Name: "nginx-release", Namespace: "demo"}}
+	chrt := &chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "nginx", Version: "1.0.0"},
+		Values:   map[string]interface{}{"replicaCount": 1},
+	}
+
+	// charts without a values schema are not validated
+	g.Expect(validateChartValues(chrt, hr, map[string]interface{}{"replicaCount": "two"})).To(gomega.Succeed())
+
+	chrt.Schema = []byte(nginxValuesSchema)
+
+	// the chart default values are merged with the release values
+	g.Expect(validateChartValues(chrt, hr, nil)).To(gomega.Succeed())
+	g.Expect(validateChartValues(chrt, hr, map[string]interface{}{"replicaCount": 3})).To(gomega.Succeed())
+
+	err := validateChartValues(chrt, hr, map[string]interface{}{"replicaCount": 0})
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("demo/nginx-release"))
+	g.Expect(err.Error()).To(gomega.ContainSubstring("replicaCount"))
+
+	sub := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "nginx-sub", Namespace: "demo"}}
+
+	setValuesSchemaCondition(sub, err)
+	cond := meta.FindStatusCondition(sub.Status.Conditions, appv1.ConditionValuesSchemaInvalid)
+	g.Expect(cond).NotTo(gomega.BeNil())
+	g.Expect(cond.Status).To(gomega.Equal(metav1.ConditionTrue))
+	g.Expect(cond.Reason).To(gomega.Equal(appv1.ReasonSchemaValidationFailed))
+	g.Expect(cond.Message).To(gomega.Equal(err.Error()))
+
+	setValuesSchemaCondition(sub, nil)
+	g.Expect(meta.FindStatusCondition(sub.Status.Conditions, appv1.ConditionValuesSchemaInvalid)).To(gomega.BeNil())
+}
@@ -0,0 +1,297 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	spokeClusterV1 "open-cluster-management.io/api/cluster/v1"
+	clusterapi "open-cluster-management.io/api/cluster/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appSubV1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+// decisionGroupNameLabel is set on the PlacementDecisions of a placement with decision groups
+const decisionGroupNameLabel = "cluster.open-cluster-management.io/decision-group-name"
+
+// decisionGroupIndexLabel is the index of the decision group of the PlacementDecisions, the groups are rolled out in
+// the order of their index
+const decisionGroupIndexLabel = "cluster.open-cluster-management.io/decision-group-index"
+
+// getDecisionGroupsFromPlacementRef returns the decision group name of every cluster decided by the placement
+func getDecisionGroupsFromPlacementRef(pref *corev1.ObjectReference, namespace string, kubeClient client.Client) (map[string]string, error) {
+	groups, _, err := getDecisionGroupOrder(pref, namespace, kubeClient)
+
+	return groups, err
+}
+
+// getDecisionGroupOrder returns the decision group of the clusters decided by the placement and the index of the groups
+func getDecisionGroupOrder(pref *corev1.ObjectReference, namespace string, kubeClient client.Client) (map[string]string,
+	map[string]int, error) {
+	label := placementRuleLabel
+
+	if strings.EqualFold(pref.Kind, "Placement") {
+		label = placementLabel
+	}
+
+	placementDecisions := &clusterapi.PlacementDecisionList{}
+	listopts := &client.ListOptions{
+		Namespace:     namespace,
+		LabelSelector: labels.SelectorFromSet(labels.Set{label: pref.Name}),
+	}
+
+	if err := kubeClient.List(context.TODO(), placementDecisions, listopts); err != nil {
+		return nil, nil, err
+	}
+
+	groups, order := map[string]string{}, map[string]int{}
+
+	for _, placementDecision := range placementDecisions.Items {
+		group := placementDecision.GetLabels()[decisionGroupNameLabel]
+
+		if index, err := strconv.Atoi(placementDecision.GetLabels()[decisionGroupIndexLabel]); err == nil {
+			order[group] = index
+		}
+
+		for _, decision := range placementDecision.Status.Decisions {
+			groups[decision.ClusterName] = group
+		}
+	}
+
+	return groups, order, nil
+}
+
+// setClusterOverrideRuleInfo sets the labels and the decision group of the clusters used to evaluate the override rules
+// and the promotion rings. The labels are always set, the agents evaluate the exclude-clusters annotations of the
+// resources against them
+func (r *ReconcileSubscription) setClusterOverrideRuleInfo(instance *appSubV1.Subscription, clusters []ManageClusters) error {
+	var groups map[string]string
+
+	if pl := instance.Spec.Placement; pl != nil && pl.PlacementRef != nil &&
+		(len(instance.Spec.OverrideRules) > 0 || instance.Spec.Promotion != nil) {
+		var err error
+
+		groups, err = getDecisionGroupsFromPlacementRef(pl.PlacementRef, instance.GetNamespace(), r.Client)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range clusters {
+		managedCluster := &spokeClusterV1.ManagedCluster{}
+
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: clusters[i].Cluster}, managedCluster); err != nil {
+			klog.Warningf("failed to get the labels of managed cluster %v, err: %v", clusters[i].Cluster, err)
+		}
+
+		clusters[i].Labels = managedCluster.GetLabels()
+		clusters[i].DecisionGroup = groups[clusters[i].Cluster]
+	}
+
+	return nil
+}
+
+// getClusterOverrideRules returns the override rules of the subscription matching the cluster, in order
+func getClusterOverrideRules(instance *appSubV1.Subscription, cluster ManageClusters) []appSubV1.OverrideRule {
+	rules := []appSubV1.OverrideRule{}
+
+	for i, rule := range instance.Spec.OverrideRules {
+		if rule.DecisionGroup != "" && rule.DecisionGroup != cluster.DecisionGroup {
+			continue
+		}
+
+		if rule.ClusterSelector != nil && !utils.LabelChecker(rule.ClusterSelector, cluster.Labels) {
+			continue
+		}
+
+		if rule.DecisionGroup == "" && rule.ClusterSelector == nil {
+			klog.Warningf("override rule %v of subscription %v/%v has no cluster selector nor decision group, skip it",
+				i, instance.GetNamespace(), instance.GetName())
+
+			continue
+		}
+
+		klog.V(1).Infof("override rule %v of subscription %v/%v matches cluster %v", i, instance.GetNamespace(), instance.GetName(),
+			cluster.Cluster)
+
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+// getClusterPackageOverrides returns the package overrides of the subscription propagated to the cluster, the
+// override sets of the matching rules are applied in order. Nil is returned if no rule matches the cluster
+func getClusterPackageOverrides(instance *appSubV1.Subscription, cluster ManageClusters) []*appSubV1.Overrides {
+	overrideSets := [][]*appSubV1.Overrides{}
+
+	for _, rule := range getClusterOverrideRules(instance, cluster) {
+		if len(rule.PackageOverrides) > 0 {
+			overrideSets = append(overrideSets, rule.PackageOverrides)
+		}
+	}
+
+	if len(overrideSets) == 0 {
+		return nil
+	}
+
+	return utils.MergePackageOverrides(instance.Spec.PackageOverrides, overrideSets...)
+}
+
+// getClusterGitOverrides returns the Git branch and path of the last matching override rules setting them, they are
+// empty if no rule overrides them for the cluster
+func getClusterGitOverrides(instance *appSubV1.Subscription, cluster ManageClusters) (branch, path string) {
+	for _, rule := range getClusterOverrideRules(instance, cluster) {
+		if rule.GitBranch != "" {
+			branch = rule.GitBranch
+		}
+
+		if rule.GitPath != "" {
+			path = rule.GitPath
+		}
+	}
+
+	return branch, path
+}
+
+// getClusterRevisionPin returns the revision the cluster is pinned to by the cluster-revision-pins annotation, nil if
+// the cluster isn't pinned
+func getClusterRevisionPin(instance *appSubV1.Subscription, cluster string) *appSubV1.ClusterRevisionPin {
+	annotation := instance.GetAnnotations()[appSubV1.AnnotationClusterRevisionPins]
+	if annotation == "" {
+		return nil
+	}
+
+	pins := map[string]appSubV1.ClusterRevisionPin{}
+	if err := json.Unmarshal([]byte(annotation), &pins); err != nil {
+		klog.Warningf("ignore the invalid %v annotation of subscription %v/%v, err: %v",
+			appSubV1.AnnotationClusterRevisionPins, instance.Namespace, instance.Name, err)
+
+		return nil
+	}
+
+	pin, ok := pins[cluster]
+	if !ok || (pin.Commit == "" && pin.Tag == "") {
+		return nil
+	}
+
+	return &pin
+}
+
+// getClusterSubscription returns the subscription propagated to the cluster after its override rules, the commit
+// pinned by its promotion ring, its revision pin and the last retry of the cluster, nil is returned if nothing changes
+// the subscription for the cluster. The commits resolved on the branch of the subscription aren't on the branch of an
+// override rule, the cluster subscribing to another branch deploys its latest commit unless it is pinned
+func getClusterSubscription(instance *appSubV1.Subscription, cluster ManageClusters) *appSubV1.Subscription {
+	packageOverrides := getClusterPackageOverrides(instance, cluster)
+	branch, path := getClusterGitOverrides(instance, cluster)
+	commit := getClusterPromotedCommit(instance, cluster)
+	retryTime := getClusterRetryTime(instance, cluster)
+	pin := getClusterRevisionPin(instance, cluster.Cluster)
+
+	instanceBranch, _, _, _ := getBranchCommitDepthAndTag(instance)
+	if branch == instanceBranch {
+		branch = ""
+	}
+
+	if branch != "" {
+		commit = ""
+	}
+
+	if packageOverrides == nil && branch == "" && path == "" && commit == "" && retryTime == "" && pin == nil {
+		return nil
+	}
+
+	clusterSub := instance.DeepCopy()
+
+	if packageOverrides != nil {
+		clusterSub.Spec.PackageOverrides = packageOverrides
+	}
+
+	annotations := clusterSub.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	if branch != "" {
+		if annotations[appSubV1.AnnotationGitTargetCommit] != "" {
+			klog.Infof("drop target commit %v of subscription %v/%v for cluster %v, it subscribes to branch %v",
+				annotations[appSubV1.AnnotationGitTargetCommit], instance.Namespace, instance.Name, cluster.Cluster, branch)
+		}
+
+		delete(annotations, appSubV1.AnnotationGithubBranch)
+		delete(annotations, appSubV1.AnnotationGitTargetCommit)
+		annotations[appSubV1.AnnotationGitBranch] = branch
+	}
+
+	if path != "" {
+		delete(annotations, appSubV1.AnnotationGithubPath)
+		annotations[appSubV1.AnnotationGitPath] = path
+	}
+
+	if commit != "" {
+		annotations[appSubV1.AnnotationGitTargetCommit] = commit
+	}
+
+	if pin != nil && pin.Tag != "" {
+		delete(annotations, appSubV1.AnnotationGitTargetCommit)
+		annotations[appSubV1.AnnotationGitTag] = pin.Tag
+	} else if pin != nil {
+		delete(annotations, appSubV1.AnnotationGitTag)
+		annotations[appSubV1.AnnotationGitTargetCommit] = pin.Commit
+	}
+
+	if retryTime != "" {
+		annotations[appSubV1.AnnotationRetryTime] = retryTime
+	}
+
+	clusterSub.SetAnnotations(annotations)
+
+	return clusterSub
+}
+
+// setClusterLabelsAnnotation sets the labels of the cluster in the propagated subscription, the agent skips the
+// resources with an exclude-clusters annotation matching them
+func setClusterLabelsAnnotation(sub *unstructured.Unstructured, clusterLabels map[string]string) error {
+	if len(clusterLabels) == 0 {
+		return nil
+	}
+
+	labelsJSON, err := json.Marshal(clusterLabels)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the cluster labels, err: %w", err)
+	}
+
+	annotations := sub.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	annotations[appSubV1.AnnotationClusterLabels] = string(labelsJSON)
+	sub.SetAnnotations(annotations)
+
+	return nil
+}
@@ -0,0 +1,226 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterapi "open-cluster-management.io/api/cluster/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func TestGetClusterPackageOverrides(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns"},
+		Spec: appv1.SubscriptionSpec{
+			PackageOverrides: []*appv1.Overrides{{PackageName: "nginx", PackageAlias: "default"}},
+			OverrideRules: []appv1.OverrideRule{
+				{
+					ClusterSelector:  &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "edge"}},
+					PackageOverrides: []*appv1.Overrides{{PackageName: "nginx", PackageAlias: "edge"}},
+				},
+				{
+					DecisionGroup:    "canary",
+					PackageOverrides: []*appv1.Overrides{{PackageName: "redis", PackageAlias: "canary"}},
+				},
+			},
+		},
+	}
+
+	g.Expect(getClusterPackageOverrides(sub, ManageClusters{Cluster: "dc1", Labels: map[string]string{"tier": "dc"}})).To(gomega.BeNil())
+
+	edge := getClusterPackageOverrides(sub, ManageClusters{Cluster: "edge1", Labels: map[string]string{"tier": "edge"}})
+	g.Expect(edge).To(gomega.HaveLen(1))
+	g.Expect(edge[0].PackageAlias).To(gomega.Equal("edge"))
+
+	both := getClusterPackageOverrides(sub, ManageClusters{Cluster: "edge2", Labels: map[string]string{"tier": "edge"}, DecisionGroup: "canary"})
+	g.Expect(both).To(gomega.HaveLen(2))
+	g.Expect(both[0].PackageAlias).To(gomega.Equal("edge"))
+	g.Expect(both[1].PackageAlias).To(gomega.Equal("canary"))
+}
+
+func TestGetClusterSubscription(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "demo",
+			Namespace: "demo-ns",
+			Annotations: map[string]string{
+				appv1.AnnotationGithubBranch: "main",
+				appv1.AnnotationGitPath:      "apps",
+			},
+		},
+		Spec: appv1.SubscriptionSpec{
+			OverrideRules: []appv1.OverrideRule{
+				{DecisionGroup: "stage", GitBranch: "release-candidate"},
+				{
+					ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "edge"}},
+					GitPath:         "apps-edge",
+				},
+				{DecisionGroup: "stage", ClusterSelector: &metav1.LabelSelector{}, GitBranch: "stage"},
+			},
+		},
+	}
+
+	g.Expect(getClusterSubscription(sub, ManageClusters{Cluster: "prod1", DecisionGroup: "prod"})).To(gomega.BeNil())
+
+	edge := getClusterSubscription(sub, ManageClusters{Cluster: "edge1", Labels: map[string]string{"tier": "edge"}})
+	g.Expect(edge).NotTo(gomega.BeNil())
+	g.Expect(edge.Spec.PackageOverrides).To(gomega.BeNil())
+	g.Expect(edge.GetAnnotations()).To(gomega.Equal(map[string]string{
+		appv1.AnnotationGithubBranch: "main",
+		appv1.AnnotationGitPath:      "apps-edge",
+	}))
+
+	stage := getClusterSubscription(sub, ManageClusters{Cluster: "stage1", DecisionGroup: "stage"})
+	g.Expect(stage.GetAnnotations()).To(gomega.Equal(map[string]string{
+		appv1.AnnotationGitBranch: "stage",
+		appv1.AnnotationGitPath:   "apps",
+	}))
+
+	g.Expect(sub.GetAnnotations()).To(gomega.HaveKeyWithValue(appv1.AnnotationGithubBranch, "main"))
+}
+
+func TestGetClusterSubscriptionBranchCommit(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "demo",
+			Namespace: "demo-ns",
+			Annotations: map[string]string{
+				appv1.AnnotationGitBranch:       "main",
+				appv1.AnnotationGitTargetCommit: "c0",
+			},
+		},
+		Spec: appv1.SubscriptionSpec{
+			OverrideRules: []appv1.OverrideRule{
+				{DecisionGroup: "stage", GitBranch: "stage"},
+				{DecisionGroup: "prod", GitBranch: "main"},
+			},
+			Promotion: &appv1.Promotion{
+				Rings: []appv1.PromotionRing{{DecisionGroup: "canary"}, {DecisionGroup: "stage"}, {DecisionGroup: "prod"}},
+			},
+		},
+		Status: appv1.SubscriptionStatus{
+			Promotion: []appv1.PromotionRingStatus{
+				{DecisionGroup: "canary", Commit: "c2"},
+				{DecisionGroup: "stage", Commit: "c1"},
+				{DecisionGroup: "prod", Commit: "c1"},
+			},
+		},
+	}
+
+	// the commits of the main branch are dropped for the cluster subscribing to another branch
+	stage := getClusterSubscription(sub, ManageClusters{Cluster: "stage1", DecisionGroup: "stage"})
+	g.Expect(stage.GetAnnotations()).To(gomega.Equal(map[string]string{appv1.AnnotationGitBranch: "stage"}))
+
+	// the rule setting the branch of the subscription keeps the commit of the ring
+	prod := getClusterSubscription(sub, ManageClusters{Cluster: "prod1", DecisionGroup: "prod"})
+	g.Expect(prod.GetAnnotations()).To(gomega.Equal(map[string]string{
+		appv1.AnnotationGitBranch:       "main",
+		appv1.AnnotationGitTargetCommit: "c1",
+	}))
+
+	g.Expect(sub.GetAnnotations()).To(gomega.HaveKeyWithValue(appv1.AnnotationGitTargetCommit, "c0"))
+}
+
+func TestGetClusterSubscriptionRevisionPins(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "demo",
+			Namespace: "demo-ns",
+			Annotations: map[string]string{
+				appv1.AnnotationGitBranch:           "main",
+				appv1.AnnotationGitTargetCommit:     "c0",
+				appv1.AnnotationClusterRevisionPins: `{"cluster1":{"commit":"c1"},"cluster2":{"commit":"c2","tag":"v2"}}`,
+			},
+		},
+	}
+
+	cluster1 := getClusterSubscription(sub, ManageClusters{Cluster: "cluster1"})
+	g.Expect(cluster1.GetAnnotations()).To(gomega.HaveKeyWithValue(appv1.AnnotationGitTargetCommit, "c1"))
+
+	// the tag takes precedence over the commit of the pin
+	cluster2 := getClusterSubscription(sub, ManageClusters{Cluster: "cluster2"})
+	g.Expect(cluster2.GetAnnotations()).To(gomega.HaveKeyWithValue(appv1.AnnotationGitTag, "v2"))
+	g.Expect(cluster2.GetAnnotations()).NotTo(gomega.HaveKey(appv1.AnnotationGitTargetCommit))
+
+	// the clusters without a pin deploy the revision of the subscription
+	g.Expect(getClusterSubscription(sub, ManageClusters{Cluster: "cluster3"})).To(gomega.BeNil())
+
+	sub.Annotations[appv1.AnnotationClusterRevisionPins] = "invalid"
+	g.Expect(getClusterSubscription(sub, ManageClusters{Cluster: "cluster1"})).To(gomega.BeNil())
+}
+
+func TestGetDecisionGroupsFromPlacementRef(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(clusterapi.AddToScheme(scheme)).To(gomega.Succeed())
+
+	decision := func(name, group string, clusters ...string) *clusterapi.PlacementDecision {
+		pd := &clusterapi.PlacementDecision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "demo-ns",
+				Labels:    map[string]string{placementLabel: "demo-placement", decisionGroupNameLabel: group},
+			},
+		}
+
+		for _, cluster := range clusters {
+			pd.Status.Decisions = append(pd.Status.Decisions, clusterapi.ClusterDecision{ClusterName: cluster})
+		}
+
+		return pd
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		decision("demo-placement-decision-1", "canary", "cluster1"),
+		decision("demo-placement-decision-2", "prod", "cluster2", "cluster3"),
+	).Build()
+
+	groups, err := getDecisionGroupsFromPlacementRef(&corev1.ObjectReference{Kind: "Placement", Name: "demo-placement"}, "demo-ns", c)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(groups).To(gomega.Equal(map[string]string{"cluster1": "canary", "cluster2": "prod", "cluster3": "prod"}))
+}
+
+func TestSetClusterLabelsAnnotation(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	sub := &unstructured.Unstructured{}
+	sub.SetAnnotations(map[string]string{appv1.AnnotationHosting: "demo-ns/demo"})
+
+	g.Expect(setClusterLabelsAnnotation(sub, nil)).To(gomega.Succeed())
+	g.Expect(sub.GetAnnotations()).NotTo(gomega.HaveKey(appv1.AnnotationClusterLabels))
+
+	g.Expect(setClusterLabelsAnnotation(sub, map[string]string{"tier": "edge", "env": "prod"})).To(gomega.Succeed())
+	g.Expect(sub.GetAnnotations()).To(gomega.Equal(map[string]string{
+		appv1.AnnotationHosting:       "demo-ns/demo",
+		appv1.AnnotationClusterLabels: `{"env":"prod","tier":"edge"}`,
+	}))
+}
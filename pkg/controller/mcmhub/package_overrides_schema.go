@@ -0,0 +1,238 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appSubV1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+// SubscriptionValidator is the validating webhook of the subscriptions, it validates the patches of the package
+// overrides and the package overrides of the subscriptions against the JSON schema of their channel. The new hub
+// subscriptions are also checked against the SubscriptionQuotas of their namespace
+type SubscriptionValidator struct {
+	client.Client
+}
+
+// SetupSubscriptionValidatorWithManager registers the validating webhook of the subscriptions on the webhook server
+// of the manager
+func SetupSubscriptionValidatorWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&appSubV1.Subscription{}).
+		WithValidator(&SubscriptionValidator{Client: mgr.GetClient()}).
+		Complete()
+}
+
+// ValidateCreate validates the package patches, the package overrides and the quotas of a new subscription
+func (v *SubscriptionValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	sub, ok := obj.(*appSubV1.Subscription)
+	if !ok {
+		return fmt.Errorf("expected a subscription, got %T", obj)
+	}
+
+	if err := utils.ValidatePackagePatches(sub); err != nil {
+		return err
+	}
+
+	if err := v.validatePackageOverrides(ctx, sub); err != nil {
+		return err
+	}
+
+	return validateSubscriptionQuota(v.Client, sub)
+}
+
+// ValidateUpdate validates the package patches and the package overrides of a subscription when they or the channel
+// change, so the subscriptions created before the schema are still updated by the controllers
+func (v *SubscriptionValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	oldSub, ok := oldObj.(*appSubV1.Subscription)
+	if !ok {
+		return fmt.Errorf("expected a subscription, got %T", oldObj)
+	}
+
+	sub, ok := newObj.(*appSubV1.Subscription)
+	if !ok {
+		return fmt.Errorf("expected a subscription, got %T", newObj)
+	}
+
+	if oldSub.Spec.Channel == sub.Spec.Channel && !packageOverridesChanged(oldSub, sub) {
+		return nil
+	}
+
+	if err := utils.ValidatePackagePatches(sub); err != nil {
+		return err
+	}
+
+	return v.validatePackageOverrides(ctx, sub)
+}
+
+// ValidateDelete allows the deletion of the subscriptions
+func (v *SubscriptionValidator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+// packageOverridesField are the package overrides of a field of the subscription
+type packageOverridesField struct {
+	field     string
+	overrides []*appSubV1.Overrides
+}
+
+// getSubscriptionPackageOverrides returns the package overrides of the subscription and of its override rules
+func getSubscriptionPackageOverrides(sub *appSubV1.Subscription) []packageOverridesField {
+	var fields []packageOverridesField
+
+	if len(sub.Spec.PackageOverrides) > 0 {
+		fields = append(fields, packageOverridesField{field: "spec.packageOverrides", overrides: sub.Spec.PackageOverrides})
+	}
+
+	for i, rule := range sub.Spec.OverrideRules {
+		if len(rule.PackageOverrides) > 0 {
+			fields = append(fields, packageOverridesField{
+				field:     fmt.Sprintf("spec.overrideRules[%v].packageOverrides", i),
+				overrides: rule.PackageOverrides,
+			})
+		}
+	}
+
+	return fields
+}
+
+// packageOverridesChanged checks if the package overrides of the subscription or of its override rules changed
+func packageOverridesChanged(oldSub, sub *appSubV1.Subscription) bool {
+	oldFields, fields := getSubscriptionPackageOverrides(oldSub), getSubscriptionPackageOverrides(sub)
+	if len(oldFields) != len(fields) {
+		return true
+	}
+
+	for i := range fields {
+		if oldFields[i].field != fields[i].field || !equality.Semantic.DeepEqual(oldFields[i].overrides, fields[i].overrides) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validatePackageOverrides validates each package overrides of the subscription against the JSON schema of the
+// ConfigMap set by the package-overrides-schema annotation of its channel
+func (v *SubscriptionValidator) validatePackageOverrides(ctx context.Context, sub *appSubV1.Subscription) error {
+	fields := getSubscriptionPackageOverrides(sub)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	chnNs, chnName := utils.ParseNamespacedName(sub.Spec.Channel)
+	if chnName == "" {
+		return nil
+	}
+
+	chn := &chnv1.Channel{}
+	if err := v.Get(ctx, types.NamespacedName{Name: chnName, Namespace: chnNs}, chn); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	schemaName := chn.GetAnnotations()[appSubV1.AnnotationPackageOverridesSchema]
+	if schemaName == "" {
+		return nil
+	}
+
+	schema, err := v.getPackageOverridesSchema(ctx, chnNs, schemaName)
+	if err != nil {
+		return fmt.Errorf("failed to load the package overrides schema of channel %v: %w", sub.Spec.Channel, err)
+	}
+
+	var violations []string
+
+	for _, f := range fields {
+		violations = append(violations, validatePackageOverridesSchema(schema, f.field, f.overrides)...)
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	klog.Infof("package overrides of subscription %v/%v rejected by the schema of channel %v: %v",
+		sub.Namespace, sub.Name, sub.Spec.Channel, violations)
+
+	return fmt.Errorf("the package overrides are not allowed by the schema of channel %v: %v",
+		sub.Spec.Channel, strings.Join(violations, "; "))
+}
+
+// getPackageOverridesSchema loads the JSON schema of the schema.json key of the ConfigMap
+func (v *SubscriptionValidator) getPackageOverridesSchema(ctx context.Context, namespace,
+	name string) (*gojsonschema.Schema, error) {
+	cm := &corev1.ConfigMap{}
+	if err := v.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, cm); err != nil {
+		return nil, err
+	}
+
+	data, ok := cm.Data[appSubV1.PackageOverridesSchemaKey]
+	if !ok {
+		return nil, fmt.Errorf("configmap %v/%v has no %v key", namespace, name, appSubV1.PackageOverridesSchemaKey)
+	}
+
+	return gojsonschema.NewSchema(gojsonschema.NewStringLoader(data))
+}
+
+// validatePackageOverridesSchema returns the schema violations of each package overrides of the field
+func validatePackageOverridesSchema(schema *gojsonschema.Schema, field string,
+	packageOverrides []*appSubV1.Overrides) []string {
+	var violations []string
+
+	for i, ov := range packageOverrides {
+		if ov == nil {
+			continue
+		}
+
+		doc, err := json.Marshal(ov)
+		if err != nil {
+			violations = append(violations, fmt.Sprintf("%v[%v]: %v", field, i, err))
+
+			continue
+		}
+
+		result, err := schema.Validate(gojsonschema.NewBytesLoader(doc))
+		if err != nil {
+			violations = append(violations, fmt.Sprintf("%v[%v]: %v", field, i, err))
+
+			continue
+		}
+
+		for _, resultErr := range result.Errors() {
+			violations = append(violations, fmt.Sprintf("%v[%v]: %v", field, i, resultErr))
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,167 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+// the package overrides may only set the replicas and resources values of the charts
+const packageOverridesSchema = `{
+  "type": "object",
+  "properties": {
+    "packageName": {"type": "string"},
+    "packageAlias": {"type": "string"},
+    "packageOverrides": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "properties": {
+          "path": {"const": "spec"},
+          "value": {
+            "type": "object",
+            "properties": {
+              "replicaCount": {"type": "integer", "minimum": 1, "maximum": 10},
+              "resources": {"type": "object"}
+            },
+            "additionalProperties": false
+          }
+        },
+        "required": ["path", "value"]
+      }
+    }
+  },
+  "additionalProperties": false
+}`
+
+func TestValidatePackageOverrides(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(corev1.AddToScheme(scheme)).To(gomega.Succeed())
+	g.Expect(chnv1.SchemeBuilder.AddToScheme(scheme)).To(gomega.Succeed())
+
+	channel := func(name, schema string) *chnv1.Channel {
+		chn := &chnv1.Channel{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "chn-ns"},
+			Spec:       chnv1.ChannelSpec{Type: chnv1.ChannelTypeHelmRepo, Pathname: "https://charts.example.com"},
+		}
+
+		if schema != "" {
+			chn.Annotations = map[string]string{appv1.AnnotationPackageOverridesSchema: schema}
+		}
+
+		return chn
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		channel("constrained", "overrides-schema"),
+		channel("unconstrained", ""),
+		channel("missing-schema", "not-found"),
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "overrides-schema", Namespace: "chn-ns"},
+			Data:       map[string]string{appv1.PackageOverridesSchemaKey: packageOverridesSchema},
+		},
+	).Build()
+
+	v := &SubscriptionValidator{Client: c}
+
+	overrides := func(value string) []*appv1.Overrides {
+		return []*appv1.Overrides{{
+			PackageName: "nginx",
+			PackageOverrides: []appv1.PackageOverride{
+				{RawExtension: runtime.RawExtension{Raw: []byte(`{"path":"spec","value":` + value + `}`)}},
+			},
+		}}
+	}
+
+	subscription := func(chn string, packageOverrides []*appv1.Overrides) *appv1.Subscription {
+		return &appv1.Subscription{
+			ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns"},
+			Spec:       appv1.SubscriptionSpec{Channel: "chn-ns/" + chn, PackageOverrides: packageOverrides},
+		}
+	}
+
+	allowed := subscription("constrained", overrides(`{"replicaCount":3}`))
+	g.Expect(v.ValidateCreate(context.TODO(), allowed)).To(gomega.Succeed())
+
+	err := v.ValidateCreate(context.TODO(), subscription("constrained", overrides(`{"image":{"tag":"latest"}}`)))
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("schema of channel chn-ns/constrained"))
+	g.Expect(err.Error()).To(gomega.ContainSubstring("spec.packageOverrides[0]: packageOverrides.0.value: Additional property image is not allowed"))
+
+	// the package overrides of the override rules are validated too
+	rules := subscription("constrained", nil)
+	rules.Spec.OverrideRules = []appv1.OverrideRule{
+		{GitBranch: "canary"},
+		{PackageOverrides: overrides(`{"replicaCount":20}`)},
+	}
+
+	err = v.ValidateCreate(context.TODO(), rules)
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("spec.overrideRules[1].packageOverrides[0]: packageOverrides.0.value.replicaCount"))
+
+	// the patches are not allowed by the schema
+	patched := subscription("constrained", overrides(`{"replicaCount":3}`))
+	patched.Spec.PackageOverrides[0].Patches = []appv1.PackagePatch{{Patch: `{"spec":{"replicas":3}}`}}
+	g.Expect(v.ValidateCreate(context.TODO(), patched)).NotTo(gomega.Succeed())
+
+	// the invalid patches are rejected, even by the channels without a schema
+	badPatch := subscription("unconstrained", overrides(`{"replicaCount":3}`))
+	badPatch.Spec.PackageOverrides[0].Patches = []appv1.PackagePatch{
+		{Type: appv1.PatchTypeJSON6902, Patch: `{"op":"remove"}`},
+	}
+
+	err = v.ValidateCreate(context.TODO(), badPatch)
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("invalid patch 0 of package nginx"))
+
+	goodPatch := subscription("unconstrained", overrides(`{"replicaCount":3}`))
+	goodPatch.Spec.PackageOverrides[0].Patches = []appv1.PackagePatch{
+		{Type: appv1.PatchTypeJSON6902, Patch: `[{"op":"remove","path":"/spec/replicas"}]`},
+	}
+	g.Expect(v.ValidateCreate(context.TODO(), goodPatch)).To(gomega.Succeed())
+	g.Expect(v.ValidateUpdate(context.TODO(), goodPatch, badPatch)).NotTo(gomega.Succeed())
+
+	// the channels without a schema don't constrain the package overrides
+	g.Expect(v.ValidateCreate(context.TODO(), subscription("unconstrained", overrides(`{"image":{"tag":"latest"}}`)))).
+		To(gomega.Succeed())
+
+	err = v.ValidateCreate(context.TODO(), subscription("missing-schema", overrides(`{"replicaCount":3}`)))
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("failed to load the package overrides schema"))
+
+	// the updates not changing the package overrides are allowed, the subscriptions created before the schema are
+	// still updated
+	invalid := subscription("constrained", overrides(`{"image":{"tag":"latest"}}`))
+	updated := invalid.DeepCopy()
+	updated.Annotations = map[string]string{appv1.AnnotationGitBranch: "main"}
+	g.Expect(v.ValidateUpdate(context.TODO(), invalid, updated)).To(gomega.Succeed())
+
+	updated.Spec.PackageOverrides = overrides(`{"image":{"tag":"v2"}}`)
+	g.Expect(v.ValidateUpdate(context.TODO(), invalid, updated)).NotTo(gomega.Succeed())
+
+	g.Expect(v.ValidateUpdate(context.TODO(), invalid, allowed)).To(gomega.Succeed())
+}
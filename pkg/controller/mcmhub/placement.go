@@ -27,6 +27,7 @@ import (
 	clusterapi "open-cluster-management.io/api/cluster/v1beta1"
 	appSubV1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
 	placementutils "open-cluster-management.io/multicloud-operators-subscription/pkg/placementrule/utils"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -38,6 +39,12 @@ const (
 type ManageClusters struct {
 	Cluster        string
 	IsLocalCluster bool
+	IsRegionalHub  bool
+	// the hub itself, the subscription is applied by the hub instead of being propagated with a ManifestWork
+	IsHub bool
+	// the cluster labels and decision group, only set when the subscription has override rules
+	Labels        map[string]string
+	DecisionGroup string
 }
 
 // Top priority: placementRef, ignore others
@@ -52,6 +59,10 @@ func (r *ReconcileSubscription) getClustersByPlacement(instance *appSubV1.Subscr
 		return clusters, nil
 	}
 
+	if utils.IsHubPlacement(instance) {
+		return []ManageClusters{hubCluster()}, nil
+	}
+
 	// Top priority: placementRef, ignore others
 	// Next priority: clusterNames, ignore selector
 	// Bottomline: Use label selector
@@ -68,6 +79,7 @@ func (r *ReconcileSubscription) getClustersByPlacement(instance *appSubV1.Subscr
 				ManageClusters{
 					Cluster:        cl.Name,
 					IsLocalCluster: isLocalClusterByLabels(cl.GetLabels()),
+					IsRegionalHub:  isRegionalHubByLabels(cl.GetLabels()),
 				})
 		}
 	}
@@ -148,7 +160,7 @@ func (r *ReconcileSubscription) getClustersFromPlacementRef(instance *appSubV1.S
 	}
 
 	for _, clusterName := range clusterNames {
-		cluster := ManageClusters{Cluster: clusterName, IsLocalCluster: r.isLocalCluster(clusterName)}
+		cluster := ManageClusters{Cluster: clusterName, IsLocalCluster: r.isLocalCluster(clusterName), IsRegionalHub: r.isRegionalHubCluster(clusterName)}
 		clusters = append(clusters, cluster)
 	}
 
@@ -0,0 +1,198 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appSubV1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appsubreportv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+// PropagationPlan is the propagation of a hub subscription computed without propagating it
+type PropagationPlan struct {
+	// Reason is why the hub doesn't propagate the subscription, the waves are empty when it is set
+	Reason string `json:"reason,omitempty"`
+	// ClusterAdmin is set when the subscription is propagated with the cluster-admin annotation
+	ClusterAdmin bool `json:"clusterAdmin"`
+	// Waves are the clusters by decision group, in the order of the decision group index
+	Waves []PlanWave `json:"waves,omitempty"`
+	// NextWindow is the start of the next timewindow when the subscription is outside of its timewindow
+	NextWindow *time.Time `json:"nextWindow,omitempty"`
+}
+
+// PlanWave is a decision group of the placement, all the clusters are in one wave without decision groups
+type PlanWave struct {
+	DecisionGroup string        `json:"decisionGroup,omitempty"`
+	Clusters      []PlanCluster `json:"clusters"`
+}
+
+// PlanCluster is a target cluster of the subscription
+type PlanCluster struct {
+	Name string `json:"name"`
+	// PackageOverrides is set when override rules change the package overrides of the cluster
+	PackageOverrides []*appSubV1.Overrides `json:"packageOverrides,omitempty"`
+	// GitBranch and GitPath are set when override rules change the Git branch and path of the cluster
+	GitBranch string `json:"gitBranch,omitempty"`
+	GitPath   string `json:"gitPath,omitempty"`
+	// GitCommit is the commit pinned by the promotion ring of the cluster
+	GitCommit string `json:"gitCommit,omitempty"`
+}
+
+// ClusterCount returns the number of target clusters of the plan
+func (p *PropagationPlan) ClusterCount() int {
+	count := 0
+
+	for _, wave := range p.Waves {
+		count += len(wave.Clusters)
+	}
+
+	return count
+}
+
+// PlanPropagation resolves the target clusters of a hub subscription with the hub rules, without creating or updating
+// anything on the hub. The resource count is the number of resources the subscription deploys, it is checked against
+// the subscription quotas of the namespace
+func PlanPropagation(clt client.Client, sub *appSubV1.Subscription, resourceCount int, now time.Time) (*PropagationPlan, error) {
+	r := &ReconcileSubscription{Client: clt}
+	sub = sub.DeepCopy()
+	plan := &PropagationPlan{}
+
+	if err := utils.ValidatePlacement(sub); err != nil {
+		plan.Reason = err.Error()
+
+		return plan, nil
+	}
+
+	if err := utils.ValidatePackagePatches(sub); err != nil {
+		plan.Reason = err.Error()
+
+		return plan, nil
+	}
+
+	if pl := sub.Spec.Placement; pl.PlacementRef == nil && pl.Clusters == nil && pl.ClusterSelector == nil {
+		plan.Reason = "the subscription has a local placement, it is deployed on the hub cluster only"
+
+		return plan, nil
+	}
+
+	plan.ClusterAdmin = r.AddClusterAdminAnnotation(sub)
+
+	clusters, err := r.getClustersByPlacement(sub)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the clusters of the placement: %w", err)
+	}
+
+	if err := r.setClusterOverrideRuleInfo(sub, clusters); err != nil {
+		return nil, fmt.Errorf("failed to get the override rule info of the clusters: %w", err)
+	}
+
+	if reason, err := planQuota(r.Client, sub, len(clusters), resourceCount); err != nil {
+		return nil, err
+	} else if reason != "" {
+		plan.Reason = reason
+
+		return plan, nil
+	}
+
+	groups, order := map[string]string{}, map[string]int{}
+
+	if pref := sub.Spec.Placement.PlacementRef; pref != nil {
+		if groups, order, err = getDecisionGroupOrder(pref, sub.Namespace, clt); err != nil {
+			return nil, fmt.Errorf("failed to get the decision groups of the placement: %w", err)
+		}
+	}
+
+	waves := map[string]*PlanWave{}
+
+	for _, cluster := range clusters {
+		group := groups[cluster.Cluster]
+
+		wave := waves[group]
+		if wave == nil {
+			wave = &PlanWave{DecisionGroup: group}
+			waves[group] = wave
+		}
+
+		branch, path := getClusterGitOverrides(sub, cluster)
+
+		wave.Clusters = append(wave.Clusters, PlanCluster{
+			Name:             cluster.Cluster,
+			PackageOverrides: getClusterPackageOverrides(sub, cluster),
+			GitBranch:        branch,
+			GitPath:          path,
+			GitCommit:        getClusterPromotedCommit(sub, cluster),
+		})
+	}
+
+	for _, wave := range waves {
+		sort.Slice(wave.Clusters, func(i, j int) bool { return wave.Clusters[i].Name < wave.Clusters[j].Name })
+
+		plan.Waves = append(plan.Waves, *wave)
+	}
+
+	sort.Slice(plan.Waves, func(i, j int) bool {
+		gi, gj := plan.Waves[i].DecisionGroup, plan.Waves[j].DecisionGroup
+		if order[gi] != order[gj] {
+			return order[gi] < order[gj]
+		}
+
+		return gi < gj
+	})
+
+	if !utils.IsInWindow(sub.Spec.TimeWindow, now) {
+		next := now.Add(utils.NextStartPoint(sub.Spec.TimeWindow, now))
+		plan.NextWindow = &next
+	}
+
+	return plan, nil
+}
+
+// planQuota returns the reason the subscription quotas of the namespace don't admit the subscription, the status of
+// the quotas is not updated
+func planQuota(clt client.Reader, sub *appSubV1.Subscription, clusterCount, resourceCount int) (string, error) {
+	quotaList := &appsubreportv1alpha1.SubscriptionQuotaList{}
+	if err := clt.List(context.TODO(), quotaList, client.InNamespace(sub.Namespace)); err != nil {
+		if meta.IsNoMatchError(err) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("failed to list the subscription quotas of namespace %v: %w", sub.Namespace, err)
+	}
+
+	if len(quotaList.Items) == 0 {
+		return "", nil
+	}
+
+	usages, err := getQuotaUsages(clt, sub, clusterCount, resourceCount)
+	if err != nil {
+		return "", err
+	}
+
+	for i := range quotaList.Items {
+		if reason := admitSubscription(&quotaList.Items[i], usages, sub); reason != "" {
+			return fmt.Sprintf("subscription quota %v exceeded: %v", quotaList.Items[i].Name, reason), nil
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	spokeClusterV1 "open-cluster-management.io/api/cluster/v1"
+	clusterapi "open-cluster-management.io/api/cluster/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	plrv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appsubreportv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+)
+
+func TestPlanPropagation(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(clusterapi.AddToScheme(scheme)).To(gomega.Succeed())
+	g.Expect(spokeClusterV1.AddToScheme(scheme)).To(gomega.Succeed())
+	g.Expect(appsubreportv1alpha1.AddToScheme(scheme)).To(gomega.Succeed())
+
+	decision := func(name, group, index string, clusters ...string) *clusterapi.PlacementDecision {
+		pd := &clusterapi.PlacementDecision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "demo-ns",
+				Labels: map[string]string{
+					placementLabel:          "demo-placement",
+					decisionGroupNameLabel:  group,
+					decisionGroupIndexLabel: index,
+				},
+			},
+		}
+
+		for _, cluster := range clusters {
+			pd.Status.Decisions = append(pd.Status.Decisions, clusterapi.ClusterDecision{ClusterName: cluster})
+		}
+
+		return pd
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		decision("demo-placement-decision-1", "prod", "1", "cluster3", "cluster2"),
+		decision("demo-placement-decision-2", "canary", "0", "cluster1"),
+	).Build()
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns"},
+		Spec: appv1.SubscriptionSpec{
+			Channel: "demo-ns/git",
+			Placement: &plrv1.Placement{
+				PlacementRef: &corev1.ObjectReference{Kind: "Placement", Name: "demo-placement"},
+			},
+			OverrideRules: []appv1.OverrideRule{
+				{
+					DecisionGroup:    "canary",
+					PackageOverrides: []*appv1.Overrides{{PackageName: "nginx", PackageAlias: "canary"}},
+				},
+			},
+		},
+	}
+
+	plan, err := PlanPropagation(c, sub, 3, time.Now())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(plan.Reason).To(gomega.BeEmpty())
+	g.Expect(plan.NextWindow).To(gomega.BeNil())
+	g.Expect(plan.ClusterCount()).To(gomega.Equal(3))
+	g.Expect(plan.Waves).To(gomega.HaveLen(2))
+	g.Expect(plan.Waves[0].DecisionGroup).To(gomega.Equal("canary"))
+	g.Expect(plan.Waves[0].Clusters).To(gomega.HaveLen(1))
+	g.Expect(plan.Waves[0].Clusters[0].PackageOverrides).To(gomega.HaveLen(1))
+	g.Expect(plan.Waves[1].DecisionGroup).To(gomega.Equal("prod"))
+	g.Expect(plan.Waves[1].Clusters).To(gomega.Equal([]PlanCluster{{Name: "cluster2"}, {Name: "cluster3"}}))
+
+	sub.Spec.Placement = &plrv1.Placement{Local: &[]bool{true}[0]}
+
+	plan, err = PlanPropagation(c, sub, 3, time.Now())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(plan.Reason).To(gomega.ContainSubstring("local placement"))
+	g.Expect(plan.Waves).To(gomega.BeEmpty())
+}
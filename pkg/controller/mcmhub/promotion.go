@@ -0,0 +1,358 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	appSubV1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appSubStatusV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+// promotionRequeueInterval is how often the hub checks the health of the rings while a commit waits for the soak
+// duration or the timewindow of the next ring, the SubscriptionReports are not watched
+var promotionRequeueInterval = time.Minute
+
+// syncPromotion updates the commit of each ring of the subscription promotion in its status. The first ring deploys
+// the subscription commit, a commit of a ring is promoted to the next ring once all the clusters of the ring have been
+// deployed during the soak duration of the next ring, within its timewindow and after its manual approval. The manual
+// approval is given by the promotion-approved annotation or by the ApprovalRequest created for the commit. The failed
+// clusters tolerated by the failure thresholds of the promotion don't hold the commit of a ring, more failed clusters
+// halt its promotion
+func (r *ReconcileSubscription) syncPromotion(sub *appSubV1.Subscription, channelType string, clusters []ManageClusters,
+	now time.Time) error {
+	if sub.Spec.Promotion == nil {
+		sub.Status.Promotion = nil
+
+		return nil
+	}
+
+	if channelType != chnv1.ChannelTypeGit && channelType != chnv1.ChannelTypeGitHub {
+		klog.Infof("the promotion of subscription %v/%v is ignored, channel type %v is not supported", sub.Namespace, sub.Name,
+			channelType)
+
+		sub.Status.Promotion = nil
+
+		return nil
+	}
+
+	// the hub suffixes the commit until it is propagated
+	head := strings.TrimSuffix(getCommitID(sub), commitIDSuffix)
+	if head == "" {
+		klog.Infof("the promotion of subscription %v/%v waits for the commit of the hub", sub.Namespace, sub.Name)
+
+		return nil
+	}
+
+	results, err := r.getClusterResults(sub)
+	if err != nil {
+		return err
+	}
+
+	groupClusters := map[string][]string{}
+
+	if pref := sub.Spec.Placement.PlacementRef; pref != nil {
+		groups, err := getDecisionGroupsFromPlacementRef(pref, sub.Namespace, r.Client)
+		if err != nil {
+			return err
+		}
+
+		for _, cluster := range clusters {
+			group := groups[cluster.Cluster]
+			groupClusters[group] = append(groupClusters[group], cluster.Cluster)
+		}
+	}
+
+	approvals := getPromotionApprovals(sub)
+
+	previous := map[string]appSubV1.PromotionRingStatus{}
+	for _, ringStatus := range sub.Status.Promotion {
+		previous[ringStatus.DecisionGroup] = ringStatus
+	}
+
+	ringStatuses := []appSubV1.PromotionRingStatus{}
+	approvalRequests := map[string]bool{}
+	halted := false
+
+	for i, ring := range sub.Spec.Promotion.Rings {
+		ringStatus := previous[ring.DecisionGroup]
+		ringStatus.DecisionGroup = ring.DecisionGroup
+
+		candidate := head
+		if i > 0 {
+			candidate = ringStatuses[i-1].Commit
+		}
+
+		promote := false
+
+		switch {
+		case ringStatus.Commit == candidate:
+			ringStatus.Phase = appSubV1.PromotionCurrent
+			ringStatus.PendingCommit = ""
+			ringStatus.ApprovalRequest = ""
+		case ringStatus.Commit == "" || i == 0:
+			// the first ring follows the subscription and a new ring starts on the commit of the previous ring
+			promote = true
+		default:
+			ringStatus.PendingCommit = candidate
+			ringStatus.ApprovalRequest = ""
+
+			if halted {
+				if ringStatus.Phase != appSubV1.PromotionHalted {
+					r.recordPromotionHalted(sub, candidate, ring.DecisionGroup, ringStatuses[i-1],
+						len(groupClusters[ringStatuses[i-1].DecisionGroup]))
+				}
+
+				ringStatus.Phase = appSubV1.PromotionHalted
+			} else {
+				ringStatus.Phase = getPromotionPhase(ring, ringStatuses[i-1], approvals[ring.DecisionGroup] == candidate, now)
+			}
+
+			if ringStatus.Phase == appSubV1.PromotionWaitingForApproval {
+				name, approved, err := r.syncApprovalRequest(sub, ring, candidate, groupClusters[ring.DecisionGroup], now)
+				if err != nil {
+					return err
+				}
+
+				ringStatus.ApprovalRequest = name
+				approvalRequests[name] = true
+
+				if approved {
+					ringStatus.Phase = appSubV1.PromotionCurrent
+				}
+			}
+
+			promote = ringStatus.Phase == appSubV1.PromotionCurrent
+		}
+
+		if promote {
+			klog.Infof("promoting commit %v of subscription %v/%v to decision group %v", candidate, sub.Namespace, sub.Name,
+				ring.DecisionGroup)
+
+			if r.eventRecorder != nil {
+				r.eventRecorder.RecordEvent(sub, "Promotion",
+					fmt.Sprintf("commit %v is promoted to decision group %v", candidate, ring.DecisionGroup), nil)
+			}
+
+			ringStatus.Phase = appSubV1.PromotionCurrent
+			ringStatus.Commit = candidate
+			ringStatus.PendingCommit = ""
+			ringStatus.ApprovalRequest = ""
+			ringStatus.PromotedTime = metav1.NewTime(now)
+			// the results of the clusters are the ones of the previous commit until they deploy the new one
+			ringStatus.HealthySince = nil
+			ringStatus.FailedClusters = nil
+			halted = false
+		} else {
+			healthy, failed := getRingHealth(sub.Spec.Promotion, groupClusters[ring.DecisionGroup], results)
+
+			ringStatus.FailedClusters = failed
+			halted = len(failed) > getToleratedFailures(sub.Spec.Promotion, len(groupClusters[ring.DecisionGroup]))
+
+			if !healthy {
+				ringStatus.HealthySince = nil
+			} else if ringStatus.HealthySince == nil {
+				healthySince := metav1.NewTime(now)
+				ringStatus.HealthySince = &healthySince
+			}
+		}
+
+		ringStatuses = append(ringStatuses, ringStatus)
+	}
+
+	sub.Status.Promotion = ringStatuses
+
+	if hasManualApproval(sub.Spec.Promotion) {
+		return r.deleteStaleApprovalRequests(sub, approvalRequests)
+	}
+
+	return nil
+}
+
+// hasManualApproval checks if a ring of the promotion requires a manual approval
+func hasManualApproval(promotion *appSubV1.Promotion) bool {
+	for _, ring := range promotion.Rings {
+		if ring.ManualApproval {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getPromotionPhase returns the phase of the ring while the commit of the previous ring waits to be promoted to it,
+// it is Current when the commit can be promoted
+func getPromotionPhase(ring appSubV1.PromotionRing, previous appSubV1.PromotionRingStatus, approved bool,
+	now time.Time) appSubV1.PromotionPhase {
+	if previous.HealthySince == nil || now.Sub(previous.HealthySince.Time) < ring.SoakDuration.Duration {
+		return appSubV1.PromotionSoaking
+	}
+
+	if ring.TimeWindow != nil && !utils.IsInWindow(ring.TimeWindow, now) {
+		return appSubV1.PromotionWaitingForWindow
+	}
+
+	if ring.ManualApproval && !approved {
+		return appSubV1.PromotionWaitingForApproval
+	}
+
+	return appSubV1.PromotionCurrent
+}
+
+// getRingHealth checks the ring has clusters and all of them are deployed, except the failed clusters tolerated by the
+// failure thresholds of the promotion. It also returns the sorted failed clusters of the ring
+func getRingHealth(promotion *appSubV1.Promotion, clusters []string,
+	results map[string]appSubStatusV1alpha1.SubscriptionResult) (bool, []string) {
+	var failed []string
+
+	deployed := 0
+
+	for _, cluster := range clusters {
+		switch results[cluster] {
+		case "deployed":
+			deployed++
+		case "failed", "propagationFailed":
+			failed = append(failed, cluster)
+		}
+	}
+
+	sort.Strings(failed)
+
+	healthy := len(clusters) > 0 && deployed+len(failed) == len(clusters) &&
+		len(failed) <= getToleratedFailures(promotion, len(clusters))
+
+	return healthy, failed
+}
+
+// getToleratedFailures returns the number of failed clusters tolerated in a ring of the given size, the lowest of
+// maxFailures and maxFailurePercentage of the ring size when both are set
+func getToleratedFailures(promotion *appSubV1.Promotion, clusters int) int {
+	if promotion.MaxFailures == nil && promotion.MaxFailurePercentage == nil {
+		return 0
+	}
+
+	tolerated := clusters
+
+	if promotion.MaxFailures != nil && int(*promotion.MaxFailures) < tolerated {
+		tolerated = int(*promotion.MaxFailures)
+	}
+
+	if promotion.MaxFailurePercentage != nil {
+		if percentage := clusters * int(*promotion.MaxFailurePercentage) / 100; percentage < tolerated {
+			tolerated = percentage
+		}
+	}
+
+	return tolerated
+}
+
+// recordPromotionHalted records a warning event listing the failed clusters of the ring halting the promotion of the
+// commit to the next ring
+func (r *ReconcileSubscription) recordPromotionHalted(sub *appSubV1.Subscription, commit, decisionGroup string,
+	previous appSubV1.PromotionRingStatus, clusters int) {
+	msg := fmt.Sprintf("the promotion of commit %v to decision group %v is halted, %v of the %v clusters of decision group %v failed: %v",
+		commit, decisionGroup, len(previous.FailedClusters), clusters, previous.DecisionGroup,
+		strings.Join(previous.FailedClusters, ", "))
+
+	klog.Infof("subscription %v/%v: %v", sub.Namespace, sub.Name, msg)
+
+	if r.eventRecorder != nil {
+		r.eventRecorder.RecordEvent(sub, "PromotionHalted", msg, fmt.Errorf("%v", msg))
+	}
+}
+
+// getClusterResults returns the result of the subscription on each cluster from the app SubscriptionReport
+func (r *ReconcileSubscription) getClusterResults(
+	sub *appSubV1.Subscription) (map[string]appSubStatusV1alpha1.SubscriptionResult, error) {
+	results := map[string]appSubStatusV1alpha1.SubscriptionResult{}
+
+	appsubReport := &appSubStatusV1alpha1.SubscriptionReport{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: sub.Name, Namespace: sub.Namespace}, appsubReport); err != nil {
+		if errors.IsNotFound(err) {
+			return results, nil
+		}
+
+		return nil, fmt.Errorf("failed to get app appsubReport %v/%v, err: %w", sub.Namespace, sub.Name, err)
+	}
+
+	for _, result := range appsubReport.Results {
+		if result != nil {
+			results[result.Source] = result.Result
+		}
+	}
+
+	return results, nil
+}
+
+// getPromotionApprovals returns the approved commit of each decision group from the promotion-approved annotation
+func getPromotionApprovals(sub *appSubV1.Subscription) map[string]string {
+	approvals := map[string]string{}
+
+	for _, approval := range strings.Split(sub.GetAnnotations()[appSubV1.AnnotationPromotionApproved], ",") {
+		group, commit, found := strings.Cut(strings.TrimSpace(approval), "=")
+		if found && group != "" && commit != "" {
+			approvals[group] = commit
+		}
+	}
+
+	return approvals
+}
+
+// getClusterPromotedCommit returns the commit pinned for the cluster by the promotion, it is empty for the clusters of
+// the first ring and outside of the rings, which deploy the subscription commit
+func getClusterPromotedCommit(instance *appSubV1.Subscription, cluster ManageClusters) string {
+	if instance.Spec.Promotion == nil || cluster.DecisionGroup == "" {
+		return ""
+	}
+
+	for i, ring := range instance.Spec.Promotion.Rings {
+		if ring.DecisionGroup != cluster.DecisionGroup || i == 0 {
+			continue
+		}
+
+		for _, ringStatus := range instance.Status.Promotion {
+			if ringStatus.DecisionGroup == ring.DecisionGroup {
+				return ringStatus.Commit
+			}
+		}
+	}
+
+	return ""
+}
+
+// promotionRequeueAfter returns when to check the promotion rings again, zero if no commit waits for the soak duration
+// or the timewindow of a ring, or is halted by the failed clusters of the previous ring. The rings waiting for an
+// approval are reconciled on the annotation or the ApprovalRequest update
+func promotionRequeueAfter(sub *appSubV1.Subscription) time.Duration {
+	for _, ringStatus := range sub.Status.Promotion {
+		switch ringStatus.Phase {
+		case appSubV1.PromotionSoaking, appSubV1.PromotionWaitingForWindow, appSubV1.PromotionHalted:
+			return promotionRequeueInterval
+		}
+	}
+
+	return 0
+}
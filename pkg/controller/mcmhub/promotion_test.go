@@ -0,0 +1,287 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterapi "open-cluster-management.io/api/cluster/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	plrv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appsubreportv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+)
+
+func TestSyncPromotion(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(clusterapi.AddToScheme(scheme)).To(gomega.Succeed())
+	g.Expect(appsubreportv1alpha1.AddToScheme(scheme)).To(gomega.Succeed())
+
+	decision := func(name, group string, clusters ...string) *clusterapi.PlacementDecision {
+		pd := &clusterapi.PlacementDecision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "demo-ns",
+				Labels:    map[string]string{placementLabel: "demo-placement", decisionGroupNameLabel: group},
+			},
+		}
+
+		for _, cluster := range clusters {
+			pd.Status.Decisions = append(pd.Status.Decisions, clusterapi.ClusterDecision{ClusterName: cluster})
+		}
+
+		return pd
+	}
+
+	report := &appsubreportv1alpha1.SubscriptionReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns"},
+		ReportType: "Application",
+		Results: []*appsubreportv1alpha1.SubscriptionReportResult{
+			{Source: "canary1", Result: "deployed"},
+			{Source: "prod1", Result: "failed"},
+		},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		decision("demo-placement-decision-1", "canary", "canary1"),
+		decision("demo-placement-decision-2", "prod", "prod1"),
+		report,
+	).Build()
+
+	r := &ReconcileSubscription{Client: c}
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "demo",
+			Namespace:   "demo-ns",
+			Annotations: map[string]string{appv1.AnnotationGitCommit: "c1"},
+		},
+		Spec: appv1.SubscriptionSpec{
+			Placement: &plrv1.Placement{
+				PlacementRef: &corev1.ObjectReference{Kind: "Placement", Name: "demo-placement"},
+			},
+			Promotion: &appv1.Promotion{
+				Rings: []appv1.PromotionRing{
+					{DecisionGroup: "canary"},
+					{DecisionGroup: "prod", SoakDuration: metav1.Duration{Duration: time.Hour}, ManualApproval: true},
+				},
+			},
+		},
+	}
+
+	clusters := []ManageClusters{{Cluster: "canary1", DecisionGroup: "canary"}, {Cluster: "prod1", DecisionGroup: "prod"}}
+	now := time.Now()
+
+	phases := func() []appv1.PromotionPhase {
+		phases := []appv1.PromotionPhase{}
+		for _, ringStatus := range sub.Status.Promotion {
+			phases = append(phases, ringStatus.Phase)
+		}
+
+		return phases
+	}
+
+	// the rings start on the subscription commit
+	g.Expect(r.syncPromotion(sub, chnv1.ChannelTypeGit, clusters, now)).To(gomega.Succeed())
+	g.Expect(sub.Status.Promotion).To(gomega.HaveLen(2))
+	g.Expect(sub.Status.Promotion[0].Commit).To(gomega.Equal("c1"))
+	g.Expect(sub.Status.Promotion[1].Commit).To(gomega.Equal("c1"))
+	g.Expect(phases()).To(gomega.Equal([]appv1.PromotionPhase{appv1.PromotionCurrent, appv1.PromotionCurrent}))
+	g.Expect(promotionRequeueAfter(sub)).To(gomega.BeZero())
+
+	// a new commit goes to the first ring and soaks there
+	sub.Annotations[appv1.AnnotationGitCommit] = "c2" + commitIDSuffix
+	g.Expect(r.syncPromotion(sub, chnv1.ChannelTypeGit, clusters, now)).To(gomega.Succeed())
+	g.Expect(sub.Status.Promotion[0].Commit).To(gomega.Equal("c2"))
+	g.Expect(sub.Status.Promotion[0].HealthySince).To(gomega.BeNil())
+	g.Expect(sub.Status.Promotion[1].PendingCommit).To(gomega.Equal("c2"))
+	g.Expect(phases()).To(gomega.Equal([]appv1.PromotionPhase{appv1.PromotionCurrent, appv1.PromotionSoaking}))
+	g.Expect(promotionRequeueAfter(sub)).To(gomega.Equal(promotionRequeueInterval))
+
+	g.Expect(getClusterPromotedCommit(sub, clusters[0])).To(gomega.BeEmpty())
+	g.Expect(getClusterPromotedCommit(sub, clusters[1])).To(gomega.Equal("c1"))
+	g.Expect(getClusterSubscription(sub, clusters[1]).GetAnnotations()).To(
+		gomega.HaveKeyWithValue(appv1.AnnotationGitTargetCommit, "c1"))
+
+	g.Expect(r.syncPromotion(sub, chnv1.ChannelTypeGit, clusters, now.Add(time.Minute))).To(gomega.Succeed())
+	g.Expect(sub.Status.Promotion[0].HealthySince).NotTo(gomega.BeNil())
+	g.Expect(sub.Status.Promotion[1].HealthySince).To(gomega.BeNil())
+
+	// the soak duration is over, the promotion waits for the approval of the commit
+	later := now.Add(2 * time.Hour)
+	g.Expect(r.syncPromotion(sub, chnv1.ChannelTypeGit, clusters, later)).To(gomega.Succeed())
+	g.Expect(phases()).To(gomega.Equal([]appv1.PromotionPhase{appv1.PromotionCurrent, appv1.PromotionWaitingForApproval}))
+	g.Expect(promotionRequeueAfter(sub)).To(gomega.BeZero())
+
+	sub.Annotations[appv1.AnnotationPromotionApproved] = "prod=c1, prod=c2"
+	g.Expect(r.syncPromotion(sub, chnv1.ChannelTypeGit, clusters, later)).To(gomega.Succeed())
+	g.Expect(sub.Status.Promotion[1].Commit).To(gomega.Equal("c2"))
+	g.Expect(sub.Status.Promotion[1].PendingCommit).To(gomega.BeEmpty())
+	g.Expect(sub.Status.Promotion[1].PromotedTime.Time).To(gomega.Equal(metav1.NewTime(later).Time))
+	g.Expect(phases()).To(gomega.Equal([]appv1.PromotionPhase{appv1.PromotionCurrent, appv1.PromotionCurrent}))
+
+	// the promotion is ignored by the other channel types
+	g.Expect(r.syncPromotion(sub, chnv1.ChannelTypeHelmRepo, clusters, later)).To(gomega.Succeed())
+	g.Expect(sub.Status.Promotion).To(gomega.BeNil())
+}
+
+func TestPromotionFailureThresholds(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	int32Ptr := func(i int32) *int32 { return &i }
+
+	// the failed clusters must stay within both thresholds
+	promotion := &appv1.Promotion{}
+	g.Expect(getToleratedFailures(promotion, 100)).To(gomega.Equal(0))
+
+	promotion.MaxFailurePercentage = int32Ptr(2)
+	g.Expect(getToleratedFailures(promotion, 100)).To(gomega.Equal(2))
+	g.Expect(getToleratedFailures(promotion, 49)).To(gomega.Equal(0))
+
+	promotion.MaxFailures = int32Ptr(1)
+	g.Expect(getToleratedFailures(promotion, 100)).To(gomega.Equal(1))
+
+	promotion.MaxFailurePercentage = nil
+	g.Expect(getToleratedFailures(promotion, 100)).To(gomega.Equal(1))
+
+	results := map[string]appsubreportv1alpha1.SubscriptionResult{
+		"edge1": "deployed",
+		"edge2": "failed",
+		"edge3": "deployed",
+		"edge4": "propagationFailed",
+	}
+
+	healthy, failed := getRingHealth(promotion, []string{"edge1", "edge2", "edge3"}, results)
+	g.Expect(healthy).To(gomega.BeTrue())
+	g.Expect(failed).To(gomega.Equal([]string{"edge2"}))
+
+	healthy, failed = getRingHealth(promotion, []string{"edge4", "edge3", "edge2"}, results)
+	g.Expect(healthy).To(gomega.BeFalse())
+	g.Expect(failed).To(gomega.Equal([]string{"edge2", "edge4"}))
+
+	// the clusters still deploying hold the ring
+	healthy, _ = getRingHealth(promotion, []string{"edge1", "edge5"}, results)
+	g.Expect(healthy).To(gomega.BeFalse())
+
+	// a systemic failure of the first ring halts the promotion to the next ring
+	scheme := runtime.NewScheme()
+	g.Expect(clusterapi.AddToScheme(scheme)).To(gomega.Succeed())
+	g.Expect(appsubreportv1alpha1.AddToScheme(scheme)).To(gomega.Succeed())
+
+	decision := func(name, group string, clusters ...string) *clusterapi.PlacementDecision {
+		pd := &clusterapi.PlacementDecision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "demo-ns",
+				Labels:    map[string]string{placementLabel: "demo-placement", decisionGroupNameLabel: group},
+			},
+		}
+
+		for _, cluster := range clusters {
+			pd.Status.Decisions = append(pd.Status.Decisions, clusterapi.ClusterDecision{ClusterName: cluster})
+		}
+
+		return pd
+	}
+
+	report := &appsubreportv1alpha1.SubscriptionReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns"},
+		ReportType: "Application",
+		Results: []*appsubreportv1alpha1.SubscriptionReportResult{
+			{Source: "edge1", Result: "deployed"},
+			{Source: "edge2", Result: "failed"},
+			{Source: "edge3", Result: "deployed"},
+		},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		decision("demo-placement-decision-1", "edge", "edge1", "edge2", "edge3"),
+		decision("demo-placement-decision-2", "prod", "prod1"),
+		report,
+	).Build()
+
+	r := &ReconcileSubscription{Client: c}
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "demo",
+			Namespace:   "demo-ns",
+			Annotations: map[string]string{appv1.AnnotationGitCommit: "c1"},
+		},
+		Spec: appv1.SubscriptionSpec{
+			Placement: &plrv1.Placement{
+				PlacementRef: &corev1.ObjectReference{Kind: "Placement", Name: "demo-placement"},
+			},
+			Promotion: &appv1.Promotion{
+				Rings:       []appv1.PromotionRing{{DecisionGroup: "edge"}, {DecisionGroup: "prod"}},
+				MaxFailures: int32Ptr(1),
+			},
+		},
+	}
+
+	clusters := []ManageClusters{
+		{Cluster: "edge1", DecisionGroup: "edge"},
+		{Cluster: "edge2", DecisionGroup: "edge"},
+		{Cluster: "edge3", DecisionGroup: "edge"},
+		{Cluster: "prod1", DecisionGroup: "prod"},
+	}
+	now := time.Now()
+
+	g.Expect(r.syncPromotion(sub, chnv1.ChannelTypeGit, clusters, now)).To(gomega.Succeed())
+
+	// a failed cluster is tolerated and listed in the status
+	sub.Annotations[appv1.AnnotationGitCommit] = "c2"
+	g.Expect(r.syncPromotion(sub, chnv1.ChannelTypeGit, clusters, now)).To(gomega.Succeed())
+	g.Expect(sub.Status.Promotion[0].Commit).To(gomega.Equal("c2"))
+	g.Expect(sub.Status.Promotion[1].Phase).To(gomega.Equal(appv1.PromotionSoaking))
+
+	g.Expect(r.syncPromotion(sub, chnv1.ChannelTypeGit, clusters, now.Add(time.Minute))).To(gomega.Succeed())
+	g.Expect(sub.Status.Promotion[0].FailedClusters).To(gomega.Equal([]string{"edge2"}))
+	g.Expect(sub.Status.Promotion[0].HealthySince).NotTo(gomega.BeNil())
+	g.Expect(sub.Status.Promotion[1].Commit).To(gomega.Equal("c2"))
+
+	// more failed clusters than tolerated halt the promotion of the next commit
+	report.Results[0].Result = "failed"
+	g.Expect(c.Update(context.TODO(), report)).To(gomega.Succeed())
+
+	sub.Annotations[appv1.AnnotationGitCommit] = "c3"
+	g.Expect(r.syncPromotion(sub, chnv1.ChannelTypeGit, clusters, now.Add(2*time.Minute))).To(gomega.Succeed())
+	g.Expect(r.syncPromotion(sub, chnv1.ChannelTypeGit, clusters, now.Add(3*time.Minute))).To(gomega.Succeed())
+	g.Expect(sub.Status.Promotion[0].FailedClusters).To(gomega.Equal([]string{"edge1", "edge2"}))
+	g.Expect(sub.Status.Promotion[0].HealthySince).To(gomega.BeNil())
+	g.Expect(sub.Status.Promotion[1].Commit).To(gomega.Equal("c2"))
+	g.Expect(sub.Status.Promotion[1].PendingCommit).To(gomega.Equal("c3"))
+	g.Expect(sub.Status.Promotion[1].Phase).To(gomega.Equal(appv1.PromotionHalted))
+	g.Expect(promotionRequeueAfter(sub)).To(gomega.Equal(promotionRequeueInterval))
+
+	// the promotion resumes once the failures are back within the thresholds
+	report.Results[0].Result = "deployed"
+	g.Expect(c.Update(context.TODO(), report)).To(gomega.Succeed())
+
+	g.Expect(r.syncPromotion(sub, chnv1.ChannelTypeGit, clusters, now.Add(4*time.Minute))).To(gomega.Succeed())
+	g.Expect(r.syncPromotion(sub, chnv1.ChannelTypeGit, clusters, now.Add(5*time.Minute))).To(gomega.Succeed())
+	g.Expect(sub.Status.Promotion[1].Commit).To(gomega.Equal("c3"))
+	g.Expect(sub.Status.Promotion[1].Phase).To(gomega.Equal(appv1.PromotionCurrent))
+}
@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	coreV1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -38,6 +39,16 @@ import (
 var manifestNSString string
 var manifestAppsubString string
 
+// hubName identifies the hub to the agents syncing to several hubs, it is set in the hub-name annotation of the
+// propagated subscriptions
+var hubName string
+
+// SetHubName sets the name of the hub set in the propagated subscriptions, the hub-name annotation of the hub
+// subscriptions is propagated if it is empty. It is called once before the controllers are set up
+func SetHubName(name string) {
+	hubName = name
+}
+
 func (r *ReconcileSubscription) PropagateAppSubManifestWork(instance *appSubV1.Subscription, clusters []ManageClusters) error {
 	// try to find all children manifestworks
 	children, err := r.getManifestWorkFamily(instance)
@@ -55,6 +66,16 @@ func (r *ReconcileSubscription) PropagateAppSubManifestWork(instance *appSubV1.S
 
 	klog.V(1).Infof("expiredManifestWorkmap: %#v", expiredManifestWorkmap)
 
+	// the ManifestWorks of the frozen clusters are not created, updated nor deleted during the change freezes
+	clusters, err = r.holdFrozenManifestWorks(instance, clusters, expiredManifestWorkmap)
+	if err != nil {
+		klog.Error("Error in holding the manifestWorks of the frozen clusters:", err)
+		return err
+	}
+
+	// the agents too old for the spec features of the subscription keep the ManifestWorks they apply
+	clusters = r.holdUnsupportedAgentManifestWorks(instance, clusters, expiredManifestWorkmap)
+
 	// propagate template
 	expiredManifestWorkmap, err = r.propagateManifestWorks(clusters, instance, expiredManifestWorkmap)
 	if err != nil {
@@ -62,6 +83,14 @@ func (r *ReconcileSubscription) PropagateAppSubManifestWork(instance *appSubV1.S
 		return err
 	}
 
+	// the hub-local subscription is kept while the hub is frozen, it is only deleted without the hub placement
+	if !utils.IsHubPlacement(instance) {
+		if err := r.deleteHubLocalSubscription(types.NamespacedName{Namespace: instance.Namespace, Name: instance.Name}); err != nil {
+			klog.Error("Error in deleting the hub-local subscription:", err)
+			return err
+		}
+	}
+
 	// delete expired appsub manifestWork
 	klog.Info("Expired manifestWork map:", expiredManifestWorkmap)
 
@@ -86,6 +115,65 @@ func (r *ReconcileSubscription) PropagateAppSubManifestWork(instance *appSubV1.S
 	return err
 }
 
+// holdFrozenManifestWorks removes the ManifestWorks of the clusters frozen by a ChangeFreeze from the expired ones and
+// pins them to the revision applied by the cluster. It returns the clusters to propagate the subscription to
+func (r *ReconcileSubscription) holdFrozenManifestWorks(instance *appSubV1.Subscription, clusters []ManageClusters,
+	familymap map[string]*manifestWorkV1.ManifestWork) ([]ManageClusters, error) {
+	clusterNames := []string{}
+	for _, cluster := range clusters {
+		clusterNames = append(clusterNames, cluster.Cluster)
+	}
+
+	for _, manifestWork := range familymap {
+		clusterNames = append(clusterNames, manifestWork.GetNamespace())
+	}
+
+	frozen, err := r.getFrozenClusters(instance, clusterNames, time.Now())
+	if err != nil || len(frozen) == 0 {
+		return clusters, err
+	}
+
+	revisions := r.getClusterRevisions(instance)
+
+	for key, manifestWork := range familymap {
+		cluster := manifestWork.GetNamespace()
+		if frozen[cluster] == "" {
+			continue
+		}
+
+		delete(familymap, key)
+
+		pinned, err := pinFrozenManifestWork(manifestWork, revisions[cluster])
+		if err != nil {
+			return nil, err
+		}
+
+		if pinned {
+			if err := r.Update(context.TODO(), manifestWork); err != nil {
+				return nil, fmt.Errorf("failed to pin manifestWork %v/%v, err: %w", manifestWork.Namespace, manifestWork.Name, err)
+			}
+
+			klog.Infof("Pinned the manifestWork %v/%v to revision %v during change freeze %v", manifestWork.Namespace,
+				manifestWork.Name, revisions[cluster], frozen[cluster])
+		}
+	}
+
+	propagated := []ManageClusters{}
+
+	for _, cluster := range clusters {
+		if freeze := frozen[cluster.Cluster]; freeze != "" {
+			klog.Infof("Skip propagating appsub %v/%v to cluster %v during change freeze %v", instance.Namespace,
+				instance.Name, cluster.Cluster, freeze)
+
+			continue
+		}
+
+		propagated = append(propagated, cluster)
+	}
+
+	return propagated, nil
+}
+
 func (r *ReconcileSubscription) getManifestWorkFamily(instance *appSubV1.Subscription) ([]*manifestWorkV1.ManifestWork, error) {
 	// get all existing manifestworks
 	exlist := &manifestWorkV1.ManifestWorkList{}
@@ -143,8 +231,19 @@ func (r *ReconcileSubscription) propagateManifestWorks(clusters []ManageClusters
 		return nil, err
 	}
 
+	if err := r.setClusterOverrideRuleInfo(instance, clusters); err != nil {
+		klog.Error("Failed to get the cluster info for the override rules, err:", err)
+
+		return nil, err
+	}
+
 	for _, cluster := range clusters {
-		familymap, err = r.createManifestWork(cluster, hosting, instance, familymap)
+		if cluster.IsHub {
+			err = r.applyHubLocalSubscription(cluster, hosting, instance)
+		} else {
+			familymap, err = r.createManifestWork(cluster, hosting, instance, familymap)
+		}
+
 		if err != nil {
 			klog.Errorf("Error in propagating to cluster: %v, error:%v", cluster.Cluster, err)
 
@@ -215,17 +314,34 @@ func (r *ReconcileSubscription) setLocalManifestWork(cluster ManageClusters, hos
 	appsub *appSubV1.Subscription, localManifestWork *manifestWorkV1.ManifestWork) (*manifestWorkV1.ManifestWork, error) {
 	newManifestAppsubByte := []byte(manifestAppsubString)
 
-	// if target cluster is local-cluster, append -local suffix to the appsub name to avoid subscription name collision in the same namespace
-	if cluster.IsLocalCluster {
-		klog.Info("This is local-cluster, Appending -local to the subscription name")
+	// the clusters matching the override rules get their own package overrides and Git branch and path
+	if clusterAppsub := getClusterSubscription(appsub, cluster); clusterAppsub != nil {
+		manifestClusterAppsubString, err := r.prepareManifestWorkAppsub(clusterAppsub, hosting)
+		if err != nil {
+			return nil, err
+		}
+
+		newManifestAppsubByte = []byte(manifestClusterAppsubString)
+	}
 
+	// if target cluster is local-cluster, append -local suffix to the appsub name to avoid subscription name collision in the same namespace.
+	// The cluster labels are passed to the agent to evaluate the exclude-clusters annotations of the resources
+	if cluster.IsLocalCluster || len(cluster.Labels) > 0 {
 		sub := &unstructured.Unstructured{}
 
 		err := json.Unmarshal(newManifestAppsubByte, sub)
 		if err != nil {
 			klog.Info("Failed to unmarshall manifestAppsub, err:", err, " |template: ", string(newManifestAppsubByte))
 		} else {
-			sub.SetName(sub.GetName() + "-local")
+			if cluster.IsLocalCluster {
+				klog.Info("This is local-cluster, Appending -local to the subscription name")
+
+				sub.SetName(sub.GetName() + "-local")
+			}
+
+			if err := setClusterLabelsAnnotation(sub, cluster.Labels); err != nil {
+				return nil, err
+			}
 		}
 
 		newManifestAppsubByte, err = json.Marshal(sub)
@@ -262,6 +378,21 @@ func (r *ReconcileSubscription) setLocalManifestWork(cluster ManageClusters, hos
 			},
 		},
 	}
+	localManifestWork.Spec.ManifestConfigs = nil
+
+	// regional hub gets the hub form of the subscription and re-propagates it to its own managed clusters
+	if cluster.IsRegionalHub && isHubOfHubsSub(appsub) {
+		klog.Infof("This is regional hub %v, propagating the hub subscription", cluster.Cluster)
+
+		manifests, err := r.prepareRegionalHubManifests(appsub, hosting)
+		if err != nil {
+			klog.Error("Failed to prepare regional hub manifests, err:", err)
+			return nil, err
+		}
+
+		localManifestWork.Spec.Workload.Manifests = manifests
+		localManifestWork.Spec.ManifestConfigs = regionalHubManifestConfigs(appsub)
+	}
 
 	localManifestWork.Spec.DeleteOption = &manifestWorkV1.DeleteOption{
 		PropagationPolicy: manifestWorkV1.DeletePropagationPolicyTypeSelectivelyOrphan,
@@ -309,7 +440,7 @@ func (r *ReconcileSubscription) prepareManifestWorkAppsub(appsub *appSubV1.Subsc
 
 	subep.Spec.Channel = appsub.Spec.Channel
 	subep.Spec.Package = appsub.Spec.Package
-	subep.Spec.PackageFilter = appsub.Spec.PackageFilter
+	subep.Spec.PackageFilter = utils.GetHeldPackageFilter(appsub)
 	subep.Spec.PackageOverrides = appsub.Spec.PackageOverrides
 	subep.Spec.Overrides = appsub.Spec.Overrides
 	subep.Spec.TimeWindow = appsub.Spec.TimeWindow
@@ -318,6 +449,8 @@ func (r *ReconcileSubscription) prepareManifestWorkAppsub(appsub *appSubV1.Subsc
 	subep.Spec.Deny = appsub.Spec.Deny
 	subep.Spec.WatchHelmNamespaceScopedResources = appsub.Spec.WatchHelmNamespaceScopedResources
 	subep.Spec.SecondaryChannel = appsub.Spec.SecondaryChannel
+	subep.Spec.Priority = appsub.Spec.Priority
+	subep.Spec.DependsOn = appsub.Spec.DependsOn
 
 	subepanno := r.updateSubAnnotations(appsub, hosting)
 	subep.SetAnnotations(subepanno)
@@ -325,6 +458,22 @@ func (r *ReconcileSubscription) prepareManifestWorkAppsub(appsub *appSubV1.Subsc
 	subepLabels := appsub.GetLabels()
 	subep.SetLabels(subepLabels)
 
+	// resolve the {{hub ... hub}} templates from the ConfigMaps, Secrets and prehooks in the appsub namespace
+	resolver := utils.NewSubscriptionHubTemplateResolver(r.Client, appsub)
+
+	if subep.Spec.PackageOverrides, err = resolver.ResolvePackageOverrides(subep.Spec.PackageOverrides); err != nil {
+		klog.Errorf("Failed to resolve the hub templates of appsub %v, err: %v", hosting.String(), err)
+		return "", err
+	}
+
+	resolvedAnno, err := resolver.ResolveAnnotations(subep.GetAnnotations())
+	if err != nil {
+		klog.Errorf("Failed to resolve the hub templates of appsub %v, err: %v", hosting.String(), err)
+		return "", err
+	}
+
+	subep.SetAnnotations(resolvedAnno)
+
 	klog.V(1).Infof("new local subep: %#v", subep)
 
 	manifestAppsubByte, err := json.Marshal(subep)
@@ -364,6 +513,12 @@ func prepareManifestWorkNS(appsubNS string, hosting types.NamespacedName) (strin
 }
 
 func (r *ReconcileSubscription) cleanupManifestWork(appsub types.NamespacedName) error {
+	if err := r.deleteHubLocalSubscription(appsub); err != nil {
+		klog.Error("Failed to delete the hub-local subscription, err:", err)
+
+		return err
+	}
+
 	manifestWorkList := &manifestWorkV1.ManifestWorkList{}
 	listopts := &client.ListOptions{}
 
@@ -471,6 +626,13 @@ func (r *ReconcileSubscription) updateSubAnnotations(sub *appSubV1.Subscription,
 
 	origsubanno := sub.GetAnnotations()
 
+	// the annotations propagated to the deployed resources by the agent
+	if propagation := sub.Spec.MetadataPropagation; propagation != nil && propagation.Annotations != nil {
+		for k, v := range utils.PropagatedMetadata(origsubanno, propagation.Annotations) {
+			subepanno[k] = v
+		}
+	}
+
 	// User and Group annotations
 	subepanno[appSubV1.AnnotationUserIdentity] = strings.Trim(origsubanno[appSubV1.AnnotationUserIdentity], "")
 	subepanno[appSubV1.AnnotationUserGroup] = strings.Trim(origsubanno[appSubV1.AnnotationUserGroup], "")
@@ -499,6 +661,10 @@ func (r *ReconcileSubscription) updateSubAnnotations(sub *appSubV1.Subscription,
 		subepanno[appSubV1.AnnotationBucketPath] = origsubanno[appSubV1.AnnotationBucketPath]
 	}
 
+	if !strings.EqualFold(origsubanno[appSubV1.AnnotationBucketLayout], "") {
+		subepanno[appSubV1.AnnotationBucketLayout] = origsubanno[appSubV1.AnnotationBucketLayout]
+	}
+
 	if !strings.EqualFold(origsubanno[appSubV1.AnnotationClusterAdmin], "") && r.AddClusterAdminAnnotation(sub) {
 		subepanno[appSubV1.AnnotationClusterAdmin] = origsubanno[appSubV1.AnnotationClusterAdmin]
 	}
@@ -519,6 +685,14 @@ func (r *ReconcileSubscription) updateSubAnnotations(sub *appSubV1.Subscription,
 		subepanno[appSubV1.AnnotationGitTag] = origsubanno[appSubV1.AnnotationGitTag]
 	}
 
+	if !strings.EqualFold(origsubanno[appSubV1.AnnotationGitPullRequest], "") {
+		subepanno[appSubV1.AnnotationGitPullRequest] = origsubanno[appSubV1.AnnotationGitPullRequest]
+	}
+
+	if !strings.EqualFold(origsubanno[appSubV1.AnnotationGitProvider], "") {
+		subepanno[appSubV1.AnnotationGitProvider] = origsubanno[appSubV1.AnnotationGitProvider]
+	}
+
 	if !strings.EqualFold(origsubanno[appSubV1.AnnotationGitCloneDepth], "") {
 		subepanno[appSubV1.AnnotationGitCloneDepth] = origsubanno[appSubV1.AnnotationGitCloneDepth]
 	}
@@ -531,6 +705,23 @@ func (r *ReconcileSubscription) updateSubAnnotations(sub *appSubV1.Subscription,
 		subepanno[appSubV1.AnnotationManualReconcileTime] = origsubanno[appSubV1.AnnotationManualReconcileTime]
 	}
 
+	if !strings.EqualFold(origsubanno[appSubV1.AnnotationRetryTime], "") {
+		subepanno[appSubV1.AnnotationRetryTime] = origsubanno[appSubV1.AnnotationRetryTime]
+	}
+
+	// The hub name of the hub takes precedence over the annotation of the hub subscription, the agents would release
+	// and claim the subscription again for another hub with a mistyped annotation
+	if hubName != "" {
+		if subHubName := origsubanno[appSubV1.AnnotationHubName]; subHubName != "" && subHubName != hubName {
+			klog.Warningf("subscription %v/%v hub-name annotation %v is replaced by the hub name %v",
+				sub.Namespace, sub.Name, subHubName, hubName)
+		}
+
+		subepanno[appSubV1.AnnotationHubName] = hubName
+	} else if !strings.EqualFold(origsubanno[appSubV1.AnnotationHubName], "") {
+		subepanno[appSubV1.AnnotationHubName] = origsubanno[appSubV1.AnnotationHubName]
+	}
+
 	// Keep cluster admin annotation from the source subscription.
 	if !strings.EqualFold(origsubanno[appSubV1.AnnotationClusterAdmin], "") {
 		subepanno[appSubV1.AnnotationClusterAdmin] = origsubanno[appSubV1.AnnotationClusterAdmin]
@@ -0,0 +1,137 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	manifestWorkV1 "open-cluster-management.io/api/work/v1"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/testharness"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+func TestPropagateAppSubManifestWork(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	sub := testharness.PlaceByPlacementRule(testharness.NewSubscription("demo-ns", "demo", "chn-ns/git"), "prod")
+	sub.Labels = map[string]string{"app": "demo"}
+	decision := testharness.NewPlacementRuleDecision("demo-ns", "prod", "cluster1", "local-cluster")
+
+	c, err := testharness.NewFakeClient(sub, decision,
+		testharness.NewManagedCluster("cluster1", nil),
+		testharness.NewManagedCluster("local-cluster", map[string]string{"local-cluster": "true"}),
+		// the placements of the other namespaces are ignored
+		testharness.NewPlacementRuleDecision("other-ns", "prod", "cluster2"))
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	r := &ReconcileSubscription{Client: c, eventRecorder: &utils.EventRecorder{EventRecorder: record.NewFakeRecorder(10)}}
+
+	propagate := func() map[string]manifestWorkV1.ManifestWork {
+		clusters, err := r.getClustersByPlacement(sub)
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+		g.Expect(r.PropagateAppSubManifestWork(sub, clusters)).To(gomega.Succeed())
+
+		works := &manifestWorkV1.ManifestWorkList{}
+		g.Expect(c.List(context.TODO(), works)).To(gomega.Succeed())
+
+		byCluster := map[string]manifestWorkV1.ManifestWork{}
+		for _, work := range works.Items {
+			byCluster[work.Namespace] = work
+		}
+
+		return byCluster
+	}
+
+	clusterSubscription := func(work manifestWorkV1.ManifestWork) *appv1.Subscription {
+		g.Expect(work.Spec.Workload.Manifests).To(gomega.HaveLen(2))
+
+		clusterSub := &appv1.Subscription{}
+		g.Expect(json.Unmarshal(work.Spec.Workload.Manifests[1].Raw, clusterSub)).To(gomega.Succeed())
+
+		return clusterSub
+	}
+
+	// one manifestWork per decided cluster with the namespace and the local subscription of the cluster
+	works := propagate()
+	g.Expect(works).To(gomega.HaveLen(2))
+
+	work := works["cluster1"]
+	g.Expect(work.Name).To(gomega.Equal("demo-ns-demo"))
+	g.Expect(work.Labels).To(gomega.HaveKeyWithValue(appv1.AnnotationHosting, "demo-ns.demo"))
+	g.Expect(string(work.Spec.Workload.Manifests[0].Raw)).To(gomega.ContainSubstring(`"name":"demo-ns"`))
+	g.Expect(work.Spec.DeleteOption.SelectivelyOrphan.OrphaningRules[0].Name).To(gomega.Equal("demo-ns"))
+
+	clusterSub := clusterSubscription(work)
+	g.Expect(clusterSub.Name).To(gomega.Equal("demo"))
+	g.Expect(clusterSub.Labels).To(gomega.Equal(map[string]string{"app": "demo"}))
+	g.Expect(clusterSub.Spec.Channel).To(gomega.Equal("chn-ns/git"))
+	g.Expect(*clusterSub.Spec.Placement.Local).To(gomega.BeTrue())
+	g.Expect(clusterSub.Annotations).To(gomega.HaveKeyWithValue(appv1.AnnotationHosting, "demo-ns/demo"))
+
+	// the subscription of the local cluster is renamed to not collide with the subscription of the hub
+	g.Expect(clusterSubscription(works["local-cluster"]).Name).To(gomega.Equal("demo-local"))
+
+	// the changes of the subscription are propagated to the existing manifestWorks
+	sub.Spec.Channel = "chn-ns/git2"
+	works = propagate()
+	g.Expect(clusterSubscription(works["cluster1"]).Spec.Channel).To(gomega.Equal("chn-ns/git2"))
+
+	// the manifestWorks of the clusters not decided anymore are deleted
+	decision.Status.Decisions = decision.Status.Decisions[:1]
+	g.Expect(c.Update(context.TODO(), decision)).To(gomega.Succeed())
+
+	works = propagate()
+	g.Expect(works).To(gomega.HaveKey("cluster1"))
+	g.Expect(works).NotTo(gomega.HaveKey("local-cluster"))
+
+	// the manifestWorks of a deleted subscription are cleaned up
+	g.Expect(r.cleanupManifestWork(types.NamespacedName{Namespace: "demo-ns", Name: "demo"})).To(gomega.Succeed())
+
+	remaining := &manifestWorkV1.ManifestWorkList{}
+	g.Expect(c.List(context.TODO(), remaining)).To(gomega.Succeed())
+	g.Expect(remaining.Items).To(gomega.BeEmpty())
+}
+
+func TestUpdateSubAnnotationsHubName(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	sub := testharness.NewSubscription("demo-ns", "demo", "chn-ns/git")
+	sub.Annotations = map[string]string{appv1.AnnotationHubName: "hub-typo"}
+
+	c, err := testharness.NewFakeClient(sub)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	r := &ReconcileSubscription{Client: c, eventRecorder: &utils.EventRecorder{EventRecorder: record.NewFakeRecorder(10)}}
+	hosting := types.NamespacedName{Namespace: "demo-ns", Name: "demo"}
+
+	// without the hub name the annotation of the hub subscription is propagated
+	g.Expect(r.updateSubAnnotations(sub, hosting)[appv1.AnnotationHubName]).To(gomega.Equal("hub-typo"))
+
+	// the hub name takes precedence over the annotation
+	SetHubName("hub2")
+	defer SetHubName("")
+
+	g.Expect(r.updateSubAnnotations(sub, hosting)[appv1.AnnotationHubName]).To(gomega.Equal("hub2"))
+
+	delete(sub.Annotations, appv1.AnnotationHubName)
+	g.Expect(r.updateSubAnnotations(sub, hosting)[appv1.AnnotationHubName]).To(gomega.Equal("hub2"))
+}
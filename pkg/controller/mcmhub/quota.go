@@ -0,0 +1,304 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appsubreportv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+// quotaUsage is the usage of a hub subscription counted by the SubscriptionQuotas of its namespace
+type quotaUsage struct {
+	sub       *appv1.Subscription
+	clusters  int64
+	resources int64
+}
+
+// isQuotaSubscription checks if the subscription is propagated by the hub and counts in the quotas of its namespace
+func isQuotaSubscription(sub *appv1.Subscription) bool {
+	pl := sub.Spec.Placement
+	if pl == nil || (pl.Local != nil && *pl.Local) {
+		return false
+	}
+
+	return !utils.IsHostingAppsub(sub) && sub.GetDeletionTimestamp().IsZero()
+}
+
+// isOverQuota checks if the subscription is held back by the QuotaExceeded condition
+func isOverQuota(sub *appv1.Subscription) bool {
+	return meta.IsStatusConditionTrue(sub.Status.Conditions, appv1.ConditionQuotaExceeded)
+}
+
+// checkSubscriptionQuota checks the subscription against the SubscriptionQuotas of its namespace before it is propagated
+// to the clusters with the resources. The subscriptions are admitted by creation order, the older subscriptions are never
+// held back by a newer one. The QuotaExceeded condition of the subscription and the usage of the quotas are updated, an
+// error is returned if the subscription exceeds a quota.
+func (r *ReconcileSubscription) checkSubscriptionQuota(sub *appv1.Subscription, clusterCount, resourceCount int) error {
+	quotaList := &appsubreportv1alpha1.SubscriptionQuotaList{}
+	if err := r.List(context.TODO(), quotaList, client.InNamespace(sub.Namespace)); err != nil {
+		if meta.IsNoMatchError(err) {
+			meta.RemoveStatusCondition(&sub.Status.Conditions, appv1.ConditionQuotaExceeded)
+
+			return nil
+		}
+
+		return fmt.Errorf("failed to list the subscription quotas of namespace %v: %w", sub.Namespace, err)
+	}
+
+	if len(quotaList.Items) == 0 {
+		meta.RemoveStatusCondition(&sub.Status.Conditions, appv1.ConditionQuotaExceeded)
+
+		return nil
+	}
+
+	usages, err := getQuotaUsages(r.Client, sub, clusterCount, resourceCount)
+	if err != nil {
+		return err
+	}
+
+	var quotaErr error
+
+	for i := range quotaList.Items {
+		quota := &quotaList.Items[i]
+
+		if reason := admitSubscription(quota, usages, sub); reason != "" && quotaErr == nil {
+			quotaErr = fmt.Errorf("subscription quota %v exceeded: %v", quota.Name, reason)
+		}
+
+		if err := r.updateQuotaStatus(quota, usages); err != nil {
+			klog.Errorf("failed to update the status of subscription quota %v/%v, err: %v", quota.Namespace, quota.Name, err)
+		}
+	}
+
+	if quotaErr != nil {
+		meta.SetStatusCondition(&sub.Status.Conditions, metav1.Condition{
+			Type:               appv1.ConditionQuotaExceeded,
+			Status:             metav1.ConditionTrue,
+			Reason:             appv1.ReasonOverQuota,
+			Message:            quotaErr.Error(),
+			ObservedGeneration: sub.GetGeneration(),
+		})
+
+		return quotaErr
+	}
+
+	meta.RemoveStatusCondition(&sub.Status.Conditions, appv1.ConditionQuotaExceeded)
+
+	return nil
+}
+
+// getQuotaUsages returns the usage of the hub subscriptions of the namespace sorted by creation order. The usage of the
+// subscription is the clusters and resources it is about to propagate, the other subscriptions count the clusters and
+// resources of their SubscriptionReport.
+func getQuotaUsages(clt client.Reader, sub *appv1.Subscription, clusterCount, resourceCount int) ([]quotaUsage, error) {
+	subList := &appv1.SubscriptionList{}
+	if err := clt.List(context.TODO(), subList, client.InNamespace(sub.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list the subscriptions of namespace %v: %w", sub.Namespace, err)
+	}
+
+	reportList := &appsubreportv1alpha1.SubscriptionReportList{}
+	if err := clt.List(context.TODO(), reportList, client.InNamespace(sub.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list the subscription reports of namespace %v: %w", sub.Namespace, err)
+	}
+
+	reports := map[string]*appsubreportv1alpha1.SubscriptionReport{}
+
+	for i := range reportList.Items {
+		if reportList.Items[i].ReportType == "Application" {
+			reports[reportList.Items[i].Name] = &reportList.Items[i]
+		}
+	}
+
+	usages := []quotaUsage{{sub: sub, clusters: int64(clusterCount), resources: int64(resourceCount)}}
+
+	for i := range subList.Items {
+		item := &subList.Items[i]
+		if item.Name == sub.Name || !isQuotaSubscription(item) {
+			continue
+		}
+
+		usage := quotaUsage{sub: item}
+
+		if report, ok := reports[item.Name]; ok {
+			clusters, err := strconv.ParseInt(report.Summary.Clusters, 10, 64)
+			if err == nil {
+				usage.clusters = clusters
+			}
+
+			usage.resources = int64(len(report.Resources))
+		}
+
+		usages = append(usages, usage)
+	}
+
+	sort.SliceStable(usages, func(i, j int) bool {
+		ti, tj := usages[i].sub.GetCreationTimestamp(), usages[j].sub.GetCreationTimestamp()
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+
+		return usages[i].sub.Name < usages[j].sub.Name
+	})
+
+	return usages, nil
+}
+
+// validateSubscriptionQuota rejects a new hub subscription exceeding the SubscriptionQuotas of its namespace. Its
+// clusters and resources are not known before it is reconciled, so it is admitted as the newest subscription without
+// clusters and resources, they are checked by checkSubscriptionQuota when it is propagated.
+func validateSubscriptionQuota(clt client.Reader, sub *appv1.Subscription) error {
+	if !isQuotaSubscription(sub) {
+		return nil
+	}
+
+	newSub := sub.DeepCopy()
+	newSub.SetCreationTimestamp(metav1.Now())
+
+	reason, err := planQuota(clt, newSub, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	if reason != "" {
+		return errors.New(reason)
+	}
+
+	return nil
+}
+
+// admitSubscription returns the exceeded limits of the quota if the subscription is admitted after the older
+// subscriptions that are not over quota, an empty string is returned if the subscription fits in the quota
+func admitSubscription(quota *appsubreportv1alpha1.SubscriptionQuota, usages []quotaUsage, sub *appv1.Subscription) string {
+	used := appsubreportv1alpha1.SubscriptionQuotaUsage{}
+
+	for _, usage := range usages {
+		isSub := usage.sub.Name == sub.Name
+		if !isSub && isOverQuota(usage.sub) {
+			continue
+		}
+
+		used.Subscriptions++
+		used.Clusters += usage.clusters
+		used.Resources += usage.resources
+
+		if isSub {
+			break
+		}
+	}
+
+	return exceededLimits(quota.Spec, used)
+}
+
+// exceededLimits returns the limits of the quota exceeded by the usage, separated by commas
+func exceededLimits(spec appsubreportv1alpha1.SubscriptionQuotaSpec, used appsubreportv1alpha1.SubscriptionQuotaUsage) string {
+	exceeded := []string{}
+
+	if spec.MaxSubscriptions != nil && used.Subscriptions > *spec.MaxSubscriptions {
+		exceeded = append(exceeded, fmt.Sprintf("subscriptions %v > %v", used.Subscriptions, *spec.MaxSubscriptions))
+	}
+
+	if spec.MaxClusters != nil && used.Clusters > *spec.MaxClusters {
+		exceeded = append(exceeded, fmt.Sprintf("clusters %v > %v", used.Clusters, *spec.MaxClusters))
+	}
+
+	if spec.MaxResources != nil && used.Resources > *spec.MaxResources {
+		exceeded = append(exceeded, fmt.Sprintf("resources %v > %v", used.Resources, *spec.MaxResources))
+	}
+
+	return strings.Join(exceeded, ", ")
+}
+
+// updateQuotaStatus sets the usage of all the hub subscriptions of the namespace in the quota status, the Exceeded
+// condition is true if the usage exceeds one of the limits
+func (r *ReconcileSubscription) updateQuotaStatus(quota *appsubreportv1alpha1.SubscriptionQuota, usages []quotaUsage) error {
+	newQuota := quota.DeepCopy()
+	newQuota.Status.Used = appsubreportv1alpha1.SubscriptionQuotaUsage{}
+
+	for _, usage := range usages {
+		newQuota.Status.Used.Subscriptions++
+		newQuota.Status.Used.Clusters += usage.clusters
+		newQuota.Status.Used.Resources += usage.resources
+	}
+
+	condition := metav1.Condition{
+		Type:               appsubreportv1alpha1.QuotaConditionExceeded,
+		Status:             metav1.ConditionFalse,
+		Reason:             "WithinQuota",
+		Message:            "the subscriptions of the namespace are within the quota",
+		ObservedGeneration: quota.GetGeneration(),
+	}
+
+	if exceeded := exceededLimits(quota.Spec, newQuota.Status.Used); exceeded != "" {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = appv1.ReasonOverQuota
+		condition.Message = exceeded
+	}
+
+	meta.SetStatusCondition(&newQuota.Status.Conditions, condition)
+
+	if reflect.DeepEqual(quota.Status, newQuota.Status) {
+		return nil
+	}
+
+	return r.Status().Update(context.TODO(), newQuota)
+}
+
+type quotaMapper struct {
+	client.Client
+}
+
+// Map enqueues the hub subscriptions of the namespace of the quota, the subscriptions over quota are admitted again
+// when the quota is raised or deleted
+func (mapper *quotaMapper) Map(obj client.Object) []reconcile.Request {
+	var requests []reconcile.Request
+
+	subList := &appv1.SubscriptionList{}
+	if err := mapper.List(context.TODO(), subList, client.InNamespace(obj.GetNamespace())); err != nil {
+		klog.Error("Listing the subscriptions in quotaMapper and got error:", err)
+
+		return nil
+	}
+
+	for i := range subList.Items {
+		if !isQuotaSubscription(&subList.Items[i]) {
+			continue
+		}
+
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+			Name:      subList.Items[i].GetName(),
+			Namespace: subList.Items[i].GetNamespace(),
+		}})
+	}
+
+	klog.V(1).Info("Out quota mapper with requests:", requests)
+
+	return requests
+}
@@ -0,0 +1,126 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	plrv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appsubreportv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+)
+
+func TestCheckSubscriptionQuota(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(appv1.SchemeBuilder.AddToScheme(scheme)).To(gomega.Succeed())
+	g.Expect(appsubreportv1alpha1.SchemeBuilder.AddToScheme(scheme)).To(gomega.Succeed())
+
+	newSub := func(name string, age time.Duration) *appv1.Subscription {
+		return &appv1.Subscription{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "tenant",
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+			},
+			Spec: appv1.SubscriptionSpec{Placement: &plrv1.Placement{}},
+		}
+	}
+
+	older := newSub("older", 2*time.Hour)
+	newer := newSub("newer", time.Hour)
+
+	olderReport := &appsubreportv1alpha1.SubscriptionReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "older", Namespace: "tenant"},
+		ReportType: "Application",
+		Summary:    appsubreportv1alpha1.SubscriptionReportSummary{Clusters: "3"},
+		Resources:  []*v1.ObjectReference{{Kind: "ConfigMap", Name: "cm1"}, {Kind: "ConfigMap", Name: "cm2"}},
+	}
+
+	maxClusters := int64(4)
+	quota := &appsubreportv1alpha1.SubscriptionQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant-quota", Namespace: "tenant"},
+		Spec:       appsubreportv1alpha1.SubscriptionQuotaSpec{MaxClusters: &maxClusters},
+	}
+
+	clt := fake.NewClientBuilder().WithScheme(scheme).WithObjects(older, newer, olderReport, quota).Build()
+	r := &ReconcileSubscription{Client: clt}
+
+	// the newer subscription exceeds the clusters left by the older one
+	err := r.checkSubscriptionQuota(newer, 2, 1)
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("clusters 5 > 4"))
+	g.Expect(isOverQuota(newer)).To(gomega.BeTrue())
+
+	got := &appsubreportv1alpha1.SubscriptionQuota{}
+	g.Expect(clt.Get(context.TODO(), types.NamespacedName{Name: "tenant-quota", Namespace: "tenant"}, got)).To(gomega.Succeed())
+	g.Expect(got.Status.Used).To(gomega.Equal(appsubreportv1alpha1.SubscriptionQuotaUsage{Subscriptions: 2, Clusters: 5, Resources: 3}))
+	g.Expect(meta.IsStatusConditionTrue(got.Status.Conditions, appsubreportv1alpha1.QuotaConditionExceeded)).To(gomega.BeTrue())
+
+	g.Expect(clt.Status().Update(context.TODO(), newer)).To(gomega.Succeed())
+
+	// the older subscription is never held back by the newer one
+	g.Expect(r.checkSubscriptionQuota(older, 4, 2)).To(gomega.Succeed())
+	g.Expect(meta.FindStatusCondition(older.Status.Conditions, appv1.ConditionQuotaExceeded)).To(gomega.BeNil())
+
+	// the newer subscription fits once the quota is raised
+	maxClusters = 6
+	g.Expect(clt.Get(context.TODO(), types.NamespacedName{Name: "tenant-quota", Namespace: "tenant"}, got)).To(gomega.Succeed())
+	got.Spec.MaxClusters = &maxClusters
+	g.Expect(clt.Update(context.TODO(), got)).To(gomega.Succeed())
+
+	g.Expect(r.checkSubscriptionQuota(newer, 2, 1)).To(gomega.Succeed())
+	g.Expect(isOverQuota(newer)).To(gomega.BeFalse())
+
+	// a subscription in a namespace without quota is not limited
+	other := newSub("other", 0)
+	other.Namespace = "other"
+	g.Expect(r.checkSubscriptionQuota(other, 100, 100)).To(gomega.Succeed())
+
+	// the webhook rejects a new subscription once the quota is used up, the newer subscription held back by the quota
+	// is not counted. The reconcile still checks the clusters and resources of the admitted ones
+	maxSubscriptions := int64(1)
+	g.Expect(clt.Get(context.TODO(), types.NamespacedName{Name: "tenant-quota", Namespace: "tenant"}, got)).To(gomega.Succeed())
+	got.Spec.MaxSubscriptions = &maxSubscriptions
+	g.Expect(clt.Update(context.TODO(), got)).To(gomega.Succeed())
+
+	v := &SubscriptionValidator{Client: clt}
+
+	err = v.ValidateCreate(context.TODO(), newSub("newest", 0))
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("subscription quota tenant-quota exceeded: subscriptions 2 > 1"))
+
+	local := newSub("local", 0)
+	local.Spec.Placement.Local = &[]bool{true}[0]
+	g.Expect(v.ValidateCreate(context.TODO(), local)).To(gomega.Succeed())
+
+	g.Expect(v.ValidateCreate(context.TODO(), other)).To(gomega.Succeed())
+
+	maxSubscriptions = 2
+	got.Spec.MaxSubscriptions = &maxSubscriptions
+	g.Expect(clt.Update(context.TODO(), got)).To(gomega.Succeed())
+	g.Expect(v.ValidateCreate(context.TODO(), newSub("newest", 0))).To(gomega.Succeed())
+}
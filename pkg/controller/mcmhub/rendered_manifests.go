@@ -0,0 +1,419 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"helm.sh/helm/v3/pkg/chartutil"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	releasev1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/helmrelease/v1"
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+const (
+	// renderedManifestsSuffix is the name suffix of the ConfigMaps holding the manifests rendered for a cluster
+	renderedManifestsSuffix = "-rendered"
+	// renderedManifestsKey is the gzip compressed multi-document YAML of the rendered manifests
+	renderedManifestsKey = "manifests.yaml.gz"
+	// renderedRevisionKey is the commit ID or the chart versions of the rendered manifests
+	renderedRevisionKey = "revision"
+	// renderedResourcesKey is the number of rendered manifests
+	renderedResourcesKey = "resources"
+	// maxRenderedManifestsSize leaves room for the metadata in the 1MiB limit of a ConfigMap
+	maxRenderedManifestsSize = 1000 * 1024
+)
+
+// isRenderedManifestsEnabled checks if the rendered-manifests annotation of the subscription is "true"
+func isRenderedManifestsEnabled(sub *appv1.Subscription) bool {
+	return strings.EqualFold(sub.GetAnnotations()[appv1.AnnotationRenderedManifests], "true")
+}
+
+// renderedManifestsLabel is the value of the rendered-manifests-of label, it is the hosting label of the manifestWorks
+func renderedManifestsLabel(appsub types.NamespacedName) string {
+	return fmt.Sprintf("%.63s", appsub.Namespace+"."+appsub.Name)
+}
+
+// syncRenderedManifests stores the manifests rendered for each cluster of the subscription, after the package overrides
+// and the override rules of the cluster, in a ConfigMap of the cluster namespace. The manifests are rendered once per
+// distinct set of cluster overrides. The ConfigMaps of the clusters no longer targeted are removed, so are all of them
+// when the annotation is removed from the subscription
+func (r *ReconcileSubscription) syncRenderedManifests(sub *appv1.Subscription, channelType string,
+	helmRls []*releasev1.HelmRelease, clusters []ManageClusters, isAdmin bool) error {
+	appsub := types.NamespacedName{Namespace: sub.GetNamespace(), Name: sub.GetName()}
+
+	if !isRenderedManifestsEnabled(sub) {
+		return r.cleanupRenderedManifests(appsub, nil)
+	}
+
+	var revision string
+
+	switch channelType {
+	case chnv1.ChannelTypeGit, chnv1.ChannelTypeGitHub:
+		revision = getCommitID(sub)
+	case chnv1.ChannelTypeHelmRepo:
+		revision = helmReleasesRevision(helmRls)
+	default:
+		klog.Infof("the rendered manifests of subscription %v are not stored, channel type %v is not supported", appsub, channelType)
+
+		return r.cleanupRenderedManifests(appsub, nil)
+	}
+
+	rendered := map[string][]string{}
+	targeted := map[string]bool{}
+	errs := []string{}
+
+	for _, cluster := range clusters {
+		// the hub only clones the subscription branch and path, the clusters on other ones are not rendered
+		if branch, path := getClusterGitOverrides(sub, cluster); branch != "" || path != "" {
+			klog.Infof("the rendered manifests of subscription %v are not stored for cluster %v, its override rules set "+
+				"the git branch %q and path %q", appsub, cluster.Cluster, branch, path)
+
+			continue
+		}
+
+		if commit := getClusterPromotedCommit(sub, cluster); commit != "" && commit != revision {
+			klog.Infof("the rendered manifests of subscription %v are not stored for cluster %v, its promotion ring is on "+
+				"commit %v", appsub, cluster.Cluster, commit)
+
+			continue
+		}
+
+		// the configmap of the last successful render is kept if the render fails
+		targeted[cluster.Cluster] = true
+
+		overrides := getClusterPackageOverrides(sub, cluster)
+
+		overridesKey, err := json.Marshal(overrides)
+		if err != nil {
+			errs = append(errs, err.Error())
+
+			continue
+		}
+
+		// the helm charts are rendered once per distinct set of cluster overrides and capabilities
+		renderKey := string(overridesKey)
+
+		var caps *chartutil.Capabilities
+
+		if channelType == chnv1.ChannelTypeHelmRepo && isHelmClusterCapabilitiesEnabled(sub) {
+			var capsKey string
+
+			caps, capsKey = clusterHelmCapabilities(r.getHeartbeatLease(cluster.Cluster))
+			renderKey += "|" + capsKey
+		}
+
+		manifests, ok := rendered[renderKey]
+		if !ok {
+			clusterSub := sub.DeepCopy()
+			if overrides != nil {
+				clusterSub.Spec.PackageOverrides = overrides
+			}
+
+			manifests, err = r.renderManifests(clusterSub, channelType, helmRls, isAdmin, caps)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("cluster %v: %v", cluster.Cluster, err))
+
+				continue
+			}
+
+			rendered[renderKey] = manifests
+		}
+
+		cm, err := buildRenderedManifestsConfigMap(appsub, cluster.Cluster, revision, manifests)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("cluster %v: %v", cluster.Cluster, err))
+
+			continue
+		}
+
+		if err := r.saveRenderedManifests(cm); err != nil {
+			errs = append(errs, fmt.Sprintf("cluster %v: %v", cluster.Cluster, err))
+		}
+	}
+
+	if err := r.cleanupRenderedManifests(appsub, targeted); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to store the rendered manifests: %v", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// renderManifests returns the manifests of the subscription, its package overrides are the ones of the cluster. The
+// helm charts are rendered with the capabilities of the cluster if they are set
+func (r *ReconcileSubscription) renderManifests(sub *appv1.Subscription, channelType string,
+	helmRls []*releasev1.HelmRelease, isAdmin bool, caps *chartutil.Capabilities) ([]string, error) {
+	if channelType == chnv1.ChannelTypeHelmRepo {
+		return r.renderHelmManifests(sub, helmRls, caps)
+	}
+
+	return r.renderGitManifests(sub, isAdmin)
+}
+
+// renderHelmManifests applies the package overrides of the subscription to the HelmReleases and returns the manifests
+// of their install dry-run
+func (r *ReconcileSubscription) renderHelmManifests(sub *appv1.Subscription, helmRls []*releasev1.HelmRelease,
+	caps *chartutil.Capabilities) ([]string, error) {
+	manifests := []string{}
+
+	for _, helmRl := range helmRls {
+		helmRelease := helmRl.DeepCopy()
+
+		if err := utils.Override(helmRelease, sub); err != nil {
+			return nil, err
+		}
+
+		rel, _, _, err := installDryRun(r.Client, r.restMapper, rest.CopyConfig(r.cfg), helmRelease, caps)
+		if err != nil {
+			return nil, err
+		}
+
+		if rel == nil {
+			return nil, fmt.Errorf("failed to render chart %v version %v", helmRelease.Repo.ChartName, helmRelease.Repo.Version)
+		}
+
+		for _, manifest := range utils.ParseKubeResoures([]byte(rel.Manifest)) {
+			manifests = append(manifests, string(manifest))
+		}
+	}
+
+	return manifests, nil
+}
+
+// renderGitManifests returns the manifests of the hub clone of the git repo with the namespace and the package overrides
+// set the same way as the git subscriber. The kustomizations are built as overridden by the package overrides of the
+// subscription, the helm charts of the repo are not rendered
+func (r *ReconcileSubscription) renderGitManifests(sub *appv1.Subscription, isAdmin bool) ([]string, error) {
+	resourcePath := getResourcePath(r.hubGitOps.ResolveLocalGitFolder, sub)
+
+	_, kustomizeDirs, crdsAndNamespaceFiles, rbacFiles, otherFiles, err := utils.SortResources(r.hubGitOps.ResolveLocalGitFolder(sub), resourcePath)
+	if err != nil {
+		return nil, err
+	}
+
+	items := [][]byte{}
+
+	for _, rscFiles := range [][]string{crdsAndNamespaceFiles, rbacFiles, otherFiles} {
+		for _, rscFile := range rscFiles {
+			dir, _ := filepath.Split(rscFile)
+
+			if strings.HasSuffix(dir, PrehookDirSuffix) || strings.HasSuffix(dir, PosthookDirSuffix) {
+				continue
+			}
+
+			file, err := ioutil.ReadFile(rscFile) // #nosec G304 rscFile is not user input
+			if err != nil {
+				return nil, err
+			}
+
+			items = append(items, utils.ParseKubeResoures(file)...)
+		}
+	}
+
+	dirs := make([]string, 0, len(kustomizeDirs))
+
+	for _, kustomizeDir := range kustomizeDirs {
+		dirs = append(dirs, kustomizeDir)
+	}
+
+	sort.Strings(dirs)
+
+	for _, kustomizeDir := range dirs {
+		out, err := utils.RunKustomizeBuild(kustomizeDir)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, utils.ParseKubeResoures(out)...)
+	}
+
+	manifests := []string{}
+
+	for _, item := range items {
+		rsc := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal(item, rsc); err != nil {
+			return nil, err
+		}
+
+		gvk := rsc.GroupVersionKind()
+
+		if r.IsNamespacedResource(gvk.Group, gvk.Version, gvk.Kind) {
+			if !isAdmin || rsc.GetNamespace() == "" || strings.EqualFold(sub.GetAnnotations()[appv1.AnnotationCurrentNamespaceScoped], "true") {
+				rsc.SetNamespace(sub.Namespace)
+			}
+		}
+
+		if sub.Spec.PackageOverrides != nil {
+			rsc, err = utils.OverrideResourceBySubscription(rsc, rsc.GetName(), sub)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		manifest, err := yaml.Marshal(rsc.Object)
+		if err != nil {
+			return nil, err
+		}
+
+		manifests = append(manifests, string(manifest))
+	}
+
+	return manifests, nil
+}
+
+// helmReleasesRevision returns the chart versions of the HelmReleases, sorted by chart name
+func helmReleasesRevision(helmRls []*releasev1.HelmRelease) string {
+	versions := []string{}
+
+	for _, helmRl := range helmRls {
+		versions = append(versions, helmRl.Repo.ChartName+":"+helmRl.Repo.Version)
+	}
+
+	sort.Strings(versions)
+
+	return strings.Join(versions, ",")
+}
+
+// buildRenderedManifestsConfigMap returns the ConfigMap of the manifests rendered for the cluster, the manifests are
+// gzip compressed to fit the ConfigMap size limit
+func buildRenderedManifestsConfigMap(appsub types.NamespacedName, cluster, revision string, manifests []string) (*corev1.ConfigMap, error) {
+	var buf bytes.Buffer
+
+	gz := gzip.NewWriter(&buf)
+
+	for i, manifest := range manifests {
+		if i > 0 {
+			if _, err := io.WriteString(gz, "---\n"); err != nil {
+				return nil, err
+			}
+		}
+
+		if _, err := io.WriteString(gz, strings.TrimSpace(manifest)+"\n"); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	if buf.Len() > maxRenderedManifestsSize {
+		return nil, fmt.Errorf("the compressed manifests exceed %v bytes", maxRenderedManifestsSize)
+	}
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      appsub.Namespace + "-" + appsub.Name + renderedManifestsSuffix,
+			Namespace: cluster,
+			Labels: map[string]string{
+				appv1.LabelRenderedManifestsOf: renderedManifestsLabel(appsub),
+			},
+			Annotations: map[string]string{
+				appv1.AnnotationHosting: appsub.String(),
+			},
+		},
+		Data: map[string]string{
+			renderedRevisionKey:  revision,
+			renderedResourcesKey: strconv.Itoa(len(manifests)),
+		},
+		BinaryData: map[string][]byte{
+			renderedManifestsKey: buf.Bytes(),
+		},
+	}, nil
+}
+
+// saveRenderedManifests creates or updates the ConfigMap, it is not updated if the manifests didn't change
+func (r *ReconcileSubscription) saveRenderedManifests(cm *corev1.ConfigMap) error {
+	existing := &corev1.ConfigMap{}
+
+	err := r.Get(context.TODO(), types.NamespacedName{Name: cm.Name, Namespace: cm.Namespace}, existing)
+	if kerrors.IsNotFound(err) {
+		klog.V(1).Infof("creating the rendered manifests configmap %v/%v", cm.Namespace, cm.Name)
+
+		return r.Create(context.TODO(), cm)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if existing.Data[renderedRevisionKey] == cm.Data[renderedRevisionKey] &&
+		existing.Data[renderedResourcesKey] == cm.Data[renderedResourcesKey] &&
+		bytes.Equal(existing.BinaryData[renderedManifestsKey], cm.BinaryData[renderedManifestsKey]) &&
+		existing.Labels[appv1.LabelRenderedManifestsOf] == cm.Labels[appv1.LabelRenderedManifestsOf] {
+		return nil
+	}
+
+	existing.Labels = cm.Labels
+	existing.Annotations = cm.Annotations
+	existing.Data = cm.Data
+	existing.BinaryData = cm.BinaryData
+
+	klog.V(1).Infof("updating the rendered manifests configmap %v/%v", cm.Namespace, cm.Name)
+
+	return r.Update(context.TODO(), existing)
+}
+
+// cleanupRenderedManifests deletes the rendered manifests ConfigMaps of the subscription in the cluster namespaces that
+// are not kept, all of them are deleted if keep is nil
+func (r *ReconcileSubscription) cleanupRenderedManifests(appsub types.NamespacedName, keep map[string]bool) error {
+	cmList := &corev1.ConfigMapList{}
+
+	err := r.List(context.TODO(), cmList, client.MatchingLabels{appv1.LabelRenderedManifestsOf: renderedManifestsLabel(appsub)})
+	if err != nil {
+		return err
+	}
+
+	for i := range cmList.Items {
+		cm := &cmList.Items[i]
+
+		if keep[cm.Namespace] || cm.Annotations[appv1.AnnotationHosting] != appsub.String() {
+			continue
+		}
+
+		if err := r.Delete(context.TODO(), cm); err != nil && !kerrors.IsNotFound(err) {
+			return err
+		}
+
+		klog.Infof("rendered manifests configmap deleted: %v/%v", cm.Namespace, cm.Name)
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	releasev1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/helmrelease/v1"
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func TestBuildRenderedManifestsConfigMap(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	appsub := types.NamespacedName{Namespace: "demo-ns", Name: "demo"}
+
+	cm, err := buildRenderedManifestsConfigMap(appsub, "cluster1", "abc123", []string{
+		"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm1\n",
+		"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm2\n",
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(cm.Name).To(gomega.Equal("demo-ns-demo-rendered"))
+	g.Expect(cm.Namespace).To(gomega.Equal("cluster1"))
+	g.Expect(cm.Labels[appv1.LabelRenderedManifestsOf]).To(gomega.Equal("demo-ns.demo"))
+	g.Expect(cm.Data[renderedRevisionKey]).To(gomega.Equal("abc123"))
+	g.Expect(cm.Data[renderedResourcesKey]).To(gomega.Equal("2"))
+
+	gz, err := gzip.NewReader(bytes.NewReader(cm.BinaryData[renderedManifestsKey]))
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	data, err := io.ReadAll(gz)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(string(data)).To(gomega.Equal("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm1\n---\n" +
+		"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm2\n"))
+
+	g.Expect(helmReleasesRevision([]*releasev1.HelmRelease{
+		{Repo: releasev1.HelmReleaseRepo{ChartName: "redis", Version: "2.0.0"}},
+		{Repo: releasev1.HelmReleaseRepo{ChartName: "nginx", Version: "1.0.0"}},
+	})).To(gomega.Equal("nginx:1.0.0,redis:2.0.0"))
+}
+
+func TestSyncRenderedManifests(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(corev1.AddToScheme(scheme)).To(gomega.Succeed())
+
+	r := &ReconcileSubscription{Client: fake.NewClientBuilder().WithScheme(scheme).Build()}
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "demo",
+			Namespace:   "demo-ns",
+			Annotations: map[string]string{appv1.AnnotationRenderedManifests: "true"},
+		},
+	}
+
+	clusters := []ManageClusters{{Cluster: "cluster1"}, {Cluster: "cluster2"}}
+	selector := client.MatchingLabels{appv1.LabelRenderedManifestsOf: "demo-ns.demo"}
+
+	g.Expect(r.syncRenderedManifests(sub, chnv1.ChannelTypeHelmRepo, nil, clusters, true)).To(gomega.Succeed())
+
+	cmList := &corev1.ConfigMapList{}
+	g.Expect(r.List(context.TODO(), cmList, selector)).To(gomega.Succeed())
+	g.Expect(cmList.Items).To(gomega.HaveLen(2))
+
+	// the configmaps of the clusters no longer targeted are removed
+	g.Expect(r.syncRenderedManifests(sub, chnv1.ChannelTypeHelmRepo, nil, clusters[:1], true)).To(gomega.Succeed())
+	g.Expect(r.List(context.TODO(), cmList, selector)).To(gomega.Succeed())
+	g.Expect(cmList.Items).To(gomega.HaveLen(1))
+	g.Expect(cmList.Items[0].Namespace).To(gomega.Equal("cluster1"))
+
+	sub.Annotations = nil
+
+	g.Expect(r.syncRenderedManifests(sub, chnv1.ChannelTypeHelmRepo, nil, clusters, true)).To(gomega.Succeed())
+	g.Expect(r.List(context.TODO(), cmList, selector)).To(gomega.Succeed())
+	g.Expect(cmList.Items).To(gomega.BeEmpty())
+}
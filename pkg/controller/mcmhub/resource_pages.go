@@ -0,0 +1,174 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appsubreportv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+)
+
+// resourcePageSize is the maximum number of resources in a SubscriptionResourcePage
+const resourcePageSize = 500
+
+// resourcePageName is the name of the page of the resources of the subscription, the pages start at 1
+func resourcePageName(subName string, page int) string {
+	return fmt.Sprintf("%s-resources-%d", subName, page)
+}
+
+// buildResourcePages splits the resources of the subscription in pages of resourcePageSize resources, sorted by API
+// version, kind, namespace and name. A subscription without resources has a single empty page
+func buildResourcePages(sub *appv1.Subscription,
+	resources []*v1.ObjectReference) []*appsubreportv1alpha1.SubscriptionResourcePage {
+	sorted := make([]v1.ObjectReference, 0, len(resources))
+
+	for _, res := range resources {
+		if res != nil {
+			sorted = append(sorted, v1.ObjectReference{
+				APIVersion: res.APIVersion,
+				Kind:       res.Kind,
+				Namespace:  res.Namespace,
+				Name:       res.Name,
+			})
+		}
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].APIVersion != sorted[j].APIVersion {
+			return sorted[i].APIVersion < sorted[j].APIVersion
+		}
+
+		if sorted[i].Kind != sorted[j].Kind {
+			return sorted[i].Kind < sorted[j].Kind
+		}
+
+		if sorted[i].Namespace != sorted[j].Namespace {
+			return sorted[i].Namespace < sorted[j].Namespace
+		}
+
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	pageCount := (len(sorted) + resourcePageSize - 1) / resourcePageSize
+	if pageCount == 0 {
+		pageCount = 1
+	}
+
+	pages := make([]*appsubreportv1alpha1.SubscriptionResourcePage, 0, pageCount)
+
+	for page := 1; page <= pageCount; page++ {
+		start := (page - 1) * resourcePageSize
+		end := start + resourcePageSize
+
+		if end > len(sorted) {
+			end = len(sorted)
+		}
+
+		pages = append(pages, &appsubreportv1alpha1.SubscriptionResourcePage{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "SubscriptionResourcePage",
+				APIVersion: "apps.open-cluster-management.io/v1alpha1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      resourcePageName(sub.Name, page),
+				Namespace: sub.Namespace,
+				Labels: map[string]string{
+					"apps.open-cluster-management.io/hosting-subscription": fmt.Sprintf("%.63s", sub.Namespace+"."+sub.Name),
+				},
+			},
+			Subscription: sub.Name,
+			Page:         page,
+			Pages:        pageCount,
+			Total:        len(sorted),
+			Resources:    sorted[start:end],
+		})
+	}
+
+	return pages
+}
+
+// syncResourcePages stores the resources of the subscription in SubscriptionResourcePages, so the resources of the
+// subscriptions with thousands of resources can be read one page at a time instead of in the application
+// SubscriptionReport. The pages are owned by the subscription, the pages past the last one are removed
+func (r *ReconcileSubscription) syncResourcePages(sub *appv1.Subscription, resources []*v1.ObjectReference) error {
+	pages := buildResourcePages(sub, resources)
+
+	for _, page := range pages {
+		page.SetOwnerReferences([]metav1.OwnerReference{
+			*metav1.NewControllerRef(sub, appv1.SchemeGroupVersion.WithKind("Subscription"))})
+
+		found := &appsubreportv1alpha1.SubscriptionResourcePage{}
+
+		err := r.Get(context.TODO(), client.ObjectKeyFromObject(page), found)
+		if kerrors.IsNotFound(err) {
+			if err := r.Create(context.TODO(), page); err != nil {
+				return fmt.Errorf("failed to create the resource page %v/%v: %w", page.Namespace, page.Name, err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to get the resource page %v/%v: %w", page.Namespace, page.Name, err)
+		}
+
+		if found.Subscription == page.Subscription && found.Page == page.Page && found.Pages == page.Pages &&
+			found.Total == page.Total && equality.Semantic.DeepEqual(found.Resources, page.Resources) &&
+			equality.Semantic.DeepEqual(found.GetLabels(), page.GetLabels()) {
+			continue
+		}
+
+		page.SetResourceVersion(found.GetResourceVersion())
+
+		if err := r.Update(context.TODO(), page); err != nil {
+			return fmt.Errorf("failed to update the resource page %v/%v: %w", page.Namespace, page.Name, err)
+		}
+	}
+
+	pageList := &appsubreportv1alpha1.SubscriptionResourcePageList{}
+
+	if err := r.List(context.TODO(), pageList, client.InNamespace(sub.Namespace),
+		client.MatchingLabels{
+			"apps.open-cluster-management.io/hosting-subscription": fmt.Sprintf("%.63s", sub.Namespace+"."+sub.Name),
+		}); err != nil {
+		return fmt.Errorf("failed to list the resource pages of subscription %v/%v: %w", sub.Namespace, sub.Name, err)
+	}
+
+	for i := range pageList.Items {
+		page := &pageList.Items[i]
+		if page.Subscription != sub.Name || page.Page <= len(pages) {
+			continue
+		}
+
+		klog.Infof("deleting the resource page %v/%v past the %v pages of the subscription",
+			page.Namespace, page.Name, len(pages))
+
+		if err := r.Delete(context.TODO(), page); err != nil && !kerrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete the resource page %v/%v: %w", page.Namespace, page.Name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appsubreportv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+)
+
+func TestSyncResourcePages(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(appv1.SchemeBuilder.AddToScheme(scheme)).To(gomega.Succeed())
+	g.Expect(appsubreportv1alpha1.SchemeBuilder.AddToScheme(scheme)).To(gomega.Succeed())
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns", UID: "demo-uid"},
+	}
+
+	r := &ReconcileSubscription{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(sub).Build()}
+
+	resources := []*v1.ObjectReference{}
+	for i := 0; i < 1200; i++ {
+		resources = append(resources, &v1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "demo-ns",
+			Name: fmt.Sprintf("cm-%04d", 1199-i), UID: "ignored"})
+	}
+
+	resources = append(resources, &v1.ObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "demo-ns",
+		Name: "web"})
+
+	// the resources are sorted and split in pages of 500 resources
+	g.Expect(r.syncResourcePages(sub, resources)).To(gomega.Succeed())
+
+	pages := &appsubreportv1alpha1.SubscriptionResourcePageList{}
+	g.Expect(r.List(context.TODO(), pages, client.InNamespace("demo-ns"))).To(gomega.Succeed())
+	g.Expect(pages.Items).To(gomega.HaveLen(3))
+
+	first := &appsubreportv1alpha1.SubscriptionResourcePage{}
+	g.Expect(r.Get(context.TODO(), client.ObjectKey{Namespace: "demo-ns", Name: "demo-resources-1"}, first)).To(gomega.Succeed())
+	g.Expect(first.Subscription).To(gomega.Equal("demo"))
+	g.Expect(first.Page).To(gomega.Equal(1))
+	g.Expect(first.Pages).To(gomega.Equal(3))
+	g.Expect(first.Total).To(gomega.Equal(1201))
+	g.Expect(first.Resources).To(gomega.HaveLen(500))
+	g.Expect(first.Resources[0]).To(gomega.Equal(v1.ObjectReference{APIVersion: "apps/v1", Kind: "Deployment",
+		Namespace: "demo-ns", Name: "web"}))
+	g.Expect(first.Resources[1].Name).To(gomega.Equal("cm-0000"))
+	g.Expect(first.Labels).To(gomega.HaveKeyWithValue("apps.open-cluster-management.io/hosting-subscription", "demo-ns.demo"))
+	g.Expect(metav1.IsControlledBy(first, sub)).To(gomega.BeTrue())
+
+	last := &appsubreportv1alpha1.SubscriptionResourcePage{}
+	g.Expect(r.Get(context.TODO(), client.ObjectKey{Namespace: "demo-ns", Name: "demo-resources-3"}, last)).To(gomega.Succeed())
+	g.Expect(last.Resources).To(gomega.HaveLen(201))
+	g.Expect(last.Resources[200].Name).To(gomega.Equal("cm-1199"))
+
+	// the pages past the last one are removed when the resources shrink
+	g.Expect(r.syncResourcePages(sub, resources[:10])).To(gomega.Succeed())
+
+	g.Expect(r.List(context.TODO(), pages, client.InNamespace("demo-ns"))).To(gomega.Succeed())
+	g.Expect(pages.Items).To(gomega.HaveLen(1))
+	g.Expect(pages.Items[0].Name).To(gomega.Equal("demo-resources-1"))
+	g.Expect(pages.Items[0].Pages).To(gomega.Equal(1))
+	g.Expect(pages.Items[0].Total).To(gomega.Equal(10))
+
+	// a subscription without resources has a single empty page
+	g.Expect(r.syncResourcePages(sub, nil)).To(gomega.Succeed())
+
+	g.Expect(r.List(context.TODO(), pages, client.InNamespace("demo-ns"))).To(gomega.Succeed())
+	g.Expect(pages.Items).To(gomega.HaveLen(1))
+	g.Expect(pages.Items[0].Total).To(gomega.Equal(0))
+	g.Expect(pages.Items[0].Resources).To(gomega.BeEmpty())
+}
@@ -0,0 +1,245 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	appSubV1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appSubStatusV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+const (
+	defaultRetryBackoff    = 5 * time.Minute
+	defaultRetryMaxBackoff = time.Hour
+)
+
+// retryCheckInterval is how often the hub checks the results of the clusters of a subscription with a retry policy
+// while none of them waits for a retry, the SubscriptionReports are not watched
+var retryCheckInterval = 5 * time.Minute
+
+// syncClusterRetries schedules the retries of the clusters failing to deploy the subscription in its status, with the
+// exponential backoff of its retry policy. A retry sets the retry-time annotation of the subscription propagated to
+// the cluster, the agent reconciles the resources again when it changes
+func (r *ReconcileSubscription) syncClusterRetries(sub *appSubV1.Subscription, clusters []ManageClusters,
+	now time.Time) error {
+	policy := sub.Spec.Retry
+	if policy == nil {
+		sub.Status.Retries = nil
+
+		return nil
+	}
+
+	results, err := r.getClusterResults(sub)
+	if err != nil {
+		return err
+	}
+
+	previous := map[string]appSubV1.ClusterRetryStatus{}
+	for _, retryStatus := range sub.Status.Retries {
+		previous[retryStatus.Cluster] = retryStatus
+	}
+
+	retryStatuses := []appSubV1.ClusterRetryStatus{}
+
+	for _, cluster := range clusters {
+		retryStatus, found := previous[cluster.Cluster]
+		retryStatus.Cluster = cluster.Cluster
+
+		if results[cluster.Cluster] != "failed" {
+			// the last retry time of a recovered cluster is kept, its retry-time annotation doesn't change and the
+			// agent doesn't reconcile the resources again
+			if found && retryStatus.LastRetryTime != nil {
+				retryStatus.Retries = 0
+				retryStatus.FailedSince = nil
+				retryStatus.NextRetryTime = nil
+				retryStatuses = append(retryStatuses, retryStatus)
+			}
+
+			continue
+		}
+
+		if retryStatus.FailedSince == nil {
+			failedSince := metav1.NewTime(now)
+			retryStatus.FailedSince = &failedSince
+			retryStatus.Retries = 0
+		}
+
+		next := getNextRetryTime(sub, retryStatus)
+		if next != nil && !now.Before(next.Time) {
+			klog.Infof("retrying subscription %v/%v on failed cluster %v, retry %v", sub.Namespace, sub.Name,
+				cluster.Cluster, retryStatus.Retries+1)
+
+			retryTime := metav1.NewTime(now)
+			retryStatus.LastRetryTime = &retryTime
+			retryStatus.Retries++
+
+			if r.eventRecorder != nil {
+				r.eventRecorder.RecordEvent(sub, "Retry",
+					fmt.Sprintf("retry %v of failed cluster %v", retryStatus.Retries, cluster.Cluster), nil)
+			}
+
+			next = getNextRetryTime(sub, retryStatus)
+		}
+
+		retryStatus.NextRetryTime = next
+		retryStatuses = append(retryStatuses, retryStatus)
+	}
+
+	if len(retryStatuses) == 0 {
+		retryStatuses = nil
+	}
+
+	sub.Status.Retries = retryStatuses
+
+	for _, retryStatus := range retryStatuses {
+		if retryStatus.Retries == previous[retryStatus.Cluster].Retries {
+			continue
+		}
+
+		if err := r.setClusterReportRetries(sub, retryStatus.Cluster, retryStatus.Retries); err != nil {
+			klog.Errorf("failed to set the retries of subscription %v/%v in the report of cluster %v, err: %v",
+				sub.Namespace, sub.Name, retryStatus.Cluster, err)
+		}
+	}
+
+	return nil
+}
+
+// getNextRetryTime returns when the failed cluster is retried next, the backoff after its failure or its last retry
+// is doubled on each retry up to the max backoff. It is nil once the retries of the cluster are exhausted
+func getNextRetryTime(sub *appSubV1.Subscription, retryStatus appSubV1.ClusterRetryStatus) *metav1.Time {
+	policy := sub.Spec.Retry
+
+	if policy.MaxRetries > 0 && retryStatus.Retries >= policy.MaxRetries {
+		return nil
+	}
+
+	backoff := policy.Backoff.Duration
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+
+	maxBackoff := policy.MaxBackoff.Duration
+	if maxBackoff <= 0 {
+		maxBackoff = defaultRetryMaxBackoff
+	}
+
+	for i := int32(0); i < retryStatus.Retries && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+
+	last := retryStatus.FailedSince.Time
+	if retryStatus.Retries > 0 && retryStatus.LastRetryTime != nil {
+		last = retryStatus.LastRetryTime.Time
+	}
+
+	next := last.Add(backoff)
+
+	// the retry waits for the next start of the timewindow
+	if policy.InTimeWindow {
+		next = next.Add(utils.NextStartPoint(sub.Spec.TimeWindow, next))
+	}
+
+	nextRetryTime := metav1.NewTime(next)
+
+	return &nextRetryTime
+}
+
+// setClusterReportRetries sets the retries of the subscription in its result of the cluster SubscriptionReport, the
+// result is added by the agent with the status of the subscription
+func (r *ReconcileSubscription) setClusterReportRetries(sub *appSubV1.Subscription, cluster string, retries int32) error {
+	clusterReport := &appSubStatusV1alpha1.SubscriptionReport{}
+
+	err := r.Get(context.TODO(), types.NamespacedName{Name: cluster, Namespace: cluster}, clusterReport)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	source := sub.Namespace + "/" + sub.Name
+
+	for _, result := range clusterReport.Results {
+		if result == nil || result.Source != source {
+			continue
+		}
+
+		if result.Retries == retries {
+			return nil
+		}
+
+		result.Retries = retries
+
+		return r.Update(context.TODO(), clusterReport)
+	}
+
+	return nil
+}
+
+// getClusterRetryTime returns the last retry time of the failed cluster, empty if it was never retried
+func getClusterRetryTime(instance *appSubV1.Subscription, cluster ManageClusters) string {
+	if instance.Spec.Retry == nil {
+		return ""
+	}
+
+	for _, retryStatus := range instance.Status.Retries {
+		if retryStatus.Cluster == cluster.Cluster && retryStatus.LastRetryTime != nil {
+			return retryStatus.LastRetryTime.UTC().Format(time.RFC3339)
+		}
+	}
+
+	return ""
+}
+
+// retryRequeueAfter returns when to check the failed clusters of the subscription again, zero without a retry policy
+func retryRequeueAfter(sub *appSubV1.Subscription, now time.Time) time.Duration {
+	if sub.Spec.Retry == nil {
+		return 0
+	}
+
+	after := retryCheckInterval
+
+	for _, retryStatus := range sub.Status.Retries {
+		if retryStatus.NextRetryTime == nil {
+			continue
+		}
+
+		next := retryStatus.NextRetryTime.Sub(now)
+		if next < time.Second {
+			next = time.Second
+		}
+
+		if next < after {
+			after = next
+		}
+	}
+
+	return after
+}
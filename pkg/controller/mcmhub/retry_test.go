@@ -0,0 +1,134 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appsubreportv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+)
+
+func TestSyncClusterRetries(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(appsubreportv1alpha1.AddToScheme(scheme)).To(gomega.Succeed())
+
+	report := &appsubreportv1alpha1.SubscriptionReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns"},
+		ReportType: "Application",
+		Results: []*appsubreportv1alpha1.SubscriptionReportResult{
+			{Source: "cluster1", Result: "deployed"},
+			{Source: "cluster2", Result: "failed"},
+		},
+	}
+
+	clusterReport := &appsubreportv1alpha1.SubscriptionReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster2", Namespace: "cluster2"},
+		ReportType: "Cluster",
+		Results: []*appsubreportv1alpha1.SubscriptionReportResult{
+			{Source: "demo-ns/demo", Result: "failed"},
+		},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(report, clusterReport).Build()
+
+	r := &ReconcileSubscription{Client: c}
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns"},
+		Spec: appv1.SubscriptionSpec{
+			Retry: &appv1.RetryPolicy{
+				Backoff:    metav1.Duration{Duration: 5 * time.Minute},
+				MaxBackoff: metav1.Duration{Duration: 15 * time.Minute},
+				MaxRetries: 3,
+			},
+		},
+	}
+
+	clusters := []ManageClusters{{Cluster: "cluster1"}, {Cluster: "cluster2"}}
+	now := time.Now()
+
+	clusterRetries := func() int32 {
+		g.Expect(c.Get(context.TODO(), types.NamespacedName{Name: "cluster2", Namespace: "cluster2"}, clusterReport)).To(
+			gomega.Succeed())
+
+		return clusterReport.Results[0].Retries
+	}
+
+	// the failed cluster is retried after the backoff
+	g.Expect(r.syncClusterRetries(sub, clusters, now)).To(gomega.Succeed())
+	g.Expect(sub.Status.Retries).To(gomega.HaveLen(1))
+	g.Expect(sub.Status.Retries[0].Cluster).To(gomega.Equal("cluster2"))
+	g.Expect(sub.Status.Retries[0].Retries).To(gomega.BeZero())
+	g.Expect(sub.Status.Retries[0].NextRetryTime.Time).To(gomega.BeTemporally("~", now.Add(5*time.Minute), time.Second))
+	g.Expect(retryRequeueAfter(sub, now)).To(gomega.BeNumerically("~", 5*time.Minute, time.Second))
+	g.Expect(getClusterSubscription(sub, clusters[1])).To(gomega.BeNil())
+
+	now = now.Add(5 * time.Minute)
+	g.Expect(r.syncClusterRetries(sub, clusters, now)).To(gomega.Succeed())
+	g.Expect(sub.Status.Retries[0].Retries).To(gomega.Equal(int32(1)))
+	g.Expect(sub.Status.Retries[0].NextRetryTime.Time).To(gomega.BeTemporally("~", now.Add(10*time.Minute), time.Second))
+	g.Expect(clusterRetries()).To(gomega.Equal(int32(1)))
+	g.Expect(getClusterSubscription(sub, clusters[1]).GetAnnotations()).To(
+		gomega.HaveKeyWithValue(appv1.AnnotationRetryTime, now.UTC().Format(time.RFC3339)))
+	g.Expect(getClusterSubscription(sub, clusters[0])).To(gomega.BeNil())
+
+	// the backoff is doubled up to the max backoff, and the retries stop once exhausted
+	now = now.Add(10 * time.Minute)
+	g.Expect(r.syncClusterRetries(sub, clusters, now)).To(gomega.Succeed())
+	g.Expect(sub.Status.Retries[0].NextRetryTime.Time).To(gomega.BeTemporally("~", now.Add(15*time.Minute), time.Second))
+
+	now = now.Add(15 * time.Minute)
+	g.Expect(r.syncClusterRetries(sub, clusters, now)).To(gomega.Succeed())
+	g.Expect(sub.Status.Retries[0].Retries).To(gomega.Equal(int32(3)))
+	g.Expect(sub.Status.Retries[0].NextRetryTime).To(gomega.BeNil())
+	g.Expect(retryRequeueAfter(sub, now)).To(gomega.Equal(retryCheckInterval))
+
+	lastRetryTime := sub.Status.Retries[0].LastRetryTime
+
+	// the retries are reset once the cluster is deployed, its last retry time is kept
+	report.Results[1].Result = "deployed"
+	g.Expect(c.Update(context.TODO(), report)).To(gomega.Succeed())
+
+	g.Expect(r.syncClusterRetries(sub, clusters, now.Add(time.Minute))).To(gomega.Succeed())
+	g.Expect(sub.Status.Retries[0].Retries).To(gomega.BeZero())
+	g.Expect(sub.Status.Retries[0].FailedSince).To(gomega.BeNil())
+	g.Expect(sub.Status.Retries[0].LastRetryTime).To(gomega.Equal(lastRetryTime))
+	g.Expect(clusterRetries()).To(gomega.BeZero())
+
+	// the retries only run in the timewindow of the subscription
+	sub.Spec.TimeWindow = &appv1.TimeWindow{WindowType: "blocked", Daysofweek: []string{now.Weekday().String()}}
+	sub.Spec.Retry.InTimeWindow = true
+	report.Results[1].Result = "failed"
+	g.Expect(c.Update(context.TODO(), report)).To(gomega.Succeed())
+
+	g.Expect(r.syncClusterRetries(sub, clusters, now)).To(gomega.Succeed())
+	g.Expect(sub.Status.Retries[0].NextRetryTime.Time.After(now.Add(5 * time.Minute))).To(gomega.BeTrue())
+
+	sub.Spec.Retry = nil
+	g.Expect(r.syncClusterRetries(sub, clusters, now)).To(gomega.Succeed())
+	g.Expect(sub.Status.Retries).To(gomega.BeNil())
+	g.Expect(retryRequeueAfter(sub, now)).To(gomega.BeZero())
+}
@@ -0,0 +1,241 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+// templateRefWatcher propagates again the hub subscriptions whose hub templates read a ConfigMap or a Secret when the
+// data of the object changes, instead of waiting for the next reconcile of the subscriptions. Only the metadata of the
+// objects is watched, the data of the referenced objects is read from the API server and hashed to skip the changes
+// of their metadata
+type templateRefWatcher struct {
+	client  client.Client
+	reader  client.Reader
+	started time.Time
+
+	mu sync.Mutex
+	// hashes are the hashes of the data of the referenced objects, the key is kind/namespace/name
+	hashes map[string]string
+}
+
+func newTemplateRefWatcher(clt client.Client, reader client.Reader) *templateRefWatcher {
+	return &templateRefWatcher{client: clt, reader: reader, started: time.Now(), hashes: map[string]string{}}
+}
+
+// handler returns the event handler of the objects of the kind, ConfigMap or Secret. The objects listed when the watch
+// starts only record their hash
+func (w *templateRefWatcher) handler(kind string) handler.EventHandler {
+	return handler.Funcs{
+		CreateFunc: func(e event.CreateEvent, q workqueue.RateLimitingInterface) {
+			subs := w.referencingSubscriptions(kind, e.Object)
+
+			if w.dataChanged(kind, e.Object, subs) && e.Object.GetCreationTimestamp().After(w.started) {
+				enqueueTemplateRefSubscriptions(subs, q)
+			}
+		},
+		UpdateFunc: func(e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+			subs := w.referencingSubscriptions(kind, e.ObjectNew)
+
+			if w.dataChanged(kind, e.ObjectNew, subs) {
+				enqueueTemplateRefSubscriptions(subs, q)
+			}
+		},
+		DeleteFunc: func(e event.DeleteEvent, q workqueue.RateLimitingInterface) {
+			subs := w.referencingSubscriptions(kind, e.Object)
+
+			w.forget(kind, e.Object)
+			enqueueTemplateRefSubscriptions(subs, q)
+		},
+	}
+}
+
+// referencingSubscriptions returns the hub subscriptions in the namespace of the object whose hub templates read it
+func (w *templateRefWatcher) referencingSubscriptions(kind string, obj client.Object) []*appv1.Subscription {
+	subList := &appv1.SubscriptionList{}
+	if err := w.client.List(context.TODO(), subList, client.InNamespace(obj.GetNamespace())); err != nil {
+		klog.Errorf("failed to list the subscriptions of namespace %v, err: %v", obj.GetNamespace(), err)
+
+		return nil
+	}
+
+	subs := []*appv1.Subscription{}
+
+	for i := range subList.Items {
+		sub := &subList.Items[i]
+		if !isQuotaSubscription(sub) {
+			continue
+		}
+
+		refs := utils.GetHubTemplateRefs(sub)
+
+		names := refs.ConfigMaps
+		if kind == "Secret" {
+			names = refs.Secrets
+		}
+
+		if idx := sort.SearchStrings(names, obj.GetName()); idx < len(names) && names[idx] == obj.GetName() {
+			subs = append(subs, sub)
+		}
+	}
+
+	return subs
+}
+
+// dataChanged reads the object and compares the hash of its data to its last hash, an object no subscription reads is
+// forgotten. The object is changed if it can't be read
+func (w *templateRefWatcher) dataChanged(kind string, obj client.Object, subs []*appv1.Subscription) bool {
+	if len(subs) == 0 {
+		w.forget(kind, obj)
+
+		return false
+	}
+
+	key := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+
+	var hash string
+
+	if kind == "Secret" {
+		secret := &corev1.Secret{}
+		if err := w.reader.Get(context.TODO(), key, secret); err != nil {
+			if !kerrors.IsNotFound(err) {
+				klog.Errorf("failed to get the secret %v read by the hub templates, err: %v", key.String(), err)
+			}
+
+			w.forget(kind, obj)
+
+			return true
+		}
+
+		hash = hashTemplateRefData(secret.Data, nil)
+	} else {
+		cm := &corev1.ConfigMap{}
+		if err := w.reader.Get(context.TODO(), key, cm); err != nil {
+			if !kerrors.IsNotFound(err) {
+				klog.Errorf("failed to get the configmap %v read by the hub templates, err: %v", key.String(), err)
+			}
+
+			w.forget(kind, obj)
+
+			return true
+		}
+
+		hash = hashTemplateRefData(cm.BinaryData, cm.Data)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	hashKey := kind + "/" + key.String()
+	last, ok := w.hashes[hashKey]
+	w.hashes[hashKey] = hash
+
+	return !ok || last != hash
+}
+
+func (w *templateRefWatcher) forget(kind string, obj client.Object) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.hashes, kind+"/"+obj.GetNamespace()+"/"+obj.GetName())
+}
+
+// hashTemplateRefData hashes the data of a ConfigMap or a Secret, sorted by key
+func hashTemplateRefData(data map[string][]byte, stringData map[string]string) string {
+	keys := make([]string, 0, len(data)+len(stringData))
+
+	for key := range data {
+		keys = append(keys, key)
+	}
+
+	for key := range stringData {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	h := sha256.New()
+
+	for _, key := range keys {
+		value, ok := data[key]
+		if !ok {
+			value = []byte(stringData[key])
+		}
+
+		h.Write([]byte(key))
+		h.Write([]byte{0})
+		h.Write(value)
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// enqueueTemplateRefSubscriptions enqueues the subscriptions after their template-refresh-debounce delay
+func enqueueTemplateRefSubscriptions(subs []*appv1.Subscription, q workqueue.RateLimitingInterface) {
+	for _, sub := range subs {
+		req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: sub.GetNamespace(), Name: sub.GetName()}}
+
+		klog.Infof("a ConfigMap or a Secret read by the hub templates of subscription %v changed", req.String())
+
+		if debounce := templateRefreshDebounce(sub); debounce > 0 {
+			q.AddAfter(req, debounce)
+		} else {
+			q.Add(req)
+		}
+	}
+}
+
+// templateRefreshDebounce returns the template-refresh-debounce delay of the subscription, 0 if not set or invalid
+func templateRefreshDebounce(sub *appv1.Subscription) time.Duration {
+	value := sub.GetAnnotations()[appv1.AnnotationTemplateRefreshDebounce]
+	if value == "" {
+		return 0
+	}
+
+	debounce, err := time.ParseDuration(value)
+	if err == nil && debounce < 0 {
+		err = fmt.Errorf("the delay is negative")
+	}
+
+	if err != nil {
+		klog.Warningf("invalid %v annotation %q of subscription %v/%v, err: %v", appv1.AnnotationTemplateRefreshDebounce,
+			value, sub.GetNamespace(), sub.GetName(), err)
+
+		return 0
+	}
+
+	return debounce
+}
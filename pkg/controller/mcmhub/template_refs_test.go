@@ -0,0 +1,115 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcmhub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	plrv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func TestTemplateRefWatcher(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(corev1.AddToScheme(scheme)).To(gomega.Succeed())
+	g.Expect(appv1.SchemeBuilder.AddToScheme(scheme)).To(gomega.Succeed())
+
+	newSub := func(name, patch string, annotations map[string]string) *appv1.Subscription {
+		return &appv1.Subscription{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "demo-ns", Annotations: annotations},
+			Spec: appv1.SubscriptionSpec{
+				Placement: &plrv1.Placement{},
+				PackageOverrides: []*appv1.Overrides{{
+					PackageName: "frontend",
+					Patches:     []appv1.PackagePatch{{Type: appv1.PatchTypeJSONMergePatch, Patch: patch}},
+				}},
+			},
+		}
+	}
+
+	env := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "env", Namespace: "demo-ns",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour))},
+		Data: map[string]string{"db": "db1"},
+	}
+
+	clt := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newSub("reads-env", `{"data": {"db": "{{hub fromConfigMap "env" "db" hub}}"}}`, nil),
+		newSub("reads-env-later", `{"data": {"db": "{{hub fromConfigMap "env" "db" hub}}"}}`,
+			map[string]string{appv1.AnnotationTemplateRefreshDebounce: "100ms"}),
+		newSub("reads-secret", `{"data": {"user": "{{hub fromSecret "env" "user" hub}}"}}`, nil),
+		env,
+	).Build()
+
+	w := newTemplateRefWatcher(clt, clt)
+	h := w.handler("ConfigMap")
+
+	q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer q.ShutDown()
+
+	// the ConfigMaps listed when the watch starts only record their hash
+	h.Create(event.CreateEvent{Object: env}, q)
+	g.Expect(q.Len()).To(gomega.Equal(0))
+
+	// the metadata changes are skipped
+	env.Labels = map[string]string{"team": "payments"}
+	g.Expect(clt.Update(context.TODO(), env)).To(gomega.Succeed())
+
+	h.Update(event.UpdateEvent{ObjectOld: env, ObjectNew: env}, q)
+	g.Expect(q.Len()).To(gomega.Equal(0))
+
+	// the subscriptions reading the ConfigMap are reconciled when its data changes, after their debounce
+	env.Data["db"] = "db2"
+	g.Expect(clt.Update(context.TODO(), env)).To(gomega.Succeed())
+
+	h.Update(event.UpdateEvent{ObjectOld: env, ObjectNew: env}, q)
+	g.Expect(q.Len()).To(gomega.Equal(1))
+
+	item, _ := q.Get()
+	g.Expect(item.(interface{ String() string }).String()).To(gomega.Equal("demo-ns/reads-env"))
+	q.Done(item)
+
+	g.Eventually(q.Len).Should(gomega.Equal(1))
+
+	item, _ = q.Get()
+	g.Expect(item.(interface{ String() string }).String()).To(gomega.Equal("demo-ns/reads-env-later"))
+	q.Done(item)
+
+	// the subscriptions are reconciled when the ConfigMap is deleted, their templates fail to resolve
+	g.Expect(clt.Delete(context.TODO(), env)).To(gomega.Succeed())
+
+	h.Delete(event.DeleteEvent{Object: env}, q)
+	g.Eventually(q.Len).Should(gomega.Equal(2))
+
+	// a ConfigMap no subscription reads is ignored
+	other := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "demo-ns"}}
+	g.Expect(w.referencingSubscriptions("ConfigMap", other)).To(gomega.BeEmpty())
+	g.Expect(w.dataChanged("ConfigMap", other, nil)).To(gomega.BeFalse())
+
+	g.Expect(templateRefreshDebounce(newSub("invalid", "", map[string]string{
+		appv1.AnnotationTemplateRefreshDebounce: "-1s"}))).To(gomega.Equal(time.Duration(0)))
+}
@@ -0,0 +1,69 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spoketoken
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+)
+
+// ServerConfig is the server of the cluster secret when the hub can't reach the API server of the managed cluster,
+// e.g. the cluster-proxy user server of the hub. CAFile is the CA of the server and ServerName its TLS server name
+type ServerConfig struct {
+	URL        string
+	ServerName string
+	CAFile     string
+}
+
+// clusterSecretServer overrides the API server URL of the cluster secrets if its URL is set
+var clusterSecretServer ServerConfig
+
+// SetClusterSecretServer sets the server of the cluster secrets, it is called once before the controllers are set up
+func SetClusterSecretServer(server ServerConfig) {
+	clusterSecretServer = server
+}
+
+// getTLSClientConfig returns the TLS settings of the cluster secret. The API server of the managed cluster is not
+// verified, the server of the cluster secret is verified with its CA if it is set. An error is returned if the CA of the
+// server can't be read, the server isn't used without verification then
+func getTLSClientConfig(server ServerConfig) (TLSClientConfig, error) {
+	tlsClientConfig := TLSClientConfig{Insecure: true}
+
+	if server.URL == "" {
+		return tlsClientConfig, nil
+	}
+
+	tlsClientConfig.ServerName = server.ServerName
+
+	if server.CAFile == "" {
+		return tlsClientConfig, nil
+	}
+
+	caData, err := os.ReadFile(server.CAFile)
+	if err != nil {
+		return TLSClientConfig{}, fmt.Errorf("failed to read the CA of the cluster secret server %v, err: %w", server.URL, err)
+	}
+
+	if len(caData) == 0 {
+		return TLSClientConfig{}, fmt.Errorf("the CA file %v of the cluster secret server %v is empty", server.CAFile,
+			server.URL)
+	}
+
+	tlsClientConfig.Insecure = false
+	tlsClientConfig.CAData = base64.StdEncoding.EncodeToString(caData)
+
+	return tlsClientConfig, nil
+}
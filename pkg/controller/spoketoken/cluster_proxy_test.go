@@ -0,0 +1,58 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spoketoken
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestGetTLSClientConfig(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// the API server of the managed cluster is not verified
+	tlsClientConfig, err := getTLSClientConfig(ServerConfig{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(tlsClientConfig.Insecure).To(gomega.BeTrue())
+
+	// the server is verified with its CA
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	g.Expect(os.WriteFile(caFile, []byte("ca"), 0600)).To(gomega.Succeed())
+
+	server := ServerConfig{URL: "https://proxy.example.com", ServerName: "proxy", CAFile: caFile}
+
+	tlsClientConfig, err = getTLSClientConfig(server)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(tlsClientConfig.Insecure).To(gomega.BeFalse())
+	g.Expect(tlsClientConfig.ServerName).To(gomega.Equal("proxy"))
+	g.Expect(tlsClientConfig.CAData).To(gomega.Equal(base64.StdEncoding.EncodeToString([]byte("ca"))))
+
+	// the server isn't used without verification when its CA can't be read
+	server.CAFile = filepath.Join(t.TempDir(), "missing.crt")
+
+	_, err = getTLSClientConfig(server)
+	g.Expect(err).To(gomega.HaveOccurred())
+
+	g.Expect(os.WriteFile(caFile, nil, 0600)).To(gomega.Succeed())
+
+	server.CAFile = caFile
+
+	_, err = getTLSClientConfig(server)
+	g.Expect(err).To(gomega.HaveOccurred())
+}
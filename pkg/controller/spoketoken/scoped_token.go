@@ -0,0 +1,242 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spoketoken
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// scopedServiceAccountLabel labels the service account, the token secret and the bindings created for the cluster
+// secret, they are pruned by the label
+const scopedServiceAccountLabel = "apps.open-cluster-management.io/cluster-secret-service-account"
+
+// ScopedServiceAccount is the dedicated service account whose token is exported in the cluster secret instead of the
+// application-manager token. The service account is bound to ClusterRole, in each of Namespaces with a RoleBinding or
+// in the whole cluster with a ClusterRoleBinding if Namespaces is empty
+type ScopedServiceAccount struct {
+	Name        string
+	ClusterRole string
+	Namespaces  []string
+}
+
+// clusterSecretServiceAccount exports the application-manager token in the cluster secrets if its Name is empty
+var clusterSecretServiceAccount ScopedServiceAccount
+
+// SetClusterSecretServiceAccount sets the service account of the cluster secrets, it is called once before the
+// controllers are set up
+func SetClusterSecretServiceAccount(sa ScopedServiceAccount) {
+	clusterSecretServiceAccount = sa
+}
+
+// getScopedServiceAccountToken creates the scoped service account, its token secret and its bindings in the agent
+// namespace, and returns its token. The token is empty until the token controller populates the token secret
+func (r *ReconcileAgentToken) getScopedServiceAccountToken(sa ScopedServiceAccount) (string, error) {
+	if sa.ClusterRole == "" {
+		return "", fmt.Errorf("the cluster secret service account %v requires a ClusterRole", sa.Name)
+	}
+
+	ns := agentServiceAccount.Namespace
+	labels := map[string]string{scopedServiceAccountLabel: sa.Name}
+
+	if err := r.ensureObject(&corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: sa.Name, Namespace: ns, Labels: labels},
+	}); err != nil {
+		return "", err
+	}
+
+	tokenSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        sa.Name + "-token",
+			Namespace:   ns,
+			Labels:      labels,
+			Annotations: map[string]string{corev1.ServiceAccountNameKey: sa.Name},
+		},
+		Type: corev1.SecretTypeServiceAccountToken,
+	}
+
+	if err := r.ensureObject(tokenSecret); err != nil {
+		return "", err
+	}
+
+	if err := r.ensureScopedBindings(sa, labels); err != nil {
+		return "", err
+	}
+
+	if err := r.Client.Get(context.TODO(), client.ObjectKeyFromObject(tokenSecret), tokenSecret); err != nil {
+		return "", err
+	}
+
+	return string(tokenSecret.Data[corev1.ServiceAccountTokenKey]), nil
+}
+
+// ensureScopedBindings binds the scoped service account to its ClusterRole, the bindings of the namespaces no longer
+// configured are pruned
+func (r *ReconcileAgentToken) ensureScopedBindings(sa ScopedServiceAccount, labels map[string]string) error {
+	roleRef := rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: sa.ClusterRole}
+	subjects := []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: sa.Name, Namespace: agentServiceAccount.Namespace}}
+	bindingName := "open-cluster-management:" + sa.Name
+
+	if len(sa.Namespaces) == 0 {
+		if err := r.ensureObject(&rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: bindingName, Labels: labels},
+			RoleRef:    roleRef,
+			Subjects:   subjects,
+		}); err != nil {
+			return err
+		}
+
+		return r.pruneScopedRoleBindings(sa.Name, nil)
+	}
+
+	for _, ns := range sa.Namespaces {
+		if err := r.ensureObject(&rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: bindingName, Namespace: ns, Labels: labels},
+			RoleRef:    roleRef,
+			Subjects:   subjects,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := r.pruneScopedRoleBindings(sa.Name, sa.Namespaces); err != nil {
+		return err
+	}
+
+	return client.IgnoreNotFound(r.Client.Delete(context.TODO(), &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: bindingName},
+	}))
+}
+
+// pruneScopedRoleBindings deletes the RoleBindings of the scoped service account outside the namespaces
+func (r *ReconcileAgentToken) pruneScopedRoleBindings(saName string, namespaces []string) error {
+	keep := map[string]bool{}
+
+	for _, ns := range namespaces {
+		keep[ns] = true
+	}
+
+	roleBindings := &rbacv1.RoleBindingList{}
+	if err := r.Client.List(context.TODO(), roleBindings, client.MatchingLabels{scopedServiceAccountLabel: saName}); err != nil {
+		return err
+	}
+
+	for i := range roleBindings.Items {
+		rb := &roleBindings.Items[i]
+
+		if keep[rb.Namespace] {
+			continue
+		}
+
+		klog.Infof("deleting the RoleBinding %v/%v of the cluster secret service account %v", rb.Namespace, rb.Name, saName)
+
+		if err := r.Client.Delete(context.TODO(), rb); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteScopedServiceAccount deletes the scoped service account, its token secret and its bindings
+func (r *ReconcileAgentToken) deleteScopedServiceAccount(sa ScopedServiceAccount) error {
+	if err := r.pruneScopedRoleBindings(sa.Name, nil); err != nil {
+		return err
+	}
+
+	ns := agentServiceAccount.Namespace
+
+	for _, obj := range []client.Object{
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "open-cluster-management:" + sa.Name}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: sa.Name + "-token", Namespace: ns}},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: sa.Name, Namespace: ns}},
+	} {
+		if err := r.Client.Delete(context.TODO(), obj); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ensureObject creates the object if it doesn't exist. The bindings are updated to the subjects and the labels of the
+// object, the role of an existing binding can't be changed so the binding is recreated for a new role
+func (r *ReconcileAgentToken) ensureObject(obj client.Object) error {
+	key := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+	existing := obj.DeepCopyObject().(client.Object)
+
+	err := r.Client.Get(context.TODO(), key, existing)
+	if kerrors.IsNotFound(err) {
+		klog.Infof("creating %T %v for the cluster secret", obj, key.String())
+
+		return r.Client.Create(context.TODO(), obj)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	switch desired := obj.(type) {
+	case *rbacv1.ClusterRoleBinding:
+		current := existing.(*rbacv1.ClusterRoleBinding)
+		if current.RoleRef != desired.RoleRef {
+			return r.recreateObject(current, desired)
+		}
+
+		if reflect.DeepEqual(current.Subjects, desired.Subjects) && reflect.DeepEqual(current.Labels, desired.Labels) {
+			return nil
+		}
+
+		current.Subjects = desired.Subjects
+		current.Labels = desired.Labels
+
+		return r.Client.Update(context.TODO(), current)
+	case *rbacv1.RoleBinding:
+		current := existing.(*rbacv1.RoleBinding)
+		if current.RoleRef != desired.RoleRef {
+			return r.recreateObject(current, desired)
+		}
+
+		if reflect.DeepEqual(current.Subjects, desired.Subjects) && reflect.DeepEqual(current.Labels, desired.Labels) {
+			return nil
+		}
+
+		current.Subjects = desired.Subjects
+		current.Labels = desired.Labels
+
+		return r.Client.Update(context.TODO(), current)
+	}
+
+	return nil
+}
+
+func (r *ReconcileAgentToken) recreateObject(current, desired client.Object) error {
+	klog.Infof("recreating %T %v/%v for its new role", desired, desired.GetNamespace(), desired.GetName())
+
+	if err := r.Client.Delete(context.TODO(), current); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+
+	return r.Client.Create(context.TODO(), desired)
+}
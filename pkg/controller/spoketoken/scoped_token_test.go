@@ -0,0 +1,94 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spoketoken
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestScopedServiceAccountToken(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(gomega.Succeed())
+
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	r := &ReconcileAgentToken{Client: c}
+
+	sa := ScopedServiceAccount{Name: "argocd-manager", ClusterRole: "view", Namespaces: []string{"team-a", "team-b"}}
+	ns := agentServiceAccount.Namespace
+	bindingName := "open-cluster-management:argocd-manager"
+
+	_, err := r.getScopedServiceAccountToken(ScopedServiceAccount{Name: "argocd-manager"})
+	g.Expect(err).To(gomega.HaveOccurred())
+
+	// the token is empty until the token controller populates the token secret
+	token, err := r.getScopedServiceAccountToken(sa)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(token).To(gomega.BeEmpty())
+
+	g.Expect(c.Get(context.TODO(), types.NamespacedName{Name: "argocd-manager", Namespace: ns}, &corev1.ServiceAccount{})).To(gomega.Succeed())
+
+	tokenSecret := &corev1.Secret{}
+	g.Expect(c.Get(context.TODO(), types.NamespacedName{Name: "argocd-manager-token", Namespace: ns}, tokenSecret)).To(gomega.Succeed())
+	g.Expect(tokenSecret.Type).To(gomega.Equal(corev1.SecretTypeServiceAccountToken))
+	g.Expect(tokenSecret.Annotations).To(gomega.HaveKeyWithValue(corev1.ServiceAccountNameKey, "argocd-manager"))
+
+	tokenSecret.Data = map[string][]byte{corev1.ServiceAccountTokenKey: []byte("scoped-token")}
+	g.Expect(c.Update(context.TODO(), tokenSecret)).To(gomega.Succeed())
+
+	token, err = r.getScopedServiceAccountToken(sa)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(token).To(gomega.Equal("scoped-token"))
+
+	rb := &rbacv1.RoleBinding{}
+	g.Expect(c.Get(context.TODO(), types.NamespacedName{Name: bindingName, Namespace: "team-a"}, rb)).To(gomega.Succeed())
+	g.Expect(rb.RoleRef.Name).To(gomega.Equal("view"))
+	g.Expect(rb.Subjects).To(gomega.Equal([]rbacv1.Subject{{Kind: "ServiceAccount", Name: "argocd-manager", Namespace: ns}}))
+
+	// the namespace no longer configured is pruned and the role change recreates the bindings
+	sa.Namespaces = []string{"team-a"}
+	sa.ClusterRole = "edit"
+
+	_, err = r.getScopedServiceAccountToken(sa)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	g.Expect(kerrors.IsNotFound(c.Get(context.TODO(), types.NamespacedName{Name: bindingName, Namespace: "team-b"}, rb))).To(gomega.BeTrue())
+	g.Expect(c.Get(context.TODO(), types.NamespacedName{Name: bindingName, Namespace: "team-a"}, rb)).To(gomega.Succeed())
+	g.Expect(rb.RoleRef.Name).To(gomega.Equal("edit"))
+
+	// the service account is bound in the whole cluster without namespaces
+	sa.Namespaces = nil
+
+	_, err = r.getScopedServiceAccountToken(sa)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	g.Expect(c.Get(context.TODO(), types.NamespacedName{Name: bindingName}, &rbacv1.ClusterRoleBinding{})).To(gomega.Succeed())
+	g.Expect(kerrors.IsNotFound(c.Get(context.TODO(), types.NamespacedName{Name: bindingName, Namespace: "team-a"}, rb))).To(gomega.BeTrue())
+
+	g.Expect(r.deleteScopedServiceAccount(sa)).To(gomega.Succeed())
+	g.Expect(kerrors.IsNotFound(c.Get(context.TODO(), types.NamespacedName{Name: bindingName}, &rbacv1.ClusterRoleBinding{}))).To(gomega.BeTrue())
+	g.Expect(kerrors.IsNotFound(c.Get(context.TODO(), types.NamespacedName{Name: "argocd-manager", Namespace: ns}, &corev1.ServiceAccount{}))).To(gomega.BeTrue())
+}
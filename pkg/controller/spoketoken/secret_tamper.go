@@ -0,0 +1,147 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spoketoken
+
+import (
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/metrics"
+)
+
+const (
+	// ReasonClusterSecretRestored is the reason of the event of a cluster secret restored on the hub
+	ReasonClusterSecretRestored = "ClusterSecretRestored"
+
+	tamperReasonDeleted  = "deleted"
+	tamperReasonModified = "modified"
+
+	primaryHubName = "primary"
+)
+
+// agentServiceAccount is the service account whose token is the cluster secret, the cluster secrets are reconciled
+// through its requests
+var agentServiceAccount = types.NamespacedName{Name: "application-manager", Namespace: "open-cluster-management-agent-addon"}
+
+// newHubSecretSource watches the cluster secret on a hub, the secret changed or deleted by another actor is restored
+// right away instead of when the service account happens to change. The cache only lists the cluster secret, the
+// agent is allowed to watch the secrets of its cluster namespace on the hub
+func newHubSecretSource(mgr manager.Manager, hubconfig *rest.Config, syncid *types.NamespacedName) (source.Source, error) {
+	hubCache, err := cache.New(hubconfig, cache.Options{
+		Scheme:    mgr.GetScheme(),
+		Namespace: syncid.Namespace,
+		SelectorsByObject: cache.SelectorsByObject{
+			&corev1.Secret{}: {Field: fields.OneTermEqualSelector("metadata.name", syncid.Name+secretSuffix)},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the cache of the cluster secret on the hub, err: %w", err)
+	}
+
+	if err := mgr.Add(hubCache); err != nil {
+		return nil, err
+	}
+
+	return source.NewKindWithCache(&corev1.Secret{}, hubCache), nil
+}
+
+// enqueueAgentServiceAccount maps the cluster secret events to the request of the agent service account
+var enqueueAgentServiceAccount = handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []reconcile.Request {
+	return []reconcile.Request{{NamespacedName: agentServiceAccount}}
+})
+
+// secretMatches checks if the cluster secret on the hub has the data and the labels of the expected cluster secret,
+// the labels added by the other actors are ignored. The stringData of the existing secret is merged into its data
+// like the API server does
+func secretMatches(existing, expected *corev1.Secret) bool {
+	data := map[string]string{}
+
+	for key, value := range existing.Data {
+		data[key] = string(value)
+	}
+
+	for key, value := range existing.StringData {
+		data[key] = value
+	}
+
+	if len(data) != len(expected.StringData) {
+		return false
+	}
+
+	for key, value := range expected.StringData {
+		if existingValue, ok := data[key]; !ok || existingValue != value {
+			return false
+		}
+	}
+
+	labels := existing.GetLabels()
+
+	for key, value := range expected.GetLabels() {
+		if labels[key] != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkTampered returns why the cluster secret on the hub was tampered with, empty if it wasn't. A secret is tampered
+// with if it is deleted or doesn't match the secret last synced, e.g. the token rotation is not a tampering
+func (r *ReconcileAgentToken) checkTampered(hub hubTarget, existing *corev1.Secret) string {
+	last, synced := r.syncedSecrets[hub.name]
+	if !synced {
+		return ""
+	}
+
+	if existing == nil {
+		return tamperReasonDeleted
+	}
+
+	if !secretMatches(existing, last) {
+		return tamperReasonModified
+	}
+
+	return ""
+}
+
+// reportTampered records the event and the metric of the cluster secret restored on the hub
+func reportTampered(hub hubTarget, secret *corev1.Secret, reason string) {
+	hubName := hub.name
+	if hubName == "" {
+		hubName = primaryHubName
+	}
+
+	msg := fmt.Sprintf("the cluster secret %v/%v was %v by another actor, it is restored", secret.Namespace, secret.Name, reason)
+
+	klog.Warningf("%v on the hub %v", msg, hubName)
+
+	metrics.ClusterSecretRestoredTotal.WithLabelValues(hubName, reason).Inc()
+
+	if hub.recorder != nil {
+		hub.recorder.RecordEvent(secret, ReasonClusterSecretRestored, msg, errors.New(reason))
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spoketoken
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onsi/gomega"
+	ocinfrav1 "github.com/openshift/api/config/v1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/metrics"
+)
+
+func TestSecretTamper(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(gomega.Succeed())
+	g.Expect(ocinfrav1.AddToScheme(scheme)).To(gomega.Succeed())
+
+	infra := &ocinfrav1.Infrastructure{
+		ObjectMeta: metav1.ObjectMeta{Name: infrastructureConfigName},
+		Status:     ocinfrav1.InfrastructureStatus{APIServerURL: "https://api.cluster1.example.com:6443"},
+	}
+
+	syncid := &types.NamespacedName{Name: "cluster1", Namespace: "cluster1"}
+	hubclient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	r := &ReconcileAgentToken{
+		Client:        fake.NewClientBuilder().WithScheme(scheme).WithObjects(infra).Build(),
+		hubclient:     hubclient,
+		syncid:        syncid,
+		syncedSecrets: map[string]*corev1.Secret{},
+	}
+
+	hub := hubTarget{hubclient: hubclient, syncid: syncid}
+	secretKey := types.NamespacedName{Name: "cluster1" + secretSuffix, Namespace: "cluster1"}
+
+	restored := func(reason string) float64 {
+		return testutil.ToFloat64(metrics.ClusterSecretRestoredTotal.WithLabelValues(primaryHubName, reason))
+	}
+
+	// the first sync and the token rotation are not tamperings
+	g.Expect(r.syncHubSecret(hub, "token-1")).To(gomega.Succeed())
+	g.Expect(r.syncHubSecret(hub, "token-2")).To(gomega.Succeed())
+	g.Expect(restored(tamperReasonModified)).To(gomega.BeZero())
+
+	// the modified cluster secret is restored, the fake client doesn't merge the stringData into the data
+	secret := &corev1.Secret{}
+	g.Expect(hubclient.Get(context.TODO(), secretKey, secret)).To(gomega.Succeed())
+	g.Expect(secret.StringData["server"]).To(gomega.Equal("https://api.cluster1.example.com:6443"))
+
+	original := secret.DeepCopy()
+
+	secret.StringData["server"] = "https://attacker.example.com"
+	g.Expect(hubclient.Update(context.TODO(), secret)).To(gomega.Succeed())
+
+	g.Expect(r.syncHubSecret(hub, "token-2")).To(gomega.Succeed())
+	g.Expect(restored(tamperReasonModified)).To(gomega.Equal(float64(1)))
+
+	g.Expect(hubclient.Get(context.TODO(), secretKey, secret)).To(gomega.Succeed())
+	g.Expect(secret.StringData).To(gomega.Equal(original.StringData))
+
+	// the labels added by the other actors are kept
+	secret.Labels["team"] = "a"
+	g.Expect(hubclient.Update(context.TODO(), secret)).To(gomega.Succeed())
+
+	g.Expect(r.syncHubSecret(hub, "token-2")).To(gomega.Succeed())
+	g.Expect(restored(tamperReasonModified)).To(gomega.Equal(float64(1)))
+
+	// the deleted cluster secret is restored
+	g.Expect(hubclient.Delete(context.TODO(), secret)).To(gomega.Succeed())
+
+	g.Expect(r.syncHubSecret(hub, "token-2")).To(gomega.Succeed())
+	g.Expect(restored(tamperReasonDeleted)).To(gomega.Equal(float64(1)))
+	g.Expect(hubclient.Get(context.TODO(), secretKey, secret)).To(gomega.Succeed())
+}
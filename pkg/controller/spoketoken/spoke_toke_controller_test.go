@@ -166,7 +166,7 @@ func TestReconcile(t *testing.T) {
 	configData := &Config{}
 	g.Expect(json.Unmarshal(theSecret.Data["config"], configData)).NotTo(gomega.HaveOccurred())
 	g.Expect(configData.BearerToken).To(gomega.Equal(dockerSecret.Annotations["openshift.io/token-secret.value"]))
-	g.Expect(configData.TLSClientConfig["insecure"]).To(gomega.BeTrue())
+	g.Expect(configData.TLSClientConfig.Insecure).To(gomega.BeTrue())
 
 	// Verify the labels
 	secretLabels := theSecret.GetLabels()
@@ -42,6 +42,7 @@ import (
 const (
 	secretSuffix             = "-cluster-secret"
 	requeuAfter              = 5
+	tokenRequeueAfter        = 5 * time.Second
 	infrastructureConfigName = "cluster"
 )
 
@@ -55,7 +56,43 @@ func Add(mgr manager.Manager, hubconfig *rest.Config, syncid *types.NamespacedNa
 			return err
 		}
 
-		return add(mgr, newReconciler(mgr, hubclient, syncid, mgr.GetConfig().Host))
+		rec := newReconciler(mgr, hubclient, syncid, mgr.GetConfig().Host).(*ReconcileAgentToken)
+		rec.recorder, _ = utils.NewEventRecorder(hubconfig, mgr.GetScheme())
+
+		hubSource, err := newHubSecretSource(mgr, hubconfig, syncid)
+		if err != nil {
+			klog.Error("Failed to watch the cluster secret on the hub with error:", err)
+			return err
+		}
+
+		hubSources := []source.Source{hubSource}
+
+		for _, hub := range utils.GetAdditionalHubConfigs() {
+			additionalHubClient, err := client.New(hub.RestConfig, client.Options{})
+			if err != nil {
+				klog.Errorf("Failed to generate client to additional hub %v with error: %v", hub.Name, err)
+				return err
+			}
+
+			additionalHubSource, err := newHubSecretSource(mgr, hub.RestConfig, hub.ClusterNamespacedName)
+			if err != nil {
+				klog.Errorf("Failed to watch the cluster secret on additional hub %v with error: %v", hub.Name, err)
+				return err
+			}
+
+			hubSources = append(hubSources, additionalHubSource)
+
+			additionalHubRecorder, _ := utils.NewEventRecorder(hub.RestConfig, mgr.GetScheme())
+
+			rec.additionalHubs = append(rec.additionalHubs, hubTarget{
+				name:      hub.Name,
+				hubclient: additionalHubClient,
+				syncid:    hub.ClusterNamespacedName,
+				recorder:  additionalHubRecorder,
+			})
+		}
+
+		return add(mgr, rec, hubSources...)
 	}
 
 	return nil
@@ -64,18 +101,19 @@ func Add(mgr manager.Manager, hubconfig *rest.Config, syncid *types.NamespacedNa
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager, hubclient client.Client, syncid *types.NamespacedName, host string) reconcile.Reconciler {
 	rec := &ReconcileAgentToken{
-		Client:    mgr.GetClient(),
-		scheme:    mgr.GetScheme(),
-		hubclient: hubclient,
-		syncid:    syncid,
-		host:      host,
+		Client:        utils.ControllerClient(mgr, "klusterlet-token-controller"),
+		scheme:        mgr.GetScheme(),
+		hubclient:     hubclient,
+		syncid:        syncid,
+		host:          host,
+		syncedSecrets: map[string]*corev1.Secret{},
 	}
 
 	return rec
 }
 
-// add adds a new Controller to mgr with r as the reconcile.Reconciler
-func add(mgr manager.Manager, r reconcile.Reconciler) error {
+// add adds a new Controller to mgr with r as the reconcile.Reconciler, hubSources are the cluster secrets on the hubs
+func add(mgr manager.Manager, r reconcile.Reconciler, hubSources ...source.Source) error {
 	klog.Info("Adding klusterlet token controller.")
 	// Create a new controller
 	c, err := controller.New("klusterlet-token-controller", mgr, controller.Options{Reconciler: r})
@@ -89,6 +127,13 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Watch for changes to the cluster secret on the hubs, the tampered cluster secret is restored.
+	for _, hubSource := range hubSources {
+		if err := c.Watch(hubSource, enqueueAgentServiceAccount); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -97,21 +142,41 @@ var _ reconcile.Reconciler = &ReconcileAgentToken{}
 
 // ReconcileAppMgrToken syncid is the namespaced name of this managed cluster.
 // host is the API server URL of this managed cluster.
+// syncedSecrets is the cluster secret last synced to each hub, keyed by the hub name.
 type ReconcileAgentToken struct {
 	client.Client
+	hubclient      client.Client
+	scheme         *runtime.Scheme
+	syncid         *types.NamespacedName
+	host           string
+	additionalHubs []hubTarget
+	recorder       *utils.EventRecorder
+	syncedSecrets  map[string]*corev1.Secret
+}
+
+// hubTarget is an additional hub the cluster secret is synced to, syncid is the cluster namespaced name on that hub
+type hubTarget struct {
+	name      string
 	hubclient client.Client
-	scheme    *runtime.Scheme
 	syncid    *types.NamespacedName
-	host      string
+	recorder  *utils.EventRecorder
 }
 
 type Config struct {
 	BearerToken     string          `json:"bearerToken"`
-	TLSClientConfig map[string]bool `json:"tlsClientConfig"`
+	TLSClientConfig TLSClientConfig `json:"tlsClientConfig"`
+}
+
+// TLSClientConfig is the TLS settings of the ArgoCD cluster secrets, caData is the base64 encoded PEM CA
+type TLSClientConfig struct {
+	Insecure   bool   `json:"insecure"`
+	CAData     string `json:"caData,omitempty"`
+	ServerName string `json:"serverName,omitempty"`
 }
 
 // Reconciles <clusterName>-cluster-secret secret in the managed cluster's namespace
 // on the hub cluster to the klusterlet-addon-appmgr service account's token secret.
+// The secret is reconciled to each additional hub independently, a failure on one hub doesn't block the others.
 // If it is running on the hub, don't do anything.
 func (r *ReconcileAgentToken) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
 	klog.Infof("Reconciling %s", request.NamespacedName)
@@ -124,13 +189,29 @@ func (r *ReconcileAgentToken) Reconcile(ctx context.Context, request reconcile.R
 		if kerrors.IsNotFound(err) {
 			klog.Infof("%s is not found. Deleting the secret from the hub.", request.NamespacedName)
 
-			err := r.hubclient.Delete(context.TODO(), r.prepareAgentTokenSecret(""))
+			// the cluster secrets deleted with the service account are not restored
+			r.syncedSecrets = map[string]*corev1.Secret{}
 
-			if err != nil {
+			err := r.hubclient.Delete(context.TODO(), r.prepareAgentTokenSecret(r.syncid, ""))
+
+			if err != nil && !kerrors.IsNotFound(err) {
 				klog.Error("Failed to delete the secret from the hub.")
 				return reconcile.Result{RequeueAfter: requeuAfter * time.Minute}, err
 			}
 
+			for _, hub := range r.additionalHubs {
+				if err := hub.hubclient.Delete(context.TODO(), r.prepareAgentTokenSecret(hub.syncid, "")); err != nil && !kerrors.IsNotFound(err) {
+					klog.Errorf("Failed to delete the secret from hub %v, error: %v", hub.name, err)
+				}
+			}
+
+			if clusterSecretServiceAccount.Name != "" {
+				if err := r.deleteScopedServiceAccount(clusterSecretServiceAccount); err != nil {
+					klog.Errorf("Failed to delete the cluster secret service account %v, error: %v", clusterSecretServiceAccount.Name, err)
+					return reconcile.Result{RequeueAfter: requeuAfter * time.Minute}, err
+				}
+			}
+
 			return reconcile.Result{}, nil
 		}
 
@@ -139,6 +220,22 @@ func (r *ReconcileAgentToken) Reconcile(ctx context.Context, request reconcile.R
 		return reconcile.Result{RequeueAfter: requeuAfter * time.Minute}, err
 	}
 
+	// Export the token of the dedicated service account with the narrower role if it is set
+	if clusterSecretServiceAccount.Name != "" {
+		token, err := r.getScopedServiceAccountToken(clusterSecretServiceAccount)
+		if err != nil {
+			klog.Errorf("Failed to get the token of the cluster secret service account %v, error: %v", clusterSecretServiceAccount.Name, err)
+			return reconcile.Result{RequeueAfter: requeuAfter * time.Minute}, err
+		}
+
+		if token == "" {
+			klog.Infof("The token of the cluster secret service account %v is not populated yet.", clusterSecretServiceAccount.Name)
+			return reconcile.Result{RequeueAfter: tokenRequeueAfter}, nil
+		}
+
+		return r.syncHubSecrets(token)
+	}
+
 	// Get the service account token from the service account's secret list
 	token := r.getServiceAccountTokenSecret()
 
@@ -147,49 +244,104 @@ func (r *ReconcileAgentToken) Reconcile(ctx context.Context, request reconcile.R
 		return reconcile.Result{}, errors.New("failed to find the klusterlet agent addon service account token secret")
 	}
 
+	return r.syncHubSecrets(token)
+}
+
+// syncHubSecrets syncs the cluster secret with the token to the primary hub and the additional hubs
+func (r *ReconcileAgentToken) syncHubSecrets(token string) (reconcile.Result, error) {
+	primaryHub := hubTarget{hubclient: r.hubclient, syncid: r.syncid, recorder: r.recorder}
+
+	if err := r.syncHubSecret(primaryHub, token); err != nil {
+		return reconcile.Result{RequeueAfter: requeuAfter * time.Minute}, err
+	}
+
+	failedHubs := []string{}
+
+	for _, hub := range r.additionalHubs {
+		if err := r.syncHubSecret(hub, token); err != nil {
+			failedHubs = append(failedHubs, hub.name)
+		}
+	}
+
+	if len(failedHubs) > 0 {
+		return reconcile.Result{RequeueAfter: requeuAfter * time.Minute},
+			errors.Errorf("failed to sync the cluster secret to hubs: %v", strings.Join(failedHubs, ","))
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// syncHubSecret creates or updates the cluster secret in the managed cluster namespace on a hub. An empty hub name is the primary hub.
+// The cluster secret deleted or modified by another actor since it was last synced is restored and reported.
+func (r *ReconcileAgentToken) syncHubSecret(hub hubTarget, token string) error {
+	hubName := hub.name
+
 	// Prepare the secret to be created/updated in the managed cluster namespace on the hub
-	secret := r.prepareAgentTokenSecret(token)
+	secret := r.prepareAgentTokenSecret(hub.syncid, token)
+	expected := secret.DeepCopy()
 
 	// Get the existing secret in the managed cluster namespace from the hub
 	hubSecret := &corev1.Secret{}
-	hubSecretName := types.NamespacedName{Namespace: r.syncid.Namespace, Name: r.syncid.Name + secretSuffix}
-	err = r.hubclient.Get(context.TODO(), hubSecretName, hubSecret)
+	hubSecretName := types.NamespacedName{Namespace: hub.syncid.Namespace, Name: hub.syncid.Name + secretSuffix}
+	err := hub.hubclient.Get(context.TODO(), hubSecretName, hubSecret)
 
 	if err != nil {
 		if kerrors.IsNotFound(err) {
-			klog.Info("Secret " + hubSecretName.String() + " not found on the hub.")
+			klog.Info("Secret " + hubSecretName.String() + " not found on the hub " + hubName)
+
+			tampered := r.checkTampered(hub, nil)
 
-			err := r.hubclient.Create(context.TODO(), secret)
+			err := hub.hubclient.Create(context.TODO(), secret)
 
 			if err != nil {
 				klog.Error(err.Error())
-				return reconcile.Result{RequeueAfter: requeuAfter * time.Minute}, err
+				return err
 			}
 
-			klog.Info("The cluster secret " + secret.Name + " was created in " + secret.Namespace + " on the hub successfully.")
+			klog.Info("The cluster secret " + secret.Name + " was created in " + secret.Namespace + " on the hub " + hubName + " successfully.")
+
+			if tampered != "" {
+				reportTampered(hub, secret, tampered)
+			}
 		} else {
-			klog.Error("Failed to get secret from the hub: ", err)
-			return reconcile.Result{RequeueAfter: requeuAfter * time.Minute}, err
+			klog.Error("Failed to get secret from the hub ", hubName, ": ", err)
+			return err
 		}
 	} else {
+		if secretMatches(hubSecret, expected) {
+			klog.V(1).Info("The cluster secret " + secret.Name + " is up to date in " + secret.Namespace + " on the hub " + hubName + ".")
+
+			r.syncedSecrets[hubName] = expected
+
+			return nil
+		}
+
+		tampered := r.checkTampered(hub, hubSecret)
+
 		// Update
-		err := r.hubclient.Update(context.TODO(), secret)
+		err := hub.hubclient.Update(context.TODO(), secret)
 
 		if err != nil {
 			klog.Error("Failed to update secret : ", err)
-			return reconcile.Result{RequeueAfter: time.Duration(requeuAfter * time.Minute.Milliseconds())}, err
+			return err
 		}
 
-		klog.Info("The cluster secret " + secret.Name + " was updated successfully in " + secret.Namespace + " on the hub.")
+		klog.Info("The cluster secret " + secret.Name + " was updated successfully in " + secret.Namespace + " on the hub " + hubName + ".")
+
+		if tampered != "" {
+			reportTampered(hub, secret, tampered)
+		}
 	}
 
-	return reconcile.Result{}, nil
+	r.syncedSecrets[hubName] = expected
+
+	return nil
 }
 
-func (r *ReconcileAgentToken) prepareAgentTokenSecret(token string) *corev1.Secret {
+func (r *ReconcileAgentToken) prepareAgentTokenSecret(syncid *types.NamespacedName, token string) *corev1.Secret {
 	mcSecret := &corev1.Secret{}
-	mcSecret.Name = r.syncid.Name + secretSuffix
-	mcSecret.Namespace = r.syncid.Namespace
+	mcSecret.Name = syncid.Name + secretSuffix
+	mcSecret.Namespace = syncid.Namespace
 
 	labels := make(map[string]string)
 	labels["argocd.argoproj.io/secret-type"] = "cluster"
@@ -197,8 +349,18 @@ func (r *ReconcileAgentToken) prepareAgentTokenSecret(token string) *corev1.Secr
 
 	configData := &Config{}
 	configData.BearerToken = token
-	tlsClientConfig := make(map[string]bool)
-	tlsClientConfig["insecure"] = true
+
+	// the cluster secret points to the API server of the cluster if the server can't be verified
+	server := clusterSecretServer
+
+	tlsClientConfig, err := getTLSClientConfig(server)
+	if err != nil {
+		klog.Errorf("skip the cluster secret server, the cluster secret points to the API server of the cluster, err: %v", err)
+
+		server = ServerConfig{}
+		tlsClientConfig, _ = getTLSClientConfig(server)
+	}
+
 	configData.TLSClientConfig = tlsClientConfig
 
 	jsonConfigData, err := json.MarshalIndent(configData, "", "  ")
@@ -214,15 +376,20 @@ func (r *ReconcileAgentToken) prepareAgentTokenSecret(token string) *corev1.Secr
 	}
 
 	data := make(map[string]string)
-	data["name"] = r.syncid.Name
+	data["name"] = syncid.Name
 	data["server"] = apiServerURL
 	data["config"] = string(jsonConfigData)
 
+	// the hub reaches the cluster through the cluster-proxy, the cluster-server label is still the API server
+	if server.URL != "" {
+		data["server"] = server.URL
+	}
+
 	mcSecret.StringData = data
 
 	labels["apps.open-cluster-management.io/cluster-name"] = data["name"]
 
-	u, err := url.Parse(data["server"])
+	u, err := url.Parse(apiServerURL)
 	if err != nil {
 		klog.Error(err)
 	}
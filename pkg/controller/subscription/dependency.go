@@ -0,0 +1,152 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+const (
+	// priorityRequeuePeriod is short, the subscriptions with a higher priority are reconciled one after another
+	priorityRequeuePeriod   = 2 * time.Second
+	dependencyRequeuePeriod = 10 * time.Second
+)
+
+// isSubscriptionInScope checks if the subscription is reconciled by this reconciler, a local subscription that is
+// either standalone or propagated from the hub depending on the reconciler mode
+func (r *ReconcileSubscription) isSubscriptionInScope(sub *appv1.Subscription) bool {
+	pl := sub.Spec.Placement
+
+	if pl == nil || pl.Local == nil || !*pl.Local || pl.PlacementRef != nil || pl.Clusters != nil || pl.ClusterSelector != nil {
+		return false
+	}
+
+	hosted := !strings.EqualFold(sub.GetAnnotations()[appv1.AnnotationHosting], "")
+
+	return hosted != r.standalone
+}
+
+// getWaitingReason returns why the subscription can't be applied yet and when to check again. The subscriptions with
+// a higher priority must be reconciled first since the agent started, then the subscriptions it depends on must be ready.
+// The WaitingForDependency condition of the subscription is updated accordingly
+func (r *ReconcileSubscription) getWaitingReason(instance *appv1.Subscription) (string, time.Duration, error) {
+	subList := &appv1.SubscriptionList{}
+
+	if err := r.List(context.TODO(), subList); err != nil {
+		return "", 0, err
+	}
+
+	for i := range subList.Items {
+		sub := &subList.Items[i]
+
+		if sub.Spec.Priority <= instance.Spec.Priority || sub.GetDeletionTimestamp() != nil || !r.isSubscriptionInScope(sub) {
+			continue
+		}
+
+		key := types.NamespacedName{Name: sub.GetName(), Namespace: sub.GetNamespace()}
+		if _, ok := r.reconciled.Load(key); !ok {
+			return fmt.Sprintf("waiting for subscription %v of a higher priority %v to be reconciled", key.String(), sub.Spec.Priority),
+				priorityRequeuePeriod, nil
+		}
+	}
+
+	// the subscriptions waiting for their dependencies don't hold the subscriptions of a lower priority
+	r.reconciled.Store(types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}, true)
+
+	dep, reason, err := utils.GetBlockingDependency(r.Client, instance)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if dep != nil {
+		msg := fmt.Sprintf("waiting for subscription %v: %v", dep.String(), reason)
+
+		meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+			Type:               appv1.ConditionWaitingForDependency,
+			Status:             metav1.ConditionTrue,
+			Reason:             appv1.ReasonDependencyNotReady,
+			Message:            msg,
+			ObservedGeneration: instance.GetGeneration(),
+		})
+
+		return msg, dependencyRequeuePeriod, nil
+	}
+
+	setDependenciesReady(instance)
+
+	return "", 0, nil
+}
+
+// setDependenciesReady sets the WaitingForDependency condition to false when the subscription has dependencies
+func setDependenciesReady(instance *appv1.Subscription) {
+	if len(instance.Spec.DependsOn) == 0 {
+		meta.RemoveStatusCondition(&instance.Status.Conditions, appv1.ConditionWaitingForDependency)
+
+		return
+	}
+
+	meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+		Type:               appv1.ConditionWaitingForDependency,
+		Status:             metav1.ConditionFalse,
+		Reason:             appv1.ReasonDependenciesReady,
+		Message:            "all the subscriptions it depends on are ready",
+		ObservedGeneration: instance.GetGeneration(),
+	})
+}
+
+// dependentMapper enqueues the subscriptions depending on the subscription of the appsubstatus
+type dependentMapper struct {
+	client.Client
+}
+
+func (mapper *dependentMapper) Map(obj client.Object) []reconcile.Request {
+	key := types.NamespacedName{Name: obj.GetName(), Namespace: obj.GetNamespace()}
+
+	subList := &appv1.SubscriptionList{}
+
+	if err := mapper.List(context.TODO(), subList); err != nil {
+		klog.Error("Listing all subscriptions in dependentMapper and got error:", err)
+
+		return nil
+	}
+
+	var requests []reconcile.Request
+
+	for i := range subList.Items {
+		sub := &subList.Items[i]
+
+		if utils.DependsOnSubscription(sub, key) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: sub.GetName(), Namespace: sub.GetNamespace()}})
+		}
+	}
+
+	klog.V(5).Info("Out dependent mapper with requests:", requests)
+
+	return requests
+}
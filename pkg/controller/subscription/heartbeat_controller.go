@@ -0,0 +1,117 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package subscription
+
+import (
+	"context"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+// HeartbeatReconciler renews the heartbeat Lease of the agent in its cluster namespace on the hub. The Lease carries
+// the version, the last sync time and the health of the agent, the hub flags the subscriptions of the clusters whose
+// agent stopped renewing it. The Lease also carries the Kubernetes version and the API versions of the managed
+// cluster when its discovery client is set, for the Helm charts the hub renders for the cluster
+type HeartbeatReconciler struct {
+	HubKubeClient           kubernetes.Interface
+	ManagedClusterDiscovery discovery.DiscoveryInterface
+	ClusterName             string
+	LeaseDurationSeconds    int32
+}
+
+func (r *HeartbeatReconciler) Reconcile(ctx context.Context) {
+	leases := r.HubKubeClient.CoordinationV1().Leases(r.ClusterName)
+
+	lease, err := leases.Get(ctx, utils.AgentHeartbeatLeaseName, metav1.GetOptions{})
+
+	switch {
+	case errors.IsNotFound(err):
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      utils.AgentHeartbeatLeaseName,
+				Namespace: r.ClusterName,
+			},
+		}
+
+		r.renew(lease)
+
+		if _, err := leases.Create(ctx, lease, metav1.CreateOptions{}); err != nil {
+			klog.Errorf("unable to create heartbeat lease %q/%q on the hub. error:%v", r.ClusterName, lease.Name, err)
+
+			return
+		}
+
+		klog.Infof("heartbeat lease %q/%q created on the hub", r.ClusterName, lease.Name)
+	case err != nil:
+		klog.Errorf("unable to get heartbeat lease %q/%q on the hub. error:%v", r.ClusterName, utils.AgentHeartbeatLeaseName, err)
+	default:
+		r.renew(lease)
+
+		if _, err := leases.Update(ctx, lease, metav1.UpdateOptions{}); err != nil {
+			klog.Errorf("unable to update heartbeat lease %q/%q on the hub. error:%v", r.ClusterName, lease.Name, err)
+
+			return
+		}
+
+		klog.V(1).Infof("heartbeat lease %q/%q updated on the hub", r.ClusterName, lease.Name)
+	}
+}
+
+// renew sets the renew time of the Lease and the current state of the agent
+func (r *HeartbeatReconciler) renew(lease *coordinationv1.Lease) {
+	labels := lease.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	labels[utils.LabelAgentHeartbeat] = "true"
+	lease.SetLabels(labels)
+
+	annotations := lease.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	// the health message is only set while the agent is degraded
+	delete(annotations, utils.AnnotationAgentHealthMessage)
+
+	for k, v := range utils.AgentHeartbeatAnnotations() {
+		annotations[k] = v
+	}
+
+	// the last reported capabilities are kept while the discovery of the managed cluster fails
+	if r.ManagedClusterDiscovery != nil {
+		capabilities, err := utils.ClusterCapabilitiesAnnotations(r.ManagedClusterDiscovery)
+		if err != nil {
+			klog.Warningf("unable to discover the capabilities of cluster %q. error:%v", r.ClusterName, err)
+		}
+
+		for k, v := range capabilities {
+			annotations[k] = v
+		}
+	}
+
+	lease.SetAnnotations(annotations)
+
+	lease.Spec.LeaseDurationSeconds = &r.LeaseDurationSeconds
+	lease.Spec.RenewTime = &metav1.MicroTime{Time: time.Now()}
+}
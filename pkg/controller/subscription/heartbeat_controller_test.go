@@ -0,0 +1,96 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package subscription
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+func TestHeartbeatReconcile(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	defer utils.RecordAgentSync(nil)
+
+	hubClient := kubefake.NewSimpleClientset()
+
+	heartbeatReconciler := &HeartbeatReconciler{
+		HubKubeClient:        hubClient,
+		ClusterName:          "cluster1",
+		LeaseDurationSeconds: 60,
+	}
+
+	// test1: create the heartbeat lease of a healthy agent
+	heartbeatReconciler.Reconcile(context.TODO())
+
+	lease, err := hubClient.CoordinationV1().Leases("cluster1").Get(context.TODO(), utils.AgentHeartbeatLeaseName, metav1.GetOptions{})
+	g.Expect(err).ShouldNot(gomega.HaveOccurred())
+	g.Expect(lease.GetLabels()).To(gomega.HaveKeyWithValue(utils.LabelAgentHeartbeat, "true"))
+	g.Expect(lease.GetAnnotations()).To(gomega.HaveKeyWithValue(utils.AnnotationAgentVersion, utils.UnknownVersion))
+	g.Expect(lease.GetAnnotations()).To(gomega.HaveKeyWithValue(utils.AnnotationAgentHealth, utils.AgentHealthy))
+
+	renewTime1 := lease.Spec.RenewTime.DeepCopy()
+
+	// test2: the failed status sync degrades the agent
+	utils.RecordAgentSync(errors.New("failed to update the appsubstatus"))
+	heartbeatReconciler.Reconcile(context.TODO())
+
+	lease, err = hubClient.CoordinationV1().Leases("cluster1").Get(context.TODO(), utils.AgentHeartbeatLeaseName, metav1.GetOptions{})
+	g.Expect(err).ShouldNot(gomega.HaveOccurred())
+	g.Expect(lease.GetAnnotations()).To(gomega.HaveKeyWithValue(utils.AnnotationAgentHealth, utils.AgentDegraded))
+	g.Expect(lease.GetAnnotations()).To(gomega.HaveKeyWithValue(utils.AnnotationAgentHealthMessage, "failed to update the appsubstatus"))
+	g.Expect(renewTime1.Before(lease.Spec.RenewTime)).Should(gomega.BeTrue())
+
+	// test3: the next successful sync restores the health and sets the last sync time
+	utils.RecordAgentSync(nil)
+	heartbeatReconciler.Reconcile(context.TODO())
+
+	lease, err = hubClient.CoordinationV1().Leases("cluster1").Get(context.TODO(), utils.AgentHeartbeatLeaseName, metav1.GetOptions{})
+	g.Expect(err).ShouldNot(gomega.HaveOccurred())
+	g.Expect(lease.GetAnnotations()).To(gomega.HaveKeyWithValue(utils.AnnotationAgentHealth, utils.AgentHealthy))
+	g.Expect(lease.GetAnnotations()).NotTo(gomega.HaveKey(utils.AnnotationAgentHealthMessage))
+	g.Expect(lease.GetAnnotations()).To(gomega.HaveKey(utils.AnnotationAgentLastSyncTime))
+	g.Expect(lease.GetAnnotations()).NotTo(gomega.HaveKey(utils.AnnotationAgentKubeVersion))
+
+	// test4: the capabilities of the managed cluster are reported for the helm charts rendered by the hub
+	managedDiscovery := kubefake.NewSimpleClientset().Discovery().(*fakediscovery.FakeDiscovery)
+	managedDiscovery.FakedServerVersion = &version.Info{GitVersion: "v1.27.3", Major: "1", Minor: "27"}
+	managedDiscovery.Resources = []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{{Name: "configmaps", Kind: "ConfigMap"}}},
+		{GroupVersion: "policy/v1", APIResources: []metav1.APIResource{
+			{Name: "poddisruptionbudgets", Kind: "PodDisruptionBudget"},
+			{Name: "poddisruptionbudgets/status", Kind: "PodDisruptionBudget"},
+		}},
+		{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{{Name: "deployments", Kind: "Deployment"}}},
+	}
+
+	heartbeatReconciler.ManagedClusterDiscovery = managedDiscovery
+	heartbeatReconciler.Reconcile(context.TODO())
+
+	lease, err = hubClient.CoordinationV1().Leases("cluster1").Get(context.TODO(), utils.AgentHeartbeatLeaseName, metav1.GetOptions{})
+	g.Expect(err).ShouldNot(gomega.HaveOccurred())
+	g.Expect(lease.GetAnnotations()).To(gomega.HaveKeyWithValue(utils.AnnotationAgentKubeVersion, "v1.27.3"))
+	g.Expect(lease.GetAnnotations()).To(gomega.HaveKeyWithValue(utils.AnnotationAgentAPIVersions,
+		"apps/v1,apps/v1/Deployment,policy/v1,policy/v1/PodDisruptionBudget,v1,v1/ConfigMap"))
+}
@@ -18,10 +18,12 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	gerr "github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -31,9 +33,10 @@ import (
 	"k8s.io/klog/v2"
 	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
 	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
-	ghsub "open-cluster-management.io/multicloud-operators-subscription/pkg/subscriber/git"
-	hrsub "open-cluster-management.io/multicloud-operators-subscription/pkg/subscriber/helmrepo"
-	ossub "open-cluster-management.io/multicloud-operators-subscription/pkg/subscriber/objectbucket"
+	appSubStatusV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/subscriber"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/subscriber/backend"
+	kubesynchronizer "open-cluster-management.io/multicloud-operators-subscription/pkg/synchronizer/kubernetes"
 	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -55,7 +58,8 @@ const (
 
 // Add creates a new Subscription Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
-// If standalone = true, it will only reconcile standalone subscriptions without hosting subscription from ACM hub.
+// If standalone = true, it will only reconcile standalone subscriptions without hosting subscription from ACM hub,
+// and the subscriptions deployed to the hub by the hub placement.
 // If standalone = false, it will only reconcile subscriptions that are propagated from ACM hub.
 func Add(mgr manager.Manager, hubconfig *rest.Config, syncid *types.NamespacedName, standalone bool) error {
 	hubclient, err := client.New(hubconfig, client.Options{})
@@ -65,14 +69,23 @@ func Add(mgr manager.Manager, hubconfig *rest.Config, syncid *types.NamespacedNa
 		return err
 	}
 
-	subs := make(map[string]appv1.Subscriber)
+	// the built-in subscribers and the subscriber of the registered channel backends
+	subs := subscriber.GetChannelSubscribers()
 
-	subs[chnv1.ChannelTypeHelmRepo] = hrsub.GetDefaultSubscriber()
-	subs[chnv1.ChannelTypeGitHub] = ghsub.GetDefaultSubscriber()
-	subs[chnv1.ChannelTypeGit] = ghsub.GetDefaultSubscriber()
-	subs[chnv1.ChannelTypeObjectBucket] = ossub.GetDefaultSubscriber()
+	rec := newReconciler(mgr, hubclient, subs, standalone).(*ReconcileSubscription)
 
-	return add(mgr, newReconciler(mgr, hubclient, subs, standalone), standalone)
+	for _, hub := range utils.GetAdditionalHubConfigs() {
+		additionalHubClient, err := client.New(hub.RestConfig, client.Options{})
+		if err != nil {
+			klog.Errorf("Failed to generate client to additional hub %v with error: %v", hub.Name, err)
+
+			return err
+		}
+
+		rec.additionalHubClients[hub.Name] = additionalHubClient
+	}
+
+	return add(mgr, rec, standalone)
 }
 
 type channelMapper struct {
@@ -122,13 +135,15 @@ func newReconciler(mgr manager.Manager, hubclient client.Client, subscribers map
 	erecorder, _ := utils.NewEventRecorder(mgr.GetConfig(), mgr.GetScheme())
 
 	rec := &ReconcileSubscription{
-		Client:        mgr.GetClient(),
-		scheme:        mgr.GetScheme(),
-		hubclient:     hubclient,
-		subscribers:   subscribers,
-		clk:           time.Now,
-		eventRecorder: erecorder,
-		standalone:    standalone,
+		Client:               utils.ControllerClient(mgr, "subscription-controller"),
+		scheme:               mgr.GetScheme(),
+		hubclient:            hubclient,
+		additionalHubClients: map[string]client.Client{},
+		hubClaims:            utils.NewHubClaims(),
+		subscribers:          subscribers,
+		clk:                  time.Now,
+		eventRecorder:        erecorder,
+		standalone:           standalone,
 	}
 
 	return rec
@@ -148,6 +163,16 @@ func add(mgr manager.Manager, r reconcile.Reconciler, standalone bool) error {
 		return err
 	}
 
+	// The dependent subscriptions are reconciled once the resources of the subscription are deployed
+	dmapper := &dependentMapper{mgr.GetClient()}
+	err = c.Watch(
+		&source.Kind{Type: &appSubStatusV1alpha1.SubscriptionStatus{}},
+		handler.EnqueueRequestsFromMapFunc(dmapper.Map))
+
+	if err != nil {
+		return err
+	}
+
 	if standalone {
 		// There is no channel CRD on a managed cluster
 		cmapper := &channelMapper{mgr.GetClient()}
@@ -174,12 +199,18 @@ type ReconcileSubscription struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
 	client.Client
-	hubclient     client.Client
-	scheme        *runtime.Scheme
-	subscribers   map[string]appv1.Subscriber
-	clk           clock
-	eventRecorder *utils.EventRecorder
-	standalone    bool
+	hubclient client.Client
+	// additionalHubClients are keyed by hub name, used when the cluster is managed by more than one hub
+	additionalHubClients map[string]client.Client
+	scheme               *runtime.Scheme
+	subscribers          map[string]appv1.Subscriber
+	clk                  clock
+	eventRecorder        *utils.EventRecorder
+	standalone           bool
+	// reconciled records the subscriptions reconciled since the agent started, to apply them by priority
+	reconciled sync.Map
+	// hubClaims are the hubs propagating each subscription, the subscription propagated by several hubs is won by one
+	hubClaims *utils.HubClaims
 }
 
 // Reconcile reads that state of the cluster for a Subscription object and makes changes based on the state read
@@ -195,6 +226,8 @@ func (r *ReconcileSubscription) Reconcile(ctx context.Context, request reconcile
 		if errors.IsNotFound(err) {
 			klog.Info("Subscription: ", request.NamespacedName, " is gone")
 
+			r.hubClaims.Release(request.NamespacedName)
+
 			// Object not found, delete existing subscriberitem if any
 			for _, sub := range r.subscribers {
 				if err := sub.UnsubscribeItem(request.NamespacedName); err != nil {
@@ -228,8 +261,46 @@ func (r *ReconcileSubscription) Reconcile(ctx context.Context, request reconcile
 	if pl != nil && pl.Local != nil && *pl.Local && pl.PlacementRef == nil && pl.Clusters == nil && pl.ClusterSelector == nil {
 		// If standalone = true, reconcile standalone subscriptions without hosting subscription from ACM hub.
 		// If standalone = false, reconcile subscriptions that are propagated from ACM hub. These subscriptions have this annotation.
+		// The subscriptions deployed to the hub by the hub placement are reconciled by the standalone controller of the hub.
+		hubLocal := utils.IsHubLocalAppsub(instance)
+
 		if (strings.EqualFold(annotations[appv1.AnnotationHosting], "") && r.standalone) ||
-			(!strings.EqualFold(annotations[appv1.AnnotationHosting], "") && !r.standalone) {
+			(!strings.EqualFold(annotations[appv1.AnnotationHosting], "") && !r.standalone && !hubLocal) ||
+			(hubLocal && r.standalone) {
+			// the subscription propagated by several hubs is only reconciled against the hub taking precedence
+			if !r.standalone {
+				if winner := r.hubClaims.Claim(instance); winner != utils.KnownSubscriptionHubName(instance) {
+					r.reportHubConflict(instance, winner)
+
+					return reconcile.Result{}, nil
+				}
+			}
+
+			oldStatus := instance.Status.DeepCopy()
+
+			waitingReason, requeueAfter, err := r.getWaitingReason(instance)
+			if err != nil {
+				klog.Errorf("failed to check the priority and dependencies of subscription %v, err: %v", request.NamespacedName, err)
+
+				return reconcile.Result{}, err
+			}
+
+			if waitingReason != "" {
+				klog.Infof("Subscription %v is %v", request.NamespacedName, waitingReason)
+
+				instance.Status.Reason = waitingReason
+
+				if !equality.Semantic.DeepEqual(oldStatus, &instance.Status) {
+					instance.Status.LastUpdateTime = metav1.Now()
+
+					if err := r.Status().Update(context.TODO(), instance); err != nil {
+						klog.Errorf("failed to update status for subscription %v with error %v", request.NamespacedName, err)
+					}
+				}
+
+				return reconcile.Result{RequeueAfter: requeueAfter}, nil
+			}
+
 			reconcileErr := r.doReconcile(instance)
 
 			// doReconcile updates the subscription. Later this function fails to update the subscription status
@@ -237,6 +308,8 @@ func (r *ReconcileSubscription) Reconcile(ctx context.Context, request reconcile
 			// Get the newly updated subscription resource.
 			_ = r.Get(context.TODO(), request.NamespacedName, instance)
 
+			setDependenciesReady(instance)
+
 			instance.Status.Phase = appv1.SubscriptionSubscribed
 			instance.Status.Reason = ""
 
@@ -311,20 +384,41 @@ func (r *ReconcileSubscription) Reconcile(ctx context.Context, request reconcile
 	return reconcile.Result{}, nil
 }
 
+// reportHubConflict reports to the hub of the subscription that the subscription of another hub is deployed instead
+func (r *ReconcileSubscription) reportHubConflict(instance *appv1.Subscription, winner string) {
+	klog.Warningf("subscription %v/%v of hub %q is also propagated by hub %q taking precedence, skip it", instance.Namespace,
+		instance.Name, utils.HubDisplayName(utils.KnownSubscriptionHubName(instance)), utils.HubDisplayName(winner))
+
+	synchronizer := kubesynchronizer.GetDefaultSynchronizer()
+	if synchronizer == nil {
+		return
+	}
+
+	if err := synchronizer.ReportHubConflict(instance, winner); err != nil {
+		klog.Errorf("failed to report the hub conflict of subscription %v/%v, err: %v", instance.Namespace, instance.Name, err)
+	}
+}
+
 func (r *ReconcileSubscription) doReconcile(instance *appv1.Subscription) error {
 	var err error
 
+	if err = utils.ValidatePackagePatches(instance); err != nil {
+		return err
+	}
+
+	hubclient := utils.SelectHubClient(instance, r.hubclient, r.additionalHubClients)
+
 	subitem := &appv1.SubscriberItem{}
 	subitem.Subscription = instance
 
 	subitem.Channel = &chnv1.Channel{}
 	chnkey := utils.NamespacedNameFormat(instance.Spec.Channel)
-	err = r.hubclient.Get(context.TODO(), chnkey, subitem.Channel)
+	err = hubclient.Get(context.TODO(), chnkey, subitem.Channel)
 
 	if err != nil {
 		time.Sleep(1 * time.Second)
 
-		err = r.hubclient.Get(context.TODO(), chnkey, subitem.Channel)
+		err = hubclient.Get(context.TODO(), chnkey, subitem.Channel)
 		if err != nil {
 			return gerr.Wrapf(err, "failed to get channel of subscription %v", instance)
 		}
@@ -333,12 +427,12 @@ func (r *ReconcileSubscription) doReconcile(instance *appv1.Subscription) error
 	if instance.Spec.SecondaryChannel != "" {
 		subitem.SecondaryChannel = &chnv1.Channel{}
 		scndChnkey := utils.NamespacedNameFormat(instance.Spec.SecondaryChannel)
-		err = r.hubclient.Get(context.TODO(), scndChnkey, subitem.SecondaryChannel)
+		err = hubclient.Get(context.TODO(), scndChnkey, subitem.SecondaryChannel)
 
 		if err != nil {
 			time.Sleep(1 * time.Second)
 
-			err = r.hubclient.Get(context.TODO(), scndChnkey, subitem.SecondaryChannel)
+			err = hubclient.Get(context.TODO(), scndChnkey, subitem.SecondaryChannel)
 			if err != nil {
 				return gerr.Wrapf(err, "failed to get the secondary channel of subscription %v", instance)
 			}
@@ -352,7 +446,7 @@ func (r *ReconcileSubscription) doReconcile(instance *appv1.Subscription) error
 			Namespace: subitem.Channel.Namespace,
 		}
 
-		if err := r.hubclient.Get(context.TODO(), chnseckey, subitem.ChannelSecret); err != nil {
+		if err := hubclient.Get(context.TODO(), chnseckey, subitem.ChannelSecret); err != nil {
 			return gerr.Wrap(err, "failed to get reference secret from channel")
 		}
 	}
@@ -364,7 +458,7 @@ func (r *ReconcileSubscription) doReconcile(instance *appv1.Subscription) error
 			Namespace: subitem.SecondaryChannel.Namespace,
 		}
 
-		if err := r.hubclient.Get(context.TODO(), scndChnSecKey, subitem.SecondaryChannelSecret); err != nil {
+		if err := hubclient.Get(context.TODO(), scndChnSecKey, subitem.SecondaryChannelSecret); err != nil {
 			return gerr.Wrap(err, "failed to get reference secret from the secondary channel")
 		}
 	}
@@ -376,7 +470,7 @@ func (r *ReconcileSubscription) doReconcile(instance *appv1.Subscription) error
 			Namespace: subitem.Channel.Namespace,
 		}
 
-		if err := r.hubclient.Get(context.TODO(), chncfgkey, subitem.ChannelConfigMap); err != nil {
+		if err := hubclient.Get(context.TODO(), chncfgkey, subitem.ChannelConfigMap); err != nil {
 			return gerr.Wrap(err, "failed to get reference configmap from channel")
 		}
 	}
@@ -388,7 +482,7 @@ func (r *ReconcileSubscription) doReconcile(instance *appv1.Subscription) error
 			Namespace: subitem.SecondaryChannel.Namespace,
 		}
 
-		if err := r.hubclient.Get(context.TODO(), scndChnCfgKey, subitem.SecondaryChannelConfigMap); err != nil {
+		if err := hubclient.Get(context.TODO(), scndChnCfgKey, subitem.SecondaryChannelConfigMap); err != nil {
 			return gerr.Wrap(err, "failed to get reference configmap from the secondary channel")
 		}
 	}
@@ -447,7 +541,7 @@ func (r *ReconcileSubscription) doReconcile(instance *appv1.Subscription) error
 		}
 
 		errLocal := r.Client.Get(context.TODO(), subcfgkeyL, subitem.SubscriptionConfigMap)
-		errRemote := r.hubclient.Get(context.TODO(), subcfgkeyR, subitem.SubscriptionConfigMap)
+		errRemote := hubclient.Get(context.TODO(), subcfgkeyR, subitem.SubscriptionConfigMap)
 
 		if errRemote != nil && errLocal != nil {
 			return gerr.Wrapf(errRemote, "failed to get reference configMap at local %v or hub %v of subsciption %v from hub",
@@ -457,11 +551,10 @@ func (r *ReconcileSubscription) doReconcile(instance *appv1.Subscription) error
 
 	subtype := strings.ToLower(string(subitem.Channel.Spec.Type))
 
-	if strings.EqualFold(subtype, chnv1.ChannelTypeGit) || strings.EqualFold(subtype, chnv1.ChannelTypeGitHub) ||
-		strings.EqualFold(subtype, chnv1.ChannelTypeObjectBucket) {
+	if usesGitSubscriber(subtype) || strings.EqualFold(subtype, chnv1.ChannelTypeObjectBucket) || usesBackendSubscriber(subtype) {
 		annotations := instance.GetAnnotations()
 
-		if utils.IsClusterAdmin(r.hubclient, instance, r.eventRecorder) {
+		if utils.IsClusterAdmin(hubclient, instance, r.eventRecorder) {
 			klog.Info("ADDING apps.open-cluster-management.io/cluster-admin: true")
 
 			annotations[appv1.AnnotationClusterAdmin] = "true"
@@ -475,12 +568,16 @@ func (r *ReconcileSubscription) doReconcile(instance *appv1.Subscription) error
 
 	// subscribe it with right channel type and unsubscribe from other channel types (in case user modify channel type)
 	for k, sub := range r.subscribers {
-		// git, github actually use the same subscriber.
+		// git, github and bundle actually use the same subscriber.
 		// The block is to prevent git sub from being un-subscribed by github subscriber.
 		// e.g. The git sub time window keeps active, but its time window spec could change.
 		// In this reconcile, we should prevent the git sub from being un-subscribed.
-		if (strings.EqualFold(k, chnv1.ChannelTypeGit) || strings.EqualFold(k, chnv1.ChannelTypeGitHub)) &&
-			(strings.EqualFold(subtype, chnv1.ChannelTypeGit) || strings.EqualFold(subtype, chnv1.ChannelTypeGitHub)) {
+		if usesGitSubscriber(k) && usesGitSubscriber(subtype) {
+			continue
+		}
+
+		// the registered channel backends share the backend subscriber too
+		if usesBackendSubscriber(k) && usesBackendSubscriber(subtype) {
 			continue
 		}
 
@@ -508,3 +605,15 @@ func (r *ReconcileSubscription) doReconcile(instance *appv1.Subscription) error
 
 	return nil
 }
+
+// usesGitSubscriber checks if the channel type is served by the git subscriber
+func usesGitSubscriber(chType string) bool {
+	return utils.IsGitChannel(chType) || utils.IsBundleChannel(chType)
+}
+
+// usesBackendSubscriber checks if the channel type is served by a registered channel backend
+func usesBackendSubscriber(chType string) bool {
+	_, ok := backend.Lookup(chType)
+
+	return ok
+}
@@ -57,6 +57,7 @@ import (
 	appSubStatusV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
 	helmoperator "open-cluster-management.io/multicloud-operators-subscription/pkg/helmrelease/release"
 	kubesynchronizer "open-cluster-management.io/multicloud-operators-subscription/pkg/synchronizer/kubernetes"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
 )
 
 const (
@@ -265,6 +266,21 @@ func (r *ReconcileHelmRelease) Reconcile(ctx context.Context, request reconcile.
 		Status: appv1.StatusTrue,
 	})
 
+	if err := r.ensureTargetNamespace(instance); err != nil {
+		klog.Error("Failed to ensure the target namespace of HelmRelease ", helmreleaseNsn(instance), " ", err)
+
+		instance.Status.SetCondition(appv1.HelmAppCondition{
+			Type:    appv1.ConditionIrreconcilable,
+			Status:  appv1.StatusTrue,
+			Reason:  appv1.ReasonReconcileError,
+			Message: err.Error(),
+		})
+		_ = r.updateResourceStatus(instance)
+		r.populateErrorAppSubStatus(err.Error(), instance)
+
+		return reconcile.Result{RequeueAfter: time.Minute * 1}, nil
+	}
+
 	klog.Info("Sync Release ", helmreleaseNsn(instance))
 
 	if err := manager.Sync(context.TODO()); err != nil {
@@ -398,10 +414,22 @@ func (r *ReconcileHelmRelease) install(instance *appv1.HelmRelease, manager helm
 
 	klog.Info("Installing (dry-run) Release ", helmreleaseNsn(instance))
 
+	// the client only dry-run renders with the default capabilities of helm, the chart is rendered with the Kubernetes
+	// version and the API versions of the cluster like the install
+	caps, err := GetCapabilities(manager.GetActionConfig())
+	if err != nil {
+		klog.Warning("Failed to get API Capabilities for the install (dry-run) ", helmreleaseNsn(instance), " ", err)
+	}
+
 	installDryRun := func(install *action.Install) error {
 		install.DryRun = true
 		install.ClientOnly = true
 
+		if caps != nil {
+			install.KubeVersion = &caps.KubeVersion
+			install.APIVersions = caps.APIVersions
+		}
+
 		return nil
 	}
 
@@ -526,11 +554,33 @@ func (r *ReconcileHelmRelease) install(instance *appv1.HelmRelease, manager helm
 			instance.Repo.Version+" "+string(appv1.ReasonInstallSuccessful), nil)
 	}
 
+	r.updateRequiredImages(instance, manager)
+
 	return reconcile.Result{}, err
 }
 
 func (r *ReconcileHelmRelease) upgrade(instance *appv1.HelmRelease, manager helmoperator.Manager) (reconcile.Result, error) {
 	klog.Info("Upgrading Release ", helmreleaseNsn(instance))
+
+	if instance.Repo.UpgradeCRDs {
+		crds, err := r.upgradeCRDs(instance, manager.ChartCRDs())
+		instance.Status.CRDs = crds
+
+		if err != nil {
+			klog.Error("Failed to upgrade the CRDs of HelmRelease ", helmreleaseNsn(instance), " ", err)
+			instance.Status.SetCondition(appv1.HelmAppCondition{
+				Type:    appv1.ConditionReleaseFailed,
+				Status:  appv1.StatusTrue,
+				Reason:  appv1.ReasonCRDUpgradeError,
+				Message: err.Error(),
+			})
+			_ = r.updateResourceStatus(instance)
+			r.populateErrorAppSubStatus(string(appv1.ReasonCRDUpgradeError)+" "+err.Error(), instance)
+
+			return reconcile.Result{RequeueAfter: time.Minute * 1}, nil
+		}
+	}
+
 	force := hasHelmUpgradeForceAnnotation(instance)
 	upgradeOpt := func(upgrade *action.Upgrade) error {
 		upgrade.DryRun = false
@@ -620,6 +670,8 @@ func (r *ReconcileHelmRelease) upgrade(instance *appv1.HelmRelease, manager helm
 			instance.Repo.Version+" "+string(appv1.ReasonUpgradeSuccessful), nil)
 	}
 
+	r.updateRequiredImages(instance, manager)
+
 	return reconcile.Result{}, err
 }
 
@@ -858,6 +910,27 @@ func (r *ReconcileHelmRelease) ensureStatusReasonPopulated(
 	return reconcile.Result{}, err
 }
 
+// updateRequiredImages records the images of the release if they are rewritten to the registry mirrors
+func (r *ReconcileHelmRelease) updateRequiredImages(instance *appv1.HelmRelease, manager helmoperator.Manager) {
+	images, ok := manager.RequiredImages()
+	if !ok {
+		return
+	}
+
+	appsubName := instance.GetName()
+
+	for _, hrOwner := range instance.OwnerReferences {
+		if strings.EqualFold(hrOwner.Kind, "Subscription") {
+			appsubName = hrOwner.Name
+		}
+	}
+
+	if err := utils.UpdateRequiredImagesConfigMap(r.GetClient(), instance, appv1.SchemeGroupVersion.WithKind("HelmRelease"),
+		appsubName, images); err != nil {
+		klog.Error("Failed to update the required images of HelmRelease ", helmreleaseNsn(instance), " ", err)
+	}
+}
+
 func (r *ReconcileHelmRelease) populateAppSubStatus(
 	manifest string, instance *appv1.HelmRelease, manager helmoperator.Manager, packagePhase string, helmReleaseMessage string,
 	skipUpdate *bool) {
@@ -882,7 +955,7 @@ func (r *ReconcileHelmRelease) populateAppSubStatus(
 							appSubUnitStatus.APIVersion = file.Head.Version
 							appSubUnitStatus.Kind = file.Head.Kind
 							appSubUnitStatus.Name = file.Head.Metadata.Name
-							appSubUnitStatus.Namespace = instance.Namespace
+							appSubUnitStatus.Namespace = instance.GetTargetNamespace()
 							appSubUnitStatus.Phase = packagePhase
 							appSubUnitStatus.Message = ""
 							appSubUnitStatuses = append(appSubUnitStatuses, appSubUnitStatus)
@@ -0,0 +1,162 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helmrelease
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ghodss/yaml"
+	cpb "helm.sh/helm/v3/pkg/chart"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/helmrelease/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+// crdFieldManager is the field manager of the server-side apply of the chart CRDs
+const crdFieldManager = "multicluster-operators-subscription-crds"
+
+// upgradeCRDs server-side applies the CRDs of the crds folders of the chart before the release upgrade, helm only
+// creates them on install. The CRDs are never deleted, a CRD is skipped if the upgrade isn't safe for its stored objects
+func (r *ReconcileHelmRelease) upgradeCRDs(hr *appv1.HelmRelease, crds []cpb.CRD) ([]appv1.HelmAppCRD, error) {
+	results := []appv1.HelmAppCRD{}
+
+	for _, crd := range crds {
+		if crd.File == nil {
+			continue
+		}
+
+		for _, item := range utils.ParseKubeResoures(crd.File.Data) {
+			desired := &unstructured.Unstructured{}
+			if err := yaml.Unmarshal(item, desired); err != nil {
+				return results, fmt.Errorf("failed to parse the CRD file %v: %w", crd.Filename, err)
+			}
+
+			result, err := r.applyCRD(desired)
+			if err != nil {
+				return results, err
+			}
+
+			klog.Info("CRD ", result.Name, " of HelmRelease ", helmreleaseNsn(hr), " ", result.Phase, " ", result.Reason)
+
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+func (r *ReconcileHelmRelease) applyCRD(desired *unstructured.Unstructured) (appv1.HelmAppCRD, error) {
+	result := appv1.HelmAppCRD{Name: desired.GetName(), Phase: appv1.CRDSkipped}
+
+	if desired.GroupVersionKind() != apiextv1.SchemeGroupVersion.WithKind("CustomResourceDefinition") {
+		result.Reason = fmt.Sprintf("%v %v is not a %v CustomResourceDefinition", desired.GetAPIVersion(), desired.GetKind(),
+			apiextv1.SchemeGroupVersion)
+
+		return result, nil
+	}
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(desired.GroupVersionKind())
+
+	err := r.GetClient().Get(context.TODO(), types.NamespacedName{Name: desired.GetName()}, existing)
+	if err != nil && !errors.IsNotFound(err) {
+		return result, fmt.Errorf("failed to get CRD %v: %w", desired.GetName(), err)
+	}
+
+	if err == nil {
+		reason, err := checkCRDUpgrade(existing, desired)
+		if err != nil {
+			return result, fmt.Errorf("failed to check the upgrade of CRD %v: %w", desired.GetName(), err)
+		}
+
+		if reason != "" {
+			result.Reason = reason
+
+			return result, nil
+		}
+
+		// the CA bundle of the conversion webhook is usually injected, an empty bundle of the chart doesn't reset it
+		caBundle, _, _ := unstructured.NestedString(desired.Object, "spec", "conversion", "webhook", "clientConfig", "caBundle")
+		if caBundle == "" {
+			unstructured.RemoveNestedField(desired.Object, "spec", "conversion", "webhook", "clientConfig", "caBundle")
+		}
+	}
+
+	desired.SetResourceVersion("")
+
+	if err := r.GetClient().Patch(context.TODO(), desired, client.Apply, client.FieldOwner(crdFieldManager),
+		client.ForceOwnership); err != nil {
+		return result, fmt.Errorf("failed to apply CRD %v: %w", desired.GetName(), err)
+	}
+
+	result.Phase = appv1.CRDUpdated
+
+	return result, nil
+}
+
+// checkCRDUpgrade returns why the CRD can't be upgraded to the desired CRD, it is empty if the upgrade is safe. The scope
+// and the kind of a CRD can't change, and the versions its objects are stored in must be kept
+func checkCRDUpgrade(existingObj, desiredObj *unstructured.Unstructured) (string, error) {
+	existing := &apiextv1.CustomResourceDefinition{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(existingObj.Object, existing); err != nil {
+		return "", err
+	}
+
+	desired := &apiextv1.CustomResourceDefinition{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(desiredObj.Object, desired); err != nil {
+		return "", err
+	}
+
+	if existing.Spec.Scope != desired.Spec.Scope {
+		return fmt.Sprintf("the scope can't change from %v to %v", existing.Spec.Scope, desired.Spec.Scope), nil
+	}
+
+	if existing.Spec.Names.Kind != desired.Spec.Names.Kind {
+		return fmt.Sprintf("the kind can't change from %v to %v", existing.Spec.Names.Kind, desired.Spec.Names.Kind), nil
+	}
+
+	versions := map[string]bool{}
+	storageVersions := 0
+
+	for _, version := range desired.Spec.Versions {
+		versions[version.Name] = true
+
+		if version.Storage {
+			storageVersions++
+		}
+	}
+
+	if storageVersions != 1 {
+		return fmt.Sprintf("it must have one storage version, found %v", storageVersions), nil
+	}
+
+	for _, storedVersion := range existing.Status.StoredVersions {
+		if !versions[storedVersion] {
+			return fmt.Sprintf("the stored version %v is removed", storedVersion), nil
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2020 Red Hat
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helmrelease
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const crdWidgets = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+spec:
+  group: example.com
+  names:
+    kind: Widget
+    plural: widgets
+  scope: Namespaced
+  versions:
+  - name: v1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        type: object
+status:
+  storedVersions:
+  - v1
+`
+
+func parseCRD(g *gomega.WithT, manifest string) *unstructured.Unstructured {
+	crd := &unstructured.Unstructured{}
+	g.Expect(yaml.Unmarshal([]byte(manifest), crd)).To(gomega.Succeed())
+
+	return crd
+}
+
+func TestCheckCRDUpgrade(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	existing := parseCRD(g, crdWidgets)
+
+	// a new served version is added
+	desired := parseCRD(g, crdWidgets)
+	versions, _, _ := unstructured.NestedSlice(desired.Object, "spec", "versions")
+	g.Expect(unstructured.SetNestedSlice(desired.Object, append(versions, map[string]interface{}{
+		"name": "v2", "served": true, "storage": false,
+	}), "spec", "versions")).To(gomega.Succeed())
+
+	reason, err := checkCRDUpgrade(existing, desired)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(reason).To(gomega.BeEmpty())
+
+	// the stored version v1 is removed
+	g.Expect(unstructured.SetNestedSlice(desired.Object, []interface{}{map[string]interface{}{
+		"name": "v2", "served": true, "storage": true,
+	}}, "spec", "versions")).To(gomega.Succeed())
+
+	reason, err = checkCRDUpgrade(existing, desired)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(reason).To(gomega.ContainSubstring("stored version v1"))
+
+	desired = parseCRD(g, crdWidgets)
+	g.Expect(unstructured.SetNestedField(desired.Object, "Cluster", "spec", "scope")).To(gomega.Succeed())
+
+	reason, err = checkCRDUpgrade(existing, desired)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(reason).To(gomega.ContainSubstring("scope"))
+
+	desired = parseCRD(g, crdWidgets)
+	g.Expect(unstructured.SetNestedField(desired.Object, "Gadget", "spec", "names", "kind")).To(gomega.Succeed())
+
+	reason, err = checkCRDUpgrade(existing, desired)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(reason).To(gomega.ContainSubstring("kind"))
+}
@@ -23,7 +23,9 @@ import (
 
 	"helm.sh/helm/v3/pkg/action"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -276,3 +278,37 @@ func GetCapabilities(c *action.Configuration) (*chartutil.Capabilities, error) {
 	}
 	return c.Capabilities, nil
 }
+
+// ensureTargetNamespace creates the target namespace of the HelmRelease with its labels if it is missing, the labels of
+// an existing namespace are not changed
+func (r *ReconcileHelmRelease) ensureTargetNamespace(hr *appv1.HelmRelease) error {
+	if hr.Repo.TargetNamespace == "" || hr.Repo.TargetNamespace == hr.GetNamespace() {
+		return nil
+	}
+
+	ns := &corev1.Namespace{}
+
+	err := r.GetClient().Get(context.TODO(), types.NamespacedName{Name: hr.Repo.TargetNamespace}, ns)
+	if err == nil {
+		return nil
+	}
+
+	if !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get the target namespace %v: %w", hr.Repo.TargetNamespace, err)
+	}
+
+	ns = &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   hr.Repo.TargetNamespace,
+			Labels: hr.Repo.TargetNamespaceLabels,
+		},
+	}
+
+	klog.Info("Creating the target namespace ", hr.Repo.TargetNamespace, " of HelmRelease ", hr.GetNamespace(), "/", hr.GetName())
+
+	if err := r.GetClient().Create(context.TODO(), ns); err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create the target namespace %v: %w", hr.Repo.TargetNamespace, err)
+	}
+
+	return nil
+}
@@ -92,7 +92,9 @@ func downloadChart(client client.Client, s *appv1.HelmRelease) (string, error) {
 		chartsDir = "/tmp/hr-charts"
 	}
 
-	chartDir, err := utils.DownloadChart(configMap, secret, chartsDir, s)
+	mirrorSecrets := utils.GetMirrorSecrets(client, s.Namespace, s)
+
+	chartDir, err := utils.DownloadChart(configMap, secret, mirrorSecrets, chartsDir, s)
 	klog.V(3).Info("ChartDir: ", chartDir)
 
 	if err != nil {
@@ -43,6 +43,7 @@ import (
 	apitypes "k8s.io/apimachinery/pkg/types"
 
 	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/helmrelease/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
 )
 
 // Manager manages a Helm release. It can install, upgrade, reconcile,
@@ -59,6 +60,8 @@ type Manager interface {
 	GetDeployedRelease() (*rpb.Release, error)
 	GetActionConfig() *action.Configuration
 	ReconcileRelease(context.Context) (*rpb.Release, error)
+	RequiredImages() ([]utils.RequiredImage, bool)
+	ChartCRDs() []cpb.CRD
 }
 
 type manager struct {
@@ -76,6 +79,8 @@ type manager struct {
 	isUpgradeRequired bool
 	deployedRelease   *rpb.Release
 	chart             *cpb.Chart
+
+	imageMirror *utils.ImageMirrorPostRenderer
 }
 
 type InstallOption func(*action.Install) error
@@ -91,6 +96,24 @@ func (m manager) ReleaseName() string {
 	return m.releaseName
 }
 
+// RequiredImages returns the images of the last rendered release if the images are rewritten to the registry mirrors
+func (m manager) RequiredImages() ([]utils.RequiredImage, bool) {
+	if m.imageMirror == nil {
+		return nil, false
+	}
+
+	return m.imageMirror.RequiredImages(), true
+}
+
+// ChartCRDs returns the CRDs of the crds folders of the chart and its subcharts
+func (m manager) ChartCRDs() []cpb.CRD {
+	if m.chart == nil {
+		return nil
+	}
+
+	return m.chart.CRDObjects()
+}
+
 func (m manager) IsInstalled() bool {
 	return m.isInstalled
 }
@@ -164,6 +187,9 @@ func (m manager) getCandidateRelease(namespace, name string, chart *cpb.Chart,
 	upgrade := action.NewUpgrade(m.actionConfig)
 	upgrade.Namespace = namespace
 	upgrade.DryRun = true
+	if m.imageMirror != nil {
+		upgrade.PostRenderer = m.imageMirror
+	}
 	return upgrade.Run(name, chart, values)
 }
 
@@ -172,6 +198,9 @@ func (m manager) InstallRelease(ctx context.Context, opts ...InstallOption) (*rp
 	install := action.NewInstall(m.actionConfig)
 	install.ReleaseName = m.releaseName
 	install.Namespace = m.namespace
+	if m.imageMirror != nil {
+		install.PostRenderer = m.imageMirror
+	}
 	for _, o := range opts {
 		if err := o(install); err != nil {
 			return nil, fmt.Errorf("failed to apply install option: %w", err)
@@ -192,6 +221,9 @@ func ForceUpgrade(force bool) UpgradeOption {
 func (m manager) UpgradeRelease(ctx context.Context, opts ...UpgradeOption) (*rpb.Release, *rpb.Release, error) {
 	upgrade := action.NewUpgrade(m.actionConfig)
 	upgrade.Namespace = m.namespace
+	if m.imageMirror != nil {
+		upgrade.PostRenderer = m.imageMirror
+	}
 	for _, o := range opts {
 		if err := o(upgrade); err != nil {
 			return nil, nil, fmt.Errorf("failed to apply upgrade option: %w", err)
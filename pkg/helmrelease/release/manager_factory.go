@@ -28,11 +28,14 @@ import (
 	"helm.sh/helm/v3/pkg/storage/driver"
 	"helm.sh/helm/v3/pkg/strvals"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
 	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	crmanager "sigs.k8s.io/controller-runtime/pkg/manager"
 
 	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/helmrelease/v1"
+	subv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
 	"open-cluster-management.io/multicloud-operators-subscription/pkg/helmrelease/client"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
 )
 
 // ManagerFactory creates Managers that are specific to custom resources. It is
@@ -61,9 +64,12 @@ func (f managerFactory) NewManager(cr *unstructured.Unstructured, overrideValues
 	}
 	storageBackend := storage.Init(driver.NewSecrets(clientv1.Secrets(cr.GetNamespace())))
 
+	// The release is stored in the namespace of the CR, the chart is installed in the target namespace
+	crReleaseName, namespace := releaseTarget(cr)
+
 	// Get the necessary clients and client getters. Use a client that injects the CR
 	// as an owner reference into all resources templated by the chart.
-	rcg, err := client.NewRESTClientGetter(f.mgr, cr.GetNamespace())
+	rcg, err := client.NewRESTClientGetter(f.mgr, namespace)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get REST client getter from manager: %w", err)
 	}
@@ -75,7 +81,7 @@ func (f managerFactory) NewManager(cr *unstructured.Unstructured, overrideValues
 		return nil, fmt.Errorf("failed to inject owner references: %w", err)
 	}
 
-	releaseName := cr.GetName()
+	releaseName := crReleaseName
 
 	var crChart *chart.Chart
 
@@ -85,7 +91,7 @@ func (f managerFactory) NewManager(cr *unstructured.Unstructured, overrideValues
 			return nil, fmt.Errorf("failed to load chart dir, most likely the given chart name is incorrect: %w", err)
 		}
 
-		releaseName, err = getReleaseName(storageBackend, crChart.Name(), cr)
+		releaseName, err = getReleaseName(storageBackend, crChart.Name(), crReleaseName)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get helm release name: %w", err)
 		}
@@ -102,6 +108,17 @@ func (f managerFactory) NewManager(cr *unstructured.Unstructured, overrideValues
 	}
 	values := mergeMaps(crValues, expOverrides)
 
+	var imageMirror *utils.ImageMirrorPostRenderer
+
+	if mirrorCM := cr.GetAnnotations()[subv1.AnnotationImageMirrorConfigMap]; mirrorCM != "" && cr.GetDeletionTimestamp() == nil {
+		mirrors, err := utils.GetImageMirrors(f.mgr.GetAPIReader(), types.NamespacedName{Name: mirrorCM, Namespace: cr.GetNamespace()})
+		if err != nil {
+			return nil, err
+		}
+
+		imageMirror = &utils.ImageMirrorPostRenderer{Mirrors: mirrors}
+	}
+
 	actionConfig := &action.Configuration{
 		RESTClientGetter: rcg,
 		Releases:         storageBackend,
@@ -115,19 +132,37 @@ func (f managerFactory) NewManager(cr *unstructured.Unstructured, overrideValues
 		kubeClient:     ownerRefClient,
 
 		releaseName: releaseName,
-		namespace:   cr.GetNamespace(),
+		namespace:   namespace,
 
 		chart:  crChart,
 		values: values,
 		status: appv1.StatusFor(cr),
+
+		imageMirror: imageMirror,
 	}, nil
 }
 
+// releaseTarget returns the release name and the target namespace set in the
+// repo of the CR, the defaults are the name and the namespace of the CR.
+func releaseTarget(cr *unstructured.Unstructured) (string, string) {
+	releaseName, _, _ := unstructured.NestedString(cr.Object, "repo", "releaseName")
+	if releaseName == "" {
+		releaseName = cr.GetName()
+	}
+
+	namespace, _, _ := unstructured.NestedString(cr.Object, "repo", "targetNamespace")
+	if namespace == "" {
+		namespace = cr.GetNamespace()
+	}
+
+	return releaseName, namespace
+}
+
 // getReleaseName returns a release name for the CR.
 //
-// getReleaseName searches for a release using the CR name. If a release
-// cannot be found, or if it is found and was created by the chart managed
-// by this manager, the CR name is returned.
+// getReleaseName searches for a release using the release name of the CR. If
+// a release cannot be found, or if it is found and was created by the chart
+// managed by this manager, the release name is returned.
 //
 // If a release is found but it was created by another chart, that means we
 // have a release name collision, so return an error. This case is possible
@@ -140,9 +175,8 @@ func (f managerFactory) NewManager(cr *unstructured.Unstructured, overrideValues
 //   collision. As is, the only indication of collision will be in the CR status
 //   and operator logs.
 func getReleaseName(storageBackend *storage.Storage, crChartName string,
-	cr *unstructured.Unstructured) (string, error) {
-	// If a release with the CR name does not exist, return the CR name.
-	releaseName := cr.GetName()
+	releaseName string) (string, error) {
+	// If a release with the release name does not exist, return the release name.
 	history, exists, err := releaseHistory(storageBackend, releaseName)
 	if err != nil {
 		return "", err
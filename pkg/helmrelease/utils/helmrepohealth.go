@@ -0,0 +1,112 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/helmrelease/v1"
+)
+
+// HelmRepoUnhealthyPeriod is how long a helm repo server stays unhealthy after a failed request, the URLs of the
+// unhealthy servers are tried after the URLs of the healthy ones until a request succeeds or the period is over
+var HelmRepoUnhealthyPeriod = 5 * time.Minute
+
+var (
+	helmRepoHealthLock sync.RWMutex
+	// the time of the last failed request of the unhealthy helm repo servers, by scheme and host
+	helmRepoFailures = map[string]time.Time{}
+)
+
+// helmRepoServer returns the scheme and host of the URL, the health is tracked per server as an outage of the
+// artifact server fails both the index and the chart requests
+func helmRepoServer(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+
+	return strings.ToLower(u.Scheme + "://" + u.Host)
+}
+
+// RecordHelmRepoRequest records the result of a request to a helm repo server, a server failing to answer or
+// answering with a server error is unhealthy until its next successful request or for HelmRepoUnhealthyPeriod
+func RecordHelmRepoRequest(rawURL string, healthy bool) {
+	server := helmRepoServer(rawURL)
+
+	helmRepoHealthLock.Lock()
+	defer helmRepoHealthLock.Unlock()
+
+	if healthy {
+		if _, ok := helmRepoFailures[server]; ok {
+			klog.Infof("helm repo server %v is healthy again", server)
+
+			delete(helmRepoFailures, server)
+		}
+
+		return
+	}
+
+	if _, ok := helmRepoFailures[server]; !ok {
+		klog.Warningf("helm repo server %v is unhealthy, its URLs are tried last for %v", server, HelmRepoUnhealthyPeriod)
+	}
+
+	helmRepoFailures[server] = time.Now()
+}
+
+// IsHelmRepoHealthy returns false if the server of the URL failed in the last HelmRepoUnhealthyPeriod
+func IsHelmRepoHealthy(rawURL string) bool {
+	helmRepoHealthLock.RLock()
+	defer helmRepoHealthLock.RUnlock()
+
+	failed, ok := helmRepoFailures[helmRepoServer(rawURL)]
+
+	return !ok || time.Since(failed) > HelmRepoUnhealthyPeriod
+}
+
+// SortHelmRepoURLs returns the URLs of the healthy servers first, in their order, followed by the URLs of the
+// unhealthy servers
+func SortHelmRepoURLs(urls []string) []string {
+	sorted := append([]string{}, urls...)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return IsHelmRepoHealthy(sorted[i]) && !IsHelmRepoHealthy(sorted[j])
+	})
+
+	return sorted
+}
+
+// getHelmRepoMirror returns the mirror the URL is under, the longest mirror URL if several match
+func getHelmRepoMirror(mirrors []appv1.HelmRepoMirror, rawURL string) *appv1.HelmRepoMirror {
+	var found *appv1.HelmRepoMirror
+
+	for i := range mirrors {
+		prefix := strings.TrimSuffix(mirrors[i].URL, "/") + "/"
+
+		if strings.HasPrefix(rawURL, prefix) && (found == nil || len(mirrors[i].URL) > len(found.URL)) {
+			found = &mirrors[i]
+		}
+	}
+
+	return found
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/helmrelease/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/testharness"
+)
+
+func TestDownloadChartFromHelmRepoMirror(t *testing.T) {
+	defer func() {
+		helmRepoHealthLock.Lock()
+		defer helmRepoHealthLock.Unlock()
+
+		helmRepoFailures = map[string]time.Time{}
+	}()
+
+	repo, err := testharness.NewHelmRepoServer()
+	assert.NoError(t, err)
+
+	defer repo.Close()
+
+	assert.NoError(t, repo.AddChart("demo", "0.1.0", map[string]string{"cm.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: demo\n"}))
+
+	// the primary server is down, the mirror only serves the requests with its own credentials
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer primary.Close()
+
+	repoURL, err := url.Parse(repo.URL)
+	assert.NoError(t, err)
+
+	proxy := httputil.NewSingleHostReverseProxy(repoURL)
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "mirror-user" || password != "mirror-password" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		proxy.ServeHTTP(w, r)
+	}))
+	defer mirror.Close()
+
+	hr := &appv1.HelmRelease{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "default"},
+		Repo: appv1.HelmReleaseRepo{
+			Source: &appv1.Source{
+				SourceType: appv1.HelmRepoSourceType,
+				HelmRepo: &appv1.HelmRepo{
+					Urls: []string{primary.URL + "/demo-0.1.0.tgz", mirror.URL + "/demo-0.1.0.tgz"},
+					Mirrors: []appv1.HelmRepoMirror{{
+						URL:       mirror.URL,
+						SecretRef: &corev1.ObjectReference{Name: "mirror-auth"},
+					}},
+				},
+			},
+			ChartName: "demo",
+		},
+	}
+
+	channelSecret := &corev1.Secret{Data: map[string][]byte{"user": []byte("channel-user"), "password": []byte("channel-password")}}
+	mirrorSecret := &corev1.Secret{Data: map[string][]byte{"user": []byte("mirror-user"), "password": []byte("mirror-password")}}
+
+	// the mirror URLs are not tried without the secret of the mirror
+	_, err = DownloadChartFromHelmRepo(nil, channelSecret, nil, t.TempDir(), hr)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "secret mirror-auth of the mirror not found")
+
+	assert.False(t, IsHelmRepoHealthy(primary.URL))
+	assert.True(t, IsHelmRepoHealthy(mirror.URL))
+	assert.Equal(t, []string{mirror.URL + "/demo-0.1.0.tgz", primary.URL + "/demo-0.1.0.tgz"},
+		SortHelmRepoURLs(hr.Repo.Source.HelmRepo.Urls))
+
+	dir := t.TempDir()
+
+	chartDir, err := DownloadChartFromHelmRepo(nil, channelSecret, map[string]*corev1.Secret{mirror.URL: mirrorSecret}, dir, hr)
+	assert.NoError(t, err)
+
+	_, err = os.Stat(filepath.Join(chartDir, "Chart.yaml"))
+	assert.NoError(t, err)
+
+	// the unhealthy servers are tried first again once the unhealthy period is over
+	helmRepoHealthLock.Lock()
+	helmRepoFailures[helmRepoServer(primary.URL)] = time.Now().Add(-HelmRepoUnhealthyPeriod - time.Second)
+	helmRepoHealthLock.Unlock()
+
+	assert.True(t, IsHelmRepoHealthy(primary.URL))
+	assert.Equal(t, hr.Repo.Source.HelmRepo.Urls, SortHelmRepoURLs(hr.Repo.Source.HelmRepo.Urls))
+
+	RecordHelmRepoRequest(primary.URL+"/index.yaml", true)
+	assert.Empty(t, helmRepoFailures)
+}
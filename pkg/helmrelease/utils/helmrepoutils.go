@@ -39,7 +39,6 @@ import (
 	"golang.org/x/crypto/ssh/knownhosts"
 	"gopkg.in/src-d/go-git.v4"
 	"gopkg.in/src-d/go-git.v4/plumbing"
-	gitclient "gopkg.in/src-d/go-git.v4/plumbing/transport/client"
 	githttp "gopkg.in/src-d/go-git.v4/plumbing/transport/http"
 	"helm.sh/helm/v3/pkg/chartutil"
 	corev1 "k8s.io/api/core/v1"
@@ -48,6 +47,8 @@ import (
 	"k8s.io/klog/v2"
 
 	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/helmrelease/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils/gittransport"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils/tlsconfig"
 )
 
 // GetHelmRepoClient returns an *http.client to access the helm repo
@@ -64,12 +65,11 @@ func GetHelmRepoClient(parentNamespace string, configMap *corev1.ConfigMap, skip
 		IdleConnTimeout:       90 * time.Second,
 		TLSHandshakeTimeout:   10 * time.Second,
 		ExpectContinueTimeout: 1 * time.Second,
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: skipCertVerify, // #nosec G402 InsecureSkipVerify conditionally
-			MinVersion:         tls.VersionTLS12,
-		},
+		TLSClientConfig:       tlsconfig.NewConfig(tls.VersionTLS12),
 	}
 
+	transport.TLSClientConfig.InsecureSkipVerify = skipCertVerify // #nosec G402 InsecureSkipVerify conditionally
+
 	if skipCertVerify {
 		klog.Info("repo.insecureSkipVerify=true. Skipping repo server's certificate verification.")
 	}
@@ -107,9 +107,10 @@ func GetHelmRepoClient(parentNamespace string, configMap *corev1.ConfigMap, skip
 	return httpClient, nil
 }
 
-// DownloadChart downloads the charts
+// DownloadChart downloads the charts, the mirrorSecrets are the secrets of the helm repo mirrors by mirror URL
 func DownloadChart(configMap *corev1.ConfigMap,
 	secret *corev1.Secret,
+	mirrorSecrets map[string]*corev1.Secret,
 	chartsDir string,
 	s *appv1.HelmRelease) (chartDir string, err error) {
 	destRepo := filepath.Join(chartsDir, s.Name, s.Namespace, s.Repo.ChartName)
@@ -123,7 +124,7 @@ func DownloadChart(configMap *corev1.ConfigMap,
 
 	switch strings.ToLower(string(s.Repo.Source.SourceType)) {
 	case string(appv1.HelmRepoSourceType):
-		return DownloadChartFromHelmRepo(configMap, secret, destRepo, s)
+		return DownloadChartFromHelmRepo(configMap, secret, mirrorSecrets, destRepo, s)
 	case string(appv1.GitHubSourceType):
 		return DownloadChartFromGit(configMap, secret, destRepo, s)
 	case string(appv1.GitSourceType):
@@ -243,6 +244,8 @@ func DownloadGitRepo(configMap *corev1.ConfigMap,
 
 		r, errClone := git.PlainClone(destRepo, false, options)
 
+		gittransport.Release(options.Auth)
+
 		if errClone != nil {
 			rErr = os.RemoveAll(destRepo)
 			if rErr != nil {
@@ -404,7 +407,7 @@ func getSSHOptions(options *git.CloneOptions, sshKey, passphrase []byte, knownho
 func getHTTPOptions(options *git.CloneOptions, caCerts string, insecureSkipVerify bool) error {
 	installProtocol := false
 
-	clientConfig := &tls.Config{MinVersion: tls.VersionTLS13}
+	clientConfig := tlsconfig.NewConfig(tls.VersionTLS13)
 
 	// skip TLS certificate verification for Git servers with custom or self-signed certs
 	if insecureSkipVerify {
@@ -486,15 +489,19 @@ func getHTTPOptions(options *git.CloneOptions, caCerts string, insecureSkipVerif
 			},
 		}
 
-		gitclient.InstallProtocol("https", githttp.NewClient(customClient))
+		// the clone has its own client, the other clones keep theirs
+		auth, _ := options.Auth.(githttp.AuthMethod)
+		options.Auth = gittransport.NewAuth(auth, customClient)
 	}
 
 	return nil
 }
 
-// DownloadChartFromHelmRepo downloads a chart into the chartDir
+// DownloadChartFromHelmRepo downloads a chart into the chartDir, the URLs of the healthy servers are tried first.
+// The URLs under a mirror are downloaded with the secret of the mirror, the other URLs with the secret.
 func DownloadChartFromHelmRepo(configMap *corev1.ConfigMap,
 	secret *corev1.Secret,
+	mirrorSecrets map[string]*corev1.Secret,
 	destRepo string,
 	s *appv1.HelmRelease) (chartDir string, err error) {
 	if s.Repo.Source.HelmRepo == nil {
@@ -504,8 +511,23 @@ func DownloadChartFromHelmRepo(configMap *corev1.ConfigMap,
 
 	var urlsError string
 
-	for _, url := range s.Repo.Source.HelmRepo.Urls {
-		chartDir, err := downloadChartFromURL(configMap, secret, destRepo, s, url)
+	for _, url := range SortHelmRepoURLs(s.Repo.Source.HelmRepo.Urls) {
+		urlSecret := secret
+
+		// the credentials of the channel are not sent to the mirrors
+		if mirror := getHelmRepoMirror(s.Repo.Source.HelmRepo.Mirrors, url); mirror != nil {
+			urlSecret = nil
+
+			if mirror.SecretRef != nil {
+				if urlSecret = mirrorSecrets[mirror.URL]; urlSecret == nil {
+					urlsError += " - url: " + url + " error: secret " + mirror.SecretRef.Name + " of the mirror not found"
+
+					continue
+				}
+			}
+		}
+
+		chartDir, err := downloadChartFromURL(configMap, urlSecret, destRepo, s, url)
 		if err == nil {
 			return chartDir, nil
 		}
@@ -665,17 +687,25 @@ func downloadFileHTTP(parentNamespace string, configMap *corev1.ConfigMap,
 		}
 
 		if secret != nil && secret.Data != nil {
-			req.SetBasicAuth(string(secret.Data["user"]), GetPassword(secret))
+			if authHeader, ok := secret.Data["authHeader"]; ok {
+				req.Header.Set("Authorization", string(authHeader))
+			} else {
+				req.SetBasicAuth(string(secret.Data["user"]), GetPassword(secret))
+			}
 		}
 
 		var resp *http.Response
 
 		resp, downloadErr = httpClient.Do(req)
 		if downloadErr != nil {
+			RecordHelmRepoRequest(fileURL, false)
 			klog.Error(downloadErr, "- Http request failed: ", "fileURL", fileURL)
+
 			return downloadErr
 		}
 
+		RecordHelmRepoRequest(fileURL, resp.StatusCode < http.StatusInternalServerError)
+
 		if resp.StatusCode != 200 {
 			downloadErr = fmt.Errorf("return code: %d unable to retrieve chart", resp.StatusCode)
 			klog.Error(downloadErr, " - Unable to retrieve chart")
@@ -169,7 +169,7 @@ func TestDownloadChartGitHub(t *testing.T) {
 
 	defer os.RemoveAll(dir)
 
-	destDir, err := DownloadChart(nil, nil, dir, hr)
+	destDir, err := DownloadChart(nil, nil, nil, dir, hr)
 	assert.NoError(t, err)
 
 	_, err = os.Stat(filepath.Join(destDir, "Chart.yaml"))
@@ -199,7 +199,7 @@ func TestDownloadChartGit(t *testing.T) {
 
 	defer os.RemoveAll(dir)
 
-	destDir, err := DownloadChart(nil, nil, dir, hr)
+	destDir, err := DownloadChart(nil, nil, nil, dir, hr)
 	assert.NoError(t, err)
 
 	_, err = os.Stat(filepath.Join(destDir, "Chart.yaml"))
@@ -229,7 +229,7 @@ func TestDownloadChartHelmRepo(t *testing.T) {
 
 	defer os.RemoveAll(dir)
 
-	destDir, err := DownloadChart(nil, nil, dir, hr)
+	destDir, err := DownloadChart(nil, nil, nil, dir, hr)
 	assert.NoError(t, err)
 
 	_, err = os.Stat(filepath.Join(destDir, "Chart.yaml"))
@@ -262,7 +262,7 @@ func TestDownloadChartHelmRepoContainsInvalidURL(t *testing.T) {
 
 	defer os.RemoveAll(dir)
 
-	destDir, err := DownloadChart(nil, nil, dir, hr)
+	destDir, err := DownloadChart(nil, nil, nil, dir, hr)
 	assert.NoError(t, err)
 
 	_, err = os.Stat(filepath.Join(destDir, "Chart.yaml"))
@@ -291,7 +291,7 @@ func TestDownloadChartHelmRepoContainsInvalidURL2(t *testing.T) {
 
 	defer os.RemoveAll(dir)
 
-	destDir, err := DownloadChart(nil, nil, dir, hr)
+	destDir, err := DownloadChart(nil, nil, nil, dir, hr)
 	assert.NoError(t, err)
 
 	_, err = os.Stat(filepath.Join(destDir, "Chart.yaml"))
@@ -319,7 +319,7 @@ func TestDownloadChartHelmRepoAllInvalidURLs(t *testing.T) {
 
 	defer os.RemoveAll(dir)
 
-	_, err = DownloadChart(nil, nil, dir, hr)
+	_, err = DownloadChart(nil, nil, nil, dir, hr)
 	assert.Error(t, err)
 }
 
@@ -406,7 +406,7 @@ func TestDownloadChartFromHelmRepoHTTP(t *testing.T) {
 
 	defer os.RemoveAll(dir)
 
-	chartDir, err := DownloadChartFromHelmRepo(nil, nil, dir, hr)
+	chartDir, err := DownloadChartFromHelmRepo(nil, nil, nil, dir, hr)
 	assert.NoError(t, err)
 
 	_, err = os.Stat(filepath.Join(chartDir, "Chart.yaml"))
@@ -443,7 +443,7 @@ func TestDownloadChartFromHelmRepoHTTPConfigMap(t *testing.T) {
 		Data: map[string]string{
 			"insecureSkipVerify": "true",
 		},
-	}, nil, dir, hr)
+	}, nil, nil, dir, hr)
 	assert.NoError(t, err)
 
 	_, err = os.Stat(filepath.Join(chartDir, "Chart.yaml"))
@@ -475,7 +475,7 @@ func TestDownloadChartFromHelmRepoHTTPNoDigest(t *testing.T) {
 
 	defer os.RemoveAll(dir)
 
-	chartDir, err := DownloadChartFromHelmRepo(nil, nil, dir, hr)
+	chartDir, err := DownloadChartFromHelmRepo(nil, nil, nil, dir, hr)
 	assert.NoError(t, err)
 
 	_, err = os.Stat(filepath.Join(chartDir, "Chart.yaml"))
@@ -507,7 +507,7 @@ func TestDownloadChartFromHelmRepoLocal(t *testing.T) {
 
 	defer os.RemoveAll(dir)
 
-	chartDir, err := DownloadChartFromHelmRepo(nil, nil, dir, hr)
+	chartDir, err := DownloadChartFromHelmRepo(nil, nil, nil, dir, hr)
 	assert.NoError(t, err)
 
 	_, err = os.Stat(filepath.Join(chartDir, "Chart.yaml"))
@@ -538,7 +538,7 @@ func TestDownloadChartFromHelmRepoLocalNoDigest(t *testing.T) {
 
 	defer os.RemoveAll(dir)
 
-	chartDir, err := DownloadChartFromHelmRepo(nil, nil, dir, hr)
+	chartDir, err := DownloadChartFromHelmRepo(nil, nil, nil, dir, hr)
 	assert.NoError(t, err)
 
 	_, err = os.Stat(filepath.Join(chartDir, "Chart.yaml"))
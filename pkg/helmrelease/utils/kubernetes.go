@@ -24,6 +24,8 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/helmrelease/v1"
 )
 
 //GetAccessToken retrieve the accessToken
@@ -130,3 +132,30 @@ func GetSecret(client client.Client, parentNamespace string, secretRef *corev1.O
 
 	return secret, err
 }
+
+// GetMirrorSecrets retrieves the secrets of the helm repo mirrors of the HelmRelease, by mirror URL. The mirrors
+// with a secret not found are left out, their URLs are skipped.
+func GetMirrorSecrets(client client.Client, parentNamespace string, s *appv1.HelmRelease) map[string]*corev1.Secret {
+	secrets := map[string]*corev1.Secret{}
+
+	if s.Repo.Source == nil || s.Repo.Source.HelmRepo == nil {
+		return secrets
+	}
+
+	for _, mirror := range s.Repo.Source.HelmRepo.Mirrors {
+		if mirror.SecretRef == nil {
+			continue
+		}
+
+		secret, err := GetSecret(client, parentNamespace, mirror.SecretRef)
+		if err != nil {
+			klog.Errorf("failed to retrieve secret %v of helm repo mirror %v, err: %v", mirror.SecretRef.Name, mirror.URL, err)
+
+			continue
+		}
+
+		secrets[mirror.URL] = secret
+	}
+
+	return secrets
+}
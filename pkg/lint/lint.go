@@ -0,0 +1,729 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lint validates the subscription and channel manifests offline, with the rules the conversion webhooks and
+// the controllers apply to them on the hub.
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appv1beta1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1beta1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+	awsutils "open-cluster-management.io/multicloud-operators-subscription/pkg/utils/aws"
+)
+
+// Severity is the severity of an issue
+type Severity string
+
+const (
+	// SeverityError is an issue the controllers reject or fail on
+	SeverityError Severity = "error"
+	// SeverityWarning is a setting the controllers ignore, or a reference not found in the manifests
+	SeverityWarning Severity = "warning"
+)
+
+// Issue is a problem found in a manifest
+type Issue struct {
+	Severity Severity
+	// Source is the file of the manifest
+	Source string
+	// Object is the kind, the namespace and the name of the manifest
+	Object  string
+	Message string
+}
+
+func (i Issue) String() string {
+	if i.Source == "" {
+		return fmt.Sprintf("%v: %v: %v", i.Severity, i.Object, i.Message)
+	}
+
+	return fmt.Sprintf("%v: %v: %v: %v", i.Source, i.Severity, i.Object, i.Message)
+}
+
+// HasErrors checks if one of the issues is an error
+func HasErrors(issues []Issue) bool {
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Linter collects the manifests and checks the subscriptions and the channels along with their references
+type Linter struct {
+	subscriptions []*appv1.Subscription
+	channels      map[string]*chnv1.Channel
+	// the sources of the subscriptions and the channels
+	sources map[interface{}]string
+	// the other objects by kind/namespace/name
+	objects map[string]*unstructured.Unstructured
+	issues  []Issue
+}
+
+// NewLinter returns an empty linter
+func NewLinter() *Linter {
+	return &Linter{
+		channels: map[string]*chnv1.Channel{},
+		sources:  map[interface{}]string{},
+		objects:  map[string]*unstructured.Unstructured{},
+	}
+}
+
+// Add adds a manifest of the source file, the decoding errors of the subscriptions and the channels are issues of the
+// manifest
+func (l *Linter) Add(source string, doc []byte) error {
+	u := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal(doc, u); err != nil {
+		return err
+	}
+
+	if len(u.Object) == 0 {
+		return nil
+	}
+
+	gvk := u.GroupVersionKind()
+	object := objectName(u.GetKind(), u.GetNamespace(), u.GetName())
+
+	switch {
+	case gvk.Group == appv1.SchemeGroupVersion.Group && gvk.Kind == "Subscription":
+		sub, err := decodeSubscription(doc, gvk.Version)
+		if err != nil {
+			l.errorf(source, object, "%v", err)
+
+			return nil
+		}
+
+		l.subscriptions = append(l.subscriptions, sub)
+		l.sources[sub] = source
+	case gvk.Group == chnv1.SchemeGroupVersion.Group && gvk.Kind == "Channel":
+		chn, err := decodeChannel(doc, gvk.Version)
+		if err != nil {
+			l.errorf(source, object, "%v", err)
+
+			return nil
+		}
+
+		l.channels[chn.Namespace+"/"+chn.Name] = chn
+		l.sources[chn] = source
+	default:
+		l.objects[object] = u
+	}
+
+	return nil
+}
+
+// Lint returns the issues of the manifests added so far
+func (l *Linter) Lint() []Issue {
+	issues := append([]Issue{}, l.issues...)
+
+	for _, chn := range sortedChannels(l.channels) {
+		issues = append(issues, l.lintChannel(chn)...)
+	}
+
+	for _, sub := range l.subscriptions {
+		issues = append(issues, l.lintSubscription(sub)...)
+	}
+
+	return issues
+}
+
+func (l *Linter) errorf(source, object, format string, args ...interface{}) {
+	l.issues = append(l.issues, Issue{
+		Severity: SeverityError, Source: source, Object: object, Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// decodeSubscription decodes a v1 or v1beta1 subscription, the v1beta1 subscriptions are converted to v1 like the
+// conversion webhook does. The unknown fields are errors, they are dropped by the API server
+func decodeSubscription(doc []byte, version string) (*appv1.Subscription, error) {
+	switch version {
+	case appv1.SchemeGroupVersion.Version:
+		sub := &appv1.Subscription{}
+
+		return sub, decodeStrict(doc, sub)
+	case appv1beta1.SchemeGroupVersion.Version:
+		src := &appv1beta1.Subscription{}
+		if err := decodeStrict(doc, src); err != nil {
+			return nil, err
+		}
+
+		sub := &appv1.Subscription{}
+		if err := src.ConvertTo(sub); err != nil {
+			return nil, fmt.Errorf("failed to convert the subscription to %v: %w", appv1.SchemeGroupVersion, err)
+		}
+
+		return sub, nil
+	}
+
+	return nil, fmt.Errorf("unsupported version %v", version)
+}
+
+func decodeChannel(doc []byte, version string) (*chnv1.Channel, error) {
+	switch version {
+	case chnv1.SchemeGroupVersion.Version:
+		chn := &chnv1.Channel{}
+
+		return chn, decodeStrict(doc, chn)
+	case appv1beta1.SchemeGroupVersion.Version:
+		src := &appv1beta1.Channel{}
+		if err := decodeStrict(doc, src); err != nil {
+			return nil, err
+		}
+
+		chn, err := appv1beta1.ConvertChannelToV1(src)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert the channel to %v: %w", chnv1.SchemeGroupVersion, err)
+		}
+
+		return chn, nil
+	}
+
+	return nil, fmt.Errorf("unsupported version %v", version)
+}
+
+func decodeStrict(doc []byte, obj interface{}) error {
+	data, err := yaml.YAMLToJSON(doc)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	return dec.Decode(obj)
+}
+
+func (l *Linter) lintChannel(chn *chnv1.Channel) []Issue {
+	c := &checker{source: l.sources[chn], object: objectName("Channel", chn.Namespace, chn.Name)}
+
+	if chn.Name == "" {
+		c.errorf("metadata.name is required")
+	}
+
+	if chn.Namespace == "" {
+		c.warningf("metadata.namespace is not set, the namespace of the kubectl context is used")
+	}
+
+	tp := strings.ToLower(string(chn.Spec.Type))
+
+	switch tp {
+	case chnv1.ChannelTypeGit, chnv1.ChannelTypeGitHub, chnv1.ChannelTypeHelmRepo, chnv1.ChannelTypeObjectBucket:
+		c.checkPathname(tp, chn.Spec.Pathname)
+	case chnv1.ChannelTypeNamespace:
+	default:
+		c.errorf("unsupported channel type %v", chn.Spec.Type)
+	}
+
+	if chn.Spec.SecretRef != nil {
+		c.checkReference("Secret", chn.Namespace, chn.Spec.SecretRef.Name, l.objects)
+	}
+
+	if chn.Spec.ConfigMapRef != nil {
+		cm := c.checkReference("ConfigMap", chn.Namespace, chn.Spec.ConfigMapRef.Name, l.objects)
+
+		if cm != nil && tp == chnv1.ChannelTypeObjectBucket {
+			if _, err := awsutils.ConnectionOptionsFromConfigMap(toConfigMap(cm), chn.Spec.InsecureSkipVerify); err != nil {
+				c.errorf("%v", err)
+			}
+		}
+	}
+
+	c.checkReconcileRate(chn.GetAnnotations())
+
+	return c.issues
+}
+
+func (l *Linter) lintSubscription(sub *appv1.Subscription) []Issue {
+	c := &checker{source: l.sources[sub], object: objectName("Subscription", sub.Namespace, sub.Name)}
+
+	if sub.Name == "" {
+		c.errorf("metadata.name is required")
+	}
+
+	if sub.Namespace == "" {
+		c.warningf("metadata.namespace is not set, the namespace of the kubectl context is used")
+	}
+
+	var chn *chnv1.Channel
+
+	if sub.Spec.Channel == "" {
+		c.errorf("spec.channel is required")
+	} else if nn := utils.NamespacedNameFormat(sub.Spec.Channel); nn.Name == "" {
+		c.errorf("spec.channel %v must be <namespace>/<name>", sub.Spec.Channel)
+	} else if chn = l.channels[nn.String()]; chn == nil {
+		c.warningf("channel %v is not in the manifests, the checks of the channel type are skipped", sub.Spec.Channel)
+	}
+
+	if sub.Spec.SecondaryChannel != "" && utils.NamespacedNameFormat(sub.Spec.SecondaryChannel).Name == "" {
+		c.errorf("spec.secondaryChannel %v must be <namespace>/<name>", sub.Spec.SecondaryChannel)
+	}
+
+	if err := utils.ValidatePlacement(sub); err != nil {
+		c.errorf("%v", err)
+	} else if ref := sub.Spec.Placement.PlacementRef; ref != nil {
+		c.checkPlacementRef(sub.Namespace, ref.Kind, ref.Name, l.objects)
+	}
+
+	if err := utils.ValidatePackagePatches(sub); err != nil {
+		c.errorf("%v", err)
+	}
+
+	c.checkPackageOverrides("spec.packageOverrides", sub.Spec.PackageOverrides)
+
+	for i, rule := range sub.Spec.OverrideRules {
+		if len(rule.PackageOverrides) == 0 && rule.GitBranch == "" && rule.GitPath == "" {
+			c.errorf("spec.overrideRules[%v] has no package overrides nor git branch and path", i)
+		}
+
+		if (rule.GitBranch != "" || rule.GitPath != "") && chn != nil && !utils.IsGitChannel(string(chn.Spec.Type)) {
+			c.warningf("spec.overrideRules[%v] git branch and path are ignored by the %v channel %v/%v", i,
+				chn.Spec.Type, chn.Namespace, chn.Name)
+		}
+
+		c.checkLabelSelector(fmt.Sprintf("spec.overrideRules[%v].clusterSelector", i), rule.ClusterSelector)
+		c.checkPackageOverrides(fmt.Sprintf("spec.overrideRules[%v].packageOverrides", i), rule.PackageOverrides)
+	}
+
+	c.checkPromotion(sub, chn)
+
+	c.checkPackageFilter(sub.Spec.PackageFilter)
+
+	if err := utils.ValidateTimeWindow(sub.Spec.TimeWindow); err != nil {
+		c.errorf("spec.timewindow: %v", err)
+	}
+
+	for i, dep := range sub.Spec.DependsOn {
+		if dep.Name == "" {
+			c.errorf("spec.dependsOn[%v].name is required", i)
+		}
+
+		if dep.Condition != "" && dep.Condition != appv1.DependencyDeployed && dep.Condition != appv1.DependencyHealthy {
+			c.errorf("spec.dependsOn[%v].condition %v must be %v or %v", i, dep.Condition, appv1.DependencyDeployed,
+				appv1.DependencyHealthy)
+		}
+	}
+
+	if sub.Spec.ProgressDeadline != nil && sub.Spec.ProgressDeadline.Duration <= 0 {
+		c.errorf("spec.progressDeadline %v must be positive", sub.Spec.ProgressDeadline.Duration)
+	}
+
+	c.checkRetry(sub)
+
+	c.checkAnnotations(sub, chn)
+
+	return c.issues
+}
+
+// checker collects the issues of a manifest
+type checker struct {
+	source string
+	object string
+	issues []Issue
+}
+
+func (c *checker) errorf(format string, args ...interface{}) {
+	c.issues = append(c.issues, Issue{
+		Severity: SeverityError, Source: c.source, Object: c.object, Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *checker) warningf(format string, args ...interface{}) {
+	c.issues = append(c.issues, Issue{
+		Severity: SeverityWarning, Source: c.source, Object: c.object, Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *checker) checkPathname(tp, pathname string) {
+	if pathname == "" {
+		c.errorf("spec.pathname is required by the %v channels", tp)
+
+		return
+	}
+
+	// the scp-like git URLs are not URLs
+	if (tp == chnv1.ChannelTypeGit || tp == chnv1.ChannelTypeGitHub) && strings.HasPrefix(pathname, "git@") {
+		return
+	}
+
+	u, err := url.Parse(pathname)
+	if err != nil {
+		c.errorf("invalid spec.pathname %v: %v", pathname, err)
+
+		return
+	}
+
+	if u.Host == "" {
+		c.errorf("spec.pathname %v has no host", pathname)
+	}
+
+	if tp == chnv1.ChannelTypeObjectBucket && strings.Trim(u.Path, "/") == "" {
+		c.errorf("spec.pathname %v has no bucket", pathname)
+	}
+}
+
+// checkReference returns the referenced object, a missing object is a warning as it can already be on the hub
+func (c *checker) checkReference(kind, namespace, name string,
+	objects map[string]*unstructured.Unstructured) *unstructured.Unstructured {
+	obj := objects[objectName(kind, namespace, name)]
+	if obj == nil {
+		c.warningf("%v %v/%v is not in the manifests", kind, namespace, name)
+	}
+
+	return obj
+}
+
+// checkPlacementRef checks the placement reference like the hub, the placement must be in the subscription namespace
+func (c *checker) checkPlacementRef(namespace, kind, name string, objects map[string]*unstructured.Unstructured) {
+	if kind != "" && kind != "PlacementRule" && kind != "Placement" {
+		c.errorf("unsupported spec.placement.placementRef kind %v, it must be PlacementRule or Placement", kind)
+
+		return
+	}
+
+	if name == "" {
+		c.errorf("spec.placement.placementRef.name is required")
+
+		return
+	}
+
+	if kind == "" {
+		kind = "PlacementRule"
+	}
+
+	c.checkReference(kind, namespace, name, objects)
+}
+
+func (c *checker) checkPackageOverrides(field string, packageOverrides []*appv1.Overrides) {
+	for i, ov := range packageOverrides {
+		if ov == nil {
+			continue
+		}
+
+		if ov.PackageName == "" {
+			c.errorf("%v[%v].packageName is required", field, i)
+		}
+
+		for j, pov := range ov.PackageOverrides {
+			if err := utils.ValidatePackageOverride(pov); err != nil {
+				c.errorf("%v[%v].packageOverrides[%v]: %v", field, i, j, err)
+			}
+		}
+
+		if err := utils.ValidatePackageRelease(ov); err != nil {
+			c.errorf("%v[%v]: %v", field, i, err)
+		}
+	}
+}
+
+func (c *checker) checkPackageFilter(filter *appv1.PackageFilter) {
+	if filter == nil {
+		return
+	}
+
+	if filter.Version != "" {
+		if _, err := semver.NewConstraint(filter.Version); err != nil {
+			c.errorf("invalid spec.packageFilter.version %v: %v", filter.Version, err)
+		}
+	}
+
+	for i, chart := range filter.Charts {
+		if chart.Name == "" {
+			c.errorf("spec.packageFilter.charts[%v].name is required", i)
+		}
+
+		if chart.Version != "" {
+			if _, err := semver.NewConstraint(chart.Version); err != nil {
+				c.errorf("invalid spec.packageFilter.charts[%v].version %v: %v", i, chart.Version, err)
+			}
+		}
+	}
+
+	if filter.NameRegex != "" {
+		if _, err := regexp.Compile(filter.NameRegex); err != nil {
+			c.errorf("invalid spec.packageFilter.nameRegex %v: %v", filter.NameRegex, err)
+		}
+	}
+
+	for _, pattern := range append(append([]string{}, filter.IncludePaths...), filter.ExcludePaths...) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			c.errorf("invalid spec.packageFilter path %v: %v", pattern, err)
+		}
+	}
+
+	c.checkLabelSelector("spec.packageFilter.labelSelector", filter.LabelSelector)
+	c.checkLabelSelector("spec.packageFilter.annotationSelector", filter.AnnotationSelector)
+}
+
+func (c *checker) checkRetry(sub *appv1.Subscription) {
+	retry := sub.Spec.Retry
+	if retry == nil {
+		return
+	}
+
+	if retry.Backoff.Duration < 0 {
+		c.errorf("spec.retry.backoff %v must not be negative", retry.Backoff.Duration)
+	}
+
+	if retry.MaxBackoff.Duration < 0 {
+		c.errorf("spec.retry.maxBackoff %v must not be negative", retry.MaxBackoff.Duration)
+	} else if retry.MaxBackoff.Duration > 0 && retry.MaxBackoff.Duration < retry.Backoff.Duration {
+		c.warningf("spec.retry.maxBackoff %v is shorter than the backoff %v, the clusters are retried every %v",
+			retry.MaxBackoff.Duration, retry.Backoff.Duration, retry.MaxBackoff.Duration)
+	}
+
+	if retry.MaxRetries < 0 {
+		c.errorf("spec.retry.maxRetries %v must not be negative", retry.MaxRetries)
+	}
+
+	if retry.InTimeWindow && sub.Spec.TimeWindow == nil {
+		c.warningf("spec.retry.inTimeWindow is ignored without spec.timewindow")
+	}
+}
+
+func (c *checker) checkPromotion(sub *appv1.Subscription, chn *chnv1.Channel) {
+	promotion := sub.Spec.Promotion
+	if promotion == nil {
+		if sub.GetAnnotations()[appv1.AnnotationPromotionApproved] != "" {
+			c.warningf("annotation %v is ignored without spec.promotion", appv1.AnnotationPromotionApproved)
+		}
+
+		return
+	}
+
+	if len(promotion.Rings) == 0 {
+		c.errorf("spec.promotion.rings must have at least one ring")
+	}
+
+	if pl := sub.Spec.Placement; pl == nil || pl.PlacementRef == nil || !strings.EqualFold(pl.PlacementRef.Kind, "Placement") {
+		c.errorf("spec.promotion requires a Placement placementRef, the rings are its decision groups")
+	}
+
+	if chn != nil && !utils.IsGitChannel(string(chn.Spec.Type)) {
+		c.warningf("spec.promotion is ignored by the %v channel %v/%v", chn.Spec.Type, chn.Namespace, chn.Name)
+	}
+
+	if promotion.MaxFailures != nil && *promotion.MaxFailures < 0 {
+		c.errorf("spec.promotion.maxFailures %v must not be negative", *promotion.MaxFailures)
+	}
+
+	if p := promotion.MaxFailurePercentage; p != nil && (*p < 0 || *p > 100) {
+		c.errorf("spec.promotion.maxFailurePercentage %v must be between 0 and 100", *p)
+	}
+
+	groups := map[string]bool{}
+
+	for i, ring := range promotion.Rings {
+		if ring.DecisionGroup == "" {
+			c.errorf("spec.promotion.rings[%v].decisionGroup is required", i)
+		} else if groups[ring.DecisionGroup] {
+			c.errorf("spec.promotion.rings[%v].decisionGroup %v is in several rings", i, ring.DecisionGroup)
+		}
+
+		groups[ring.DecisionGroup] = true
+
+		if ring.SoakDuration.Duration < 0 {
+			c.errorf("spec.promotion.rings[%v].soakDuration %v must not be negative", i, ring.SoakDuration.Duration)
+		}
+
+		if err := utils.ValidateTimeWindow(ring.TimeWindow); err != nil {
+			c.errorf("spec.promotion.rings[%v].timewindow: %v", i, err)
+		}
+
+		if ring.ApprovalTimeout.Duration < 0 {
+			c.errorf("spec.promotion.rings[%v].approvalTimeout %v must not be negative", i, ring.ApprovalTimeout.Duration)
+		} else if ring.ApprovalTimeout.Duration > 0 && !ring.ManualApproval {
+			c.warningf("spec.promotion.rings[%v].approvalTimeout is ignored without manualApproval", i)
+		}
+
+		if i == 0 && (ring.SoakDuration.Duration > 0 || ring.TimeWindow != nil || ring.ManualApproval) {
+			c.warningf("spec.promotion.rings[0] deploys the subscription commit, its soak duration, timewindow and " +
+				"manual approval are ignored")
+		}
+	}
+
+	approvals := sub.GetAnnotations()[appv1.AnnotationPromotionApproved]
+	if approvals == "" {
+		return
+	}
+
+	for _, approval := range strings.Split(approvals, ",") {
+		group, commit, found := strings.Cut(strings.TrimSpace(approval), "=")
+		if !found || group == "" || commit == "" {
+			c.errorf("annotation %v entry %q must be <decision group>=<commit>", appv1.AnnotationPromotionApproved, approval)
+		} else if !groups[group] {
+			c.warningf("annotation %v approves decision group %v which is not a promotion ring", appv1.AnnotationPromotionApproved,
+				group)
+		}
+	}
+}
+
+func (c *checker) checkLabelSelector(field string, selector *metav1.LabelSelector) {
+	if selector == nil {
+		return
+	}
+
+	if _, err := metav1.LabelSelectorAsSelector(selector); err != nil {
+		c.errorf("invalid %v: %v", field, err)
+	}
+}
+
+// the annotations of the subscriptions only honored by a channel type
+var channelTypeAnnotations = []struct {
+	channelType string
+	annotations []string
+}{
+	{chnv1.ChannelTypeGit, []string{
+		appv1.AnnotationGitPath, appv1.AnnotationGitBranch, appv1.AnnotationGitTargetCommit, appv1.AnnotationGitTag,
+		appv1.AnnotationGitCloneDepth, appv1.AnnotationGitPullRequest, appv1.AnnotationGitProvider,
+		appv1.AnnotationGitCommitStatus, appv1.AnnotationGithubPath, appv1.AnnotationGithubBranch,
+	}},
+	{chnv1.ChannelTypeHelmRepo, []string{appv1.AnnotationHelmVersionHold}},
+	{chnv1.ChannelTypeObjectBucket, []string{appv1.AnnotationBucketPath, appv1.AnnotationBucketLayout}},
+}
+
+// the annotations of the subscriptions only enabled by "true"
+var booleanAnnotations = []string{
+	appv1.AnnotationClusterAdmin, appv1.AnnotationCurrentNamespaceScoped, appv1.AnnotationGitCommitStatus,
+	appv1.AnnotationHelmVersionHold, appv1.AnnotationRenderedManifests,
+}
+
+func (c *checker) checkAnnotations(sub *appv1.Subscription, chn *chnv1.Channel) {
+	annotations := sub.GetAnnotations()
+
+	for _, key := range booleanAnnotations {
+		if v, ok := annotations[key]; ok && !strings.EqualFold(v, "true") && !strings.EqualFold(v, "false") {
+			c.warningf("annotation %v is %q, only true enables it", key, v)
+		}
+	}
+
+	switch v := annotations[appv1.AnnotationResourceReconcileOption]; {
+	case v == "", strings.EqualFold(v, appv1.MergeReconcile), strings.EqualFold(v, appv1.ReplaceReconcile),
+		strings.EqualFold(v, appv1.MergeAndOwnReconcile):
+	default:
+		c.errorf("unsupported annotation %v %v, it must be %v, %v or %v", appv1.AnnotationResourceReconcileOption, v,
+			appv1.MergeReconcile, appv1.ReplaceReconcile, appv1.MergeAndOwnReconcile)
+	}
+
+	c.checkReconcileRate(annotations)
+
+	if v := annotations[appv1.AnnotationGitCloneDepth]; v != "" {
+		if depth, err := strconv.Atoi(v); err != nil || depth <= 0 {
+			c.errorf("annotation %v %v must be a positive integer", appv1.AnnotationGitCloneDepth, v)
+		}
+	}
+
+	if annotations[appv1.AnnotationGitTargetCommit] != "" && annotations[appv1.AnnotationGitTag] != "" {
+		c.warningf("annotation %v is ignored, the commit of %v is deployed", appv1.AnnotationGitTag,
+			appv1.AnnotationGitTargetCommit)
+	}
+
+	switch v := annotations[appv1.AnnotationBucketLayout]; {
+	case v == "", strings.EqualFold(v, utils.BucketLayoutFlat), strings.EqualFold(v, utils.BucketLayoutFolders):
+	default:
+		c.errorf("unsupported annotation %v %v, it must be %v or %v", appv1.AnnotationBucketLayout, v,
+			utils.BucketLayoutFlat, utils.BucketLayoutFolders)
+	}
+
+	if chn == nil {
+		return
+	}
+
+	tp := strings.ToLower(string(chn.Spec.Type))
+	if tp == chnv1.ChannelTypeGitHub {
+		tp = chnv1.ChannelTypeGit
+	}
+
+	for _, cta := range channelTypeAnnotations {
+		if cta.channelType == tp {
+			continue
+		}
+
+		for _, key := range cta.annotations {
+			if _, ok := annotations[key]; ok {
+				c.warningf("annotation %v is ignored by the %v channel %v", key, chn.Spec.Type, sub.Spec.Channel)
+			}
+		}
+	}
+
+	if tp == chnv1.ChannelTypeGit {
+		if _, err := utils.GetSubscriptionGitProvider(sub, chn.Spec.Pathname); err != nil {
+			c.errorf("annotation %v: %v", appv1.AnnotationGitProvider, err)
+		} else if _, err := utils.GetSubscriptionPullRequest(sub, chn.Spec.Pathname); err != nil {
+			c.errorf("annotation %v: %v", appv1.AnnotationGitPullRequest, err)
+		}
+	}
+}
+
+func (c *checker) checkReconcileRate(annotations map[string]string) {
+	switch v := strings.ToLower(annotations[appv1.AnnotationResourceReconcileLevel]); v {
+	case "", "off", "low", "medium", "high":
+	default:
+		c.warningf("unsupported annotation %v %v, it must be off, low, medium or high", appv1.AnnotationResourceReconcileLevel, v)
+	}
+}
+
+func objectName(kind, namespace, name string) string {
+	if namespace == "" {
+		return kind + "/" + name
+	}
+
+	return kind + "/" + namespace + "/" + name
+}
+
+func sortedChannels(channels map[string]*chnv1.Channel) []*chnv1.Channel {
+	keys := make([]string, 0, len(channels))
+
+	for key := range channels {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	sorted := make([]*chnv1.Channel, 0, len(keys))
+
+	for _, key := range keys {
+		sorted = append(sorted, channels[key])
+	}
+
+	return sorted
+}
+
+func toConfigMap(u *unstructured.Unstructured) *corev1.ConfigMap {
+	cm := &corev1.ConfigMap{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, cm); err != nil {
+		return nil
+	}
+
+	return cm
+}
@@ -0,0 +1,241 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+const validManifests = `apiVersion: apps.open-cluster-management.io/v1
+kind: Channel
+metadata:
+  name: git
+  namespace: demo
+spec:
+  type: Git
+  pathname: https://github.com/open-cluster-management-io/multicloud-operators-subscription.git
+  secretRef:
+    name: git-secret
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: git-secret
+  namespace: demo
+---
+apiVersion: apps.open-cluster-management.io/v1
+kind: PlacementRule
+metadata:
+  name: all
+  namespace: demo
+---
+apiVersion: apps.open-cluster-management.io/v1
+kind: Subscription
+metadata:
+  name: demo
+  namespace: demo
+  annotations:
+    apps.open-cluster-management.io/git-path: examples
+    apps.open-cluster-management.io/git-branch: main
+    apps.open-cluster-management.io/reconcile-option: mergeAndOwn
+spec:
+  channel: demo/git
+  placement:
+    placementRef:
+      kind: PlacementRule
+      name: all
+  packageOverrides:
+  - packageName: cm1
+    packageOverrides:
+    - path: data
+      value:
+        key: value
+  timewindow:
+    windowtype: active
+    location: America/Toronto
+    daysofweek: [Monday]
+    hours:
+    - start: "09:00AM"
+      end: "05:00PM"
+`
+
+const invalidSubscription = `apiVersion: apps.open-cluster-management.io/v1
+kind: Subscription
+metadata:
+  name: invalid
+  namespace: demo
+  annotations:
+    apps.open-cluster-management.io/git-clone-depth: "0"
+    apps.open-cluster-management.io/bucket-path: apps
+    apps.open-cluster-management.io/cluster-admin: "yes"
+spec:
+  channel: git
+  placement:
+    local: true
+    clusterSelector: {}
+  packageOverrides:
+  - packageName: nginx
+    releaseName: Nginx
+    packageOverrides:
+    - value: 1
+  timewindow:
+    windowtype: paused
+  packageFilter:
+    version: not-a-version
+`
+
+func lintManifests(g *gomega.WithT, manifests ...string) []Issue {
+	linter := NewLinter()
+
+	for _, manifest := range manifests {
+		g.Expect(linter.Add("test.yaml", []byte(manifest))).To(gomega.Succeed())
+	}
+
+	return linter.Lint()
+}
+
+func TestLintValid(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	docs := []string{}
+
+	for _, doc := range utils.ParseKubeResoures([]byte(validManifests)) {
+		docs = append(docs, string(doc))
+	}
+
+	issues := lintManifests(g, docs...)
+	g.Expect(issues).To(gomega.BeEmpty())
+}
+
+func TestLintInvalid(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	issues := lintManifests(g, invalidSubscription)
+	g.Expect(HasErrors(issues)).To(gomega.BeTrue())
+
+	messages := []string{}
+
+	for _, issue := range issues {
+		g.Expect(issue.Source).To(gomega.Equal("test.yaml"))
+		g.Expect(issue.Object).To(gomega.Equal("Subscription/demo/invalid"))
+
+		messages = append(messages, string(issue.Severity)+": "+issue.Message)
+	}
+
+	g.Expect(messages).To(gomega.ConsistOf(
+		"error: spec.channel git must be <namespace>/<name>",
+		"error: local placement and remote placement cannot be used together",
+		"error: spec.packageOverrides[0].packageOverrides[0]: can not convert path of override",
+		gomega.HavePrefix("error: spec.packageOverrides[0]: invalid release name Nginx of package nginx"),
+		gomega.HavePrefix("error: invalid spec.packageFilter.version not-a-version"),
+		"error: spec.timewindow: unsupported windowtype paused, it must be active or blocked",
+		`warning: annotation apps.open-cluster-management.io/cluster-admin is "yes", only true enables it`,
+		"error: annotation apps.open-cluster-management.io/git-clone-depth 0 must be a positive integer",
+	))
+}
+
+func TestLintChannelAnnotations(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	issues := lintManifests(g, `apiVersion: apps.open-cluster-management.io/v1
+kind: Channel
+metadata:
+  name: helm
+  namespace: demo
+spec:
+  type: HelmRepo
+  pathname: https://charts.example.com
+  configMapRef:
+    name: helm-config
+`, `apiVersion: apps.open-cluster-management.io/v1
+kind: Subscription
+metadata:
+  name: helm
+  namespace: demo
+  annotations:
+    apps.open-cluster-management.io/git-pull-request: "12"
+spec:
+  channel: demo/helm
+  placement:
+    placementRef:
+      kind: Placement
+      name: all
+`, `apiVersion: apps.open-cluster-management.io/v1
+kind: Channel
+metadata:
+  name: unknown
+  namespace: demo
+spec:
+  type: ftp
+  pathname: ftp://example.com
+  unknownField: true
+`)
+
+	g.Expect(HasErrors(issues)).To(gomega.BeTrue())
+	g.Expect(issues).To(gomega.HaveLen(4))
+	g.Expect(issues[0].Severity).To(gomega.Equal(SeverityError))
+	g.Expect(issues[0].Object).To(gomega.Equal("Channel/demo/unknown"))
+	g.Expect(issues[0].Message).To(gomega.ContainSubstring("unknownField"))
+	g.Expect(issues[1].String()).To(gomega.Equal("test.yaml: warning: Channel/demo/helm: ConfigMap demo/helm-config is not in the manifests"))
+	g.Expect(issues[2].Message).To(gomega.Equal("Placement demo/all is not in the manifests"))
+	g.Expect(issues[3].Message).To(gomega.Equal(
+		"annotation apps.open-cluster-management.io/git-pull-request is ignored by the HelmRepo channel demo/helm"))
+}
+
+func TestLintPromotion(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	issues := lintManifests(g, `apiVersion: apps.open-cluster-management.io/v1
+kind: Subscription
+metadata:
+  name: rings
+  namespace: demo
+  annotations:
+    apps.open-cluster-management.io/promotion-approved: prod=abc123,prod
+spec:
+  channel: demo/git
+  placement:
+    placementRef:
+      kind: PlacementRule
+      name: all
+  promotion:
+    maxFailurePercentage: 120
+    rings:
+    - decisionGroup: canary
+      manualApproval: true
+    - decisionGroup: prod
+      soakDuration: 1h
+      approvalTimeout: 24h
+    - {}
+`)
+
+	messages := []string{}
+	for _, issue := range issues {
+		messages = append(messages, string(issue.Severity)+": "+issue.Message)
+	}
+
+	g.Expect(messages).To(gomega.ContainElements(
+		"error: spec.promotion requires a Placement placementRef, the rings are its decision groups",
+		"error: spec.promotion.maxFailurePercentage 120 must be between 0 and 100",
+		"error: spec.promotion.rings[2].decisionGroup is required",
+		"warning: spec.promotion.rings[1].approvalTimeout is ignored without manualApproval",
+		"warning: spec.promotion.rings[0] deploys the subscription commit, its soak duration, timewindow and manual approval are ignored",
+		`error: annotation apps.open-cluster-management.io/promotion-approved entry "prod" must be <decision group>=<commit>`,
+	))
+}
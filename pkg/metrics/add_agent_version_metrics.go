@@ -0,0 +1,26 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var AgentVersionUnsupported = *prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "agent_version_unsupported",
+	Help: "Gauge set to 1 for the managed clusters whose agent version is not supported by the hub controllers",
+}, []string{LabelCluster, LabelAgentVersion, LabelHubVersion})
+
+func init() {
+	CollectorsForRegistration = append(CollectorsForRegistration, AgentVersionUnsupported)
+}
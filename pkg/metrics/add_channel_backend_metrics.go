@@ -0,0 +1,44 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+const (
+	LabelChannelType = "channel_type"
+)
+
+var ChannelBackendFetchTime = *prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name: "channel_backend_fetch_time",
+	Help: "Histogram of the fetch latency of the registered channel backends",
+}, []string{LabelChannelType, LabelResult})
+
+var ChannelBackendRenderTime = *prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name: "channel_backend_render_time",
+	Help: "Histogram of the render latency of the registered channel backends",
+}, []string{LabelChannelType, LabelResult})
+
+var ChannelBackendCacheTotal = *prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "channel_backend_cache_total",
+	Help: "Number of the fetches of the registered channel backends, hit when the revision fetched is already deployed",
+}, []string{LabelChannelType, LabelResult})
+
+func init() {
+	CollectorsForRegistration = append(CollectorsForRegistration,
+		ChannelBackendFetchTime,
+		ChannelBackendRenderTime,
+		ChannelBackendCacheTotal,
+	)
+}
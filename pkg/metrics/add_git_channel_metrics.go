@@ -0,0 +1,48 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var GitCloneTotal = *prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "git_clone_total",
+	Help: "Counter of the git clones of a channel",
+}, []string{LabelChannelNameSpace, LabelChannelName, LabelResult})
+
+var GitCloneDuration = *prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "git_clone_duration_seconds",
+	Help:    "Histogram of the git clone latency of a channel",
+	Buckets: []float64{0.5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+}, []string{LabelChannelNameSpace, LabelChannelName})
+
+var GitFetchErrorsTotal = *prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "git_fetch_errors_total",
+	Help: "Counter of the failed git clones of a channel",
+}, []string{LabelChannelNameSpace, LabelChannelName})
+
+var GitRepoSize = *prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "git_repo_size_bytes",
+	Help: "Size on disk of the last git clone of a channel",
+}, []string{LabelChannelNameSpace, LabelChannelName})
+
+var GitRateLimitedTotal = *prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "git_rate_limited_total",
+	Help: "Counter of the rate-limit responses of the git provider of a channel",
+}, []string{LabelChannelNameSpace, LabelChannelName, LabelProvider})
+
+func init() {
+	CollectorsForRegistration = append(CollectorsForRegistration,
+		GitCloneTotal, GitCloneDuration, GitFetchErrorsTotal, GitRepoSize, GitRateLimitedTotal)
+}
@@ -0,0 +1,45 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+const (
+	LabelDecision = "decision"
+	LabelResource = "resource"
+)
+
+var AgentMemoryUsageRatio = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "agent_cgroup_memory_usage_ratio",
+	Help: "Working set memory of the agent divided by the memory limit of its cgroup, 0 without a limit",
+})
+
+var AgentCPUUsageRatio = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "agent_cgroup_cpu_usage_ratio",
+	Help: "CPU usage of the agent divided by the CPU quota of its cgroup, 0 without a quota",
+})
+
+var ResourceThrottleDecisionsTotal = *prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "agent_resource_throttle_decisions_total",
+	Help: "Number of the apply concurrency reductions and of the deferred Git clones of the agent, by resource pressure",
+}, []string{LabelDecision, LabelResource})
+
+func init() {
+	CollectorsForRegistration = append(CollectorsForRegistration,
+		AgentMemoryUsageRatio,
+		AgentCPUUsageRatio,
+		ResourceThrottleDecisionsTotal,
+	)
+}
@@ -23,6 +23,15 @@ const (
 	// Vector label keys
 	LabelSubscriptionNameSpace = "subscription_namespace"
 	LabelSubscriptionName      = "subscription_name"
+	LabelHub                   = "hub"
+	LabelReason                = "reason"
+	LabelChannelNameSpace      = "channel_namespace"
+	LabelChannelName           = "channel_name"
+	LabelResult                = "result"
+	LabelProvider              = "provider"
+	LabelCluster               = "cluster"
+	LabelAgentVersion          = "agent_version"
+	LabelHubVersion            = "hub_version"
 )
 
 var CollectorsForRegistration []prometheus.Collector
@@ -0,0 +1,569 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package render renders the manifests a subscription deploys from its Git, Helm repo or ObjectBucket channel, with
+// the package filter, the package overrides and the namespaces applied the same way as the subscribers. It doesn't
+// need a cluster, the secrets and configmaps referenced by the channel are read with the client of the options
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	releasev1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/helmrelease/v1"
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	rUtils "open-cluster-management.io/multicloud-operators-subscription/pkg/helmrelease/utils"
+	helmops "open-cluster-management.io/multicloud-operators-subscription/pkg/subscriber/helmrepo"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+// clusterScopedKinds are the built-in cluster scoped kinds, used when the options have no REST mapper
+var clusterScopedKinds = map[string]bool{
+	"APIService":                     true,
+	"CSIDriver":                      true,
+	"CSINode":                        true,
+	"CertificateSigningRequest":      true,
+	"ClusterClaim":                   true,
+	"ClusterIssuer":                  true,
+	"ClusterManagementAddOn":         true,
+	"ClusterOperator":                true,
+	"ClusterResourceQuota":           true,
+	"ClusterRole":                    true,
+	"ClusterRoleBinding":             true,
+	"ClusterVersion":                 true,
+	"CustomResourceDefinition":       true,
+	"FlowSchema":                     true,
+	"IngressClass":                   true,
+	"ManagedCluster":                 true,
+	"ManagedClusterSet":              true,
+	"MutatingWebhookConfiguration":   true,
+	"Namespace":                      true,
+	"Node":                           true,
+	"OAuth":                          true,
+	"PersistentVolume":               true,
+	"PodSecurityPolicy":              true,
+	"PriorityClass":                  true,
+	"PriorityLevelConfiguration":     true,
+	"Project":                        true,
+	"RuntimeClass":                   true,
+	"SecurityContextConstraints":     true,
+	"StorageClass":                   true,
+	"ValidatingWebhookConfiguration": true,
+	"VolumeAttachment":               true,
+}
+
+// Options are the inputs of a render
+type Options struct {
+	// Subscription is the subscription to render
+	Subscription *appv1.Subscription
+	// Channel is the channel of the subscription
+	Channel *chnv1.Channel
+	// Client reads the secrets and configmaps referenced by the channel and by the HelmReleases of its charts
+	Client client.Client
+	// RESTMapper tells the namespaced kinds, the built-in cluster scoped kinds are known if it's nil
+	RESTMapper meta.RESTMapper
+	// WorkDir holds the git clone and the chart downloads, a temporary folder removed after the render is used if empty
+	WorkDir string
+	// ClusterAdmin keeps the namespaces of the manifests like the cluster-admin subscriptions, otherwise the namespaced
+	// manifests are deployed in the subscription namespace
+	ClusterAdmin bool
+}
+
+// Result is the outcome of a render
+type Result struct {
+	// Revision is the commit of a Git channel or the chart versions of a Helm repo channel, empty for the object buckets
+	Revision string
+	// Manifests are the rendered manifests in the order the subscriber applies them
+	Manifests []*unstructured.Unstructured
+}
+
+// Render fetches the channel content of the subscription and returns the manifests the subscription would apply
+func Render(opts Options) (*Result, error) {
+	if opts.Subscription == nil || opts.Channel == nil {
+		return nil, errors.New("the subscription and its channel are required")
+	}
+
+	if opts.Client == nil {
+		return nil, errors.New("a client is required to read the channel references")
+	}
+
+	if opts.WorkDir == "" {
+		workDir, err := ioutil.TempDir("", "appsub-render")
+		if err != nil {
+			return nil, err
+		}
+
+		defer os.RemoveAll(workDir)
+
+		opts.WorkDir = workDir
+	}
+
+	switch tp := strings.ToLower(string(opts.Channel.Spec.Type)); tp {
+	case chnv1.ChannelTypeGit, chnv1.ChannelTypeGitHub:
+		return renderGit(opts)
+	case chnv1.ChannelTypeHelmRepo:
+		return renderHelmRepo(opts)
+	case chnv1.ChannelTypeObjectBucket:
+		return renderObjectBucket(opts)
+	default:
+		return nil, fmt.Errorf("unsupported channel type %v", tp)
+	}
+}
+
+// ToYAML returns the manifests as a multi-document YAML
+func ToYAML(manifests []*unstructured.Unstructured) ([]byte, error) {
+	var buf bytes.Buffer
+
+	for i, manifest := range manifests {
+		data, err := yaml.Marshal(manifest.Object)
+		if err != nil {
+			return nil, err
+		}
+
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+
+		buf.Write(data)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// renderGit clones the repo and renders its manifests, kustomizations and charts under the subscription git path
+func renderGit(opts Options) (*Result, error) {
+	sub := opts.Subscription
+	annotations := sub.GetAnnotations()
+
+	user, pwd, sshKey, passphrase, clientKey, clientCert, err := utils.GetChannelSecret(opts.Client, opts.Channel)
+	if err != nil {
+		return nil, err
+	}
+
+	caCert := ""
+	cm := utils.GetChannelConfigMap(opts.Client, opts.Channel)
+
+	if cm != nil {
+		caCert = cm.Data[appv1.ChannelCertificateData]
+	}
+
+	cloneDepth := 1
+
+	if annotations[appv1.AnnotationGitCloneDepth] != "" {
+		cloneDepth, err = strconv.Atoi(annotations[appv1.AnnotationGitCloneDepth])
+		if err != nil {
+			return nil, fmt.Errorf("invalid %v annotation: %w", appv1.AnnotationGitCloneDepth, err)
+		}
+	}
+
+	repoRoot := filepath.Join(opts.WorkDir, "git", sub.Namespace, sub.Name)
+
+	cloneOptions := &utils.GitCloneOption{
+		CommitHash:  annotations[appv1.AnnotationGitTargetCommit],
+		RevisionTag: annotations[appv1.AnnotationGitTag],
+		CloneDepth:  cloneDepth,
+		Branch:      utils.GetSubscriptionBranch(sub),
+		DestDir:     repoRoot,
+		PrimaryConnectionOption: &utils.ChannelConnectionCfg{
+			RepoURL:            opts.Channel.Spec.Pathname,
+			User:               user,
+			Password:           pwd,
+			SSHKey:             sshKey,
+			Passphrase:         passphrase,
+			ClientKey:          clientKey,
+			ClientCert:         clientCert,
+			CaCerts:            caCert,
+			InsecureSkipVerify: opts.Channel.Spec.InsecureSkipVerify,
+		},
+		MirrorConnectionOptions: utils.GetGitMirrorConnectionConfigs(opts.Channel,
+			utils.FetchGitMirrorSecrets(opts.Client, opts.Channel), cm),
+	}
+
+	pr, err := utils.GetSubscriptionPullRequest(sub, opts.Channel.Spec.Pathname)
+	if err != nil {
+		return nil, err
+	}
+
+	if pr != nil {
+		cloneOptions.Branch = pr.Ref()
+	}
+
+	commitID, err := utils.CloneGitRepo(cloneOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to clone %v: %w", opts.Channel.Spec.Pathname, err)
+	}
+
+	resourcePath := repoRoot
+
+	if annotations[appv1.AnnotationGithubPath] != "" {
+		resourcePath = filepath.Join(repoRoot, annotations[appv1.AnnotationGithubPath])
+	} else if annotations[appv1.AnnotationGitPath] != "" {
+		resourcePath = filepath.Join(repoRoot, annotations[appv1.AnnotationGitPath])
+	}
+
+	manifests, err := renderGitFolder(opts, repoRoot, resourcePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{Revision: commitID, Manifests: manifests}, nil
+}
+
+// renderGitFolder renders the git folder like the git subscriber, the manifests first with the CRDs and namespaces,
+// then the RBAC and the other resources, then the kustomizations and the charts
+func renderGitFolder(opts Options, repoRoot, resourcePath string) ([]*unstructured.Unstructured, error) {
+	sub := opts.Subscription
+
+	chartDirs, kustomizeDirs, crdsAndNamespaceFiles, rbacFiles, otherFiles, err := utils.SortResources(repoRoot, resourcePath,
+		utils.ExcludePathsSkipFunc(repoRoot, utils.GitExcludePaths(opts.Channel), utils.ManagedSkipFunc(sub.Spec.PackageFilter)))
+	if err != nil {
+		return nil, err
+	}
+
+	items := []*unstructured.Unstructured{}
+	limits := utils.GetManifestLimits()
+
+	collect := func(_ []byte, rsc *unstructured.Unstructured) error {
+		items = append(items, rsc)
+
+		return nil
+	}
+
+	for _, rscFiles := range [][]string{crdsAndNamespaceFiles, rbacFiles, otherFiles} {
+		for _, rscFile := range rscFiles {
+			if err := utils.DecodeManifestFile(rscFile, limits, collect); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, kustomizeDir := range sortedKeys(kustomizeDirs) {
+		relativePath := kustomizeDir
+
+		if len(strings.SplitAfter(kustomizeDir, repoRoot+"/")) > 1 {
+			relativePath = strings.SplitAfter(kustomizeDir, repoRoot+"/")[1]
+		}
+
+		utils.VerifyAndOverrideKustomize(sub.Spec.PackageOverrides, relativePath, kustomizeDir)
+
+		out, err := utils.RunKustomizeBuild(kustomizeDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build kustomization %v: %w", relativePath, err)
+		}
+
+		if err := utils.DecodeManifests(bytes.NewReader(out), limits, collect); err != nil {
+			return nil, fmt.Errorf("failed to decode kustomization %v: %w", relativePath, err)
+		}
+	}
+
+	manifests := []*unstructured.Unstructured{}
+
+	for _, rsc := range items {
+		rsc, err := processManifest(opts, rsc, rsc.GetName(), "")
+		if err != nil {
+			return nil, err
+		}
+
+		if rsc != nil {
+			manifests = append(manifests, rsc)
+		}
+	}
+
+	if len(chartDirs) == 0 {
+		return manifests, nil
+	}
+
+	indexFile, err := utils.GenerateHelmIndexFile(sub, repoRoot, chartDirs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, chartDir := range sortedKeys(chartDirs) {
+		chrt, err := loader.LoadDir(chartDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load chart %v: %w", chartDir, err)
+		}
+
+		// the charts filtered out of the index are not deployed
+		if _, err := indexFile.Get(chrt.Name(), chrt.Metadata.Version); err != nil {
+			klog.V(1).Infof("skip chart %v, it doesn't match the subscription", chartDir)
+
+			continue
+		}
+
+		templates, err := utils.RenderSubscriptionChart(sub, chrt, sub.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render chart %v: %w", chrt.Name(), err)
+		}
+
+		manifests = append(manifests, setNamespaces(opts, templates, sub.Namespace)...)
+	}
+
+	return manifests, nil
+}
+
+// renderHelmRepo renders the charts of the helm repo selected by the subscription, from the HelmReleases the hub
+// propagates, with their values, release names and target namespaces
+func renderHelmRepo(opts Options) (*Result, error) {
+	helmRls, err := helmops.GetSubscriptionChartsOnHub(opts.Client, opts.Channel, nil, opts.Subscription)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(helmRls, func(i, j int) bool { return helmRls[i].Repo.ChartName < helmRls[j].Repo.ChartName })
+
+	versions := []string{}
+	manifests := []*unstructured.Unstructured{}
+
+	for _, helmRl := range helmRls {
+		if !opts.ClusterAdmin {
+			helmRl.Repo.TargetNamespace = ""
+		}
+
+		templates, err := renderHelmRelease(opts, helmRl)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render chart %v: %w", helmRl.Repo.ChartName, err)
+		}
+
+		versions = append(versions, helmRl.Repo.ChartName+":"+helmRl.Repo.Version)
+		manifests = append(manifests, templates...)
+	}
+
+	return &Result{Revision: strings.Join(versions, ","), Manifests: manifests}, nil
+}
+
+func renderHelmRelease(opts Options, helmRl *releasev1.HelmRelease) ([]*unstructured.Unstructured, error) {
+	configMap, err := rUtils.GetConfigMap(opts.Client, helmRl.Namespace, helmRl.Repo.ConfigMapRef)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := rUtils.GetSecret(opts.Client, helmRl.Namespace, helmRl.Repo.SecretRef)
+	if err != nil {
+		return nil, err
+	}
+
+	mirrorSecrets := rUtils.GetMirrorSecrets(opts.Client, helmRl.Namespace, helmRl)
+
+	chartDir, err := rUtils.DownloadChart(configMap, secret, mirrorSecrets, filepath.Join(opts.WorkDir, "charts"), helmRl)
+	if err != nil {
+		return nil, err
+	}
+
+	chrt, err := loader.LoadDir(chartDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// the HelmRelease spec holds the chart values
+	values := chartutil.Values{}
+
+	if helmRl.Spec != nil {
+		data, err := json.Marshal(helmRl.Spec)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := yaml.Unmarshal(data, &values); err != nil {
+			return nil, err
+		}
+	}
+
+	templates, err := utils.RenderChart(chrt, helmRl.GetReleaseName(), helmRl.GetTargetNamespace(), values)
+	if err != nil {
+		return nil, err
+	}
+
+	return setNamespaces(opts, templates, helmRl.GetTargetNamespace()), nil
+}
+
+// renderObjectBucket renders the objects of the bucket folder selected by the subscription bucket layout
+func renderObjectBucket(opts Options) (*Result, error) {
+	sub := opts.Subscription
+
+	awsHandler, bucket, err := utils.InitObjectStore(opts.Client, opts.Channel)
+	if err != nil {
+		return nil, err
+	}
+
+	var folderName *string
+
+	bucketPath := sub.GetAnnotations()[appv1.AnnotationBucketPath]
+	if bucketPath != "" {
+		folderName = &bucketPath
+	}
+
+	keys, err := awsHandler.List(bucket, folderName)
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := utils.SelectBucketObjects(sub, keys, bucketPath, func(key string) ([]byte, error) {
+		obj, err := awsHandler.Get(bucket, key)
+
+		return obj.Content, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	manifests := []*unstructured.Unstructured{}
+
+	for _, object := range objects {
+		obj, err := awsHandler.Get(bucket, object.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(obj.Content) == 0 {
+			continue
+		}
+
+		if utils.IsBucketArchive(object.Key) {
+			templates, chartName, err := utils.ExpandBucketArchive(sub, object.Key, obj.Content)
+			if err != nil {
+				return nil, err
+			}
+
+			// the resources of a chart archive are filtered on the chart name
+			pkgName := object.Package
+			if pkgName == "" {
+				pkgName = chartName
+			}
+
+			for _, template := range templates {
+				rsc, err := processManifest(opts, template, template.GetName(), pkgName)
+				if err != nil {
+					return nil, err
+				}
+
+				if rsc != nil {
+					manifests = append(manifests, rsc)
+				}
+			}
+
+			continue
+		}
+
+		template := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal(obj.Content, template); err != nil {
+			klog.V(1).Infof("skip object %v, it isn't a manifest: %v", object.Key, err)
+
+			continue
+		}
+
+		rsc, err := processManifest(opts, template, template.GetName(), object.Package)
+		if err != nil {
+			return nil, err
+		}
+
+		if rsc != nil {
+			manifests = append(manifests, rsc)
+		}
+	}
+
+	return &Result{Manifests: manifests}, nil
+}
+
+// processManifest checks the package filter of the subscription, applies the package overrides of the manifest name
+// and sets its namespace. A nil manifest is returned if it is filtered out. In the folders layout of the object
+// buckets the package filter is checked on the package folder name
+func processManifest(opts Options, rsc *unstructured.Unstructured, name, pkgName string) (*unstructured.Unstructured, error) {
+	sub := opts.Subscription
+
+	filterName := name
+	if pkgName != "" {
+		filterName = pkgName
+	}
+
+	if sub.Spec.PackageFilter != nil || pkgName != "" {
+		if errMsg := utils.CheckPackageFilter(sub, filterName, rsc.GetLabels(), rsc.GetAnnotations()); errMsg != "" {
+			klog.V(1).Info(errMsg)
+
+			return nil, nil
+		}
+	}
+
+	if sub.Spec.PackageOverrides != nil {
+		overridden, err := utils.OverrideResourceBySubscription(rsc, name, sub)
+		if err != nil {
+			return nil, fmt.Errorf("failed to override package %v: %w", name, err)
+		}
+
+		rsc = overridden
+	}
+
+	return setNamespaces(opts, []*unstructured.Unstructured{rsc}, sub.Namespace)[0], nil
+}
+
+// setNamespaces sets the namespace of the namespaced manifests, the cluster-admin subscriptions keep the namespaces
+// of the manifests unless the current-namespace-scoped annotation is set
+func setNamespaces(opts Options, manifests []*unstructured.Unstructured, namespace string) []*unstructured.Unstructured {
+	currentNamespaceScoped := strings.EqualFold(opts.Subscription.GetAnnotations()[appv1.AnnotationCurrentNamespaceScoped], "true")
+
+	for _, rsc := range manifests {
+		if !isNamespaced(opts.RESTMapper, rsc.GroupVersionKind()) {
+			continue
+		}
+
+		if !opts.ClusterAdmin || rsc.GetNamespace() == "" || currentNamespaceScoped {
+			rsc.SetNamespace(namespace)
+		}
+	}
+
+	return manifests
+}
+
+func isNamespaced(rm meta.RESTMapper, gvk schema.GroupVersionKind) bool {
+	if rm == nil {
+		return !clusterScopedKinds[gvk.Kind]
+	}
+
+	mapping, err := rm.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return !clusterScopedKinds[gvk.Kind]
+	}
+
+	return mapping.Scope.Name() == meta.RESTScopeNameNamespace
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,124 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func writeFiles(g *gomega.WithT, root string, files map[string]string) {
+	for name, content := range files {
+		g.Expect(os.MkdirAll(filepath.Dir(filepath.Join(root, name)), 0750)).To(gomega.Succeed())
+		g.Expect(os.WriteFile(filepath.Join(root, name), []byte(content), 0600)).To(gomega.Succeed())
+	}
+}
+
+func TestRenderGitFolder(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	root := t.TempDir()
+
+	writeFiles(g, root, map[string]string{
+		"app/cm.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+  namespace: other
+  labels:
+    app: demo
+data:
+  message: default
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: reader
+`,
+		"app/prehook/job.yaml":  "apiVersion: batch/v1\nkind: Job\nmetadata:\n  name: hook\n",
+		"app/nginx/Chart.yaml":  "apiVersion: v2\nname: nginx\nversion: 1.0.0\n",
+		"app/nginx/values.yaml": "message: default\n",
+		"app/nginx/templates/cm.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}-config
+data:
+  message: {{ .Values.message }}
+`,
+	})
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns"},
+		Spec: appv1.SubscriptionSpec{
+			PackageOverrides: []*appv1.Overrides{
+				{
+					PackageName: "cm1",
+					PackageOverrides: []appv1.PackageOverride{
+						{RawExtension: runtime.RawExtension{Raw: []byte(`{"path": "data", "value": {"message": "overridden"}}`)}},
+					},
+				},
+				{
+					PackageName:  "nginx",
+					PackageAlias: "web",
+					PackageOverrides: []appv1.PackageOverride{
+						{RawExtension: runtime.RawExtension{Raw: []byte(`{"path": "spec", "value": {"message": "chart"}}`)}},
+					},
+				},
+			},
+		},
+	}
+
+	manifests, err := renderGitFolder(Options{Subscription: sub}, root, filepath.Join(root, "app"))
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(manifests).To(gomega.HaveLen(3))
+
+	byName := map[string]*unstructured.Unstructured{}
+	for _, manifest := range manifests {
+		byName[manifest.GetName()] = manifest
+	}
+
+	g.Expect(byName).To(gomega.HaveKey("cm1"))
+	g.Expect(byName["cm1"].GetNamespace()).To(gomega.Equal("demo-ns"))
+	g.Expect(byName["cm1"].Object["data"]).To(gomega.Equal(map[string]interface{}{"message": "overridden"}))
+	g.Expect(byName).To(gomega.HaveKey("reader"))
+	g.Expect(byName["reader"].GetNamespace()).To(gomega.BeEmpty())
+	g.Expect(byName).To(gomega.HaveKey("web-config"))
+	g.Expect(byName["web-config"].GetNamespace()).To(gomega.Equal("demo-ns"))
+	g.Expect(byName["web-config"].Object["data"]).To(gomega.Equal(map[string]interface{}{"message": "chart"}))
+
+	// the cluster admins keep the namespaces of the manifests
+	manifests, err = renderGitFolder(Options{Subscription: sub, ClusterAdmin: true}, root, filepath.Join(root, "app"))
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(manifests[0].GetNamespace()).To(gomega.Equal("other"))
+
+	sub.Spec.PackageFilter = &appv1.PackageFilter{LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "demo"}}}
+
+	manifests, err = renderGitFolder(Options{Subscription: sub}, root, filepath.Join(root, "app"))
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(manifests).To(gomega.HaveLen(1))
+	g.Expect(manifests[0].GetName()).To(gomega.Equal("cm1"))
+
+	data, err := ToYAML(manifests)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(string(data)).To(gomega.ContainSubstring("name: cm1"))
+}
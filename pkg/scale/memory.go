@@ -0,0 +1,78 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scale
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// residentMemoryMetric is the resident memory of the process in the metrics of the controllers
+const residentMemoryMetric = "process_resident_memory_bytes"
+
+// ProcessMemorySampler samples the heap of the harness process, the controllers run in the process with envtest
+func ProcessMemorySampler() MemorySampler {
+	return func() (uint64, error) {
+		stats := &runtime.MemStats{}
+		runtime.ReadMemStats(stats)
+
+		return stats.HeapInuse, nil
+	}
+}
+
+// MetricsMemorySampler samples the resident memory of the controllers from their metrics endpoint
+func MetricsMemorySampler(metricsURL string) MemorySampler {
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+
+	return func() (uint64, error) {
+		resp, err := httpClient.Get(metricsURL) // #nosec G107 the metrics URL is given by the user
+		if err != nil {
+			return 0, err
+		}
+
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return 0, fmt.Errorf("failed to get the metrics %v, status: %v", metricsURL, resp.Status)
+		}
+
+		scanner := bufio.NewScanner(resp.Body)
+
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) != 2 || fields[0] != residentMemoryMetric {
+				continue
+			}
+
+			bytes, err := strconv.ParseFloat(fields[1], 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid %v value %v, err: %w", residentMemoryMetric, fields[1], err)
+			}
+
+			return uint64(bytes), nil
+		}
+
+		if err := scanner.Err(); err != nil {
+			return 0, err
+		}
+
+		return 0, fmt.Errorf("no %v metric in %v", residentMemoryMetric, metricsURL)
+	}
+}
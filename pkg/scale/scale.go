@@ -0,0 +1,577 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package scale creates synthetic subscriptions, channels and clusters on a hub and measures how fast the hub
+// propagates them to the clusters and how much memory it takes.
+package scale
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/klog/v2"
+	spokeClusterV1 "open-cluster-management.io/api/cluster/v1"
+	manifestWorkV1 "open-cluster-management.io/api/work/v1"
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	plrv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+const (
+	// RunLabel labels the synthetic objects of a scale run with the run ID, they are deleted by Cleanup
+	RunLabel = "apps.open-cluster-management.io/scale-run"
+
+	memorySampleInterval = time.Second
+)
+
+// MemorySampler returns the memory in bytes of the measured controllers
+type MemorySampler func() (uint64, error)
+
+// Options of a scale run
+type Options struct {
+	// RunID names and labels the synthetic objects of the run
+	RunID string
+	// Namespace holds the synthetic channels and subscriptions, it is created if missing
+	Namespace     string
+	Subscriptions int
+	Channels      int
+	// Clusters is the number of synthetic managed clusters, ignored with a ClusterSelector
+	Clusters int
+	// ClusterSelector selects the existing managed clusters of a fleet instead of the synthetic clusters
+	ClusterSelector string
+	// ChannelType and ChannelPathname of the synthetic channels, the Namespace channels don't fetch any repository
+	ChannelType     string
+	ChannelPathname string
+	// CreateInterval paces the creation of the subscriptions
+	CreateInterval time.Duration
+	// WaitApplied waits for the work agents of the clusters to apply the manifestWorks of the subscriptions
+	WaitApplied bool
+	Timeout     time.Duration
+	// MemorySampler samples the memory of the controllers during the run, the memory isn't reported if nil
+	MemorySampler MemorySampler
+}
+
+// Report of a scale run
+type Report struct {
+	RunID         string `json:"runID"`
+	Subscriptions int    `json:"subscriptions"`
+	Channels      int    `json:"channels"`
+	Clusters      int    `json:"clusters"`
+	Duration      string `json:"duration"`
+	// Propagated is the number of subscriptions propagated to all their clusters before the timeout
+	Propagated         int             `json:"propagated"`
+	PropagationLatency LatencySummary  `json:"propagationLatency"`
+	Applied            int             `json:"applied,omitempty"`
+	AppliedLatency     *LatencySummary `json:"appliedLatency,omitempty"`
+	Memory             *MemorySummary  `json:"memory,omitempty"`
+}
+
+// LatencySummary is the distribution of the latencies from the creation of the subscriptions
+type LatencySummary struct {
+	P50 string `json:"p50"`
+	P90 string `json:"p90"`
+	P99 string `json:"p99"`
+	Max string `json:"max"`
+}
+
+// MemorySummary is the memory of the controllers in bytes at the start, the peak and the end of the run
+type MemorySummary struct {
+	Start uint64 `json:"start"`
+	Peak  uint64 `json:"peak"`
+	End   uint64 `json:"end"`
+}
+
+// tracker records when the manifestWorks of the subscriptions are created and applied on each cluster
+type tracker struct {
+	lock     sync.Mutex
+	created  map[string]time.Time
+	clusters map[string]bool
+	// propagated and applied are the times by subscription and cluster
+	propagated map[string]map[string]time.Time
+	applied    map[string]map[string]time.Time
+}
+
+func newTracker(clusters []string) *tracker {
+	t := &tracker{
+		created:    map[string]time.Time{},
+		clusters:   map[string]bool{},
+		propagated: map[string]map[string]time.Time{},
+		applied:    map[string]map[string]time.Time{},
+	}
+
+	for _, cl := range clusters {
+		t.clusters[cl] = true
+	}
+
+	return t
+}
+
+// observe records the manifestWork, named <namespace>-<name> of its subscription in the cluster namespace
+func (t *tracker) observe(work *manifestWorkV1.ManifestWork, now time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if !t.clusters[work.Namespace] {
+		return
+	}
+
+	if _, ok := t.created[work.Name]; !ok {
+		return
+	}
+
+	record := func(times map[string]map[string]time.Time) {
+		if times[work.Name] == nil {
+			times[work.Name] = map[string]time.Time{}
+		}
+
+		if _, ok := times[work.Name][work.Namespace]; !ok {
+			times[work.Name][work.Namespace] = now
+		}
+	}
+
+	record(t.propagated)
+
+	if meta.IsStatusConditionTrue(work.Status.Conditions, manifestWorkV1.WorkApplied) {
+		record(t.applied)
+	}
+}
+
+func (t *tracker) create(workName string, now time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.created[workName] = now
+}
+
+// latencies returns the latencies of the subscriptions propagated or applied to all the clusters
+func (t *tracker) latencies(times map[string]map[string]time.Time) []time.Duration {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	latencies := []time.Duration{}
+
+	for workName, created := range t.created {
+		if len(times[workName]) < len(t.clusters) {
+			continue
+		}
+
+		last := created
+
+		for _, at := range times[workName] {
+			if at.After(last) {
+				last = at
+			}
+		}
+
+		latencies = append(latencies, last.Sub(created))
+	}
+
+	return latencies
+}
+
+func (t *tracker) done(waitApplied bool) bool {
+	times := t.propagated
+	if waitApplied {
+		times = t.applied
+	}
+
+	t.lock.Lock()
+	n := len(t.created)
+	t.lock.Unlock()
+
+	return len(t.latencies(times)) == n
+}
+
+// Run creates the synthetic objects and waits for the hub to propagate the subscriptions to all the clusters, or for
+// the timeout. The synthetic objects are kept, they are deleted by Cleanup
+func Run(ctx context.Context, c client.WithWatch, opts Options) (*Report, error) {
+	if opts.Subscriptions <= 0 || opts.Channels <= 0 {
+		return nil, fmt.Errorf("the subscriptions and the channels must be positive")
+	}
+
+	start := time.Now()
+
+	labels := map[string]string{RunLabel: opts.RunID}
+
+	if err := ensureNamespace(ctx, c, opts.Namespace, labels); err != nil {
+		return nil, err
+	}
+
+	clusters, selector, err := prepareClusters(ctx, c, opts, labels)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(clusters) == 0 {
+		return nil, fmt.Errorf("no managed cluster is selected by %v", opts.ClusterSelector)
+	}
+
+	for i := 0; i < opts.Channels; i++ {
+		chn := &chnv1.Channel{
+			ObjectMeta: metav1.ObjectMeta{Name: channelName(opts.RunID, i), Namespace: opts.Namespace, Labels: labels},
+			Spec: chnv1.ChannelSpec{
+				Type:     chnv1.ChannelType(opts.ChannelType),
+				Pathname: opts.ChannelPathname,
+			},
+		}
+
+		if err := createObject(ctx, c, chn); err != nil {
+			return nil, err
+		}
+	}
+
+	t := newTracker(clusters)
+
+	watchCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	if err := watchManifestWorks(watchCtx, c, t); err != nil {
+		return nil, err
+	}
+
+	memory := &memoryRecorder{sampler: opts.MemorySampler}
+	memory.sample()
+
+	go memory.run(watchCtx)
+
+	for i := 0; i < opts.Subscriptions; i++ {
+		sub := &appv1.Subscription{
+			ObjectMeta: metav1.ObjectMeta{Name: subscriptionName(opts.RunID, i), Namespace: opts.Namespace, Labels: labels},
+			Spec: appv1.SubscriptionSpec{
+				Channel: opts.Namespace + "/" + channelName(opts.RunID, i%opts.Channels),
+				Placement: &plrv1.Placement{
+					GenericPlacementFields: plrv1.GenericPlacementFields{ClusterSelector: selector},
+				},
+			},
+		}
+
+		t.create(sub.Namespace+"-"+sub.Name, time.Now())
+
+		if err := createObject(ctx, c, sub); err != nil {
+			return nil, err
+		}
+
+		if opts.CreateInterval > 0 {
+			time.Sleep(opts.CreateInterval)
+		}
+	}
+
+	klog.Infof("created %v subscriptions, %v channels for %v clusters, waiting for the propagation",
+		opts.Subscriptions, opts.Channels, len(clusters))
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+wait:
+	for !t.done(opts.WaitApplied) {
+		select {
+		case <-watchCtx.Done():
+			klog.Warningf("scale run %v timed out after %v", opts.RunID, opts.Timeout)
+
+			break wait
+		case <-ticker.C:
+		}
+	}
+
+	memory.sample()
+
+	propagated := t.latencies(t.propagated)
+
+	report := &Report{
+		RunID:              opts.RunID,
+		Subscriptions:      opts.Subscriptions,
+		Channels:           opts.Channels,
+		Clusters:           len(clusters),
+		Duration:           time.Since(start).Round(time.Millisecond).String(),
+		Propagated:         len(propagated),
+		PropagationLatency: summarize(propagated),
+		Memory:             memory.summary(),
+	}
+
+	if opts.WaitApplied {
+		applied := t.latencies(t.applied)
+		appliedSummary := summarize(applied)
+
+		report.Applied = len(applied)
+		report.AppliedLatency = &appliedSummary
+	}
+
+	return report, nil
+}
+
+// Cleanup deletes the synthetic objects of the run, all the runs if the run ID is empty
+func Cleanup(ctx context.Context, c client.Client, namespace, runID string) error {
+	listOpts := []client.ListOption{client.HasLabels{RunLabel}}
+	if runID != "" {
+		listOpts = []client.ListOption{client.MatchingLabels{RunLabel: runID}}
+	}
+
+	subs := &appv1.SubscriptionList{}
+	if err := c.List(ctx, subs, append(listOpts, client.InNamespace(namespace))...); err != nil {
+		return err
+	}
+
+	for i := range subs.Items {
+		if err := deleteObject(ctx, c, &subs.Items[i]); err != nil {
+			return err
+		}
+	}
+
+	chns := &chnv1.ChannelList{}
+	if err := c.List(ctx, chns, append(listOpts, client.InNamespace(namespace))...); err != nil {
+		return err
+	}
+
+	for i := range chns.Items {
+		if err := deleteObject(ctx, c, &chns.Items[i]); err != nil {
+			return err
+		}
+	}
+
+	clusters := &spokeClusterV1.ManagedClusterList{}
+	if err := c.List(ctx, clusters, listOpts...); err != nil {
+		return err
+	}
+
+	for i := range clusters.Items {
+		if err := deleteObject(ctx, c, &clusters.Items[i]); err != nil {
+			return err
+		}
+	}
+
+	namespaces := &corev1.NamespaceList{}
+	if err := c.List(ctx, namespaces, listOpts...); err != nil {
+		return err
+	}
+
+	for i := range namespaces.Items {
+		if err := deleteObject(ctx, c, &namespaces.Items[i]); err != nil {
+			return err
+		}
+	}
+
+	klog.Infof("deleted %v subscriptions, %v channels, %v clusters and %v namespaces", len(subs.Items),
+		len(chns.Items), len(clusters.Items), len(namespaces.Items))
+
+	return nil
+}
+
+// prepareClusters returns the names of the target clusters and the cluster selector of the subscriptions. The
+// synthetic clusters are created with their namespaces, they have no agent
+func prepareClusters(ctx context.Context, c client.Client, opts Options,
+	labels map[string]string) ([]string, *metav1.LabelSelector, error) {
+	if opts.ClusterSelector != "" {
+		selector, err := metav1.ParseToLabelSelector(opts.ClusterSelector)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid cluster selector %v, err: %w", opts.ClusterSelector, err)
+		}
+
+		clSelector, err := metav1.LabelSelectorAsSelector(selector)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		cllist := &spokeClusterV1.ManagedClusterList{}
+		if err := c.List(ctx, cllist, client.MatchingLabelsSelector{Selector: clSelector}); err != nil {
+			return nil, nil, err
+		}
+
+		clusters := []string{}
+		for _, cl := range cllist.Items {
+			clusters = append(clusters, cl.Name)
+		}
+
+		return clusters, selector, nil
+	}
+
+	clusters := []string{}
+
+	for i := 0; i < opts.Clusters; i++ {
+		name := fmt.Sprintf("%v-cluster-%d", opts.RunID, i)
+
+		if err := ensureNamespace(ctx, c, name, labels); err != nil {
+			return nil, nil, err
+		}
+
+		// the hub selects the clusters of the placement clusters by their name label
+		cl := &spokeClusterV1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{RunLabel: opts.RunID, "name": name}},
+			Spec:       spokeClusterV1.ManagedClusterSpec{HubAcceptsClient: true},
+		}
+
+		if err := createObject(ctx, c, cl); err != nil {
+			return nil, nil, err
+		}
+
+		clusters = append(clusters, name)
+	}
+
+	return clusters, &metav1.LabelSelector{MatchLabels: map[string]string{RunLabel: opts.RunID}}, nil
+}
+
+// watchManifestWorks records the manifestWorks of the subscriptions until the context is done, the watch is
+// restarted when the API server closes it
+func watchManifestWorks(ctx context.Context, c client.WithWatch, t *tracker) error {
+	w, err := c.Watch(ctx, &manifestWorkV1.ManifestWorkList{})
+	if err != nil {
+		return fmt.Errorf("failed to watch the manifestWorks, err: %w", err)
+	}
+
+	go func() {
+		for {
+			for event := range w.ResultChan() {
+				if work, ok := event.Object.(*manifestWorkV1.ManifestWork); ok && event.Type != watch.Deleted {
+					t.observe(work, time.Now())
+				}
+			}
+
+			if ctx.Err() != nil {
+				return
+			}
+
+			if w, err = c.Watch(ctx, &manifestWorkV1.ManifestWorkList{}); err != nil {
+				klog.Errorf("failed to watch the manifestWorks, err: %v", err)
+
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+type memoryRecorder struct {
+	sampler MemorySampler
+	lock    sync.Mutex
+	samples []uint64
+}
+
+func (m *memoryRecorder) sample() {
+	if m.sampler == nil {
+		return
+	}
+
+	bytes, err := m.sampler()
+	if err != nil {
+		klog.Warningf("failed to sample the memory, err: %v", err)
+
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.samples = append(m.samples, bytes)
+}
+
+func (m *memoryRecorder) run(ctx context.Context) {
+	ticker := time.NewTicker(memorySampleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.sample()
+		}
+	}
+}
+
+func (m *memoryRecorder) summary() *MemorySummary {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if len(m.samples) == 0 {
+		return nil
+	}
+
+	summary := &MemorySummary{Start: m.samples[0], End: m.samples[len(m.samples)-1]}
+
+	for _, s := range m.samples {
+		if s > summary.Peak {
+			summary.Peak = s
+		}
+	}
+
+	return summary
+}
+
+// summarize returns the percentiles of the latencies
+func summarize(latencies []time.Duration) LatencySummary {
+	if len(latencies) == 0 {
+		return LatencySummary{}
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	percentile := func(p int) string {
+		i := (len(latencies)*p+99)/100 - 1
+		if i < 0 {
+			i = 0
+		}
+
+		return latencies[i].Round(time.Millisecond).String()
+	}
+
+	return LatencySummary{
+		P50: percentile(50),
+		P90: percentile(90),
+		P99: percentile(99),
+		Max: latencies[len(latencies)-1].Round(time.Millisecond).String(),
+	}
+}
+
+func channelName(runID string, i int) string {
+	return fmt.Sprintf("%v-channel-%d", runID, i)
+}
+
+func subscriptionName(runID string, i int) string {
+	return fmt.Sprintf("%v-sub-%d", runID, i)
+}
+
+func ensureNamespace(ctx context.Context, c client.Client, name string, labels map[string]string) error {
+	err := c.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}})
+	if err != nil && !kerrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create namespace %v, err: %w", name, err)
+	}
+
+	return nil
+}
+
+func createObject(ctx context.Context, c client.Client, obj client.Object) error {
+	if err := c.Create(ctx, obj); err != nil {
+		return fmt.Errorf("failed to create %T %v/%v, err: %w", obj, obj.GetNamespace(), obj.GetName(), err)
+	}
+
+	return nil
+}
+
+func deleteObject(ctx context.Context, c client.Client, obj client.Object) error {
+	if err := c.Delete(ctx, obj); err != nil && !kerrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete %T %v/%v, err: %w", obj, obj.GetNamespace(), obj.GetName(), err)
+	}
+
+	return nil
+}
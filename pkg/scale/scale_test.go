@@ -0,0 +1,154 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scale
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	spokeClusterV1 "open-cluster-management.io/api/cluster/v1"
+	manifestWorkV1 "open-cluster-management.io/api/work/v1"
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	subapis "open-cluster-management.io/multicloud-operators-subscription/pkg/apis"
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+// fakeHub propagates the subscriptions to the manifestWorks of the synthetic clusters like the hub controller
+func fakeHub(ctx context.Context, c client.Client) {
+	propagated := map[string]bool{}
+
+	for ctx.Err() == nil {
+		subs := &appv1.SubscriptionList{}
+		clusters := &spokeClusterV1.ManagedClusterList{}
+
+		if c.List(ctx, subs) != nil || c.List(ctx, clusters) != nil {
+			return
+		}
+
+		for _, sub := range subs.Items {
+			for _, cl := range clusters.Items {
+				work := &manifestWorkV1.ManifestWork{
+					ObjectMeta: metav1.ObjectMeta{Name: sub.Namespace + "-" + sub.Name, Namespace: cl.Name},
+				}
+
+				if !propagated[cl.Name+"/"+work.Name] && c.Create(ctx, work) == nil {
+					propagated[cl.Name+"/"+work.Name] = true
+				}
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRun(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(gomega.Succeed())
+	g.Expect(subapis.AddToScheme(scheme)).To(gomega.Succeed())
+
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	go fakeHub(ctx, c)
+
+	var samples uint64
+
+	report, err := Run(ctx, c, Options{
+		RunID:           "test",
+		Namespace:       "appsub-scale",
+		Subscriptions:   5,
+		Channels:        2,
+		Clusters:        3,
+		ChannelType:     chnv1.ChannelTypeNamespace,
+		ChannelPathname: "appsub-scale",
+		Timeout:         30 * time.Second,
+		MemorySampler: func() (uint64, error) {
+			return atomic.AddUint64(&samples, 1) * 1024, nil
+		},
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(report.Propagated).To(gomega.Equal(5))
+	g.Expect(report.Clusters).To(gomega.Equal(3))
+	g.Expect(report.PropagationLatency.Max).NotTo(gomega.BeEmpty())
+	g.Expect(report.Memory).NotTo(gomega.BeNil())
+	g.Expect(report.Memory.Peak).To(gomega.Equal(report.Memory.End))
+
+	sub := &appv1.Subscription{}
+	g.Expect(c.Get(ctx, client.ObjectKey{Namespace: "appsub-scale", Name: "test-sub-3"}, sub)).To(gomega.Succeed())
+	g.Expect(sub.Spec.Channel).To(gomega.Equal("appsub-scale/test-channel-1"))
+	g.Expect(sub.Spec.Placement.ClusterSelector.MatchLabels).To(gomega.HaveKeyWithValue(RunLabel, "test"))
+
+	g.Expect(Cleanup(ctx, c, "appsub-scale", "test")).To(gomega.Succeed())
+
+	subs := &appv1.SubscriptionList{}
+	g.Expect(c.List(ctx, subs)).To(gomega.Succeed())
+	g.Expect(subs.Items).To(gomega.BeEmpty())
+
+	clusters := &spokeClusterV1.ManagedClusterList{}
+	g.Expect(c.List(ctx, clusters)).To(gomega.Succeed())
+	g.Expect(clusters.Items).To(gomega.BeEmpty())
+}
+
+func TestSummarize(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	latencies := []time.Duration{}
+	for i := 100; i > 0; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Second)
+	}
+
+	g.Expect(summarize(latencies)).To(gomega.Equal(LatencySummary{P50: "50s", P90: "1m30s", P99: "1m39s", Max: "1m40s"}))
+	g.Expect(summarize(nil)).To(gomega.Equal(LatencySummary{}))
+}
+
+func TestMetricsMemorySampler(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/metrics" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		fmt.Fprintln(w, "# TYPE process_resident_memory_bytes gauge")
+		fmt.Fprintln(w, "process_resident_memory_bytes 1.2345678e+08")
+	}))
+
+	defer ts.Close()
+
+	bytes, err := MetricsMemorySampler(ts.URL + "/metrics")()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(bytes).To(gomega.Equal(uint64(123456780)))
+
+	_, err = MetricsMemorySampler(ts.URL + "/missing")()
+	g.Expect(err).To(gomega.HaveOccurred())
+}
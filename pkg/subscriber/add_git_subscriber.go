@@ -15,10 +15,16 @@
 package subscriber
 
 import (
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
 	"open-cluster-management.io/multicloud-operators-subscription/pkg/subscriber/git"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
 )
 
 func init() {
 	// AddToManagerFuncs is a list of functions to create controllers and add them to a manager.
 	AddToManagerFuncs = append(AddToManagerFuncs, git.Add)
+
+	ChannelSubscriberFuncs[chnv1.ChannelTypeGit] = git.GetDefaultSubscriber
+	ChannelSubscriberFuncs[chnv1.ChannelTypeGitHub] = git.GetDefaultSubscriber
+	ChannelSubscriberFuncs[utils.ChannelTypeBundle] = git.GetDefaultSubscriber
 }
@@ -15,10 +15,13 @@
 package subscriber
 
 import (
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
 	"open-cluster-management.io/multicloud-operators-subscription/pkg/subscriber/helmrepo"
 )
 
 func init() {
 	// AddToManagerFuncs is a list of functions to create controllers and add them to a manager.
 	AddToManagerFuncs = append(AddToManagerFuncs, helmrepo.Add)
+
+	ChannelSubscriberFuncs[chnv1.ChannelTypeHelmRepo] = helmrepo.GetDefaultSubscriber
 }
@@ -0,0 +1,152 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package backend is the extension point of the channel types. A channel backend registered with Register is served
+// by the backend subscriber: the agent fetches the channel with its Fetcher, renders the fetched content with its
+// Renderer and deploys the resources like the built-in subscribers, with the caching and the metrics of the backend
+// subscriber.
+package backend
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+// FetchRequest is the channel of a subscription to fetch, then to render
+type FetchRequest struct {
+	Subscription     *appv1.Subscription
+	Channel          *chnv1.Channel
+	ChannelSecret    *corev1.Secret
+	ChannelConfigMap *corev1.ConfigMap
+
+	// Client is the client of the cluster the resources of the subscription are deployed to
+	Client client.Client
+
+	// Dir is the directory of the subscription the content of the channel is fetched to. It is kept between the
+	// fetches of the subscription, a Fetcher can reuse the content fetched before.
+	Dir string
+
+	// Revision is the revision deployed by the subscription, empty before the first successful deployment
+	Revision string
+}
+
+// Fetcher fetches the content of a channel type
+type Fetcher interface {
+	// Fetch fetches the content of the channel to the directory of the request and returns its revision. The
+	// resources of a revision already deployed are not rendered again, a Fetcher returning an empty revision has
+	// the content rendered and deployed at every fetch.
+	Fetch(ctx context.Context, req *FetchRequest) (revision string, err error)
+}
+
+// Renderer renders the content fetched from a channel type
+type Renderer interface {
+	// Render returns the resources of the content fetched to the directory of the request. The package filter and
+	// overrides of the subscription are applied to the resources by the backend subscriber.
+	Render(ctx context.Context, req *FetchRequest) ([]*unstructured.Unstructured, error)
+}
+
+// Backend is a registered channel backend
+type Backend struct {
+	ChannelType string
+	Fetcher     Fetcher
+	Renderer    Renderer
+}
+
+// the channel types of the built-in subscribers can't be registered
+var builtinChannelTypes = map[string]bool{
+	chnv1.ChannelTypeNamespace:    true,
+	chnv1.ChannelTypeHelmRepo:     true,
+	chnv1.ChannelTypeObjectBucket: true,
+	chnv1.ChannelTypeGitHub:       true,
+	chnv1.ChannelTypeGit:          true,
+	utils.ChannelTypeBundle:       true,
+}
+
+var (
+	backendsLock sync.RWMutex
+	backends     = map[string]*Backend{}
+)
+
+// Register registers the backend of a channel type. It is called before the manager is set up, typically from the
+// init function of the package of the backend. The manifests of the fetched directory are rendered without a
+// Renderer.
+func Register(channelType string, fetcher Fetcher, renderer Renderer) error {
+	chType := strings.ToLower(channelType)
+
+	if chType == "" || fetcher == nil {
+		return fmt.Errorf("channel backend %q needs a channel type and a fetcher", channelType)
+	}
+
+	if builtinChannelTypes[chType] {
+		return fmt.Errorf("channel type %v is served by a built-in subscriber", chType)
+	}
+
+	if renderer == nil {
+		renderer = ManifestRenderer{}
+	}
+
+	backendsLock.Lock()
+	defer backendsLock.Unlock()
+
+	if _, ok := backends[chType]; ok {
+		return fmt.Errorf("channel type %v is already registered", chType)
+	}
+
+	backends[chType] = &Backend{ChannelType: chType, Fetcher: fetcher, Renderer: renderer}
+
+	return nil
+}
+
+// MustRegister registers the backend of a channel type and panics when it can't
+func MustRegister(channelType string, fetcher Fetcher, renderer Renderer) {
+	if err := Register(channelType, fetcher, renderer); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup returns the backend registered for a channel type
+func Lookup(channelType string) (*Backend, bool) {
+	backendsLock.RLock()
+	defer backendsLock.RUnlock()
+
+	b, ok := backends[strings.ToLower(channelType)]
+
+	return b, ok
+}
+
+// ChannelTypes returns the sorted channel types of the registered backends
+func ChannelTypes() []string {
+	backendsLock.RLock()
+	defer backendsLock.RUnlock()
+
+	types := make([]string, 0, len(backends))
+	for chType := range backends {
+		types = append(types, chType)
+	}
+
+	sort.Strings(types)
+
+	return types
+}
@@ -0,0 +1,200 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	kubesynchronizer "open-cluster-management.io/multicloud-operators-subscription/pkg/synchronizer/kubernetes"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+type itemmap map[types.NamespacedName]*SubscriberItem
+
+type SyncSource interface {
+	GetLocalClient() client.Client
+	ProcessSubResources(*appv1.Subscription, []kubesynchronizer.ResourceUnit,
+		map[string]map[string]string, map[string]map[string]string, bool) error
+	PurgeAllSubscribedResources(*appv1.Subscription) error
+}
+
+// Subscriber - information to run the subscriptions of the registered channel backends.
+type Subscriber struct {
+	itemmap
+	lock         sync.Mutex
+	synchronizer SyncSource
+	cacheDir     string
+}
+
+var defaultSubscriber *Subscriber
+
+// Add creates the backend subscriber when channel backends are registered.
+func Add(mgr manager.Manager, hubconfig *rest.Config, syncid *types.NamespacedName, syncinterval int, hub, standalone bool) error {
+	if len(ChannelTypes()) == 0 {
+		return nil
+	}
+
+	klog.Infof("Setting up the subscriber of channel backends %v on %v", ChannelTypes(), syncid)
+
+	sync := kubesynchronizer.GetDefaultSynchronizer()
+	if sync == nil {
+		if err := kubesynchronizer.Add(mgr, hubconfig, syncid, syncinterval, hub, standalone); err != nil {
+			klog.Error("Failed to initialize synchronizer for channel backends with error:", err)
+
+			return err
+		}
+
+		sync = kubesynchronizer.GetDefaultSynchronizer()
+	}
+
+	defaultSubscriber = CreateBackendSubscriber(sync, filepath.Join(os.TempDir(), "channel-backends"))
+	if defaultSubscriber == nil {
+		return errors.New("failed to create the subscriber of channel backends")
+	}
+
+	return nil
+}
+
+// GetDefaultSubscriber returns the backend subscriber, nil when no channel backend is registered.
+func GetDefaultSubscriber() appv1.Subscriber {
+	if defaultSubscriber == nil {
+		return nil
+	}
+
+	return defaultSubscriber
+}
+
+// CreateBackendSubscriber creates the backend subscriber with a synchronizer to the local cluster. The channels are
+// fetched to the directories of the subscriptions under cacheDir.
+func CreateBackendSubscriber(kubesync SyncSource, cacheDir string) *Subscriber {
+	if kubesync == nil {
+		klog.Error("Can not create the backend subscriber without kubernetes synchronizer")
+
+		return nil
+	}
+
+	return &Subscriber{
+		itemmap:      make(itemmap),
+		synchronizer: kubesync,
+		cacheDir:     cacheDir,
+	}
+}
+
+// SubscribeItem subscribes a subscriber item with the backend of its channel type.
+func (bs *Subscriber) SubscribeItem(subitem *appv1.SubscriberItem) error {
+	chType := strings.ToLower(string(subitem.Channel.Spec.Type))
+
+	b, ok := Lookup(chType)
+	if !ok {
+		return fmt.Errorf("no channel backend is registered for channel type %v", chType)
+	}
+
+	bs.lock.Lock()
+	defer bs.lock.Unlock()
+
+	itemkey := types.NamespacedName{Name: subitem.Subscription.Name, Namespace: subitem.Subscription.Namespace}
+	klog.Info("subscribeItem ", itemkey, " with channel backend ", chType)
+
+	bsitem, ok := bs.itemmap[itemkey]
+
+	if !ok || bsitem.backend != b {
+		if ok {
+			// the channel type of the subscription changed, the content fetched before is dropped
+			bsitem.Stop()
+		}
+
+		bsitem = &SubscriberItem{
+			backend:      b,
+			synchronizer: bs.synchronizer,
+			dir:          filepath.Join(bs.cacheDir, chType, itemkey.Namespace, itemkey.Name),
+		}
+
+		if err := os.RemoveAll(bsitem.dir); err != nil {
+			return err
+		}
+	}
+
+	subitem.DeepCopyInto(&bsitem.SubscriberItem)
+
+	bs.itemmap[itemkey] = bsitem
+
+	previousReconcileRate := bsitem.reconcileRate
+	previousSyncTime := bsitem.syncTime
+
+	subAnnotations := bsitem.Subscription.GetAnnotations()
+
+	bsitem.clusterAdmin = strings.EqualFold(subAnnotations[appv1.AnnotationClusterAdmin], "true")
+	bsitem.reconcileRate = utils.GetReconcileRate(bsitem.Channel.GetAnnotations(), subAnnotations)
+	bsitem.syncTime = utils.GetSyncTime(bsitem.Subscription)
+
+	if strings.EqualFold(subAnnotations[appv1.AnnotationResourceReconcileLevel], "off") {
+		klog.Infof("Overriding channel's reconcile rate %s to turn it off", bsitem.reconcileRate)
+		bsitem.reconcileRate = "off"
+	}
+
+	restart := previousReconcileRate != "" && !strings.EqualFold(previousReconcileRate, bsitem.reconcileRate)
+
+	// If manual sync time is updated, we want to restart the reconcile cycle and deploy the revision again
+	if !strings.EqualFold(previousSyncTime, bsitem.syncTime) {
+		klog.Infof("Manual reconcile time has changed from %s to %s. restart to reconcile resources", previousSyncTime, bsitem.syncTime)
+
+		restart = true
+		bsitem.revision = ""
+	}
+
+	bsitem.Start(restart)
+
+	return nil
+}
+
+// UnsubscribeItem unsubscribes a subscriber item and deletes the content fetched for it.
+func (bs *Subscriber) UnsubscribeItem(key types.NamespacedName) error {
+	bs.lock.Lock()
+	defer bs.lock.Unlock()
+
+	bsitem, ok := bs.itemmap[key]
+	if !ok {
+		return nil
+	}
+
+	klog.Info("channel backend UnsubscribeItem ", key)
+
+	bsitem.Stop()
+	delete(bs.itemmap, key)
+
+	if err := os.RemoveAll(bsitem.dir); err != nil {
+		klog.Warningf("failed to delete the channel content of %v, err: %v", key.String(), err)
+	}
+
+	if err := bs.synchronizer.PurgeAllSubscribedResources(bsitem.Subscription); err != nil {
+		klog.Errorf("failed to unsubscribe  %v, err: %v", key.String(), err)
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,307 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/metrics"
+	kubesynchronizer "open-cluster-management.io/multicloud-operators-subscription/pkg/synchronizer/kubernetes"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+const (
+	resultSucceeded = "succeeded"
+	resultFailed    = "failed"
+	resultCacheHit  = "hit"
+	resultCacheMiss = "miss"
+)
+
+// SubscriberItem - defines the unit of a channel backend subscription.
+type SubscriberItem struct {
+	appv1.SubscriberItem
+
+	backend       *Backend
+	dir           string
+	revision      string
+	reconcileRate string
+	syncTime      string
+	stopch        chan struct{}
+	successful    bool
+	clusterAdmin  bool
+	synchronizer  SyncSource
+}
+
+// Start subscribes the subscriber item every reconcile interval, restart stops the running loop first.
+func (bsi *SubscriberItem) Start(restart bool) {
+	// do nothing if already started
+	if bsi.stopch != nil {
+		if !restart {
+			klog.Info("channel backend SubscriberItem already started: ", bsi.Subscription.Name)
+
+			return
+		}
+
+		klog.Info("Stopping channel backend SubscriberItem: ", bsi.Subscription.Name)
+		bsi.Stop()
+	}
+
+	bsi.stopch = make(chan struct{})
+
+	loopPeriod, retryInterval, retries := utils.GetReconcileInterval(bsi.reconcileRate, bsi.backend.ChannelType)
+	klog.Infof("reconcileRate: %v, loopPeriod: %v, retryInterval: %v, retries: %v", bsi.reconcileRate, loopPeriod, retryInterval, retries)
+
+	if strings.EqualFold(bsi.reconcileRate, "off") {
+		klog.Infof("auto-reconcile is OFF")
+
+		bsi.doSubscriptionWithRetries(retryInterval, retries)
+
+		return
+	}
+
+	go utils.UntilReconcileInterval(func() {
+		if tw := bsi.Subscription.Spec.TimeWindow; tw != nil {
+			if nextRun := utils.NextStartPoint(tw, time.Now()); nextRun > time.Duration(0) {
+				klog.Infof("Subscription is currently blocked by the time window. It %v/%v will be deployed after %v",
+					bsi.Subscription.GetNamespace(), bsi.Subscription.GetName(), nextRun)
+
+				return
+			}
+		}
+
+		// if the subscription pause lable is true, stop subscription here.
+		if utils.GetPauseLabel(bsi.Subscription) {
+			klog.Infof("Channel backend Subscription %v/%v is paused.", bsi.Subscription.GetNamespace(), bsi.Subscription.GetName())
+
+			return
+		}
+
+		bsi.doSubscriptionWithRetries(retryInterval, retries)
+	}, bsi.reconcileRate, bsi.backend.ChannelType, bsi.stopch)
+}
+
+// Stop the subscriber item.
+func (bsi *SubscriberItem) Stop() {
+	if bsi.stopch != nil {
+		close(bsi.stopch)
+		bsi.stopch = nil
+	}
+}
+
+func (bsi *SubscriberItem) doSubscriptionWithRetries(retryInterval time.Duration, retries int) {
+	bsi.doSubscription()
+
+	// If the initial subscription fails, retry.
+	for n := 0; n < retries && !bsi.successful; n++ {
+		time.Sleep(retryInterval)
+		klog.Infof("Re-try #%d: subcribing to the %v channel: %v", n+1, bsi.backend.ChannelType, bsi.Channel.Name)
+		bsi.doSubscription()
+	}
+}
+
+// fetch fetches the channel with the backend, then the secondary channel when the primary channel can't be fetched
+func (bsi *SubscriberItem) fetch(ctx context.Context) (*FetchRequest, string, error) {
+	if err := os.MkdirAll(bsi.dir, 0700); err != nil {
+		return nil, "", err
+	}
+
+	req := &FetchRequest{
+		Subscription:     bsi.Subscription,
+		Channel:          bsi.Channel,
+		ChannelSecret:    bsi.ChannelSecret,
+		ChannelConfigMap: bsi.ChannelConfigMap,
+		Client:           bsi.synchronizer.GetLocalClient(),
+		Dir:              bsi.dir,
+	}
+
+	if bsi.successful {
+		req.Revision = bsi.revision
+	}
+
+	revision, err := bsi.observeFetch(ctx, req)
+	if err == nil || bsi.SecondaryChannel == nil {
+		return req, revision, err
+	}
+
+	klog.Warningf("failed to fetch the primary channel %v, trying with the secondary channel, err: %v", bsi.Channel.Name, err)
+
+	req.Channel = bsi.SecondaryChannel
+	req.ChannelSecret = bsi.SecondaryChannelSecret
+	req.ChannelConfigMap = bsi.SecondaryChannelConfigMap
+
+	revision, err = bsi.observeFetch(ctx, req)
+
+	return req, revision, err
+}
+
+func (bsi *SubscriberItem) observeFetch(ctx context.Context, req *FetchRequest) (string, error) {
+	start := time.Now()
+	revision, err := bsi.backend.Fetcher.Fetch(ctx, req)
+
+	result := resultSucceeded
+	if err != nil {
+		result = resultFailed
+	}
+
+	metrics.ChannelBackendFetchTime.WithLabelValues(bsi.backend.ChannelType, result).Observe(time.Since(start).Seconds())
+
+	return revision, err
+}
+
+func (bsi *SubscriberItem) render(ctx context.Context, req *FetchRequest) ([]*unstructured.Unstructured, error) {
+	start := time.Now()
+	tpls, err := bsi.backend.Renderer.Render(ctx, req)
+
+	result := resultSucceeded
+	if err != nil {
+		result = resultFailed
+	}
+
+	metrics.ChannelBackendRenderTime.WithLabelValues(bsi.backend.ChannelType, result).Observe(time.Since(start).Seconds())
+
+	return tpls, err
+}
+
+func (bsi *SubscriberItem) doSubscription() {
+	ctx := context.TODO()
+	chType := bsi.backend.ChannelType
+
+	utils.UpdateLastUpdateTime(bsi.synchronizer.GetLocalClient(), bsi.Subscription)
+
+	req, revision, err := bsi.fetch(ctx)
+	if err != nil {
+		klog.Errorf("Failed to fetch the %v channel of subscription %v/%v, err: %v", chType, bsi.Subscription.Namespace,
+			bsi.Subscription.Name, err)
+
+		bsi.successful = false
+
+		return
+	}
+
+	utils.UpdateFetchStatus(bsi.synchronizer.GetLocalClient(), bsi.Subscription, chType, revision, bsi.reconcileRate)
+
+	// the revision already deployed isn't deployed again, except with the high reconcile rate that corrects the drifts
+	if revision != "" && revision == bsi.revision && bsi.successful && !strings.EqualFold(bsi.reconcileRate, "high") {
+		klog.Infof("Appsub %v/%v revision: %s hasn't changed. Skip reconcile.", bsi.Subscription.Namespace,
+			bsi.Subscription.Name, revision)
+		metrics.ChannelBackendCacheTotal.WithLabelValues(chType, resultCacheHit).Inc()
+
+		return
+	}
+
+	metrics.ChannelBackendCacheTotal.WithLabelValues(chType, resultCacheMiss).Inc()
+
+	tpls, err := bsi.render(ctx, req)
+	if err != nil {
+		klog.Errorf("Failed to render the %v channel of subscription %v/%v, err: %v", chType, bsi.Subscription.Namespace,
+			bsi.Subscription.Name, err)
+
+		bsi.successful = false
+
+		return
+	}
+
+	resources := make([]kubesynchronizer.ResourceUnit, 0, len(tpls))
+
+	// track if there's any error when doSubscribeManifest, if there's any, then we should retry this
+	var doErr error
+
+	for _, tpl := range tpls {
+		resource, err := bsi.doSubscribeManifest(tpl)
+		if err != nil {
+			klog.Errorf("channel backend %v failed to package resource, err: %v", chType, err)
+
+			doErr = err
+
+			continue
+		}
+
+		resources = append(resources, *resource)
+	}
+
+	allowedGroupResources, deniedGroupResources := utils.GetAllowDenyLists(*bsi.Subscription)
+
+	if err := bsi.synchronizer.ProcessSubResources(bsi.Subscription, resources, allowedGroupResources, deniedGroupResources, false); err != nil {
+		klog.Error(err)
+
+		bsi.successful = false
+
+		return
+	}
+
+	if doErr != nil {
+		bsi.successful = false
+
+		return
+	}
+
+	bsi.revision = revision
+	bsi.successful = true
+}
+
+func (bsi *SubscriberItem) doSubscribeManifest(template *unstructured.Unstructured) (*kubesynchronizer.ResourceUnit, error) {
+	tplName := template.GetName()
+	// Set app label
+	utils.SetPartOfLabel(bsi.Subscription, template)
+
+	if bsi.Subscription.Spec.PackageFilter != nil {
+		if errmsg := utils.CheckPackageFilter(bsi.Subscription, tplName, template.GetLabels(), template.GetAnnotations()); errmsg != "" {
+			klog.Info(errmsg)
+
+			return nil, errors.New(errmsg)
+		}
+	}
+
+	template, err := utils.OverrideResourceBySubscription(template, tplName, bsi.Subscription)
+	if err != nil {
+		errmsg := "Failed override package " + tplName + " with error: " + err.Error()
+
+		klog.Info(errmsg)
+
+		return nil, errors.New(errmsg)
+	}
+
+	validgvk := template.GetObjectKind().GroupVersionKind()
+
+	rscAnnotations := template.GetAnnotations()
+	if rscAnnotations == nil {
+		rscAnnotations = make(map[string]string)
+	}
+
+	if bsi.clusterAdmin {
+		rscAnnotations[appv1.AnnotationClusterAdmin] = "true"
+	}
+
+	if option := bsi.Subscription.GetAnnotations()[appv1.AnnotationResourceReconcileOption]; option != "" {
+		rscAnnotations[appv1.AnnotationResourceReconcileOption] = option
+	}
+
+	template.SetAnnotations(rscAnnotations)
+
+	// the resources are deployed to the subscription namespace, except the namespaced resources of a cluster admin
+	if !bsi.clusterAdmin || template.GetNamespace() == "" {
+		template.SetNamespace(bsi.Subscription.Namespace)
+	}
+
+	return &kubesynchronizer.ResourceUnit{Resource: template, Gvk: validgvk}, nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	promTestUtils "github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/metrics"
+	kubesynchronizer "open-cluster-management.io/multicloud-operators-subscription/pkg/synchronizer/kubernetes"
+)
+
+// artifactFetcher writes a ConfigMap manifest of the revision of the channel pathname
+type artifactFetcher struct {
+	revision string
+	fetches  int
+}
+
+func (f *artifactFetcher) Fetch(ctx context.Context, req *FetchRequest) (string, error) {
+	f.fetches++
+
+	manifest := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: " + req.Channel.Spec.Pathname + "\n" +
+		"data:\n  revision: " + f.revision + "\n---\napiVersion: v1\nkind: Secret\nmetadata:\n  name: app-secret\n"
+
+	if err := os.WriteFile(filepath.Join(req.Dir, "app.yaml"), []byte(manifest), 0600); err != nil {
+		return "", err
+	}
+
+	return f.revision, os.WriteFile(filepath.Join(req.Dir, "notes.txt"), []byte("not a manifest"), 0600)
+}
+
+type fakeSynchronizer struct {
+	client.Client
+	resources []kubesynchronizer.ResourceUnit
+	purged    bool
+}
+
+func (s *fakeSynchronizer) GetLocalClient() client.Client {
+	return s.Client
+}
+
+func (s *fakeSynchronizer) ProcessSubResources(sub *appv1.Subscription, resources []kubesynchronizer.ResourceUnit,
+	allowed, denied map[string]map[string]string, isAdmin bool) error {
+	s.resources = resources
+
+	return nil
+}
+
+func (s *fakeSynchronizer) PurgeAllSubscribedResources(sub *appv1.Subscription) error {
+	s.purged = true
+
+	return nil
+}
+
+func TestChannelBackend(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	fetcher := &artifactFetcher{revision: "v1"}
+
+	// the built-in and the registered channel types can't be registered again
+	g.Expect(Register(chnv1.ChannelTypeGit, fetcher, nil)).NotTo(Succeed())
+	g.Expect(Register("ArtifactStore", nil, nil)).NotTo(Succeed())
+	g.Expect(Register("ArtifactStore", fetcher, nil)).To(Succeed())
+	g.Expect(Register("artifactstore", fetcher, nil)).NotTo(Succeed())
+
+	b, ok := Lookup("ARTIFACTSTORE")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(b.Renderer).To(Equal(ManifestRenderer{}))
+	g.Expect(ChannelTypes()).To(Equal([]string{"artifactstore"}))
+
+	scheme := runtime.NewScheme()
+	g.Expect(appv1.SchemeBuilder.AddToScheme(scheme)).To(Succeed())
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns", Annotations: map[string]string{
+			appv1.AnnotationResourceReconcileLevel: "off",
+		}},
+		Spec: appv1.SubscriptionSpec{
+			Channel: "chn-ns/store",
+		},
+	}
+
+	sync := &fakeSynchronizer{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(sub).Build()}
+	bs := CreateBackendSubscriber(sync, t.TempDir())
+
+	subitem := &appv1.SubscriberItem{
+		Subscription: sub,
+		Channel: &chnv1.Channel{
+			ObjectMeta: metav1.ObjectMeta{Name: "store", Namespace: "chn-ns"},
+			Spec:       chnv1.ChannelSpec{Type: "ArtifactStore", Pathname: "app-config"},
+		},
+	}
+
+	metrics.ChannelBackendCacheTotal.Reset()
+
+	// the manifests fetched are rendered and deployed to the subscription namespace
+	g.Expect(bs.SubscribeItem(subitem)).To(Succeed())
+	g.Expect(fetcher.fetches).To(Equal(1))
+	g.Expect(sync.resources).To(HaveLen(2))
+	g.Expect(sync.resources[0].Resource.GetName()).To(Equal("app-config"))
+	g.Expect(sync.resources[0].Resource.GetNamespace()).To(Equal("demo-ns"))
+	g.Expect(sync.resources[0].Resource.Object["data"]).To(Equal(map[string]interface{}{"revision": "v1"}))
+
+	key := types.NamespacedName{Name: "demo", Namespace: "demo-ns"}
+	item := bs.itemmap[key]
+	g.Expect(item.successful).To(BeTrue())
+	g.Expect(item.revision).To(Equal("v1"))
+
+	// the revision already deployed isn't rendered again
+	sync.resources = nil
+
+	item.doSubscription()
+	g.Expect(fetcher.fetches).To(Equal(2))
+	g.Expect(sync.resources).To(BeNil())
+	g.Expect(promTestUtils.ToFloat64(metrics.ChannelBackendCacheTotal.WithLabelValues("artifactstore", "hit"))).To(Equal(1.0))
+	g.Expect(promTestUtils.ToFloat64(metrics.ChannelBackendCacheTotal.WithLabelValues("artifactstore", "miss"))).To(Equal(1.0))
+
+	fetcher.revision = "v2"
+
+	item.doSubscription()
+	g.Expect(sync.resources).To(HaveLen(2))
+	g.Expect(sync.resources[0].Resource.Object["data"]).To(Equal(map[string]interface{}{"revision": "v2"}))
+	g.Expect(item.revision).To(Equal("v2"))
+
+	// the unsubscribed item purges its resources and the content fetched
+	g.Expect(bs.UnsubscribeItem(key)).To(Succeed())
+	g.Expect(sync.purged).To(BeTrue())
+	g.Expect(bs.itemmap).NotTo(HaveKey(key))
+
+	_, err := os.Stat(item.dir)
+	g.Expect(os.IsNotExist(err)).To(BeTrue())
+}
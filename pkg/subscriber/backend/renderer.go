@@ -0,0 +1,76 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+// ManifestRenderer is the Renderer of the backends registered without one. It returns the Kubernetes resources of
+// the YAML files of the fetched directory, in the lexical order of their paths. The hidden files and directories
+// are skipped.
+type ManifestRenderer struct{}
+
+// Render returns the resources of the YAML files of the fetched directory
+func (ManifestRenderer) Render(ctx context.Context, req *FetchRequest) ([]*unstructured.Unstructured, error) {
+	resources := []*unstructured.Unstructured{}
+
+	err := filepath.WalkDir(req.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path != req.Dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		ext := strings.ToLower(filepath.Ext(path))
+		if d.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			return nil
+		}
+
+		file, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return err
+		}
+
+		for _, item := range utils.ParseKubeResoures(file) {
+			obj := &unstructured.Unstructured{}
+			if err := yaml.Unmarshal(item, &obj.Object); err != nil {
+				return fmt.Errorf("failed to parse %v, err: %w", path, err)
+			}
+
+			resources = append(resources, obj)
+		}
+
+		return nil
+	})
+
+	return resources, err
+}
@@ -0,0 +1,273 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package flux is the channel backend of the Flux sources. The subscriptions consume the artifacts of the Flux
+// GitRepository and OCIRepository objects of the managed cluster, fetched by the Flux source-controller.
+package flux
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/subscriber/backend"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+const (
+	// ChannelTypeFluxSource is the channel type of the Flux sources. The channel pathname is the kind, the namespace
+	// and the name of the source, like GitRepository/flux-system/podinfo.
+	ChannelTypeFluxSource = "fluxsource"
+
+	// SourceGroup is the API group of the Flux sources
+	SourceGroup = "source.toolkit.fluxcd.io"
+
+	// the size limit of the artifacts
+	maxArtifactSize = 512 << 20
+)
+
+// the served versions of the source kinds, by order of preference
+var sourceVersions = map[string][]string{
+	"gitrepository": {"v1", "v1beta2"},
+	"ocirepository": {"v1", "v1beta2"},
+}
+
+var sourceKinds = map[string]string{
+	"gitrepository": "GitRepository",
+	"ocirepository": "OCIRepository",
+}
+
+// Fetcher fetches the artifact of a Flux source from the source-controller
+type Fetcher struct {
+	// HTTPClient downloads the artifacts, http.DefaultClient when not set
+	HTTPClient *http.Client
+}
+
+// Renderer renders the manifests of the directory of the artifact selected by the git-path annotation
+type Renderer struct{}
+
+func init() {
+	backend.MustRegister(ChannelTypeFluxSource, Fetcher{}, Renderer{})
+}
+
+// artifact is the artifact of a ready Flux source
+type artifact struct {
+	url      string
+	revision string
+	digest   string
+}
+
+// Fetch downloads and extracts the artifact of the Flux source of the channel and returns its digest as the
+// revision. The artifact isn't downloaded again while its digest doesn't change.
+func (f Fetcher) Fetch(ctx context.Context, req *backend.FetchRequest) (string, error) {
+	if req.Client == nil {
+		return "", errors.New("no client to get the flux source")
+	}
+
+	source, err := getSource(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	a, err := readyArtifact(source)
+	if err != nil {
+		return "", err
+	}
+
+	revision := a.digest
+	if revision == "" {
+		revision = a.revision
+	}
+
+	if req.Revision != "" && req.Revision == revision {
+		if entries, err := os.ReadDir(req.Dir); err == nil && len(entries) > 0 {
+			klog.Infof("the artifact of flux source %v is unchanged, revision: %v", req.Channel.Spec.Pathname, a.revision)
+
+			return revision, nil
+		}
+	}
+
+	data, err := f.download(ctx, a)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.RemoveAll(req.Dir); err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(req.Dir, 0700); err != nil {
+		return "", err
+	}
+
+	if err := utils.ExtractTarGz(data, req.Dir); err != nil {
+		return "", fmt.Errorf("failed to extract the artifact of flux source %v, err: %w", req.Channel.Spec.Pathname, err)
+	}
+
+	klog.Infof("the artifact of flux source %v is fetched, revision: %v", req.Channel.Spec.Pathname, a.revision)
+
+	return revision, nil
+}
+
+// Render renders the manifests of the artifact directory selected by the git-path annotation of the subscription
+func (Renderer) Render(ctx context.Context, req *backend.FetchRequest) ([]*unstructured.Unstructured, error) {
+	subPath := strings.Trim(req.Subscription.GetAnnotations()[appv1.AnnotationGitPath], "/")
+	if subPath == "" {
+		return backend.ManifestRenderer{}.Render(ctx, req)
+	}
+
+	dir := filepath.Join(req.Dir, filepath.Clean("/"+subPath))
+
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("path %v isn't found in the artifact of flux source %v", subPath, req.Channel.Spec.Pathname)
+	}
+
+	pathReq := *req
+	pathReq.Dir = dir
+
+	return backend.ManifestRenderer{}.Render(ctx, &pathReq)
+}
+
+// parseSourceRef parses the channel pathname, kind/namespace/name
+func parseSourceRef(pathname string) (string, types.NamespacedName, error) {
+	parts := strings.Split(strings.Trim(strings.TrimSpace(pathname), "/"), "/")
+
+	if len(parts) != 3 || parts[1] == "" || parts[2] == "" {
+		return "", types.NamespacedName{}, fmt.Errorf("invalid flux source %v, it must be <kind>/<namespace>/<name>", pathname)
+	}
+
+	kind := strings.ToLower(strings.TrimSuffix(parts[0], "."+SourceGroup))
+	if _, ok := sourceKinds[kind]; !ok {
+		return "", types.NamespacedName{}, fmt.Errorf("unsupported flux source kind %v, it must be GitRepository or OCIRepository", parts[0])
+	}
+
+	return kind, types.NamespacedName{Namespace: parts[1], Name: parts[2]}, nil
+}
+
+// getSource gets the flux source with the first served version of its kind
+func getSource(ctx context.Context, req *backend.FetchRequest) (*unstructured.Unstructured, error) {
+	kind, key, err := parseSourceRef(req.Channel.Spec.Pathname)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, version := range sourceVersions[kind] {
+		source := &unstructured.Unstructured{}
+		source.SetGroupVersionKind(schema.GroupVersionKind{Group: SourceGroup, Version: version, Kind: sourceKinds[kind]})
+
+		err = req.Client.Get(ctx, key, source)
+		if err == nil {
+			return source, nil
+		}
+
+		if !meta.IsNoMatchError(err) {
+			return nil, fmt.Errorf("failed to get flux source %v, err: %w", req.Channel.Spec.Pathname, err)
+		}
+	}
+
+	return nil, fmt.Errorf("flux source %v isn't served by the cluster, err: %w", req.Channel.Spec.Pathname, err)
+}
+
+// readyArtifact returns the artifact of the source once the source is ready
+func readyArtifact(source *unstructured.Unstructured) (*artifact, error) {
+	ref := source.GetKind() + "/" + source.GetNamespace() + "/" + source.GetName()
+
+	conditions, _, _ := unstructured.NestedSlice(source.Object, "status", "conditions")
+
+	ready := false
+
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if ok && cond["type"] == "Ready" {
+			if cond["status"] != "True" {
+				return nil, fmt.Errorf("flux source %v isn't ready: %v", ref, cond["message"])
+			}
+
+			ready = true
+		}
+	}
+
+	a := &artifact{}
+	a.url, _, _ = unstructured.NestedString(source.Object, "status", "artifact", "url")
+	a.revision, _, _ = unstructured.NestedString(source.Object, "status", "artifact", "revision")
+	a.digest, _, _ = unstructured.NestedString(source.Object, "status", "artifact", "digest")
+
+	// the sources of the older flux versions have the sha256 checksum of the artifact instead of its digest
+	if checksum, _, _ := unstructured.NestedString(source.Object, "status", "artifact", "checksum"); a.digest == "" && checksum != "" {
+		a.digest = "sha256:" + checksum
+	}
+
+	if !ready || a.url == "" {
+		return nil, fmt.Errorf("flux source %v has no ready artifact", ref)
+	}
+
+	return a, nil
+}
+
+// download downloads the artifact and verifies its sha256 digest
+func (f Fetcher) download(ctx context.Context, a *artifact) ([]byte, error) {
+	httpClient := f.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 5 * time.Minute}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download artifact %v, status: %v", a.url, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxArtifactSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > maxArtifactSize {
+		return nil, fmt.Errorf("artifact %v exceeds the size limit", a.url)
+	}
+
+	if algo, digest, ok := strings.Cut(a.digest, ":"); ok && algo == "sha256" {
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != digest {
+			return nil, fmt.Errorf("the digest of artifact %v doesn't match %v", a.url, a.digest)
+		}
+	}
+
+	return data, nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flux
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/subscriber/backend"
+)
+
+func tarGz(g *WithT, files map[string]string) []byte {
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+
+	for name, content := range files {
+		g.Expect(tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(content)), Typeflag: tar.TypeReg})).To(Succeed())
+
+		_, err := tw.Write([]byte(content))
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+
+	g.Expect(tw.Close()).To(Succeed())
+	g.Expect(gz.Close()).To(Succeed())
+
+	return buf.Bytes()
+}
+
+func TestFluxSourceFetcher(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ctx := context.TODO()
+
+	_, ok := backend.Lookup("FluxSource")
+	g.Expect(ok).To(BeTrue())
+
+	artifactData := tarGz(g, map[string]string{
+		"deploy/app.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\n",
+		"README.md":       "podinfo",
+	})
+	sum := sha256.Sum256(artifactData)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+
+	downloads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads++
+
+		_, _ = w.Write(artifactData)
+	}))
+	defer server.Close()
+
+	source := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "source.toolkit.fluxcd.io/v1",
+		"kind":       "GitRepository",
+		"metadata":   map[string]interface{}{"name": "podinfo", "namespace": "flux-system"},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{map[string]interface{}{"type": "Ready", "status": "False", "message": "auth failed"}},
+		},
+	}}
+
+	clt := fake.NewClientBuilder().WithScheme(runtime.NewScheme()).WithObjects(source).Build()
+
+	req := &backend.FetchRequest{
+		Subscription: &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns",
+			Annotations: map[string]string{appv1.AnnotationGitPath: "deploy"}}},
+		Channel: &chnv1.Channel{Spec: chnv1.ChannelSpec{Type: "FluxSource", Pathname: "GitRepository/flux-system/podinfo"}},
+		Client:  clt,
+		Dir:     t.TempDir(),
+	}
+
+	// the source must be ready
+	_, err := Fetcher{}.Fetch(ctx, req)
+	g.Expect(err).To(MatchError(ContainSubstring("isn't ready: auth failed")))
+
+	g.Expect(unstructured.SetNestedSlice(source.Object, []interface{}{
+		map[string]interface{}{"type": "Ready", "status": "True"}}, "status", "conditions")).To(Succeed())
+	g.Expect(unstructured.SetNestedStringMap(source.Object, map[string]string{
+		"url":      server.URL + "/gitrepository/flux-system/podinfo/abc.tar.gz",
+		"revision": "main@sha1:abc",
+		"digest":   digest,
+	}, "status", "artifact")).To(Succeed())
+	g.Expect(clt.Update(ctx, source)).To(Succeed())
+
+	// the artifact is extracted, the manifests of the git path are rendered
+	revision, err := Fetcher{}.Fetch(ctx, req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(revision).To(Equal(digest))
+	g.Expect(filepath.Join(req.Dir, "deploy", "app.yaml")).To(BeARegularFile())
+
+	tpls, err := Renderer{}.Render(ctx, req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tpls).To(HaveLen(1))
+	g.Expect(tpls[0].GetName()).To(Equal("app"))
+
+	// the artifact deployed isn't downloaded again
+	req.Revision = revision
+
+	_, err = Fetcher{}.Fetch(ctx, req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(downloads).To(Equal(1))
+
+	// the artifact must match its digest
+	req.Revision = ""
+
+	g.Expect(unstructured.SetNestedField(source.Object, "sha256:0000", "status", "artifact", "digest")).To(Succeed())
+	g.Expect(clt.Update(ctx, source)).To(Succeed())
+
+	_, err = Fetcher{}.Fetch(ctx, req)
+	g.Expect(err).To(MatchError(ContainSubstring("doesn't match")))
+
+	// the channel pathname is kind/namespace/name of a supported source kind
+	for _, pathname := range []string{"GitRepository/podinfo", "HelmChart/flux-system/podinfo"} {
+		_, _, err := parseSourceRef(pathname)
+		g.Expect(err).To(HaveOccurred())
+	}
+
+	kind, key, err := parseSourceRef("ocirepository.source.toolkit.fluxcd.io/flux-system/manifests")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(kind).To(Equal("ocirepository"))
+	g.Expect(key.String()).To(Equal("flux-system/manifests"))
+}
@@ -44,6 +44,7 @@ type SyncSource interface {
 	ProcessSubResources(*appv1alpha1.Subscription, []kubesynchronizer.ResourceUnit,
 		map[string]map[string]string, map[string]map[string]string, bool) error
 	PurgeAllSubscribedResources(*appv1alpha1.Subscription) error
+	RecordEvent(runtime.Object, string, string, error)
 }
 
 // Subscriber - information to run namespace subscription
@@ -150,7 +151,7 @@ func (ghs *Subscriber) SubscribeItem(subitem *appv1alpha1.SubscriberItem) error
 
 	ghssubitem.desiredCommit = subAnnotations[appv1alpha1.AnnotationGitTargetCommit]
 	ghssubitem.desiredTag = subAnnotations[appv1alpha1.AnnotationGitTag]
-	ghssubitem.syncTime = subAnnotations[appv1alpha1.AnnotationManualReconcileTime]
+	ghssubitem.syncTime = utils.GetSyncTime(ghssubitem.Subscription)
 	ghssubitem.userID = strings.Trim(subAnnotations[appv1alpha1.AnnotationUserIdentity], "")
 	ghssubitem.userGroup = strings.Trim(subAnnotations[appv1alpha1.AnnotationUserGroup], "")
 
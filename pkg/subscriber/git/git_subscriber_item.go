@@ -15,9 +15,10 @@
 package git
 
 import (
+	"bytes"
 	"context"
 	"errors"
-	"io/ioutil"
+	"fmt"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -29,7 +30,6 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 
 	corev1 "k8s.io/api/core/v1"
@@ -51,6 +51,8 @@ const (
 )
 
 var (
+	errSubscriberItemStopped = errors.New("the subscriber item is stopped")
+
 	helmGvk = schema.GroupVersionKind{
 		Group:   appv1.SchemeGroupVersion.Group,
 		Version: appv1.SchemeGroupVersion.Version,
@@ -84,6 +86,9 @@ type SubscriberItem struct {
 	currentNamespaceScoped bool
 	userID                 string
 	userGroup              string
+	checkpointChecked      bool
+	mirrorSecrets          map[string]*corev1.Secret
+	clonedRemote           string
 }
 
 type kubeResource struct {
@@ -109,7 +114,7 @@ func (ghsi *SubscriberItem) Start(restart bool) {
 
 	ghsi.stopch = make(chan struct{})
 
-	loopPeriod, retryInterval, retries := utils.GetReconcileInterval(ghsi.reconcileRate, chnv1.ChannelTypeGit)
+	_, retryInterval, retries := utils.GetReconcileInterval(ghsi.reconcileRate, chnv1.ChannelTypeGit)
 
 	if strings.EqualFold(ghsi.reconcileRate, "off") {
 		klog.Infof("auto-reconcile is OFF")
@@ -119,7 +124,7 @@ func (ghsi *SubscriberItem) Start(restart bool) {
 		return
 	}
 
-	go wait.Until(func() {
+	go utils.UntilReconcileInterval(func() {
 		tw := ghsi.SubscriberItem.Subscription.Spec.TimeWindow
 		if tw != nil {
 			nextRun := utils.NextStartPoint(tw, time.Now())
@@ -140,17 +145,27 @@ func (ghsi *SubscriberItem) Start(restart bool) {
 		}
 
 		ghsi.doSubscriptionWithRetries(retryInterval, retries)
-	}, loopPeriod, ghsi.stopch)
+	}, ghsi.reconcileRate, chnv1.ChannelTypeGit, ghsi.stopch)
 }
 
 // Stop unsubscribes a subscriber item with namespace channel
 func (ghsi *SubscriberItem) Stop() {
 	klog.Info("Stopping SubscriberItem ", ghsi.Subscription.Name)
 	close(ghsi.stopch)
+
+	cacheKey := ghsi.Subscription.Namespace + "/" + ghsi.Subscription.Name
+	utils.ForgetCacheSize(utils.CacheGitClone, cacheKey)
+	utils.ForgetCacheSize(utils.CacheGitRender, cacheKey)
+	utils.ForgetGitCloneSize(cacheKey)
 }
 
 func (ghsi *SubscriberItem) doSubscriptionWithRetries(retryInterval time.Duration, retries int) {
-	err := ghsi.doSubscription()
+	err := ghsi.doScheduledSubscription()
+
+	// the subscriber item stopped while waiting for a worker isn't retried
+	if errors.Is(err, errSubscriberItemStopped) {
+		return
+	}
 
 	if err != nil {
 		klog.Error(err, "Subscription error.")
@@ -164,7 +179,11 @@ func (ghsi *SubscriberItem) doSubscriptionWithRetries(retryInterval time.Duratio
 			time.Sleep(retryInterval)
 			klog.Infof("Re-try #%d: subcribing to the Git repo", n+1)
 
-			err = ghsi.doSubscription()
+			err = ghsi.doScheduledSubscription()
+			if errors.Is(err, errSubscriberItemStopped) {
+				return
+			}
+
 			if err != nil {
 				klog.Error(err, "Subscription error.")
 			}
@@ -176,6 +195,22 @@ func (ghsi *SubscriberItem) doSubscriptionWithRetries(retryInterval time.Duratio
 	}
 }
 
+// doScheduledSubscription runs the subscription once the scheduler gives it a worker of its channel
+func (ghsi *SubscriberItem) doScheduledSubscription() error {
+	channel := ""
+	if ghsi.Channel != nil {
+		channel = ghsi.Channel.GetNamespace() + "/" + ghsi.Channel.GetName()
+	}
+
+	if !scheduler.acquire(channel, ghsi.stopch) {
+		return errSubscriberItemStopped
+	}
+
+	defer scheduler.release(channel)
+
+	return ghsi.doSubscription()
+}
+
 func (ghsi *SubscriberItem) doSubscription() error {
 	hostkey := types.NamespacedName{Name: ghsi.Subscription.Name, Namespace: ghsi.Subscription.Namespace}
 	klog.Info("enter doSubscription: ", hostkey.String())
@@ -255,6 +290,21 @@ func (ghsi *SubscriberItem) doSubscription() error {
 		}
 	}
 
+	// the mirror remotes of the channel are cloned with the credentials of their secrets on the hub
+	ghsi.mirrorSecrets = utils.FetchGitMirrorSecrets(ghsi.synchronizer.GetRemoteNonCachedClient(), ghsi.Channel)
+
+	// the clone is deferred to the next retry while the agent is close to its memory limit, not to be OOM killed mid-rollout
+	if reason, deferred := utils.DeferGitClone(hostkey.String()); deferred {
+		ghsi.successful = false
+
+		msg := fmt.Sprintf("Git clone deferred, %v", reason)
+
+		utils.RecordThrottleDecision(utils.ThrottleDecisionDeferGitClone, reason)
+		ghsi.synchronizer.RecordEvent(ghsi.Subscription, utils.EventReasonResourceThrottled, msg, nil)
+
+		return errors.New(msg)
+	}
+
 	//Clone the git repo
 	startTime := time.Now().UnixMilli()
 	commitID, err := ghsi.cloneGitRepo()
@@ -277,6 +327,34 @@ func (ghsi *SubscriberItem) doSubscription() error {
 
 	klog.Info("Git commit: ", commitID)
 
+	utils.UpdateGitFetchStatus(ghsi.synchronizer.GetLocalClient(), ghsi.Subscription, commitID, ghsi.clonedRemote, ghsi.reconcileRate)
+
+	if utils.IsProfilingEnabled() || utils.IsResourceThrottlingEnabled() {
+		size := utils.DirSize(ghsi.repoRoot)
+
+		utils.RecordCacheSize(utils.CacheGitClone, hostkey.String(), size)
+		utils.RecordGitCloneSize(hostkey.String(), size)
+	}
+
+	// after an agent restart, the commit fully applied before the restart isn't rendered and applied again
+	if !ghsi.checkpointChecked {
+		ghsi.checkpointChecked = true
+
+		checkpoint, err := utils.GetSubscriptionCheckpoint(ghsi.synchronizer.GetLocalClient(), ghsi.Subscription)
+		if err != nil {
+			klog.Warningf("failed to get the checkpoint of appsub %v, err: %v", hostkey.String(), err)
+		}
+
+		if checkpoint.IsCompleted(ghsi.Subscription, commitID) {
+			klog.Infof("Appsub %s Git commit: %s is already applied before the restart. Skip reconcile.", hostkey.String(), commitID)
+
+			ghsi.commitID = commitID
+			ghsi.successful = true
+
+			return nil
+		}
+	}
+
 	if strings.EqualFold(ghsi.reconcileRate, "medium") {
 		// every 3 minutes, compare commit ID. If changed, reconcile resources.
 		// every 15 minutes, reconcile resources without commit ID comparison.
@@ -397,19 +475,65 @@ func (ghsi *SubscriberItem) doSubscription() error {
 		return errors.New("failed to prepare resources to apply and there is no resource to apply. err: " + errMsg)
 	}
 
+	if utils.IsProfilingEnabled() {
+		utils.RecordCacheSize(utils.CacheGitRender, hostkey.String(), resourcesSize(ghsi.resources))
+	}
+
 	allowedGroupResources, deniedGroupResources := utils.GetAllowDenyLists(*ghsi.Subscription)
 
-	if err := ghsi.synchronizer.ProcessSubResources(ghsi.Subscription, ghsi.resources,
+	// the applied commit is the revision of the appsubstatus inventory
+	appliedSub := ghsi.Subscription.DeepCopy()
+	subAnnotations := appliedSub.GetAnnotations()
+
+	if subAnnotations == nil {
+		subAnnotations = map[string]string{}
+	}
+
+	subAnnotations[appv1.AnnotationGitCommit] = commitID
+	appliedSub.SetAnnotations(subAnnotations)
+
+	// the namespaced resources of each commit are deployed into the namespace of the revision
+	if ns := utils.GetRevisionNamespace(appliedSub, commitID); ns != "" {
+		klog.Infof("Deploying the resources of appsub %s Git commit: %s into namespace %s", hostkey.String(), commitID, ns)
+
+		for _, resource := range ghsi.resources {
+			if ghsi.synchronizer.IsResourceNamespaced(resource.Resource) {
+				resource.Resource.SetNamespace(ns)
+			}
+		}
+	}
+
+	// a locked subscription refuses a tag or an image tag moved to another content
+	if err := ghsi.checkLock(commitID); err != nil {
+		klog.Errorf("refuse to deploy appsub %s Git commit: %s, err: %v", hostkey.String(), commitID, err)
+
+		ghsi.successful = false
+
+		return err
+	}
+
+	if err := ghsi.synchronizer.ProcessSubResources(appliedSub, ghsi.resources,
 		allowedGroupResources, deniedGroupResources, ghsi.clusterAdmin); err != nil {
 		klog.Error(err)
 
 		ghsi.successful = false
 
+		if errors.Is(err, kubesynchronizer.ErrDraining) {
+			interrupted := &kubesynchronizer.InterruptedError{}
+			if !errors.As(err, &interrupted) {
+				interrupted = nil
+			}
+
+			ghsi.saveCheckpoint(commitID, utils.CheckpointPhaseInterrupted, interrupted)
+		}
+
 		return err
 	}
 
 	ghsi.commitID = commitID
 
+	ghsi.saveCheckpoint(commitID, utils.CheckpointPhaseCompleted, nil)
+
 	ghsi.resources = nil
 	ghsi.chartDirs = nil
 	ghsi.kustomizeDirs = nil
@@ -442,28 +566,24 @@ func (ghsi *SubscriberItem) subscribeKustomizations() error {
 		}
 
 		// Split the output of kustomize build output into individual kube resource YAML files
-		resources := utils.ParseYAML(out)
-		for _, resource := range resources {
-			resourceFile := []byte(strings.Trim(resource, "\t \n"))
-
-			t := kubeResource{}
-			err := yaml.Unmarshal(resourceFile, &t)
-
-			if err != nil {
-				klog.Error(err, "Failed to unmarshal YAML file")
-				continue
-			}
+		err = utils.DecodeManifests(bytes.NewReader(out), utils.GetManifestLimits(),
+			func(resourceFile []byte, rsc *unstructured.Unstructured) error {
+				t := kubeResource{TypeMeta: metav1.TypeMeta{APIVersion: rsc.GetAPIVersion(), Kind: rsc.GetKind()}}
+				t.SetAnnotations(rsc.GetAnnotations())
 
-			if t.APIVersion == "" || t.Kind == "" {
-				klog.Info("Not a Kubernetes resource")
-			} else {
 				err := checkSubscriptionAnnotation(t)
 				if err != nil {
 					klog.Errorf("Failed to apply %s/%s resource. err: %s", t.APIVersion, t.Kind, err)
 				}
 
 				ghsi.subscribeResourceFile(resourceFile)
-			}
+
+				return nil
+			})
+		if err != nil {
+			klog.Errorf("Failed to decode the kustomization %v, err: %v", relativePath, err)
+
+			return err
 		}
 	}
 
@@ -484,60 +604,43 @@ func checkSubscriptionAnnotation(resource kubeResource) error {
 
 func (ghsi *SubscriberItem) subscribeResources(rscFiles []string) error {
 	// sync kube resource manifests
+	// the files are decoded one resource at a time, the large files aren't loaded in memory
+	limits := utils.GetManifestLimits()
+
 	for _, rscFile := range rscFiles {
-		file, err := ioutil.ReadFile(rscFile) // #nosec G304 rscFile is not user input
+		err := utils.DecodeManifestFile(rscFile, limits, func(resource []byte, o *unstructured.Unstructured) error {
+			klog.V(1).Info("Applying Kubernetes resource of kind ", o.GetKind())
 
-		if err != nil {
-			klog.Error(err, "Failed to read YAML file "+rscFile)
+			if o.GetKind() == "Subscription" {
+				klog.V(1).Infof("Injecting userID(%s), Group(%s) to subscription", ghsi.userID, ghsi.userGroup)
 
-			return err
-		}
+				annotations := o.GetAnnotations()
+				if len(annotations) == 0 {
+					annotations = map[string]string{}
+				}
 
-		resources := utils.ParseKubeResoures(file)
+				annotations[appv1.AnnotationUserIdentity] = ghsi.userID
+				annotations[appv1.AnnotationUserGroup] = ghsi.userGroup
+				o.SetAnnotations(annotations)
 
-		if len(resources) > 0 {
-			for _, resource := range resources {
-				t := kubeResource{}
-				err := yaml.Unmarshal(resource, &t)
+				var err error
 
+				resource, err = yaml.Marshal(o)
 				if err != nil {
-					// Ignore if it does not have apiVersion or kind fields in the YAML
-					klog.Infof("Invalid kube resources. err: %v ", err)
+					klog.Error(err)
 
-					continue
+					return nil
 				}
+			}
 
-				klog.V(1).Info("Applying Kubernetes resource of kind ", t.Kind)
-
-				if t.Kind == "Subscription" {
-					klog.V(1).Infof("Injecting userID(%s), Group(%s) to subscription", ghsi.userID, ghsi.userGroup)
-
-					o := &unstructured.Unstructured{}
-					if err := yaml.Unmarshal(resource, o); err != nil {
-						klog.Error("Failed to unmarshal resource YAML.")
-
-						return err
-					}
-
-					annotations := o.GetAnnotations()
-					if len(annotations) == 0 {
-						annotations = map[string]string{}
-					}
-
-					annotations[appv1.AnnotationUserIdentity] = ghsi.userID
-					annotations[appv1.AnnotationUserGroup] = ghsi.userGroup
-					o.SetAnnotations(annotations)
-
-					resource, err = yaml.Marshal(o)
-					if err != nil {
-						klog.Error(err)
+			ghsi.subscribeResourceFile(resource)
 
-						continue
-					}
-				}
+			return nil
+		})
+		if err != nil {
+			klog.Error(err, "Failed to read YAML file "+rscFile)
 
-				ghsi.subscribeResourceFile(resource)
-			}
+			return err
 		}
 	}
 
@@ -660,55 +763,12 @@ func (ghsi *SubscriberItem) subscribeResource(file []byte) (*unstructured.Unstru
 }
 
 func (ghsi *SubscriberItem) checkFilters(rsc *unstructured.Unstructured) (errMsg string) {
-	if ghsi.Subscription.Spec.Package != "" && ghsi.Subscription.Spec.Package != rsc.GetName() {
-		errMsg = "Name does not match, skiping:" + ghsi.Subscription.Spec.Package + "|" + rsc.GetName()
-
-		return errMsg
-	}
-
-	if ghsi.Subscription.Spec.Package == rsc.GetName() {
-		klog.V(4).Info("Name does matches: " + ghsi.Subscription.Spec.Package + "|" + rsc.GetName())
+	errMsg = utils.CheckPackageFilter(ghsi.Subscription, rsc.GetName(), rsc.GetLabels(), rsc.GetAnnotations())
+	if errMsg == "" {
+		klog.V(4).Info("Passed package filter check on resource " + rsc.GetName())
 	}
 
-	if ghsi.Subscription.Spec.PackageFilter != nil {
-		if utils.LabelChecker(ghsi.Subscription.Spec.PackageFilter.LabelSelector, rsc.GetLabels()) {
-			klog.V(4).Info("Passed label check on resource " + rsc.GetName())
-		} else {
-			errMsg = "Failed to pass label check on resource " + rsc.GetName()
-
-			return errMsg
-		}
-
-		annotations := ghsi.Subscription.Spec.PackageFilter.Annotations
-		if annotations != nil {
-			klog.V(4).Info("checking annotations filter:", annotations)
-
-			rscanno := rsc.GetAnnotations()
-			if rscanno == nil {
-				rscanno = make(map[string]string)
-			}
-
-			matched := true
-
-			for k, v := range annotations {
-				if rscanno[k] != v {
-					klog.Info("Annotation filter does not match:", k, "|", v, "|", rscanno[k])
-
-					matched = false
-
-					break
-				}
-			}
-
-			if !matched {
-				errMsg = "Failed to pass annotation check to manifest " + rsc.GetName()
-
-				return errMsg
-			}
-		}
-	}
-
-	return ""
+	return errMsg
 }
 
 func (ghsi *SubscriberItem) subscribeHelmCharts(indexFile *repo.IndexFile) (err error) {
@@ -731,15 +791,21 @@ func (ghsi *SubscriberItem) subscribeHelmCharts(indexFile *repo.IndexFile) (err
 }
 
 func (ghsi *SubscriberItem) cloneGitRepo() (commitID string, err error) {
+	ghsi.clonedRemote = ""
+
+	if utils.IsBundleChannel(string(ghsi.Channel.Spec.Type)) {
+		return ghsi.fetchBundle()
+	}
+
 	annotations := ghsi.Subscription.GetAnnotations()
 
-	cloneDepth := 1
+	cloneDepth := utils.DefaultGitCloneDepth(1)
 
 	if annotations[appv1.AnnotationGitCloneDepth] != "" {
 		cloneDepth, err = strconv.Atoi(annotations[appv1.AnnotationGitCloneDepth])
 
 		if err != nil {
-			cloneDepth = 1
+			cloneDepth = utils.DefaultGitCloneDepth(1)
 
 			klog.Error(err, " failed to convert git-clone-depth annotation to integer")
 		}
@@ -753,6 +819,17 @@ func (ghsi *SubscriberItem) cloneGitRepo() (commitID string, err error) {
 		CloneDepth:  cloneDepth,
 		Branch:      utils.GetSubscriptionBranch(ghsi.Subscription),
 		DestDir:     ghsi.repoRoot,
+		Channel:     types.NamespacedName{Namespace: ghsi.Channel.Namespace, Name: ghsi.Channel.Name},
+	}
+
+	// a preview subscription deploys the head of the pull request
+	pr, err := utils.GetSubscriptionPullRequest(ghsi.Subscription, ghsi.Channel.Spec.Pathname)
+	if err != nil {
+		return "", err
+	}
+
+	if pr != nil {
+		cloneOptions.Branch = pr.Ref()
 	}
 
 	// Get the primary channel connection options
@@ -780,7 +857,70 @@ func (ghsi *SubscriberItem) cloneGitRepo() (commitID string, err error) {
 		cloneOptions.SecondaryConnectionOption = secondaryChannelConnectionConfig
 	}
 
-	return utils.CloneGitRepo(cloneOptions)
+	cloneOptions.MirrorConnectionOptions = utils.GetGitMirrorConnectionConfigs(ghsi.Channel, ghsi.mirrorSecrets, ghsi.ChannelConfigMap)
+
+	commitID, err = utils.CloneGitRepo(cloneOptions)
+	if err != nil {
+		return "", err
+	}
+
+	ghsi.clonedRemote = cloneOptions.ClonedRemote
+
+	return commitID, nil
+}
+
+// fetchBundle makes the offline bundle of the channel available locally, the bundle revision is used as the commit ID
+func (ghsi *SubscriberItem) fetchBundle() (revision string, err error) {
+	fetchOption := func(channel *chnv1.Channel, secret *corev1.Secret) (*utils.BundleFetchOption, error) {
+		opt := &utils.BundleFetchOption{
+			Pathname: channel.Spec.Pathname,
+			Insecure: channel.Spec.InsecureSkipVerify,
+			DestDir:  utils.GetLocalGitFolder(ghsi.Subscription),
+		}
+
+		if secret != nil {
+			user, token, _, _, _, _, err := utils.ParseChannelSecret(secret)
+			if err != nil {
+				return nil, err
+			}
+
+			opt.User = user
+			opt.Password = token
+		}
+
+		return opt, nil
+	}
+
+	opt, err := fetchOption(ghsi.Channel, ghsi.ChannelSecret)
+	if err != nil {
+		return "", err
+	}
+
+	root, revision, err := utils.FetchBundle(opt)
+	if err != nil {
+		if ghsi.SecondaryChannel == nil {
+			return "", err
+		}
+
+		klog.Warning("failed to fetch the bundle of the primary channel, err: " + err.Error())
+		klog.Info("trying with the secondary channel")
+
+		opt, err2 := fetchOption(ghsi.SecondaryChannel, ghsi.SecondaryChannelSecret)
+		if err2 != nil {
+			return "", err2
+		}
+
+		root, revision, err2 = utils.FetchBundle(opt)
+		if err2 != nil {
+			klog.Error("failed to fetch the bundle of the secondary channel, err: " + err2.Error())
+
+			return "", err2
+		}
+	}
+
+	ghsi.repoRoot = root
+
+	return revision, nil
 }
 
 func getChannelConnectionConfig(secret *corev1.Secret, configmap *corev1.ConfigMap) (connCfg *utils.ChannelConnectionCfg, err error) {
@@ -810,6 +950,28 @@ func getChannelConnectionConfig(secret *corev1.Secret, configmap *corev1.ConfigM
 	return connCfg, nil
 }
 
+// checkLock checks the commit of the git tag and the digests of the images of the resources against the lock of the
+// subscription, the commits declared by their ID are their content
+func (ghsi *SubscriberItem) checkLock(commitID string) error {
+	inputs := map[string]string{}
+
+	if utils.IsSubscriptionLocked(ghsi.Subscription) {
+		if ghsi.desiredTag != "" && ghsi.desiredCommit == "" {
+			inputs[utils.LockGitTagInput(ghsi.desiredTag)] = commitID
+		}
+
+		for _, resource := range ghsi.resources {
+			utils.AddImageLockInputs(inputs, resource.Resource.Object)
+		}
+	}
+
+	err := utils.CheckSubscriptionLock(ghsi.synchronizer.GetLocalClient(), ghsi.Subscription, inputs)
+
+	utils.SetLockMismatchCondition(ghsi.synchronizer.GetLocalClient(), ghsi.Subscription, err)
+
+	return err
+}
+
 func (ghsi *SubscriberItem) sortClonedGitRepo() error {
 	if ghsi.Subscription.Spec.PackageFilter != nil && ghsi.Subscription.Spec.PackageFilter.FilterRef != nil {
 		ghsi.SubscriberItem.SubscriptionConfigMap = &corev1.ConfigMap{}
@@ -840,7 +1002,10 @@ func (ghsi *SubscriberItem) sortClonedGitRepo() error {
 	// crdsAndNamespaceFiles contains CustomResourceDefinition and Namespace Kubernetes resources file paths
 	// rbacFiles contains ServiceAccount, ClusterRole and Role Kubernetes resource file paths
 	// otherFiles contains all other Kubernetes resource file paths
-	chartDirs, kustomizeDirs, crdsAndNamespaceFiles, rbacFiles, otherFiles, err := utils.SortResources(ghsi.repoRoot, resourcePath, utils.SkipHooksOnManaged)
+	skip := utils.ExcludePathsSkipFunc(ghsi.repoRoot, utils.GitExcludePaths(ghsi.Channel),
+		utils.ManagedSkipFunc(ghsi.Subscription.Spec.PackageFilter))
+
+	chartDirs, kustomizeDirs, crdsAndNamespaceFiles, rbacFiles, otherFiles, err := utils.SortResources(ghsi.repoRoot, resourcePath, skip)
 	if err != nil {
 		klog.Error(err, "Failed to sort kubernetes resources and helm charts.")
 
@@ -853,8 +1018,15 @@ func (ghsi *SubscriberItem) sortClonedGitRepo() error {
 	ghsi.rbacFiles = rbacFiles
 	ghsi.otherFiles = otherFiles
 
-	// Build a helm repo index file
-	indexFile, err := utils.GenerateHelmIndexFile(ghsi.Subscription, ghsi.repoRoot, chartDirs)
+	var indexFile *repo.IndexFile
+
+	if utils.IsBundleChannel(string(ghsi.Channel.Spec.Type)) {
+		// Charts in the bundle are packaged and listed in the bundle index file
+		indexFile, err = utils.LoadBundleIndex(ghsi.Subscription, ghsi.repoRoot)
+	} else {
+		// Build a helm repo index file
+		indexFile, err = utils.GenerateHelmIndexFile(ghsi.Subscription, ghsi.repoRoot, chartDirs)
+	}
 
 	if err != nil {
 		// If package name is not specified in the subscription, filterCharts throws an error. In this case, just return the original index file.
@@ -870,3 +1042,40 @@ func (ghsi *SubscriberItem) sortClonedGitRepo() error {
 
 	return nil
 }
+
+// saveCheckpoint persists the progress of the commit so the agent restarts resume from it, the applied waves and the
+// pending deletions of an interrupted apply are kept until the commit is fully applied
+func (ghsi *SubscriberItem) saveCheckpoint(commitID, phase string, interrupted *kubesynchronizer.InterruptedError) {
+	checkpoint := utils.SubscriptionCheckpoint{
+		Revision:  commitID,
+		Hash:      utils.SubscriptionCheckpointHash(ghsi.Subscription),
+		Phase:     phase,
+		Resources: len(ghsi.resources),
+	}
+
+	if interrupted != nil {
+		checkpoint.AppliedWaves = interrupted.AppliedWaves
+		checkpoint.PendingDeletions = interrupted.PendingDeletions
+	}
+
+	if err := utils.SaveSubscriptionCheckpoint(ghsi.synchronizer.GetLocalClient(), ghsi.Subscription, checkpoint); err != nil {
+		klog.Warningf("failed to save the checkpoint of appsub %v/%v, err: %v", ghsi.Subscription.Namespace, ghsi.Subscription.Name, err)
+	}
+}
+
+// resourcesSize returns the size in bytes of the JSON of the rendered resources
+func resourcesSize(resources []kubesynchronizer.ResourceUnit) int64 {
+	size := int64(0)
+
+	for _, resource := range resources {
+		if resource.Resource == nil {
+			continue
+		}
+
+		if data, err := resource.Resource.MarshalJSON(); err == nil {
+			size += int64(len(data))
+		}
+	}
+
+	return size
+}
@@ -0,0 +1,175 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"sync"
+
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+// channelScheduler shares the workers of the git subscriber between the channels. Each channel has its own queue of
+// subscriptions waiting for a worker, the free workers are given to the channels in turn so the subscriptions of a
+// huge repository can't starve the subscriptions of the other channels
+type channelScheduler struct {
+	mtx sync.Mutex
+	// workers returns the number of workers and the number of workers per channel, 0 for no limit
+	workers  func() (int, int)
+	running  int
+	channels map[string]*channelQueue
+	// the channels with waiting subscriptions, in the order they get the next free workers
+	ring []string
+}
+
+type channelQueue struct {
+	waiting []chan struct{}
+	running int
+}
+
+// scheduler runs the subscriptions of all the git subscriber items
+var scheduler = newChannelScheduler(utils.GitWorkers)
+
+func newChannelScheduler(workers func() (int, int)) *channelScheduler {
+	return &channelScheduler{workers: workers, channels: map[string]*channelQueue{}}
+}
+
+// acquire waits for a worker to run a subscription of the channel, it returns false if the subscription is stopped
+// while waiting. The worker is released with release once the subscription is done
+func (s *channelScheduler) acquire(channel string, stopch <-chan struct{}) bool {
+	ticket := make(chan struct{})
+
+	s.mtx.Lock()
+
+	q, ok := s.channels[channel]
+	if !ok {
+		q = &channelQueue{}
+		s.channels[channel] = q
+	}
+
+	q.waiting = append(q.waiting, ticket)
+	if len(q.waiting) == 1 {
+		s.ring = append(s.ring, channel)
+	}
+
+	s.dispatch()
+	s.mtx.Unlock()
+
+	select {
+	case <-ticket:
+		return true
+	case <-stopch:
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	select {
+	case <-ticket:
+		// the worker was given to the subscription as it stopped
+		s.releaseLocked(channel)
+
+		return false
+	default:
+	}
+
+	for i, waiting := range q.waiting {
+		if waiting == ticket {
+			q.waiting = append(q.waiting[:i:i], q.waiting[i+1:]...)
+
+			break
+		}
+	}
+
+	if len(q.waiting) == 0 {
+		s.removeFromRing(channel)
+		s.forget(channel)
+	}
+
+	return false
+}
+
+// release gives the worker of a subscription of the channel back to the waiting subscriptions
+func (s *channelScheduler) release(channel string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.releaseLocked(channel)
+}
+
+func (s *channelScheduler) releaseLocked(channel string) {
+	s.running--
+
+	if q, ok := s.channels[channel]; ok {
+		q.running--
+		s.forget(channel)
+	}
+
+	s.dispatch()
+}
+
+// dispatch gives the free workers to the first channels of the ring below their worker limit, a channel given a
+// worker goes to the end of the ring
+func (s *channelScheduler) dispatch() {
+	workers, perChannel := s.workers()
+
+	for len(s.ring) > 0 && (workers < 1 || s.running < workers) {
+		dispatched := false
+
+		for _, channel := range s.ring {
+			q := s.channels[channel]
+			if perChannel > 0 && q.running >= perChannel {
+				continue
+			}
+
+			ticket := q.waiting[0]
+			q.waiting = q.waiting[1:]
+			q.running++
+			s.running++
+
+			s.removeFromRing(channel)
+
+			if len(q.waiting) > 0 {
+				s.ring = append(s.ring, channel)
+			}
+
+			close(ticket)
+
+			dispatched = true
+
+			break
+		}
+
+		if !dispatched {
+			return
+		}
+	}
+}
+
+func (s *channelScheduler) removeFromRing(channel string) {
+	for i, c := range s.ring {
+		if c == channel {
+			s.ring = append(s.ring[:i:i], s.ring[i+1:]...)
+
+			return
+		}
+	}
+}
+
+// forget drops the queue of the channel once it has no running or waiting subscriptions
+func (s *channelScheduler) forget(channel string) {
+	if q := s.channels[channel]; q.running == 0 && len(q.waiting) == 0 {
+		delete(s.channels, channel)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestChannelScheduler(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	workers, perChannel := 2, 1
+	s := newChannelScheduler(func() (int, int) { return workers, perChannel })
+
+	mtx := sync.Mutex{}
+	started := []string{}
+
+	// run waits for a worker and records the subscriptions in the order they get one
+	run := func(channel, sub string, stopch chan struct{}) chan bool {
+		acquired := make(chan bool, 1)
+
+		go func() {
+			ok := s.acquire(channel, stopch)
+			if ok {
+				mtx.Lock()
+				started = append(started, sub)
+				mtx.Unlock()
+			}
+
+			acquired <- ok
+		}()
+
+		return acquired
+	}
+
+	stopch := make(chan struct{})
+
+	// the huge repository runs on one worker, the other channel gets the second worker
+	g.Expect(<-run("huge", "huge-1", stopch)).To(BeTrue())
+
+	huge2 := run("huge", "huge-2", stopch)
+	huge3 := run("huge", "huge-3", stopch)
+
+	g.Expect(<-run("small", "small-1", stopch)).To(BeTrue())
+	g.Consistently(huge2, 100*time.Millisecond).ShouldNot(Receive())
+
+	// the queued subscriptions of the channels get the free workers in turn
+	small2 := run("small", "small-2", stopch)
+
+	time.Sleep(50 * time.Millisecond)
+	s.release("small")
+	g.Eventually(small2).Should(Receive(BeTrue()))
+
+	s.release("huge")
+	g.Eventually(huge2).Should(Receive(BeTrue()))
+	g.Consistently(huge3, 100*time.Millisecond).ShouldNot(Receive())
+
+	// a subscription stopped while waiting leaves the queue
+	stopped := make(chan struct{})
+	huge4 := run("huge", "huge-4", stopped)
+
+	time.Sleep(50 * time.Millisecond)
+	close(stopped)
+	g.Eventually(huge4).Should(Receive(BeFalse()))
+
+	s.release("huge")
+	g.Eventually(huge3).Should(Receive(BeTrue()))
+
+	s.release("huge")
+	s.release("small")
+
+	g.Expect(started).To(Equal([]string{"huge-1", "small-1", "small-2", "huge-2", "huge-3"}))
+	g.Expect(s.running).To(Equal(0))
+	g.Expect(s.channels).To(BeEmpty())
+	g.Expect(s.ring).To(BeEmpty())
+
+	// without workers the subscriptions are not scheduled
+	workers, perChannel = 0, 0
+
+	for i := 0; i < 5; i++ {
+		g.Expect(s.acquire("huge", stopch)).To(BeTrue())
+	}
+}
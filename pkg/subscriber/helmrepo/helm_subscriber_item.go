@@ -35,14 +35,15 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
 	releasev1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/helmrelease/v1"
 	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	rUtils "open-cluster-management.io/multicloud-operators-subscription/pkg/helmrelease/utils"
 	kubesynchronizer "open-cluster-management.io/multicloud-operators-subscription/pkg/synchronizer/kubernetes"
 	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils/tlsconfig"
 )
 
 // SubscriberItem - defines the unit of namespace subscription.
@@ -57,6 +58,8 @@ type SubscriberItem struct {
 	success       bool
 	synchronizer  SyncSource
 	clusterAdmin  bool
+	// the secrets of the helm repo mirrors of the channels, by mirror URL
+	mirrorSecrets map[string]*corev1.Secret
 }
 
 var (
@@ -86,7 +89,7 @@ func (hrsi *SubscriberItem) Start(restart bool) {
 
 	hrsi.stopch = make(chan struct{})
 
-	loopPeriod, retryInterval, retries := utils.GetReconcileInterval(hrsi.reconcileRate, chnv1.ChannelTypeHelmRepo)
+	_, retryInterval, retries := utils.GetReconcileInterval(hrsi.reconcileRate, chnv1.ChannelTypeHelmRepo)
 
 	if strings.EqualFold(hrsi.reconcileRate, "off") {
 		klog.Infof("auto-reconcile is OFF")
@@ -96,7 +99,7 @@ func (hrsi *SubscriberItem) Start(restart bool) {
 		return
 	}
 
-	go wait.Until(func() {
+	go utils.UntilReconcileInterval(func() {
 		tw := hrsi.SubscriberItem.Subscription.Spec.TimeWindow
 		if tw != nil {
 			nextRun := utils.NextStartPoint(tw, time.Now())
@@ -117,7 +120,7 @@ func (hrsi *SubscriberItem) Start(restart bool) {
 		}
 
 		hrsi.doSubscriptionWithRetries(retryInterval, retries)
-	}, loopPeriod, hrsi.stopch)
+	}, hrsi.reconcileRate, chnv1.ChannelTypeHelmRepo, hrsi.stopch)
 }
 
 func (hrsi *SubscriberItem) Stop() {
@@ -162,7 +165,7 @@ func (hrsi *SubscriberItem) getRepoInfo(usePrimary bool) (*repo.IndexFile, strin
 		return nil, "", err
 	}
 
-	indexFile, hash, err := getHelmRepoIndex(httpClient, hrsi.Subscription, hrsi.ChannelSecret, repoURL)
+	indexFile, hash, err := getHelmRepoIndexWithMirrors(httpClient, hrsi.Subscription, channel, hrsi.ChannelSecret, hrsi.mirrorSecrets)
 
 	if err != nil {
 		klog.Error(err, "Unable to retrieve the helm repo index", repoURL)
@@ -238,6 +241,8 @@ func (hrsi *SubscriberItem) doSubscription() {
 		}
 	}
 
+	hrsi.updateMirrorSecrets()
+
 	indexFile, hash, err = hrsi.getRepoInfo(true) // true for using primary channel
 
 	if err != nil {
@@ -263,6 +268,23 @@ func (hrsi *SubscriberItem) doSubscription() {
 			hrsi.Subscription.GetNamespace(), "/", hrsi.Subscription.GetName())
 	}
 
+	utils.UpdateFetchStatus(hrsi.synchronizer.GetLocalClient(), hrsi.Subscription, chnv1.ChannelTypeHelmRepo,
+		utils.ChartIndexRevision(indexFile), hrsi.reconcileRate)
+
+	// a locked subscription refuses a chart version with another digest
+	lockErr := utils.CheckSubscriptionLock(hrsi.synchronizer.GetLocalClient(), hrsi.Subscription, utils.ChartLockInputs(indexFile))
+
+	utils.SetLockMismatchCondition(hrsi.synchronizer.GetLocalClient(), hrsi.Subscription, lockErr)
+
+	if lockErr != nil {
+		klog.Errorf("refuse to deploy the charts of subscription %v/%v, err: %v", hrsi.Subscription.Namespace,
+			hrsi.Subscription.Name, lockErr)
+
+		hrsi.success = false
+
+		return
+	}
+
 	klog.V(4).Infof("Check if helmRepo changed with hash %s", hash)
 
 	isParentMultiClusterHub := isParentMultiClusterHub(hrsi.Subscription)
@@ -420,6 +442,29 @@ func isHelmReleaseStatusPopulated(client client.Client, hostSub types.Namespaced
 	return true, nil
 }
 
+// updateMirrorSecrets retrieves the secrets of the helm repo mirrors of the channels from the hub and copies them in
+// the namespace of the subscription for the HelmReleases
+func (hrsi *SubscriberItem) updateMirrorSecrets() {
+	mirrorSecrets := map[string]*corev1.Secret{}
+
+	for _, chn := range []*chnv1.Channel{hrsi.Channel, hrsi.SecondaryChannel} {
+		if chn == nil {
+			continue
+		}
+
+		for mirrorURL, secret := range utils.FetchHelmRepoMirrorSecrets(hrsi.synchronizer.GetRemoteNonCachedClient(), chn) {
+			if err := utils.DeployHelmRepoMirrorSecret(hrsi.synchronizer.GetLocalNonCachedClient(), hrsi.Subscription, secret); err != nil {
+				klog.Warningf("can't deploy secret %v of helm repo mirror %v for subscription %v/%v, err: %v",
+					secret.Name, mirrorURL, hrsi.Subscription.Namespace, hrsi.Subscription.Name, err)
+			}
+
+			mirrorSecrets[mirrorURL] = secret
+		}
+	}
+
+	hrsi.mirrorSecrets = mirrorSecrets
+}
+
 func (hrsi *SubscriberItem) processSubscription(indexFile *repo.IndexFile, hash string) error {
 	if err := hrsi.manageHelmCR(indexFile); err != nil {
 		return err
@@ -448,13 +493,11 @@ func getHelmRepoClient(chnCfg *corev1.ConfigMap, insecureSkipVerify bool) (*http
 		IdleConnTimeout:       90 * time.Second,
 		TLSHandshakeTimeout:   10 * time.Second,
 		ExpectContinueTimeout: 1 * time.Second,
-		/* #nosec G402 */
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: insecureSkipVerify, // #nosec G402 InsecureSkipVerify optionally
-			MinVersion:         tls.VersionTLS12,
-		},
+		TLSClientConfig:       tlsconfig.NewConfig(tls.VersionTLS12),
 	}
 
+	transport.TLSClientConfig.InsecureSkipVerify = insecureSkipVerify // #nosec G402 InsecureSkipVerify optionally
+
 	if chnCfg != nil && !insecureSkipVerify {
 		helmRepoConfigData := chnCfg.Data
 		klog.V(1).Infof("s.HelmRepoConfig.Data %v", helmRepoConfigData)
@@ -512,11 +555,14 @@ func getHelmRepoIndex(client rest.HTTPClient, sub *appv1.Subscription,
 	resp, err := client.Do(req)
 
 	if err != nil {
+		rUtils.RecordHelmRepoRequest(cleanRepoURL, false)
 		klog.Error(err, "Http request failed: ", cleanRepoURL)
 
 		return nil, "", err
 	}
 
+	rUtils.RecordHelmRepoRequest(cleanRepoURL, resp.StatusCode < http.StatusInternalServerError)
+
 	if resp.StatusCode != http.StatusOK {
 		klog.Errorf("http request %s failed: status %s", cleanRepoURL, resp.Status)
 
@@ -547,11 +593,46 @@ func getHelmRepoIndex(client rest.HTTPClient, sub *appv1.Subscription,
 		return nil, "", err
 	}
 
+	utils.FilterChartsByPath(sub, indexfile)
+
 	err = utils.FilterCharts(sub, indexfile)
 
 	return indexfile, hash, err
 }
 
+// getHelmRepoIndexWithMirrors retrieves the index from the pathname of the channel or from its mirrors, in order with
+// the healthy servers first. The mirrors are sent their own secret, not the secret of the channel.
+func getHelmRepoIndexWithMirrors(client rest.HTTPClient, sub *appv1.Subscription, chn *chnv1.Channel,
+	chnSrt *corev1.Secret, mirrorSecrets map[string]*corev1.Secret) (indexFile *repo.IndexFile, hash string, err error) {
+	repoURLs := utils.GetHelmRepoURLs(chn)
+	if len(repoURLs) == 1 {
+		return getHelmRepoIndex(client, sub, chnSrt, chn.Spec.Pathname)
+	}
+
+	var errs []string
+
+	for _, repoURL := range rUtils.SortHelmRepoURLs(repoURLs) {
+		secret := chnSrt
+
+		if repoURL != chn.Spec.Pathname {
+			secret = mirrorSecrets[repoURL]
+		}
+
+		indexFile, hash, err = getHelmRepoIndex(client, sub, secret, repoURL)
+		if err == nil {
+			if repoURL != chn.Spec.Pathname {
+				klog.Infof("retrieved the helm repo index of channel %v/%v from mirror %v", chn.Namespace, chn.Name, repoURL)
+			}
+
+			return indexFile, hash, nil
+		}
+
+		errs = append(errs, fmt.Sprintf("%v: %v", repoURL, err))
+	}
+
+	return nil, "", fmt.Errorf("failed to retrieve the helm repo index from the channel and its mirrors: %v", strings.Join(errs, "; "))
+}
+
 func GetSubscriptionChartsOnHub(hubClt client.Client, channel, secondChannel *chnv1.Channel, sub *appv1.Subscription) ([]*releasev1.HelmRelease, error) {
 	// Try with the primary channel first
 	indexFile, err := getChartIndexWithChannel(hubClt, channel, sub)
@@ -626,7 +707,9 @@ func getChartIndexWithChannel(hubClt client.Client, channel *chnv1.Channel, sub
 		return nil, gerr.Wrapf(err, "Unable to create client for helm repo %v", channel.Spec.Pathname)
 	}
 
-	indexFile, _, err := getHelmRepoIndex(httpClient, sub, chSecret, channel.Spec.Pathname)
+	mirrorSecrets := utils.FetchHelmRepoMirrorSecrets(hubClt, channel)
+
+	indexFile, _, err := getHelmRepoIndexWithMirrors(httpClient, sub, channel, chSecret, mirrorSecrets)
 	if err != nil {
 		return nil, gerr.Wrapf(err, "unable to retrieve the helm repo index %v", channel.Spec.Pathname)
 	}
@@ -0,0 +1,125 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helmrepo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/repo"
+	"sigs.k8s.io/yaml"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	rUtils "open-cluster-management.io/multicloud-operators-subscription/pkg/helmrelease/utils"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/testharness"
+)
+
+func TestHelmRepoMirrorFailover(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	index := repo.NewIndexFile()
+	g.Expect(index.MustAdd(&chart.Metadata{APIVersion: chart.APIVersionV2, Name: "demo", Version: "0.1.0"},
+		"demo-0.1.0.tgz", "", "sha256:0123")).To(Succeed())
+
+	indexYAML, err := yaml.Marshal(index)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// the artifact server of the channel is down, the mirror only answers with its own credentials
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer primary.Close()
+
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer mirror-token" || r.URL.Path != "/charts/index.yaml" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		_, _ = w.Write(indexYAML)
+	}))
+	defer mirror.Close()
+
+	chn := &chnv1.Channel{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "helm",
+			Namespace: "ch-ns",
+			Annotations: map[string]string{
+				appv1.AnnotationHelmRepoMirrors: `[{"url": "` + mirror.URL + `/charts", "secretRef": {"name": "mirror-auth"}}]`,
+			},
+		},
+		Spec: chnv1.ChannelSpec{
+			Type:      chnv1.ChannelTypeHelmRepo,
+			Pathname:  primary.URL,
+			SecretRef: &corev1.ObjectReference{Name: "channel-auth"},
+		},
+	}
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "sub-ns"},
+		Spec:       appv1.SubscriptionSpec{Channel: "ch-ns/helm", Package: "demo"},
+	}
+
+	clt, err := testharness.NewFakeClient(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "channel-auth", Namespace: "ch-ns"},
+			Data:       map[string][]byte{"authHeader": []byte("Bearer channel-token")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "mirror-auth", Namespace: "ch-ns"},
+			Data:       map[string][]byte{"authHeader": []byte("Bearer mirror-token")},
+		},
+	)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	helms, err := GetSubscriptionChartsOnHub(clt, chn, nil, sub)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(helms).To(HaveLen(1))
+
+	// the chart is downloaded from the channel or the mirror, with the secret of the mirror
+	g.Expect(helms[0].Repo.Source.HelmRepo.Urls).To(Equal([]string{
+		primary.URL + "/demo-0.1.0.tgz",
+		mirror.URL + "/charts/demo-0.1.0.tgz",
+	}))
+	g.Expect(helms[0].Repo.Source.HelmRepo.Mirrors).To(HaveLen(1))
+	g.Expect(helms[0].Repo.Source.HelmRepo.Mirrors[0].URL).To(Equal(mirror.URL + "/charts"))
+	g.Expect(helms[0].Repo.Source.HelmRepo.Mirrors[0].SecretRef).To(Equal(&corev1.ObjectReference{Name: "mirror-auth", Namespace: "ch-ns"}))
+
+	g.Expect(rUtils.IsHelmRepoHealthy(primary.URL)).To(BeFalse())
+	g.Expect(rUtils.IsHelmRepoHealthy(mirror.URL)).To(BeTrue())
+
+	// the mirror is not sent the secret of the channel
+	_, _, err = getHelmRepoIndexWithMirrors(http.DefaultClient, sub, chn, &corev1.Secret{
+		Data: map[string][]byte{"authHeader": []byte("Bearer channel-token")},
+	}, nil)
+	g.Expect(err).To(MatchError(ContainSubstring("401 Unauthorized")))
+
+	// the invalid mirrors are ignored
+	chn.Annotations[appv1.AnnotationHelmRepoMirrors] = `[{"url": "not a url"}]`
+
+	_, err = GetSubscriptionChartsOnHub(clt, chn, nil, sub)
+	g.Expect(err).To(MatchError(ContainSubstring("502 Bad Gateway")))
+	g.Expect(err).NotTo(MatchError(ContainSubstring("not a url")))
+
+	rUtils.RecordHelmRepoRequest(primary.URL, true)
+}
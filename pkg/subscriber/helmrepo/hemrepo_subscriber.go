@@ -126,7 +126,7 @@ func (hrs *Subscriber) SubscribeItem(subitem *appv1alpha1.SubscriberItem) error
 	}
 
 	hrssubitem.reconcileRate = utils.GetReconcileRate(chnAnnotations, subAnnotations)
-	hrssubitem.syncTime = subAnnotations[appv1alpha1.AnnotationManualReconcileTime]
+	hrssubitem.syncTime = utils.GetSyncTime(hrssubitem.Subscription)
 
 	// Reconcile level can be overridden to be
 	if strings.EqualFold(subAnnotations[appv1alpha1.AnnotationResourceReconcileLevel], "off") {
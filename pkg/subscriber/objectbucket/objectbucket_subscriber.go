@@ -124,7 +124,7 @@ func (obs *Subscriber) SubscribeItem(subitem *appv1alpha1.SubscriberItem) error
 	}
 
 	obssubitem.reconcileRate = utils.GetReconcileRate(chnAnnotations, subAnnotations)
-	obssubitem.syncTime = subAnnotations[appv1alpha1.AnnotationManualReconcileTime]
+	obssubitem.syncTime = utils.GetSyncTime(obssubitem.Subscription)
 
 	// Reconcile level can be overridden to be
 	if strings.EqualFold(subAnnotations[appv1alpha1.AnnotationResourceReconcileLevel], "off") {
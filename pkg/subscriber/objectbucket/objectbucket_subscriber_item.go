@@ -22,7 +22,6 @@ import (
 	"github.com/ghodss/yaml"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 
 	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
@@ -79,7 +78,7 @@ func (obsi *SubscriberItem) Start(restart bool) {
 		return
 	}
 
-	go wait.Until(func() {
+	go utils.UntilReconcileInterval(func() {
 		tw := obsi.SubscriberItem.Subscription.Spec.TimeWindow
 		if tw != nil {
 			nextRun := utils.NextStartPoint(tw, time.Now())
@@ -100,7 +99,7 @@ func (obsi *SubscriberItem) Start(restart bool) {
 		}
 
 		obsi.doSubscriptionWithRetries(retryInterval, retries)
-	}, loopPeriod, obsi.stopch)
+	}, obsi.reconcileRate, chnv1.ChannelTypeObjectBucket, obsi.stopch)
 }
 
 // Stop the subscriber.
@@ -182,9 +181,20 @@ func (obsi *SubscriberItem) getAwsHandler(primary bool) error {
 		return err
 	}
 
+	channel, configMap := obsi.Channel, obsi.ChannelConfigMap
+
+	if !primary {
+		channel, configMap = obsi.SecondaryChannel, obsi.SecondaryChannelConfigMap
+	}
+
+	opts, err := awsutils.ConnectionOptionsFromConfigMap(configMap, channel.Spec.InsecureSkipVerify)
+	if err != nil {
+		return err
+	}
+
 	klog.V(1).Info("Trying to connect to object bucket ", endpoint, "|", obsi.bucket)
 
-	if err := awshandler.InitObjectStoreConnection(endpoint, accessKeyID, secretAccessKey, region); err != nil {
+	if err := awshandler.InitObjectStoreConnectionWithOptions(endpoint, accessKeyID, secretAccessKey, region, opts); err != nil {
 		klog.Error(err, "unable initialize object store settings")
 		return err
 	}
@@ -329,10 +339,30 @@ func (obsi *SubscriberItem) doSubscription() {
 		return
 	}
 
-	tpls := []unstructured.Unstructured{}
+	objects, err := utils.SelectBucketObjects(obsi.Subscription, keys, bucketPath, func(key string) ([]byte, error) {
+		obj, err := obsi.objectStore.Get(obsi.bucket, key)
+
+		return obj.Content, err
+	})
+	if err != nil {
+		klog.Errorf("Failed to select the objects in bucket %v, err: %v", obsi.bucket, err)
+		obsi.successful = false
+		metrics.LocalDeploymentFailedPullTime.
+			WithLabelValues(obsi.SubscriberItem.Subscription.Namespace, obsi.SubscriberItem.Subscription.Name).
+			Observe(0)
+
+		return
+	}
+
+	utils.UpdateFetchStatus(obsi.synchronizer.GetLocalClient(), obsi.Subscription, chnv1.ChannelTypeObjectBucket, "", obsi.reconcileRate)
+
+	tpls := []bucketTemplate{}
+	lockInputs := map[string]string{}
 
 	// converting template from obeject store to DPL
-	for _, key := range keys {
+	for _, object := range objects {
+		key := object.Key
+
 		tplb, err := obsi.objectStore.Get(obsi.bucket, key)
 		if err != nil {
 			klog.Error("Failed to get object ", key, " in bucket ", obsi.bucket)
@@ -349,6 +379,36 @@ func (obsi *SubscriberItem) doSubscription() {
 			continue
 		}
 
+		// the objects are locked by their deployable version
+		if tplb.Version != "" {
+			lockInputs[utils.LockObjectInput(key, tplb.Version)] = utils.LockObjectContent(tplb.ETag, tplb.Content)
+		}
+
+		if utils.IsBucketArchive(key) {
+			archiveTpls, chartName, err := utils.ExpandBucketArchive(obsi.Subscription, key, tplb.Content)
+			if err != nil {
+				klog.Error("Failed to expand ", obsi.bucket, "/", key, " err:", err)
+				obsi.successful = false
+				metrics.LocalDeploymentFailedPullTime.
+					WithLabelValues(obsi.SubscriberItem.Subscription.Namespace, obsi.SubscriberItem.Subscription.Name).
+					Observe(0)
+
+				return
+			}
+
+			// the resources of a chart archive are filtered on the chart name
+			pkgName := object.Package
+			if pkgName == "" {
+				pkgName = chartName
+			}
+
+			for _, tpl := range archiveTpls {
+				tpls = append(tpls, bucketTemplate{template: *tpl, pkgName: pkgName})
+			}
+
+			continue
+		}
+
 		tpl := &unstructured.Unstructured{}
 		err = yaml.Unmarshal(tplb.Content, tpl)
 
@@ -362,7 +422,26 @@ func (obsi *SubscriberItem) doSubscription() {
 			return
 		}
 
-		tpls = append(tpls, *tpl)
+		tpls = append(tpls, bucketTemplate{template: *tpl, pkgName: object.Package})
+	}
+
+	// a locked subscription refuses an object version or an image tag moved to another content
+	if utils.IsSubscriptionLocked(obsi.Subscription) {
+		for _, tpl := range tpls {
+			utils.AddImageLockInputs(lockInputs, tpl.template.Object)
+		}
+	}
+
+	lockErr := utils.CheckSubscriptionLock(obsi.synchronizer.GetLocalClient(), obsi.Subscription, lockInputs)
+
+	utils.SetLockMismatchCondition(obsi.synchronizer.GetLocalClient(), obsi.Subscription, lockErr)
+
+	if lockErr != nil {
+		klog.Errorf("refuse to deploy the objects of bucket %v, err: %v", obsi.bucket, lockErr)
+
+		obsi.successful = false
+
+		return
 	}
 
 	resources := make([]kubesynchronizer.ResourceUnit, 0)
@@ -372,7 +451,7 @@ func (obsi *SubscriberItem) doSubscription() {
 
 	for _, tpl := range tpls {
 		tpl := tpl
-		resource, err := obsi.doSubscribeManifest(&tpl) // this is now the address of the inner tpl
+		resource, err := obsi.doSubscribeManifest(&tpl.template, tpl.pkgName) // this is now the address of the inner tpl
 
 		if err != nil {
 			klog.Errorf("object bucket failed to package deployable, err: %v", err)
@@ -404,52 +483,29 @@ func (obsi *SubscriberItem) doSubscription() {
 	obsi.successful = true
 }
 
-func (obsi *SubscriberItem) doSubscribeManifest(template *unstructured.Unstructured) (*kubesynchronizer.ResourceUnit, error) {
+// bucketTemplate is a template of the bucket with the package it belongs to in the folders layout
+type bucketTemplate struct {
+	template unstructured.Unstructured
+	pkgName  string
+}
+
+func (obsi *SubscriberItem) doSubscribeManifest(template *unstructured.Unstructured, pkgName string) (*kubesynchronizer.ResourceUnit, error) {
 	tplName := template.GetName()
 	// Set app label
 	utils.SetPartOfLabel(obsi.SubscriberItem.Subscription, template)
 
-	if obsi.Subscription.Spec.PackageFilter != nil {
-		if obsi.Subscription.Spec.Package != "" && obsi.Subscription.Spec.Package != tplName {
-			errmsg := "Name does not match, skiping:" + obsi.Subscription.Spec.Package + "|" + tplName
-			klog.Info(errmsg)
-
-			return nil, errors.New(errmsg)
-		}
+	// in the folders layout, the package name and name regex are checked on the package folder
+	filterName := tplName
+	if pkgName != "" {
+		filterName = pkgName
+	}
 
-		if !utils.LabelChecker(obsi.Subscription.Spec.PackageFilter.LabelSelector, template.GetLabels()) {
-			errmsg := "Failed to pass label check to deployable " + tplName
+	if obsi.Subscription.Spec.PackageFilter != nil || pkgName != "" {
+		if errmsg := utils.CheckPackageFilter(obsi.Subscription, filterName, template.GetLabels(), template.GetAnnotations()); errmsg != "" {
 			klog.Info(errmsg)
 
 			return nil, errors.New(errmsg)
 		}
-
-		annotations := obsi.Subscription.Spec.PackageFilter.Annotations
-		if annotations != nil {
-			dplanno := template.GetAnnotations()
-			if dplanno == nil {
-				dplanno = make(map[string]string)
-			}
-
-			matched := true
-
-			for k, v := range annotations {
-				if dplanno[k] != v {
-					klog.Info("Annotation filter does not match:", k, "|", v, "|", dplanno[k])
-
-					matched = false
-
-					break
-				}
-			}
-
-			if !matched {
-				errmsg := "Failed to pass annotation check to deployable " + tplName
-				klog.Info(errmsg)
-
-				return nil, errors.New(errmsg)
-			}
-		}
 	}
 
 	template, err := utils.OverrideResourceBySubscription(template, tplName, obsi.Subscription)
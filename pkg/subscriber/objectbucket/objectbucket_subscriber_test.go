@@ -214,7 +214,7 @@ func TestObjectSubscriber(t *testing.T) {
 
 	// If cluster admin, the original object namespace is respected
 	obssubitem.clusterAdmin = true
-	resource, err := obssubitem.doSubscribeManifest(objTemplate)
+	resource, err := obssubitem.doSubscribeManifest(objTemplate, "")
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 
 	objNamespace := objConfigMap.GetNamespace()
@@ -223,7 +223,7 @@ func TestObjectSubscriber(t *testing.T) {
 
 	// If not cluster admin, the original object namespace is replaced into the appsub namespace
 	obssubitem.clusterAdmin = false
-	resource, err = obssubitem.doSubscribeManifest(objTemplate)
+	resource, err = obssubitem.doSubscribeManifest(objTemplate, "")
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 
 	appsubNamespace := objSub.GetNamespace()
@@ -18,11 +18,17 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/subscriber/backend"
 )
 
 // AddToManagerFuncs is a list of functions to add all Controllers to the Manager
 var AddToManagerFuncs []func(manager.Manager, *rest.Config, *types.NamespacedName, int, bool, bool) error
 
+// ChannelSubscriberFuncs maps the channel types of the built-in subscribers to the functions returning their subscriber
+var ChannelSubscriberFuncs = map[string]func() appv1.Subscriber{}
+
 // AddToManager adds all Controllers to the Manager
 func AddToManager(m manager.Manager, hubconfig *rest.Config, syncid *types.NamespacedName, syncinterval int, hub, standalone bool) error {
 	for _, f := range AddToManagerFuncs {
@@ -33,3 +39,21 @@ func AddToManager(m manager.Manager, hubconfig *rest.Config, syncid *types.Names
 
 	return nil
 }
+
+// GetChannelSubscribers returns the subscribers of the channel types, the built-in subscribers and the subscriber of
+// the registered channel backends
+func GetChannelSubscribers() map[string]appv1.Subscriber {
+	subs := make(map[string]appv1.Subscriber)
+
+	for chType, f := range ChannelSubscriberFuncs {
+		subs[chType] = f()
+	}
+
+	if bs := backend.GetDefaultSubscriber(); bs != nil {
+		for _, chType := range backend.ChannelTypes() {
+			subs[chType] = bs
+		}
+	}
+
+	return subs
+}
@@ -0,0 +1,522 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webdav is the channel backend of the WebDAV servers and of the generic repositories of the artifact servers,
+// like the Nexus raw and the Artifactory generic repositories.
+package webdav
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/subscriber/backend"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils/tlsconfig"
+)
+
+const (
+	// ChannelTypeWebDAV is the channel type of the WebDAV servers and of the generic repositories of the artifact
+	// servers. The channel pathname is the URL of the directory of the manifests.
+	ChannelTypeWebDAV = "webdav"
+
+	// SecretKeyUser is the key of the basic auth user in the channel secret, with the password or accessToken key
+	SecretKeyUser = "user"
+	// SecretKeyPassword is the key of the basic auth password in the channel secret
+	SecretKeyPassword = "password"
+	// SecretKeyAccessToken is the key of the basic auth API key of the user in the channel secret
+	SecretKeyAccessToken = "accessToken"
+	// SecretKeyToken is the key of the bearer token in the channel secret
+	SecretKeyToken = "token"
+
+	// ConfigMapKeyCACerts is key of the PEM CA certificates of the server in the channel configmap
+	ConfigMapKeyCACerts = "caCerts"
+
+	// the checksums of the files fetched, a hidden file skipped by the renderer
+	checksumsFile = ".webdav-checksums.json"
+
+	// the depth of the sub-directories listed under the channel directory
+	maxListDepth = 10
+)
+
+// the extensions of the checksum files published next to the files, by order of preference
+var checksumExtensions = []string{".sha256", ".sha1", ".md5"}
+
+var hrefPattern = regexp.MustCompile(`(?i)href\s*=\s*["']([^"'#?]+)["']`)
+
+// Fetcher fetches the files of a WebDAV or artifact server directory. The changes are detected with the checksum
+// files published next to the files, or with the ETags of the WebDAV listing, so only the files that changed are
+// downloaded again.
+type Fetcher struct {
+	// Timeout is the timeout of the requests to the server, 60 seconds when not set
+	Timeout time.Duration
+}
+
+func init() {
+	backend.MustRegister(ChannelTypeWebDAV, Fetcher{}, nil)
+}
+
+// remoteFile is a file of the listing of the channel directory
+type remoteFile struct {
+	url  *url.URL
+	etag string
+}
+
+type client struct {
+	httpClient *http.Client
+	secret     *corev1.Secret
+}
+
+// Fetch fetches the files of the channel directory, selected by the git-path annotation of the subscription, and
+// returns the digest of their checksums as the revision
+func (f Fetcher) Fetch(ctx context.Context, req *backend.FetchRequest) (string, error) {
+	root, err := channelDirURL(req.Channel.Spec.Pathname, req.Subscription)
+	if err != nil {
+		return "", err
+	}
+
+	httpClient, err := f.newHTTPClient(req)
+	if err != nil {
+		return "", err
+	}
+
+	c := &client{httpClient: httpClient, secret: req.ChannelSecret}
+
+	files := map[string]remoteFile{}
+	if err := c.list(ctx, root, root, files, 0); err != nil {
+		return "", err
+	}
+
+	previous := readChecksums(req.Dir)
+	checksums := map[string]string{}
+
+	for rel, file := range files {
+		if isChecksumFile(rel, files) {
+			continue
+		}
+
+		sum, err := c.checksum(ctx, rel, file, files)
+		if err != nil {
+			return "", err
+		}
+
+		local := filepath.Join(req.Dir, filepath.FromSlash(rel))
+
+		if _, statErr := os.Stat(local); sum != "" && previous[rel] == sum && statErr == nil {
+			checksums[rel] = sum
+
+			continue
+		}
+
+		content, err := c.get(ctx, file.url)
+		if err != nil {
+			return "", err
+		}
+
+		if sum == "" {
+			digest := sha256.Sum256(content)
+			sum = "sha256:" + hex.EncodeToString(digest[:])
+		}
+
+		if err := os.MkdirAll(filepath.Dir(local), 0700); err != nil {
+			return "", err
+		}
+
+		if err := os.WriteFile(local, content, 0600); err != nil {
+			return "", err
+		}
+
+		checksums[rel] = sum
+	}
+
+	if err := removeStaleFiles(req.Dir, checksums); err != nil {
+		return "", err
+	}
+
+	if err := writeChecksums(req.Dir, checksums); err != nil {
+		return "", err
+	}
+
+	klog.Infof("Fetched %v files from %v", len(checksums), root.Redacted())
+
+	return revision(checksums), nil
+}
+
+// channelDirURL returns the URL of the channel directory, with the sub-directory of the subscription
+func channelDirURL(pathname string, sub *appv1.Subscription) (*url.URL, error) {
+	root, err := url.Parse(strings.TrimSpace(pathname))
+	if err != nil {
+		return nil, fmt.Errorf("invalid webdav channel pathname %v, err: %w", pathname, err)
+	}
+
+	if root.Scheme != "http" && root.Scheme != "https" {
+		return nil, fmt.Errorf("invalid webdav channel pathname %v, the URL scheme must be http or https", pathname)
+	}
+
+	subPath := strings.Trim(sub.GetAnnotations()[appv1.AnnotationGitPath], "/")
+	if subPath != "" {
+		if strings.Contains("/"+subPath+"/", "/../") {
+			return nil, fmt.Errorf("invalid path %v of subscription %v/%v", subPath, sub.Namespace, sub.Name)
+		}
+
+		root.Path = strings.TrimSuffix(root.Path, "/") + "/" + subPath
+	}
+
+	if !strings.HasSuffix(root.Path, "/") {
+		root.Path += "/"
+	}
+
+	root.RawPath = ""
+
+	return root, nil
+}
+
+func (f Fetcher) newHTTPClient(req *backend.FetchRequest) (*http.Client, error) {
+	tlsConfig := tlsconfig.NewConfig(tls.VersionTLS12)
+	tlsConfig.InsecureSkipVerify = req.Channel.Spec.InsecureSkipVerify // #nosec G402 InsecureSkipVerify optionally
+
+	if req.ChannelConfigMap != nil && req.ChannelConfigMap.Data[ConfigMapKeyCACerts] != "" && !tlsConfig.InsecureSkipVerify {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM([]byte(req.ChannelConfigMap.Data[ConfigMapKeyCACerts])) {
+			return nil, fmt.Errorf("failed to parse the CA certificates of configmap %v", req.ChannelConfigMap.Name)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	timeout := f.Timeout
+	if timeout == 0 {
+		timeout = 60 * time.Second
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		},
+	}, nil
+}
+
+// do sends a request with the basic auth or the bearer token of the channel secret
+func (c *client) do(req *http.Request) (*http.Response, error) {
+	if c.secret != nil {
+		user := string(c.secret.Data[SecretKeyUser])
+
+		password := string(c.secret.Data[SecretKeyPassword])
+		if password == "" {
+			password = string(c.secret.Data[SecretKeyAccessToken])
+		}
+
+		if token := strings.TrimSpace(string(c.secret.Data[SecretKeyToken])); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		} else if user != "" {
+			req.SetBasicAuth(user, password)
+		}
+	}
+
+	return c.httpClient.Do(req)
+}
+
+func (c *client) get(ctx context.Context, u *url.URL) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get %v, status: %v", u.Redacted(), resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// the PROPFIND multistatus response of a directory
+type multistatus struct {
+	Responses []struct {
+		Href     string `xml:"href"`
+		Propstat []struct {
+			Prop struct {
+				ResourceType struct {
+					Collection *struct{} `xml:"collection"`
+				} `xml:"resourcetype"`
+				ETag string `xml:"getetag"`
+			} `xml:"prop"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+const propfindBody = `<?xml version="1.0" encoding="utf-8"?>
+<propfind xmlns="DAV:"><prop><resourcetype/><getetag/></prop></propfind>`
+
+// list adds the files of a directory and of its sub-directories to files, by path relative to the channel
+// directory. The directory is listed with PROPFIND, or from the links of its HTML page when the server isn't a
+// WebDAV server.
+func (c *client) list(ctx context.Context, root, dir *url.URL, files map[string]remoteFile, depth int) error {
+	if depth > maxListDepth {
+		return fmt.Errorf("the directories under %v are nested deeper than %v levels", root.Redacted(), maxListDepth)
+	}
+
+	entries, err := c.propfind(ctx, dir)
+	if errors.Is(err, errNotWebDAV) {
+		entries, err = c.htmlListing(ctx, dir)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		// only the entries under the directory are listed, not the directory itself or its parents
+		if !strings.HasPrefix(entry.url.Path, dir.Path) || len(entry.url.Path) <= len(dir.Path) ||
+			entry.url.Host != dir.Host {
+			continue
+		}
+
+		rel := strings.TrimPrefix(entry.url.Path, root.Path)
+		if strings.Contains("/"+rel, "/../") || strings.Contains("/"+rel, "/./") {
+			continue
+		}
+
+		if strings.HasSuffix(entry.url.Path, "/") {
+			if err := c.list(ctx, root, entry.url, files, depth+1); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		files[rel] = remoteFile{url: entry.url, etag: entry.etag}
+	}
+
+	return nil
+}
+
+var errNotWebDAV = errors.New("not a webdav server")
+
+type listEntry struct {
+	url  *url.URL
+	etag string
+}
+
+func (c *client) propfind(ctx context.Context, dir *url.URL) ([]listEntry, error) {
+	req, err := http.NewRequestWithContext(ctx, "PROPFIND", dir.String(), strings.NewReader(propfindBody))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Depth", "1")
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusMultiStatus:
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented, http.StatusBadRequest, http.StatusForbidden:
+		return nil, errNotWebDAV
+	default:
+		return nil, fmt.Errorf("failed to list %v, status: %v", dir.Redacted(), resp.Status)
+	}
+
+	ms := &multistatus{}
+	if err := xml.NewDecoder(resp.Body).Decode(ms); err != nil {
+		return nil, fmt.Errorf("failed to parse the listing of %v, err: %w", dir.Redacted(), err)
+	}
+
+	entries := []listEntry{}
+
+	for _, r := range ms.Responses {
+		u, err := dir.Parse(strings.TrimSpace(r.Href))
+		if err != nil {
+			continue
+		}
+
+		entry := listEntry{url: u}
+
+		for _, ps := range r.Propstat {
+			if ps.Prop.ResourceType.Collection != nil && !strings.HasSuffix(u.Path, "/") {
+				u.Path += "/"
+			}
+
+			if ps.Prop.ETag != "" {
+				entry.etag = ps.Prop.ETag
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func (c *client) htmlListing(ctx context.Context, dir *url.URL) ([]listEntry, error) {
+	page, err := c.get(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []listEntry{}
+
+	for _, m := range hrefPattern.FindAllStringSubmatch(string(page), -1) {
+		u, err := dir.Parse(m[1])
+		if err != nil {
+			continue
+		}
+
+		entries = append(entries, listEntry{url: u})
+	}
+
+	return entries, nil
+}
+
+// isChecksumFile checks if the file is the checksum file of another listed file
+func isChecksumFile(rel string, files map[string]remoteFile) bool {
+	for _, ext := range checksumExtensions {
+		if strings.HasSuffix(rel, ext) {
+			if _, ok := files[strings.TrimSuffix(rel, ext)]; ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// checksum returns the checksum of a file from its checksum file, or its ETag. It is empty when the server
+// publishes neither.
+func (c *client) checksum(ctx context.Context, rel string, file remoteFile, files map[string]remoteFile) (string, error) {
+	for _, ext := range checksumExtensions {
+		sumFile, ok := files[rel+ext]
+		if !ok {
+			continue
+		}
+
+		content, err := c.get(ctx, sumFile.url)
+		if err != nil {
+			return "", err
+		}
+
+		// the checksum files hold the hex digest, optionally followed by the file name
+		fields := strings.Fields(string(content))
+		if len(fields) == 0 {
+			return "", fmt.Errorf("empty checksum file %v", sumFile.url.Redacted())
+		}
+
+		return strings.TrimPrefix(ext, ".") + ":" + strings.ToLower(fields[0]), nil
+	}
+
+	if file.etag != "" {
+		return "etag:" + file.etag, nil
+	}
+
+	return "", nil
+}
+
+func readChecksums(dir string) map[string]string {
+	checksums := map[string]string{}
+
+	content, err := os.ReadFile(filepath.Clean(filepath.Join(dir, checksumsFile)))
+	if err != nil {
+		return checksums
+	}
+
+	if err := json.Unmarshal(content, &checksums); err != nil {
+		klog.Warningf("failed to read the checksums of the files fetched to %v, err: %v", dir, err)
+
+		return map[string]string{}
+	}
+
+	return checksums
+}
+
+func writeChecksums(dir string, checksums map[string]string) error {
+	content, err := json.Marshal(checksums)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, checksumsFile), content, 0600)
+}
+
+// removeStaleFiles deletes the files fetched before that aren't in the channel directory anymore
+func removeStaleFiles(dir string, checksums map[string]string) error {
+	return filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := checksums[filepath.ToSlash(rel)]; ok || rel == checksumsFile {
+			return nil
+		}
+
+		return os.Remove(p)
+	})
+}
+
+// revision returns the digest of the checksums of the files
+func revision(checksums map[string]string) string {
+	paths := make([]string, 0, len(checksums))
+	for p := range checksums {
+		paths = append(paths, p)
+	}
+
+	sort.Strings(paths)
+
+	h := sha256.New()
+	for _, p := range paths {
+		fmt.Fprintf(h, "%v %v\n", path.Clean(p), checksums[p])
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
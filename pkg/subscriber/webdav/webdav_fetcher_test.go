@@ -0,0 +1,222 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webdav
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/webdav"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/subscriber/backend"
+)
+
+const configMapManifest = "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: %v\n"
+
+func TestWebDAVFetcher(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ctx := context.TODO()
+
+	b, ok := backend.Lookup("WebDAV")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(b.Fetcher).To(Equal(Fetcher{}))
+
+	fs := webdav.NewMemFS()
+	g.Expect(fs.Mkdir(ctx, "/apps", 0700)).To(Succeed())
+	g.Expect(fs.Mkdir(ctx, "/apps/demo", 0700)).To(Succeed())
+	g.Expect(fs.Mkdir(ctx, "/apps/demo/base", 0700)).To(Succeed())
+
+	writeFile := func(name, content string) {
+		f, err := fs.OpenFile(ctx, name, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		_, err = f.Write([]byte(content))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(f.Close()).To(Succeed())
+	}
+
+	writeFile("/apps/demo/base/app.yaml", fmt.Sprintf(configMapManifest, "app"))
+	writeFile("/apps/demo/db.yaml", fmt.Sprintf(configMapManifest, "db"))
+	writeFile("/apps/other.yaml", fmt.Sprintf(configMapManifest, "other"))
+
+	gets := map[string]int{}
+	dav := &webdav.Handler{FileSystem: fs, LockSystem: webdav.NewMemLS()}
+
+	// the WebDAV server requires the bearer token
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cr3t" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		if r.Method == http.MethodGet {
+			gets[r.URL.Path]++
+		}
+
+		dav.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	req := &backend.FetchRequest{
+		Subscription: &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns",
+			Annotations: map[string]string{appv1.AnnotationGitPath: "demo"}}},
+		Channel: &chnv1.Channel{ObjectMeta: metav1.ObjectMeta{Name: "dav"},
+			Spec: chnv1.ChannelSpec{Type: "WebDAV", Pathname: server.URL + "/apps"}},
+		Dir: t.TempDir(),
+	}
+
+	_, err := Fetcher{}.Fetch(ctx, req)
+	g.Expect(err).To(HaveOccurred())
+
+	req.ChannelSecret = &corev1.Secret{Data: map[string][]byte{SecretKeyToken: []byte("s3cr3t")}}
+
+	// the files of the subscription path are fetched
+	rev1, err := Fetcher{}.Fetch(ctx, req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rev1).NotTo(BeEmpty())
+
+	content, err := os.ReadFile(filepath.Join(req.Dir, "base", "app.yaml"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(content)).To(Equal(fmt.Sprintf(configMapManifest, "app")))
+	g.Expect(filepath.Join(req.Dir, "db.yaml")).To(BeARegularFile())
+	g.Expect(filepath.Join(req.Dir, "other.yaml")).NotTo(BeAnExistingFile())
+
+	tpls, err := backend.ManifestRenderer{}.Render(ctx, req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tpls).To(HaveLen(2))
+
+	// the files are only downloaded again when their ETag changes
+	rev2, err := Fetcher{}.Fetch(ctx, req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rev2).To(Equal(rev1))
+	g.Expect(gets["/apps/demo/db.yaml"]).To(Equal(1))
+
+	writeFile("/apps/demo/db.yaml", fmt.Sprintf(configMapManifest, "database"))
+	g.Expect(fs.RemoveAll(ctx, "/apps/demo/base")).To(Succeed())
+
+	rev3, err := Fetcher{}.Fetch(ctx, req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rev3).NotTo(Equal(rev1))
+	g.Expect(gets["/apps/demo/db.yaml"]).To(Equal(2))
+	g.Expect(filepath.Join(req.Dir, "base", "app.yaml")).NotTo(BeAnExistingFile())
+}
+
+func TestArtifactServerFetcher(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ctx := context.TODO()
+
+	files := map[string]string{
+		"/repository/raw/demo/app.yaml":        fmt.Sprintf(configMapManifest, "app"),
+		"/repository/raw/demo/app.yaml.sha256": "aaaa  app.yaml\n",
+		"/repository/raw/demo/tools/job.yml":   fmt.Sprintf(configMapManifest, "job"),
+	}
+
+	listings := map[string][]string{
+		"/repository/raw/demo/":       {"../", "app.yaml", "app.yaml.sha256", "tools/", "https://elsewhere.example.com/x.yaml"},
+		"/repository/raw/demo/tools/": {"../", "/repository/raw/demo/tools/job.yml"},
+	}
+
+	gets := map[string]int{}
+
+	// the generic repository isn't a WebDAV server, it lists the directories in HTML pages, with basic auth
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "deployer" || password != "api-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		gets[r.URL.Path]++
+
+		if links, ok := listings[r.URL.Path]; ok {
+			page := []string{"<html><body><a href=\"?sort=name\">Name</a>"}
+			for _, l := range links {
+				page = append(page, fmt.Sprintf("<a href=\"%v\">%v</a>", l, l))
+			}
+
+			_, _ = w.Write([]byte(strings.Join(page, "\n") + "</body></html>"))
+
+			return
+		}
+
+		if content, ok := files[r.URL.Path]; ok {
+			_, _ = w.Write([]byte(content))
+
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	req := &backend.FetchRequest{
+		Subscription:  &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns"}},
+		Channel:       &chnv1.Channel{Spec: chnv1.ChannelSpec{Type: "WebDAV", Pathname: server.URL + "/repository/raw/demo/"}},
+		ChannelSecret: &corev1.Secret{Data: map[string][]byte{SecretKeyUser: []byte("deployer"), SecretKeyAccessToken: []byte("api-key")}},
+		Dir:           t.TempDir(),
+	}
+
+	rev1, err := Fetcher{}.Fetch(ctx, req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(filepath.Join(req.Dir, "app.yaml")).To(BeARegularFile())
+	g.Expect(filepath.Join(req.Dir, "app.yaml.sha256")).NotTo(BeAnExistingFile())
+	g.Expect(filepath.Join(req.Dir, "tools", "job.yml")).To(BeARegularFile())
+	g.Expect(readChecksums(req.Dir)).To(HaveKeyWithValue("app.yaml", "sha256:aaaa"))
+
+	// the file with a checksum file is only downloaded again when its checksum changes, the other files every time
+	rev2, err := Fetcher{}.Fetch(ctx, req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rev2).To(Equal(rev1))
+	g.Expect(gets["/repository/raw/demo/app.yaml"]).To(Equal(1))
+	g.Expect(gets["/repository/raw/demo/tools/job.yml"]).To(Equal(2))
+
+	files["/repository/raw/demo/app.yaml.sha256"] = "BBBB\n"
+
+	rev3, err := Fetcher{}.Fetch(ctx, req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rev3).NotTo(Equal(rev1))
+	g.Expect(gets["/repository/raw/demo/app.yaml"]).To(Equal(2))
+	g.Expect(readChecksums(req.Dir)).To(HaveKeyWithValue("app.yaml", "sha256:bbbb"))
+
+	// the channel pathname must be an http URL, the subscription path can't leave the channel directory
+	req.Channel.Spec.Pathname = "ftp://example.com/raw"
+	_, err = Fetcher{}.Fetch(ctx, req)
+	g.Expect(err).To(HaveOccurred())
+
+	req.Channel.Spec.Pathname = server.URL + "/repository/raw/demo/"
+	req.Subscription.SetAnnotations(map[string]string{appv1.AnnotationGitPath: "../secrets"})
+	_, err = Fetcher{}.Fetch(ctx, req)
+	g.Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,115 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	appv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+// the field managers of the other GitOps controllers in the managedFields of the resources they apply
+var foreignFieldManagers = map[string]string{
+	"helm":                          "Helm",
+	"argocd-controller":             "Argo CD",
+	"argocd-application-controller": "Argo CD",
+	"kustomize-controller":          "Flux",
+	"helm-controller":               "Flux",
+}
+
+// foreignController returns the other GitOps controller managing the resource, from its labels, annotations and
+// managedFields. It is empty if the resource isn't managed by Helm, Argo CD or Flux. The labels and annotations also
+// set by the template, like in the helm charts rendered in the Git repos, are not markers of the other controllers
+func foreignController(obj, tplunit *unstructured.Unstructured) string {
+	label := func(key string) string {
+		if v := obj.GetLabels()[key]; v != tplunit.GetLabels()[key] {
+			return v
+		}
+
+		return ""
+	}
+
+	annotation := func(key string) string {
+		if v := obj.GetAnnotations()[key]; v != tplunit.GetAnnotations()[key] {
+			return v
+		}
+
+		return ""
+	}
+
+	switch {
+	case annotation("meta.helm.sh/release-name") != "":
+		return fmt.Sprintf("Helm release %v/%v", obj.GetAnnotations()["meta.helm.sh/release-namespace"],
+			annotation("meta.helm.sh/release-name"))
+	case strings.EqualFold(label("app.kubernetes.io/managed-by"), "Helm"):
+		return "Helm"
+	case annotation("argocd.argoproj.io/tracking-id") != "":
+		return "Argo CD application " + strings.SplitN(annotation("argocd.argoproj.io/tracking-id"), ":", 2)[0]
+	case label("argocd.argoproj.io/instance") != "":
+		return "Argo CD application " + label("argocd.argoproj.io/instance")
+	case label("kustomize.toolkit.fluxcd.io/name") != "":
+		return fmt.Sprintf("Flux Kustomization %v/%v", obj.GetLabels()["kustomize.toolkit.fluxcd.io/namespace"],
+			label("kustomize.toolkit.fluxcd.io/name"))
+	case label("helm.toolkit.fluxcd.io/name") != "":
+		return fmt.Sprintf("Flux HelmRelease %v/%v", obj.GetLabels()["helm.toolkit.fluxcd.io/namespace"],
+			label("helm.toolkit.fluxcd.io/name"))
+	}
+
+	for _, field := range obj.GetManagedFields() {
+		if controller, ok := foreignFieldManagers[field.Manager]; ok {
+			return controller
+		}
+	}
+
+	return ""
+}
+
+// checkAdoption refuses to update an existing resource managed by another GitOps controller, so the appsub and the
+// controller don't fight over the resource. The resources deployed by the appsub are never refused. The appsub or the
+// resource adopts the resource with the allow-adoption annotation
+func (sync *KubeSynchronizer) checkAdoption(origUnit, tplunit *unstructured.Unstructured) error {
+	tplown := sync.Extension.GetHostFromObject(tplunit)
+	if tplown != nil && sync.Extension.IsObjectOwnedByHost(origUnit, *tplown, sync.SynchronizerID) {
+		return nil
+	}
+
+	controller := foreignController(origUnit, tplunit)
+	if controller == "" {
+		return nil
+	}
+
+	allowed := strings.EqualFold(tplunit.GetAnnotations()[appv1alpha1.AnnotationAllowAdoption], "true")
+
+	if !allowed && tplown != nil {
+		appsub := &appv1alpha1.Subscription{}
+		if err := sync.LocalClient.Get(context.TODO(), *tplown, appsub); err == nil {
+			allowed = strings.EqualFold(appsub.GetAnnotations()[appv1alpha1.AnnotationAllowAdoption], "true")
+		}
+	}
+
+	if allowed {
+		klog.Infof("adopting %v %v/%v managed by %v", tplunit.GetKind(), tplunit.GetNamespace(), tplunit.GetName(), controller)
+
+		return nil
+	}
+
+	return fmt.Errorf("%v %v/%v is managed by %v, set the %v: \"true\" annotation to adopt it", tplunit.GetKind(),
+		tplunit.GetNamespace(), tplunit.GetName(), controller, appv1alpha1.AnnotationAllowAdoption)
+}
@@ -0,0 +1,150 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func TestAdoption(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(appv1.SchemeBuilder.AddToScheme(scheme)).To(Succeed())
+
+	appsub := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns"}}
+
+	configMap := func(labels, annotations map[string]string, value string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "app-config", "namespace": "demo-ns"},
+			"data":       map[string]interface{}{"key": value},
+		}}
+		obj.SetLabels(labels)
+		obj.SetAnnotations(annotations)
+
+		return obj
+	}
+
+	hosted := func(annotations map[string]string) map[string]string {
+		an := map[string]string{appv1.AnnotationHosting: "demo-ns/demo"}
+		for k, v := range annotations {
+			an[k] = v
+		}
+
+		return an
+	}
+
+	helmLabels := map[string]string{"app.kubernetes.io/managed-by": "Helm"}
+	helmRelease := map[string]string{"meta.helm.sh/release-name": "db", "meta.helm.sh/release-namespace": "demo-ns"}
+
+	// the other GitOps controllers
+	g.Expect(foreignController(configMap(helmLabels, nil, ""), configMap(nil, nil, ""))).To(Equal("Helm"))
+	g.Expect(foreignController(configMap(nil, helmRelease, ""), configMap(nil, nil, ""))).To(Equal("Helm release demo-ns/db"))
+	g.Expect(foreignController(configMap(nil, map[string]string{
+		"argocd.argoproj.io/tracking-id": "guestbook:/ConfigMap:demo-ns/app-config"}, ""),
+		configMap(nil, nil, ""))).To(Equal("Argo CD application guestbook"))
+	g.Expect(foreignController(configMap(map[string]string{
+		"kustomize.toolkit.fluxcd.io/name": "apps", "kustomize.toolkit.fluxcd.io/namespace": "flux-system"}, nil, ""),
+		configMap(nil, nil, ""))).To(Equal("Flux Kustomization flux-system/apps"))
+
+	managed := configMap(nil, nil, "")
+	managed.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "kubectl-client-side-apply"}, {Manager: "helm-controller"}})
+	g.Expect(foreignController(managed, configMap(nil, nil, ""))).To(Equal("Flux"))
+
+	// the labels of the template, like the helm charts rendered in the repo, and the kubectl changes are not markers
+	g.Expect(foreignController(configMap(helmLabels, nil, ""), configMap(helmLabels, nil, ""))).To(BeEmpty())
+
+	managed.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "kubectl-client-side-apply"}})
+	g.Expect(foreignController(managed, configMap(nil, nil, ""))).To(BeEmpty())
+
+	cmGVR := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{cmGVR: "ConfigMapList"}, configMap(helmLabels, helmRelease, "helm"))
+
+	s := &KubeSynchronizer{
+		DynamicClient:  dynamicClient,
+		LocalClient:    fake.NewClientBuilder().WithScheme(scheme).WithObjects(appsub).Build(),
+		Extension:      &SubscriptionExtension{},
+		SynchronizerID: &types.NamespacedName{Name: "cluster1"},
+	}
+
+	apply := func(tplunit *unstructured.Unstructured) error {
+		_, err := s.applyTemplate(dynamicClient.Resource(cmGVR), true, ResourceUnit{Resource: tplunit}, false, nil, nil,
+			true, nil)
+
+		return err
+	}
+
+	live := func() *unstructured.Unstructured {
+		obj, err := dynamicClient.Resource(cmGVR).Namespace("demo-ns").Get(context.TODO(), "app-config", metav1.GetOptions{})
+		g.Expect(err).NotTo(HaveOccurred())
+
+		return obj
+	}
+
+	// the resource managed by Helm is refused, even with a reconcile option
+	err := apply(configMap(nil, hosted(map[string]string{
+		appv1.AnnotationClusterAdmin:            "true",
+		appv1.AnnotationResourceReconcileOption: appv1.MergeReconcile,
+	}), "appsub"))
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("ConfigMap demo-ns/app-config is managed by Helm release demo-ns/db"))
+	g.Expect(live().Object["data"]).To(Equal(map[string]interface{}{"key": "helm"}))
+
+	// the resource adopts it
+	g.Expect(apply(configMap(nil, hosted(map[string]string{
+		appv1.AnnotationClusterAdmin:            "true",
+		appv1.AnnotationResourceReconcileOption: appv1.MergeAndOwnReconcile,
+		appv1.AnnotationAllowAdoption:           "true",
+	}), "appsub"))).To(Succeed())
+	g.Expect(live().Object["data"]).To(Equal(map[string]interface{}{"key": "appsub"}))
+
+	// the resource owned by the appsub is updated, whatever its labels
+	g.Expect(apply(configMap(nil, hosted(nil), "owned"))).To(Succeed())
+	g.Expect(live().Object["data"]).To(Equal(map[string]interface{}{"key": "owned"}))
+
+	// the appsub adopts all its resources
+	orphan := live()
+	orphan.SetAnnotations(helmRelease)
+	_, err = dynamicClient.Resource(cmGVR).Namespace("demo-ns").Update(context.TODO(), orphan, metav1.UpdateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	tplunit := configMap(nil, hosted(map[string]string{
+		appv1.AnnotationClusterAdmin:            "true",
+		appv1.AnnotationResourceReconcileOption: appv1.MergeAndOwnReconcile,
+	}), "adopted")
+	g.Expect(apply(tplunit)).NotTo(Succeed())
+
+	appsub.SetAnnotations(map[string]string{appv1.AnnotationAllowAdoption: "true"})
+	g.Expect(s.LocalClient.Update(context.TODO(), appsub, &client.UpdateOptions{})).To(Succeed())
+
+	g.Expect(apply(tplunit)).To(Succeed())
+	g.Expect(live().Object["data"]).To(Equal(map[string]interface{}{"key": "adopted"}))
+}
@@ -0,0 +1,108 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+
+	appv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+// applyBatch applies the consecutive resources of the same kind of an appsub in parallel, up to the apply concurrency
+// of the controller config. The kinds are applied one after the other, so the namespaces and the CRDs sorted first
+// exist before the resources using them
+type applyBatch struct {
+	// the lock protects the statuses, the created namespaces and the images set by the resources applied in parallel
+	sync.Mutex
+
+	concurrency int
+	kind        schema.GroupVersionKind
+	applies     []func()
+}
+
+func newApplyBatch(concurrency int) *applyBatch {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	return &applyBatch{concurrency: concurrency}
+}
+
+// applyConcurrency returns the apply concurrency of the controller config, reduced while the agent is close to the
+// memory or CPU limits of its cgroup. The reduction is recorded in an event of the appsub and in the metrics
+func (sync *KubeSynchronizer) applyConcurrency(appsub *appv1alpha1.Subscription) int {
+	concurrency := utils.ApplyConcurrency()
+
+	throttled, reason := utils.ThrottledApplyConcurrency(concurrency)
+	if reason == "" {
+		return concurrency
+	}
+
+	msg := fmt.Sprintf("Apply concurrency reduced from %v to %v, %v", concurrency, throttled, reason)
+
+	klog.Infof("appsub %v/%v: %v", appsub.Namespace, appsub.Name, msg)
+	utils.RecordThrottleDecision(utils.ThrottleDecisionApplyConcurrency, reason)
+	sync.RecordEvent(appsub, utils.EventReasonResourceThrottled, msg, nil)
+
+	return throttled
+}
+
+// next applies the batch before a resource of another kind is processed
+func (b *applyBatch) next(kind schema.GroupVersionKind) {
+	if len(b.applies) > 0 && kind != b.kind {
+		b.flush()
+	}
+}
+
+// add adds the apply of a resource to the batch, the batch is applied once it is full
+func (b *applyBatch) add(kind schema.GroupVersionKind, apply func()) {
+	b.kind = kind
+	b.applies = append(b.applies, apply)
+
+	if len(b.applies) >= b.concurrency {
+		b.flush()
+	}
+}
+
+// flush applies the resources of the batch and waits for them
+func (b *applyBatch) flush() {
+	applies := b.applies
+	b.applies = nil
+
+	if len(applies) == 1 {
+		applies[0]()
+
+		return
+	}
+
+	wg := sync.WaitGroup{}
+
+	for _, apply := range applies {
+		wg.Add(1)
+
+		go func(apply func()) {
+			defer wg.Done()
+
+			apply()
+		}(apply)
+	}
+
+	wg.Wait()
+}
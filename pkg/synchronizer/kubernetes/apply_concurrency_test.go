@@ -0,0 +1,78 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestApplyBatch(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	namespaceKind := schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}
+	configMapKind := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+
+	var running, maxRunning int32
+
+	applied := []string{}
+	batch := newApplyBatch(3)
+
+	apply := func(name string) func() {
+		return func() {
+			n := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+
+			for {
+				max := atomic.LoadInt32(&maxRunning)
+				if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+					break
+				}
+			}
+
+			time.Sleep(20 * time.Millisecond)
+
+			batch.Lock()
+			applied = append(applied, name)
+			batch.Unlock()
+		}
+	}
+
+	for _, name := range []string{"ns1", "ns2"} {
+		batch.next(namespaceKind)
+		batch.add(namespaceKind, apply(name))
+	}
+
+	// the namespaces are applied before the first ConfigMap is processed
+	batch.next(configMapKind)
+	g.Expect(applied).To(gomega.ConsistOf("ns1", "ns2"))
+
+	for _, name := range []string{"cm1", "cm2", "cm3", "cm4"} {
+		batch.next(configMapKind)
+		batch.add(configMapKind, apply(name))
+	}
+
+	// the first 3 ConfigMaps are applied once the batch is full
+	g.Expect(applied).To(gomega.HaveLen(5))
+
+	batch.flush()
+	g.Expect(applied).To(gomega.HaveLen(6))
+	g.Expect(applied[5]).To(gomega.Equal("cm4"))
+	g.Expect(maxRunning).To(gomega.BeNumerically("==", 3))
+}
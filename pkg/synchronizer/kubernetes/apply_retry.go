@@ -0,0 +1,94 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	appv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+const (
+	defaultApplyRetryBackoff = time.Second
+	// the retries hold the synchronizer lock, they are capped to keep the other appsubs applying
+	maxApplyRetries      = 5
+	maxApplyRetryBackoff = 30 * time.Second
+)
+
+// applyRetryBackoff returns the backoff of the retries of the resources failing to apply from the apply-retries and
+// the apply-retry-backoff annotations of the appsub, the invalid values are ignored
+func applyRetryBackoff(appsub *appv1alpha1.Subscription) wait.Backoff {
+	backoff := wait.Backoff{Steps: 1, Duration: defaultApplyRetryBackoff, Factor: 2, Jitter: 0.1, Cap: maxApplyRetryBackoff}
+
+	an := appsub.GetAnnotations()
+
+	if v := strings.TrimSpace(an[appv1alpha1.AnnotationApplyRetries]); v != "" {
+		retries, err := strconv.Atoi(v)
+		if err != nil || retries < 0 {
+			klog.Warningf("invalid apply retries %v of appsub %v/%v, the failed resources are not retried",
+				v, appsub.GetNamespace(), appsub.GetName())
+
+			retries = 0
+		}
+
+		if retries > maxApplyRetries {
+			retries = maxApplyRetries
+		}
+
+		backoff.Steps = retries + 1
+	}
+
+	if v := strings.TrimSpace(an[appv1alpha1.AnnotationApplyRetryBackoff]); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			backoff.Duration = d
+		} else {
+			klog.Warningf("invalid apply retry backoff %v of appsub %v/%v, using %v",
+				v, appsub.GetNamespace(), appsub.GetName(), defaultApplyRetryBackoff)
+		}
+	}
+
+	return backoff
+}
+
+// isRetriableApplyError checks if the apply error is transient, the invalid, forbidden and denied resources fail
+// the same way on each retry
+func isRetriableApplyError(err error) bool {
+	return errors.IsConflict(err) || errors.IsNotFound(err) || errors.IsServerTimeout(err) || errors.IsTimeout(err) ||
+		errors.IsTooManyRequests(err) || errors.IsServiceUnavailable(err) || errors.IsInternalError(err) ||
+		errors.IsUnexpectedServerError(err)
+}
+
+// retryApply applies a resource until it succeeds, it fails with a non retriable error, the retries of the backoff
+// are exhausted or the apply is interrupted. It returns the number of attempts and the last error
+func retryApply(backoff wait.Backoff, interrupted func() bool, apply func() error) (int, error) {
+	attempts := 0
+
+	err := retry.OnError(backoff, func(err error) bool {
+		return isRetriableApplyError(err) && !interrupted()
+	}, func() error {
+		attempts++
+
+		return apply()
+	})
+
+	return attempts, err
+}
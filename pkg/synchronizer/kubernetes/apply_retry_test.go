@@ -0,0 +1,99 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func TestApplyRetry(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	appsub := func(retries, backoff string) *appv1.Subscription {
+		an := map[string]string{}
+
+		if retries != "" {
+			an[appv1.AnnotationApplyRetries] = retries
+		}
+
+		if backoff != "" {
+			an[appv1.AnnotationApplyRetryBackoff] = backoff
+		}
+
+		return &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns", Annotations: an}}
+	}
+
+	backoff := applyRetryBackoff(appsub("", ""))
+	g.Expect(backoff.Steps).To(Equal(1))
+	g.Expect(backoff.Duration).To(Equal(time.Second))
+
+	backoff = applyRetryBackoff(appsub("3", "10ms"))
+	g.Expect(backoff.Steps).To(Equal(4))
+	g.Expect(backoff.Duration).To(Equal(10 * time.Millisecond))
+
+	backoff = applyRetryBackoff(appsub("100", "-1s"))
+	g.Expect(backoff.Steps).To(Equal(maxApplyRetries + 1))
+	g.Expect(backoff.Duration).To(Equal(time.Second))
+
+	g.Expect(applyRetryBackoff(appsub("three", "")).Steps).To(Equal(1))
+
+	gr := schema.GroupResource{Resource: "configmaps"}
+	conflict := errors.NewConflict(gr, "cm", fmt.Errorf("the object has been modified"))
+	invalid := errors.NewBadRequest("invalid configmap")
+
+	g.Expect(isRetriableApplyError(conflict)).To(BeTrue())
+	g.Expect(isRetriableApplyError(errors.NewServiceUnavailable("unavailable"))).To(BeTrue())
+	g.Expect(isRetriableApplyError(invalid)).To(BeFalse())
+	g.Expect(isRetriableApplyError(fmt.Errorf("the resource is on the deny list"))).To(BeFalse())
+
+	backoff = applyRetryBackoff(appsub("3", "1ms"))
+	notInterrupted := func() bool { return false }
+
+	// the transient errors are retried until the apply succeeds
+	calls := 0
+	attempts, err := retryApply(backoff, notInterrupted, func() error {
+		if calls++; calls < 3 {
+			return conflict
+		}
+
+		return nil
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(attempts).To(Equal(3))
+
+	// the retries are exhausted
+	attempts, err = retryApply(backoff, notInterrupted, func() error { return conflict })
+	g.Expect(errors.IsConflict(err)).To(BeTrue())
+	g.Expect(attempts).To(Equal(4))
+
+	// the invalid resources are not retried
+	attempts, err = retryApply(backoff, notInterrupted, func() error { return invalid })
+	g.Expect(errors.IsBadRequest(err)).To(BeTrue())
+	g.Expect(attempts).To(Equal(1))
+
+	// the interrupted apply stops retrying
+	attempts, err = retryApply(backoff, func() bool { return true }, func() error { return conflict })
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(attempts).To(Equal(1))
+}
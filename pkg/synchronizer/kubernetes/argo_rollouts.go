@@ -0,0 +1,103 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+
+	appv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appSubStatusV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+var argoRolloutGVR = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "rollouts"}
+
+// argoRolloutsCanary returns the canary strategy the Deployments of the appsub are converted to Argo Rollouts with. It
+// is nil if the appsub doesn't convert its Deployments or the Rollouts CRD isn't installed on the cluster, the
+// Deployments are then applied as is
+func (sync *KubeSynchronizer) argoRolloutsCanary(appsub *appv1alpha1.Subscription) (map[string]interface{}, error) {
+	if appsub.GetAnnotations()[appv1alpha1.AnnotationArgoRolloutsConfigMap] == "" {
+		return nil, nil
+	}
+
+	gvk := utils.ArgoRolloutGVK
+	if _, _, err := sync.getGVRfromGVK(gvk.Group, gvk.Version, gvk.Kind); err != nil {
+		klog.Warningf("skip converting the deployments of appsub %v/%v to argo rollouts, err: %v", appsub.GetNamespace(),
+			appsub.GetName(), err)
+
+		return nil, nil
+	}
+
+	return utils.GetSubscriptionCanaryStrategy(sync.LocalClient, appsub)
+}
+
+// convertToArgoRollout converts the Deployment of the resource to an Argo Rollout with the canary strategy
+func convertToArgoRollout(canary map[string]interface{}, resource *ResourceUnit) bool {
+	if canary == nil || resource.Gvk.Group != "apps" || resource.Gvk.Kind != "Deployment" {
+		return false
+	}
+
+	resource.Resource = utils.ConvertDeploymentToRollout(resource.Resource, canary)
+	resource.Gvk = utils.ArgoRolloutGVK
+
+	return true
+}
+
+// updateArgoRolloutStatuses reports the health of the applied Argo Rollouts in their unit statuses. The progressing and
+// paused Rollouts stay deployed with their phase in the message, the degraded Rollouts, e.g. aborted by a failed
+// analysis, are failed. It returns true if a Rollout is degraded
+func (sync *KubeSynchronizer) updateArgoRolloutStatuses(unitStatuses []SubscriptionUnitStatus) bool {
+	degraded := false
+
+	for i, unitStatus := range unitStatuses {
+		if unitStatus.APIVersion != utils.ArgoRolloutGVK.GroupVersion().String() ||
+			unitStatus.Kind != utils.ArgoRolloutGVK.Kind || unitStatus.Phase != string(appSubStatusV1alpha1.PackageDeployed) {
+			continue
+		}
+
+		live, err := sync.DynamicClient.Resource(argoRolloutGVR).Namespace(unitStatus.Namespace).Get(context.TODO(),
+			unitStatus.Name, metav1.GetOptions{})
+		if err != nil {
+			klog.Warningf("failed to get the health of rollout %v/%v, err: %v", unitStatus.Namespace, unitStatus.Name, err)
+
+			continue
+		}
+
+		phase, _, _ := unstructured.NestedString(live.Object, "status", "phase")
+		message, _, _ := unstructured.NestedString(live.Object, "status", "message")
+
+		switch phase {
+		case "Healthy":
+			continue
+		case "":
+			phase = "Progressing"
+		case "Degraded":
+			unitStatuses[i].Phase = string(appSubStatusV1alpha1.PackageDeployFailed)
+			degraded = true
+		}
+
+		unitStatuses[i].Message = "Rollout " + phase
+		if message != "" {
+			unitStatuses[i].Message += ": " + message
+		}
+	}
+
+	return degraded
+}
@@ -0,0 +1,76 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	appSubStatusV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+func TestArgoRollouts(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	canary := map[string]interface{}{"steps": []interface{}{map[string]interface{}{"setWeight": int64(50)}}}
+
+	deploy := ResourceUnit{
+		Resource: &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": "web", "namespace": "default"},
+		}},
+		Gvk: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+	}
+
+	g.Expect(convertToArgoRollout(nil, &deploy)).To(BeFalse())
+	g.Expect(convertToArgoRollout(canary, &deploy)).To(BeTrue())
+	g.Expect(deploy.Gvk).To(Equal(utils.ArgoRolloutGVK))
+	g.Expect(deploy.Resource.GetKind()).To(Equal("Rollout"))
+	g.Expect(convertToArgoRollout(canary, &deploy)).To(BeFalse())
+
+	rollout := func(name, phase, message string) *unstructured.Unstructured {
+		obj := utils.ConvertDeploymentToRollout(deploy.Resource, canary)
+		obj.SetName(name)
+		obj.Object["status"] = map[string]interface{}{"phase": phase, "message": message}
+
+		return obj
+	}
+
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), rollout("healthy", "Healthy", ""),
+		rollout("paused", "Paused", "CanaryPauseStep"), rollout("degraded", "Degraded", "RolloutAborted"))
+	s := &KubeSynchronizer{DynamicClient: client}
+
+	unit := func(name string) SubscriptionUnitStatus {
+		return SubscriptionUnitStatus{APIVersion: "argoproj.io/v1alpha1", Kind: "Rollout", Name: name, Namespace: "default",
+			Phase: string(appSubStatusV1alpha1.PackageDeployed)}
+	}
+
+	unitStatuses := []SubscriptionUnitStatus{unit("healthy"), unit("paused"), unit("degraded"), unit("missing")}
+
+	g.Expect(s.updateArgoRolloutStatuses(unitStatuses)).To(BeTrue())
+	g.Expect(unitStatuses[0]).To(Equal(unit("healthy")))
+	g.Expect(unitStatuses[1].Phase).To(Equal(string(appSubStatusV1alpha1.PackageDeployed)))
+	g.Expect(unitStatuses[1].Message).To(Equal("Rollout Paused: CanaryPauseStep"))
+	g.Expect(unitStatuses[2].Phase).To(Equal(string(appSubStatusV1alpha1.PackageDeployFailed)))
+	g.Expect(unitStatuses[2].Message).To(Equal("Rollout Degraded: RolloutAborted"))
+	g.Expect(unitStatuses[3]).To(Equal(unit("missing")))
+}
@@ -0,0 +1,582 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	appv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appSubStatusV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+const (
+	blueGreenBlue           = "blue"
+	blueGreenGreen          = "green"
+	defaultBlueGreenTimeout = 5 * time.Minute
+)
+
+var (
+	blueGreenPollInterval = 5 * time.Second
+
+	deploymentGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	jobGVR        = schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+)
+
+// blueGreenRollout is the blue/green plan of a Deployment of the appsub and of the Services selecting its pods. The
+// Deployment is applied as <name>-<color>, the Services select the active color until the other color is healthy
+type blueGreenRollout struct {
+	namespace  string
+	deployment string
+	services   []string
+	smokeTests []*unstructured.Unstructured
+	// hash of the desired Deployment
+	hash string
+	// color selected by the Services, empty on the first deploy
+	active string
+	// color the desired Deployment is applied to
+	color string
+	// hash of the last aborted revision
+	aborted string
+	// the desired Deployment is the aborted revision, it isn't applied again
+	held bool
+}
+
+// isRollingOut checks if the desired Deployment is applied to the inactive color
+func (r *blueGreenRollout) isRollingOut() bool {
+	return r.active != "" && r.color != r.active && !r.held
+}
+
+// selectedColor returns the color the Services select until the cutover
+func (r *blueGreenRollout) selectedColor() string {
+	if r.active == "" {
+		return r.color
+	}
+
+	return r.active
+}
+
+func (r *blueGreenRollout) coloredName(color string) string {
+	return r.deployment + "-" + color
+}
+
+func otherColor(color string) string {
+	if color == blueGreenBlue {
+		return blueGreenGreen
+	}
+
+	return blueGreenBlue
+}
+
+// blueGreenRollouts are the blue/green plans of an apply by the Deployment, Service and smoke test Job keys
+type blueGreenRollouts map[string]*blueGreenRollout
+
+func blueGreenKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+// isBlueGreen checks if the appsub deploys its Services and Deployments with the blue/green strategy
+func isBlueGreen(appsub *appv1alpha1.Subscription) bool {
+	return strings.EqualFold(appsub.GetAnnotations()[appv1alpha1.AnnotationBlueGreen], "true")
+}
+
+// blueGreenTimeout returns how long the new color has to become healthy and pass its smoke tests from the
+// blue-green-timeout annotation of the appsub, the invalid values are ignored
+func blueGreenTimeout(appsub *appv1alpha1.Subscription) time.Duration {
+	v := strings.TrimSpace(appsub.GetAnnotations()[appv1alpha1.AnnotationBlueGreenTimeout])
+	if v == "" {
+		return defaultBlueGreenTimeout
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		klog.Warningf("invalid blue/green timeout %v of appsub %v/%v, using %v", v, appsub.GetNamespace(), appsub.GetName(),
+			defaultBlueGreenTimeout)
+
+		return defaultBlueGreenTimeout
+	}
+
+	return d
+}
+
+// planBlueGreen returns the blue/green plans of the Deployments of the resources selected by a Service of the
+// resources. The colors are picked from the live Services and Deployments:
+//   - on the first deploy, the Deployment is applied to blue and the Services select blue
+//   - if the desired Deployment is the revision of the active color, it is applied to the active color
+//   - if the desired Deployment is the last aborted revision, it is held and the active color is kept
+//   - otherwise the desired Deployment is rolled out to the inactive color
+func (sync *KubeSynchronizer) planBlueGreen(appsub *appv1alpha1.Subscription, resources []ResourceUnit) blueGreenRollouts {
+	rollouts := blueGreenRollouts{}
+
+	if !isBlueGreen(appsub) {
+		return rollouts
+	}
+
+	for _, svc := range resources {
+		if svc.Gvk.Group != "" || svc.Gvk.Kind != "Service" {
+			continue
+		}
+
+		selector, _, _ := unstructured.NestedStringMap(svc.Resource.Object, "spec", "selector")
+		if len(selector) == 0 {
+			continue
+		}
+
+		for _, deploy := range resources {
+			if deploy.Gvk.Group != "apps" || deploy.Gvk.Kind != "Deployment" ||
+				deploy.Resource.GetNamespace() != svc.Resource.GetNamespace() {
+				continue
+			}
+
+			labels, _, _ := unstructured.NestedStringMap(deploy.Resource.Object, "spec", "template", "metadata", "labels")
+			if !selectsLabels(selector, labels) {
+				continue
+			}
+
+			key := blueGreenKey("Deployment", deploy.Resource.GetNamespace(), deploy.Resource.GetName())
+
+			rollout, ok := rollouts[key]
+			if !ok {
+				rollout = &blueGreenRollout{
+					namespace:  deploy.Resource.GetNamespace(),
+					deployment: deploy.Resource.GetName(),
+					hash:       manifestHash(deploy.Resource),
+				}
+				rollouts[key] = rollout
+			}
+
+			rollout.services = append(rollout.services, svc.Resource.GetName())
+			rollouts[blueGreenKey("Service", svc.Resource.GetNamespace(), svc.Resource.GetName())] = rollout
+
+			break
+		}
+	}
+
+	for key, rollout := range rollouts {
+		if !strings.HasPrefix(key, "Deployment/") {
+			continue
+		}
+
+		sync.pickBlueGreenColor(rollout)
+
+		klog.Infof("appsub: %v/%v, blue/green deployment %v/%v, active color: %v, applied color: %v, held: %v",
+			appsub.GetNamespace(), appsub.GetName(), rollout.namespace, rollout.deployment, rollout.active, rollout.color,
+			rollout.held)
+	}
+
+	// the smoke tests run against the new colors of their namespace
+	for _, res := range resources {
+		if res.Gvk.Group != "batch" || res.Gvk.Kind != "Job" ||
+			!strings.EqualFold(res.Resource.GetAnnotations()[appv1alpha1.AnnotationBlueGreenSmokeTest], "true") {
+			continue
+		}
+
+		for key, rollout := range rollouts {
+			if strings.HasPrefix(key, "Deployment/") && rollout.namespace == res.Resource.GetNamespace() {
+				rollouts[blueGreenKey("Job", res.Resource.GetNamespace(), res.Resource.GetName())] = rollout
+
+				break
+			}
+		}
+	}
+
+	return rollouts
+}
+
+// pickBlueGreenColor sets the active and the applied colors of the rollout from the live Services and Deployments
+func (sync *KubeSynchronizer) pickBlueGreenColor(rollout *blueGreenRollout) {
+	rollout.color = blueGreenBlue
+
+	for _, name := range rollout.services {
+		svc, err := sync.DynamicClient.Resource(serviceGVR).Namespace(rollout.namespace).Get(context.TODO(), name,
+			metav1.GetOptions{})
+		if err != nil {
+			continue
+		}
+
+		if active := svc.GetAnnotations()[appv1alpha1.AnnotationBlueGreenActiveColor]; active != "" {
+			rollout.active = active
+			rollout.aborted = svc.GetAnnotations()[appv1alpha1.AnnotationBlueGreenAbortedHash]
+
+			break
+		}
+	}
+
+	if rollout.active == "" {
+		return
+	}
+
+	rollout.color = rollout.active
+
+	live, err := sync.DynamicClient.Resource(deploymentGVR).Namespace(rollout.namespace).Get(context.TODO(),
+		rollout.coloredName(rollout.active), metav1.GetOptions{})
+	if err == nil && live.GetAnnotations()[appv1alpha1.AnnotationBlueGreenHash] == rollout.hash {
+		return
+	}
+
+	// the active Deployment is deleted, it is applied again instead of rolling out
+	if kerrors.IsNotFound(err) {
+		return
+	}
+
+	if rollout.hash == rollout.aborted {
+		rollout.held = true
+
+		return
+	}
+
+	rollout.color = otherColor(rollout.active)
+}
+
+func selectsLabels(selector, labels map[string]string) bool {
+	for k, v := range selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// render applies the blue/green plan to a resource of the apply. The Deployment is renamed to its color, the Services
+// select the active color. It returns true if the resource isn't applied, the held Deployments and the smoke tests
+// run at the cutover are not applied
+func (rollouts blueGreenRollouts) render(obj *unstructured.Unstructured) bool {
+	rollout, ok := rollouts[blueGreenKey(obj.GetKind(), obj.GetNamespace(), obj.GetName())]
+	if !ok {
+		return false
+	}
+
+	switch obj.GetKind() {
+	case "Deployment":
+		if rollout.held {
+			return true
+		}
+
+		obj.SetName(rollout.coloredName(rollout.color))
+
+		_ = unstructured.SetNestedField(obj.Object, rollout.color, "spec", "selector", "matchLabels",
+			appv1alpha1.LabelBlueGreenColor)
+		_ = unstructured.SetNestedField(obj.Object, rollout.color, "spec", "template", "metadata", "labels",
+			appv1alpha1.LabelBlueGreenColor)
+
+		setAnnotation(obj, appv1alpha1.AnnotationBlueGreenHash, rollout.hash)
+	case "Service":
+		_ = unstructured.SetNestedField(obj.Object, rollout.selectedColor(), "spec", "selector",
+			appv1alpha1.LabelBlueGreenColor)
+
+		setAnnotation(obj, appv1alpha1.AnnotationBlueGreenActiveColor, rollout.selectedColor())
+
+		if rollout.aborted != "" {
+			setAnnotation(obj, appv1alpha1.AnnotationBlueGreenAbortedHash, rollout.aborted)
+		}
+	case "Job":
+		rollout.smokeTests = append(rollout.smokeTests, obj.DeepCopy())
+
+		return true
+	}
+
+	return false
+}
+
+func setAnnotation(obj *unstructured.Unstructured, key, value string) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	annotations[key] = value
+	obj.SetAnnotations(annotations)
+}
+
+// rolloutBlueGreen cuts the Services of the rollouts over to their new color once the new Deployment is healthy and
+// its smoke tests complete, then the previous color is scaled down. The rollout is aborted if the new color doesn't
+// become healthy or a smoke test fails before the timeout, the new color is scaled down and the Services keep the
+// previous color. It returns the unit statuses of the apply with the kept colors and smoke tests
+func (sync *KubeSynchronizer) rolloutBlueGreen(appsub *appv1alpha1.Subscription, rollouts blueGreenRollouts,
+	unitStatuses []SubscriptionUnitStatus) ([]SubscriptionUnitStatus, bool) {
+	failed := false
+	timeout := blueGreenTimeout(appsub)
+
+	for key, rollout := range rollouts {
+		if !strings.HasPrefix(key, "Deployment/") {
+			continue
+		}
+
+		if rollout.isRollingOut() {
+			index := -1
+
+			for i, unitStatus := range unitStatuses {
+				if unitStatus.Kind == "Deployment" && unitStatus.Namespace == rollout.namespace &&
+					unitStatus.Name == rollout.coloredName(rollout.color) {
+					index = i
+				}
+			}
+
+			if index >= 0 && unitStatuses[index].Phase == string(appSubStatusV1alpha1.PackageDeployed) {
+				err := sync.cutoverBlueGreen(rollout, timeout)
+
+				switch {
+				case errors.Is(err, ErrDraining):
+					// the rollout continues after the restart, the Services keep the active color
+					klog.Infof("stop the blue/green rollout of deployment %v/%v, err: %v", rollout.namespace,
+						rollout.deployment, err)
+				case err != nil:
+					klog.Errorf("abort the blue/green rollout of deployment %v/%v, err: %v", rollout.namespace,
+						rollout.deployment, err)
+
+					sync.abortBlueGreen(rollout)
+
+					unitStatuses[index].Phase = string(appSubStatusV1alpha1.PackageDeployFailed)
+					unitStatuses[index].Message = "blue/green rollout aborted: " + err.Error()
+					failed = true
+				default:
+					// the Services select the new color, the previous color is kept scaled down
+					rollout.active = rollout.color
+				}
+			}
+		}
+
+		unitStatuses = append(unitStatuses, sync.keptBlueGreenUnitStatuses(rollout)...)
+	}
+
+	return unitStatuses, failed
+}
+
+// keptBlueGreenUnitStatuses returns the unit statuses of the live colors and smoke tests not applied by the apply, they
+// stay in the appsubstatus instead of being deleted as orphans
+func (sync *KubeSynchronizer) keptBlueGreenUnitStatuses(rollout *blueGreenRollout) []SubscriptionUnitStatus {
+	unitStatuses := []SubscriptionUnitStatus{}
+	kept := map[string]string{otherColor(rollout.color): ""}
+
+	// the held revision is the aborted color, the active color isn't applied either
+	if rollout.held {
+		kept = map[string]string{
+			rollout.active:             "",
+			otherColor(rollout.active): "blue/green rollout aborted, the revision is held until the Deployment changes",
+		}
+	}
+
+	for color, msg := range kept {
+		if _, err := sync.DynamicClient.Resource(deploymentGVR).Namespace(rollout.namespace).Get(context.TODO(),
+			rollout.coloredName(color), metav1.GetOptions{}); err != nil {
+			continue
+		}
+
+		unitStatus := SubscriptionUnitStatus{APIVersion: "apps/v1", Kind: "Deployment", Name: rollout.coloredName(color),
+			Namespace: rollout.namespace, Phase: string(appSubStatusV1alpha1.PackageDeployed)}
+
+		if msg != "" {
+			unitStatus.Phase = string(appSubStatusV1alpha1.PackageDeployFailed)
+			unitStatus.Message = msg
+		}
+
+		unitStatuses = append(unitStatuses, unitStatus)
+	}
+
+	for _, job := range rollout.smokeTests {
+		if _, err := sync.DynamicClient.Resource(jobGVR).Namespace(rollout.namespace).Get(context.TODO(), job.GetName(),
+			metav1.GetOptions{}); err == nil {
+			unitStatuses = append(unitStatuses, SubscriptionUnitStatus{APIVersion: "batch/v1", Kind: "Job", Name: job.GetName(),
+				Namespace: rollout.namespace, Phase: string(appSubStatusV1alpha1.PackageDeployed)})
+		}
+	}
+
+	return unitStatuses
+}
+
+// cutoverBlueGreen waits for the new color to be healthy, runs the smoke tests and switches the Services to the new
+// color, the previous color is scaled down
+func (sync *KubeSynchronizer) cutoverBlueGreen(rollout *blueGreenRollout, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	name := rollout.coloredName(rollout.color)
+
+	err := wait.PollImmediate(blueGreenPollInterval, timeout, func() (bool, error) {
+		if sync.isInterrupted() {
+			return false, ErrDraining
+		}
+
+		live, err := sync.DynamicClient.Resource(deploymentGVR).Namespace(rollout.namespace).Get(context.TODO(), name,
+			metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		if hasDeploymentProgressDeadlineExceeded(live) {
+			return false, fmt.Errorf("deployment %v/%v exceeded its progress deadline", rollout.namespace, name)
+		}
+
+		healthy, _, err := utils.IsResourceHealthy(sync.LocalClient, live.GroupVersionKind(),
+			types.NamespacedName{Namespace: rollout.namespace, Name: name})
+
+		return healthy, err
+	})
+	if err != nil {
+		return fmt.Errorf("deployment %v/%v is not healthy, err: %w", rollout.namespace, name, err)
+	}
+
+	for _, job := range rollout.smokeTests {
+		if err := sync.runSmokeTest(job, time.Until(deadline)); err != nil {
+			return err
+		}
+	}
+
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": map[string]interface{}{
+			appv1alpha1.AnnotationBlueGreenActiveColor: rollout.color,
+			appv1alpha1.AnnotationBlueGreenAbortedHash: nil,
+		}},
+		"spec": map[string]interface{}{"selector": map[string]interface{}{appv1alpha1.LabelBlueGreenColor: rollout.color}},
+	})
+
+	for _, svc := range rollout.services {
+		if _, err := sync.DynamicClient.Resource(serviceGVR).Namespace(rollout.namespace).Patch(context.TODO(), svc,
+			types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return fmt.Errorf("failed to switch service %v/%v to %v, err: %w", rollout.namespace, svc, rollout.color, err)
+		}
+
+		klog.Infof("Switched service %v/%v to the %v deployment %v", rollout.namespace, svc, rollout.color, name)
+	}
+
+	if err := sync.scaleDownDeployment(rollout.namespace, rollout.coloredName(rollout.active)); err != nil {
+		klog.Warningf("failed to scale down the previous color of deployment %v/%v, err: %v", rollout.namespace,
+			rollout.deployment, err)
+	}
+
+	return nil
+}
+
+// abortBlueGreen scales the new color down and records the aborted revision in the Services, the Services keep the
+// previous color
+func (sync *KubeSynchronizer) abortBlueGreen(rollout *blueGreenRollout) {
+	if err := sync.scaleDownDeployment(rollout.namespace, rollout.coloredName(rollout.color)); err != nil {
+		klog.Warningf("failed to scale down the aborted color of deployment %v/%v, err: %v", rollout.namespace,
+			rollout.deployment, err)
+	}
+
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": map[string]interface{}{
+			appv1alpha1.AnnotationBlueGreenAbortedHash: rollout.hash,
+		}},
+	})
+
+	for _, svc := range rollout.services {
+		if _, err := sync.DynamicClient.Resource(serviceGVR).Namespace(rollout.namespace).Patch(context.TODO(), svc,
+			types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			klog.Warningf("failed to record the aborted revision of service %v/%v, err: %v", rollout.namespace, svc, err)
+		}
+	}
+}
+
+func (sync *KubeSynchronizer) scaleDownDeployment(namespace, name string) error {
+	_, err := sync.DynamicClient.Resource(deploymentGVR).Namespace(namespace).Patch(context.TODO(), name,
+		types.MergePatchType, []byte(`{"spec":{"replicas":0}}`), metav1.PatchOptions{})
+	if kerrors.IsNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+// runSmokeTest runs the smoke test Job again and waits for it to complete, it fails if the Job fails or doesn't
+// complete before the timeout
+func (sync *KubeSynchronizer) runSmokeTest(job *unstructured.Unstructured, timeout time.Duration) error {
+	ri := sync.DynamicClient.Resource(jobGVR).Namespace(job.GetNamespace())
+	background := metav1.DeletePropagationBackground
+
+	if err := ri.Delete(context.TODO(), job.GetName(), metav1.DeleteOptions{PropagationPolicy: &background}); err != nil &&
+		!kerrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete the previous smoke test %v/%v, err: %w", job.GetNamespace(), job.GetName(), err)
+	}
+
+	err := wait.PollImmediate(blueGreenPollInterval, timeout, func() (bool, error) {
+		_, err := ri.Get(context.TODO(), job.GetName(), metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			return true, nil
+		}
+
+		return false, err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to wait for the deletion of the previous smoke test %v/%v, err: %w", job.GetNamespace(),
+			job.GetName(), err)
+	}
+
+	smokeTest := job.DeepCopy()
+	smokeTest.SetResourceVersion("")
+
+	if _, err := ri.Create(context.TODO(), smokeTest, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create the smoke test %v/%v, err: %w", job.GetNamespace(), job.GetName(), err)
+	}
+
+	klog.Infof("Running smoke test %v/%v", job.GetNamespace(), job.GetName())
+
+	err = wait.PollImmediate(blueGreenPollInterval, timeout, func() (bool, error) {
+		live, err := ri.Get(context.TODO(), job.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		if hasCondition(live, "Failed") {
+			return false, fmt.Errorf("the smoke test %v/%v failed", job.GetNamespace(), job.GetName())
+		}
+
+		return hasCondition(live, "Complete"), nil
+	})
+	if err != nil {
+		return fmt.Errorf("smoke test %v/%v didn't complete, err: %w", job.GetNamespace(), job.GetName(), err)
+	}
+
+	return nil
+}
+
+func hasDeploymentProgressDeadlineExceeded(deploy *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(deploy.Object, "status", "conditions")
+
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if ok && cond["type"] == "Progressing" && cond["reason"] == "ProgressDeadlineExceeded" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasCondition(obj *unstructured.Unstructured, condType string) bool {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if ok && cond["type"] == condType && cond["status"] == "True" {
+			return true
+		}
+	}
+
+	return false
+}
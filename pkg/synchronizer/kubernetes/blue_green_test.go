@@ -0,0 +1,183 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appSubStatusV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+)
+
+func TestBlueGreen(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	blueGreenPollInterval = 10 * time.Millisecond
+
+	deployment := func(name, image string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+			"spec": map[string]interface{}{
+				"replicas": int64(1),
+				"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "web"}},
+				"template": map[string]interface{}{
+					"metadata": map[string]interface{}{"labels": map[string]interface{}{"app": "web"}},
+					"spec": map[string]interface{}{
+						"containers": []interface{}{map[string]interface{}{"name": "web", "image": image}},
+					},
+				},
+			},
+		}}
+	}
+
+	service := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Service",
+		"metadata":   map[string]interface{}{"name": "web", "namespace": "default"},
+		"spec":       map[string]interface{}{"selector": map[string]interface{}{"app": "web"}},
+	}}
+
+	resources := func(image string) []ResourceUnit {
+		return []ResourceUnit{
+			{Resource: service.DeepCopy(), Gvk: schema.GroupVersionKind{Version: "v1", Kind: "Service"}},
+			{Resource: deployment("web", image), Gvk: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}},
+		}
+	}
+
+	appsub := &appv1.Subscription{}
+	appsub.SetAnnotations(map[string]string{appv1.AnnotationBlueGreen: "true", appv1.AnnotationBlueGreenTimeout: "50ms"})
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{deploymentGVR: "DeploymentList", serviceGVR: "ServiceList"})
+	s := &KubeSynchronizer{DynamicClient: client}
+
+	// the Deployment and the Service are applied to blue on the first deploy
+	first := resources("nginx:1.24")
+	rollouts := s.planBlueGreen(appsub, first)
+	g.Expect(rollouts).To(HaveLen(2))
+
+	for _, res := range first {
+		g.Expect(rollouts.render(res.Resource)).To(BeFalse())
+
+		_, err := client.Resource(schema.GroupVersionResource{Group: res.Gvk.Group, Version: res.Gvk.Version,
+			Resource: map[string]string{"Service": "services", "Deployment": "deployments"}[res.Gvk.Kind]}).
+			Namespace("default").Create(context.TODO(), res.Resource, metav1.CreateOptions{})
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+
+	g.Expect(first[1].Resource.GetName()).To(Equal("web-blue"))
+	g.Expect(first[1].Resource.GetLabels()).To(BeEmpty())
+
+	matchLabels, _, _ := unstructured.NestedStringMap(first[1].Resource.Object, "spec", "selector", "matchLabels")
+	g.Expect(matchLabels).To(HaveKeyWithValue(appv1.LabelBlueGreenColor, "blue"))
+
+	selector, _, _ := unstructured.NestedStringMap(first[0].Resource.Object, "spec", "selector")
+	g.Expect(selector).To(HaveKeyWithValue(appv1.LabelBlueGreenColor, "blue"))
+	g.Expect(first[0].Resource.GetAnnotations()).To(HaveKeyWithValue(appv1.AnnotationBlueGreenActiveColor, "blue"))
+
+	// the same revision is applied to the active color again
+	rollouts = s.planBlueGreen(appsub, resources("nginx:1.24"))
+	g.Expect(rollouts["Deployment/default/web"].color).To(Equal("blue"))
+	g.Expect(rollouts["Deployment/default/web"].isRollingOut()).To(BeFalse())
+
+	// a new revision is rolled out to green while the Service selects blue
+	second := resources("nginx:1.25")
+	rollouts = s.planBlueGreen(appsub, second)
+	rollout := rollouts["Deployment/default/web"]
+	g.Expect(rollout.active).To(Equal("blue"))
+	g.Expect(rollout.color).To(Equal("green"))
+	g.Expect(rollout.isRollingOut()).To(BeTrue())
+
+	rollouts.render(second[1].Resource)
+	g.Expect(second[1].Resource.GetName()).To(Equal("web-green"))
+
+	_, err := client.Resource(deploymentGVR).Namespace("default").Create(context.TODO(), second[1].Resource,
+		metav1.CreateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// the rollout is aborted when green doesn't become healthy
+	unhealthy := second[1].Resource.DeepCopy()
+	g.Expect(unstructured.SetNestedField(unhealthy.Object, int64(0), "status", "availableReplicas")).To(Succeed())
+	s.LocalClient = fake.NewClientBuilder().WithObjects(unhealthy).Build()
+
+	unitStatuses := []SubscriptionUnitStatus{{APIVersion: "apps/v1", Kind: "Deployment", Name: "web-green",
+		Namespace: "default", Phase: string(appSubStatusV1alpha1.PackageDeployed)}}
+
+	unitStatuses, failed := s.rolloutBlueGreen(appsub, rollouts, unitStatuses)
+	g.Expect(failed).To(BeTrue())
+	g.Expect(unitStatuses[0].Phase).To(Equal(string(appSubStatusV1alpha1.PackageDeployFailed)))
+	g.Expect(unitStatuses).To(ContainElement(SubscriptionUnitStatus{APIVersion: "apps/v1", Kind: "Deployment",
+		Name: "web-blue", Namespace: "default", Phase: string(appSubStatusV1alpha1.PackageDeployed)}))
+
+	green, err := client.Resource(deploymentGVR).Namespace("default").Get(context.TODO(), "web-green", metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	replicas, _, _ := unstructured.NestedInt64(green.Object, "spec", "replicas")
+	g.Expect(replicas).To(BeZero())
+
+	// the aborted revision is held
+	rollouts = s.planBlueGreen(appsub, resources("nginx:1.25"))
+	g.Expect(rollouts["Deployment/default/web"].held).To(BeTrue())
+	g.Expect(rollouts.render(deployment("web", "nginx:1.25"))).To(BeTrue())
+
+	// the Service is switched to green once it is healthy and blue is scaled down
+	third := resources("nginx:1.26")
+	rollouts = s.planBlueGreen(appsub, third)
+	g.Expect(rollouts["Deployment/default/web"].color).To(Equal("green"))
+
+	rollouts.render(third[1].Resource)
+
+	_, err = client.Resource(deploymentGVR).Namespace("default").Update(context.TODO(), third[1].Resource,
+		metav1.UpdateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	healthy := third[1].Resource.DeepCopy()
+	g.Expect(unstructured.SetNestedField(healthy.Object, int64(1), "status", "updatedReplicas")).To(Succeed())
+	g.Expect(unstructured.SetNestedField(healthy.Object, int64(1), "status", "availableReplicas")).To(Succeed())
+	s.LocalClient = fake.NewClientBuilder().WithObjects(healthy).Build()
+
+	unitStatuses = []SubscriptionUnitStatus{{APIVersion: "apps/v1", Kind: "Deployment", Name: "web-green",
+		Namespace: "default", Phase: string(appSubStatusV1alpha1.PackageDeployed)}}
+
+	unitStatuses, failed = s.rolloutBlueGreen(appsub, rollouts, unitStatuses)
+	g.Expect(failed).To(BeFalse())
+	g.Expect(unitStatuses).To(HaveLen(2))
+
+	svc, err := client.Resource(serviceGVR).Namespace("default").Get(context.TODO(), "web", metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(svc.GetAnnotations()).To(HaveKeyWithValue(appv1.AnnotationBlueGreenActiveColor, "green"))
+	g.Expect(svc.GetAnnotations()).NotTo(HaveKey(appv1.AnnotationBlueGreenAbortedHash))
+
+	selector, _, _ = unstructured.NestedStringMap(svc.Object, "spec", "selector")
+	g.Expect(selector).To(HaveKeyWithValue(appv1.LabelBlueGreenColor, "green"))
+
+	blue, err := client.Resource(deploymentGVR).Namespace("default").Get(context.TODO(), "web-blue", metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	replicas, _, _ = unstructured.NestedInt64(blue.Object, "spec", "replicas")
+	g.Expect(replicas).To(BeZero())
+}
@@ -0,0 +1,158 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	appv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+// configChecksumWorkloads are the kinds of the apps group whose pod template gets the checksum of its config, the
+// pods and the Jobs run with the config they started with and aren't rolled
+var configChecksumWorkloads = map[string]bool{
+	"Deployment":  true,
+	"StatefulSet": true,
+	"DaemonSet":   true,
+}
+
+// isRestartOnConfigChange checks if the appsub opts in to roll the pods of its workloads when their config changes
+func isRestartOnConfigChange(appsub *appv1alpha1.Subscription) bool {
+	return strings.EqualFold(appsub.GetAnnotations()[appv1alpha1.AnnotationRestartOnConfigChange], "true")
+}
+
+// configRef is a ConfigMap or a Secret of the appsub, the resources without namespace are deployed to the appsub
+// namespace
+type configRef struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+// configChecksums returns the sha256 of the content of each ConfigMap and Secret of the appsub resources, rendered
+// with the package overrides and the package patches like they are applied. The resources failing to render are left
+// out, their failure is reported when they are applied
+func (sync *KubeSynchronizer) configChecksums(appsub *appv1alpha1.Subscription, hostSub types.NamespacedName,
+	resources []ResourceUnit) map[configRef]string {
+	checksums := map[configRef]string{}
+
+	for _, resource := range resources {
+		resource := resource
+
+		if resource.Resource == nil || resource.Gvk.Group != "" ||
+			resource.Gvk.Kind != "ConfigMap" && resource.Gvk.Kind != "Secret" {
+			continue
+		}
+
+		obj, err := sync.OverrideResource(hostSub, &resource)
+		if err == nil {
+			obj, err = utils.ApplyPackagePatches(appsub, obj)
+		}
+
+		if err != nil {
+			klog.Warningf("skip the checksum of %v %v/%v of appsub %v, err: %v", resource.Gvk.Kind,
+				resource.Resource.GetNamespace(), resource.Resource.GetName(), hostSub.String(), err)
+
+			continue
+		}
+
+		content := map[string]interface{}{}
+
+		for _, field := range []string{"data", "binaryData", "stringData"} {
+			if v, ok := obj.Object[field]; ok {
+				content[field] = v
+			}
+		}
+
+		// the keys of the maps are sorted by the marshaling, the same content has the same checksum
+		b, err := json.Marshal(content)
+		if err != nil {
+			klog.Warningf("skip the checksum of %v %v/%v of appsub %v, err: %v", resource.Gvk.Kind,
+				obj.GetNamespace(), obj.GetName(), hostSub.String(), err)
+
+			continue
+		}
+
+		ref := configRef{kind: resource.Gvk.Kind, namespace: obj.GetNamespace(), name: obj.GetName()}
+		if ref.namespace == "" {
+			ref.namespace = appsub.GetNamespace()
+		}
+
+		checksums[ref] = fmt.Sprintf("%x", sha256.Sum256(b))
+	}
+
+	return checksums
+}
+
+// setConfigChecksum stamps the checksum of the ConfigMaps and Secrets referenced by the pod template of a workload in
+// its annotations, a change of the referenced config changes the pod template and rolls the pods. The annotation is
+// left out when the workload references none of the ConfigMaps and Secrets of the appsub
+func setConfigChecksum(template *unstructured.Unstructured, defaultNamespace string, checksums map[configRef]string) {
+	gvk := template.GroupVersionKind()
+	if len(checksums) == 0 || gvk.Group != "apps" || !configChecksumWorkloads[gvk.Kind] {
+		return
+	}
+
+	spec, found, err := unstructured.NestedMap(template.Object, "spec", "template", "spec")
+	if err != nil || !found {
+		return
+	}
+
+	podSpec := &corev1.PodSpec{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(spec, podSpec); err != nil {
+		klog.Warningf("skip the config checksum of %v %v/%v, err: %v", gvk.Kind, template.GetNamespace(),
+			template.GetName(), err)
+
+		return
+	}
+
+	namespace := template.GetNamespace()
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+
+	referenced := []string{}
+
+	for ref, checksum := range checksums {
+		if ref.namespace == namespace && podSpecReferences(podSpec, ref.kind, ref.name) {
+			referenced = append(referenced, ref.kind+"/"+ref.name+"="+checksum)
+		}
+	}
+
+	if len(referenced) == 0 {
+		return
+	}
+
+	sort.Strings(referenced)
+
+	checksum := fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(referenced, "\n"))))
+
+	if err := unstructured.SetNestedField(template.Object, checksum, "spec", "template", "metadata", "annotations",
+		appv1alpha1.AnnotationConfigChecksum); err != nil {
+		klog.Warningf("failed to set the config checksum of %v %v/%v, err: %v", gvk.Kind, template.GetNamespace(),
+			template.GetName(), err)
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func TestConfigChecksum(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	appsub := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{
+		Name:        "demo",
+		Namespace:   "demo-ns",
+		Annotations: map[string]string{appv1.AnnotationRestartOnConfigChange: "true"},
+	}}
+	g.Expect(isRestartOnConfigChange(appsub)).To(BeTrue())
+
+	scheme := runtime.NewScheme()
+	g.Expect(appv1.SchemeBuilder.AddToScheme(scheme)).To(Succeed())
+
+	s := &KubeSynchronizer{
+		LocalClient: fake.NewClientBuilder().WithScheme(scheme).WithObjects(appsub).Build(),
+		Extension:   &SubscriptionExtension{},
+	}
+	hostSub := types.NamespacedName{Namespace: "demo-ns", Name: "demo"}
+
+	configMap := func(value string) ResourceUnit {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "app-config"},
+			"data":       map[string]interface{}{"log-level": value},
+		}}
+
+		return ResourceUnit{Resource: obj, Gvk: obj.GroupVersionKind()}
+	}
+
+	secret := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": "app-secret", "namespace": "demo-ns"},
+		"stringData": map[string]interface{}{"password": "s3cr3t"},
+	}}
+
+	workload := func(kind, namespace string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       kind,
+			"metadata":   map[string]interface{}{"name": "web", "namespace": namespace},
+			"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{
+				"volumes": []interface{}{
+					map[string]interface{}{"name": "config", "configMap": map[string]interface{}{"name": "app-config"}},
+				},
+				"containers": []interface{}{map[string]interface{}{
+					"name":  "web",
+					"image": "quay.io/demo/web:1.0",
+					"env": []interface{}{map[string]interface{}{"name": "PASSWORD", "valueFrom": map[string]interface{}{
+						"secretKeyRef": map[string]interface{}{"name": "app-secret", "key": "password"}}}},
+				}},
+			}}},
+		}}
+	}
+
+	checksumOf := func(obj *unstructured.Unstructured) string {
+		v, _, _ := unstructured.NestedString(obj.Object, "spec", "template", "metadata", "annotations",
+			appv1.AnnotationConfigChecksum)
+
+		return v
+	}
+
+	secretUnit := ResourceUnit{Resource: secret, Gvk: secret.GroupVersionKind()}
+	deployment := workload("Deployment", "")
+	deploymentUnit := ResourceUnit{Resource: deployment, Gvk: deployment.GroupVersionKind()}
+
+	// only the ConfigMaps and the Secrets are checksummed
+	checksums := s.configChecksums(appsub, hostSub, []ResourceUnit{configMap("info"), secretUnit, deploymentUnit})
+	g.Expect(checksums).To(HaveLen(2))
+	g.Expect(checksums).To(HaveKey(configRef{kind: "ConfigMap", namespace: "demo-ns", name: "app-config"}))
+
+	// the checksum is stamped in the pod template, not in the workload
+	deployment = workload("Deployment", "demo-ns")
+	setConfigChecksum(deployment, appsub.GetNamespace(), checksums)
+
+	infoChecksum := checksumOf(deployment)
+	g.Expect(infoChecksum).To(HaveLen(64))
+	g.Expect(deployment.GetAnnotations()).To(BeEmpty())
+
+	// the same content stamps the same checksum, a change of the ConfigMap changes it
+	deployment = workload("Deployment", "")
+	setConfigChecksum(deployment, appsub.GetNamespace(), checksums)
+	g.Expect(checksumOf(deployment)).To(Equal(infoChecksum))
+
+	checksums = s.configChecksums(appsub, hostSub, []ResourceUnit{configMap("debug"), secretUnit})
+	deployment = workload("StatefulSet", "demo-ns")
+	setConfigChecksum(deployment, appsub.GetNamespace(), checksums)
+	g.Expect(checksumOf(deployment)).NotTo(BeEmpty())
+	g.Expect(checksumOf(deployment)).NotTo(Equal(infoChecksum))
+
+	// a package patch changing the ConfigMap changes the checksum too
+	debugChecksum := checksumOf(deployment)
+
+	appsub.Spec.PackageOverrides = []*appv1.Overrides{{
+		PackageName: "app-config",
+		Patches: []appv1.PackagePatch{{
+			Type:  appv1.PatchTypeJSONMergePatch,
+			Patch: `{"data": {"log-level": "trace"}}`,
+		}},
+	}}
+	g.Expect(s.LocalClient.Update(context.TODO(), appsub)).To(Succeed())
+
+	checksums = s.configChecksums(appsub, hostSub, []ResourceUnit{configMap("debug"), secretUnit})
+	deployment = workload("StatefulSet", "demo-ns")
+	setConfigChecksum(deployment, appsub.GetNamespace(), checksums)
+	g.Expect(checksumOf(deployment)).NotTo(BeEmpty())
+	g.Expect(checksumOf(deployment)).NotTo(Equal(debugChecksum))
+
+	// the workloads of other namespaces and the pods are left alone
+	deployment = workload("DaemonSet", "other-ns")
+	setConfigChecksum(deployment, appsub.GetNamespace(), checksums)
+	g.Expect(checksumOf(deployment)).To(BeEmpty())
+
+	pod := workload("Deployment", "demo-ns")
+	pod.SetAPIVersion("v1")
+	pod.SetKind("Pod")
+	setConfigChecksum(pod, appsub.GetNamespace(), checksums)
+	g.Expect(checksumOf(pod)).To(BeEmpty())
+
+	// a workload referencing none of the config of the appsub has no checksum
+	deployment = workload("Deployment", "demo-ns")
+	g.Expect(unstructured.SetNestedSlice(deployment.Object, []interface{}{}, "spec", "template", "spec",
+		"volumes")).To(Succeed())
+	setConfigChecksum(deployment, appsub.GetNamespace(), map[configRef]string{
+		{kind: "ConfigMap", namespace: "demo-ns", name: "app-config"}: infoChecksum})
+	g.Expect(checksumOf(deployment)).To(BeEmpty())
+}
@@ -0,0 +1,302 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+const (
+	// defaultDrainTimeout is the time the in-flight apply batches are given to finish on shutdown, it is below the
+	// 30s graceful shutdown timeout of the manager
+	defaultDrainTimeout = 20 * time.Second
+	// checkpointTimeout is the time the interrupted apply batches are given to persist their inventory
+	checkpointTimeout = 5 * time.Second
+)
+
+// ErrDraining is returned by the apply batches received or interrupted while the synchronizer is shutting down
+var ErrDraining = errors.New("the synchronizer is shutting down")
+
+// InterruptedError is returned by an apply batch interrupted by the drain, the subscriber persists its progress in the
+// checkpoint of the appsub and the apply of the same revision after the restart resumes from it
+type InterruptedError struct {
+	// AppliedWaves are the resources applied before the interruption
+	AppliedWaves []utils.CheckpointWave
+	// PendingDeletions are the resources of the previous revision not rendered by the interrupted one
+	PendingDeletions []utils.CheckpointResource
+}
+
+func (e *InterruptedError) Error() string {
+	return ErrDraining.Error()
+}
+
+func (e *InterruptedError) Unwrap() error {
+	return ErrDraining
+}
+
+// acquireWork registers an apply batch, it fails once the synchronizer is draining
+func (sync *KubeSynchronizer) acquireWork() bool {
+	sync.drainMtx.Lock()
+	defer sync.drainMtx.Unlock()
+
+	if sync.stopping {
+		return false
+	}
+
+	sync.inflight.Add(1)
+
+	return true
+}
+
+// releaseWork unregisters an apply batch
+func (sync *KubeSynchronizer) releaseWork() {
+	sync.inflight.Done()
+}
+
+// isStopping checks if the synchronizer is draining
+func (sync *KubeSynchronizer) isStopping() bool {
+	sync.drainMtx.Lock()
+	defer sync.drainMtx.Unlock()
+
+	return sync.stopping
+}
+
+// isInterrupted checks if the drain timed out, the in-flight batches then stop applying and checkpoint their inventory
+func (sync *KubeSynchronizer) isInterrupted() bool {
+	sync.drainMtx.Lock()
+	interrupt := sync.interrupt
+	sync.drainMtx.Unlock()
+
+	if interrupt == nil {
+		return false
+	}
+
+	select {
+	case <-interrupt:
+		return true
+	default:
+		return false
+	}
+}
+
+// drain stops accepting new apply batches and waits for the in-flight ones to finish. The batches still running when
+// the drain times out are interrupted, they persist the inventory of the resources applied so far and the next apply
+// after the restart resumes from it
+func (sync *KubeSynchronizer) drain(timeout time.Duration) {
+	sync.drainMtx.Lock()
+	sync.stopping = true
+
+	if sync.interrupt == nil {
+		sync.interrupt = make(chan struct{})
+	}
+	sync.drainMtx.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		sync.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		klog.Info("the in-flight apply batches are drained")
+
+		return
+	case <-time.After(timeout):
+	}
+
+	klog.Warningf("the in-flight apply batches didn't finish in %v, interrupting them", timeout)
+	close(sync.interrupt)
+
+	select {
+	case <-done:
+		klog.Info("the interrupted apply batches are checkpointed")
+	case <-time.After(checkpointTimeout):
+		klog.Warning("the interrupted apply batches didn't checkpoint, they are re-applied after the restart")
+	}
+}
+
+// checkpointUnitStatuses returns the inventory of an interrupted apply batch, the statuses of the resources applied so
+// far followed by the previous statuses of the resources not reached yet, so none of them are orphaned. The revision and
+// the images are the ones of the previous completed apply, the interrupted revision is not fully applied yet
+func (sync *KubeSynchronizer) checkpointUnitStatuses(appsub *appv1.Subscription,
+	applied []SubscriptionUnitStatus) ([]SubscriptionUnitStatus, string, []string) {
+	pkgstatus := &appv1alpha1.SubscriptionStatus{}
+	key := client.ObjectKey{Name: sync.appsubStatusName(appsub.GetName(), sync.SynchronizerID.Name, true), Namespace: appsub.GetNamespace()}
+
+	if err := sync.LocalClient.Get(context.TODO(), key, pkgstatus); err != nil {
+		klog.Infof("no previous inventory of appsub %v/%v to checkpoint, err: %v", appsub.GetNamespace(), appsub.GetName(), err)
+
+		return applied, "", nil
+	}
+
+	statuses := append([]SubscriptionUnitStatus{}, applied...)
+
+	for _, previous := range pkgstatus.Statuses.SubscriptionStatus {
+		found := false
+
+		for _, status := range applied {
+			if status.Name == previous.Name && status.Namespace == previous.Namespace &&
+				status.Kind == previous.Kind && status.APIVersion == previous.APIVersion {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			statuses = append(statuses, SubscriptionUnitStatus{
+				Name:       previous.Name,
+				Namespace:  previous.Namespace,
+				APIVersion: previous.APIVersion,
+				Kind:       previous.Kind,
+				Phase:      string(previous.Phase),
+				Message:    previous.Message,
+			})
+		}
+	}
+
+	return statuses, pkgstatus.Statuses.Revision, pkgstatus.Statuses.Images
+}
+
+// resumeCheckpoint returns the resources applied before the interruption of the revision, keyed by checkpointKey, and
+// the pending deletions of the interrupted apply. There are none if the last apply of the revision wasn't interrupted
+func (sync *KubeSynchronizer) resumeCheckpoint(appsub *appv1.Subscription,
+	revision string) (map[string]utils.CheckpointResource, []utils.CheckpointResource) {
+	checkpoint, err := utils.GetSubscriptionCheckpoint(sync.LocalClient, appsub)
+	if err != nil {
+		klog.Warningf("failed to get the checkpoint of appsub %v/%v, err: %v", appsub.GetNamespace(), appsub.GetName(), err)
+
+		return nil, nil
+	}
+
+	if !checkpoint.IsInterrupted(revision) {
+		return nil, nil
+	}
+
+	applied := map[string]utils.CheckpointResource{}
+
+	for _, wave := range checkpoint.AppliedWaves {
+		for _, resource := range wave.Resources {
+			applied[checkpointKey(wave.APIVersion, wave.Kind, resource.Namespace, resource.Name)] = resource
+		}
+	}
+
+	klog.Infof("resume the interrupted apply of appsub %v/%v revision %v, applied: %v, pending deletions: %v",
+		appsub.GetNamespace(), appsub.GetName(), revision, len(applied), len(checkpoint.PendingDeletions))
+
+	return applied, checkpoint.PendingDeletions
+}
+
+func checkpointKey(apiVersion, kind, namespace, name string) string {
+	return apiVersion + "/" + kind + "/" + namespace + "/" + name
+}
+
+// appliedWaves returns the waves of the resources deployed so far, the consecutive deployed resources of the same kind
+// in the apply order. renderHashes are the hashes of the rendered manifests by status index
+func appliedWaves(statuses []SubscriptionUnitStatus, renderHashes map[int]string) []utils.CheckpointWave {
+	waves := []utils.CheckpointWave{}
+
+	for i, status := range statuses {
+		renderHash, ok := renderHashes[i]
+		if !ok || status.Phase != string(appv1alpha1.PackageDeployed) {
+			continue
+		}
+
+		if len(waves) == 0 || waves[len(waves)-1].APIVersion != status.APIVersion || waves[len(waves)-1].Kind != status.Kind {
+			waves = append(waves, utils.CheckpointWave{APIVersion: status.APIVersion, Kind: status.Kind})
+		}
+
+		wave := &waves[len(waves)-1]
+		wave.Resources = append(wave.Resources, utils.CheckpointResource{
+			Namespace:  status.Namespace,
+			Name:       status.Name,
+			RenderHash: renderHash,
+			Hash:       status.Hash,
+		})
+	}
+
+	return waves
+}
+
+// pendingDeletions returns the resources of the previous inventory not reached by the interrupted apply and not rendered
+// by the interrupted revision, they are deleted once the revision is fully applied
+func pendingDeletions(previous []SubscriptionUnitStatus, resources []ResourceUnit) []utils.CheckpointResource {
+	rendered := map[string]bool{}
+
+	for _, resource := range resources {
+		if resource.Resource == nil {
+			continue
+		}
+
+		// the namespace of a rendered resource can still be set by the overrides
+		rendered[checkpointKey(resource.Resource.GetAPIVersion(), resource.Resource.GetKind(), "", resource.Resource.GetName())] = true
+	}
+
+	pending := []utils.CheckpointResource{}
+
+	for _, status := range previous {
+		if rendered[checkpointKey(status.APIVersion, status.Kind, "", status.Name)] {
+			continue
+		}
+
+		pending = append(pending, utils.CheckpointResource{
+			APIVersion: status.APIVersion,
+			Kind:       status.Kind,
+			Namespace:  status.Namespace,
+			Name:       status.Name,
+		})
+	}
+
+	return pending
+}
+
+// deletePendingResources deletes the pending deletions of the interrupted apply not deployed by the resumed one. The
+// orphan deletion of the inventory removes most of them, the ones already deleted are skipped
+func (sync *KubeSynchronizer) deletePendingResources(hostSub types.NamespacedName, pending []utils.CheckpointResource,
+	statuses []SubscriptionUnitStatus) {
+	deployed := map[string]bool{}
+
+	for _, status := range statuses {
+		deployed[checkpointKey(status.APIVersion, status.Kind, status.Namespace, status.Name)] = true
+	}
+
+	for _, resource := range pending {
+		if deployed[checkpointKey(resource.APIVersion, resource.Kind, resource.Namespace, resource.Name)] {
+			continue
+		}
+
+		if err := sync.DeleteSingleSubscribedResource(hostSub, appv1alpha1.SubscriptionUnitStatus{
+			APIVersion: resource.APIVersion,
+			Kind:       resource.Kind,
+			Namespace:  resource.Namespace,
+			Name:       resource.Name,
+		}); err != nil {
+			klog.Errorf("failed to delete the pending resource %v %v/%v of appsub %v, err: %v", resource.Kind,
+				resource.Namespace, resource.Name, hostSub.String(), err)
+		}
+	}
+}
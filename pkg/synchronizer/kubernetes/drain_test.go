@@ -0,0 +1,255 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	gosync "sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appSubStatusV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+func TestDrain(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	s := &KubeSynchronizer{}
+
+	g.Expect(s.acquireWork()).To(BeTrue())
+	g.Expect(s.isInterrupted()).To(BeFalse())
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		s.releaseWork()
+	}()
+
+	// the in-flight batch finishes before the timeout
+	s.drain(5 * time.Second)
+	g.Expect(s.isStopping()).To(BeTrue())
+	g.Expect(s.isInterrupted()).To(BeFalse())
+	g.Expect(s.acquireWork()).To(BeFalse())
+
+	s = &KubeSynchronizer{}
+	g.Expect(s.acquireWork()).To(BeTrue())
+
+	go func() {
+		for !s.isInterrupted() {
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		s.releaseWork()
+	}()
+
+	// the in-flight batch is interrupted by the timeout
+	s.drain(100 * time.Millisecond)
+	g.Expect(s.isInterrupted()).To(BeTrue())
+}
+
+func TestCheckpointUnitStatuses(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(appSubStatusV1alpha1.AddToScheme(scheme)).To(Succeed())
+
+	appsub := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "appsub-1", Namespace: "default"}}
+
+	s := &KubeSynchronizer{
+		LocalClient:    fake.NewClientBuilder().WithScheme(scheme).Build(),
+		SynchronizerID: &types.NamespacedName{Name: "cluster1", Namespace: "cluster1"},
+	}
+
+	applied := []SubscriptionUnitStatus{
+		{Name: "cm1", Namespace: "default", APIVersion: "v1", Kind: "ConfigMap", Phase: string(appSubStatusV1alpha1.PackageDeployed)},
+	}
+
+	// no previous inventory, no revision is recorded
+	statuses, revision, images := s.checkpointUnitStatuses(appsub, applied)
+	g.Expect(statuses).To(Equal(applied))
+	g.Expect(revision).To(BeEmpty())
+	g.Expect(images).To(BeEmpty())
+
+	s.LocalClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(&appSubStatusV1alpha1.SubscriptionStatus{
+		ObjectMeta: metav1.ObjectMeta{Name: "appsub-1", Namespace: "default"},
+		Statuses: appSubStatusV1alpha1.SubscriptionClusterStatusMap{
+			Revision: "1234abc",
+			Images:   []string{"nginx:1.21"},
+			SubscriptionStatus: []appSubStatusV1alpha1.SubscriptionUnitStatus{
+				{Name: "cm1", Namespace: "default", APIVersion: "v1", Kind: "ConfigMap", Phase: appSubStatusV1alpha1.PackageDeployFailed},
+				{Name: "cm2", Namespace: "default", APIVersion: "v1", Kind: "ConfigMap", Phase: appSubStatusV1alpha1.PackageDeployed},
+			},
+		},
+	}).Build()
+
+	// the interrupted revision is not fully applied, the inventory keeps the previous completed revision
+	statuses, revision, images = s.checkpointUnitStatuses(appsub, applied)
+	g.Expect(revision).To(Equal("1234abc"))
+	g.Expect(images).To(Equal([]string{"nginx:1.21"}))
+	g.Expect(statuses).To(HaveLen(2))
+	g.Expect(statuses[0]).To(Equal(applied[0]))
+	g.Expect(statuses[1].Name).To(Equal("cm2"))
+	g.Expect(statuses[1].Phase).To(Equal(string(appSubStatusV1alpha1.PackageDeployed)))
+}
+
+func TestResumeInterruptedApply(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(appv1.SchemeBuilder.AddToScheme(scheme)).To(Succeed())
+	g.Expect(appSubStatusV1alpha1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	restMapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Secret"}, meta.RESTScopeNamespace)
+
+	newResource := func(kind, name string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       kind,
+			"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+		}}
+
+		return obj
+	}
+
+	// the previous revision deployed a ConfigMap the new one doesn't render
+	oldConfigMap := newResource("ConfigMap", "old-cm")
+	oldConfigMap.SetAnnotations(map[string]string{appv1.AnnotationHosting: "default/appsub-1"})
+
+	cmGVR := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	secretGVR := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{cmGVR: "ConfigMapList", secretGVR: "SecretList"}, oldConfigMap)
+
+	appsub := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{
+		Name:        "appsub-1",
+		Namespace:   "default",
+		UID:         "1234",
+		Annotations: map[string]string{appv1.AnnotationGitCommit: "2222"},
+	}}
+
+	localClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(appsub.DeepCopy(),
+		&appSubStatusV1alpha1.SubscriptionStatus{
+			ObjectMeta: metav1.ObjectMeta{Name: "appsub-1", Namespace: "default"},
+			Statuses: appSubStatusV1alpha1.SubscriptionClusterStatusMap{
+				Revision: "1111",
+				SubscriptionStatus: []appSubStatusV1alpha1.SubscriptionUnitStatus{
+					{Name: "old-cm", Namespace: "default", APIVersion: "v1", Kind: "ConfigMap",
+						Phase: appSubStatusV1alpha1.PackageDeployed},
+				},
+			},
+		}).Build()
+
+	s := &KubeSynchronizer{
+		LocalClient:    localClient,
+		RemoteClient:   localClient,
+		DynamicClient:  dynamicClient,
+		RestMapper:     restMapper,
+		SynchronizerID: &types.NamespacedName{Name: "cluster1", Namespace: "cluster1"},
+		Extension:      &SubscriptionExtension{},
+		eventrecorder:  &utils.EventRecorder{EventRecorder: record.NewFakeRecorder(10)},
+		interrupt:      make(chan struct{}),
+	}
+
+	// the drain times out once the ConfigMaps wave is applied, before the Secret
+	applies := map[string]int{}
+	mtx := gosync.Mutex{}
+
+	for _, verb := range []string{"create", "update", "patch"} {
+		dynamicClient.PrependReactor(verb, "*", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			mtx.Lock()
+			defer mtx.Unlock()
+
+			applies[action.GetResource().Resource]++
+			if action.GetResource().Resource == "configmaps" && applies["configmaps"] == 2 {
+				close(s.interrupt)
+			}
+
+			return false, nil, nil
+		})
+	}
+
+	resources := func() []ResourceUnit {
+		units := []ResourceUnit{}
+
+		for _, obj := range []*unstructured.Unstructured{
+			newResource("ConfigMap", "cm1"), newResource("ConfigMap", "cm2"), newResource("Secret", "secret1"),
+		} {
+			units = append(units, ResourceUnit{Resource: obj, Gvk: obj.GroupVersionKind()})
+		}
+
+		return units
+	}
+
+	err := s.ProcessSubResources(appsub, resources(), nil, nil, false)
+	g.Expect(errors.Is(err, ErrDraining)).To(BeTrue())
+
+	interrupted := &InterruptedError{}
+	g.Expect(errors.As(err, &interrupted)).To(BeTrue())
+	g.Expect(interrupted.AppliedWaves).To(HaveLen(1))
+	g.Expect(interrupted.AppliedWaves[0].Kind).To(Equal("ConfigMap"))
+	g.Expect(interrupted.AppliedWaves[0].Resources).To(HaveLen(2))
+	g.Expect(interrupted.PendingDeletions).To(Equal([]utils.CheckpointResource{
+		{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "old-cm"},
+	}))
+	g.Expect(applies["secrets"]).To(BeZero())
+
+	// the inventory keeps the previous resources and revision until the revision is fully applied
+	pkgstatus := &appSubStatusV1alpha1.SubscriptionStatus{}
+	g.Expect(s.LocalClient.Get(context.TODO(), client.ObjectKey{Name: "appsub-1", Namespace: "default"}, pkgstatus)).To(Succeed())
+	g.Expect(pkgstatus.Statuses.Revision).To(Equal("1111"))
+	g.Expect(pkgstatus.Statuses.SubscriptionStatus).To(HaveLen(3))
+
+	g.Expect(utils.SaveSubscriptionCheckpoint(s.LocalClient, appsub, utils.SubscriptionCheckpoint{
+		Revision:         "2222",
+		Hash:             utils.SubscriptionCheckpointHash(appsub),
+		Phase:            utils.CheckpointPhaseInterrupted,
+		AppliedWaves:     interrupted.AppliedWaves,
+		PendingDeletions: interrupted.PendingDeletions,
+	})).To(Succeed())
+
+	// after the restart, the apply resumes after the ConfigMaps wave and deletes the pending resources
+	s.interrupt = nil
+	applies = map[string]int{}
+
+	g.Expect(s.ProcessSubResources(appsub, resources(), nil, nil, false)).To(Succeed())
+	g.Expect(applies["configmaps"]).To(BeZero())
+	g.Expect(applies["secrets"]).To(Equal(1))
+
+	_, err = dynamicClient.Resource(cmGVR).Namespace("default").Get(context.TODO(), "old-cm", metav1.GetOptions{})
+	g.Expect(err).To(HaveOccurred())
+
+	g.Expect(s.LocalClient.Get(context.TODO(), client.ObjectKey{Name: "appsub-1", Namespace: "default"}, pkgstatus)).To(Succeed())
+	g.Expect(pkgstatus.Statuses.Revision).To(Equal("2222"))
+	g.Expect(pkgstatus.Statuses.SubscriptionStatus).To(HaveLen(3))
+}
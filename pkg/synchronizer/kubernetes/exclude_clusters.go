@@ -0,0 +1,68 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	appv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+// getClusterLabels returns the labels of the managed cluster set by the hub in the appsub, nil if the appsub has no
+// cluster labels such as the standalone appsubs
+func getClusterLabels(appsub *appv1alpha1.Subscription) map[string]string {
+	labelsJSON := appsub.GetAnnotations()[appv1alpha1.AnnotationClusterLabels]
+	if labelsJSON == "" {
+		return nil
+	}
+
+	clusterLabels := map[string]string{}
+
+	if err := json.Unmarshal([]byte(labelsJSON), &clusterLabels); err != nil {
+		klog.Warningf("failed to parse the cluster labels of appsub %v/%v, err: %v", appsub.Namespace, appsub.Name, err)
+
+		return nil
+	}
+
+	return clusterLabels
+}
+
+// isExcludedCluster checks if the exclude-clusters label selector of the resource matches the cluster labels. The
+// resource is not excluded if the cluster labels are unknown
+func isExcludedCluster(tplunit *unstructured.Unstructured, clusterLabels map[string]string) (bool, error) {
+	selector := tplunit.GetAnnotations()[appv1alpha1.AnnotationExcludeClusters]
+	if selector == "" {
+		return false, nil
+	}
+
+	parsed, err := labels.Parse(selector)
+	if err != nil {
+		return false, errors.NewBadRequest("invalid exclude-clusters selector " + selector + ", err: " + err.Error())
+	}
+
+	if clusterLabels == nil {
+		klog.Warningf("the cluster labels are unknown, skip evaluating the exclude-clusters selector of %v %v/%v",
+			tplunit.GetKind(), tplunit.GetNamespace(), tplunit.GetName())
+
+		return false, nil
+	}
+
+	return parsed.Matches(labels.Set(clusterLabels)), nil
+}
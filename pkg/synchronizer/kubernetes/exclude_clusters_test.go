@@ -0,0 +1,74 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func TestExcludeClusters(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	appsub := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns"}}
+	g.Expect(getClusterLabels(appsub)).To(BeNil())
+
+	appsub.SetAnnotations(map[string]string{appv1.AnnotationClusterLabels: "{invalid"})
+	g.Expect(getClusterLabels(appsub)).To(BeNil())
+
+	appsub.SetAnnotations(map[string]string{appv1.AnnotationClusterLabels: `{"node-role/edge":"true","env":"prod"}`})
+	clusterLabels := getClusterLabels(appsub)
+	g.Expect(clusterLabels).To(Equal(map[string]string{"node-role/edge": "true", "env": "prod"}))
+
+	service := func(selector string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Service",
+			"metadata":   map[string]interface{}{"name": "ingress", "namespace": "demo"},
+		}}
+
+		if selector != "" {
+			obj.SetAnnotations(map[string]string{appv1.AnnotationExcludeClusters: selector})
+		}
+
+		return obj
+	}
+
+	for selector, expected := range map[string]bool{
+		"":                         false,
+		"node-role/edge=true":      true,
+		"node-role/edge=false":     false,
+		"env in (dev,prod)":        true,
+		"!gpu":                     true,
+		"env=prod,!node-role/edge": false,
+	} {
+		excluded, err := isExcludedCluster(service(selector), clusterLabels)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(excluded).To(Equal(expected), selector)
+	}
+
+	_, err := isExcludedCluster(service("env in prod"), clusterLabels)
+	g.Expect(err).To(HaveOccurred())
+
+	// the resources are deployed if the cluster labels are unknown
+	excluded, err := isExcludedCluster(service("!gpu"), nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(excluded).To(BeFalse())
+}
@@ -0,0 +1,260 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+
+	appv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appSubStatusV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+)
+
+const (
+	// generatedResourceInUseMessage is the message of the previous generated resources kept in the appsubstatus
+	generatedResourceInUseMessage = "kept until no workload references the previous generated resource"
+	// generatedResourceRetainedMessage is the message of the previous generated resources kept for the rollbacks
+	generatedResourceRetainedMessage = "kept as a previous revision of the generated resource"
+	// the retained revisions are capped to bound the ConfigMaps and Secrets accumulating in the namespaces
+	maxGeneratedRevisionHistoryLimit = 10
+)
+
+// workloadPodSpecs are the workloads whose pod template keeps a previous generated resource, with the path of their pod
+// spec. The ReplicaSets are left out, the scaled down ReplicaSets of the rollout history keep their old references
+var workloadPodSpecs = []struct {
+	gvr  schema.GroupVersionResource
+	path []string
+}{
+	{gvr: schema.GroupVersionResource{Version: "v1", Resource: "pods"}, path: []string{"spec"}},
+	{gvr: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+		path: []string{"spec", "template", "spec"}},
+	{gvr: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"},
+		path: []string{"spec", "template", "spec"}},
+	{gvr: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"},
+		path: []string{"spec", "template", "spec"}},
+	{gvr: schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "cronjobs"},
+		path: []string{"spec", "jobTemplate", "spec", "template", "spec"}},
+}
+
+// generatedRevisionHistoryLimit returns the number of previous revisions of each generated resource kept for the
+// rollbacks from the generated-revision-history-limit annotation of the appsub, 0 by default
+func generatedRevisionHistoryLimit(appsub *appv1alpha1.Subscription) int {
+	if appsub == nil {
+		return 0
+	}
+
+	v := strings.TrimSpace(appsub.GetAnnotations()[appv1alpha1.AnnotationGeneratedRevisionHistoryLimit])
+	if v == "" {
+		return 0
+	}
+
+	limit, err := strconv.Atoi(v)
+	if err != nil || limit < 0 {
+		klog.Warningf("invalid generated revision history limit %v of appsub %v/%v, the previous revisions are not kept",
+			v, appsub.GetNamespace(), appsub.GetName())
+
+		return 0
+	}
+
+	if limit > maxGeneratedRevisionHistoryLimit {
+		limit = maxGeneratedRevisionHistoryLimit
+	}
+
+	return limit
+}
+
+// keptGeneratedResources returns the messages of the orphan ConfigMaps and Secrets of the kustomize generators kept
+// in the appsubstatus by their unit status key. The previous generated resources are kept after the rollover to a new
+// hash suffix while a workload references them, and the last revisions of each generator up to the revision history
+// limit of the appsub are kept for the rollbacks. The other orphans are deleted
+func (sync *KubeSynchronizer) keptGeneratedResources(appsub *appv1alpha1.Subscription,
+	orphans []appSubStatusV1alpha1.SubscriptionUnitStatus) map[string]string {
+	kept := map[string]string{}
+	revisions := map[string][]*unstructured.Unstructured{}
+
+	for _, orphan := range orphans {
+		obj, err := sync.getGeneratedResource(orphan)
+		if err != nil || obj == nil {
+			continue
+		}
+
+		if inUse, err := sync.isGeneratedResourceInUse(obj); err == nil && inUse {
+			kept[generatedResourceKey(obj)] = generatedResourceInUseMessage
+
+			continue
+		}
+
+		base := obj.GetAnnotations()[appv1alpha1.AnnotationKustomizeGenerated]
+		generator := obj.GetKind() + "/" + obj.GetNamespace() + "/" + base
+		revisions[generator] = append(revisions[generator], obj)
+	}
+
+	limit := generatedRevisionHistoryLimit(appsub)
+
+	for _, objs := range revisions {
+		sort.Slice(objs, func(i, j int) bool {
+			return objs[i].GetCreationTimestamp().Time.After(objs[j].GetCreationTimestamp().Time)
+		})
+
+		for i := 0; i < limit && i < len(objs); i++ {
+			kept[generatedResourceKey(objs[i])] = generatedResourceRetainedMessage
+		}
+	}
+
+	return kept
+}
+
+func generatedResourceKey(obj *unstructured.Unstructured) string {
+	return obj.GetKind() + "/" + obj.GetNamespace() + "/" + obj.GetName()
+}
+
+func unitStatusKey(resource appSubStatusV1alpha1.SubscriptionUnitStatus) string {
+	return resource.Kind + "/" + resource.Namespace + "/" + resource.Name
+}
+
+// getGeneratedResource gets the ConfigMap or the Secret of the unit status if it is generated by a kustomize generator
+func (sync *KubeSynchronizer) getGeneratedResource(resource appSubStatusV1alpha1.SubscriptionUnitStatus) (
+	*unstructured.Unstructured, error) {
+	if resource.APIVersion != "v1" || (resource.Kind != "ConfigMap" && resource.Kind != "Secret") {
+		return nil, nil
+	}
+
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	if resource.Kind == "Secret" {
+		gvr.Resource = "secrets"
+	}
+
+	obj, err := sync.DynamicClient.Resource(gvr).Namespace(resource.Namespace).Get(context.TODO(), resource.Name,
+		metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if obj.GetAnnotations()[appv1alpha1.AnnotationKustomizeGenerated] == "" {
+		return nil, nil
+	}
+
+	return obj, nil
+}
+
+// isGeneratedResourceInUse checks if the generated resource is still referenced by a workload of its namespace, the
+// pods of the previous rollout are running with it until the rollover completes
+func (sync *KubeSynchronizer) isGeneratedResourceInUse(obj *unstructured.Unstructured) (bool, error) {
+	for _, workload := range workloadPodSpecs {
+		list, err := sync.DynamicClient.Resource(workload.gvr).Namespace(obj.GetNamespace()).List(context.TODO(),
+			metav1.ListOptions{})
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return false, err
+		}
+
+		for _, item := range list.Items {
+			if workload.gvr.Resource == "pods" && isPodTerminated(item) {
+				continue
+			}
+
+			spec, found, err := unstructured.NestedMap(item.Object, workload.path...)
+			if err != nil || !found {
+				continue
+			}
+
+			podSpec := &corev1.PodSpec{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(spec, podSpec); err != nil {
+				continue
+			}
+
+			if podSpecReferences(podSpec, obj.GetKind(), obj.GetName()) {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+func isPodTerminated(pod unstructured.Unstructured) bool {
+	phase, _, _ := unstructured.NestedString(pod.Object, "status", "phase")
+
+	return phase == string(corev1.PodSucceeded) || phase == string(corev1.PodFailed)
+}
+
+// podSpecReferences checks if the volumes, the environment or the image pull secrets of the pod spec reference the
+// ConfigMap or the Secret
+func podSpecReferences(spec *corev1.PodSpec, kind, name string) bool {
+	isConfigMap := kind == "ConfigMap"
+
+	for _, volume := range spec.Volumes {
+		if isConfigMap && volume.ConfigMap != nil && volume.ConfigMap.Name == name ||
+			!isConfigMap && volume.Secret != nil && volume.Secret.SecretName == name {
+			return true
+		}
+
+		if volume.Projected == nil {
+			continue
+		}
+
+		for _, source := range volume.Projected.Sources {
+			if isConfigMap && source.ConfigMap != nil && source.ConfigMap.Name == name ||
+				!isConfigMap && source.Secret != nil && source.Secret.Name == name {
+				return true
+			}
+		}
+	}
+
+	if !isConfigMap {
+		for _, pullSecret := range spec.ImagePullSecrets {
+			if pullSecret.Name == name {
+				return true
+			}
+		}
+	}
+
+	containers := append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...)
+
+	for _, container := range containers {
+		for _, envFrom := range container.EnvFrom {
+			if isConfigMap && envFrom.ConfigMapRef != nil && envFrom.ConfigMapRef.Name == name ||
+				!isConfigMap && envFrom.SecretRef != nil && envFrom.SecretRef.Name == name {
+				return true
+			}
+		}
+
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+
+			if isConfigMap && env.ValueFrom.ConfigMapKeyRef != nil && env.ValueFrom.ConfigMapKeyRef.Name == name ||
+				!isConfigMap && env.ValueFrom.SecretKeyRef != nil && env.ValueFrom.SecretKeyRef.Name == name {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,109 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appSubStatusV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+)
+
+func TestKeptGeneratedResources(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	now := time.Now()
+
+	configMap := func(name string, generated bool, age time.Duration) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+		}}
+		obj.SetCreationTimestamp(metav1.NewTime(now.Add(-age)))
+
+		if generated {
+			obj.SetAnnotations(map[string]string{appv1.AnnotationKustomizeGenerated: "app-config"})
+		}
+
+		return obj
+	}
+
+	pod := func(name, configMap, phase string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+			"spec": map[string]interface{}{
+				"containers": []interface{}{map[string]interface{}{
+					"name":    "app",
+					"image":   "nginx",
+					"envFrom": []interface{}{map[string]interface{}{"configMapRef": map[string]interface{}{"name": configMap}}},
+				}},
+			},
+			"status": map[string]interface{}{"phase": phase},
+		}}
+	}
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			{Version: "v1", Resource: "pods"}:                        "PodList",
+			{Group: "apps", Version: "v1", Resource: "deployments"}:  "DeploymentList",
+			{Group: "apps", Version: "v1", Resource: "statefulsets"}: "StatefulSetList",
+			{Group: "apps", Version: "v1", Resource: "daemonsets"}:   "DaemonSetList",
+			{Group: "batch", Version: "v1", Resource: "cronjobs"}:    "CronJobList",
+		},
+		configMap("app-config-old", true, time.Hour), configMap("app-config-older", true, 2*time.Hour),
+		configMap("app-config-oldest", true, 3*time.Hour), configMap("plain", false, time.Hour),
+		pod("running", "app-config-old", "Running"), pod("completed", "app-config-older", "Succeeded"),
+		pod("plain", "plain", "Running"))
+	s := &KubeSynchronizer{DynamicClient: client}
+
+	unit := func(name string) appSubStatusV1alpha1.SubscriptionUnitStatus {
+		return appSubStatusV1alpha1.SubscriptionUnitStatus{APIVersion: "v1", Kind: "ConfigMap", Name: name, Namespace: "default"}
+	}
+
+	orphans := []appSubStatusV1alpha1.SubscriptionUnitStatus{
+		unit("app-config-old"), unit("app-config-older"), unit("app-config-oldest"), unit("plain"), unit("missing"),
+	}
+
+	// the generated resource referenced by a running pod is kept, the resources not generated are deleted as before
+	kept := s.keptGeneratedResources(&appv1.Subscription{}, orphans)
+	g.Expect(kept).To(Equal(map[string]string{"ConfigMap/default/app-config-old": generatedResourceInUseMessage}))
+
+	// the last revisions not referenced anymore are kept up to the revision history limit
+	appsub := &appv1.Subscription{}
+	appsub.SetAnnotations(map[string]string{appv1.AnnotationGeneratedRevisionHistoryLimit: "1"})
+
+	kept = s.keptGeneratedResources(appsub, orphans)
+	g.Expect(kept).To(Equal(map[string]string{
+		"ConfigMap/default/app-config-old":   generatedResourceInUseMessage,
+		"ConfigMap/default/app-config-older": generatedResourceRetainedMessage,
+	}))
+
+	appsub.SetAnnotations(map[string]string{appv1.AnnotationGeneratedRevisionHistoryLimit: "-1"})
+	g.Expect(generatedRevisionHistoryLimit(appsub)).To(Equal(0))
+
+	appsub.SetAnnotations(map[string]string{appv1.AnnotationGeneratedRevisionHistoryLimit: "100"})
+	g.Expect(generatedRevisionHistoryLimit(appsub)).To(Equal(maxGeneratedRevisionHistoryLimit))
+}
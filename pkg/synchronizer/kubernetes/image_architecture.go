@@ -0,0 +1,237 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	appv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+const (
+	// imageArchLookupTimeout bounds the inspection of all the images of an apply, the images not inspected in time
+	// aren't checked
+	imageArchLookupTimeout = time.Minute
+	imageArchLookupWorkers = 4
+)
+
+// getImageArchitectures is replaced in the tests, the images are inspected in their registries
+var getImageArchitectures = utils.GetImageArchitectures
+
+// isCheckImageArchitecture checks if the appsub opts in to check the architectures of its images before applying them
+func isCheckImageArchitecture(appsub *appv1alpha1.Subscription) bool {
+	return strings.EqualFold(appsub.GetAnnotations()[appv1alpha1.AnnotationCheckImageArchitecture], "true")
+}
+
+// lookupImageArchitectures inspects the distinct images with a few workers and returns the architectures of the images
+// inspected within the timeout. The images failing to be inspected, e.g. in the private or unreachable registries, and
+// the images not inspected in time are left out, the late inspections complete in the background and are cached for
+// the next apply
+func lookupImageArchitectures(images []string, timeout time.Duration) map[string][]string {
+	type lookup struct {
+		image string
+		archs []string
+		err   error
+	}
+
+	distinct := []string{}
+	seen := map[string]bool{}
+
+	for _, image := range images {
+		if !seen[image] {
+			seen[image] = true
+
+			distinct = append(distinct, image)
+		}
+	}
+
+	imageArchs := map[string][]string{}
+	if len(distinct) == 0 {
+		return imageArchs
+	}
+
+	queue := make(chan string, len(distinct))
+	for _, image := range distinct {
+		queue <- image
+	}
+
+	close(queue)
+
+	// buffered for all the images, the late workers never block
+	results := make(chan lookup, len(distinct))
+
+	workers := imageArchLookupWorkers
+	if len(distinct) < workers {
+		workers = len(distinct)
+	}
+
+	// the late workers outlive the apply, they keep the lookup function of the apply
+	getArchs := getImageArchitectures
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for image := range queue {
+				archs, err := getArchs(image)
+				results <- lookup{image: image, archs: archs, err: err}
+			}
+		}()
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for received := 0; received < len(distinct); received++ {
+		select {
+		case result := <-results:
+			if result.err != nil {
+				klog.Warningf("skip checking the architectures of image %v, err: %v", result.image, result.err)
+
+				continue
+			}
+
+			imageArchs[result.image] = result.archs
+		case <-timer.C:
+			klog.Warningf("skip checking the architectures of %v images, they aren't inspected within %v",
+				len(distinct)-received, timeout)
+
+			return imageArchs
+		}
+	}
+
+	return imageArchs
+}
+
+// resourceImages returns the images of the resources as they are applied, after the rewrite to the registry mirrors
+func resourceImages(resources []ResourceUnit, mirrors []utils.ImageMirror) []string {
+	images := []string{}
+
+	for _, resource := range resources {
+		if resource.Resource == nil {
+			continue
+		}
+
+		for _, image := range utils.ContainerImages(resource.Resource.Object) {
+			if mirror := utils.MirrorImage(image, mirrors); mirror != "" {
+				image = mirror
+			}
+
+			images = append(images, image)
+		}
+	}
+
+	return images
+}
+
+// checkImageArchitectures returns why the images of the resource can't run on the cluster, empty if they can. The
+// pods with a kubernetes.io/arch node selector run on that architecture, the other pods on any node of the cluster.
+// The images are looked up in imageArchs, the images missing there aren't checked
+func checkImageArchitectures(enabled bool, tplunit *unstructured.Unstructured, clusterArchs []string,
+	imageArchs map[string][]string) string {
+	if !enabled {
+		return ""
+	}
+
+	targetArchs := clusterArchs
+	if arch := utils.GetNodeSelectorArchitecture(tplunit.Object); arch != "" {
+		targetArchs = []string{arch}
+	}
+
+	if len(targetArchs) == 0 {
+		return ""
+	}
+
+	for _, image := range utils.ContainerImages(tplunit.Object) {
+		archs, ok := imageArchs[image]
+		if !ok {
+			continue
+		}
+
+		unsupported := utils.UnsupportedArchitectures(archs, targetArchs)
+
+		switch {
+		case len(unsupported) == len(targetArchs):
+			return fmt.Sprintf("image %v is built for %v, the pods of %v %v/%v run on %v", image,
+				strings.Join(archs, ","), tplunit.GetKind(), tplunit.GetNamespace(), tplunit.GetName(),
+				strings.Join(targetArchs, ","))
+		case len(unsupported) > 0:
+			klog.Warningf("image %v isn't built for %v, set the %v node selector of %v %v/%v to schedule its pods on %v",
+				image, strings.Join(unsupported, ","), utils.NodeArchLabel, tplunit.GetKind(), tplunit.GetNamespace(),
+				tplunit.GetName(), strings.Join(archs, ","))
+		}
+	}
+
+	return ""
+}
+
+// setUnsupportedArchitectureCondition sets the UnsupportedArchitecture condition of the appsub from the resources
+// whose images don't match the cluster architectures, the condition is removed once the appsub opts out
+func (sync *KubeSynchronizer) setUnsupportedArchitectureCondition(appsub *appv1alpha1.Subscription, enabled bool,
+	mismatches []string) {
+	if !enabled && meta.FindStatusCondition(appsub.Status.Conditions, appv1alpha1.ConditionUnsupportedArchitecture) == nil {
+		return
+	}
+
+	hostSub := types.NamespacedName{Namespace: appsub.Namespace, Name: appsub.Name}
+
+	latest := &appv1alpha1.Subscription{}
+	if err := sync.LocalClient.Get(context.TODO(), hostSub, latest); err != nil {
+		klog.Warningf("failed to get appsub %v to set the %v condition, err: %v", hostSub.String(),
+			appv1alpha1.ConditionUnsupportedArchitecture, err)
+
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:               appv1alpha1.ConditionUnsupportedArchitecture,
+		Status:             metav1.ConditionFalse,
+		Reason:             appv1alpha1.ReasonImageArchitecturesSupported,
+		Message:            "the images are built for the architectures of the cluster",
+		ObservedGeneration: latest.GetGeneration(),
+	}
+
+	if len(mismatches) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = appv1alpha1.ReasonImageArchitectureMismatch
+		condition.Message = strings.Join(mismatches, "; ")
+	}
+
+	existing := meta.FindStatusCondition(latest.Status.Conditions, condition.Type)
+
+	switch {
+	case !enabled && existing == nil:
+		return
+	case !enabled:
+		meta.RemoveStatusCondition(&latest.Status.Conditions, condition.Type)
+	case existing != nil && existing.Status == condition.Status && existing.Message == condition.Message &&
+		existing.ObservedGeneration == condition.ObservedGeneration:
+		return
+	default:
+		meta.SetStatusCondition(&latest.Status.Conditions, condition)
+	}
+
+	if err := sync.LocalClient.Status().Update(context.TODO(), latest); err != nil {
+		klog.Warningf("failed to set the %v condition of appsub %v, err: %v", condition.Type, hostSub.String(), err)
+	}
+}
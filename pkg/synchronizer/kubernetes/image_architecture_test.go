@@ -0,0 +1,149 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+func TestImageArchitecture(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	defer func(f func(string) ([]string, error)) { getImageArchitectures = f }(getImageArchitectures)
+
+	getImageArchitectures = func(image string) ([]string, error) {
+		switch image {
+		case "quay.io/org/amd64-only:v1":
+			return []string{"amd64"}, nil
+		case "quay.io/org/multi-arch:v1":
+			return []string{"amd64", "arm64", "ppc64le", "s390x"}, nil
+		}
+
+		return nil, fmt.Errorf("image %v not found", image)
+	}
+
+	deployment := func(image, arch string) *unstructured.Unstructured {
+		podSpec := map[string]interface{}{
+			"containers": []interface{}{map[string]interface{}{"name": "app", "image": image}},
+		}
+
+		if arch != "" {
+			podSpec["nodeSelector"] = map[string]interface{}{utils.NodeArchLabel: arch}
+		}
+
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": "app", "namespace": "demo"},
+			"spec":       map[string]interface{}{"template": map[string]interface{}{"spec": podSpec}},
+		}}
+	}
+
+	arm64 := []string{"arm64"}
+
+	// the images failing to be inspected are left out
+	imageArchs := lookupImageArchitectures([]string{"quay.io/org/amd64-only:v1", "quay.io/org/multi-arch:v1",
+		"quay.io/org/amd64-only:v1", "registry.local/private:v1"}, time.Minute)
+	g.Expect(imageArchs).To(Equal(map[string][]string{
+		"quay.io/org/amd64-only:v1": {"amd64"},
+		"quay.io/org/multi-arch:v1": {"amd64", "arm64", "ppc64le", "s390x"},
+	}))
+
+	g.Expect(checkImageArchitectures(false, deployment("quay.io/org/amd64-only:v1", ""), arm64, imageArchs)).To(BeEmpty())
+	g.Expect(checkImageArchitectures(true, deployment("quay.io/org/amd64-only:v1", ""), arm64, imageArchs)).To(
+		ContainSubstring("image quay.io/org/amd64-only:v1 is built for amd64, the pods of Deployment demo/app run on arm64"))
+	g.Expect(checkImageArchitectures(true, deployment("quay.io/org/multi-arch:v1", ""), arm64, imageArchs)).To(BeEmpty())
+
+	// the mixed clusters and the node selectors
+	g.Expect(checkImageArchitectures(true, deployment("quay.io/org/amd64-only:v1", ""),
+		[]string{"amd64", "arm64"}, imageArchs)).To(BeEmpty())
+	g.Expect(checkImageArchitectures(true, deployment("quay.io/org/amd64-only:v1", "amd64"), arm64,
+		imageArchs)).To(BeEmpty())
+	g.Expect(checkImageArchitectures(true, deployment("quay.io/org/multi-arch:v1", "riscv64"), arm64,
+		imageArchs)).NotTo(BeEmpty())
+
+	// the images not inspected aren't checked
+	g.Expect(checkImageArchitectures(true, deployment("registry.local/private:v1", ""), arm64, imageArchs)).To(BeEmpty())
+
+	// the images of the resources are inspected after the rewrite to the mirrors
+	g.Expect(resourceImages([]ResourceUnit{{Resource: deployment("quay.io/org/app:v1", "")}, {}},
+		[]utils.ImageMirror{{Source: "quay.io/org", Mirrors: []string{"mirror.local/org"}}})).To(Equal(
+		[]string{"mirror.local/org/app:v1"}))
+
+	scheme := runtime.NewScheme()
+	g.Expect(appv1.SchemeBuilder.AddToScheme(scheme)).To(Succeed())
+
+	appsub := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns"}}
+	s := &KubeSynchronizer{LocalClient: fake.NewClientBuilder().WithScheme(scheme).WithObjects(appsub).Build()}
+
+	latest := func() *appv1.Subscription {
+		sub := &appv1.Subscription{}
+		g.Expect(s.LocalClient.Get(context.TODO(), types.NamespacedName{Name: "demo", Namespace: "demo-ns"}, sub)).To(Succeed())
+
+		return sub
+	}
+
+	s.setUnsupportedArchitectureCondition(appsub, true, []string{"image quay.io/org/amd64-only:v1 is built for amd64"})
+	condition := meta.FindStatusCondition(latest().Status.Conditions, appv1.ConditionUnsupportedArchitecture)
+	g.Expect(condition).NotTo(BeNil())
+	g.Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+	g.Expect(condition.Reason).To(Equal(appv1.ReasonImageArchitectureMismatch))
+
+	s.setUnsupportedArchitectureCondition(appsub, true, nil)
+	g.Expect(meta.IsStatusConditionFalse(latest().Status.Conditions, appv1.ConditionUnsupportedArchitecture)).To(BeTrue())
+
+	// the condition is removed once the appsub opts out
+	s.setUnsupportedArchitectureCondition(latest(), false, nil)
+	g.Expect(latest().Status.Conditions).To(BeEmpty())
+}
+
+func TestLookupImageArchitecturesTimeout(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	defer func(f func(string) ([]string, error)) { getImageArchitectures = f }(getImageArchitectures)
+
+	release := make(chan struct{})
+	defer close(release)
+
+	getImageArchitectures = func(image string) ([]string, error) {
+		if image == "registry.slow/app:v1" {
+			<-release
+		}
+
+		return []string{"amd64"}, nil
+	}
+
+	start := time.Now()
+	imageArchs := lookupImageArchitectures([]string{"registry.slow/app:v1", "quay.io/org/app:v1"},
+		100*time.Millisecond)
+
+	// the slow image doesn't hold the apply past the timeout
+	g.Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+	g.Expect(imageArchs).To(Equal(map[string][]string{"quay.io/org/app:v1": {"amd64"}}))
+}
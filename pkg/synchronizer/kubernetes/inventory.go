@@ -0,0 +1,102 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	appv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+// manifestHash returns the hash of the applied manifest, the status and the server side metadata are not part of it
+func manifestHash(obj *unstructured.Unstructured) string {
+	if obj == nil {
+		return ""
+	}
+
+	manifest := obj.DeepCopy()
+	unstructured.RemoveNestedField(manifest.Object, "status")
+
+	for _, field := range []string{"resourceVersion", "uid", "generation", "creationTimestamp", "managedFields"} {
+		unstructured.RemoveNestedField(manifest.Object, "metadata", field)
+	}
+
+	data, err := json.Marshal(manifest.Object)
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:8])
+}
+
+// appliedRevision returns the revision of the applied resources, the commit the git subscriber sets in the
+// git-current-commit annotation or the versions of the HelmRelease charts, else the revisions fetched by the other
+// subscribers
+func appliedRevision(appsub *appv1alpha1.Subscription, resources []ResourceUnit) string {
+	if commit := appsub.GetAnnotations()[appv1alpha1.AnnotationGitCommit]; commit != "" {
+		return commit
+	}
+
+	charts := []string{}
+
+	for _, resource := range resources {
+		if resource.Resource == nil || resource.Gvk.Kind != "HelmRelease" {
+			continue
+		}
+
+		chart, _, _ := unstructured.NestedString(resource.Resource.Object, "repo", "chartName")
+		version, _, _ := unstructured.NestedString(resource.Resource.Object, "repo", "version")
+
+		if chart != "" && version != "" {
+			charts = append(charts, chart+":"+version)
+		}
+	}
+
+	if len(charts) > 0 {
+		return strings.Join(uniqueSorted(charts), ",")
+	}
+
+	revisions := []string{}
+	for _, revision := range appsub.Status.Revisions {
+		revisions = append(revisions, revision)
+	}
+
+	return strings.Join(uniqueSorted(revisions), ",")
+}
+
+// uniqueSorted returns the sorted values without duplicates
+func uniqueSorted(values []string) []string {
+	set := map[string]bool{}
+	sorted := []string{}
+
+	for _, v := range values {
+		if v != "" && !set[v] {
+			set[v] = true
+			sorted = append(sorted, v)
+		}
+	}
+
+	sort.Strings(sorted)
+
+	return sorted
+}
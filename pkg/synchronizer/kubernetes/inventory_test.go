@@ -0,0 +1,79 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func TestInventory(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deployment := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "demo", "namespace": "demo-ns"},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "app", "image": "quay.io/demo/app:1.0"},
+					},
+				},
+			},
+		},
+	}}
+
+	// the hash doesn't change with the status and the server side metadata
+	hash := manifestHash(deployment)
+	g.Expect(hash).To(HaveLen(16))
+
+	applied := deployment.DeepCopy()
+	applied.SetResourceVersion("42")
+	g.Expect(unstructured.SetNestedField(applied.Object, int64(1), "status", "replicas")).To(Succeed())
+	g.Expect(manifestHash(applied)).To(Equal(hash))
+
+	g.Expect(unstructured.SetNestedField(applied.Object, int64(3), "spec", "replicas")).To(Succeed())
+	g.Expect(manifestHash(applied)).NotTo(Equal(hash))
+
+	// the revision is the applied commit or the versions of the charts
+	sub := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns"}}
+
+	chart := func(name, version string) ResourceUnit {
+		return ResourceUnit{
+			Resource: &unstructured.Unstructured{Object: map[string]interface{}{
+				"repo": map[string]interface{}{"chartName": name, "version": version},
+			}},
+			Gvk: schema.GroupVersionKind{Group: "apps.open-cluster-management.io", Version: "v1", Kind: "HelmRelease"},
+		}
+	}
+
+	resources := []ResourceUnit{chart("nginx", "1.2.0"), chart("redis", "7.0.1"), chart("nginx", "1.2.0"),
+		{Resource: deployment, Gvk: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}}}
+
+	g.Expect(appliedRevision(sub, resources)).To(Equal("nginx:1.2.0,redis:7.0.1"))
+
+	sub.SetAnnotations(map[string]string{appv1.AnnotationGitCommit: "c1"})
+	g.Expect(appliedRevision(sub, resources)).To(Equal("c1"))
+
+	g.Expect(uniqueSorted([]string{"b", "a", "", "b"})).To(Equal([]string{"a", "b"}))
+}
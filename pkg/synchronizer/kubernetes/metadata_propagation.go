@@ -0,0 +1,59 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	appv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+// propagateMetadata sets the labels and annotations of the appsub selected by its metadata propagation on a resource.
+// Without metadata propagation, all the labels of the appsub are set unless the template of the resource has them
+func propagateMetadata(template *unstructured.Unstructured, appsub *appv1alpha1.Subscription) {
+	propagation := appsub.Spec.MetadataPropagation
+	if propagation == nil {
+		template.SetLabels(mergeMetadata(template.GetLabels(), appsub.GetLabels(), false))
+
+		return
+	}
+
+	template.SetLabels(mergeMetadata(template.GetLabels(),
+		utils.PropagatedMetadata(appsub.GetLabels(), propagation.Labels), propagation.Overwrite))
+
+	if propagation.Annotations != nil {
+		template.SetAnnotations(mergeMetadata(template.GetAnnotations(),
+			utils.PropagatedMetadata(appsub.GetAnnotations(), propagation.Annotations), propagation.Overwrite))
+	}
+}
+
+// mergeMetadata adds the propagated labels or annotations to the ones of a template, the ones of the template are
+// kept unless overwritten
+func mergeMetadata(tplMetadata, propagated map[string]string, overwrite bool) map[string]string {
+	if tplMetadata == nil {
+		tplMetadata = make(map[string]string)
+	}
+
+	for k, v := range propagated {
+		if _, ok := tplMetadata[k]; ok && !overwrite {
+			continue
+		}
+
+		tplMetadata[k] = v
+	}
+
+	return tplMetadata
+}
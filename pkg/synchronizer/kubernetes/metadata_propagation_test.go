@@ -0,0 +1,96 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func TestMetadataPropagation(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(appv1.SchemeBuilder.AddToScheme(scheme)).To(Succeed())
+
+	appsub := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{
+		Name:      "demo",
+		Namespace: "demo-ns",
+		Labels:    map[string]string{"app": "demo", "team": "payments", "cost-center.example.com/id": "42"},
+		Annotations: map[string]string{
+			"cost-center.example.com/owner": "jane",
+			"environment":                   "production",
+			appv1.AnnotationGitBranch:       "main",
+		},
+	}}
+
+	s := &KubeSynchronizer{
+		LocalClient:    fake.NewClientBuilder().WithScheme(scheme).WithObjects(appsub).Build(),
+		Extension:      &SubscriptionExtension{},
+		SynchronizerID: &types.NamespacedName{Name: "cluster1"},
+	}
+
+	override := func(propagation *appv1.MetadataPropagation) *unstructured.Unstructured {
+		appsub.Spec.MetadataPropagation = propagation
+		g.Expect(s.LocalClient.Update(context.TODO(), appsub)).To(Succeed())
+
+		tpl := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "app-config", "namespace": "demo-ns"},
+		}}
+		tpl.SetLabels(map[string]string{"team": "platform"})
+		tpl.SetAnnotations(map[string]string{"environment": "staging"})
+
+		obj, err := s.OverrideResource(types.NamespacedName{Name: "demo", Namespace: "demo-ns"}, &ResourceUnit{Resource: tpl})
+		g.Expect(err).NotTo(HaveOccurred())
+
+		return obj
+	}
+
+	// all the labels of the appsub are propagated without overriding the template, and no annotation
+	obj := override(nil)
+	g.Expect(obj.GetLabels()).To(Equal(map[string]string{"app": "demo", "team": "platform", "cost-center.example.com/id": "42"}))
+	g.Expect(obj.GetAnnotations()).NotTo(HaveKey("cost-center.example.com/owner"))
+
+	// the selected labels and annotations are propagated, the keys of the subscription controller never are
+	obj = override(&appv1.MetadataPropagation{
+		Labels:      &appv1.MetadataKeySelector{Include: []string{"team", "cost-center.example.com/*"}},
+		Annotations: &appv1.MetadataKeySelector{Exclude: []string{"environment"}},
+	})
+	g.Expect(obj.GetLabels()).To(Equal(map[string]string{"team": "platform", "cost-center.example.com/id": "42"}))
+	g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue("cost-center.example.com/owner", "jane"))
+	g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue("environment", "staging"))
+	g.Expect(obj.GetAnnotations()).NotTo(HaveKey(appv1.AnnotationGitBranch))
+
+	// the propagated labels and annotations overwrite the ones of the template
+	obj = override(&appv1.MetadataPropagation{
+		Labels:      &appv1.MetadataKeySelector{Exclude: []string{"*"}},
+		Annotations: &appv1.MetadataKeySelector{Include: []string{"environment"}},
+		Overwrite:   true,
+	})
+	g.Expect(obj.GetLabels()).To(Equal(map[string]string{"team": "platform"}))
+	g.Expect(obj.GetAnnotations()).To(HaveKeyWithValue("environment", "production"))
+	g.Expect(obj.GetAnnotations()).NotTo(HaveKey("cost-center.example.com/owner"))
+}
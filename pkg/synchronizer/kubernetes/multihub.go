@@ -0,0 +1,76 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+// hubReportTarget returns the client of the hub the appsub is propagated from and the namespace of the cluster on that
+// hub, the status of an appsub is only reported to its own hub
+func (sync *KubeSynchronizer) hubReportTarget(appsub *appv1.Subscription, cluster string) (client.Client, string) {
+	if sync.hub || sync.standalone || len(sync.hubClients) == 0 {
+		return sync.RemoteClient, cluster
+	}
+
+	return utils.SelectHubClient(appsub, sync.RemoteClient, sync.hubClients), utils.SelectHubClusterName(appsub, cluster)
+}
+
+// ReportHubConflict reports to the hub of the appsub that another hub propagating the same appsub to the cluster takes
+// precedence, the appsub of this hub isn't deployed. The conflict is cleared by the next status of the appsub
+func (sync *KubeSynchronizer) ReportHubConflict(appsub *appv1.Subscription, winner string) error {
+	winner = utils.HubDisplayName(winner)
+	rClient, clusterAppsubReportNs := sync.hubReportTarget(appsub, sync.SynchronizerID.Name)
+
+	appsubReport, err := getClusterAppsubReport(rClient, clusterAppsubReportNs, true)
+	if err != nil {
+		return err
+	}
+
+	source := appsub.Namespace + "/" + appsub.Name
+	failed := v1alpha1.SubscriptionResult("failed")
+
+	for _, result := range appsubReport.Results {
+		if result.Source != source {
+			continue
+		}
+
+		if result.Result == failed && result.Conflict == winner {
+			return nil
+		}
+
+		result.Result = failed
+		result.Conflict = winner
+
+		return rClient.Update(context.TODO(), appsubReport)
+	}
+
+	appsubReport.Results = append(appsubReport.Results, &v1alpha1.SubscriptionReportResult{
+		Source:    source,
+		Result:    failed,
+		Timestamp: metav1.Timestamp{Seconds: time.Now().Unix()},
+		Conflict:  winner,
+	})
+
+	return rClient.Update(context.TODO(), appsubReport)
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appSubStatusV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+func TestReportHubConflict(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	utils.SetAdditionalHubConfigs([]utils.HubConfig{{Name: "hub2", ClusterName: "cluster-x"}})
+	defer utils.SetAdditionalHubConfigs(nil)
+
+	scheme := runtime.NewScheme()
+	g.Expect(appSubStatusV1alpha1.AddToScheme(scheme)).To(Succeed())
+
+	primary := fake.NewClientBuilder().WithScheme(scheme).Build()
+	hub2 := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	s := &KubeSynchronizer{
+		RemoteClient:   primary,
+		hubClients:     map[string]client.Client{"hub2": hub2},
+		SynchronizerID: &types.NamespacedName{Name: "cluster1", Namespace: "cluster1"},
+	}
+
+	appsub := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{
+		Name:        "appsub-1",
+		Namespace:   "default",
+		Annotations: map[string]string{appv1.AnnotationHubName: "hub2"},
+	}}
+
+	// the status of the appsub is reported to the namespace of the cluster on its hub
+	rClient, ns := s.hubReportTarget(appsub, "cluster1")
+	g.Expect(rClient).To(BeIdenticalTo(hub2))
+	g.Expect(ns).To(Equal("cluster-x"))
+
+	rClient, ns = s.hubReportTarget(&appv1.Subscription{}, "cluster1")
+	g.Expect(rClient).To(BeIdenticalTo(primary))
+	g.Expect(ns).To(Equal("cluster1"))
+
+	// the primary hub propagating the same appsub takes precedence
+	g.Expect(s.ReportHubConflict(appsub, "")).To(Succeed())
+
+	report := &appSubStatusV1alpha1.SubscriptionReport{}
+	g.Expect(hub2.Get(context.TODO(), client.ObjectKey{Name: "cluster-x", Namespace: "cluster-x"}, report)).To(Succeed())
+	g.Expect(report.Results).To(HaveLen(1))
+	g.Expect(report.Results[0].Source).To(Equal("default/appsub-1"))
+	g.Expect(report.Results[0].Result).To(Equal(appSubStatusV1alpha1.SubscriptionResult("failed")))
+	g.Expect(report.Results[0].Conflict).To(Equal(utils.PrimaryHubName))
+
+	g.Expect(primary.Get(context.TODO(), client.ObjectKey{Name: "cluster1", Namespace: "cluster1"},
+		&appSubStatusV1alpha1.SubscriptionReport{})).NotTo(Succeed())
+
+	// the conflict is cleared once the appsub of the hub is deployed
+	g.Expect(updateAppsubReportResult(hub2, "default", "appsub-1", "cluster-x", false, false, false,
+		appSubStatusV1alpha1.SubscriptionClusterStatusMap{})).To(Succeed())
+	g.Expect(hub2.Get(context.TODO(), client.ObjectKey{Name: "cluster-x", Namespace: "cluster-x"}, report)).To(Succeed())
+	g.Expect(report.Results[0].Result).To(Equal(appSubStatusV1alpha1.SubscriptionResult("deployed")))
+	g.Expect(report.Results[0].Conflict).To(BeEmpty())
+}
@@ -0,0 +1,89 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	appv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appSubStatusV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+)
+
+// pruneCreatedNamespaces deletes the namespaces created by the deleted appsub if its namespace creation prunes them.
+// The namespaces holding the resources of other appsubs are kept. It returns the unit statuses of the namespaces, the
+// namespaces failing to be deleted stay in the appsubstatus and are deleted on the next purge
+func (sync *KubeSynchronizer) pruneCreatedNamespaces(appsub *appv1alpha1.Subscription, hostSub types.NamespacedName,
+	appSubStatus *appSubStatusV1alpha1.SubscriptionStatus) []SubscriptionUnitStatus {
+	if appsub.Spec.NamespaceCreation == nil || !appsub.Spec.NamespaceCreation.Prune {
+		return nil
+	}
+
+	unitStatuses := []SubscriptionUnitStatus{}
+
+	for _, ns := range appSubStatus.Statuses.CreatedNamespaces {
+		nsStatus := appSubStatusV1alpha1.SubscriptionUnitStatus{APIVersion: "v1", Kind: "Namespace", Name: ns}
+		unitStatus := SubscriptionUnitStatus{APIVersion: "v1", Kind: "Namespace", Name: ns}
+
+		inUse, err := sync.isNamespaceInUse(ns, appSubStatus)
+		if err == nil && inUse {
+			klog.Infof("appsub: %v, namespace: %v holds the resources of other appsubs, skip pruning", hostSub, ns)
+
+			continue
+		}
+
+		if err == nil {
+			klog.Infof("appsub: %v, prune created namespace: %v", hostSub, ns)
+
+			err = sync.DeleteSingleSubscribedResource(hostSub, nsStatus)
+		}
+
+		if err != nil {
+			unitStatus.Phase = string(appSubStatusV1alpha1.PackageDeployFailed)
+			unitStatus.Message = err.Error()
+		} else {
+			unitStatus.Phase = string(appSubStatusV1alpha1.PackageDeployed)
+		}
+
+		unitStatuses = append(unitStatuses, unitStatus)
+	}
+
+	return unitStatuses
+}
+
+// isNamespaceInUse checks if another appsubstatus of the cluster has resources in the namespace
+func (sync *KubeSynchronizer) isNamespaceInUse(ns string, appSubStatus *appSubStatusV1alpha1.SubscriptionStatus) (bool, error) {
+	appsubStatusList := &appSubStatusV1alpha1.SubscriptionStatusList{}
+	if err := sync.LocalClient.List(context.TODO(), appsubStatusList); err != nil {
+		return false, fmt.Errorf("failed to list the appsubstatuses to prune namespace %v, err: %w", ns, err)
+	}
+
+	for _, other := range appsubStatusList.Items {
+		if other.Namespace == appSubStatus.Namespace && other.Name == appSubStatus.Name {
+			continue
+		}
+
+		for _, unitStatus := range other.Statuses.SubscriptionStatus {
+			if unitStatus.Namespace == ns {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appSubStatusV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+)
+
+func TestNamespaceCreation(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cmGVR := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	nsGVR := schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{cmGVR: "ConfigMapList", nsGVR: "NamespaceList"})
+
+	// the configmaps of the missing namespaces are rejected like the API server does
+	client.PrependReactor("create", "configmaps", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		ns := action.GetNamespace()
+		if _, err := client.Tracker().Get(nsGVR, "", ns); err != nil {
+			return true, nil, errors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, ns)
+		}
+
+		return false, nil, nil
+	})
+
+	configMap := func(ns string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "app-config", "namespace": ns},
+		}}
+		obj.SetAnnotations(map[string]string{appv1.AnnotationHosting: "demo-ns/demo"})
+
+		return obj
+	}
+
+	s := &KubeSynchronizer{DynamicClient: client}
+
+	// the namespace creation is disabled
+	created, err := s.createNewResourceByTemplateUnit(client.Resource(cmGVR).Namespace("team-a"), configMap("team-a"),
+		&appv1.NamespaceCreation{Disabled: true})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(created).To(BeFalse())
+
+	// the namespace is created with the labels and the annotations of the namespace creation
+	created, err = s.createNewResourceByTemplateUnit(client.Resource(cmGVR).Namespace("team-a"), configMap("team-a"),
+		&appv1.NamespaceCreation{
+			Labels:      map[string]string{"pod-security.kubernetes.io/enforce": "restricted"},
+			Annotations: map[string]string{appv1.AnnotationHosting: "other-ns/other", "owner": "team-a"},
+		})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(created).To(BeTrue())
+
+	ns, err := client.Resource(nsGVR).Get(context.TODO(), "team-a", metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ns.GetLabels()).To(HaveKeyWithValue("pod-security.kubernetes.io/enforce", "restricted"))
+	g.Expect(ns.GetAnnotations()).To(HaveKeyWithValue("owner", "team-a"))
+	g.Expect(ns.GetAnnotations()).To(HaveKeyWithValue(appv1.AnnotationHosting, "demo-ns/demo"))
+
+	// the namespace exists
+	cm := configMap("team-a")
+	cm.SetName("app-config-2")
+	created, err = s.createNewResourceByTemplateUnit(client.Resource(cmGVR).Namespace("team-a"), cm, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(created).To(BeFalse())
+
+	// the namespaces holding the resources of other appsubs are not pruned
+	scheme := runtime.NewScheme()
+	g.Expect(appSubStatusV1alpha1.AddToScheme(scheme)).To(Succeed())
+
+	appsubStatus := func(name, ns string) *appSubStatusV1alpha1.SubscriptionStatus {
+		return &appSubStatusV1alpha1.SubscriptionStatus{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "demo-ns"},
+			Statuses: appSubStatusV1alpha1.SubscriptionClusterStatusMap{
+				SubscriptionStatus: []appSubStatusV1alpha1.SubscriptionUnitStatus{{Name: "app-config", Namespace: ns}},
+			},
+		}
+	}
+
+	demo := appsubStatus("demo", "team-a")
+	s.LocalClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(demo, appsubStatus("other", "team-b")).Build()
+
+	inUse, err := s.isNamespaceInUse("team-a", demo)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(inUse).To(BeFalse())
+
+	inUse, err = s.isNamespaceInUse("team-b", demo)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(inUse).To(BeTrue())
+}
@@ -0,0 +1,115 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+
+	appv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+// validateOwnerReferences rejects the ownerReferences the garbage collector can't resolve. The owner of a namespaced
+// resource must be in the namespace of the resource, the garbage collector deletes the resource otherwise. The
+// cluster-scoped owners and the owners of the cluster-scoped resources are also rejected, the resources are parented
+// to their appsub with the set-owner-reference annotation instead
+func (sync *KubeSynchronizer) validateOwnerReferences(tplunit *unstructured.Unstructured, namespaced bool) error {
+	for _, ref := range tplunit.GetOwnerReferences() {
+		ownerKey := fmt.Sprintf("%v %v", ref.Kind, ref.Name)
+
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil {
+			return errors.NewBadRequest(fmt.Sprintf("invalid ownerReference %v, err: %v", ownerKey, err))
+		}
+
+		ownerGVR, ownerNamespaced, err := sync.getGVRfromGVK(gv.Group, gv.Version, ref.Kind)
+		if err != nil {
+			return errors.NewBadRequest(fmt.Sprintf("unknown kind of ownerReference %v, err: %v", ownerKey, err))
+		}
+
+		if !ownerNamespaced {
+			return errors.NewBadRequest(fmt.Sprintf("the ownerReference %v is cluster-scoped, it is not allowed", ownerKey))
+		}
+
+		if !namespaced {
+			return errors.NewBadRequest(fmt.Sprintf("the cluster-scoped resource can't be owned by the namespaced %v", ownerKey))
+		}
+
+		owner, err := sync.DynamicClient.Resource(ownerGVR).Namespace(tplunit.GetNamespace()).Get(context.TODO(),
+			ref.Name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return errors.NewBadRequest(fmt.Sprintf("the ownerReference %v is not in the namespace %v, it is not allowed",
+				ownerKey, tplunit.GetNamespace()))
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to get ownerReference %v, err: %w", ownerKey, err)
+		}
+
+		if ref.UID != "" && owner.GetUID() != ref.UID {
+			return errors.NewBadRequest(fmt.Sprintf("the uid of the ownerReference %v doesn't match the owner in namespace %v",
+				ownerKey, tplunit.GetNamespace()))
+		}
+	}
+
+	return nil
+}
+
+// isParentedToAppSub checks if the resource opts in to be owned by its appsub, with the set-owner-reference annotation
+// of the appsub for all its resources or of the resource
+func isParentedToAppSub(appsub *appv1alpha1.Subscription, tplunit *unstructured.Unstructured) bool {
+	return strings.EqualFold(appsub.GetAnnotations()[appv1alpha1.AnnotationSetOwnerReference], "true") ||
+		strings.EqualFold(tplunit.GetAnnotations()[appv1alpha1.AnnotationSetOwnerReference], "true")
+}
+
+// setAppSubOwnerReference parents the resource to its appsub so the resource is deleted with the appsub by the garbage
+// collector. Only the resources in the namespace of the appsub can be owned by the appsub, the other resources are
+// deleted by the agent from the appsubstatus inventory
+func setAppSubOwnerReference(appsub *appv1alpha1.Subscription, tplunit *unstructured.Unstructured, namespaced bool) {
+	if !isParentedToAppSub(appsub, tplunit) {
+		return
+	}
+
+	if !namespaced || tplunit.GetNamespace() != appsub.Namespace {
+		klog.Infof("skip setting the ownerReference of appsub %v/%v on %v %v/%v, it is not in the appsub namespace",
+			appsub.Namespace, appsub.Name, tplunit.GetKind(), tplunit.GetNamespace(), tplunit.GetName())
+
+		return
+	}
+
+	refs := []metav1.OwnerReference{}
+
+	for _, ref := range tplunit.GetOwnerReferences() {
+		if ref.UID != appsub.UID {
+			refs = append(refs, ref)
+		}
+	}
+
+	refs = append(refs, metav1.OwnerReference{
+		APIVersion: appv1alpha1.SchemeGroupVersion.String(),
+		Kind:       "Subscription",
+		Name:       appsub.Name,
+		UID:        appsub.UID,
+	})
+
+	tplunit.SetOwnerReferences(refs)
+}
@@ -0,0 +1,100 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func TestOwnerReferences(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+	restMapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+
+	owner := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "owner", "namespace": "team-a", "uid": "owner-uid"},
+	}}
+
+	cmGVR := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{cmGVR: "ConfigMapList"}, owner)
+
+	s := &KubeSynchronizer{DynamicClient: client, RestMapper: restMapper}
+
+	ownedBy := func(ns string, refs ...metav1.OwnerReference) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "app-config", "namespace": ns},
+		}}
+		obj.SetOwnerReferences(refs)
+
+		return obj
+	}
+
+	ownerRef := metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "owner", UID: "owner-uid"}
+
+	g.Expect(s.validateOwnerReferences(ownedBy("team-a"), true)).To(Succeed())
+	g.Expect(s.validateOwnerReferences(ownedBy("team-a", ownerRef), true)).To(Succeed())
+
+	// the owner in another namespace
+	g.Expect(s.validateOwnerReferences(ownedBy("team-b", ownerRef), true)).NotTo(Succeed())
+
+	// the owner recreated with another uid
+	staleRef := ownerRef
+	staleRef.UID = "stale-uid"
+	g.Expect(s.validateOwnerReferences(ownedBy("team-a", staleRef), true)).NotTo(Succeed())
+
+	// the cluster-scoped owners, the cluster-scoped dependents and the unknown kinds
+	nsRef := metav1.OwnerReference{APIVersion: "v1", Kind: "Namespace", Name: "team-a"}
+	g.Expect(s.validateOwnerReferences(ownedBy("team-a", nsRef), true)).NotTo(Succeed())
+	g.Expect(s.validateOwnerReferences(ownedBy("", ownerRef), false)).NotTo(Succeed())
+	g.Expect(s.validateOwnerReferences(ownedBy("team-a",
+		metav1.OwnerReference{APIVersion: "example.com/v1", Kind: "Widget", Name: "w"}), true)).NotTo(Succeed())
+
+	// the resources in the appsub namespace are parented to the appsub with the set-owner-reference annotation
+	appsub := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "team-a", UID: types.UID("appsub-uid")}}
+
+	tplunit := ownedBy("team-a", ownerRef)
+	setAppSubOwnerReference(appsub, tplunit, true)
+	g.Expect(tplunit.GetOwnerReferences()).To(HaveLen(1))
+
+	appsub.SetAnnotations(map[string]string{appv1.AnnotationSetOwnerReference: "true"})
+
+	setAppSubOwnerReference(appsub, tplunit, true)
+	setAppSubOwnerReference(appsub, tplunit, true)
+	g.Expect(tplunit.GetOwnerReferences()).To(Equal([]metav1.OwnerReference{ownerRef, {
+		APIVersion: "apps.open-cluster-management.io/v1", Kind: "Subscription", Name: "demo", UID: "appsub-uid",
+	}}))
+
+	otherNs := ownedBy("team-b")
+	setAppSubOwnerReference(appsub, otherNs, true)
+	g.Expect(otherNs.GetOwnerReferences()).To(BeEmpty())
+}
@@ -0,0 +1,53 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"k8s.io/klog/v2"
+
+	appv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+// isPreview checks if the appsub deploys a pull request preview
+func isPreview(appsub *appv1alpha1.Subscription) bool {
+	return appsub.GetAnnotations()[appv1alpha1.AnnotationGitPullRequest] != ""
+}
+
+// isolatePreviewResources keeps the resources of a pull request preview apart from the resources of the other
+// environments, so the preview teardown never deletes them. The namespaced resources are deployed into the appsub
+// namespace, the cluster-scoped resources are skipped and the resources owned by the other appsubs are never updated.
+func (sync *KubeSynchronizer) isolatePreviewResources(appsub *appv1alpha1.Subscription, resources []ResourceUnit) []ResourceUnit {
+	isolated := []ResourceUnit{}
+
+	for _, resource := range resources {
+		if !sync.IsResourceNamespaced(resource.Resource) {
+			klog.Infof("skip the cluster-scoped %v %v of the preview appsub %v/%v", resource.Resource.GetKind(),
+				resource.Resource.GetName(), appsub.GetNamespace(), appsub.GetName())
+
+			continue
+		}
+
+		resource.Resource.SetNamespace(appsub.GetNamespace())
+
+		if annotations := resource.Resource.GetAnnotations(); annotations != nil {
+			delete(annotations, appv1alpha1.AnnotationClusterAdmin)
+			resource.Resource.SetAnnotations(annotations)
+		}
+
+		isolated = append(isolated, resource)
+	}
+
+	return isolated
+}
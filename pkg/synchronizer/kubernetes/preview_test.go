@@ -0,0 +1,103 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appSubStatusV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+)
+
+func TestIsolatePreviewResources(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	restMapper.Add(schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"},
+		meta.RESTScopeRoot)
+
+	deployment := func(namespace, host string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": "app", "namespace": namespace},
+		}}
+		obj.SetAnnotations(map[string]string{appv1.AnnotationHosting: host})
+
+		return obj
+	}
+
+	deployGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	s := &KubeSynchronizer{
+		RestMapper: restMapper,
+		DynamicClient: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{deployGVR: "DeploymentList"},
+			deployment("prod", "prod/app-sub"), deployment("app-pr-42", "app-pr-42/app-sub")),
+	}
+
+	appsub := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{
+		Name:        "app-sub",
+		Namespace:   "app-pr-42",
+		Annotations: map[string]string{appv1.AnnotationGitPullRequest: "42"},
+	}}
+	g.Expect(isPreview(appsub)).To(BeTrue())
+
+	// the manifests of the pull request target the production namespace
+	tplDeployment := deployment("prod", "app-pr-42/app-sub")
+	tplDeployment.SetAnnotations(map[string]string{appv1.AnnotationClusterAdmin: "true"})
+
+	tplClusterRole := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "rbac.authorization.k8s.io/v1",
+		"kind":       "ClusterRole",
+		"metadata":   map[string]interface{}{"name": "app"},
+	}}
+
+	resources := s.isolatePreviewResources(appsub, []ResourceUnit{
+		{Resource: tplDeployment, Gvk: tplDeployment.GroupVersionKind()},
+		{Resource: tplClusterRole, Gvk: tplClusterRole.GroupVersionKind()},
+	})
+	g.Expect(resources).To(HaveLen(1))
+	g.Expect(resources[0].Resource.GetNamespace()).To(Equal("app-pr-42"))
+	g.Expect(resources[0].Resource.GetAnnotations()).NotTo(HaveKey(appv1.AnnotationClusterAdmin))
+
+	// the teardown of the preview only deletes the resources of the preview
+	previewKey := types.NamespacedName{Namespace: "app-pr-42", Name: "app-sub"}
+
+	for _, ns := range []string{"app-pr-42", "prod"} {
+		g.Expect(s.DeleteSingleSubscribedResource(previewKey, appSubStatusV1alpha1.SubscriptionUnitStatus{
+			APIVersion: "apps/v1", Kind: "Deployment", Name: "app", Namespace: ns,
+		})).To(Succeed())
+	}
+
+	_, err := s.DynamicClient.Resource(deployGVR).Namespace("app-pr-42").Get(context.TODO(), "app", metav1.GetOptions{})
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = s.DynamicClient.Resource(deployGVR).Namespace("prod").Get(context.TODO(), "app", metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// the other appsubs are not isolated
+	g.Expect(isPreview(&appv1.Subscription{})).To(BeFalse())
+}
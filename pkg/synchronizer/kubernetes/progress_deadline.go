@@ -0,0 +1,245 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	appv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+// progressDeadlineCheckPeriod is how often the agent checks the progress of the appsubs with a progress deadline
+const progressDeadlineCheckPeriod = 30 * time.Second
+
+// startProgressDeadlineCheck starts a goroutine that checks the progress of the appsubs with a progress deadline
+func startProgressDeadlineCheck(sync *KubeSynchronizer) {
+	if sync.hub && !sync.standalone {
+		return
+	}
+
+	go func() {
+		for {
+			time.Sleep(progressDeadlineCheckPeriod)
+
+			sync.checkProgressDeadlines(time.Now())
+		}
+	}()
+}
+
+// checkProgressDeadlines sets the Progressing and Stalled conditions of the local appsubs with a progress deadline
+func (sync *KubeSynchronizer) checkProgressDeadlines(now time.Time) {
+	appsubList := &appv1alpha1.SubscriptionList{}
+	if err := sync.LocalClient.List(context.TODO(), appsubList); err != nil {
+		klog.Warningf("failed to list the appsubs to check their progress deadline, err: %v", err)
+
+		return
+	}
+
+	for i := range appsubList.Items {
+		appsub := &appsubList.Items[i]
+
+		pl := appsub.Spec.Placement
+		if appsub.Spec.ProgressDeadline == nil || appsub.Spec.ProgressDeadline.Duration <= 0 || appsub.GetDeletionTimestamp() != nil ||
+			pl == nil || pl.Local == nil || !*pl.Local {
+			continue
+		}
+
+		if err := sync.checkProgressDeadline(appsub, now); err != nil {
+			klog.Warningf("failed to check the progress deadline of appsub %v/%v, err: %v", appsub.Namespace, appsub.Name, err)
+		}
+	}
+}
+
+// checkProgressDeadline sets the Progressing condition of the appsub while it waits for its dependencies or for its
+// resources to be deployed and healthy, and the Stalled condition once it has been progressing for longer than its
+// progress deadline. The deadline counts from when the appsub started progressing, or from its last spec change
+func (sync *KubeSynchronizer) checkProgressDeadline(appsub *appv1alpha1.Subscription, now time.Time) error {
+	key := types.NamespacedName{Namespace: appsub.Namespace, Name: appsub.Name}
+
+	waitingFor := ""
+
+	if waiting := meta.FindStatusCondition(appsub.Status.Conditions, appv1alpha1.ConditionWaitingForDependency); waiting != nil &&
+		waiting.Status == metav1.ConditionTrue {
+		waitingFor = waiting.Message
+	} else {
+		ready, reason, err := utils.IsSubscriptionReady(sync.LocalClient, key, appv1alpha1.DependencyHealthy)
+		if err != nil {
+			return err
+		}
+
+		if !ready {
+			waitingFor = reason
+		}
+	}
+
+	progressing := metav1.Condition{
+		Type:               appv1alpha1.ConditionProgressing,
+		Status:             metav1.ConditionFalse,
+		Reason:             appv1alpha1.ReasonResourcesReady,
+		Message:            "all the resources are deployed and healthy",
+		ObservedGeneration: appsub.GetGeneration(),
+	}
+
+	stalled := metav1.Condition{
+		Type:               appv1alpha1.ConditionStalled,
+		Status:             metav1.ConditionFalse,
+		Reason:             appv1alpha1.ReasonResourcesReady,
+		Message:            "all the resources are deployed and healthy",
+		ObservedGeneration: appsub.GetGeneration(),
+	}
+
+	newProgress := false
+
+	if waitingFor != "" {
+		progressing.Status = metav1.ConditionTrue
+		progressing.Reason = appv1alpha1.ReasonWaitingForResources
+		progressing.Message = waitingFor
+
+		since := now
+
+		existing := meta.FindStatusCondition(appsub.Status.Conditions, appv1alpha1.ConditionProgressing)
+		if existing != nil && existing.Status == metav1.ConditionTrue && existing.ObservedGeneration == appsub.GetGeneration() {
+			since = existing.LastTransitionTime.Time
+		} else {
+			newProgress = true
+		}
+
+		progressing.LastTransitionTime = metav1.NewTime(since)
+
+		deadline := appsub.Spec.ProgressDeadline.Duration
+
+		stalled.Reason = appv1alpha1.ReasonWaitingForResources
+		stalled.Message = fmt.Sprintf("progressing since %v, within the deadline of %v", since.UTC().Format(time.RFC3339), deadline)
+
+		if now.Sub(since) > deadline {
+			stalled.Status = metav1.ConditionTrue
+			stalled.Reason = appv1alpha1.ReasonProgressDeadlineExceeded
+			stalled.Message = fmt.Sprintf("progressing for more than %v: %v", deadline, waitingFor)
+		}
+	}
+
+	existingStalled := meta.FindStatusCondition(appsub.Status.Conditions, appv1alpha1.ConditionStalled)
+	wasStalled := existingStalled != nil && existingStalled.Status == metav1.ConditionTrue
+	isStalled := stalled.Status == metav1.ConditionTrue
+
+	// the report keeps the stalled message while the appsub is stalled, it is set again if the result is recreated
+	if wasStalled || isStalled {
+		report := ""
+		if isStalled {
+			report = stalled.Message
+		}
+
+		if err := sync.setAppsubReportStalled(appsub, report); err != nil {
+			return err
+		}
+	}
+
+	// the Stalled condition is only added once the appsub is stalled
+	setStalled := isStalled || existingStalled != nil
+
+	if !conditionChanged(appsub.Status.Conditions, progressing) &&
+		(!setStalled || !conditionChanged(appsub.Status.Conditions, stalled)) {
+		return nil
+	}
+
+	latest := &appv1alpha1.Subscription{}
+	if err := sync.LocalClient.Get(context.TODO(), key, latest); err != nil {
+		return err
+	}
+
+	if newProgress {
+		meta.RemoveStatusCondition(&latest.Status.Conditions, appv1alpha1.ConditionProgressing)
+	}
+
+	meta.SetStatusCondition(&latest.Status.Conditions, progressing)
+
+	if setStalled {
+		meta.SetStatusCondition(&latest.Status.Conditions, stalled)
+	}
+
+	if err := sync.LocalClient.Status().Update(context.TODO(), latest); err != nil {
+		return err
+	}
+
+	if isStalled && !wasStalled {
+		klog.Warningf("appsub %v is stalled, %v", key.String(), stalled.Message)
+
+		sync.RecordEvent(latest, appv1alpha1.ReasonProgressDeadlineExceeded, stalled.Message, errors.New(stalled.Message))
+	} else if wasStalled && !isStalled {
+		klog.Infof("appsub %v is not stalled anymore", key.String())
+
+		sync.RecordEvent(latest, appv1alpha1.ReasonResourcesReady, "all the resources are deployed and healthy", nil)
+	}
+
+	return nil
+}
+
+// conditionChanged checks if the condition differs from the one of the conditions, regardless of its transition time
+func conditionChanged(conditions []metav1.Condition, condition metav1.Condition) bool {
+	existing := meta.FindStatusCondition(conditions, condition.Type)
+
+	return existing == nil || existing.Status != condition.Status || existing.Reason != condition.Reason ||
+		existing.Message != condition.Message || existing.ObservedGeneration != condition.ObservedGeneration
+}
+
+// setAppsubReportStalled sets what the appsub is stuck on in its result of the cluster report on the hub, the
+// standalone appsubs aren't reported
+func (sync *KubeSynchronizer) setAppsubReportStalled(appsub *appv1alpha1.Subscription, stalled string) error {
+	if sync.RemoteClient == nil || utils.GetHostSubscriptionFromObject(appsub) == nil {
+		return nil
+	}
+
+	rClient, clusterAppsubReportNs := sync.hubReportTarget(appsub, sync.SynchronizerID.Name)
+	appsubName := sync.appsubStatusName(appsub.Name, sync.SynchronizerID.Name, false)
+
+	if sync.standalone {
+		clusterAppsubReportNs = localCluster
+		appsubName = strings.TrimSuffix(appsub.Name, localSuffix)
+	}
+
+	appsubReport, err := getClusterAppsubReport(rClient, clusterAppsubReportNs, false)
+	if err != nil {
+		return err
+	}
+
+	source := appsub.Namespace + "/" + appsubName
+
+	for _, result := range appsubReport.Results {
+		if result.Source != source {
+			continue
+		}
+
+		if result.Stalled == stalled {
+			return nil
+		}
+
+		result.Stalled = stalled
+
+		return rClient.Update(context.TODO(), appsubReport)
+	}
+
+	// the result is added with the next status of the appsub
+	return nil
+}
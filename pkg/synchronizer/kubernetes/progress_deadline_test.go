@@ -0,0 +1,141 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	plrv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appSubStatusV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+)
+
+func TestProgressDeadline(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	s := runtime.NewScheme()
+	g.Expect(scheme.AddToScheme(s)).To(Succeed())
+	g.Expect(appv1.SchemeBuilder.AddToScheme(s)).To(Succeed())
+	g.Expect(appSubStatusV1alpha1.AddToScheme(s)).To(Succeed())
+
+	local := true
+	appsub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns", Generation: 1,
+			Annotations: map[string]string{appv1.AnnotationHosting: "demo-ns/demo"}},
+		Spec: appv1.SubscriptionSpec{
+			Placement:        &plrv1.Placement{Local: &local},
+			ProgressDeadline: &metav1.Duration{Duration: 10 * time.Minute},
+		},
+		Status: appv1.SubscriptionStatus{Phase: appv1.SubscriptionSubscribed},
+	}
+
+	appsubStatus := &appSubStatusV1alpha1.SubscriptionStatus{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns"},
+		Statuses: appSubStatusV1alpha1.SubscriptionClusterStatusMap{
+			SubscriptionStatus: []appSubStatusV1alpha1.SubscriptionUnitStatus{{APIVersion: "apps/v1", Kind: "Deployment",
+				Name: "web", Namespace: "demo-ns", Phase: appSubStatusV1alpha1.PackageDeployed}},
+		},
+	}
+
+	deployment := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "web", "namespace": "demo-ns"},
+		"spec":       map[string]interface{}{"replicas": int64(2)},
+		"status":     map[string]interface{}{"updatedReplicas": int64(2), "availableReplicas": int64(1)},
+	}}
+
+	report := &appSubStatusV1alpha1.SubscriptionReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster1", Namespace: "cluster1"},
+		ReportType: "Cluster",
+		Results:    []*appSubStatusV1alpha1.SubscriptionReportResult{{Source: "demo-ns/demo", Result: "deployed"}},
+	}
+
+	sync := &KubeSynchronizer{
+		LocalClient:    fake.NewClientBuilder().WithScheme(s).WithObjects(appsub, appsubStatus, deployment).Build(),
+		RemoteClient:   fake.NewClientBuilder().WithScheme(s).WithObjects(report).Build(),
+		SynchronizerID: &types.NamespacedName{Name: "cluster1"},
+	}
+
+	key := types.NamespacedName{Name: "demo", Namespace: "demo-ns"}
+
+	check := func(now time.Time) *appv1.Subscription {
+		sync.checkProgressDeadlines(now)
+
+		got := &appv1.Subscription{}
+		g.Expect(sync.LocalClient.Get(context.TODO(), key, got)).To(Succeed())
+
+		return got
+	}
+
+	stalledReport := func() string {
+		got := &appSubStatusV1alpha1.SubscriptionReport{}
+		g.Expect(sync.RemoteClient.Get(context.TODO(), client.ObjectKeyFromObject(report), got)).To(Succeed())
+
+		return got.Results[0].Stalled
+	}
+
+	// the appsub progresses while its deployment isn't available, it isn't stalled before the deadline
+	start := time.Now()
+	got := check(start)
+
+	progressing := meta.FindStatusCondition(got.Status.Conditions, appv1.ConditionProgressing)
+	g.Expect(progressing).NotTo(BeNil())
+	g.Expect(progressing.Status).To(Equal(metav1.ConditionTrue))
+	g.Expect(progressing.Message).To(ContainSubstring("resource Deployment demo-ns/web of subscription demo-ns/demo is not healthy"))
+	g.Expect(meta.FindStatusCondition(got.Status.Conditions, appv1.ConditionStalled)).To(BeNil())
+
+	got = check(start.Add(5 * time.Minute))
+	g.Expect(meta.FindStatusCondition(got.Status.Conditions, appv1.ConditionStalled)).To(BeNil())
+	g.Expect(stalledReport()).To(BeEmpty())
+
+	// the appsub is stalled on the deployment after the deadline
+	got = check(start.Add(11 * time.Minute))
+
+	stalled := meta.FindStatusCondition(got.Status.Conditions, appv1.ConditionStalled)
+	g.Expect(stalled).NotTo(BeNil())
+	g.Expect(stalled.Status).To(Equal(metav1.ConditionTrue))
+	g.Expect(stalled.Reason).To(Equal(appv1.ReasonProgressDeadlineExceeded))
+	g.Expect(stalled.Message).To(ContainSubstring("progressing for more than 10m0s: resource Deployment demo-ns/web"))
+	g.Expect(stalledReport()).To(Equal(stalled.Message))
+
+	// a spec change starts a new progress
+	got.Generation = 2
+	g.Expect(sync.LocalClient.Update(context.TODO(), got)).To(Succeed())
+
+	got = check(start.Add(12 * time.Minute))
+	g.Expect(meta.IsStatusConditionTrue(got.Status.Conditions, appv1.ConditionStalled)).To(BeFalse())
+	g.Expect(stalledReport()).To(BeEmpty())
+
+	// the appsub is ready once the deployment is available
+	g.Expect(unstructured.SetNestedField(deployment.Object, int64(2), "status", "availableReplicas")).To(Succeed())
+	g.Expect(sync.LocalClient.Update(context.TODO(), deployment)).To(Succeed())
+
+	got = check(start.Add(30 * time.Minute))
+	g.Expect(meta.IsStatusConditionTrue(got.Status.Conditions, appv1.ConditionProgressing)).To(BeFalse())
+	g.Expect(meta.FindStatusCondition(got.Status.Conditions, appv1.ConditionStalled).Reason).To(Equal(appv1.ReasonResourcesReady))
+}
@@ -0,0 +1,135 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+
+	appv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+var (
+	// recreateDeleteTimeout is how long the apply waits for the foreground deletion of a recreated resource, the
+	// resource is created on the next apply if its deletion takes longer
+	recreateDeleteTimeout  = 30 * time.Second
+	recreateDeleteInterval = time.Second
+
+	// immutableFieldMessages are the messages of the API server rejecting the change of an immutable field, such as the
+	// template of a Job, the clusterIP of a Service or the decrease of the size of a PVC
+	immutableFieldMessages = []string{
+		"field is immutable",
+		"may not change once set",
+		"field can not be less than previous value",
+	}
+
+	// the resources deleting their own children are never recreated
+	nonRecreatableKinds = map[string]bool{
+		"Namespace":                true,
+		"CustomResourceDefinition": true,
+	}
+)
+
+// isRecreateOnImmutableChange checks if the resource opts in to be recreated when its update is rejected by the
+// change of an immutable field
+func isRecreateOnImmutableChange(tplunit *unstructured.Unstructured) bool {
+	return strings.EqualFold(tplunit.GetAnnotations()[appv1alpha1.AnnotationResourceRecreateOnImmutableChange], "true")
+}
+
+// isImmutableFieldError checks if the update is rejected by the change of an immutable field
+func isImmutableFieldError(err error) bool {
+	if !errors.IsInvalid(err) {
+		return false
+	}
+
+	msgs := []string{err.Error()}
+
+	if status, ok := err.(errors.APIStatus); ok && status.Status().Details != nil {
+		for _, cause := range status.Status().Details.Causes {
+			msgs = append(msgs, cause.Message)
+		}
+	}
+
+	for _, msg := range msgs {
+		for _, immutable := range immutableFieldMessages {
+			if strings.Contains(msg, immutable) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// recreateResource deletes the resource with the foreground deletion and creates it from the template once its
+// children are deleted. The resources owned by others, marked do-not-delete, already deleted or deleting their own
+// children are not recreated, the deleted resource is also not recreated if it was changed since the failed update
+func (sync *KubeSynchronizer) recreateResource(ri dynamic.ResourceInterface, origUnit, tplunit *unstructured.Unstructured,
+	ownedByOthers bool) error {
+	objKey := fmt.Sprintf("%v %v/%v", origUnit.GetKind(), origUnit.GetNamespace(), origUnit.GetName())
+
+	switch {
+	case ownedByOthers:
+		return errors.NewBadRequest("failed to recreate " + objKey + ", it is owned by others")
+	case nonRecreatableKinds[origUnit.GetKind()]:
+		return errors.NewBadRequest("failed to recreate " + objKey + ", the kind can't be recreated")
+	case origUnit.GetAnnotations()[appv1alpha1.AnnotationResourceDoNotDeleteOption] == "true":
+		return errors.NewBadRequest("failed to recreate " + objKey + ", it is marked do-not-delete")
+	case origUnit.GetDeletionTimestamp() != nil:
+		return fmt.Errorf("failed to recreate %v, it is being deleted", objKey)
+	}
+
+	klog.Infof("Apply - Recreating resource %v on the immutable field change", objKey)
+
+	uid := origUnit.GetUID()
+	resourceVersion := origUnit.GetResourceVersion()
+	foreground := metav1.DeletePropagationForeground
+
+	err := ri.Delete(context.TODO(), origUnit.GetName(), metav1.DeleteOptions{
+		PropagationPolicy: &foreground,
+		Preconditions:     &metav1.Preconditions{UID: &uid, ResourceVersion: &resourceVersion},
+	})
+	if err != nil && !errors.IsNotFound(err) {
+		klog.Errorf("failed to delete %v to recreate it, err: %v", objKey, err)
+
+		return err
+	}
+
+	err = wait.PollImmediate(recreateDeleteInterval, recreateDeleteTimeout, func() (bool, error) {
+		_, err := ri.Get(context.TODO(), origUnit.GetName(), metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+
+		return false, err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to wait for the deletion of %v to recreate it, err: %w", objKey, err)
+	}
+
+	// the namespace of the deleted resource exists
+	_, err = sync.createNewResourceByTemplateUnit(ri, tplunit.DeepCopy(), nil)
+
+	return err
+}
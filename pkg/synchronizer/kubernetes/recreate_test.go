@@ -0,0 +1,97 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func TestRecreateOnImmutableChange(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	gk := schema.GroupKind{Group: "batch", Kind: "Job"}
+	immutable := errors.NewInvalid(gk, "pi", field.ErrorList{
+		field.Invalid(field.NewPath("spec", "template"), "", "field is immutable"),
+	})
+	invalid := errors.NewInvalid(gk, "pi", field.ErrorList{
+		field.Required(field.NewPath("spec", "template"), ""),
+	})
+
+	g.Expect(isImmutableFieldError(immutable)).To(BeTrue())
+	g.Expect(isImmutableFieldError(invalid)).To(BeFalse())
+	g.Expect(isImmutableFieldError(errors.NewBadRequest("field is immutable"))).To(BeFalse())
+
+	job := func(image string, annotations map[string]string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "batch/v1",
+			"kind":       "Job",
+			"metadata":   map[string]interface{}{"name": "pi", "namespace": "default"},
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": []interface{}{map[string]interface{}{"name": "pi", "image": image}},
+					},
+				},
+			},
+		}}
+		obj.SetAnnotations(annotations)
+
+		return obj
+	}
+
+	recreate := map[string]string{appv1.AnnotationResourceRecreateOnImmutableChange: "true"}
+	g.Expect(isRecreateOnImmutableChange(job("perl:5.34", recreate))).To(BeTrue())
+	g.Expect(isRecreateOnImmutableChange(job("perl:5.34", nil))).To(BeFalse())
+
+	jobGVR := schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{jobGVR: "JobList"}, job("perl:5.34", nil))
+	ri := client.Resource(jobGVR).Namespace("default")
+	s := &KubeSynchronizer{DynamicClient: client}
+
+	origUnit, err := ri.Get(context.TODO(), "pi", metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// the resources owned by others and marked do-not-delete are kept
+	g.Expect(s.recreateResource(ri, origUnit, job("perl:5.36", recreate), true)).NotTo(Succeed())
+
+	doNotDelete := origUnit.DeepCopy()
+	doNotDelete.SetAnnotations(map[string]string{appv1.AnnotationResourceDoNotDeleteOption: "true"})
+	g.Expect(s.recreateResource(ri, doNotDelete, job("perl:5.36", recreate), false)).NotTo(Succeed())
+
+	_, err = ri.Get(context.TODO(), "pi", metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// the resource is recreated from the template
+	g.Expect(s.recreateResource(ri, origUnit, job("perl:5.36", recreate), false)).To(Succeed())
+
+	recreated, err := ri.Get(context.TODO(), "pi", metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	containers, _, _ := unstructured.NestedSlice(recreated.Object, "spec", "template", "spec", "containers")
+	g.Expect(containers[0].(map[string]interface{})["image"]).To(Equal("perl:5.36"))
+}
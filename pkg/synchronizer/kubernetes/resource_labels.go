@@ -0,0 +1,187 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appSubStatusV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+// resourceLabels returns the labels set on all the resources deployed by an appsub: its application, its hosting
+// subscription, the cluster and the revision of the channel
+func (sync *KubeSynchronizer) resourceLabels(appsub *appv1alpha1.Subscription, revision string) map[string]string {
+	host := types.NamespacedName{Namespace: appsub.GetNamespace(), Name: appsub.GetName()}
+	if hosting := utils.GetHostSubscriptionFromObject(appsub); hosting != nil {
+		host = *hosting
+	}
+
+	app := appsub.GetLabels()["app"]
+	if app == "" {
+		app = host.Name
+	}
+
+	lbls := map[string]string{
+		appv1alpha1.LabelApplication:      utils.SafeLabelValue(app),
+		appv1alpha1.LabelSubscriptionName: utils.SafeLabelValue(host.Namespace + "." + host.Name),
+	}
+
+	if sync.SynchronizerID != nil && sync.SynchronizerID.Name != "" {
+		lbls[appv1alpha1.LabelCluster] = utils.SafeLabelValue(sync.SynchronizerID.Name)
+	}
+
+	if revision != "" {
+		lbls[appv1alpha1.LabelRevision] = utils.SafeLabelValue(revision)
+	}
+
+	return lbls
+}
+
+// setResourceLabels sets the labels of the appsub on a resource, they override the labels of the template
+func setResourceLabels(obj metav1.Object, resourceLabels map[string]string) {
+	lbls := obj.GetLabels()
+	if lbls == nil {
+		lbls = map[string]string{}
+	}
+
+	for k, v := range resourceLabels {
+		lbls[k] = v
+	}
+
+	// the revision label is removed when the revision isn't known anymore
+	if _, ok := resourceLabels[appv1alpha1.LabelRevision]; !ok {
+		delete(lbls, appv1alpha1.LabelRevision)
+	}
+
+	obj.SetLabels(lbls)
+}
+
+// hasResourceLabels checks if the resource has all the labels of the appsub
+func hasResourceLabels(obj metav1.Object, resourceLabels map[string]string) bool {
+	lbls := obj.GetLabels()
+
+	for k, v := range resourceLabels {
+		if lbls[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// startResourceLabelMigration labels the resources deployed before the agent set the resource labels, once the
+// agent starts. The resources are labeled again on the next apply of their appsub anyway, the migration labels the
+// resources of the appsubs that aren't applied again until their channel changes
+func startResourceLabelMigration(sync *KubeSynchronizer) {
+	go func() {
+		if err := sync.migrateResourceLabels(context.Background()); err != nil {
+			klog.Warningf("failed to label all the deployed resources, err: %v", err)
+		}
+	}()
+}
+
+// migrateResourceLabels labels the resources listed in the appsubstatuses of the appsubs
+func (sync *KubeSynchronizer) migrateResourceLabels(ctx context.Context) error {
+	appsubStatusList := &appSubStatusV1alpha1.SubscriptionStatusList{}
+	if err := sync.LocalNonCachedClient.List(ctx, appsubStatusList, &client.ListOptions{}); err != nil {
+		return err
+	}
+
+	errs := []error{}
+	labeled := 0
+
+	for i := range appsubStatusList.Items {
+		appsubStatus := &appsubStatusList.Items[i]
+		hostSub := types.NamespacedName{Namespace: appsubStatus.Namespace, Name: appsubStatus.Name}
+
+		appsub := &appv1alpha1.Subscription{}
+		if err := sync.LocalNonCachedClient.Get(ctx, hostSub, appsub); err != nil {
+			// the resources of the deleted appsubs are deleted by the cleanup
+			continue
+		}
+
+		resourceLabels := sync.resourceLabels(appsub, appsubStatus.Statuses.Revision)
+
+		for _, pkgStatus := range appsubStatus.Statuses.SubscriptionStatus {
+			if pkgStatus.Phase != appSubStatusV1alpha1.PackageDeployed {
+				continue
+			}
+
+			done, err := sync.labelDeployedResource(ctx, hostSub, pkgStatus, resourceLabels)
+			if err != nil {
+				errs = append(errs, err)
+
+				continue
+			}
+
+			if done {
+				labeled++
+			}
+		}
+	}
+
+	klog.Infof("%v deployed resources labeled with the labels of their appsub", labeled)
+
+	return errors.NewAggregate(errs)
+}
+
+// labelDeployedResource adds the labels of the appsub to a resource it deployed, it returns true when the resource
+// is labeled
+func (sync *KubeSynchronizer) labelDeployedResource(ctx context.Context, hostSub types.NamespacedName,
+	pkgStatus appSubStatusV1alpha1.SubscriptionUnitStatus, resourceLabels map[string]string) (bool, error) {
+	group, version := utils.ParseAPIVersion(pkgStatus.APIVersion)
+
+	gvr, isNamespaced, err := sync.getGVRfromGVK(group, version, pkgStatus.Kind)
+	if err != nil {
+		return false, err
+	}
+
+	nri := sync.DynamicClient.Resource(gvr)
+
+	var ri dynamic.ResourceInterface = nri
+	if isNamespaced {
+		ri = nri.Namespace(pkgStatus.Namespace)
+	}
+
+	obj, err := ri.Get(ctx, pkgStatus.Name, metav1.GetOptions{})
+	if err != nil {
+		klog.V(1).Infof("skip labeling %v %v/%v, err: %v", pkgStatus.Kind, pkgStatus.Namespace, pkgStatus.Name, err)
+
+		return false, nil
+	}
+
+	if !sync.Extension.IsObjectOwnedByHost(obj, hostSub, sync.SynchronizerID) || hasResourceLabels(obj, resourceLabels) {
+		return false, nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"labels": resourceLabels}})
+	if err != nil {
+		return false, err
+	}
+
+	_, err = ri.Patch(ctx, pkgStatus.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+
+	return err == nil, err
+}
@@ -0,0 +1,130 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appSubStatusV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+)
+
+func TestResourceLabels(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	appsub := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{
+		Name:        "demo",
+		Namespace:   "demo-ns",
+		Labels:      map[string]string{"app": "guestbook"},
+		Annotations: map[string]string{appv1.AnnotationHosting: "hub-ns/demo"},
+	}}
+
+	s := &KubeSynchronizer{SynchronizerID: &types.NamespacedName{Name: "cluster1"}, Extension: &SubscriptionExtension{}}
+
+	// the hosting subscription of the hub and the HelmRelease chart versions, made valid label values
+	lbls := s.resourceLabels(appsub, "nginx:1.1.0,redis:17.0.1")
+	g.Expect(lbls).To(Equal(map[string]string{
+		appv1.LabelApplication:      "guestbook",
+		appv1.LabelSubscriptionName: "hub-ns.demo",
+		appv1.LabelCluster:          "cluster1",
+		appv1.LabelRevision:         "nginx-1.1.0-redis-17.0.1",
+	}))
+
+	// the template labels are overridden, the revision label is removed without a revision
+	cm := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{"name": "app-config", "namespace": "demo-ns", "labels": map[string]interface{}{
+			"tier": "web", appv1.LabelApplication: "other", appv1.LabelRevision: "old"}},
+	}}
+
+	setResourceLabels(cm, s.resourceLabels(appsub, ""))
+	g.Expect(cm.GetLabels()).To(Equal(map[string]string{
+		"tier":                      "web",
+		appv1.LabelApplication:      "guestbook",
+		appv1.LabelSubscriptionName: "hub-ns.demo",
+		appv1.LabelCluster:          "cluster1",
+	}))
+
+	// the resources deployed before are labeled once the agent starts, the resources of other appsubs are left alone
+	scheme := runtime.NewScheme()
+	g.Expect(appv1.SchemeBuilder.AddToScheme(scheme)).To(Succeed())
+	g.Expect(appSubStatusV1alpha1.SchemeBuilder.AddToScheme(scheme)).To(Succeed())
+
+	appsub.SetAnnotations(nil)
+
+	appsubStatus := &appSubStatusV1alpha1.SubscriptionStatus{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns"},
+		Statuses: appSubStatusV1alpha1.SubscriptionClusterStatusMap{
+			Revision: "3a5f8c2d9e",
+			SubscriptionStatus: []appSubStatusV1alpha1.SubscriptionUnitStatus{
+				{APIVersion: "v1", Kind: "ConfigMap", Name: "app-config", Namespace: "demo-ns", Phase: appSubStatusV1alpha1.PackageDeployed},
+				{APIVersion: "v1", Kind: "ConfigMap", Name: "shared", Namespace: "demo-ns", Phase: appSubStatusV1alpha1.PackageDeployed},
+				{APIVersion: "v1", Kind: "ConfigMap", Name: "missing", Namespace: "demo-ns", Phase: appSubStatusV1alpha1.PackageDeployed},
+			},
+		},
+	}
+
+	configMap := func(name, host string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": name, "namespace": "demo-ns", "labels": map[string]interface{}{"tier": "web"}},
+		}}
+		obj.SetAnnotations(map[string]string{appv1.AnnotationHosting: host})
+
+		return obj
+	}
+
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+
+	cmGVR := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+	s.RestMapper = restMapper
+	s.LocalNonCachedClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(appsub, appsubStatus).Build()
+	s.DynamicClient = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{cmGVR: "ConfigMapList"},
+		configMap("app-config", "demo-ns/demo"), configMap("shared", "demo-ns/other"))
+
+	g.Expect(s.migrateResourceLabels(context.TODO())).To(Succeed())
+
+	live := func(name string) map[string]string {
+		obj, err := s.DynamicClient.Resource(cmGVR).Namespace("demo-ns").Get(context.TODO(), name, metav1.GetOptions{})
+		g.Expect(err).NotTo(HaveOccurred())
+
+		return obj.GetLabels()
+	}
+
+	g.Expect(live("app-config")).To(Equal(map[string]string{
+		"tier":                      "web",
+		appv1.LabelApplication:      "guestbook",
+		appv1.LabelSubscriptionName: "demo-ns.demo",
+		appv1.LabelCluster:          "cluster1",
+		appv1.LabelRevision:         "3a5f8c2d9e",
+	}))
+	g.Expect(live("shared")).To(Equal(map[string]string{"tier": "web"}))
+}
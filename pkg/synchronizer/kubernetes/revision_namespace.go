@@ -0,0 +1,126 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	appv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appSubStatusV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+)
+
+// previousRevisionMessage is the message of the resources of the previous revision kept in the appsubstatus
+const previousRevisionMessage = "kept until the resources of the new revision are healthy"
+
+// keptPreviousRevisionResources returns the messages of the orphans in the previous revision namespaces of the appsub
+// by their unit status key. They are kept in the appsubstatus until the resources of the current revision are deployed
+// and healthy, then they are deleted
+func (sync *KubeSynchronizer) keptPreviousRevisionResources(appsub *appv1alpha1.Subscription,
+	current, orphans []appSubStatusV1alpha1.SubscriptionUnitStatus) map[string]string {
+	kept := map[string]string{}
+	previous := []appSubStatusV1alpha1.SubscriptionUnitStatus{}
+
+	for _, orphan := range orphans {
+		if utils.IsPreviousRevisionNamespace(appsub, orphan.Namespace) {
+			previous = append(previous, orphan)
+		}
+	}
+
+	if len(previous) == 0 {
+		return kept
+	}
+
+	healthy, reason := sync.isRevisionHealthy(current)
+	if healthy {
+		return kept
+	}
+
+	klog.Infof("appsub: %v/%v, keep the resources of the previous revision, %v", appsub.GetNamespace(), appsub.GetName(), reason)
+
+	for _, resource := range previous {
+		kept[unitStatusKey(resource)] = previousRevisionMessage
+	}
+
+	return kept
+}
+
+// isRevisionHealthy checks if the resources of the revision are deployed and healthy, the reason is returned if not
+func (sync *KubeSynchronizer) isRevisionHealthy(unitStatuses []appSubStatusV1alpha1.SubscriptionUnitStatus) (bool, string) {
+	for _, res := range unitStatuses {
+		if res.Phase != appSubStatusV1alpha1.PackageDeployed {
+			return false, fmt.Sprintf("resource %v %v/%v is not deployed", res.Kind, res.Namespace, res.Name)
+		}
+
+		gv, err := schema.ParseGroupVersion(res.APIVersion)
+		if err != nil {
+			continue
+		}
+
+		healthy, reason, err := utils.IsResourceHealthy(sync.LocalClient, gv.WithKind(res.Kind),
+			types.NamespacedName{Name: res.Name, Namespace: res.Namespace})
+		if err != nil {
+			return false, fmt.Sprintf("failed to check the health of resource %v %v/%v, err: %v", res.Kind, res.Namespace,
+				res.Name, err)
+		}
+
+		if !healthy {
+			return false, fmt.Sprintf("resource %v %v/%v is not healthy: %v", res.Kind, res.Namespace, res.Name, reason)
+		}
+	}
+
+	return true, ""
+}
+
+// prunePreviousRevisionNamespaces deletes the previous revision namespaces created by the appsub once none of the
+// resources of the appsubstatus are left in them. It returns the created namespaces left, the namespaces failing to
+// be deleted are pruned on the next apply
+func (sync *KubeSynchronizer) prunePreviousRevisionNamespaces(appsub *appv1alpha1.Subscription, hostSub types.NamespacedName,
+	createdNamespaces []string, unitStatuses []appSubStatusV1alpha1.SubscriptionUnitStatus) []string {
+	left := []string{}
+
+	for _, ns := range createdNamespaces {
+		if !utils.IsPreviousRevisionNamespace(appsub, ns) || hasNamespaceResources(ns, unitStatuses) {
+			left = append(left, ns)
+
+			continue
+		}
+
+		klog.Infof("appsub: %v, prune previous revision namespace: %v", hostSub, ns)
+
+		nsStatus := appSubStatusV1alpha1.SubscriptionUnitStatus{APIVersion: "v1", Kind: "Namespace", Name: ns}
+		if err := sync.DeleteSingleSubscribedResource(hostSub, nsStatus); err != nil {
+			klog.Errorf("failed to prune previous revision namespace %v of appsub %v, err: %v", ns, hostSub, err)
+
+			left = append(left, ns)
+		}
+	}
+
+	return left
+}
+
+func hasNamespaceResources(ns string, unitStatuses []appSubStatusV1alpha1.SubscriptionUnitStatus) bool {
+	for _, unitStatus := range unitStatuses {
+		if unitStatus.Namespace == ns {
+			return true
+		}
+	}
+
+	return false
+}
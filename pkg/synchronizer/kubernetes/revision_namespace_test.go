@@ -0,0 +1,101 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appSubStatusV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+)
+
+func TestRevisionNamespaces(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	deployment := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "app", "namespace": "app-3a5f8c2"},
+		"spec":       map[string]interface{}{"replicas": int64(1)},
+		"status":     map[string]interface{}{"updatedReplicas": int64(1), "availableReplicas": int64(0)},
+	}}
+
+	s := &KubeSynchronizer{LocalClient: fake.NewClientBuilder().WithObjects(deployment).Build()}
+
+	appsub := &appv1.Subscription{}
+	appsub.SetAnnotations(map[string]string{
+		appv1.AnnotationRevisionNamespace: "app",
+		appv1.AnnotationGitCommit:         "3a5f8c2d9e",
+	})
+
+	current := []appSubStatusV1alpha1.SubscriptionUnitStatus{{
+		APIVersion: "apps/v1", Kind: "Deployment", Name: "app", Namespace: "app-3a5f8c2",
+		Phase: appSubStatusV1alpha1.PackageDeployed,
+	}}
+	orphans := []appSubStatusV1alpha1.SubscriptionUnitStatus{
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: "app", Namespace: "app-1b2c3d4"},
+		{APIVersion: "v1", Kind: "ConfigMap", Name: "removed", Namespace: "app-3a5f8c2"},
+	}
+
+	// the resources of the previous revision are kept while the new revision isn't healthy
+	g.Expect(s.keptPreviousRevisionResources(appsub, current, orphans)).To(Equal(map[string]string{
+		"Deployment/app-1b2c3d4/app": previousRevisionMessage,
+	}))
+
+	g.Expect(unstructured.SetNestedField(deployment.Object, int64(1), "status", "availableReplicas")).To(Succeed())
+	g.Expect(s.LocalClient.Update(context.TODO(), deployment)).To(Succeed())
+
+	g.Expect(s.keptPreviousRevisionResources(appsub, current, orphans)).To(BeEmpty())
+
+	// the previous revision namespaces created by the appsub are pruned once they are empty
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, meta.RESTScopeRoot)
+
+	namespace := func(name string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Namespace",
+			"metadata":   map[string]interface{}{"name": name},
+		}}
+		obj.SetAnnotations(map[string]string{appv1.AnnotationHosting: "demo-ns/demo"})
+
+		return obj
+	}
+
+	nsGVR := schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	s.DynamicClient = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{nsGVR: "NamespaceList"},
+		namespace("app-1b2c3d4"), namespace("app-9e8d7c6"), namespace("app-3a5f8c2"))
+	s.RestMapper = restMapper
+
+	left := s.prunePreviousRevisionNamespaces(appsub, types.NamespacedName{Namespace: "demo-ns", Name: "demo"},
+		[]string{"app-1b2c3d4", "app-9e8d7c6", "app-3a5f8c2", "team-a"},
+		append(current, appSubStatusV1alpha1.SubscriptionUnitStatus{Kind: "ConfigMap", Name: "kept", Namespace: "app-9e8d7c6"}))
+	g.Expect(left).To(Equal([]string{"app-9e8d7c6", "app-3a5f8c2", "team-a"}))
+
+	_, err := s.DynamicClient.Resource(nsGVR).Get(context.TODO(), "app-1b2c3d4", metav1.GetOptions{})
+	g.Expect(err).To(HaveOccurred())
+}
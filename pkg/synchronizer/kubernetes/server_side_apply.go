@@ -0,0 +1,52 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+)
+
+// applyFieldManager is the field manager of the server-side apply of the subscription resources
+const applyFieldManager = "multicluster-operators-subscription"
+
+// serverSideApply updates an existing resource with a server-side apply of the ServerSideApply feature gate instead of
+// a three-way merge patch. The fields set by the other managers are kept unless the resource sets them, the conflicts
+// are forced since the subscription is the source of truth of its resources
+func (sync *KubeSynchronizer) serverSideApply(ri dynamic.ResourceInterface, obj *unstructured.Unstructured) error {
+	applied := obj.DeepCopy()
+	applied.SetResourceVersion("")
+	applied.SetManagedFields(nil)
+
+	data, err := applied.MarshalJSON()
+	if err != nil {
+		klog.Error("Failed to marshall obj with error:", err)
+
+		return err
+	}
+
+	klog.Infof("Server-side apply object. obj: %s, %s", applied.GetName(), applied.GroupVersionKind().String())
+
+	force := true
+	_, err = ri.Patch(context.TODO(), applied.GetName(), types.ApplyPatchType, data,
+		metav1.PatchOptions{FieldManager: applyFieldManager, Force: &force})
+
+	return err
+}
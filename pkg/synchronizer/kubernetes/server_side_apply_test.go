@@ -0,0 +1,66 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestServerSideApply(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cmGVR := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{cmGVR: "ConfigMapList"})
+
+	var patch clienttesting.PatchActionImpl
+
+	// the object tracker of the fake client doesn't support the apply patches
+	client.PrependReactor("patch", "configmaps", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		patch = action.(clienttesting.PatchActionImpl)
+
+		return true, &unstructured.Unstructured{}, nil
+	})
+
+	cm := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "cm", "namespace": "default", "resourceVersion": "12"},
+		"data":       map[string]interface{}{"key": "value"},
+	}}
+
+	s := &KubeSynchronizer{DynamicClient: client}
+	g.Expect(s.serverSideApply(client.Resource(cmGVR).Namespace("default"), cm)).To(Succeed())
+
+	g.Expect(patch.GetPatchType()).To(Equal(types.ApplyPatchType))
+	g.Expect(patch.GetName()).To(Equal("cm"))
+
+	applied := map[string]interface{}{}
+	g.Expect(json.Unmarshal(patch.GetPatch(), &applied)).To(Succeed())
+	g.Expect(applied["data"]).To(Equal(map[string]interface{}{"key": "value"}))
+	g.Expect(applied["metadata"]).NotTo(HaveKey("resourceVersion"))
+
+	// the template is not changed
+	g.Expect(cm.GetResourceVersion()).To(Equal("12"))
+}
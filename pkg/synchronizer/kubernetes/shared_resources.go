@@ -0,0 +1,167 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	appv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+// sharedResourceConflictError is returned instead of applying a cluster-scoped resource deployed by another appsub with
+// a higher or the same precedence, so two appsubs never take the resource from each other on each reconcile
+type sharedResourceConflictError struct {
+	message string
+}
+
+func (e *sharedResourceConflictError) Error() string {
+	return appv1alpha1.ConditionSharedResourceConflict + ": " + e.message
+}
+
+// sharedResourceConflict returns the message of a sharedResourceConflictError
+func sharedResourceConflict(err error) (string, bool) {
+	var conflict *sharedResourceConflictError
+	if errors.As(err, &conflict) {
+		return conflict.message, true
+	}
+
+	return "", false
+}
+
+// conflictPrecedence returns the conflict precedence of an appsub or a resource, the invalid values are 0
+func conflictPrecedence(obj metav1.Object) int {
+	v := strings.TrimSpace(obj.GetAnnotations()[appv1alpha1.AnnotationConflictPrecedence])
+	if v == "" {
+		return 0
+	}
+
+	precedence, err := strconv.Atoi(v)
+	if err != nil {
+		klog.Warningf("invalid conflict precedence %v of %v/%v, using 0", v, obj.GetNamespace(), obj.GetName())
+
+		return 0
+	}
+
+	return precedence
+}
+
+// setConflictPrecedence sets the conflict precedence of the appsub to a cluster-scoped resource, for the other appsubs
+// deploying it to compare with theirs
+func setConflictPrecedence(appsub *appv1alpha1.Subscription, tplunit *unstructured.Unstructured) {
+	precedence, ok := appsub.GetAnnotations()[appv1alpha1.AnnotationConflictPrecedence]
+	if !ok {
+		return
+	}
+
+	annotations := tplunit.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	annotations[appv1alpha1.AnnotationConflictPrecedence] = precedence
+	tplunit.SetAnnotations(annotations)
+}
+
+// checkSharedResource checks if a cluster-scoped resource deployed by another appsub can be applied. The appsub with the
+// higher precedence takes the resource over, with the same precedence the resource stays with its appsub. It returns
+// true for a take over, and a sharedResourceConflictError if the resource is left to the other appsub. The resources
+// of the appsubs that don't exist anymore are not conflicts, they are applied with the reconcile option of the appsub
+func (sync *KubeSynchronizer) checkSharedResource(origUnit, tplunit *unstructured.Unstructured) (bool, error) {
+	tplown := sync.Extension.GetHostFromObject(tplunit)
+	owner := sync.Extension.GetHostFromObject(origUnit)
+
+	if tplown == nil || owner == nil || sync.Extension.IsObjectOwnedByHost(origUnit, *tplown, sync.SynchronizerID) {
+		return false, nil
+	}
+
+	if err := sync.LocalClient.Get(context.TODO(), *owner, &appv1alpha1.Subscription{}); err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	tplPrecedence, ownerPrecedence := conflictPrecedence(tplunit), conflictPrecedence(origUnit)
+	if tplPrecedence > ownerPrecedence {
+		klog.Infof("appsub %v takes %v %v over from appsub %v, its precedence %v is higher than %v", tplown.String(),
+			tplunit.GetKind(), tplunit.GetName(), owner.String(), tplPrecedence, ownerPrecedence)
+
+		return true, nil
+	}
+
+	return false, &sharedResourceConflictError{message: fmt.Sprintf(
+		"%v %v is deployed by appsub %v with the precedence %v, the precedence of appsub %v is %v", tplunit.GetKind(),
+		tplunit.GetName(), owner.String(), ownerPrecedence, tplown.String(), tplPrecedence)}
+}
+
+// setSharedResourceConflictCondition sets the SharedResourceConflict condition of the appsub from the cluster-scoped
+// resources left to the other appsubs. The condition is only added on a conflict, and set to false once it is resolved
+func (sync *KubeSynchronizer) setSharedResourceConflictCondition(appsub *appv1alpha1.Subscription, conflicts []string) {
+	if len(conflicts) == 0 && !meta.IsStatusConditionTrue(appsub.Status.Conditions, appv1alpha1.ConditionSharedResourceConflict) {
+		return
+	}
+
+	hostSub := types.NamespacedName{Namespace: appsub.Namespace, Name: appsub.Name}
+
+	latest := &appv1alpha1.Subscription{}
+	if err := sync.LocalClient.Get(context.TODO(), hostSub, latest); err != nil {
+		klog.Warningf("failed to get appsub %v to set the %v condition, err: %v", hostSub.String(),
+			appv1alpha1.ConditionSharedResourceConflict, err)
+
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:               appv1alpha1.ConditionSharedResourceConflict,
+		Status:             metav1.ConditionFalse,
+		Reason:             appv1alpha1.ReasonNoSharedResourceConflict,
+		Message:            "the cluster-scoped resources are not deployed by other appsubs",
+		ObservedGeneration: latest.GetGeneration(),
+	}
+
+	if len(conflicts) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = appv1alpha1.ReasonOwnedByOtherSubscription
+		condition.Message = strings.Join(conflicts, "; ")
+	}
+
+	existing := meta.FindStatusCondition(latest.Status.Conditions, condition.Type)
+
+	switch {
+	case existing == nil && len(conflicts) == 0:
+		return
+	case existing != nil && existing.Status == condition.Status && existing.Message == condition.Message &&
+		existing.ObservedGeneration == condition.ObservedGeneration:
+		return
+	}
+
+	meta.SetStatusCondition(&latest.Status.Conditions, condition)
+
+	if err := sync.LocalClient.Status().Update(context.TODO(), latest); err != nil {
+		klog.Warningf("failed to set the %v condition of appsub %v, err: %v", condition.Type, hostSub.String(), err)
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func TestSharedResourceConflict(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(appv1.SchemeBuilder.AddToScheme(scheme)).To(Succeed())
+
+	first := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: "team-a"}}
+	second := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "second", Namespace: "team-b"}}
+
+	clusterRole := func(host *appv1.Subscription, rule string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "rbac.authorization.k8s.io/v1",
+			"kind":       "ClusterRole",
+			"metadata":   map[string]interface{}{"name": "shared"},
+			"rules":      []interface{}{map[string]interface{}{"verbs": []interface{}{rule}}},
+		}}
+		obj.SetAnnotations(map[string]string{appv1.AnnotationHosting: host.Namespace + "/" + host.Name})
+		setConflictPrecedence(host, obj)
+
+		return obj
+	}
+
+	crGVR := schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{crGVR: "ClusterRoleList"}, clusterRole(first, "get"))
+
+	s := &KubeSynchronizer{
+		DynamicClient:  dynamicClient,
+		LocalClient:    fake.NewClientBuilder().WithScheme(scheme).WithObjects(first, second).Build(),
+		Extension:      &SubscriptionExtension{},
+		SynchronizerID: &types.NamespacedName{Name: "cluster1"},
+	}
+
+	apply := func(host *appv1.Subscription, rule string) error {
+		_, err := s.applyTemplate(dynamicClient.Resource(crGVR), false, ResourceUnit{Resource: clusterRole(host, rule)},
+			false, nil, nil, true, nil)
+
+		return err
+	}
+
+	live := func() (string, string) {
+		obj, err := dynamicClient.Resource(crGVR).Get(context.TODO(), "shared", metav1.GetOptions{})
+		g.Expect(err).NotTo(HaveOccurred())
+
+		rules, _, _ := unstructured.NestedSlice(obj.Object, "rules")
+
+		return obj.GetAnnotations()[appv1.AnnotationHosting], rules[0].(map[string]interface{})["verbs"].([]interface{})[0].(string)
+	}
+
+	// the owner applies its resource
+	g.Expect(apply(first, "list")).To(Succeed())
+
+	// with the same precedence, the resource stays with its appsub even with a reconcile option
+	second.SetAnnotations(map[string]string{appv1.AnnotationResourceReconcileOption: appv1.MergeAndOwnReconcile})
+
+	err := apply(second, "watch")
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(HavePrefix(appv1.ConditionSharedResourceConflict + ": ClusterRole shared is deployed by appsub team-a/first"))
+
+	msg, ok := sharedResourceConflict(err)
+	g.Expect(ok).To(BeTrue())
+
+	host, verb := live()
+	g.Expect(host).To(Equal("team-a/first"))
+	g.Expect(verb).To(Equal("list"))
+
+	// the appsub with the higher precedence takes the resource over, and keeps it
+	second.SetAnnotations(map[string]string{appv1.AnnotationConflictPrecedence: "10"})
+
+	g.Expect(apply(second, "watch")).To(Succeed())
+
+	host, verb = live()
+	g.Expect(host).To(Equal("team-b/second"))
+	g.Expect(verb).To(Equal("watch"))
+
+	_, ok = sharedResourceConflict(apply(first, "list"))
+	g.Expect(ok).To(BeTrue())
+	g.Expect(apply(second, "watch")).To(Succeed())
+
+	// the invalid precedences are 0
+	g.Expect(conflictPrecedence(&metav1.ObjectMeta{Annotations: map[string]string{
+		appv1.AnnotationConflictPrecedence: "high"}})).To(Equal(0))
+
+	// the condition is set on a conflict and cleared once it is resolved
+	latest := func() *metav1.Condition {
+		sub := &appv1.Subscription{}
+		g.Expect(s.LocalClient.Get(context.TODO(), types.NamespacedName{Name: "first", Namespace: "team-a"}, sub)).To(Succeed())
+
+		first.Status = sub.Status
+
+		return meta.FindStatusCondition(sub.Status.Conditions, appv1.ConditionSharedResourceConflict)
+	}
+
+	s.setSharedResourceConflictCondition(first, nil)
+	g.Expect(latest()).To(BeNil())
+
+	s.setSharedResourceConflictCondition(first, []string{msg})
+	g.Expect(latest().Status).To(Equal(metav1.ConditionTrue))
+	g.Expect(latest().Message).To(Equal(msg))
+
+	s.setSharedResourceConflictCondition(first, nil)
+	g.Expect(latest().Status).To(Equal(metav1.ConditionFalse))
+	g.Expect(latest().Reason).To(Equal(appv1.ReasonNoSharedResourceConflict))
+}
@@ -19,6 +19,8 @@ import (
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -64,6 +66,10 @@ func (sync *KubeSynchronizer) SyncAppsubClusterStatus(appsub *appv1.Subscription
 	klog.Infof("cluster: %v, appsub: %v/%v, action: %v, hub:%v, standalone:%v\n", appsubClusterStatus.Cluster,
 		appsubClusterStatus.AppSub.Namespace, appsubClusterStatus.AppSub.Name, appsubClusterStatus.Action, sync.hub, sync.standalone)
 
+	if utils.DropStatusUpdate(appsubClusterStatus.AppSub.Namespace, appsubClusterStatus.AppSub.Name) {
+		return nil
+	}
+
 	skipOrphanDel := false
 	if skipOrphanDelete != nil {
 		skipOrphanDel = *skipOrphanDelete
@@ -75,20 +81,15 @@ func (sync *KubeSynchronizer) SyncAppsubClusterStatus(appsub *appv1.Subscription
 	}
 
 	// Get existing appsubstatus on managed cluster, if it exists
-	appsubName := appsubClusterStatus.AppSub.Name
 	pkgstatusNs := appsubClusterStatus.AppSub.Namespace
-	isLocalCluster := (sync.hub && !sync.standalone) ||
-		(appsubClusterStatus.Cluster == localCluster && strings.HasSuffix(appsubName, localSuffix)) ||
-		(sync.standalone && strings.HasSuffix(appsubName, localSuffix))
-
-	if isLocalCluster || sync.standalone && skipOrphanDel {
-		if strings.HasSuffix(appsubName, localSuffix) {
-			appsubName = appsubName[:len(appsubName)-6]
-		}
-	}
+	isLocalCluster := sync.isLocalAppSub(appsubClusterStatus.AppSub.Name, appsubClusterStatus.Cluster)
+	appsubName := sync.appsubStatusName(appsubClusterStatus.AppSub.Name, appsubClusterStatus.Cluster, skipOrphanDel)
 
 	pkgstatusName := appsubName
 
+	// the appsub is reported to the hub it is propagated from
+	remoteClient, clusterAppsubReportNs := sync.hubReportTarget(appsub, appsubClusterStatus.Cluster)
+
 	pkgstatus := &v1alpha1.SubscriptionStatus{
 		TypeMeta: metaV1.TypeMeta{
 			Kind:       "SubscriptionStatus",
@@ -119,9 +120,9 @@ func (sync *KubeSynchronizer) SyncAppsubClusterStatus(appsub *appv1.Subscription
 			pkgstatus.Namespace, pkgstatus.Name)
 
 		// Update result in cluster AppsubReport
-		if err := updateAppsubReportResult(sync.RemoteClient, appsubClusterStatus.AppSub.Namespace,
-			appsubName, appsubClusterStatus.Cluster, false,
-			sync.standalone, isLocalCluster); err != nil {
+		if err := updateAppsubReportResult(remoteClient, appsubClusterStatus.AppSub.Namespace,
+			appsubName, clusterAppsubReportNs, false,
+			sync.standalone, isLocalCluster, pkgstatus.Statuses); err != nil {
 			return err
 		}
 
@@ -137,7 +138,7 @@ func (sync *KubeSynchronizer) SyncAppsubClusterStatus(appsub *appv1.Subscription
 			klog.V(1).Infof("Skip create appsubstatus(%v/%v) for HelmRelease", pkgstatus.Namespace, pkgstatus.Name)
 
 			// Create cluster report so the helm release controller on the standalone could update it
-			_, err := getClusterAppsubReport(sync.RemoteClient, appsubClusterStatus.Cluster, true)
+			_, err := getClusterAppsubReport(remoteClient, clusterAppsubReportNs, true)
 			if err != nil {
 				return err
 			}
@@ -146,6 +147,7 @@ func (sync *KubeSynchronizer) SyncAppsubClusterStatus(appsub *appv1.Subscription
 		}
 
 		newUnitStatus := []v1alpha1.SubscriptionUnitStatus{}
+		aggregated := false
 
 		for _, resource := range appsubClusterStatus.SubscriptionPackageStatus {
 			klog.V(1).Infof("resource status - Name: %v, Namespace: %v, Apiversion: %v, Kind: %v, Phase: %v, Message: %v\n",
@@ -159,6 +161,7 @@ func (sync *KubeSynchronizer) SyncAppsubClusterStatus(appsub *appv1.Subscription
 				Phase:          v1alpha1.PackagePhase(resource.Phase),
 				Message:        resource.Message,
 				LastUpdateTime: metaV1.Time{Time: time.Now()},
+				Hash:           resource.Hash,
 			}
 			newUnitStatus = append(newUnitStatus, *uS)
 		}
@@ -173,6 +176,7 @@ func (sync *KubeSynchronizer) SyncAppsubClusterStatus(appsub *appv1.Subscription
 			// Create new appsubstatus
 			pkgstatus = buildAppSubStatus(pkgstatusName, pkgstatusNs, appsubName,
 				appsubClusterStatus.AppSub.Namespace, appsubClusterStatus.Cluster, newUnitStatus)
+			setInventorySummary(pkgstatus, appsubClusterStatus)
 			klog.Infof("Creating new appsubstatus: %v/%v", pkgstatus.Namespace, pkgstatus.Name)
 
 			// Create appsubstatus on appSub NS
@@ -182,6 +186,14 @@ func (sync *KubeSynchronizer) SyncAppsubClusterStatus(appsub *appv1.Subscription
 				return err
 			}
 		} else {
+			// the HelmReleases of a subscription with several charts report their unit statuses one by one
+			if merged := mergeHelmReleaseUnitStatuses(pkgstatus.Statuses.SubscriptionStatus, newUnitStatus); merged != nil {
+				klog.V(1).Infof("Aggregate the HelmRelease unit statuses of appsubstatus:%v/%v", pkgstatus.Namespace, pkgstatus.Name)
+
+				newUnitStatus = merged
+				aggregated = true
+			}
+
 			if isLocalCluster && foundPkgStatus && len(pkgstatus.Statuses.SubscriptionStatus) == 1 &&
 				strings.EqualFold(pkgstatus.Statuses.SubscriptionStatus[0].Kind, "HelmRelease") &&
 				strings.EqualFold(pkgstatus.Statuses.SubscriptionStatus[0].APIVersion, "apps.open-cluster-management.io/v1") &&
@@ -224,7 +236,26 @@ func (sync *KubeSynchronizer) SyncAppsubClusterStatus(appsub *appv1.Subscription
 					}
 				}
 
+				// the previous generated resources and the resources of the previous revision stay in the appsubstatus
+				// and are deleted on a later apply
+				keptOrphans := sync.keptGeneratedResources(appsub, deleteUnitStatuses)
+				for key, msg := range sync.keptPreviousRevisionResources(appsub, newUnitStatus, deleteUnitStatuses) {
+					keptOrphans[key] = msg
+				}
+
 				for _, resource := range deleteUnitStatuses {
+					if msg, ok := keptOrphans[unitStatusKey(resource)]; ok {
+						klog.Infof("Keep subscription unit kind:%v resource:%v/%v, %v",
+							resource.Kind, resource.Namespace, resource.Name, msg)
+
+						keptUnitStatus := resource.DeepCopy()
+						keptUnitStatus.Message = msg
+
+						newUnitStatus = append(newUnitStatus, *keptUnitStatus)
+
+						continue
+					}
+
 					klog.Infof("Delete subscription unit kind:%v resource:%v/%v", resource.Kind, resource.Namespace, resource.Name)
 
 					hostSub := types.NamespacedName{
@@ -242,6 +273,10 @@ func (sync *KubeSynchronizer) SyncAppsubClusterStatus(appsub *appv1.Subscription
 					}
 				}
 
+				pkgstatus.Statuses.CreatedNamespaces = sync.prunePreviousRevisionNamespaces(appsub,
+					types.NamespacedName{Namespace: appsubClusterStatus.AppSub.Namespace, Name: appsubName},
+					pkgstatus.Statuses.CreatedNamespaces, newUnitStatus)
+
 				// if legacy statuses exist  - remove them
 				if len(legacyUnitStatuses) != 0 {
 					if err := sync.LocalClient.Get(context.TODO(),
@@ -264,8 +299,8 @@ func (sync *KubeSynchronizer) SyncAppsubClusterStatus(appsub *appv1.Subscription
 				}
 
 				klog.V(1).Infof("Delete result from cluster AppsubReport:%v/%v", pkgstatus.Namespace, pkgstatus.Name)
-				if err := deleteAppsubReportResult(sync.RemoteClient, appsubClusterStatus.AppSub.Namespace,
-					appsubName, appsubClusterStatus.Cluster, sync.standalone); err != nil {
+				if err := deleteAppsubReportResult(remoteClient, appsubClusterStatus.AppSub.Namespace,
+					appsubName, clusterAppsubReportNs, sync.standalone); err != nil {
 					return err
 				}
 
@@ -279,6 +314,8 @@ func (sync *KubeSynchronizer) SyncAppsubClusterStatus(appsub *appv1.Subscription
 			}
 
 			pkgstatus.Statuses.SubscriptionStatus = newUnitStatus
+			setInventorySummary(pkgstatus, appsubClusterStatus)
+
 			if err := sync.LocalClient.Update(context.TODO(), pkgstatus); err != nil {
 				klog.Errorf("Error in updating on managed cluster, appsubstatus:%v/%v, err:%v", pkgstatus.Namespace, pkgstatusName, err)
 				return err
@@ -295,10 +332,20 @@ func (sync *KubeSynchronizer) SyncAppsubClusterStatus(appsub *appv1.Subscription
 			}
 		}
 
+		// the cluster result of the aggregated HelmReleases fails if any of them fails
+		if aggregated && !deployFailed {
+			for _, resource := range newUnitStatus {
+				if isHelmReleaseUnit(resource) && resource.Phase == v1alpha1.PackageDeployFailed {
+					deployFailed = true
+					break
+				}
+			}
+		}
+
 		// Update result in cluster AppsubReport
-		if err := updateAppsubReportResult(sync.RemoteClient, appsubClusterStatus.AppSub.Namespace,
-			appsubName, appsubClusterStatus.Cluster, deployFailed,
-			sync.standalone, isLocalCluster); err != nil {
+		if err := updateAppsubReportResult(remoteClient, appsubClusterStatus.AppSub.Namespace,
+			appsubName, clusterAppsubReportNs, deployFailed,
+			sync.standalone, isLocalCluster, pkgstatus.Statuses); err != nil {
 			return err
 		}
 	}
@@ -366,8 +413,8 @@ func (sync *KubeSynchronizer) SyncAppsubClusterStatus(appsub *appv1.Subscription
 
 			klog.V(1).Infof("Delete result from cluster AppsubReport:%v/%v", pkgstatus.Namespace, pkgstatus.Name)
 
-			if err := deleteAppsubReportResult(sync.RemoteClient, appsubClusterStatus.AppSub.Namespace,
-				appsubName, appsubClusterStatus.Cluster, sync.standalone); err != nil {
+			if err := deleteAppsubReportResult(remoteClient, appsubClusterStatus.AppSub.Namespace,
+				appsubName, clusterAppsubReportNs, sync.standalone); err != nil {
 				return err
 			}
 		} else {
@@ -395,9 +442,9 @@ func (sync *KubeSynchronizer) SyncAppsubClusterStatus(appsub *appv1.Subscription
 				}
 
 				// Update result in cluster AppsubReport
-				if err := updateAppsubReportResult(sync.RemoteClient, appsubClusterStatus.AppSub.Namespace,
-					appsubName, appsubClusterStatus.Cluster, deployFailed,
-					sync.standalone, isLocalCluster); err != nil {
+				if err := updateAppsubReportResult(remoteClient, appsubClusterStatus.AppSub.Namespace,
+					appsubName, clusterAppsubReportNs, deployFailed,
+					sync.standalone, isLocalCluster, pkgstatus.Statuses); err != nil {
 					return err
 				}
 			}
@@ -407,6 +454,22 @@ func (sync *KubeSynchronizer) SyncAppsubClusterStatus(appsub *appv1.Subscription
 	return nil
 }
 
+// isLocalAppSub checks if the appsub is deployed on the hub cluster itself
+func (sync *KubeSynchronizer) isLocalAppSub(appsubName, cluster string) bool {
+	return (sync.hub && !sync.standalone) ||
+		(cluster == localCluster && strings.HasSuffix(appsubName, localSuffix)) ||
+		(sync.standalone && strings.HasSuffix(appsubName, localSuffix))
+}
+
+// appsubStatusName returns the name of the SubscriptionStatus of the appsub, the local appsubs drop their -local suffix
+func (sync *KubeSynchronizer) appsubStatusName(appsubName, cluster string, skipOrphanDel bool) string {
+	if sync.isLocalAppSub(appsubName, cluster) || sync.standalone && skipOrphanDel {
+		return strings.TrimSuffix(appsubName, localSuffix)
+	}
+
+	return appsubName
+}
+
 func (sync *KubeSynchronizer) recordAppSubStatusEvents(appsub *appv1.Subscription, action string,
 	pkgStatuses []v1alpha1.SubscriptionUnitStatus) {
 	curUser := ""
@@ -416,6 +479,22 @@ func (sync *KubeSynchronizer) recordAppSubStatusEvents(appsub *appv1.Subscriptio
 	}
 
 	packageStatuses := fmt.Sprintf("AppSub: '%s/%s'; User: '%s'; Action: '%s'; ", appsub.Namespace, appsub.Name, curUser, action)
+
+	// the summary verbosity of the controller config lists the failed resources only, the events of the appsubs with
+	// thousands of resources stay small
+	if utils.IsStatusVerbositySummary() {
+		failed := []v1alpha1.SubscriptionUnitStatus{}
+
+		for _, resource := range pkgStatuses {
+			if resource.Phase == v1alpha1.PackageDeployFailed {
+				failed = append(failed, resource)
+			}
+		}
+
+		packageStatuses += fmt.Sprintf("Resources: '%d'; Failed: '%d'; ", len(pkgStatuses), len(failed))
+		pkgStatuses = failed
+	}
+
 	packageStatuses += "PackageStatus: 'Name|Namespace|Apiversion|Kind|Phase|Message|LastUpdateTime"
 
 	for _, resource := range pkgStatuses {
@@ -450,8 +529,62 @@ func buildAppSubStatus(statusName, statusNs, appsubName, appsubNs, cluster strin
 	return pkgstatus
 }
 
+// setInventorySummary sets the revision and the images of the applied resources, the failed resources, the created
+// namespaces, the last applied time and the fetch status of the appsub in the appsubstatus, the HelmRelease statuses reported one by one don't change the revision,
+// the images and the fetch status
+func setInventorySummary(pkgstatus *v1alpha1.SubscriptionStatus, appsubClusterStatus SubscriptionClusterStatus) {
+	now := metaV1.Now()
+	pkgstatus.Statuses.LastAppliedTime = &now
+	pkgstatus.Statuses.FailedResources = failedResources(pkgstatus.Statuses.SubscriptionStatus)
+
+	// the created namespaces are kept until the appsub is deleted, they are pruned by the namespace creation of the appsub
+	if len(appsubClusterStatus.CreatedNamespaces) > 0 {
+		pkgstatus.Statuses.CreatedNamespaces = uniqueSorted(
+			append(pkgstatus.Statuses.CreatedNamespaces, appsubClusterStatus.CreatedNamespaces...))
+	}
+
+	if appsubClusterStatus.LastFetchTime != nil {
+		pkgstatus.Statuses.LastFetchTime = appsubClusterStatus.LastFetchTime
+		pkgstatus.Statuses.NextReconcileTime = appsubClusterStatus.NextReconcileTime
+		pkgstatus.Statuses.Revisions = appsubClusterStatus.Revisions
+		pkgstatus.Statuses.GitRemote = appsubClusterStatus.GitRemote
+	}
+
+	if appsubClusterStatus.Revision == "" && len(appsubClusterStatus.Images) == 0 {
+		return
+	}
+
+	pkgstatus.Statuses.Revision = appsubClusterStatus.Revision
+	pkgstatus.Statuses.Images = appsubClusterStatus.Images
+}
+
+// failedResources returns the references of the failed resources of the appsubstatus
+func failedResources(unitStatuses []v1alpha1.SubscriptionUnitStatus) []corev1.ObjectReference {
+	var refs []corev1.ObjectReference
+
+	for _, unitStatus := range unitStatuses {
+		if unitStatus.Phase == v1alpha1.PackageDeployFailed {
+			refs = append(refs, corev1.ObjectReference{
+				APIVersion: unitStatus.APIVersion,
+				Kind:       unitStatus.Kind,
+				Name:       unitStatus.Name,
+				Namespace:  unitStatus.Namespace,
+			})
+		}
+	}
+
+	return refs
+}
+
+// updateAppsubReportResult updates the result of the appsub in the cluster AppsubReport, with the revision, the
+// images and the failed resources of the appsubstatus so the hub can query the clusters by them
 func updateAppsubReportResult(rClient client.Client, appsubNs, appsubName,
-	clusterAppsubReportNs string, deployFailed, standalone, isLocalCluster bool) error {
+	clusterAppsubReportNs string, deployFailed, standalone, isLocalCluster bool,
+	inventory v1alpha1.SubscriptionClusterStatusMap) error {
+	if !standalone {
+		utils.DelayHubWrite(appsubNs)
+	}
+
 	// For managed clusters, get cluster AppsubReport
 	var appsubReport *v1alpha1.SubscriptionReport
 
@@ -497,6 +630,8 @@ func updateAppsubReportResult(rClient client.Client, appsubNs, appsubName,
 		result = v1alpha1.SubscriptionResult("failed")
 	}
 
+	failed := failedResources(inventory.SubscriptionStatus)
+
 	// Update result in AppsubReport
 	prResultFoundIndex := -1
 
@@ -515,13 +650,22 @@ func updateAppsubReportResult(rClient client.Client, appsubNs, appsubName,
 		klog.V(1).Infof("Add result (source:%v) to appsubReport", prResultSource)
 
 		prFailedResult := &v1alpha1.SubscriptionReportResult{
-			Source:    prResultSource,
-			Result:    result,
-			Timestamp: metaV1.Timestamp{Seconds: time.Now().Unix()},
+			Source:          prResultSource,
+			Result:          result,
+			Timestamp:       metaV1.Timestamp{Seconds: time.Now().Unix()},
+			Revision:        inventory.Revision,
+			Images:          inventory.Images,
+			FailedResources: failed,
 		}
 		appsubReport.Results = append(appsubReport.Results, prFailedResult)
-	} else if prResultFoundIndex >= 0 && appsubReport.Results[prResultFoundIndex].Result != result {
-		appsubReport.Results[prResultFoundIndex].Result = result
+	} else if prResult := appsubReport.Results[prResultFoundIndex]; prResult.Result != result ||
+		prResult.Revision != inventory.Revision || !equality.Semantic.DeepEqual(prResult.Images, inventory.Images) ||
+		!equality.Semantic.DeepEqual(prResult.FailedResources, failed) || prResult.Conflict != "" {
+		prResult.Result = result
+		prResult.Conflict = ""
+		prResult.Revision = inventory.Revision
+		prResult.Images = inventory.Images
+		prResult.FailedResources = failed
 	} else {
 		return nil
 	}
@@ -539,6 +683,10 @@ func deleteAppsubReportResult(rClient client.Client, appsubNs, appsubName, clust
 	source := appsubNs + "/" + appsubName
 	klog.V(1).Infof("Delete AppsubReport result, Namespace:%v, source:%v", clusterAppsubReportNs, source)
 
+	if !standalone {
+		utils.DelayHubWrite(appsubNs)
+	}
+
 	// For managed clusters, get cluster appsubReport, for standalone get app appsubReport
 	var appsubReport *v1alpha1.SubscriptionReport
 
@@ -677,6 +825,77 @@ func shouldSkip(appsubClusterStatus SubscriptionClusterStatus, foundPkgStatus bo
 	return false
 }
 
+// isHelmReleaseUnit checks if the unit status is a HelmRelease
+func isHelmReleaseUnit(unitStatus v1alpha1.SubscriptionUnitStatus) bool {
+	return strings.EqualFold(unitStatus.Kind, "HelmRelease") &&
+		strings.EqualFold(unitStatus.APIVersion, "apps.open-cluster-management.io/v1")
+}
+
+// mergeHelmReleaseUnitStatuses aggregates the unit statuses of the HelmReleases of a subscription with several charts.
+// The helmrelease controller reports the resources of a HelmRelease followed by the HelmRelease itself, the unit
+// statuses of the other HelmReleases are kept. The subscriber reports the HelmReleases alone, the resources of the
+// HelmReleases it still deploys are kept. nil is returned if the unit statuses are not the ones of several HelmReleases.
+func mergeHelmReleaseUnitStatuses(existing, incoming []v1alpha1.SubscriptionUnitStatus) []v1alpha1.SubscriptionUnitStatus {
+	if len(incoming) == 0 || !isHelmReleaseUnit(incoming[len(incoming)-1]) {
+		return nil
+	}
+
+	// group the existing unit statuses by HelmRelease, the resources of a HelmRelease come before it
+	groups := map[string][]v1alpha1.SubscriptionUnitStatus{}
+	order := []string{}
+	group := []v1alpha1.SubscriptionUnitStatus{}
+
+	for _, unitStatus := range existing {
+		group = append(group, unitStatus)
+
+		if isHelmReleaseUnit(unitStatus) {
+			groups[unitStatus.Name] = group
+			order = append(order, unitStatus.Name)
+			group = []v1alpha1.SubscriptionUnitStatus{}
+		}
+	}
+
+	// the helmrelease controller reports a failed HelmRelease alone
+	fromSubscriber := len(incoming) > 1 || incoming[0].Phase != v1alpha1.PackageDeployFailed
+
+	for _, unitStatus := range incoming {
+		if !isHelmReleaseUnit(unitStatus) {
+			fromSubscriber = false
+
+			break
+		}
+	}
+
+	merged := []v1alpha1.SubscriptionUnitStatus{}
+
+	if fromSubscriber && (len(incoming) > 1 || len(order) > 1) {
+		for _, unitStatus := range incoming {
+			if existingGroup, ok := groups[unitStatus.Name]; ok && unitStatus.Phase != v1alpha1.PackageDeployFailed {
+				merged = append(merged, existingGroup...)
+			} else {
+				merged = append(merged, unitStatus)
+			}
+		}
+
+		return merged
+	}
+
+	helmReleaseName := incoming[len(incoming)-1].Name
+	if _, ok := groups[helmReleaseName]; !ok || len(order) < 2 {
+		return nil
+	}
+
+	for _, name := range order {
+		if name == helmReleaseName {
+			merged = append(merged, incoming...)
+		} else {
+			merged = append(merged, groups[name]...)
+		}
+	}
+
+	return merged
+}
+
 func (sync *KubeSynchronizer) getResourcesByLegacySubStatus(appsub *appv1.Subscription) []v1alpha1.SubscriptionUnitStatus {
 	appsubStatuses := []v1alpha1.SubscriptionUnitStatus{}
 	if appsub == nil {
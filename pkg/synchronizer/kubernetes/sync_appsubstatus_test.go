@@ -18,6 +18,7 @@ import (
 	"context"
 	"reflect"
 	"sync"
+	"testing"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -556,3 +557,49 @@ var _ = Describe("test create/update/delete appsub status for standalone and man
 		Expect(shouldSkip(appsubClusterStatus, true, emptyAppsubStatus)).To(BeFalse())
 	})
 })
+
+func TestMergeHelmReleaseUnitStatuses(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	unit := func(kind, name string, phase appSubStatusV1alpha1.PackagePhase) appSubStatusV1alpha1.SubscriptionUnitStatus {
+		apiVersion := "v1"
+		if kind == "HelmRelease" {
+			apiVersion = "apps.open-cluster-management.io/v1"
+		}
+
+		return appSubStatusV1alpha1.SubscriptionUnitStatus{Name: name, Namespace: "default", APIVersion: apiVersion, Kind: kind, Phase: phase}
+	}
+
+	deployed := appSubStatusV1alpha1.PackageDeployed
+	failed := appSubStatusV1alpha1.PackageDeployFailed
+
+	// the subscriber reports the HelmReleases of the charts
+	subscriber := []appSubStatusV1alpha1.SubscriptionUnitStatus{unit("HelmRelease", "nginx", deployed), unit("HelmRelease", "redis", deployed)}
+	g.Expect(mergeHelmReleaseUnitStatuses(nil, subscriber)).To(Equal(subscriber))
+
+	// the helmrelease controller reports the resources of each HelmRelease
+	nginx := []appSubStatusV1alpha1.SubscriptionUnitStatus{unit("Deployment", "nginx", deployed), unit("HelmRelease", "nginx", deployed)}
+	existing := mergeHelmReleaseUnitStatuses(subscriber, nginx)
+	g.Expect(existing).To(Equal(append(append([]appSubStatusV1alpha1.SubscriptionUnitStatus{}, nginx...), subscriber[1])))
+
+	redis := []appSubStatusV1alpha1.SubscriptionUnitStatus{unit("StatefulSet", "redis", deployed), unit("HelmRelease", "redis", deployed)}
+	existing = mergeHelmReleaseUnitStatuses(existing, redis)
+	g.Expect(existing).To(Equal(append(append([]appSubStatusV1alpha1.SubscriptionUnitStatus{}, nginx...), redis...)))
+
+	// the resources of the HelmReleases are kept when the subscriber reports them again
+	g.Expect(mergeHelmReleaseUnitStatuses(existing, subscriber)).To(Equal(existing))
+
+	// a failed HelmRelease replaces its resources
+	redisFailed := []appSubStatusV1alpha1.SubscriptionUnitStatus{unit("HelmRelease", "redis", failed)}
+	g.Expect(mergeHelmReleaseUnitStatuses(existing, redisFailed)).To(Equal(append(append([]appSubStatusV1alpha1.SubscriptionUnitStatus{}, nginx...), redisFailed...)))
+
+	// the resources of a removed chart are dropped
+	g.Expect(mergeHelmReleaseUnitStatuses(existing, []appSubStatusV1alpha1.SubscriptionUnitStatus{subscriber[0]})).To(Equal(nginx))
+	g.Expect(mergeHelmReleaseUnitStatuses(existing, []appSubStatusV1alpha1.SubscriptionUnitStatus{subscriber[0], unit("HelmRelease", "mysql", deployed)})).
+		To(Equal(append(append([]appSubStatusV1alpha1.SubscriptionUnitStatus{}, nginx...), unit("HelmRelease", "mysql", deployed))))
+
+	// the unit statuses of a single HelmRelease or of plain resources are not aggregated
+	g.Expect(mergeHelmReleaseUnitStatuses(nginx, nginx)).To(BeNil())
+	g.Expect(mergeHelmReleaseUnitStatuses(nginx, []appSubStatusV1alpha1.SubscriptionUnitStatus{subscriber[0]})).To(BeNil())
+	g.Expect(mergeHelmReleaseUnitStatuses(existing, []appSubStatusV1alpha1.SubscriptionUnitStatus{unit("ConfigMap", "cm1", deployed)})).To(BeNil())
+}
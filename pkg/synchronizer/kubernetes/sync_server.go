@@ -21,6 +21,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -49,6 +50,7 @@ type SubscriptionUnitStatus struct {
 	Kind       string
 	Phase      string
 	Message    string
+	Hash       string /* hash of the applied manifest */
 }
 
 type SubscriptionClusterStatus struct {
@@ -56,6 +58,13 @@ type SubscriptionClusterStatus struct {
 	AppSub                    types.NamespacedName /* hosting appsub */
 	Action                    string               /* "APPLY" or "DELETE" */
 	SubscriptionPackageStatus []SubscriptionUnitStatus
+	Revision                  string            /* git commit or chart versions of the applied resources */
+	Images                    []string          /* container images of the applied resources */
+	Revisions                 map[string]string /* revisions resolved on the last fetch per source type */
+	GitRemote                 string            /* git remote the revision of the last fetch was cloned from */
+	LastFetchTime             *metav1.Time
+	NextReconcileTime         *metav1.Time
+	CreatedNamespaces         []string /* target namespaces created by the apply */
 }
 
 // KubeSynchronizer handles resources to a kube endpoint.
@@ -65,6 +74,7 @@ type KubeSynchronizer struct {
 	LocalNonCachedClient   client.Client
 	RemoteClient           client.Client
 	RemoteNonCachedClient  client.Client
+	hubClients             map[string]client.Client // the clients of the additional hubs, keyed by hub name
 	localConfig            *rest.Config
 	hub                    bool
 	standalone             bool
@@ -76,6 +86,11 @@ type KubeSynchronizer struct {
 	eventrecorder          *utils.EventRecorder
 	dmtx                   sync.Mutex //this lock protect the dynamicFactory and stopCh
 	SkipAppSubStatusResDel bool       // used by helm subscriber to skip resource delete based on AppSubStatus
+	DrainTimeout           time.Duration
+	drainMtx               sync.Mutex     // this lock protect the stopping flag and the interrupt channel
+	stopping               bool           // no new apply batch is accepted while draining
+	inflight               sync.WaitGroup // the in-flight apply batches
+	interrupt              chan struct{}  // closed when the drain times out
 }
 
 var defaultSynchronizer *KubeSynchronizer
@@ -92,6 +107,8 @@ func Add(mgr manager.Manager, hubconfig *rest.Config, syncid *types.NamespacedNa
 	}
 
 	startCleanup(defaultSynchronizer)
+	startResourceLabelMigration(defaultSynchronizer)
+	startProgressDeadlineCheck(defaultSynchronizer)
 
 	return mgr.Add(defaultSynchronizer)
 }
@@ -131,10 +148,12 @@ func CreateSynchronizer(config, remoteConfig *rest.Config, scheme *runtime.Schem
 		kmtx:           sync.Mutex{},
 		Extension:      ext,
 		dmtx:           sync.Mutex{},
+		DrainTimeout:   defaultDrainTimeout,
+		interrupt:      make(chan struct{}),
 	}
 
 	// set up non cached local client, the local client is the client for managed cluster
-	s.LocalNonCachedClient, err = client.New(config, client.Options{})
+	s.LocalNonCachedClient, err = utils.NewUncachedAPIClient(config, client.Options{}, "synchronizer")
 	if err != nil {
 		klog.Error("Failed to generate client to local cluster with error: ", err)
 
@@ -155,6 +174,18 @@ func CreateSynchronizer(config, remoteConfig *rest.Config, scheme *runtime.Schem
 		}
 
 		s.RemoteClient = s.RemoteNonCachedClient
+
+		// the status of the appsubs propagated by the additional hubs is reported to their hub
+		s.hubClients = map[string]client.Client{}
+
+		for _, hub := range utils.GetAdditionalHubConfigs() {
+			s.hubClients[hub.Name], err = client.New(hub.RestConfig, client.Options{})
+			if err != nil {
+				klog.Errorf("Failed to generate client to additional hub %v with error: %v", hub.Name, err)
+
+				return nil, err
+			}
+		}
 	}
 
 	defaultExtension.localClient = s.LocalClient
@@ -175,11 +206,20 @@ func CreateSynchronizer(config, remoteConfig *rest.Config, scheme *runtime.Schem
 	return s, nil
 }
 
-// this will be triggered by the manager.
+// this will be triggered by the manager. It blocks until the manager stops, then drains the in-flight apply batches
 func (sync *KubeSynchronizer) Start(ctx context.Context) error {
 	klog.Info("start synchronizer")
 	defer klog.Info("stop synchronizer")
 
+	<-ctx.Done()
+
+	timeout := sync.DrainTimeout
+	if timeout <= 0 {
+		timeout = defaultDrainTimeout
+	}
+
+	sync.drain(timeout)
+
 	return nil
 }
 
@@ -203,6 +243,15 @@ func (sync *KubeSynchronizer) GetRemoteNonCachedClient() client.Client {
 	return sync.RemoteNonCachedClient
 }
 
+// RecordEvent records an event of the subscriptions, like the throttling decisions of the agent
+func (sync *KubeSynchronizer) RecordEvent(obj runtime.Object, reason, msg string, err error) {
+	if sync.eventrecorder == nil {
+		return
+	}
+
+	sync.eventrecorder.RecordEvent(obj, reason, msg, err)
+}
+
 // startCleanup starts a goroutine that cleanup all the orphan subscriptionstatuses
 func startCleanup(synchronizer *KubeSynchronizer) {
 	waitDuration := time.Second * 10
@@ -238,6 +238,9 @@ func (sync *KubeSynchronizer) PurgeAllSubscribedResources(appsub *appv1alpha1.Su
 			appSubUnitStatus.Message = ""
 			appSubUnitStatuses = append(appSubUnitStatuses, appSubUnitStatus)
 		}
+
+		// the created namespaces are deleted last, after their resources
+		appSubUnitStatuses = append(appSubUnitStatuses, sync.pruneCreatedNamespaces(appsub, hostSub, appSubStatus)...)
 	}
 
 	appsubClusterStatus := SubscriptionClusterStatus{
@@ -261,6 +264,21 @@ func (sync *KubeSynchronizer) ProcessSubResources(appsub *appv1alpha1.Subscripti
 		Namespace: appsub.GetNamespace(),
 		Name:      appsub.GetName(),
 	}
+
+	// no new batch is accepted on shutdown, the subscription is applied again after the restart
+	if !sync.acquireWork() {
+		klog.Infof("skip applying appsub %v, err: %v", hostSub.String(), ErrDraining)
+
+		return ErrDraining
+	}
+
+	defer sync.releaseWork()
+
+	// the preview teardown must not delete the resources of the other environments
+	if isPreview(appsub) {
+		resources = sync.isolatePreviewResources(appsub, resources)
+	}
+
 	// meaning clean up all the resource from a source:host
 	if len(resources) == 0 {
 		return sync.PurgeAllSubscribedResources(appsub)
@@ -271,15 +289,115 @@ func (sync *KubeSynchronizer) ProcessSubResources(appsub *appv1alpha1.Subscripti
 
 	defer sync.kmtx.Unlock()
 
+	// the batches queued behind the lock are dropped once the drain started
+	if sync.isStopping() {
+		klog.Infof("skip applying appsub %v, err: %v", hostSub.String(), ErrDraining)
+
+		return ErrDraining
+	}
+
 	appSubUnitStatuses := []SubscriptionUnitStatus{}
 	gotDeployErrs := false
 	startTime := time.Now().UnixMilli()
 
+	// rewrite the images to the registry mirrors for disconnected clusters
+	mirrorImages := appsub.GetAnnotations()[appv1alpha1.AnnotationImageMirrorConfigMap] != ""
+	requiredImages := []utils.RequiredImage{}
+
+	mirrors, err := utils.GetSubscriptionImageMirrors(sync.LocalClient, appsub)
+	if err != nil {
+		klog.Errorf("failed to get the image mirrors of appsub %v, err: %v", hostSub.String(), err)
+
+		return err
+	}
+
+	interrupted := false
+	images := []string{}
+	applyBackoff := applyRetryBackoff(appsub)
+	createdNamespaces := []string{}
+	clusterLabels := getClusterLabels(appsub)
+	resourceLabels := sync.resourceLabels(appsub, appliedRevision(appsub, resources))
+
+	// the images not built for the architectures of the cluster are reported instead of failing to start
+	checkArchs := isCheckImageArchitecture(appsub)
+	clusterArchs := []string{}
+	imageArchs := map[string][]string{}
+	archMismatches := []string{}
+	sharedConflicts := []string{}
+
+	if checkArchs {
+		clusterArchs, err = utils.GetClusterArchitectures(sync.LocalClient)
+		if err != nil {
+			klog.Warningf("skip checking the image architectures of appsub %v, err: %v", hostSub.String(), err)
+		}
+	}
+
+	// the images are inspected once before the apply, within a timeout for all of them
+	if checkArchs && len(clusterArchs) > 0 {
+		imageArchs = lookupImageArchitectures(resourceImages(resources, mirrors), imageArchLookupTimeout)
+	}
+
+	// the workloads referencing a ConfigMap or a Secret of the appsub are rolled when their content changes
+	checksums := map[configRef]string{}
+	if isRestartOnConfigChange(appsub) {
+		checksums = sync.configChecksums(appsub, hostSub, resources)
+	}
+
+	// the Deployments selected by the Services are rolled out to their inactive color with the blue/green strategy
+	blueGreen := sync.planBlueGreen(appsub, resources)
+
+	// the other Deployments are converted to Argo Rollouts with the canary strategy of the appsub
+	canary, err := sync.argoRolloutsCanary(appsub)
+	if err != nil {
+		klog.Errorf("failed to get the canary strategy of appsub %v, err: %v", hostSub.String(), err)
+
+		return err
+	}
+
+	// the apply of the revision interrupted by the last shutdown resumes after the resources it applied
+	resumed, resumedDeletions := sync.resumeCheckpoint(appsub, appliedRevision(appsub, resources))
+	renderHashes := map[int]string{}
+
+	applied := newApplyBatch(sync.applyConcurrency(appsub))
+
 	for _, resource := range resources {
+		applied.next(resource.Gvk)
+
+		// the drain timed out, the resources applied so far are checkpointed and the rest on the next apply
+		if sync.isInterrupted() {
+			interrupted = true
+
+			break
+		}
+
 		appSubUnitStatus := SubscriptionUnitStatus{}
 
 		resource := resource
 
+		// the excluded resources are left out of the appsubstatus, they are deleted if they were deployed before
+		excluded, err := isExcludedCluster(resource.Resource, clusterLabels)
+		if err != nil {
+			appSubUnitStatus.APIVersion = resource.Resource.GetAPIVersion()
+			appSubUnitStatus.Kind = resource.Resource.GetKind()
+			appSubUnitStatus.Name = resource.Resource.GetName()
+			appSubUnitStatus.Namespace = resource.Resource.GetNamespace()
+			appSubUnitStatus.Phase = string(appSubStatusV1alpha1.PackageDeployFailed)
+			appSubUnitStatus.Message = err.Error()
+			appSubUnitStatuses = append(appSubUnitStatuses, appSubUnitStatus)
+			gotDeployErrs = true
+
+			klog.Infof("Failed to evaluate the exclude-clusters selector. err: %v", err)
+
+			continue
+		}
+
+		if excluded {
+			klog.Infof("skip resource %v %v/%v of appsub %v, the cluster is excluded", resource.Resource.GetKind(),
+				resource.Resource.GetNamespace(), resource.Resource.GetName(), hostSub.String())
+
+			continue
+		}
+
 		template, err := sync.OverrideResource(hostSub, &resource)
 
 		if err != nil {
@@ -293,8 +411,62 @@ func (sync *KubeSynchronizer) ProcessSubResources(appsub *appv1alpha1.Subscripti
 			continue
 		}
 
+		// the patches of the package overrides are applied to the rendered resources
+		template, err = utils.ApplyPackagePatches(appsub, template)
+		if err != nil {
+			appSubUnitStatus.APIVersion = resource.Resource.GetAPIVersion()
+			appSubUnitStatus.Kind = resource.Resource.GetKind()
+			appSubUnitStatus.Name = resource.Resource.GetName()
+			appSubUnitStatus.Namespace = resource.Resource.GetNamespace()
+			appSubUnitStatus.Phase = string(appSubStatusV1alpha1.PackageDeployFailed)
+			appSubUnitStatus.Message = err.Error()
+			appSubUnitStatuses = append(appSubUnitStatuses, appSubUnitStatus)
+			gotDeployErrs = true
+
+			klog.Infof("Failed to patch resource. err: %v", err)
+
+			continue
+		}
+
+		// the labels of the appsub are set on every resource for the fleet search and the selectors of the users
+		setResourceLabels(template, resourceLabels)
+
+		setConfigChecksum(template, appsub.GetNamespace(), checksums)
+
 		resource.Resource = template
 
+		if _, ok := blueGreen[blueGreenKey(template.GetKind(), template.GetNamespace(), template.GetName())]; !ok &&
+			convertToArgoRollout(canary, &resource) {
+			template = resource.Resource
+		}
+
+		if blueGreen.render(template) {
+			klog.Infof("skip resource %v %v/%v of appsub %v, it is applied by the blue/green rollout", template.GetKind(),
+				template.GetNamespace(), template.GetName(), hostSub.String())
+
+			continue
+		}
+
+		if mirrorImages {
+			requiredImages = append(requiredImages, utils.RewriteImages(template.Object, mirrors)...)
+		}
+
+		if mismatch := checkImageArchitectures(checkArchs, template, clusterArchs, imageArchs); mismatch != "" {
+			appSubUnitStatus.APIVersion = resource.Resource.GetAPIVersion()
+			appSubUnitStatus.Kind = resource.Resource.GetKind()
+			appSubUnitStatus.Name = resource.Resource.GetName()
+			appSubUnitStatus.Namespace = resource.Resource.GetNamespace()
+			appSubUnitStatus.Phase = string(appSubStatusV1alpha1.PackageDeployFailed)
+			appSubUnitStatus.Message = appv1alpha1.ConditionUnsupportedArchitecture + ": " + mismatch
+			appSubUnitStatuses = append(appSubUnitStatuses, appSubUnitStatus)
+			archMismatches = append(archMismatches, mismatch)
+			gotDeployErrs = true
+
+			klog.Infof("Failed to apply resource. err: %v", appSubUnitStatus.Message)
+
+			continue
+		}
+
 		appSubUnitStatus.APIVersion = resource.Resource.GetAPIVersion()
 		appSubUnitStatus.Kind = resource.Resource.GetKind()
 		appSubUnitStatus.Name = resource.Resource.GetName()
@@ -319,23 +491,90 @@ func (sync *KubeSynchronizer) ProcessSubResources(appsub *appv1alpha1.Subscripti
 
 		nri := sync.DynamicClient.Resource(pkgGVR)
 
-		err = sync.applyTemplate(nri, isNamespaced, resource, isSpecialResource(pkgGVR), allowlist, denyList, isAdmin)
+		setAppSubOwnerReference(appsub, resource.Resource, isNamespaced)
 
-		if err != nil {
-			appSubUnitStatus.Phase = string(appSubStatusV1alpha1.PackageDeployFailed)
-			appSubUnitStatus.Message = err.Error()
-			appSubUnitStatuses = append(appSubUnitStatuses, appSubUnitStatus)
-			gotDeployErrs = true
+		if !isNamespaced {
+			setConflictPrecedence(appsub, resource.Resource)
+		}
+
+		renderHash := manifestHash(resource.Resource)
 
-			klog.Errorf("Failed to apply kind template, pkg: %v/%v, error: %v ",
-				appSubUnitStatus.Namespace, appSubUnitStatus.Name, err)
+		if done, ok := resumed[checkpointKey(appSubUnitStatus.APIVersion, appSubUnitStatus.Kind, appSubUnitStatus.Namespace,
+			appSubUnitStatus.Name)]; ok && done.RenderHash == renderHash {
+			klog.Infof("skip resource %v %v/%v of appsub %v, it is applied before the interruption", appSubUnitStatus.Kind,
+				appSubUnitStatus.Namespace, appSubUnitStatus.Name, hostSub.String())
+
+			appSubUnitStatus.Phase = string(appSubStatusV1alpha1.PackageDeployed)
+			appSubUnitStatus.Hash = done.Hash
+			renderHashes[len(appSubUnitStatuses)] = renderHash
+			appSubUnitStatuses = append(appSubUnitStatuses, appSubUnitStatus)
+			images = append(images, utils.ContainerImages(resource.Resource.Object)...)
 
 			continue
 		}
 
-		appSubUnitStatus.Phase = string(appSubStatusV1alpha1.PackageDeployed)
-		appSubUnitStatus.Message = ""
+		// the status of the resource is set once it is applied, in the order of the resources
+		statusIndex := len(appSubUnitStatuses)
+		renderHashes[statusIndex] = renderHash
 		appSubUnitStatuses = append(appSubUnitStatuses, appSubUnitStatus)
+
+		applied.add(resource.Gvk, func() {
+			// a failed resource is retried on the transient errors and doesn't stop the apply of the other resources
+			attempts, err := retryApply(applyBackoff, sync.isInterrupted, func() error {
+				if err := utils.InjectApplyFailure(appsub.Namespace, resource.Resource); err != nil {
+					return err
+				}
+
+				nsCreated, err := sync.applyTemplate(nri, isNamespaced, resource, isSpecialResource(pkgGVR), allowlist, denyList,
+					isAdmin, appsub.Spec.NamespaceCreation)
+				if nsCreated {
+					applied.Lock()
+					createdNamespaces = append(createdNamespaces, resource.Resource.GetNamespace())
+					applied.Unlock()
+				}
+
+				return err
+			})
+
+			applied.Lock()
+			defer applied.Unlock()
+
+			if err != nil {
+				appSubUnitStatus.Phase = string(appSubStatusV1alpha1.PackageDeployFailed)
+				appSubUnitStatus.Message = err.Error()
+				appSubUnitStatuses[statusIndex] = appSubUnitStatus
+				gotDeployErrs = true
+
+				if conflict, ok := sharedResourceConflict(err); ok {
+					sharedConflicts = append(sharedConflicts, conflict)
+				}
+
+				klog.Errorf("Failed to apply kind template, pkg: %v/%v, attempts: %v, error: %v ",
+					appSubUnitStatus.Namespace, appSubUnitStatus.Name, attempts, err)
+
+				return
+			}
+
+			appSubUnitStatus.Phase = string(appSubStatusV1alpha1.PackageDeployed)
+			appSubUnitStatus.Message = ""
+			appSubUnitStatus.Hash = manifestHash(resource.Resource)
+			appSubUnitStatuses[statusIndex] = appSubUnitStatus
+
+			images = append(images, utils.ContainerImages(resource.Resource.Object)...)
+		})
+	}
+
+	applied.flush()
+
+	if len(blueGreen) > 0 && !interrupted {
+		var rolloutFailed bool
+
+		appSubUnitStatuses, rolloutFailed = sync.rolloutBlueGreen(appsub, blueGreen, appSubUnitStatuses)
+		gotDeployErrs = gotDeployErrs || rolloutFailed
+	}
+
+	if canary != nil && !interrupted {
+		gotDeployErrs = sync.updateArgoRolloutStatuses(appSubUnitStatuses) || gotDeployErrs
 	}
 
 	appsubClusterStatus := SubscriptionClusterStatus{
@@ -343,11 +582,48 @@ func (sync *KubeSynchronizer) ProcessSubResources(appsub *appv1alpha1.Subscripti
 		AppSub:                    hostSub,
 		Action:                    "APPLY",
 		SubscriptionPackageStatus: appSubUnitStatuses,
+		Revision:                  appliedRevision(appsub, resources),
+		Images:                    uniqueSorted(images),
+		Revisions:                 appsub.Status.Revisions,
+		GitRemote:                 appsub.Status.GitRemote,
+		LastFetchTime:             appsub.Status.LastFetchTime,
+		NextReconcileTime:         appsub.Status.NextReconcileTime,
+		CreatedNamespaces:         createdNamespaces,
+	}
+
+	sync.setUnsupportedArchitectureCondition(appsub, checkArchs, archMismatches)
+	sync.setSharedResourceConflictCondition(appsub, sharedConflicts)
+
+	if mirrorImages {
+		if err := utils.UpdateRequiredImagesConfigMap(sync.LocalClient, appsub,
+			appv1alpha1.SchemeGroupVersion.WithKind("Subscription"), appsub.GetName(), requiredImages); err != nil {
+			klog.Errorf("failed to update the required images of appsub %v, err: %v", hostSub.String(), err)
+		}
+	}
+
+	if interrupted {
+		klog.Infof("checkpoint the inventory of the interrupted appsub %v", hostSub.String())
+
+		skipOrphanDelete := true
+		appsubClusterStatus.SubscriptionPackageStatus, appsubClusterStatus.Revision, appsubClusterStatus.Images =
+			sync.checkpointUnitStatuses(appsub, appSubUnitStatuses)
+
+		if err := sync.SyncAppsubClusterStatus(appsub, appsubClusterStatus, &skipOrphanDelete, nil); err != nil {
+			klog.Errorf("failed to checkpoint the inventory of appsub %v, err: %v", hostSub.String(), err)
+		}
+
+		return &InterruptedError{
+			AppliedWaves:     appliedWaves(appSubUnitStatuses, renderHashes),
+			PendingDeletions: pendingDeletions(appsubClusterStatus.SubscriptionPackageStatus[len(appSubUnitStatuses):], resources),
+		}
 	}
 
-	err := sync.SyncAppsubClusterStatus(appsub, appsubClusterStatus, nil, nil)
+	err = sync.SyncAppsubClusterStatus(appsub, appsubClusterStatus, nil, nil)
 	endTime := time.Now().UnixMilli()
 
+	// the heartbeat of the agent reports the last sync to the hub
+	utils.RecordAgentSync(err)
+
 	if err != nil {
 		klog.Error("error while sync app sub cluster status: ", err)
 		metrics.LocalDeploymentFailedPullTime.
@@ -357,6 +633,12 @@ func (sync *KubeSynchronizer) ProcessSubResources(appsub *appv1alpha1.Subscripti
 		return err
 	}
 
+	utils.UpdateLastAppliedTime(sync.LocalClient, appsub)
+
+	if len(resumedDeletions) > 0 {
+		sync.deletePendingResources(hostSub, resumedDeletions, appSubUnitStatuses)
+	}
+
 	if gotDeployErrs {
 		metrics.LocalDeploymentFailedPullTime.
 			WithLabelValues(appsub.Namespace, appsub.Name).
@@ -370,11 +652,23 @@ func (sync *KubeSynchronizer) ProcessSubResources(appsub *appv1alpha1.Subscripti
 	return nil
 }
 
-func (sync *KubeSynchronizer) createNewResourceByTemplateUnit(ri dynamic.ResourceInterface, tplunit *unstructured.Unstructured) error {
+// createNewResourceByTemplateUnit creates the resource and its missing target namespace with the labels and the
+// annotations of the namespace creation of the appsub. It returns if the namespace is created
+func (sync *KubeSynchronizer) createNewResourceByTemplateUnit(ri dynamic.ResourceInterface, tplunit *unstructured.Unstructured,
+	nsCreation *appv1alpha1.NamespaceCreation) (bool, error) {
 	klog.Infof("Apply - Creating New Resource: %v/%v, kind: %v", tplunit.GetNamespace(), tplunit.GetName(), tplunit.GetKind())
 
 	tplunit.SetResourceVersion("")
 	obj, err := ri.Create(context.TODO(), tplunit, metav1.CreateOptions{})
+	nsCreated := false
+
+	if err != nil && errors.IsNotFound(err) && tplunit.GetNamespace() != "" && nsCreation != nil && nsCreation.Disabled {
+		klog.Errorf("Failed to apply resource, the namespace %v doesn't exist and the namespace creation is disabled",
+			tplunit.GetNamespace())
+
+		return false, fmt.Errorf("the namespace %v doesn't exist and the namespace creation of the subscription is disabled, err: %w",
+			tplunit.GetNamespace(), err)
+	}
 
 	// Auto Create Namespace if not exist
 	if err != nil && errors.IsNotFound(err) {
@@ -386,9 +680,14 @@ func (sync *KubeSynchronizer) createNewResourceByTemplateUnit(ri dynamic.Resourc
 			tplanno = make(map[string]string)
 		}
 
-		nsanno := ns.GetAnnotations()
-		if nsanno == nil {
-			nsanno = make(map[string]string)
+		nsanno := make(map[string]string)
+
+		if nsCreation != nil {
+			ns.SetLabels(nsCreation.Labels)
+
+			for k, v := range nsCreation.Annotations {
+				nsanno[k] = v
+			}
 		}
 
 		if tplanno[appv1alpha1.AnnotationHosting] > "" {
@@ -419,7 +718,10 @@ func (sync *KubeSynchronizer) createNewResourceByTemplateUnit(ri dynamic.Resourc
 				Resource: "namespaces",
 			}).Create(context.TODO(), nsus, metav1.CreateOptions{})
 
-			if err == nil {
+			// the namespace is created by a resource of the same kind applied in parallel
+			if err == nil || errors.IsAlreadyExists(err) {
+				nsCreated = err == nil
+
 				// try again
 				obj, err = ri.Create(context.TODO(), tplunit, metav1.CreateOptions{})
 			}
@@ -429,7 +731,7 @@ func (sync *KubeSynchronizer) createNewResourceByTemplateUnit(ri dynamic.Resourc
 	if err != nil {
 		klog.Error("Failed to apply resource with error: ", err)
 
-		return err
+		return nsCreated, err
 	}
 
 	obj.SetGroupVersionKind(tplunit.GroupVersionKind())
@@ -438,7 +740,7 @@ func (sync *KubeSynchronizer) createNewResourceByTemplateUnit(ri dynamic.Resourc
 		klog.Error("Failed to update host status with error: ", err)
 	}
 
-	return err
+	return nsCreated, err
 }
 
 // updateResourceByTemplateUnit will have a NamespaceableResourceInterface,
@@ -448,7 +750,7 @@ func (sync *KubeSynchronizer) createNewResourceByTemplateUnit(ri dynamic.Resourc
 //
 // updateResourceByTemplateUnit will then update,patch the obj given tplunit.
 func (sync *KubeSynchronizer) updateResourceByTemplateUnit(ri dynamic.ResourceInterface,
-	origUnit *unstructured.Unstructured, tplunit *unstructured.Unstructured, specialResource bool) error {
+	origUnit *unstructured.Unstructured, tplunit *unstructured.Unstructured, specialResource, takeover bool) error {
 	var err error
 
 	overwrite := false
@@ -459,7 +761,7 @@ func (sync *KubeSynchronizer) updateResourceByTemplateUnit(ri dynamic.ResourceIn
 
 	tmplAnnotations := tplunit.GetAnnotations()
 
-	if tplown != nil && !sync.Extension.IsObjectOwnedByHost(origUnit, *tplown, sync.SynchronizerID) {
+	if tplown != nil && !takeover && !sync.Extension.IsObjectOwnedByHost(origUnit, *tplown, sync.SynchronizerID) {
 		// If the subscription is created by a subscription admin and reconcile option exists,
 		// we can update the resource even if it is not owned by this subscription.
 		// These subscription annotations are passed down payload by the subscribers.
@@ -510,7 +812,9 @@ func (sync *KubeSynchronizer) updateResourceByTemplateUnit(ri dynamic.ResourceIn
 		newobj = utils.RemoveSubOwnerRef(newobj)
 	}
 
-	if (merge || specialResource) && !isHelmRelease {
+	if (merge || specialResource) && !isHelmRelease && utils.FeatureEnabled(utils.ServerSideApply) {
+		err = sync.serverSideApply(ri, newobj)
+	} else if (merge || specialResource) && !isHelmRelease {
 		if specialResource {
 			klog.Info("One of special resources requiring merge update")
 		}
@@ -555,13 +859,20 @@ func (sync *KubeSynchronizer) updateResourceByTemplateUnit(ri dynamic.ResourceIn
 			klog.Info(err.Error())
 
 			return nil
-		} else if errors.IsInvalid(err) {
+		} else if errors.IsInvalid(err) && !(isRecreateOnImmutableChange(tplunit) && isImmutableFieldError(err)) {
 			klog.Info(err.Error())
 
 			return nil
 		}
 	}
 
+	// the resources opting in are deleted and created again instead of failing on each update
+	if isRecreateOnImmutableChange(tplunit) && isImmutableFieldError(err) {
+		klog.Info(err.Error())
+
+		return sync.recreateResource(ri, origUnit, tplunit, overwrite)
+	}
+
 	klog.Info("Check - Updated existing Resource to", tplunit, " with err:", err)
 
 	if err != nil {
@@ -597,7 +908,8 @@ func isSpecialResource(gvr schema.GroupVersionResource) bool {
 }
 
 func (sync *KubeSynchronizer) applyTemplate(nri dynamic.NamespaceableResourceInterface, namespaced bool,
-	resource ResourceUnit, specialResource bool, allowlist, denyList map[string]map[string]string, isAdmin bool) error {
+	resource ResourceUnit, specialResource bool, allowlist, denyList map[string]map[string]string, isAdmin bool,
+	nsCreation *appv1alpha1.NamespaceCreation) (bool, error) {
 	tplunit := resource.Resource
 	klog.Infof("Applying template: %v/%v, kind: %v", tplunit.GetNamespace(), tplunit.GetName(), tplunit.GetKind())
 
@@ -611,7 +923,7 @@ func (sync *KubeSynchronizer) applyTemplate(nri dynamic.NamespaceableResourceInt
 	if !utils.AllowApplyTemplate(sync.LocalClient, tplunit) {
 		klog.Infof("Applying template is paused: %v/%v, kind: %v", tplunit.GetNamespace(), tplunit.GetName(), tplunit.GetKind())
 
-		return nil
+		return false, nil
 	}
 
 	if utils.IsResourceDenied(*tplunit, denyList, isAdmin) {
@@ -620,7 +932,7 @@ func (sync *KubeSynchronizer) applyTemplate(nri dynamic.NamespaceableResourceInt
 
 		klog.Info(denyError.Error())
 
-		return denyError
+		return false, denyError
 	}
 
 	if !utils.IsResourceAllowed(*tplunit, allowlist, isAdmin) {
@@ -634,24 +946,42 @@ func (sync *KubeSynchronizer) applyTemplate(nri dynamic.NamespaceableResourceInt
 
 		klog.Info(denyError.Error())
 
-		return denyError
+		return false, denyError
 	}
 
+	if err := sync.validateOwnerReferences(tplunit, namespaced); err != nil {
+		klog.Info(err.Error())
+
+		return false, err
+	}
+
+	nsCreated := false
 	origUnit, err := ri.Get(context.TODO(), tplunit.GetName(), metav1.GetOptions{})
 
 	if err != nil {
 		if errors.IsNotFound(err) {
-			err = sync.createNewResourceByTemplateUnit(ri, tplunit)
+			nsCreated, err = sync.createNewResourceByTemplateUnit(ri, tplunit, nsCreation)
 		} else {
 			klog.Error("Failed to apply resource with error:", err)
 		}
 	} else {
-		err = sync.updateResourceByTemplateUnit(ri, origUnit, tplunit, specialResource)
+		// the resources managed by the other GitOps controllers are only updated once adopted
+		err = sync.checkAdoption(origUnit, tplunit)
+
+		// the cluster-scoped resources deployed by other appsubs are applied by the appsub with the highest precedence
+		takeover := false
+		if err == nil && !namespaced {
+			takeover, err = sync.checkSharedResource(origUnit, tplunit)
+		}
+
+		if err == nil {
+			err = sync.updateResourceByTemplateUnit(ri, origUnit, tplunit, specialResource, takeover)
+		}
 	}
 
 	klog.Infof("Applied Kind Template: %v/%v, err: %v ", tplunit.GetNamespace(), tplunit.GetName(), err)
 
-	return err
+	return nsCreated, err
 }
 
 // OverrideResource updates resource based on the hosting appsub before the resource is deployed.
@@ -682,25 +1012,12 @@ func (sync *KubeSynchronizer) OverrideResource(hostSub types.NamespacedName, res
 		template.SetName(hostSub.Name)
 	}
 
-	// carry/override with appsub labels
-	tpllbls := template.GetLabels()
-	if tpllbls == nil {
-		tpllbls = make(map[string]string)
-	}
-
-	klog.V(1).Infof("pre template lables : %v", tpllbls)
-
-	for k, v := range appsub.GetLabels() {
-		if _, ok := tpllbls[k]; ok {
-			continue
-		}
-
-		tpllbls[k] = v
-	}
+	// carry/override with appsub labels and annotations
+	klog.V(1).Infof("pre template lables : %v", template.GetLabels())
 
-	klog.V(1).Infof("template lables combinded with appsub labels: %v", tpllbls)
+	propagateMetadata(template, appsub)
 
-	template.SetLabels(tpllbls)
+	klog.V(1).Infof("template lables combinded with appsub labels: %v", template.GetLabels())
 
 	err = sync.Extension.SetHostToObject(template, hostSub, sync.SynchronizerID)
 	if err != nil {
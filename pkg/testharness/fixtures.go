@@ -0,0 +1,154 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testharness
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	spokeClusterV1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+
+	plrv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+const (
+	// PlacementRuleLabel and PlacementLabel label the placement decisions with the name of their PlacementRule or
+	// Placement, the hub finds the decisions of a subscription placement with them
+	PlacementRuleLabel = "cluster.open-cluster-management.io/placementrule"
+	PlacementLabel     = "cluster.open-cluster-management.io/placement"
+)
+
+// NewManagedCluster returns a managed cluster accepted by the hub
+func NewManagedCluster(name string, labels map[string]string) *spokeClusterV1.ManagedCluster {
+	return &spokeClusterV1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Spec:       spokeClusterV1.ManagedClusterSpec{HubAcceptsClient: true},
+	}
+}
+
+// NewChannel returns a channel of the type, the pathname is the URL of the Git repository, of the Helm repository
+// or of the bucket, like the URL of the fake servers
+func NewChannel(namespace, name string, channelType chnv1.ChannelType, pathname string) *chnv1.Channel {
+	return &chnv1.Channel{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: chnv1.ChannelSpec{
+			Type:     channelType,
+			Pathname: pathname,
+		},
+	}
+}
+
+// NewSubscription returns a subscription of the channel, <namespace>/<name> of the channel. It is not placed on any
+// cluster until PlaceOnClusters, PlaceByPlacementRule or PlaceByPlacement
+func NewSubscription(namespace, name, channel string) *appv1.Subscription {
+	return &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Annotations: map[string]string{}},
+		Spec: appv1.SubscriptionSpec{
+			Channel: channel,
+		},
+	}
+}
+
+// PlaceOnClusters places the subscription on the clusters by their names
+func PlaceOnClusters(sub *appv1.Subscription, clusters ...string) *appv1.Subscription {
+	refs := make([]plrv1.GenericClusterReference, 0, len(clusters))
+	for _, cluster := range clusters {
+		refs = append(refs, plrv1.GenericClusterReference{Name: cluster})
+	}
+
+	sub.Spec.Placement = &plrv1.Placement{GenericPlacementFields: plrv1.GenericPlacementFields{Clusters: refs}}
+
+	return sub
+}
+
+// PlaceByPlacementRule places the subscription with the PlacementRule of its namespace
+func PlaceByPlacementRule(sub *appv1.Subscription, placementRule string) *appv1.Subscription {
+	sub.Spec.Placement = &plrv1.Placement{
+		PlacementRef: &corev1.ObjectReference{Kind: "PlacementRule", Name: placementRule},
+	}
+
+	return sub
+}
+
+// PlaceByPlacement places the subscription with the Placement of its namespace
+func PlaceByPlacement(sub *appv1.Subscription, placement string) *appv1.Subscription {
+	sub.Spec.Placement = &plrv1.Placement{
+		PlacementRef: &corev1.ObjectReference{
+			Kind:       "Placement",
+			APIVersion: "cluster.open-cluster-management.io/v1beta1",
+			Name:       placement,
+		},
+	}
+
+	return sub
+}
+
+// NewPlacementRule returns a PlacementRule which decided the clusters. The hub reads the decisions of the
+// PlacementRule from its PlacementDecision, see NewPlacementRuleDecision
+func NewPlacementRule(namespace, name string, clusters ...string) *plrv1.PlacementRule {
+	plr := &plrv1.PlacementRule{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+	}
+
+	for _, cluster := range clusters {
+		plr.Status.Decisions = append(plr.Status.Decisions, plrv1.PlacementDecision{
+			ClusterName:      cluster,
+			ClusterNamespace: cluster,
+		})
+	}
+
+	return plr
+}
+
+// NewPlacementRuleDecision returns the PlacementDecision of the PlacementRule with the clusters, created with
+// CreateWithStatus on an API server
+func NewPlacementRuleDecision(namespace, placementRule string, clusters ...string) *clusterv1beta1.PlacementDecision {
+	return newPlacementDecision(namespace, placementRule, PlacementRuleLabel, clusters)
+}
+
+// NewPlacement returns a Placement of the clusters of the ManagedClusterSets bound to its namespace. The hub reads
+// the decisions of the Placement from its PlacementDecision, see NewPlacementDecision
+func NewPlacement(namespace, name string) *clusterv1beta1.Placement {
+	return &clusterv1beta1.Placement{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+	}
+}
+
+// NewPlacementDecision returns the PlacementDecision of the Placement with the clusters, created with
+// CreateWithStatus on an API server
+func NewPlacementDecision(namespace, placement string, clusters ...string) *clusterv1beta1.PlacementDecision {
+	return newPlacementDecision(namespace, placement, PlacementLabel, clusters)
+}
+
+func newPlacementDecision(namespace, placement, label string, clusters []string) *clusterv1beta1.PlacementDecision {
+	decision := &clusterv1beta1.PlacementDecision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      placement + "-decision-1",
+			Namespace: namespace,
+			Labels:    map[string]string{label: placement},
+		},
+	}
+
+	for _, cluster := range clusters {
+		decision.Status.Decisions = append(decision.Status.Decisions, clusterv1beta1.ClusterDecision{
+			ClusterName: cluster,
+			Reason:      "test",
+		})
+	}
+
+	return decision
+}
@@ -0,0 +1,245 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testharness
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/pktline"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp/capability"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/server"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// GitServerBranch is the default branch of the GitServer repository, the branch of the Git subscriptions without a
+// branch annotation
+const GitServerBranch = "master"
+
+// GitServer serves an in-memory Git repository over the smart HTTP protocol, for the Git channels of the tests
+type GitServer struct {
+	// URL of the repository, the pathname of the Git channels
+	URL string
+
+	lock     sync.Mutex
+	storage  *memory.Storage
+	worktree billy.Filesystem
+	repo     *git.Repository
+	server   *httptest.Server
+}
+
+// NewGitServer starts serving a repository with a first commit of the files, by path in the repository
+func NewGitServer(files map[string]string) (*GitServer, error) {
+	s := &GitServer{
+		storage:  memory.NewStorage(),
+		worktree: memfs.New(),
+	}
+
+	repo, err := git.Init(s.storage, s.worktree)
+	if err != nil {
+		return nil, err
+	}
+
+	s.repo = repo
+
+	head := plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.NewBranchReferenceName(GitServerBranch))
+	if err := s.storage.SetReference(head); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.Commit(files, "initial commit"); err != nil {
+		return nil, err
+	}
+
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.server.URL + "/repo.git"
+
+	return s, nil
+}
+
+// Close stops serving the repository
+func (s *GitServer) Close() {
+	s.server.Close()
+}
+
+// Commit writes the files to the default branch, an empty content deletes the file. It returns the commit hash
+func (s *GitServer) Commit(files map[string]string, message string) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	worktree, err := s.repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		if files[path] == "" {
+			if _, err := worktree.Remove(path); err != nil {
+				return "", err
+			}
+
+			continue
+		}
+
+		if err := writeFile(s.worktree, path, files[path]); err != nil {
+			return "", err
+		}
+
+		if _, err := worktree.Add(path); err != nil {
+			return "", err
+		}
+	}
+
+	hash, err := worktree.Commit(message, &git.CommitOptions{
+		Author: &object.Signature{Name: "testharness", Email: "testharness@example.com", When: time.Now()},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return hash.String(), nil
+}
+
+// Tag tags the head of the default branch
+func (s *GitServer) Tag(name string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	head, err := s.repo.Head()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.repo.CreateTag(name, head.Hash(), nil)
+
+	return err
+}
+
+func writeFile(fs billy.Filesystem, path, content string) error {
+	f, err := fs.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write([]byte(content)); err != nil {
+		f.Close()
+
+		return err
+	}
+
+	return f.Close()
+}
+
+// Load implements the server.Loader of the upload-pack sessions, all the endpoints are the repository
+func (s *GitServer) Load(ep *transport.Endpoint) (storer.Storer, error) {
+	return s.storage, nil
+}
+
+func (s *GitServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	session, err := server.NewServer(s).NewUploadPackSession(&transport.Endpoint{}, nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/repo.git/info/refs" &&
+		r.URL.Query().Get("service") == transport.UploadPackServiceName:
+		err = s.advertiseReferences(r.Context(), w, session)
+	case r.Method == http.MethodPost && r.URL.Path == "/repo.git/"+transport.UploadPackServiceName:
+		err = s.uploadPack(r, w, session)
+	default:
+		http.NotFound(w, r)
+
+		return
+	}
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *GitServer) advertiseReferences(ctx context.Context, w http.ResponseWriter,
+	session transport.UploadPackSession) error {
+	refs, err := session.AdvertisedReferencesContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// the shallow clones of the subscriptions get the whole history, the server doesn't support shallow packs
+	if err := refs.Capabilities.Set(capability.Shallow); err != nil {
+		return err
+	}
+
+	refs.Prefix = [][]byte{[]byte("# service=" + transport.UploadPackServiceName), pktline.Flush}
+
+	w.Header().Set("Content-Type", fmt.Sprintf("application/x-%v-advertisement", transport.UploadPackServiceName))
+
+	return refs.Encode(w)
+}
+
+func (s *GitServer) uploadPack(r *http.Request, w http.ResponseWriter, session transport.UploadPackSession) error {
+	req := packp.NewUploadPackRequest()
+	if err := req.Decode(r.Body); err != nil {
+		return err
+	}
+
+	shallow := !req.Depth.IsZero()
+
+	req.Depth = packp.DepthCommits(0)
+	req.Capabilities.Delete(capability.Shallow)
+
+	resp, err := session.UploadPack(r.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Close()
+
+	w.Header().Set("Content-Type", fmt.Sprintf("application/x-%v-result", transport.UploadPackServiceName))
+
+	// the shallow clones expect the shallow update of the depth they asked, empty since the whole history is sent
+	if shallow {
+		if err := pktline.NewEncoder(w).Flush(); err != nil {
+			return err
+		}
+	}
+
+	return resp.Encode(w)
+}
@@ -0,0 +1,219 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package testharness runs the subscription controllers against an envtest hub with fixtures of the channels, the
+// subscriptions and the placements, and serves fake Git, Helm and object bucket channels in process. It is used by
+// the integration tests of the repository and by the out-of-tree channel backends, hooks and override features.
+package testharness
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apiruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	manifestWorkV1 "open-cluster-management.io/api/work/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/apis"
+	appsubv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+)
+
+// Scheme returns a scheme with the Kubernetes, channel, subscription, placement, cluster and manifestWork APIs
+func Scheme() (*apiruntime.Scheme, error) {
+	scheme := apiruntime.NewScheme()
+
+	for _, addToScheme := range []func(*apiruntime.Scheme) error{
+		clientgoscheme.AddToScheme,
+		apis.AddToScheme,
+		appsubv1alpha1.AddToScheme,
+	} {
+		if err := addToScheme(scheme); err != nil {
+			return nil, err
+		}
+	}
+
+	return scheme, nil
+}
+
+// NewFakeClient returns a fake client of the Scheme APIs with the objects, for the tests without an API server
+func NewFakeClient(objs ...client.Object) (client.Client, error) {
+	scheme, err := Scheme()
+	if err != nil {
+		return nil, err
+	}
+
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(), nil
+}
+
+// RepositoryRoot returns the root of the subscription repository, or of its module in the module cache for the
+// out-of-tree tests, which has the CRDs installed by StartHub
+func RepositoryRoot() string {
+	_, file, _, _ := runtime.Caller(0)
+
+	return filepath.Join(filepath.Dir(file), "..", "..")
+}
+
+// CRDDirectoryPaths returns the CRD folders of the hub: the subscription CRDs and the managedCluster, manifestWork,
+// placementDecision and ansibleJob CRDs of the test folder
+func CRDDirectoryPaths() []string {
+	root := RepositoryRoot()
+
+	return []string{
+		filepath.Join(root, "deploy", "hub-common"),
+		filepath.Join(root, "hack", "test"),
+	}
+}
+
+// Hub is an envtest API server with the hub CRDs
+type Hub struct {
+	Env    *envtest.Environment
+	Config *rest.Config
+	Client client.Client
+	Scheme *apiruntime.Scheme
+}
+
+// StartHub starts an envtest API server with the CRDs of CRDDirectoryPaths and the additional CRD folders, the
+// envtest binaries are found with the KUBEBUILDER_ASSETS environment variable
+func StartHub(crdDirs ...string) (*Hub, error) {
+	scheme, err := Scheme()
+	if err != nil {
+		return nil, err
+	}
+
+	env := &envtest.Environment{
+		CRDDirectoryPaths:     append(CRDDirectoryPaths(), crdDirs...),
+		ErrorIfCRDPathMissing: true,
+	}
+
+	cfg, err := env.Start()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start envtest, err: %w", err)
+	}
+
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		_ = env.Stop()
+
+		return nil, err
+	}
+
+	return &Hub{Env: env, Config: cfg, Client: c, Scheme: scheme}, nil
+}
+
+// Stop stops the API server of the hub
+func (h *Hub) Stop() error {
+	return h.Env.Stop()
+}
+
+// NewManager returns a manager of the hub without metrics and leader election, the controllers under test are added
+// to it before StartManager
+func (h *Hub) NewManager() (manager.Manager, error) {
+	return manager.New(h.Config, manager.Options{
+		Scheme:             h.Scheme,
+		MetricsBindAddress: "0",
+		LeaderElection:     false,
+	})
+}
+
+// StartManager starts the manager until the context is done, the returned channel is closed once it stopped
+func StartManager(ctx context.Context, mgr manager.Manager) <-chan error {
+	done := make(chan error, 1)
+
+	go func() {
+		defer close(done)
+
+		done <- mgr.Start(ctx)
+	}()
+
+	return done
+}
+
+// CreateNamespace creates the namespace if it doesn't exist
+func CreateNamespace(ctx context.Context, c client.Client, name string) error {
+	err := c.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	if err != nil && !kerrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	return nil
+}
+
+// CreateManagedCluster creates the managed cluster and its namespace, the namespace of its manifestWorks
+func CreateManagedCluster(ctx context.Context, c client.Client, name string, labels map[string]string) error {
+	if err := CreateNamespace(ctx, c, name); err != nil {
+		return err
+	}
+
+	err := c.Create(ctx, NewManagedCluster(name, labels))
+	if err != nil && !kerrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	return nil
+}
+
+// CreateWithStatus creates the object then updates its status, the API server drops the status of the created
+// objects with a status subresource like the placement decisions
+func CreateWithStatus(ctx context.Context, c client.Client, obj client.Object) error {
+	status := obj.DeepCopyObject().(client.Object)
+
+	if err := c.Create(ctx, obj); err != nil {
+		return err
+	}
+
+	status.SetResourceVersion(obj.GetResourceVersion())
+	status.SetUID(obj.GetUID())
+
+	if err := c.Status().Update(ctx, status); err != nil {
+		return err
+	}
+
+	obj.SetResourceVersion(status.GetResourceVersion())
+
+	return nil
+}
+
+// WaitForManifestWork waits for the hub to propagate the subscription to the cluster and returns its manifestWork
+func WaitForManifestWork(ctx context.Context, c client.Client, cluster string, sub types.NamespacedName,
+	timeout time.Duration) (*manifestWorkV1.ManifestWork, error) {
+	work := &manifestWorkV1.ManifestWork{}
+	key := types.NamespacedName{Namespace: cluster, Name: sub.Namespace + "-" + sub.Name}
+
+	err := wait.PollImmediate(100*time.Millisecond, timeout, func() (bool, error) {
+		err := c.Get(ctx, key, work)
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return err == nil, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("manifestWork %v not found, err: %w", key, err)
+	}
+
+	return work, nil
+}
@@ -0,0 +1,172 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testharness
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/johannesboyne/gofakes3"
+	"github.com/johannesboyne/gofakes3/backend/s3mem"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/repo"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	awsutils "open-cluster-management.io/multicloud-operators-subscription/pkg/utils/aws"
+)
+
+// HelmRepoServer serves a Helm repository of the charts added with AddChart, for the HelmRepo channels of the tests
+type HelmRepoServer struct {
+	// URL of the repository, the pathname of the HelmRepo channels
+	URL string
+
+	lock   sync.Mutex
+	dir    string
+	server *httptest.Server
+}
+
+// NewHelmRepoServer starts serving an empty Helm repository
+func NewHelmRepoServer() (*HelmRepoServer, error) {
+	dir, err := os.MkdirTemp("", "testharness-helmrepo-")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &HelmRepoServer{dir: dir}
+	files := http.FileServer(http.Dir(dir))
+
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		files.ServeHTTP(w, r)
+	}))
+	s.URL = s.server.URL
+
+	if err := s.index(); err != nil {
+		s.Close()
+
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Close stops serving the repository and deletes its charts
+func (s *HelmRepoServer) Close() {
+	s.server.Close()
+	os.RemoveAll(s.dir)
+}
+
+// AddChart packages a chart of the templates, by file name in the templates folder, and adds it to the index of
+// the repository
+func (s *HelmRepoServer) AddChart(name, version string, templates map[string]string) error {
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{
+			APIVersion: chart.APIVersionV2,
+			Name:       name,
+			Version:    version,
+			AppVersion: version,
+		},
+	}
+
+	for file, content := range templates {
+		ch.Templates = append(ch.Templates, &chart.File{
+			Name: filepath.Join("templates", file),
+			Data: []byte(content),
+		})
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, err := chartutil.Save(ch, s.dir); err != nil {
+		return err
+	}
+
+	return s.index()
+}
+
+func (s *HelmRepoServer) index() error {
+	index, err := repo.IndexDirectory(s.dir, s.URL)
+	if err != nil {
+		return err
+	}
+
+	index.SortEntries()
+
+	return index.WriteFile(filepath.Join(s.dir, "index.yaml"), 0600)
+}
+
+// ObjectBucketServer serves fake S3 buckets, for the ObjectBucket channels of the tests. Any access key is accepted
+type ObjectBucketServer struct {
+	// URL of the server, the pathname of the ObjectBucket channels is <URL>/<bucket>
+	URL string
+
+	backend *s3mem.Backend
+	server  *httptest.Server
+}
+
+// NewObjectBucketServer starts serving the buckets
+func NewObjectBucketServer(buckets ...string) (*ObjectBucketServer, error) {
+	backend := s3mem.New()
+
+	for _, bucket := range buckets {
+		if err := backend.CreateBucket(bucket); err != nil {
+			return nil, err
+		}
+	}
+
+	server := httptest.NewServer(gofakes3.New(backend).Server())
+
+	return &ObjectBucketServer{URL: server.URL, backend: backend, server: server}, nil
+}
+
+// Close stops serving the buckets
+func (s *ObjectBucketServer) Close() {
+	s.server.Close()
+}
+
+// PutObject puts the object in the bucket, the objects of the subscriptions are the YAML templates of the resources
+func (s *ObjectBucketServer) PutObject(bucket, name, content string) error {
+	_, err := s.backend.PutObject(bucket, name, map[string]string{}, bytes.NewBufferString(content), int64(len(content)))
+
+	return err
+}
+
+// Secret returns the secret of the ObjectBucket channels of the server, referenced by their secretRef
+func (s *ObjectBucketServer) Secret(namespace, name string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Data: map[string][]byte{
+			awsutils.SecretMapKeyAccessKeyID:     []byte("testharness"),
+			awsutils.SecretMapKeySecretAccessKey: []byte("testharness"),
+			awsutils.SecretMapKeyRegion:          []byte("us-east-1"),
+		},
+	}
+}
+
+// DeleteObject deletes the object from the bucket
+func (s *ObjectBucketServer) DeleteObject(bucket, name string) error {
+	_, err := s.backend.DeleteObject(bucket, name)
+
+	return err
+}
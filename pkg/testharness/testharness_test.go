@@ -0,0 +1,135 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testharness
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/repo"
+	"sigs.k8s.io/yaml"
+
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
+	awsutils "open-cluster-management.io/multicloud-operators-subscription/pkg/utils/aws"
+)
+
+func TestGitServer(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	s, err := NewGitServer(map[string]string{"app/configmap.yaml": "kind: ConfigMap\n"})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	defer s.Close()
+
+	clone := func(opts *utils.GitCloneOption) (string, string) {
+		opts.DestDir = t.TempDir()
+		opts.PrimaryConnectionOption = &utils.ChannelConnectionCfg{RepoURL: s.URL}
+
+		commit, err := utils.CloneGitRepo(opts)
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+
+		return commit, opts.DestDir
+	}
+
+	// the shallow clone of the default branch
+	first, dir := clone(&utils.GitCloneOption{Branch: utils.GetSubscriptionBranchRef(GitServerBranch)})
+
+	content, err := os.ReadFile(filepath.Join(dir, "app", "configmap.yaml"))
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(string(content)).To(gomega.Equal("kind: ConfigMap\n"))
+
+	g.Expect(s.Tag("v1")).To(gomega.Succeed())
+
+	second, err := s.Commit(map[string]string{"app/configmap.yaml": "", "app/secret.yaml": "kind: Secret\n"}, "second")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	commit, dir := clone(&utils.GitCloneOption{})
+	g.Expect(commit).To(gomega.Equal(second))
+	g.Expect(filepath.Join(dir, "app", "configmap.yaml")).NotTo(gomega.BeAnExistingFile())
+	g.Expect(filepath.Join(dir, "app", "secret.yaml")).To(gomega.BeAnExistingFile())
+
+	// the revision tags are checked out from the history
+	commit, dir = clone(&utils.GitCloneOption{RevisionTag: "v1"})
+	g.Expect(commit).To(gomega.Equal(first))
+	g.Expect(filepath.Join(dir, "app", "configmap.yaml")).To(gomega.BeAnExistingFile())
+}
+
+func TestHelmRepoServer(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	s, err := NewHelmRepoServer()
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	defer s.Close()
+
+	g.Expect(s.AddChart("nginx", "1.0.0", map[string]string{"cm.yaml": "kind: ConfigMap\n"})).To(gomega.Succeed())
+	g.Expect(s.AddChart("nginx", "1.1.0", map[string]string{"cm.yaml": "kind: ConfigMap\n"})).To(gomega.Succeed())
+
+	get := func(url string) []byte {
+		resp, err := http.Get(url) // #nosec G107 the URL of the test server
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+
+		defer resp.Body.Close()
+
+		g.Expect(resp.StatusCode).To(gomega.Equal(http.StatusOK))
+
+		body, err := io.ReadAll(resp.Body)
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+
+		return body
+	}
+
+	index := &repo.IndexFile{}
+	g.Expect(yaml.Unmarshal(get(s.URL+"/index.yaml"), index)).To(gomega.Succeed())
+	g.Expect(index.Entries["nginx"]).To(gomega.HaveLen(2))
+	g.Expect(index.Entries["nginx"][0].Version).To(gomega.Equal("1.1.0"))
+	g.Expect(index.Entries["nginx"][0].URLs).To(gomega.Equal([]string{s.URL + "/nginx-1.1.0.tgz"}))
+	g.Expect(get(index.Entries["nginx"][0].URLs[0])).NotTo(gomega.BeEmpty())
+}
+
+func TestObjectBucketServer(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	s, err := NewObjectBucketServer("apps")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	defer s.Close()
+
+	g.Expect(s.PutObject("apps", "configmap", "kind: ConfigMap\n")).To(gomega.Succeed())
+
+	secret := s.Secret("chn-ns", "bucket-secret")
+	handler := &awsutils.Handler{}
+	g.Expect(handler.InitObjectStoreConnection(s.URL, string(secret.Data[awsutils.SecretMapKeyAccessKeyID]),
+		string(secret.Data[awsutils.SecretMapKeySecretAccessKey]), string(secret.Data[awsutils.SecretMapKeyRegion]))).To(
+		gomega.Succeed())
+
+	names, err := handler.List("apps", nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(names).To(gomega.Equal([]string{"configmap"}))
+
+	obj, err := handler.Get("apps", "configmap")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(string(obj.Content)).To(gomega.Equal("kind: ConfigMap\n"))
+
+	g.Expect(s.DeleteObject("apps", "configmap")).To(gomega.Succeed())
+
+	names, err = handler.List("apps", nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(names).To(gomega.BeEmpty())
+}
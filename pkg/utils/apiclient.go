@@ -0,0 +1,209 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	appsubReportV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+)
+
+// userAgentPrefix starts the user-agents of the operator requests to the API servers
+const userAgentPrefix = "multicluster-operators-subscription"
+
+// APIClientOptions configures the clients of the controllers to the API server. The status writes have their own
+// rate limiter and user-agent so a storm of status updates doesn't starve the spec reads and writes, and the API
+// Priority and Fairness flow schemas of the API server can grant them a lower priority level
+type APIClientOptions struct {
+	// Component identifies the operator in the user-agents, e.g. hub-subscription or application-manager
+	Component   string
+	QPS         float32
+	Burst       int
+	StatusQPS   float32
+	StatusBurst int
+}
+
+var (
+	apiClientLock     sync.RWMutex
+	apiClientOptions  *APIClientOptions
+	statusRateLimiter flowcontrol.RateLimiter
+	// apiClientUncached are the objects the manager client reads from the API server, reused by the controller clients
+	apiClientUncached []client.Object
+)
+
+// ConfigureAPIClient sets the user-agent and the rate limiter of the spec requests on the config of the manager. The
+// rate limiter is shared by all the clients of the config instead of one rate limiter per resource kind
+func ConfigureAPIClient(cfg *rest.Config, opts APIClientOptions) {
+	cfg.UserAgent = APIUserAgent(opts.Component, "", false)
+	cfg.QPS = opts.QPS
+	cfg.Burst = opts.Burst
+	cfg.RateLimiter = flowcontrol.NewTokenBucketRateLimiter(opts.QPS, opts.Burst)
+
+	apiClientLock.Lock()
+	defer apiClientLock.Unlock()
+
+	apiClientOptions = &opts
+	statusRateLimiter = flowcontrol.NewTokenBucketRateLimiter(opts.StatusQPS, opts.StatusBurst)
+
+	klog.Infof("API client user-agent: %v, qps: %v, burst: %v, status qps: %v, status burst: %v", cfg.UserAgent,
+		opts.QPS, opts.Burst, opts.StatusQPS, opts.StatusBurst)
+}
+
+// APIUserAgent returns the user-agent of the requests of a controller of the component,
+// multicluster-operators-subscription/<component>[/<controller>][/status] (<os>/<arch>)
+func APIUserAgent(component, controller string, status bool) string {
+	ua := userAgentPrefix
+
+	if component != "" {
+		ua += "/" + component
+	}
+
+	if controller != "" {
+		ua += "/" + controller
+	}
+
+	if status {
+		ua += "/status"
+	}
+
+	return ua + " (" + runtime.GOOS + "/" + runtime.GOARCH + ")"
+}
+
+// NewManagerClient is the NewClient function of the managers, the status writes of its client go through the status
+// rate limiter when the API client is configured
+func NewManagerClient(cache cache.Cache, config *rest.Config, options client.Options,
+	uncachedObjects ...client.Object) (client.Client, error) {
+	apiClientLock.Lock()
+	apiClientUncached = uncachedObjects
+	apiClientLock.Unlock()
+
+	return newAPIClient(cache, config, options, "", uncachedObjects)
+}
+
+// ControllerClient returns the client of a controller, it reads from the manager cache and its requests carry the
+// user-agent of the controller. The manager client is returned if the API client isn't configured
+func ControllerClient(mgr manager.Manager, controller string) client.Client {
+	apiClientLock.RLock()
+	configured, uncached := apiClientOptions != nil, apiClientUncached
+	apiClientLock.RUnlock()
+
+	if !configured {
+		return mgr.GetClient()
+	}
+
+	c, err := newAPIClient(mgr.GetCache(), mgr.GetConfig(),
+		client.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()}, controller, uncached)
+	if err != nil {
+		klog.Warningf("failed to create the client of controller %v, using the manager client, err: %v", controller, err)
+
+		return mgr.GetClient()
+	}
+
+	return c
+}
+
+// NewUncachedAPIClient returns a client reading from the API server for a controller, its requests carry the
+// user-agent of the controller and its status writes go through the status rate limiter when the API client is
+// configured
+func NewUncachedAPIClient(config *rest.Config, options client.Options, controller string) (client.Client, error) {
+	return newAPIClient(nil, config, options, controller, nil)
+}
+
+// newAPIClient returns the client of the controller, reading from the cache if not nil
+func newAPIClient(cache cache.Cache, config *rest.Config, options client.Options, controller string,
+	uncachedObjects []client.Object) (client.Client, error) {
+	apiClientLock.RLock()
+	opts, limiter := apiClientOptions, statusRateLimiter
+	apiClientLock.RUnlock()
+
+	specConfig := rest.CopyConfig(config)
+	if opts != nil {
+		specConfig.UserAgent = APIUserAgent(opts.Component, controller, false)
+	}
+
+	specClient, err := client.New(specConfig, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if cache != nil {
+		specClient, err = client.NewDelegatingClient(client.NewDelegatingClientInput{
+			CacheReader:     cache,
+			Client:          specClient,
+			UncachedObjects: uncachedObjects,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if opts == nil {
+		return specClient, nil
+	}
+
+	statusConfig := rest.CopyConfig(config)
+	statusConfig.UserAgent = APIUserAgent(opts.Component, controller, true)
+	statusConfig.QPS = opts.StatusQPS
+	statusConfig.Burst = opts.StatusBurst
+	statusConfig.RateLimiter = limiter
+
+	statusClient, err := client.New(statusConfig, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiClient{Client: specClient, status: statusClient}, nil
+}
+
+// apiClient sends the status writes with the status client, the status subresources and the SubscriptionStatus and
+// SubscriptionReport objects
+type apiClient struct {
+	client.Client
+	status client.Client
+}
+
+func (c *apiClient) Status() client.StatusWriter {
+	return c.status.Status()
+}
+
+func (c *apiClient) writer(obj client.Object) client.Writer {
+	switch obj.(type) {
+	case *appsubReportV1alpha1.SubscriptionStatus, *appsubReportV1alpha1.SubscriptionReport:
+		return c.status
+	}
+
+	return c.Client
+}
+
+func (c *apiClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	return c.writer(obj).Create(ctx, obj, opts...)
+}
+
+func (c *apiClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	return c.writer(obj).Update(ctx, obj, opts...)
+}
+
+func (c *apiClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return c.writer(obj).Patch(ctx, obj, patch, opts...)
+}
@@ -0,0 +1,81 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsubReportV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+)
+
+func TestAPIUserAgent(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	platform := " (" + runtime.GOOS + "/" + runtime.GOARCH + ")"
+
+	g.Expect(APIUserAgent("hub-subscription", "", false)).To(Equal("multicluster-operators-subscription/hub-subscription" + platform))
+	g.Expect(APIUserAgent("application-manager", "subscription-controller", true)).
+		To(Equal("multicluster-operators-subscription/application-manager/subscription-controller/status" + platform))
+
+	defer func() {
+		apiClientOptions, statusRateLimiter = nil, nil
+	}()
+
+	cfg := &rest.Config{}
+	ConfigureAPIClient(cfg, APIClientOptions{Component: "hub-subscription", QPS: 20, Burst: 40, StatusQPS: 5, StatusBurst: 10})
+
+	g.Expect(cfg.UserAgent).To(Equal(APIUserAgent("hub-subscription", "", false)))
+	g.Expect(cfg.RateLimiter).NotTo(BeNil())
+	g.Expect(cfg.RateLimiter.QPS()).To(BeNumerically("==", 20))
+	g.Expect(statusRateLimiter.QPS()).To(BeNumerically("==", 5))
+}
+
+func TestAPIClientStatusWrites(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	scheme := k8sruntime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(appsubReportV1alpha1.AddToScheme(scheme)).To(Succeed())
+
+	specClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	statusClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	c := &apiClient{Client: specClient, status: statusClient}
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default"}}
+	g.Expect(c.Create(context.TODO(), cm)).To(Succeed())
+
+	appsubStatus := &appsubReportV1alpha1.SubscriptionStatus{ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "default"}}
+	g.Expect(c.Create(context.TODO(), appsubStatus)).To(Succeed())
+
+	// the spec writes go through the spec client, the status writes through the status client
+	g.Expect(specClient.Get(context.TODO(), client.ObjectKeyFromObject(cm), &corev1.ConfigMap{})).To(Succeed())
+	g.Expect(statusClient.Get(context.TODO(), client.ObjectKeyFromObject(cm), &corev1.ConfigMap{})).NotTo(Succeed())
+	g.Expect(statusClient.Get(context.TODO(), client.ObjectKeyFromObject(appsubStatus),
+		&appsubReportV1alpha1.SubscriptionStatus{})).To(Succeed())
+	g.Expect(specClient.Get(context.TODO(), client.ObjectKeyFromObject(appsubStatus),
+		&appsubReportV1alpha1.SubscriptionStatus{})).NotTo(Succeed())
+}
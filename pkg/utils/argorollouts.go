@@ -0,0 +1,114 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+const (
+	// ArgoRolloutsCanaryKey is the key of the canary strategy in the Argo Rollouts ConfigMap
+	ArgoRolloutsCanaryKey = "canary.yaml"
+)
+
+// ArgoRolloutGVK is the kind of the Argo Rollouts the Deployments are converted to
+var ArgoRolloutGVK = schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "Rollout"}
+
+// rolloutSpecFields are the fields of the Deployment spec with the same meaning in the Rollout spec
+var rolloutSpecFields = []string{
+	"replicas", "selector", "template", "minReadySeconds", "revisionHistoryLimit", "paused", "progressDeadlineSeconds",
+}
+
+// ParseCanaryStrategy parses the canary strategy of the Rollouts, the spec.strategy.canary field of a Rollout
+func ParseCanaryStrategy(data string) (map[string]interface{}, error) {
+	canary := map[string]interface{}{}
+
+	if err := yaml.Unmarshal([]byte(data), &canary); err != nil {
+		return nil, fmt.Errorf("failed to parse the canary strategy, err: %w", err)
+	}
+
+	if len(canary) == 0 {
+		return nil, fmt.Errorf("the canary strategy is empty")
+	}
+
+	return canary, nil
+}
+
+// GetSubscriptionCanaryStrategy returns the canary strategy of the ConfigMap referred by the subscription annotation.
+// The ConfigMap sits in the subscription namespace of the cluster the resources are deployed to
+func GetSubscriptionCanaryStrategy(c client.Reader, sub *appv1.Subscription) (map[string]interface{}, error) {
+	cmName := sub.GetAnnotations()[appv1.AnnotationArgoRolloutsConfigMap]
+	if cmName == "" {
+		return nil, nil
+	}
+
+	key := types.NamespacedName{Name: cmName, Namespace: sub.GetNamespace()}
+	cm := &corev1.ConfigMap{}
+
+	if err := c.Get(context.TODO(), key, cm); err != nil {
+		return nil, fmt.Errorf("failed to get the argo rollouts configmap %v, err: %w", key.String(), err)
+	}
+
+	return ParseCanaryStrategy(cm.Data[ArgoRolloutsCanaryKey])
+}
+
+// ConvertDeploymentToRollout returns the Argo Rollout of the Deployment with the canary strategy. The metadata and the
+// pod template of the Deployment are kept, the maxSurge and maxUnavailable of its rolling update are used unless the
+// canary strategy sets them
+func ConvertDeploymentToRollout(deploy *unstructured.Unstructured, canary map[string]interface{}) *unstructured.Unstructured {
+	rollout := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	rollout.SetGroupVersionKind(ArgoRolloutGVK)
+	rollout.SetName(deploy.GetName())
+	rollout.SetNamespace(deploy.GetNamespace())
+	rollout.SetLabels(deploy.GetLabels())
+	rollout.SetAnnotations(deploy.GetAnnotations())
+	rollout.SetOwnerReferences(deploy.GetOwnerReferences())
+
+	spec := map[string]interface{}{}
+
+	for _, field := range rolloutSpecFields {
+		if v, found, _ := unstructured.NestedFieldCopy(deploy.Object, "spec", field); found {
+			spec[field] = v
+		}
+	}
+
+	strategy := runtime.DeepCopyJSON(canary)
+
+	for _, field := range []string{"maxSurge", "maxUnavailable"} {
+		if _, ok := strategy[field]; ok {
+			continue
+		}
+
+		if v, found, _ := unstructured.NestedFieldCopy(deploy.Object, "spec", "strategy", "rollingUpdate", field); found {
+			strategy[field] = v
+		}
+	}
+
+	spec["strategy"] = map[string]interface{}{"canary": strategy}
+	rollout.Object["spec"] = spec
+
+	return rollout
+}
@@ -0,0 +1,123 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+const testCanaryStrategy = `
+maxSurge: 1
+steps:
+- setWeight: 20
+- pause:
+    duration: 1m
+`
+
+func TestConvertDeploymentToRollout(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "canary", Namespace: "sub-ns"},
+		Data:       map[string]string{ArgoRolloutsCanaryKey: testCanaryStrategy},
+	}
+	c := fake.NewClientBuilder().WithObjects(cm).Build()
+
+	sub := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "sub", Namespace: "sub-ns"}}
+
+	canary, err := GetSubscriptionCanaryStrategy(c, sub)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(canary).To(BeNil())
+
+	sub.SetAnnotations(map[string]string{appv1.AnnotationArgoRolloutsConfigMap: "canary"})
+
+	canary, err = GetSubscriptionCanaryStrategy(c, sub)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(canary).To(HaveKey("steps"))
+
+	deploy := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name": "web", "namespace": "default", "labels": map[string]interface{}{"app": "web"},
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(3),
+			"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "web"}},
+			"strategy": map[string]interface{}{
+				"type":          "RollingUpdate",
+				"rollingUpdate": map[string]interface{}{"maxSurge": "25%", "maxUnavailable": int64(0)},
+			},
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{"labels": map[string]interface{}{"app": "web"}},
+			},
+		},
+	}}
+
+	rollout := ConvertDeploymentToRollout(deploy, canary)
+	g.Expect(rollout.GroupVersionKind()).To(Equal(ArgoRolloutGVK))
+	g.Expect(rollout.GetName()).To(Equal("web"))
+	g.Expect(rollout.GetNamespace()).To(Equal("default"))
+	g.Expect(rollout.GetLabels()).To(Equal(map[string]string{"app": "web"}))
+
+	replicas, _, _ := unstructured.NestedInt64(rollout.Object, "spec", "replicas")
+	g.Expect(replicas).To(Equal(int64(3)))
+
+	_, found, _ := unstructured.NestedMap(rollout.Object, "spec", "template")
+	g.Expect(found).To(BeTrue())
+
+	// the canary strategy wins over the rolling update of the Deployment
+	strategy, _, _ := unstructured.NestedMap(rollout.Object, "spec", "strategy", "canary")
+	g.Expect(strategy).To(HaveKeyWithValue("maxSurge", float64(1)))
+	g.Expect(strategy).To(HaveKeyWithValue("maxUnavailable", int64(0)))
+	g.Expect(strategy["steps"]).To(HaveLen(2))
+
+	// the canary strategy is not shared by the Rollouts
+	g.Expect(canary).NotTo(HaveKey("maxUnavailable"))
+
+	_, err = ParseCanaryStrategy("")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestArgoRolloutHealth(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	rollout := &unstructured.Unstructured{}
+	rollout.SetGroupVersionKind(ArgoRolloutGVK)
+
+	healthy, reason := isObjectHealthy(rollout)
+	g.Expect(healthy).To(BeFalse())
+	g.Expect(reason).To(Equal("the rollout is not observed yet"))
+
+	g.Expect(unstructured.SetNestedField(rollout.Object, "Paused", "status", "phase")).To(Succeed())
+	g.Expect(unstructured.SetNestedField(rollout.Object, "CanaryPauseStep", "status", "message")).To(Succeed())
+
+	healthy, reason = isObjectHealthy(rollout)
+	g.Expect(healthy).To(BeFalse())
+	g.Expect(reason).To(Equal("the rollout is Paused: CanaryPauseStep"))
+
+	g.Expect(unstructured.SetNestedField(rollout.Object, "Healthy", "status", "phase")).To(Succeed())
+
+	healthy, _ = isObjectHealthy(rollout)
+	g.Expect(healthy).To(BeTrue())
+}
@@ -17,10 +17,13 @@ package aws
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io/ioutil"
+	"net/http"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"k8s.io/klog/v2"
@@ -81,6 +84,8 @@ type DeployableObject struct {
 	GenerateName string
 	Version      string
 	Content      []byte
+	// ETag of the object, set by Get
+	ETag string
 }
 
 func (d DeployableObject) isEmpty() bool {
@@ -106,29 +111,70 @@ func isAwsS3ObjectBucket(endpoint string) bool {
 
 // InitObjectStoreConnection connect to object store.
 func (h *Handler) InitObjectStoreConnection(endpoint, accessKeyID, secretAccessKey, region string) error {
-	klog.Infof("Preparing S3 settings endpoint: %v", endpoint)
+	return h.InitObjectStoreConnectionWithOptions(endpoint, accessKeyID, secretAccessKey, region, ConnectionOptions{})
+}
+
+// InitObjectStoreConnectionWithOptions connect to object store with the addressing, region and TLS options of the channel.
+func (h *Handler) InitObjectStoreConnectionWithOptions(endpoint, accessKeyID, secretAccessKey, region string,
+	opts ConnectionOptions) error {
+	klog.Infof("Preparing S3 settings endpoint: %v, options: %+v", endpoint, opts)
+
+	awsS3 := isAwsS3ObjectBucket(endpoint)
 
 	// set the default object store region  as minio
 	objectRegion := "minio"
 
-	if isAwsS3ObjectBucket(endpoint) {
+	if awsS3 {
 		objectRegion = region
 	}
 
+	if opts.Region != "" {
+		objectRegion = opts.Region
+	}
+
+	// path-style by default for the S3 compatible stores, virtual-host by default for aws
+	pathStyle := !awsS3
+
+	switch strings.ToLower(opts.AddressingStyle) {
+	case "":
+	case AddressingStylePath:
+		pathStyle = true
+	case AddressingStyleVirtualHost:
+		pathStyle = false
+	default:
+		return fmt.Errorf("unsupported addressing style %v, it must be %v or %v", opts.AddressingStyle,
+			AddressingStylePath, AddressingStyleVirtualHost)
+	}
+
 	// aws s3 object store doesn't need to specify URL.
 	// minio object store needs immutable URL. The aws sdk is not allowed to modify the host name of the minio URL
 	customResolver := aws.EndpointResolverWithOptionsFunc(func(service, region string, options ...interface{}) (aws.Endpoint, error) {
 		klog.V(1).Infof("service: %v, region: %v", service, region)
-		if region == "minio" {
+		if !awsS3 {
 			return aws.Endpoint{
 				URL:               endpoint,
-				HostnameImmutable: true,
+				SigningRegion:     objectRegion,
+				HostnameImmutable: pathStyle,
 			}, nil
 		}
 		return aws.Endpoint{}, &aws.EndpointNotFoundError{}
 	})
 
-	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithEndpointResolverWithOptions(customResolver))
+	loadOptions := []func(*config.LoadOptions) error{config.WithEndpointResolverWithOptions(customResolver)}
+
+	if opts.InsecureSkipVerify || opts.CACerts != "" {
+		tlsConfig, err := opts.tlsConfig()
+		if err != nil {
+			return err
+		}
+
+		loadOptions = append(loadOptions, config.WithHTTPClient(awshttp.NewBuildableClient().WithTransportOptions(
+			func(tr *http.Transport) {
+				tr.TLSClientConfig = tlsConfig
+			})))
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.TODO(), loadOptions...)
 	if err != nil {
 		klog.Error("Failed to load aws config. error: ", err)
 
@@ -145,6 +191,7 @@ func (h *Handler) InitObjectStoreConnection(endpoint, accessKeyID, secretAccessK
 	h.Client = s3.NewFromConfig(cfg, func(o *s3.Options) {
 		o.Region = objectRegion
 		o.Credentials = objCredential
+		o.UsePathStyle = pathStyle
 	})
 
 	if h.Client == nil {
@@ -275,6 +322,10 @@ func (h *Handler) Get(bucket, name string) (DeployableObject, error) {
 	dplObj.Content = body
 	dplObj.Version = version
 
+	if resp.ETag != nil {
+		dplObj.ETag = *resp.ETag
+	}
+
 	klog.V(1).Info("Get Success: \n", string(body))
 
 	return dplObj, nil
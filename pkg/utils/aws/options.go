@@ -0,0 +1,108 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils/tlsconfig"
+)
+
+const (
+	// AddressingStylePath addresses the bucket in the URL path, https://endpoint/bucket/key
+	AddressingStylePath = "path"
+	// AddressingStyleVirtualHost addresses the bucket in the host name, https://bucket.endpoint/key
+	AddressingStyleVirtualHost = "virtual-host"
+
+	// ConfigMapKeyAddressingStyle is key of the addressing style in the channel configmap.
+	ConfigMapKeyAddressingStyle = "addressingStyle"
+	// ConfigMapKeyRegion is key of the region in the channel configmap, it overrides the region of the secret.
+	ConfigMapKeyRegion = "region"
+	// ConfigMapKeyInsecureSkipVerify is key of insecureSkipVerify in the channel configmap.
+	ConfigMapKeyInsecureSkipVerify = "insecureSkipVerify"
+	// ConfigMapKeyCACerts is key of the PEM CA certificates of the endpoint in the channel configmap.
+	ConfigMapKeyCACerts = "caCerts"
+)
+
+// ConnectionOptions are the connection settings of the S3 compatible stores.
+type ConnectionOptions struct {
+	// AddressingStyle is path or virtual-host. The default is path for the custom endpoints and virtual-host for aws.
+	AddressingStyle string
+	// Region is the signing region, the default is the secret region for aws and minio for the custom endpoints.
+	Region string
+	// InsecureSkipVerify skips the verification of the endpoint certificate.
+	InsecureSkipVerify bool
+	// CACerts are the PEM CA certificates to verify the endpoint certificate.
+	CACerts string
+}
+
+// ConnectionOptionsFromConfigMap returns the connection options set in the channel configmap, insecureSkipVerify
+// is the channel spec setting and can't be turned off by the configmap.
+func ConnectionOptionsFromConfigMap(cm *corev1.ConfigMap, insecureSkipVerify bool) (ConnectionOptions, error) {
+	opts := ConnectionOptions{InsecureSkipVerify: insecureSkipVerify}
+
+	if cm == nil {
+		return opts, nil
+	}
+
+	opts.AddressingStyle = strings.TrimSpace(cm.Data[ConfigMapKeyAddressingStyle])
+	opts.Region = strings.TrimSpace(cm.Data[ConfigMapKeyRegion])
+	opts.CACerts = cm.Data[ConfigMapKeyCACerts]
+
+	switch strings.ToLower(opts.AddressingStyle) {
+	case "", AddressingStylePath, AddressingStyleVirtualHost:
+	default:
+		return opts, fmt.Errorf("unsupported %v %v in configmap %v, it must be %v or %v", ConfigMapKeyAddressingStyle,
+			opts.AddressingStyle, cm.Name, AddressingStylePath, AddressingStyleVirtualHost)
+	}
+
+	if v := cm.Data[ConfigMapKeyInsecureSkipVerify]; v != "" && !opts.InsecureSkipVerify {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return opts, fmt.Errorf("failed to parse %v in configmap %v: %w", ConfigMapKeyInsecureSkipVerify, cm.Name, err)
+		}
+
+		opts.InsecureSkipVerify = b
+	}
+
+	return opts, nil
+}
+
+func (o ConnectionOptions) tlsConfig() (*tls.Config, error) {
+	tlsConfig := tlsconfig.NewConfig(tls.VersionTLS12)
+	tlsConfig.InsecureSkipVerify = o.InsecureSkipVerify // #nosec G402 InsecureSkipVerify optionally
+
+	if o.CACerts != "" && !o.InsecureSkipVerify {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM([]byte(o.CACerts)) {
+			return nil, errors.New("failed to parse the CA certificates of the object store")
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
+}
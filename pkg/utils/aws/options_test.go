@@ -0,0 +1,93 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"encoding/pem"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/johannesboyne/gofakes3"
+	"github.com/johannesboyne/gofakes3/backend/s3mem"
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConnectionOptionsFromConfigMap(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	opts, err := ConnectionOptionsFromConfigMap(nil, true)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(opts).To(gomega.Equal(ConnectionOptions{InsecureSkipVerify: true}))
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "ceph-config"},
+		Data: map[string]string{
+			ConfigMapKeyAddressingStyle:    "virtual-host",
+			ConfigMapKeyRegion:             "default",
+			ConfigMapKeyInsecureSkipVerify: "true",
+		},
+	}
+
+	opts, err = ConnectionOptionsFromConfigMap(cm, false)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(opts).To(gomega.Equal(ConnectionOptions{AddressingStyle: AddressingStyleVirtualHost, Region: "default", InsecureSkipVerify: true}))
+
+	cm.Data[ConfigMapKeyInsecureSkipVerify] = "maybe"
+	_, err = ConnectionOptionsFromConfigMap(cm, false)
+	g.Expect(err).To(gomega.HaveOccurred())
+
+	cm.Data[ConfigMapKeyInsecureSkipVerify] = "false"
+	cm.Data[ConfigMapKeyAddressingStyle] = "subdomain"
+	_, err = ConnectionOptionsFromConfigMap(cm, false)
+	g.Expect(err).To(gomega.HaveOccurred())
+}
+
+func TestObjectstoreConnectionOptions(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Set up a fake S3 server with TLS on a random port
+	ts := httptest.NewTLSServer(gofakes3.New(s3mem.New()).Server())
+
+	defer ts.Close()
+
+	// the endpoint certificate can't be verified without the CA
+	awshandler := &Handler{}
+	err := awshandler.InitObjectStoreConnectionWithOptions(ts.URL, "randomid", "randomkey", "", ConnectionOptions{
+		AddressingStyle: AddressingStylePath,
+		Region:          "us-east-1",
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(awshandler.Create("tls-bucket")).NotTo(gomega.Succeed())
+
+	caCerts := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw}))
+
+	err = awshandler.InitObjectStoreConnectionWithOptions(ts.URL, "randomid", "randomkey", "", ConnectionOptions{
+		AddressingStyle: AddressingStylePath,
+		Region:          "us-east-1",
+		CACerts:         caCerts,
+	})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(awshandler.Create("tls-bucket")).To(gomega.Succeed())
+	g.Expect(awshandler.Exists("tls-bucket")).To(gomega.Succeed())
+
+	err = awshandler.InitObjectStoreConnectionWithOptions(ts.URL, "randomid", "randomkey", "", ConnectionOptions{InsecureSkipVerify: true})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(awshandler.Exists("tls-bucket")).To(gomega.Succeed())
+
+	err = awshandler.InitObjectStoreConnectionWithOptions(ts.URL, "randomid", "randomkey", "", ConnectionOptions{AddressingStyle: "subdomain"})
+	g.Expect(err).To(gomega.HaveOccurred())
+}
@@ -0,0 +1,271 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/ghodss/yaml"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+// maxBucketArchiveSize is the maximum expanded size of a bucket archive, to protect the subscriber from archive bombs
+const maxBucketArchiveSize = 100 * 1024 * 1024
+
+// archiveFile is a regular file of an archive
+type archiveFile struct {
+	name string
+	data []byte
+}
+
+// IsBucketArchive checks if the bucket object is a .tar.gz, .tgz or .zip archive
+func IsBucketArchive(key string) bool {
+	key = strings.ToLower(key)
+
+	return strings.HasSuffix(key, ".tar.gz") || strings.HasSuffix(key, ".tgz") || strings.HasSuffix(key, ".zip")
+}
+
+// ExpandBucketArchive returns the resources of a bucket archive. An archive of manifests returns all the resources of
+// its YAML files. An archive of a Helm chart, with a Chart.yaml at its root or in its top folder, is rendered in the
+// subscription namespace with the chart values overridden by the "spec" package override of the chart, the same way as
+// the HelmRelease of a helm repo chart, and the chart name is returned along with the rendered resources
+func ExpandBucketArchive(sub *appv1.Subscription, key string, content []byte) ([]*unstructured.Unstructured, string, error) {
+	files, err := readArchive(key, content)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to expand archive %v: %w", key, err)
+	}
+
+	if root, ok := chartRoot(files); ok {
+		templates, chartName, err := renderArchiveChart(sub, files, root)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to render the chart of archive %v: %w", key, err)
+		}
+
+		return templates, chartName, nil
+	}
+
+	templates := []*unstructured.Unstructured{}
+
+	for _, file := range files {
+		ext := strings.ToLower(path.Ext(file.name))
+		if ext != ".yaml" && ext != ".yml" {
+			klog.V(1).Infof("skip file %v of archive %v, not a YAML file", file.name, key)
+
+			continue
+		}
+
+		for _, item := range ParseKubeResoures(file.data) {
+			template := &unstructured.Unstructured{}
+			if err := yaml.Unmarshal(item, template); err != nil {
+				return nil, "", fmt.Errorf("failed to parse file %v of archive %v: %w", file.name, key, err)
+			}
+
+			templates = append(templates, template)
+		}
+	}
+
+	return templates, "", nil
+}
+
+// readArchive returns the regular files of the archive sorted by name, skipping the hidden files and folders
+func readArchive(key string, content []byte) ([]archiveFile, error) {
+	var (
+		files []archiveFile
+		err   error
+	)
+
+	if strings.HasSuffix(strings.ToLower(key), ".zip") {
+		files, err = readZip(content)
+	} else {
+		files, err = readTarGz(content)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].name < files[j].name })
+
+	return files, nil
+}
+
+func readTarGz(content []byte) ([]archiveFile, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+
+	defer gz.Close()
+
+	files := []archiveFile{}
+	size := int64(0)
+	tr := tar.NewReader(gz)
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if hdr.Typeflag != tar.TypeReg || isHiddenArchivePath(hdr.Name) {
+			continue
+		}
+
+		size += hdr.Size
+		if size > maxBucketArchiveSize {
+			return nil, fmt.Errorf("the expanded archive exceeds %v bytes", maxBucketArchiveSize)
+		}
+
+		data, err := io.ReadAll(io.LimitReader(tr, hdr.Size))
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, archiveFile{name: cleanArchivePath(hdr.Name), data: data})
+	}
+
+	return files, nil
+}
+
+func readZip(content []byte) ([]archiveFile, error) {
+	zr, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, err
+	}
+
+	files := []archiveFile{}
+	size := int64(0)
+
+	for _, zf := range zr.File {
+		if !zf.Mode().IsRegular() || isHiddenArchivePath(zf.Name) {
+			continue
+		}
+
+		rc, err := zf.Open()
+		if err != nil {
+			return nil, err
+		}
+
+		// the declared size can't be trusted, the reads are limited to the remaining budget
+		data, err := io.ReadAll(io.LimitReader(rc, maxBucketArchiveSize-size+1))
+		rc.Close()
+
+		if err != nil {
+			return nil, err
+		}
+
+		size += int64(len(data))
+		if size > maxBucketArchiveSize {
+			return nil, fmt.Errorf("the expanded archive exceeds %v bytes", maxBucketArchiveSize)
+		}
+
+		files = append(files, archiveFile{name: cleanArchivePath(zf.Name), data: data})
+	}
+
+	return files, nil
+}
+
+func cleanArchivePath(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+func isHiddenArchivePath(name string) bool {
+	for _, part := range strings.Split(cleanArchivePath(name), "/") {
+		if strings.HasPrefix(part, ".") || part == "__MACOSX" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// chartRoot returns the folder of the chart in the archive, it is empty if the Chart.yaml is at the root
+func chartRoot(files []archiveFile) (string, bool) {
+	for _, file := range files {
+		if file.name == "Chart.yaml" {
+			return "", true
+		}
+	}
+
+	for _, file := range files {
+		dir, name := path.Split(file.name)
+		if name == "Chart.yaml" && strings.Count(dir, "/") == 1 {
+			return dir, true
+		}
+	}
+
+	return "", false
+}
+
+func renderArchiveChart(sub *appv1.Subscription, files []archiveFile, root string) ([]*unstructured.Unstructured, string, error) {
+	bufferedFiles := []*loader.BufferedFile{}
+
+	for _, file := range files {
+		if !strings.HasPrefix(file.name, root) {
+			continue
+		}
+
+		bufferedFiles = append(bufferedFiles, &loader.BufferedFile{Name: strings.TrimPrefix(file.name, root), Data: file.data})
+	}
+
+	chrt, err := loader.LoadFiles(bufferedFiles)
+	if err != nil {
+		return nil, "", err
+	}
+
+	chartName := chrt.Name()
+
+	if sub.Spec.PackageFilter != nil && sub.Spec.PackageFilter.Version != "" {
+		constraint, err := semver.NewConstraint(sub.Spec.PackageFilter.Version)
+		if err != nil {
+			return nil, "", err
+		}
+
+		version, err := semver.NewVersion(chrt.Metadata.Version)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if !constraint.Check(version) {
+			return nil, "", fmt.Errorf("chart %v version %v doesn't match the package filter version %v", chartName,
+				chrt.Metadata.Version, sub.Spec.PackageFilter.Version)
+		}
+	}
+
+	templates, err := RenderSubscriptionChart(sub, chrt, sub.Namespace)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return templates, chartName, nil
+}
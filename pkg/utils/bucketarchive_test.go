@@ -0,0 +1,160 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+const archiveConfigMaps = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm2
+`
+
+func buildTarGz(g *WithT, files map[string]string) []byte {
+	var buf bytes.Buffer
+
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	for name, content := range files {
+		g.Expect(tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})).To(Succeed())
+		_, err := tw.Write([]byte(content))
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+
+	g.Expect(tw.Close()).To(Succeed())
+	g.Expect(gz.Close()).To(Succeed())
+
+	return buf.Bytes()
+}
+
+func buildZip(g *WithT, files map[string]string) []byte {
+	var buf bytes.Buffer
+
+	zw := zip.NewWriter(&buf)
+
+	for name, content := range files {
+		w, err := zw.Create(name)
+		g.Expect(err).NotTo(HaveOccurred())
+		_, err = w.Write([]byte(content))
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+
+	g.Expect(zw.Close()).To(Succeed())
+
+	return buf.Bytes()
+}
+
+func TestExpandBucketArchiveManifests(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sub := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns"}}
+
+	files := map[string]string{
+		"app/configmaps.yaml":  archiveConfigMaps,
+		"app/secret.yml":       "apiVersion: v1\nkind: Secret\nmetadata:\n  name: s1\n",
+		"app/README.md":        "# not a manifest",
+		"app/.hidden/cm.yaml":  "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: hidden\n",
+		"__MACOSX/app/cm.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: macos\n",
+	}
+
+	g.Expect(IsBucketArchive("apps/app.tar.gz")).To(BeTrue())
+	g.Expect(IsBucketArchive("apps/app.ZIP")).To(BeTrue())
+	g.Expect(IsBucketArchive("apps/app.yaml")).To(BeFalse())
+
+	for key, content := range map[string][]byte{"app.tgz": buildTarGz(g, files), "app.zip": buildZip(g, files)} {
+		templates, chartName, err := ExpandBucketArchive(sub, key, content)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(chartName).To(BeEmpty())
+		g.Expect(templates).To(HaveLen(3))
+		g.Expect(templates[0].GetName()).To(Equal("cm1"))
+		g.Expect(templates[1].GetName()).To(Equal("cm2"))
+		g.Expect(templates[2].GetKind()).To(Equal("Secret"))
+	}
+
+	_, _, err := ExpandBucketArchive(sub, "app.tgz", []byte("not an archive"))
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestExpandBucketArchiveChart(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	files := map[string]string{
+		"nginx/Chart.yaml":  "apiVersion: v2\nname: nginx\nversion: 1.2.0\n",
+		"nginx/values.yaml": "replicas: 1\nmessage: default\n",
+		"nginx/templates/cm.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}-config
+  namespace: {{ .Release.Namespace }}
+data:
+  message: {{ .Values.message }}
+  replicas: "{{ .Values.replicas }}"
+`,
+		"nginx/templates/hook.yaml": `apiVersion: v1
+kind: Pod
+metadata:
+  name: test
+  annotations:
+    helm.sh/hook: test
+`,
+		"nginx/templates/NOTES.txt": "installed {{ .Release.Name }}",
+	}
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns"},
+		Spec: appv1.SubscriptionSpec{
+			PackageOverrides: []*appv1.Overrides{
+				{
+					PackageName:  "nginx",
+					PackageAlias: "web",
+					PackageOverrides: []appv1.PackageOverride{
+						{RawExtension: runtime.RawExtension{Raw: []byte(`{"path": "spec", "value": {"message": "overridden"}}`)}},
+					},
+				},
+			},
+		},
+	}
+
+	templates, chartName, err := ExpandBucketArchive(sub, "nginx-1.2.0.tgz", buildTarGz(g, files))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(chartName).To(Equal("nginx"))
+	g.Expect(templates).To(HaveLen(1))
+	g.Expect(templates[0].GetName()).To(Equal("web-config"))
+	g.Expect(templates[0].GetNamespace()).To(Equal("demo-ns"))
+	g.Expect(templates[0].Object["data"]).To(Equal(map[string]interface{}{"message": "overridden", "replicas": "1"}))
+
+	sub.Spec.PackageFilter = &appv1.PackageFilter{Version: ">=2.0.0"}
+
+	_, _, err = ExpandBucketArchive(sub, "nginx-1.2.0.tgz", buildTarGz(g, files))
+	g.Expect(err).To(HaveOccurred())
+}
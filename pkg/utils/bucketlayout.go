@@ -0,0 +1,296 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/ghodss/yaml"
+	"k8s.io/klog/v2"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+const (
+	// BucketLayoutFlat deploys all the objects under the bucket path, the resource names are the package names
+	BucketLayoutFlat = "flat"
+	// BucketLayoutFolders deploys a folder per package, with optional version sub folders
+	BucketLayoutFolders = "folders"
+	// BucketIndexFileName is the optional index of the packages at the root of the bucket path in the folders layout
+	BucketIndexFileName = "index.yaml"
+)
+
+// BucketIndex lists the packages of a bucket with the folders layout
+type BucketIndex struct {
+	Packages []BucketIndexPackage `json:"packages"`
+}
+
+// BucketIndexPackage is a package of the bucket index
+type BucketIndexPackage struct {
+	Name string `json:"name"`
+	// the folder of the package relative to the bucket path, the package name by default
+	Path     string               `json:"path,omitempty"`
+	Versions []BucketIndexVersion `json:"versions,omitempty"`
+}
+
+// BucketIndexVersion is a version of a package in the bucket index
+type BucketIndexVersion struct {
+	Version string `json:"version"`
+	// the folder of the version relative to the bucket path, <package path>/<version> by default
+	Path string `json:"path,omitempty"`
+}
+
+// BucketObject is an object of the bucket selected for the subscription
+type BucketObject struct {
+	Key string
+	// the package of the object, it is empty in the flat layout where the resource name is the package name
+	Package string
+}
+
+// SelectBucketObjects selects the objects to deploy among the keys listed under the bucket path. The flat layout
+// selects all the objects. The folders layout takes the first level folders as the packages, checked against the
+// package name and filter, and picks the latest version sub folder matching the packageFilter version along with the
+// package objects outside of the version folders. The packages
+// and versions are read from the index.yaml instead of the folders when it exists. In both layouts the objects must
+// match the package filter paths
+func SelectBucketObjects(sub *appv1.Subscription, keys []string, bucketPath string,
+	getObject func(key string) ([]byte, error)) ([]BucketObject, error) {
+	layout := sub.GetAnnotations()[appv1.AnnotationBucketLayout]
+
+	objects := []BucketObject{}
+
+	if layout == "" || strings.EqualFold(layout, BucketLayoutFlat) {
+		for _, key := range keys {
+			if !MatchPackagePath(sub.Spec.PackageFilter, bucketRelPath(key, bucketPath)) {
+				klog.V(1).Infof("skip object %v not matching the package filter paths", key)
+
+				continue
+			}
+
+			objects = append(objects, BucketObject{Key: key})
+		}
+
+		return objects, nil
+	}
+
+	if !strings.EqualFold(layout, BucketLayoutFolders) {
+		return nil, fmt.Errorf("unsupported bucket layout %v", layout)
+	}
+
+	index, err := getBucketIndex(keys, bucketPath, getObject)
+	if err != nil {
+		return nil, err
+	}
+
+	if index == nil {
+		index = buildBucketIndex(keys, bucketPath)
+	}
+
+	for _, pkg := range index.Packages {
+		if (sub.Spec.Package != "" && sub.Spec.Package != pkg.Name) || !MatchPackageNameRegex(sub.Spec.PackageFilter, pkg.Name) {
+			klog.V(1).Infof("skip package %v not matching the package name or name regex", pkg.Name)
+
+			continue
+		}
+
+		folder, err := selectPackageFolder(sub, pkg)
+		if err != nil {
+			klog.Infof("skip package %v, %v", pkg.Name, err)
+
+			continue
+		}
+
+		for _, key := range keys {
+			rel := bucketRelPath(key, bucketPath)
+
+			if rel == BucketIndexFileName {
+				continue
+			}
+
+			// the objects of the selected version, and the objects of the package outside of the version folders
+			if !MatchPathGlob(folder, rel) && (!MatchPathGlob(packagePath(pkg), rel) || isInVersionFolder(pkg, rel)) {
+				continue
+			}
+
+			if !MatchPackagePath(sub.Spec.PackageFilter, rel) {
+				klog.V(1).Infof("skip object %v not matching the package filter paths", key)
+
+				continue
+			}
+
+			objects = append(objects, BucketObject{Key: key, Package: pkg.Name})
+		}
+	}
+
+	return objects, nil
+}
+
+func bucketRelPath(key, bucketPath string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(key, bucketPath), "/")
+}
+
+func getBucketIndex(keys []string, bucketPath string, getObject func(key string) ([]byte, error)) (*BucketIndex, error) {
+	for _, key := range keys {
+		if bucketRelPath(key, bucketPath) != BucketIndexFileName {
+			continue
+		}
+
+		content, err := getObject(key)
+		if err != nil {
+			return nil, err
+		}
+
+		index := &BucketIndex{}
+		if err := yaml.Unmarshal(content, index); err != nil {
+			return nil, fmt.Errorf("failed to parse the bucket index %v: %w", key, err)
+		}
+
+		return index, nil
+	}
+
+	return nil, nil
+}
+
+// buildBucketIndex builds the index from the folders, a second level folder is a version if it is a semantic version
+func buildBucketIndex(keys []string, bucketPath string) *BucketIndex {
+	index := &BucketIndex{}
+	packages := map[string]int{}
+
+	for _, key := range keys {
+		parts := strings.Split(bucketRelPath(key, bucketPath), "/")
+		if len(parts) < 2 {
+			// the objects at the root don't belong to a package
+			continue
+		}
+
+		i, ok := packages[parts[0]]
+		if !ok {
+			index.Packages = append(index.Packages, BucketIndexPackage{Name: parts[0]})
+			i = len(index.Packages) - 1
+			packages[parts[0]] = i
+		}
+
+		pkg := &index.Packages[i]
+
+		if len(parts) < 3 {
+			continue
+		}
+
+		if _, err := semver.NewVersion(parts[1]); err != nil {
+			continue
+		}
+
+		found := false
+
+		for _, v := range pkg.Versions {
+			if v.Version == parts[1] {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			pkg.Versions = append(pkg.Versions, BucketIndexVersion{Version: parts[1]})
+		}
+	}
+
+	return index
+}
+
+// selectPackageFolder returns the folder of the latest package version matching the packageFilter version
+func selectPackageFolder(sub *appv1.Subscription, pkg BucketIndexPackage) (string, error) {
+	pkgPath := packagePath(pkg)
+
+	var constraint *semver.Constraints
+
+	if sub.Spec.PackageFilter != nil && sub.Spec.PackageFilter.Version != "" {
+		var err error
+
+		constraint, err = semver.NewConstraint(sub.Spec.PackageFilter.Version)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if len(pkg.Versions) == 0 {
+		if constraint != nil {
+			return "", fmt.Errorf("no version matches %v", sub.Spec.PackageFilter.Version)
+		}
+
+		return pkgPath, nil
+	}
+
+	var latest *semver.Version
+
+	latestPath := ""
+
+	for _, v := range pkg.Versions {
+		ver, err := semver.NewVersion(v.Version)
+		if err != nil {
+			klog.Warningf("skip invalid version %v of package %v, err: %v", v.Version, pkg.Name, err)
+
+			continue
+		}
+
+		if constraint != nil && !constraint.Check(ver) {
+			continue
+		}
+
+		if latest == nil || ver.GreaterThan(latest) {
+			latest = ver
+			latestPath = v.Path
+
+			if latestPath == "" {
+				latestPath = path.Join(pkgPath, v.Version)
+			}
+		}
+	}
+
+	if latest == nil {
+		return "", fmt.Errorf("no version matches %v", sub.Spec.PackageFilter.Version)
+	}
+
+	klog.V(1).Infof("selected version %v of package %v", latest.Original(), pkg.Name)
+
+	return latestPath, nil
+}
+
+func packagePath(pkg BucketIndexPackage) string {
+	if pkg.Path != "" {
+		return pkg.Path
+	}
+
+	return pkg.Name
+}
+
+// isInVersionFolder checks if the object belongs to one of the versions of the package
+func isInVersionFolder(pkg BucketIndexPackage, rel string) bool {
+	for _, v := range pkg.Versions {
+		versionPath := v.Path
+		if versionPath == "" {
+			versionPath = path.Join(packagePath(pkg), v.Version)
+		}
+
+		if MatchPathGlob(versionPath, rel) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,143 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func selectedKeys(objects []BucketObject) map[string]string {
+	keys := map[string]string{}
+
+	for _, obj := range objects {
+		keys[obj.Key] = obj.Package
+	}
+
+	return keys
+}
+
+func TestSelectBucketObjects(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	keys := []string{
+		"apps/readme.yaml",
+		"apps/frontend/1.0.0/deploy.yaml",
+		"apps/frontend/1.1.0/deploy.yaml",
+		"apps/frontend/2.0.0/deploy.yaml",
+		"apps/frontend/common/cm.yaml",
+		"apps/backend/deploy.yaml",
+	}
+
+	noObject := func(key string) ([]byte, error) { return nil, errors.New("unexpected get " + key) }
+
+	sub := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns"}}
+
+	// flat layout, all the objects
+	objects, err := SelectBucketObjects(sub, keys, "apps", noObject)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(objects).To(HaveLen(len(keys)))
+
+	// folders layout, latest version of each package
+	sub.SetAnnotations(map[string]string{appv1.AnnotationBucketLayout: BucketLayoutFolders})
+
+	objects, err = SelectBucketObjects(sub, keys, "apps", noObject)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(selectedKeys(objects)).To(Equal(map[string]string{
+		"apps/frontend/2.0.0/deploy.yaml": "frontend",
+		"apps/frontend/common/cm.yaml":    "frontend",
+		"apps/backend/deploy.yaml":        "backend",
+	}))
+
+	// version selection with the package filter, the packages without versions don't match
+	sub.Spec.PackageFilter = &appv1.PackageFilter{Version: "<2.0.0"}
+
+	objects, err = SelectBucketObjects(sub, keys, "apps", noObject)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(selectedKeys(objects)).To(Equal(map[string]string{
+		"apps/frontend/1.1.0/deploy.yaml": "frontend",
+		"apps/frontend/common/cm.yaml":    "frontend",
+	}))
+
+	// package name
+	sub.Spec.PackageFilter = nil
+	sub.Spec.Package = "backend"
+
+	objects, err = SelectBucketObjects(sub, keys, "apps", noObject)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(selectedKeys(objects)).To(Equal(map[string]string{"apps/backend/deploy.yaml": "backend"}))
+}
+
+func TestSelectBucketObjectsIndex(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	keys := []string{
+		"apps/index.yaml",
+		"apps/releases/frontend-1.0/deploy.yaml",
+		"apps/releases/frontend-1.1/deploy.yaml",
+		"apps/db/statefulset.yaml",
+	}
+
+	index := `packages:
+- name: frontend
+  versions:
+  - version: 1.0.0
+    path: releases/frontend-1.0
+  - version: 1.1.0
+    path: releases/frontend-1.1
+- name: database
+  path: db
+`
+
+	getObject := func(key string) ([]byte, error) {
+		if key == "apps/index.yaml" {
+			return []byte(index), nil
+		}
+
+		return nil, errors.New("unexpected get " + key)
+	}
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "demo",
+			Namespace:   "demo-ns",
+			Annotations: map[string]string{appv1.AnnotationBucketLayout: BucketLayoutFolders},
+		},
+		Spec: appv1.SubscriptionSpec{PackageFilter: &appv1.PackageFilter{Version: "~1.0.0"}},
+	}
+
+	objects, err := SelectBucketObjects(sub, keys, "apps", getObject)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(selectedKeys(objects)).To(Equal(map[string]string{"apps/releases/frontend-1.0/deploy.yaml": "frontend"}))
+
+	sub.Spec.PackageFilter = nil
+
+	objects, err = SelectBucketObjects(sub, keys, "apps", getObject)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(selectedKeys(objects)).To(Equal(map[string]string{
+		"apps/releases/frontend-1.1/deploy.yaml": "frontend",
+		"apps/db/statefulset.yaml":               "database",
+	}))
+
+	sub.SetAnnotations(map[string]string{appv1.AnnotationBucketLayout: "tree"})
+
+	_, err = SelectBucketObjects(sub, keys, "apps", getObject)
+	g.Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,336 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"helm.sh/helm/v3/pkg/repo"
+	"k8s.io/klog/v2"
+	"oras.land/oras-go/pkg/content"
+	"oras.land/oras-go/pkg/oras"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+const (
+	// ChannelTypeBundle is the channel type of the offline bundles. The channel pathname is either a local directory,
+	// e.g. file:///bundles/app mounted from a PVC, or an OCI artifact in an in-cluster registry, e.g. oci://registry/app:v1
+	ChannelTypeBundle = "bundle"
+	// BundleIndexFile is the helm repo index file of the packaged charts in the bundle, the chart URLs are relative to the bundle root
+	BundleIndexFile = "index.yaml"
+	// BundleLayerMediaType is the media type of the bundle layer in the OCI artifact, a tar.gz of the bundle directory
+	BundleLayerMediaType = "application/vnd.open-cluster-management.bundle.layer.v1.tar+gzip"
+
+	bundleFileScheme = "file://"
+	bundleOCIScheme  = "oci://"
+	// maxBundleFileSize guards against decompression bombs in the bundle layers
+	maxBundleFileSize = 1 << 30
+)
+
+// BundleFetchOption defines how to fetch an offline bundle
+type BundleFetchOption struct {
+	Pathname string
+	User     string
+	Password string
+	Insecure bool
+	// DestDir is where the OCI artifact is extracted, local bundles are read in place
+	DestDir string
+}
+
+// IsBundleChannel checks if the channel type is an offline bundle
+func IsBundleChannel(chType string) bool {
+	return strings.EqualFold(chType, ChannelTypeBundle)
+}
+
+// ParseBundlePathname returns either the local directory or the OCI reference of the bundle channel pathname
+func ParseBundlePathname(pathname string) (localDir, ociRef string, err error) {
+	switch {
+	case strings.HasPrefix(pathname, bundleFileScheme):
+		localDir = strings.TrimPrefix(pathname, bundleFileScheme)
+	case strings.HasPrefix(pathname, bundleOCIScheme):
+		ociRef = strings.TrimPrefix(pathname, bundleOCIScheme)
+	case strings.HasPrefix(pathname, "/"):
+		localDir = pathname
+	default:
+		return "", "", fmt.Errorf("invalid bundle pathname %v, expecting file://<dir> or oci://<reference>", pathname)
+	}
+
+	if localDir == "" && ociRef == "" {
+		return "", "", fmt.Errorf("invalid bundle pathname %v, empty location", pathname)
+	}
+
+	if localDir != "" {
+		if !filepath.IsAbs(localDir) {
+			return "", "", fmt.Errorf("invalid bundle pathname %v, the local directory must be an absolute path", pathname)
+		}
+
+		localDir = filepath.Clean(localDir)
+	}
+
+	return localDir, ociRef, nil
+}
+
+// FetchBundle makes the bundle available on the local file system. It returns the bundle root directory and
+// its revision, the index file digest for local bundles or the manifest digest for OCI artifacts.
+func FetchBundle(opt *BundleFetchOption) (root, revision string, err error) {
+	localDir, ociRef, err := ParseBundlePathname(opt.Pathname)
+	if err != nil {
+		return "", "", err
+	}
+
+	if localDir != "" {
+		info, err := os.Stat(localDir)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read the bundle directory %v, err: %w", localDir, err)
+		}
+
+		if !info.IsDir() {
+			return "", "", fmt.Errorf("bundle %v is not a directory", localDir)
+		}
+
+		revision, err := localBundleRevision(localDir)
+		if err != nil {
+			return "", "", err
+		}
+
+		return localDir, revision, nil
+	}
+
+	return pullBundle(ociRef, opt)
+}
+
+// localBundleRevision hashes the path and the content of all the files of the bundle, so a manifest or a chart changed
+// without updating the index still changes the revision
+func localBundleRevision(root string) (string, error) {
+	h := sha256.New()
+
+	// filepath.Walk visits the files in lexical order, the revision doesn't depend on the directory listing order
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(path) // #nosec G304 the bundle root is owned by the cluster admin
+		if err != nil {
+			return err
+		}
+
+		defer f.Close()
+
+		fmt.Fprintf(h, "%s|%d\n", filepath.ToSlash(strings.TrimPrefix(path, root)), info.Size())
+
+		_, err = io.Copy(h, f)
+
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to read the bundle directory %v, err: %w", root, err)
+	}
+
+	return fmt.Sprintf("sha256:%x", h.Sum(nil)), nil
+}
+
+func pullBundle(ref string, opt *BundleFetchOption) (string, string, error) {
+	if opt.DestDir == "" {
+		return "", "", errors.New("the destination directory of the bundle is not set")
+	}
+
+	registry, err := content.NewRegistry(content.RegistryOptions{
+		Username: opt.User,
+		Password: opt.Password,
+		Insecure: opt.Insecure,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to connect to the registry of bundle %v, err: %w", ref, err)
+	}
+
+	store := content.NewMemory()
+
+	var layers []ocispec.Descriptor
+
+	manifest, err := oras.Copy(context.TODO(), registry, ref, store, "",
+		oras.WithPullEmptyNameAllowed(),
+		oras.WithAllowedMediaTypes([]string{BundleLayerMediaType, ocispec.MediaTypeImageLayerGzip}),
+		oras.WithLayerDescriptors(func(l []ocispec.Descriptor) {
+			layers = l
+		}))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to pull bundle %v, err: %w", ref, err)
+	}
+
+	if err := os.RemoveAll(opt.DestDir); err != nil {
+		return "", "", err
+	}
+
+	if err := os.MkdirAll(opt.DestDir, 0750); err != nil {
+		return "", "", err
+	}
+
+	extracted := 0
+
+	for _, layer := range layers {
+		if layer.MediaType != BundleLayerMediaType && layer.MediaType != ocispec.MediaTypeImageLayerGzip {
+			continue
+		}
+
+		_, data, ok := store.Get(layer)
+		if !ok {
+			return "", "", fmt.Errorf("layer %v of bundle %v is not pulled", layer.Digest, ref)
+		}
+
+		if err := extractBundleLayer(data, opt.DestDir); err != nil {
+			return "", "", fmt.Errorf("failed to extract layer %v of bundle %v, err: %w", layer.Digest, ref, err)
+		}
+
+		extracted++
+	}
+
+	if extracted == 0 {
+		return "", "", fmt.Errorf("bundle %v has no layer of media type %v", ref, BundleLayerMediaType)
+	}
+
+	klog.Infof("bundle %v pulled to %v, digest: %v", ref, opt.DestDir, manifest.Digest)
+
+	return opt.DestDir, manifest.Digest.String(), nil
+}
+
+// ExtractTarGz extracts a tar.gz archive to a directory like a bundle layer
+func ExtractTarGz(data []byte, destDir string) error {
+	return extractBundleLayer(data, destDir)
+}
+
+// extractBundleLayer extracts a tar.gz layer. Only directories and regular files are extracted, all the entries
+// are confined to the destination directory
+func extractBundleLayer(data []byte, destDir string) error {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(destDir, filepath.Clean("/"+hdr.Name)) // #nosec G305 the name is cleaned as an absolute path first
+		if target != destDir && !strings.HasPrefix(target, destDir+string(os.PathSeparator)) {
+			return fmt.Errorf("illegal file path %v in the bundle", hdr.Name)
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0750); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0750); err != nil {
+				return err
+			}
+
+			if err := writeBundleFile(target, tr); err != nil {
+				return err
+			}
+		default:
+			klog.Warningf("skip the unsupported entry %v of type %v in the bundle", hdr.Name, string(hdr.Typeflag))
+		}
+	}
+}
+
+func writeBundleFile(target string, r io.Reader) error {
+	f, err := os.OpenFile(filepath.Clean(target), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	n, err := io.CopyN(f, r, maxBundleFileSize+1)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	if n > maxBundleFileSize {
+		return fmt.Errorf("file %v in the bundle exceeds the size limit", target)
+	}
+
+	return nil
+}
+
+// LoadBundleIndex loads the index file of the packaged charts in the bundle and filters it by the subscription.
+// The relative chart URLs are resolved to the bundle root, an empty index is returned if there is no index file
+func LoadBundleIndex(sub *appv1.Subscription, root string) (*repo.IndexFile, error) {
+	indexPath := filepath.Join(root, BundleIndexFile)
+
+	if _, err := os.Stat(indexPath); os.IsNotExist(err) {
+		return repo.NewIndexFile(), nil
+	}
+
+	indexFile, err := repo.LoadIndexFile(indexPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the bundle index file %v, err: %w", indexPath, err)
+	}
+
+	// the paths of the package filter are relative to the bundle root
+	FilterChartsByPath(sub, indexFile)
+
+	for _, chartVersions := range indexFile.Entries {
+		for _, chartVersion := range chartVersions {
+			for i, u := range chartVersion.URLs {
+				if IsURL(u) || strings.HasPrefix(u, bundleFileScheme) {
+					continue
+				}
+
+				chartPath := filepath.Join(root, filepath.Clean("/"+u))
+				chartVersion.URLs[i] = bundleFileScheme + chartPath
+			}
+		}
+	}
+
+	if err := FilterCharts(sub, indexFile); err != nil {
+		return indexFile, err
+	}
+
+	return indexFile, nil
+}
+
+// IsBundleChartURL checks if the chart URL points to a chart package of a local bundle
+func IsBundleChartURL(u string) bool {
+	return strings.HasPrefix(u, bundleFileScheme+"/")
+}
@@ -0,0 +1,153 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func TestParseBundlePathname(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	localDir, ociRef, err := ParseBundlePathname("file:///bundles/app/")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(localDir).To(Equal("/bundles/app"))
+	g.Expect(ociRef).To(BeEmpty())
+
+	localDir, ociRef, err = ParseBundlePathname("oci://registry.local:5000/bundles/app:v1")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(localDir).To(BeEmpty())
+	g.Expect(ociRef).To(Equal("registry.local:5000/bundles/app:v1"))
+
+	_, _, err = ParseBundlePathname("https://github.com/open-cluster-management-io/multicloud-operators-subscription")
+	g.Expect(err).To(HaveOccurred())
+
+	_, _, err = ParseBundlePathname("file://bundles/app")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func tarGz(t *testing.T, files map[string]string) []byte {
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+
+	for name, body := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(body)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := tw.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestExtractBundleLayer(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	destDir := t.TempDir()
+
+	err := extractBundleLayer(tarGz(t, map[string]string{"manifests/cm.yaml": "kind: ConfigMap"}), destDir)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	data, err := os.ReadFile(filepath.Join(destDir, "manifests", "cm.yaml"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(data)).To(Equal("kind: ConfigMap"))
+
+	// entries escaping the bundle are kept inside the destination directory
+	err = extractBundleLayer(tarGz(t, map[string]string{"../../escape.yaml": "kind: ConfigMap"}), destDir)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(filepath.Join(destDir, "escape.yaml")).To(BeAnExistingFile())
+	g.Expect(filepath.Join(filepath.Dir(filepath.Dir(destDir)), "escape.yaml")).NotTo(BeAnExistingFile())
+}
+
+func TestFetchLocalBundle(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	root := t.TempDir()
+	index := `apiVersion: v1
+entries:
+  nginx:
+  - apiVersion: v2
+    name: nginx
+    version: 1.0.0
+    urls:
+    - charts/nginx-1.0.0.tgz
+  - apiVersion: v2
+    name: nginx
+    version: 1.1.0
+    urls:
+    - charts/nginx-1.1.0.tgz
+`
+
+	g.Expect(os.WriteFile(filepath.Join(root, BundleIndexFile), []byte(index), 0600)).To(Succeed())
+
+	dir, revision, err := FetchBundle(&BundleFetchOption{Pathname: "file://" + root})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(dir).To(Equal(root))
+	g.Expect(revision).To(HavePrefix("sha256:"))
+
+	_, revision2, err := FetchBundle(&BundleFetchOption{Pathname: root})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(revision2).To(Equal(revision))
+
+	// a manifest changed without updating the index changes the revision
+	g.Expect(os.MkdirAll(filepath.Join(root, "manifests"), 0700)).To(Succeed())
+	g.Expect(os.WriteFile(filepath.Join(root, "manifests", "cm.yaml"), []byte("data: a"), 0600)).To(Succeed())
+
+	_, revision3, err := FetchBundle(&BundleFetchOption{Pathname: root})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(revision3).NotTo(Equal(revision))
+
+	g.Expect(os.WriteFile(filepath.Join(root, "manifests", "cm.yaml"), []byte("data: b"), 0600)).To(Succeed())
+
+	_, revision4, err := FetchBundle(&BundleFetchOption{Pathname: root})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(revision4).NotTo(Equal(revision3))
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "sub", Namespace: "default"},
+		Spec:       appv1.SubscriptionSpec{Package: "nginx"},
+	}
+
+	indexFile, err := LoadBundleIndex(sub, root)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(indexFile.Entries["nginx"]).To(HaveLen(1))
+	g.Expect(indexFile.Entries["nginx"][0].Version).To(Equal("1.1.0"))
+	g.Expect(indexFile.Entries["nginx"][0].URLs).To(Equal([]string{"file://" + filepath.Join(root, "charts", "nginx-1.1.0.tgz")}))
+
+	_, _, err = FetchBundle(&BundleFetchOption{Pathname: filepath.Join(root, "missing")})
+	g.Expect(err).To(HaveOccurred())
+}
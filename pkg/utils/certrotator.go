@@ -0,0 +1,422 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+const (
+	// CertSecretCABundleKey is the key of the CA bundle in the secret of the rotated certificate
+	CertSecretCABundleKey = "ca.crt"
+
+	certRotatorInterval = time.Hour
+	certValidity        = 365 * 24 * time.Hour
+	// the certificate is renewed when less than a third of its validity is left
+	certRenewBefore = certValidity / 3
+)
+
+var caBundleTargets = []schema.GroupVersionKind{
+	{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "MutatingWebhookConfigurationList"},
+	{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "ValidatingWebhookConfigurationList"},
+	{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinitionList"},
+}
+
+// CertRotator keeps a self-signed serving certificate of a service in a secret of the service namespace, so that all the
+// replicas serve the same certificate. It renews the certificate before it expires, writes it in the certificate
+// directory of the server, and injects the CA bundle in the webhook configurations and CRDs labeled with
+// apps.open-cluster-management.io/inject-cabundle: <service>. The previous CA stays in the bundle until it expires, so the
+// replicas still serving the previous certificate are trusted during the rotation.
+type CertRotator struct {
+	Client     client.Client
+	Reader     client.Reader
+	Service    string
+	Namespace  string
+	SecretName string
+	CertDir    string
+
+	now func() time.Time
+}
+
+// NewCertRotator returns the certificate rotator of the service, the certificate is kept in the <service>-rotated-cert secret
+func NewCertRotator(mgr manager.Manager, service, namespace, certDir string) *CertRotator {
+	return &CertRotator{
+		Client:     mgr.GetClient(),
+		Reader:     mgr.GetAPIReader(),
+		Service:    service,
+		Namespace:  namespace,
+		SecretName: service + "-rotated-cert",
+		CertDir:    certDir,
+		now:        time.Now,
+	}
+}
+
+// Start checks the certificate periodically until the context is done, Rotate must be called once before the server
+// starts so the certificate files exist
+func (r *CertRotator) Start(ctx context.Context) error {
+	ticker := time.NewTicker(certRotatorInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := r.Rotate(ctx); err != nil {
+				klog.Errorf("failed to rotate the certificate of service %v/%v, error: %v", r.Namespace, r.Service, err)
+			}
+		}
+	}
+}
+
+// NeedLeaderElection runs the rotator on all the replicas, each replica writes the certificate in its own directory
+func (r *CertRotator) NeedLeaderElection() bool {
+	return false
+}
+
+// Rotate renews the certificate if it is missing, expiring or issued for other DNS names, then writes it in the
+// certificate directory and injects the CA bundle
+func (r *CertRotator) Rotate(ctx context.Context) error {
+	secret, err := r.ensureSecret(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := writeCertFiles(r.CertDir, secret.Data); err != nil {
+		return err
+	}
+
+	return r.injectCABundle(ctx, secret.Data[CertSecretCABundleKey])
+}
+
+// DNSNames returns the DNS names of the service certificate
+func (r *CertRotator) DNSNames() []string {
+	return []string{
+		r.Service,
+		fmt.Sprintf("%s.%s", r.Service, r.Namespace),
+		fmt.Sprintf("%s.%s.svc", r.Service, r.Namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", r.Service, r.Namespace),
+	}
+}
+
+func (r *CertRotator) ensureSecret(ctx context.Context) (*corev1.Secret, error) {
+	key := types.NamespacedName{Name: r.SecretName, Namespace: r.Namespace}
+	secret := &corev1.Secret{}
+
+	err := r.Reader.Get(ctx, key, secret)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	found := err == nil
+
+	if found && !r.needsRenewal(secret.Data) {
+		return secret, nil
+	}
+
+	data, err := r.generateCerts(secret.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	if found {
+		klog.Infof("renewing the certificate of service %v/%v", r.Namespace, r.Service)
+
+		secret.Data = data
+		err = r.Client.Update(ctx, secret)
+	} else {
+		klog.Infof("creating the certificate of service %v/%v", r.Namespace, r.Service)
+
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: r.SecretName, Namespace: r.Namespace},
+			Type:       corev1.SecretTypeTLS,
+			Data:       data,
+		}
+		err = r.Client.Create(ctx, secret)
+	}
+
+	// another replica renewed the certificate first, its certificate is used
+	if kerrors.IsConflict(err) || kerrors.IsAlreadyExists(err) {
+		secret = &corev1.Secret{}
+		err = r.Reader.Get(ctx, key, secret)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
+
+func (r *CertRotator) needsRenewal(data map[string][]byte) bool {
+	if len(data[corev1.TLSPrivateKeyKey]) == 0 || len(data[CertSecretCABundleKey]) == 0 {
+		return true
+	}
+
+	certs := parseCertificates(data[corev1.TLSCertKey])
+	if len(certs) == 0 {
+		return true
+	}
+
+	if r.now().Add(certRenewBefore).After(certs[0].NotAfter) {
+		return true
+	}
+
+	return !reflect.DeepEqual(certs[0].DNSNames, r.DNSNames())
+}
+
+// generateCerts returns a new CA and serving certificate, the CA bundle keeps the previous CAs which are not expired
+func (r *CertRotator) generateCerts(previous map[string][]byte) (map[string][]byte, error) {
+	notBefore := r.now().Add(-time.Hour)
+	notAfter := notBefore.Add(certValidity)
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	caTemplate := &x509.Certificate{
+		SerialNumber:          newSerialNumber(),
+		Subject:               pkix.Name{CommonName: r.Service + "-ca@" + fmt.Sprint(notBefore.Unix())},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+
+	caBytes, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+
+	ca, err := x509.ParseCertificate(caBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	servingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	servingTemplate := &x509.Certificate{
+		SerialNumber: newSerialNumber(),
+		Subject:      pkix.Name{CommonName: fmt.Sprintf("%s.%s.svc", r.Service, r.Namespace)},
+		DNSNames:     r.DNSNames(),
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	servingBytes, err := x509.CreateCertificate(rand.Reader, servingTemplate, ca, &servingKey.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+
+	keyBytes, err := x509.MarshalECPrivateKey(servingKey)
+	if err != nil {
+		return nil, err
+	}
+
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caBytes})
+
+	for _, previousCA := range parseCertificates(previous[CertSecretCABundleKey]) {
+		if r.now().Before(previousCA.NotAfter) {
+			caBundle = append(caBundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: previousCA.Raw})...)
+		}
+	}
+
+	return map[string][]byte{
+		corev1.TLSCertKey:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: servingBytes}),
+		corev1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes}),
+		CertSecretCABundleKey:   caBundle,
+	}, nil
+}
+
+// injectCABundle sets the CA bundle in the client configs of the labeled webhook configurations and CRD conversion webhooks
+func (r *CertRotator) injectCABundle(ctx context.Context, caBundle []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(caBundle)
+
+	for _, gvk := range caBundleTargets {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk)
+
+		if err := r.Client.List(ctx, list, client.MatchingLabels{appv1.LabelInjectCABundle: r.Service}); err != nil {
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+
+			return err
+		}
+
+		for i := range list.Items {
+			obj := &list.Items[i]
+			orig := obj.DeepCopy()
+
+			if !setCABundle(obj, encoded) {
+				continue
+			}
+
+			klog.Infof("injecting the CA bundle of service %v/%v in %v %v", r.Namespace, r.Service, obj.GetKind(), obj.GetName())
+
+			if err := r.Client.Patch(ctx, obj, client.MergeFrom(orig)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// setCABundle sets the base64 CA bundle in the webhooks of a webhook configuration or in the conversion webhook of a
+// CRD, it returns false if nothing changed
+func setCABundle(obj *unstructured.Unstructured, caBundle string) bool {
+	changed := false
+
+	webhooks, found, _ := unstructured.NestedSlice(obj.Object, "webhooks")
+	if found {
+		for i, webhook := range webhooks {
+			webhookMap, ok := webhook.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			if current, _, _ := unstructured.NestedString(webhookMap, "clientConfig", "caBundle"); current != caBundle {
+				_ = unstructured.SetNestedField(webhookMap, caBundle, "clientConfig", "caBundle")
+				webhooks[i] = webhookMap
+				changed = true
+			}
+		}
+
+		if changed {
+			_ = unstructured.SetNestedSlice(obj.Object, webhooks, "webhooks")
+		}
+
+		return changed
+	}
+
+	if strategy, _, _ := unstructured.NestedString(obj.Object, "spec", "conversion", "strategy"); strategy != "Webhook" {
+		return false
+	}
+
+	current, _, _ := unstructured.NestedString(obj.Object, "spec", "conversion", "webhook", "clientConfig", "caBundle")
+	if current == caBundle {
+		return false
+	}
+
+	_ = unstructured.SetNestedField(obj.Object, caBundle, "spec", "conversion", "webhook", "clientConfig", "caBundle")
+
+	return true
+}
+
+// writeCertFiles writes the tls.crt and tls.key files of the certificate if their content changed. The files are
+// replaced by a rename so the certificate watchers of the servers never read a partial file.
+func writeCertFiles(dir string, data map[string][]byte) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	for _, name := range []string{corev1.TLSPrivateKeyKey, corev1.TLSCertKey} {
+		path := filepath.Join(dir, name)
+
+		if current, err := os.ReadFile(filepath.Clean(path)); err == nil && bytes.Equal(current, data[name]) {
+			continue
+		}
+
+		tmp, err := os.CreateTemp(dir, "."+name)
+		if err != nil {
+			return err
+		}
+
+		_, err = tmp.Write(data[name])
+		if closeErr := tmp.Close(); err == nil {
+			err = closeErr
+		}
+
+		if err == nil {
+			err = os.Rename(tmp.Name(), path)
+		}
+
+		if err != nil {
+			os.Remove(tmp.Name())
+
+			return fmt.Errorf("failed to write the certificate file %v: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+func parseCertificates(data []byte) []*x509.Certificate {
+	certs := []*x509.Certificate{}
+
+	for {
+		var block *pem.Block
+
+		block, data = pem.Decode(data)
+		if block == nil {
+			return certs
+		}
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+
+		certs = append(certs, cert)
+	}
+}
+
+func newSerialNumber() *big.Int {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return big.NewInt(time.Now().UnixNano())
+	}
+
+	return serial
+}
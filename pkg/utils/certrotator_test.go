@@ -0,0 +1,141 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"crypto/tls"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func TestCertRotator(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ctx := context.TODO()
+
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(apiextensionsv1.AddToScheme(scheme)).To(Succeed())
+
+	injected := map[string]string{appv1.LabelInjectCABundle: "webhook-service"}
+
+	webhookConfig := &admissionv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "appsub-validator", Labels: injected},
+		Webhooks: []admissionv1.ValidatingWebhook{
+			{Name: "a.apps.open-cluster-management.io"},
+			{Name: "b.apps.open-cluster-management.io"},
+		},
+	}
+	otherConfig := &admissionv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "other"},
+		Webhooks:   []admissionv1.MutatingWebhook{{Name: "other.example.com"}},
+	}
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "subscriptions.apps.open-cluster-management.io", Labels: injected},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Conversion: &apiextensionsv1.CustomResourceConversion{
+				Strategy: apiextensionsv1.WebhookConverter,
+				Webhook:  &apiextensionsv1.WebhookConversion{ClientConfig: &apiextensionsv1.WebhookClientConfig{}},
+			},
+		},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(webhookConfig, otherConfig, crd).Build()
+
+	dir, err := os.MkdirTemp("", "certrotator")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+	rotator := &CertRotator{
+		Client:     c,
+		Reader:     c,
+		Service:    "webhook-service",
+		Namespace:  "open-cluster-management",
+		SecretName: "webhook-service-rotated-cert",
+		CertDir:    dir,
+		now:        func() time.Time { return now },
+	}
+
+	g.Expect(rotator.Rotate(ctx)).To(Succeed())
+
+	secret := &corev1.Secret{}
+	g.Expect(c.Get(ctx, types.NamespacedName{Name: rotator.SecretName, Namespace: rotator.Namespace}, secret)).To(Succeed())
+
+	_, err = tls.LoadX509KeyPair(filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	certs := parseCertificates(secret.Data[corev1.TLSCertKey])
+	g.Expect(certs).To(HaveLen(1))
+	g.Expect(certs[0].DNSNames).To(ContainElement("webhook-service.open-cluster-management.svc"))
+
+	firstCAs := parseCertificates(secret.Data[CertSecretCABundleKey])
+	g.Expect(firstCAs).To(HaveLen(1))
+	g.Expect(certs[0].CheckSignatureFrom(firstCAs[0])).To(Succeed())
+
+	g.Expect(c.Get(ctx, types.NamespacedName{Name: webhookConfig.Name}, webhookConfig)).To(Succeed())
+
+	for _, webhook := range webhookConfig.Webhooks {
+		g.Expect(webhook.ClientConfig.CABundle).To(Equal(secret.Data[CertSecretCABundleKey]))
+	}
+
+	g.Expect(c.Get(ctx, types.NamespacedName{Name: otherConfig.Name}, otherConfig)).To(Succeed())
+	g.Expect(otherConfig.Webhooks[0].ClientConfig.CABundle).To(BeEmpty())
+
+	g.Expect(c.Get(ctx, types.NamespacedName{Name: crd.Name}, crd)).To(Succeed())
+	g.Expect(crd.Spec.Conversion.Webhook.ClientConfig.CABundle).To(Equal(secret.Data[CertSecretCABundleKey]))
+
+	// the certificate is kept until it expires soon
+	g.Expect(rotator.Rotate(ctx)).To(Succeed())
+
+	kept := &corev1.Secret{}
+	g.Expect(c.Get(ctx, types.NamespacedName{Name: rotator.SecretName, Namespace: rotator.Namespace}, kept)).To(Succeed())
+	g.Expect(kept.Data).To(Equal(secret.Data))
+
+	now = now.Add(certValidity - certRenewBefore + time.Hour)
+	g.Expect(rotator.Rotate(ctx)).To(Succeed())
+
+	renewed := &corev1.Secret{}
+	g.Expect(c.Get(ctx, types.NamespacedName{Name: rotator.SecretName, Namespace: rotator.Namespace}, renewed)).To(Succeed())
+	g.Expect(renewed.Data[corev1.TLSCertKey]).NotTo(Equal(secret.Data[corev1.TLSCertKey]))
+
+	// the previous CA is still trusted during the rotation
+	renewedCAs := parseCertificates(renewed.Data[CertSecretCABundleKey])
+	g.Expect(renewedCAs).To(HaveLen(2))
+	g.Expect(renewedCAs[1].Equal(firstCAs[0])).To(BeTrue())
+
+	crt, err := os.ReadFile(filepath.Join(dir, "tls.crt"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(crt).To(Equal(renewed.Data[corev1.TLSCertKey]))
+
+	g.Expect(c.Get(ctx, types.NamespacedName{Name: webhookConfig.Name}, webhookConfig)).To(Succeed())
+	g.Expect(webhookConfig.Webhooks[0].ClientConfig.CABundle).To(Equal(renewed.Data[CertSecretCABundleKey]))
+}
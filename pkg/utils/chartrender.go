@@ -0,0 +1,142 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+// RenderSubscriptionChart renders the chart in the namespace with the chart values overridden by the "spec" package
+// override of the chart, the same way as the HelmRelease of a helm repo chart. The release name is the package alias
+// of the chart, the chart name by default
+func RenderSubscriptionChart(sub *appv1.Subscription, chrt *chart.Chart, namespace string) ([]*unstructured.Unstructured, error) {
+	values, err := chartOverrideValues(sub, chrt.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	releaseName := GetPackageAlias(sub, chrt.Name())
+	if releaseName == "" {
+		releaseName = chrt.Name()
+	}
+
+	return RenderChart(chrt, releaseName, namespace, values)
+}
+
+// RenderChart renders the CRDs and the templates of the chart as a client only install, the hooks and the notes are
+// skipped. The templates are rendered with the default capabilities
+func RenderChart(chrt *chart.Chart, releaseName, namespace string, values chartutil.Values) ([]*unstructured.Unstructured, error) {
+	if err := chartutil.ProcessDependencies(chrt, values); err != nil {
+		return nil, err
+	}
+
+	renderValues, err := chartutil.ToRenderValues(chrt, values, chartutil.ReleaseOptions{
+		Name:      releaseName,
+		Namespace: namespace,
+		IsInstall: true,
+	}, chartutil.DefaultCapabilities)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered, err := engine.Render(chrt, renderValues)
+	if err != nil {
+		return nil, err
+	}
+
+	manifests := []archiveFile{}
+
+	for _, crd := range chrt.CRDObjects() {
+		manifests = append(manifests, archiveFile{name: crd.Filename, data: crd.File.Data})
+	}
+
+	names := make([]string, 0, len(rendered))
+
+	for name := range rendered {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if strings.HasSuffix(name, "NOTES.txt") || strings.HasPrefix(path.Base(name), "_") {
+			continue
+		}
+
+		manifests = append(manifests, archiveFile{name: name, data: []byte(rendered[name])})
+	}
+
+	templates := []*unstructured.Unstructured{}
+
+	for _, manifest := range manifests {
+		for _, item := range ParseKubeResoures(manifest.data) {
+			template := &unstructured.Unstructured{}
+			if err := yaml.Unmarshal(item, template); err != nil {
+				return nil, fmt.Errorf("failed to parse the rendered template %v: %w", manifest.name, err)
+			}
+
+			if isHelmHook(template) {
+				klog.V(1).Infof("skip hook %v of chart %v", manifest.name, chrt.Name())
+
+				continue
+			}
+
+			templates = append(templates, template)
+		}
+	}
+
+	return templates, nil
+}
+
+// chartOverrideValues returns the values of the chart set by the package overrides, the HelmRelease spec holds the
+// values so the overrides are applied to a spec the same way as to a HelmRelease
+func chartOverrideValues(sub *appv1.Subscription, chartName string) (chartutil.Values, error) {
+	overrides := getOverrides(chartName, sub)
+	if len(overrides.ClusterOverrides) == 0 {
+		return chartutil.Values{}, nil
+	}
+
+	template := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{}}}
+
+	template, err := OverrideTemplate(template, overrides.ClusterOverrides)
+	if err != nil {
+		return nil, err
+	}
+
+	values, ok := template.Object["spec"].(map[string]interface{})
+	if !ok {
+		return chartutil.Values{}, nil
+	}
+
+	return values, nil
+}
+
+func isHelmHook(template *unstructured.Unstructured) bool {
+	_, ok := template.GetAnnotations()["helm.sh/hook"]
+
+	return ok
+}
@@ -0,0 +1,227 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+const (
+	// CheckpointSuffix is the name suffix of the ConfigMap holding the render and apply progress of a subscription
+	CheckpointSuffix = "-checkpoint"
+	// CheckpointRevisionKey is the resolved revision, the commit ID, of the last render
+	CheckpointRevisionKey = "revision"
+	// CheckpointHashKey is the hash of the subscription spec and annotations of the last render
+	CheckpointHashKey = "subscriptionHash"
+	// CheckpointPhaseKey is the apply phase of the last render
+	CheckpointPhaseKey = "phase"
+	// CheckpointResourcesKey is the number of resources of the last render
+	CheckpointResourcesKey = "resources"
+	// CheckpointAppliedWavesKey is the JSON of the waves applied before the apply of the revision was interrupted
+	CheckpointAppliedWavesKey = "appliedWaves"
+	// CheckpointPendingDeletionsKey is the JSON of the resources of the previous revision not deleted yet
+	CheckpointPendingDeletionsKey = "pendingDeletions"
+	// CheckpointUpdateTimeKey is the time of the last checkpoint
+	CheckpointUpdateTimeKey = "updateTime"
+
+	// CheckpointPhaseCompleted means all the resources of the revision are applied
+	CheckpointPhaseCompleted = "Completed"
+	// CheckpointPhaseInterrupted means the apply of the revision was interrupted by a shutdown
+	CheckpointPhaseInterrupted = "Interrupted"
+)
+
+// SubscriptionCheckpoint is the render and apply progress of a subscription, it survives the agent restarts
+type SubscriptionCheckpoint struct {
+	Revision  string
+	Hash      string
+	Phase     string
+	Resources int
+	// AppliedWaves are the resources applied before the interruption, grouped by the waves of the apply
+	AppliedWaves []CheckpointWave
+	// PendingDeletions are the resources of the previous revision the interrupted apply didn't delete
+	PendingDeletions []CheckpointResource
+}
+
+// CheckpointWave is a wave of the resources of the same kind applied together
+type CheckpointWave struct {
+	APIVersion string               `json:"apiVersion"`
+	Kind       string               `json:"kind"`
+	Resources  []CheckpointResource `json:"resources"`
+}
+
+// CheckpointResource is a resource of the checkpoint. RenderHash is the hash of the rendered manifest, a resource
+// rendered the same way after the restart isn't applied again, Hash is the hash of the applied manifest
+type CheckpointResource struct {
+	APIVersion string `json:"apiVersion,omitempty"`
+	Kind       string `json:"kind,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	RenderHash string `json:"renderHash,omitempty"`
+	Hash       string `json:"hash,omitempty"`
+}
+
+// IsCompleted checks if the revision is fully applied with the current spec and annotations of the subscription
+func (cp *SubscriptionCheckpoint) IsCompleted(sub *appv1.Subscription, revision string) bool {
+	return cp != nil && revision != "" && cp.Revision == revision && cp.Hash == SubscriptionCheckpointHash(sub) &&
+		cp.Phase == CheckpointPhaseCompleted
+}
+
+// IsInterrupted checks if the apply of the revision was interrupted, the next apply of the revision resumes after the
+// applied waves
+func (cp *SubscriptionCheckpoint) IsInterrupted(revision string) bool {
+	return cp != nil && revision != "" && cp.Revision == revision && cp.Phase == CheckpointPhaseInterrupted
+}
+
+// SubscriptionCheckpointHash returns the hash of the spec and annotations of the subscription, the git path, branch
+// and the other render settings are annotations so the generation alone doesn't track them
+func SubscriptionCheckpointHash(sub *appv1.Subscription) string {
+	data, err := json.Marshal(struct {
+		Spec        appv1.SubscriptionSpec `json:"spec"`
+		Annotations map[string]string      `json:"annotations"`
+	}{Spec: sub.Spec, Annotations: sub.GetAnnotations()})
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:8])
+}
+
+// GetSubscriptionCheckpoint returns the checkpoint of the subscription, nil if there is none
+func GetSubscriptionCheckpoint(c client.Client, sub *appv1.Subscription) (*SubscriptionCheckpoint, error) {
+	cm := &corev1.ConfigMap{}
+
+	err := c.Get(context.TODO(), types.NamespacedName{Name: sub.GetName() + CheckpointSuffix, Namespace: sub.GetNamespace()}, cm)
+	if kerrors.IsNotFound(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	cp := &SubscriptionCheckpoint{
+		Revision: cm.Data[CheckpointRevisionKey],
+		Hash:     cm.Data[CheckpointHashKey],
+		Phase:    cm.Data[CheckpointPhaseKey],
+	}
+
+	cp.Resources, _ = strconv.Atoi(cm.Data[CheckpointResourcesKey])
+
+	// a corrupted progress is dropped, the revision is then fully applied again
+	if data := cm.Data[CheckpointAppliedWavesKey]; data != "" {
+		if err := json.Unmarshal([]byte(data), &cp.AppliedWaves); err != nil {
+			klog.Warningf("failed to parse the applied waves of checkpoint %v/%v, err: %v", cm.Namespace, cm.Name, err)
+
+			cp.AppliedWaves = nil
+		}
+	}
+
+	if data := cm.Data[CheckpointPendingDeletionsKey]; data != "" {
+		if err := json.Unmarshal([]byte(data), &cp.PendingDeletions); err != nil {
+			klog.Warningf("failed to parse the pending deletions of checkpoint %v/%v, err: %v", cm.Namespace, cm.Name, err)
+
+			cp.PendingDeletions = nil
+		}
+	}
+
+	return cp, nil
+}
+
+// SaveSubscriptionCheckpoint creates or updates the checkpoint ConfigMap of the subscription, the ConfigMap is owned by
+// the subscription and is garbage collected with it
+func SaveSubscriptionCheckpoint(c client.Client, sub *appv1.Subscription, cp SubscriptionCheckpoint) error {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sub.GetName() + CheckpointSuffix,
+			Namespace: sub.GetNamespace(),
+			Labels: map[string]string{
+				appv1.LabelCheckpointOf: sub.GetName(),
+			},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(sub, appv1.SchemeGroupVersion.WithKind("Subscription"))},
+		},
+		Data: map[string]string{
+			CheckpointRevisionKey:   cp.Revision,
+			CheckpointHashKey:       cp.Hash,
+			CheckpointPhaseKey:      cp.Phase,
+			CheckpointResourcesKey:  strconv.Itoa(cp.Resources),
+			CheckpointUpdateTimeKey: time.Now().UTC().Format(time.RFC3339),
+		},
+	}
+
+	// the progress is only kept for an interrupted apply, the completed one clears it
+	if len(cp.AppliedWaves) > 0 {
+		data, err := json.Marshal(cp.AppliedWaves)
+		if err != nil {
+			return err
+		}
+
+		cm.Data[CheckpointAppliedWavesKey] = string(data)
+	}
+
+	if len(cp.PendingDeletions) > 0 {
+		data, err := json.Marshal(cp.PendingDeletions)
+		if err != nil {
+			return err
+		}
+
+		cm.Data[CheckpointPendingDeletionsKey] = string(data)
+	}
+
+	existing := &corev1.ConfigMap{}
+
+	err := c.Get(context.TODO(), types.NamespacedName{Name: cm.Name, Namespace: cm.Namespace}, existing)
+	if kerrors.IsNotFound(err) {
+		klog.V(1).Infof("creating the checkpoint configmap %v/%v", cm.Namespace, cm.Name)
+
+		return c.Create(context.TODO(), cm)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// the periodic reconciles of the same commit don't update the checkpoint
+	if existing.Data[CheckpointRevisionKey] == cp.Revision && existing.Data[CheckpointHashKey] == cp.Hash &&
+		existing.Data[CheckpointPhaseKey] == cp.Phase && existing.Data[CheckpointResourcesKey] == cm.Data[CheckpointResourcesKey] &&
+		existing.Data[CheckpointAppliedWavesKey] == cm.Data[CheckpointAppliedWavesKey] &&
+		existing.Data[CheckpointPendingDeletionsKey] == cm.Data[CheckpointPendingDeletionsKey] &&
+		existing.Labels[appv1.LabelCheckpointOf] == sub.GetName() {
+		return nil
+	}
+
+	existing.Data = cm.Data
+	existing.Labels = cm.Labels
+	existing.OwnerReferences = cm.OwnerReferences
+
+	klog.V(1).Infof("updating the checkpoint configmap %v/%v", cm.Namespace, cm.Name)
+
+	return c.Update(context.TODO(), existing)
+}
@@ -0,0 +1,98 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func TestSubscriptionCheckpoint(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "sub",
+			Namespace:   "ns",
+			UID:         "1234",
+			Annotations: map[string]string{appv1.AnnotationGitPath: "app"},
+		},
+	}
+	c := fake.NewClientBuilder().Build()
+
+	checkpoint, err := GetSubscriptionCheckpoint(c, sub)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(checkpoint).To(BeNil())
+	g.Expect(checkpoint.IsCompleted(sub, "abc")).To(BeFalse())
+
+	waves := []CheckpointWave{{
+		APIVersion: "v1",
+		Kind:       "ConfigMap",
+		Resources:  []CheckpointResource{{Namespace: "ns", Name: "cm1", RenderHash: "1234", Hash: "5678"}},
+	}}
+	pending := []CheckpointResource{{APIVersion: "v1", Kind: "ConfigMap", Namespace: "ns", Name: "old"}}
+
+	g.Expect(SaveSubscriptionCheckpoint(c, sub, SubscriptionCheckpoint{
+		Revision:         "abc",
+		Hash:             SubscriptionCheckpointHash(sub),
+		Phase:            CheckpointPhaseInterrupted,
+		Resources:        3,
+		AppliedWaves:     waves,
+		PendingDeletions: pending,
+	})).To(Succeed())
+
+	checkpoint, err = GetSubscriptionCheckpoint(c, sub)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(checkpoint.Resources).To(Equal(3))
+	g.Expect(checkpoint.IsCompleted(sub, "abc")).To(BeFalse())
+	g.Expect(checkpoint.IsInterrupted("abc")).To(BeTrue())
+	g.Expect(checkpoint.IsInterrupted("def")).To(BeFalse())
+	g.Expect(checkpoint.AppliedWaves).To(Equal(waves))
+	g.Expect(checkpoint.PendingDeletions).To(Equal(pending))
+
+	g.Expect(SaveSubscriptionCheckpoint(c, sub, SubscriptionCheckpoint{
+		Revision:  "abc",
+		Hash:      SubscriptionCheckpointHash(sub),
+		Phase:     CheckpointPhaseCompleted,
+		Resources: 3,
+	})).To(Succeed())
+
+	cm := &corev1.ConfigMap{}
+	g.Expect(c.Get(context.TODO(), types.NamespacedName{Name: "sub" + CheckpointSuffix, Namespace: "ns"}, cm)).To(Succeed())
+	g.Expect(cm.Labels[appv1.LabelCheckpointOf]).To(Equal("sub"))
+	g.Expect(cm.OwnerReferences).To(HaveLen(1))
+	g.Expect(cm.Data[CheckpointPhaseKey]).To(Equal(CheckpointPhaseCompleted))
+
+	// the completed apply clears the progress of the interrupted one
+	g.Expect(cm.Data).NotTo(HaveKey(CheckpointAppliedWavesKey))
+	g.Expect(cm.Data).NotTo(HaveKey(CheckpointPendingDeletionsKey))
+
+	checkpoint, err = GetSubscriptionCheckpoint(c, sub)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(checkpoint.IsCompleted(sub, "abc")).To(BeTrue())
+	g.Expect(checkpoint.IsCompleted(sub, "def")).To(BeFalse())
+
+	// a changed git path needs a new render
+	sub.Annotations[appv1.AnnotationGitPath] = "other"
+	g.Expect(checkpoint.IsCompleted(sub, "abc")).To(BeFalse())
+}
@@ -0,0 +1,384 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// ControllerConfigMapName is the ConfigMap of the operator namespace overriding the defaults of the controllers
+	ControllerConfigMapName = "subscription-controller-config"
+
+	ControllerConfigReconcileIntervalLow    = "reconcileIntervalLow"
+	ControllerConfigReconcileIntervalMedium = "reconcileIntervalMedium"
+	ControllerConfigReconcileIntervalHigh   = "reconcileIntervalHigh"
+	ControllerConfigGitCloneDepth           = "gitCloneDepth"
+	ControllerConfigApplyConcurrency        = "applyConcurrency"
+	ControllerConfigGitWorkers              = "gitWorkers"
+	ControllerConfigGitWorkersPerChannel    = "gitWorkersPerChannel"
+	ControllerConfigMaxManifestSize         = "maxManifestSize"
+	ControllerConfigMaxManifestDocuments    = "maxManifestDocuments"
+	ControllerConfigStatusVerbosity         = "statusVerbosity"
+	ControllerConfigFeatureGates            = "featureGates"
+
+	// StatusVerbosityFull lists all the resources of an appsub in its appsubstatus events
+	StatusVerbosityFull = "full"
+	// StatusVerbositySummary lists the number of resources and the failed resources of an appsub in its appsubstatus events
+	StatusVerbositySummary = "summary"
+
+	// the resources applied in parallel hold the synchronizer lock, the concurrency is capped to keep the API server
+	// requests of the other appsubs flowing
+	maxApplyConcurrency = 20
+	// the reconcile intervals shorter than a minute hammer the sources of the channels
+	minReconcileInterval = time.Minute
+)
+
+// ControllerConfig is the configuration of the controllers read from the subscription-controller-config ConfigMap,
+// the zero values keep the defaults of the controllers
+type ControllerConfig struct {
+	// ReconcileIntervals are the reconcile intervals of the low, medium and high reconcile rates for all the channel types
+	ReconcileIntervals map[string]time.Duration
+	// GitCloneDepth is the clone depth of the subscriptions without the git-clone-depth annotation
+	GitCloneDepth int
+	// ApplyConcurrency is the number of resources of the same kind of an appsub the agent applies in parallel
+	ApplyConcurrency int
+	// GitWorkers is the number of Git subscriptions cloned, rendered and applied at the same time
+	GitWorkers int
+	// GitWorkersPerChannel is the number of GitWorkers the subscriptions of a channel run on at the same time
+	GitWorkersPerChannel int
+	// MaxManifestSize is the size of a manifest file of the Git subscriptions, in bytes
+	MaxManifestSize int64
+	// MaxManifestDocuments is the number of YAML documents of a manifest file of the Git subscriptions
+	MaxManifestDocuments int
+	// StatusVerbosity is full or summary
+	StatusVerbosity string
+	// FeatureGates enables or disables the feature gates, on top of the feature gates of the command line
+	FeatureGates map[string]bool
+}
+
+var (
+	controllerConfigLock sync.RWMutex
+	controllerConfig     ControllerConfig
+)
+
+// GetControllerConfig returns the current configuration of the controllers
+func GetControllerConfig() ControllerConfig {
+	controllerConfigLock.RLock()
+	defer controllerConfigLock.RUnlock()
+
+	return controllerConfig
+}
+
+// SetControllerConfig replaces the configuration of the controllers, they read it on their next reconcile
+func SetControllerConfig(config ControllerConfig) {
+	controllerConfigLock.Lock()
+	defer controllerConfigLock.Unlock()
+
+	controllerConfig = config
+}
+
+// ParseControllerConfig parses the data of the controller config ConfigMap, the invalid values are returned as errors
+// and ignored so a typo doesn't reset the other settings
+func ParseControllerConfig(data map[string]string) (ControllerConfig, []error) {
+	config := ControllerConfig{}
+	errs := []error{}
+
+	for rate, key := range map[string]string{
+		"low":    ControllerConfigReconcileIntervalLow,
+		"medium": ControllerConfigReconcileIntervalMedium,
+		"high":   ControllerConfigReconcileIntervalHigh,
+	} {
+		v := strings.TrimSpace(data[key])
+		if v == "" {
+			continue
+		}
+
+		d, err := time.ParseDuration(v)
+		if err != nil || d < minReconcileInterval {
+			errs = append(errs, fmt.Errorf("%v %v must be a duration of at least %v", key, v, minReconcileInterval))
+
+			continue
+		}
+
+		if config.ReconcileIntervals == nil {
+			config.ReconcileIntervals = map[string]time.Duration{}
+		}
+
+		config.ReconcileIntervals[rate] = d
+	}
+
+	if v := strings.TrimSpace(data[ControllerConfigGitCloneDepth]); v != "" {
+		depth, err := strconv.Atoi(v)
+		if err != nil || depth < 0 {
+			errs = append(errs, fmt.Errorf("%v %v must be a positive integer", ControllerConfigGitCloneDepth, v))
+		} else {
+			config.GitCloneDepth = depth
+		}
+	}
+
+	if v := strings.TrimSpace(data[ControllerConfigApplyConcurrency]); v != "" {
+		concurrency, err := strconv.Atoi(v)
+		if err != nil || concurrency < 1 || concurrency > maxApplyConcurrency {
+			errs = append(errs, fmt.Errorf("%v %v must be an integer between 1 and %v", ControllerConfigApplyConcurrency, v,
+				maxApplyConcurrency))
+		} else {
+			config.ApplyConcurrency = concurrency
+		}
+	}
+
+	for key, value := range map[string]*int{
+		ControllerConfigGitWorkers:           &config.GitWorkers,
+		ControllerConfigGitWorkersPerChannel: &config.GitWorkersPerChannel,
+		ControllerConfigMaxManifestDocuments: &config.MaxManifestDocuments,
+	} {
+		v := strings.TrimSpace(data[key])
+		if v == "" {
+			continue
+		}
+
+		workers, err := strconv.Atoi(v)
+		if err != nil || workers < 1 {
+			errs = append(errs, fmt.Errorf("%v %v must be a positive integer", key, v))
+
+			continue
+		}
+
+		*value = workers
+	}
+
+	if v := strings.TrimSpace(data[ControllerConfigMaxManifestSize]); v != "" {
+		size, err := resource.ParseQuantity(v)
+		if err != nil || size.Value() < MaxManifestDocumentSize {
+			errs = append(errs, fmt.Errorf("%v %v must be a quantity of at least %v", ControllerConfigMaxManifestSize, v,
+				resource.NewQuantity(MaxManifestDocumentSize, resource.BinarySI)))
+		} else {
+			config.MaxManifestSize = size.Value()
+		}
+	}
+
+	if v := strings.TrimSpace(data[ControllerConfigStatusVerbosity]); v != "" {
+		if !strings.EqualFold(v, StatusVerbosityFull) && !strings.EqualFold(v, StatusVerbositySummary) {
+			errs = append(errs, fmt.Errorf("%v %v must be %v or %v", ControllerConfigStatusVerbosity, v,
+				StatusVerbosityFull, StatusVerbositySummary))
+		} else {
+			config.StatusVerbosity = strings.ToLower(v)
+		}
+	}
+
+	if v := strings.TrimSpace(data[ControllerConfigFeatureGates]); v != "" {
+		gates, err := parseFeatureGates(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%v %v is invalid, err: %w", ControllerConfigFeatureGates, v, err))
+		} else {
+			// the unknown gates are ignored, the known ones apply
+			known, err := knownGates(gates)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%v %v is invalid, err: %w", ControllerConfigFeatureGates, v, err))
+			}
+
+			if len(known) > 0 {
+				config.FeatureGates = map[string]bool{}
+
+				for gate, enabled := range known {
+					config.FeatureGates[string(gate)] = enabled
+				}
+			}
+		}
+	}
+
+	return config, errs
+}
+
+// parseFeatureGates parses the <gate>=<true|false> pairs separated by commas
+func parseFeatureGates(v string) (map[string]bool, error) {
+	gates := map[string]bool{}
+
+	for _, pair := range strings.Split(v, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("missing the value of feature gate %v", pair)
+		}
+
+		enabled, err := strconv.ParseBool(strings.TrimSpace(kv[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %v of feature gate %v", kv[1], kv[0])
+		}
+
+		gates[strings.TrimSpace(kv[0])] = enabled
+	}
+
+	return gates, nil
+}
+
+// DefaultGitCloneDepth returns the clone depth of the controller config, or the given default if it isn't set
+func DefaultGitCloneDepth(defaultDepth int) int {
+	if depth := GetControllerConfig().GitCloneDepth; depth > 0 {
+		return depth
+	}
+
+	return defaultDepth
+}
+
+// ApplyConcurrency returns the number of resources of the same kind of an appsub applied in parallel, one by default
+func ApplyConcurrency() int {
+	if concurrency := GetControllerConfig().ApplyConcurrency; concurrency > 0 {
+		return concurrency
+	}
+
+	return 1
+}
+
+// GitWorkers returns the number of Git subscriptions processed at the same time and the number of them from the same
+// channel, 0 if the Git subscriptions are not scheduled. The subscriptions of a channel run on all the workers unless
+// the per channel workers are set
+func GitWorkers() (int, int) {
+	config := GetControllerConfig()
+	if config.GitWorkers < 1 {
+		return 0, 0
+	}
+
+	perChannel := config.GitWorkersPerChannel
+	if perChannel < 1 || perChannel > config.GitWorkers {
+		perChannel = config.GitWorkers
+	}
+
+	return config.GitWorkers, perChannel
+}
+
+// IsStatusVerbositySummary checks if the appsubstatus events summarize the resources instead of listing them all
+func IsStatusVerbositySummary() bool {
+	return GetControllerConfig().StatusVerbosity == StatusVerbositySummary
+}
+
+// ControllerConfigWatcher loads the controller config ConfigMap of the operator namespace when it changes, the
+// controllers read the new configuration on their next reconcile without a restart
+type ControllerConfigWatcher struct {
+	Client    kubernetes.Interface
+	Namespace string
+	Name      string
+}
+
+// NewControllerConfigWatcher returns the watcher of the subscription-controller-config ConfigMap of the namespace
+func NewControllerConfigWatcher(kubeClient kubernetes.Interface, namespace string) *ControllerConfigWatcher {
+	return &ControllerConfigWatcher{
+		Client:    kubeClient,
+		Namespace: namespace,
+		Name:      ControllerConfigMapName,
+	}
+}
+
+// Load reads the ConfigMap once, it is called before the controllers start so their first reconcile has the configuration
+func (w *ControllerConfigWatcher) Load(ctx context.Context) error {
+	cm, err := w.Client.CoreV1().ConfigMaps(w.Namespace).Get(ctx, w.Name, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		w.apply(nil)
+
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to get the controller config %v/%v, err: %w", w.Namespace, w.Name, err)
+	}
+
+	w.apply(cm)
+
+	return nil
+}
+
+// Start watches the ConfigMap until the context is done
+func (w *ControllerConfigWatcher) Start(ctx context.Context) error {
+	selector := fields.OneTermEqualSelector("metadata.name", w.Name).String()
+	lw := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = selector
+
+			return w.Client.CoreV1().ConfigMaps(w.Namespace).List(ctx, options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = selector
+
+			return w.Client.CoreV1().ConfigMaps(w.Namespace).Watch(ctx, options)
+		},
+	}
+
+	informer := cache.NewSharedInformer(lw, &corev1.ConfigMap{}, 0)
+
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			w.apply(obj.(*corev1.ConfigMap))
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			w.apply(obj.(*corev1.ConfigMap))
+		},
+		DeleteFunc: func(interface{}) {
+			w.apply(nil)
+		},
+	})
+
+	informer.Run(ctx.Done())
+
+	return nil
+}
+
+// NeedLeaderElection runs the watcher on all the replicas, the controllers of each replica read the configuration
+func (w *ControllerConfigWatcher) NeedLeaderElection() bool {
+	return false
+}
+
+// apply sets the configuration of the ConfigMap, the defaults if it is nil
+func (w *ControllerConfigWatcher) apply(cm *corev1.ConfigMap) {
+	config := ControllerConfig{}
+
+	if cm != nil {
+		var errs []error
+
+		config, errs = ParseControllerConfig(cm.Data)
+
+		for _, err := range errs {
+			klog.Warningf("ignore the invalid setting of the controller config %v/%v, err: %v", w.Namespace, w.Name, err)
+		}
+	}
+
+	if reflect.DeepEqual(config, GetControllerConfig()) {
+		return
+	}
+
+	SetControllerConfig(config)
+
+	klog.Infof("controller config %v/%v loaded: %+v, feature gates: %v", w.Namespace, w.Name, config, FeatureGateStates())
+}
@@ -0,0 +1,139 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+)
+
+func TestParseControllerConfig(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	config, errs := ParseControllerConfig(map[string]string{
+		ControllerConfigReconcileIntervalLow:    "2h",
+		ControllerConfigReconcileIntervalMedium: "10m",
+		ControllerConfigReconcileIntervalHigh:   "10s",
+		ControllerConfigGitCloneDepth:           "20",
+		ControllerConfigApplyConcurrency:        "4",
+		ControllerConfigGitWorkers:              "8",
+		ControllerConfigGitWorkersPerChannel:    "2",
+		ControllerConfigMaxManifestSize:         "512Mi",
+		ControllerConfigMaxManifestDocuments:    "500",
+		ControllerConfigStatusVerbosity:         "Summary",
+		ControllerConfigFeatureGates:            "ServerSideApply=true, Foo=false",
+	})
+
+	// the high interval is too short and the Foo gate is unknown, the other settings are kept
+	g.Expect(errs).To(HaveLen(2))
+	g.Expect(config.ReconcileIntervals).To(Equal(map[string]time.Duration{"low": 2 * time.Hour, "medium": 10 * time.Minute}))
+	g.Expect(config.GitCloneDepth).To(Equal(20))
+	g.Expect(config.ApplyConcurrency).To(Equal(4))
+	g.Expect(config.GitWorkers).To(Equal(8))
+	g.Expect(config.GitWorkersPerChannel).To(Equal(2))
+	g.Expect(config.MaxManifestSize).To(Equal(int64(512 << 20)))
+	g.Expect(config.MaxManifestDocuments).To(Equal(500))
+	g.Expect(config.StatusVerbosity).To(Equal(StatusVerbositySummary))
+	g.Expect(config.FeatureGates).To(Equal(map[string]bool{"ServerSideApply": true}))
+
+	config, errs = ParseControllerConfig(map[string]string{
+		ControllerConfigGitCloneDepth:    "-1",
+		ControllerConfigApplyConcurrency: "100",
+		ControllerConfigGitWorkers:       "0",
+		ControllerConfigMaxManifestSize:  "1Mi",
+		ControllerConfigStatusVerbosity:  "debug",
+		ControllerConfigFeatureGates:     "Foo",
+	})
+	g.Expect(errs).To(HaveLen(6))
+	g.Expect(config).To(Equal(ControllerConfig{}))
+}
+
+func TestControllerConfigDefaults(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	defer SetControllerConfig(ControllerConfig{})
+
+	g.Expect(DefaultGitCloneDepth(1)).To(Equal(1))
+	g.Expect(ApplyConcurrency()).To(Equal(1))
+	g.Expect(IsStatusVerbositySummary()).To(BeFalse())
+
+	workers, perChannel := GitWorkers()
+	g.Expect(workers).To(Equal(0))
+	g.Expect(perChannel).To(Equal(0))
+
+	SetControllerConfig(ControllerConfig{
+		ReconcileIntervals: map[string]time.Duration{"medium": 10 * time.Minute},
+		GitCloneDepth:      5,
+		ApplyConcurrency:   3,
+		GitWorkers:         4,
+		StatusVerbosity:    StatusVerbositySummary,
+	})
+
+	g.Expect(DefaultGitCloneDepth(1)).To(Equal(5))
+	g.Expect(ApplyConcurrency()).To(Equal(3))
+
+	workers, perChannel = GitWorkers()
+	g.Expect(workers).To(Equal(4))
+	g.Expect(perChannel).To(Equal(4))
+	g.Expect(IsStatusVerbositySummary()).To(BeTrue())
+
+	loopPeriod, retryInterval, _ := GetReconcileInterval("Medium", chnv1.ChannelTypeHelmRepo)
+	g.Expect(loopPeriod).To(Equal(10 * time.Minute))
+	g.Expect(retryInterval).To(Equal(90 * time.Second))
+
+	loopPeriod, _, _ = GetReconcileInterval("high", chnv1.ChannelTypeGit)
+	g.Expect(loopPeriod).To(Equal(2 * time.Minute))
+}
+
+func TestControllerConfigWatcher(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	defer SetControllerConfig(ControllerConfig{})
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ControllerConfigMapName, Namespace: "open-cluster-management"},
+		Data:       map[string]string{ControllerConfigApplyConcurrency: "2"},
+	}
+
+	kubeClient := fake.NewSimpleClientset(cm)
+	watcher := NewControllerConfigWatcher(kubeClient, "open-cluster-management")
+
+	g.Expect(watcher.Load(context.TODO())).To(Succeed())
+	g.Expect(ApplyConcurrency()).To(Equal(2))
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	go func() {
+		_ = watcher.Start(ctx)
+	}()
+
+	cm.Data[ControllerConfigApplyConcurrency] = "6"
+	_, err := kubeClient.CoreV1().ConfigMaps(cm.Namespace).Update(context.TODO(), cm, metav1.UpdateOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Eventually(ApplyConcurrency, 5*time.Second, 50*time.Millisecond).Should(Equal(6))
+
+	// the defaults are restored when the ConfigMap is deleted
+	g.Expect(kubeClient.CoreV1().ConfigMaps(cm.Namespace).Delete(context.TODO(), cm.Name, metav1.DeleteOptions{})).To(Succeed())
+	g.Eventually(ApplyConcurrency, 5*time.Second, 50*time.Millisecond).Should(Equal(1))
+}
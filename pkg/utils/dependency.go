@@ -0,0 +1,156 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appsubReportV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+)
+
+// GetSubscriptionDependencies returns the dependencies of the subscription, with the namespace and condition defaulted
+func GetSubscriptionDependencies(sub *appv1.Subscription) []appv1.SubscriptionDependency {
+	deps := []appv1.SubscriptionDependency{}
+
+	for _, dep := range sub.Spec.DependsOn {
+		if dep.Namespace == "" {
+			dep.Namespace = sub.GetNamespace()
+		}
+
+		if dep.Condition == "" {
+			dep.Condition = appv1.DependencyDeployed
+		}
+
+		if dep.Name == sub.GetName() && dep.Namespace == sub.GetNamespace() {
+			klog.Warningf("subscription %v/%v depends on itself, ignore the dependency", dep.Namespace, dep.Name)
+
+			continue
+		}
+
+		deps = append(deps, dep)
+	}
+
+	return deps
+}
+
+// AppsubStatusKey returns the key of the appsubstatus of the subscription on the managed cluster
+func AppsubStatusKey(key types.NamespacedName) types.NamespacedName {
+	return types.NamespacedName{Name: strings.TrimSuffix(key.Name, "-local"), Namespace: key.Namespace}
+}
+
+// IsSubscriptionReady checks if the subscription is subscribed and all the resources in its appsubstatus are
+// deployed. For the Healthy condition, the deployed resources must also be healthy.
+// The reason is returned if the subscription is not ready yet
+func IsSubscriptionReady(c client.Reader, key types.NamespacedName, condition appv1.DependencyCondition) (bool, string, error) {
+	sub := &appv1.Subscription{}
+
+	if err := c.Get(context.TODO(), key, sub); err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, fmt.Sprintf("subscription %v is not found", key.String()), nil
+		}
+
+		return false, "", err
+	}
+
+	if sub.Status.Phase != appv1.SubscriptionSubscribed {
+		return false, fmt.Sprintf("subscription %v is not subscribed", key.String()), nil
+	}
+
+	appsubStatus := &appsubReportV1alpha1.SubscriptionStatus{}
+
+	if err := c.Get(context.TODO(), AppsubStatusKey(key), appsubStatus); err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, fmt.Sprintf("subscription %v has no deployed resources yet", key.String()), nil
+		}
+
+		return false, "", err
+	}
+
+	if len(appsubStatus.Statuses.SubscriptionStatus) == 0 {
+		return false, fmt.Sprintf("subscription %v has no deployed resources yet", key.String()), nil
+	}
+
+	for _, res := range appsubStatus.Statuses.SubscriptionStatus {
+		if res.Phase != appsubReportV1alpha1.PackageDeployed {
+			return false, fmt.Sprintf("resource %v %v/%v of subscription %v is not deployed", res.Kind, res.Namespace, res.Name,
+				key.String()), nil
+		}
+	}
+
+	if condition != appv1.DependencyHealthy {
+		return true, "", nil
+	}
+
+	for _, res := range appsubStatus.Statuses.SubscriptionStatus {
+		gv, err := schema.ParseGroupVersion(res.APIVersion)
+		if err != nil {
+			klog.Warningf("skip checking the health of resource %v %v/%v, err: %v", res.Kind, res.Namespace, res.Name, err)
+
+			continue
+		}
+
+		healthy, reason, err := IsResourceHealthy(c, gv.WithKind(res.Kind), types.NamespacedName{Name: res.Name, Namespace: res.Namespace})
+		if err != nil {
+			return false, "", err
+		}
+
+		if !healthy {
+			return false, fmt.Sprintf("resource %v %v/%v of subscription %v is not healthy: %v", res.Kind, res.Namespace, res.Name,
+				key.String(), reason), nil
+		}
+	}
+
+	return true, "", nil
+}
+
+// GetBlockingDependency returns the first subscription dependency that is not ready, nil if all the dependencies are ready
+func GetBlockingDependency(c client.Reader, sub *appv1.Subscription) (*types.NamespacedName, string, error) {
+	for _, dep := range GetSubscriptionDependencies(sub) {
+		key := types.NamespacedName{Name: dep.Name, Namespace: dep.Namespace}
+
+		ready, reason, err := IsSubscriptionReady(c, key, dep.Condition)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if !ready {
+			return &key, reason, nil
+		}
+	}
+
+	return nil, "", nil
+}
+
+// DependsOnSubscription checks if the subscription depends on the subscription of the key
+func DependsOnSubscription(sub *appv1.Subscription, key types.NamespacedName) bool {
+	for _, dep := range GetSubscriptionDependencies(sub) {
+		depKey := types.NamespacedName{Name: dep.Name, Namespace: dep.Namespace}
+
+		if depKey == key || AppsubStatusKey(depKey) == key {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,187 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	appsubReportV1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
+)
+
+func TestGetBlockingDependency(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(appv1.SchemeBuilder.AddToScheme(scheme)).To(Succeed())
+	g.Expect(appsubReportV1alpha1.SchemeBuilder.AddToScheme(scheme)).To(Succeed())
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "ns"},
+		Spec: appv1.SubscriptionSpec{
+			DependsOn: []appv1.SubscriptionDependency{
+				{Name: "app"},
+				{Name: "crds"},
+				{Name: "cert-manager-local", Namespace: "infra"},
+			},
+		},
+	}
+
+	g.Expect(GetSubscriptionDependencies(sub)).To(Equal([]appv1.SubscriptionDependency{
+		{Name: "crds", Namespace: "ns", Condition: appv1.DependencyDeployed},
+		{Name: "cert-manager-local", Namespace: "infra", Condition: appv1.DependencyDeployed},
+	}))
+	g.Expect(DependsOnSubscription(sub, types.NamespacedName{Name: "cert-manager", Namespace: "infra"})).To(BeTrue())
+	g.Expect(DependsOnSubscription(sub, types.NamespacedName{Name: "app", Namespace: "ns"})).To(BeFalse())
+
+	crds := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "crds", Namespace: "ns"},
+		Status:     appv1.SubscriptionStatus{Phase: appv1.SubscriptionSubscribed},
+	}
+	crdsStatus := &appsubReportV1alpha1.SubscriptionStatus{
+		ObjectMeta: metav1.ObjectMeta{Name: "crds", Namespace: "ns"},
+		Statuses: appsubReportV1alpha1.SubscriptionClusterStatusMap{
+			SubscriptionStatus: []appsubReportV1alpha1.SubscriptionUnitStatus{
+				{Kind: "CustomResourceDefinition", Name: "certificates.cert-manager.io", Phase: appsubReportV1alpha1.PackageDeployed},
+			},
+		},
+	}
+	certManager := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "cert-manager-local", Namespace: "infra"},
+		Status:     appv1.SubscriptionStatus{Phase: appv1.SubscriptionSubscribed},
+	}
+	certManagerStatus := &appsubReportV1alpha1.SubscriptionStatus{
+		ObjectMeta: metav1.ObjectMeta{Name: "cert-manager", Namespace: "infra"},
+		Statuses: appsubReportV1alpha1.SubscriptionClusterStatusMap{
+			SubscriptionStatus: []appsubReportV1alpha1.SubscriptionUnitStatus{
+				{Kind: "Deployment", Namespace: "infra", Name: "cert-manager", Phase: appsubReportV1alpha1.PackageDeployFailed},
+			},
+		},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(crds).Build()
+
+	dep, reason, err := GetBlockingDependency(c, sub)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(*dep).To(Equal(types.NamespacedName{Name: "crds", Namespace: "ns"}))
+	g.Expect(reason).To(ContainSubstring("no deployed resources"))
+
+	c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(crds, crdsStatus, certManager, certManagerStatus).Build()
+
+	dep, reason, err = GetBlockingDependency(c, sub)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(*dep).To(Equal(types.NamespacedName{Name: "cert-manager-local", Namespace: "infra"}))
+	g.Expect(reason).To(ContainSubstring("Deployment infra/cert-manager"))
+
+	certManagerStatus.Statuses.SubscriptionStatus[0].Phase = appsubReportV1alpha1.PackageDeployed
+	c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(crds, crdsStatus, certManager, certManagerStatus).Build()
+
+	dep, _, err = GetBlockingDependency(c, sub)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(dep).To(BeNil())
+}
+
+func TestHealthyDependency(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(appv1.SchemeBuilder.AddToScheme(scheme)).To(Succeed())
+	g.Expect(appsubReportV1alpha1.SchemeBuilder.AddToScheme(scheme)).To(Succeed())
+	g.Expect(appsv1.AddToScheme(scheme)).To(Succeed())
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "ns"},
+		Spec: appv1.SubscriptionSpec{
+			DependsOn: []appv1.SubscriptionDependency{{Name: "operator", Condition: appv1.DependencyHealthy}},
+		},
+	}
+	operator := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "operator", Namespace: "ns"},
+		Status:     appv1.SubscriptionStatus{Phase: appv1.SubscriptionSubscribed},
+	}
+	operatorStatus := &appsubReportV1alpha1.SubscriptionStatus{
+		ObjectMeta: metav1.ObjectMeta{Name: "operator", Namespace: "ns"},
+		Statuses: appsubReportV1alpha1.SubscriptionClusterStatusMap{
+			SubscriptionStatus: []appsubReportV1alpha1.SubscriptionUnitStatus{
+				{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "ns", Name: "operator", Phase: appsubReportV1alpha1.PackageDeployed},
+			},
+		},
+	}
+
+	replicas := int32(2)
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "operator", Namespace: "ns", Generation: 3},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status:     appsv1.DeploymentStatus{ObservedGeneration: 3, UpdatedReplicas: 2, AvailableReplicas: 1},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(operator, operatorStatus, deploy).Build()
+
+	dep, reason, err := GetBlockingDependency(c, sub)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(dep).NotTo(BeNil())
+	g.Expect(reason).To(ContainSubstring("is not healthy: 2 of 2 replicas are updated and 1 are available"))
+
+	deploy.Status.AvailableReplicas = 2
+	c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(operator, operatorStatus, deploy).Build()
+
+	dep, _, err = GetBlockingDependency(c, sub)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(dep).To(BeNil())
+}
+
+func TestIsObjectHealthy(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	job := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "batch/v1",
+		"kind":       "Job",
+		"status": map[string]interface{}{
+			"conditions": []interface{}{map[string]interface{}{"type": "Complete", "status": "True"}},
+		},
+	}}
+
+	healthy, _ := isObjectHealthy(job)
+	g.Expect(healthy).To(BeTrue())
+
+	ds := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "DaemonSet",
+		"metadata":   map[string]interface{}{"generation": int64(2)},
+		"status": map[string]interface{}{
+			"observedGeneration":     int64(1),
+			"desiredNumberScheduled": int64(3),
+			"updatedNumberScheduled": int64(3),
+			"numberReady":            int64(3),
+		},
+	}}
+
+	healthy, reason := isObjectHealthy(ds)
+	g.Expect(healthy).To(BeFalse())
+	g.Expect(reason).To(Equal("the latest spec is not observed yet"))
+
+	cm := &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap"}}
+
+	healthy, _ = isObjectHealthy(cm)
+	g.Expect(healthy).To(BeTrue())
+}
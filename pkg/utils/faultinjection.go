@@ -0,0 +1,184 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/metrics"
+)
+
+// the faults injected by the agent, the values of the fault label of the agent_fault_injections_total metric
+const (
+	FaultApplyFailure  = "apply_failure"
+	FaultHubWriteDelay = "hub_write_delay"
+	FaultStatusDrop    = "status_drop"
+)
+
+// FaultInjection configures the faults the agent injects in its synchronizer for the resilience tests, like the soak
+// tests of the alerting. No fault is injected by default
+type FaultInjection struct {
+	// ApplyFailureRate is the probability, from 0 to 1, of a resource apply failing with a retriable error
+	ApplyFailureRate float64
+	// HubWriteDelay delays the writes of the cluster SubscriptionReports to the hub
+	HubWriteDelay time.Duration
+	// StatusDropRate is the probability, from 0 to 1, of a status sync of a subscription being dropped
+	StatusDropRate float64
+	// Namespaces limits the faults to the subscriptions of the namespaces, all the namespaces if empty
+	Namespaces []string
+}
+
+var (
+	faultInjection     FaultInjection
+	faultInjectionLock sync.RWMutex
+
+	// faultRandom returns the random numbers of the injected faults, in [0, 1)
+	faultRandom     = rand.New(rand.NewSource(time.Now().UnixNano())).Float64 // #nosec G404 not a security use
+	faultRandomLock sync.Mutex
+)
+
+// Validate checks the rates are probabilities and the delay isn't negative
+func (f FaultInjection) Validate() error {
+	if f.ApplyFailureRate < 0 || f.ApplyFailureRate > 1 {
+		return fmt.Errorf("the apply failure rate %v is not between 0 and 1", f.ApplyFailureRate)
+	}
+
+	if f.StatusDropRate < 0 || f.StatusDropRate > 1 {
+		return fmt.Errorf("the status drop rate %v is not between 0 and 1", f.StatusDropRate)
+	}
+
+	if f.HubWriteDelay < 0 {
+		return fmt.Errorf("the hub write delay %v is negative", f.HubWriteDelay)
+	}
+
+	return nil
+}
+
+// Enabled checks if any fault is injected
+func (f FaultInjection) Enabled() bool {
+	return f.ApplyFailureRate > 0 || f.StatusDropRate > 0 || f.HubWriteDelay > 0
+}
+
+func (f FaultInjection) inScope(namespace string) bool {
+	if len(f.Namespaces) == 0 {
+		return true
+	}
+
+	for _, ns := range f.Namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetFaultInjection sets the faults of the agent, it is called once before the controllers are set up
+func SetFaultInjection(f FaultInjection) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+
+	faultInjectionLock.Lock()
+	defer faultInjectionLock.Unlock()
+
+	faultInjection = f
+
+	if f.Enabled() {
+		klog.Warningf("fault injection enabled, apply failure rate: %v, hub write delay: %v, status drop rate: %v, "+
+			"namespaces: %v", f.ApplyFailureRate, f.HubWriteDelay, f.StatusDropRate, f.Namespaces)
+	}
+
+	return nil
+}
+
+// GetFaultInjection returns the faults of the agent
+func GetFaultInjection() FaultInjection {
+	faultInjectionLock.RLock()
+	defer faultInjectionLock.RUnlock()
+
+	return faultInjection
+}
+
+func injectFault(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+
+	faultRandomLock.Lock()
+	defer faultRandomLock.Unlock()
+
+	return faultRandom() < rate
+}
+
+func recordFault(fault string) {
+	metrics.FaultInjectionsTotal.WithLabelValues(fault).Inc()
+}
+
+// InjectApplyFailure returns a service unavailable error at the apply failure rate, the apply of the resource of the
+// subscription namespace fails like on an overloaded API server and is retried with the apply retries
+func InjectApplyFailure(namespace string, resource *unstructured.Unstructured) error {
+	f := GetFaultInjection()
+
+	if !f.inScope(namespace) || !injectFault(f.ApplyFailureRate) {
+		return nil
+	}
+
+	recordFault(FaultApplyFailure)
+
+	klog.Warningf("fault injection: failing the apply of %v %v/%v", resource.GetKind(), resource.GetNamespace(),
+		resource.GetName())
+
+	return errors.NewServiceUnavailable(fmt.Sprintf("fault injection: the apply of %v %v/%v failed",
+		resource.GetKind(), resource.GetNamespace(), resource.GetName()))
+}
+
+// DelayHubWrite waits for the hub write delay before the write of the result of the subscription namespace to the hub
+func DelayHubWrite(namespace string) {
+	f := GetFaultInjection()
+
+	if f.HubWriteDelay <= 0 || !f.inScope(namespace) {
+		return
+	}
+
+	recordFault(FaultHubWriteDelay)
+
+	klog.Warningf("fault injection: delaying the hub write of the subscriptions of %v by %v", namespace, f.HubWriteDelay)
+
+	time.Sleep(f.HubWriteDelay)
+}
+
+// DropStatusUpdate checks if the status sync of the subscription is dropped at the status drop rate, the status of
+// the subscription and its result on the hub are not updated until its next sync
+func DropStatusUpdate(namespace, name string) bool {
+	f := GetFaultInjection()
+
+	if !f.inScope(namespace) || !injectFault(f.StatusDropRate) {
+		return false
+	}
+
+	recordFault(FaultStatusDrop)
+
+	klog.Warningf("fault injection: dropping the status update of subscription %v/%v", namespace, name)
+
+	return true
+}
@@ -0,0 +1,79 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestFaultInjection(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	random := faultRandom
+
+	defer func() {
+		faultRandom = random
+
+		g.Expect(SetFaultInjection(FaultInjection{})).To(Succeed())
+	}()
+
+	next := 0.5
+	faultRandom = func() float64 { return next }
+
+	resource := &unstructured.Unstructured{}
+	resource.SetKind("ConfigMap")
+	resource.SetNamespace("app")
+	resource.SetName("cm")
+
+	// no fault is injected by default
+	g.Expect(GetFaultInjection().Enabled()).To(BeFalse())
+	g.Expect(InjectApplyFailure("soak", resource)).To(Succeed())
+	g.Expect(DropStatusUpdate("soak", "demo")).To(BeFalse())
+
+	g.Expect(SetFaultInjection(FaultInjection{ApplyFailureRate: 1.5})).NotTo(Succeed())
+	g.Expect(SetFaultInjection(FaultInjection{StatusDropRate: -1})).NotTo(Succeed())
+	g.Expect(SetFaultInjection(FaultInjection{HubWriteDelay: -time.Second})).NotTo(Succeed())
+
+	g.Expect(SetFaultInjection(FaultInjection{
+		ApplyFailureRate: 0.6,
+		StatusDropRate:   0.4,
+		HubWriteDelay:    time.Millisecond,
+		Namespaces:       []string{"soak"},
+	})).To(Succeed())
+	g.Expect(GetFaultInjection().Enabled()).To(BeTrue())
+
+	// the faults are injected below their rate, in the namespaces of the scope only
+	err := InjectApplyFailure("soak", resource)
+	g.Expect(errors.IsServiceUnavailable(err)).To(BeTrue())
+	g.Expect(err.Error()).To(ContainSubstring("fault injection: the apply of ConfigMap app/cm failed"))
+	g.Expect(InjectApplyFailure("prod", resource)).To(Succeed())
+
+	g.Expect(DropStatusUpdate("soak", "demo")).To(BeFalse())
+
+	next = 0.1
+
+	g.Expect(DropStatusUpdate("soak", "demo")).To(BeTrue())
+	g.Expect(DropStatusUpdate("prod", "demo")).To(BeFalse())
+
+	start := time.Now()
+
+	DelayHubWrite("soak")
+	g.Expect(time.Since(start)).To(BeNumerically(">=", time.Millisecond))
+}
@@ -0,0 +1,145 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FeatureGate is the name of an experimental capability of the controllers, it ships disabled until it graduates
+type FeatureGate string
+
+const (
+	// ServerSideApply updates the existing resources of the subscriptions on the managed clusters with a server-side
+	// apply instead of a three-way merge patch
+	ServerSideApply FeatureGate = "ServerSideApply"
+)
+
+const (
+	FeatureAlpha = "Alpha"
+	FeatureBeta  = "Beta"
+)
+
+// featureGateSpec is the default state and the maturity of a feature gate
+type featureGateSpec struct {
+	Default    bool
+	PreRelease string
+}
+
+// knownFeatureGates are the feature gates of the controllers, a new capability adds its gate here
+var knownFeatureGates = map[FeatureGate]featureGateSpec{
+	ServerSideApply: {Default: false, PreRelease: FeatureAlpha},
+}
+
+var (
+	featureGatesLock        sync.RWMutex
+	commandLineFeatureGates map[FeatureGate]bool
+)
+
+// SetFeatureGates sets the feature gates of the --feature-gates flag, <gate>=<true|false> pairs separated by commas
+func SetFeatureGates(v string) error {
+	gates, err := parseFeatureGates(v)
+	if err != nil {
+		return err
+	}
+
+	known, err := knownGates(gates)
+	if err != nil {
+		return err
+	}
+
+	featureGatesLock.Lock()
+	defer featureGatesLock.Unlock()
+
+	commandLineFeatureGates = known
+
+	return nil
+}
+
+// knownGates returns the known feature gates of the parsed gates, it fails on the unknown gates
+func knownGates(gates map[string]bool) (map[FeatureGate]bool, error) {
+	known := map[FeatureGate]bool{}
+	unknown := []string{}
+
+	for name, enabled := range gates {
+		if _, ok := knownFeatureGates[FeatureGate(name)]; !ok {
+			unknown = append(unknown, name)
+
+			continue
+		}
+
+		known[FeatureGate(name)] = enabled
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+
+		return known, fmt.Errorf("unknown feature gates %v", strings.Join(unknown, ","))
+	}
+
+	return known, nil
+}
+
+// FeatureEnabled checks if a feature gate is enabled. The feature gates of the controller config take precedence over
+// the feature gates of the command line, the gates set in neither have their default state
+func FeatureEnabled(gate FeatureGate) bool {
+	spec, ok := knownFeatureGates[gate]
+	if !ok {
+		return false
+	}
+
+	if enabled, ok := GetControllerConfig().FeatureGates[string(gate)]; ok {
+		return enabled
+	}
+
+	featureGatesLock.RLock()
+	defer featureGatesLock.RUnlock()
+
+	if enabled, ok := commandLineFeatureGates[gate]; ok {
+		return enabled
+	}
+
+	return spec.Default
+}
+
+// FeatureGateStates returns the state of all the feature gates, e.g. ServerSideApply=false
+func FeatureGateStates() string {
+	states := []string{}
+
+	for gate := range knownFeatureGates {
+		states = append(states, fmt.Sprintf("%v=%v", gate, FeatureEnabled(gate)))
+	}
+
+	sort.Strings(states)
+
+	return strings.Join(states, ",")
+}
+
+// FeatureGatesUsage returns the help of the --feature-gates flag listing the feature gates
+func FeatureGatesUsage() string {
+	gates := []string{}
+
+	for gate, spec := range knownFeatureGates {
+		gates = append(gates, fmt.Sprintf("%v=true|false (%v - default=%v)", gate, spec.PreRelease, spec.Default))
+	}
+
+	sort.Strings(gates)
+
+	return "The feature gates of the experimental capabilities, <gate>=<true|false> pairs separated by commas. The " +
+		"featureGates of the subscription-controller-config ConfigMap take precedence. Options are: " + strings.Join(gates, ", ")
+}
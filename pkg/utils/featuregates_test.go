@@ -0,0 +1,50 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestFeatureGates(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	defer func() {
+		g.Expect(SetFeatureGates("")).To(Succeed())
+		SetControllerConfig(ControllerConfig{})
+	}()
+
+	// the feature gates are disabled by default
+	g.Expect(FeatureEnabled(ServerSideApply)).To(BeFalse())
+	g.Expect(FeatureEnabled(FeatureGate("Unknown"))).To(BeFalse())
+	g.Expect(FeatureGateStates()).To(Equal("ServerSideApply=false"))
+
+	g.Expect(SetFeatureGates("Unknown=true")).NotTo(Succeed())
+	g.Expect(SetFeatureGates("ServerSideApply")).NotTo(Succeed())
+
+	g.Expect(SetFeatureGates("ServerSideApply=true")).To(Succeed())
+	g.Expect(FeatureEnabled(ServerSideApply)).To(BeTrue())
+
+	// the controller config takes precedence over the command line
+	SetControllerConfig(ControllerConfig{FeatureGates: map[string]bool{string(ServerSideApply): false}})
+	g.Expect(FeatureEnabled(ServerSideApply)).To(BeFalse())
+
+	SetControllerConfig(ControllerConfig{})
+	g.Expect(FeatureEnabled(ServerSideApply)).To(BeTrue())
+
+	g.Expect(FeatureGatesUsage()).To(ContainSubstring("ServerSideApply=true|false (Alpha - default=false)"))
+}
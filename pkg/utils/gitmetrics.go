@@ -0,0 +1,187 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v42/github"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/metrics"
+)
+
+const (
+	// GitFetchSucceeded is the result of a successful git clone
+	GitFetchSucceeded = "Succeeded"
+	// GitFetchFailed is the result of a failed git clone
+	GitFetchFailed = "Failed"
+	// GitFetchRateLimited is the result of a git clone rejected by the rate limit of the git provider
+	GitFetchRateLimited = "RateLimited"
+
+	gitProviderOther = "other"
+)
+
+// GitFetchStatus is the outcome of the last git clone of a channel, in the lastGitFetch status of the channel
+type GitFetchStatus struct {
+	Time     metav1.Time `json:"time"`
+	Result   string      `json:"result"`
+	Commit   string      `json:"commit,omitempty"`
+	Duration string      `json:"duration"`
+	Message  string      `json:"message,omitempty"`
+}
+
+// NewGitFetchStatus returns the outcome of a git clone started at start
+func NewGitFetchStatus(repoURL string, start time.Time, commit string, err error) GitFetchStatus {
+	status := GitFetchStatus{
+		Time:     metav1.NewTime(time.Now()),
+		Result:   GitFetchSucceeded,
+		Commit:   commit,
+		Duration: time.Since(start).Round(time.Millisecond).String(),
+	}
+
+	if err != nil {
+		status.Result = GitFetchFailed
+		status.Message = err.Error()
+
+		if _, limited := GitRateLimitProvider(repoURL, err); limited {
+			status.Result = GitFetchRateLimited
+		}
+	}
+
+	return status
+}
+
+// SetChannelGitFetchStatus sets the lastGitFetch status of the channel. The v1 channels have no status subresource and
+// the status changes don't trigger the subscription reconciles
+func SetChannelGitFetchStatus(clt client.Client, channel types.NamespacedName, status GitFetchStatus) error {
+	if channel.Name == "" {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{"status": map[string]interface{}{"lastGitFetch": status}})
+	if err != nil {
+		return err
+	}
+
+	// the status isn't typed in the v1 channel API
+	chn := &unstructured.Unstructured{}
+	chn.SetGroupVersionKind(chnv1.SchemeGroupVersion.WithKind("Channel"))
+	chn.SetName(channel.Name)
+	chn.SetNamespace(channel.Namespace)
+
+	return client.IgnoreNotFound(clt.Patch(context.TODO(), chn, client.RawPatch(types.MergePatchType, patch)))
+}
+
+// GitRateLimitProvider checks if the git error is a rate-limit response of the git provider, the 429 responses and the
+// 403 responses of the GitHub and GitLab rate limits. The provider is github, gitlab or other from the repo URL, it is
+// also returned for a nil error
+func GitRateLimitProvider(repoURL string, err error) (string, bool) {
+	provider := gitProviderOther
+
+	switch lowerURL := strings.ToLower(repoURL); {
+	case strings.Contains(lowerURL, GitProviderGitHub):
+		provider = GitProviderGitHub
+	case strings.Contains(lowerURL, GitProviderGitLab):
+		provider = GitProviderGitLab
+	}
+
+	if err == nil {
+		return provider, false
+	}
+
+	var gitRateLimitErr *GitRateLimitError
+
+	var rateLimitErr *github.RateLimitError
+
+	var abuseRateLimitErr *github.AbuseRateLimitError
+
+	if errors.As(err, &gitRateLimitErr) || errors.As(err, &rateLimitErr) || errors.As(err, &abuseRateLimitErr) {
+		return provider, true
+	}
+
+	msg := strings.ToLower(err.Error())
+
+	for _, s := range []string{"429", "too many requests", "rate limit"} {
+		if strings.Contains(msg, s) {
+			return provider, true
+		}
+	}
+
+	return provider, false
+}
+
+// recordGitClone records the git metrics of the channel of the clone
+func recordGitClone(cloneOptions *GitCloneOption, start time.Time, err error) {
+	ns, name := cloneOptions.Channel.Namespace, cloneOptions.Channel.Name
+
+	metrics.GitCloneDuration.WithLabelValues(ns, name).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		metrics.GitCloneTotal.WithLabelValues(ns, name, GitFetchFailed).Inc()
+		metrics.GitFetchErrorsTotal.WithLabelValues(ns, name).Inc()
+
+		if provider, limited := GitRateLimitProvider(gitCloneRepoURL(cloneOptions), err); limited {
+			metrics.GitRateLimitedTotal.WithLabelValues(ns, name, provider).Inc()
+		}
+
+		return
+	}
+
+	metrics.GitCloneTotal.WithLabelValues(ns, name, GitFetchSucceeded).Inc()
+
+	size, sizeErr := dirSize(cloneOptions.DestDir)
+	if sizeErr != nil {
+		klog.Warningf("failed to get the size of the git repo %v, err: %v", cloneOptions.DestDir, sizeErr)
+
+		return
+	}
+
+	metrics.GitRepoSize.WithLabelValues(ns, name).Set(float64(size))
+}
+
+func dirSize(dir string) (int64, error) {
+	var size int64
+
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+
+			size += info.Size()
+		}
+
+		return nil
+	})
+
+	return size, err
+}
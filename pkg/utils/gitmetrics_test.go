@@ -0,0 +1,106 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v42/github"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/metrics"
+)
+
+func TestGitRateLimitProvider(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	provider, limited := GitRateLimitProvider("https://github.com/org/repo.git", &github.RateLimitError{Message: "API rate limit exceeded"})
+	g.Expect(limited).To(BeTrue())
+	g.Expect(provider).To(Equal(GitProviderGitHub))
+
+	provider, limited = GitRateLimitProvider("https://gitlab.example.com/org/repo.git",
+		errors.New("Failed to clone git: unexpected client error: unexpected requesting status code: 429"))
+	g.Expect(limited).To(BeTrue())
+	g.Expect(provider).To(Equal(GitProviderGitLab))
+
+	_, limited = GitRateLimitProvider("https://git.example.com/org/repo.git", errors.New("authentication required"))
+	g.Expect(limited).To(BeFalse())
+
+	_, limited = GitRateLimitProvider("https://github.com/org/repo.git", nil)
+	g.Expect(limited).To(BeFalse())
+}
+
+func TestRecordGitClone(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	dir := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(dir, "deployment.yaml"), make([]byte, 100), 0600)).To(Succeed())
+	g.Expect(os.MkdirAll(filepath.Join(dir, ".git"), 0700)).To(Succeed())
+	g.Expect(os.WriteFile(filepath.Join(dir, ".git", "HEAD"), make([]byte, 20), 0600)).To(Succeed())
+
+	cloneOptions := &GitCloneOption{
+		DestDir:                 dir,
+		Channel:                 types.NamespacedName{Namespace: "ch-ns", Name: "git-metrics"},
+		PrimaryConnectionOption: &ChannelConnectionCfg{RepoURL: "https://github.com/org/repo.git"},
+	}
+
+	recordGitClone(cloneOptions, time.Now(), nil)
+	recordGitClone(cloneOptions, time.Now(), errors.New("API rate limit exceeded"))
+
+	g.Expect(testutil.ToFloat64(metrics.GitCloneTotal.WithLabelValues("ch-ns", "git-metrics", GitFetchSucceeded))).To(Equal(float64(1)))
+	g.Expect(testutil.ToFloat64(metrics.GitCloneTotal.WithLabelValues("ch-ns", "git-metrics", GitFetchFailed))).To(Equal(float64(1)))
+	g.Expect(testutil.ToFloat64(metrics.GitFetchErrorsTotal.WithLabelValues("ch-ns", "git-metrics"))).To(Equal(float64(1)))
+	g.Expect(testutil.ToFloat64(metrics.GitRateLimitedTotal.WithLabelValues("ch-ns", "git-metrics", GitProviderGitHub))).To(Equal(float64(1)))
+	g.Expect(testutil.ToFloat64(metrics.GitRepoSize.WithLabelValues("ch-ns", "git-metrics"))).To(Equal(float64(120)))
+}
+
+func TestSetChannelGitFetchStatus(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// the channel is unstructured, the status isn't typed in the v1 channel API
+	chn := &unstructured.Unstructured{}
+	chn.SetGroupVersionKind(chnv1.SchemeGroupVersion.WithKind("Channel"))
+	chn.SetName("git")
+	chn.SetNamespace("ch-ns")
+
+	clt := fake.NewClientBuilder().WithScheme(runtime.NewScheme()).WithObjects(chn).Build()
+	key := types.NamespacedName{Namespace: "ch-ns", Name: "git"}
+
+	status := NewGitFetchStatus("https://github.com/org/repo.git", time.Now(), "", errors.New("status code: 429"))
+	g.Expect(status.Result).To(Equal(GitFetchRateLimited))
+
+	g.Expect(SetChannelGitFetchStatus(clt, key, status)).To(Succeed())
+
+	// the missing channels are ignored
+	g.Expect(SetChannelGitFetchStatus(clt, types.NamespacedName{Namespace: "ch-ns", Name: "missing"}, status)).To(Succeed())
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(chnv1.SchemeGroupVersion.WithKind("Channel"))
+	g.Expect(clt.Get(context.TODO(), key, obj)).To(Succeed())
+
+	result, _, _ := unstructured.NestedString(obj.Object, "status", "lastGitFetch", "result")
+	g.Expect(result).To(Equal(GitFetchRateLimited))
+}
@@ -0,0 +1,136 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+// GetGitMirrors returns the mirror remotes of the git-mirrors annotation of a git channel
+func GetGitMirrors(chn *chnv1.Channel) ([]appv1.GitMirror, error) {
+	annotation := chn.GetAnnotations()[appv1.AnnotationGitMirrors]
+	if annotation == "" || !IsGitChannel(string(chn.Spec.Type)) {
+		return nil, nil
+	}
+
+	mirrors := []appv1.GitMirror{}
+	if err := json.Unmarshal([]byte(annotation), &mirrors); err != nil {
+		return nil, fmt.Errorf("invalid %v annotation of channel %v/%v: %w", appv1.AnnotationGitMirrors,
+			chn.Namespace, chn.Name, err)
+	}
+
+	for _, mirror := range mirrors {
+		if strings.TrimSpace(mirror.URL) == "" {
+			return nil, fmt.Errorf("mirror without URL in the %v annotation of channel %v/%v",
+				appv1.AnnotationGitMirrors, chn.Namespace, chn.Name)
+		}
+	}
+
+	return mirrors, nil
+}
+
+// FetchGitMirrorSecrets retrieves the secrets of the mirror remotes of the channel in the namespace of the channel,
+// by mirror URL. The invalid annotation and the secrets not found are logged and left out.
+func FetchGitMirrorSecrets(clt client.Client, chn *chnv1.Channel) map[string]*corev1.Secret {
+	secrets := map[string]*corev1.Secret{}
+
+	mirrors, err := GetGitMirrors(chn)
+	if err != nil {
+		klog.Errorf("ignore the git mirrors, err: %v", err)
+
+		return secrets
+	}
+
+	for _, mirror := range mirrors {
+		if mirror.SecretRef == nil || mirror.SecretRef.Name == "" {
+			continue
+		}
+
+		secret := &corev1.Secret{}
+		key := types.NamespacedName{Namespace: chn.Namespace, Name: mirror.SecretRef.Name}
+
+		if err := clt.Get(context.TODO(), key, secret); err != nil {
+			klog.Warningf("failed to get secret %v of git mirror %v, err: %v", key, mirror.URL, err)
+
+			continue
+		}
+
+		secrets[mirror.URL] = secret
+	}
+
+	return secrets
+}
+
+// GetGitMirrorConnectionConfigs returns the connection configs of the mirror remotes of the channel, with the
+// credentials of their secret and the CA certificates and the insecureSkipVerify of the channel. The mirrors
+// with a secret not found are left out, so they are not cloned without their credentials
+func GetGitMirrorConnectionConfigs(chn *chnv1.Channel, secrets map[string]*corev1.Secret,
+	configMap *corev1.ConfigMap) []*ChannelConnectionCfg {
+	mirrors, err := GetGitMirrors(chn)
+	if err != nil {
+		klog.Errorf("ignore the git mirrors, err: %v", err)
+
+		return nil
+	}
+
+	var configs []*ChannelConnectionCfg
+
+	for _, mirror := range mirrors {
+		config := &ChannelConnectionCfg{RepoURL: mirror.URL, InsecureSkipVerify: chn.Spec.InsecureSkipVerify}
+
+		if configMap != nil {
+			config.CaCerts = configMap.Data[appv1.ChannelCertificateData]
+		}
+
+		if mirror.SecretRef != nil && mirror.SecretRef.Name != "" {
+			secret := secrets[mirror.URL]
+			if secret == nil {
+				klog.Warningf("skip git mirror %v of channel %v/%v, secret %v not found", mirror.URL,
+					chn.Namespace, chn.Name, mirror.SecretRef.Name)
+
+				continue
+			}
+
+			user, token, sshKey, passphrase, clientKey, clientCert, err := ParseChannelSecret(secret)
+			if err != nil {
+				klog.Warningf("skip git mirror %v of channel %v/%v, err: %v", mirror.URL, chn.Namespace, chn.Name, err)
+
+				continue
+			}
+
+			config.User = user
+			config.Password = token
+			config.SSHKey = sshKey
+			config.Passphrase = passphrase
+			config.ClientKey = clientKey
+			config.ClientCert = clientCert
+		}
+
+		configs = append(configs, config)
+	}
+
+	return configs
+}
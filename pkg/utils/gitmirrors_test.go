@@ -0,0 +1,105 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/testharness"
+)
+
+func TestGitMirrors(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	mirror, err := testharness.NewGitServer(map[string]string{"app/configmap.yaml": "kind: ConfigMap"})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	defer mirror.Close()
+
+	// the primary remote is down
+	down := httptest.NewServer(nil)
+	down.Close()
+
+	chn := &chnv1.Channel{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "git",
+			Namespace: "ch-ns",
+			Annotations: map[string]string{
+				appv1.AnnotationGitMirrors: `[{"url": "https://internal.example.com/app.git", "secretRef": {"name": "missing"}},
+					{"url": "` + mirror.URL + `", "secretRef": {"name": "mirror-auth"}}]`,
+			},
+		},
+		Spec: chnv1.ChannelSpec{Type: chnv1.ChannelTypeGit, Pathname: down.URL + "/repo.git"},
+	}
+
+	mirrors, err := GetGitMirrors(chn)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(mirrors).To(HaveLen(2))
+
+	clt, err := testharness.NewFakeClient(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "mirror-auth", Namespace: "ch-ns"},
+		Data:       map[string][]byte{UserID: []byte("mirror"), AccessToken: []byte("token")},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	secrets := FetchGitMirrorSecrets(clt, chn)
+	g.Expect(secrets).To(HaveLen(1))
+	g.Expect(secrets).To(HaveKey(mirror.URL))
+
+	// the mirror with a secret not found is left out, the other mirror has the credentials of its secret
+	configs := GetGitMirrorConnectionConfigs(chn, secrets, nil)
+	g.Expect(configs).To(HaveLen(1))
+	g.Expect(configs[0].RepoURL).To(Equal(mirror.URL))
+	g.Expect(configs[0].User).To(Equal("mirror"))
+	g.Expect(configs[0].Password).To(Equal("token"))
+
+	// the mirror is cloned when the primary remote can't be
+	cloneOptions := &GitCloneOption{
+		Branch:                  plumbing.NewBranchReferenceName(testharness.GitServerBranch),
+		DestDir:                 t.TempDir(),
+		PrimaryConnectionOption: &ChannelConnectionCfg{RepoURL: chn.Spec.Pathname},
+		MirrorConnectionOptions: []*ChannelConnectionCfg{{RepoURL: mirror.URL}},
+		Channel:                 types.NamespacedName{Namespace: chn.Namespace, Name: chn.Name},
+	}
+
+	commitID, err := CloneGitRepo(cloneOptions)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(commitID).NotTo(BeEmpty())
+	g.Expect(cloneOptions.ClonedRemote).To(Equal(mirror.URL))
+
+	// the error of the last remote is returned when none can be cloned
+	cloneOptions.MirrorConnectionOptions = []*ChannelConnectionCfg{{RepoURL: down.URL + "/mirror.git"}}
+
+	_, err = CloneGitRepo(cloneOptions)
+	g.Expect(err).To(MatchError(ContainSubstring(down.URL + "/mirror.git")))
+	g.Expect(cloneOptions.ClonedRemote).To(BeEmpty())
+
+	// the invalid annotation is ignored
+	chn.Annotations[appv1.AnnotationGitMirrors] = `[{"secretRef": {"name": "mirror-auth"}}]`
+
+	_, err = GetGitMirrors(chn)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(FetchGitMirrorSecrets(clt, chn)).To(BeEmpty())
+	g.Expect(GetGitMirrorConnectionConfigs(chn, secrets, nil)).To(BeEmpty())
+}
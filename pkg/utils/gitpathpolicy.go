@@ -0,0 +1,123 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ErrGitPathOutsideRepo is returned when a path of a git repo resolves outside of the repo
+var ErrGitPathOutsideRepo = errors.New("path outside of the git repo")
+
+// GitPathPolicy controls the symlinks and the hidden files of the git repos the subscriptions deploy. By default, the
+// symlinks resolving outside of the repo fail the subscriptions and the hidden files and folders are not deployed
+type GitPathPolicy struct {
+	// AllowExternalSymlinks follows the symlinks resolving outside of the repo
+	AllowExternalSymlinks bool
+	// AllowHiddenFiles deploys the files and folders starting with a dot below the subscription path
+	AllowHiddenFiles bool
+}
+
+var (
+	gitPathPolicy     GitPathPolicy
+	gitPathPolicyLock sync.RWMutex
+)
+
+// SetGitPathPolicy sets the policy of the git repos, it is called once before the controllers are set up
+func SetGitPathPolicy(policy GitPathPolicy) {
+	gitPathPolicyLock.Lock()
+	defer gitPathPolicyLock.Unlock()
+
+	gitPathPolicy = policy
+}
+
+// GetGitPathPolicy returns the policy of the git repos
+func GetGitPathPolicy() GitPathPolicy {
+	gitPathPolicyLock.RLock()
+	defer gitPathPolicyLock.RUnlock()
+
+	return gitPathPolicy
+}
+
+// CheckPath returns ErrGitPathOutsideRepo if the path is outside of the repo root, e.g. a subscription path with "..",
+// or if it resolves outside of the repo root through a symlink and the external symlinks are not allowed
+func (p GitPathPolicy) CheckPath(repoRoot, path string) error {
+	if !isPathUnder(repoRoot, path) {
+		return fmt.Errorf("%w: %v", ErrGitPathOutsideRepo, path)
+	}
+
+	if p.AllowExternalSymlinks {
+		return nil
+	}
+
+	root, err := filepath.EvalSymlinks(repoRoot)
+	if err != nil {
+		return err
+	}
+
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %v, err: %w", path, err)
+	}
+
+	if !isPathUnder(root, resolved) {
+		return fmt.Errorf("%w: %v resolves to %v", ErrGitPathOutsideRepo, path, resolved)
+	}
+
+	return nil
+}
+
+// IsHidden returns true if the path relative to the subscription path is hidden and the hidden files are not allowed
+func (p GitPathPolicy) IsHidden(resourcePath, path string) bool {
+	if p.AllowHiddenFiles {
+		return false
+	}
+
+	relPath, err := filepath.Rel(resourcePath, path)
+	if err != nil {
+		return false
+	}
+
+	for _, name := range strings.Split(filepath.ToSlash(relPath), "/") {
+		if strings.HasPrefix(name, ".") && name != "." && name != ".." {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isPathUnder(dir, path string) bool {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+
+	path, err = filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+
+	relPath, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+
+	return relPath != ".." && !strings.HasPrefix(relPath, "../")
+}
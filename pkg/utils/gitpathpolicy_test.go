@@ -0,0 +1,101 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestGitPathPolicy(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	defer SetGitPathPolicy(GitPathPolicy{})
+
+	dir := t.TempDir()
+	repoRoot := filepath.Join(dir, "repo")
+	configMap := []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n")
+
+	for _, name := range []string{"secret.yaml", "repo/apps/cm.yaml", "repo/apps/.hidden.yaml", "repo/apps/.ci/cm.yaml",
+		"repo/.github/cm.yaml", "repo/shared/cm.yaml"} {
+		path := filepath.Join(dir, name)
+		g.Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(Succeed())
+		g.Expect(os.WriteFile(path, configMap, 0600)).To(Succeed())
+	}
+
+	// the symlinks inside of the repo are followed
+	g.Expect(os.Symlink("../shared/cm.yaml", filepath.Join(repoRoot, "apps", "shared.yaml"))).To(Succeed())
+	g.Expect(os.Symlink("missing.yaml", filepath.Join(repoRoot, "apps", "broken.yaml"))).To(Succeed())
+
+	names := func(paths []string) []string {
+		names := []string{}
+
+		for _, p := range paths {
+			rel, err := filepath.Rel(repoRoot, p)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			names = append(names, filepath.ToSlash(rel))
+		}
+
+		return names
+	}
+
+	// the hidden files and folders below the subscription path are ignored
+	_, _, _, _, otherFiles, err := SortResources(repoRoot, filepath.Join(repoRoot, "apps"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(names(otherFiles)).To(ConsistOf("apps/cm.yaml", "apps/shared.yaml"))
+
+	// the subscription path can be hidden
+	_, _, _, _, otherFiles, err = SortResources(repoRoot, filepath.Join(repoRoot, "apps", ".ci"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(names(otherFiles)).To(ConsistOf("apps/.ci/cm.yaml"))
+
+	SetGitPathPolicy(GitPathPolicy{AllowHiddenFiles: true})
+
+	_, _, _, _, otherFiles, err = SortResources(repoRoot, repoRoot)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(names(otherFiles)).To(ConsistOf("apps/cm.yaml", "apps/shared.yaml", "apps/.hidden.yaml", "apps/.ci/cm.yaml",
+		".github/cm.yaml", "shared/cm.yaml"))
+
+	// the symlinks and the subscription paths outside of the repo fail
+	SetGitPathPolicy(GitPathPolicy{})
+
+	_, _, _, _, _, err = SortResources(repoRoot, filepath.Join(repoRoot, ".."))
+	g.Expect(errors.Is(err, ErrGitPathOutsideRepo)).To(BeTrue())
+
+	g.Expect(os.Symlink("../../secret.yaml", filepath.Join(repoRoot, "apps", ".secret.yaml"))).To(Succeed())
+
+	_, _, _, _, _, err = SortResources(repoRoot, filepath.Join(repoRoot, "apps"))
+	g.Expect(errors.Is(err, ErrGitPathOutsideRepo)).To(BeTrue())
+
+	g.Expect(os.Symlink(dir, filepath.Join(repoRoot, "link"))).To(Succeed())
+
+	_, _, _, _, _, err = SortResources(repoRoot, filepath.Join(repoRoot, "link"))
+	g.Expect(errors.Is(err, ErrGitPathOutsideRepo)).To(BeTrue())
+
+	// unless they are allowed
+	SetGitPathPolicy(GitPathPolicy{AllowExternalSymlinks: true, AllowHiddenFiles: true})
+
+	_, _, _, _, otherFiles, err = SortResources(repoRoot, filepath.Join(repoRoot, "apps"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(names(otherFiles)).To(ContainElement("apps/.secret.yaml"))
+
+	_, _, _, _, _, err = SortResources(repoRoot, filepath.Join(repoRoot, ".."))
+	g.Expect(errors.Is(err, ErrGitPathOutsideRepo)).To(BeTrue())
+}
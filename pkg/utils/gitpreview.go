@@ -0,0 +1,242 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"k8s.io/klog/v2"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils/tlsconfig"
+)
+
+const (
+	// GitProviderGitHub is the GitHub and GitHub Enterprise provider
+	GitProviderGitHub = "github"
+	// GitProviderGitLab is the GitLab provider
+	GitProviderGitLab = "gitlab"
+)
+
+// GitPullRequest is the GitHub pull request or GitLab merge request deployed by a preview subscription
+type GitPullRequest struct {
+	Provider string
+	RepoURL  string
+	Number   int
+}
+
+// GetSubscriptionPullRequest returns the pull request of a preview subscription, nil is returned if the subscription
+// doesn't have the git-pull-request annotation
+func GetSubscriptionPullRequest(sub *appv1.Subscription, repoURL string) (*GitPullRequest, error) {
+	annotations := sub.GetAnnotations()
+
+	prNumber := strings.TrimSpace(annotations[appv1.AnnotationGitPullRequest])
+	if prNumber == "" {
+		return nil, nil
+	}
+
+	number, err := strconv.Atoi(prNumber)
+	if err != nil || number <= 0 {
+		return nil, fmt.Errorf("invalid pull request number %v", prNumber)
+	}
+
+	provider, err := GetSubscriptionGitProvider(sub, repoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GitPullRequest{Provider: provider, RepoURL: repoURL, Number: number}, nil
+}
+
+// GetSubscriptionGitProvider returns the git-provider annotation of the subscription, the provider is gitlab if the
+// annotation is not set and the repo URL contains gitlab, github otherwise
+func GetSubscriptionGitProvider(sub *appv1.Subscription, repoURL string) (string, error) {
+	provider := strings.ToLower(strings.TrimSpace(sub.GetAnnotations()[appv1.AnnotationGitProvider]))
+
+	switch provider {
+	case "":
+		provider = GitProviderGitHub
+
+		if strings.Contains(strings.ToLower(repoURL), GitProviderGitLab) {
+			provider = GitProviderGitLab
+		}
+	case GitProviderGitHub, GitProviderGitLab:
+	default:
+		return "", fmt.Errorf("unsupported git provider %v, it must be %v or %v", provider, GitProviderGitHub, GitProviderGitLab)
+	}
+
+	return provider, nil
+}
+
+// GetPreviewSuffix returns the suffix of the helm releases of a preview subscription
+func GetPreviewSuffix(sub *appv1.Subscription) string {
+	prNumber := strings.TrimSpace(sub.GetAnnotations()[appv1.AnnotationGitPullRequest])
+	if prNumber == "" {
+		return ""
+	}
+
+	return "-pr-" + prNumber
+}
+
+// Ref returns the git reference of the pull request head
+func (pr *GitPullRequest) Ref() plumbing.ReferenceName {
+	if pr.Provider == GitProviderGitLab {
+		return plumbing.ReferenceName(fmt.Sprintf("refs/merge-requests/%d/head", pr.Number))
+	}
+
+	return plumbing.ReferenceName(fmt.Sprintf("refs/pull/%d/head", pr.Number))
+}
+
+// apiURL returns the provider API URL of the pull request
+func (pr *GitPullRequest) apiURL() (string, error) {
+	host, repoPath, err := parseGitRepoURL(pr.RepoURL)
+	if err != nil {
+		return "", err
+	}
+
+	if pr.Provider == GitProviderGitLab {
+		return fmt.Sprintf("%s/projects/%s/merge_requests/%d", gitAPIBase(pr.Provider, host), url.PathEscape(repoPath), pr.Number), nil
+	}
+
+	return fmt.Sprintf("%s/repos/%s/pulls/%d", gitAPIBase(pr.Provider, host), repoPath, pr.Number), nil
+}
+
+// IsOpen checks the state of the pull request with the provider API, the token is the channel secret accessToken
+func (pr *GitPullRequest) IsOpen(token string, insecureSkipVerify bool) (bool, error) {
+	apiURL, err := pr.apiURL()
+	if err != nil {
+		return false, err
+	}
+
+	req, err := newGitAPIRequest(http.MethodGet, apiURL, pr.Provider, token, nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := doGitAPIRequest(gitAPIHTTPClient(insecureSkipVerify), req, pr.Provider)
+	if err != nil {
+		return false, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("failed to get pull request %v, status: %v", apiURL, resp.Status)
+	}
+
+	state := struct {
+		State string `json:"state"`
+	}{}
+
+	if err := json.NewDecoder(resp.Body).Decode(&state); err != nil {
+		return false, fmt.Errorf("failed to parse pull request %v: %w", apiURL, err)
+	}
+
+	klog.V(1).Infof("pull request %v state: %v", apiURL, state.State)
+
+	// github: open, closed. gitlab: opened, closed, locked, merged
+	return state.State == "open" || state.State == "opened" || state.State == "locked", nil
+}
+
+// gitAPIBase returns the REST API root of the provider host
+func gitAPIBase(provider, host string) string {
+	if provider == GitProviderGitLab {
+		return "https://" + host + "/api/v4"
+	}
+
+	if host == "github.com" {
+		return "https://api.github.com"
+	}
+
+	return "https://" + host + "/api/v3"
+}
+
+// newGitAPIRequest returns a provider API request authenticated by the token
+func newGitAPIRequest(method, apiURL, provider, token string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, apiURL, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if token != "" {
+		if provider == GitProviderGitLab {
+			req.Header.Set("PRIVATE-TOKEN", token)
+		} else {
+			req.Header.Set("Authorization", "token "+token)
+		}
+	}
+
+	return req, nil
+}
+
+func gitAPIHTTPClient(insecureSkipVerify bool) *http.Client {
+	tlsClientConfig := tlsconfig.NewConfig(tls.VersionTLS12)
+	tlsClientConfig.InsecureSkipVerify = insecureSkipVerify // #nosec G402 InsecureSkipVerify optionally
+
+	return &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsClientConfig,
+		},
+	}
+}
+
+// parseGitRepoURL returns the host and the owner/repo path of https, ssh and scp-like git URLs
+func parseGitRepoURL(repoURL string) (string, string, error) {
+	host, repoPath := "", ""
+
+	if strings.HasPrefix(repoURL, "git@") && !strings.Contains(repoURL, "://") {
+		parts := strings.SplitN(strings.TrimPrefix(repoURL, "git@"), ":", 2)
+		if len(parts) != 2 {
+			return "", "", fmt.Errorf("invalid git repo URL %v", repoURL)
+		}
+
+		host, repoPath = parts[0], parts[1]
+	} else {
+		u, err := url.Parse(repoURL)
+		if err != nil {
+			return "", "", err
+		}
+
+		host, repoPath = u.Host, u.Path
+
+		// the API is not served on the ssh port
+		if u.Scheme == "ssh" {
+			host = u.Hostname()
+		}
+	}
+
+	repoPath = strings.TrimSuffix(strings.Trim(repoPath, "/"), ".git")
+
+	if host == "" || !strings.Contains(repoPath, "/") {
+		return "", "", fmt.Errorf("invalid git repo URL %v", repoURL)
+	}
+
+	return host, repoPath, nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func TestGetSubscriptionPullRequest(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sub := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns"}}
+
+	pr, err := GetSubscriptionPullRequest(sub, "https://github.com/org/app.git")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pr).To(BeNil())
+	g.Expect(GetPreviewSuffix(sub)).To(BeEmpty())
+
+	sub.SetAnnotations(map[string]string{appv1.AnnotationGitPullRequest: "42"})
+
+	pr, err = GetSubscriptionPullRequest(sub, "https://github.com/org/app.git")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pr.Ref().String()).To(Equal("refs/pull/42/head"))
+	g.Expect(pr.apiURL()).To(Equal("https://api.github.com/repos/org/app/pulls/42"))
+	g.Expect(GetPreviewSuffix(sub)).To(Equal("-pr-42"))
+
+	pr, err = GetSubscriptionPullRequest(sub, "git@gitlab.example.com:group/sub/app.git")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pr.Ref().String()).To(Equal("refs/merge-requests/42/head"))
+	g.Expect(pr.apiURL()).To(Equal("https://gitlab.example.com/api/v4/projects/group%2Fsub%2Fapp/merge_requests/42"))
+
+	sub.SetAnnotations(map[string]string{appv1.AnnotationGitPullRequest: "42", appv1.AnnotationGitProvider: "github"})
+
+	pr, err = GetSubscriptionPullRequest(sub, "ssh://git@git.example.com:2222/org/app.git")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pr.apiURL()).To(Equal("https://git.example.com/api/v3/repos/org/app/pulls/42"))
+
+	sub.SetAnnotations(map[string]string{appv1.AnnotationGitPullRequest: "latest"})
+
+	_, err = GetSubscriptionPullRequest(sub, "https://github.com/org/app.git")
+	g.Expect(err).To(HaveOccurred())
+
+	sub.SetAnnotations(map[string]string{appv1.AnnotationGitPullRequest: "42", appv1.AnnotationGitProvider: "gitea"})
+
+	_, err = GetSubscriptionPullRequest(sub, "https://github.com/org/app.git")
+	g.Expect(err).To(HaveOccurred())
+
+	g.Expect(GetSubscriptionBranchRef("refs/pull/42/head").String()).To(Equal("refs/pull/42/head"))
+	g.Expect(GetSubscriptionBranchRef("main").String()).To(Equal("refs/heads/main"))
+}
+
+func TestGitPullRequestIsOpen(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	state := "open"
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/repos/org/app/pulls/42":
+			if r.Header.Get("Authorization") != "token secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+		case "/api/v4/projects/org/app/merge_requests/42":
+			if r.Header.Get("PRIVATE-TOKEN") != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"number": 42, "state": "` + state + `"}`))
+	}))
+
+	defer ts.Close()
+
+	pr := &GitPullRequest{Provider: GitProviderGitHub, RepoURL: ts.URL + "/org/app.git", Number: 42}
+
+	open, err := pr.IsOpen("secret", true)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(open).To(BeTrue())
+
+	state = "closed"
+
+	open, err = pr.IsOpen("secret", true)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(open).To(BeFalse())
+
+	_, err = pr.IsOpen("wrong", true)
+	g.Expect(err).To(HaveOccurred())
+
+	pr.Provider = GitProviderGitLab
+	state = "merged"
+
+	open, err = pr.IsOpen("secret", true)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(open).To(BeFalse())
+
+	state = "opened"
+
+	open, err = pr.IsOpen("secret", true)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(open).To(BeTrue())
+}
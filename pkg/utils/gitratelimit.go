@@ -0,0 +1,281 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// gitRateLimitMinBackoff is the first backoff of a rate-limited channel without the retry time of the provider
+	gitRateLimitMinBackoff = 30 * time.Second
+	// gitRateLimitMaxBackoff caps the exponential backoff and the retry time announced by the provider
+	gitRateLimitMaxBackoff = time.Hour
+)
+
+// GitRateLimitError is returned by the git clones and the provider API calls rate limited by the git provider, and
+// while their backoff lasts without contacting the provider
+type GitRateLimitError struct {
+	Provider   string
+	RetryAfter time.Time
+	Err        error
+}
+
+func (e *GitRateLimitError) Error() string {
+	msg := fmt.Sprintf("rate limited by the %v git provider, retry after %v", e.Provider, e.RetryAfter.Format(time.RFC3339))
+
+	if e.Err != nil {
+		return e.Err.Error() + ", " + msg
+	}
+
+	return msg
+}
+
+func (e *GitRateLimitError) Unwrap() error {
+	return e.Err
+}
+
+type gitRateLimitBackoff struct {
+	until    time.Time
+	failures int
+}
+
+type gitRateLimitHint struct {
+	seen    time.Time
+	retryAt time.Time
+}
+
+var (
+	// gitRateLimitBackoffs are the backoffs by channel, shared by all the subscriptions of the channel
+	gitRateLimitBackoffs     = map[string]*gitRateLimitBackoff{}
+	gitRateLimitBackoffsLock sync.Mutex
+
+	// gitRateLimitHints are the rate-limit responses seen by the git transport by host, go-git doesn't return the
+	// response headers in its errors
+	gitRateLimitHints sync.Map
+)
+
+// gitRateLimitKey returns the backoff key of a channel, the repo URL if the channel isn't known
+func gitRateLimitKey(channel types.NamespacedName, repoURL string) string {
+	if channel.Name != "" {
+		return channel.String()
+	}
+
+	return repoURL
+}
+
+// checkGitRateLimitBackoff returns a GitRateLimitError while the backoff of the key lasts
+func checkGitRateLimitBackoff(key, provider string) error {
+	gitRateLimitBackoffsLock.Lock()
+	defer gitRateLimitBackoffsLock.Unlock()
+
+	if b, ok := gitRateLimitBackoffs[key]; ok && time.Now().Before(b.until) {
+		return &GitRateLimitError{Provider: provider, RetryAfter: b.until}
+	}
+
+	return nil
+}
+
+// backOffGitRateLimit starts the backoff of the key and returns its end. The retry time of the provider is respected
+// if known, the backoff is exponential otherwise. The jitter spreads the retries of the channels of the provider
+func backOffGitRateLimit(key string, retryAt time.Time) time.Time {
+	gitRateLimitBackoffsLock.Lock()
+	defer gitRateLimitBackoffsLock.Unlock()
+
+	b, ok := gitRateLimitBackoffs[key]
+	if !ok {
+		b = &gitRateLimitBackoff{}
+		gitRateLimitBackoffs[key] = b
+	}
+
+	b.failures++
+
+	wait := gitRateLimitMaxBackoff
+	if b.failures <= 7 {
+		wait = gitRateLimitMinBackoff << (b.failures - 1)
+	}
+
+	now := time.Now()
+
+	if retryAt.After(now) {
+		wait = retryAt.Sub(now)
+	}
+
+	if wait > gitRateLimitMaxBackoff {
+		wait = gitRateLimitMaxBackoff
+	}
+
+	wait += time.Duration(rand.Int63n(int64(wait/10) + 1)) // #nosec G404 the jitter doesn't need a secure random
+
+	b.until = now.Add(wait)
+
+	klog.Warningf("git provider rate limited %v %v times, backing off until %v", key, b.failures,
+		b.until.Format(time.RFC3339))
+
+	return b.until
+}
+
+// resetGitRateLimit ends the backoff of the key
+func resetGitRateLimit(key string) {
+	gitRateLimitBackoffsLock.Lock()
+	defer gitRateLimitBackoffsLock.Unlock()
+
+	delete(gitRateLimitBackoffs, key)
+}
+
+// isGitRateLimitResponse checks if the response is a rate-limit response, the 429 responses and the 403 responses of
+// the exhausted GitHub and GitLab rate limits or of the GitHub secondary rate limits
+func isGitRateLimitResponse(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusForbidden:
+		return resp.Header.Get("X-RateLimit-Remaining") == "0" || resp.Header.Get("RateLimit-Remaining") == "0" ||
+			resp.Header.Get("Retry-After") != ""
+	}
+
+	return false
+}
+
+// gitRateLimitRetryTime returns the retry time of a rate-limit response, from the Retry-After header in seconds or
+// as an HTTP date, or the X-RateLimit-Reset (GitHub) and RateLimit-Reset (GitLab) headers in unix seconds. Zero is
+// returned if the response has none
+func gitRateLimitRetryTime(header http.Header, now time.Time) time.Time {
+	if retryAfter := header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.ParseInt(retryAfter, 10, 64); err == nil {
+			return now.Add(time.Duration(seconds) * time.Second)
+		}
+
+		if date, err := http.ParseTime(retryAfter); err == nil {
+			return date
+		}
+	}
+
+	for _, h := range []string{"X-RateLimit-Reset", "RateLimit-Reset"} {
+		if reset, err := strconv.ParseInt(header.Get(h), 10, 64); err == nil {
+			return time.Unix(reset, 0)
+		}
+	}
+
+	return time.Time{}
+}
+
+// gitRateLimitTransport records the rate-limit responses of the git clones
+type gitRateLimitTransport struct {
+	base http.RoundTripper
+}
+
+func (t *gitRateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+
+	if err == nil && isGitRateLimitResponse(resp) {
+		now := time.Now()
+
+		gitRateLimitHints.Store(req.URL.Host, gitRateLimitHint{seen: now, retryAt: gitRateLimitRetryTime(resp.Header, now)})
+	}
+
+	return resp, err
+}
+
+// takeGitRateLimitHint returns the retry time of the rate-limit response of the repo host seen since the clone start
+func takeGitRateLimitHint(repoURL string, since time.Time) (time.Time, bool) {
+	host, _, err := parseGitRepoURL(repoURL)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	hint, ok := gitRateLimitHints.LoadAndDelete(host)
+	if !ok || hint.(gitRateLimitHint).seen.Before(since) {
+		return time.Time{}, false
+	}
+
+	return hint.(gitRateLimitHint).retryAt, true
+}
+
+// gitCloneRepoURL returns the primary repo URL of the clone
+func gitCloneRepoURL(cloneOptions *GitCloneOption) string {
+	if cloneOptions.PrimaryConnectionOption != nil {
+		return cloneOptions.PrimaryConnectionOption.RepoURL
+	}
+
+	return ""
+}
+
+// checkGitCloneRateLimit backs off the channel of a clone rate limited by the git provider and returns the
+// GitRateLimitError of the clone error. The backoff of the channel ends with a successful clone
+func checkGitCloneRateLimit(cloneOptions *GitCloneOption, start time.Time, err error) error {
+	repoURL := gitCloneRepoURL(cloneOptions)
+	key := gitRateLimitKey(cloneOptions.Channel, repoURL)
+
+	if err == nil {
+		resetGitRateLimit(key)
+
+		return nil
+	}
+
+	retryAt, hinted := takeGitRateLimitHint(repoURL, start)
+
+	for _, remote := range gitCloneRemotes(cloneOptions)[1:] {
+		if remote == nil {
+			continue
+		}
+
+		if remoteRetryAt, ok := takeGitRateLimitHint(remote.RepoURL, start); ok {
+			retryAt, hinted = remoteRetryAt, true
+		}
+	}
+
+	provider, limited := GitRateLimitProvider(repoURL, err)
+	if !hinted && !limited {
+		return err
+	}
+
+	return &GitRateLimitError{Provider: provider, RetryAfter: backOffGitRateLimit(key, retryAt), Err: err}
+}
+
+// doGitAPIRequest sends a provider API request, the requests to a rate-limited API host are backed off
+func doGitAPIRequest(httpClient *http.Client, req *http.Request, provider string) (*http.Response, error) {
+	key := provider + "-api:" + req.URL.Host
+
+	if err := checkGitRateLimitBackoff(key, provider); err != nil {
+		return nil, err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if isGitRateLimitResponse(resp) {
+		resp.Body.Close()
+
+		retryAt := backOffGitRateLimit(key, gitRateLimitRetryTime(resp.Header, time.Now()))
+
+		return nil, &GitRateLimitError{Provider: provider, RetryAfter: retryAt,
+			Err: fmt.Errorf("failed to call %v, status: %v", req.URL.String(), resp.Status)}
+	}
+
+	resetGitRateLimit(key)
+
+	return resp, nil
+}
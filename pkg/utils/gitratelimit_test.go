@@ -0,0 +1,163 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestGitRateLimitRetryTime(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	now := time.Unix(1700000000, 0)
+
+	g.Expect(gitRateLimitRetryTime(http.Header{"Retry-After": {"120"}}, now)).To(Equal(now.Add(2 * time.Minute)))
+	g.Expect(gitRateLimitRetryTime(http.Header{"Retry-After": {"Tue, 14 Nov 2023 22:15:20 GMT"}}, now).Unix()).
+		To(Equal(int64(1700000120)))
+	g.Expect(gitRateLimitRetryTime(http.Header{"X-Ratelimit-Reset": {"1700000300"}}, now)).To(Equal(time.Unix(1700000300, 0)))
+	g.Expect(gitRateLimitRetryTime(http.Header{"Ratelimit-Reset": {"1700000600"}}, now)).To(Equal(time.Unix(1700000600, 0)))
+	g.Expect(gitRateLimitRetryTime(http.Header{}, now).IsZero()).To(BeTrue())
+
+	g.Expect(isGitRateLimitResponse(&http.Response{StatusCode: http.StatusTooManyRequests})).To(BeTrue())
+	g.Expect(isGitRateLimitResponse(&http.Response{StatusCode: http.StatusForbidden,
+		Header: http.Header{"X-Ratelimit-Remaining": {"0"}}})).To(BeTrue())
+	g.Expect(isGitRateLimitResponse(&http.Response{StatusCode: http.StatusForbidden, Header: http.Header{}})).To(BeFalse())
+	g.Expect(isGitRateLimitResponse(&http.Response{StatusCode: http.StatusOK, Header: http.Header{}})).To(BeFalse())
+}
+
+func TestGitRateLimitBackoff(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	key := "backoff-ns/backoff"
+	defer resetGitRateLimit(key)
+
+	// exponential with jitter without the retry time of the provider
+	first := backOffGitRateLimit(key, time.Time{})
+	g.Expect(time.Until(first)).To(BeNumerically("~", gitRateLimitMinBackoff, gitRateLimitMinBackoff/5))
+
+	second := backOffGitRateLimit(key, time.Time{})
+	g.Expect(time.Until(second)).To(BeNumerically("~", 2*gitRateLimitMinBackoff, 2*gitRateLimitMinBackoff/5))
+
+	// the retry time of the provider is respected and capped
+	g.Expect(time.Until(backOffGitRateLimit(key, time.Now().Add(10*time.Minute)))).
+		To(BeNumerically("~", 10*time.Minute, 2*time.Minute))
+	g.Expect(time.Until(backOffGitRateLimit(key, time.Now().Add(24*time.Hour)))).
+		To(BeNumerically("<=", gitRateLimitMaxBackoff+gitRateLimitMaxBackoff/10))
+
+	err := checkGitRateLimitBackoff(key, GitProviderGitHub)
+	g.Expect(err).To(HaveOccurred())
+
+	_, limited := GitRateLimitProvider("", err)
+	g.Expect(limited).To(BeTrue())
+
+	resetGitRateLimit(key)
+	g.Expect(checkGitRateLimitBackoff(key, GitProviderGitHub)).To(Succeed())
+}
+
+func TestGitRateLimitAPIRequest(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var requests, limited int32 = 0, 1
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		if atomic.LoadInt32(&limited) == 1 {
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusTooManyRequests)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	defer ts.Close()
+
+	status := &GitCommitStatus{Provider: GitProviderGitHub, RepoURL: ts.URL + "/org/app.git", Commit: "abc123",
+		Context: "cluster1", State: GitCommitStatusSuccess}
+
+	err := status.Post("secret", true)
+
+	rateLimitErr := &GitRateLimitError{}
+	g.Expect(errors.As(err, &rateLimitErr)).To(BeTrue())
+	g.Expect(time.Until(rateLimitErr.RetryAfter)).To(BeNumerically("~", 2*time.Minute, 30*time.Second))
+
+	// the provider isn't called while the backoff lasts
+	atomic.StoreInt32(&limited, 0)
+
+	g.Expect(status.Post("secret", true)).NotTo(Succeed())
+	g.Expect(atomic.LoadInt32(&requests)).To(Equal(int32(1)))
+
+	resetGitRateLimit(GitProviderGitHub + "-api:" + ts.Listener.Addr().String())
+
+	g.Expect(status.Post("secret", true)).To(Succeed())
+	g.Expect(atomic.LoadInt32(&requests)).To(Equal(int32(2)))
+}
+
+func TestGitRateLimitClone(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var requests int32
+
+	reset := time.Now().Add(5 * time.Minute).Unix()
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+		w.WriteHeader(http.StatusForbidden)
+	}))
+
+	defer ts.Close()
+
+	channel := types.NamespacedName{Namespace: "clone-ns", Name: "clone"}
+	defer resetGitRateLimit(channel.String())
+
+	cloneOptions := func() *GitCloneOption {
+		return &GitCloneOption{
+			Branch:  GetSubscriptionBranchRef("main"),
+			DestDir: t.TempDir(),
+			PrimaryConnectionOption: &ChannelConnectionCfg{
+				RepoURL:            ts.URL + "/org/app.git",
+				InsecureSkipVerify: true,
+			},
+			Channel: channel,
+		}
+	}
+
+	_, err := CloneGitRepo(cloneOptions())
+
+	rateLimitErr := &GitRateLimitError{}
+	g.Expect(errors.As(err, &rateLimitErr)).To(BeTrue())
+	g.Expect(rateLimitErr.RetryAfter.Unix()).To(BeNumerically(">=", reset))
+
+	// the subscriptions of the channel share the backoff
+	cloned := atomic.LoadInt32(&requests)
+
+	_, err = CloneGitRepo(cloneOptions())
+	g.Expect(errors.As(err, &rateLimitErr)).To(BeTrue())
+	g.Expect(atomic.LoadInt32(&requests)).To(Equal(cloned))
+}
@@ -39,6 +39,7 @@ import (
 	admissionv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
 
@@ -48,12 +49,13 @@ import (
 	gitignore "github.com/sabhiram/go-gitignore"
 
 	"github.com/ghodss/yaml"
-	gitclient "gopkg.in/src-d/go-git.v4/plumbing/transport/client"
 	githttp "gopkg.in/src-d/go-git.v4/plumbing/transport/http"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
 	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils/gittransport"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils/tlsconfig"
 )
 
 const (
@@ -91,6 +93,13 @@ type GitCloneOption struct {
 	CloneDepth                int
 	PrimaryConnectionOption   *ChannelConnectionCfg
 	SecondaryConnectionOption *ChannelConnectionCfg
+	// MirrorConnectionOptions are the mirror remotes of the primary channel, cloned in order after the primary
+	// channel and before the secondary channel
+	MirrorConnectionOptions []*ChannelConnectionCfg
+	// ClonedRemote is set by CloneGitRepo to the URL of the remote the repo was cloned from
+	ClonedRemote string
+	// Channel is the primary channel of the clone, the git metrics are labeled with it
+	Channel types.NamespacedName
 }
 
 type ChannelConnectionCfg struct {
@@ -133,12 +142,36 @@ func KubeResourceParser(file []byte, cond Kube) [][]byte {
 			continue
 		}
 
+		rsc := &unstructured.Unstructured{}
+		if strings.HasSuffix(t.Kind, "List") && yaml.Unmarshal(item, &rsc.Object) == nil && IsResourceList(rsc) {
+			// The items of the lists are separate resources
+			for _, listItem := range ExpandResourceList(rsc) {
+				lt := KubeResource{kubeResource{APIVersion: listItem.GetAPIVersion(), Kind: listItem.GetKind()}}
+				if cond(lt) {
+					continue
+				}
+
+				itemDoc, err := yaml.Marshal(listItem.Object)
+				if err != nil {
+					klog.Warning(err, "Failed to marshal the list item")
+
+					continue
+				}
+
+				ret = append(ret, itemDoc)
+			}
+
+			continue
+		}
+
 		if cond(t) {
 			// Ignore item that does not have apiVersion or kind.
 			klog.Warning("Not a Kubernetes resource")
-		} else {
-			ret = append(ret, item)
+
+			continue
 		}
+
+		ret = append(ret, item)
 	}
 
 	return ret
@@ -168,18 +201,7 @@ func getCertChain(certs string) tls.Certificate {
 
 // A subscription can have secondary channel to use when it cannot connect to the primary channel
 // This builds connectionOptions *git.CloneOptions based on the channel selection
-func getConnectionOptions(cloneOptions *GitCloneOption, primary bool) (connectionOptions *git.CloneOptions, err error) {
-	channelConnOptions := cloneOptions.PrimaryConnectionOption
-
-	if !primary {
-		if cloneOptions.SecondaryConnectionOption == nil {
-			klog.Error("no secondary channel to try")
-			return nil, nil
-		}
-
-		channelConnOptions = cloneOptions.SecondaryConnectionOption
-	}
-
+func getConnectionOptions(cloneOptions *GitCloneOption, channelConnOptions *ChannelConnectionCfg) (connectionOptions *git.CloneOptions, err error) {
 	options := &git.CloneOptions{
 		URL:               channelConnOptions.RepoURL,
 		SingleBranch:      true,
@@ -256,72 +278,99 @@ func getConnectionOptions(cloneOptions *GitCloneOption, primary bool) (connectio
 	return options, nil
 }
 
-// CloneGitRepo clones a GitHub repository
-func CloneGitRepo(cloneOptions *GitCloneOption) (commitID string, err error) {
-	usingPrimary := true
+// gitCloneRemotes returns the connection configs of the remotes of a clone in the order they are tried, the primary
+// channel, its mirrors and the secondary channel
+func gitCloneRemotes(cloneOptions *GitCloneOption) []*ChannelConnectionCfg {
+	remotes := []*ChannelConnectionCfg{cloneOptions.PrimaryConnectionOption}
+	remotes = append(remotes, cloneOptions.MirrorConnectionOptions...)
 
-	options, err := getConnectionOptions(cloneOptions, true)
+	if cloneOptions.SecondaryConnectionOption != nil {
+		remotes = append(remotes, cloneOptions.SecondaryConnectionOption)
+	}
 
-	if err != nil {
-		klog.Error("Failed to get Git clone options with the primary channel. Trying the secondary channel.")
+	return remotes
+}
 
-		usingPrimary = false
-	}
+// cloneGitRemotes clones the first remote of the clone which can be cloned, the URL of the remote is set in the
+// ClonedRemote of the clone options. The error of the last remote is returned if none can be cloned
+func cloneGitRemotes(cloneOptions *GitCloneOption) (*git.Repository, error) {
+	cloneOptions.ClonedRemote = ""
 
-	secondaryOptions, err := getConnectionOptions(cloneOptions, false)
+	var lastErr error
 
-	if err != nil {
-		if !usingPrimary {
-			// we could not get both primary and secondary Git connection options. return error
-			klog.Error("Failed to get Git clone options with the secondary channel.")
-			return "", err
+	for _, remote := range gitCloneRemotes(cloneOptions) {
+		if remote == nil {
+			continue
 		}
 
-		klog.Warning("Failed to get Git clone options with the secondary channel.")
-	}
+		if lastErr != nil {
+			klog.Infof("Trying to clone with the next remote %v", remote.RepoURL)
+		}
+
+		options, err := getConnectionOptions(cloneOptions, remote)
+		if err != nil {
+			klog.Errorf("Failed to get Git clone options of %v, err: %v", remote.RepoURL, err)
 
-	// we could not get the connection options with the primary channel but we got it with the secondary channel. Use it instead
-	if !usingPrimary {
-		if secondaryOptions == nil {
-			// if trying the secondary connection option but nothing there, return error
-			// at this point, we have no Git connection options
-			return "", errors.New("failed to build git connection options")
+			lastErr = err
+
+			continue
+		}
+
+		klog.Info("Cloning ", options.URL, " into ", cloneOptions.DestDir)
+
+		klog.Info("cloneOptions.DestDir = " + cloneOptions.DestDir)
+		klog.Info("cloneOptions.Branch = " + cloneOptions.Branch)
+		klog.Info("cloneOptions.CommitHash = " + cloneOptions.CommitHash)
+		klog.Info("cloneOptions.RevisionTag = " + cloneOptions.RevisionTag)
+		klog.Infof("cloneOptions.CloneDepth = %d", cloneOptions.CloneDepth)
+
+		repo, err := git.PlainClone(cloneOptions.DestDir, false, options)
+
+		gittransport.Release(options.Auth)
+
+		if err != nil {
+			klog.Error(err, " Failed to git clone ", options.URL)
+
+			lastErr = errors.New("Failed to clone git: " + options.URL + " branch: " + cloneOptions.Branch.String() + Error + err.Error())
+
+			continue
 		}
 
-		options = secondaryOptions
+		cloneOptions.ClonedRemote = remote.RepoURL
+
+		return repo, nil
 	}
 
-	klog.Info("Cloning ", options.URL, " into ", cloneOptions.DestDir)
+	if lastErr == nil {
+		lastErr = errors.New("failed to build git connection options")
+	}
 
-	klog.Info("cloneOptions.DestDir = " + cloneOptions.DestDir)
-	klog.Info("cloneOptions.Branch = " + cloneOptions.Branch)
-	klog.Info("cloneOptions.CommitHash = " + cloneOptions.CommitHash)
-	klog.Info("cloneOptions.RevisionTag = " + cloneOptions.RevisionTag)
-	klog.Infof("cloneOptions.CloneDepth = %d", cloneOptions.CloneDepth)
+	return nil, lastErr
+}
 
-	repo, err := git.PlainClone(cloneOptions.DestDir, false, options)
+// CloneGitRepo clones a GitHub repository, the clone is recorded in the git metrics of its channel. The channels
+// rate limited by their git provider aren't cloned until their backoff ends
+func CloneGitRepo(cloneOptions *GitCloneOption) (commitID string, err error) {
+	repoURL := gitCloneRepoURL(cloneOptions)
+	provider, _ := GitRateLimitProvider(repoURL, nil)
 
-	if err != nil {
-		if usingPrimary {
-			klog.Error(err, " Failed to git clone with the primary channel: ", err.Error())
+	if err := checkGitRateLimitBackoff(gitRateLimitKey(cloneOptions.Channel, repoURL), provider); err != nil {
+		klog.Warningf("skip cloning %v, err: %v", repoURL, err)
 
-			if secondaryOptions == nil {
-				return "", errors.New("Failed to clone git: " + options.URL + Error + err.Error())
-			}
+		return "", err
+	}
 
-			klog.Info("Trying to clone with the secondary channel")
-			klog.Info("Cloning ", secondaryOptions.URL, " into ", cloneOptions.DestDir)
+	start := time.Now()
 
-			repo, err = git.PlainClone(cloneOptions.DestDir, false, secondaryOptions)
+	defer func() {
+		err = checkGitCloneRateLimit(cloneOptions, start, err)
 
-			if err != nil {
-				klog.Error("Failed to clone Git with the secondary channel." + Error + err.Error())
+		recordGitClone(cloneOptions, start, err)
+	}()
 
-				return "", errors.New("Failed to clone git: " + secondaryOptions.URL + " branch: " + cloneOptions.Branch.String() + Error + err.Error())
-			}
-		} else {
-			return "", errors.New("Failed to clone git: " + options.URL + " branch: " + cloneOptions.Branch.String() + Error + err.Error())
-		}
+	repo, err := cloneGitRemotes(cloneOptions)
+	if err != nil {
+		return "", err
 	}
 
 	ref, err := repo.Head()
@@ -478,16 +527,22 @@ func getSSHOptions(options *git.CloneOptions, sshKey, passphrase []byte, knownho
 }
 
 func getHTTPOptions(options *git.CloneOptions, user, password, caCerts string, insecureSkipVerify bool, clientkey, clientcert []byte) error {
+	var auth githttp.AuthMethod
+
 	if user != "" && password != "" {
-		options.Auth = &githttp.BasicAuth{
+		auth = &githttp.BasicAuth{
 			Username: user,
 			Password: password,
 		}
+	} else if u, err := url.Parse(options.URL); err == nil && u.User != nil {
+		// the credentials of the repo URL, go-git only reads them without an auth
+		pw, _ := u.User.Password()
+		auth = &githttp.BasicAuth{Username: u.User.Username(), Password: pw}
 	}
 
 	installProtocol := false
 
-	clientConfig := &tls.Config{MinVersion: tls.VersionTLS13}
+	clientConfig := tlsconfig.NewConfig(tls.VersionTLS13)
 
 	// skip TLS certificate verification for Git servers with custom or self-signed certs
 	if insecureSkipVerify {
@@ -572,7 +627,7 @@ func getHTTPOptions(options *git.CloneOptions, user, password, caCerts string, i
 
 		customClient := &http.Client{
 			/* #nosec G402 */
-			Transport: transportConfig,
+			Transport: &gitRateLimitTransport{base: transportConfig},
 
 			// 15 second timeout
 			Timeout: 15 * time.Second,
@@ -582,9 +637,16 @@ func getHTTPOptions(options *git.CloneOptions, user, password, caCerts string, i
 			},
 		}
 
-		gitclient.InstallProtocol("https", githttp.NewClient(customClient))
+		options.Auth = gittransport.NewAuth(auth, customClient)
+
+		return nil
 	}
 
+	// the clone has its own client, the other clones keep theirs
+	options.Auth = gittransport.NewAuth(auth, &http.Client{
+		Transport: &gitRateLimitTransport{base: http.DefaultTransport},
+	})
+
 	return nil
 }
 
@@ -602,7 +664,8 @@ func GetSubscriptionBranch(sub *appv1.Subscription) plumbing.ReferenceName {
 
 func GetSubscriptionBranchRef(b string) plumbing.ReferenceName {
 	if b != "" {
-		if !strings.HasPrefix(b, "refs/heads/") {
+		// keep the full references like the pull request heads
+		if !strings.HasPrefix(b, "refs/") {
 			b = "refs/heads/" + b
 		}
 
@@ -744,6 +807,14 @@ func SortResources(repoRoot, resourcePath string, skips ...SkipFunc) (map[string
 	currentKustomizeDir := "NONE"
 
 	kubeIgnore := GetKubeIgnore(resourcePath)
+	appSubIgnores := getAppSubIgnores(repoRoot, resourcePath)
+	policy := GetGitPathPolicy()
+
+	if err := policy.CheckPath(repoRoot, resourcePath); err != nil {
+		klog.Error(err.Error())
+
+		return chartDirs, kustomizeDirs, crdsAndNamespaceFiles, rbacFiles, otherFiles, err
+	}
 
 	err := filepath.Walk(resourcePath,
 		func(path string, info os.FileInfo, err error) error {
@@ -751,54 +822,92 @@ func SortResources(repoRoot, resourcePath string, skips ...SkipFunc) (map[string
 				return err
 			}
 
+			// The symlinks are checked in the hidden folders and in the chart and kustomization folders too, as helm
+			// and kustomize follow them
+			if info.Mode()&os.ModeSymlink != 0 && !isGitDir(repoRoot, path) {
+				if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+					klog.Warning("Ignoring the broken symlink ", path)
+
+					return nil
+				}
+
+				if err := policy.CheckPath(repoRoot, path); err != nil {
+					klog.Error(err.Error())
+
+					return err
+				}
+			}
+
+			if policy.IsHidden(resourcePath, path) {
+				klog.V(4).Info("Ignoring the hidden path ", path)
+
+				return nil
+			}
+
 			relativePath := path
 
 			if len(strings.SplitAfter(path, repoRoot+"/")) > 1 {
 				relativePath = strings.SplitAfter(path, repoRoot+"/")[1]
 			}
 
-			if !kubeIgnore.MatchesPath(relativePath) && !skip(resourcePath, path) {
-				if info.IsDir() {
-					klog.V(4).Info("Ignoring subfolders of ", currentChartDir)
-					if _, err := os.Stat(path + "/Chart.yaml"); err == nil {
-						klog.V(4).Info("Found Chart.yaml in ", path)
-						if !strings.HasPrefix(path, currentChartDir) {
-							klog.V(4).Info("This is a helm chart folder.")
+			if kubeIgnore.MatchesPath(relativePath) || matchAppSubIgnores(appSubIgnores, path, info.IsDir()) {
+				return nil
+			}
+
+			// The chart and kustomization roots are recorded even when the skip function filters them out, e.g. with
+			// includePaths that only match the files below them, so these files are not applied as plain manifests
+			skipped := skip(resourcePath, path)
+
+			if info.IsDir() {
+				klog.V(4).Info("Ignoring subfolders of ", currentChartDir)
+				if _, err := os.Stat(path + "/Chart.yaml"); err == nil {
+					klog.V(4).Info("Found Chart.yaml in ", path)
+					if !strings.HasPrefix(path, currentChartDir) {
+						klog.V(4).Info("This is a helm chart folder.")
+						currentChartDir = path + "/"
+
+						if !skipped {
 							chartDirs[path+"/"] = path + "/"
-							currentChartDir = path + "/"
 						}
-					} else if _, err := os.Stat(path + "/kustomization.yaml"); err == nil {
-						// If there are nested kustomizations or any other folder structures containing kube
-						// resources under a kustomization, subscription should not process them and let kustomize
-						// build handle them based on the top-level kustomization.yaml.
-						if !strings.HasPrefix(path, currentKustomizeDir) {
-							klog.V(4).Info("Found kustomization.yaml in ", path)
-							currentKustomizeDir = path + "/"
-							kustomizeDirs[path+"/"] = path + "/"
-						}
-					} else if _, err := os.Stat(path + "/kustomization.yml"); err == nil {
-						// If there are nested kustomizations or any other folder structures containing kube
-						// resources under a kustomization, subscription should not process them and let kustomize
-						// build handle them based on the top-level kustomization.yaml
-						if !strings.HasPrefix(path, currentKustomizeDir) {
-							klog.V(4).Info("Found kustomization.yml in ", path)
-							currentKustomizeDir = path + "/"
+					}
+				} else if _, err := os.Stat(path + "/kustomization.yaml"); err == nil {
+					// If there are nested kustomizations or any other folder structures containing kube
+					// resources under a kustomization, subscription should not process them and let kustomize
+					// build handle them based on the top-level kustomization.yaml.
+					if !strings.HasPrefix(path, currentKustomizeDir) {
+						klog.V(4).Info("Found kustomization.yaml in ", path)
+						currentKustomizeDir = path + "/"
+
+						if !skipped {
 							kustomizeDirs[path+"/"] = path + "/"
 						}
 					}
-				} else if !strings.HasPrefix(path, currentChartDir) &&
-					!strings.HasPrefix(path, repoRoot+"/.git") &&
-					!strings.HasPrefix(path, currentKustomizeDir) {
-					// Do not process kubernetes YAML files under helm chart or kustomization directory
+				} else if _, err := os.Stat(path + "/kustomization.yml"); err == nil {
 					// If there are nested kustomizations or any other folder structures containing kube
 					// resources under a kustomization, subscription should not process them and let kustomize
 					// build handle them based on the top-level kustomization.yaml
-					crdsAndNamespaceFiles, rbacFiles, otherFiles, err = sortKubeResource(crdsAndNamespaceFiles, rbacFiles, otherFiles, path)
-					if err != nil {
-						klog.Error(err.Error())
-						return err
+					if !strings.HasPrefix(path, currentKustomizeDir) {
+						klog.V(4).Info("Found kustomization.yml in ", path)
+						currentKustomizeDir = path + "/"
+
+						if !skipped {
+							kustomizeDirs[path+"/"] = path + "/"
+						}
 					}
 				}
+			} else if !skipped &&
+				!strings.HasPrefix(path, currentChartDir) &&
+				!isGitDir(repoRoot, path) &&
+				!strings.HasPrefix(path, currentKustomizeDir) {
+				// Do not process kubernetes YAML files under helm chart or kustomization directory
+				// If there are nested kustomizations or any other folder structures containing kube
+				// resources under a kustomization, subscription should not process them and let kustomize
+				// build handle them based on the top-level kustomization.yaml
+				crdsAndNamespaceFiles, rbacFiles, otherFiles, err = sortKubeResource(crdsAndNamespaceFiles, rbacFiles, otherFiles, path)
+				if err != nil {
+					klog.Error(err.Error())
+					return err
+				}
 			}
 
 			return nil
@@ -807,6 +916,11 @@ func SortResources(repoRoot, resourcePath string, skips ...SkipFunc) (map[string
 	return chartDirs, kustomizeDirs, crdsAndNamespaceFiles, rbacFiles, otherFiles, err
 }
 
+// isGitDir returns true for the .git folder of the repo and the paths below it, not for the .github folder
+func isGitDir(repoRoot, path string) bool {
+	return path == repoRoot+"/.git" || strings.HasPrefix(path, repoRoot+"/.git/")
+}
+
 func sortKubeResource(crdsAndNamespaceFiles, rbacFiles, otherFiles []string, path string) ([]string, []string, []string, error) {
 	if strings.EqualFold(filepath.Ext(path), ".yml") || strings.EqualFold(filepath.Ext(path), ".yaml") {
 		klog.V(4).Info("Reading file: ", path)
@@ -880,6 +994,78 @@ func GetKubeIgnore(resourcePath string) *gitignore.GitIgnore {
 	return kubeIgnore
 }
 
+// AppSubIgnoreFile lists the paths of a git repo that the subscriptions don't deploy, in the .gitignore format
+const AppSubIgnoreFile = ".appsubignore"
+
+type appSubIgnore struct {
+	dir    string
+	ignore *gitignore.GitIgnore
+}
+
+// getAppSubIgnores compiles the .appsubignore files of the repo root and of the resource path. Like the .gitignore
+// files, the patterns of a file are relative to its directory
+func getAppSubIgnores(repoRoot, resourcePath string) []appSubIgnore {
+	ignores := []appSubIgnore{}
+	dirs := []string{filepath.Clean(repoRoot)}
+
+	if filepath.Clean(resourcePath) != dirs[0] {
+		dirs = append(dirs, filepath.Clean(resourcePath))
+	}
+
+	for _, dir := range dirs {
+		ignore, err := gitignore.CompileIgnoreFile(filepath.Join(dir, AppSubIgnoreFile))
+		if err != nil {
+			if !os.IsNotExist(err) {
+				klog.Errorf("failed to read %v in %v, err: %v", AppSubIgnoreFile, dir, err)
+			}
+
+			continue
+		}
+
+		klog.V(4).Infof("Found %v in %v", AppSubIgnoreFile, dir)
+
+		ignores = append(ignores, appSubIgnore{dir: dir, ignore: ignore})
+	}
+
+	return ignores
+}
+
+// matchAppSubIgnores checks a path of the repo against the .appsubignore files above it
+func matchAppSubIgnores(ignores []appSubIgnore, path string, isDir bool) bool {
+	for _, i := range ignores {
+		relPath, err := filepath.Rel(i.dir, path)
+		if err != nil || relPath == "." || strings.HasPrefix(relPath, "..") {
+			continue
+		}
+
+		relPath = filepath.ToSlash(relPath)
+
+		// the directory patterns like "docs/" only match the paths ending with a slash
+		if i.ignore.MatchesPath(relPath) || (isDir && i.ignore.MatchesPath(relPath+"/")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GitExcludePaths returns the path globs of the git-exclude-paths annotation of the channel
+func GitExcludePaths(chn *chnv1.Channel) []string {
+	if chn == nil {
+		return nil
+	}
+
+	globs := []string{}
+
+	for _, glob := range strings.Split(chn.GetAnnotations()[appv1.AnnotationGitExcludePaths], ",") {
+		if glob = strings.TrimSpace(glob); glob != "" {
+			globs = append(globs, glob)
+		}
+	}
+
+	return globs
+}
+
 // IsGitChannel returns true if channel type is github or git
 func IsGitChannel(chType string) bool {
 	return strings.EqualFold(chType, chnv1.ChannelTypeGitHub) ||
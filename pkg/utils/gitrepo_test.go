@@ -621,6 +621,68 @@ func TestNestedKustomize(t *testing.T) {
 	g.Expect(kustomizeDirs["../../test/github/nestedKustomize/wordpress2/"]).To(gomega.Equal("../../test/github/nestedKustomize/wordpress2/"))
 }
 
+func TestSortResourcesExcludedPaths(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	repoRoot := t.TempDir()
+	configMap := []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n")
+
+	files := map[string][]byte{
+		".appsubignore":           []byte("docs/\n*.fixture.yaml\n"),
+		"apps/.appsubignore":      []byte("test/\n"),
+		"apps/cm.yaml":            configMap,
+		"apps/ci.fixture.yaml":    configMap,
+		"apps/test/cm.yaml":       configMap,
+		"apps/docs/cm.yaml":       configMap,
+		"apps/ci/cm.yaml":         configMap,
+		"docs/cm.yaml":            configMap,
+		"test/cm.yaml":            configMap,
+		"ci/fixtures/cm.yaml":     configMap,
+		"ci/fixtures/app/cm.yaml": configMap,
+	}
+
+	for name, data := range files {
+		path := filepath.Join(repoRoot, name)
+		g.Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(gomega.Succeed())
+		g.Expect(os.WriteFile(path, data, 0600)).To(gomega.Succeed())
+	}
+
+	relPaths := func(paths []string) []string {
+		rel := []string{}
+
+		for _, p := range paths {
+			r, err := filepath.Rel(repoRoot, p)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			rel = append(rel, filepath.ToSlash(r))
+		}
+
+		return rel
+	}
+
+	chn := &chnv1.Channel{}
+	chn.SetAnnotations(map[string]string{appv1.AnnotationGitExcludePaths: "ci/**, apps/ci"})
+	g.Expect(GitExcludePaths(chn)).To(gomega.Equal([]string{"ci/**", "apps/ci"}))
+
+	skip := ExcludePathsSkipFunc(repoRoot, GitExcludePaths(chn), nil)
+
+	// the patterns of the .appsubignore files are relative to their directory
+	_, _, _, _, otherFiles, err := SortResources(repoRoot, filepath.Join(repoRoot, "apps"), skip)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(relPaths(otherFiles)).To(gomega.ConsistOf("apps/cm.yaml"))
+
+	// the .appsubignore file of a folder below the resource path is not used
+	_, _, _, _, otherFiles, err = SortResources(repoRoot, repoRoot, skip)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(relPaths(otherFiles)).To(gomega.ConsistOf("apps/cm.yaml", "apps/test/cm.yaml", "test/cm.yaml"))
+
+	// without the channel globs
+	_, _, _, _, otherFiles, err = SortResources(repoRoot, repoRoot)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(relPaths(otherFiles)).To(gomega.ConsistOf("apps/cm.yaml", "apps/test/cm.yaml", "apps/ci/cm.yaml",
+		"test/cm.yaml", "ci/fixtures/cm.yaml", "ci/fixtures/app/cm.yaml"))
+}
+
 func TestSimple(t *testing.T) {
 	g := gomega.NewGomegaWithT(t)
 	g.Expect("hello").To(gomega.Equal("hello"))
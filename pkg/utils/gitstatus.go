@@ -0,0 +1,107 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// GitCommitStatusPending is the status of a commit not deployed yet
+	GitCommitStatusPending = "pending"
+	// GitCommitStatusSuccess is the status of a commit deployed
+	GitCommitStatusSuccess = "success"
+	// GitCommitStatusFailure is the status of a commit failed to be deployed
+	GitCommitStatusFailure = "failure"
+)
+
+// GitCommitStatus is the deployment status of a commit in an environment, posted as a GitHub commit status or a
+// GitLab commit pipeline status
+type GitCommitStatus struct {
+	Provider string
+	RepoURL  string
+	Commit   string
+	// Context identifies the environment, a commit has one status per context
+	Context     string
+	State       string
+	Description string
+}
+
+// apiURL returns the provider API URL of the commit statuses
+func (s *GitCommitStatus) apiURL() (string, error) {
+	host, repoPath, err := parseGitRepoURL(s.RepoURL)
+	if err != nil {
+		return "", err
+	}
+
+	if s.Provider == GitProviderGitLab {
+		return fmt.Sprintf("%s/projects/%s/statuses/%s", gitAPIBase(s.Provider, host), url.PathEscape(repoPath), s.Commit), nil
+	}
+
+	return fmt.Sprintf("%s/repos/%s/statuses/%s", gitAPIBase(s.Provider, host), repoPath, s.Commit), nil
+}
+
+// body returns the request body of the commit status, gitlab names the failure state failed
+func (s *GitCommitStatus) body() ([]byte, error) {
+	if s.Provider == GitProviderGitLab {
+		state := s.State
+		if state == GitCommitStatusFailure {
+			state = "failed"
+		}
+
+		return json.Marshal(map[string]string{"state": state, "name": s.Context, "description": s.Description})
+	}
+
+	return json.Marshal(map[string]string{"state": s.State, "context": s.Context, "description": s.Description})
+}
+
+// Post creates the commit status with the provider API, the token is the channel secret accessToken
+func (s *GitCommitStatus) Post(token string, insecureSkipVerify bool) error {
+	apiURL, err := s.apiURL()
+	if err != nil {
+		return err
+	}
+
+	body, err := s.body()
+	if err != nil {
+		return err
+	}
+
+	req, err := newGitAPIRequest(http.MethodPost, apiURL, s.Provider, token, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	resp, err := doGitAPIRequest(gitAPIHTTPClient(insecureSkipVerify), req, s.Provider)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("failed to post commit status %v, status: %v", apiURL, resp.Status)
+	}
+
+	klog.V(1).Infof("commit status posted, %v %v: %v", apiURL, s.Context, s.State)
+
+	return nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestGitCommitStatusPost(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	posted := map[string]string{}
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/repos/org/app/statuses/abc123":
+			if r.Header.Get("Authorization") != "token secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+		case "/api/v4/projects/org/app/statuses/abc123":
+			if r.Header.Get("PRIVATE-TOKEN") != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		posted = map[string]string{}
+		g.Expect(json.NewDecoder(r.Body).Decode(&posted)).To(Succeed())
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	defer ts.Close()
+
+	status := &GitCommitStatus{
+		Provider: GitProviderGitHub,
+		RepoURL:  ts.URL + "/org/app.git",
+		Commit:   "abc123",
+		Context:  "open-cluster-management/app-ns/app/cluster1",
+		State:    GitCommitStatusFailure,
+	}
+
+	g.Expect(status.Post("secret", true)).To(Succeed())
+	g.Expect(posted).To(HaveKeyWithValue("state", "failure"))
+	g.Expect(posted).To(HaveKeyWithValue("context", "open-cluster-management/app-ns/app/cluster1"))
+
+	g.Expect(status.Post("wrong", true)).NotTo(Succeed())
+
+	status.Provider = GitProviderGitLab
+
+	g.Expect(status.Post("secret", true)).To(Succeed())
+	g.Expect(posted).To(HaveKeyWithValue("state", "failed"))
+	g.Expect(posted).To(HaveKeyWithValue("name", "open-cluster-management/app-ns/app/cluster1"))
+
+	status.Commit = "unknown"
+
+	g.Expect(status.Post("secret", true)).NotTo(Succeed())
+}
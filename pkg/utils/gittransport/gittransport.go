@@ -0,0 +1,128 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gittransport sends the http requests of each go-git clone with the http client of the clone. go-git has one
+// client per protocol for all the clones, replacing it for a clone changes the client of the other running clones.
+package gittransport
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"gopkg.in/src-d/go-git.v4/plumbing/transport"
+	gitclient "gopkg.in/src-d/go-git.v4/plumbing/transport/client"
+	githttp "gopkg.in/src-d/go-git.v4/plumbing/transport/http"
+)
+
+// cloneHeader carries the clone ID from the auth of the clone to the installed client, it is not sent to the server
+const cloneHeader = "X-Git-Clone-Id"
+
+var (
+	clients sync.Map
+	lastID  uint64
+
+	// client is the go-git http and https client, the requests of the clones are sent by their own client and the
+	// other requests by the default transport
+	client = &http.Client{
+		Transport: cloneTransport{},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			// the client of the clone already followed its redirects
+			if via[0].Header.Get(cloneHeader) != "" {
+				return http.ErrUseLastResponse
+			}
+
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+
+			return nil
+		},
+	}
+)
+
+func init() {
+	gitclient.InstallProtocol("http", githttp.NewClient(client))
+	gitclient.InstallProtocol("https", githttp.NewClient(client))
+}
+
+// cloneAuth is the http auth of a clone, it sets the basic auth of the clone and the clone ID of its client
+type cloneAuth struct {
+	auth githttp.AuthMethod
+	id   string
+}
+
+func (a *cloneAuth) Name() string {
+	if a.auth != nil {
+		return a.auth.Name()
+	}
+
+	return "http-clone-client"
+}
+
+func (a *cloneAuth) String() string {
+	if a.auth != nil {
+		return a.auth.String()
+	}
+
+	return fmt.Sprintf("%v - %v", a.Name(), a.id)
+}
+
+func (a *cloneAuth) SetAuth(r *http.Request) {
+	if a.auth != nil {
+		a.auth.SetAuth(r)
+	}
+
+	r.Header.Set(cloneHeader, a.id)
+}
+
+// NewAuth returns the go-git auth of a clone sending its requests with the http client c, auth is the basic auth of
+// the clone if any. Release the auth once the clone is done.
+func NewAuth(auth githttp.AuthMethod, c *http.Client) githttp.AuthMethod {
+	id := strconv.FormatUint(atomic.AddUint64(&lastID, 1), 10)
+
+	clients.Store(id, c)
+
+	return &cloneAuth{auth: auth, id: id}
+}
+
+// Release forgets the http client of a clone auth returned by NewAuth
+func Release(auth transport.AuthMethod) {
+	if a, ok := auth.(*cloneAuth); ok {
+		clients.Delete(a.id)
+	}
+}
+
+// cloneTransport sends the requests of the clones with the client of the clone
+type cloneTransport struct{}
+
+func (cloneTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	id := req.Header.Get(cloneHeader)
+	if id == "" {
+		return http.DefaultTransport.RoundTrip(req)
+	}
+
+	c, ok := clients.Load(id)
+	if !ok {
+		return nil, fmt.Errorf("the http client of git clone %v is released", id)
+	}
+
+	out := req.Clone(req.Context())
+	out.Header.Del(cloneHeader)
+
+	return c.(*http.Client).Do(out)
+}
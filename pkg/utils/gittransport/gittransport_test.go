@@ -0,0 +1,91 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gittransport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/onsi/gomega"
+	githttp "gopkg.in/src-d/go-git.v4/plumbing/transport/http"
+)
+
+// recordTransport counts the requests sent by a clone client
+type recordTransport struct {
+	requests int
+}
+
+func (t *recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestCloneClients(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	received := []*http.Request{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r)
+	}))
+
+	defer server.Close()
+
+	first, second := &recordTransport{}, &recordTransport{}
+	firstAuth := NewAuth(&githttp.BasicAuth{Username: "user", Password: "token"}, &http.Client{Transport: first})
+	secondAuth := NewAuth(nil, &http.Client{Transport: second})
+
+	get := func(auth githttp.AuthMethod) error {
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+
+		if auth != nil {
+			auth.SetAuth(req)
+		}
+
+		resp, err := client.Do(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+
+		return err
+	}
+
+	// each clone sends its requests with its own client
+	g.Expect(get(firstAuth)).To(gomega.Succeed())
+	g.Expect(get(secondAuth)).To(gomega.Succeed())
+	g.Expect(first.requests).To(gomega.Equal(1))
+	g.Expect(second.requests).To(gomega.Equal(1))
+
+	g.Expect(received).To(gomega.HaveLen(2))
+	g.Expect(received[0].Header.Get(cloneHeader)).To(gomega.BeEmpty())
+
+	user, password, ok := received[0].BasicAuth()
+	g.Expect(ok).To(gomega.BeTrue())
+	g.Expect(user).To(gomega.Equal("user"))
+	g.Expect(password).To(gomega.Equal("token"))
+
+	// the requests without a clone auth are sent with the default transport
+	g.Expect(get(nil)).To(gomega.Succeed())
+	g.Expect(received).To(gomega.HaveLen(3))
+	g.Expect(first.requests + second.requests).To(gomega.Equal(2))
+
+	// the released clients are not used anymore
+	Release(firstAuth)
+	g.Expect(get(firstAuth)).To(gomega.HaveOccurred())
+	g.Expect(get(secondAuth)).To(gomega.Succeed())
+	g.Expect(second.requests).To(gomega.Equal(2))
+}
@@ -0,0 +1,132 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"fmt"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// IsResourceHealthy checks if the deployed resource is ready. Deployments, StatefulSets and DaemonSets must have all
+// their replicas updated and available, Jobs must be complete, Argo Rollouts healthy and CRDs established. Other kinds are healthy once they
+// exist. The reason is returned if the resource is not healthy
+func IsResourceHealthy(c client.Reader, gvk schema.GroupVersionKind, key types.NamespacedName) (bool, string, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+
+	if err := c.Get(context.TODO(), key, obj); err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, "not found", nil
+		}
+
+		return false, "", err
+	}
+
+	healthy, reason := isObjectHealthy(obj)
+
+	return healthy, reason, nil
+}
+
+func isObjectHealthy(obj *unstructured.Unstructured) (bool, string) {
+	gvk := obj.GroupVersionKind()
+
+	if gvk.Group == "apps" && (gvk.Kind == "Deployment" || gvk.Kind == "StatefulSet" || gvk.Kind == "DaemonSet") {
+		observed, _, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+		if observed < obj.GetGeneration() {
+			return false, "the latest spec is not observed yet"
+		}
+	}
+
+	switch {
+	case gvk.Group == "apps" && gvk.Kind == "Deployment":
+		replicas := desiredReplicas(obj)
+		updated, _, _ := unstructured.NestedInt64(obj.Object, "status", "updatedReplicas")
+		available, _, _ := unstructured.NestedInt64(obj.Object, "status", "availableReplicas")
+
+		if updated < replicas || available < replicas {
+			return false, fmt.Sprintf("%v of %v replicas are updated and %v are available", updated, replicas, available)
+		}
+	case gvk.Group == "apps" && gvk.Kind == "StatefulSet":
+		replicas := desiredReplicas(obj)
+		ready, _, _ := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+
+		if ready < replicas {
+			return false, fmt.Sprintf("%v of %v replicas are ready", ready, replicas)
+		}
+	case gvk.Group == "apps" && gvk.Kind == "DaemonSet":
+		desired, _, _ := unstructured.NestedInt64(obj.Object, "status", "desiredNumberScheduled")
+		updated, _, _ := unstructured.NestedInt64(obj.Object, "status", "updatedNumberScheduled")
+		ready, _, _ := unstructured.NestedInt64(obj.Object, "status", "numberReady")
+
+		if updated < desired || ready < desired {
+			return false, fmt.Sprintf("%v of %v pods are updated and %v are ready", updated, desired, ready)
+		}
+	case gvk.Group == "batch" && gvk.Kind == "Job":
+		if !hasTrueCondition(obj, "Complete") {
+			return false, "the job is not complete"
+		}
+	case gvk.Group == ArgoRolloutGVK.Group && gvk.Kind == ArgoRolloutGVK.Kind:
+		phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+		if phase == "" {
+			return false, "the rollout is not observed yet"
+		}
+
+		if phase != "Healthy" {
+			if message, _, _ := unstructured.NestedString(obj.Object, "status", "message"); message != "" {
+				return false, fmt.Sprintf("the rollout is %v: %v", phase, message)
+			}
+
+			return false, fmt.Sprintf("the rollout is %v", phase)
+		}
+	case gvk.Group == "apiextensions.k8s.io" && gvk.Kind == "CustomResourceDefinition":
+		if !hasTrueCondition(obj, "Established") {
+			return false, "the CRD is not established"
+		}
+	}
+
+	return true, ""
+}
+
+func desiredReplicas(obj *unstructured.Unstructured) int64 {
+	replicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	if !found {
+		return 1
+	}
+
+	return replicas
+}
+
+func hasTrueCondition(obj *unstructured.Unstructured, condType string) bool {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if cond["type"] == condType && cond["status"] == "True" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,133 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/client-go/discovery"
+)
+
+const (
+	// AgentHeartbeatLeaseName is the Lease the agent renews in its cluster namespace on the hub
+	AgentHeartbeatLeaseName = "application-manager-heartbeat"
+	// LabelAgentHeartbeat labels the heartbeat Leases so the hub watches them only
+	LabelAgentHeartbeat = "apps.open-cluster-management.io/agent-heartbeat"
+
+	// AnnotationAgentVersion is the version of the agent on the heartbeat Lease
+	AnnotationAgentVersion = "apps.open-cluster-management.io/agent-version"
+	// AnnotationAgentLastSyncTime is the last time the agent synced the status of an appsub, in RFC3339
+	AnnotationAgentLastSyncTime = "apps.open-cluster-management.io/agent-last-sync-time"
+	// AnnotationAgentHealth is Healthy or Degraded
+	AnnotationAgentHealth = "apps.open-cluster-management.io/agent-health"
+	// AnnotationAgentHealthMessage is the error of the last appsub status sync while the agent is degraded
+	AnnotationAgentHealthMessage = "apps.open-cluster-management.io/agent-health-message"
+	// AnnotationAgentKubeVersion is the Kubernetes version of the managed cluster, the hub renders the Helm charts with it
+	AnnotationAgentKubeVersion = "apps.open-cluster-management.io/agent-kube-version"
+	// AnnotationAgentAPIVersions is the comma separated API versions served by the managed cluster, with and without
+	// their kinds, the hub renders the Helm charts with them
+	AnnotationAgentAPIVersions = "apps.open-cluster-management.io/agent-api-versions"
+
+	AgentHealthy  = "Healthy"
+	AgentDegraded = "Degraded"
+
+	// the health message is an annotation, the long errors are cut
+	maxAgentHealthMessageLength = 256
+)
+
+var (
+	agentSyncLock     sync.RWMutex
+	agentLastSyncTime time.Time
+	agentLastSyncErr  error
+)
+
+// RecordAgentSync records the result of the last appsub status sync of the agent for its heartbeat
+func RecordAgentSync(err error) {
+	agentSyncLock.Lock()
+	defer agentSyncLock.Unlock()
+
+	agentLastSyncErr = err
+
+	if err == nil {
+		agentLastSyncTime = time.Now()
+	}
+}
+
+// AgentHeartbeatAnnotations returns the version, the last sync time and the health of the agent for its heartbeat Lease
+func AgentHeartbeatAnnotations() map[string]string {
+	agentSyncLock.RLock()
+	defer agentSyncLock.RUnlock()
+
+	annotations := map[string]string{
+		AnnotationAgentVersion: Version(),
+		AnnotationAgentHealth:  AgentHealthy,
+	}
+
+	if !agentLastSyncTime.IsZero() {
+		annotations[AnnotationAgentLastSyncTime] = agentLastSyncTime.UTC().Format(time.RFC3339)
+	}
+
+	if agentLastSyncErr != nil {
+		msg := agentLastSyncErr.Error()
+		if len(msg) > maxAgentHealthMessageLength {
+			msg = msg[:maxAgentHealthMessageLength]
+		}
+
+		annotations[AnnotationAgentHealth] = AgentDegraded
+		annotations[AnnotationAgentHealthMessage] = msg
+	}
+
+	return annotations
+}
+
+// ClusterCapabilitiesAnnotations returns the Kubernetes version and the API versions served by the cluster for the
+// heartbeat Lease, the hub renders the Helm charts of the cluster with them. The API versions are the group versions
+// and the group versions with their kinds, the same as the capabilities of Helm on the cluster
+func ClusterCapabilitiesAnnotations(dc discovery.DiscoveryInterface) (map[string]string, error) {
+	kubeVersion, err := dc.ServerVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	versionSet, err := action.GetVersionSet(dc)
+	if err != nil {
+		return nil, err
+	}
+
+	apiVersions := append([]string{}, versionSet...)
+
+	sort.Strings(apiVersions)
+
+	return map[string]string{
+		AnnotationAgentKubeVersion: kubeVersion.GitVersion,
+		AnnotationAgentAPIVersions: strings.Join(apiVersions, ","),
+	}, nil
+}
+
+// IsAgentHeartbeatExpired checks if the agent hasn't renewed its heartbeat Lease for the timeout
+func IsAgentHeartbeatExpired(lease *coordinationv1.Lease, timeout time.Duration, now time.Time) bool {
+	renewTime := lease.GetCreationTimestamp().Time
+
+	if lease.Spec.RenewTime != nil {
+		renewTime = lease.Spec.RenewTime.Time
+	}
+
+	return now.Sub(renewTime) > timeout
+}
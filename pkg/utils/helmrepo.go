@@ -21,6 +21,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	semver "github.com/Masterminds/semver/v3"
@@ -33,6 +34,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -112,7 +114,7 @@ func createSource(channel *chnv1.Channel, chartVersions repo.ChartVersions, sub
 		var validURLs []string
 
 		for _, url := range chartVersions[0].URLs {
-			if IsURL(url) {
+			if IsURL(url) || IsBundleChartURL(url) {
 				validURLs = append(validURLs, url)
 			} else if IsURL(channel.Spec.Pathname + "/" + url) {
 				validURLs = append(validURLs, channel.Spec.Pathname+"/"+url)
@@ -126,7 +128,8 @@ func createSource(channel *chnv1.Channel, chartVersions repo.ChartVersions, sub
 		source = &releasev1.Source{
 			SourceType: releasev1.HelmRepoSourceType,
 			HelmRepo: &releasev1.HelmRepo{
-				Urls: validURLs,
+				Urls:    mirrorHelmRepoChartURLs(channel, validURLs),
+				Mirrors: helmReleaseMirrors(channel),
 			},
 		}
 	}
@@ -150,7 +153,7 @@ func createAltSource(channel *chnv1.Channel, chartVersions repo.ChartVersions, s
 		var validURLs []string
 
 		for _, url := range chartVersions[0].URLs {
-			if IsURL(url) {
+			if IsURL(url) || IsBundleChartURL(url) {
 				validURLs = append(validURLs, url)
 			} else if IsURL(channel.Spec.Pathname + "/" + url) {
 				validURLs = append(validURLs, channel.Spec.Pathname+"/"+url)
@@ -164,7 +167,8 @@ func createAltSource(channel *chnv1.Channel, chartVersions repo.ChartVersions, s
 		altSource = &releasev1.AltSource{
 			SourceType: releasev1.HelmRepoSourceType,
 			HelmRepo: &releasev1.HelmRepo{
-				Urls: validURLs,
+				Urls:    mirrorHelmRepoChartURLs(channel, validURLs),
+				Mirrors: helmReleaseMirrors(channel),
 			},
 		}
 	}
@@ -314,9 +318,54 @@ func CreateOrUpdateHelmChart(
 		}
 	}
 
+	if err := setPackageRelease(helmRelease, sub, packageName); err != nil {
+		return nil, err
+	}
+
 	return helmRelease, nil
 }
 
+// setPackageRelease sets the helm release name, the target namespace and the CRD upgrade of the package overrides to the
+// HelmRelease
+func setPackageRelease(helmRelease *releasev1.HelmRelease, sub *appv1.Subscription, packageName string) error {
+	for _, overrides := range sub.Spec.PackageOverrides {
+		if overrides.PackageName != packageName {
+			continue
+		}
+
+		if err := ValidatePackageRelease(overrides); err != nil {
+			return err
+		}
+
+		helmRelease.Repo.ReleaseName = overrides.ReleaseName
+		helmRelease.Repo.TargetNamespace = overrides.TargetNamespace
+		helmRelease.Repo.TargetNamespaceLabels = overrides.TargetNamespaceLabels
+		helmRelease.Repo.UpgradeCRDs = overrides.UpgradeCRDs
+
+		return nil
+	}
+
+	return nil
+}
+
+// ValidatePackageRelease checks the helm release name and the target namespace of the package overrides
+func ValidatePackageRelease(overrides *appv1.Overrides) error {
+	if overrides.ReleaseName != "" {
+		if err := chartutil.ValidateReleaseName(overrides.ReleaseName); err != nil {
+			return fmt.Errorf("invalid release name %v of package %v: %w", overrides.ReleaseName, overrides.PackageName, err)
+		}
+	}
+
+	if overrides.TargetNamespace != "" {
+		if errs := validation.IsDNS1123Label(overrides.TargetNamespace); len(errs) > 0 {
+			return fmt.Errorf("invalid target namespace %v of package %v: %v", overrides.TargetNamespace, overrides.PackageName,
+				strings.Join(errs, ", "))
+		}
+	}
+
+	return nil
+}
+
 func Override(helmRelease *releasev1.HelmRelease, sub *appv1.Subscription) error {
 	//Overrides with the values provided in the subscription for that package
 	overrides := getOverrides(helmRelease.Repo.ChartName, sub)
@@ -382,7 +431,7 @@ func PkgToReleaseCRName(sub *appv1.Subscription, packageName string) (string, er
 		}
 	}
 
-	releaseCRName, err := GetReleaseName(releaseCRName)
+	releaseCRName, err := GetReleaseName(releaseCRName + GetPreviewSuffix(sub))
 	if err != nil {
 		return "", err
 	}
@@ -467,6 +516,25 @@ func CreateHelmCRManifest(
 
 		rscAnnotations[appv1.AnnotationClusterAdmin] = "true"
 		helmRelease.SetAnnotations(rscAnnotations)
+	} else if helmRelease.Repo.TargetNamespace != "" {
+		// only the cluster admin subscriptions deploy outside of the subscription namespace
+		klog.Warningf("ignore the target namespace %v of package %v, subscription %v/%v is not cluster-admin",
+			helmRelease.Repo.TargetNamespace, packageName, sub.Namespace, sub.Name)
+
+		helmRelease.Repo.TargetNamespace = ""
+		helmRelease.Repo.TargetNamespaceLabels = nil
+	}
+
+	// the helmrelease controller rewrites the images of the rendered chart
+	if mirrorCM := sub.GetAnnotations()[appv1.AnnotationImageMirrorConfigMap]; mirrorCM != "" {
+		rscAnnotations := helmRelease.GetAnnotations()
+
+		if rscAnnotations == nil {
+			rscAnnotations = make(map[string]string)
+		}
+
+		rscAnnotations[appv1.AnnotationImageMirrorConfigMap] = mirrorCM
+		helmRelease.SetAnnotations(rscAnnotations)
 	}
 
 	helmReleaseRaw, err := json.Marshal(helmRelease)
@@ -542,8 +610,8 @@ func takeLatestVersion(indexFile *repo.IndexFile) (err error) {
 		//Get return the latest version when version is empty but
 		//there is a bug in the masterminds semver used by helm
 		// "*" constraint is not working properly
-		// "*" is equivalent to ">=0.0.0"
-		chartVersion, err := indexFile.Get(k, ">=0.0.0")
+		// ">=0.0.0-0" is "*" including the prereleases, they are already filtered by checkVersion
+		chartVersion, err := indexFile.Get(k, ">=0.0.0-0")
 		if err != nil {
 			klog.Error(err)
 			return err
@@ -555,6 +623,26 @@ func takeLatestVersion(indexFile *repo.IndexFile) (err error) {
 	return nil
 }
 
+// ChartIndexRevision returns the sorted <chart>:<version> of the charts of the filtered indexFile, it is the revision
+// of a helm repo subscription
+func ChartIndexRevision(indexFile *repo.IndexFile) string {
+	if indexFile == nil {
+		return ""
+	}
+
+	charts := []string{}
+
+	for name, chartVersions := range indexFile.Entries {
+		if len(chartVersions) > 0 && chartVersions[0] != nil {
+			charts = append(charts, name+":"+chartVersions[0].Version)
+		}
+	}
+
+	sort.Strings(charts)
+
+	return strings.Join(charts, ",")
+}
+
 // checkDigest Checks if the digest matches
 func checkDigest(sub *appv1.Subscription, chartVersion *repo.ChartVersion) bool {
 	if sub != nil {
@@ -572,23 +660,36 @@ func checkDigest(sub *appv1.Subscription, chartVersion *repo.ChartVersion) bool
 	return true
 }
 
-// removeNoMatchingName Deletes entries that the name doesn't match the name provided in the subscription
+// removeNoMatchingName Deletes entries that the name doesn't match the name provided in the subscription, the charts of
+// the package filter are kept along with the spec.package chart
 func removeNoMatchingName(sub *appv1.Subscription, indexFile *repo.IndexFile) error {
+	names := map[string]bool{}
+
 	if sub.Spec.Package != "" {
-		keys := make([]string, 0)
-		for k := range indexFile.Entries {
-			keys = append(keys, k)
-		}
+		names[sub.Spec.Package] = true
+	}
 
-		for _, k := range keys {
-			if k != sub.Spec.Package {
-				delete(indexFile.Entries, k)
-			}
+	if sub.Spec.PackageFilter != nil {
+		for _, chart := range sub.Spec.PackageFilter.Charts {
+			names[chart.Name] = true
 		}
-	} else {
+	}
+
+	if len(names) == 0 {
 		return fmt.Errorf("subsciption.spec.package is missing for subscription: %s/%s", sub.Namespace, sub.Name)
 	}
 
+	keys := make([]string, 0)
+	for k := range indexFile.Entries {
+		keys = append(keys, k)
+	}
+
+	for _, k := range keys {
+		if !names[k] {
+			delete(indexFile.Entries, k)
+		}
+	}
+
 	klog.V(4).Info("After name matching:", indexFile)
 
 	return nil
@@ -607,7 +708,8 @@ func filterOnVersion(sub *appv1.Subscription, indexFile *repo.IndexFile) {
 		newChartVersions := make([]*repo.ChartVersion, 0)
 
 		for index, chartVersion := range chartVersions {
-			if checkKeywords(sub, chartVersion) && checkDigest(sub, chartVersion) && checkVersion(sub, chartVersion) {
+			if checkKeywords(sub, chartVersion) && checkDigest(sub, chartVersion) && checkVersion(sub, chartVersion) &&
+				checkChartFilter(sub, chartVersion) {
 				newChartVersions = append(newChartVersions, chartVersions[index])
 			}
 		}
@@ -632,32 +734,138 @@ func checkKeywords(sub *appv1.Subscription, chartVersion *repo.ChartVersion) boo
 	return KeywordsChecker(labelSelector, chartVersion.Keywords)
 }
 
-// checkVersion checks if the version matches
-func checkVersion(sub *appv1.Subscription, chartVersion *repo.ChartVersion) bool {
-	if sub.Spec.PackageFilter != nil {
-		if sub.Spec.PackageFilter.Version != "" {
-			version := chartVersion.Version
-			versionVersion, err := semver.NewVersion(version)
+// checkChartFilter checks the chart name regex and annotation selector of the packageFilter.
+// The exact annotations are not checked here, they hold the digest filter of the charts
+func checkChartFilter(sub *appv1.Subscription, chartVersion *repo.ChartVersion) bool {
+	filter := sub.Spec.PackageFilter
+	if filter == nil {
+		return true
+	}
 
-			if err != nil {
-				klog.Error(err)
-				return false
-			}
+	if !MatchPackageNameRegex(filter, chartVersion.Name) {
+		return false
+	}
 
-			filterVersion, err := semver.NewConstraint(sub.Spec.PackageFilter.Version)
+	if filter.AnnotationSelector != nil && !LabelChecker(filter.AnnotationSelector, chartVersion.Annotations) {
+		return false
+	}
 
-			if err != nil {
-				klog.Error(err)
-				return false
+	return true
+}
+
+// FilterChartsByPath removes the chart versions whose URL path doesn't match the includePaths and excludePaths of
+// the packageFilter. It applies to helm repo indexes, the git charts are filtered by their directory instead
+func FilterChartsByPath(sub *appv1.Subscription, indexFile *repo.IndexFile) {
+	filter := sub.Spec.PackageFilter
+	if filter == nil || (len(filter.IncludePaths) == 0 && len(filter.ExcludePaths) == 0) {
+		return
+	}
+
+	for name, chartVersions := range indexFile.Entries {
+		matched := make([]*repo.ChartVersion, 0, len(chartVersions))
+
+		for _, chartVersion := range chartVersions {
+			if len(chartVersion.URLs) > 0 && MatchPackagePath(filter, chartURLPath(chartVersion.URLs[0])) {
+				matched = append(matched, chartVersion)
 			}
+		}
 
-			return filterVersion.Check(versionVersion)
+		if len(matched) > 0 {
+			indexFile.Entries[name] = matched
+		} else {
+			delete(indexFile.Entries, name)
 		}
 	}
+}
 
-	klog.V(4).Info("Version check passed for:", chartVersion)
+// checkVersion checks if the version matches the semver constraint of the chart in the package filter charts, or the
+// version of the package filter. The prereleases only match when the package filter includes the prereleases, or when
+// the constraint has a prerelease, e.g. ">=1.2.0-0"
+func checkVersion(sub *appv1.Subscription, chartVersion *repo.ChartVersion) bool {
+	versionVersion, err := semver.NewVersion(chartVersion.Version)
+	if err != nil {
+		klog.Error(err)
+		return false
+	}
 
-	return true
+	includePrereleases := sub.Spec.PackageFilter != nil && sub.Spec.PackageFilter.IncludePrereleases
+
+	filterVersion := getChartFilterVersion(sub, chartVersion.Name)
+	if filterVersion == "" {
+		klog.V(4).Info("Version check passed for:", chartVersion)
+
+		// the latest stable version is deployed without a version constraint
+		return versionVersion.Prerelease() == "" || includePrereleases
+	}
+
+	constraint, err := semver.NewConstraint(filterVersion)
+	if err != nil {
+		klog.Error(err)
+		return false
+	}
+
+	if constraint.Check(versionVersion) {
+		return true
+	}
+
+	// a prerelease matches the constraint of its release version, e.g. 1.3.0-rc.1 matches "~1.3"
+	if includePrereleases && versionVersion.Prerelease() != "" {
+		releaseVersion, err := versionVersion.SetPrerelease("")
+		if err == nil {
+			return constraint.Check(&releaseVersion)
+		}
+	}
+
+	return false
+}
+
+// getChartFilterVersion returns the version of the chart in the package filter charts, the version of the package
+// filter is returned if the chart has no version
+func getChartFilterVersion(sub *appv1.Subscription, chartName string) string {
+	if sub.Spec.PackageFilter == nil {
+		return ""
+	}
+
+	for _, chart := range sub.Spec.PackageFilter.Charts {
+		if chart.Name == chartName && chart.Version != "" {
+			return chart.Version
+		}
+	}
+
+	return sub.Spec.PackageFilter.Version
+}
+
+// IsHelmVersionHold checks if the chart versions of the subscription are frozen by the helm-version-hold annotation
+func IsHelmVersionHold(sub *appv1.Subscription) bool {
+	return strings.EqualFold(sub.GetAnnotations()[appv1.AnnotationHelmVersionHold], "true")
+}
+
+// GetHeldPackageFilter returns the package filter of the subscription with its charts pinned to the versions of the
+// status resolvedVersions when the versions are on hold, the package filter of the spec is returned otherwise
+func GetHeldPackageFilter(sub *appv1.Subscription) *appv1.PackageFilter {
+	if !IsHelmVersionHold(sub) || len(sub.Status.ResolvedVersions) == 0 {
+		return sub.Spec.PackageFilter
+	}
+
+	filter := &appv1.PackageFilter{}
+	if sub.Spec.PackageFilter != nil {
+		filter = sub.Spec.PackageFilter.DeepCopy()
+	}
+
+	pinned := map[string]bool{}
+
+	for i, chart := range filter.Charts {
+		if version, ok := sub.Status.ResolvedVersions[chart.Name]; ok {
+			filter.Charts[i].Version = version
+			pinned[chart.Name] = true
+		}
+	}
+
+	if version, ok := sub.Status.ResolvedVersions[sub.Spec.Package]; ok && sub.Spec.Package != "" && !pinned[sub.Spec.Package] {
+		filter.Charts = append(filter.Charts, appv1.ChartFilter{Name: sub.Spec.Package, Version: version})
+	}
+
+	return filter
 }
 
 // DeleteHelmReleaseCRD deletes the HelmRelease CRD
@@ -21,6 +21,7 @@ import (
 
 	"github.com/ghodss/yaml"
 	"github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/repo"
 	corev1 "k8s.io/api/core/v1"
 	clientsetx "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
@@ -87,6 +88,45 @@ func TestGetPackageAlias(t *testing.T) {
 	g.Expect(pkgAlias).To(gomega.Equal("pkgName2Alias"))
 }
 
+func TestSetPackageRelease(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx-sub", Namespace: "default"},
+		Spec: appv1.SubscriptionSpec{
+			PackageOverrides: []*appv1.Overrides{
+				{
+					PackageName:           "nginx",
+					ReleaseName:           "nginx-frontend",
+					TargetNamespace:       "frontend",
+					TargetNamespaceLabels: map[string]string{"team": "web"},
+					UpgradeCRDs:           true,
+				},
+			},
+		},
+	}
+
+	hr := &releasev1.HelmRelease{}
+	g.Expect(setPackageRelease(hr, sub, "nginx")).To(gomega.Succeed())
+	g.Expect(hr.GetReleaseName()).To(gomega.Equal("nginx-frontend"))
+	g.Expect(hr.GetTargetNamespace()).To(gomega.Equal("frontend"))
+	g.Expect(hr.Repo.TargetNamespaceLabels).To(gomega.Equal(map[string]string{"team": "web"}))
+	g.Expect(hr.Repo.UpgradeCRDs).To(gomega.BeTrue())
+
+	// the defaults are the name and the namespace of the HelmRelease
+	hr = &releasev1.HelmRelease{ObjectMeta: metav1.ObjectMeta{Name: "redis-12345", Namespace: "default"}}
+	g.Expect(setPackageRelease(hr, sub, "redis")).To(gomega.Succeed())
+	g.Expect(hr.GetReleaseName()).To(gomega.Equal("redis-12345"))
+	g.Expect(hr.GetTargetNamespace()).To(gomega.Equal("default"))
+
+	sub.Spec.PackageOverrides[0].ReleaseName = "Nginx_Frontend"
+	g.Expect(setPackageRelease(hr, sub, "nginx")).NotTo(gomega.Succeed())
+
+	sub.Spec.PackageOverrides[0].ReleaseName = ""
+	sub.Spec.PackageOverrides[0].TargetNamespace = "front.end"
+	g.Expect(setPackageRelease(hr, sub, "nginx")).NotTo(gomega.Succeed())
+}
+
 func TestGenerateHelmIndexFile(t *testing.T) {
 	g := gomega.NewGomegaWithT(t)
 
@@ -207,6 +247,146 @@ func TestCreateOrUpdateHelmChart(t *testing.T) {
 			"https://charts.helm.sh/stable/packages/nginx-ingress-1.36.3.tgz"))
 }
 
+func TestFilterChartsByChartFilters(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	newIndexFile := func() *repo.IndexFile {
+		indexFile := &repo.IndexFile{Entries: map[string]repo.ChartVersions{}}
+
+		for _, cv := range [][2]string{{"nginx", "1.0.0"}, {"nginx", "1.1.0"}, {"nginx", "2.0.0"},
+			{"redis", "5.0.0"}, {"redis", "6.0.0"}, {"mysql", "8.0.0"}} {
+			indexFile.Entries[cv[0]] = append(indexFile.Entries[cv[0]], &repo.ChartVersion{
+				Metadata: &chart.Metadata{Name: cv[0], Version: cv[1]},
+				URLs:     []string{cv[0] + "-" + cv[1] + ".tgz"},
+			})
+		}
+
+		return indexFile
+	}
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "charts-sub", Namespace: "default"},
+		Spec: appv1.SubscriptionSpec{
+			Package: "nginx",
+			PackageFilter: &appv1.PackageFilter{
+				Version: "1.x",
+				Charts:  []appv1.ChartFilter{{Name: "redis", Version: "5.x"}, {Name: "mysql"}},
+			},
+		},
+	}
+
+	indexFile := newIndexFile()
+	g.Expect(FilterCharts(sub, indexFile)).To(gomega.Succeed())
+	g.Expect(indexFile.Entries).To(gomega.HaveLen(2))
+	g.Expect(indexFile.Entries["nginx"][0].Version).To(gomega.Equal("1.1.0"))
+	g.Expect(indexFile.Entries["redis"][0].Version).To(gomega.Equal("5.0.0"))
+
+	// the charts without version use the package filter version
+	sub.Spec.PackageFilter.Version = ""
+
+	indexFile = newIndexFile()
+	g.Expect(FilterCharts(sub, indexFile)).To(gomega.Succeed())
+	g.Expect(indexFile.Entries).To(gomega.HaveLen(3))
+	g.Expect(indexFile.Entries["nginx"][0].Version).To(gomega.Equal("2.0.0"))
+	g.Expect(indexFile.Entries["mysql"][0].Version).To(gomega.Equal("8.0.0"))
+
+	// the spec.package is not required with the charts of the package filter
+	sub.Spec.Package = ""
+
+	indexFile = newIndexFile()
+	g.Expect(FilterCharts(sub, indexFile)).To(gomega.Succeed())
+	g.Expect(indexFile.Entries).To(gomega.HaveLen(2))
+	g.Expect(indexFile.Entries).To(gomega.HaveKey("redis"))
+	g.Expect(indexFile.Entries).To(gomega.HaveKey("mysql"))
+}
+
+func TestCheckVersionConstraints(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	sub := &appv1.Subscription{Spec: appv1.SubscriptionSpec{Package: "nginx"}}
+
+	matches := func(version string) bool {
+		return checkVersion(sub, &repo.ChartVersion{Metadata: &chart.Metadata{Name: "nginx", Version: version}})
+	}
+
+	// the prereleases are not deployed by default
+	g.Expect(matches("1.2.3")).To(gomega.BeTrue())
+	g.Expect(matches("1.3.0-rc.1")).To(gomega.BeFalse())
+	g.Expect(matches("latest")).To(gomega.BeFalse())
+
+	sub.Spec.PackageFilter = &appv1.PackageFilter{Version: "~1.2"}
+	g.Expect(matches("1.2.9")).To(gomega.BeTrue())
+	g.Expect(matches("1.3.0")).To(gomega.BeFalse())
+
+	sub.Spec.PackageFilter.Version = ">=1.2 <2"
+	g.Expect(matches("1.9.0")).To(gomega.BeTrue())
+	g.Expect(matches("1.3.0-rc.1")).To(gomega.BeFalse())
+	g.Expect(matches("2.0.0")).To(gomega.BeFalse())
+
+	sub.Spec.PackageFilter.IncludePrereleases = true
+	g.Expect(matches("1.3.0-rc.1")).To(gomega.BeTrue())
+	g.Expect(matches("2.0.0-rc.1")).To(gomega.BeFalse())
+
+	sub.Spec.PackageFilter = &appv1.PackageFilter{Version: ">=1.3.0-0"}
+	g.Expect(matches("1.3.0-rc.1")).To(gomega.BeTrue())
+
+	sub.Spec.PackageFilter.Version = "not a constraint"
+	g.Expect(matches("1.2.3")).To(gomega.BeFalse())
+
+	indexFile := &repo.IndexFile{Entries: map[string]repo.ChartVersions{}}
+
+	for _, version := range []string{"1.2.0", "1.3.0-rc.1", "1.2.5"} {
+		indexFile.Entries["nginx"] = append(indexFile.Entries["nginx"], &repo.ChartVersion{
+			Metadata: &chart.Metadata{Name: "nginx", Version: version},
+		})
+	}
+
+	sub.Spec.PackageFilter = &appv1.PackageFilter{IncludePrereleases: true}
+	g.Expect(FilterCharts(sub, indexFile)).To(gomega.Succeed())
+	g.Expect(indexFile.Entries["nginx"][0].Version).To(gomega.Equal("1.3.0-rc.1"))
+}
+
+func TestGetHeldPackageFilter(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: appv1.SubscriptionSpec{
+			Package: "nginx",
+			PackageFilter: &appv1.PackageFilter{
+				Version: "~1.2",
+				Charts:  []appv1.ChartFilter{{Name: "redis", Version: ">=17"}},
+			},
+		},
+		Status: appv1.SubscriptionStatus{
+			ResolvedVersions: map[string]string{"nginx": "1.2.5", "redis": "17.3.1"},
+		},
+	}
+
+	g.Expect(GetHeldPackageFilter(sub)).To(gomega.BeIdenticalTo(sub.Spec.PackageFilter))
+
+	sub.SetAnnotations(map[string]string{appv1.AnnotationHelmVersionHold: "true"})
+
+	filter := GetHeldPackageFilter(sub)
+	g.Expect(filter.Version).To(gomega.Equal("~1.2"))
+	g.Expect(filter.Charts).To(gomega.Equal([]appv1.ChartFilter{{Name: "redis", Version: "17.3.1"}, {Name: "nginx", Version: "1.2.5"}}))
+	g.Expect(sub.Spec.PackageFilter.Charts[0].Version).To(gomega.Equal(">=17"))
+
+	// the held versions are the only versions left after filtering
+	indexFile := &repo.IndexFile{Entries: map[string]repo.ChartVersions{}}
+
+	for _, version := range []string{"1.2.5", "1.2.6"} {
+		indexFile.Entries["nginx"] = append(indexFile.Entries["nginx"], &repo.ChartVersion{
+			Metadata: &chart.Metadata{Name: "nginx", Version: version},
+		})
+	}
+
+	heldSub := sub.DeepCopy()
+	heldSub.Spec.PackageFilter = filter
+	g.Expect(FilterCharts(heldSub, indexFile)).To(gomega.Succeed())
+	g.Expect(indexFile.Entries["nginx"][0].Version).To(gomega.Equal("1.2.5"))
+}
+
 func TestCheckVersion(t *testing.T) {
 	g := gomega.NewGomegaWithT(t)
 
@@ -489,3 +669,15 @@ func Test_generateGitChartPath(t *testing.T) {
 		})
 	}
 }
+
+func TestChartIndexRevision(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	indexFile := &repo.IndexFile{Entries: map[string]repo.ChartVersions{
+		"redis": {{Metadata: &chart.Metadata{Name: "redis", Version: "7.0.1"}}},
+		"nginx": {{Metadata: &chart.Metadata{Name: "nginx", Version: "1.2.0"}}},
+	}}
+
+	g.Expect(ChartIndexRevision(indexFile)).To(gomega.Equal("nginx:1.2.0,redis:7.0.1"))
+	g.Expect(ChartIndexRevision(nil)).To(gomega.BeEmpty())
+}
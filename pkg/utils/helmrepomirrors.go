@@ -0,0 +1,197 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	releasev1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/helmrelease/v1"
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+// GetHelmRepoMirrors returns the mirrors of the helm-repo-mirrors annotation of a helm repo channel
+func GetHelmRepoMirrors(chn *chnv1.Channel) ([]appv1.HelmRepoMirror, error) {
+	annotation := chn.GetAnnotations()[appv1.AnnotationHelmRepoMirrors]
+	if annotation == "" || !strings.EqualFold(string(chn.Spec.Type), chnv1.ChannelTypeHelmRepo) {
+		return nil, nil
+	}
+
+	mirrors := []appv1.HelmRepoMirror{}
+	if err := json.Unmarshal([]byte(annotation), &mirrors); err != nil {
+		return nil, fmt.Errorf("invalid %v annotation of channel %v/%v: %w", appv1.AnnotationHelmRepoMirrors,
+			chn.Namespace, chn.Name, err)
+	}
+
+	for _, mirror := range mirrors {
+		if !IsURL(mirror.URL) {
+			return nil, fmt.Errorf("invalid URL %q of the %v annotation of channel %v/%v", mirror.URL,
+				appv1.AnnotationHelmRepoMirrors, chn.Namespace, chn.Name)
+		}
+	}
+
+	return mirrors, nil
+}
+
+// getValidHelmRepoMirrors returns the mirrors of the channel, the invalid annotation is logged and ignored so the
+// channel keeps working from its pathname
+func getValidHelmRepoMirrors(chn *chnv1.Channel) []appv1.HelmRepoMirror {
+	mirrors, err := GetHelmRepoMirrors(chn)
+	if err != nil {
+		klog.Errorf("ignore the helm repo mirrors, err: %v", err)
+
+		return nil
+	}
+
+	return mirrors
+}
+
+// GetHelmRepoURLs returns the pathname of the helm repo channel followed by the URLs of its mirrors
+func GetHelmRepoURLs(chn *chnv1.Channel) []string {
+	urls := []string{chn.Spec.Pathname}
+
+	for _, mirror := range getValidHelmRepoMirrors(chn) {
+		urls = append(urls, mirror.URL)
+	}
+
+	return urls
+}
+
+// FetchHelmRepoMirrorSecrets retrieves the secrets of the mirrors of the channel in the namespace of the channel, by
+// mirror URL. The secrets not found are logged and left out.
+func FetchHelmRepoMirrorSecrets(clt client.Client, chn *chnv1.Channel) map[string]*corev1.Secret {
+	secrets := map[string]*corev1.Secret{}
+
+	for _, mirror := range getValidHelmRepoMirrors(chn) {
+		if mirror.SecretRef == nil || mirror.SecretRef.Name == "" {
+			continue
+		}
+
+		secret := &corev1.Secret{}
+		key := types.NamespacedName{Namespace: chn.Namespace, Name: mirror.SecretRef.Name}
+
+		if err := clt.Get(context.TODO(), key, secret); err != nil {
+			klog.Warningf("failed to get secret %v of helm repo mirror %v, err: %v", key, mirror.URL, err)
+
+			continue
+		}
+
+		secrets[mirror.URL] = secret
+	}
+
+	return secrets
+}
+
+// DeployHelmRepoMirrorSecret copies the secret of a helm repo mirror in the namespace of the subscription, owned by
+// the subscription, for the HelmRelease to download the charts from the mirror
+func DeployHelmRepoMirrorSecret(clt client.Client, sub *appv1.Subscription, secret *corev1.Secret) error {
+	// the secrets of the channels in the namespace of the subscription are used in place
+	if secret.Namespace == sub.Namespace {
+		return nil
+	}
+
+	existing := &corev1.Secret{}
+
+	err := clt.Get(context.TODO(), types.NamespacedName{Namespace: sub.Namespace, Name: secret.Name}, existing)
+	if errors.IsNotFound(err) {
+		mirrorSecret := &corev1.Secret{Type: secret.Type, Data: secret.Data}
+		mirrorSecret.Name = secret.Name
+		mirrorSecret.Namespace = sub.Namespace
+		mirrorSecret.SetOwnerReferences(addObjectOwnedBySub(mirrorSecret, sub))
+
+		return clt.Create(context.TODO(), mirrorSecret)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	owners := addObjectOwnedBySub(existing, sub)
+
+	if reflect.DeepEqual(existing.Data, secret.Data) && reflect.DeepEqual(existing.GetOwnerReferences(), owners) {
+		return nil
+	}
+
+	existing.Data = secret.Data
+	existing.SetOwnerReferences(owners)
+
+	return clt.Update(context.TODO(), existing)
+}
+
+// mirrorHelmRepoChartURLs adds the URLs of the charts on the mirrors of the channel after the chart URLs, the chart
+// URLs under the pathname or a mirror of the channel are moved under the other ones
+func mirrorHelmRepoChartURLs(chn *chnv1.Channel, urls []string) []string {
+	repoURLs := GetHelmRepoURLs(chn)
+	if len(repoURLs) == 1 {
+		return urls
+	}
+
+	mirrored := append([]string{}, urls...)
+
+	for _, chartURL := range urls {
+		for _, repoURL := range repoURLs {
+			prefix := strings.TrimSuffix(repoURL, "/") + "/"
+			if !strings.HasPrefix(chartURL, prefix) {
+				continue
+			}
+
+			for _, mirrorURL := range repoURLs {
+				mirrored = appendUnique(mirrored, strings.TrimSuffix(mirrorURL, "/")+"/"+strings.TrimPrefix(chartURL, prefix))
+			}
+
+			break
+		}
+	}
+
+	return mirrored
+}
+
+// helmReleaseMirrors returns the mirrors of the channel with their secret in the namespace of the channel, the
+// HelmRelease finds the secrets there on the hub and in the namespace of the subscription on the managed clusters
+func helmReleaseMirrors(chn *chnv1.Channel) []releasev1.HelmRepoMirror {
+	var mirrors []releasev1.HelmRepoMirror
+
+	for _, mirror := range getValidHelmRepoMirrors(chn) {
+		releaseMirror := releasev1.HelmRepoMirror{URL: mirror.URL}
+
+		if mirror.SecretRef != nil && mirror.SecretRef.Name != "" {
+			releaseMirror.SecretRef = &corev1.ObjectReference{Name: mirror.SecretRef.Name, Namespace: chn.Namespace}
+		}
+
+		mirrors = append(mirrors, releaseMirror)
+	}
+
+	return mirrors
+}
+
+func appendUnique(list []string, item string) []string {
+	for _, existing := range list {
+		if existing == item {
+			return list
+		}
+	}
+
+	return append(list, item)
+}
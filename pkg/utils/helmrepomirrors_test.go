@@ -0,0 +1,90 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/testharness"
+)
+
+func TestHelmRepoMirrors(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	chn := &chnv1.Channel{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "helm",
+			Namespace: "ch-ns",
+			Annotations: map[string]string{
+				appv1.AnnotationHelmRepoMirrors: `[{"url": "https://mirror.example.com/charts/", "secretRef": {"name": "mirror-auth"}},
+					{"url": "https://backup.example.com"}]`,
+			},
+		},
+		Spec: chnv1.ChannelSpec{Type: chnv1.ChannelTypeHelmRepo, Pathname: "https://charts.example.com/stable"},
+	}
+
+	g.Expect(GetHelmRepoURLs(chn)).To(Equal([]string{
+		"https://charts.example.com/stable", "https://mirror.example.com/charts/", "https://backup.example.com",
+	}))
+
+	// the chart URLs under the channel or a mirror are moved under the other ones, the other URLs are kept as is
+	g.Expect(mirrorHelmRepoChartURLs(chn, []string{
+		"https://mirror.example.com/charts/nginx-1.0.0.tgz", "https://github.com/org/repo/releases/nginx-1.0.0.tgz",
+	})).To(Equal([]string{
+		"https://mirror.example.com/charts/nginx-1.0.0.tgz",
+		"https://github.com/org/repo/releases/nginx-1.0.0.tgz",
+		"https://charts.example.com/stable/nginx-1.0.0.tgz",
+		"https://backup.example.com/nginx-1.0.0.tgz",
+	}))
+
+	mirrors := helmReleaseMirrors(chn)
+	g.Expect(mirrors).To(HaveLen(2))
+	g.Expect(mirrors[0].SecretRef).To(Equal(&corev1.ObjectReference{Name: "mirror-auth", Namespace: "ch-ns"}))
+	g.Expect(mirrors[1].SecretRef).To(BeNil())
+
+	// the mirror secrets are copied in the namespace of the subscription
+	clt, err := testharness.NewFakeClient(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "mirror-auth", Namespace: "ch-ns"},
+		Data:       map[string][]byte{"user": []byte("admin"), "password": []byte("secret")},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	secrets := FetchHelmRepoMirrorSecrets(clt, chn)
+	g.Expect(secrets).To(HaveKey("https://mirror.example.com/charts/"))
+
+	sub := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "sub-ns", UID: "sub-uid"}}
+	g.Expect(DeployHelmRepoMirrorSecret(clt, sub, secrets["https://mirror.example.com/charts/"])).To(Succeed())
+
+	copied := &corev1.Secret{}
+	g.Expect(clt.Get(context.TODO(), types.NamespacedName{Name: "mirror-auth", Namespace: "sub-ns"}, copied)).To(Succeed())
+	g.Expect(copied.Data).To(HaveKeyWithValue("password", []byte("secret")))
+	g.Expect(copied.OwnerReferences).To(HaveLen(1))
+	g.Expect(copied.OwnerReferences[0].UID).To(Equal(types.UID("sub-uid")))
+
+	// the invalid annotations are ignored
+	chn.Annotations[appv1.AnnotationHelmRepoMirrors] = `{"url": "https://mirror.example.com"}`
+
+	_, err = GetHelmRepoMirrors(chn)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(GetHelmRepoURLs(chn)).To(Equal([]string{"https://charts.example.com/stable"}))
+}
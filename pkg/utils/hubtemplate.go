@@ -0,0 +1,396 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ansiblejob "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/ansible/v1alpha1"
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+const (
+	hubTemplateStartDelim = "{{hub"
+	hubTemplateEndDelim   = "hub}}"
+)
+
+// hubTemplateRefRegexp matches the ConfigMaps and Secrets read with a quoted name by the hub templates
+var hubTemplateRefRegexp = regexp.MustCompile("(fromConfigMap|fromSecret)\\s+(?:\"([^\"]*)\"|`([^`]*)`)")
+
+// HubTemplateResolver resolves the {{hub ... hub}} templates of a subscription from the ConfigMaps and Secrets in
+// the subscription namespace on the hub. The templates can use:
+//
+//	fromConfigMap "name" "key" - the value of the key in the ConfigMap
+//	fromSecret "name" "key"    - the base64 encoded value of the key in the Secret
+//	fromHook "name" "key"      - the value of the key in the artifacts of the subscription prehook AnsibleJob
+//	base64enc, base64dec       - to encode or decode a value
+type HubTemplateResolver struct {
+	client    client.Reader
+	namespace string
+	// subscription is the name of the subscription of the prehooks, fromHook is not supported if it is empty
+	subscription string
+}
+
+// NewHubTemplateResolver returns a resolver reading the ConfigMaps and Secrets in the namespace
+func NewHubTemplateResolver(c client.Reader, namespace string) *HubTemplateResolver {
+	return &HubTemplateResolver{client: c, namespace: namespace}
+}
+
+// NewSubscriptionHubTemplateResolver returns a resolver reading the ConfigMaps, Secrets and prehook AnsibleJobs of
+// the subscription
+func NewSubscriptionHubTemplateResolver(c client.Reader, sub *appv1.Subscription) *HubTemplateResolver {
+	return &HubTemplateResolver{client: c, namespace: sub.GetNamespace(), subscription: sub.GetName()}
+}
+
+// HasHubTemplate checks if the text contains a hub template
+func HasHubTemplate(text string) bool {
+	return strings.Contains(text, hubTemplateStartDelim)
+}
+
+// Resolve resolves the hub templates in the text, the text is returned as is if it has no hub template
+func (r *HubTemplateResolver) Resolve(text string) (string, error) {
+	if !HasHubTemplate(text) {
+		return text, nil
+	}
+
+	tpl, err := template.New("hub").Delims(hubTemplateStartDelim, hubTemplateEndDelim).Option("missingkey=error").
+		Funcs(template.FuncMap{
+			"fromConfigMap": r.fromConfigMap,
+			"fromSecret":    r.fromSecret,
+			"fromHook":      r.fromHook,
+			"base64enc":     base64enc,
+			"base64dec":     base64dec,
+		}).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse the hub template: %w", err)
+	}
+
+	var buf bytes.Buffer
+
+	if err := tpl.Execute(&buf, nil); err != nil {
+		return "", fmt.Errorf("failed to resolve the hub template: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+func (r *HubTemplateResolver) fromConfigMap(name, key string) (string, error) {
+	cm := &corev1.ConfigMap{}
+
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: r.namespace}, cm); err != nil {
+		return "", err
+	}
+
+	value, ok := cm.Data[key]
+	if !ok {
+		return "", fmt.Errorf("key %v is not found in ConfigMap %v/%v", key, r.namespace, name)
+	}
+
+	return value, nil
+}
+
+func (r *HubTemplateResolver) fromSecret(name, key string) (string, error) {
+	secret := &corev1.Secret{}
+
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: r.namespace}, secret); err != nil {
+		return "", err
+	}
+
+	value, ok := secret.Data[key]
+	if !ok {
+		return "", fmt.Errorf("key %v is not found in Secret %v/%v", key, r.namespace, name)
+	}
+
+	return base64.StdEncoding.EncodeToString(value), nil
+}
+
+// fromHook returns the value of the key in the artifacts of the last prehook AnsibleJob created from the hook
+// named name in the prehook folder of the subscription, non string values are returned as JSON
+func (r *HubTemplateResolver) fromHook(name, key string) (string, error) {
+	if r.subscription == "" {
+		return "", fmt.Errorf("fromHook is only supported in subscriptions")
+	}
+
+	jobList := &ansiblejob.AnsibleJobList{}
+	if err := r.client.List(context.TODO(), jobList, client.InNamespace(r.namespace)); err != nil {
+		return "", err
+	}
+
+	hosting := types.NamespacedName{Name: r.subscription, Namespace: r.namespace}.String()
+
+	var lastJob *ansiblejob.AnsibleJob
+
+	for i := range jobList.Items {
+		job := &jobList.Items[i]
+		annotations := job.GetAnnotations()
+
+		// the prehook instances are named after the hook with the generation and commit suffix
+		if annotations[appv1.AnnotationHosting] != hosting || annotations[appv1.AnnotationHookType] != "prehook" ||
+			(job.GetName() != name && !strings.HasPrefix(job.GetName(), name+"-")) {
+			continue
+		}
+
+		if lastJob == nil || lastJob.CreationTimestamp.Before(&job.CreationTimestamp) {
+			lastJob = job
+		}
+	}
+
+	if lastJob == nil {
+		return "", fmt.Errorf("prehook %v of subscription %v is not found", name, hosting)
+	}
+
+	artifacts := map[string]interface{}{}
+
+	if len(lastJob.Status.Artifacts) > 0 {
+		if err := json.Unmarshal(lastJob.Status.Artifacts, &artifacts); err != nil {
+			return "", fmt.Errorf("failed to parse the artifacts of prehook %v/%v: %w", r.namespace, lastJob.GetName(), err)
+		}
+	}
+
+	value, ok := artifacts[key]
+	if !ok {
+		return "", fmt.Errorf("key %v is not found in the artifacts of prehook %v/%v", key, r.namespace, lastJob.GetName())
+	}
+
+	if str, ok := value.(string); ok {
+		return str, nil
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func base64enc(value string) string {
+	return base64.StdEncoding.EncodeToString([]byte(value))
+}
+
+func base64dec(value string) (string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(decoded), nil
+}
+
+// ResolvePackageOverrides returns a copy of the package overrides with the hub templates resolved in all the string
+// values of the overrides and in the patches
+func (r *HubTemplateResolver) ResolvePackageOverrides(packageOverrides []*appv1.Overrides) ([]*appv1.Overrides, error) {
+	if packageOverrides == nil {
+		return nil, nil
+	}
+
+	resolved := make([]*appv1.Overrides, 0, len(packageOverrides))
+
+	for _, ov := range packageOverrides {
+		if ov == nil {
+			resolved = append(resolved, nil)
+
+			continue
+		}
+
+		ov = ov.DeepCopy()
+
+		for i := range ov.PackageOverrides {
+			raw := ov.PackageOverrides[i].Raw
+			if !HasHubTemplate(string(raw)) {
+				continue
+			}
+
+			var value interface{}
+			if err := json.Unmarshal(raw, &value); err != nil {
+				return nil, fmt.Errorf("failed to parse the override of package %v: %w", ov.PackageName, err)
+			}
+
+			value, err := r.resolveValue(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve the override of package %v: %w", ov.PackageName, err)
+			}
+
+			if ov.PackageOverrides[i].Raw, err = json.Marshal(value); err != nil {
+				return nil, err
+			}
+
+			ov.PackageOverrides[i].Object = nil
+		}
+
+		for i := range ov.Patches {
+			patch, err := r.Resolve(ov.Patches[i].Patch)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve patch %v of package %v: %w", i, ov.PackageName, err)
+			}
+
+			ov.Patches[i].Patch = patch
+		}
+
+		resolved = append(resolved, ov)
+	}
+
+	return resolved, nil
+}
+
+// ResolveAnnotations returns a copy of the annotations with the hub templates resolved in the values
+func (r *HubTemplateResolver) ResolveAnnotations(annotations map[string]string) (map[string]string, error) {
+	if annotations == nil {
+		return nil, nil
+	}
+
+	resolved := make(map[string]string, len(annotations))
+
+	for k, v := range annotations {
+		value, err := r.Resolve(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve annotation %v: %w", k, err)
+		}
+
+		resolved[k] = value
+	}
+
+	return resolved, nil
+}
+
+func (r *HubTemplateResolver) resolveValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return r.Resolve(v)
+	case map[string]interface{}:
+		for k, item := range v {
+			resolved, err := r.resolveValue(item)
+			if err != nil {
+				return nil, err
+			}
+
+			v[k] = resolved
+		}
+	case []interface{}:
+		for i, item := range v {
+			resolved, err := r.resolveValue(item)
+			if err != nil {
+				return nil, err
+			}
+
+			v[i] = resolved
+		}
+	}
+
+	return value, nil
+}
+
+// HubTemplateRefs are the names of the ConfigMaps and Secrets read by the hub templates of a subscription
+type HubTemplateRefs struct {
+	ConfigMaps []string
+	Secrets    []string
+}
+
+// GetHubTemplateRefs returns the sorted names of the ConfigMaps and Secrets read by the hub templates in the package
+// overrides and the annotations of the subscription. The names that aren't quoted strings, like the names resolved by
+// a pipeline, are not returned
+func GetHubTemplateRefs(sub *appv1.Subscription) HubTemplateRefs {
+	texts := []string{}
+
+	for _, ov := range sub.Spec.PackageOverrides {
+		if ov == nil {
+			continue
+		}
+
+		for _, po := range ov.PackageOverrides {
+			if !HasHubTemplate(string(po.Raw)) {
+				continue
+			}
+
+			var value interface{}
+			if err := json.Unmarshal(po.Raw, &value); err == nil {
+				texts = appendStringValues(texts, value)
+			}
+		}
+
+		for _, patch := range ov.Patches {
+			texts = append(texts, patch.Patch)
+		}
+	}
+
+	for _, v := range sub.GetAnnotations() {
+		texts = append(texts, v)
+	}
+
+	configMaps, secrets := map[string]bool{}, map[string]bool{}
+
+	for _, text := range texts {
+		if !HasHubTemplate(text) {
+			continue
+		}
+
+		for _, match := range hubTemplateRefRegexp.FindAllStringSubmatch(text, -1) {
+			name := match[2] + match[3]
+
+			if match[1] == "fromConfigMap" {
+				configMaps[name] = true
+			} else {
+				secrets[name] = true
+			}
+		}
+	}
+
+	return HubTemplateRefs{ConfigMaps: sortedKeys(configMaps), Secrets: sortedKeys(secrets)}
+}
+
+// appendStringValues appends the strings nested in the value
+func appendStringValues(texts []string, value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		texts = append(texts, v)
+	case map[string]interface{}:
+		for _, item := range v {
+			texts = appendStringValues(texts, item)
+		}
+	case []interface{}:
+		for _, item := range v {
+			texts = appendStringValues(texts, item)
+		}
+	}
+
+	return texts
+}
+
+func sortedKeys(set map[string]bool) []string {
+	if len(set) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,180 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ansiblejob "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/ansible/v1alpha1"
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func TestHubTemplateResolver(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	c := fake.NewClientBuilder().WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "env", Namespace: "demo-ns"},
+			Data:       map[string]string{"endpoint": "https://db.prod.example.com"},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "demo-ns"},
+			Data:       map[string][]byte{"secretName": []byte("db-creds")},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "env", Namespace: "other-ns"},
+			Data:       map[string]string{"endpoint": "https://db.other.example.com"},
+		},
+	).Build()
+
+	resolver := NewHubTemplateResolver(c, "demo-ns")
+
+	value, err := resolver.Resolve(`{{hub fromConfigMap "env" "endpoint" hub}}`)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(value).To(Equal("https://db.prod.example.com"))
+
+	value, err = resolver.Resolve(`{{hub fromSecret "creds" "secretName" | base64dec hub}}`)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(value).To(Equal("db-creds"))
+
+	value, err = resolver.Resolve("no template {{ .Values.name }}")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(value).To(Equal("no template {{ .Values.name }}"))
+
+	_, err = resolver.Resolve(`{{hub fromConfigMap "env" "missing" hub}}`)
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = resolver.Resolve(`{{hub fromConfigMap "absent" "endpoint" hub}}`)
+	g.Expect(err).To(HaveOccurred())
+
+	packageOverrides := []*appv1.Overrides{
+		{
+			PackageName: "nginx",
+			PackageOverrides: []appv1.PackageOverride{
+				{RawExtension: runtime.RawExtension{
+					Raw: []byte(`{"path": "spec.values", "value": "db: {{hub fromConfigMap \"env\" \"endpoint\" hub}}"}`)}},
+			},
+			Patches: []appv1.PackagePatch{
+				{Type: appv1.PatchTypeJSONMergePatch, Patch: `{"data": {"db": "{{hub fromConfigMap "env" "endpoint" hub}}"}}`},
+			},
+		},
+	}
+
+	resolved, err := resolver.ResolvePackageOverrides(packageOverrides)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(resolved[0].PackageOverrides[0].Raw)).To(ContainSubstring("db: https://db.prod.example.com"))
+	g.Expect(resolved[0].Patches[0].Patch).To(Equal(`{"data": {"db": "https://db.prod.example.com"}}`))
+
+	// the source package overrides are not changed
+	g.Expect(packageOverrides[0].Patches[0].Patch).To(ContainSubstring("{{hub"))
+
+	annotations, err := resolver.ResolveAnnotations(map[string]string{
+		appv1.AnnotationGitBranch: `{{hub fromConfigMap "env" "endpoint" hub}}`,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(annotations[appv1.AnnotationGitBranch]).To(Equal("https://db.prod.example.com"))
+}
+
+func TestGetHubTemplateRefs(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "demo",
+			Namespace: "demo-ns",
+			Annotations: map[string]string{
+				appv1.AnnotationGitBranch: "{{hub fromConfigMap `branches` \"prod\" hub}}",
+				appv1.AnnotationGitPath:   `fromConfigMap "not-a-template" "path"`,
+			},
+		},
+		Spec: appv1.SubscriptionSpec{
+			PackageOverrides: []*appv1.Overrides{
+				{
+					PackageName: "nginx",
+					PackageOverrides: []appv1.PackageOverride{
+						{RawExtension: runtime.RawExtension{
+							Raw: []byte(`{"path": "spec.values", "value": "db: {{hub fromConfigMap \"env\" \"endpoint\" hub}}"}`)}},
+					},
+					Patches: []appv1.PackagePatch{
+						{Type: appv1.PatchTypeJSONMergePatch,
+							Patch: `{"data": {"user": "{{hub fromSecret "creds" "user" hub}}", "db": "{{hub fromConfigMap "env" "db" hub}}"}}`},
+					},
+				},
+			},
+		},
+	}
+
+	refs := GetHubTemplateRefs(sub)
+	g.Expect(refs.ConfigMaps).To(Equal([]string{"branches", "env"}))
+	g.Expect(refs.Secrets).To(Equal([]string{"creds"}))
+
+	g.Expect(GetHubTemplateRefs(&appv1.Subscription{})).To(Equal(HubTemplateRefs{}))
+}
+
+func TestHubTemplateFromHook(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(ansiblejob.AddToScheme(scheme)).To(Succeed())
+
+	newJob := func(name, hookType string, created time.Time, artifacts string) *ansiblejob.AnsibleJob {
+		return &ansiblejob.AnsibleJob{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "demo-ns",
+				CreationTimestamp: metav1.NewTime(created),
+				Annotations: map[string]string{
+					appv1.AnnotationHosting:  "demo-ns/demo",
+					appv1.AnnotationHookType: hookType,
+				},
+			},
+			Status: ansiblejob.AnsibleJobStatus{Artifacts: []byte(artifacts)},
+		}
+	}
+
+	now := time.Now()
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newJob("provision-db-1-abc123", "prehook", now.Add(-time.Hour), `{"dbEndpoint": "old.example.com"}`),
+		newJob("provision-db-2-def456", "prehook", now, `{"dbEndpoint": "db.example.com", "replicas": 2}`),
+		newJob("notify-2-def456", "posthook", now, `{"dbEndpoint": "posthook.example.com"}`),
+	).Build()
+
+	resolver := NewSubscriptionHubTemplateResolver(c, &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns"}})
+
+	value, err := resolver.Resolve(`{{hub fromHook "provision-db" "dbEndpoint" hub}}`)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(value).To(Equal("db.example.com"))
+
+	value, err = resolver.Resolve(`{{hub fromHook "provision-db" "replicas" hub}}`)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(value).To(Equal("2"))
+
+	_, err = resolver.Resolve(`{{hub fromHook "provision-db" "missing" hub}}`)
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = resolver.Resolve(`{{hub fromHook "notify" "dbEndpoint" hub}}`)
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = NewHubTemplateResolver(c, "demo-ns").Resolve(`{{hub fromHook "provision-db" "dbEndpoint" hub}}`)
+	g.Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,256 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/reference/docker"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	corev1 "k8s.io/api/core/v1"
+	"oras.land/oras-go/pkg/content"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// NodeArchLabel is the architecture label of the nodes, also used in the node selectors of the workloads
+	NodeArchLabel = "kubernetes.io/arch"
+
+	// imageArchCacheTTL is how long the architectures of an image are cached, the tags can be pushed again
+	imageArchCacheTTL = time.Hour
+	// imageArchFailureCacheTTL is how long a failed inspection is cached, the private and unreachable registries
+	// aren't accessed again on every apply
+	imageArchFailureCacheTTL = 10 * time.Minute
+	// maxImageManifestSize guards against the oversized manifests and configs
+	maxImageManifestSize = 4 << 20
+	imageInspectTimeout  = 30 * time.Second
+)
+
+type imageArchEntry struct {
+	archs   []string
+	err     error
+	expires time.Time
+}
+
+var imageArchCache sync.Map
+
+// GetImageArchitectures returns the architectures the image is built for, from the platforms of its manifest list or
+// the config of its manifest. The registry is accessed anonymously, the architectures and the failures are cached
+func GetImageArchitectures(image string) ([]string, error) {
+	if entry, ok := imageArchCache.Load(image); ok && time.Now().Before(entry.(imageArchEntry).expires) {
+		return entry.(imageArchEntry).archs, entry.(imageArchEntry).err
+	}
+
+	archs, err := inspectImageArchitectures(image)
+
+	ttl := imageArchCacheTTL
+	if err != nil {
+		ttl = imageArchFailureCacheTTL
+	}
+
+	imageArchCache.Store(image, imageArchEntry{archs: archs, err: err, expires: time.Now().Add(ttl)})
+
+	return archs, err
+}
+
+func inspectImageArchitectures(image string) ([]string, error) {
+	named, err := docker.ParseDockerRef(image)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image %v, err: %w", image, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), imageInspectTimeout)
+	defer cancel()
+
+	registry, err := content.NewRegistry(content.RegistryOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	name, desc, err := registry.Resolve(ctx, named.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve image %v, err: %w", image, err)
+	}
+
+	fetcher, err := registry.Fetcher(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch image %v, err: %w", image, err)
+	}
+
+	fetch := func(d ocispec.Descriptor, v interface{}) error {
+		rc, err := fetcher.Fetch(ctx, d)
+		if err != nil {
+			return err
+		}
+
+		defer rc.Close()
+
+		data, err := io.ReadAll(io.LimitReader(rc, maxImageManifestSize))
+		if err != nil {
+			return err
+		}
+
+		return json.Unmarshal(data, v)
+	}
+
+	archs := []string{}
+
+	switch desc.MediaType {
+	case ocispec.MediaTypeImageIndex, images.MediaTypeDockerSchema2ManifestList:
+		index := &ocispec.Index{}
+		if err := fetch(desc, index); err != nil {
+			return nil, fmt.Errorf("failed to fetch the manifest list of image %v, err: %w", image, err)
+		}
+
+		for _, m := range index.Manifests {
+			// the attestation manifests have the unknown platform
+			if m.Platform != nil && m.Platform.Architecture != "unknown" {
+				archs = append(archs, m.Platform.Architecture)
+			}
+		}
+	case ocispec.MediaTypeImageManifest, images.MediaTypeDockerSchema2Manifest:
+		manifest := &ocispec.Manifest{}
+		if err := fetch(desc, manifest); err != nil {
+			return nil, fmt.Errorf("failed to fetch the manifest of image %v, err: %w", image, err)
+		}
+
+		config := &ocispec.Image{}
+		if err := fetch(manifest.Config, config); err != nil {
+			return nil, fmt.Errorf("failed to fetch the config of image %v, err: %w", image, err)
+		}
+
+		archs = append(archs, config.Architecture)
+	default:
+		return nil, fmt.Errorf("unsupported media type %v of image %v", desc.MediaType, image)
+	}
+
+	return uniqueSortedStrings(archs), nil
+}
+
+// GetImageDigest resolves the tag of the image to the digest of its manifest or manifest list in its registry, the
+// registry is accessed anonymously. The digest isn't cached so the tags pushed again are detected
+func GetImageDigest(image string) (string, error) {
+	named, err := docker.ParseDockerRef(image)
+	if err != nil {
+		return "", fmt.Errorf("invalid image %v, err: %w", image, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), imageInspectTimeout)
+	defer cancel()
+
+	registry, err := content.NewRegistry(content.RegistryOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	_, desc, err := registry.Resolve(ctx, named.String())
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve image %v, err: %w", image, err)
+	}
+
+	return desc.Digest.String(), nil
+}
+
+// GetClusterArchitectures returns the architectures of the nodes of the cluster, from their kubernetes.io/arch label
+func GetClusterArchitectures(c client.Client) ([]string, error) {
+	nodes := &corev1.NodeList{}
+	if err := c.List(context.TODO(), nodes); err != nil {
+		return nil, fmt.Errorf("failed to list the nodes, err: %w", err)
+	}
+
+	archs := []string{}
+
+	for _, node := range nodes.Items {
+		if arch := node.GetLabels()[NodeArchLabel]; arch != "" {
+			archs = append(archs, arch)
+		}
+	}
+
+	return uniqueSortedStrings(archs), nil
+}
+
+// GetNodeSelectorArchitecture returns the architecture the pods of the workload are scheduled on, from the
+// kubernetes.io/arch node selector of its pod template
+func GetNodeSelectorArchitecture(obj map[string]interface{}) string {
+	for key, value := range obj {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if key == "nodeSelector" {
+				if arch, ok := v[NodeArchLabel].(string); ok {
+					return arch
+				}
+
+				continue
+			}
+
+			if arch := GetNodeSelectorArchitecture(v); arch != "" {
+				return arch
+			}
+		case []interface{}:
+			for _, item := range v {
+				if m, ok := item.(map[string]interface{}); ok {
+					if arch := GetNodeSelectorArchitecture(m); arch != "" {
+						return arch
+					}
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// UnsupportedArchitectures returns the target architectures the image architectures don't include
+func UnsupportedArchitectures(imageArchs, targetArchs []string) []string {
+	supported := map[string]bool{}
+
+	for _, arch := range imageArchs {
+		supported[arch] = true
+	}
+
+	unsupported := []string{}
+
+	for _, arch := range targetArchs {
+		if !supported[arch] {
+			unsupported = append(unsupported, arch)
+		}
+	}
+
+	return unsupported
+}
+
+func uniqueSortedStrings(in []string) []string {
+	seen := map[string]bool{}
+	out := []string{}
+
+	for _, s := range in {
+		if !seen[s] {
+			seen[s] = true
+
+			out = append(out, s)
+		}
+	}
+
+	sort.Strings(out)
+
+	return out
+}
@@ -0,0 +1,75 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestImageArchitectures(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	node := func(name, arch string) *corev1.Node {
+		n := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if arch != "" {
+			n.SetLabels(map[string]string{NodeArchLabel: arch})
+		}
+
+		return n
+	}
+
+	c := fake.NewClientBuilder().WithObjects(node("n1", "arm64"), node("n2", "amd64"), node("n3", "arm64"),
+		node("n4", "")).Build()
+
+	archs, err := GetClusterArchitectures(c)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(archs).To(Equal([]string{"amd64", "arm64"}))
+
+	deployment := map[string]interface{}{
+		"kind": "Deployment",
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"nodeSelector": map[string]interface{}{NodeArchLabel: "s390x"},
+					"containers":   []interface{}{map[string]interface{}{"name": "app", "image": "quay.io/org/app:v1"}},
+				},
+			},
+		},
+	}
+	g.Expect(GetNodeSelectorArchitecture(deployment)).To(Equal("s390x"))
+	g.Expect(GetNodeSelectorArchitecture(map[string]interface{}{"kind": "ConfigMap"})).To(BeEmpty())
+
+	g.Expect(UnsupportedArchitectures([]string{"amd64"}, []string{"amd64", "arm64"})).To(Equal([]string{"arm64"}))
+	g.Expect(UnsupportedArchitectures([]string{"amd64", "arm64", "ppc64le"}, []string{"arm64"})).To(BeEmpty())
+
+	// the cached architectures are returned without inspecting the registry
+	imageArchCache.Store("quay.io/org/app:v1", imageArchEntry{archs: []string{"amd64"}, expires: time.Now().Add(time.Hour)})
+	g.Expect(GetImageArchitectures("quay.io/org/app:v1")).To(Equal([]string{"amd64"}))
+
+	_, err = GetImageArchitectures("Invalid Image")
+	g.Expect(err).To(HaveOccurred())
+
+	// the failures are cached for a shorter time
+	entry, ok := imageArchCache.Load("Invalid Image")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(entry.(imageArchEntry).err).To(HaveOccurred())
+	g.Expect(entry.(imageArchEntry).expires).To(BeTemporally("<=", time.Now().Add(imageArchFailureCacheTTL)))
+}
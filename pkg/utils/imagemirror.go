@@ -0,0 +1,343 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/docker/distribution/reference"
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+const (
+	// ImageMirrorConfigKey is the key of the registry mirror list in the image mirror ConfigMap
+	ImageMirrorConfigKey = "mirrors.yaml"
+	// RequiredImagesSuffix is the name suffix of the ConfigMap listing the images required by a subscription
+	RequiredImagesSuffix = "-required-images"
+	// RequiredImagesKey lists the source images, one per line
+	RequiredImagesKey = "images.txt"
+	// RequiredImagesMappingKey lists the <source>=<mirror> mapping, one per line, for the mirroring tools
+	RequiredImagesMappingKey = "mapping.txt"
+)
+
+// ImageMirror maps a source repository or registry to its mirrors, similar to an ImageContentSourcePolicy entry
+type ImageMirror struct {
+	Source  string   `json:"source"`
+	Mirrors []string `json:"mirrors"`
+}
+
+// RequiredImage is an image referenced by the rendered manifests, Mirror is empty if no mirror matches the image
+type RequiredImage struct {
+	Source string
+	Mirror string
+}
+
+// ParseImageMirrors parses the image mirror list, the longest matching source wins when rewriting
+func ParseImageMirrors(data string) ([]ImageMirror, error) {
+	mirrors := []ImageMirror{}
+
+	if err := yaml.Unmarshal([]byte(data), &mirrors); err != nil {
+		return nil, fmt.Errorf("failed to parse the image mirrors, err: %w", err)
+	}
+
+	for i, m := range mirrors {
+		if m.Source == "" || len(m.Mirrors) == 0 || m.Mirrors[0] == "" {
+			return nil, fmt.Errorf("invalid image mirror #%d, the source and at least one mirror are required", i)
+		}
+
+		mirrors[i].Source = normalizeImageSource(strings.TrimSuffix(m.Source, "/"))
+	}
+
+	sort.SliceStable(mirrors, func(i, j int) bool { return len(mirrors[i].Source) > len(mirrors[j].Source) })
+
+	return mirrors, nil
+}
+
+// normalizeImageSource returns the fully qualified form of the repository or image of a mirror source, e.g. nginx is
+// docker.io/library/nginx. A registry without repository path and a source that isn't a valid reference are kept
+func normalizeImageSource(source string) string {
+	if !strings.Contains(source, "/") && (strings.ContainsAny(source, ".:") || source == "localhost") {
+		return source
+	}
+
+	named, err := reference.ParseNormalizedNamed(source)
+	if err != nil {
+		return source
+	}
+
+	return named.String()
+}
+
+// normalizeImage returns the fully qualified form of the image reference with the latest tag if it has no tag nor
+// digest, e.g. nginx is docker.io/library/nginx:latest. An image that isn't a valid reference is kept
+func normalizeImage(image string) string {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return image
+	}
+
+	return reference.TagNameOnly(named).String()
+}
+
+// GetSubscriptionImageMirrors returns the image mirrors of the ConfigMap referred by the subscription annotation.
+// The ConfigMap sits in the subscription namespace of the cluster the resources are deployed to
+func GetSubscriptionImageMirrors(c client.Reader, sub *appv1.Subscription) ([]ImageMirror, error) {
+	cmName := sub.GetAnnotations()[appv1.AnnotationImageMirrorConfigMap]
+	if cmName == "" {
+		return nil, nil
+	}
+
+	return GetImageMirrors(c, types.NamespacedName{Name: cmName, Namespace: sub.GetNamespace()})
+}
+
+// GetImageMirrors reads the image mirrors from the ConfigMap
+func GetImageMirrors(c client.Reader, key types.NamespacedName) ([]ImageMirror, error) {
+	cm := &corev1.ConfigMap{}
+
+	if err := c.Get(context.TODO(), key, cm); err != nil {
+		return nil, fmt.Errorf("failed to get the image mirror configmap %v, err: %w", key.String(), err)
+	}
+
+	return ParseImageMirrors(cm.Data[ImageMirrorConfigKey])
+}
+
+// MirrorImage returns the image reference rewritten with the first mirror of the longest matching source.
+// The source matches a whole registry, repository path or image, e.g. quay.io/org matches quay.io/org/app:v1
+// but not quay.io/organization/app:v1. The image is compared in its fully qualified form, so nginx matches the
+// docker.io/library/nginx:latest source. An empty string is returned if no source matches
+func MirrorImage(image string, mirrors []ImageMirror) string {
+	image = normalizeImage(image)
+
+	for _, m := range mirrors {
+		if !strings.HasPrefix(image, m.Source) {
+			continue
+		}
+
+		rest := image[len(m.Source):]
+		if rest != "" && !strings.ContainsAny(rest[:1], "/:@") {
+			continue
+		}
+
+		return strings.TrimSuffix(m.Mirrors[0], "/") + rest
+	}
+
+	return ""
+}
+
+// RewriteImages rewrites the container images in the object according to the mirrors and returns all the images
+// found. Any containers, initContainers or ephemeralContainers list is handled, so pod templates embedded in
+// workloads and custom resources are covered
+func RewriteImages(obj map[string]interface{}, mirrors []ImageMirror) []RequiredImage {
+	images := []RequiredImage{}
+
+	var walk func(v interface{})
+
+	walk = func(v interface{}) {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			for k, child := range t {
+				if k == "containers" || k == "initContainers" || k == "ephemeralContainers" {
+					if containers, ok := child.([]interface{}); ok {
+						for _, c := range containers {
+							container, ok := c.(map[string]interface{})
+							if !ok {
+								continue
+							}
+
+							image, ok := container["image"].(string)
+							if !ok || image == "" {
+								continue
+							}
+
+							mirror := MirrorImage(image, mirrors)
+							if mirror != "" {
+								container["image"] = mirror
+							}
+
+							images = append(images, RequiredImage{Source: image, Mirror: mirror})
+						}
+
+						continue
+					}
+				}
+
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range t {
+				walk(child)
+			}
+		}
+	}
+
+	walk(obj)
+
+	return images
+}
+
+// ContainerImages returns the container images of the object, in the order they are found
+func ContainerImages(obj map[string]interface{}) []string {
+	images := []string{}
+
+	// without mirrors the images are only collected
+	for _, image := range RewriteImages(obj, nil) {
+		images = append(images, image.Source)
+	}
+
+	return images
+}
+
+// ImageMirrorPostRenderer is a helm post renderer rewriting the images of the rendered chart
+type ImageMirrorPostRenderer struct {
+	Mirrors []ImageMirror
+
+	mtx    sync.Mutex
+	images []RequiredImage
+}
+
+// Run implements the helm postrender.PostRenderer interface
+func (r *ImageMirrorPostRenderer) Run(renderedManifests *bytes.Buffer) (*bytes.Buffer, error) {
+	out := &bytes.Buffer{}
+	images := []RequiredImage{}
+
+	for _, doc := range ParseYAML(renderedManifests.Bytes()) {
+		doc = strings.TrimSpace(doc)
+		if doc == "" {
+			continue
+		}
+
+		obj := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+			return nil, fmt.Errorf("failed to parse the rendered manifest, err: %w", err)
+		}
+
+		if len(obj) == 0 {
+			continue
+		}
+
+		images = append(images, RewriteImages(obj, r.Mirrors)...)
+
+		b, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		out.WriteString("---\n")
+		out.Write(b)
+	}
+
+	r.mtx.Lock()
+	r.images = images
+	r.mtx.Unlock()
+
+	return out, nil
+}
+
+// RequiredImages returns the images found in the last rendering
+func (r *ImageMirrorPostRenderer) RequiredImages() []RequiredImage {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	return r.images
+}
+
+// UpdateRequiredImagesConfigMap records the images required by the owner, a subscription or a helm release, in the
+// <owner>-required-images ConfigMap. All the ConfigMaps of a subscription carry the subscription name label, so the
+// mirroring tools can list them.
+func UpdateRequiredImagesConfigMap(c client.Client, owner metav1.Object, ownerGVK schema.GroupVersionKind,
+	appsubName string, images []RequiredImage) error {
+	sources := map[string]bool{}
+	mapping := map[string]bool{}
+
+	for _, image := range images {
+		sources[image.Source] = true
+
+		if image.Mirror != "" {
+			mapping[image.Source+"="+image.Mirror] = true
+		}
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      owner.GetName() + RequiredImagesSuffix,
+			Namespace: owner.GetNamespace(),
+			Labels: map[string]string{
+				appv1.LabelRequiredImagesOf: appsubName,
+			},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(owner, ownerGVK)},
+		},
+		Data: map[string]string{
+			RequiredImagesKey:        sortedLines(sources),
+			RequiredImagesMappingKey: sortedLines(mapping),
+		},
+	}
+
+	existing := &corev1.ConfigMap{}
+
+	err := c.Get(context.TODO(), types.NamespacedName{Name: cm.Name, Namespace: cm.Namespace}, existing)
+	if kerrors.IsNotFound(err) {
+		klog.Infof("creating the required images configmap %v/%v", cm.Namespace, cm.Name)
+
+		return c.Create(context.TODO(), cm)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if existing.Data[RequiredImagesKey] == cm.Data[RequiredImagesKey] &&
+		existing.Data[RequiredImagesMappingKey] == cm.Data[RequiredImagesMappingKey] &&
+		existing.Labels[appv1.LabelRequiredImagesOf] == appsubName {
+		return nil
+	}
+
+	existing.Data = cm.Data
+	existing.Labels = cm.Labels
+	existing.OwnerReferences = cm.OwnerReferences
+
+	klog.Infof("updating the required images configmap %v/%v", cm.Namespace, cm.Name)
+
+	return c.Update(context.TODO(), existing)
+}
+
+func sortedLines(set map[string]bool) string {
+	lines := make([]string, 0, len(set))
+
+	for l := range set {
+		lines = append(lines, l)
+	}
+
+	sort.Strings(lines)
+
+	if len(lines) == 0 {
+		return ""
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
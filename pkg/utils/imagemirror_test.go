@@ -0,0 +1,180 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+const testImageMirrors = `
+- source: quay.io
+  mirrors:
+  - mirror.local:5000/quay
+- source: quay.io/org/
+  mirrors:
+  - mirror.local:5000/org
+`
+
+func TestMirrorImage(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	mirrors, err := ParseImageMirrors(testImageMirrors)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(mirrors[0].Source).To(Equal("quay.io/org"))
+
+	g.Expect(MirrorImage("quay.io/org/app:v1", mirrors)).To(Equal("mirror.local:5000/org/app:v1"))
+	g.Expect(MirrorImage("quay.io/organization/app:v1", mirrors)).To(Equal("mirror.local:5000/quay/organization/app:v1"))
+	g.Expect(MirrorImage("quay.io/other/app@sha256:abc", mirrors)).To(Equal("mirror.local:5000/quay/other/app@sha256:abc"))
+	g.Expect(MirrorImage("quay.iox/app:v1", mirrors)).To(BeEmpty())
+	g.Expect(MirrorImage("docker.io/library/nginx", mirrors)).To(BeEmpty())
+
+	_, err = ParseImageMirrors("- source: quay.io\n")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestMirrorNormalizedImage(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	mirrors, err := ParseImageMirrors(`
+- source: docker.io/library/nginx:latest
+  mirrors:
+  - mirror.local:5000/nginx:stable
+- source: busybox
+  mirrors:
+  - mirror.local:5000/busybox
+- source: docker.io
+  mirrors:
+  - mirror.local:5000/docker
+`)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// the short names are compared in their fully qualified form
+	g.Expect(MirrorImage("nginx", mirrors)).To(Equal("mirror.local:5000/nginx:stable"))
+	g.Expect(MirrorImage("nginx:latest", mirrors)).To(Equal("mirror.local:5000/nginx:stable"))
+	g.Expect(MirrorImage("docker.io/library/nginx", mirrors)).To(Equal("mirror.local:5000/nginx:stable"))
+	g.Expect(MirrorImage("busybox:1.36", mirrors)).To(Equal("mirror.local:5000/busybox:1.36"))
+	g.Expect(MirrorImage("nginx:1.25", mirrors)).To(Equal("mirror.local:5000/docker/library/nginx:1.25"))
+	g.Expect(MirrorImage("org/app:v1", mirrors)).To(Equal("mirror.local:5000/docker/org/app:v1"))
+	g.Expect(MirrorImage("quay.io/org/app:v1", mirrors)).To(BeEmpty())
+}
+
+func TestRewriteImages(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	mirrors, err := ParseImageMirrors(testImageMirrors)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	cronJob := map[string]interface{}{
+		"kind": "CronJob",
+		"spec": map[string]interface{}{
+			"jobTemplate": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"initContainers": []interface{}{
+								map[string]interface{}{"name": "init", "image": "docker.io/busybox"},
+							},
+							"containers": []interface{}{
+								map[string]interface{}{"name": "app", "image": "quay.io/org/app:v1"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	images := RewriteImages(cronJob, mirrors)
+	g.Expect(images).To(ConsistOf(
+		RequiredImage{Source: "docker.io/busybox"},
+		RequiredImage{Source: "quay.io/org/app:v1", Mirror: "mirror.local:5000/org/app:v1"},
+	))
+
+	podSpec := cronJob["spec"].(map[string]interface{})["jobTemplate"].(map[string]interface{})["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})
+	g.Expect(podSpec["containers"].([]interface{})[0].(map[string]interface{})["image"]).To(Equal("mirror.local:5000/org/app:v1"))
+	g.Expect(podSpec["initContainers"].([]interface{})[0].(map[string]interface{})["image"]).To(Equal("docker.io/busybox"))
+}
+
+func TestImageMirrorPostRenderer(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	mirrors, err := ParseImageMirrors(testImageMirrors)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	rendered := `---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: quay.io/org/app:v1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+`
+
+	r := &ImageMirrorPostRenderer{Mirrors: mirrors}
+
+	out, err := r.Run(bytes.NewBufferString(rendered))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(out.String()).To(ContainSubstring("image: mirror.local:5000/org/app:v1"))
+	g.Expect(out.String()).To(ContainSubstring("kind: ConfigMap"))
+	g.Expect(r.RequiredImages()).To(Equal([]RequiredImage{{Source: "quay.io/org/app:v1", Mirror: "mirror.local:5000/org/app:v1"}}))
+}
+
+func TestUpdateRequiredImagesConfigMap(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sub := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "sub", Namespace: "ns", UID: "1234"}}
+	c := fake.NewClientBuilder().Build()
+
+	images := []RequiredImage{
+		{Source: "quay.io/org/app:v1", Mirror: "mirror.local:5000/org/app:v1"},
+		{Source: "docker.io/busybox"},
+		{Source: "docker.io/busybox"},
+	}
+
+	err := UpdateRequiredImagesConfigMap(c, sub, appv1.SchemeGroupVersion.WithKind("Subscription"), sub.Name, images)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	cm := &corev1.ConfigMap{}
+	g.Expect(c.Get(context.TODO(), types.NamespacedName{Name: "sub" + RequiredImagesSuffix, Namespace: "ns"}, cm)).To(Succeed())
+	g.Expect(cm.Labels[appv1.LabelRequiredImagesOf]).To(Equal("sub"))
+	g.Expect(cm.Data[RequiredImagesKey]).To(Equal("docker.io/busybox\nquay.io/org/app:v1\n"))
+	g.Expect(cm.Data[RequiredImagesMappingKey]).To(Equal("quay.io/org/app:v1=mirror.local:5000/org/app:v1\n"))
+
+	err = UpdateRequiredImagesConfigMap(c, sub, appv1.SchemeGroupVersion.WithKind("Subscription"), sub.Name, images[1:])
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(c.Get(context.TODO(), types.NamespacedName{Name: "sub" + RequiredImagesSuffix, Namespace: "ns"}, cm)).To(Succeed())
+	g.Expect(cm.Data[RequiredImagesKey]).To(Equal("docker.io/busybox\n"))
+	g.Expect(cm.Data[RequiredImagesMappingKey]).To(BeEmpty())
+}
@@ -27,7 +27,9 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/klog/v2"
 	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"sigs.k8s.io/kustomize/api/hasher"
 	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/resmap"
 	kustomizetypes "sigs.k8s.io/kustomize/api/types"
 	"sigs.k8s.io/kustomize/kyaml/filesys"
 )
@@ -55,6 +57,10 @@ func RunKustomizeBuild(kustomizeDir string) ([]byte, error) {
 		return nil, err
 	}
 
+	if err := markGeneratedResources(mapOut); err != nil {
+		return nil, err
+	}
+
 	byteOut, err := mapOut.AsYaml()
 	if err != nil {
 		return nil, err
@@ -63,6 +69,45 @@ func RunKustomizeBuild(kustomizeDir string) ([]byte, error) {
 	return byteOut, nil
 }
 
+// markGeneratedResources annotates the ConfigMaps and Secrets named with the hash suffix of a kustomize generator with
+// their name without the suffix, the synchronizer keeps the previous generated resources while they are referenced
+func markGeneratedResources(m resmap.ResMap) error {
+	h := &hasher.Hasher{}
+
+	for _, r := range m.Resources() {
+		if r.GetKind() != "ConfigMap" && r.GetKind() != "Secret" {
+			continue
+		}
+
+		name := r.GetName()
+
+		i := strings.LastIndex(name, "-")
+		if i <= 0 {
+			continue
+		}
+
+		// the generator hashes the resource before appending the hash to its name
+		unhashed := r.RNode.Copy()
+		if err := unhashed.SetName(name[:i]); err != nil {
+			return err
+		}
+
+		hash, err := h.Hash(unhashed)
+		if err != nil || hash != name[i+1:] {
+			continue
+		}
+
+		annotations := r.GetAnnotations()
+		annotations[appv1.AnnotationKustomizeGenerated] = name[:i]
+
+		if err := r.SetAnnotations(annotations); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func CheckPackageOverride(ov *appv1.Overrides) error {
 	if ov.PackageOverrides == nil || len(ov.PackageOverrides) < 1 {
 		return errors.New("no PackageOverride is specified. Skipping to override kustomization")
@@ -15,12 +15,16 @@
 package utils
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/ghodss/yaml"
 	"github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
 )
 
 func Test_RunKustomizeBuild(t *testing.T) {
@@ -47,3 +51,48 @@ func Test_RunKustomizeBuild(t *testing.T) {
 		}
 	}
 }
+
+func Test_RunKustomizeBuildGenerators(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	dir := t.TempDir()
+	kustomization := `configMapGenerator:
+- name: app-config
+  literals:
+  - color=blue
+resources:
+- plain.yaml
+`
+	plain := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: plain-config
+data:
+  color: blue
+`
+	g.Expect(os.WriteFile(filepath.Join(dir, "kustomization.yaml"), []byte(kustomization), 0600)).To(gomega.Succeed())
+	g.Expect(os.WriteFile(filepath.Join(dir, "plain.yaml"), []byte(plain), 0600)).To(gomega.Succeed())
+
+	out, err := RunKustomizeBuild(dir)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	generated := map[string]string{}
+
+	for _, resource := range ParseYAML(out) {
+		cm := corev1.ConfigMap{}
+		g.Expect(yaml.Unmarshal([]byte(resource), &cm)).To(gomega.Succeed())
+
+		generated[cm.Name] = cm.Annotations[appv1.AnnotationKustomizeGenerated]
+	}
+
+	// only the ConfigMap of the generator is annotated with its name without the hash suffix
+	g.Expect(generated).To(gomega.HaveLen(2))
+	g.Expect(generated["plain-config"]).To(gomega.BeEmpty())
+
+	for name, base := range generated {
+		if name != "plain-config" {
+			g.Expect(name).To(gomega.HavePrefix("app-config-"))
+			g.Expect(base).To(gomega.Equal("app-config"))
+		}
+	}
+}
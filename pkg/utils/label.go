@@ -15,14 +15,23 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"strings"
 	"unicode"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/klog/v2"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
 )
 
+// unpropagatedMetadataKeys are the subscription labels and annotations never propagated to the deployed resources,
+// they configure the subscription itself
+var unpropagatedMetadataKeys = []string{appv1.SchemeGroupVersion.Group + "/*", "kubectl.kubernetes.io/*"}
+
 func MatchLabelForSubAndDpl(ls *metav1.LabelSelector, dplls map[string]string) bool {
 	klog.V(5).Infof("sub label: %#v, dpl label: %#v", ls, dplls)
 
@@ -96,3 +105,55 @@ func ValidateK8sLabel(s string) string {
 
 	return s[start:stop]
 }
+
+// SafeLabelValue returns a valid label value for any string. The characters not allowed in the label values are
+// replaced with '-', and the values longer than 63 characters are shortened with the hash of the whole value, so
+// different long values keep different label values
+func SafeLabelValue(s string) string {
+	value := strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsNumber(r) || r == '-' || r == '_' || r == '.') {
+			return r
+		}
+
+		return '-'
+	}, s)
+
+	if len(value) > 63 {
+		sum := sha256.Sum256([]byte(s))
+		value = strings.TrimRight(value[:52], "-_.") + "-" + hex.EncodeToString(sum[:])[:10]
+	}
+
+	return strings.Trim(value, "-_.")
+}
+
+// PropagatedMetadata returns the labels or annotations of a subscription selected by a key selector of its metadata
+// propagation, all of them if the selector is nil. The keys configuring the subscription are never propagated
+func PropagatedMetadata(metadata map[string]string, selector *appv1.MetadataKeySelector) map[string]string {
+	propagated := map[string]string{}
+
+	for k, v := range metadata {
+		if matchMetadataKey(k, unpropagatedMetadataKeys) {
+			continue
+		}
+
+		if selector != nil && (len(selector.Include) > 0 && !matchMetadataKey(k, selector.Include) ||
+			matchMetadataKey(k, selector.Exclude)) {
+			continue
+		}
+
+		propagated[k] = v
+	}
+
+	return propagated
+}
+
+// matchMetadataKey checks if a key matches one of the keys, the keys ending with '*' match the keys with their prefix
+func matchMetadataKey(key string, keys []string) bool {
+	for _, k := range keys {
+		if prefix := strings.TrimSuffix(k, "*"); prefix != k && strings.HasPrefix(key, prefix) || key == k {
+			return true
+		}
+	}
+
+	return false
+}
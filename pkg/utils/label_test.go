@@ -14,6 +14,7 @@
 package utils
 
 import (
+	"strings"
 	"testing"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -373,3 +374,20 @@ func assertMatch(t *testing.T, got, want bool) {
 		t.Errorf("want %v, got %v", want, got)
 	}
 }
+
+func TestSafeLabelValue(t *testing.T) {
+	if got := SafeLabelValue("demo-ns.demo"); got != "demo-ns.demo" {
+		t.Errorf("SafeLabelValue() = %v, want demo-ns.demo", got)
+	}
+
+	if got := SafeLabelValue("/main/"); got != "main" {
+		t.Errorf("SafeLabelValue() = %v, want main", got)
+	}
+
+	long := strings.Repeat("a", 70)
+	got := SafeLabelValue(long)
+
+	if len(got) > 63 || got == SafeLabelValue(long+"b") {
+		t.Errorf("SafeLabelValue() = %v, want a unique value of 63 characters at most", got)
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultMaxManifestSize is the default size of a manifest file, in bytes
+	DefaultMaxManifestSize int64 = 256 << 20
+	// DefaultMaxManifestDocuments is the default number of YAML documents of a manifest file
+	DefaultMaxManifestDocuments = 10000
+	// MaxManifestDocumentSize is the size of a YAML document, in bytes. The API server rejects the objects above 1.5MiB
+	// stored in etcd, the larger documents are not Kubernetes resources
+	MaxManifestDocumentSize int64 = 16 << 20
+
+	manifestReadBufferSize = 64 << 10
+)
+
+// ErrManifestLimitExceeded is returned when a manifest is larger than the manifest limits
+var ErrManifestLimitExceeded = errors.New("manifest limit exceeded")
+
+// ManifestLimits bound the memory used to decode a manifest file
+type ManifestLimits struct {
+	// MaxSize is the size of the manifest file, in bytes
+	MaxSize int64
+	// MaxDocumentSize is the size of a YAML document of the manifest file, in bytes
+	MaxDocumentSize int64
+	// MaxDocuments is the number of YAML documents of the manifest file
+	MaxDocuments int
+}
+
+// GetManifestLimits returns the manifest limits of the controller config, or their defaults
+func GetManifestLimits() ManifestLimits {
+	config := GetControllerConfig()
+	limits := ManifestLimits{
+		MaxSize:         DefaultMaxManifestSize,
+		MaxDocumentSize: MaxManifestDocumentSize,
+		MaxDocuments:    DefaultMaxManifestDocuments,
+	}
+
+	if config.MaxManifestSize > 0 {
+		limits.MaxSize = config.MaxManifestSize
+	}
+
+	if config.MaxManifestDocuments > 0 {
+		limits.MaxDocuments = config.MaxManifestDocuments
+	}
+
+	return limits
+}
+
+// DecodeManifestFile decodes the Kubernetes resources of a manifest file one YAML document at a time, see
+// DecodeManifests. The files larger than the size limit are rejected before they are read
+func DecodeManifestFile(path string, limits ManifestLimits,
+	fn func(doc []byte, rsc *unstructured.Unstructured) error) error {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	if info, err := f.Stat(); err == nil && limits.MaxSize > 0 && info.Size() > limits.MaxSize {
+		return fmt.Errorf("%w: file %v is %v bytes, the limit is %v bytes", ErrManifestLimitExceeded, path, info.Size(),
+			limits.MaxSize)
+	}
+
+	if err := DecodeManifests(f, limits, fn); err != nil {
+		return fmt.Errorf("failed to decode file %v, err: %w", path, err)
+	}
+
+	return nil
+}
+
+// DecodeManifests reads the YAML documents separated by --- one at a time and calls fn with each Kubernetes
+// resource, only the current document is held in memory and doc is only valid until fn returns. The documents that
+// are not Kubernetes resources are skipped like ParseKubeResoures does. The decoding stops with
+// ErrManifestLimitExceeded once the manifest, a document or the number of documents exceeds the limits, or with the
+// error of fn
+func DecodeManifests(r io.Reader, limits ManifestLimits, fn func(doc []byte, rsc *unstructured.Unstructured) error) error {
+	reader := bufio.NewReaderSize(r, manifestReadBufferSize)
+	doc := &bytes.Buffer{}
+	size := int64(0)
+	documents := 0
+	lineStart := true
+
+	flush := func() error {
+		defer doc.Reset()
+
+		item := bytes.Trim(doc.Bytes(), "\t \n")
+		if len(item) == 0 {
+			return nil
+		}
+
+		documents++
+		if limits.MaxDocuments > 0 && documents > limits.MaxDocuments {
+			return fmt.Errorf("%w: more than %v documents", ErrManifestLimitExceeded, limits.MaxDocuments)
+		}
+
+		rsc := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal(item, &rsc.Object); err != nil {
+			// Ignore item that cannot be unmarshalled..
+			klog.Warning(err, "Failed to unmarshal YAML content")
+
+			return nil
+		}
+
+		if rsc.GetAPIVersion() == "" || rsc.GetKind() == "" {
+			// Ignore item that does not have apiVersion or kind.
+			klog.Warning("Not a Kubernetes resource")
+
+			return nil
+		}
+
+		if !IsResourceList(rsc) {
+			return fn(item, rsc)
+		}
+
+		for _, listItem := range ExpandResourceList(rsc) {
+			itemDoc, err := yaml.Marshal(listItem.Object)
+			if err != nil {
+				klog.Warning(err, "Failed to marshal the list item")
+
+				continue
+			}
+
+			if err := fn(itemDoc, listItem); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	for {
+		line, err := reader.ReadSlice('\n')
+		if err != nil && !errors.Is(err, bufio.ErrBufferFull) && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		size += int64(len(line))
+		if limits.MaxSize > 0 && size > limits.MaxSize {
+			return fmt.Errorf("%w: more than %v bytes", ErrManifestLimitExceeded, limits.MaxSize)
+		}
+
+		// Multi-document YAML delimeter --- might have trailing spaces
+		if lineStart && isDocumentSeparator(line) {
+			if err := flush(); err != nil {
+				return err
+			}
+		} else {
+			if limits.MaxDocumentSize > 0 && int64(doc.Len()+len(line)) > limits.MaxDocumentSize {
+				return fmt.Errorf("%w: document #%v is more than %v bytes", ErrManifestLimitExceeded, documents+1,
+					limits.MaxDocumentSize)
+			}
+
+			doc.Write(line)
+		}
+
+		// a line longer than the read buffer continues in the next read
+		lineStart = !errors.Is(err, bufio.ErrBufferFull)
+
+		if errors.Is(err, io.EOF) {
+			return flush()
+		}
+	}
+}
+
+func isDocumentSeparator(line []byte) bool {
+	return bytes.Equal(bytes.TrimRight(line, " \r\n"), []byte("---"))
+}
+
+// IsResourceList returns true for the lists of resources like v1.List, whose items are applied as separate resources.
+// The API server doesn't serve the List kinds, kubectl expands them on the client side too
+func IsResourceList(rsc *unstructured.Unstructured) bool {
+	return strings.HasSuffix(rsc.GetKind(), "List") && rsc.IsList()
+}
+
+// ExpandResourceList returns the items of a list of resources, the nested lists are expanded too. The items without
+// apiVersion or kind are skipped like the documents that are not Kubernetes resources
+func ExpandResourceList(rsc *unstructured.Unstructured) []*unstructured.Unstructured {
+	items := []*unstructured.Unstructured{}
+
+	list, ok := rsc.Object["items"].([]interface{})
+	if !ok {
+		return items
+	}
+
+	for i, obj := range list {
+		m, ok := obj.(map[string]interface{})
+		if !ok {
+			klog.Warningf("Item #%v of %v %v is not a Kubernetes resource", i, rsc.GetKind(), rsc.GetName())
+
+			continue
+		}
+
+		item := &unstructured.Unstructured{Object: m}
+
+		if item.GetAPIVersion() == "" || item.GetKind() == "" {
+			klog.Warningf("Item #%v of %v %v is not a Kubernetes resource", i, rsc.GetKind(), rsc.GetName())
+
+			continue
+		}
+
+		if IsResourceList(item) {
+			items = append(items, ExpandResourceList(item)...)
+
+			continue
+		}
+
+		items = append(items, item)
+	}
+
+	return items
+}
@@ -0,0 +1,185 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const testManifests = `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+---
+# not a resource
+foo: bar
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: second
+data:
+  separator: |
+    --- is not a separator when indented
+---
+
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: third`
+
+func TestDecodeManifests(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	names := []string{}
+	collect := func(doc []byte, rsc *unstructured.Unstructured) error {
+		names = append(names, rsc.GetName())
+
+		return nil
+	}
+
+	limits := ManifestLimits{MaxSize: 1024, MaxDocumentSize: 256, MaxDocuments: 3}
+
+	// the documents that are not resources count toward the limit, the empty documents don't
+	g.Expect(DecodeManifests(strings.NewReader(testManifests), limits, collect)).NotTo(Succeed())
+	g.Expect(names).To(Equal([]string{"first", "second"}))
+
+	names = []string{}
+	limits.MaxDocuments = 4
+
+	g.Expect(DecodeManifests(strings.NewReader(testManifests), limits, collect)).To(Succeed())
+	g.Expect(names).To(Equal([]string{"first", "second", "third"}))
+
+	// the resources are the same as the ones of ParseKubeResoures
+	resources := ParseKubeResoures([]byte(testManifests))
+	g.Expect(resources).To(HaveLen(3))
+
+	// a line longer than the read buffer is kept in its document
+	names = []string{}
+	long := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: long\ndata:\n  key: " +
+		strings.Repeat("a", 2*manifestReadBufferSize) + "\n"
+
+	g.Expect(DecodeManifests(strings.NewReader(long), ManifestLimits{}, collect)).To(Succeed())
+	g.Expect(names).To(Equal([]string{"long"}))
+
+	err := DecodeManifests(strings.NewReader(long), ManifestLimits{MaxDocumentSize: manifestReadBufferSize}, collect)
+	g.Expect(errors.Is(err, ErrManifestLimitExceeded)).To(BeTrue())
+
+	err = DecodeManifests(strings.NewReader(long), ManifestLimits{MaxSize: manifestReadBufferSize}, collect)
+	g.Expect(errors.Is(err, ErrManifestLimitExceeded)).To(BeTrue())
+
+	// the errors of the callback stop the decoding
+	stop := errors.New("stop")
+	names = []string{}
+
+	err = DecodeManifests(strings.NewReader(testManifests), ManifestLimits{}, func(doc []byte, rsc *unstructured.Unstructured) error {
+		names = append(names, rsc.GetName())
+
+		return stop
+	})
+	g.Expect(err).To(Equal(stop))
+	g.Expect(names).To(Equal([]string{"first"}))
+}
+
+func TestDecodeManifestFile(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	path := filepath.Join(t.TempDir(), "manifests.yaml")
+	g.Expect(os.WriteFile(path, []byte(testManifests), 0600)).To(Succeed())
+
+	count := 0
+	collect := func(doc []byte, rsc *unstructured.Unstructured) error {
+		count++
+
+		return nil
+	}
+
+	g.Expect(DecodeManifestFile(path, GetManifestLimits(), collect)).To(Succeed())
+	g.Expect(count).To(Equal(3))
+
+	// the files above the size limit are not read
+	count = 0
+	err := DecodeManifestFile(path, ManifestLimits{MaxSize: 16}, collect)
+	g.Expect(errors.Is(err, ErrManifestLimitExceeded)).To(BeTrue())
+	g.Expect(count).To(Equal(0))
+}
+
+const testListManifests = `apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: first
+- foo: bar
+- apiVersion: v1
+  kind: ConfigMapList
+  items:
+  - apiVersion: v1
+    kind: ConfigMap
+    metadata:
+      name: nested
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: second
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: third
+`
+
+func TestDecodeManifestsLists(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	names := []string{}
+	docs := []string{}
+
+	err := DecodeManifests(strings.NewReader(testListManifests), ManifestLimits{}, func(doc []byte, rsc *unstructured.Unstructured) error {
+		names = append(names, rsc.GetKind()+"/"+rsc.GetName())
+		docs = append(docs, string(doc))
+
+		return nil
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// the items of the lists are separate resources, the items that are not resources are skipped
+	g.Expect(names).To(Equal([]string{"ConfigMap/first", "ConfigMap/nested", "Deployment/second", "ConfigMap/third"}))
+	g.Expect(docs[0]).To(ContainSubstring("name: first"))
+	g.Expect(docs[0]).NotTo(ContainSubstring("kind: List"))
+
+	// ParseKubeResoures expands the lists the same way
+	resources := ParseKubeResoures([]byte(testListManifests))
+	g.Expect(resources).To(HaveLen(4))
+	g.Expect(string(resources[2])).To(ContainSubstring("kind: Deployment"))
+
+	// a resource with items is not a list
+	rsc := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Inventory",
+		"items":      []interface{}{map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap"}},
+	}}
+	g.Expect(IsResourceList(rsc)).To(BeFalse())
+}
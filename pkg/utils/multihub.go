@@ -0,0 +1,284 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils/tlsconfig"
+)
+
+// HubConfig describes an additional hub the managed cluster agent syncs to.
+// ClusterName is the name of this managed cluster on that hub, it can differ between hubs.
+type HubConfig struct {
+	Name                  string
+	ClusterName           string
+	ConfigFilePathName    string
+	RestConfig            *rest.Config
+	ClusterNamespacedName *types.NamespacedName
+}
+
+// PrimaryHubName is the name reported for the primary hub in the hub conflicts of a subscription
+const PrimaryHubName = "primary"
+
+var (
+	additionalHubsLock sync.RWMutex
+	additionalHubs     []HubConfig
+)
+
+// ParseAdditionalHubConfigs parses the additional hub flags. Each entry is in the format of
+// <hub-name>=<kubeconfig-path> or <hub-name>/<cluster-name>=<kubeconfig-path>. If the cluster name is not
+// given, defaultClusterName is used
+func ParseAdditionalHubConfigs(entries []string, defaultClusterName string) ([]HubConfig, error) {
+	hubs := []HubConfig{}
+	names := map[string]bool{}
+
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		kv := strings.SplitN(entry, "=", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return nil, fmt.Errorf("invalid additional hub config %q, expecting <hub-name>[/<cluster-name>]=<kubeconfig-path>", entry)
+		}
+
+		hub := HubConfig{
+			Name:               kv[0],
+			ClusterName:        defaultClusterName,
+			ConfigFilePathName: kv[1],
+		}
+
+		if idx := strings.Index(kv[0], "/"); idx != -1 {
+			hub.Name = kv[0][:idx]
+			hub.ClusterName = kv[0][idx+1:]
+		}
+
+		if hub.Name == "" || hub.ClusterName == "" {
+			return nil, fmt.Errorf("invalid additional hub config %q, hub name and cluster name can't be empty", entry)
+		}
+
+		if names[hub.Name] {
+			return nil, fmt.Errorf("duplicated additional hub name %v", hub.Name)
+		}
+
+		names[hub.Name] = true
+
+		hub.ClusterNamespacedName = &types.NamespacedName{Name: hub.ClusterName, Namespace: hub.ClusterName}
+
+		hubs = append(hubs, hub)
+	}
+
+	return hubs, nil
+}
+
+// LoadHubConfigs builds the rest config of each additional hub from its kubeconfig file, with the TLS settings of the
+// operator
+func LoadHubConfigs(hubs []HubConfig) error {
+	for i := range hubs {
+		cfg, err := clientcmd.BuildConfigFromFlags("", hubs[i].ConfigFilePathName)
+		if err != nil {
+			return fmt.Errorf("failed to build config to hub %v with the pathname %v, err: %w", hubs[i].Name, hubs[i].ConfigFilePathName, err)
+		}
+
+		tlsconfig.ApplyRestConfig(cfg)
+
+		hubs[i].RestConfig = cfg
+	}
+
+	return nil
+}
+
+// SetAdditionalHubConfigs registers the additional hubs, it is called once before the controllers are set up
+func SetAdditionalHubConfigs(hubs []HubConfig) {
+	additionalHubsLock.Lock()
+	defer additionalHubsLock.Unlock()
+
+	additionalHubs = hubs
+}
+
+// GetAdditionalHubConfigs returns the registered additional hubs
+func GetAdditionalHubConfigs() []HubConfig {
+	additionalHubsLock.RLock()
+	defer additionalHubsLock.RUnlock()
+
+	return additionalHubs
+}
+
+// GetSubscriptionHubName returns the hub a subscription on the managed cluster belongs to.
+// An empty string means the primary hub.
+func GetSubscriptionHubName(sub *appv1.Subscription) string {
+	if sub == nil {
+		return ""
+	}
+
+	return sub.GetAnnotations()[appv1.AnnotationHubName]
+}
+
+// KnownSubscriptionHubName returns the additional hub of the subscription, an empty string means the primary hub. The
+// unknown hub names are the primary hub
+func KnownSubscriptionHubName(sub *appv1.Subscription) string {
+	hubName := GetSubscriptionHubName(sub)
+
+	for _, hub := range GetAdditionalHubConfigs() {
+		if hub.Name == hubName {
+			return hubName
+		}
+	}
+
+	return ""
+}
+
+// SelectHubClusterName returns the name of this managed cluster on the hub of the subscription, the status of the
+// subscription is reported in its namespace on that hub
+func SelectHubClusterName(sub *appv1.Subscription, primaryClusterName string) string {
+	hubName := KnownSubscriptionHubName(sub)
+
+	for _, hub := range GetAdditionalHubConfigs() {
+		if hub.Name == hubName && hub.ClusterName != "" {
+			return hub.ClusterName
+		}
+	}
+
+	return primaryClusterName
+}
+
+// HubDisplayName returns the name of a hub in the logs and the reports, the primary hub has no name
+func HubDisplayName(hubName string) string {
+	if hubName == "" {
+		return PrimaryHubName
+	}
+
+	return hubName
+}
+
+// hubPriority returns the precedence of a hub, the lowest wins. The primary hub comes first, then the additional hubs
+// in the order of their flags
+func hubPriority(hubName string) int {
+	if hubName == "" {
+		return 0
+	}
+
+	for i, hub := range GetAdditionalHubConfigs() {
+		if hub.Name == hubName {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
+// HubClaims records the hubs propagating each subscription to the managed cluster. The work agents of several hubs
+// propagating the same subscription overwrite each other, the hub with the precedence wins the subscription: the primary
+// hub, then the additional hubs in the order of their flags. The subscription propagated by a losing hub isn't
+// reconciled, the conflict is reported to that hub. The claims of a subscription are released when it is deleted, and
+// the claims of the other hubs are released once a single hub propagates it
+type HubClaims struct {
+	mtx    sync.Mutex
+	claims map[types.NamespacedName]map[string]bool
+}
+
+// NewHubClaims returns the empty hub claims
+func NewHubClaims() *HubClaims {
+	return &HubClaims{claims: map[types.NamespacedName]map[string]bool{}}
+}
+
+// Claim records the hub of the subscription and returns the hub winning the subscription, an empty string is the
+// primary hub
+func (c *HubClaims) Claim(sub *appv1.Subscription) string {
+	hubName := KnownSubscriptionHubName(sub)
+	if c == nil {
+		return hubName
+	}
+
+	key := types.NamespacedName{Namespace: sub.GetNamespace(), Name: sub.GetName()}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.claims == nil {
+		c.claims = map[types.NamespacedName]map[string]bool{}
+	}
+
+	// The work agent of each hub applying the subscription owns it with its AppliedManifestWork, the owner is removed
+	// when the hub deletes its ManifestWork. With a single owner left the other hubs stopped propagating the
+	// subscription, e.g. their hub-name annotation changed, their claims are stale
+	if c.claims[key] == nil || appliedManifestWorkOwners(sub) <= 1 {
+		c.claims[key] = map[string]bool{}
+	}
+
+	c.claims[key][hubName] = true
+
+	winner := hubName
+
+	for claimed := range c.claims[key] {
+		if hubPriority(claimed) < hubPriority(winner) {
+			winner = claimed
+		}
+	}
+
+	return winner
+}
+
+// Release forgets the hubs of a deleted subscription, the next hub propagating it wins it
+func (c *HubClaims) Release(key types.NamespacedName) {
+	if c == nil {
+		return
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	delete(c.claims, key)
+}
+
+// appliedManifestWorkOwners counts the AppliedManifestWorks owning the subscription, one per hub propagating it
+func appliedManifestWorkOwners(sub *appv1.Subscription) int {
+	owners := 0
+
+	for _, owner := range sub.GetOwnerReferences() {
+		if owner.Kind == "AppliedManifestWork" && strings.HasPrefix(owner.APIVersion, "work.open-cluster-management.io/") {
+			owners++
+		}
+	}
+
+	return owners
+}
+
+// SelectHubClient picks the hub client for the subscription. The primary hub takes precedence: the
+// subscription is reconciled against an additional hub only if it is explicitly annotated with a known
+// additional hub name. Unknown hub names fall back to the primary hub
+func SelectHubClient(sub *appv1.Subscription, primary client.Client, additional map[string]client.Client) client.Client {
+	hubName := GetSubscriptionHubName(sub)
+
+	if hubName == "" {
+		return primary
+	}
+
+	if hubclient, ok := additional[hubName]; ok && hubclient != nil {
+		return hubclient
+	}
+
+	return primary
+}
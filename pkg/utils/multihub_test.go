@@ -0,0 +1,115 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func TestParseAdditionalHubConfigs(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	hubs, err := ParseAdditionalHubConfigs([]string{"hub2=/var/run/hub2/kubeconfig", "hub3/cluster-x=/var/run/hub3/kubeconfig"}, "cluster1")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(hubs).To(HaveLen(2))
+	g.Expect(hubs[0].Name).To(Equal("hub2"))
+	g.Expect(hubs[0].ClusterName).To(Equal("cluster1"))
+	g.Expect(hubs[0].ConfigFilePathName).To(Equal("/var/run/hub2/kubeconfig"))
+	g.Expect(hubs[1].Name).To(Equal("hub3"))
+	g.Expect(hubs[1].ClusterNamespacedName.String()).To(Equal("cluster-x/cluster-x"))
+
+	_, err = ParseAdditionalHubConfigs([]string{"/var/run/hub2/kubeconfig"}, "cluster1")
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = ParseAdditionalHubConfigs([]string{"hub2=/a", "hub2=/b"}, "cluster1")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestSelectHubClient(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	primary := fake.NewClientBuilder().Build()
+	hub2 := fake.NewClientBuilder().Build()
+	additional := map[string]client.Client{"hub2": hub2}
+
+	sub := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{}}}
+	g.Expect(SelectHubClient(sub, primary, additional)).To(BeIdenticalTo(primary))
+
+	sub.Annotations[appv1.AnnotationHubName] = "hub2"
+	g.Expect(SelectHubClient(sub, primary, additional)).To(BeIdenticalTo(hub2))
+
+	sub.Annotations[appv1.AnnotationHubName] = "unknown"
+	g.Expect(SelectHubClient(sub, primary, additional)).To(BeIdenticalTo(primary))
+}
+
+func TestHubClaims(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	SetAdditionalHubConfigs([]HubConfig{{Name: "hub2", ClusterName: "cluster1"}, {Name: "hub3", ClusterName: "cluster-x"}})
+	defer SetAdditionalHubConfigs(nil)
+
+	newSub := func(hubName string, owners ...string) *appv1.Subscription {
+		sub := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "sub", Namespace: "ns"}}
+		if hubName != "" {
+			sub.Annotations = map[string]string{appv1.AnnotationHubName: hubName}
+		}
+
+		for _, owner := range owners {
+			sub.OwnerReferences = append(sub.OwnerReferences, metav1.OwnerReference{
+				APIVersion: "work.open-cluster-management.io/v1", Kind: "AppliedManifestWork", Name: owner})
+		}
+
+		return sub
+	}
+
+	// the status is reported to the namespace of the cluster on the hub of the subscription
+	g.Expect(SelectHubClusterName(newSub(""), "cluster1")).To(Equal("cluster1"))
+	g.Expect(SelectHubClusterName(newSub("hub3"), "cluster1")).To(Equal("cluster-x"))
+	g.Expect(SelectHubClusterName(newSub("unknown"), "cluster1")).To(Equal("cluster1"))
+
+	claims := NewHubClaims()
+
+	// the additional hubs conflict in the order of their flags
+	g.Expect(claims.Claim(newSub("hub3", "hub3-work"))).To(Equal("hub3"))
+	g.Expect(claims.Claim(newSub("hub2", "hub3-work", "hub2-work"))).To(Equal("hub2"))
+	g.Expect(claims.Claim(newSub("hub3", "hub3-work", "hub2-work"))).To(Equal("hub2"))
+
+	// the primary hub takes precedence, the unknown hubs are the primary hub
+	g.Expect(claims.Claim(newSub("unknown", "hub3-work", "hub2-work", "primary-work"))).To(Equal(""))
+	g.Expect(claims.Claim(newSub("hub2", "hub3-work", "hub2-work", "primary-work"))).To(Equal(""))
+
+	// the deleted subscription is won by the next hub propagating it
+	claims.Release(types.NamespacedName{Namespace: "ns", Name: "sub"})
+	g.Expect(claims.Claim(newSub("hub3", "hub3-work"))).To(Equal("hub3"))
+
+	// the claims of the hubs that stopped propagating the subscription are released, e.g. the subscription of hub2
+	// propagated without the hub-name annotation is claimed by the primary hub until the annotation is set
+	claims.Release(types.NamespacedName{Namespace: "ns", Name: "sub"})
+	g.Expect(claims.Claim(newSub("", "hub2-work"))).To(Equal(""))
+	g.Expect(claims.Claim(newSub("hub2", "hub2-work"))).To(Equal("hub2"))
+
+	// the subscription is won back by hub2 once the primary hub stops propagating it
+	g.Expect(claims.Claim(newSub("", "hub2-work", "primary-work"))).To(Equal(""))
+	g.Expect(claims.Claim(newSub("hub2", "hub2-work", "primary-work"))).To(Equal(""))
+	g.Expect(claims.Claim(newSub("hub2", "hub2-work"))).To(Equal("hub2"))
+}
@@ -0,0 +1,133 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	gerr "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	chnv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
+	awsutils "open-cluster-management.io/multicloud-operators-subscription/pkg/utils/aws"
+)
+
+// InitObjectStore connects to the object bucket of the channel with its secret and configmap, the handler and the
+// bucket name are returned
+func InitObjectStore(clt client.Client, channel *chnv1.Channel) (*awsutils.Handler, string, error) {
+	var err error
+
+	awshandler := &awsutils.Handler{}
+
+	pathName := channel.Spec.Pathname
+
+	if pathName == "" {
+		errmsg := "Empty Pathname in channel " + channel.Spec.Pathname
+		klog.Error(errmsg)
+
+		return nil, "", errors.New(errmsg)
+	}
+
+	if strings.HasSuffix(pathName, "/") {
+		last := len(pathName) - 1
+		pathName = pathName[:last]
+	}
+
+	loc := strings.LastIndex(pathName, "/")
+	endpoint := pathName[:loc]
+	bucket := pathName[loc+1:]
+
+	accessKeyID := ""
+	secretAccessKey := ""
+	region := ""
+
+	if channel.Spec.SecretRef != nil {
+		channelSecret := &corev1.Secret{}
+		chnseckey := types.NamespacedName{
+			Name:      channel.Spec.SecretRef.Name,
+			Namespace: channel.Namespace,
+		}
+
+		if err := clt.Get(context.TODO(), chnseckey, channelSecret); err != nil {
+			return nil, "", gerr.Wrap(err, "failed to get reference secret from channel")
+		}
+
+		err = yaml.Unmarshal(channelSecret.Data[awsutils.SecretMapKeyAccessKeyID], &accessKeyID)
+		if err != nil {
+			klog.Error("Failed to unmashall accessKey from secret with error:", err)
+
+			return nil, "", err
+		}
+
+		err = yaml.Unmarshal(channelSecret.Data[awsutils.SecretMapKeySecretAccessKey], &secretAccessKey)
+		if err != nil {
+			klog.Error("Failed to unmashall secretaccessKey from secret with error:", err)
+
+			return nil, "", err
+		}
+
+		regionData := channelSecret.Data[awsutils.SecretMapKeyRegion]
+
+		if len(regionData) > 0 {
+			err = yaml.Unmarshal(regionData, &region)
+			if err != nil {
+				klog.Error("Failed to unmashall region from secret with error:", err)
+
+				return nil, "", err
+			}
+		}
+	}
+
+	var channelConfigMap *corev1.ConfigMap
+
+	if channel.Spec.ConfigMapRef != nil {
+		channelConfigMap = &corev1.ConfigMap{}
+		chncfgkey := types.NamespacedName{
+			Name:      channel.Spec.ConfigMapRef.Name,
+			Namespace: channel.Namespace,
+		}
+
+		if err := clt.Get(context.TODO(), chncfgkey, channelConfigMap); err != nil {
+			return nil, "", gerr.Wrap(err, "failed to get reference configmap from channel")
+		}
+	}
+
+	opts, err := awsutils.ConnectionOptionsFromConfigMap(channelConfigMap, channel.Spec.InsecureSkipVerify)
+	if err != nil {
+		return nil, "", err
+	}
+
+	klog.V(1).Info("Trying to connect to object bucket ", endpoint, "|", bucket)
+
+	if err := awshandler.InitObjectStoreConnectionWithOptions(endpoint, accessKeyID, secretAccessKey, region, opts); err != nil {
+		klog.Error(err, "unable initialize object store settings")
+
+		return nil, "", err
+	}
+	// Check whether the connection is setup successfully
+	if err := awshandler.Exists(bucket); err != nil {
+		klog.Error(err, "Unable to access object store bucket ", bucket, " for channel ", channel.Name)
+
+		return nil, "", err
+	}
+
+	return awshandler, bucket, nil
+}
@@ -55,6 +55,26 @@ func PrepareOverrides(cluster types.NamespacedName, appsub *appsubv1.Subscriptio
 	return overrides, nil
 }
 
+// ValidatePackageOverride checks the package override has the path and the value applied by OverrideTemplate
+func ValidatePackageOverride(pov appsubv1.PackageOverride) error {
+	override := appsubv1.ClusterOverride(pov)
+
+	ovuobj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&override)
+	if err != nil {
+		return errors.New("can not parse override")
+	}
+
+	if path, ok := ovuobj["path"].(string); !ok || path == "" {
+		return errors.New("can not convert path of override")
+	}
+
+	if _, ok := ovuobj["value"]; !ok {
+		return errors.New("the override has no value")
+	}
+
+	return nil
+}
+
 // OverrideTemplate alter the given template with overrides.
 func OverrideTemplate(template *unstructured.Unstructured, overrides []appsubv1.ClusterOverride) (*unstructured.Unstructured, error) {
 	if klog.V(QuiteLogLel).Enabled() {
@@ -97,3 +117,32 @@ func OverrideTemplate(template *unstructured.Unstructured, overrides []appsubv1.
 
 	return ovt, nil
 }
+
+// MergePackageOverrides returns the package overrides with the override sets applied in order, an override set
+// replaces the package overrides of the same package name and the new packages are appended
+func MergePackageOverrides(packageOverrides []*appsubv1.Overrides, overrideSets ...[]*appsubv1.Overrides) []*appsubv1.Overrides {
+	merged := append([]*appsubv1.Overrides{}, packageOverrides...)
+
+	for _, overrideSet := range overrideSets {
+		for _, ov := range overrideSet {
+			if ov == nil {
+				continue
+			}
+
+			replaced := false
+
+			for i, existing := range merged {
+				if existing != nil && existing.PackageName == ov.PackageName {
+					merged[i] = ov
+					replaced = true
+				}
+			}
+
+			if !replaced {
+				merged = append(merged, ov)
+			}
+		}
+	}
+
+	return merged
+}
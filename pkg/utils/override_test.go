@@ -52,3 +52,24 @@ func TestPrepareOverrides(t *testing.T) {
 	g.Expect(err).NotTo(HaveOccurred())
 	g.Expect(overrideMap).To(BeNil())
 }
+
+func TestMergePackageOverrides(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	base := []*appv1.Overrides{
+		{PackageName: "nginx", PackageAlias: "base"},
+		{PackageName: "redis", PackageAlias: "base"},
+	}
+
+	merged := MergePackageOverrides(base,
+		[]*appv1.Overrides{{PackageName: "nginx", PackageAlias: "edge"}, {PackageName: "mysql", PackageAlias: "edge"}},
+		[]*appv1.Overrides{{PackageName: "nginx", PackageAlias: "small"}})
+
+	g.Expect(merged).To(HaveLen(3))
+	g.Expect(merged[0].PackageAlias).To(Equal("small"))
+	g.Expect(merged[1].PackageAlias).To(Equal("base"))
+	g.Expect(merged[2].PackageName).To(Equal("mysql"))
+
+	// the subscription package overrides are not changed
+	g.Expect(base[0].PackageAlias).To(Equal("base"))
+}
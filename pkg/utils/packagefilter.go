@@ -0,0 +1,231 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"net/url"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+// The package filter checks below are shared by the Git, ObjectBucket and Helm repo subscribers, so a filter selects
+// the same packages whatever the channel type. Names are resource names or chart names. Paths are relative to the git
+// path or the bucket folder, for helm repos the path of the chart URL is used
+
+// CheckPackageFilter checks the name, labels and annotations of a source manifest against the subscription package
+// and package filter. An empty string is returned if the manifest passes, otherwise the reason it is filtered out
+func CheckPackageFilter(sub *appv1.Subscription, name string, labels, annotations map[string]string) string {
+	if sub.Spec.Package != "" && sub.Spec.Package != name {
+		return "Name does not match, skiping:" + sub.Spec.Package + "|" + name
+	}
+
+	filter := sub.Spec.PackageFilter
+	if filter == nil {
+		return ""
+	}
+
+	if !MatchPackageNameRegex(filter, name) {
+		return "Name does not match the regex " + filter.NameRegex + ", skiping:" + name
+	}
+
+	if !LabelChecker(filter.LabelSelector, labels) {
+		return "Failed to pass label check on resource " + name
+	}
+
+	if !MatchPackageAnnotations(filter, annotations) {
+		return "Failed to pass annotation check to manifest " + name
+	}
+
+	return ""
+}
+
+// MatchPackageNameRegex checks the name against the nameRegex of the package filter, an invalid regex matches nothing
+func MatchPackageNameRegex(filter *appv1.PackageFilter, name string) bool {
+	if filter == nil || filter.NameRegex == "" {
+		return true
+	}
+
+	matched, err := regexp.MatchString(filter.NameRegex, name)
+	if err != nil {
+		klog.Errorf("invalid package filter nameRegex %v, err: %v", filter.NameRegex, err)
+
+		return false
+	}
+
+	return matched
+}
+
+// MatchPackageAnnotations checks the annotations against both the exact annotations and the annotationSelector of the package filter
+func MatchPackageAnnotations(filter *appv1.PackageFilter, annotations map[string]string) bool {
+	if filter == nil {
+		return true
+	}
+
+	for k, v := range filter.Annotations {
+		if annotations[k] != v {
+			klog.Info("Annotation filter does not match:", k, "|", v, "|", annotations[k])
+
+			return false
+		}
+	}
+
+	if filter.AnnotationSelector != nil && !LabelChecker(filter.AnnotationSelector, annotations) {
+		klog.Info("Annotation selector does not match:", annotations)
+
+		return false
+	}
+
+	return true
+}
+
+// MatchPackagePath checks the path relative to the channel root against the includePaths and excludePaths globs of
+// the package filter. The path must match one of the include globs if any, and none of the exclude globs
+func MatchPackagePath(filter *appv1.PackageFilter, relPath string) bool {
+	if filter == nil {
+		return true
+	}
+
+	relPath = strings.TrimPrefix(path.Clean("/"+relPath), "/")
+
+	if len(filter.IncludePaths) > 0 {
+		included := false
+
+		for _, pattern := range filter.IncludePaths {
+			if MatchPathGlob(pattern, relPath) {
+				included = true
+
+				break
+			}
+		}
+
+		if !included {
+			return false
+		}
+	}
+
+	for _, pattern := range filter.ExcludePaths {
+		if MatchPathGlob(pattern, relPath) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ManagedSkipFunc returns the SortResources skip function of the managed clusters, the hook folders are skipped and so
+// are the paths that don't match the includePaths and excludePaths of the package filter
+func ManagedSkipFunc(filter *appv1.PackageFilter) SkipFunc {
+	if filter == nil || (len(filter.IncludePaths) == 0 && len(filter.ExcludePaths) == 0) {
+		return SkipHooksOnManaged
+	}
+
+	return func(resourcePath, curPath string) bool {
+		relPath, err := filepath.Rel(resourcePath, curPath)
+		if err != nil || relPath == "." {
+			return SkipHooksOnManaged(resourcePath, curPath)
+		}
+
+		return !MatchPackagePath(filter, filepath.ToSlash(relPath)) || SkipHooksOnManaged(resourcePath, curPath)
+	}
+}
+
+// ExcludePathsSkipFunc adds the exclude globs of the channel to a SortResources skip function. The globs are relative
+// to the repo root, as the channel is shared by the subscriptions of the different paths of the repo
+func ExcludePathsSkipFunc(repoRoot string, globs []string, skip SkipFunc) SkipFunc {
+	if skip == nil {
+		skip = func(string, string) bool { return false }
+	}
+
+	if len(globs) == 0 {
+		return skip
+	}
+
+	return func(resourcePath, curPath string) bool {
+		relPath, err := filepath.Rel(repoRoot, curPath)
+		if err == nil && relPath != "." {
+			for _, pattern := range globs {
+				if MatchPathGlob(pattern, filepath.ToSlash(relPath)) {
+					return true
+				}
+			}
+		}
+
+		return skip(resourcePath, curPath)
+	}
+}
+
+// MatchPathGlob matches a slash separated path against a glob. "*" and "?" don't match "/", "**" matches any number
+// of directories. A glob also matches all the paths under the directories it matches, e.g. "apps" matches "apps/cm.yaml"
+func MatchPathGlob(pattern, p string) bool {
+	// "dir/**" matches the directory itself too, like "dir"
+	pattern = strings.TrimSuffix(strings.Trim(pattern, "/"), "/**")
+	if pattern == "" {
+		return false
+	}
+
+	re, err := regexp.Compile("^" + globToRegex(pattern) + "(/.*)?$")
+	if err != nil {
+		klog.Errorf("invalid path glob %v, err: %v", pattern, err)
+
+		return false
+	}
+
+	return re.MatchString(p)
+}
+
+func globToRegex(pattern string) string {
+	var sb strings.Builder
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+
+		switch {
+		case c == '*' && i+1 < len(pattern) && pattern[i+1] == '*':
+			i++
+
+			if i+1 < len(pattern) && pattern[i+1] == '/' {
+				// "**/" matches zero or more directories
+				i++
+
+				sb.WriteString("(.*/)?")
+			} else {
+				sb.WriteString(".*")
+			}
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	return sb.String()
+}
+
+// chartURLPath returns the path of the chart URL used to match the package filter paths, the scheme and host are stripped
+func chartURLPath(u string) string {
+	if parsed, err := url.Parse(u); err == nil && parsed.Scheme != "" {
+		return parsed.Path
+	}
+
+	return u
+}
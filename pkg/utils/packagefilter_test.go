@@ -0,0 +1,163 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/repo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func TestMatchPathGlob(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(MatchPathGlob("apps", "apps/nginx/deploy.yaml")).To(BeTrue())
+	g.Expect(MatchPathGlob("apps/**", "apps")).To(BeTrue())
+	g.Expect(MatchPathGlob("apps/*.yaml", "apps/cm.yaml")).To(BeTrue())
+	g.Expect(MatchPathGlob("apps/*.yaml", "apps/nginx/cm.yaml")).To(BeFalse())
+	g.Expect(MatchPathGlob("**/test/*.yaml", "test/cm.yaml")).To(BeTrue())
+	g.Expect(MatchPathGlob("**/test/*.yaml", "apps/nginx/test/cm.yaml")).To(BeTrue())
+	g.Expect(MatchPathGlob("apps/cm?.yaml", "apps/cm1.yaml")).To(BeTrue())
+	g.Expect(MatchPathGlob("apps", "apps-old/cm.yaml")).To(BeFalse())
+	g.Expect(MatchPathGlob("", "apps/cm.yaml")).To(BeFalse())
+}
+
+func TestMatchPackagePath(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(MatchPackagePath(nil, "apps/cm.yaml")).To(BeTrue())
+
+	filter := &appv1.PackageFilter{
+		IncludePaths: []string{"apps/**", "infra/*.yaml"},
+		ExcludePaths: []string{"**/test"},
+	}
+
+	g.Expect(MatchPackagePath(filter, "apps/nginx/deploy.yaml")).To(BeTrue())
+	g.Expect(MatchPackagePath(filter, "/infra/ns.yaml")).To(BeTrue())
+	g.Expect(MatchPackagePath(filter, "infra/rbac/role.yaml")).To(BeFalse())
+	g.Expect(MatchPackagePath(filter, "apps/nginx/test/pod.yaml")).To(BeFalse())
+	g.Expect(MatchPackagePath(filter, "docs/readme.yaml")).To(BeFalse())
+}
+
+func TestManagedSkipFuncChartAndKustomizeRoots(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	repoRoot := t.TempDir()
+	configMap := []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n")
+
+	for name, content := range map[string][]byte{
+		"apps/cm.yaml":                          configMap,
+		"apps/nginx/Chart.yaml":                 []byte("apiVersion: v2\nname: nginx\nversion: 1.0.0\n"),
+		"apps/nginx/templates/cm.yaml":          configMap,
+		"apps/overlay/kustomization.yaml":       []byte("resources:\n- base/cm.yaml\n"),
+		"apps/overlay/base/cm.yaml":             configMap,
+		"apps/overlay/base/nested/cm.yaml":      configMap,
+		"apps/included/nginx/Chart.yaml":        []byte("apiVersion: v2\nname: nginx\nversion: 1.0.0\n"),
+		"apps/included/nginx/templates/cm.yaml": configMap,
+	} {
+		path := filepath.Join(repoRoot, name)
+		g.Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(Succeed())
+		g.Expect(os.WriteFile(path, content, 0600)).To(Succeed())
+	}
+
+	// the include globs only match the files below the chart and kustomization roots, these files are neither
+	// applied as plain manifests nor deployed with helm or kustomize
+	skip := ManagedSkipFunc(&appv1.PackageFilter{
+		IncludePaths: []string{"*.yaml", "**/templates/*.yaml", "overlay/base/**", "included"},
+	})
+
+	chartDirs, kustomizeDirs, _, _, otherFiles, err := SortResources(repoRoot, filepath.Join(repoRoot, "apps"), skip)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(otherFiles).To(ConsistOf(filepath.Join(repoRoot, "apps", "cm.yaml")))
+	g.Expect(kustomizeDirs).To(BeEmpty())
+
+	includedChart := filepath.Join(repoRoot, "apps", "included", "nginx") + "/"
+	g.Expect(chartDirs).To(Equal(map[string]string{includedChart: includedChart}))
+}
+
+func TestCheckPackageFilter(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sub := &appv1.Subscription{
+		Spec: appv1.SubscriptionSpec{
+			PackageFilter: &appv1.PackageFilter{
+				NameRegex: "^nginx-.*",
+				LabelSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"env": "prod"},
+				},
+				AnnotationSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "team", Operator: metav1.LabelSelectorOpIn, Values: []string{"web", "api"}},
+					},
+				},
+			},
+		},
+	}
+
+	prod := map[string]string{"env": "prod"}
+
+	g.Expect(CheckPackageFilter(sub, "nginx-cm", prod, map[string]string{"team": "web"})).To(BeEmpty())
+	g.Expect(CheckPackageFilter(sub, "redis-cm", prod, map[string]string{"team": "web"})).To(ContainSubstring("regex"))
+	g.Expect(CheckPackageFilter(sub, "nginx-cm", nil, map[string]string{"team": "web"})).To(ContainSubstring("label check"))
+	g.Expect(CheckPackageFilter(sub, "nginx-cm", prod, map[string]string{"team": "db"})).To(ContainSubstring("annotation check"))
+
+	sub.Spec.PackageFilter.NameRegex = "(["
+	g.Expect(CheckPackageFilter(sub, "nginx-cm", prod, map[string]string{"team": "web"})).NotTo(BeEmpty())
+}
+
+func TestFilterChartsWithPackageFilter(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	chartVersion := func(name, version, url string, annotations map[string]string) *repo.ChartVersion {
+		return &repo.ChartVersion{
+			Metadata: &chart.Metadata{Name: name, Version: version, Annotations: annotations},
+			URLs:     []string{url},
+		}
+	}
+
+	indexFile := repo.NewIndexFile()
+	indexFile.Entries["nginx"] = repo.ChartVersions{
+		chartVersion("nginx", "1.0.0", "https://charts.example.com/stable/nginx-1.0.0.tgz", map[string]string{"tier": "web"}),
+		chartVersion("nginx", "2.0.0", "https://charts.example.com/incubator/nginx-2.0.0.tgz", map[string]string{"tier": "web"}),
+	}
+	indexFile.Entries["redis"] = repo.ChartVersions{
+		chartVersion("redis", "1.0.0", "https://charts.example.com/stable/redis-1.0.0.tgz", map[string]string{"tier": "db"}),
+	}
+
+	sub := &appv1.Subscription{
+		Spec: appv1.SubscriptionSpec{
+			PackageFilter: &appv1.PackageFilter{
+				NameRegex:    "^nginx$",
+				IncludePaths: []string{"stable"},
+				AnnotationSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"tier": "web"},
+				},
+			},
+		},
+	}
+
+	FilterChartsByPath(sub, indexFile)
+	g.Expect(FilterCharts(sub, indexFile)).To(Succeed())
+	g.Expect(indexFile.Entries).To(HaveLen(1))
+	g.Expect(indexFile.Entries["nginx"]).To(HaveLen(1))
+	g.Expect(indexFile.Entries["nginx"][0].Version).To(Equal("1.0.0"))
+}
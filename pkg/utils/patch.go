@@ -0,0 +1,177 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/klog/v2"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+// ValidatePackagePatches checks the type and the content of all the patches in the subscription package overrides,
+// including the package overrides of the override rules
+func ValidatePackagePatches(sub *appv1.Subscription) error {
+	packageOverrides := append([]*appv1.Overrides{}, sub.Spec.PackageOverrides...)
+
+	for _, rule := range sub.Spec.OverrideRules {
+		packageOverrides = append(packageOverrides, rule.PackageOverrides...)
+	}
+
+	for _, ov := range packageOverrides {
+		if ov == nil {
+			continue
+		}
+
+		for i, patch := range ov.Patches {
+			if _, err := decodePatch(patch); err != nil {
+				return fmt.Errorf("invalid patch %v of package %v: %w", i, ov.PackageName, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ApplyPackagePatches applies the patches of the subscription package overrides to the rendered resource, in the
+// order they are defined. The resource is not changed if a patch fails, the error tells which patch failed
+func ApplyPackagePatches(sub *appv1.Subscription, rsc *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	if sub == nil || rsc == nil {
+		return rsc, nil
+	}
+
+	patched := rsc
+
+	for _, ov := range sub.Spec.PackageOverrides {
+		if ov == nil {
+			continue
+		}
+
+		for i, patch := range ov.Patches {
+			if !matchPatchTarget(patch.Target, ov.PackageName, patched) {
+				continue
+			}
+
+			obj, err := applyPackagePatch(patch, patched)
+			if err != nil {
+				return nil, fmt.Errorf("failed to apply %v patch %v of package %v to %v %v/%v: %w",
+					patch.Type, i, ov.PackageName, rsc.GetKind(), rsc.GetNamespace(), rsc.GetName(), err)
+			}
+
+			klog.V(1).Infof("applied %v patch %v of package %v to %v %v/%v", patch.Type, i, ov.PackageName,
+				rsc.GetKind(), rsc.GetNamespace(), rsc.GetName())
+
+			patched = obj
+		}
+	}
+
+	return patched, nil
+}
+
+func matchPatchTarget(target *appv1.PatchTarget, packageName string, rsc *unstructured.Unstructured) bool {
+	name := packageName
+
+	if target != nil {
+		gvk := rsc.GroupVersionKind()
+
+		if (target.Group != "" && target.Group != gvk.Group) ||
+			(target.Version != "" && target.Version != gvk.Version) ||
+			(target.Kind != "" && target.Kind != gvk.Kind) ||
+			(target.Namespace != "" && target.Namespace != rsc.GetNamespace()) {
+			return false
+		}
+
+		if target.Name != "" {
+			name = target.Name
+		}
+	}
+
+	return name == rsc.GetName()
+}
+
+// decodePatch returns the JSON of the patch after checking it can be applied with the patch type
+func decodePatch(patch appv1.PackagePatch) ([]byte, error) {
+	patchJSON, err := yaml.YAMLToJSON([]byte(patch.Patch))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the patch: %w", err)
+	}
+
+	switch patch.Type {
+	case appv1.PatchTypeJSON6902:
+		if _, err := jsonpatch.DecodePatch(patchJSON); err != nil {
+			return nil, fmt.Errorf("failed to decode the json6902 patch: %w", err)
+		}
+	case appv1.PatchTypeStrategicMerge, appv1.PatchTypeJSONMergePatch:
+		obj := map[string]interface{}{}
+
+		if err := json.Unmarshal(patchJSON, &obj); err != nil {
+			return nil, fmt.Errorf("the %v patch must be an object: %w", patch.Type, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported patch type %q", patch.Type)
+	}
+
+	return patchJSON, nil
+}
+
+func applyPackagePatch(patch appv1.PackagePatch, rsc *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	patchJSON, err := decodePatch(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := rsc.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	var patchedJSON []byte
+
+	switch patch.Type {
+	case appv1.PatchTypeJSON6902:
+		ops, _ := jsonpatch.DecodePatch(patchJSON)
+		patchedJSON, err = ops.Apply(original)
+	case appv1.PatchTypeJSONMergePatch:
+		patchedJSON, err = jsonpatch.MergePatch(original, patchJSON)
+	case appv1.PatchTypeStrategicMerge:
+		typed, schemeErr := scheme.Scheme.New(rsc.GroupVersionKind())
+		if schemeErr != nil {
+			// the kinds unknown to the scheme, like custom resources, have no patch strategy
+			klog.V(1).Infof("no schema for %v, apply the strategic merge patch as a JSON merge patch", rsc.GroupVersionKind())
+
+			patchedJSON, err = jsonpatch.MergePatch(original, patchJSON)
+		} else {
+			patchedJSON, err = strategicpatch.StrategicMergePatch(original, patchJSON, typed)
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(patchedJSON); err != nil {
+		return nil, fmt.Errorf("the patched resource is invalid: %w", err)
+	}
+
+	return obj, nil
+}
@@ -0,0 +1,160 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func patchTestDeployment() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "nginx", "namespace": "default"},
+		"spec": map[string]interface{}{
+			"replicas": int64(1),
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "nginx", "image": "nginx:1.20"},
+						map[string]interface{}{"name": "sidecar", "image": "envoy:1.0"},
+					},
+				},
+			},
+		},
+	}}
+}
+
+func TestApplyPackagePatches(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sub := &appv1.Subscription{
+		Spec: appv1.SubscriptionSpec{
+			PackageOverrides: []*appv1.Overrides{
+				{
+					PackageName: "nginx",
+					Patches: []appv1.PackagePatch{
+						{
+							Type:   appv1.PatchTypeStrategicMerge,
+							Target: &appv1.PatchTarget{Kind: "Deployment"},
+							Patch: `spec:
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.21`,
+						},
+						{
+							Type:  appv1.PatchTypeJSON6902,
+							Patch: `[{"op": "replace", "path": "/spec/replicas", "value": 3}]`,
+						},
+						{
+							Type:   appv1.PatchTypeJSONMergePatch,
+							Target: &appv1.PatchTarget{Kind: "ConfigMap"},
+							Patch:  `{"data": {"key": "value"}}`,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	patched, err := ApplyPackagePatches(sub, patchTestDeployment())
+	g.Expect(err).NotTo(HaveOccurred())
+
+	replicas, _, _ := unstructured.NestedInt64(patched.Object, "spec", "replicas")
+	g.Expect(replicas).To(Equal(int64(3)))
+
+	containers, _, _ := unstructured.NestedSlice(patched.Object, "spec", "template", "spec", "containers")
+	g.Expect(containers).To(HaveLen(2))
+	g.Expect(containers[0].(map[string]interface{})["image"]).To(Equal("nginx:1.21"))
+	g.Expect(containers[1].(map[string]interface{})["image"]).To(Equal("envoy:1.0"))
+
+	_, found, _ := unstructured.NestedMap(patched.Object, "data")
+	g.Expect(found).To(BeFalse())
+
+	// a failed patch reports its index and type
+	sub.Spec.PackageOverrides[0].Patches[1].Patch = `[{"op": "remove", "path": "/spec/paused"}]`
+
+	_, err = ApplyPackagePatches(sub, patchTestDeployment())
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("json6902 patch 1 of package nginx"))
+}
+
+func TestApplyPackagePatchesCustomResource(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cr := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Widget",
+		"metadata":   map[string]interface{}{"name": "widget"},
+		"spec":       map[string]interface{}{"size": "small", "color": "red"},
+	}}
+
+	sub := &appv1.Subscription{
+		Spec: appv1.SubscriptionSpec{
+			PackageOverrides: []*appv1.Overrides{
+				{
+					PackageName: "widgets",
+					Patches: []appv1.PackagePatch{
+						{
+							Type:   appv1.PatchTypeStrategicMerge,
+							Target: &appv1.PatchTarget{Group: "example.com", Kind: "Widget", Name: "widget"},
+							Patch:  `{"spec": {"size": "large", "color": null}}`,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	patched, err := ApplyPackagePatches(sub, cr)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(patched.Object["spec"]).To(Equal(map[string]interface{}{"size": "large"}))
+}
+
+func TestValidatePackagePatches(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sub := &appv1.Subscription{
+		Spec: appv1.SubscriptionSpec{
+			PackageOverrides: []*appv1.Overrides{
+				{
+					PackageName: "nginx",
+					Patches: []appv1.PackagePatch{
+						{Type: appv1.PatchTypeJSONMergePatch, Patch: "spec:\n  replicas: 2"},
+					},
+				},
+			},
+		},
+	}
+
+	g.Expect(ValidatePackagePatches(sub)).To(Succeed())
+
+	sub.Spec.PackageOverrides[0].Patches = append(sub.Spec.PackageOverrides[0].Patches,
+		appv1.PackagePatch{Type: appv1.PatchTypeJSON6902, Patch: `{"op": "add"}`})
+	g.Expect(ValidatePackagePatches(sub)).To(MatchError(ContainSubstring("invalid patch 1 of package nginx")))
+
+	sub.Spec.PackageOverrides[0].Patches[1] = appv1.PackagePatch{Type: "replace", Patch: "{}"}
+	g.Expect(ValidatePackagePatches(sub)).To(MatchError(ContainSubstring("unsupported patch type")))
+
+	sub.Spec.PackageOverrides[0].Patches[1] = appv1.PackagePatch{Type: appv1.PatchTypeStrategicMerge, Patch: "- a\n- b"}
+	g.Expect(ValidatePackagePatches(sub)).To(MatchError(ContainSubstring("must be an object")))
+}
@@ -0,0 +1,188 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"expvar"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// CacheGitClone is the cache of the Git repositories cloned by the subscriptions
+	CacheGitClone = "git-clone"
+	// CacheGitRender is the cache of the resources rendered from the Git repositories of the subscriptions
+	CacheGitRender = "git-render"
+)
+
+var (
+	profilingEnabled int32
+
+	publishOnce sync.Once
+
+	cacheSizesMtx sync.Mutex
+	// cacheSizes are the sizes in bytes of the cache entries, per cache and entry key
+	cacheSizes = map[string]map[string]int64{}
+)
+
+// ServeProfiling serves the pprof endpoints under /debug/pprof/ and the expvar endpoint /debug/vars on the address.
+// The expvar endpoint reports the memstats, the runtime stats and the size of the clone and render caches, the cache
+// sizes are only accounted once the profiling is served. The endpoints are plain HTTP without authentication, the
+// address should be a loopback address reached with a port-forward
+func ServeProfiling(addr string) error {
+	server := http.Server{
+		Handler:           newProfilingHandler(),
+		ReadHeaderTimeout: 5 * time.Second,
+		Addr:              addr,
+	}
+
+	if !isLoopbackAddr(addr) {
+		klog.Warningf("profiling server %v is not bound to a loopback address, its endpoints are not authenticated", addr)
+	}
+
+	klog.Infof("profiling server is running on %v", addr)
+
+	return server.ListenAndServe()
+}
+
+// isLoopbackAddr checks if the host of the address is localhost or a loopback IP
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+
+	return ip != nil && ip.IsLoopback()
+}
+
+// newProfilingHandler enables the cache accounting and returns the handler of the profiling endpoints
+func newProfilingHandler() http.Handler {
+	atomic.StoreInt32(&profilingEnabled, 1)
+
+	publishOnce.Do(func() {
+		expvar.Publish("runtime", expvar.Func(runtimeStats))
+		expvar.Publish("caches", expvar.Func(cacheStats))
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	return mux
+}
+
+// IsProfilingEnabled checks if the profiling is served, the callers skip the costly cache accounting otherwise
+func IsProfilingEnabled() bool {
+	return atomic.LoadInt32(&profilingEnabled) == 1
+}
+
+// RecordCacheSize sets the size of the entry of the cache when the profiling is enabled
+func RecordCacheSize(cache, key string, size int64) {
+	if !IsProfilingEnabled() {
+		return
+	}
+
+	cacheSizesMtx.Lock()
+	defer cacheSizesMtx.Unlock()
+
+	if cacheSizes[cache] == nil {
+		cacheSizes[cache] = map[string]int64{}
+	}
+
+	cacheSizes[cache][key] = size
+}
+
+// ForgetCacheSize removes the entry of the cache
+func ForgetCacheSize(cache, key string) {
+	cacheSizesMtx.Lock()
+	defer cacheSizesMtx.Unlock()
+
+	delete(cacheSizes[cache], key)
+}
+
+// DirSize returns the total size of the regular files in the directory
+func DirSize(dir string) int64 {
+	size := int64(0)
+
+	_ = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+
+		return nil
+	})
+
+	return size
+}
+
+type cacheStat struct {
+	Entries    int              `json:"entries"`
+	TotalBytes int64            `json:"totalBytes"`
+	Bytes      map[string]int64 `json:"bytes"`
+}
+
+func cacheStats() interface{} {
+	cacheSizesMtx.Lock()
+	defer cacheSizesMtx.Unlock()
+
+	stats := map[string]cacheStat{}
+
+	for cache, entries := range cacheSizes {
+		stat := cacheStat{Entries: len(entries), Bytes: map[string]int64{}}
+
+		for key, size := range entries {
+			stat.Bytes[key] = size
+			stat.TotalBytes += size
+		}
+
+		stats[cache] = stat
+	}
+
+	return stats
+}
+
+func runtimeStats() interface{} {
+	return map[string]interface{}{
+		"goroutines": runtime.NumGoroutine(),
+		"gomaxprocs": runtime.GOMAXPROCS(0),
+		"numCPU":     runtime.NumCPU(),
+		"version":    runtime.Version(),
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestProfilingCacheSizes(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	RecordCacheSize(CacheGitClone, "ns/ignored", 10)
+	g.Expect(cacheStats()).NotTo(HaveKey(CacheGitClone))
+
+	ts := httptest.NewServer(newProfilingHandler())
+	defer ts.Close()
+
+	g.Expect(IsProfilingEnabled()).To(BeTrue())
+
+	dir := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(dir, "a.yaml"), make([]byte, 100), 0600)).To(Succeed())
+	g.Expect(os.MkdirAll(filepath.Join(dir, "sub"), 0700)).To(Succeed())
+	g.Expect(os.WriteFile(filepath.Join(dir, "sub", "b.yaml"), make([]byte, 50), 0600)).To(Succeed())
+	g.Expect(DirSize(dir)).To(Equal(int64(150)))
+
+	RecordCacheSize(CacheGitClone, "ns/sub1", DirSize(dir))
+	RecordCacheSize(CacheGitClone, "ns/sub2", 50)
+	RecordCacheSize(CacheGitRender, "ns/sub1", 20)
+
+	resp, err := http.Get(ts.URL + "/debug/vars")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	defer resp.Body.Close()
+
+	vars := struct {
+		Caches  map[string]cacheStat   `json:"caches"`
+		Runtime map[string]interface{} `json:"runtime"`
+	}{}
+	g.Expect(json.NewDecoder(resp.Body).Decode(&vars)).To(Succeed())
+	g.Expect(vars.Caches[CacheGitClone].Entries).To(Equal(2))
+	g.Expect(vars.Caches[CacheGitClone].TotalBytes).To(Equal(int64(200)))
+	g.Expect(vars.Caches[CacheGitRender].Bytes).To(Equal(map[string]int64{"ns/sub1": 20}))
+	g.Expect(vars.Runtime).To(HaveKey("goroutines"))
+
+	ForgetCacheSize(CacheGitClone, "ns/sub1")
+	g.Expect(cacheStats()).To(HaveKeyWithValue(CacheGitClone, cacheStat{Entries: 1, TotalBytes: 50, Bytes: map[string]int64{"ns/sub2": 50}}))
+
+	pprofResp, err := http.Get(ts.URL + "/debug/pprof/")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	defer pprofResp.Body.Close()
+
+	g.Expect(pprofResp.StatusCode).To(Equal(http.StatusOK))
+}
+
+func TestIsLoopbackAddr(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(isLoopbackAddr("localhost:6060")).To(BeTrue())
+	g.Expect(isLoopbackAddr("127.0.0.1:6060")).To(BeTrue())
+	g.Expect(isLoopbackAddr("[::1]:6060")).To(BeTrue())
+	g.Expect(isLoopbackAddr(":6060")).To(BeFalse())
+	g.Expect(isLoopbackAddr("0.0.0.0:6060")).To(BeFalse())
+	g.Expect(isLoopbackAddr("10.0.0.5:6060")).To(BeFalse())
+}
@@ -0,0 +1,363 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/metrics"
+)
+
+const (
+	// EventReasonResourceThrottled is the reason of the appsub events of the throttling decisions of the agent
+	EventReasonResourceThrottled = "ResourceThrottled"
+
+	// DefaultCgroupRoot is where the cgroup of the agent container is mounted
+	DefaultCgroupRoot = "/sys/fs/cgroup"
+	// DefaultResourceThrottleInterval is the interval the cgroup usage of the agent is sampled at
+	DefaultResourceThrottleInterval = 10 * time.Second
+
+	// the memory usage, in percent of the cgroup limit, halving the apply concurrency
+	throttleApplyMemoryPercent = 80
+	// the memory usage applying one resource at a time
+	throttleMinApplyMemoryPercent = 90
+	// the memory usage deferring the Git clones that may not fit in the memory left
+	throttleCloneMemoryPercent = 90
+	// the CPU usage, in percent of the cgroup quota, halving the apply concurrency
+	throttleApplyCPUPercent = 90
+
+	// the cgroup v1 memory limit of the unlimited cgroups is the max int64 rounded to the page size
+	cgroupV1UnlimitedMemory = int64(1) << 62
+
+	// the throttling decisions counted in the metrics
+	ThrottleDecisionApplyConcurrency = "apply_concurrency"
+	ThrottleDecisionDeferGitClone    = "defer_git_clone"
+)
+
+// ResourcePressure is the usage of the agent cgroup against its limits, the percents are 0 without a limit
+type ResourcePressure struct {
+	MemoryPercent float64
+	CPUPercent    float64
+	// MemoryHeadroom is the memory left below the limit, in bytes
+	MemoryHeadroom int64
+}
+
+var (
+	resourcePressureLock sync.RWMutex
+	resourcePressure     ResourcePressure
+	throttlingEnabled    bool
+	gitCloneSizes        = map[string]int64{}
+)
+
+// SetResourcePressure sets the last sampled usage of the agent cgroup the throttling decisions are made from
+func SetResourcePressure(pressure ResourcePressure) {
+	resourcePressureLock.Lock()
+	defer resourcePressureLock.Unlock()
+
+	resourcePressure = pressure
+	throttlingEnabled = true
+}
+
+// GetResourcePressure returns the last sampled usage of the agent cgroup
+func GetResourcePressure() ResourcePressure {
+	resourcePressureLock.RLock()
+	defer resourcePressureLock.RUnlock()
+
+	return resourcePressure
+}
+
+// IsResourceThrottlingEnabled checks if the cgroup usage of the agent is sampled
+func IsResourceThrottlingEnabled() bool {
+	resourcePressureLock.RLock()
+	defer resourcePressureLock.RUnlock()
+
+	return throttlingEnabled
+}
+
+// ThrottledApplyConcurrency returns the apply concurrency reduced by the resource pressure, with the reason of the
+// reduction. The concurrency is halved above 80% of the memory limit or 90% of the CPU quota, and set to 1 above 90%
+// of the memory limit
+func ThrottledApplyConcurrency(concurrency int) (int, string) {
+	pressure := GetResourcePressure()
+
+	throttled, reason := concurrency, ""
+
+	switch {
+	case pressure.MemoryPercent >= throttleMinApplyMemoryPercent:
+		throttled = 1
+		reason = fmt.Sprintf("memory usage %.0f%% of the limit", pressure.MemoryPercent)
+	case pressure.MemoryPercent >= throttleApplyMemoryPercent:
+		throttled = concurrency / 2
+		reason = fmt.Sprintf("memory usage %.0f%% of the limit", pressure.MemoryPercent)
+	case pressure.CPUPercent >= throttleApplyCPUPercent:
+		throttled = concurrency / 2
+		reason = fmt.Sprintf("CPU usage %.0f%% of the quota", pressure.CPUPercent)
+	}
+
+	if throttled < 1 {
+		throttled = 1
+	}
+
+	if throttled == concurrency {
+		return concurrency, ""
+	}
+
+	return throttled, reason
+}
+
+// DeferGitClone checks if the Git clone of the appsub is deferred above 90% of the memory limit, with the reason. The
+// clones of unknown size and the clones larger than the memory left are deferred
+func DeferGitClone(appsubKey string) (string, bool) {
+	resourcePressureLock.RLock()
+	defer resourcePressureLock.RUnlock()
+
+	if resourcePressure.MemoryPercent < throttleCloneMemoryPercent {
+		return "", false
+	}
+
+	size, ok := gitCloneSizes[appsubKey]
+	if ok && size < resourcePressure.MemoryHeadroom {
+		return "", false
+	}
+
+	if !ok {
+		return fmt.Sprintf("memory usage %.0f%% of the limit, the size of the Git clone is unknown",
+			resourcePressure.MemoryPercent), true
+	}
+
+	return fmt.Sprintf("memory usage %.0f%% of the limit, the Git clone of %v bytes is larger than the %v bytes left",
+		resourcePressure.MemoryPercent, size, resourcePressure.MemoryHeadroom), true
+}
+
+// RecordGitCloneSize records the size of the Git clone of the appsub for the next clone deferral decisions
+func RecordGitCloneSize(appsubKey string, size int64) {
+	resourcePressureLock.Lock()
+	defer resourcePressureLock.Unlock()
+
+	gitCloneSizes[appsubKey] = size
+}
+
+// ForgetGitCloneSize drops the size of the Git clone of a stopped appsub
+func ForgetGitCloneSize(appsubKey string) {
+	resourcePressureLock.Lock()
+	defer resourcePressureLock.Unlock()
+
+	delete(gitCloneSizes, appsubKey)
+}
+
+// RecordThrottleDecision counts a throttling decision in the metrics
+func RecordThrottleDecision(decision, reason string) {
+	resource := "memory"
+	if strings.HasPrefix(reason, "CPU") {
+		resource = "cpu"
+	}
+
+	metrics.ResourceThrottleDecisionsTotal.WithLabelValues(decision, resource).Inc()
+}
+
+// cgroupUsage is a sample of the agent cgroup, the limits are 0 when the cgroup has none
+type cgroupUsage struct {
+	memoryUsage int64
+	memoryLimit int64
+	// the CPU time used by the cgroup since its creation, in seconds
+	cpuSeconds float64
+	// the CPU quota of the cgroup, in cores
+	cpuLimit float64
+}
+
+// readCgroupUsage reads the usage and the limits of the cgroup v2, or v1, mounted at the root. The memory usage is the
+// working set, without the inactive page cache the kernel reclaims before the OOM kill
+func readCgroupUsage(root string) (cgroupUsage, error) {
+	usage := cgroupUsage{}
+
+	if _, err := os.Stat(filepath.Join(root, "cgroup.controllers")); err == nil {
+		memoryLimit, err := readCgroupValue(filepath.Join(root, "memory.max"))
+		if err != nil {
+			return usage, err
+		}
+
+		if usage.memoryUsage, err = readCgroupValue(filepath.Join(root, "memory.current")); err != nil {
+			return usage, err
+		}
+
+		usage.memoryLimit = memoryLimit
+		usage.memoryUsage -= readCgroupStat(filepath.Join(root, "memory.stat"), "inactive_file")
+
+		usageUsec := readCgroupStat(filepath.Join(root, "cpu.stat"), "usage_usec")
+		usage.cpuSeconds = float64(usageUsec) / float64(time.Second/time.Microsecond)
+
+		if data, err := os.ReadFile(filepath.Join(root, "cpu.max")); err == nil {
+			fields := strings.Fields(string(data))
+			if len(fields) == 2 && fields[0] != "max" {
+				quota, qerr := strconv.ParseFloat(fields[0], 64)
+				period, perr := strconv.ParseFloat(fields[1], 64)
+
+				if qerr == nil && perr == nil && period > 0 {
+					usage.cpuLimit = quota / period
+				}
+			}
+		}
+
+		return usage, nil
+	}
+
+	memoryLimit, err := readCgroupValue(filepath.Join(root, "memory", "memory.limit_in_bytes"))
+	if err != nil {
+		return usage, err
+	}
+
+	if memoryLimit < cgroupV1UnlimitedMemory {
+		usage.memoryLimit = memoryLimit
+	}
+
+	if usage.memoryUsage, err = readCgroupValue(filepath.Join(root, "memory", "memory.usage_in_bytes")); err != nil {
+		return usage, err
+	}
+
+	usage.memoryUsage -= readCgroupStat(filepath.Join(root, "memory", "memory.stat"), "total_inactive_file")
+
+	if usageNsec, err := readCgroupValue(filepath.Join(root, "cpuacct", "cpuacct.usage")); err == nil {
+		usage.cpuSeconds = float64(usageNsec) / float64(time.Second)
+	}
+
+	quota, qerr := readCgroupValue(filepath.Join(root, "cpu", "cpu.cfs_quota_us"))
+	period, perr := readCgroupValue(filepath.Join(root, "cpu", "cpu.cfs_period_us"))
+
+	if qerr == nil && perr == nil && quota > 0 && period > 0 {
+		usage.cpuLimit = float64(quota) / float64(period)
+	}
+
+	return usage, nil
+}
+
+// readCgroupValue reads a cgroup file holding a single value, max is 0
+func readCgroupValue(path string) (int64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	v := strings.TrimSpace(string(data))
+	if v == "max" {
+		return 0, nil
+	}
+
+	return strconv.ParseInt(v, 10, 64)
+}
+
+// readCgroupStat reads a key of a cgroup stat file, 0 if it is missing
+func readCgroupStat(path, key string) int64 {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == key {
+			v, _ := strconv.ParseInt(fields[1], 10, 64)
+
+			return v
+		}
+	}
+
+	return 0
+}
+
+// ResourceThrottler samples the cgroup usage of the agent so the agent reduces its apply concurrency and defers the
+// large Git clones before it is OOM killed or CPU throttled mid-rollout
+type ResourceThrottler struct {
+	Root     string
+	Interval time.Duration
+
+	lastCPUSeconds float64
+	lastSample     time.Time
+}
+
+// NewResourceThrottler returns the throttler of the agent cgroup sampled at the interval
+func NewResourceThrottler(interval time.Duration) *ResourceThrottler {
+	if interval <= 0 {
+		interval = DefaultResourceThrottleInterval
+	}
+
+	return &ResourceThrottler{Root: DefaultCgroupRoot, Interval: interval}
+}
+
+// Start samples the cgroup usage until the context is done
+func (t *ResourceThrottler) Start(ctx context.Context) error {
+	klog.Infof("Sampling the cgroup usage of the agent every %v", t.Interval)
+
+	wait.UntilWithContext(ctx, func(context.Context) {
+		t.sample(time.Now())
+	}, t.Interval)
+
+	return nil
+}
+
+// sample reads the cgroup usage and updates the resource pressure and its metrics
+func (t *ResourceThrottler) sample(now time.Time) {
+	usage, err := readCgroupUsage(t.Root)
+	if err != nil {
+		klog.V(1).Infof("failed to read the cgroup usage of the agent, err: %v", err)
+
+		return
+	}
+
+	pressure := ResourcePressure{}
+
+	if usage.memoryLimit > 0 {
+		pressure.MemoryPercent = float64(usage.memoryUsage) * 100 / float64(usage.memoryLimit)
+		pressure.MemoryHeadroom = usage.memoryLimit - usage.memoryUsage
+	}
+
+	if usage.cpuLimit > 0 && !t.lastSample.IsZero() {
+		if elapsed := now.Sub(t.lastSample).Seconds(); elapsed > 0 {
+			pressure.CPUPercent = (usage.cpuSeconds - t.lastCPUSeconds) * 100 / elapsed / usage.cpuLimit
+		}
+	}
+
+	t.lastCPUSeconds = usage.cpuSeconds
+	t.lastSample = now
+
+	previous := GetResourcePressure()
+
+	SetResourcePressure(pressure)
+
+	metrics.AgentMemoryUsageRatio.Set(pressure.MemoryPercent / 100)
+	metrics.AgentCPUUsageRatio.Set(pressure.CPUPercent / 100)
+
+	if _, reason := ThrottledApplyConcurrency(2); reason != "" && previous.MemoryPercent < throttleApplyMemoryPercent &&
+		previous.CPUPercent < throttleApplyCPUPercent {
+		klog.Warningf("The agent is throttled, %v", reason)
+	} else if reason == "" && (previous.MemoryPercent >= throttleApplyMemoryPercent ||
+		previous.CPUPercent >= throttleApplyCPUPercent) {
+		klog.Infof("The agent isn't throttled anymore, memory usage %.0f%%, CPU usage %.0f%%", pressure.MemoryPercent,
+			pressure.CPUPercent)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestResourceThrottler(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	defer func() {
+		resourcePressureLock.Lock()
+		defer resourcePressureLock.Unlock()
+
+		resourcePressure = ResourcePressure{}
+		throttlingEnabled = false
+		gitCloneSizes = map[string]int64{}
+	}()
+
+	writeFiles := func(root string, files map[string]string) {
+		for name, content := range files {
+			path := filepath.Join(root, name)
+			g.Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(Succeed())
+			g.Expect(os.WriteFile(path, []byte(content), 0600)).To(Succeed())
+		}
+	}
+
+	// cgroup v2, the inactive page cache isn't counted
+	v2 := t.TempDir()
+	writeFiles(v2, map[string]string{
+		"cgroup.controllers": "cpu memory",
+		"memory.max":         "1000\n",
+		"memory.current":     "900\n",
+		"memory.stat":        "anon 700\ninactive_file 50\n",
+		"cpu.max":            "200000 100000\n",
+		"cpu.stat":           "usage_usec 10000000\n",
+	})
+
+	usage, err := readCgroupUsage(v2)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(usage).To(Equal(cgroupUsage{memoryUsage: 850, memoryLimit: 1000, cpuSeconds: 10, cpuLimit: 2}))
+
+	// cgroup v1 without limits
+	v1 := t.TempDir()
+	writeFiles(v1, map[string]string{
+		"memory/memory.limit_in_bytes": "9223372036854771712\n",
+		"memory/memory.usage_in_bytes": "500\n",
+		"cpu/cpu.cfs_quota_us":         "-1\n",
+		"cpu/cpu.cfs_period_us":        "100000\n",
+		"cpuacct/cpuacct.usage":        "3000000000\n",
+	})
+
+	usage, err = readCgroupUsage(v1)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(usage).To(Equal(cgroupUsage{memoryUsage: 500, cpuSeconds: 3}))
+
+	throttler := &ResourceThrottler{Root: v1, Interval: time.Second}
+	throttler.sample(time.Now())
+	g.Expect(IsResourceThrottlingEnabled()).To(BeTrue())
+	g.Expect(GetResourcePressure()).To(Equal(ResourcePressure{}))
+
+	// the CPU usage is measured between two samples, 1.9 of the 2 cores
+	now := time.Now()
+	throttler = &ResourceThrottler{Root: v2, Interval: time.Second}
+	throttler.sample(now)
+
+	writeFiles(v2, map[string]string{"cpu.stat": "usage_usec 29000000\n"})
+	throttler.sample(now.Add(10 * time.Second))
+
+	pressure := GetResourcePressure()
+	g.Expect(pressure.MemoryPercent).To(BeNumerically("~", 85, 0.01))
+	g.Expect(pressure.CPUPercent).To(BeNumerically("~", 95, 0.01))
+	g.Expect(pressure.MemoryHeadroom).To(Equal(int64(150)))
+
+	// the apply concurrency is halved above 80% of the memory limit, and set to 1 above 90%
+	concurrency, reason := ThrottledApplyConcurrency(8)
+	g.Expect(concurrency).To(Equal(4))
+	g.Expect(reason).To(Equal("memory usage 85% of the limit"))
+
+	SetResourcePressure(ResourcePressure{CPUPercent: 95})
+
+	concurrency, reason = ThrottledApplyConcurrency(8)
+	g.Expect(concurrency).To(Equal(4))
+	g.Expect(reason).To(Equal("CPU usage 95% of the quota"))
+
+	concurrency, reason = ThrottledApplyConcurrency(1)
+	g.Expect(concurrency).To(Equal(1))
+	g.Expect(reason).To(BeEmpty())
+
+	SetResourcePressure(ResourcePressure{MemoryPercent: 95, MemoryHeadroom: 100})
+
+	concurrency, _ = ThrottledApplyConcurrency(8)
+	g.Expect(concurrency).To(Equal(1))
+
+	// the clones of unknown size or larger than the memory left are deferred above 90% of the memory limit
+	_, deferred := DeferGitClone("ns/unknown")
+	g.Expect(deferred).To(BeTrue())
+
+	RecordGitCloneSize("ns/small", 10)
+	RecordGitCloneSize("ns/large", 1000)
+
+	_, deferred = DeferGitClone("ns/small")
+	g.Expect(deferred).To(BeFalse())
+
+	reason, deferred = DeferGitClone("ns/large")
+	g.Expect(deferred).To(BeTrue())
+	g.Expect(reason).To(ContainSubstring("the Git clone of 1000 bytes is larger than the 100 bytes left"))
+
+	SetResourcePressure(ResourcePressure{MemoryPercent: 85, MemoryHeadroom: 100})
+
+	_, deferred = DeferGitClone("ns/large")
+	g.Expect(deferred).To(BeFalse())
+
+	ForgetGitCloneSize("ns/large")
+	g.Expect(gitCloneSizes).NotTo(HaveKey("ns/large"))
+}
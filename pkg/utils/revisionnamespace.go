@@ -0,0 +1,74 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+// shortCommitLength is the length of the commit in the revision namespace names, as the short commits of git
+const shortCommitLength = 7
+
+// GetRevisionNamespacePrefix returns the revision-namespace annotation of the subscription, empty if the revisions
+// are not deployed into their own namespaces
+func GetRevisionNamespacePrefix(sub *appv1.Subscription) string {
+	if sub == nil {
+		return ""
+	}
+
+	return strings.TrimSpace(sub.GetAnnotations()[appv1.AnnotationRevisionNamespace])
+}
+
+// GetRevisionNamespace returns the <prefix>-<short commit> namespace of the commit of a subscription with the
+// revision-namespace annotation, empty if the annotation isn't set or the namespace name is invalid
+func GetRevisionNamespace(sub *appv1.Subscription, commit string) string {
+	prefix := GetRevisionNamespacePrefix(sub)
+	if prefix == "" || commit == "" {
+		return ""
+	}
+
+	if len(commit) > shortCommitLength {
+		commit = commit[:shortCommitLength]
+	}
+
+	ns := strings.ToLower(prefix + "-" + commit)
+
+	if errs := validation.IsDNS1123Label(ns); len(errs) > 0 {
+		klog.Warningf("invalid revision namespace %v of appsub %v/%v, err: %v", ns, sub.GetNamespace(), sub.GetName(),
+			strings.Join(errs, ", "))
+
+		return ""
+	}
+
+	return ns
+}
+
+// IsPreviousRevisionNamespace checks if the namespace is the namespace of another revision of the subscription
+func IsPreviousRevisionNamespace(sub *appv1.Subscription, ns string) bool {
+	prefix := GetRevisionNamespacePrefix(sub)
+	if prefix == "" {
+		return false
+	}
+
+	current := GetRevisionNamespace(sub, sub.GetAnnotations()[appv1.AnnotationGitCommit])
+	commit := strings.TrimPrefix(ns, strings.ToLower(prefix)+"-")
+
+	return ns != current && commit != ns && len(commit) == shortCommitLength && strings.Trim(commit, "0123456789abcdef") == ""
+}
@@ -0,0 +1,48 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+func TestRevisionNamespace(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	sub := &appv1.Subscription{}
+	g.Expect(GetRevisionNamespace(sub, "3a5f8c2d9e")).To(gomega.BeEmpty())
+	g.Expect(IsPreviousRevisionNamespace(sub, "app-1b2c3d4")).To(gomega.BeFalse())
+
+	sub.SetAnnotations(map[string]string{
+		appv1.AnnotationRevisionNamespace: "App",
+		appv1.AnnotationGitCommit:         "3a5f8c2d9e",
+	})
+	g.Expect(GetRevisionNamespace(sub, "3a5f8c2d9e")).To(gomega.Equal("app-3a5f8c2"))
+	g.Expect(GetRevisionNamespace(sub, "")).To(gomega.BeEmpty())
+
+	// the namespaces of the other commits are the previous revision namespaces
+	g.Expect(IsPreviousRevisionNamespace(sub, "app-1b2c3d4")).To(gomega.BeTrue())
+	g.Expect(IsPreviousRevisionNamespace(sub, "app-3a5f8c2")).To(gomega.BeFalse())
+	g.Expect(IsPreviousRevisionNamespace(sub, "app-config")).To(gomega.BeFalse())
+	g.Expect(IsPreviousRevisionNamespace(sub, "app-configs")).To(gomega.BeFalse())
+	g.Expect(IsPreviousRevisionNamespace(sub, "default")).To(gomega.BeFalse())
+
+	sub.SetAnnotations(map[string]string{appv1.AnnotationRevisionNamespace: "invalid_prefix"})
+	g.Expect(GetRevisionNamespace(sub, "3a5f8c2d9e")).To(gomega.BeEmpty())
+}
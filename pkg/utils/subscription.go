@@ -41,6 +41,7 @@ import (
 	clientsetx "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	"k8s.io/apimachinery/pkg/api/equality"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
@@ -68,6 +69,8 @@ const (
 	// klusterletagentaddon secret token reconcile
 	addonServiceAccountName      = "application-manager"
 	addonServiceAccountNamespace = "open-cluster-management-agent-addon"
+	// HubCluster is the cluster of the hub in the reports of the subscriptions with the hub placement
+	HubCluster = "local-cluster"
 )
 
 // PlacementDecisionPredicateFunctions filters PlacementDecision status decisions update
@@ -178,6 +181,12 @@ func IsSubscriptionBasicChanged(o, n *appv1.Subscription) bool {
 		return true
 	}
 
+	// the owners change when a hub stops propagating the subscription to a cluster syncing to several hubs, the claims
+	// of the other hubs are released
+	if !reflect.DeepEqual(fOsub.GetOwnerReferences(), fNSub.GetOwnerReferences()) {
+		return true
+	}
+
 	// we care label change, pass it down
 	if !reflect.DeepEqual(fOsub.GetLabels(), fNSub.GetLabels()) {
 		return true
@@ -326,6 +335,27 @@ func IsHubRelatedStatusChanged(old, nnew *appv1.SubscriptionStatus) bool {
 		return true
 	}
 
+	if !reflect.DeepEqual(old.RollupSummary, nnew.RollupSummary) {
+		return true
+	}
+
+	if !reflect.DeepEqual(old.ResolvedVersions, nnew.ResolvedVersions) {
+		return true
+	}
+
+	if !reflect.DeepEqual(old.Promotion, nnew.Promotion) {
+		return true
+	}
+
+	// the quota, permission and values schema conditions are set by the hub
+	for _, conditionType := range []string{appv1.ConditionQuotaExceeded, appv1.ConditionPermissionDenied,
+		appv1.ConditionValuesSchemaInvalid} {
+		if !reflect.DeepEqual(meta.FindStatusCondition(old.Conditions, conditionType),
+			meta.FindStatusCondition(nnew.Conditions, conditionType)) {
+			return true
+		}
+	}
+
 	return false
 }
 
@@ -396,6 +426,38 @@ var ServiceAccountPredicateFunctions = predicate.Funcs{
 	},
 }
 
+// ValidatePlacement checks the placement of a hub subscription, the error is the reason of the propagation failure
+func ValidatePlacement(sub *appv1.Subscription) error {
+	pl := sub.Spec.Placement
+
+	if pl == nil {
+		return errors.New("Placement must be specified")
+	}
+
+	if (pl.PlacementRef != nil || pl.Clusters != nil || pl.ClusterSelector != nil) && (pl.Local != nil && *pl.Local) {
+		return errors.New("local placement and remote placement cannot be used together")
+	}
+
+	if pl.Hub != nil && *pl.Hub && ((pl.Local != nil && *pl.Local) || pl.PlacementRef != nil || pl.Clusters != nil ||
+		pl.ClusterSelector != nil) {
+		return errors.New("hub placement cannot be used with local placement or remote placement")
+	}
+
+	return nil
+}
+
+// IsHubPlacement checks if the subscription is deployed to the hub cluster itself by the hub placement
+func IsHubPlacement(sub *appv1.Subscription) bool {
+	pl := sub.Spec.Placement
+
+	return pl != nil && pl.Hub != nil && *pl.Hub
+}
+
+// IsHubLocalAppsub checks if the appsub is created on the hub by a subscription with the hub placement
+func IsHubLocalAppsub(appsub *appv1.Subscription) bool {
+	return IsHostingAppsub(appsub) && strings.EqualFold(appsub.GetAnnotations()[appv1.AnnotationHubLocal], "true")
+}
+
 // GetHostSubscriptionFromObject extract the namespacedname of subscription hosting the object resource
 func GetHostSubscriptionFromObject(obj metav1.Object) *types.NamespacedName {
 	if obj == nil {
@@ -608,6 +670,84 @@ func UpdateLastUpdateTime(clt client.Client, instance *appv1.Subscription) {
 	}
 }
 
+// UpdateFetchStatus sets the last fetch time, the next reconcile time and the revision resolved from the source of
+// the given channel type in the appsub status, also in the given instance for the synchronizer to report them in the
+// appsubstatus. The next reconcile is the reconcile interval later, or when the timewindow opens next, it is not set
+// if the reconcile rate is off
+func UpdateFetchStatus(clt client.Client, instance *appv1.Subscription, sourceType, revision, reconcileRate string) {
+	updateFetchStatus(clt, instance, sourceType, revision, "", reconcileRate)
+}
+
+// UpdateGitFetchStatus sets the fetch status of a git subscription like UpdateFetchStatus, with the URL of the git
+// remote the commit was cloned from
+func UpdateGitFetchStatus(clt client.Client, instance *appv1.Subscription, commitID, remote, reconcileRate string) {
+	updateFetchStatus(clt, instance, chnv1.ChannelTypeGit, commitID, remote, reconcileRate)
+}
+
+func updateFetchStatus(clt client.Client, instance *appv1.Subscription, sourceType, revision, remote, reconcileRate string) {
+	now := time.Now()
+	fetchTime := metav1.NewTime(now)
+
+	var nextReconcileTime *metav1.Time
+
+	if !strings.EqualFold(reconcileRate, "off") {
+		loopPeriod, _, _ := GetReconcileInterval(reconcileRate, sourceType)
+		next := now.Add(loopPeriod)
+		if tw := instance.Spec.TimeWindow; tw != nil {
+			next = next.Add(NextStartPoint(tw, next))
+		}
+
+		nextReconcileTime = &metav1.Time{Time: next}
+	}
+
+	setFetchStatus := func(status *appv1.SubscriptionStatus) {
+		status.LastFetchTime = &fetchTime
+		status.NextReconcileTime = nextReconcileTime
+
+		if revision != "" {
+			if status.Revisions == nil {
+				status.Revisions = map[string]string{}
+			}
+
+			status.Revisions[sourceType] = revision
+		}
+
+		if remote != "" {
+			status.GitRemote = remote
+		}
+	}
+
+	setFetchStatus(&instance.Status)
+
+	curSub := &appv1.Subscription{}
+	if err := clt.Get(context.TODO(), types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}, curSub); err != nil {
+		klog.Warning("Failed to get appsub to update LastFetchTime", err)
+		return
+	}
+
+	setFetchStatus(&curSub.Status)
+
+	if err := clt.Status().Update(context.TODO(), curSub); err != nil {
+		klog.Warning("Failed to update LastFetchTime", err)
+	}
+}
+
+// UpdateLastAppliedTime sets the last applied time in the appsub status
+func UpdateLastAppliedTime(clt client.Client, instance *appv1.Subscription) {
+	curSub := &appv1.Subscription{}
+	if err := clt.Get(context.TODO(), types.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}, curSub); err != nil {
+		klog.Warning("Failed to get appsub to update LastAppliedTime", err)
+		return
+	}
+
+	now := metav1.Now()
+	curSub.Status.LastAppliedTime = &now
+
+	if err := clt.Status().Update(context.TODO(), curSub); err != nil {
+		klog.Warning("Failed to update LastAppliedTime", err)
+	}
+}
+
 // OverrideResourceBySubscription alter the given template with overrides
 func OverrideResourceBySubscription(template *unstructured.Unstructured,
 	pkgName string, instance *appv1.Subscription) (*unstructured.Unstructured, error) {
@@ -679,6 +819,19 @@ func GetReleaseName(base string) (string, error) {
 	return base, nil
 }
 
+// GetSyncTime returns the time of the last manual reconcile of the subscription, followed by the time of the last
+// retry of the cluster by the hub. The subscribers reconcile the resources again when it changes
+func GetSyncTime(instance *appv1.Subscription) string {
+	annotations := instance.GetAnnotations()
+
+	syncTime := annotations[appv1.AnnotationManualReconcileTime]
+	if retryTime := annotations[appv1.AnnotationRetryTime]; retryTime != "" {
+		syncTime += ",retry=" + retryTime
+	}
+
+	return syncTime
+}
+
 // GetPauseLabel check if the subscription-pause label exists
 func GetPauseLabel(instance *appv1.Subscription) bool {
 	labels := instance.GetLabels()
@@ -1001,9 +1154,39 @@ func GetReconcileInterval(reconcileRate, chType string) (time.Duration, time.Dur
 		retryCount = 1
 	}
 
+	// the controller config overrides the interval of the rate for all the channel types
+	if override := GetControllerConfig().ReconcileIntervals[strings.ToLower(reconcileRate)]; override > 0 {
+		interval = override
+	}
+
 	return interval, retryInterval, retryCount
 }
 
+// UntilReconcileInterval calls f every reconcile interval of the reconcile rate and the channel type until stopCh is
+// closed. The interval is read again after each call, so a change of the controller config applies from the next call
+func UntilReconcileInterval(f func(), reconcileRate, chType string, stopCh <-chan struct{}) {
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+		}
+
+		f()
+
+		interval, _, _ := GetReconcileInterval(reconcileRate, chType)
+		timer := time.NewTimer(interval)
+
+		select {
+		case <-stopCh:
+			timer.Stop()
+
+			return
+		case <-timer.C:
+		}
+	}
+}
+
 func SetPartOfLabel(s *appv1.Subscription, rsc *unstructured.Unstructured) {
 	rscLbls := AddPartOfLabel(s, rsc.GetLabels())
 	if rscLbls != nil {
@@ -1026,6 +1209,11 @@ func AddPartOfLabel(s *appv1.Subscription, m map[string]string) map[string]strin
 
 // CompareManifestWork compare two manifestWorks and return true if they are equal.
 func CompareManifestWork(oldManifestWork, newManifestWork *manifestWorkV1.ManifestWork) bool {
+	if !reflect.DeepEqual(oldManifestWork.Spec.ManifestConfigs, newManifestWork.Spec.ManifestConfigs) {
+		klog.V(1).Infof("manifestWork manifestConfigs changed")
+		return false
+	}
+
 	if len(oldManifestWork.Spec.Workload.Manifests) != len(newManifestWork.Spec.Workload.Manifests) {
 		klog.V(1).Infof("oldManifestWork length: %v, newManifestWork length: %v",
 			len(oldManifestWork.Spec.Workload.Manifests), len(newManifestWork.Spec.Workload.Manifests))
@@ -1228,6 +1416,126 @@ func IsReadyPlacementDecision(clReader client.Reader) bool {
 	return true
 }
 
+// IsReadySubscriptionQuota checks if the SubscriptionQuota API is installed on the hub
+func IsReadySubscriptionQuota(clReader client.Reader) bool {
+	quotaList := &appsubReportV1alpha1.SubscriptionQuotaList{}
+
+	if err := clReader.List(context.TODO(), quotaList, &client.ListOptions{}); err != nil {
+		klog.Error("Subscription Quota API NOT ready: ", err)
+
+		return false
+	}
+
+	klog.Info("Subscription Quota API is ready")
+
+	return true
+}
+
+// IsReadyApprovalRequest checks if the ApprovalRequest API is installed on the hub
+func IsReadyApprovalRequest(clReader client.Reader) bool {
+	requestList := &appsubReportV1alpha1.ApprovalRequestList{}
+
+	if err := clReader.List(context.TODO(), requestList, &client.ListOptions{}); err != nil {
+		klog.Error("Approval Request API NOT ready: ", err)
+
+		return false
+	}
+
+	klog.Info("Approval Request API is ready")
+
+	return true
+}
+
+// IsReadyChangeFreeze checks if the ChangeFreeze API is installed on the hub
+func IsReadyChangeFreeze(clReader client.Reader) bool {
+	freezeList := &appsubReportV1alpha1.ChangeFreezeList{}
+
+	if err := clReader.List(context.TODO(), freezeList, &client.ListOptions{}); err != nil {
+		klog.Error("Change Freeze API NOT ready: ", err)
+
+		return false
+	}
+
+	klog.Info("Change Freeze API is ready")
+
+	return true
+}
+
+// IsReadySubscriptionTelemetry checks if the SubscriptionTelemetry API is installed on the hub
+func IsReadySubscriptionTelemetry(clReader client.Reader) bool {
+	telemetryList := &appsubReportV1alpha1.SubscriptionTelemetryList{}
+
+	if err := clReader.List(context.TODO(), telemetryList, &client.ListOptions{}); err != nil {
+		klog.Error("Subscription Telemetry API NOT ready: ", err)
+
+		return false
+	}
+
+	klog.V(1).Info("Subscription Telemetry API is ready")
+
+	return true
+}
+
+// IsReadyAgentUpgrade checks if the AgentUpgrade API is installed on the hub
+func IsReadyAgentUpgrade(clReader client.Reader) bool {
+	upgradeList := &appsubReportV1alpha1.AgentUpgradeList{}
+
+	if err := clReader.List(context.TODO(), upgradeList, &client.ListOptions{}); err != nil {
+		klog.Error("Agent Upgrade API NOT ready: ", err)
+
+		return false
+	}
+
+	klog.Info("Agent Upgrade API is ready")
+
+	return true
+}
+
+// IsReadySubscriptionQuery checks if the SubscriptionQuery API is installed on the hub
+func IsReadySubscriptionQuery(clReader client.Reader) bool {
+	queryList := &appsubReportV1alpha1.SubscriptionQueryList{}
+
+	if err := clReader.List(context.TODO(), queryList, &client.ListOptions{}); err != nil {
+		klog.Error("Subscription Query API NOT ready: ", err)
+
+		return false
+	}
+
+	klog.V(1).Info("Subscription Query API is ready")
+
+	return true
+}
+
+// IsReadySubscriptionWebhook checks if the SubscriptionWebhook API is installed on the hub
+func IsReadySubscriptionWebhook(clReader client.Reader) bool {
+	webhookList := &appsubReportV1alpha1.SubscriptionWebhookList{}
+
+	if err := clReader.List(context.TODO(), webhookList, &client.ListOptions{}); err != nil {
+		klog.Error("Subscription Webhook API NOT ready: ", err)
+
+		return false
+	}
+
+	klog.V(1).Info("Subscription Webhook API is ready")
+
+	return true
+}
+
+// IsReadySubscriptionHistory checks if the SubscriptionHistory API is installed on the hub
+func IsReadySubscriptionHistory(clReader client.Reader) bool {
+	historyList := &appsubReportV1alpha1.SubscriptionHistoryList{}
+
+	if err := clReader.List(context.TODO(), historyList, &client.ListOptions{}); err != nil {
+		klog.Error("Subscription History API NOT ready: ", err)
+
+		return false
+	}
+
+	klog.V(1).Info("Subscription History API is ready")
+
+	return true
+}
+
 func CreateClusterManagementAddon(clt client.Client) {
 	cma := &addonV1alpha1.ClusterManagementAddOn{
 		ObjectMeta: metav1.ObjectMeta{
@@ -36,6 +36,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
@@ -761,6 +762,26 @@ func TestGetPauseLabel(t *testing.T) {
 	}
 }
 
+func TestGetSyncTime(t *testing.T) {
+	appsub := &appv1.Subscription{}
+
+	if syncTime := GetSyncTime(appsub); syncTime != "" {
+		t.Errorf("expected no sync time, actual %v", syncTime)
+	}
+
+	appsub.SetAnnotations(map[string]string{appv1.AnnotationManualReconcileTime: "2026-10-13T09:00:00Z"})
+
+	if syncTime := GetSyncTime(appsub); syncTime != "2026-10-13T09:00:00Z" {
+		t.Errorf("expected the manual reconcile time, actual %v", syncTime)
+	}
+
+	appsub.Annotations[appv1.AnnotationRetryTime] = "2026-10-13T10:00:00Z"
+
+	if syncTime := GetSyncTime(appsub); syncTime != "2026-10-13T09:00:00Z,retry=2026-10-13T10:00:00Z" {
+		t.Errorf("expected the manual reconcile and the retry times, actual %v", syncTime)
+	}
+}
+
 func TestRemoveSubAnnotations(t *testing.T) {
 	var tests = []struct {
 		name     string
@@ -1333,6 +1354,15 @@ func TestIsSubscriptionResourceChanged(t *testing.T) {
 	nSub.Status.Phase = "same"
 
 	g.Expect(IsSubscriptionResourceChanged(oSub, nSub)).To(BeFalse())
+
+	// an AppliedManifestWork owner is removed when a hub stops propagating the subscription
+	oSub.OwnerReferences = []metav1.OwnerReference{
+		{APIVersion: "work.open-cluster-management.io/v1", Kind: "AppliedManifestWork", Name: "hub1-work"},
+		{APIVersion: "work.open-cluster-management.io/v1", Kind: "AppliedManifestWork", Name: "hub2-work"},
+	}
+	nSub.OwnerReferences = oSub.OwnerReferences[1:]
+
+	g.Expect(IsSubscriptionResourceChanged(oSub, nSub)).To(BeTrue())
 }
 
 func TestIsHubRelatedStatusChanged(t *testing.T) {
@@ -1773,3 +1803,41 @@ func TestGetCheckSum(t *testing.T) {
 	_, err = GetCheckSum(tmpFile.Name())
 	g.Expect(err).ShouldNot(HaveOccurred())
 }
+
+func TestUpdateFetchStatus(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	testScheme := runtime.NewScheme()
+	g.Expect(appv1.SchemeBuilder.AddToScheme(testScheme)).To(Succeed())
+
+	sub := &appv1.Subscription{ObjectMeta: metav1.ObjectMeta{Name: "sub", Namespace: "ns"}}
+	c := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(sub.DeepCopy()).Build()
+
+	UpdateFetchStatus(c, sub, "git", "abc123", "medium")
+
+	curSub := &appv1.Subscription{}
+	g.Expect(c.Get(context.TODO(), types.NamespacedName{Name: "sub", Namespace: "ns"}, curSub)).To(Succeed())
+	g.Expect(curSub.Status.LastFetchTime).NotTo(BeNil())
+	g.Expect(curSub.Status.NextReconcileTime.Sub(curSub.Status.LastFetchTime.Time)).To(Equal(3 * time.Minute))
+	g.Expect(curSub.Status.Revisions).To(Equal(map[string]string{"git": "abc123"}))
+
+	// the instance carries the fetch status to the synchronizer
+	g.Expect(sub.Status.Revisions).To(Equal(map[string]string{"git": "abc123"}))
+
+	// no next reconcile while the reconcile rate is off
+	UpdateFetchStatus(c, sub, "git", "def456", "off")
+	g.Expect(c.Get(context.TODO(), types.NamespacedName{Name: "sub", Namespace: "ns"}, curSub)).To(Succeed())
+	g.Expect(curSub.Status.NextReconcileTime).To(BeNil())
+	g.Expect(curSub.Status.Revisions["git"]).To(Equal("def456"))
+
+	// the git remote the commit was cloned from is kept until the next git fetch
+	UpdateGitFetchStatus(c, sub, "fed789", "https://mirror.example.com/app.git", "medium")
+	g.Expect(c.Get(context.TODO(), types.NamespacedName{Name: "sub", Namespace: "ns"}, curSub)).To(Succeed())
+	g.Expect(curSub.Status.Revisions["git"]).To(Equal("fed789"))
+	g.Expect(curSub.Status.GitRemote).To(Equal("https://mirror.example.com/app.git"))
+
+	UpdateLastAppliedTime(c, sub)
+	g.Expect(c.Get(context.TODO(), types.NamespacedName{Name: "sub", Namespace: "ns"}, curSub)).To(Succeed())
+	g.Expect(curSub.Status.LastAppliedTime).NotTo(BeNil())
+	g.Expect(curSub.Status.LastFetchTime).NotTo(BeNil())
+}
@@ -0,0 +1,330 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"helm.sh/helm/v3/pkg/repo"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+)
+
+const (
+	// LockSuffix is the name suffix of the ConfigMap holding the lock of a subscription
+	LockSuffix = "-lock"
+	// LockInputsKey is the JSON of the resolved content of the locked inputs, by declared input
+	LockInputsKey = "inputs"
+	// LockUpdateTimeKey is the time an input was last added to the lock
+	LockUpdateTimeKey = "updateTime"
+)
+
+// subscriptionLockMutex serializes the checks of the locks, the subscriber items of the agent check them concurrently
+var subscriptionLockMutex sync.Mutex
+
+// resolveImageDigest is replaced in the tests, the images are resolved in their registries
+var resolveImageDigest = GetImageDigest
+
+// SubscriptionLock is the content the declared versions of a subscription resolved to the first time they were
+// deployed, it survives the agent restarts
+type SubscriptionLock struct {
+	// Inputs are the resolved content by declared input: the commit of a git tag, the digest of a chart version, the
+	// ETag of an object version and the digest of an image tag
+	Inputs map[string]string
+}
+
+// LockMismatchError is returned when some declared versions resolve to a different content than recorded in the lock
+type LockMismatchError struct {
+	// Mismatches name the mismatching inputs with their locked and resolved content
+	Mismatches []string
+}
+
+func (e *LockMismatchError) Error() string {
+	return "the content of the locked versions changed: " + strings.Join(e.Mismatches, "; ")
+}
+
+// LockGitTagInput is the input of the commit a git tag resolves to
+func LockGitTagInput(tag string) string {
+	return "git-tag:" + tag
+}
+
+// LockChartInput is the input of the digest of a chart version
+func LockChartInput(name, version string) string {
+	return "chart:" + name + "@" + version
+}
+
+// LockObjectInput is the input of the ETag of a version of an object of a bucket
+func LockObjectInput(key, version string) string {
+	return "object:" + key + "@" + version
+}
+
+// LockImageInput is the input of the digest an image tag resolves to
+func LockImageInput(image string) string {
+	return "image:" + image
+}
+
+// LockObjectContent returns the locked content of an object, its ETag or the sha256 of its content without ETag
+func LockObjectContent(etag string, content []byte) string {
+	if etag = strings.Trim(etag, `"`); etag != "" {
+		return etag
+	}
+
+	sum := sha256.Sum256(content)
+
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// IsSubscriptionLocked checks if the subscription opts in to lock the content of its declared versions
+func IsSubscriptionLocked(sub *appv1.Subscription) bool {
+	return strings.EqualFold(sub.GetAnnotations()[appv1.AnnotationContentLock], "true")
+}
+
+// AddImageLockInputs adds the digests of the container images of the object to the inputs. The images pinned by
+// digest aren't resolved, and the images failing to be resolved, e.g. in the private or unreachable registries, are
+// logged and left out
+func AddImageLockInputs(inputs map[string]string, obj map[string]interface{}) {
+	for _, image := range ContainerImages(obj) {
+		if strings.Contains(image, "@") {
+			continue
+		}
+
+		if _, ok := inputs[LockImageInput(image)]; ok {
+			continue
+		}
+
+		digest, err := resolveImageDigest(image)
+		if err != nil {
+			klog.Warningf("skip locking the digest of image %v, err: %v", image, err)
+
+			continue
+		}
+
+		inputs[LockImageInput(image)] = digest
+	}
+}
+
+// ChartLockInputs returns the digests in the index of the chart versions of the filtered indexFile, the chart versions
+// without digest aren't locked
+func ChartLockInputs(indexFile *repo.IndexFile) map[string]string {
+	inputs := map[string]string{}
+
+	if indexFile == nil {
+		return inputs
+	}
+
+	for name, chartVersions := range indexFile.Entries {
+		if len(chartVersions) > 0 && chartVersions[0] != nil && chartVersions[0].Digest != "" {
+			inputs[LockChartInput(name, chartVersions[0].Version)] = chartVersions[0].Digest
+		}
+	}
+
+	return inputs
+}
+
+// GetSubscriptionLock returns the lock of the subscription, an empty lock if there is none
+func GetSubscriptionLock(c client.Client, sub *appv1.Subscription) (*SubscriptionLock, error) {
+	lock := &SubscriptionLock{Inputs: map[string]string{}}
+	cm := &corev1.ConfigMap{}
+
+	err := c.Get(context.TODO(), types.NamespacedName{Name: sub.GetName() + LockSuffix, Namespace: sub.GetNamespace()}, cm)
+	if kerrors.IsNotFound(err) {
+		return lock, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if data := cm.Data[LockInputsKey]; data != "" {
+		if err := json.Unmarshal([]byte(data), &lock.Inputs); err != nil {
+			return nil, fmt.Errorf("invalid lock %v/%v, err: %w", cm.Namespace, cm.Name, err)
+		}
+	}
+
+	return lock, nil
+}
+
+// CheckSubscriptionLock checks the resolved content of the inputs against the lock of the subscription. The inputs
+// not locked yet are added to the lock, a LockMismatchError names the inputs resolving to a different content and
+// nothing is added then. The subscriptions without the content-lock annotation aren't checked
+func CheckSubscriptionLock(c client.Client, sub *appv1.Subscription, inputs map[string]string) error {
+	if !IsSubscriptionLocked(sub) || len(inputs) == 0 {
+		return nil
+	}
+
+	subscriptionLockMutex.Lock()
+	defer subscriptionLockMutex.Unlock()
+
+	lock, err := GetSubscriptionLock(c, sub)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(inputs))
+	for input := range inputs {
+		keys = append(keys, input)
+	}
+
+	sort.Strings(keys)
+
+	mismatches := []string{}
+	added := false
+
+	for _, input := range keys {
+		resolved := inputs[input]
+		if resolved == "" {
+			continue
+		}
+
+		locked, ok := lock.Inputs[input]
+		if !ok {
+			lock.Inputs[input] = resolved
+			added = true
+
+			continue
+		}
+
+		if locked != resolved {
+			mismatches = append(mismatches, fmt.Sprintf("%v resolved to %v, locked to %v", input, resolved, locked))
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return &LockMismatchError{Mismatches: mismatches}
+	}
+
+	if !added {
+		return nil
+	}
+
+	return saveSubscriptionLock(c, sub, lock)
+}
+
+// saveSubscriptionLock creates or updates the lock ConfigMap of the subscription, the ConfigMap is owned by the
+// subscription and is garbage collected with it
+func saveSubscriptionLock(c client.Client, sub *appv1.Subscription, lock *SubscriptionLock) error {
+	data, err := json.Marshal(lock.Inputs)
+	if err != nil {
+		return err
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            sub.GetName() + LockSuffix,
+			Namespace:       sub.GetNamespace(),
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(sub, appv1.SchemeGroupVersion.WithKind("Subscription"))},
+		},
+		Data: map[string]string{
+			LockInputsKey:     string(data),
+			LockUpdateTimeKey: time.Now().UTC().Format(time.RFC3339),
+		},
+	}
+
+	existing := &corev1.ConfigMap{}
+
+	err = c.Get(context.TODO(), types.NamespacedName{Name: cm.Name, Namespace: cm.Namespace}, existing)
+	if kerrors.IsNotFound(err) {
+		klog.Infof("creating the lock configmap %v/%v", cm.Namespace, cm.Name)
+
+		return c.Create(context.TODO(), cm)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	existing.Data = cm.Data
+	existing.OwnerReferences = cm.OwnerReferences
+
+	klog.Infof("updating the lock configmap %v/%v", cm.Namespace, cm.Name)
+
+	return c.Update(context.TODO(), existing)
+}
+
+// SetLockMismatchCondition sets the LockMismatch condition of the subscription from the result of its lock check, the
+// condition is removed once the content-lock annotation is removed. The condition isn't changed by the failed checks
+func SetLockMismatchCondition(c client.Client, sub *appv1.Subscription, lockErr error) {
+	locked := IsSubscriptionLocked(sub)
+	mismatchErr := &LockMismatchError{}
+
+	if lockErr != nil && !errors.As(lockErr, &mismatchErr) {
+		return
+	}
+
+	if !locked && meta.FindStatusCondition(sub.Status.Conditions, appv1.ConditionLockMismatch) == nil {
+		return
+	}
+
+	hostSub := types.NamespacedName{Namespace: sub.Namespace, Name: sub.Name}
+
+	latest := &appv1.Subscription{}
+	if err := c.Get(context.TODO(), hostSub, latest); err != nil {
+		klog.Warningf("failed to get appsub %v to set the %v condition, err: %v", hostSub.String(),
+			appv1.ConditionLockMismatch, err)
+
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:               appv1.ConditionLockMismatch,
+		Status:             metav1.ConditionFalse,
+		Reason:             appv1.ReasonResolvedContentLocked,
+		Message:            "the declared versions resolve to the locked content",
+		ObservedGeneration: latest.GetGeneration(),
+	}
+
+	if lockErr != nil {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = appv1.ReasonResolvedContentChanged
+		condition.Message = strings.Join(mismatchErr.Mismatches, "; ")
+	}
+
+	existing := meta.FindStatusCondition(latest.Status.Conditions, condition.Type)
+
+	switch {
+	case !locked && existing == nil:
+		return
+	case !locked:
+		meta.RemoveStatusCondition(&latest.Status.Conditions, condition.Type)
+	case existing != nil && existing.Status == condition.Status && existing.Message == condition.Message &&
+		existing.ObservedGeneration == condition.ObservedGeneration:
+		return
+	default:
+		meta.SetStatusCondition(&latest.Status.Conditions, condition)
+	}
+
+	// the conditions of the instance are kept for the next checks
+	sub.Status.Conditions = latest.Status.Conditions
+
+	if err := c.Status().Update(context.TODO(), latest); err != nil {
+		klog.Warningf("failed to set the %v condition of appsub %v, err: %v", condition.Type, hostSub.String(), err)
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/repo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	appv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/testharness"
+)
+
+func TestSubscriptionLock(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	digests := map[string]string{"nginx:1.25": "sha256:aaa"}
+
+	defer func() {
+		resolveImageDigest = GetImageDigest
+	}()
+
+	resolveImageDigest = func(image string) (string, error) {
+		if digest, ok := digests[image]; ok {
+			return digest, nil
+		}
+
+		return "", errors.New("not found")
+	}
+
+	sub := &appv1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "demo-ns", UID: "sub-uid"},
+	}
+
+	c, err := testharness.NewFakeClient(sub.DeepCopy())
+	g.Expect(err).NotTo(HaveOccurred())
+
+	lockKey := types.NamespacedName{Name: "demo" + LockSuffix, Namespace: "demo-ns"}
+	inputs := map[string]string{LockGitTagInput("v1.0.0"): "abc123"}
+
+	// the subscriptions without the annotation aren't locked
+	g.Expect(CheckSubscriptionLock(c, sub, inputs)).To(Succeed())
+	g.Expect(c.Get(context.TODO(), lockKey, &corev1.ConfigMap{})).NotTo(Succeed())
+
+	sub.Annotations = map[string]string{appv1.AnnotationContentLock: "true"}
+
+	// the images pinned by digest and the images failing to be resolved aren't locked
+	AddImageLockInputs(inputs, map[string]interface{}{
+		"kind": "Pod",
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"name": "web", "image": "nginx:1.25"},
+				map[string]interface{}{"name": "pinned", "image": "busybox@sha256:bbb"},
+				map[string]interface{}{"name": "private", "image": "registry.local/app:v1"},
+			},
+		},
+	})
+	g.Expect(inputs).To(Equal(map[string]string{
+		LockGitTagInput("v1.0.0"):    "abc123",
+		LockImageInput("nginx:1.25"): "sha256:aaa",
+	}))
+
+	// the first check records the inputs
+	g.Expect(CheckSubscriptionLock(c, sub, inputs)).To(Succeed())
+
+	lock, err := GetSubscriptionLock(c, sub)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(lock.Inputs).To(Equal(inputs))
+
+	cm := &corev1.ConfigMap{}
+	g.Expect(c.Get(context.TODO(), lockKey, cm)).To(Succeed())
+	g.Expect(cm.OwnerReferences).To(HaveLen(1))
+	g.Expect(cm.OwnerReferences[0].UID).To(Equal(types.UID("sub-uid")))
+
+	SetLockMismatchCondition(c, sub, nil)
+
+	cond := meta.FindStatusCondition(sub.Status.Conditions, appv1.ConditionLockMismatch)
+	g.Expect(cond).NotTo(BeNil())
+	g.Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+
+	// a moved tag is refused and the new inputs aren't recorded
+	err = CheckSubscriptionLock(c, sub, map[string]string{
+		LockGitTagInput("v1.0.0"): "def456",
+		LockGitTagInput("v1.1.0"): "fed789",
+	})
+
+	mismatchErr := &LockMismatchError{}
+	g.Expect(errors.As(err, &mismatchErr)).To(BeTrue())
+	g.Expect(mismatchErr.Mismatches).To(Equal([]string{"git-tag:v1.0.0 resolved to def456, locked to abc123"}))
+
+	lock, err = GetSubscriptionLock(c, sub)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(lock.Inputs).NotTo(HaveKey(LockGitTagInput("v1.1.0")))
+
+	SetLockMismatchCondition(c, sub, mismatchErr)
+
+	latest := &appv1.Subscription{}
+	g.Expect(c.Get(context.TODO(), types.NamespacedName{Name: "demo", Namespace: "demo-ns"}, latest)).To(Succeed())
+
+	cond = meta.FindStatusCondition(latest.Status.Conditions, appv1.ConditionLockMismatch)
+	g.Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+	g.Expect(cond.Reason).To(Equal(appv1.ReasonResolvedContentChanged))
+	g.Expect(cond.Message).To(Equal("git-tag:v1.0.0 resolved to def456, locked to abc123"))
+
+	// the failed checks don't change the condition
+	SetLockMismatchCondition(c, sub, errors.New("connection refused"))
+	g.Expect(meta.IsStatusConditionTrue(sub.Status.Conditions, appv1.ConditionLockMismatch)).To(BeTrue())
+
+	// the new versions are added to the lock
+	g.Expect(CheckSubscriptionLock(c, sub, map[string]string{LockGitTagInput("v1.1.0"): "fed789"})).To(Succeed())
+
+	lock, err = GetSubscriptionLock(c, sub)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(lock.Inputs).To(HaveKeyWithValue(LockGitTagInput("v1.1.0"), "fed789"))
+	g.Expect(lock.Inputs).To(HaveKeyWithValue(LockGitTagInput("v1.0.0"), "abc123"))
+
+	// the condition is removed with the annotation
+	sub.Annotations = nil
+
+	SetLockMismatchCondition(c, sub, nil)
+	g.Expect(c.Get(context.TODO(), types.NamespacedName{Name: "demo", Namespace: "demo-ns"}, latest)).To(Succeed())
+	g.Expect(meta.FindStatusCondition(latest.Status.Conditions, appv1.ConditionLockMismatch)).To(BeNil())
+
+	// the charts are locked by the digest of their version in the index, the objects by their ETag
+	indexFile := &repo.IndexFile{Entries: map[string]repo.ChartVersions{
+		"nginx": {{Metadata: &chart.Metadata{Name: "nginx", Version: "1.2.3"}, Digest: "sha256:ccc"}},
+		"redis": {{Metadata: &chart.Metadata{Name: "redis", Version: "7.0.0"}}},
+	}}
+
+	g.Expect(ChartLockInputs(indexFile)).To(Equal(map[string]string{LockChartInput("nginx", "1.2.3"): "sha256:ccc"}))
+	g.Expect(LockObjectContent(`"etag-1"`, []byte("data"))).To(Equal("etag-1"))
+	g.Expect(LockObjectContent("", []byte("data"))).To(HavePrefix("sha256:"))
+}
@@ -0,0 +1,175 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// SubscriptionWebhookSignatureHeader is the header with the HMAC SHA256 signature of the posted documents
+const SubscriptionWebhookSignatureHeader = "X-Subscription-Signature"
+
+// SubscriptionWebhookEvent is the document posted to a SubscriptionWebhook endpoint when the status of a
+// subscription changes on a cluster
+type SubscriptionWebhookEvent struct {
+	Webhook          string `json:"webhook"`
+	Subscription     string `json:"subscription"`
+	Namespace        string `json:"namespace"`
+	Cluster          string `json:"cluster"`
+	Revision         string `json:"revision,omitempty"`
+	Result           string `json:"result"`
+	PreviousRevision string `json:"previousRevision,omitempty"`
+	PreviousResult   string `json:"previousResult,omitempty"`
+	Timestamp        string `json:"timestamp"`
+}
+
+// SubscriptionWebhookAuth are the credentials of a SubscriptionWebhook endpoint from the webhook secret
+type SubscriptionWebhookAuth struct {
+	Token    string
+	User     string
+	Password string
+	HMACKey  string
+}
+
+// ValidateSubscriptionWebhookURL checks the endpoint URL of a SubscriptionWebhook. The URL must be https and, unless the
+// internal endpoints are allowed, must not target a loopback, link-local, private or cluster service address
+func ValidateSubscriptionWebhookURL(rawURL string, allowInternal bool) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL %v, err: %w", rawURL, err)
+	}
+
+	if !strings.EqualFold(u.Scheme, "https") {
+		return fmt.Errorf("webhook URL %v must use https", rawURL)
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "" {
+		return fmt.Errorf("webhook URL %v has no host", rawURL)
+	}
+
+	if allowInternal {
+		return nil
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		if isInternalWebhookIP(ip) {
+			return fmt.Errorf("webhook URL %v targets the internal address %v", rawURL, ip)
+		}
+
+		return nil
+	}
+
+	// the single label names and the service names are resolved by the cluster DNS
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || !strings.Contains(host, ".") ||
+		strings.HasSuffix(host, ".svc") || strings.Contains(host, ".svc.") || strings.HasSuffix(host, ".local") {
+		return fmt.Errorf("webhook URL %v targets the internal host %v", rawURL, host)
+	}
+
+	return nil
+}
+
+// isInternalWebhookIP checks if the address is a loopback, link-local, private or unspecified address
+func isInternalWebhookIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsPrivate() || ip.IsUnspecified()
+}
+
+// webhookDialControl rejects the connections to the internal addresses, the host names of the URLs are checked again
+// once resolved
+func webhookDialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	if ip := net.ParseIP(host); ip == nil || isInternalWebhookIP(ip) {
+		return fmt.Errorf("webhook endpoint %v is an internal address", address)
+	}
+
+	return nil
+}
+
+// Post posts the event to the endpoint URL, the endpoint must answer with a 2xx status. The connections to the
+// internal addresses are rejected unless allowInternal, except through a proxy
+func (e *SubscriptionWebhookEvent) Post(url string, auth SubscriptionWebhookAuth, insecureSkipVerify,
+	allowInternal bool) error {
+	if err := ValidateSubscriptionWebhookURL(url, allowInternal); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if auth.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+auth.Token)
+	} else if auth.User != "" {
+		req.SetBasicAuth(auth.User, auth.Password)
+	}
+
+	if auth.HMACKey != "" {
+		mac := hmac.New(sha256.New, []byte(auth.HMACKey))
+		mac.Write(body)
+
+		req.Header.Set(SubscriptionWebhookSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	httpClient := gitAPIHTTPClient(insecureSkipVerify)
+
+	if proxyURL, err := http.ProxyFromEnvironment(req); err == nil && proxyURL == nil && !allowInternal {
+		if transport, ok := httpClient.Transport.(*http.Transport); ok {
+			dialer := &net.Dialer{Timeout: 30 * time.Second, Control: webhookDialControl}
+			transport.DialContext = dialer.DialContext
+		}
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("failed to post to webhook %v, status: %v", url, resp.Status)
+	}
+
+	klog.V(1).Infof("webhook event posted, %v %v/%v on cluster %v: %v", url, e.Namespace, e.Subscription, e.Cluster,
+		e.Result)
+
+	return nil
+}
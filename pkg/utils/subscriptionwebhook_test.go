@@ -0,0 +1,48 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestValidateSubscriptionWebhookURL(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(ValidateSubscriptionWebhookURL("https://cmdb.example.com/hooks/appsub", false)).To(Succeed())
+	g.Expect(ValidateSubscriptionWebhookURL("https://203.0.113.10:8443/hooks", false)).To(Succeed())
+
+	for _, url := range []string{
+		"http://cmdb.example.com/hooks",
+		"https://127.0.0.1/hooks",
+		"https://[::1]/hooks",
+		"https://169.254.169.254/latest/meta-data",
+		"https://10.96.0.1/hooks",
+		"https://localhost:8443/hooks",
+		"https://cmdb/hooks",
+		"https://cmdb.app-ns.svc/hooks",
+		"https://cmdb.app-ns.svc.cluster.local/hooks",
+		"https:///hooks",
+	} {
+		g.Expect(ValidateSubscriptionWebhookURL(url, false)).NotTo(Succeed(), url)
+	}
+
+	// the internal endpoints can be allowed, https is always required
+	g.Expect(ValidateSubscriptionWebhookURL("https://cmdb.app-ns.svc/hooks", true)).To(Succeed())
+	g.Expect(ValidateSubscriptionWebhookURL("https://10.96.0.1/hooks", true)).To(Succeed())
+	g.Expect(ValidateSubscriptionWebhookURL("http://cmdb.app-ns.svc/hooks", true)).NotTo(Succeed())
+}
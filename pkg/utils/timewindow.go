@@ -15,6 +15,7 @@
 package utils
 
 import (
+	"fmt"
 	"sort"
 	"strings"
 	"time"
@@ -43,6 +44,42 @@ func IsInWindow(tw *appv1alpha1.TimeWindow, t time.Time) bool {
 	return NextStartPoint(tw, t) == 0
 }
 
+// ValidateTimeWindow checks the window type, the location, the days of the week and the hours of a timewindow. The
+// controllers don't reject an invalid timewindow, they ignore the invalid days and use the current time for the
+// invalid hours
+func ValidateTimeWindow(tw *appv1alpha1.TimeWindow) error {
+	if tw == nil {
+		return nil
+	}
+
+	switch strings.ToLower(tw.WindowType) {
+	case "", "active", "block", "blocked":
+	default:
+		return fmt.Errorf("unsupported windowtype %v, it must be active or blocked", tw.WindowType)
+	}
+
+	loc, err := time.LoadLocation(tw.Location)
+	if err != nil {
+		return fmt.Errorf("invalid location %v: %w", tw.Location, err)
+	}
+
+	for _, wd := range tw.Daysofweek {
+		if vwds, _ := validateDaysofweekSlice([]string{wd}); len(vwds) == 0 {
+			return fmt.Errorf("invalid day of the week %v", wd)
+		}
+	}
+
+	for _, hr := range tw.Hours {
+		for _, t := range []string{hr.Start, hr.End} {
+			if _, err := time.ParseInLocation(time.Kitchen, t, loc); err != nil {
+				return fmt.Errorf("invalid hour %q, the format must be %v", t, time.Kitchen)
+			}
+		}
+	}
+
+	return nil
+}
+
 // NextStatusReconcile generate a duartion for the reconcile to requeue after
 func NextStatusReconcile(tw *appv1alpha1.TimeWindow, t time.Time) time.Duration {
 	if tw == nil {
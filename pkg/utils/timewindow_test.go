@@ -555,6 +555,54 @@ func TestParseTimeWithKicthenFormat(t *testing.T) {
 	}
 }
 
+func TestValidateTimeWindow(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		windows *appv1alpha1.TimeWindow
+		valid   bool
+	}{
+		{
+			desc:  "no timewindow",
+			valid: true,
+		},
+		{
+			desc: "valid timewindow",
+			windows: &appv1alpha1.TimeWindow{
+				WindowType: "Blocked",
+				Location:   "America/Toronto",
+				Daysofweek: []string{"Monday", "friday"},
+				Hours:      []appv1alpha1.HourRange{{Start: "10:30AM", End: "12:00AM"}},
+			},
+			valid: true,
+		},
+		{
+			desc:    "invalid window type",
+			windows: &appv1alpha1.TimeWindow{WindowType: "paused"},
+		},
+		{
+			desc:    "invalid location",
+			windows: &appv1alpha1.TimeWindow{Location: "Mars/Olympus"},
+		},
+		{
+			desc:    "invalid day",
+			windows: &appv1alpha1.TimeWindow{Daysofweek: []string{"Mon"}},
+		},
+		{
+			desc:    "invalid hour",
+			windows: &appv1alpha1.TimeWindow{Hours: []appv1alpha1.HourRange{{Start: "10:30am", End: "11:00AM"}}},
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			err := ValidateTimeWindow(tC.windows)
+			if (err == nil) != tC.valid {
+				t.Errorf("validation error %v, wanted valid %v", err, tC.valid)
+			}
+		})
+	}
+}
+
 func getTime(t string) time.Time {
 	tt, _ := time.Parse(time.UnixDate, t)
 	return tt
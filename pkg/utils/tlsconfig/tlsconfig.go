@@ -0,0 +1,243 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tlsconfig holds the TLS settings of the listeners and the outbound connections of the operators, they are
+// configured once by the command line flags before the controllers are set up.
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	pflag "github.com/spf13/pflag"
+	"k8s.io/client-go/rest"
+)
+
+// Options are the TLS command line flags
+type Options struct {
+	// MinVersion is the minimum TLS version, 1.2 or 1.3. The listeners and connections with a higher default minimum
+	// version keep their default.
+	MinVersion string
+	// CipherSuites are the allowed TLS 1.2 cipher suites, the TLS 1.3 cipher suites are not configurable
+	CipherSuites []string
+	// FIPSMode restricts the TLS settings to the FIPS approved ones, the binary must be built with the FIPS crypto module
+	FIPSMode bool
+}
+
+var (
+	lock         sync.RWMutex
+	minVersion   uint16
+	cipherSuites []uint16
+	fipsMode     bool
+)
+
+// fipsCipherSuites are the FIPS approved TLS 1.2 cipher suites
+var fipsCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+}
+
+// AddFlags adds the TLS flags to the flag set
+func (o *Options) AddFlags(flag *pflag.FlagSet) {
+	flag.StringVar(
+		&o.MinVersion,
+		"tls-min-version",
+		o.MinVersion,
+		"The minimum TLS version of the listeners and the outbound connections, 1.2 or 1.3. "+
+			"The listeners and connections requiring TLS 1.3 by default keep it.",
+	)
+
+	flag.StringSliceVar(
+		&o.CipherSuites,
+		"tls-cipher-suites",
+		o.CipherSuites,
+		"Comma-separated list of the allowed TLS 1.2 cipher suites, for example TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256. "+
+			"All the secure cipher suites of Go are allowed if empty.",
+	)
+
+	flag.BoolVar(
+		&o.FIPSMode,
+		"fips-mode",
+		o.FIPSMode,
+		"Restrict the TLS versions, cipher suites and curves to the FIPS approved ones. "+
+			"The binary must be built with the FIPS crypto module, see make build-fips.",
+	)
+}
+
+// Configure validates the TLS options and sets them for all the listeners and connections
+func Configure(o Options) error {
+	version, err := parseVersion(o.MinVersion)
+	if err != nil {
+		return err
+	}
+
+	suites, err := parseCipherSuites(o.CipherSuites, o.FIPSMode)
+	if err != nil {
+		return err
+	}
+
+	if o.FIPSMode {
+		if !fipsAvailable() {
+			return fmt.Errorf("the FIPS mode requires a binary built with the FIPS crypto module")
+		}
+
+		if len(suites) == 0 {
+			suites = fipsCipherSuites
+		}
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	minVersion = version
+	cipherSuites = suites
+	fipsMode = o.FIPSMode
+
+	return nil
+}
+
+// NewConfig returns a TLS config with the configured settings, the minimum version is at least the default one
+func NewConfig(defaultMinVersion uint16) *tls.Config {
+	/* #nosec G402 */
+	cfg := &tls.Config{MinVersion: defaultMinVersion}
+
+	Apply(cfg)
+
+	return cfg
+}
+
+// Apply sets the configured settings in the TLS config, the minimum version is only raised
+func Apply(cfg *tls.Config) {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	if cfg.MinVersion < minVersion {
+		cfg.MinVersion = minVersion
+	}
+
+	if len(cipherSuites) > 0 {
+		cfg.CipherSuites = append([]uint16{}, cipherSuites...)
+	}
+
+	if fipsMode {
+		cfg.CurvePreferences = []tls.CurveID{tls.CurveP256, tls.CurveP384}
+	}
+}
+
+// ApplyRestConfig sets the configured settings in the connections of the rest config to the Kubernetes API server. The
+// transport shared by the clients of the config is cloned, it is left untouched if no setting is configured
+func ApplyRestConfig(cfg *rest.Config) {
+	cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		t, ok := rt.(*http.Transport)
+		if !ok || !configured() {
+			return rt
+		}
+
+		t = t.Clone()
+
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = NewConfig(tls.VersionTLS12)
+		} else {
+			Apply(t.TLSClientConfig)
+		}
+
+		return t
+	})
+}
+
+func configured() bool {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	return minVersion != 0 || len(cipherSuites) > 0 || fipsMode
+}
+
+// MinVersionName returns the configured minimum version, at least the default one, in the 1.x format of the
+// controller-runtime webhook server
+func MinVersionName(defaultMinVersion uint16) string {
+	version := NewConfig(defaultMinVersion).MinVersion
+
+	if version == tls.VersionTLS13 {
+		return "1.3"
+	}
+
+	return "1.2"
+}
+
+// FIPSMode returns if the TLS settings are restricted to the FIPS approved ones
+func FIPSMode() bool {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	return fipsMode
+}
+
+func parseVersion(version string) (uint16, error) {
+	switch strings.TrimPrefix(strings.TrimSpace(version), "VersionTLS") {
+	case "":
+		return 0, nil
+	case "1.2", "12":
+		return tls.VersionTLS12, nil
+	case "1.3", "13":
+		return tls.VersionTLS13, nil
+	}
+
+	return 0, fmt.Errorf("unsupported TLS minimum version %v, it must be 1.2 or 1.3", version)
+}
+
+func parseCipherSuites(names []string, fips bool) ([]uint16, error) {
+	secure := map[string]uint16{}
+	for _, suite := range tls.CipherSuites() {
+		secure[suite.Name] = suite.ID
+	}
+
+	insecure := map[string]bool{}
+	for _, suite := range tls.InsecureCipherSuites() {
+		insecure[suite.Name] = true
+	}
+
+	approved := map[uint16]bool{}
+	for _, id := range fipsCipherSuites {
+		approved[id] = true
+	}
+
+	suites := []uint16{}
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		id, ok := secure[name]
+
+		switch {
+		case insecure[name]:
+			return nil, fmt.Errorf("the TLS cipher suite %v is insecure", name)
+		case !ok:
+			return nil, fmt.Errorf("unsupported TLS cipher suite %v", name)
+		case fips && !approved[id]:
+			return nil, fmt.Errorf("the TLS cipher suite %v is not FIPS approved", name)
+		}
+
+		suites = append(suites, id)
+	}
+
+	return suites, nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+
+	"github.com/onsi/gomega"
+	"k8s.io/client-go/rest"
+)
+
+func TestConfigure(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	defer func() {
+		g.Expect(Configure(Options{})).To(gomega.Succeed())
+	}()
+
+	// the default settings keep the minimum versions of the callers
+	g.Expect(Configure(Options{})).To(gomega.Succeed())
+	g.Expect(NewConfig(tls.VersionTLS12).MinVersion).To(gomega.Equal(uint16(tls.VersionTLS12)))
+	g.Expect(NewConfig(tls.VersionTLS12).CipherSuites).To(gomega.BeNil())
+	g.Expect(MinVersionName(tls.VersionTLS13)).To(gomega.Equal("1.3"))
+
+	g.Expect(Configure(Options{
+		MinVersion:   "1.3",
+		CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256"},
+	})).To(gomega.Succeed())
+
+	cfg := NewConfig(tls.VersionTLS12)
+	g.Expect(cfg.MinVersion).To(gomega.Equal(uint16(tls.VersionTLS13)))
+	g.Expect(cfg.CipherSuites).To(gomega.Equal([]uint16{
+		tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256}))
+	g.Expect(MinVersionName(tls.VersionTLS12)).To(gomega.Equal("1.3"))
+
+	// the minimum version is never lowered
+	g.Expect(Configure(Options{MinVersion: "1.2"})).To(gomega.Succeed())
+	g.Expect(NewConfig(tls.VersionTLS13).MinVersion).To(gomega.Equal(uint16(tls.VersionTLS13)))
+
+	g.Expect(Configure(Options{MinVersion: "1.1"})).NotTo(gomega.Succeed())
+	g.Expect(Configure(Options{CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}})).NotTo(gomega.Succeed())
+	g.Expect(Configure(Options{CipherSuites: []string{"TLS_UNKNOWN"}})).NotTo(gomega.Succeed())
+
+	if !fipsAvailable() {
+		g.Expect(Configure(Options{FIPSMode: true})).NotTo(gomega.Succeed())
+
+		return
+	}
+
+	g.Expect(Configure(Options{CipherSuites: []string{"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256"}, FIPSMode: true})).NotTo(gomega.Succeed())
+	g.Expect(Configure(Options{FIPSMode: true})).To(gomega.Succeed())
+	g.Expect(NewConfig(tls.VersionTLS12).CipherSuites).To(gomega.Equal(fipsCipherSuites))
+	g.Expect(FIPSMode()).To(gomega.BeTrue())
+}
+
+func TestApplyRestConfig(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	defer func() {
+		g.Expect(Configure(Options{})).To(gomega.Succeed())
+	}()
+
+	base := &http.Transport{TLSClientConfig: &tls.Config{MinVersion: tls.VersionTLS12, ServerName: "hub"}} // #nosec G402
+
+	cfg := &rest.Config{}
+	ApplyRestConfig(cfg)
+
+	// the transport is kept without TLS settings
+	g.Expect(Configure(Options{})).To(gomega.Succeed())
+	g.Expect(cfg.WrapTransport(base)).To(gomega.BeIdenticalTo(base))
+
+	g.Expect(Configure(Options{
+		MinVersion:   "1.3",
+		CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+	})).To(gomega.Succeed())
+
+	wrapped, ok := cfg.WrapTransport(base).(*http.Transport)
+	g.Expect(ok).To(gomega.BeTrue())
+	g.Expect(wrapped).NotTo(gomega.BeIdenticalTo(base))
+	g.Expect(wrapped.TLSClientConfig.MinVersion).To(gomega.Equal(uint16(tls.VersionTLS13)))
+	g.Expect(wrapped.TLSClientConfig.CipherSuites).To(gomega.Equal([]uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}))
+	g.Expect(wrapped.TLSClientConfig.ServerName).To(gomega.Equal("hub"))
+
+	// the shared transport is not changed
+	g.Expect(base.TLSClientConfig.MinVersion).To(gomega.Equal(uint16(tls.VersionTLS12)))
+
+	wrapped, ok = cfg.WrapTransport(&http.Transport{}).(*http.Transport)
+	g.Expect(ok).To(gomega.BeTrue())
+	g.Expect(wrapped.TLSClientConfig.MinVersion).To(gomega.Equal(uint16(tls.VersionTLS13)))
+}
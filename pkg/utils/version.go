@@ -0,0 +1,30 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+// UnknownVersion is the version of the binaries built without the version stamp, e.g. by go build or go test
+const UnknownVersion = "unknown"
+
+// buildVersion is stamped by common/scripts/gobuild.sh from the COMPONENT_VERSION file
+var buildVersion = ""
+
+// Version returns the version of the binary
+func Version() string {
+	if buildVersion == "" {
+		return UnknownVersion
+	}
+
+	return buildVersion
+}
@@ -0,0 +1,66 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+
+	semver "github.com/Masterminds/semver/v3"
+)
+
+// MaxAgentMinorVersionSkew is how many minor versions the agents can lag behind the hub controllers. The agents of
+// another major version and the agents newer than the hub are not supported
+const MaxAgentMinorVersionSkew = 2
+
+// CheckAgentVersionSkew returns an error if the agent version is not supported by the hub version. The unknown or
+// invalid versions, e.g. of the development builds, are not checked
+func CheckAgentVersionSkew(hubVersion, agentVersion string) error {
+	hub, err := semver.NewVersion(hubVersion)
+	if err != nil {
+		return nil
+	}
+
+	agent, err := semver.NewVersion(agentVersion)
+	if err != nil {
+		return nil
+	}
+
+	switch {
+	case agent.Major() != hub.Major():
+		return fmt.Errorf("agent %v and hub %v have different major versions", agentVersion, hubVersion)
+	case agent.Minor() > hub.Minor():
+		return fmt.Errorf("agent %v is newer than hub %v", agentVersion, hubVersion)
+	case hub.Minor()-agent.Minor() > MaxAgentMinorVersionSkew:
+		return fmt.Errorf("agent %v is more than %v minor versions older than hub %v", agentVersion,
+			MaxAgentMinorVersionSkew, hubVersion)
+	}
+
+	return nil
+}
+
+// IsAgentVersionOlder checks if the agent version is older than the version, the unknown or invalid versions are not
+func IsAgentVersionOlder(agentVersion, version string) bool {
+	agent, err := semver.NewVersion(agentVersion)
+	if err != nil {
+		return false
+	}
+
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return false
+	}
+
+	return agent.LessThan(v)
+}
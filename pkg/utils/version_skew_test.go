@@ -0,0 +1,39 @@
+// Copyright 2021 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestCheckAgentVersionSkew(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(CheckAgentVersionSkew("2.8.0", "2.8.1")).To(Succeed())
+	g.Expect(CheckAgentVersionSkew("2.8.0", "2.6.0")).To(Succeed())
+	g.Expect(CheckAgentVersionSkew("2.8.0", "2.5.3")).NotTo(Succeed())
+	g.Expect(CheckAgentVersionSkew("2.8.0", "2.9.0")).NotTo(Succeed())
+	g.Expect(CheckAgentVersionSkew("2.8.0", "1.8.0")).NotTo(Succeed())
+
+	// the development builds are not checked
+	g.Expect(CheckAgentVersionSkew("2.8.0", UnknownVersion)).To(Succeed())
+	g.Expect(CheckAgentVersionSkew(UnknownVersion, "1.0.0")).To(Succeed())
+
+	g.Expect(IsAgentVersionOlder("2.7.2", "2.8.0")).To(BeTrue())
+	g.Expect(IsAgentVersionOlder("2.8.0", "2.8.0")).To(BeFalse())
+	g.Expect(IsAgentVersionOlder(UnknownVersion, "2.8.0")).To(BeFalse())
+}
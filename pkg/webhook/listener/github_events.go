@@ -64,7 +64,7 @@ func (listener *WebhookListener) handleGithubWebhook(r *http.Request) error {
 		return err
 	}
 
-	if strings.EqualFold(eventType, "push") || strings.EqualFold(eventType, "pull") {
+	if strings.EqualFold(eventType, "push") || strings.EqualFold(eventType, "pull") || strings.EqualFold(eventType, "pull_request") {
 		// Loop through all subscriptions
 		for _, sub := range subList.Items {
 			if !listener.processSubscription(sub, event, signature, eventType, body) {
@@ -115,6 +115,11 @@ func (listener *WebhookListener) processSubscription(sub appv1alpha1.Subscriptio
 			chobj.Spec.Pathname == e.GetRepo().GetURL() ||
 			strings.Contains(chobj.Spec.Pathname, e.GetRepo().GetFullName()) {
 			klog.Info("Processing PR event from " + e.GetRepo().GetHTMLURL())
+
+			if strings.EqualFold(e.GetAction(), "closed") && listener.deletePreviewSubscription(sub, e.GetNumber()) {
+				break
+			}
+
 			listener.updateSubscription(sub)
 		}
 	case *github.PushEvent:
@@ -40,7 +40,14 @@ const (
 )
 
 type GitLabPayload struct {
-	Repository GitLabRepository `json:"repository"`
+	Repository       GitLabRepository       `json:"repository"`
+	ObjectAttributes GitLabObjectAttributes `json:"object_attributes"`
+}
+
+// GitLabObjectAttributes are the merge request attributes of the merge request events
+type GitLabObjectAttributes struct {
+	IID   int    `json:"iid"`
+	State string `json:"state"`
 }
 
 type GitLabRepository struct {
@@ -138,6 +145,13 @@ func (listener *WebhookListener) processGitLabEvent(sub appv1alpha1.Subscription
 		strings.TrimSpace(payload.Repository.Homepage) != "" &&
 		strings.EqualFold(channelSecret, hookSecret) {
 		klog.Infof("Processing %s event from %s repository for subscription %s", event, payload.Repository.URL, sub.Name)
+
+		mrState := payload.ObjectAttributes.State
+		if strings.EqualFold(event, GitLabMergeRequestEvents) && (mrState == "closed" || mrState == "merged") &&
+			listener.deletePreviewSubscription(sub, payload.ObjectAttributes.IID) {
+			return true
+		}
+
 		listener.updateSubscription(sub)
 	}
 
@@ -37,12 +37,14 @@ import (
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils"
 
 	appv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
+	"open-cluster-management.io/multicloud-operators-subscription/pkg/utils/tlsconfig"
 )
 
 const (
@@ -63,6 +65,7 @@ type WebhookListener struct {
 	DynamicClient dynamic.Interface
 	TLSKeyFile    string
 	TLSCrtFile    string
+	certWatcher   *certwatcher.CertWatcher
 }
 
 var webhookListener *WebhookListener
@@ -71,13 +74,11 @@ var webhookListener *WebhookListener
 func Add(mgr manager.Manager, hubconfig *rest.Config, tlsKeyFile, tlsCrtFile string, disableTLS bool, createService bool) error {
 	klog.V(2).Info("Setting up webhook listener ...")
 
-	if !disableTLS {
+	if !disableTLS && !hasDefaultCertFiles() {
 		dir := "/root/certs"
 
 		if strings.EqualFold(tlsKeyFile, "") || strings.EqualFold(tlsCrtFile, "") {
-			err := utils.GenerateServerCerts(dir)
-
-			if err != nil {
+			if err := generateServingCerts(mgr, dir); err != nil {
 				klog.Error("Failed to generate a self signed certificate. error: ", err)
 				return err
 			}
@@ -96,9 +97,52 @@ func Add(mgr manager.Manager, hubconfig *rest.Config, tlsKeyFile, tlsCrtFile str
 		return err
 	}
 
+	if webhookListener.TLSKeyFile != "" && webhookListener.TLSCrtFile != "" {
+		// the renewed certificates of cert-manager, the service CA or the rotator are served without a restart
+		webhookListener.certWatcher, err = certwatcher.New(webhookListener.TLSCrtFile, webhookListener.TLSKeyFile)
+		if err != nil {
+			klog.Error("Failed to load the WebHook listener certificate. error: ", err)
+			return err
+		}
+
+		if err := mgr.Add(webhookListener.certWatcher); err != nil {
+			return err
+		}
+	}
+
 	return mgr.Add(webhookListener)
 }
 
+// hasDefaultCertFiles checks if the certificate is mounted in the default files, by cert-manager or the service CA
+func hasDefaultCertFiles() bool {
+	for _, file := range []string{defaultKeyFile, defaultCrtFile} {
+		if _, err := os.Stat(file); err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// generateServingCerts writes a self-signed certificate of the listener service in the directory. In the cluster the
+// certificate is kept and renewed by a certificate rotator, out of the cluster it is generated once.
+func generateServingCerts(mgr manager.Manager, dir string) error {
+	namespace, err := getOperatorNamespace()
+	if err != nil {
+		klog.Info("The operator namespace is unknown, generating a certificate which is not rotated. error: ", err)
+
+		return utils.GenerateServerCerts(dir)
+	}
+
+	rotator := utils.NewCertRotator(mgr, serviceName, namespace, dir)
+
+	if err := rotator.Rotate(context.TODO()); err != nil {
+		return err
+	}
+
+	return mgr.Add(rotator)
+}
+
 // Start the GutHub WebHook event listener
 func (listener *WebhookListener) Start(ctx context.Context) error {
 	if klog.V(utils.QuiteLogLel).Enabled() {
@@ -118,7 +162,13 @@ func (listener *WebhookListener) Start(ctx context.Context) error {
 			Addr:              ":8443",
 			Handler:           mux,
 			ReadHeaderTimeout: 32 * time.Second,
-			TLSConfig:         &tls.Config{MinVersion: tls.VersionTLS13},
+			TLSConfig:         tlsconfig.NewConfig(tls.VersionTLS13),
+		}
+
+		if listener.certWatcher != nil {
+			s.TLSConfig.GetCertificate = listener.certWatcher.GetCertificate
+
+			klog.Fatal(s.ListenAndServeTLS("", ""))
 		}
 
 		klog.Fatal(s.ListenAndServeTLS(listener.TLSCrtFile, listener.TLSKeyFile))
@@ -378,6 +428,21 @@ func (listener *WebhookListener) updateSubscription(sub appv1alpha1.Subscription
 	return newsub
 }
 
+// deletePreviewSubscription deletes the subscription if it is the preview of the closed pull request
+func (listener *WebhookListener) deletePreviewSubscription(sub appv1alpha1.Subscription, prNumber int) bool {
+	if prNumber == 0 || sub.GetAnnotations()[appv1alpha1.AnnotationGitPullRequest] != strconv.Itoa(prNumber) {
+		return false
+	}
+
+	klog.Infof("Pull request %v is closed, deleting preview subscription %v/%v", prNumber, sub.Namespace, sub.Name)
+
+	if err := listener.LocalClient.Delete(context.TODO(), &sub); err != nil && !errors.IsNotFound(err) {
+		klog.Error("Failed to delete preview subscription. error: ", err)
+	}
+
+	return true
+}
+
 func getOperatorNamespace() (string, error) {
 	nsBytes, err := ioutil.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/namespace")
 	if err != nil {